@@ -23,8 +23,10 @@ const (
 type Condition func(ctx context.Context, subject string, permission Permission, resource string) bool
 
 // Rule defines an ABAC rule with an effect, conditions, and priority. Rules
-// with higher priority are evaluated first. The first matching rule determines
-// the authorization outcome.
+// with higher priority are evaluated first; among rules of equal priority,
+// deny rules are evaluated before allow rules, and otherwise rules keep the
+// order they were added in. The first matching rule determines the
+// authorization outcome.
 type Rule struct {
 	// Name identifies this rule for logging and debugging.
 	Name string
@@ -41,8 +43,10 @@ type Rule struct {
 }
 
 // ABACPolicy implements attribute-based access control. Rules are evaluated in
-// priority order (highest first); the first matching rule determines the
-// outcome. If no rule matches, access is denied (default deny).
+// priority order (highest first, deny before allow at equal priority); the
+// first matching rule determines the outcome. If no rule matches, access is
+// denied (default deny). PermissionMatches and ResourceMatches build
+// conditions with the same wildcard matching as RBACPolicy.
 //
 // ABACPolicy is safe for concurrent use.
 type ABACPolicy struct {
@@ -82,9 +86,9 @@ func (p *ABACPolicy) AddRule(rule Rule) error {
 	return nil
 }
 
-// Authorize evaluates rules in priority order (highest first). The first rule
-// whose conditions all match determines the result. Returns false if no rule
-// matches (default deny).
+// Authorize evaluates rules in priority order (highest first, deny before
+// allow at equal priority). The first rule whose conditions all match
+// determines the result. Returns false if no rule matches (default deny).
 func (p *ABACPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
 	p.mu.RLock()
 	// Copy rules under lock to sort without holding the lock during evaluation.
@@ -92,9 +96,13 @@ func (p *ABACPolicy) Authorize(ctx context.Context, subject string, permission P
 	copy(sorted, p.rules)
 	p.mu.RUnlock()
 
-	// Sort by priority descending.
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Priority > sorted[j].Priority
+	// Sort by priority descending, deny first within a priority. The sort is
+	// stable so that ties keep insertion order.
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Effect == EffectDeny && sorted[j].Effect != EffectDeny
 	})
 
 	for _, rule := range sorted {
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DecisionCache memoizes authorization decisions by (subject, permission,
+// resource) so that repeated checks skip policy evaluation. Allow and deny
+// decisions have separate TTLs; errors are never cached.
+//
+// A decision is only cached when it depends on nothing but its key. Requests
+// with an empty subject, requests whose context carries a bearer token,
+// token claims (see JWTPolicy) or a delegation token (see DelegationPolicy),
+// and requests whose context was marked with SkipDecisionCache are passed
+// through uncached. So are decisions whose evaluation called MarkVolatile,
+// as TimeWindowCondition and QuotaCondition do; wrap custom conditions that
+// read the clock, request history or request-time attributes with Volatile.
+//
+// DecisionCache is safe for concurrent use.
+type DecisionCache struct {
+	allowTTL   time.Duration
+	denyTTL    time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]map[decisionKey]decision // subject -> decisions
+	size    int
+	gen     uint64 // incremented by every invalidation
+}
+
+// decisionKey identifies a cached decision within a subject.
+type decisionKey struct {
+	permission Permission
+	resource   string
+}
+
+// decision is a cached authorization outcome.
+type decision struct {
+	allowed bool
+	expires time.Time
+}
+
+// DecisionCacheOption configures a DecisionCache.
+type DecisionCacheOption func(*DecisionCache)
+
+// WithDenyTTL sets how long deny decisions are cached. Zero disables caching
+// of denies. Default is the allow TTL.
+func WithDenyTTL(d time.Duration) DecisionCacheOption {
+	return func(c *DecisionCache) {
+		c.denyTTL = d
+	}
+}
+
+// WithMaxEntries bounds the number of cached decisions. When the cache is
+// full, expired decisions are dropped and, if it is still full, new
+// decisions are not cached until space frees up. Default is 10000.
+func WithMaxEntries(n int) DecisionCacheOption {
+	return func(c *DecisionCache) {
+		c.maxEntries = n
+	}
+}
+
+// NewDecisionCache creates a cache that keeps allow decisions for ttl. A zero
+// ttl disables caching of allows.
+func NewDecisionCache(ttl time.Duration, opts ...DecisionCacheOption) *DecisionCache {
+	c := &DecisionCache{
+		allowTTL:   ttl,
+		denyTTL:    ttl,
+		maxEntries: 10000,
+		now:        time.Now,
+		entries:    make(map[string]map[decisionKey]decision),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Middleware returns middleware that serves decisions from the cache. Several
+// policies may share one cache only if they make identical decisions.
+func (c *DecisionCache) Middleware() Middleware {
+	return func(next Policy) Policy {
+		return &cachedPolicy{next: next, cache: c}
+	}
+}
+
+// Invalidate drops every cached decision for subject. Call it when the
+// subject's roles or attributes change.
+func (c *DecisionCache) Invalidate(subject string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size -= len(c.entries[subject])
+	delete(c.entries, subject)
+	c.gen++
+}
+
+// InvalidateAll drops every cached decision. Call it when roles or rules
+// change.
+func (c *DecisionCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]map[decisionKey]decision)
+	c.size = 0
+	c.gen++
+}
+
+// lookup returns the cached decision for the key and the current
+// generation, to be passed to store.
+func (c *DecisionCache) lookup(subject string, key decisionKey) (allowed, ok bool, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, found := c.entries[subject][key]
+	if found && c.now().Before(d.expires) {
+		return d.allowed, true, c.gen
+	}
+	return false, false, c.gen
+}
+
+// store caches a decision computed at generation gen. It is discarded if an
+// invalidation happened since, as the decision may predate it.
+func (c *DecisionCache) store(subject string, key decisionKey, allowed bool, gen uint64) {
+	ttl := c.denyTTL
+	if allowed {
+		ttl = c.allowTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return
+	}
+	now := c.now()
+	byKey := c.entries[subject]
+	if _, exists := byKey[key]; !exists {
+		if c.size >= c.maxEntries {
+			c.evictExpired(now)
+		}
+		if c.size >= c.maxEntries {
+			return
+		}
+		if byKey == nil {
+			byKey = make(map[decisionKey]decision)
+			c.entries[subject] = byKey
+		}
+		c.size++
+	}
+	byKey[key] = decision{allowed: allowed, expires: now.Add(ttl)}
+}
+
+// evictExpired drops expired decisions. It must be called with c.mu held.
+func (c *DecisionCache) evictExpired(now time.Time) {
+	for subject, byKey := range c.entries {
+		for key, d := range byKey {
+			if !now.Before(d.expires) {
+				delete(byKey, key)
+				c.size--
+			}
+		}
+		if len(byKey) == 0 {
+			delete(c.entries, subject)
+		}
+	}
+}
+
+// WithDecisionCache returns middleware that caches decisions for ttl using a
+// new DecisionCache. Use NewDecisionCache and its Middleware method instead
+// when decisions must be invalidated.
+func WithDecisionCache(ttl time.Duration, opts ...DecisionCacheOption) Middleware {
+	return NewDecisionCache(ttl, opts...).Middleware()
+}
+
+// skipCacheKey marks a context whose decisions must not be cached.
+type skipCacheKey struct{}
+
+// SkipDecisionCache returns a copy of ctx whose authorization decisions
+// bypass any DecisionCache, for requests evaluated against request-time
+// attributes.
+func SkipDecisionCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+// volatileKey carries the marker MarkVolatile sets during evaluation.
+type volatileKey struct{}
+
+// MarkVolatile records that the decision being evaluated in ctx depends on
+// more than its subject, permission and resource, so that no DecisionCache
+// stores it. Policies and conditions call it when they consult the clock,
+// request history or other request-time state. It does nothing when no
+// DecisionCache is evaluating ctx.
+func MarkVolatile(ctx context.Context) {
+	if v, ok := ctx.Value(volatileKey{}).(*atomic.Bool); ok {
+		v.Store(true)
+	}
+}
+
+// Volatile returns a Condition that evaluates cond and marks the decision
+// volatile, for custom conditions whose result varies between identical
+// requests.
+func Volatile(cond Condition) Condition {
+	return func(ctx context.Context, subject string, permission Permission, resource string) bool {
+		MarkVolatile(ctx)
+		return cond(ctx, subject, permission, resource)
+	}
+}
+
+// cacheable reports whether a decision for subject in ctx depends only on
+// its cache key.
+func cacheable(ctx context.Context, subject string) bool {
+	if subject == "" || BearerTokenFromContext(ctx) != "" || ClaimsFromContext(ctx) != nil || DelegationFromContext(ctx) != nil {
+		return false
+	}
+	skip, _ := ctx.Value(skipCacheKey{}).(bool)
+	return !skip
+}
+
+// cachedPolicy serves decisions from a DecisionCache.
+type cachedPolicy struct {
+	next  Policy
+	cache *DecisionCache
+}
+
+func (p *cachedPolicy) Name() string { return p.next.Name() }
+
+func (p *cachedPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
+	if !cacheable(ctx, subject) {
+		return p.next.Authorize(ctx, subject, permission, resource)
+	}
+	key := decisionKey{permission: permission, resource: resource}
+	allowed, ok, gen := p.cache.lookup(subject, key)
+	if ok {
+		return allowed, nil
+	}
+	volatile := new(atomic.Bool)
+	allowed, err := p.next.Authorize(context.WithValue(ctx, volatileKey{}, volatile), subject, permission, resource)
+	if err != nil || volatile.Load() {
+		return allowed, err
+	}
+	p.cache.store(subject, key, allowed, gen)
+	return allowed, nil
+}
+
+// Ensure cachedPolicy implements Policy at compile time.
+var _ Policy = (*cachedPolicy)(nil)
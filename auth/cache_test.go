@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingPolicy allows subjects in allowed and counts evaluations.
+type countingPolicy struct {
+	calls   atomic.Int32
+	mu      sync.Mutex
+	allowed map[string]bool
+	err     error
+}
+
+func (p *countingPolicy) Name() string { return "counting" }
+
+func (p *countingPolicy) Authorize(_ context.Context, subject string, _ Permission, _ string) (bool, error) {
+	p.calls.Add(1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return false, p.err
+	}
+	return p.allowed[subject], nil
+}
+
+func (p *countingPolicy) set(subject string, allowed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowed[subject] = allowed
+}
+
+func newCachedTestPolicy(inner Policy, ttl time.Duration, opts ...DecisionCacheOption) (Policy, *DecisionCache, *time.Time) {
+	now := time.Unix(0, 0)
+	c := NewDecisionCache(ttl, opts...)
+	c.now = func() time.Time { return now }
+	return ApplyMiddleware(inner, c.Middleware()), c, &now
+}
+
+func TestDecisionCache_TTLs(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+	p, _, now := newCachedTestPolicy(inner, time.Minute, WithDenyTTL(10*time.Second))
+
+	authorize := func(subject string) bool {
+		t.Helper()
+		allowed, err := p.Authorize(ctx, subject, PermToolExec, "calculator")
+		if err != nil {
+			t.Fatalf("Authorize error: %v", err)
+		}
+		return allowed
+	}
+
+	if !authorize("alice") || authorize("bob") {
+		t.Fatal("unexpected initial decisions")
+	}
+	authorize("alice")
+	authorize("bob")
+	if got := inner.calls.Load(); got != 2 {
+		t.Fatalf("expected cached decisions, got %d evaluations", got)
+	}
+
+	// The deny expires before the allow.
+	inner.set("bob", true)
+	*now = now.Add(10 * time.Second)
+	if !authorize("bob") {
+		t.Error("expected bob's deny to expire")
+	}
+	authorize("alice")
+	if got := inner.calls.Load(); got != 3 {
+		t.Errorf("expected only the deny to be re-evaluated, got %d evaluations", got)
+	}
+
+	*now = now.Add(time.Minute)
+	authorize("alice")
+	if got := inner.calls.Load(); got != 4 {
+		t.Errorf("expected the allow to expire, got %d evaluations", got)
+	}
+}
+
+func TestDecisionCache_DisabledDenies(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{}}
+	p, _, _ := newCachedTestPolicy(inner, time.Minute, WithDenyTTL(0))
+
+	for range 3 {
+		_, _ = p.Authorize(ctx, "bob", PermToolExec, "calculator")
+	}
+	if got := inner.calls.Load(); got != 3 {
+		t.Errorf("expected denies not to be cached, got %d evaluations", got)
+	}
+}
+
+func TestDecisionCache_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true, "carol": true}}
+	p, c, _ := newCachedTestPolicy(inner, time.Minute)
+
+	_, _ = p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	_, _ = p.Authorize(ctx, "carol", PermToolExec, "calculator")
+
+	inner.set("alice", false)
+	c.Invalidate("alice")
+	if allowed, _ := p.Authorize(ctx, "alice", PermToolExec, "calculator"); allowed {
+		t.Error("expected alice's role change to take effect after Invalidate")
+	}
+	_, _ = p.Authorize(ctx, "carol", PermToolExec, "calculator")
+	if got := inner.calls.Load(); got != 3 {
+		t.Errorf("expected only alice to be re-evaluated, got %d evaluations", got)
+	}
+
+	inner.set("carol", false)
+	c.InvalidateAll()
+	if allowed, _ := p.Authorize(ctx, "carol", PermToolExec, "calculator"); allowed {
+		t.Error("expected InvalidateAll to clear carol's decision")
+	}
+}
+
+func TestDecisionCache_InvalidateDuringEvaluation(t *testing.T) {
+	ctx := context.Background()
+	c := NewDecisionCache(time.Minute)
+	inner := &invalidatingPolicy{invalidate: func() { c.Invalidate("alice") }}
+	p := ApplyMiddleware(inner, c.Middleware())
+
+	// The decision was computed before the invalidation completed, so it
+	// must not be cached.
+	_, _ = p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	_, _ = p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	if inner.calls != 2 {
+		t.Errorf("expected a decision racing an invalidation not to be cached, got %d evaluations", inner.calls)
+	}
+}
+
+// invalidatingPolicy allows everything and invalidates the cache on its
+// first evaluation.
+type invalidatingPolicy struct {
+	calls      int
+	invalidate func()
+}
+
+func (p *invalidatingPolicy) Name() string { return "invalidating" }
+
+func (p *invalidatingPolicy) Authorize(context.Context, string, Permission, string) (bool, error) {
+	p.calls++
+	if p.calls == 1 {
+		p.invalidate()
+	}
+	return true, nil
+}
+
+func TestDecisionCache_Uncacheable(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		subject string
+	}{
+		{name: "empty subject", ctx: context.Background(), subject: ""},
+		{name: "bearer token", ctx: WithBearerToken(context.Background(), "token"), subject: "alice"},
+		{name: "claims", ctx: WithClaims(context.Background(), Claims{"sub": "alice"}), subject: "alice"},
+		{name: "marked", ctx: SkipDecisionCache(context.Background()), subject: "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+			p, _, _ := newCachedTestPolicy(inner, time.Minute)
+			for range 2 {
+				_, _ = p.Authorize(tt.ctx, tt.subject, PermToolExec, "calculator")
+			}
+			if got := inner.calls.Load(); got != 2 {
+				t.Errorf("expected no caching, got %d evaluations", got)
+			}
+		})
+	}
+}
+
+func TestDecisionCache_ErrorsNotCached(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}, err: errors.New("backend down")}
+	p, _, _ := newCachedTestPolicy(inner, time.Minute)
+
+	if _, err := p.Authorize(ctx, "alice", PermToolExec, "calculator"); err == nil {
+		t.Fatal("expected error")
+	}
+	inner.mu.Lock()
+	inner.err = nil
+	inner.mu.Unlock()
+	allowed, err := p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	if err != nil || !allowed {
+		t.Errorf("expected recovery after error, got %v, %v", allowed, err)
+	}
+}
+
+func TestDecisionCache_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+	p, c, now := newCachedTestPolicy(inner, time.Minute, WithMaxEntries(2))
+
+	for _, res := range []string{"a", "b", "c"} {
+		_, _ = p.Authorize(ctx, "alice", PermToolExec, res)
+	}
+	if c.size != 2 {
+		t.Fatalf("expected 2 cached decisions, got %d", c.size)
+	}
+	_, _ = p.Authorize(ctx, "alice", PermToolExec, "c")
+	if got := inner.calls.Load(); got != 4 {
+		t.Errorf("expected the decision over the limit not to be cached, got %d evaluations", got)
+	}
+
+	// Expired decisions make room.
+	*now = now.Add(time.Minute)
+	_, _ = p.Authorize(ctx, "alice", PermToolExec, "c")
+	if c.size != 1 {
+		t.Errorf("expected expired decisions to be evicted, got %d", c.size)
+	}
+}
+
+func TestDecisionCache_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+	c := NewDecisionCache(time.Minute)
+	p := ApplyMiddleware(inner, c.Middleware())
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for j := range 100 {
+				if i == 0 && j%10 == 0 {
+					c.Invalidate("alice")
+				}
+				allowed, err := p.Authorize(ctx, "alice", PermToolExec, "calculator")
+				if err != nil || !allowed {
+					t.Errorf("unexpected decision: %v, %v", allowed, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if p.Name() != "counting" {
+		t.Errorf("expected name 'counting', got %q", p.Name())
+	}
+}
+
+func TestWithDecisionCache(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+	p := ApplyMiddleware(inner, WithDecisionCache(time.Minute))
+	for range 3 {
+		_, _ = p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	}
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("expected 1 evaluation, got %d", got)
+	}
+}
+
+func TestDecisionCache_Volatile(t *testing.T) {
+	calls := 0
+	cond := Volatile(func(context.Context, string, Permission, string) bool {
+		calls++
+		return true
+	})
+	abac := NewABACPolicy("abac")
+	if err := abac.AddRule(Rule{Name: "custom", Effect: EffectAllow, Conditions: []Condition{cond}}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	p, _, _ := newCachedTestPolicy(abac, time.Minute)
+	for range 2 {
+		_, _ = p.Authorize(context.Background(), "alice", PermToolExec, "calculator")
+	}
+	if calls != 2 {
+		t.Errorf("expected no caching, got %d evaluations", calls)
+	}
+
+	// Outside a DecisionCache the mark is a no-op.
+	MarkVolatile(context.Background())
+}
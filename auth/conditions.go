@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/state"
+)
+
+// Schedule describes a recurring time window, such as business hours.
+type Schedule struct {
+	// Days lists the weekdays the window opens on. Empty means every day.
+	Days []time.Weekday
+
+	// Start and End are times of day as offsets from midnight, between 0 and
+	// 24h. The window includes Start and excludes End. If End is before
+	// Start the window spans midnight and belongs to the day it opens on, so
+	// a Friday 22:00-06:00 window includes Saturday 02:00. If Start equals
+	// End the window covers the whole day.
+	Start, End time.Duration
+
+	// Location is the time zone the schedule is expressed in. Nil means UTC.
+	Location *time.Location
+}
+
+// contains reports whether t falls within the schedule.
+func (s Schedule) contains(t time.Time) bool {
+	if s.Start < 0 || s.End < 0 || s.Start > 24*time.Hour || s.End > 24*time.Hour {
+		return false
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	offset := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	day := t.Weekday()
+
+	switch {
+	case s.Start == s.End:
+		return s.opensOn(day)
+	case s.Start < s.End:
+		return offset >= s.Start && offset < s.End && s.opensOn(day)
+	case offset >= s.Start:
+		return s.opensOn(day)
+	case offset < s.End:
+		return s.opensOn((day + 6) % 7) // opened the day before
+	}
+	return false
+}
+
+// opensOn reports whether the window opens on day.
+func (s Schedule) opensOn(day time.Weekday) bool {
+	return len(s.Days) == 0 || slices.Contains(s.Days, day)
+}
+
+// conditionOptions holds configuration for the built-in conditions.
+type conditionOptions struct {
+	keyPrefix string
+	nowFunc   func() time.Time
+}
+
+// ConditionOption configures TimeWindowCondition and QuotaCondition.
+type ConditionOption func(*conditionOptions)
+
+// WithQuotaKeyPrefix sets the prefix of the counter keys QuotaCondition keeps
+// in the store. Give each quota its own prefix so that their counters are
+// independent. Default is "global:auth/quota/".
+func WithQuotaKeyPrefix(prefix string) ConditionOption {
+	return func(o *conditionOptions) {
+		o.keyPrefix = prefix
+	}
+}
+
+// withNowFunc overrides the time source for testing. This is unexported
+// because it is only useful in tests.
+func withNowFunc(fn func() time.Time) ConditionOption {
+	return func(o *conditionOptions) {
+		o.nowFunc = fn
+	}
+}
+
+func newConditionOptions(opts []ConditionOption) conditionOptions {
+	o := conditionOptions{
+		keyPrefix: state.ScopedKey(state.ScopeGlobal, "auth/quota/"),
+		nowFunc:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// TimeWindowCondition returns a Condition that matches while the current
+// time is within schedule, for rules such as "allow external API calls only
+// during business hours":
+//
+//	auth.Rule{
+//	    Name:   "business-hours-api",
+//	    Effect: auth.EffectAllow,
+//	    Conditions: []auth.Condition{
+//	        auth.PermissionMatches(auth.PermExternalAPI),
+//	        auth.TimeWindowCondition(auth.Schedule{
+//	            Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+//	            Start:    9 * time.Hour,
+//	            End:      17 * time.Hour,
+//	            Location: nyc,
+//	        }),
+//	    },
+//	}
+//
+// Evaluation only reads the clock. A schedule with Start or End outside 0 to
+// 24h never matches. Decisions that evaluate the condition are marked
+// volatile, so a DecisionCache does not store them.
+func TimeWindowCondition(schedule Schedule, opts ...ConditionOption) Condition {
+	o := newConditionOptions(opts)
+	return func(ctx context.Context, _ string, _ Permission, _ string) bool {
+		MarkVolatile(ctx)
+		return schedule.contains(o.nowFunc())
+	}
+}
+
+// QuotaCondition returns a Condition that matches while the subject has made
+// at most limit requests in the current window, counting the request being
+// evaluated. Counters are kept in store under the subject's name, so the
+// quota is shared by every process using the same store.
+//
+// Every evaluation consumes one unit of quota, so place the condition last
+// in a rule: ABACPolicy stops at the first condition that does not match,
+// and requests the rest of the rule rejects are then not counted. A quota is
+// enforced with an allow rule; once it is exhausted the rule stops matching
+// and evaluation falls through to lower-priority rules, so pair it with a
+// deny rule for the same requests at a lower priority (or rely on default
+// deny):
+//
+//	abac.AddRule(auth.Rule{Name: "api-quota", Effect: auth.EffectAllow, Priority: 20,
+//	    Conditions: []auth.Condition{
+//	        auth.PermissionMatches(auth.PermExternalAPI),
+//	        auth.QuotaCondition(store, 1000, 24*time.Hour),
+//	    }})
+//	abac.AddRule(auth.Rule{Name: "api-over-quota", Effect: auth.EffectDeny, Priority: 10,
+//	    Conditions: []auth.Condition{auth.PermissionMatches(auth.PermExternalAPI)}})
+//
+// Windows are fixed and aligned to the Unix epoch: a 24h window resets at
+// midnight UTC, a 1h window on the hour. Each window has its own counter key.
+// On a state.ExpiringStore the first request of a window rewrites its counter
+// with a TTL of two windows, so counters of subjects that stop making
+// requests expire; this relies on Increment keeping the expiry, as the
+// inmemory and redis stores do, and a request racing the first one of a
+// window may go uncounted. On other stores the first request of a window
+// deletes the subject's counter for the previous window instead, so a subject
+// leaves at most one stale counter behind.
+//
+// Each evaluation performs one state.Increment on store (a round trip for
+// remote stores). If the store fails, the condition does not match, so the
+// quota fails closed. Decisions depend on request history, so they are
+// marked volatile and a DecisionCache does not store them.
+func QuotaCondition(store state.Store, limit int64, window time.Duration, opts ...ConditionOption) Condition {
+	o := newConditionOptions(opts)
+	return func(ctx context.Context, subject string, _ Permission, _ string) bool {
+		MarkVolatile(ctx)
+		if window <= 0 {
+			return false
+		}
+		bucket := o.nowFunc().UnixNano() / int64(window)
+		prefix := o.keyPrefix + subject + ":"
+		key := prefix + strconv.FormatInt(bucket, 10)
+		n, err := state.Increment(ctx, store, key, 1)
+		if err != nil {
+			return false
+		}
+		if n == 1 && state.SetWithTTL(ctx, store, key, n, 2*window) != nil {
+			_ = store.Delete(ctx, prefix+strconv.FormatInt(bucket-1, 10))
+		}
+		return n <= limit
+	}
+}
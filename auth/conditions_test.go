@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/state"
+	"github.com/lookatitude/beluga-ai/v2/state/providers/inmemory"
+)
+
+func TestTimeWindowCondition(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	nyc := time.FixedZone("EST", -5*60*60)
+	// 2024-01-05 is a Friday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		want     bool
+	}{
+		{name: "business hours", schedule: Schedule{Days: weekdays, Start: 9 * time.Hour, End: 17 * time.Hour}, now: at(5, 10, 0), want: true},
+		{name: "start is inclusive", schedule: Schedule{Days: weekdays, Start: 9 * time.Hour, End: 17 * time.Hour}, now: at(5, 9, 0), want: true},
+		{name: "end is exclusive", schedule: Schedule{Days: weekdays, Start: 9 * time.Hour, End: 17 * time.Hour}, now: at(5, 17, 0), want: false},
+		{name: "weekend", schedule: Schedule{Days: weekdays, Start: 9 * time.Hour, End: 17 * time.Hour}, now: at(6, 10, 0), want: false},
+		{name: "time zone", schedule: Schedule{Start: 9 * time.Hour, End: 17 * time.Hour, Location: nyc}, now: at(5, 15, 0), want: true},
+		{name: "time zone shifts day", schedule: Schedule{Days: weekdays, Start: 20 * time.Hour, End: 23 * time.Hour, Location: nyc}, now: at(6, 2, 0), want: true},
+		{name: "overnight before midnight", schedule: Schedule{Days: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 6 * time.Hour}, now: at(5, 23, 0), want: true},
+		{name: "overnight after midnight", schedule: Schedule{Days: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 6 * time.Hour}, now: at(6, 2, 0), want: true},
+		{name: "overnight belongs to opening day", schedule: Schedule{Days: []time.Weekday{time.Friday}, Start: 22 * time.Hour, End: 6 * time.Hour}, now: at(5, 2, 0), want: false},
+		{name: "overnight gap", schedule: Schedule{Start: 22 * time.Hour, End: 6 * time.Hour}, now: at(5, 12, 0), want: false},
+		{name: "whole day", schedule: Schedule{Days: []time.Weekday{time.Saturday}}, now: at(6, 23, 59), want: true},
+		{name: "invalid", schedule: Schedule{Start: 25 * time.Hour}, now: at(5, 10, 0), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := TimeWindowCondition(tt.schedule, withNowFunc(func() time.Time { return tt.now }))
+			if got := cond(context.Background(), "alice", PermExternalAPI, "api"); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaCondition(t *testing.T) {
+	ctx := context.Background()
+	store := inmemory.New()
+	defer store.Close()
+	now := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	abac := NewABACPolicy("abac")
+	rules := []Rule{
+		{Name: "api-quota", Effect: EffectAllow, Priority: 20, Conditions: []Condition{
+			PermissionMatches(PermExternalAPI),
+			QuotaCondition(store, 3, 24*time.Hour, withNowFunc(func() time.Time { return now })),
+		}},
+		{Name: "api-over-quota", Effect: EffectDeny, Priority: 10, Conditions: []Condition{
+			PermissionMatches(PermExternalAPI),
+		}},
+		{Name: "allow-rest", Effect: EffectAllow},
+	}
+	for _, rule := range rules {
+		if err := abac.AddRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	authorize := func(subject string, perm Permission) bool {
+		t.Helper()
+		allowed, err := abac.Authorize(ctx, subject, perm, "api")
+		if err != nil {
+			t.Fatalf("Authorize error: %v", err)
+		}
+		return allowed
+	}
+
+	for i := range 3 {
+		if !authorize("alice", PermExternalAPI) {
+			t.Fatalf("expected call %d to be within quota", i+1)
+		}
+	}
+	if authorize("alice", PermExternalAPI) {
+		t.Error("expected call over quota to be denied")
+	}
+	if !authorize("bob", PermExternalAPI) {
+		t.Error("expected quotas to be per subject")
+	}
+	// Requests the rule rejects earlier do not consume quota.
+	if !authorize("carol", PermMemoryRead) {
+		t.Fatal("expected other permissions to be allowed")
+	}
+	if v, _ := store.Get(ctx, "global:auth/quota/carol:"+dayBucket(now)); v != nil {
+		t.Errorf("expected no counter for carol, got %v", v)
+	}
+
+	now = now.Add(24 * time.Hour)
+	if !authorize("alice", PermExternalAPI) {
+		t.Error("expected quota to reset in the next window")
+	}
+}
+
+// dayBucket returns the quota counter suffix of a 24h window.
+func dayBucket(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(24*time.Hour), 10)
+}
+
+// ttlStore records the TTLs its counters are written with.
+type ttlStore struct {
+	*inmemory.Store
+	ttls map[string]time.Duration
+}
+
+func (s *ttlStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	s.ttls[key] = ttl
+	return s.Store.SetWithTTL(ctx, key, value, ttl)
+}
+
+// plainStore hides the ExpiringStore methods of the store it wraps.
+type plainStore struct {
+	state.AtomicStore
+}
+
+func TestQuotaCondition_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+	nowFunc := withNowFunc(func() time.Time { return now })
+	key := "global:auth/quota/alice:" + dayBucket(now)
+
+	t.Run("expiring store", func(t *testing.T) {
+		store := &ttlStore{Store: inmemory.New(), ttls: map[string]time.Duration{}}
+		defer store.Close()
+		cond := QuotaCondition(store, 3, 24*time.Hour, nowFunc)
+		for range 2 {
+			if !cond(ctx, "alice", PermExternalAPI, "api") {
+				t.Fatal("expected call to be within quota")
+			}
+		}
+		if got := store.ttls[key]; got != 48*time.Hour || len(store.ttls) != 1 {
+			t.Errorf("ttls = %v, want %s written once with 48h", store.ttls, key)
+		}
+		if v, _ := store.Get(ctx, key); v != int64(2) {
+			t.Errorf("counter = %v, want 2", v)
+		}
+	})
+
+	t.Run("plain store", func(t *testing.T) {
+		inner := inmemory.New()
+		defer inner.Close()
+		cond := QuotaCondition(plainStore{inner}, 3, 24*time.Hour, nowFunc)
+		cond(ctx, "alice", PermExternalAPI, "api")
+		now = now.Add(24 * time.Hour)
+		cond(ctx, "alice", PermExternalAPI, "api")
+		if v, _ := inner.Get(ctx, key); v != nil {
+			t.Errorf("expected previous window's counter to be deleted, got %v", v)
+		}
+	})
+}
+
+func TestQuotaCondition_KeyPrefix(t *testing.T) {
+	ctx := context.Background()
+	store := inmemory.New()
+	defer store.Close()
+
+	a := QuotaCondition(store, 1, time.Hour, WithQuotaKeyPrefix("quota/a/"))
+	b := QuotaCondition(store, 1, time.Hour, WithQuotaKeyPrefix("quota/b/"))
+	if !a(ctx, "alice", PermToolExec, "x") || !b(ctx, "alice", PermToolExec, "x") {
+		t.Error("expected quotas with different prefixes to be independent")
+	}
+	if a(ctx, "alice", PermToolExec, "x") {
+		t.Error("expected quota a to be exhausted")
+	}
+}
+
+// failingStore is a state.Store whose operations all fail.
+type failingStore struct{}
+
+var errStoreDown = errors.New("store down")
+
+func (failingStore) Get(context.Context, string) (any, error) { return nil, errStoreDown }
+func (failingStore) Set(context.Context, string, any) error   { return errStoreDown }
+func (failingStore) Delete(context.Context, string) error     { return errStoreDown }
+func (failingStore) Close() error                             { return nil }
+func (failingStore) Watch(context.Context, string) iter.Seq2[state.StateChange, error] {
+	return func(func(state.StateChange, error) bool) {}
+}
+
+func TestQuotaCondition_FailsClosed(t *testing.T) {
+	cond := QuotaCondition(failingStore{}, 100, time.Hour)
+	if cond(context.Background(), "alice", PermToolExec, "x") {
+		t.Error("expected the condition not to match when the store fails")
+	}
+	cond = QuotaCondition(inmemory.New(), 100, 0)
+	if cond(context.Background(), "alice", PermToolExec, "x") {
+		t.Error("expected a zero window never to match")
+	}
+}
+
+func TestConditions_BypassDecisionCache(t *testing.T) {
+	ctx := context.Background()
+	store := inmemory.New()
+	defer store.Close()
+	now := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+	clock := withNowFunc(func() time.Time { return now })
+
+	abac := NewABACPolicy("abac")
+	if err := abac.AddRule(Rule{Name: "quota", Effect: EffectAllow, Conditions: []Condition{
+		PermissionMatches(PermExternalAPI),
+		QuotaCondition(store, 2, time.Hour, clock),
+	}}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	if err := abac.AddRule(Rule{Name: "hours", Effect: EffectAllow, Conditions: []Condition{
+		PermissionMatches(PermToolExec),
+		TimeWindowCondition(Schedule{Start: 9 * time.Hour, End: 17 * time.Hour}, clock),
+	}}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	p := ApplyMiddleware(abac, WithDecisionCache(time.Hour))
+
+	var got []bool
+	for range 3 {
+		allowed, err := p.Authorize(ctx, "alice", PermExternalAPI, "search")
+		if err != nil {
+			t.Fatalf("Authorize: %v", err)
+		}
+		got = append(got, allowed)
+	}
+	if got[0] != true || got[1] != true || got[2] != false {
+		t.Errorf("quota decisions = %v, want [true true false]", got)
+	}
+
+	if allowed, _ := p.Authorize(ctx, "alice", PermToolExec, "calculator"); !allowed {
+		t.Error("expected access during business hours")
+	}
+	now = now.Add(8 * time.Hour)
+	if allowed, _ := p.Authorize(ctx, "alice", PermToolExec, "calculator"); allowed {
+		t.Error("expected a cached allow not to outlive the time window")
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import "context"
+
+// contextKey is an unexported type used for context keys in this package to
+// prevent collisions with keys defined in other packages.
+type contextKey int
+
+const (
+	bearerTokenKey contextKey = iota
+	claimsKey
+	delegationKey
+)
+
+// WithBearerToken returns a copy of ctx carrying the raw bearer token of the
+// caller, for verification by JWTPolicy.
+func WithBearerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, bearerTokenKey, token)
+}
+
+// BearerTokenFromContext extracts the bearer token from ctx. It returns ""
+// if no token is present.
+func BearerTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(bearerTokenKey).(string)
+	return token
+}
+
+// WithClaims returns a copy of ctx carrying verified token claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext extracts the verified token claims from ctx. It returns
+// nil if no claims are present.
+func ClaimsFromContext(ctx context.Context) Claims {
+	claims, _ := ctx.Value(claimsKey).(Claims)
+	return claims
+}
+
+// WithDelegation returns a copy of ctx carrying a delegation token, for
+// enforcement by DelegationPolicy and extension by Delegate. A nil token
+// removes any token carried by ctx.
+func WithDelegation(ctx context.Context, tok *DelegationToken) context.Context {
+	return context.WithValue(ctx, delegationKey, tok)
+}
+
+// DelegationFromContext extracts the delegation token from ctx. It returns
+// nil if no token is present.
+func DelegationFromContext(ctx context.Context) *DelegationToken {
+	tok, _ := ctx.Value(delegationKey).(*DelegationToken)
+	return tok
+}
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrInvalidDelegation is wrapped by errors returned for delegations that
+// exceed their delegator's scope or are otherwise malformed.
+var ErrInvalidDelegation = errors.New("invalid delegation")
+
+// Delegation records one link of a delegation chain: From granted To the
+// listed permissions.
+type Delegation struct {
+	From        string
+	To          string
+	Permissions []Permission
+	IssuedAt    time.Time
+	// ExpiresAt is when the grant lapses. Zero means it lasts as long as the
+	// grant it was derived from.
+	ExpiresAt time.Time
+}
+
+// DelegationToken is a scoped grant that lets an agent act on behalf of
+// another with a subset of its permissions. It holds the full delegation
+// chain, from the original delegator to the current holder. Tokens are
+// minted by Delegate, carried with WithDelegation and enforced by
+// DelegationPolicy. They are immutable and safe for concurrent use.
+type DelegationToken struct {
+	chain []Delegation
+}
+
+// Subject returns the agent the token was granted to.
+func (t *DelegationToken) Subject() string { return t.last().To }
+
+// Delegator returns the subject at the root of the chain, whose permissions
+// bound the token.
+func (t *DelegationToken) Delegator() string { return t.chain[0].From }
+
+// Permissions returns the permissions the token grants. They may be
+// patterns, as accepted by MatchPermission.
+func (t *DelegationToken) Permissions() []Permission {
+	return slices.Clone(t.last().Permissions)
+}
+
+// ExpiresAt returns when the token lapses, or the zero time if it does not.
+func (t *DelegationToken) ExpiresAt() time.Time {
+	var earliest time.Time
+	for _, d := range t.chain {
+		if !d.ExpiresAt.IsZero() && (earliest.IsZero() || d.ExpiresAt.Before(earliest)) {
+			earliest = d.ExpiresAt
+		}
+	}
+	return earliest
+}
+
+// Chain returns the delegation chain, starting with the original grant.
+func (t *DelegationToken) Chain() []Delegation {
+	chain := make([]Delegation, len(t.chain))
+	for i, d := range t.chain {
+		d.Permissions = slices.Clone(d.Permissions)
+		chain[i] = d
+	}
+	return chain
+}
+
+// String returns the chain of subjects, such as "alice -> planner -> coder".
+func (t *DelegationToken) String() string {
+	names := make([]string, 0, len(t.chain)+1)
+	names = append(names, t.Delegator())
+	for _, d := range t.chain {
+		names = append(names, d.To)
+	}
+	return strings.Join(names, " -> ")
+}
+
+func (t *DelegationToken) last() Delegation { return t.chain[len(t.chain)-1] }
+
+// DelegateOption configures Delegate.
+type DelegateOption func(*delegateOptions)
+
+type delegateOptions struct {
+	ttl time.Duration
+}
+
+// WithDelegationTTL limits how long the delegation is valid. It never
+// outlives the grant it is derived from. Default is no limit beyond that.
+func WithDelegationTTL(d time.Duration) DelegateOption {
+	return func(o *delegateOptions) {
+		o.ttl = d
+	}
+}
+
+// Delegate mints a token granting to the permissions perms on behalf of
+// from. If ctx already carries a delegation (see WithDelegation), from must
+// be its subject and the new token extends its chain: every permission in
+// perms must be covered by the existing grant, which must itself include
+// PermAgentDelegate. A root delegation is checked against from's own
+// permissions when DelegationPolicy evaluates it, so a token never grants
+// more than its delegator holds.
+//
+// Pass the token to the sub-agent with WithDelegation.
+func Delegate(ctx context.Context, from, to string, perms []Permission, opts ...DelegateOption) (*DelegationToken, error) {
+	if from == "" || to == "" {
+		return nil, core.Errorf(core.ErrInvalidInput, "auth/delegation: delegator and delegate are required")
+	}
+	if len(perms) == 0 {
+		return nil, core.Errorf(core.ErrInvalidInput, "auth/delegation: no permissions to delegate")
+	}
+	var o delegateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	now := time.Now()
+	link := Delegation{
+		From:        from,
+		To:          to,
+		Permissions: slices.Clone(perms),
+		IssuedAt:    now,
+	}
+	if o.ttl > 0 {
+		link.ExpiresAt = now.Add(o.ttl)
+	}
+
+	parent := DelegationFromContext(ctx)
+	if parent == nil {
+		if from == to {
+			return nil, core.Errorf(core.ErrInvalidInput, "auth/delegation: %q cannot delegate to itself: %w", from, ErrInvalidDelegation)
+		}
+		return &DelegationToken{chain: []Delegation{link}}, nil
+	}
+
+	if parent.Subject() != from {
+		return nil, core.Errorf(core.ErrAuth, "auth/delegation: %q cannot delegate on behalf of %q: %w", from, parent.Subject(), ErrInvalidDelegation)
+	}
+	if to == parent.Delegator() || slices.ContainsFunc(parent.chain, func(d Delegation) bool { return d.To == to }) {
+		return nil, core.Errorf(core.ErrInvalidInput, "auth/delegation: %q is already in the delegation chain: %w", to, ErrInvalidDelegation)
+	}
+	granted := parent.last().Permissions
+	if !coversPermission(granted, PermAgentDelegate) {
+		return nil, core.Errorf(core.ErrAuth, "auth/delegation: %q may not re-delegate: %w", from, ErrInvalidDelegation)
+	}
+	for _, perm := range perms {
+		if !coversPermission(granted, perm) {
+			return nil, core.Errorf(core.ErrAuth, "auth/delegation: %q exceeds the permissions delegated to %q: %w", perm, from, ErrInvalidDelegation)
+		}
+	}
+	if expires := parent.ExpiresAt(); !expires.IsZero() && (link.ExpiresAt.IsZero() || expires.Before(link.ExpiresAt)) {
+		link.ExpiresAt = expires
+	}
+
+	chain := make([]Delegation, len(parent.chain), len(parent.chain)+1)
+	copy(chain, parent.chain)
+	return &DelegationToken{chain: append(chain, link)}, nil
+}
+
+// coversPermission reports whether any of granted matches perm. perm may
+// itself be a pattern, which is covered only by an identical or broader
+// pattern.
+func coversPermission(granted []Permission, perm Permission) bool {
+	for _, g := range granted {
+		if MatchPermission(g, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// DelegationPolicy enforces delegation tokens. When the context carries a
+// token (see WithDelegation), a request is allowed only if every link of the
+// chain is unexpired and grants the permission, the original delegator was
+// allowed PermAgentDelegate on the first delegate by the inner policy, and
+// the original delegator is itself allowed the request by the inner policy.
+// The delegate's own permissions are not consulted, so acting under a
+// delegation never grants more than the delegator holds.
+//
+// Requests without a token are passed to the inner policy unchanged.
+//
+// Tokens are trusted in-process values, like the claims JWTPolicy places in
+// the context: only code that can already construct contexts for a subject
+// can present a token on its behalf.
+type DelegationPolicy struct {
+	name  string
+	inner Policy
+	now   func() time.Time
+}
+
+// NewDelegationPolicy creates a policy that enforces delegation tokens on
+// top of inner, which decides what each subject may do on its own behalf.
+func NewDelegationPolicy(name string, inner Policy) *DelegationPolicy {
+	return &DelegationPolicy{name: name, inner: inner, now: time.Now}
+}
+
+// Name returns the policy name.
+func (p *DelegationPolicy) Name() string { return p.name }
+
+// Authorize checks the delegation token in ctx, if any. A non-empty subject
+// that differs from the token's subject is denied; an empty subject
+// authorizes as the token's subject. Expired tokens return a core.ErrAuth
+// error wrapping ErrTokenExpired.
+func (p *DelegationPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
+	tok := DelegationFromContext(ctx)
+	if tok == nil {
+		return p.inner.Authorize(ctx, subject, permission, resource)
+	}
+	if subject != "" && subject != tok.Subject() {
+		return false, nil
+	}
+	if expires := tok.ExpiresAt(); !expires.IsZero() && !p.now().Before(expires) {
+		return false, core.Errorf(core.ErrAuth, "auth/delegation: delegation %s expired: %w", tok, ErrTokenExpired)
+	}
+	for _, d := range tok.chain {
+		if !coversPermission(d.Permissions, permission) {
+			return false, nil
+		}
+	}
+
+	// The inner policy decides for the delegator on its own behalf.
+	ctx = WithDelegation(ctx, nil)
+	root := tok.chain[0]
+	allowed, err := p.inner.Authorize(ctx, root.From, PermAgentDelegate, root.To)
+	if err != nil || !allowed {
+		return false, err
+	}
+	return p.inner.Authorize(ctx, root.From, permission, resource)
+}
+
+// Ensure DelegationPolicy implements Policy at compile time.
+var _ Policy = (*DelegationPolicy)(nil)
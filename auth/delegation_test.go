@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDelegationTestPolicy(t *testing.T) *DelegationPolicy {
+	t.Helper()
+	rbac := NewRBACPolicy("rbac")
+	roles := []Role{
+		{Name: "orchestrator", Permissions: []Permission{PermToolExec, PermMemoryRead, PermMemoryWrite, PermAgentDelegate}},
+		{Name: "worker", Permissions: []Permission{PermExternalAPI}},
+	}
+	for _, role := range roles {
+		if err := rbac.AddRole(role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rbac.AssignRole("alice", "orchestrator"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.AssignRole("coder", "worker"); err != nil {
+		t.Fatal(err)
+	}
+	return NewDelegationPolicy("delegation", rbac)
+}
+
+func TestDelegationPolicy(t *testing.T) {
+	p := newDelegationTestPolicy(t)
+	tok, err := Delegate(context.Background(), "alice", "planner", []Permission{PermToolExec, PermMemoryRead, PermAgentDelegate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithDelegation(context.Background(), tok)
+
+	tests := []struct {
+		name    string
+		subject string
+		perm    Permission
+		want    bool
+	}{
+		{"delegated permission", "planner", PermToolExec, true},
+		{"empty subject uses token subject", "", PermMemoryRead, true},
+		{"permission outside scope", "planner", PermMemoryWrite, false},
+		{"other subject", "coder", PermToolExec, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := p.Authorize(ctx, tt.subject, tt.perm, "calculator")
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Authorize(%q, %s) = %v, want %v", tt.subject, tt.perm, allowed, tt.want)
+			}
+		})
+	}
+
+	// Without a token, subjects act on their own behalf.
+	if allowed, _ := p.Authorize(context.Background(), "planner", PermToolExec, "calculator"); allowed {
+		t.Error("expected planner to have no permissions of its own")
+	}
+}
+
+func TestDelegationPolicy_BoundedByDelegator(t *testing.T) {
+	p := newDelegationTestPolicy(t)
+
+	// coder's own permissions are not consulted under a delegation, and a
+	// grant the delegator does not hold is worthless.
+	tok, err := Delegate(context.Background(), "alice", "coder", []Permission{PermToolExec, PermExternalAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithDelegation(context.Background(), tok)
+	if allowed, _ := p.Authorize(ctx, "coder", PermExternalAPI, "api"); allowed {
+		t.Error("expected a permission alice does not hold to be denied")
+	}
+	if allowed, _ := p.Authorize(ctx, "coder", PermToolExec, "calculator"); !allowed {
+		t.Error("expected alice's delegated permission to be allowed")
+	}
+
+	// Delegating requires PermAgentDelegate.
+	tok, err = Delegate(context.Background(), "coder", "helper", []Permission{PermExternalAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = WithDelegation(context.Background(), tok)
+	if allowed, _ := p.Authorize(ctx, "helper", PermExternalAPI, "api"); allowed {
+		t.Error("expected delegation by a subject without PermAgentDelegate to be denied")
+	}
+}
+
+func TestDelegate_Chain(t *testing.T) {
+	p := newDelegationTestPolicy(t)
+	root, err := Delegate(context.Background(), "alice", "planner", []Permission{"tool:*", PermAgentDelegate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithDelegation(context.Background(), root)
+
+	tok, err := Delegate(ctx, "planner", "coder", []Permission{PermToolExec})
+	if err != nil {
+		t.Fatalf("Delegate error: %v", err)
+	}
+	if got := tok.String(); got != "alice -> planner -> coder" {
+		t.Errorf("String() = %q", got)
+	}
+	if tok.Delegator() != "alice" || tok.Subject() != "coder" || len(tok.Chain()) != 2 {
+		t.Errorf("unexpected chain %+v", tok.Chain())
+	}
+	allowed, err := p.Authorize(WithDelegation(ctx, tok), "coder", PermToolExec, "calculator")
+	if err != nil || !allowed {
+		t.Errorf("expected re-delegated permission to be allowed, got %v, %v", allowed, err)
+	}
+
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		perms []Permission
+	}{
+		{"escalation", "planner", "coder", []Permission{PermMemoryWrite}},
+		{"broader pattern", "planner", "coder", []Permission{"*"}},
+		{"on behalf of another subject", "alice", "coder", []Permission{PermToolExec}},
+		{"cycle", "planner", "alice", []Permission{PermToolExec}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Delegate(ctx, tt.from, tt.to, tt.perms)
+			if !errors.Is(err, ErrInvalidDelegation) {
+				t.Errorf("expected ErrInvalidDelegation, got %v", err)
+			}
+		})
+	}
+
+	// Without PermAgentDelegate in its grant, coder cannot re-delegate.
+	if _, err := Delegate(WithDelegation(ctx, tok), "coder", "helper", []Permission{PermToolExec}); !errors.Is(err, ErrInvalidDelegation) {
+		t.Errorf("expected ErrInvalidDelegation, got %v", err)
+	}
+}
+
+func TestDelegate_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Delegate(ctx, "", "planner", []Permission{PermToolExec}); err == nil {
+		t.Error("expected error for empty delegator")
+	}
+	if _, err := Delegate(ctx, "alice", "planner", nil); err == nil {
+		t.Error("expected error for no permissions")
+	}
+	if _, err := Delegate(ctx, "alice", "alice", []Permission{PermToolExec}); err == nil {
+		t.Error("expected error for self-delegation")
+	}
+}
+
+func TestDelegate_Expiry(t *testing.T) {
+	p := newDelegationTestPolicy(t)
+	root, err := Delegate(context.Background(), "alice", "planner", []Permission{PermToolExec, PermAgentDelegate}, WithDelegationTTL(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A child never outlives its parent.
+	tok, err := Delegate(WithDelegation(context.Background(), root), "planner", "coder", []Permission{PermToolExec}, WithDelegationTTL(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tok.ExpiresAt().Equal(root.ExpiresAt()) {
+		t.Errorf("expected child to expire with its parent at %v, got %v", root.ExpiresAt(), tok.ExpiresAt())
+	}
+
+	ctx := WithDelegation(context.Background(), tok)
+	if allowed, err := p.Authorize(ctx, "coder", PermToolExec, "calculator"); err != nil || !allowed {
+		t.Fatalf("expected allow before expiry, got %v, %v", allowed, err)
+	}
+	p.now = func() time.Time { return time.Now().Add(time.Minute) }
+	if _, err := p.Authorize(ctx, "coder", PermToolExec, "calculator"); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestDelegation_AuditAndCache(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	inner := &countingPolicy{allowed: map[string]bool{"alice": true}}
+	p := ApplyMiddleware(NewDelegationPolicy("delegation", inner), WithAudit(logger), WithDecisionCache(time.Minute))
+
+	tok, err := Delegate(context.Background(), "alice", "planner", []Permission{PermToolExec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithDelegation(context.Background(), tok)
+	for range 2 {
+		if allowed, err := p.Authorize(ctx, "planner", PermToolExec, "calculator"); err != nil || !allowed {
+			t.Fatalf("expected allow, got %v, %v", allowed, err)
+		}
+	}
+	if !strings.Contains(buf.String(), `delegation="alice -> planner"`) {
+		t.Errorf("expected the delegation chain in the audit log, got %q", buf.String())
+	}
+	// Decisions depend on the token, so each request checks the delegator's
+	// PermAgentDelegate and the permission itself.
+	if got := inner.calls.Load(); got != 4 {
+		t.Errorf("expected delegated decisions not to be cached, got %d evaluations", got)
+	}
+}
@@ -22,6 +22,14 @@
 //	rbac.AssignRole("alice", "admin")
 //	allowed, err := rbac.Authorize(ctx, "alice", auth.PermToolExec, "calculator")
 //
+// Roles managed in an external store are loaded through a RoleProvider with
+// NewRBACPolicyWithProvider. They are cached for a TTL and reloaded without a
+// restart; each reload swaps roles and assignments together, so checks see a
+// consistent snapshot. Providers that also implement RoleWriter receive the
+// changes made with AddRole, AssignRole and RemoveRole.
+//
+//	rbac := auth.NewRBACPolicyWithProvider("main", dbRoles, auth.WithRoleCacheTTL(30*time.Second))
+//
 // # ABAC
 //
 // ABACPolicy implements attribute-based access control. Rules with conditions
@@ -36,6 +44,46 @@
 //	    Conditions: []auth.Condition{isProdEnv},
 //	})
 //
+// # Wildcards and Precedence
+//
+// Permissions (':'-separated) and resources ('/'-separated) are hierarchies
+// matched segment by segment with MatchPermission and MatchResource. A
+// trailing "*" segment matches everything below it, so a role granting
+// PermToolExec on "tools/*" authorizes "tools/calculator", and "tool:*"
+// grants every tool permission. Other segments may use path.Match globs.
+// Role.Resources scopes a role's permissions; empty means every resource.
+//
+// RBAC grants are additive: access is allowed if any assigned role matches,
+// and overlapping wildcards never restrict each other. ABAC rules, which can
+// use the same matching through PermissionMatches and ResourceMatches, are
+// evaluated by priority, deny before allow at equal priority, then in the
+// order added. In both, a request that nothing matches is denied.
+//
+// # Time and Quota Conditions
+//
+// TimeWindowCondition matches while the clock is within a recurring Schedule,
+// such as weekdays 09:00-17:00 in a given time zone, and costs nothing but a
+// clock read. QuotaCondition matches while a subject has made at most limit
+// requests in a fixed window, counting them with state.Increment in a
+// state.Store; use a shared store to enforce the quota across processes:
+//
+//	abac.AddRule(auth.Rule{Name: "api-quota", Effect: auth.EffectAllow, Priority: 20,
+//	    Conditions: []auth.Condition{
+//	        auth.PermissionMatches(auth.PermExternalAPI),
+//	        auth.TimeWindowCondition(businessHours),
+//	        auth.QuotaCondition(store, 1000, 24*time.Hour),
+//	    }})
+//	abac.AddRule(auth.Rule{Name: "api-over-quota", Effect: auth.EffectDeny, Priority: 10,
+//	    Conditions: []auth.Condition{auth.PermissionMatches(auth.PermExternalAPI)}})
+//
+// Every evaluation of a quota condition costs a store round trip and
+// consumes a unit of quota, so put it last in its rule. Counters reset when
+// a new window starts, windows being aligned to the Unix epoch, and expire
+// after two windows on a state.ExpiringStore; a store failure makes the
+// condition fail closed. Both conditions mark the decisions
+// they take part in as volatile (see MarkVolatile), so a DecisionCache never
+// stores them.
+//
 // # Composite Policies
 //
 // CompositePolicy combines multiple policies using configurable modes:
@@ -44,6 +92,50 @@
 //   - AllowIfAll allows access only if all child policies allow (logical AND).
 //   - DenyIfAny denies access if any child policy denies (conservative).
 //
+// # JWT and OIDC
+//
+// JWTPolicy connects identity to the policies above. It verifies the bearer
+// token carried in the context (signature, exp and nbf within a clock skew
+// tolerance, iss and aud), then delegates to an inner policy with the
+// token's subject. The verified claims are available through
+// ClaimsFromContext, and HasClaim turns them into ABAC conditions:
+//
+//	keys := auth.NewRemoteJWKS("https://issuer.example/.well-known/jwks.json")
+//	abac.AddRule(auth.Rule{
+//	    Name:       "admins",
+//	    Effect:     auth.EffectAllow,
+//	    Conditions: []auth.Condition{auth.HasClaim("groups", "admin")},
+//	})
+//	pol := auth.NewJWTPolicy("oidc", keys, abac,
+//	    auth.WithIssuer("https://issuer.example"),
+//	    auth.WithAudience("my-client-id"),
+//	)
+//	ctx = auth.WithBearerToken(ctx, token)
+//	allowed, err := pol.Authorize(ctx, "", auth.PermToolExec, "calculator")
+//
+// RemoteJWKS caches the issuer's key set and refetches it periodically and
+// when a token names an unknown key, so key rotation needs no restart.
+// Missing or invalid tokens return a core.ErrAuth error wrapping
+// ErrInvalidToken or ErrTokenExpired.
+//
+// # Delegation
+//
+// An agent holding PermAgentDelegate can hand a sub-agent a subset of its
+// permissions with Delegate. The resulting DelegationToken travels in the
+// context and is enforced by DelegationPolicy, which authorizes the
+// sub-agent as the original delegator restricted to the delegated scope:
+//
+//	tok, err := auth.Delegate(ctx, "orchestrator", "coder", []auth.Permission{auth.PermToolExec},
+//	    auth.WithDelegationTTL(10*time.Minute))
+//	subCtx := auth.WithDelegation(ctx, tok)
+//	pol := auth.NewDelegationPolicy("delegation", rbac)
+//	allowed, err := pol.Authorize(subCtx, "coder", auth.PermToolExec, "calculator")
+//
+// A sub-agent re-delegates by calling Delegate with a context carrying its
+// own token, which requires PermAgentDelegate in its grant and rejects any
+// permission outside it. Tokens record the whole chain (Chain, String), and
+// WithAudit logs it with every decision.
+//
 // # Built-in Permissions
 //
 // Standard permissions include PermToolExec, PermMemoryRead, PermMemoryWrite,
@@ -57,6 +149,11 @@
 //   - WithHooks wraps a Policy with lifecycle callbacks for OnAuthorize,
 //     OnAllow, OnDeny, and OnError events.
 //   - WithAudit wraps a Policy with slog-based audit logging.
+//   - WithDecisionCache memoizes decisions per (subject, permission,
+//     resource), with separate TTLs for allows and denies. Create the cache
+//     with NewDecisionCache to call Invalidate when a subject's roles change.
+//     Requests carrying tokens, claims or a SkipDecisionCache mark, and
+//     decisions marked volatile by their conditions, are never cached.
 //   - ApplyMiddleware composes middlewares in the standard right-to-left order.
 //
 // # Registry
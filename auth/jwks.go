@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// JWKSProvider supplies the public keys used to verify token signatures.
+// Implementations must be safe for concurrent use.
+type JWKSProvider interface {
+	// PublicKey returns the key with the given key ID. An empty kid selects
+	// the only key of a single-key set. The key is an *rsa.PublicKey,
+	// *ecdsa.PublicKey or ed25519.PublicKey.
+	PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// StaticJWKS is a fixed set of public keys indexed by key ID.
+type StaticJWKS map[string]crypto.PublicKey
+
+// PublicKey returns the key with the given key ID.
+func (s StaticJWKS) PublicKey(_ context.Context, kid string) (crypto.PublicKey, error) {
+	return lookupKey(s, kid)
+}
+
+// ParseJWKS parses a JSON Web Key Set document (RFC 7517). Keys of
+// unsupported types and keys marked for encryption ("use": "enc") are
+// skipped.
+func ParseJWKS(data []byte) (StaticJWKS, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "auth/jwks: invalid key set: %w", err)
+	}
+	keys := make(StaticJWKS, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "auth/jwks: key %q: %w", k.Kid, err)
+		}
+		if pub != nil {
+			keys[k.Kid] = pub
+		}
+	}
+	return keys, nil
+}
+
+// jwk is a JSON Web Key holding a public key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey decodes the key. It returns nil, nil for unsupported key types.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 2 || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA parameters")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid %s coordinates", k.Crv)
+		}
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+// lookupKey finds kid in keys, where an empty kid matches a single key.
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, error) {
+	if kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			return k, nil
+		}
+	}
+	if k, ok := keys[kid]; ok {
+		return k, nil
+	}
+	return nil, core.Errorf(core.ErrAuth, "auth/jwks: unknown key %q", kid)
+}
+
+// RemoteJWKS fetches a key set from a URL, such as an OIDC provider's
+// jwks_uri, and caches it. The set is refetched once the refresh interval has
+// passed, and early when a token names an unknown key ID, so signing key
+// rotation is picked up without a restart. Fetch attempts are at least the
+// minimum refresh interval apart; if a refetch fails the cached keys remain
+// in use until the next attempt.
+//
+// RemoteJWKS is safe for concurrent use.
+type RemoteJWKS struct {
+	url        string
+	client     *http.Client
+	refresh    time.Duration
+	minRefresh time.Duration
+	now        func() time.Time
+
+	group singleflight.Group
+
+	mu        sync.Mutex
+	keys      StaticJWKS
+	fetched   time.Time // last successful fetch
+	attempted time.Time // last fetch attempt
+}
+
+// JWKSOption configures a RemoteJWKS.
+type JWKSOption func(*RemoteJWKS)
+
+// WithJWKSHTTPClient sets the HTTP client used to fetch the key set. Default
+// is a client with a 10 second timeout.
+func WithJWKSHTTPClient(client *http.Client) JWKSOption {
+	return func(r *RemoteJWKS) {
+		r.client = client
+	}
+}
+
+// WithRefreshInterval sets how long a fetched key set is used before it is
+// refetched. Default is 1 hour.
+func WithRefreshInterval(d time.Duration) JWKSOption {
+	return func(r *RemoteJWKS) {
+		r.refresh = d
+	}
+}
+
+// WithMinRefreshInterval sets the minimum time between fetch attempts once a
+// key set has been fetched, whether they are due to an expired set or an
+// unknown key ID. It bounds the load a stream of forged tokens or a failing
+// key server puts on the server. Default is 1 minute.
+func WithMinRefreshInterval(d time.Duration) JWKSOption {
+	return func(r *RemoteJWKS) {
+		r.minRefresh = d
+	}
+}
+
+// NewRemoteJWKS creates a key set fetched from url. Nothing is fetched until
+// the first key is requested.
+func NewRemoteJWKS(url string, opts ...JWKSOption) *RemoteJWKS {
+	r := &RemoteJWKS{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		refresh:    time.Hour,
+		minRefresh: time.Minute,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// PublicKey returns the key with the given key ID, fetching the key set if
+// it is stale or does not contain kid. Concurrent callers share a single
+// fetch, which runs without holding the lock, and a stale set keeps being
+// served between fetch attempts, which are at least the minimum refresh
+// interval apart.
+func (r *RemoteJWKS) PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	now := r.now()
+	keys, fetched, attempted := r.snapshot()
+
+	var fetchErr error
+	switch {
+	case keys == nil:
+		keys, fetchErr = r.refetch(ctx)
+	case now.Sub(fetched) >= r.refresh && now.Sub(attempted) >= r.minRefresh:
+		keys, fetchErr = r.refetch(ctx)
+	}
+	if keys == nil {
+		return nil, fetchErr
+	}
+	if key, err := lookupKey(keys, kid); err == nil {
+		return key, nil
+	}
+	if _, _, attempted = r.snapshot(); fetchErr == nil && now.Sub(attempted) >= r.minRefresh {
+		keys, fetchErr = r.refetch(ctx)
+	}
+	key, err := lookupKey(keys, kid)
+	if err != nil && fetchErr != nil {
+		return nil, fetchErr
+	}
+	return key, err
+}
+
+// snapshot returns the cached key set and fetch timestamps.
+func (r *RemoteJWKS) snapshot() (keys StaticJWKS, fetched, attempted time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys, r.fetched, r.attempted
+}
+
+// refetch fetches the key set, joining a fetch already in flight. It returns
+// the key set in use afterwards, which is the previous one if the fetch
+// failed. The fetch is not canceled with ctx, so that other callers waiting
+// on it are unaffected; the caller stops waiting when ctx is done.
+func (r *RemoteJWKS) refetch(ctx context.Context) (StaticJWKS, error) {
+	ch := r.group.DoChan("", func() (any, error) {
+		return r.fetch(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-ch:
+		keys, _ := res.Val.(StaticJWKS)
+		return keys, res.Err
+	case <-ctx.Done():
+		keys, _, _ := r.snapshot()
+		return keys, core.Errorf(core.ErrTimeout, "auth/jwks: waiting for key set: %w", ctx.Err())
+	}
+}
+
+// fetch downloads and parses the key set and caches it. It returns the
+// cached key set along with any error.
+func (r *RemoteJWKS) fetch(ctx context.Context) (StaticJWKS, error) {
+	r.mu.Lock()
+	r.attempted = r.now()
+	r.mu.Unlock()
+
+	keys, err := r.download(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		return r.keys, err
+	}
+	r.keys = keys
+	r.fetched = r.now()
+	return keys, nil
+}
+
+// download fetches and parses the key set document.
+func (r *RemoteJWKS) download(ctx context.Context) (StaticJWKS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "auth/jwks: failed to create request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "auth/jwks: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, core.Errorf(core.ErrProviderDown, "auth/jwks: key server returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "auth/jwks: failed to read response body: %w", err)
+	}
+	return ParseJWKS(body)
+}
+
+// decodeSegment decodes unpadded base64url, as used by JWK and JWS.
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// Ensure the key sets implement JWKSProvider at compile time.
+var (
+	_ JWKSProvider = StaticJWKS(nil)
+	_ JWKSProvider = (*RemoteJWKS)(nil)
+)
@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwkJSON returns the JWK representation of a test public key.
+func jwkJSON(t *testing.T, kid string, pub crypto.PublicKey) map[string]any {
+	t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return map[string]any{"kty": "RSA", "kid": kid, "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		point, err := k.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (len(point) - 1) / 2
+		return map[string]any{"kty": "EC", "kid": kid, "crv": k.Curve.Params().Name, "x": b64(point[1 : 1+size]), "y": b64(point[1+size:])}
+	case ed25519.PublicKey:
+		return map[string]any{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k)}
+	}
+	t.Fatalf("unsupported key %T", pub)
+	return nil
+}
+
+// jwksDocument returns a JWKS document holding the given test keys.
+func jwksDocument(t *testing.T, kids ...string) []byte {
+	t.Helper()
+	keys := make([]map[string]any, 0, len(kids))
+	for _, kid := range kids {
+		keys = append(keys, jwkJSON(t, kid, testKeys[kid].Public()))
+	}
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseJWKS(t *testing.T) {
+	keys, err := ParseJWKS(jwksDocument(t, "rsa", "p256", "p384", "ed"))
+	if err != nil {
+		t.Fatalf("ParseJWKS error: %v", err)
+	}
+	for kid, signer := range testKeys {
+		pub, err := keys.PublicKey(context.Background(), kid)
+		if err != nil {
+			t.Fatalf("PublicKey(%q) error: %v", kid, err)
+		}
+		if !signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(pub) {
+			t.Errorf("key %q did not round-trip", kid)
+		}
+	}
+}
+
+func TestParseJWKS_Skipped(t *testing.T) {
+	doc := `{"keys": [
+		{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"},
+		{"kty": "OKP", "kid": "enc", "use": "enc", "crv": "X25519", "x": "AAAA"}
+	]}`
+	keys, err := ParseJWKS([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseJWKS error: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected no usable keys, got %d", len(keys))
+	}
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "not JSON", doc: `{`},
+		{name: "bad modulus", doc: `{"keys": [{"kty": "RSA", "kid": "a", "n": "!!", "e": "AQAB"}]}`},
+		{name: "unknown curve", doc: `{"keys": [{"kty": "EC", "kid": "a", "crv": "P-192", "x": "AA", "y": "AA"}]}`},
+		{name: "point not on curve", doc: `{"keys": [{"kty": "EC", "kid": "a", "crv": "P-256", "x": "` + base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `", "y": "` + base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `"}]}`},
+		{name: "short Ed25519 key", doc: `{"keys": [{"kty": "OKP", "kid": "a", "crv": "Ed25519", "x": "AAAA"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJWKS([]byte(tt.doc)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStaticJWKS_SingleKeyWithoutKid(t *testing.T) {
+	keys := StaticJWKS{"only": testKeys["ed"].Public()}
+	if _, err := keys.PublicKey(context.Background(), ""); err != nil {
+		t.Errorf("expected the single key for an empty kid, got %v", err)
+	}
+	keys["second"] = testKeys["rsa"].Public()
+	if _, err := keys.PublicKey(context.Background(), ""); err == nil {
+		t.Error("expected error for an empty kid with several keys")
+	}
+}
+
+// jwksServer serves a key set that tests can swap, counting requests.
+type jwksServer struct {
+	*httptest.Server
+	requests atomic.Int32
+
+	mu     sync.Mutex
+	doc    []byte
+	status int
+}
+
+func newJWKSServer(t *testing.T, doc []byte) *jwksServer {
+	t.Helper()
+	s := &jwksServer{doc: doc, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.WriteHeader(s.status)
+		w.Write(s.doc)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(doc []byte, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc, s.status = doc, status
+}
+
+func TestRemoteJWKS_Refresh(t *testing.T) {
+	ctx := context.Background()
+	srv := newJWKSServer(t, jwksDocument(t, "rsa"))
+	now := time.Unix(0, 0)
+	r := NewRemoteJWKS(srv.URL, WithRefreshInterval(time.Hour), WithMinRefreshInterval(time.Minute))
+	r.now = func() time.Time { return now }
+
+	if _, err := r.PublicKey(ctx, "rsa"); err != nil {
+		t.Fatalf("PublicKey error: %v", err)
+	}
+	if _, err := r.PublicKey(ctx, "rsa"); err != nil {
+		t.Fatalf("PublicKey error: %v", err)
+	}
+	if got := srv.requests.Load(); got != 1 {
+		t.Fatalf("expected the key set to be cached, got %d requests", got)
+	}
+
+	// The issuer rotates its key: an unknown kid triggers a refetch.
+	srv.set(jwksDocument(t, "rsa", "p256"), http.StatusOK)
+	now = now.Add(time.Minute)
+	if _, err := r.PublicKey(ctx, "p256"); err != nil {
+		t.Fatalf("expected rotated key to be found, got %v", err)
+	}
+
+	// Unknown kids do not refetch again within the minimum interval.
+	if _, err := r.PublicKey(ctx, "forged"); err == nil {
+		t.Error("expected error for unknown kid")
+	}
+	if got := srv.requests.Load(); got != 2 {
+		t.Errorf("expected refetches to be rate limited, got %d requests", got)
+	}
+
+	// A stale set is refetched; if that fails the cached keys stay usable.
+	srv.set(nil, http.StatusInternalServerError)
+	now = now.Add(2 * time.Hour)
+	if _, err := r.PublicKey(ctx, "rsa"); err != nil {
+		t.Errorf("expected cached key after failed refresh, got %v", err)
+	}
+	if got := srv.requests.Load(); got != 3 {
+		t.Errorf("expected a refresh attempt, got %d requests", got)
+	}
+
+	// The stale set is served without refetching until the minimum
+	// interval has passed since the failed attempt.
+	now = now.Add(time.Second)
+	if _, err := r.PublicKey(ctx, "rsa"); err != nil {
+		t.Errorf("expected stale key between attempts, got %v", err)
+	}
+	if got := srv.requests.Load(); got != 3 {
+		t.Errorf("expected stale refreshes to be rate limited, got %d requests", got)
+	}
+	now = now.Add(time.Minute)
+	if _, err := r.PublicKey(ctx, "rsa"); err != nil {
+		t.Errorf("expected cached key after failed refresh, got %v", err)
+	}
+	if got := srv.requests.Load(); got != 4 {
+		t.Errorf("expected a second refresh attempt, got %d requests", got)
+	}
+}
+
+func TestRemoteJWKS_ConcurrentFetch(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	doc := jwksDocument(t, "rsa")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write(doc)
+	}))
+	defer srv.Close()
+	r := NewRemoteJWKS(srv.URL)
+
+	errs := make(chan error, 10)
+	for range cap(errs) {
+		go func() {
+			_, err := r.PublicKey(context.Background(), "rsa")
+			errs <- err
+		}()
+	}
+	// A caller that gives up does not cancel the shared fetch.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := r.PublicKey(ctx, "rsa"); err == nil {
+		t.Error("expected error when the caller's context expires")
+	}
+	close(release)
+
+	for range cap(errs) {
+		if err := <-errs; err != nil {
+			t.Errorf("PublicKey error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected concurrent callers to share one fetch, got %d requests", got)
+	}
+}
+
+func TestRemoteJWKS_Unavailable(t *testing.T) {
+	srv := newJWKSServer(t, nil)
+	srv.set(nil, http.StatusServiceUnavailable)
+	r := NewRemoteJWKS(srv.URL)
+	if _, err := r.PublicKey(context.Background(), "rsa"); err == nil {
+		t.Error("expected error when the key server is unavailable")
+	}
+}
+
+func TestRemoteJWKS_WithJWTPolicy(t *testing.T) {
+	srv := newJWKSServer(t, jwksDocument(t, "p256"))
+	p := NewJWTPolicy("oidc", NewRemoteJWKS(srv.URL, WithJWKSHTTPClient(srv.Client())), newAliceRBAC(t))
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	claims["nbf"] = time.Now().Add(-time.Minute).Unix()
+
+	ctx := WithBearerToken(context.Background(), signToken(t, "ES256", "p256", claims))
+	allowed, err := p.Authorize(ctx, "", PermToolExec, "calculator")
+	if err != nil {
+		t.Fatalf("Authorize error: %v", err)
+	}
+	if !allowed {
+		t.Error("expected alice to be allowed")
+	}
+}
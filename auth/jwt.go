@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrInvalidToken is returned when a bearer token is malformed, its signature
+// does not verify, or its claims fail validation.
+var ErrInvalidToken = errors.New("auth/jwt: invalid token")
+
+// ErrTokenExpired is returned when a bearer token's exp claim has passed.
+var ErrTokenExpired = errors.New("auth/jwt: token expired")
+
+// Claims holds the verified claims of a token.
+type Claims map[string]any
+
+// Subject returns the sub claim.
+func (c Claims) Subject() string { return c.String("sub") }
+
+// Issuer returns the iss claim.
+func (c Claims) Issuer() string { return c.String("iss") }
+
+// Audience returns the aud claim, which may be a string or a list.
+func (c Claims) Audience() []string { return c.Strings("aud") }
+
+// String returns the named claim if it is a string, otherwise "".
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns the named claim as a list. A string claim yields a
+// single-element list; non-string elements are skipped.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// time returns a NumericDate claim. ok is false if the claim is absent;
+// err is set if it is not a number.
+func (c Claims) time(name string) (t time.Time, ok bool, err error) {
+	v, present := c[name]
+	if !present {
+		return time.Time{}, false, nil
+	}
+	n, isNum := v.(json.Number)
+	if !isNum {
+		return time.Time{}, false, core.Errorf(core.ErrAuth, "auth/jwt: %s claim is not a number: %w", name, ErrInvalidToken)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, core.Errorf(core.ErrAuth, "auth/jwt: %s claim is not a number: %w", name, ErrInvalidToken)
+	}
+	sec := int64(f)
+	return time.Unix(sec, int64((f-float64(sec))*1e9)), true, nil
+}
+
+// HasClaim returns a Condition that matches when the verified claims in the
+// context contain a claim name equal to value, or a list claim containing
+// value. Use it in ABAC rules behind a JWTPolicy, for example to key off a
+// "groups" or "roles" claim.
+func HasClaim(name, value string) Condition {
+	return func(ctx context.Context, _ string, _ Permission, _ string) bool {
+		return slices.Contains(ClaimsFromContext(ctx).Strings(name), value)
+	}
+}
+
+// JWTPolicy authenticates callers by verifying the JWT bearer token carried
+// in the context, as issued by an OIDC provider, and then delegates the
+// authorization decision to an inner policy. The inner policy receives the
+// token's subject, and the verified claims are available to it through
+// ClaimsFromContext, so RBAC assignments can key off the subject and ABAC
+// conditions off any claim.
+//
+// A token is accepted when its signature verifies against a key from the
+// key set, its exp claim (required) and nbf claim are satisfied within the
+// clock skew tolerance, and its iss and aud claims match the configured
+// issuer and audience. Asymmetric algorithms only are accepted: RS256,
+// RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 and EdDSA.
+//
+// JWTPolicy is safe for concurrent use.
+type JWTPolicy struct {
+	name     string
+	keys     JWKSProvider
+	inner    Policy
+	issuer   string
+	audience string
+	skew     time.Duration
+	now      func() time.Time
+}
+
+// JWTOption configures a JWTPolicy.
+type JWTOption func(*JWTPolicy)
+
+// WithIssuer requires the iss claim to equal issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(p *JWTPolicy) {
+		p.issuer = issuer
+	}
+}
+
+// WithAudience requires the aud claim to contain audience, typically the
+// OIDC client ID. Setting it is strongly recommended: without it, tokens the
+// issuer minted for other applications are accepted.
+func WithAudience(audience string) JWTOption {
+	return func(p *JWTPolicy) {
+		p.audience = audience
+	}
+}
+
+// WithClockSkew sets the tolerance applied to the exp and nbf claims to
+// allow for clock drift between the issuer and this host. Default is 1
+// minute.
+func WithClockSkew(d time.Duration) JWTOption {
+	return func(p *JWTPolicy) {
+		p.skew = d
+	}
+}
+
+// NewJWTPolicy creates a policy that verifies bearer tokens against keys and
+// delegates authorization to inner.
+func NewJWTPolicy(name string, keys JWKSProvider, inner Policy, opts ...JWTOption) *JWTPolicy {
+	p := &JWTPolicy{
+		name:  name,
+		keys:  keys,
+		inner: inner,
+		skew:  time.Minute,
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Name returns the policy name.
+func (p *JWTPolicy) Name() string { return p.name }
+
+// Authorize verifies the bearer token from the context and asks the inner
+// policy whether the token's subject may perform permission on resource.
+// Returns a core.ErrAuth error if the token is missing or invalid. If subject
+// is not empty it must equal the token's subject, otherwise access is
+// denied.
+func (p *JWTPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
+	token := BearerTokenFromContext(ctx)
+	if token == "" {
+		return false, core.Errorf(core.ErrAuth, "auth/jwt: no bearer token in context")
+	}
+	claims, err := p.Verify(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	sub := claims.Subject()
+	if subject != "" && subject != sub {
+		return false, nil
+	}
+	return p.inner.Authorize(WithClaims(ctx, claims), sub, permission, resource)
+}
+
+// Verify checks the token's signature and claims and returns the claims.
+// Failures are core.ErrAuth errors wrapping ErrInvalidToken or
+// ErrTokenExpired.
+func (p *JWTPolicy) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: malformed token: %w", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg  string   `json:"alg"`
+		Kid  string   `json:"kid"`
+		Crit []string `json:"crit"`
+	}
+	if err := decodeJSONSegment(parts[0], &header); err != nil {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: malformed header: %w", ErrInvalidToken)
+	}
+	if len(header.Crit) > 0 {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: unsupported critical header %q: %w", header.Crit, ErrInvalidToken)
+	}
+	key, err := p.keys.PublicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: malformed signature: %w", ErrInvalidToken)
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: %s: %w", err.Error(), ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeJSONSegment(parts[1], &claims); err != nil || claims == nil {
+		return nil, core.Errorf(core.ErrAuth, "auth/jwt: malformed claims: %w", ErrInvalidToken)
+	}
+	if err := p.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// validate checks the registered claims.
+func (p *JWTPolicy) validate(claims Claims) error {
+	now := p.now()
+	exp, ok, err := claims.time("exp")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return core.Errorf(core.ErrAuth, "auth/jwt: missing exp claim: %w", ErrInvalidToken)
+	}
+	if !now.Before(exp.Add(p.skew)) {
+		return core.Errorf(core.ErrAuth, "auth/jwt: expired at %s: %w", exp.UTC().Format(time.RFC3339), ErrTokenExpired)
+	}
+	nbf, ok, err := claims.time("nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(p.skew).Before(nbf) {
+		return core.Errorf(core.ErrAuth, "auth/jwt: not valid before %s: %w", nbf.UTC().Format(time.RFC3339), ErrInvalidToken)
+	}
+	if p.issuer != "" && claims.Issuer() != p.issuer {
+		return core.Errorf(core.ErrAuth, "auth/jwt: unexpected issuer %q: %w", claims.Issuer(), ErrInvalidToken)
+	}
+	if p.audience != "" && !slices.Contains(claims.Audience(), p.audience) {
+		return core.Errorf(core.ErrAuth, "auth/jwt: token not issued for audience %q: %w", p.audience, ErrInvalidToken)
+	}
+	return nil
+}
+
+// decodeJSONSegment decodes a base64url JSON segment, keeping numbers as
+// json.Number so NumericDate claims are exact.
+func decodeJSONSegment(seg string, v any) error {
+	data, err := decodeSegment(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// verifySignature checks sig over signingInput with key according to alg.
+// The algorithm must match the key type, so a token cannot select a weaker
+// or different scheme than the key was published for.
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+	default:
+		return errors.New("unsupported algorithm " + strings.ToValidUTF8(alg, "?"))
+	}
+
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write([]byte(signingInput))
+		digest = h.Sum(nil)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(k, hash, digest, sig)
+		case "PS":
+			err = rsa.VerifyPSS(k, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return errors.New("algorithm " + alg + " does not match RSA key")
+		}
+		if err != nil {
+			return errors.New("signature verification failed")
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || !ecdsaMatches(alg, size) {
+			return errors.New("algorithm " + alg + " does not match EC key")
+		}
+		if len(sig) != 2*size {
+			return errors.New("signature verification failed")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("signature verification failed")
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return errors.New("algorithm " + alg + " does not match Ed25519 key")
+		}
+		if !ed25519.Verify(k, []byte(signingInput), sig) {
+			return errors.New("signature verification failed")
+		}
+	default:
+		return errors.New("unsupported key type")
+	}
+	return nil
+}
+
+// ecdsaMatches reports whether an ES algorithm uses the curve with the given
+// coordinate size (RFC 7518 section 3.4).
+func ecdsaMatches(alg string, size int) bool {
+	switch alg {
+	case "ES256":
+		return size == 32
+	case "ES384":
+		return size == 48
+	case "ES512":
+		return size == 66
+	}
+	return false
+}
+
+// Ensure JWTPolicy implements Policy at compile time.
+var _ Policy = (*JWTPolicy)(nil)
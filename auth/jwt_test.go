@@ -0,0 +1,350 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// testKeys holds one signing key per supported key type, generated once.
+var testKeys = func() map[string]crypto.Signer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return map[string]crypto.Signer{"rsa": rsaKey, "p256": p256, "p384": p384, "ed": edKey}
+}()
+
+// testJWKS returns the public halves of testKeys.
+func testJWKS() StaticJWKS {
+	keys := make(StaticJWKS, len(testKeys))
+	for kid, k := range testKeys {
+		keys[kid] = k.Public()
+	}
+	return keys
+}
+
+var testNow = time.Unix(1_700_000_000, 0)
+
+// signToken builds a JWT with the given alg and claims, signed with
+// testKeys[kid].
+func signToken(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	header := map[string]any{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	return signWithHeader(t, header, kid, claims)
+}
+
+// signWithHeader builds a JWT with an arbitrary header, signed with
+// testKeys[kid] according to the header's alg. An empty kid leaves the
+// signature empty.
+func signWithHeader(t *testing.T, header map[string]any, kid string, claims map[string]any) string {
+	t.Helper()
+	enc := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := enc(header) + "." + enc(claims)
+	alg, _ := header["alg"].(string)
+
+	var sig []byte
+	key := testKeys[kid]
+	var hash crypto.Hash
+	switch alg[len(alg)-3:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	}
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write([]byte(input))
+		digest = h.Sum(nil)
+	}
+	var err error
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if strings.HasPrefix(alg, "PS") {
+			sig, err = rsa.SignPSS(rand.Reader, k, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		}
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		if r, s, err = ecdsa.Sign(rand.Reader, k, digest); err == nil {
+			size := (k.Curve.Params().BitSize + 7) / 8
+			sig = make([]byte, 2*size)
+			r.FillBytes(sig[:size])
+			s.FillBytes(sig[size:])
+		}
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(input))
+	}
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// validClaims returns claims accepted by newTestJWTPolicy.
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "alice",
+		"iss": "https://issuer.example",
+		"aud": []string{"beluga", "other"},
+		"exp": testNow.Add(time.Hour).Unix(),
+		"nbf": testNow.Add(-time.Minute).Unix(),
+	}
+}
+
+func newTestJWTPolicy(inner Policy, opts ...JWTOption) *JWTPolicy {
+	opts = append([]JWTOption{WithIssuer("https://issuer.example"), WithAudience("beluga")}, opts...)
+	p := NewJWTPolicy("jwt", testJWKS(), inner, opts...)
+	p.now = func() time.Time { return testNow }
+	return p
+}
+
+func newAliceRBAC(t *testing.T) *RBACPolicy {
+	t.Helper()
+	rbac := NewRBACPolicy("rbac")
+	if err := rbac.AddRole(Role{Name: "user", Permissions: []Permission{PermToolExec}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.AssignRole("alice", "user"); err != nil {
+		t.Fatal(err)
+	}
+	return rbac
+}
+
+func TestJWTPolicy_Algorithms(t *testing.T) {
+	p := newTestJWTPolicy(newAliceRBAC(t))
+	tests := []struct {
+		alg string
+		kid string
+	}{
+		{alg: "RS256", kid: "rsa"},
+		{alg: "RS512", kid: "rsa"},
+		{alg: "PS256", kid: "rsa"},
+		{alg: "ES256", kid: "p256"},
+		{alg: "ES384", kid: "p384"},
+		{alg: "EdDSA", kid: "ed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			ctx := WithBearerToken(context.Background(), signToken(t, tt.alg, tt.kid, validClaims()))
+			allowed, err := p.Authorize(ctx, "", PermToolExec, "calculator")
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if !allowed {
+				t.Error("expected alice to be allowed")
+			}
+
+			allowed, err = p.Authorize(ctx, "", PermMemoryWrite, "history")
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if allowed {
+				t.Error("expected inner policy to deny memory:write")
+			}
+		})
+	}
+}
+
+func TestJWTPolicy_Rejects(t *testing.T) {
+	p := newTestJWTPolicy(newAliceRBAC(t), WithClockSkew(30*time.Second))
+	with := func(key string, value any) map[string]any {
+		c := validClaims()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+	// The claims of one token with the signature of another.
+	tampered := func() string {
+		valid := strings.Split(signToken(t, "RS256", "rsa", validClaims()), ".")
+		forged := strings.Split(signToken(t, "RS256", "rsa", with("sub", "mallory")), ".")
+		return valid[0] + "." + forged[1] + "." + valid[2]
+	}()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "expired", token: signToken(t, "RS256", "rsa", with("exp", testNow.Add(-time.Minute).Unix())), wantErr: ErrTokenExpired},
+		{name: "missing exp", token: signToken(t, "RS256", "rsa", with("exp", nil)), wantErr: ErrInvalidToken},
+		{name: "string exp", token: signToken(t, "RS256", "rsa", with("exp", "tomorrow")), wantErr: ErrInvalidToken},
+		{name: "not yet valid", token: signToken(t, "RS256", "rsa", with("nbf", testNow.Add(time.Minute).Unix())), wantErr: ErrInvalidToken},
+		{name: "wrong issuer", token: signToken(t, "RS256", "rsa", with("iss", "https://evil.example")), wantErr: ErrInvalidToken},
+		{name: "wrong audience", token: signToken(t, "RS256", "rsa", with("aud", "other")), wantErr: ErrInvalidToken},
+		{name: "tampered claims", token: tampered, wantErr: ErrInvalidToken},
+		{name: "alg none", token: signWithHeader(t, map[string]any{"alg": "none", "kid": "rsa"}, "", validClaims()), wantErr: ErrInvalidToken},
+		{name: "HMAC", token: signWithHeader(t, map[string]any{"alg": "HS256", "kid": "rsa"}, "", validClaims()), wantErr: ErrInvalidToken},
+		{name: "alg does not match key", token: signWithHeader(t, map[string]any{"alg": "ES256", "kid": "rsa"}, "p256", validClaims()), wantErr: ErrInvalidToken},
+		{name: "curve does not match alg", token: signWithHeader(t, map[string]any{"alg": "ES256", "kid": "p384"}, "p384", validClaims()), wantErr: ErrInvalidToken},
+		{name: "critical header", token: signWithHeader(t, map[string]any{"alg": "RS256", "kid": "rsa", "crit": []string{"x"}}, "rsa", validClaims()), wantErr: ErrInvalidToken},
+		{name: "malformed", token: "not-a-jwt", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithBearerToken(context.Background(), tt.token)
+			allowed, err := p.Authorize(ctx, "", PermToolExec, "calculator")
+			if allowed {
+				t.Error("expected token to be rejected")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			var ce *core.Error
+			if !errors.As(err, &ce) || ce.Code != core.ErrAuth {
+				t.Errorf("expected core.ErrAuth, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTPolicy_ClockSkew(t *testing.T) {
+	claims := validClaims()
+	claims["exp"] = testNow.Add(-20 * time.Second).Unix()
+	claims["nbf"] = testNow.Add(20 * time.Second).Unix()
+	ctx := WithBearerToken(context.Background(), signToken(t, "ES256", "p256", claims))
+
+	if _, err := newTestJWTPolicy(newAliceRBAC(t), WithClockSkew(0)).Authorize(ctx, "", PermToolExec, "calculator"); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired without skew, got %v", err)
+	}
+	allowed, err := newTestJWTPolicy(newAliceRBAC(t)).Authorize(ctx, "", PermToolExec, "calculator")
+	if err != nil || !allowed {
+		t.Fatalf("expected token within default skew to be accepted, got %v, %v", allowed, err)
+	}
+}
+
+func TestJWTPolicy_UnknownKey(t *testing.T) {
+	p := newTestJWTPolicy(newAliceRBAC(t))
+	token := signToken(t, "RS256", "rsa", validClaims())
+	p.keys = StaticJWKS{"other": testKeys["rsa"].Public()}
+
+	_, err := p.Authorize(WithBearerToken(context.Background(), token), "", PermToolExec, "calculator")
+	var ce *core.Error
+	if !errors.As(err, &ce) || ce.Code != core.ErrAuth {
+		t.Errorf("expected core.ErrAuth, got %v", err)
+	}
+}
+
+func TestJWTPolicy_MissingToken(t *testing.T) {
+	p := newTestJWTPolicy(newAliceRBAC(t))
+	allowed, err := p.Authorize(context.Background(), "alice", PermToolExec, "calculator")
+	if allowed {
+		t.Error("expected deny without token")
+	}
+	var ce *core.Error
+	if !errors.As(err, &ce) || ce.Code != core.ErrAuth {
+		t.Errorf("expected core.ErrAuth, got %v", err)
+	}
+}
+
+func TestJWTPolicy_SubjectMismatch(t *testing.T) {
+	p := newTestJWTPolicy(newAliceRBAC(t))
+	ctx := WithBearerToken(context.Background(), signToken(t, "RS256", "rsa", validClaims()))
+
+	allowed, err := p.Authorize(ctx, "bob", PermToolExec, "calculator")
+	if err != nil {
+		t.Fatalf("Authorize error: %v", err)
+	}
+	if allowed {
+		t.Error("expected deny when subject does not match the token")
+	}
+
+	allowed, err = p.Authorize(ctx, "alice", PermToolExec, "calculator")
+	if err != nil || !allowed {
+		t.Errorf("expected allow for matching subject, got %v, %v", allowed, err)
+	}
+}
+
+func TestJWTPolicy_ClaimsAsAttributes(t *testing.T) {
+	abac := NewABACPolicy("abac")
+	if err := abac.AddRule(Rule{
+		Name:       "admins",
+		Effect:     EffectAllow,
+		Conditions: []Condition{HasClaim("groups", "admin")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p := newTestJWTPolicy(abac)
+
+	tests := []struct {
+		name   string
+		groups any
+		want   bool
+	}{
+		{name: "list claim", groups: []string{"dev", "admin"}, want: true},
+		{name: "string claim", groups: "admin", want: true},
+		{name: "not a member", groups: []string{"dev"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			claims["groups"] = tt.groups
+			ctx := WithBearerToken(context.Background(), signToken(t, "EdDSA", "ed", claims))
+			allowed, err := p.Authorize(ctx, "", PermAgentDelegate, "planner")
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("expected allowed=%v, got %v", tt.want, allowed)
+			}
+		})
+	}
+}
+
+func TestJWTPolicy_Verify(t *testing.T) {
+	p := newTestJWTPolicy(nil)
+	claims, err := p.Verify(context.Background(), signToken(t, "PS256", "rsa", validClaims()))
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if claims.Subject() != "alice" || claims.Issuer() != "https://issuer.example" {
+		t.Errorf("unexpected claims: %v", claims)
+	}
+	if got := claims.Audience(); len(got) != 2 || got[0] != "beluga" {
+		t.Errorf("unexpected audience: %v", got)
+	}
+	if p.Name() != "jwt" {
+		t.Errorf("expected name 'jwt', got %q", p.Name())
+	}
+}
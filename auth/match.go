@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// MatchPermission reports whether the permission pattern grants perm.
+// Permissions are hierarchies of ':'-separated segments, such as
+// "tool:exec:calculator". A pattern matches when it has the same segments,
+// where:
+//
+//   - "*" alone matches every permission;
+//   - a trailing "*" segment matches one or more remaining segments, so
+//     "tool:*" matches "tool:execute" and "tool:exec:calculator" but not
+//     "tool";
+//   - any other segment may use path.Match syntax ("*", "?", "[...]") and
+//     matches exactly one segment, so "memory:re*" matches "memory:read".
+//
+// A pattern without wildcards matches only itself.
+func MatchPermission(pattern, perm Permission) bool {
+	return matchSegments(string(pattern), string(perm), ":")
+}
+
+// MatchResource reports whether the resource pattern matches resource.
+// Resources are hierarchies of '/'-separated segments, such as
+// "tools/calculator", and patterns follow the same rules as MatchPermission:
+// "tools/*" matches "tools/calculator" and "tools/math/calculator" but not
+// "tools", and "tools/calc*" matches "tools/calculator" only.
+func MatchResource(pattern, resource string) bool {
+	return matchSegments(pattern, resource, "/")
+}
+
+// matchSegments matches value against pattern segment by segment.
+func matchSegments(pattern, value, sep string) bool {
+	if pattern == value || pattern == "*" {
+		return true
+	}
+	ps := strings.Split(pattern, sep)
+	vs := strings.Split(value, sep)
+	for i, p := range ps {
+		if p == "*" && i == len(ps)-1 {
+			return len(vs) > i
+		}
+		if i >= len(vs) {
+			return false
+		}
+		if p == vs[i] {
+			continue
+		}
+		if ok, err := path.Match(p, vs[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return len(ps) == len(vs)
+}
+
+// PermissionMatches returns a Condition that matches when the requested
+// permission matches any of the patterns, as defined by MatchPermission.
+func PermissionMatches(patterns ...Permission) Condition {
+	return func(_ context.Context, _ string, permission Permission, _ string) bool {
+		for _, p := range patterns {
+			if MatchPermission(p, permission) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ResourceMatches returns a Condition that matches when the requested
+// resource matches any of the patterns, as defined by MatchResource.
+func ResourceMatches(patterns ...string) Condition {
+	return func(_ context.Context, _ string, _ Permission, resource string) bool {
+		return matchesAnyResource(patterns, resource)
+	}
+}
+
+// matchesAnyResource reports whether resource matches one of patterns.
+func matchesAnyResource(patterns []string, resource string) bool {
+	for _, p := range patterns {
+		if MatchResource(p, resource) {
+			return true
+		}
+	}
+	return false
+}
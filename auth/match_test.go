@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		pattern Permission
+		perm    Permission
+		want    bool
+	}{
+		{"tool:execute", "tool:execute", true},
+		{"tool:execute", "tool:exec", false},
+		{"*", "tool:exec:calculator", true},
+		{"tool:*", "tool:execute", true},
+		{"tool:*", "tool:exec:calculator", true},
+		{"tool:*", "tool", false},
+		{"tool:exec:*", "tool:exec:calculator", true},
+		{"tool:exec:*", "tool:execute", false},
+		{"memory:re*", "memory:read", true},
+		{"memory:re*", "memory:read:history", false},
+		{"*:read", "memory:read", true},
+		{"*:read", "memory:write", false},
+		{"agent:?elegate", "agent:delegate", true},
+		{"tool:[", "tool:[", true},
+		{"tool:[", "tool:x", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPermission(tt.pattern, tt.perm); got != tt.want {
+			t.Errorf("MatchPermission(%q, %q) = %v, want %v", tt.pattern, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestMatchResource(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		resource string
+		want     bool
+	}{
+		{"tools/calculator", "tools/calculator", true},
+		{"tools/*", "tools/calculator", true},
+		{"tools/*", "tools/math/calculator", true},
+		{"tools/*", "tools", false},
+		{"tools/*", "toolsets/x", false},
+		{"tools/calc*", "tools/calculator", true},
+		{"tools/calc*", "tools/calc/extra", false},
+		{"*/calculator", "tools/calculator", true},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := MatchResource(tt.pattern, tt.resource); got != tt.want {
+			t.Errorf("MatchResource(%q, %q) = %v, want %v", tt.pattern, tt.resource, got, tt.want)
+		}
+	}
+}
+
+func TestRBACPolicy_Wildcards(t *testing.T) {
+	ctx := context.Background()
+	p := NewRBACPolicy("rbac")
+	roles := []Role{
+		{Name: "tool-user", Permissions: []Permission{PermToolExec}, Resources: []string{"tools/*"}},
+		{Name: "calc-admin", Permissions: []Permission{"tool:*"}, Resources: []string{"tools/calculator"}},
+		{Name: "reader", Permissions: []Permission{"memory:read"}},
+	}
+	for _, role := range roles {
+		if err := p.AddRole(role); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.AssignRole("alice", role.Name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		perm     Permission
+		resource string
+		want     bool
+	}{
+		{"resource wildcard", PermToolExec, "tools/calculator", true},
+		{"nested resource", PermToolExec, "tools/math/solver", true},
+		{"resource outside grant", PermToolExec, "admin/shutdown", false},
+		{"permission wildcard on exact resource", "tool:configure", "tools/calculator", true},
+		{"permission wildcard elsewhere", "tool:configure", "tools/search", false},
+		{"unscoped role", PermMemoryRead, "anything", true},
+		{"default deny", PermMemoryWrite, "tools/calculator", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := p.Authorize(ctx, "alice", tt.perm, tt.resource)
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Authorize(%s, %s) = %v, want %v", tt.perm, tt.resource, allowed, tt.want)
+			}
+		})
+	}
+}
+
+func TestABACPolicy_WildcardPrecedence(t *testing.T) {
+	ctx := context.Background()
+	p := NewABACPolicy("abac")
+	rules := []Rule{
+		// Added first, but a deny at the same priority is evaluated first.
+		{
+			Name:       "allow-tools",
+			Effect:     EffectAllow,
+			Conditions: []Condition{PermissionMatches("tool:*"), ResourceMatches("tools/*")},
+		},
+		{
+			Name:       "deny-shell",
+			Effect:     EffectDeny,
+			Conditions: []Condition{ResourceMatches("tools/shell", "tools/shell/*")},
+		},
+		// A higher priority allow overrides the deny for one tool.
+		{
+			Name:       "allow-safe-shell",
+			Effect:     EffectAllow,
+			Priority:   10,
+			Conditions: []Condition{PermissionMatches(PermToolExec), ResourceMatches("tools/shell/ls")},
+		},
+	}
+	for _, rule := range rules {
+		if err := p.AddRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		perm     Permission
+		resource string
+		want     bool
+	}{
+		{"allowed by wildcard", PermToolExec, "tools/calculator", true},
+		{"deny wins at equal priority", PermToolExec, "tools/shell/rm", false},
+		{"higher priority allow", PermToolExec, "tools/shell/ls", true},
+		{"default deny for other permissions", PermMemoryRead, "tools/calculator", false},
+		{"default deny for other resources", PermToolExec, "files/x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := p.Authorize(ctx, "alice", tt.perm, tt.resource)
+			if err != nil {
+				t.Fatalf("Authorize error: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("Authorize(%s, %s) = %v, want %v", tt.perm, tt.resource, allowed, tt.want)
+			}
+		})
+	}
+}
@@ -68,7 +68,8 @@ func (p *hookedPolicy) Authorize(ctx context.Context, subject string, permission
 }
 
 // WithAudit returns middleware that logs all Authorize calls using the
-// provided slog.Logger.
+// provided slog.Logger. Requests made under a delegation token also log the
+// delegation chain.
 func WithAudit(logger *slog.Logger) Middleware {
 	return func(next Policy) Policy {
 		return &auditPolicy{next: next, logger: logger}
@@ -86,28 +87,22 @@ func (p *auditPolicy) Name() string { return p.next.Name() }
 func (p *auditPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
 	allowed, err := p.next.Authorize(ctx, subject, permission, resource)
 
+	attrs := []any{
+		"policy", p.next.Name(),
+		"subject", subject,
+		"permission", string(permission),
+		"resource", resource,
+	}
+	if tok := DelegationFromContext(ctx); tok != nil {
+		attrs = append(attrs, "delegation", tok.String())
+	}
+
 	if err != nil {
-		p.logger.ErrorContext(ctx, "auth.authorize.error",
-			"policy", p.next.Name(),
-			"subject", subject,
-			"permission", string(permission),
-			"resource", resource,
-			"error", err,
-		)
+		p.logger.ErrorContext(ctx, "auth.authorize.error", append(attrs, "error", err)...)
 	} else if allowed {
-		p.logger.InfoContext(ctx, "auth.authorize.allow",
-			"policy", p.next.Name(),
-			"subject", subject,
-			"permission", string(permission),
-			"resource", resource,
-		)
+		p.logger.InfoContext(ctx, "auth.authorize.allow", attrs...)
 	} else {
-		p.logger.WarnContext(ctx, "auth.authorize.deny",
-			"policy", p.next.Name(),
-			"subject", subject,
-			"permission", string(permission),
-			"resource", resource,
-		)
+		p.logger.WarnContext(ctx, "auth.authorize.deny", attrs...)
 	}
 
 	return allowed, err
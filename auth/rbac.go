@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
 )
@@ -13,14 +14,53 @@ type Role struct {
 	// Name uniquely identifies this role.
 	Name string
 
-	// Permissions lists the actions this role grants.
+	// Permissions lists the actions this role grants. Entries may be
+	// wildcard patterns, see MatchPermission.
 	Permissions []Permission
+
+	// Resources restricts the resources the permissions apply to, as
+	// patterns matched with MatchResource. Empty means every resource.
+	Resources []string
+}
+
+// RoleSet is a complete set of roles and subject assignments, as loaded from
+// a RoleProvider.
+type RoleSet struct {
+	// Roles lists the defined roles.
+	Roles []Role
+
+	// Assignments maps each subject to the names of its roles.
+	Assignments map[string][]string
+}
+
+// RoleProvider loads roles and assignments from an external source such as a
+// database. Implementations must be safe for concurrent use.
+type RoleProvider interface {
+	// LoadRoles returns the current roles and assignments.
+	LoadRoles(ctx context.Context) (RoleSet, error)
+}
+
+// RoleWriter is implemented by a RoleProvider that accepts changes. When the
+// provider of an RBACPolicy implements it, AddRole, AssignRole and RemoveRole
+// write through to the provider before updating the policy.
+type RoleWriter interface {
+	// SaveRole stores a new role.
+	SaveRole(ctx context.Context, role Role) error
+
+	// SaveAssignment assigns the named role to subject.
+	SaveAssignment(ctx context.Context, subject, roleName string) error
+
+	// DeleteAssignment removes the named role from subject.
+	DeleteAssignment(ctx context.Context, subject, roleName string) error
 }
 
 // RBACPolicy implements role-based access control. Subjects are assigned one or
 // more roles, and authorization checks whether any assigned role contains the
 // requested permission.
 //
+// Roles are either configured in code with AddRole and AssignRole, or loaded
+// from a RoleProvider (see NewRBACPolicyWithProvider).
+//
 // RBACPolicy is safe for concurrent use.
 type RBACPolicy struct {
 	name string
@@ -28,6 +68,26 @@ type RBACPolicy struct {
 	mu          sync.RWMutex
 	roles       map[string]*Role    // roleName -> Role
 	assignments map[string][]string // subject -> []roleName
+
+	// Provider state. loadMu serializes loads and write-backs so that a
+	// load in flight cannot discard a concurrent write.
+	provider  RoleProvider
+	ttl       time.Duration
+	now       func() time.Time
+	loadMu    sync.Mutex
+	loaded    bool      // guarded by mu
+	attempted time.Time // last load attempt; guarded by mu
+}
+
+// RBACOption configures an RBACPolicy backed by a RoleProvider.
+type RBACOption func(*RBACPolicy)
+
+// WithRoleCacheTTL sets how long loaded roles are used before they are
+// reloaded from the provider. Default is 1 minute.
+func WithRoleCacheTTL(d time.Duration) RBACOption {
+	return func(p *RBACPolicy) {
+		p.ttl = d
+	}
 }
 
 // NewRBACPolicy creates a new RBAC policy with the given name.
@@ -36,71 +96,217 @@ func NewRBACPolicy(name string) *RBACPolicy {
 		name:        name,
 		roles:       make(map[string]*Role),
 		assignments: make(map[string][]string),
+		now:         time.Now,
+	}
+}
+
+// NewRBACPolicyWithProvider creates an RBAC policy whose roles and
+// assignments are loaded from provider and cached. The first authorization
+// check loads them, and they are reloaded once the cache TTL has passed, so
+// changes in the source take effect without a restart. A reload replaces
+// roles and assignments together, so every check sees one consistent
+// snapshot. If a reload fails, the previous snapshot stays in use and the
+// load is retried after another TTL; if the first load fails, Authorize
+// returns the error.
+//
+// If provider implements RoleWriter, AddRole, AssignRole and RemoveRole
+// write through to it; otherwise their changes are local and are discarded
+// by the next reload.
+func NewRBACPolicyWithProvider(name string, provider RoleProvider, opts ...RBACOption) *RBACPolicy {
+	p := NewRBACPolicy(name)
+	p.provider = provider
+	p.ttl = time.Minute
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Refresh reloads roles and assignments from the provider immediately. It is
+// a no-op for a policy without a provider.
+func (p *RBACPolicy) Refresh(ctx context.Context) error {
+	if p.provider == nil {
+		return nil
+	}
+	p.loadMu.Lock()
+	defer p.loadMu.Unlock()
+	return p.load(ctx)
+}
+
+// ensureLoaded reloads from the provider if the cache has expired. It only
+// returns an error if no snapshot has ever been loaded.
+func (p *RBACPolicy) ensureLoaded(ctx context.Context) error {
+	if p.provider == nil || p.fresh() {
+		return nil
+	}
+	p.loadMu.Lock()
+	defer p.loadMu.Unlock()
+	if p.fresh() { // loaded while waiting for loadMu
+		return nil
+	}
+	err := p.load(ctx)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.loaded {
+		return nil
+	}
+	return err
+}
+
+// fresh reports whether the cached snapshot is within its TTL.
+func (p *RBACPolicy) fresh() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.loaded && p.now().Sub(p.attempted) < p.ttl
+}
+
+// load fetches a snapshot from the provider and swaps it in. It must be
+// called with loadMu held.
+func (p *RBACPolicy) load(ctx context.Context) error {
+	set, err := p.provider.LoadRoles(ctx)
+	now := p.now()
+	if err != nil {
+		p.mu.Lock()
+		p.attempted = now
+		p.mu.Unlock()
+		return core.Errorf(core.ErrProviderDown, "auth/rbac: load roles: %w", err)
+	}
+
+	roles := make(map[string]*Role, len(set.Roles))
+	for _, role := range set.Roles {
+		r := role // copy
+		roles[role.Name] = &r
+	}
+	assignments := make(map[string][]string, len(set.Assignments))
+	for subject, names := range set.Assignments {
+		assignments[subject] = append([]string(nil), names...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roles = roles
+	p.assignments = assignments
+	p.loaded = true
+	p.attempted = now
+	return nil
+}
+
+// writer returns the provider as a RoleWriter, after making sure roles are
+// loaded so that local validation sees the provider's state. It returns nil
+// for policies without a provider or with a read-only one.
+func (p *RBACPolicy) writer(ctx context.Context) (RoleWriter, error) {
+	if err := p.ensureLoaded(ctx); err != nil {
+		return nil, err
 	}
+	w, _ := p.provider.(RoleWriter)
+	return w, nil
 }
 
 // Name returns the policy name.
 func (p *RBACPolicy) Name() string { return p.name }
 
 // AddRole registers a role. Returns an error if a role with the same name
-// already exists.
+// already exists. With a RoleWriter provider the role is saved to the
+// provider first.
 func (p *RBACPolicy) AddRole(role Role) error {
 	if role.Name == "" {
 		return core.Errorf(core.ErrInvalidInput, "auth/rbac: role name must not be empty")
 	}
 
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	return p.update(func(ctx context.Context, w RoleWriter) error {
+		if _, exists := p.roles[role.Name]; exists {
+			return core.Errorf(core.ErrInvalidInput, "auth/rbac: role %q already exists", role.Name)
+		}
+		if w != nil {
+			if err := w.SaveRole(ctx, role); err != nil {
+				return core.Errorf(core.ErrProviderDown, "auth/rbac: save role %q: %w", role.Name, err)
+			}
+		}
+		r := role // copy
+		p.roles[role.Name] = &r
+		return nil
+	})
+}
 
-	if _, exists := p.roles[role.Name]; exists {
-		return core.Errorf(core.ErrInvalidInput, "auth/rbac: role %q already exists", role.Name)
+// update runs fn with the policy locked for writing and, when the policy has
+// a RoleWriter provider, the writer to save changes through. fn runs with
+// loadMu held as well, so no reload can interleave with the write-back.
+func (p *RBACPolicy) update(fn func(ctx context.Context, w RoleWriter) error) error {
+	ctx := context.Background()
+	var w RoleWriter
+	if p.provider != nil {
+		var err error
+		if w, err = p.writer(ctx); err != nil {
+			return err
+		}
+		p.loadMu.Lock()
+		defer p.loadMu.Unlock()
 	}
-	r := role // copy
-	p.roles[role.Name] = &r
-	return nil
-}
 
-// AssignRole assigns a named role to a subject. Returns an error if the role
-// does not exist or is already assigned.
-func (p *RBACPolicy) AssignRole(subject, roleName string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	return fn(ctx, w)
+}
 
-	if _, exists := p.roles[roleName]; !exists {
-		return core.Errorf(core.ErrNotFound, "auth/rbac: role %q does not exist", roleName)
-	}
+// AssignRole assigns a named role to a subject. Returns an error if the role
+// does not exist or is already assigned. With a RoleWriter provider the
+// assignment is saved to the provider first.
+func (p *RBACPolicy) AssignRole(subject, roleName string) error {
+	return p.update(func(ctx context.Context, w RoleWriter) error {
+		if _, exists := p.roles[roleName]; !exists {
+			return core.Errorf(core.ErrNotFound, "auth/rbac: role %q does not exist", roleName)
+		}
 
-	for _, r := range p.assignments[subject] {
-		if r == roleName {
-			return core.Errorf(core.ErrInvalidInput, "auth/rbac: role %q already assigned to %q", roleName, subject)
+		for _, r := range p.assignments[subject] {
+			if r == roleName {
+				return core.Errorf(core.ErrInvalidInput, "auth/rbac: role %q already assigned to %q", roleName, subject)
+			}
 		}
-	}
 
-	p.assignments[subject] = append(p.assignments[subject], roleName)
-	return nil
+		if w != nil {
+			if err := w.SaveAssignment(ctx, subject, roleName); err != nil {
+				return core.Errorf(core.ErrProviderDown, "auth/rbac: assign role %q to %q: %w", roleName, subject, err)
+			}
+		}
+		p.assignments[subject] = append(p.assignments[subject], roleName)
+		return nil
+	})
 }
 
 // RemoveRole removes a role assignment from a subject. Returns an error if the
-// role is not assigned to the subject.
+// role is not assigned to the subject. With a RoleWriter provider the
+// assignment is deleted from the provider first.
 func (p *RBACPolicy) RemoveRole(subject, roleName string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	roles := p.assignments[subject]
-	for i, r := range roles {
-		if r == roleName {
-			p.assignments[subject] = append(roles[:i], roles[i+1:]...)
-			return nil
+	return p.update(func(ctx context.Context, w RoleWriter) error {
+		roles := p.assignments[subject]
+		for i, r := range roles {
+			if r == roleName {
+				if w != nil {
+					if err := w.DeleteAssignment(ctx, subject, roleName); err != nil {
+						return core.Errorf(core.ErrProviderDown, "auth/rbac: remove role %q from %q: %w", roleName, subject, err)
+					}
+				}
+				p.assignments[subject] = append(roles[:i], roles[i+1:]...)
+				return nil
+			}
 		}
-	}
-	return core.Errorf(core.ErrNotFound, "auth/rbac: role %q not assigned to %q", roleName, subject)
+		return core.Errorf(core.ErrNotFound, "auth/rbac: role %q not assigned to %q", roleName, subject)
+	})
 }
 
 // Authorize checks whether subject has permission on resource. It iterates
 // through all roles assigned to the subject and returns true if any role
-// contains the requested permission. Default deny: returns false if no role
-// grants the permission.
+// grants a permission pattern matching the requested permission on a
+// resource pattern matching resource. Grants are additive: overlapping
+// wildcard grants never restrict each other. Default deny: returns false if
+// no role grants the permission. A policy with a provider first reloads roles if the
+// cache has expired, and returns an error if they could never be loaded.
 func (p *RBACPolicy) Authorize(ctx context.Context, subject string, permission Permission, resource string) (bool, error) {
+	if err := p.ensureLoaded(ctx); err != nil {
+		return false, err
+	}
+
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -110,8 +316,11 @@ func (p *RBACPolicy) Authorize(ctx context.Context, subject string, permission P
 		if !ok {
 			continue
 		}
+		if len(role.Resources) > 0 && !matchesAnyResource(role.Resources, resource) {
+			continue
+		}
 		for _, perm := range role.Permissions {
-			if perm == permission {
+			if MatchPermission(perm, permission) {
 				return true, nil
 			}
 		}
@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestRBACPolicy_Name(t *testing.T) {
@@ -174,3 +177,205 @@ func TestRBACPolicy_RemoveRoleDeniesAccess(t *testing.T) {
 		t.Error("expected denied after role removal")
 	}
 }
+
+// mockRoleProvider is a RoleProvider backed by a RoleSet that tests mutate.
+type mockRoleProvider struct {
+	mu    sync.Mutex
+	set   RoleSet
+	err   error
+	loads int
+}
+
+func (m *mockRoleProvider) LoadRoles(context.Context) (RoleSet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loads++
+	if m.err != nil {
+		return RoleSet{}, m.err
+	}
+	set := RoleSet{Roles: append([]Role(nil), m.set.Roles...), Assignments: make(map[string][]string)}
+	for subject, roles := range m.set.Assignments {
+		set.Assignments[subject] = append([]string(nil), roles...)
+	}
+	return set, nil
+}
+
+func (m *mockRoleProvider) update(fn func(*mockRoleProvider)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(m)
+}
+
+// mockRoleWriter adds write-back to mockRoleProvider.
+type mockRoleWriter struct {
+	mockRoleProvider
+	writeErr error
+}
+
+func (m *mockRoleWriter) SaveRole(_ context.Context, role Role) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.set.Roles = append(m.set.Roles, role)
+	return nil
+}
+
+func (m *mockRoleWriter) SaveAssignment(_ context.Context, subject, roleName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.set.Assignments[subject] = append(m.set.Assignments[subject], roleName)
+	return nil
+}
+
+func (m *mockRoleWriter) DeleteAssignment(_ context.Context, subject, roleName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	roles := m.set.Assignments[subject]
+	for i, r := range roles {
+		if r == roleName {
+			m.set.Assignments[subject] = append(roles[:i], roles[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func newProviderSet() RoleSet {
+	return RoleSet{
+		Roles:       []Role{{Name: "reader", Permissions: []Permission{PermMemoryRead}}},
+		Assignments: map[string][]string{"alice": {"reader"}},
+	}
+}
+
+func TestRBACPolicy_ProviderRefresh(t *testing.T) {
+	ctx := context.Background()
+	provider := &mockRoleProvider{set: newProviderSet()}
+	now := time.Unix(0, 0)
+	p := NewRBACPolicyWithProvider("rbac", provider, WithRoleCacheTTL(time.Minute))
+	p.now = func() time.Time { return now }
+
+	allowed, err := p.Authorize(ctx, "alice", PermMemoryRead, "history")
+	if err != nil || !allowed {
+		t.Fatalf("expected alice to be allowed, got %v, %v", allowed, err)
+	}
+
+	// Changes in the source are picked up once the cache expires.
+	provider.update(func(m *mockRoleProvider) { m.set.Assignments = map[string][]string{"bob": {"reader"}} })
+	allowed, _ = p.Authorize(ctx, "alice", PermMemoryRead, "history")
+	if !allowed {
+		t.Error("expected cached snapshot before the TTL expires")
+	}
+	now = now.Add(time.Minute)
+	allowed, _ = p.Authorize(ctx, "alice", PermMemoryRead, "history")
+	if allowed {
+		t.Error("expected alice to be denied after reload")
+	}
+	allowed, _ = p.Authorize(ctx, "bob", PermMemoryRead, "history")
+	if !allowed {
+		t.Error("expected bob to be allowed after reload")
+	}
+
+	// A failed reload keeps the previous snapshot and retries after the TTL.
+	provider.update(func(m *mockRoleProvider) { m.err = errors.New("db down") })
+	now = now.Add(time.Minute)
+	allowed, err = p.Authorize(ctx, "bob", PermMemoryRead, "history")
+	if err != nil || !allowed {
+		t.Errorf("expected stale snapshot after failed reload, got %v, %v", allowed, err)
+	}
+	_, _ = p.Authorize(ctx, "bob", PermMemoryRead, "history")
+	if provider.loads != 3 {
+		t.Errorf("expected 3 loads, got %d", provider.loads)
+	}
+	if err := p.Refresh(ctx); err == nil {
+		t.Error("expected Refresh to report the provider error")
+	}
+}
+
+func TestRBACPolicy_ProviderInitialLoadFails(t *testing.T) {
+	provider := &mockRoleProvider{err: errors.New("db down")}
+	p := NewRBACPolicyWithProvider("rbac", provider)
+	allowed, err := p.Authorize(context.Background(), "alice", PermMemoryRead, "history")
+	if err == nil || allowed {
+		t.Errorf("expected error before roles were ever loaded, got %v, %v", allowed, err)
+	}
+}
+
+func TestRBACPolicy_ProviderWriteBack(t *testing.T) {
+	ctx := context.Background()
+	provider := &mockRoleWriter{mockRoleProvider: mockRoleProvider{set: newProviderSet()}}
+	p := NewRBACPolicyWithProvider("rbac", provider)
+
+	if err := p.AddRole(Role{Name: "operator", Permissions: []Permission{PermToolExec}}); err != nil {
+		t.Fatalf("AddRole failed: %v", err)
+	}
+	if err := p.AssignRole("carol", "operator"); err != nil {
+		t.Fatalf("AssignRole failed: %v", err)
+	}
+	if err := p.RemoveRole("alice", "reader"); err != nil {
+		t.Fatalf("RemoveRole failed: %v", err)
+	}
+	// Roles loaded from the provider are validated locally.
+	if err := p.AddRole(Role{Name: "reader"}); err == nil {
+		t.Error("expected error for a role that exists in the provider")
+	}
+
+	// The changes survive a reload because they were written through.
+	if err := p.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	allowed, _ := p.Authorize(ctx, "carol", PermToolExec, "calculator")
+	if !allowed {
+		t.Error("expected carol to be allowed after reload")
+	}
+	allowed, _ = p.Authorize(ctx, "alice", PermMemoryRead, "history")
+	if allowed {
+		t.Error("expected alice to be denied after reload")
+	}
+
+	// A failed write leaves the policy unchanged.
+	provider.writeErr = errors.New("read-only replica")
+	if err := p.AssignRole("dave", "operator"); err == nil {
+		t.Fatal("expected write-back error")
+	}
+	allowed, _ = p.Authorize(ctx, "dave", PermToolExec, "calculator")
+	if allowed {
+		t.Error("expected failed assignment not to be applied")
+	}
+}
+
+func TestRBACPolicy_ProviderConsistentSnapshot(t *testing.T) {
+	ctx := context.Background()
+	// Two snapshots that each grant alice exactly one permission; a check
+	// mixing roles from one with assignments from the other grants none.
+	snapshots := []RoleSet{
+		{Roles: []Role{{Name: "a", Permissions: []Permission{PermMemoryRead}}}, Assignments: map[string][]string{"alice": {"a"}}},
+		{Roles: []Role{{Name: "b", Permissions: []Permission{PermMemoryRead}}}, Assignments: map[string][]string{"alice": {"b"}}},
+	}
+	provider := &mockRoleProvider{set: snapshots[0]}
+	p := NewRBACPolicyWithProvider("rbac", provider, WithRoleCacheTTL(0))
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			for j := range 200 {
+				if i == 0 {
+					provider.update(func(m *mockRoleProvider) { m.set = snapshots[j%2] })
+					continue
+				}
+				allowed, err := p.Authorize(ctx, "alice", PermMemoryRead, "history")
+				if err != nil || !allowed {
+					t.Errorf("inconsistent snapshot: %v, %v", allowed, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
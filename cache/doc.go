@@ -1,6 +1,6 @@
 // Package cache provides exact and semantic caching for the Beluga AI framework.
 // It defines the Cache interface for key-value storage with TTL support, a registry
-// for pluggable cache backends, and a SemanticCache wrapper for embedding-based
+// for pluggable cache backends, and a SemanticCache for embedding-based
 // similarity lookups.
 //
 // # Cache Interface
@@ -16,13 +16,42 @@
 //
 // Cache backends register via the standard Beluga registry pattern. Import a
 // provider package for side-effect registration, then create instances via New.
+// Two backends are provided: "inmemory", a per-process LRU cache, and
+// "redis", which instances of an application share.
 //
 // # SemanticCache
 //
-// SemanticCache wraps any Cache to provide similarity-based lookups using
-// embedding vectors. When an exact key match is not found, it falls back to
-// comparing embedding vectors using cosine similarity. Configure the minimum
-// similarity threshold when creating the wrapper.
+// SemanticCache is a Cache that matches keys by similarity rather than
+// equality: keys are embedded and compared by cosine similarity against the
+// stored entries, and the best match above the threshold is a hit.
+// SemanticCache holds its entries in process memory unless WithBackend gives
+// it a Cache to keep the values in, such as a "redis" cache shared by every
+// instance. The embeddings stay in process: exact-key lookups hit values set
+// by any instance, while similarity matches are found among the keys the
+// instance set itself.
+//
+//	backend, err := cache.New("redis", cache.Config{
+//	    TTL:     time.Hour,
+//	    Options: map[string]any{"addr": "localhost:6379", "namespace": "myapp:semantic"},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	sc := cache.NewSemanticCache(embedder, cache.WithBackend(backend))
+//
+// # Stampede Protection
+//
+// WithSingleflight wraps any Cache, including a SemanticCache, with
+// GetOrCompute, which returns a cached value or computes and stores it on a
+// miss. Concurrent misses for the same key share a single computation, so an
+// expired hot key does not send every caller to the backend at once. A
+// computation error is returned to every caller waiting on it and is not
+// cached; the next call retries.
+//
+//	sf := cache.WithSingleflight(c)
+//	val, err := sf.GetOrCompute(ctx, "key", time.Minute, func(ctx context.Context) (any, error) {
+//	    return expensiveLookup(ctx)
+//	})
 //
 // # Usage
 //
@@ -45,10 +74,10 @@
 //
 // Semantic caching:
 //
-//	sc := cache.NewSemanticCache(c, 0.95) // 95% similarity threshold
-//	err = sc.SetSemantic(ctx, embedding, cachedResponse)
+//	sc := cache.NewSemanticCache(embedder, cache.WithThreshold(0.95))
+//	err = sc.Set(ctx, "What is the capital of France?", cachedResponse, 0)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-//	val, ok, err = sc.GetSemantic(ctx, queryEmbedding, 0)
+//	val, ok, err = sc.Get(ctx, "What's France's capital?")
 package cache
@@ -0,0 +1,53 @@
+// Package redis provides a Redis-backed cache implementation for the Beluga
+// AI framework, so application instances share one cache. It uses
+// github.com/redis/go-redis/v9 and registers itself under the name "redis"
+// in the cache registry.
+//
+// # Usage
+//
+// Import for side-effect registration, then create via the cache registry:
+//
+//	import _ "github.com/lookatitude/beluga-ai/v2/cache/providers/redis"
+//
+//	c, err := cache.New("redis", cache.Config{
+//	    TTL: 10 * time.Minute,
+//	    Options: map[string]any{
+//	        "addr":      "localhost:6379",
+//	        "namespace": "myapp:llm",
+//	        "fail_open": true,
+//	    },
+//	})
+//
+// Or create directly with [New]; [RedisCache.Close] closes the client the
+// cache created.
+//
+// # Keys and Values
+//
+// Each entry is stored under "<namespace>:<key>", with "beluga:cache" as the
+// default namespace, so caches with different namespaces share a database
+// without colliding. Values are encoded as JSON. Get returns them as
+// encoding/json decodes into an any, so numbers come back as float64 and
+// structs as map[string]any; [RedisCache.GetInto] decodes into a typed
+// destination instead. TTLs map to native Redis expiry: a zero TTL uses
+// Config.TTL and a negative one stores the entry without expiry.
+// Config.MaxSize is ignored; configure maxmemory-policy on the server to bound
+// memory.
+//
+// Clear deletes only the keys of the cache's namespace, walking them with
+// SCAN and removing them with UNLINK, so it never blocks the server or
+// flushes other data. Each key is unlinked by its own command, so Clear also
+// works against a Redis Cluster, where it scans every master.
+//
+// A cache.SemanticCache created with cache.WithBackend keeps its values in
+// this provider; give it a namespace of its own, as its Clear clears the
+// namespace.
+//
+// # Fail-Open Mode
+//
+// By default a cache operation that cannot reach Redis returns an error with
+// code core.ErrProviderDown. With Options["fail_open"] set to true,
+// connection failures are logged as warnings instead: Get reports a miss and
+// Set, Delete and Clear do nothing, so an outage of the cache slows the
+// application down rather than failing it. Error replies from the server and
+// cancelled contexts are still returned.
+package redis
@@ -0,0 +1,233 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/lookatitude/beluga-ai/v2/cache"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func init() {
+	cache.Register("redis", func(cfg cache.Config) (cache.Cache, error) {
+		return New(cfg)
+	})
+}
+
+const (
+	// defaultNamespace prefixes the keys of a cache created without a
+	// "namespace" option.
+	defaultNamespace = "beluga:cache"
+
+	// scanBatch is the COUNT hint of the SCAN calls made by Clear.
+	scanBatch = 500
+)
+
+// globEscaper escapes the characters special in SCAN MATCH patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// RedisCache is a Redis-backed implementation of the cache.Cache interface,
+// so several instances of an application share one cache. Values are stored
+// as JSON under "<namespace>:<key>" with native Redis expiry.
+type RedisCache struct {
+	client     goredis.UniversalClient
+	ownsClient bool
+	prefix     string
+	defaultTTL time.Duration
+	failOpen   bool
+}
+
+// Compile-time interface check.
+var _ cache.Cache = (*RedisCache)(nil)
+
+// New creates a RedisCache from cfg. cfg.TTL is the default TTL; cfg.MaxSize
+// is ignored, since Redis bounds memory with its own eviction policy.
+// Recognised Options keys are "addr" (required unless "client" is set),
+// "password", "db", "client" (a goredis.UniversalClient, which the cache does
+// not close), "namespace" (default "beluga:cache") and "fail_open" (a bool).
+func New(cfg cache.Config) (*RedisCache, error) {
+	namespace := defaultNamespace
+	if v, ok := cfg.Options["namespace"]; ok {
+		ns, _ := v.(string)
+		if ns == "" {
+			return nil, core.Errorf(core.ErrInvalidInput, "redis: namespace must be a non-empty string")
+		}
+		namespace = ns
+	}
+	failOpen, _ := cfg.Options["fail_open"].(bool)
+
+	c := &RedisCache{
+		prefix:     namespace + ":",
+		defaultTTL: cfg.TTL,
+		failOpen:   failOpen,
+	}
+	if client, ok := cfg.Options["client"].(goredis.UniversalClient); ok {
+		c.client = client
+		return c, nil
+	}
+
+	addr, _ := cfg.Options["addr"].(string)
+	if addr == "" {
+		return nil, core.Errorf(core.ErrInvalidInput, "redis: addr is required")
+	}
+	password, _ := cfg.Options["password"].(string)
+	var db int
+	switch v := cfg.Options["db"].(type) {
+	case int:
+		db = v
+	case float64:
+		db = int(v)
+	}
+	c.client = goredis.NewClient(&goredis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+	c.ownsClient = true
+	return c, nil
+}
+
+// Get retrieves the value stored under key. Values are decoded from JSON
+// into the types encoding/json produces for an any: numbers become float64,
+// objects map[string]any. Use GetInto to decode into a typed value.
+func (c *RedisCache) Get(ctx context.Context, key string) (any, bool, error) {
+	var value any
+	found, err := c.get(ctx, key, &value)
+	if !found || err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// GetInto decodes the value stored under key into dest, which must be a
+// non-nil pointer, and reports whether the key was found.
+func (c *RedisCache) GetInto(ctx context.Context, key string, dest any) (bool, error) {
+	return c.get(ctx, key, dest)
+}
+
+func (c *RedisCache) get(ctx context.Context, key string, dest any) (bool, error) {
+	raw, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, c.fail(err, "get "+strconv.Quote(key))
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, core.Errorf(core.ErrInvalidInput, "redis: get %q: decode: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores value, encoded as JSON, under key. A zero TTL uses the cache's
+// default TTL; a negative TTL, or a zero TTL without a default, stores the
+// entry without expiration.
+func (c *RedisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "redis: set %q: encode: %w", key, err)
+	}
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+	// go-redis reads a negative expiration as KEEPTTL.
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := c.client.Set(ctx, c.prefix+key, raw, ttl).Err(); err != nil {
+		return c.fail(err, "set "+strconv.Quote(key))
+	}
+	return nil
+}
+
+// Delete removes key from the cache. Deleting a missing key is a no-op.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		return c.fail(err, "delete "+strconv.Quote(key))
+	}
+	return nil
+}
+
+// Clear removes the entries of the cache's namespace, leaving other keys in
+// the database alone. Keys are found with SCAN, so the server is never
+// blocked; entries written during the scan may survive it. With a Redis
+// Cluster client every master is scanned, and keys are unlinked one per
+// command, pipelined, so no command spans hash slots.
+func (c *RedisCache) Clear(ctx context.Context) error {
+	var err error
+	if cluster, ok := c.client.(*goredis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return c.clearNode(ctx, node)
+		})
+	} else {
+		err = c.clearNode(ctx, c.client)
+	}
+	if err != nil {
+		return c.fail(err, "clear")
+	}
+	return nil
+}
+
+// clearNode unlinks the namespace's keys found by scanning client.
+func (c *RedisCache) clearNode(ctx context.Context, client goredis.Cmdable) error {
+	match := globEscaper.Replace(c.prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, match, scanBatch).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			_, err := client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.Unlink(ctx, key)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Close closes the Redis client if the cache created it.
+func (c *RedisCache) Close() error {
+	if c.ownsClient {
+		return c.client.Close()
+	}
+	return nil
+}
+
+// fail returns the error for a failed op. In fail-open mode a
+// connection failure is logged and swallowed, so callers see a miss or a
+// skipped write instead of an error; errors returned by the server and
+// context cancellation are always returned.
+func (c *RedisCache) fail(err error, op string) error {
+	if c.failOpen && isConnError(err) {
+		slog.Warn("redis: cache unavailable, failing open", "op", op, "error", err)
+		return nil
+	}
+	return core.Errorf(core.ErrProviderDown, "redis: %s: %w", op, err)
+}
+
+// isConnError reports whether err is a failure to reach the server rather
+// than an error reply or a cancelled context.
+func isConnError(err error) bool {
+	var reply goredis.Error
+	if errors.As(err, &reply) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
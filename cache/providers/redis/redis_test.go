@@ -0,0 +1,273 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/lookatitude/beluga-ai/v2/cache"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func newTestCache(t *testing.T, options map[string]any) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	opts := map[string]any{"addr": mr.Addr()}
+	for k, v := range options {
+		opts[k] = v
+	}
+	c, err := New(cache.Config{TTL: time.Minute, Options: opts})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, mr
+}
+
+// newUnreachableCache returns a cache whose server has shut down. Its
+// client does not retry, to keep the test fast.
+func newUnreachableCache(t *testing.T, failOpen bool) *RedisCache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	c, err := New(cache.Config{Options: map[string]any{"client": client, "fail_open": failOpen}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	mr.Close()
+	return c
+}
+
+func TestRegistry(t *testing.T) {
+	if !slices.Contains(cache.List(), "redis") {
+		t.Fatalf("cache.List() = %v, want redis", cache.List())
+	}
+	mr := miniredis.RunT(t)
+	c, err := cache.New("redis", cache.Config{Options: map[string]any{"addr": mr.Addr()}})
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	if _, ok := c.(*RedisCache); !ok {
+		t.Errorf("cache.New() = %T, want *RedisCache", c)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+	}{
+		{name: "no addr"},
+		{name: "empty namespace", options: map[string]any{"addr": "localhost:6379", "namespace": ""}},
+		{name: "namespace not a string", options: map[string]any{"addr": "localhost:6379", "namespace": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(cache.Config{Options: tt.options})
+			var coreErr *core.Error
+			if !errors.As(err, &coreErr) || coreErr.Code != core.ErrInvalidInput {
+				t.Errorf("New() error = %v, want an %s error", err, core.ErrInvalidInput)
+			}
+		})
+	}
+}
+
+func TestRedisCache_SetAndGet(t *testing.T) {
+	c, mr := newTestCache(t, nil)
+	ctx := context.Background()
+
+	value := map[string]any{"text": "hello", "tokens": 3}
+	if err := c.Set(ctx, "key1", value, 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, ok, err := c.Get(ctx, "key1")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v, %v", got, ok, err)
+	}
+	m, _ := got.(map[string]any)
+	if m["text"] != "hello" || m["tokens"] != float64(3) {
+		t.Errorf("Get() = %v", got)
+	}
+
+	raw, err := mr.Get("beluga:cache:key1")
+	if err != nil {
+		t.Fatalf("stored key: %v", err)
+	}
+	if raw != `{"text":"hello","tokens":3}` {
+		t.Errorf("stored value = %s", raw)
+	}
+
+	var typed struct {
+		Text   string `json:"text"`
+		Tokens int    `json:"tokens"`
+	}
+	ok, err = c.GetInto(ctx, "key1", &typed)
+	if err != nil || !ok || typed.Text != "hello" || typed.Tokens != 3 {
+		t.Errorf("GetInto() = %+v, %v, %v", typed, ok, err)
+	}
+}
+
+func TestRedisCache_GetMissing(t *testing.T) {
+	c, _ := newTestCache(t, nil)
+	got, ok, err := c.Get(context.Background(), "missing")
+	if got != nil || ok || err != nil {
+		t.Errorf("Get() = %v, %v, %v, want nil, false, nil", got, ok, err)
+	}
+}
+
+func TestRedisCache_TTL(t *testing.T) {
+	c, mr := newTestCache(t, nil)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "default", "v", 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := c.Set(ctx, "explicit", "v", 10*time.Second); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := c.Set(ctx, "forever", "v", -1); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if ttl := mr.TTL("beluga:cache:default"); ttl != time.Minute {
+		t.Errorf("default TTL = %v, want 1m", ttl)
+	}
+	if ttl := mr.TTL("beluga:cache:explicit"); ttl != 10*time.Second {
+		t.Errorf("explicit TTL = %v, want 10s", ttl)
+	}
+	if ttl := mr.TTL("beluga:cache:forever"); ttl != 0 {
+		t.Errorf("negative TTL = %v, want none", ttl)
+	}
+
+	mr.FastForward(30 * time.Second)
+	if _, ok, _ := c.Get(ctx, "explicit"); ok {
+		t.Error("Get() found an expired entry")
+	}
+	if _, ok, _ := c.Get(ctx, "default"); !ok {
+		t.Error("Get() missed an unexpired entry")
+	}
+}
+
+func TestRedisCache_Delete(t *testing.T) {
+	c, _ := newTestCache(t, nil)
+	ctx := context.Background()
+
+	_ = c.Set(ctx, "key1", "v", 0)
+	if err := c.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "key1"); ok {
+		t.Error("Get() found a deleted entry")
+	}
+	if err := c.Delete(ctx, "missing"); err != nil {
+		t.Errorf("Delete() of a missing key error = %v", err)
+	}
+}
+
+func TestRedisCache_ClearScopedToNamespace(t *testing.T) {
+	c, mr := newTestCache(t, map[string]any{"namespace": "app[1]"})
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := c.Set(ctx, key, key, 0); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	other, err := New(cache.Config{Options: map[string]any{"client": client, "namespace": "app1"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_ = other.Set(ctx, "a", "kept", 0)
+	_ = mr.Set("unrelated", "kept")
+
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if keys := mr.Keys(); !slices.Equal(keys, []string{"app1:a", "unrelated"}) {
+		t.Errorf("keys after Clear() = %v", keys)
+	}
+	if v, ok, _ := other.Get(ctx, "a"); !ok || v != "kept" {
+		t.Errorf("other namespace Get() = %v, %v", v, ok)
+	}
+}
+
+func TestRedisCache_ClearCluster(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClusterClient(&goredis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+	c, err := New(cache.Config{Options: map[string]any{"client": client}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// The keys hash to different slots, which a multi-key UNLINK rejects.
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := c.Set(ctx, key, key, 0); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("keys after Clear() = %v", keys)
+	}
+}
+
+func TestRedisCache_EncodeError(t *testing.T) {
+	c, _ := newTestCache(t, nil)
+	err := c.Set(context.Background(), "key", make(chan int), 0)
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Code != core.ErrInvalidInput {
+		t.Errorf("Set() error = %v, want an %s error", err, core.ErrInvalidInput)
+	}
+}
+
+func TestRedisCache_ConnectionFailure(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fail closed", func(t *testing.T) {
+		c := newUnreachableCache(t, false)
+		_, _, err := c.Get(ctx, "key")
+		var coreErr *core.Error
+		if !errors.As(err, &coreErr) || coreErr.Code != core.ErrProviderDown {
+			t.Errorf("Get() error = %v, want a %s error", err, core.ErrProviderDown)
+		}
+		if err := c.Set(ctx, "key", "v", 0); err == nil {
+			t.Error("Set() error = nil, want error")
+		}
+	})
+
+	t.Run("fail open", func(t *testing.T) {
+		c := newUnreachableCache(t, true)
+		got, ok, err := c.Get(ctx, "key")
+		if got != nil || ok || err != nil {
+			t.Errorf("Get() = %v, %v, %v, want a miss", got, ok, err)
+		}
+		if err := c.Set(ctx, "key", "v", 0); err != nil {
+			t.Errorf("Set() error = %v", err)
+		}
+		if err := c.Delete(ctx, "key"); err != nil {
+			t.Errorf("Delete() error = %v", err)
+		}
+		if err := c.Clear(ctx); err != nil {
+			t.Errorf("Clear() error = %v", err)
+		}
+	})
+
+	t.Run("fail open returns server errors", func(t *testing.T) {
+		c, mr := newTestCache(t, map[string]any{"fail_open": true})
+		mr.SetError("READONLY You can't write against a read only replica")
+		if err := c.Set(ctx, "key", "v", 0); err == nil {
+			t.Error("Set() error = nil, want the server's error")
+		}
+	})
+}
@@ -32,7 +32,8 @@ func cosineSimilarity(a, b []float32) float64 {
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
-// semanticEntry stores a single cached entry with its embedding vector.
+// semanticEntry stores a single cached entry with its embedding vector. When
+// the cache has a backend, value is unused and the value lives there.
 type semanticEntry struct {
 	key       string
 	embedding []float32
@@ -44,8 +45,13 @@ type semanticEntry struct {
 // When Get is called with a text key, the key is embedded and compared against
 // stored entries using cosine similarity. If a match exceeds the threshold, the
 // cached value is returned.
+//
+// By default entries are held in process memory. WithBackend stores the
+// values in another Cache, such as a shared "redis" cache, while the
+// embeddings used for similarity matching stay in process.
 type SemanticCache struct {
 	embedder      embedding.Embedder
+	backend       Cache
 	threshold     float64
 	defaultTTL    time.Duration
 	maxEntries    int
@@ -102,6 +108,20 @@ func WithMaxDimensions(n int) Option {
 	}
 }
 
+// WithBackend stores cached values in backend instead of process memory, so
+// that they survive restarts and are shared with every instance using the
+// same backend. Exact-key lookups are answered by the backend and so hit
+// values set by any instance; similarity matching only considers the keys
+// this instance has set, as the embeddings stay in process. Values come back
+// in whatever form the backend returns them, for example as the types
+// encoding/json decodes into for a JSON-serializing backend. Clear also
+// clears the backend, so give the semantic cache a backend of its own.
+func WithBackend(backend Cache) Option {
+	return func(sc *SemanticCache) {
+		sc.backend = backend
+	}
+}
+
 // NewSemanticCache creates a SemanticCache that uses the given Embedder to
 // convert text keys into vectors for similarity comparison.
 func NewSemanticCache(embedder embedding.Embedder, opts ...Option) *SemanticCache {
@@ -119,7 +139,14 @@ func NewSemanticCache(embedder embedding.Embedder, opts ...Option) *SemanticCach
 
 // Get retrieves a value by embedding the key text and scanning entries for the
 // best cosine similarity match above the threshold. Expired entries are skipped.
+// With a backend, an exact key match in the backend is returned without
+// embedding the key.
 func (sc *SemanticCache) Get(ctx context.Context, key string) (any, bool, error) {
+	if sc.backend != nil {
+		if val, ok, err := sc.backend.Get(ctx, key); err != nil || ok {
+			return val, ok, err
+		}
+	}
 	emb, err := sc.embedder.EmbedSingle(ctx, key)
 	if err != nil {
 		return nil, false, core.Errorf(core.ErrProviderDown, "cache: semantic embed: %w", err)
@@ -142,34 +169,68 @@ func (sc *SemanticCache) Set(ctx context.Context, key string, value any, ttl tim
 }
 
 // Delete removes an entry by exact key string match.
-func (sc *SemanticCache) Delete(_ context.Context, key string) error {
+func (sc *SemanticCache) Delete(ctx context.Context, key string) error {
+	sc.removeEntry(key)
+	if sc.backend != nil {
+		return sc.backend.Delete(ctx, key)
+	}
+	return nil
+}
+
+// removeEntry drops the entry for key from the in-process entries.
+func (sc *SemanticCache) removeEntry(key string) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 	for i, e := range sc.entries {
 		if e.key == key {
 			sc.entries = append(sc.entries[:i], sc.entries[i+1:]...)
-			return nil
+			return
 		}
 	}
-	return nil
 }
 
-// Clear removes all entries from the cache.
-func (sc *SemanticCache) Clear(_ context.Context) error {
+// Clear removes all entries from the cache, including those in the backend.
+func (sc *SemanticCache) Clear(ctx context.Context) error {
 	sc.mu.Lock()
-	defer sc.mu.Unlock()
 	sc.entries = nil
+	sc.mu.Unlock()
+	if sc.backend != nil {
+		return sc.backend.Clear(ctx)
+	}
 	return nil
 }
 
 // GetByEmbedding searches for the best matching entry using a pre-computed
 // embedding vector. Returns the value, whether a match was found, and any error.
-func (sc *SemanticCache) GetByEmbedding(_ context.Context, emb []float32) (any, bool, error) {
+// With a backend, the value is read from it; a match whose value the backend
+// no longer holds is dropped and reported as a miss.
+func (sc *SemanticCache) GetByEmbedding(ctx context.Context, emb []float32) (any, bool, error) {
+	best, ok := sc.bestMatch(emb)
+	if !ok {
+		return nil, false, nil
+	}
+	if sc.backend == nil {
+		return best.value, true, nil
+	}
+	val, found, err := sc.backend.Get(ctx, best.key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		sc.removeEntry(best.key)
+		return nil, false, nil
+	}
+	return val, true, nil
+}
+
+// bestMatch returns the unexpired entry most similar to emb, if any reaches
+// the threshold.
+func (sc *SemanticCache) bestMatch(emb []float32) (semanticEntry, bool) {
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
 
 	now := sc.now()
-	var bestVal any
+	var best semanticEntry
 	bestSim := -1.0
 	for _, e := range sc.entries {
 		// Skip expired entries. Zero expiresAt means no expiration.
@@ -179,13 +240,10 @@ func (sc *SemanticCache) GetByEmbedding(_ context.Context, emb []float32) (any,
 		sim := cosineSimilarity(emb, e.embedding)
 		if sim >= sc.threshold && sim > bestSim {
 			bestSim = sim
-			bestVal = e.value
+			best = e
 		}
 	}
-	if bestSim < sc.threshold {
-		return nil, false, nil
-	}
-	return bestVal, true, nil
+	return best, bestSim >= sc.threshold
 }
 
 // SetByEmbedding stores an entry with a pre-computed embedding vector. If an
@@ -194,12 +252,32 @@ func (sc *SemanticCache) GetByEmbedding(_ context.Context, emb []float32) (any,
 //
 // The embedding slice is copied defensively; the caller may safely mutate it
 // after this call returns. The value is stored by reference and should be
-// treated as immutable once passed.
-func (sc *SemanticCache) SetByEmbedding(_ context.Context, key string, emb []float32, value any, ttl time.Duration) error {
+// treated as immutable once passed. With a backend, the value is stored there
+// first and the entry is only indexed if that succeeds; the value of an
+// evicted entry is deleted from the backend.
+func (sc *SemanticCache) SetByEmbedding(ctx context.Context, key string, emb []float32, value any, ttl time.Duration) error {
 	if sc.maxDimensions > 0 && len(emb) > sc.maxDimensions {
 		return core.Errorf(core.ErrInvalidInput, "cache: embedding dimension %d exceeds maximum %d", len(emb), sc.maxDimensions)
 	}
+	if sc.backend != nil {
+		if ttl == 0 {
+			ttl = sc.defaultTTL
+		}
+		if err := sc.backend.Set(ctx, key, value, ttl); err != nil {
+			return err
+		}
+		value = nil
+	}
+	evicted, ok := sc.index(key, emb, value, ttl)
+	if ok && sc.backend != nil {
+		return sc.backend.Delete(ctx, evicted)
+	}
+	return nil
+}
 
+// index adds or updates the in-process entry for key and returns the key of
+// the entry evicted to make room, if any.
+func (sc *SemanticCache) index(key string, emb []float32, value any, ttl time.Duration) (evicted string, ok bool) {
 	// Defensive copy to prevent caller mutations from corrupting cache.
 	embCopy := make([]float32, len(emb))
 	copy(embCopy, emb)
@@ -215,7 +293,7 @@ func (sc *SemanticCache) SetByEmbedding(_ context.Context, key string, emb []flo
 			sc.entries[i].embedding = embCopy
 			sc.entries[i].value = value
 			sc.entries[i].expiresAt = exp
-			return nil
+			return "", false
 		}
 	}
 
@@ -224,6 +302,7 @@ func (sc *SemanticCache) SetByEmbedding(_ context.Context, key string, emb []flo
 
 	// Evict oldest if at capacity.
 	if sc.maxEntries > 0 && len(sc.entries) >= sc.maxEntries {
+		evicted, ok = sc.entries[0].key, true
 		sc.entries = sc.entries[1:]
 	}
 
@@ -233,7 +312,7 @@ func (sc *SemanticCache) SetByEmbedding(_ context.Context, key string, emb []flo
 		value:     value,
 		expiresAt: exp,
 	})
-	return nil
+	return evicted, ok
 }
 
 // Prune removes all expired entries from the cache. It is safe for concurrent
@@ -293,6 +372,13 @@ func init() {
 		if cfg.MaxSize > 0 {
 			opts = append(opts, WithMaxEntries(cfg.MaxSize))
 		}
+		if b, ok := cfg.Options["backend"]; ok {
+			backend, ok := b.(Cache)
+			if !ok {
+				return nil, core.Errorf(core.ErrInvalidInput, "cache: semantic factory: Options[\"backend\"] is not a cache.Cache")
+			}
+			opts = append(opts, WithBackend(backend))
+		}
 		if t, ok := cfg.Options["threshold"]; ok {
 			if tv, ok := t.(float64); ok {
 				opts = append(opts, WithThreshold(tv))
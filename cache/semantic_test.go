@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"math"
 	"sync"
 	"testing"
@@ -418,6 +419,9 @@ func TestSemanticCache_Registry(t *testing.T) {
 	if !ok {
 		t.Fatal("expected *SemanticCache from registry")
 	}
+	if sc.backend != nil {
+		t.Error("expected no backend by default")
+	}
 	if sc.threshold != 0.9 {
 		t.Errorf("threshold = %v, want 0.9", sc.threshold)
 	}
@@ -429,6 +433,26 @@ func TestSemanticCache_Registry(t *testing.T) {
 	}
 }
 
+func TestSemanticCache_Registry_Backend(t *testing.T) {
+	backend := newMapCache()
+	c, err := New("semantic", Config{
+		Options: map[string]any{"embedder": newMockEmbedder(), "backend": backend},
+	})
+	if err != nil {
+		t.Fatalf("New(\"semantic\"): %v", err)
+	}
+	if sc := c.(*SemanticCache); sc.backend != backend {
+		t.Error("expected the configured backend")
+	}
+
+	_, err = New("semantic", Config{
+		Options: map[string]any{"embedder": newMockEmbedder(), "backend": "redis"},
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid backend type")
+	}
+}
+
 func TestSemanticCache_Registry_MissingEmbedder(t *testing.T) {
 	_, err := New("semantic", Config{
 		Options: map[string]any{},
@@ -653,3 +677,65 @@ func TestSemanticCache_EmbeddingDefensiveCopy(t *testing.T) {
 		t.Errorf("expected cache hit after mutating original embedding, ok=%v val=%v", ok, val)
 	}
 }
+
+func TestSemanticCache_Backend(t *testing.T) {
+	ctx := context.Background()
+	backend := newMapCache()
+	emb := newMockEmbedder()
+	sc := NewSemanticCache(emb, WithBackend(backend), WithMaxEntries(2))
+
+	if err := sc.Set(ctx, "hello", "world", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v, _, _ := backend.Get(ctx, "hello"); v != "world" {
+		t.Errorf("backend value = %v, want world", v)
+	}
+	if v, ok, err := sc.Get(ctx, "hi"); err != nil || !ok || v != "world" {
+		t.Errorf("similar Get = %v, %v, %v; want world", v, ok, err)
+	}
+
+	// Another instance sharing the backend hits exact keys.
+	other := NewSemanticCache(emb, WithBackend(backend))
+	if v, ok, _ := other.Get(ctx, "hello"); !ok || v != "world" {
+		t.Errorf("shared exact Get = %v, %v; want world", v, ok)
+	}
+
+	// A value dropped by the backend is a miss and leaves the index.
+	_ = backend.Delete(ctx, "hello")
+	if _, ok, _ := sc.Get(ctx, "hi"); ok {
+		t.Error("expected miss after the backend dropped the value")
+	}
+	if n := len(sc.entries); n != 0 {
+		t.Errorf("entries = %d, want 0", n)
+	}
+
+	// Evicted entries are deleted from the backend.
+	for _, k := range []string{"hello", "goodbye", "weather"} {
+		if err := sc.Set(ctx, k, k, time.Minute); err != nil {
+			t.Fatalf("Set(%q): %v", k, err)
+		}
+	}
+	if _, ok, _ := backend.Get(ctx, "hello"); ok {
+		t.Error("expected the evicted value to be deleted from the backend")
+	}
+
+	if err := sc.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if len(backend.data) != 0 {
+		t.Errorf("backend holds %d values after Clear", len(backend.data))
+	}
+}
+
+func TestSemanticCache_BackendSetError(t *testing.T) {
+	backend := newMapCache()
+	backend.setErr = errors.New("backend down")
+	sc := NewSemanticCache(newMockEmbedder(), WithBackend(backend))
+
+	if err := sc.Set(context.Background(), "hello", "world", time.Minute); err == nil {
+		t.Fatal("expected the backend error")
+	}
+	if len(sc.entries) != 0 {
+		t.Error("expected no entry to be indexed when the backend fails")
+	}
+}
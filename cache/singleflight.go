@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Compile-time interface check.
+var _ Cache = (*SingleflightCache)(nil)
+
+// ComputeFunc computes the value of a key missing from the cache.
+type ComputeFunc func(ctx context.Context) (any, error)
+
+// flight is a computation in progress. done is closed once val and err are
+// set.
+type flight struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// SingleflightCache wraps a Cache, including a SemanticCache, with
+// GetOrCompute, which coalesces concurrent misses for the same key so that
+// only one computation runs while the other callers wait for its result.
+// Get, Set, Delete and Clear go straight to the wrapped cache.
+type SingleflightCache struct {
+	cache Cache
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// WithSingleflight returns c wrapped in a SingleflightCache. Coalescing only
+// spans calls made through the same wrapper, so share one per cache.
+func WithSingleflight(c Cache) *SingleflightCache {
+	return &SingleflightCache{
+		cache:   c,
+		flights: make(map[string]*flight),
+	}
+}
+
+// Get retrieves a value from the wrapped cache.
+func (s *SingleflightCache) Get(ctx context.Context, key string) (any, bool, error) {
+	return s.cache.Get(ctx, key)
+}
+
+// Set stores a value in the wrapped cache.
+func (s *SingleflightCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return s.cache.Set(ctx, key, value, ttl)
+}
+
+// Delete removes a key from the wrapped cache. A computation of key already
+// in flight still stores its result, but callers arriving after Delete start
+// a new one.
+func (s *SingleflightCache) Delete(ctx context.Context, key string) error {
+	s.forget(key)
+	return s.cache.Delete(ctx, key)
+}
+
+// Clear removes all entries from the wrapped cache.
+func (s *SingleflightCache) Clear(ctx context.Context) error {
+	return s.cache.Clear(ctx)
+}
+
+// GetOrCompute returns the cached value of key or, on a miss, computes it
+// with fn and stores it with ttl. Concurrent callers that miss the same key
+// share one call of fn: the first starts it and the others wait for its
+// result. Keys are coalesced by exact match, also when the wrapped cache is a
+// SemanticCache.
+//
+// fn runs with the first caller's context stripped of its cancellation, so
+// a caller that gives up does not fail the others; each caller stops waiting
+// and returns its context's error when its own ctx is done. If fn returns an
+// error or panics, nothing is cached: every waiting caller receives the
+// error, and the next call for the key computes it afresh. If storing the
+// result fails, the callers receive the store error. An error reading the
+// cache is returned without computing.
+func (s *SingleflightCache) GetOrCompute(ctx context.Context, key string, ttl time.Duration, fn ComputeFunc) (any, error) {
+	if v, ok, err := s.cache.Get(ctx, key); err != nil || ok {
+		return v, err
+	}
+
+	s.mu.Lock()
+	f, ok := s.flights[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		s.flights[key] = f
+		go s.compute(context.WithoutCancel(ctx), key, ttl, fn, f)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// compute runs fn for key, stores its result and completes f.
+func (s *SingleflightCache) compute(ctx context.Context, key string, ttl time.Duration, fn ComputeFunc, f *flight) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.val, f.err = nil, fmt.Errorf("cache: compute %q panicked: %v", key, r)
+		}
+		s.mu.Lock()
+		if s.flights[key] == f {
+			delete(s.flights, key)
+		}
+		s.mu.Unlock()
+		close(f.done)
+	}()
+
+	// A caller may have stored the value between the miss and the start of
+	// this flight.
+	if v, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		f.val = v
+		return
+	}
+	v, err := fn(ctx)
+	if err != nil {
+		f.err = err
+		return
+	}
+	if err := s.cache.Set(ctx, key, v, ttl); err != nil {
+		f.err = err
+		return
+	}
+	f.val = v
+}
+
+// forget detaches the flight of key, if any, from later callers.
+func (s *SingleflightCache) forget(key string) {
+	s.mu.Lock()
+	delete(s.flights, key)
+	s.mu.Unlock()
+}
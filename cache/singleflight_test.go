@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mapCache is a minimal Cache backed by a map.
+type mapCache struct {
+	mu     sync.Mutex
+	data   map[string]any
+	setErr error
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{data: make(map[string]any)}
+}
+
+func (m *mapCache) Get(_ context.Context, key string) (any, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	return v, ok, nil
+}
+
+func (m *mapCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.data[key] = value
+	return nil
+}
+
+func (m *mapCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func (m *mapCache) Clear(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[string]any)
+	return nil
+}
+
+// runConcurrently calls GetOrCompute for key from n goroutines and returns
+// their results once all have returned.
+func runConcurrently(t *testing.T, sf *SingleflightCache, key string, n int, fn ComputeFunc) ([]any, []error) {
+	t.Helper()
+	vals := make([]any, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i], errs[i] = sf.GetOrCompute(context.Background(), key, time.Minute, fn)
+		}()
+	}
+	wg.Wait()
+	return vals, errs
+}
+
+func TestGetOrCompute_CoalescesMisses(t *testing.T) {
+	c := newMapCache()
+	sf := WithSingleflight(c)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	done := make(chan struct{})
+	var vals []any
+	var errs []error
+	go func() {
+		vals, errs = runConcurrently(t, sf, "k", 10, fn)
+		close(done)
+	}()
+	// Give every caller time to join the flight before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	for i := range vals {
+		if errs[i] != nil || vals[i] != "value" {
+			t.Errorf("caller %d: got (%v, %v), want (value, nil)", i, vals[i], errs[i])
+		}
+	}
+	if v, ok, _ := c.Get(context.Background(), "k"); !ok || v != "value" {
+		t.Errorf("cached value = (%v, %v), want (value, true)", v, ok)
+	}
+}
+
+func TestGetOrCompute_Hit(t *testing.T) {
+	c := newMapCache()
+	_ = c.Set(context.Background(), "k", "cached", 0)
+	sf := WithSingleflight(c)
+
+	v, err := sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+		t.Error("fn called on a hit")
+		return nil, nil
+	})
+	if err != nil || v != "cached" {
+		t.Errorf("got (%v, %v), want (cached, nil)", v, err)
+	}
+}
+
+func TestGetOrCompute_ErrorSharedAndNotCached(t *testing.T) {
+	c := newMapCache()
+	sf := WithSingleflight(c)
+	boom := errors.New("boom")
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fail := func(context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return nil, boom
+	}
+	done := make(chan struct{})
+	var errs []error
+	go func() {
+		_, errs = runConcurrently(t, sf, "k", 5, fail)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	for i, err := range errs {
+		if !errors.Is(err, boom) {
+			t.Errorf("caller %d: err = %v, want boom", i, err)
+		}
+	}
+	if _, ok, _ := c.Get(context.Background(), "k"); ok {
+		t.Error("error result was cached")
+	}
+
+	v, err := sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+		return "retried", nil
+	})
+	if err != nil || v != "retried" {
+		t.Errorf("retry: got (%v, %v), want (retried, nil)", v, err)
+	}
+}
+
+func TestGetOrCompute_SetError(t *testing.T) {
+	c := newMapCache()
+	c.setErr = errors.New("store down")
+	sf := WithSingleflight(c)
+
+	_, err := sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+		return "value", nil
+	})
+	if !errors.Is(err, c.setErr) {
+		t.Errorf("err = %v, want store error", err)
+	}
+}
+
+func TestGetOrCompute_Panic(t *testing.T) {
+	sf := WithSingleflight(newMapCache())
+
+	_, err := sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+		panic("kaboom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "kaboom") {
+		t.Errorf("err = %v, want panic error", err)
+	}
+}
+
+func TestGetOrCompute_CallerCancelled(t *testing.T) {
+	c := newMapCache()
+	sf := WithSingleflight(c)
+
+	release := make(chan struct{})
+	computed := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		defer close(computed)
+		<-release
+		return "value", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := sf.GetOrCompute(ctx, "k", time.Minute, fn)
+		errc <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+
+	// The computation outlives the cancelled caller and is cached.
+	close(release)
+	<-computed
+	time.Sleep(20 * time.Millisecond)
+	if v, ok, _ := c.Get(context.Background(), "k"); !ok || v != "value" {
+		t.Errorf("cached value = (%v, %v), want (value, true)", v, ok)
+	}
+}
+
+func TestGetOrCompute_DeleteForgetsFlight(t *testing.T) {
+	sf := WithSingleflight(newMapCache())
+
+	release := make(chan struct{})
+	go func() {
+		_, _ = sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+			<-release
+			return "stale", nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	defer close(release)
+
+	if err := sf.Delete(context.Background(), "k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	v, err := sf.GetOrCompute(context.Background(), "k", time.Minute, func(context.Context) (any, error) {
+		return "fresh", nil
+	})
+	if err != nil || v != "fresh" {
+		t.Errorf("got (%v, %v), want (fresh, nil)", v, err)
+	}
+}
+
+func TestGetOrCompute_SemanticCache(t *testing.T) {
+	sc := NewSemanticCache(newMockEmbedder(), WithThreshold(0.9))
+	sf := WithSingleflight(sc)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return "greeting", nil
+	}
+	done := make(chan struct{})
+	go func() {
+		runConcurrently(t, sf, "hello", 5, fn)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	// A similar key is served by the semantic lookup without computing.
+	v, err := sf.GetOrCompute(context.Background(), "hi", time.Minute, func(context.Context) (any, error) {
+		t.Error("fn called for a semantic hit")
+		return nil, nil
+	})
+	if err != nil || v != "greeting" {
+		t.Errorf("got (%v, %v), want (greeting, nil)", v, err)
+	}
+}
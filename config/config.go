@@ -27,17 +27,24 @@ func Load[T any](path string) (T, error) {
 	}
 
 	ext := strings.ToLower(filepath.Ext(path))
-	var providedKeys map[string]json.RawMessage
 	switch ext {
 	case ".json":
-		if err := json.Unmarshal(data, &cfg); err != nil {
-			return cfg, core.Errorf(core.ErrInvalidInput, "config: unmarshal json: %w", err)
-		}
-		// Also decode into a map to track which keys were explicitly provided.
-		_ = json.Unmarshal(data, &providedKeys)
+		return parseJSON[T](data)
 	default:
 		return cfg, core.Errorf(core.ErrInvalidInput, "config: unsupported file extension %q (supported: .json)", ext)
 	}
+}
+
+// parseJSON unmarshals data into T, applies defaults for keys that were not
+// explicitly provided, and validates the result.
+func parseJSON[T any](data []byte) (T, error) {
+	var cfg T
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, core.Errorf(core.ErrInvalidInput, "config: unmarshal json: %w", err)
+	}
+	// Also decode into a map to track which keys were explicitly provided.
+	var providedKeys map[string]json.RawMessage
+	_ = json.Unmarshal(data, &providedKeys)
 
 	// Validate required fields before applying defaults so that missing
 	// required values are caught even when a default tag exists.
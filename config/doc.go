@@ -68,4 +68,16 @@
 //	    data := newConfig.([]byte)
 //	    // re-parse and apply configuration
 //	})
+//
+// [WatchTyped] parses each update into a typed struct and reports which
+// field paths changed, so components can hot-reload only what they use:
+//
+//	errs := config.WatchTyped(ctx, watcher, cfg, func(old, new AppConfig, changed []string) {
+//	    if slices.Contains(changed, "limits.rps") {
+//	        limiter.SetRate(new.Limits.RPS)
+//	    }
+//	})
+//	for err := range errs {
+//	    log.Printf("config reload skipped: %v", err)
+//	}
 package config
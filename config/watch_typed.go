@@ -0,0 +1,170 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// typedWatchErrBuffer is the capacity of the error channel returned by
+// WatchTyped. Errors that arrive while the buffer is full are dropped so a
+// slow consumer never stalls the watcher.
+const typedWatchErrBuffer = 16
+
+// WatchTyped runs w in a background goroutine and translates each raw
+// configuration update into a typed diff against the previous value.
+//
+// Updates delivered as []byte (as [FileWatcher] does) are parsed as JSON
+// into T with defaults and validation applied, exactly like [Load]. Updates
+// already of type T are used as-is. The callback is invoked only when at
+// least one field changed; changed lists the dotted JSON paths of the
+// modified fields (for example "limits.rps"), sorted lexically.
+//
+// Parse and validation errors skip the update — the previous value stays
+// current — and are sent on the returned channel. The terminal error from
+// w.Watch, if any and not caused by ctx cancellation, is sent last. The
+// channel is closed once watching stops. Errors are dropped rather than
+// blocking the watcher when the channel buffer is full.
+func WatchTyped[T any](ctx context.Context, w Watcher, initial T, callback func(old, new T, changed []string)) <-chan error {
+	errs := make(chan error, typedWatchErrBuffer)
+	current := initial
+
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(errs)
+		err := w.Watch(ctx, func(newConfig any) {
+			next, err := decodeTyped[T](newConfig)
+			if err != nil {
+				report(err)
+				return
+			}
+			changed := DiffFields(current, next)
+			if len(changed) == 0 {
+				return
+			}
+			old := current
+			current = next
+			callback(old, next, changed)
+		})
+		if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			report(err)
+		}
+	}()
+
+	return errs
+}
+
+// decodeTyped converts a raw watcher update into T.
+func decodeTyped[T any](v any) (T, error) {
+	switch val := v.(type) {
+	case T:
+		return val, nil
+	case []byte:
+		return parseJSON[T](val)
+	default:
+		var zero T
+		return zero, core.Errorf(core.ErrInvalidInput, "config: watch: unsupported update type %T", v)
+	}
+}
+
+// DiffFields returns the dotted JSON paths of the fields that differ between
+// a and b, sorted lexically. Nested structs are compared field by field and
+// maps key by key; all other values (including slices) are compared as a
+// whole. Pointers are followed. Unexported fields and fields tagged
+// `json:"-"` are ignored.
+func DiffFields[T any](a, b T) []string {
+	var changed []string
+	diffValue(reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem(), "", &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+// diffValue appends the paths of differing leaves under prefix to out.
+func diffValue(a, b reflect.Value, prefix string, out *[]string) {
+	if a.Kind() == reflect.Ptr || a.Kind() == reflect.Interface {
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*out = append(*out, pathOrRoot(prefix))
+			}
+			return
+		}
+		diffValue(a.Elem(), b.Elem(), prefix, out)
+		return
+	}
+	if a.Type() != b.Type() {
+		*out = append(*out, pathOrRoot(prefix))
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Tag.Get("json") == "-" {
+				continue
+			}
+			diffValue(a.Field(i), b.Field(i), joinPath(prefix, jsonKeyForField(sf)), out)
+		}
+	case reflect.Map:
+		diffMap(a, b, prefix, out)
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*out = append(*out, pathOrRoot(prefix))
+		}
+	}
+}
+
+// diffMap compares two maps key by key.
+func diffMap(a, b reflect.Value, prefix string, out *[]string) {
+	seen := make(map[any]struct{}, a.Len())
+	for _, k := range a.MapKeys() {
+		seen[k.Interface()] = struct{}{}
+		path := joinPath(prefix, mapKeyString(k))
+		bv := b.MapIndex(k)
+		if !bv.IsValid() {
+			*out = append(*out, path)
+			continue
+		}
+		diffValue(a.MapIndex(k), bv, path, out)
+	}
+	for _, k := range b.MapKeys() {
+		if _, ok := seen[k.Interface()]; !ok {
+			*out = append(*out, joinPath(prefix, mapKeyString(k)))
+		}
+	}
+}
+
+// mapKeyString renders a map key for use in a field path.
+func mapKeyString(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// pathOrRoot returns "." for the root value so top-level changes of
+// non-struct types still report a non-empty path.
+func pathOrRoot(p string) string {
+	if p == "" {
+		return "."
+	}
+	return p
+}
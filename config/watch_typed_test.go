@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type typedLimits struct {
+	RPS   int `json:"rps"`
+	Burst int `json:"burst"`
+}
+
+type typedAppConfig struct {
+	Name   string            `json:"name"`
+	Limits typedLimits       `json:"limits"`
+	Tags   []string          `json:"tags"`
+	Extra  map[string]string `json:"extra"`
+	Ptr    *typedLimits      `json:"ptr"`
+	Cache  string            `json:"-"`
+}
+
+func TestDiffFields(t *testing.T) {
+	base := typedAppConfig{
+		Name:   "svc",
+		Limits: typedLimits{RPS: 10, Burst: 20},
+		Tags:   []string{"a"},
+		Extra:  map[string]string{"k": "v"},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(c *typedAppConfig)
+		want   []string
+	}{
+		{name: "no change", mutate: func(c *typedAppConfig) {}, want: nil},
+		{name: "top-level field", mutate: func(c *typedAppConfig) { c.Name = "other" }, want: []string{"name"}},
+		{name: "nested field", mutate: func(c *typedAppConfig) { c.Limits.RPS = 99 }, want: []string{"limits.rps"}},
+		{
+			name:   "multiple fields sorted",
+			mutate: func(c *typedAppConfig) { c.Name = "x"; c.Limits.Burst = 1 },
+			want:   []string{"limits.burst", "name"},
+		},
+		{name: "slice compared whole", mutate: func(c *typedAppConfig) { c.Tags = []string{"a", "b"} }, want: []string{"tags"}},
+		{name: "map value changed", mutate: func(c *typedAppConfig) { c.Extra = map[string]string{"k": "w"} }, want: []string{"extra.k"}},
+		{
+			name:   "map key added and removed",
+			mutate: func(c *typedAppConfig) { c.Extra = map[string]string{"n": "v"} },
+			want:   []string{"extra.k", "extra.n"},
+		},
+		{name: "json-ignored field", mutate: func(c *typedAppConfig) { c.Cache = "warm" }, want: nil},
+		{name: "pointer set", mutate: func(c *typedAppConfig) { c.Ptr = &typedLimits{RPS: 1} }, want: []string{"ptr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			next.Extra = map[string]string{"k": "v"}
+			tt.mutate(&next)
+			got := DiffFields(base, next)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffFields_PointerFollowed(t *testing.T) {
+	a := typedAppConfig{Ptr: &typedLimits{RPS: 1}}
+	b := typedAppConfig{Ptr: &typedLimits{RPS: 2}}
+	got := DiffFields(a, b)
+	if want := []string{"ptr.rps"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFields() = %v, want %v", got, want)
+	}
+}
+
+func TestWatchTyped_InvokesWithDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cfg.json")
+	if err := os.WriteFile(path, []byte(`{"name":"svc","limits":{"rps":10,"burst":20}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	initial, err := Load[typedAppConfig](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewFileWatcher(path, 100*time.Millisecond)
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	type update struct {
+		old, new typedAppConfig
+		changed  []string
+	}
+	updates := make(chan update, 4)
+	errs := WatchTyped(ctx, w, initial, func(old, new typedAppConfig, changed []string) {
+		updates <- update{old: old, new: new, changed: changed}
+	})
+
+	time.Sleep(150 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{"name":"svc","limits":{"rps":50,"burst":20}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case u := <-updates:
+		if !reflect.DeepEqual(u.changed, []string{"limits.rps"}) {
+			t.Errorf("changed = %v, want [limits.rps]", u.changed)
+		}
+		if u.old.Limits.RPS != 10 || u.new.Limits.RPS != 50 {
+			t.Errorf("old/new rps = %d/%d, want 10/50", u.old.Limits.RPS, u.new.Limits.RPS)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for typed update")
+	}
+
+	cancel()
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWatchTyped_ParseErrorSkipsUpdate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cfg.json")
+	if err := os.WriteFile(path, []byte(`{"name":"svc"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewFileWatcher(path, 100*time.Millisecond)
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	updates := make(chan []string, 4)
+	errs := WatchTyped(ctx, w, typedAppConfig{Name: "svc"}, func(_, _ typedAppConfig, changed []string) {
+		updates <- changed
+	})
+
+	time.Sleep(150 * time.Millisecond)
+	if err := os.WriteFile(path, []byte(`{not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected parse error")
+		}
+	case <-updates:
+		t.Fatal("callback invoked for unparseable update")
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for parse error")
+	}
+
+	// A subsequent valid update is diffed against the last good value.
+	if err := os.WriteFile(path, []byte(`{"name":"next"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case changed := <-updates:
+		if !reflect.DeepEqual(changed, []string{"name"}) {
+			t.Errorf("changed = %v, want [name]", changed)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for update after parse error")
+	}
+}
@@ -0,0 +1,226 @@
+package eval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// Sample fields that columns can be mapped to with WithColumnMapping.
+const (
+	FieldInput          = "input"
+	FieldOutput         = "output"
+	FieldExpectedOutput = "expected_output"
+	// FieldMetadataPrefix followed by a key maps a column to that
+	// metadata key, for example "metadata.category".
+	FieldMetadataPrefix = "metadata."
+)
+
+// loadOptions holds the configuration for LoadDatasetCSV and
+// LoadDatasetJSONL.
+type loadOptions struct {
+	mapping map[string]string
+}
+
+// LoadOption configures LoadDatasetCSV and LoadDatasetJSONL.
+type LoadOption func(*loadOptions)
+
+// WithColumnMapping maps the columns of a CSV file, or the keys of JSONL
+// objects, to sample fields: FieldInput, FieldOutput, FieldExpectedOutput,
+// or a metadata key with FieldMetadataPrefix. Without a mapping, columns
+// named input, output and expected_output (or expected), in any case and
+// with or without underscores, fill those fields.
+func WithColumnMapping(mapping map[string]string) LoadOption {
+	return func(o *loadOptions) {
+		o.mapping = mapping
+	}
+}
+
+// LoadDatasetCSV reads a dataset from a CSV file whose first row names the
+// columns. Each further row becomes a sample; columns not mapped to a
+// sample field are kept in Metadata as strings. The dataset is named after
+// the file, without its extension.
+func LoadDatasetCSV(path string, opts ...LoadOption) (*Dataset, error) {
+	m, err := newColumnMapper(opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Clean(path)) // #nosec G304 -- path cleaned
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, core.Errorf(core.ErrInvalidInput, "eval.load_csv: %s has no header row", path)
+	}
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "eval.load_csv: %w", err)
+	}
+
+	ds := &Dataset{Name: datasetName(path)}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "eval.load_csv: %w", err)
+		}
+		var s EvalSample
+		for i, column := range header {
+			m.set(&s, column, record[i])
+		}
+		ds.Samples = append(ds.Samples, s)
+	}
+}
+
+// LoadDatasetJSONL reads a dataset from a file holding one JSON object per
+// line. Mapped keys fill the sample fields; other keys are kept in Metadata
+// with their decoded values, and the members of a "metadata" object are
+// merged into it. Blank lines are skipped. The dataset is named after the
+// file, without its extension.
+func LoadDatasetJSONL(path string, opts ...LoadOption) (*Dataset, error) {
+	m, err := newColumnMapper(opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Clean(path)) // #nosec G304 -- path cleaned
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	ds := &Dataset{Name: datasetName(path)}
+	br := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, core.Errorf(core.ErrInvalidInput, "eval.load_jsonl: %w", readErr)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var obj map[string]any
+			if err := json.Unmarshal(line, &obj); err != nil {
+				return nil, core.Errorf(core.ErrInvalidInput, "eval.load_jsonl: line %d: %w", lineNo, err)
+			}
+			var s EvalSample
+			for key, value := range obj {
+				if meta, ok := value.(map[string]any); ok && normalizeColumn(key) == "metadata" && m.field(key) == "" {
+					for k, v := range meta {
+						s.setMetadata(k, v)
+					}
+					continue
+				}
+				m.set(&s, key, value)
+			}
+			ds.Samples = append(ds.Samples, s)
+		}
+		if readErr != nil {
+			return ds, nil
+		}
+	}
+}
+
+// datasetName returns the name of the dataset stored at path.
+func datasetName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// newColumnMapper applies opts and validates the column mapping.
+func newColumnMapper(opts []LoadOption) (columnMapper, error) {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for column, field := range o.mapping {
+		switch {
+		case field == FieldInput, field == FieldOutput, field == FieldExpectedOutput:
+		case strings.HasPrefix(field, FieldMetadataPrefix) && len(field) > len(FieldMetadataPrefix):
+		default:
+			return columnMapper{}, core.Errorf(core.ErrInvalidInput, "eval.load: column %q mapped to unknown field %q", column, field)
+		}
+	}
+	return columnMapper{mapping: o.mapping}, nil
+}
+
+// columnMapper assigns column values to sample fields.
+type columnMapper struct {
+	mapping map[string]string
+}
+
+// field returns the sample field column maps to, or "" if it maps to none.
+func (m columnMapper) field(column string) string {
+	if m.mapping != nil {
+		return m.mapping[column]
+	}
+	switch normalizeColumn(column) {
+	case "input":
+		return FieldInput
+	case "output":
+		return FieldOutput
+	case "expectedoutput", "expected":
+		return FieldExpectedOutput
+	}
+	return ""
+}
+
+// set assigns the value of column to its field of s, or to the metadata
+// key named after the column. Values assigned to text fields are
+// formatted as text.
+func (m columnMapper) set(s *EvalSample, column string, value any) {
+	field := m.field(column)
+	switch {
+	case field == FieldInput:
+		s.Input = asText(value)
+	case field == FieldOutput:
+		s.Output = asText(value)
+	case field == FieldExpectedOutput:
+		s.ExpectedOutput = asText(value)
+	case strings.HasPrefix(field, FieldMetadataPrefix):
+		s.setMetadata(strings.TrimPrefix(field, FieldMetadataPrefix), value)
+	default:
+		s.setMetadata(column, value)
+	}
+}
+
+// setMetadata sets the metadata key of s to value.
+func (s *EvalSample) setMetadata(key string, value any) {
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]any)
+	}
+	s.Metadata[key] = value
+}
+
+// asText formats a decoded JSON value as text.
+func asText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(value) // #nosec G104 -- decoded JSON always re-encodes
+	return string(data)
+}
+
+// normalizeColumn lower-cases name and drops underscores, hyphens and
+// spaces, so that "Expected Output" and "expected_output" match.
+func normalizeColumn(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
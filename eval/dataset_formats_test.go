@@ -0,0 +1,96 @@
+package eval_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDatasetCSV(t *testing.T) {
+	path := writeFile(t, "qa.csv", "Input,Expected Output,category\n"+
+		"What is Go?,A programming language,lang\n"+
+		"\"Say \"\"hi\"\", please\",hi,chat\n")
+
+	ds, err := eval.LoadDatasetCSV(path)
+	require.NoError(t, err)
+	assert.Equal(t, "qa", ds.Name)
+	require.Len(t, ds.Samples, 2)
+	assert.Equal(t, eval.EvalSample{
+		Input:          "What is Go?",
+		ExpectedOutput: "A programming language",
+		Metadata:       map[string]any{"category": "lang"},
+	}, ds.Samples[0])
+	assert.Equal(t, `Say "hi", please`, ds.Samples[1].Input)
+}
+
+func TestLoadDatasetCSV_ColumnMapping(t *testing.T) {
+	path := writeFile(t, "qa.csv", "question,answer,reference,topic\nq1,a1,r1,t1\n")
+
+	ds, err := eval.LoadDatasetCSV(path, eval.WithColumnMapping(map[string]string{
+		"question":  eval.FieldInput,
+		"answer":    eval.FieldOutput,
+		"reference": eval.FieldExpectedOutput,
+		"topic":     eval.FieldMetadataPrefix + "category",
+	}))
+	require.NoError(t, err)
+	require.Len(t, ds.Samples, 1)
+	assert.Equal(t, eval.EvalSample{
+		Input:          "q1",
+		Output:         "a1",
+		ExpectedOutput: "r1",
+		Metadata:       map[string]any{"category": "t1"},
+	}, ds.Samples[0])
+}
+
+func TestLoadDatasetCSV_Errors(t *testing.T) {
+	_, err := eval.LoadDatasetCSV(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	_, err = eval.LoadDatasetCSV(writeFile(t, "empty.csv", ""))
+	assert.Error(t, err)
+
+	_, err = eval.LoadDatasetCSV(writeFile(t, "ragged.csv", "input,output\nq1\n"))
+	assert.Error(t, err)
+
+	_, err = eval.LoadDatasetCSV(writeFile(t, "qa.csv", "input\nq1\n"),
+		eval.WithColumnMapping(map[string]string{"input": "prompt"}))
+	assert.Error(t, err)
+}
+
+func TestLoadDatasetJSONL(t *testing.T) {
+	path := writeFile(t, "qa.jsonl", `{"input":"q1","output":"a1","expected":"e1","difficulty":3}
+
+{"input":"q2","metadata":{"category":"math"},"tags":["x"]}
+{"input":42}`)
+
+	ds, err := eval.LoadDatasetJSONL(path)
+	require.NoError(t, err)
+	assert.Equal(t, "qa", ds.Name)
+	require.Len(t, ds.Samples, 3)
+	assert.Equal(t, eval.EvalSample{
+		Input:          "q1",
+		Output:         "a1",
+		ExpectedOutput: "e1",
+		Metadata:       map[string]any{"difficulty": 3.0},
+	}, ds.Samples[0])
+	assert.Equal(t, map[string]any{"category": "math", "tags": []any{"x"}}, ds.Samples[1].Metadata)
+	assert.Equal(t, "42", ds.Samples[2].Input)
+}
+
+func TestLoadDatasetJSONL_InvalidLine(t *testing.T) {
+	_, err := eval.LoadDatasetJSONL(writeFile(t, "qa.jsonl", "{\"input\":\"q1\"}\nnot json\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -2,8 +2,11 @@ package eval_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/lookatitude/beluga-ai/v2/eval"
@@ -409,3 +412,105 @@ func TestDataset_LargeSampleCount(t *testing.T) {
 
 	assert.Len(t, loaded.Samples, 1000)
 }
+
+// categorizedDataset returns a dataset with 60 "easy", 30 "hard" and 10
+// uncategorized samples.
+func categorizedDataset() *eval.Dataset {
+	ds := &eval.Dataset{Name: "qa"}
+	for i := range 100 {
+		s := eval.EvalSample{Input: fmt.Sprintf("q%03d", i)}
+		switch {
+		case i < 60:
+			s.Metadata = map[string]any{"category": "easy"}
+		case i < 90:
+			s.Metadata = map[string]any{"category": "hard"}
+		}
+		ds.Samples = append(ds.Samples, s)
+	}
+	return ds
+}
+
+func countCategories(ds *eval.Dataset) map[any]int {
+	counts := make(map[any]int)
+	for _, s := range ds.Samples {
+		counts[s.Metadata["category"]]++
+	}
+	return counts
+}
+
+func inputs(ds *eval.Dataset) []string {
+	out := make([]string, len(ds.Samples))
+	for i, s := range ds.Samples {
+		out[i] = s.Input
+	}
+	return out
+}
+
+func TestDataset_Sample(t *testing.T) {
+	ds := categorizedDataset()
+
+	sample := ds.Sample(10)
+	assert.Equal(t, "qa", sample.Name)
+	require.Len(t, sample.Samples, 10)
+	assert.True(t, slices.IsSorted(inputs(sample)), "samples keep dataset order")
+	assert.Len(t, slices.Compact(inputs(sample)), 10, "no sample is drawn twice")
+
+	// Seeded: the same options draw the same subset.
+	assert.Equal(t, inputs(sample), inputs(ds.Sample(10)))
+	assert.NotEqual(t, inputs(sample), inputs(ds.Sample(10, eval.WithSampleSeed(42))))
+}
+
+func TestDataset_Sample_Stratified(t *testing.T) {
+	ds := categorizedDataset()
+
+	sample := ds.Sample(20, eval.WithStratifyBy("category"))
+	require.Len(t, sample.Samples, 20)
+	assert.Equal(t, map[any]int{"easy": 12, "hard": 6, nil: 2}, countCategories(sample))
+
+	// Largest remainders get the leftover samples.
+	sample = ds.Sample(7, eval.WithStratifyBy("category"))
+	require.Len(t, sample.Samples, 7)
+	assert.Equal(t, map[any]int{"easy": 4, "hard": 2, nil: 1}, countCategories(sample))
+}
+
+func TestDataset_Sample_Bounds(t *testing.T) {
+	ds := categorizedDataset()
+
+	assert.Len(t, ds.Sample(500).Samples, 100)
+	assert.Empty(t, ds.Sample(0).Samples)
+	assert.Empty(t, ds.Sample(-1).Samples)
+	assert.Empty(t, (&eval.Dataset{}).Sample(5).Samples)
+}
+
+func TestDataset_Split(t *testing.T) {
+	ds := categorizedDataset()
+
+	train, test, err := ds.Split(0.8, eval.WithStratifyBy("category"))
+	require.NoError(t, err)
+	assert.Equal(t, "qa-train", train.Name)
+	assert.Equal(t, "qa-test", test.Name)
+	assert.Len(t, train.Samples, 80)
+	assert.Len(t, test.Samples, 20)
+	assert.Equal(t, map[any]int{"easy": 48, "hard": 24, nil: 8}, countCategories(train))
+
+	all := append(inputs(train), inputs(test)...)
+	slices.Sort(all)
+	assert.Equal(t, inputs(ds), all, "train and test partition the dataset")
+
+	train2, _, err := ds.Split(0.8, eval.WithStratifyBy("category"))
+	require.NoError(t, err)
+	assert.Equal(t, inputs(train), inputs(train2))
+}
+
+func TestDataset_Split_InvalidRatio(t *testing.T) {
+	ds := categorizedDataset()
+	for _, ratio := range []float64{-0.1, 1.5, math.NaN()} {
+		_, _, err := ds.Split(ratio)
+		assert.Error(t, err, "ratio %v", ratio)
+	}
+
+	train, test, err := ds.Split(1)
+	require.NoError(t, err)
+	assert.Len(t, train.Samples, 100)
+	assert.Empty(t, test.Samples)
+}
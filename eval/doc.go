@@ -34,11 +34,84 @@
 //   - WithHooks sets lifecycle callbacks (BeforeRun, AfterRun, BeforeSample,
 //     AfterSample).
 //
+// # Statistics
+//
+// Besides the mean of each metric, EvalReport.Stats holds its standard
+// deviation and a bootstrap confidence interval, configured with WithStats.
+// CompareReports tells whether the metric differences between two reports,
+// such as runs of two prompt variants on the same dataset, are significant
+// or noise:
+//
+//	cmp := eval.CompareReports(baseline, candidate)
+//	for name, c := range cmp.Metrics {
+//	    fmt.Printf("%s: %+.3f (p=%.3f, significant=%v)\n", name, c.Diff, c.PValue, c.Significant)
+//	}
+//
+// # Regression Gate
+//
+// SaveBaseline records the metric means of a report, and
+// WithRegressionGate makes later runs fail when a metric drops below its
+// baseline by more than its tolerance, turning an evaluation into a CI
+// quality gate. The AggregateMetric key gates the mean of all metrics:
+//
+//	baseline, err := eval.LoadBaseline("testdata/baseline.json")
+//	runner := eval.NewRunner(
+//	    eval.WithMetrics(metrics...),
+//	    eval.WithDataset(samples),
+//	    eval.WithRegressionGate(baseline, map[string]float64{
+//	        "faithfulness":       0.02,
+//	        eval.AggregateMetric: 0.01,
+//	    }),
+//	)
+//	report, err := runner.Run(ctx)
+//	var cerr *core.Error
+//	if errors.As(err, &cerr) && cerr.Code == eval.ErrRegression {
+//	    log.Fatalf("regressed: %v", report.Gate.Regressions())
+//	}
+//
+// CheckBaseline applies the same gate to an existing report.
+//
+// # Export
+//
+// EvalReport.WriteCSV, WriteJSONL and WriteHTML export per-sample results
+// for spreadsheets, data pipelines and browsers; the HTML page lets readers
+// drill down from each score to the sample's input and output.
+//
+// # Pairwise Evaluation
+//
+// PairwiseMetric compares two outputs for the same input and picks the
+// better one (WinnerA, WinnerB or Tie), for "model A vs model B"
+// evaluations that absolute scores cannot capture. RunPairwise compares
+// each dataset sample with the sample at the same index of the comparison
+// dataset and reports the win rates of each metric:
+//
+//	runner := eval.NewRunner(
+//	    eval.WithPairwiseMetrics(pairwiseJudge),
+//	    eval.WithDataset(modelA),
+//	    eval.WithComparisonDataset(modelB),
+//	)
+//	report, err := runner.RunPairwise(ctx)
+//	rate := report.WinRates["pairwise_judge"]
+//	fmt.Printf("A %.0f%%, B %.0f%%, tie %.0f%%\n", rate.A*100, rate.B*100, rate.Tie*100)
+//
+// eval/judge provides PairwiseJudge, an LLM judge implementing
+// PairwiseMetric.
+//
 // # Dataset
 //
 // Dataset is a named collection of EvalSample values that can be loaded from
 // and saved to JSON files via LoadDataset and Save.
 //
+// LoadDatasetCSV and LoadDatasetJSONL read CSV and JSON Lines files,
+// mapping columns to sample fields with WithColumnMapping.
+//
+// Sample draws a seeded random subset, optionally stratified by a metadata
+// field, for quick evaluations in CI, and Split divides a dataset into
+// training and test sets:
+//
+//	smoke := ds.Sample(200, eval.WithStratifyBy("category"))
+//	train, test, err := ds.Split(0.8, eval.WithSampleSeed(7))
+//
 // # Augmenter
 //
 // The Augmenter interface generates additional evaluation samples from
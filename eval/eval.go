@@ -72,6 +72,12 @@ type EvalReport struct {
 	Samples []SampleResult
 	// Metrics contains the average score for each metric across all samples.
 	Metrics map[string]float64
+	// Stats contains the spread and confidence interval of each metric's
+	// scores. Compare two reports with CompareReports.
+	Stats map[string]MetricStats
+	// Gate is the outcome of the regression gate set with
+	// WithRegressionGate, or nil if the run was not gated.
+	Gate *GateResult
 	// Duration is the total wall-clock time of the evaluation run.
 	Duration time.Duration
 	// Errors collects all errors encountered during evaluation.
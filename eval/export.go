@@ -0,0 +1,175 @@
+package eval
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// metricNames returns the names of the metrics scored in the report, in
+// alphabetical order so that exports are deterministic.
+func (r *EvalReport) metricNames() []string {
+	seen := make(map[string]struct{})
+	for name := range r.Metrics {
+		seen[name] = struct{}{}
+	}
+	for _, res := range r.Samples {
+		for name := range res.Scores {
+			seen[name] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// errorText returns the message of err, or "" if err is nil.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// WriteCSV writes one row per sample to w: its index, input, output,
+// expected output, a column per metric, in alphabetical order, and its
+// error. Missing scores are left empty.
+func (r *EvalReport) WriteCSV(w io.Writer) error {
+	names := r.metricNames()
+	cw := csv.NewWriter(w)
+
+	header := append([]string{"index", FieldInput, FieldOutput, FieldExpectedOutput}, names...)
+	if err := cw.Write(append(header, "error")); err != nil {
+		return err
+	}
+	for i, res := range r.Samples {
+		row := []string{strconv.Itoa(i), res.Sample.Input, res.Sample.Output, res.Sample.ExpectedOutput}
+		for _, name := range names {
+			score, ok := res.Scores[name]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(score, 'g', -1, 64))
+		}
+		if err := cw.Write(append(row, errorText(res.Error))); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// sampleRecord is the JSONL form of a sample result.
+type sampleRecord struct {
+	Index          int                `json:"index"`
+	Input          string             `json:"input"`
+	Output         string             `json:"output"`
+	ExpectedOutput string             `json:"expected_output,omitempty"`
+	Metadata       map[string]any     `json:"metadata,omitempty"`
+	Scores         map[string]float64 `json:"scores"`
+	Error          string             `json:"error,omitempty"`
+}
+
+// WriteJSONL writes one JSON object per sample to w, holding its index,
+// input, output, expected output, metadata, scores and error. The output
+// can be read back as a dataset with LoadDatasetJSONL.
+func (r *EvalReport) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, res := range r.Samples {
+		rec := sampleRecord{
+			Index:          i,
+			Input:          res.Sample.Input,
+			Output:         res.Sample.Output,
+			ExpectedOutput: res.Sample.ExpectedOutput,
+			Metadata:       res.Sample.Metadata,
+			Scores:         res.Scores,
+			Error:          errorText(res.Error),
+		}
+		if rec.Scores == nil {
+			rec.Scores = map[string]float64{}
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"score": func(scores map[string]float64, name string) string {
+		score, ok := scores[name]
+		if !ok {
+			return "–"
+		}
+		return strconv.FormatFloat(score, 'f', 3, 64)
+	},
+	"f3":        func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) },
+	"errorText": errorText,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Evaluation report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2rem; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+tr.failed { background: #fdecea; }
+details pre { white-space: pre-wrap; max-width: 60rem; }
+</style>
+</head>
+<body>
+<h1>Evaluation report</h1>
+<p>{{len .Report.Samples}} samples, {{len .Report.Errors}} errors, {{.Report.Duration}}</p>
+<h2>Metrics</h2>
+<table>
+<tr><th>Metric</th><th>Mean</th><th>Std dev</th><th>Confidence interval</th><th>N</th></tr>
+{{- range $name := .Metrics}}
+{{- $stats := index $.Report.Stats $name}}
+<tr><td>{{$name}}</td><td class="num">{{f3 (index $.Report.Metrics $name)}}</td><td class="num">{{f3 $stats.StdDev}}</td><td class="num">{{f3 $stats.CILower}} – {{f3 $stats.CIUpper}}</td><td class="num">{{$stats.N}}</td></tr>
+{{- end}}
+</table>
+<h2>Samples</h2>
+<table>
+<tr><th>#</th><th>Sample</th>{{range .Metrics}}<th>{{.}}</th>{{end}}<th>Error</th></tr>
+{{- range $i, $res := .Report.Samples}}
+<tr{{if $res.Error}} class="failed"{{end}}>
+<td class="num">{{$i}}</td>
+<td><details><summary>{{$res.Sample.Input}}</summary>
+<pre><b>Input:</b> {{$res.Sample.Input}}</pre>
+<pre><b>Output:</b> {{$res.Sample.Output}}</pre>
+{{- if $res.Sample.ExpectedOutput}}
+<pre><b>Expected:</b> {{$res.Sample.ExpectedOutput}}</pre>
+{{- end}}
+</details></td>
+{{- range $.Metrics}}
+<td class="num">{{score $res.Scores .}}</td>
+{{- end}}
+<td>{{errorText $res.Error}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the report to w as a self-contained HTML page: a table
+// of the metrics' means, spreads and confidence intervals, and a table of
+// per-sample scores in which each sample expands to show its input, output
+// and expected output. Failed samples are highlighted.
+func (r *EvalReport) WriteHTML(w io.Writer) error {
+	data := struct {
+		Report  *EvalReport
+		Metrics []string
+	}{Report: r, Metrics: r.metricNames()}
+	return reportTemplate.Execute(w, data)
+}
@@ -0,0 +1,83 @@
+package eval_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportReport() *eval.EvalReport {
+	return &eval.EvalReport{
+		Samples: []eval.SampleResult{
+			{
+				Sample: eval.EvalSample{Input: "What is Go?", Output: "A language, by Google", ExpectedOutput: "A language"},
+				Scores: map[string]float64{"relevance": 0.9, "toxicity": 0},
+			},
+			{
+				Sample: eval.EvalSample{Input: "<script>alert(1)</script>", Output: "no"},
+				Scores: map[string]float64{"relevance": 0.25},
+				Error:  errors.New("toxicity: timeout"),
+			},
+		},
+		Metrics: map[string]float64{"relevance": 0.575, "toxicity": 0},
+		Stats: map[string]eval.MetricStats{
+			"relevance": {Mean: 0.575, StdDev: 0.46, CILower: 0.25, CIUpper: 0.9, N: 2},
+			"toxicity":  {N: 1},
+		},
+	}
+}
+
+func TestEvalReport_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportReport().WriteCSV(&buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"index", "input", "output", "expected_output", "relevance", "toxicity", "error"},
+		{"0", "What is Go?", "A language, by Google", "A language", "0.9", "0", ""},
+		{"1", "<script>alert(1)</script>", "no", "", "0.25", "", "toxicity: timeout"},
+	}, records)
+}
+
+func TestEvalReport_WriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportReport().WriteJSONL(&buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, 1.0, rec["index"])
+	assert.Equal(t, "<script>alert(1)</script>", rec["input"])
+	assert.Equal(t, map[string]any{"relevance": 0.25}, rec["scores"])
+	assert.Equal(t, "toxicity: timeout", rec["error"])
+
+	// The export reads back as a dataset.
+	ds, err := eval.LoadDatasetJSONL(writeFile(t, "report.jsonl", buf.String()))
+	require.NoError(t, err)
+	require.Len(t, ds.Samples, 2)
+	assert.Equal(t, "A language", ds.Samples[0].ExpectedOutput)
+}
+
+func TestEvalReport_WriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportReport().WriteHTML(&buf))
+	html := buf.String()
+
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Contains(t, html, "<th>relevance</th><th>toxicity</th>")
+	assert.Contains(t, html, "0.250 – 0.900")
+	assert.Contains(t, html, `<tr class="failed">`)
+	assert.Contains(t, html, "toxicity: timeout")
+	assert.Contains(t, html, "<b>Expected:</b> A language")
+	assert.NotContains(t, html, "<script>", "sample text is escaped")
+	assert.Contains(t, html, "&lt;script&gt;")
+}
@@ -1,12 +1,14 @@
 // Package judge provides LLM-as-Judge evaluation capabilities.
 //
 // It implements eval.Metric using an LLM to score samples against structured
-// rubrics. BatchJudge enables concurrent evaluation with rate limiting, and
+// rubrics, and eval.PairwiseMetric using an LLM to pick the better of two
+// responses. BatchJudge enables concurrent evaluation with rate limiting, and
 // ConsistencyChecker validates scoring reliability through repeated evaluation
 // and cross-model agreement analysis.
 //
 // Key types:
 //   - JudgeMetric: Evaluates samples using an LLM judge and a rubric
+//   - PairwiseJudge: Compares two responses using an LLM judge
 //   - Rubric: Defines scoring criteria with levels and weights
 //   - BatchJudge: Concurrent evaluation with bounded parallelism
 //   - ConsistencyChecker: Repeated eval + cross-model agreement
@@ -416,3 +416,73 @@ func TestParseJudgeResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestPairwiseJudge_Compare(t *testing.T) {
+	model := newMock(mockllm.WithResponse(schema.NewAIMessage("winner: B\nconfidence: 0.7")))
+	pj, err := NewPairwiseJudge(WithModel(model), WithRubric(testRubric()))
+	require.NoError(t, err)
+	assert.Equal(t, "pairwise_judge", pj.Name())
+
+	a := eval.EvalSample{Input: "What is 2+2?", Output: "5", ExpectedOutput: "4"}
+	b := eval.EvalSample{Input: "What is 2+2?", Output: "4"}
+	winner, score, err := pj.Compare(context.Background(), a, b)
+	require.NoError(t, err)
+	assert.Equal(t, eval.WinnerB, winner)
+	assert.InDelta(t, 0.7, score, 1e-9)
+
+	msgs := model.LastMessages()
+	require.Len(t, msgs, 1)
+	prompt := msgs[0].(*schema.HumanMessage).Text()
+	assert.Contains(t, prompt, "Response A: 5")
+	assert.Contains(t, prompt, "Response B: 4")
+	assert.Contains(t, prompt, "Expected/Reference Answer: 4")
+	assert.Contains(t, prompt, "accuracy")
+}
+
+func TestPairwiseJudge_Errors(t *testing.T) {
+	_, err := NewPairwiseJudge()
+	require.Error(t, err)
+
+	_, err = NewPairwiseJudge(WithModel(newMock()), WithRubric(&Rubric{}))
+	require.Error(t, err)
+
+	pj, err := NewPairwiseJudge(WithModel(newMock(mockllm.WithError(errors.New("boom")))))
+	require.NoError(t, err)
+	_, _, err = pj.Compare(context.Background(), eval.EvalSample{}, eval.EvalSample{})
+	require.Error(t, err)
+
+	pj, err = NewPairwiseJudge(WithModel(newMock(mockllm.WithResponse(schema.NewAIMessage("no verdict")))))
+	require.NoError(t, err)
+	_, _, err = pj.Compare(context.Background(), eval.EvalSample{}, eval.EvalSample{})
+	require.Error(t, err)
+}
+
+func TestParsePairwiseResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		wantWinner     int
+		wantConfidence float64
+		wantErr        bool
+	}{
+		{name: "winner A", text: "winner: A\nconfidence: 0.9", wantWinner: eval.WinnerA, wantConfidence: 0.9},
+		{name: "tie", text: "Winner: Tie\nConfidence: 0.5", wantWinner: eval.Tie, wantConfidence: 0.5},
+		{name: "decorated verdict", text: "After comparing both:\nwinner: **B**", wantWinner: eval.WinnerB, wantConfidence: 1},
+		{name: "confidence clamped", text: "winner: a\nconfidence: 1.4", wantWinner: eval.WinnerA, wantConfidence: 1},
+		{name: "unknown verdict", text: "winner: C", wantErr: true},
+		{name: "missing verdict", text: "confidence: 0.9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, confidence, err := parsePairwiseResponse(tt.text)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWinner, winner)
+			assert.InDelta(t, tt.wantConfidence, confidence, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+const pairwisePromptTemplate = `You are an expert evaluation judge. Compare two AI-generated responses to the same input and decide which one is better.
+
+%s
+
+Input/Question: %s
+
+Response A: %s
+
+Response B: %s
+
+%s
+
+Respond with ONLY the following two lines:
+winner: A, B or tie
+confidence: a number between 0.0 and 1.0`
+
+// Compile-time interface check.
+var _ eval.PairwiseMetric = (*PairwiseJudge)(nil)
+
+// PairwiseJudge compares two samples using an LLM as judge and picks the
+// better response. It implements eval.PairwiseMetric, returning the judge's
+// confidence in its verdict as the score. The rubric, if set, tells the
+// judge what to compare on. The input and expected output are taken from
+// sample a.
+type PairwiseJudge struct {
+	opts judgeOptions
+}
+
+// NewPairwiseJudge creates a new PairwiseJudge with the given options.
+// WithModel is required; WithRubric is optional.
+func NewPairwiseJudge(opts ...JudgeOption) (*PairwiseJudge, error) {
+	o := judgeOptions{metricName: "pairwise_judge"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.model == nil {
+		return nil, core.NewError("judge.pairwise.new", core.ErrInvalidInput, "model is required", nil)
+	}
+	if o.rubric != nil {
+		if err := o.rubric.Validate(); err != nil {
+			return nil, core.NewError("judge.pairwise.new", core.ErrInvalidInput, "invalid rubric", err)
+		}
+	}
+	return &PairwiseJudge{opts: o}, nil
+}
+
+// Name returns the metric name.
+func (j *PairwiseJudge) Name() string { return j.opts.metricName }
+
+// Compare asks the LLM judge which of a and b is the better response.
+func (j *PairwiseJudge) Compare(ctx context.Context, a, b eval.EvalSample) (int, float64, error) {
+	criteria := "Judge overall quality: correctness, helpfulness and clarity."
+	if j.opts.rubric != nil {
+		criteria = j.opts.rubric.ToPrompt()
+	}
+	expectedCtx := ""
+	if a.ExpectedOutput != "" {
+		expectedCtx = fmt.Sprintf("Expected/Reference Answer: %s", a.ExpectedOutput)
+	}
+
+	prompt := fmt.Sprintf(pairwisePromptTemplate,
+		criteria,
+		a.Input,
+		a.Output,
+		b.Output,
+		expectedCtx,
+	)
+
+	var msgs []schema.Message
+	if j.opts.systemMsg != "" {
+		msgs = append(msgs, schema.NewSystemMessage(j.opts.systemMsg))
+	}
+	msgs = append(msgs, schema.NewHumanMessage(prompt))
+
+	resp, err := j.opts.model.Generate(ctx, msgs)
+	if err != nil {
+		return 0, 0, core.NewError("judge.compare", core.ErrToolFailed, "llm generate failed", err)
+	}
+
+	winner, confidence, err := parsePairwiseResponse(resp.Text())
+	if err != nil {
+		return 0, 0, core.NewError("judge.compare", core.ErrInvalidInput, "parse response failed", err)
+	}
+	return winner, confidence, nil
+}
+
+// parsePairwiseResponse extracts the verdict and confidence from the LLM
+// response. A missing confidence defaults to 1.
+func parsePairwiseResponse(text string) (winner int, confidence float64, err error) {
+	confidence = 1
+	found := false
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "winner":
+			switch strings.ToLower(strings.Trim(value, ".*\"' ")) {
+			case "a":
+				winner, found = eval.WinnerA, true
+			case "b":
+				winner, found = eval.WinnerB, true
+			case "tie":
+				winner, found = eval.Tie, true
+			}
+		case "confidence":
+			val, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			confidence = min(max(val, 0), 1)
+		}
+	}
+	if !found {
+		return 0, 0, fmt.Errorf("missing winner in response %q", text)
+	}
+	return winner, confidence, nil
+}
@@ -13,6 +13,17 @@
 //   - Hallucination detects fabricated facts by comparing answers against
 //     context documents. Requires an llm.ChatModel as judge.
 //
+// LLM judges are nondeterministic and each verdict costs a call. Pass
+// JudgeOptions to their constructors to make runs reproducible and cheaper:
+// WithJudgeCache reuses verdicts from a cache.Cache, keyed by metric, judge
+// model and sample, and WithJudgeSamples asks the judge k times and takes
+// the mean, or with WithJudgeMajority the most common verdict.
+//
+//	faith := metrics.NewFaithfulness(judgeModel,
+//	    metrics.WithJudgeCache(c),
+//	    metrics.WithJudgeSamples(3),
+//	)
+//
 // # Keyword-Based Metrics
 //
 //   - Toxicity performs keyword-based toxicity checking. Returns 1.0 (not
@@ -31,12 +31,12 @@ Respond with ONLY a single decimal number between 0.0 and 1.0.`
 // Faithfulness evaluates whether an AI-generated answer is grounded in the
 // provided context documents. It uses an LLM as a judge to assess faithfulness.
 type Faithfulness struct {
-	llm llm.ChatModel
+	judge judge
 }
 
 // NewFaithfulness creates a new Faithfulness metric using the given LLM as judge.
-func NewFaithfulness(model llm.ChatModel) *Faithfulness {
-	return &Faithfulness{llm: model}
+func NewFaithfulness(model llm.ChatModel, opts ...JudgeOption) *Faithfulness {
+	return &Faithfulness{judge: newJudge(model, opts)}
 }
 
 // Name returns "faithfulness".
@@ -47,15 +47,7 @@ func (f *Faithfulness) Name() string { return "faithfulness" }
 func (f *Faithfulness) Score(ctx context.Context, sample eval.EvalSample) (float64, error) {
 	docs := formatDocs(sample.RetrievedDocs)
 	prompt := fmt.Sprintf(faithfulnessPrompt, sample.Input, docs, sample.Output)
-
-	resp, err := f.llm.Generate(ctx, []schema.Message{
-		schema.NewHumanMessage(prompt),
-	})
-	if err != nil {
-		return 0, core.Errorf(core.ErrProviderDown, "faithfulness: llm generate: %w", err)
-	}
-
-	return parseScore(resp.Text())
+	return f.judge.score(ctx, f.Name(), prompt)
 }
 
 // formatDocs concatenates document contents into a numbered list.
@@ -4,10 +4,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/eval"
 	"github.com/lookatitude/beluga-ai/v2/llm"
-	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
 const hallucinationPrompt = `You are an evaluation judge. Given a question, context documents, and an answer, detect whether the answer contains fabricated or hallucinated information that is not supported by the context or commonly known facts.
@@ -30,12 +28,12 @@ Respond with ONLY a single decimal number between 0.0 and 1.0.`
 // them against the provided context documents. It uses an LLM as a judge.
 // A score of 1.0 means no hallucination was detected.
 type Hallucination struct {
-	llm llm.ChatModel
+	judge judge
 }
 
 // NewHallucination creates a new Hallucination metric using the given LLM as judge.
-func NewHallucination(model llm.ChatModel) *Hallucination {
-	return &Hallucination{llm: model}
+func NewHallucination(model llm.ChatModel, opts ...JudgeOption) *Hallucination {
+	return &Hallucination{judge: newJudge(model, opts)}
 }
 
 // Name returns "hallucination".
@@ -46,13 +44,5 @@ func (h *Hallucination) Name() string { return "hallucination" }
 func (h *Hallucination) Score(ctx context.Context, sample eval.EvalSample) (float64, error) {
 	docs := formatDocs(sample.RetrievedDocs)
 	prompt := fmt.Sprintf(hallucinationPrompt, sample.Input, docs, sample.Output)
-
-	resp, err := h.llm.Generate(ctx, []schema.Message{
-		schema.NewHumanMessage(prompt),
-	})
-	if err != nil {
-		return 0, core.Errorf(core.ErrProviderDown, "hallucination: llm generate: %w", err)
-	}
-
-	return parseScore(resp.Text())
+	return h.judge.score(ctx, h.Name(), prompt)
 }
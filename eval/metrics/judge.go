@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+
+	"github.com/lookatitude/beluga-ai/v2/cache"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// judgeOptions holds the configuration shared by the LLM-as-judge metrics.
+type judgeOptions struct {
+	cache    cache.Cache
+	samples  int
+	majority bool
+}
+
+// JudgeOption configures an LLM-as-judge metric: Faithfulness, Relevance
+// or Hallucination.
+type JudgeOption func(*judgeOptions)
+
+// WithJudgeCache caches verdicts in c, keyed by the metric, the judge model
+// and a hash of the judge prompt, which holds the sample fields the metric
+// reads. Repeated runs over the same samples then reuse the verdicts
+// instead of calling the judge again. Entries use the cache's default TTL.
+// Cache errors are ignored: the judge is called instead.
+func WithJudgeCache(c cache.Cache) JudgeOption {
+	return func(o *judgeOptions) {
+		o.cache = c
+	}
+}
+
+// WithJudgeSamples asks the judge k times per sample and takes the mean of
+// its verdicts, or the most common verdict with WithJudgeMajority, to
+// smooth out the judge's nondeterminism. Defaults to 1.
+func WithJudgeSamples(k int) JudgeOption {
+	return func(o *judgeOptions) {
+		if k > 0 {
+			o.samples = k
+		}
+	}
+}
+
+// WithJudgeMajority makes WithJudgeSamples take the most common verdict
+// instead of the mean. Ties go to the lowest of the tied verdicts.
+func WithJudgeMajority() JudgeOption {
+	return func(o *judgeOptions) {
+		o.majority = true
+	}
+}
+
+// judge scores prompts with an LLM, applying caching and self-consistency.
+type judge struct {
+	model llm.ChatModel
+	opts  judgeOptions
+}
+
+// newJudge creates a judge for model with the given options.
+func newJudge(model llm.ChatModel, opts []JudgeOption) judge {
+	o := judgeOptions{samples: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return judge{model: model, opts: o}
+}
+
+// score returns the verdict on prompt for the named metric, from the cache
+// if present there.
+func (j judge) score(ctx context.Context, metric, prompt string) (float64, error) {
+	key := j.cacheKey(metric, prompt)
+	if j.opts.cache != nil {
+		if v, ok, err := j.opts.cache.Get(ctx, key); err == nil && ok {
+			if score, ok := v.(float64); ok {
+				return score, nil
+			}
+		}
+	}
+
+	verdicts := make([]float64, 0, j.opts.samples)
+	for range j.opts.samples {
+		resp, err := j.model.Generate(ctx, []schema.Message{
+			schema.NewHumanMessage(prompt),
+		})
+		if err != nil {
+			return 0, core.Errorf(core.ErrProviderDown, "%s: llm generate: %w", metric, err)
+		}
+		verdict, err := parseScore(resp.Text())
+		if err != nil {
+			return 0, err
+		}
+		verdicts = append(verdicts, verdict)
+	}
+
+	score := meanVerdict(verdicts)
+	if j.opts.majority {
+		score = majorityVerdict(verdicts)
+	}
+	if j.opts.cache != nil {
+		_ = j.opts.cache.Set(ctx, key, score, 0)
+	}
+	return score, nil
+}
+
+// cacheKey returns the cache key of the verdict on prompt for the named
+// metric. The number of samples and the aggregation are part of the key,
+// since they change the verdict.
+func (j judge) cacheKey(metric, prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	agg := "mean"
+	if j.opts.majority {
+		agg = "majority"
+	}
+	return "eval.judge:" + metric + ":" + j.model.ModelID() + ":" +
+		strconv.Itoa(j.opts.samples) + agg + ":" + hex.EncodeToString(sum[:])
+}
+
+// meanVerdict returns the mean of verdicts.
+func meanVerdict(verdicts []float64) float64 {
+	var sum float64
+	for _, v := range verdicts {
+		sum += v
+	}
+	return sum / float64(len(verdicts))
+}
+
+// majorityVerdict returns the most common of verdicts, the lowest one on
+// ties.
+func majorityVerdict(verdicts []float64) float64 {
+	sorted := slices.Clone(verdicts)
+	slices.Sort(sorted)
+	best, bestCount := sorted[0], 0
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+		if j-i > bestCount {
+			best, bestCount = sorted[i], j-i
+		}
+		i = j
+	}
+	return best
+}
@@ -0,0 +1,151 @@
+package metrics_test
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/cache"
+	"github.com/lookatitude/beluga-ai/v2/cache/providers/inmemory"
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/lookatitude/beluga-ai/v2/eval/metrics"
+	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mockllm"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceModel answers with the given verdicts in turn, cycling.
+type sequenceModel struct {
+	mu       sync.Mutex
+	id       string
+	verdicts []string
+	calls    int
+}
+
+func (m *sequenceModel) Generate(_ context.Context, _ []schema.Message, _ ...llm.GenerateOption) (*schema.AIMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v := m.verdicts[m.calls%len(m.verdicts)]
+	m.calls++
+	return schema.NewAIMessage(v), nil
+}
+
+func (m *sequenceModel) Stream(_ context.Context, _ []schema.Message, _ ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
+	return func(func(schema.StreamChunk, error) bool) {}
+}
+
+func (m *sequenceModel) BindTools(_ []schema.ToolDefinition) llm.ChatModel { return m }
+
+func (m *sequenceModel) ModelID() string { return m.id }
+
+func (m *sequenceModel) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+var judgeSample = eval.EvalSample{
+	Input:         "What is Go?",
+	Output:        "Go is a programming language.",
+	RetrievedDocs: []schema.Document{{ID: "doc1", Content: "Go is a programming language."}},
+}
+
+func TestJudge_Cache(t *testing.T) {
+	ctx := context.Background()
+	c := inmemory.New(cache.Config{})
+	model := &sequenceModel{id: "judge-a", verdicts: []string{"0.8", "0.2"}}
+	f := metrics.NewFaithfulness(model, metrics.WithJudgeCache(c))
+
+	first, err := f.Score(ctx, judgeSample)
+	require.NoError(t, err)
+	second, err := f.Score(ctx, judgeSample)
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, first)
+	assert.Equal(t, first, second, "the cached verdict is reused")
+	assert.Equal(t, 1, model.Calls())
+
+	// Another sample misses the cache.
+	other := judgeSample
+	other.Output = "Go is a board game."
+	score, err := f.Score(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, score)
+	assert.Equal(t, 2, model.Calls())
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestJudge_CacheKeyedByMetricAndModel(t *testing.T) {
+	ctx := context.Background()
+	c := inmemory.New(cache.Config{})
+	modelA := &sequenceModel{id: "judge-a", verdicts: []string{"0.9"}}
+	modelB := &sequenceModel{id: "judge-b", verdicts: []string{"0.3"}}
+
+	a, err := metrics.NewFaithfulness(modelA, metrics.WithJudgeCache(c)).Score(ctx, judgeSample)
+	require.NoError(t, err)
+	b, err := metrics.NewFaithfulness(modelB, metrics.WithJudgeCache(c)).Score(ctx, judgeSample)
+	require.NoError(t, err)
+	h, err := metrics.NewHallucination(modelA, metrics.WithJudgeCache(c)).Score(ctx, judgeSample)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.9, a)
+	assert.Equal(t, 0.3, b)
+	assert.Equal(t, 0.9, h)
+	assert.Equal(t, 2, modelA.Calls())
+	assert.Equal(t, 1, modelB.Calls())
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestJudge_Samples(t *testing.T) {
+	tests := []struct {
+		name     string
+		verdicts []string
+		opts     []metrics.JudgeOption
+		want     float64
+	}{
+		{name: "mean", verdicts: []string{"1.0", "0.5", "0.0"}, opts: []metrics.JudgeOption{metrics.WithJudgeSamples(3)}, want: 0.5},
+		{name: "majority", verdicts: []string{"1.0", "0.0", "1.0"}, opts: []metrics.JudgeOption{metrics.WithJudgeSamples(3), metrics.WithJudgeMajority()}, want: 1.0},
+		{name: "majority tie", verdicts: []string{"1.0", "0.5"}, opts: []metrics.JudgeOption{metrics.WithJudgeSamples(2), metrics.WithJudgeMajority()}, want: 0.5},
+		{name: "invalid k", verdicts: []string{"0.7", "0.1"}, opts: []metrics.JudgeOption{metrics.WithJudgeSamples(0)}, want: 0.7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &sequenceModel{id: "judge", verdicts: tt.verdicts}
+			score, err := metrics.NewRelevance(model, tt.opts...).Score(context.Background(), judgeSample)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, score, 1e-9)
+		})
+	}
+}
+
+func TestJudge_SamplesCached(t *testing.T) {
+	ctx := context.Background()
+	c := inmemory.New(cache.Config{})
+	model := &sequenceModel{id: "judge", verdicts: []string{"1.0", "0.0", "0.5"}}
+	r := metrics.NewRelevance(model, metrics.WithJudgeSamples(3), metrics.WithJudgeCache(c))
+
+	for range 3 {
+		score, err := r.Score(ctx, judgeSample)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.5, score, 1e-9)
+	}
+	assert.Equal(t, 3, model.Calls(), "one round of k verdicts, then cached")
+}
+
+func TestJudge_ErrorNotCached(t *testing.T) {
+	ctx := context.Background()
+	c := inmemory.New(cache.Config{})
+	model := newMockChatModel(mockllm.WithResponse(schema.NewAIMessage("not a number")))
+	f := metrics.NewFaithfulness(model, metrics.WithJudgeCache(c))
+
+	_, err := f.Score(ctx, judgeSample)
+	require.Error(t, err)
+	assert.Equal(t, 0, c.Len())
+
+	model.SetResponse(schema.NewAIMessage("0.6"))
+	score, err := f.Score(ctx, judgeSample)
+	require.NoError(t, err)
+	assert.Equal(t, 0.6, score)
+}
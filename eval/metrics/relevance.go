@@ -4,10 +4,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/eval"
 	"github.com/lookatitude/beluga-ai/v2/llm"
-	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
 const relevancePrompt = `You are an evaluation judge. Given a question and an answer, evaluate whether the answer is relevant to and adequately addresses the question.
@@ -26,12 +24,12 @@ Respond with ONLY a single decimal number between 0.0 and 1.0.`
 // Relevance evaluates whether an AI-generated answer adequately addresses the
 // input question. It uses an LLM as a judge to assess relevance.
 type Relevance struct {
-	llm llm.ChatModel
+	judge judge
 }
 
 // NewRelevance creates a new Relevance metric using the given LLM as judge.
-func NewRelevance(model llm.ChatModel) *Relevance {
-	return &Relevance{llm: model}
+func NewRelevance(model llm.ChatModel, opts ...JudgeOption) *Relevance {
+	return &Relevance{judge: newJudge(model, opts)}
 }
 
 // Name returns "relevance".
@@ -41,13 +39,5 @@ func (r *Relevance) Name() string { return "relevance" }
 // Returns a score in [0.0, 1.0] where 1.0 means fully relevant.
 func (r *Relevance) Score(ctx context.Context, sample eval.EvalSample) (float64, error) {
 	prompt := fmt.Sprintf(relevancePrompt, sample.Input, sample.Output)
-
-	resp, err := r.llm.Generate(ctx, []schema.Message{
-		schema.NewHumanMessage(prompt),
-	})
-	if err != nil {
-		return 0, core.Errorf(core.ErrProviderDown, "relevance: llm generate: %w", err)
-	}
-
-	return parseScore(resp.Text())
+	return r.judge.score(ctx, r.Name(), prompt)
 }
@@ -0,0 +1,234 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+)
+
+// Outcomes of a pairwise comparison, as returned by PairwiseMetric.Compare.
+const (
+	// Tie means neither sample is better.
+	Tie = -1
+	// WinnerA means the first sample is better.
+	WinnerA = 0
+	// WinnerB means the second sample is better.
+	WinnerB = 1
+)
+
+// PairwiseMetric compares two outputs for the same input, such as the
+// answers of two models, and picks the better one. It captures relative
+// preferences that absolute scores from a Metric miss.
+type PairwiseMetric interface {
+	// Name returns the unique name of this metric (e.g., "preference").
+	Name() string
+
+	// Compare judges sample a against sample b and returns the winner
+	// (WinnerA, WinnerB or Tie) and the strength of the preference in
+	// [0.0, 1.0].
+	Compare(ctx context.Context, a, b EvalSample) (winner int, score float64, err error)
+}
+
+// PairResult holds the outcome of comparing one pair of samples across all
+// configured pairwise metrics.
+type PairResult struct {
+	// A and B are the compared samples.
+	A, B EvalSample
+	// Winners maps metric names to the winner they picked.
+	Winners map[string]int
+	// Scores maps metric names to the strength of their preference.
+	Scores map[string]float64
+	// Error is set if any metric failed for this pair.
+	Error error
+}
+
+// WinRate summarizes the outcomes of one pairwise metric across a dataset.
+type WinRate struct {
+	// A, B and Tie are the fractions of comparisons won by A, won by B and
+	// tied. They sum to 1 when Comparisons is positive.
+	A, B, Tie float64
+	// Comparisons is the number of successful comparisons.
+	Comparisons int
+}
+
+// PairwiseReport is the aggregate result of a pairwise evaluation run.
+type PairwiseReport struct {
+	// Pairs contains the per-pair results.
+	Pairs []PairResult
+	// WinRates maps metric names to their win rates.
+	WinRates map[string]WinRate
+	// Duration is the total wall-clock time of the evaluation run.
+	Duration time.Duration
+	// Errors collects all errors encountered during evaluation.
+	Errors []error
+}
+
+// WithPairwiseMetrics sets the metrics used by RunPairwise.
+func WithPairwiseMetrics(metrics ...PairwiseMetric) RunnerOption {
+	return func(r *EvalRunner) {
+		r.pairwise = metrics
+	}
+}
+
+// WithComparisonDataset sets the samples RunPairwise compares against the
+// dataset, matched by index: sample i of the dataset is A and sample i of
+// samples is B. The two usually share inputs and differ in their outputs.
+func WithComparisonDataset(samples []EvalSample) RunnerOption {
+	return func(r *EvalRunner) {
+		r.comparison = samples
+	}
+}
+
+// RunPairwise compares each sample of the dataset with the sample at the
+// same index of the comparison dataset using the pairwise metrics, and
+// returns the win rates of each metric. It honours the Parallel, Timeout
+// and StopOnError settings; hooks are not called. The run is wrapped in an
+// eval.run span and each pair in an eval.row child span.
+func (r *EvalRunner) RunPairwise(ctx context.Context) (*PairwiseReport, error) {
+	if len(r.comparison) != len(r.dataset) {
+		return nil, core.NewError("eval.run_pairwise", core.ErrInvalidInput, "dataset and comparison dataset differ in length", nil)
+	}
+	if r.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Timeout)
+		defer cancel()
+	}
+
+	ctx, runSpan := startRunSpan(ctx, r.datasetName, len(r.dataset), len(r.pairwise))
+	defer runSpan.End()
+
+	start := time.Now()
+	results := make([]PairResult, len(r.dataset))
+
+	sem := make(chan struct{}, r.cfg.Parallel)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+
+	for i := range r.dataset {
+		// Acquire a slot before checking for a stop so that a failure in
+		// the pair just before prevents this one.
+		sem <- struct{}{}
+		mu.Lock()
+		done := stopped
+		mu.Unlock()
+		if done || ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			rowCtx, rowSpan := startRowSpan(ctx, r.datasetName, idx)
+			defer rowSpan.End()
+			result := r.comparePair(rowCtx, r.dataset[idx], r.comparison[idx])
+			if result.Error != nil {
+				rowSpan.RecordError(result.Error)
+				rowSpan.SetStatus(o11y.StatusError, result.Error.Error())
+			} else {
+				rowSpan.SetStatus(o11y.StatusOK, "")
+			}
+
+			mu.Lock()
+			results[idx] = result
+			if result.Error != nil && r.cfg.StopOnError {
+				stopped = true
+			}
+			mu.Unlock()
+		}(i)
+	}
+
+	wg.Wait()
+
+	report := r.buildPairwiseReport(results, time.Since(start))
+	if len(report.Errors) > 0 {
+		runSpan.SetStatus(o11y.StatusError, "one or more pairs failed")
+	} else {
+		runSpan.SetStatus(o11y.StatusOK, "")
+	}
+	return report, nil
+}
+
+// comparePair runs all pairwise metrics against a pair of samples. Each
+// successful comparison emits a gen_ai.evaluation.result event on the
+// enclosing eval.row span.
+func (r *EvalRunner) comparePair(ctx context.Context, a, b EvalSample) PairResult {
+	result := PairResult{
+		A:       a,
+		B:       b,
+		Winners: make(map[string]int, len(r.pairwise)),
+		Scores:  make(map[string]float64, len(r.pairwise)),
+	}
+
+	for _, m := range r.pairwise {
+		if ctx.Err() != nil {
+			result.Error = ctx.Err()
+			break
+		}
+		winner, score, err := m.Compare(ctx, a, b)
+		if err == nil && winner != WinnerA && winner != WinnerB && winner != Tie {
+			err = core.Errorf(core.ErrInvalidInput, "eval.run_pairwise: metric %s returned invalid winner %d", m.Name(), winner)
+		}
+		if err != nil {
+			result.Error = err
+			if r.cfg.StopOnError {
+				break
+			}
+			continue
+		}
+		result.Winners[m.Name()] = winner
+		result.Scores[m.Name()] = score
+		recordEvalResult(ctx, m.Name(), score)
+	}
+	return result
+}
+
+// buildPairwiseReport aggregates per-pair results into a PairwiseReport.
+// Pairs never compared because the run stopped early count towards no
+// win rate.
+func (r *EvalRunner) buildPairwiseReport(results []PairResult, duration time.Duration) *PairwiseReport {
+	report := &PairwiseReport{
+		Pairs:    results,
+		WinRates: make(map[string]WinRate),
+		Duration: duration,
+	}
+
+	counts := make(map[string]*[3]int)
+	for _, res := range results {
+		if res.Error != nil {
+			report.Errors = append(report.Errors, res.Error)
+		}
+		for name, winner := range res.Winners {
+			c := counts[name]
+			if c == nil {
+				c = new([3]int)
+				counts[name] = c
+			}
+			switch winner {
+			case WinnerA:
+				c[0]++
+			case WinnerB:
+				c[1]++
+			default:
+				c[2]++
+			}
+		}
+	}
+
+	for name, c := range counts {
+		n := c[0] + c[1] + c[2]
+		report.WinRates[name] = WinRate{
+			A:           float64(c[0]) / float64(n),
+			B:           float64(c[1]) / float64(n),
+			Tie:         float64(c[2]) / float64(n),
+			Comparisons: n,
+		}
+	}
+	return report
+}
@@ -0,0 +1,140 @@
+package eval_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthPreference prefers the longer output, calling equal lengths a tie.
+type lengthPreference struct {
+	err error
+}
+
+func (m *lengthPreference) Name() string { return "length" }
+
+func (m *lengthPreference) Compare(_ context.Context, a, b eval.EvalSample) (int, float64, error) {
+	if m.err != nil {
+		return 0, 0, m.err
+	}
+	switch {
+	case len(a.Output) > len(b.Output):
+		return eval.WinnerA, 1, nil
+	case len(a.Output) < len(b.Output):
+		return eval.WinnerB, 1, nil
+	}
+	return eval.Tie, 0, nil
+}
+
+// fixedPreference returns the same verdict for every pair.
+type fixedPreference struct {
+	winner int
+}
+
+func (m *fixedPreference) Name() string { return "fixed" }
+
+func (m *fixedPreference) Compare(context.Context, eval.EvalSample, eval.EvalSample) (int, float64, error) {
+	return m.winner, 0.5, nil
+}
+
+func pairwiseDatasets() (a, b []eval.EvalSample) {
+	a = []eval.EvalSample{
+		{Input: "q1", Output: "long answer"},
+		{Input: "q2", Output: "short"},
+		{Input: "q3", Output: "same"},
+		{Input: "q4", Output: "detailed answer"},
+	}
+	b = []eval.EvalSample{
+		{Input: "q1", Output: "short"},
+		{Input: "q2", Output: "long answer"},
+		{Input: "q3", Output: "same"},
+		{Input: "q4", Output: "brief"},
+	}
+	return a, b
+}
+
+func TestRunPairwise_WinRates(t *testing.T) {
+	a, b := pairwiseDatasets()
+	runner := eval.NewRunner(
+		eval.WithPairwiseMetrics(&lengthPreference{}, &fixedPreference{winner: eval.WinnerB}),
+		eval.WithDataset(a),
+		eval.WithComparisonDataset(b),
+		eval.WithParallel(2),
+	)
+
+	report, err := runner.RunPairwise(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	require.Len(t, report.Pairs, 4)
+
+	assert.Equal(t, eval.WinRate{A: 0.5, B: 0.25, Tie: 0.25, Comparisons: 4}, report.WinRates["length"])
+	assert.Equal(t, eval.WinRate{B: 1, Comparisons: 4}, report.WinRates["fixed"])
+
+	pair := report.Pairs[1]
+	assert.Equal(t, "q2", pair.A.Input)
+	assert.Equal(t, eval.WinnerB, pair.Winners["length"])
+	assert.InDelta(t, 1.0, pair.Scores["length"], 1e-9)
+}
+
+func TestRunPairwise_LengthMismatch(t *testing.T) {
+	a, b := pairwiseDatasets()
+	runner := eval.NewRunner(
+		eval.WithPairwiseMetrics(&lengthPreference{}),
+		eval.WithDataset(a),
+		eval.WithComparisonDataset(b[:2]),
+	)
+
+	_, err := runner.RunPairwise(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, &core.Error{Code: core.ErrInvalidInput}))
+}
+
+func TestRunPairwise_MetricError(t *testing.T) {
+	a, b := pairwiseDatasets()
+	runner := eval.NewRunner(
+		eval.WithPairwiseMetrics(&lengthPreference{err: errors.New("judge unavailable")}, &fixedPreference{winner: eval.WinnerA}),
+		eval.WithDataset(a),
+		eval.WithComparisonDataset(b),
+	)
+
+	report, err := runner.RunPairwise(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.Errors, 4)
+	assert.NotContains(t, report.WinRates, "length")
+	assert.Equal(t, eval.WinRate{A: 1, Comparisons: 4}, report.WinRates["fixed"])
+}
+
+func TestRunPairwise_InvalidWinner(t *testing.T) {
+	a, b := pairwiseDatasets()
+	runner := eval.NewRunner(
+		eval.WithPairwiseMetrics(&fixedPreference{winner: 7}),
+		eval.WithDataset(a[:1]),
+		eval.WithComparisonDataset(b[:1]),
+	)
+
+	report, err := runner.RunPairwise(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Errors, 1)
+	assert.True(t, strings.Contains(report.Errors[0].Error(), "invalid winner"))
+	assert.Empty(t, report.WinRates)
+}
+
+func TestRunPairwise_StopOnError(t *testing.T) {
+	a, b := pairwiseDatasets()
+	runner := eval.NewRunner(
+		eval.WithPairwiseMetrics(&lengthPreference{err: errors.New("judge unavailable")}),
+		eval.WithDataset(a),
+		eval.WithComparisonDataset(b),
+		eval.WithStopOnError(true),
+	)
+
+	report, err := runner.RunPairwise(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.Errors, 1)
+}
@@ -0,0 +1,164 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrRegression is the code of the error Run returns when a metric falls
+// below its baseline by more than its tolerance.
+const ErrRegression core.ErrorCode = "eval_regression"
+
+// AggregateMetric is the tolerance and gate key of the aggregate score, the
+// mean of the baseline's metrics.
+const AggregateMetric = "*"
+
+// Baseline holds the metric means of a reference run that later runs are
+// gated against.
+type Baseline struct {
+	// Metrics maps metric names to their mean score in the reference run.
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// SaveBaseline writes the metric means of report to a JSON file at the
+// given path, for use with LoadBaseline and WithRegressionGate.
+func SaveBaseline(path string, report *EvalReport) error {
+	b := Baseline{Metrics: report.Metrics}
+	if b.Metrics == nil {
+		b.Metrics = map[string]float64{}
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadBaseline reads a baseline from a JSON file written by SaveBaseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	path = filepath.Clean(path)
+	data, err := os.ReadFile(path) // #nosec G304 -- path cleaned above
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// MetricGate is the outcome of gating one metric against its baseline.
+type MetricGate struct {
+	// Baseline is the metric's mean in the baseline.
+	Baseline float64
+	// Current is the metric's mean in the report.
+	Current float64
+	// Delta is Current - Baseline.
+	Delta float64
+	// Tolerance is the largest drop below Baseline that passes.
+	Tolerance float64
+	// Missing reports whether the report has no scores for the metric.
+	Missing bool
+	// Regressed reports whether the metric is missing or dropped by more
+	// than Tolerance.
+	Regressed bool
+}
+
+// GateResult is the outcome of a regression gate.
+type GateResult struct {
+	// Passed reports whether no gated metric regressed.
+	Passed bool
+	// Metrics maps each metric of the baseline, and AggregateMetric when it
+	// has a tolerance, to its outcome.
+	Metrics map[string]MetricGate
+}
+
+// Regressions returns the names of the regressed metrics in alphabetical
+// order.
+func (g *GateResult) Regressions() []string {
+	var names []string
+	for name, m := range g.Metrics {
+		if m.Regressed {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CheckBaseline gates the metrics of report against baseline. Every metric
+// of the baseline is gated, with the tolerance listed for it in tolerance,
+// or none if it is not listed. The aggregate score is gated only when
+// tolerance lists AggregateMetric; it is the mean of the baseline's metrics
+// that the report has scores for.
+func CheckBaseline(report *EvalReport, baseline *Baseline, tolerance map[string]float64) *GateResult {
+	g := &GateResult{Passed: true, Metrics: make(map[string]MetricGate, len(baseline.Metrics)+1)}
+	var baseSum, curSum float64
+	var n int
+	for name, base := range baseline.Metrics {
+		cur, ok := report.Metrics[name]
+		m := newMetricGate(base, cur, tolerance[name])
+		if !ok {
+			m.Current, m.Delta, m.Missing, m.Regressed = 0, 0, true, true
+		} else {
+			baseSum += base
+			curSum += cur
+			n++
+		}
+		g.Metrics[name] = m
+		g.Passed = g.Passed && !m.Regressed
+	}
+
+	if tol, ok := tolerance[AggregateMetric]; ok && n > 0 {
+		m := newMetricGate(baseSum/float64(n), curSum/float64(n), tol)
+		g.Metrics[AggregateMetric] = m
+		g.Passed = g.Passed && !m.Regressed
+	}
+	return g
+}
+
+// gateEpsilon absorbs floating-point rounding, so that a drop of exactly
+// the tolerance passes.
+const gateEpsilon = 1e-9
+
+// newMetricGate gates a current mean against a baseline mean. Negative
+// tolerances count as zero.
+func newMetricGate(base, cur, tol float64) MetricGate {
+	tol = math.Max(tol, 0)
+	return MetricGate{
+		Baseline:  base,
+		Current:   cur,
+		Delta:     cur - base,
+		Tolerance: tol,
+		Regressed: base-cur > tol+gateEpsilon,
+	}
+}
+
+// err returns an ErrRegression error describing the regressed metrics, or
+// nil if the gate passed.
+func (g *GateResult) err() error {
+	if g.Passed {
+		return nil
+	}
+	var parts []string
+	for _, name := range g.Regressions() {
+		m := g.Metrics[name]
+		if m.Missing {
+			parts = append(parts, fmt.Sprintf("%s has no scores", name))
+			continue
+		}
+		if name == AggregateMetric {
+			name = "aggregate"
+		}
+		parts = append(parts, fmt.Sprintf("%s dropped from %.3f to %.3f (tolerance %.3f)", name, m.Baseline, m.Current, m.Tolerance))
+	}
+	return core.NewError("eval.run", ErrRegression, "regression against baseline: "+strings.Join(parts, "; "), nil)
+}
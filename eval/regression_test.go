@@ -0,0 +1,124 @@
+package eval_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	report := &eval.EvalReport{Metrics: map[string]float64{"quality": 0.8, "relevance": 0.65}}
+	require.NoError(t, eval.SaveBaseline(path, report))
+
+	baseline, err := eval.LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, report.Metrics, baseline.Metrics)
+
+	_, err = eval.LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestCheckBaseline(t *testing.T) {
+	baseline := &eval.Baseline{Metrics: map[string]float64{"quality": 0.8, "relevance": 0.6, "safety": 1}}
+	report := &eval.EvalReport{Metrics: map[string]float64{"quality": 0.75, "relevance": 0.7, "safety": 1}}
+
+	tests := []struct {
+		name        string
+		tolerance   map[string]float64
+		passed      bool
+		regressions []string
+	}{
+		{name: "no tolerance", passed: false, regressions: []string{"quality"}},
+		{name: "within tolerance", tolerance: map[string]float64{"quality": 0.05}, passed: true},
+		{name: "beyond tolerance", tolerance: map[string]float64{"quality": 0.01}, passed: false, regressions: []string{"quality"}},
+		{name: "negative tolerance", tolerance: map[string]float64{"quality": 0.05, "safety": -1}, passed: true},
+		{
+			name:      "aggregate improved",
+			tolerance: map[string]float64{"quality": 0.1, eval.AggregateMetric: 0},
+			passed:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := eval.CheckBaseline(report, baseline, tt.tolerance)
+			assert.Equal(t, tt.passed, gate.Passed)
+			assert.Equal(t, tt.regressions, gate.Regressions())
+		})
+	}
+
+	gate := eval.CheckBaseline(report, baseline, map[string]float64{"quality": 0.05})
+	q := gate.Metrics["quality"]
+	assert.InDelta(t, 0.8, q.Baseline, 1e-9)
+	assert.InDelta(t, 0.75, q.Current, 1e-9)
+	assert.InDelta(t, -0.05, q.Delta, 1e-9)
+	assert.False(t, q.Regressed)
+	assert.NotContains(t, gate.Metrics, eval.AggregateMetric)
+}
+
+func TestCheckBaseline_Aggregate(t *testing.T) {
+	baseline := &eval.Baseline{Metrics: map[string]float64{"quality": 0.8, "relevance": 0.6}}
+	report := &eval.EvalReport{Metrics: map[string]float64{"quality": 0.7, "relevance": 0.6}}
+
+	gate := eval.CheckBaseline(report, baseline, map[string]float64{
+		"quality":            0.2,
+		eval.AggregateMetric: 0.02,
+	})
+	assert.False(t, gate.Passed)
+	assert.Equal(t, []string{eval.AggregateMetric}, gate.Regressions())
+	agg := gate.Metrics[eval.AggregateMetric]
+	assert.InDelta(t, 0.7, agg.Baseline, 1e-9)
+	assert.InDelta(t, 0.65, agg.Current, 1e-9)
+}
+
+func TestCheckBaseline_MissingMetric(t *testing.T) {
+	baseline := &eval.Baseline{Metrics: map[string]float64{"quality": 0.8, "latency": 0.5}}
+	report := &eval.EvalReport{Metrics: map[string]float64{"quality": 0.9}}
+
+	gate := eval.CheckBaseline(report, baseline, map[string]float64{"latency": 1, eval.AggregateMetric: 0})
+	assert.False(t, gate.Passed)
+	assert.True(t, gate.Metrics["latency"].Missing)
+	assert.Equal(t, []string{"latency"}, gate.Regressions())
+	// The aggregate only covers metrics the report scored.
+	assert.InDelta(t, 0.9, gate.Metrics[eval.AggregateMetric].Current, 1e-9)
+}
+
+func TestRunner_RegressionGate(t *testing.T) {
+	baseline := &eval.Baseline{Metrics: map[string]float64{"quality": 0.8}}
+	m := &scoreByInput{name: "quality", scores: map[string]float64{"a": 0.6, "b": 0.8}}
+	samples := []eval.EvalSample{{Input: "a"}, {Input: "b"}}
+
+	report, err := eval.NewRunner(
+		eval.WithMetrics(m),
+		eval.WithDataset(samples),
+		eval.WithRegressionGate(baseline, map[string]float64{"quality": 0.05}),
+	).Run(context.Background())
+	require.Error(t, err)
+	var cerr *core.Error
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, eval.ErrRegression, cerr.Code)
+	assert.Contains(t, err.Error(), "quality dropped from 0.800 to 0.700")
+	require.NotNil(t, report)
+	require.NotNil(t, report.Gate)
+	assert.False(t, report.Gate.Passed)
+
+	report, err = eval.NewRunner(
+		eval.WithMetrics(m),
+		eval.WithDataset(samples),
+		eval.WithRegressionGate(baseline, map[string]float64{"quality": 0.1}),
+	).Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Gate.Passed)
+}
+
+func TestRunner_NoRegressionGate(t *testing.T) {
+	report, err := eval.NewRunner(eval.WithDataset([]eval.EvalSample{{Input: "a"}})).Run(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, report.Gate)
+}
@@ -91,13 +91,29 @@ func WithDatasetName(name string) RunnerOption {
 	}
 }
 
+// WithRegressionGate gates each run against baseline: Run records the
+// outcome in EvalReport.Gate and, if any metric dropped below its baseline
+// by more than its tolerance, returns the report together with an
+// ErrRegression error. See CheckBaseline for how tolerance applies.
+func WithRegressionGate(baseline *Baseline, tolerance map[string]float64) RunnerOption {
+	return func(r *EvalRunner) {
+		r.baseline = baseline
+		r.tolerance = tolerance
+	}
+}
+
 // EvalRunner runs a set of metrics against a dataset of samples.
 type EvalRunner struct {
 	metrics     []Metric
+	pairwise    []PairwiseMetric
 	dataset     []EvalSample
+	comparison  []EvalSample
 	datasetName string
 	cfg         Config
 	hooks       Hooks
+	statsOpts   []StatsOption
+	baseline    *Baseline
+	tolerance   map[string]float64
 }
 
 // NewRunner creates a new EvalRunner with the given options.
@@ -116,7 +132,8 @@ func NewRunner(opts ...RunnerOption) *EvalRunner {
 // Run executes all configured metrics against all samples and returns
 // an aggregate report. Samples are evaluated with the configured
 // concurrency level. The entire run is wrapped in an eval.run span; each
-// sample in an eval.row child span.
+// sample in an eval.row child span. With WithRegressionGate, Run returns
+// the report and an ErrRegression error when the gate fails.
 func (r *EvalRunner) Run(ctx context.Context) (*EvalReport, error) {
 	if r.cfg.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -175,9 +192,18 @@ func (r *EvalRunner) Run(ctx context.Context) (*EvalReport, error) {
 
 	report := r.buildReport(results, time.Since(start))
 
+	var gateErr error
+	if r.baseline != nil {
+		report.Gate = CheckBaseline(report, r.baseline, r.tolerance)
+		gateErr = report.Gate.err()
+	}
+
 	if firstErr != nil {
 		runSpan.RecordError(firstErr)
 		runSpan.SetStatus(o11y.StatusError, firstErr.Error())
+	} else if gateErr != nil {
+		runSpan.RecordError(gateErr)
+		runSpan.SetStatus(o11y.StatusError, gateErr.Error())
 	} else if len(report.Errors) > 0 {
 		runSpan.SetStatus(o11y.StatusError, "one or more samples failed")
 	} else {
@@ -188,7 +214,7 @@ func (r *EvalRunner) Run(ctx context.Context) (*EvalReport, error) {
 		r.hooks.AfterRun(ctx, report)
 	}
 
-	return report, nil
+	return report, gateErr
 }
 
 // processSample evaluates a single sample with hooks and records the result.
@@ -294,6 +320,7 @@ func (r *EvalRunner) buildReport(results []SampleResult, duration time.Duration)
 			report.Metrics[name] = sum / float64(counts[name])
 		}
 	}
+	report.Stats = computeStats(report, r.statsOpts)
 
 	return report
 }
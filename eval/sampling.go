@@ -0,0 +1,168 @@
+package eval
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2" //#nosec G404 -- non-crypto randomness for sampling; the seed is a reproducibility feature
+	"slices"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// sampleOptions holds the configuration for Sample and Split.
+type sampleOptions struct {
+	stratifyBy string
+	seed       uint64
+}
+
+// SampleOption configures Dataset.Sample and Dataset.Split.
+type SampleOption func(*sampleOptions)
+
+// WithStratifyBy stratifies by the metadata field key: each distinct value
+// of the field, such as each category, keeps its share of the dataset.
+// Samples without the field form a stratum of their own.
+func WithStratifyBy(key string) SampleOption {
+	return func(o *sampleOptions) {
+		o.stratifyBy = key
+	}
+}
+
+// WithSampleSeed sets the seed of the random selection. The default seed
+// is fixed, so the same dataset always yields the same subset.
+func WithSampleSeed(seed uint64) SampleOption {
+	return func(o *sampleOptions) {
+		o.seed = seed
+	}
+}
+
+// Sample returns a dataset of n samples drawn at random without
+// replacement, for quick evaluations of a large dataset, such as smoke
+// tests in CI. With WithStratifyBy, each stratum contributes in proportion
+// to its size, so rare strata may be left out of small samples. The samples
+// keep their order in d, and the result shares d's name. If n is at least
+// the size of d, all samples are returned.
+func (d *Dataset) Sample(n int, opts ...SampleOption) *Dataset {
+	picked := d.pick(n, opts)
+	out := &Dataset{Name: d.Name, Samples: make([]EvalSample, 0, min(max(n, 0), len(d.Samples)))}
+	for i, s := range d.Samples {
+		if picked[i] {
+			out.Samples = append(out.Samples, s)
+		}
+	}
+	return out
+}
+
+// Split divides the samples at random into a training set holding ratio of
+// them, rounded to the nearest sample, and a test set holding the rest,
+// named after d with "-train" and "-test" suffixes. With WithStratifyBy,
+// each stratum is split in the same proportion. The samples keep their
+// order in d. ratio must be in [0, 1].
+func (d *Dataset) Split(ratio float64, opts ...SampleOption) (train, test *Dataset, err error) {
+	if ratio < 0 || ratio > 1 || math.IsNaN(ratio) {
+		return nil, nil, core.Errorf(core.ErrInvalidInput, "eval.dataset.split: ratio %v is not in [0, 1]", ratio)
+	}
+	n := int(math.Round(ratio * float64(len(d.Samples))))
+	picked := d.pick(n, opts)
+
+	train = &Dataset{Name: d.Name + "-train", Samples: make([]EvalSample, 0, n)}
+	test = &Dataset{Name: d.Name + "-test", Samples: make([]EvalSample, 0, len(d.Samples)-n)}
+	for i, s := range d.Samples {
+		if picked[i] {
+			train.Samples = append(train.Samples, s)
+		} else {
+			test.Samples = append(test.Samples, s)
+		}
+	}
+	return train, test, nil
+}
+
+// pick selects n samples at random, stratified as configured, and reports
+// for each sample whether it was selected.
+func (d *Dataset) pick(n int, opts []SampleOption) []bool {
+	var o sampleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rng := rand.New(rand.NewPCG(o.seed, 0x5a4e)) //#nosec G404 -- see import
+
+	strata := d.strata(o.stratifyBy)
+	sizes := make([]int, len(strata))
+	for i, s := range strata {
+		sizes[i] = len(s)
+	}
+	counts := allocate(sizes, min(max(n, 0), len(d.Samples)))
+
+	picked := make([]bool, len(d.Samples))
+	for i, stratum := range strata {
+		for _, j := range rng.Perm(len(stratum))[:counts[i]] {
+			picked[stratum[j]] = true
+		}
+	}
+	return picked
+}
+
+// strata groups the indices of the samples by the value of the metadata
+// field key, in order of first appearance. Without a key, all samples form
+// one stratum.
+func (d *Dataset) strata(key string) [][]int {
+	if key == "" {
+		all := make([]int, len(d.Samples))
+		for i := range all {
+			all[i] = i
+		}
+		return [][]int{all}
+	}
+
+	var strata [][]int
+	index := make(map[string]int)
+	for i, s := range d.Samples {
+		value := "\x00missing"
+		if v, ok := s.Metadata[key]; ok {
+			value = fmt.Sprint(v)
+		}
+		k, ok := index[value]
+		if !ok {
+			k = len(strata)
+			index[value] = k
+			strata = append(strata, nil)
+		}
+		strata[k] = append(strata[k], i)
+	}
+	return strata
+}
+
+// allocate divides n among strata of the given sizes in proportion to
+// their size, by the largest remainder method. sum(sizes) must be at
+// least n.
+func allocate(sizes []int, n int) []int {
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+	counts := make([]int, len(sizes))
+	if total == 0 {
+		return counts
+	}
+
+	remainders := make([]float64, len(sizes))
+	assigned := 0
+	for i, size := range sizes {
+		exact := float64(n) * float64(size) / float64(total)
+		counts[i] = int(exact)
+		remainders[i] = exact - float64(counts[i])
+		assigned += counts[i]
+	}
+
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for _, i := range order[:n-assigned] {
+		counts[i]++
+	}
+	return counts
+}
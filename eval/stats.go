@@ -0,0 +1,322 @@
+package eval
+
+import (
+	"math"
+	"math/rand/v2" //#nosec G404 -- non-crypto randomness for bootstrap resampling; the fixed seed makes reports reproducible
+	"slices"
+)
+
+const (
+	defaultConfidenceLevel = 0.95
+	defaultResamples       = 1000
+)
+
+// MetricStats describes the distribution of one metric's scores across the
+// samples of a report.
+type MetricStats struct {
+	// Mean is the average score.
+	Mean float64
+	// StdDev is the sample standard deviation of the scores.
+	StdDev float64
+	// CILower and CIUpper bound the bootstrap confidence interval of the
+	// mean at the configured confidence level.
+	CILower, CIUpper float64
+	// N is the number of scores.
+	N int
+}
+
+// MetricComparison describes the difference in one metric between two
+// reports.
+type MetricComparison struct {
+	// MeanA and MeanB are the metric's means in the two reports.
+	MeanA, MeanB float64
+	// Diff is MeanB - MeanA.
+	Diff float64
+	// CILower and CIUpper bound the bootstrap confidence interval of Diff.
+	CILower, CIUpper float64
+	// PValue is the two-sided bootstrap p-value of the hypothesis that the
+	// means do not differ.
+	PValue float64
+	// Significant reports whether PValue is below 1 - the confidence level.
+	Significant bool
+	// Paired reports whether the samples of the two reports were matched
+	// one to one, which makes the test more sensitive.
+	Paired bool
+}
+
+// ReportComparison is the result of CompareReports.
+type ReportComparison struct {
+	// Metrics maps the names of the metrics present in both reports to
+	// their comparison.
+	Metrics map[string]MetricComparison
+	// ConfidenceLevel is the confidence level of the intervals and tests.
+	ConfidenceLevel float64
+}
+
+// statsOptions holds the configuration for statistics.
+type statsOptions struct {
+	confidence float64
+	resamples  int
+	seed       uint64
+}
+
+// StatsOption configures the statistics of a report or comparison.
+type StatsOption func(*statsOptions)
+
+// WithConfidenceLevel sets the confidence level of intervals and tests, in
+// (0, 1). Defaults to 0.95.
+func WithConfidenceLevel(level float64) StatsOption {
+	return func(o *statsOptions) {
+		if level > 0 && level < 1 {
+			o.confidence = level
+		}
+	}
+}
+
+// WithResamples sets the number of bootstrap resamples. Defaults to 1000.
+func WithResamples(n int) StatsOption {
+	return func(o *statsOptions) {
+		if n > 0 {
+			o.resamples = n
+		}
+	}
+}
+
+// WithSeed sets the seed of the bootstrap's random source. The default
+// seed is fixed, so the same scores always yield the same statistics.
+func WithSeed(seed uint64) StatsOption {
+	return func(o *statsOptions) {
+		o.seed = seed
+	}
+}
+
+// WithStats configures the statistics the runner adds to its reports.
+func WithStats(opts ...StatsOption) RunnerOption {
+	return func(r *EvalRunner) {
+		r.statsOpts = opts
+	}
+}
+
+func newStatsOptions(opts []StatsOption) statsOptions {
+	o := statsOptions{confidence: defaultConfidenceLevel, resamples: defaultResamples}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o statsOptions) rand() *rand.Rand {
+	return rand.New(rand.NewPCG(o.seed, 0x5eed)) //#nosec G404 -- see import
+}
+
+// metricScores returns the scores of each metric across the report's
+// samples, in sample order.
+func (r *EvalReport) metricScores() map[string][]float64 {
+	scores := make(map[string][]float64)
+	for _, res := range r.Samples {
+		for name, score := range res.Scores {
+			scores[name] = append(scores[name], score)
+		}
+	}
+	return scores
+}
+
+// computeStats returns the statistics of each metric in report.
+func computeStats(report *EvalReport, opts []StatsOption) map[string]MetricStats {
+	o := newStatsOptions(opts)
+	stats := make(map[string]MetricStats)
+	for name, scores := range report.metricScores() {
+		m := mean(scores)
+		lo, hi := o.meanCI(scores)
+		stats[name] = MetricStats{
+			Mean:    m,
+			StdDev:  stdDev(scores, m),
+			CILower: lo,
+			CIUpper: hi,
+			N:       len(scores),
+		}
+	}
+	return stats
+}
+
+// CompareReports reports, for each metric present in both reports, whether
+// its mean differs significantly between report a and report b, using a
+// bootstrap test. If the reports evaluated the same inputs in the same
+// order, for example two prompt variants run on one dataset, and every
+// sample has a score for the metric, the samples are paired and the test
+// uses the per-sample differences; otherwise the two sets of scores are
+// treated as independent. Metrics with fewer than two scores in either
+// report are never significant.
+func CompareReports(a, b *EvalReport, opts ...StatsOption) *ReportComparison {
+	o := newStatsOptions(opts)
+	cmp := &ReportComparison{
+		Metrics:         make(map[string]MetricComparison),
+		ConfidenceLevel: o.confidence,
+	}
+	scoresA, scoresB := a.metricScores(), b.metricScores()
+	for name := range scoresA {
+		if _, ok := scoresB[name]; !ok {
+			continue
+		}
+		if diffs, ok := pairedDiffs(a, b, name); ok {
+			cmp.Metrics[name] = o.compare(scoresA[name], scoresB[name], true, func(rng *rand.Rand) float64 {
+				return resampledMean(rng, diffs)
+			})
+			continue
+		}
+		xs, ys := scoresA[name], scoresB[name]
+		cmp.Metrics[name] = o.compare(xs, ys, false, func(rng *rand.Rand) float64 {
+			return resampledMean(rng, ys) - resampledMean(rng, xs)
+		})
+	}
+	return cmp
+}
+
+// compare builds the comparison of the scores xs and ys from the bootstrap
+// distribution of their difference produced by sample.
+func (o statsOptions) compare(xs, ys []float64, paired bool, sample func(*rand.Rand) float64) MetricComparison {
+	meanA, meanB := mean(xs), mean(ys)
+	if len(xs) < 2 || len(ys) < 2 {
+		// Too few scores to tell a difference from noise.
+		return MetricComparison{
+			MeanA:   meanA,
+			MeanB:   meanB,
+			Diff:    meanB - meanA,
+			CILower: meanB - meanA,
+			CIUpper: meanB - meanA,
+			PValue:  1,
+			Paired:  paired,
+		}
+	}
+
+	dist := o.bootstrap(sample)
+	lo, hi := o.interval(dist)
+
+	// Two-sided p-value: twice the share of resampled differences on the
+	// far side of zero, with the +1 correction so it is never zero.
+	var below, above int
+	for _, d := range dist {
+		if d <= 0 {
+			below++
+		}
+		if d >= 0 {
+			above++
+		}
+	}
+	p := 2 * float64(min(below, above)+1) / float64(len(dist)+1)
+
+	return MetricComparison{
+		MeanA:       meanA,
+		MeanB:       meanB,
+		Diff:        meanB - meanA,
+		CILower:     lo,
+		CIUpper:     hi,
+		PValue:      min(p, 1),
+		Significant: p < 1-o.confidence,
+		Paired:      paired,
+	}
+}
+
+// pairedDiffs returns the per-sample differences b - a of the named metric
+// if the reports' samples pair up: the same number of samples with the
+// same inputs, all scored on the metric.
+func pairedDiffs(a, b *EvalReport, name string) ([]float64, bool) {
+	if len(a.Samples) != len(b.Samples) || len(a.Samples) == 0 {
+		return nil, false
+	}
+	diffs := make([]float64, len(a.Samples))
+	for i := range a.Samples {
+		ra, rb := a.Samples[i], b.Samples[i]
+		if ra.Sample.Input != rb.Sample.Input {
+			return nil, false
+		}
+		sa, okA := ra.Scores[name]
+		sb, okB := rb.Scores[name]
+		if !okA || !okB {
+			return nil, false
+		}
+		diffs[i] = sb - sa
+	}
+	return diffs, true
+}
+
+// meanCI returns the bootstrap confidence interval of the mean of scores.
+func (o statsOptions) meanCI(scores []float64) (lo, hi float64) {
+	if len(scores) < 2 {
+		m := mean(scores)
+		return m, m
+	}
+	return o.interval(o.bootstrap(func(rng *rand.Rand) float64 {
+		return resampledMean(rng, scores)
+	}))
+}
+
+// bootstrap returns the sorted values of sample over the configured number
+// of resamples.
+func (o statsOptions) bootstrap(sample func(*rand.Rand) float64) []float64 {
+	rng := o.rand()
+	dist := make([]float64, o.resamples)
+	for i := range dist {
+		dist[i] = sample(rng)
+	}
+	slices.Sort(dist)
+	return dist
+}
+
+// interval returns the percentile interval of the sorted distribution dist
+// at the configured confidence level.
+func (o statsOptions) interval(dist []float64) (lo, hi float64) {
+	alpha := (1 - o.confidence) / 2
+	return percentile(dist, alpha), percentile(dist, 1-alpha)
+}
+
+// percentile returns the q-quantile of the sorted values, interpolating
+// linearly between neighbours.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
+
+// resampledMean returns the mean of len(xs) values drawn from xs with
+// replacement.
+func resampledMean(rng *rand.Rand, xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for range xs {
+		sum += xs[rng.IntN(len(xs))]
+	}
+	return sum / float64(len(xs))
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// stdDev returns the sample standard deviation of xs around m.
+func stdDev(xs []float64, m float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - m) * (x - m)
+	}
+	return math.Sqrt(ss / float64(len(xs)-1))
+}
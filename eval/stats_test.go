@@ -0,0 +1,130 @@
+package eval_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scoreByInput scores each sample with the score listed for its input.
+type scoreByInput struct {
+	name   string
+	scores map[string]float64
+}
+
+func (m *scoreByInput) Name() string { return m.name }
+
+func (m *scoreByInput) Score(_ context.Context, sample eval.EvalSample) (float64, error) {
+	return m.scores[sample.Input], nil
+}
+
+// reportWith runs a runner over one sample per score, with inputs q0, q1...
+func reportWith(t *testing.T, scores []float64, opts ...eval.StatsOption) *eval.EvalReport {
+	t.Helper()
+	m := &scoreByInput{name: "quality", scores: make(map[string]float64)}
+	samples := make([]eval.EvalSample, len(scores))
+	for i, score := range scores {
+		input := fmt.Sprintf("q%d", i)
+		m.scores[input] = score
+		samples[i] = eval.EvalSample{Input: input}
+	}
+	report, err := eval.NewRunner(
+		eval.WithMetrics(m),
+		eval.WithDataset(samples),
+		eval.WithStats(opts...),
+	).Run(context.Background())
+	require.NoError(t, err)
+	return report
+}
+
+func TestEvalReport_Stats(t *testing.T) {
+	report := reportWith(t, []float64{0.2, 0.4, 0.6, 0.8, 1.0})
+
+	stats, ok := report.Stats["quality"]
+	require.True(t, ok)
+	assert.Equal(t, 5, stats.N)
+	assert.InDelta(t, 0.6, stats.Mean, 1e-9)
+	assert.InDelta(t, math.Sqrt(0.1), stats.StdDev, 1e-9)
+	assert.Less(t, stats.CILower, stats.Mean)
+	assert.Greater(t, stats.CIUpper, stats.Mean)
+	assert.GreaterOrEqual(t, stats.CILower, 0.2)
+	assert.LessOrEqual(t, stats.CIUpper, 1.0)
+
+	// The default seed is fixed, so the interval is reproducible.
+	again := reportWith(t, []float64{0.2, 0.4, 0.6, 0.8, 1.0})
+	assert.Equal(t, stats, again.Stats["quality"])
+}
+
+func TestEvalReport_Stats_ConfidenceLevel(t *testing.T) {
+	scores := []float64{0.1, 0.9, 0.3, 0.7, 0.5, 0.4, 0.6, 0.2, 0.8, 0.5}
+	narrow := reportWith(t, scores, eval.WithConfidenceLevel(0.5)).Stats["quality"]
+	wide := reportWith(t, scores, eval.WithConfidenceLevel(0.99), eval.WithResamples(2000)).Stats["quality"]
+
+	assert.Less(t, wide.CILower, narrow.CILower)
+	assert.Greater(t, wide.CIUpper, narrow.CIUpper)
+}
+
+func TestEvalReport_Stats_SingleSample(t *testing.T) {
+	stats := reportWith(t, []float64{0.7}).Stats["quality"]
+	assert.Equal(t, eval.MetricStats{Mean: 0.7, CILower: 0.7, CIUpper: 0.7, N: 1}, stats)
+}
+
+func TestCompareReports_PairedImprovement(t *testing.T) {
+	a := reportWith(t, []float64{0.5, 0.6, 0.4, 0.7, 0.5, 0.6, 0.3, 0.5})
+	b := reportWith(t, []float64{0.7, 0.7, 0.6, 0.8, 0.7, 0.8, 0.5, 0.6})
+
+	cmp := eval.CompareReports(a, b)
+	assert.InDelta(t, 0.95, cmp.ConfidenceLevel, 1e-9)
+	c, ok := cmp.Metrics["quality"]
+	require.True(t, ok)
+	assert.True(t, c.Paired)
+	assert.True(t, c.Significant)
+	assert.InDelta(t, 0.1625, c.Diff, 1e-9)
+	assert.InDelta(t, c.MeanB-c.MeanA, c.Diff, 1e-9)
+	assert.Less(t, c.PValue, 0.05)
+	assert.Greater(t, c.CILower, 0.0)
+	assert.GreaterOrEqual(t, c.CIUpper, c.Diff)
+}
+
+func TestCompareReports_Noise(t *testing.T) {
+	a := reportWith(t, []float64{0.5, 0.9, 0.1, 0.7, 0.3, 0.6})
+	b := reportWith(t, []float64{0.6, 0.2, 0.8, 0.4, 0.9, 0.2})
+
+	c := eval.CompareReports(a, b).Metrics["quality"]
+	assert.False(t, c.Significant)
+	assert.Greater(t, c.PValue, 0.05)
+	assert.Less(t, c.CILower, 0.0)
+	assert.Greater(t, c.CIUpper, 0.0)
+}
+
+func TestCompareReports_Unpaired(t *testing.T) {
+	a := reportWith(t, []float64{0.1, 0.2, 0.15, 0.2, 0.1, 0.25})
+	b := reportWith(t, []float64{0.8, 0.9, 0.85, 0.95})
+
+	c := eval.CompareReports(a, b).Metrics["quality"]
+	assert.False(t, c.Paired)
+	assert.True(t, c.Significant)
+	assert.Greater(t, c.Diff, 0.6)
+}
+
+func TestCompareReports_TooFewScores(t *testing.T) {
+	a := reportWith(t, []float64{0.1})
+	b := reportWith(t, []float64{0.9})
+
+	c := eval.CompareReports(a, b).Metrics["quality"]
+	assert.False(t, c.Significant)
+	assert.InDelta(t, 1.0, c.PValue, 1e-9)
+	assert.InDelta(t, 0.8, c.Diff, 1e-9)
+}
+
+func TestCompareReports_DisjointMetrics(t *testing.T) {
+	a := reportWith(t, []float64{0.1, 0.2})
+	b := &eval.EvalReport{Samples: []eval.SampleResult{{Scores: map[string]float64{"other": 1}}}}
+
+	assert.Empty(t, eval.CompareReports(a, b).Metrics)
+}
@@ -51,6 +51,11 @@ func (f *ContentFilter) Name() string {
 	return "content_filter"
 }
 
+// MutatesContent returns false: ContentFilter only inspects content.
+func (f *ContentFilter) MutatesContent() bool {
+	return false
+}
+
 // Validate checks the input content for blocked keywords. If the number of
 // distinct keyword matches meets or exceeds the threshold, the content is
 // blocked and the matching keywords are listed in the reason.
@@ -70,9 +75,12 @@ func (f *ContentFilter) Validate(_ context.Context, input GuardInput) (GuardResu
 
 	if len(matched) >= f.threshold {
 		return GuardResult{
-			Allowed:   false,
-			Reason:    "content blocked: matched keywords [" + strings.Join(matched, ", ") + "]",
-			GuardName: f.Name(),
+			Allowed:     false,
+			Reason:      "content blocked: matched keywords [" + strings.Join(matched, ", ") + "]",
+			GuardName:   f.Name(),
+			Severity:    SeverityBlock,
+			Code:        CodeContentFiltered,
+			Remediation: "Remove or rephrase the flagged terms: " + strings.Join(matched, ", ") + ".",
 		}, nil
 	}
 
@@ -14,16 +14,34 @@
 //
 // # Built-in Guards
 //
-// The package ships with four built-in guard implementations:
+// The package ships with these built-in guard implementations:
 //
 //   - PromptInjectionDetector detects common prompt injection patterns using
 //     configurable regular expressions.
 //   - PIIRedactor detects and redacts personally identifiable information
 //     (email, phone, SSN, credit card, IP address) using regex-based patterns.
+//     NewPIIRedactorWithOptions enables extended entity types (IBAN,
+//     passport, international phone formats) and custom patterns, and the
+//     result reports per-entity redaction counts.
 //   - ContentFilter performs keyword-based content moderation with a
 //     configurable match threshold.
 //   - Spotlighting wraps untrusted content in delimiters to isolate it
 //     from trusted instructions, reducing prompt injection effectiveness.
+//     Datamarking and base64 encoding modes harden the boundary further,
+//     and an optional canary token lets CanaryGuard flag output that
+//     echoes the untrusted block.
+//   - LLMGuard classifies content with a judge LLM to catch paraphrased
+//     jailbreaks, with a score threshold, verdict caching, and a
+//     configurable fail-open or fail-closed fallback.
+//   - RateGuard tracks per-subject requests and block verdicts in a
+//     state.Store and times out abusive subjects with escalating timeouts.
+//     Pass the subject to the pipeline with WithSubject.
+//   - SecretLeakDetector redacts credentials echoed in model output: known
+//     key formats (AWS, GitHub, JWT, PEM private keys) and high-entropy
+//     tokens, with an allowlist for known-safe values.
+//   - SchemaGuard validates tool-call arguments against the tool's
+//     InputSchema from a tool.Registry and applies per-tool policies such
+//     as DenyKeywords and PathAllowlist.
 //
 // # Pipeline
 //
@@ -32,6 +50,24 @@
 // the first guard that blocks stops the pipeline for that stage. Modified
 // content from one guard is passed to subsequent guards.
 //
+// WithParallelStage runs read-only guards (those whose MutatesContent
+// returns false) concurrently within a stage and collects every block
+// instead of stopping at the first. Mutating guards still run sequentially
+// in declared order, and each read-only guard sees the content produced by
+// the mutating guards declared before it. Wrap third-party guards that never
+// modify content with ReadOnly to make them eligible for concurrency.
+//
+// # Structured Results
+//
+// Besides Allowed and Reason, a GuardResult carries a Severity (info, warn,
+// block), a machine-readable Code such as CodePromptInjection, and
+// user-facing Remediation text; the built-in guards and the guards in
+// guard/providers populate all three. Pipeline stage results aggregate every
+// notable per-guard result into Findings and report the highest severity; a
+// blocked stage also copies the Code and Remediation of its first block,
+// while an allowed one leaves them on the findings, so front ends can explain
+// exactly why content was blocked or changed.
+//
 // # Registry
 //
 // The package follows the standard Beluga registry pattern with Register,
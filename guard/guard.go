@@ -14,6 +14,15 @@ type Guard interface {
 	Validate(ctx context.Context, input GuardInput) (GuardResult, error)
 }
 
+// ContentMutator is an optional interface for guards that declare whether
+// they may rewrite content via GuardResult.Modified. A Pipeline with a
+// parallel stage runs guards reporting false concurrently. Guards that do
+// not implement ContentMutator are treated as mutating.
+type ContentMutator interface {
+	// MutatesContent reports whether Validate may return modified content.
+	MutatesContent() bool
+}
+
 // GuardInput carries the content to be validated along with metadata about
 // the validation stage and arbitrary key-value pairs for guard-specific
 // configuration.
@@ -29,6 +38,48 @@ type GuardInput struct {
 	Metadata map[string]any
 }
 
+// Severity grades a guard finding so front ends can decide how to surface
+// it.
+type Severity string
+
+const (
+	// SeverityInfo marks a benign transformation, such as spotlighting.
+	SeverityInfo Severity = "info"
+	// SeverityWarn marks content that was allowed after remediation, such
+	// as redaction.
+	SeverityWarn Severity = "warn"
+	// SeverityBlock marks content that was rejected.
+	SeverityBlock Severity = "block"
+)
+
+// rank orders severities from least to most severe. Unknown or empty
+// severities rank lowest.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarn:
+		return 2
+	case SeverityBlock:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Machine-readable result codes populated by the built-in guards.
+const (
+	CodePromptInjection  = "prompt_injection"
+	CodeJailbreak        = "jailbreak"
+	CodeGuardUnavailable = "guard_unavailable"
+	CodeContentFiltered  = "content_filtered"
+	CodePIIRedacted      = "pii_redacted"
+	CodeSecretRedacted   = "secret_redacted"
+	CodeSpotlighted      = "spotlighted"
+	CodeRateLimited      = "rate_limited"
+	CodeAbuseDetected    = "abuse_detected"
+)
+
 // GuardResult conveys the outcome of a Guard.Validate call.
 type GuardResult struct {
 	// Allowed is true when the content passes validation.
@@ -44,6 +95,31 @@ type GuardResult struct {
 
 	// GuardName identifies which guard produced this result.
 	GuardName string
+
+	// Metadata carries guard-specific details about the decision, such as
+	// the PII entity counts reported by PIIRedactor under "pii_entities".
+	Metadata map[string]any
+
+	// Score is an optional confidence in [0, 1] that the content is unsafe.
+	// Guards that do not produce a score leave it at zero.
+	Score float64
+
+	// Severity grades the result. Pipelines default it to SeverityBlock for
+	// blocked results and SeverityInfo for other findings.
+	Severity Severity
+
+	// Code is a stable, machine-readable identifier for the finding, such
+	// as CodePromptInjection.
+	Code string
+
+	// Remediation is a user-facing suggestion for how to resolve the
+	// finding, e.g. "Rephrase the request without ...".
+	Remediation string
+
+	// Findings is populated by Pipeline with every notable per-guard result
+	// of the stage (blocks, modifications, and warnings) in declared order.
+	// The top-level Severity is the highest severity among the findings.
+	Findings []GuardResult
 }
 
 // GuardFactory creates a Guard from an arbitrary configuration map. Factories
@@ -71,6 +71,11 @@ func (d *PromptInjectionDetector) Name() string {
 	return "prompt_injection_detector"
 }
 
+// MutatesContent returns false: PromptInjectionDetector only inspects content.
+func (d *PromptInjectionDetector) MutatesContent() bool {
+	return false
+}
+
 // Validate checks the input content against all configured injection patterns.
 // If any pattern matches, the content is blocked with a reason identifying the
 // matched pattern.
@@ -82,6 +87,10 @@ func (d *PromptInjectionDetector) Validate(_ context.Context, input GuardInput)
 				Allowed:   false,
 				Reason:    "prompt injection detected: " + p.name,
 				GuardName: d.Name(),
+				Severity:  SeverityBlock,
+				Code:      CodePromptInjection,
+				Remediation: "Rephrase the request without instructions that try to " +
+					"override, reveal, or bypass the assistant's rules.",
 			}, nil
 		}
 	}
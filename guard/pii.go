@@ -3,8 +3,16 @@ package guard
 import (
 	"context"
 	"regexp"
+	"slices"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
 )
 
+// MetadataPIIEntities is the GuardResult.Metadata key under which
+// PIIRedactor reports a map[string]int of redacted entity names to match
+// counts.
+const MetadataPIIEntities = "pii_entities"
+
 // PIIPattern defines a named PII detection pattern with its replacement
 // placeholder. For example, an email pattern would use the placeholder
 // "[EMAIL]".
@@ -15,7 +23,8 @@ type PIIPattern struct {
 	// Pattern is the compiled regexp that matches the PII in text.
 	Pattern *regexp.Regexp
 
-	// Placeholder is the replacement string, e.g. "[EMAIL]".
+	// Placeholder is the replacement string, e.g. "[EMAIL]". It may reference
+	// capture groups using regexp.Expand syntax such as "${1}".
 	Placeholder string
 }
 
@@ -50,6 +59,37 @@ var DefaultPIIPatterns = []PIIPattern{
 	},
 }
 
+// ExtendedPIIPatterns contains built-in patterns that are not enabled by
+// default because they are locale-specific or more prone to false positives.
+// Enable them by name with WithEntities.
+var ExtendedPIIPatterns = []PIIPattern{
+	{
+		Name:        "iban",
+		Pattern:     regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+		Placeholder: "[IBAN]",
+	},
+	{
+		Name:        "passport",
+		Pattern:     regexp.MustCompile(`(?i)(passport(?:\s+(?:no\.?|number|#))?\s*[:#]?\s*)[A-Z0-9]{6,9}\b`),
+		Placeholder: "${1}[PASSPORT]",
+	},
+	{
+		Name:        "phone_intl",
+		Pattern:     regexp.MustCompile(`\+[1-9][0-9]{0,2}[-.\s]?\(?[0-9]{1,4}\)?(?:[-.\s]?[0-9]{2,4}){2,4}\b`),
+		Placeholder: "[PHONE]",
+	},
+	{
+		Name:        "phone_uk",
+		Pattern:     regexp.MustCompile(`(?:\+44\s?|\b0)7[0-9]{3}\s?[0-9]{3}\s?[0-9]{3}\b`),
+		Placeholder: "[PHONE]",
+	},
+	{
+		Name:        "phone_fr",
+		Pattern:     regexp.MustCompile(`(?:\+33\s?|\b0)[1-9](?:[-.\s]?[0-9]{2}){4}\b`),
+		Placeholder: "[PHONE]",
+	},
+}
+
 // PIIRedactor is a Guard that detects and redacts personally identifiable
 // information from content. It replaces matched patterns with configurable
 // placeholders and returns the sanitized content as a modified result.
@@ -69,36 +109,141 @@ func NewPIIRedactor(patterns ...PIIPattern) *PIIRedactor {
 	}
 }
 
+// PIIOption configures a PIIRedactor built with NewPIIRedactorWithOptions.
+type PIIOption func(*piiConfig)
+
+type piiConfig struct {
+	entities []string
+	disabled []string
+	custom   []PIIPattern
+}
+
+// WithEntities restricts the built-in patterns to the named entity types.
+// Names may come from DefaultPIIPatterns or ExtendedPIIPatterns, e.g.
+// WithEntities("email", "iban", "phone_uk"). Without this option the
+// DefaultPIIPatterns set is used.
+func WithEntities(names ...string) PIIOption {
+	return func(c *piiConfig) {
+		c.entities = append(c.entities, names...)
+	}
+}
+
+// WithoutEntities disables the named built-in entity types.
+func WithoutEntities(names ...string) PIIOption {
+	return func(c *piiConfig) {
+		c.disabled = append(c.disabled, names...)
+	}
+}
+
+// WithCustomPattern registers an additional named entity pattern. Matches
+// are replaced with replacement, which may reference capture groups.
+// Custom patterns run after the built-in ones, in registration order.
+func WithCustomPattern(name string, re *regexp.Regexp, replacement string) PIIOption {
+	return func(c *piiConfig) {
+		c.custom = append(c.custom, PIIPattern{Name: name, Pattern: re, Placeholder: replacement})
+	}
+}
+
+// NewPIIRedactorWithOptions creates a PIIRedactor from entity options. It
+// starts from DefaultPIIPatterns, or from the entity types selected with
+// WithEntities, removes any WithoutEntities names, and appends custom
+// patterns. It returns an error if an entity name is unknown or a custom
+// pattern is invalid.
+//
+// Usage:
+//
+//	redactor, err := guard.NewPIIRedactorWithOptions(
+//	    guard.WithEntities("email", "iban", "passport", "phone_uk"),
+//	    guard.WithCustomPattern("employee_id", regexp.MustCompile(`EMP-[0-9]{6}`), "[EMPLOYEE_ID]"),
+//	)
+func NewPIIRedactorWithOptions(opts ...PIIOption) (*PIIRedactor, error) {
+	cfg := &piiConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	builtin := slices.Concat(DefaultPIIPatterns, ExtendedPIIPatterns)
+	known := func(name string) bool {
+		return slices.ContainsFunc(builtin, func(p PIIPattern) bool { return p.Name == name })
+	}
+	for _, name := range slices.Concat(cfg.entities, cfg.disabled) {
+		if !known(name) {
+			return nil, core.Errorf(core.ErrInvalidInput, "guard: unknown PII entity type %q", name)
+		}
+	}
+
+	selected := DefaultPIIPatterns
+	if len(cfg.entities) > 0 {
+		selected = nil
+		for _, p := range builtin {
+			if slices.Contains(cfg.entities, p.Name) {
+				selected = append(selected, p)
+			}
+		}
+	}
+
+	patterns := make([]PIIPattern, 0, len(selected)+len(cfg.custom))
+	for _, p := range selected {
+		if !slices.Contains(cfg.disabled, p.Name) {
+			patterns = append(patterns, p)
+		}
+	}
+	for _, p := range cfg.custom {
+		if p.Name == "" || p.Pattern == nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "guard: custom PII pattern requires a name and regexp")
+		}
+		patterns = append(patterns, p)
+	}
+	return NewPIIRedactor(patterns...), nil
+}
+
 // Name returns "pii_redactor".
 func (r *PIIRedactor) Name() string {
 	return "pii_redactor"
 }
 
+// MutatesContent returns true because PIIRedactor rewrites content.
+func (r *PIIRedactor) MutatesContent() bool {
+	return true
+}
+
 // Validate scans the input content for PII and returns a result with the
 // sanitized content in Modified. The result is always Allowed because
-// redaction makes the content safe to pass through.
+// redaction makes the content safe to pass through. When anything was
+// redacted, Metadata[MetadataPIIEntities] holds the per-entity match counts.
 func (r *PIIRedactor) Validate(_ context.Context, input GuardInput) (GuardResult, error) {
 	modified := input.Content
-	redacted := false
+	counts := make(map[string]int)
 
 	for _, p := range r.patterns {
-		if p.Pattern.MatchString(modified) {
+		if n := len(p.Pattern.FindAllStringIndex(modified, -1)); n > 0 {
 			modified = p.Pattern.ReplaceAllString(modified, p.Placeholder)
-			redacted = true
+			counts[p.Name] += n
 		}
 	}
 
 	result := GuardResult{Allowed: true}
-	if redacted {
+	if len(counts) > 0 {
 		result.Modified = modified
 		result.Reason = "PII redacted"
 		result.GuardName = r.Name()
+		result.Metadata = map[string]any{MetadataPIIEntities: counts}
+		result.Severity = SeverityWarn
+		result.Code = CodePIIRedacted
+		result.Remediation = "Avoid sharing personal information; it was redacted before processing."
 	}
 	return result, nil
 }
 
 func init() {
 	Register("pii_redactor", func(cfg map[string]any) (Guard, error) {
-		return NewPIIRedactor(DefaultPIIPatterns...), nil
+		var opts []PIIOption
+		if names, ok := cfg["entities"].([]string); ok {
+			opts = append(opts, WithEntities(names...))
+		}
+		if names, ok := cfg["disabled_entities"].([]string); ok {
+			opts = append(opts, WithoutEntities(names...))
+		}
+		return NewPIIRedactorWithOptions(opts...)
 	})
 }
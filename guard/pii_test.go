@@ -2,6 +2,7 @@ package guard
 
 import (
 	"context"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -263,3 +264,104 @@ func TestPIIRedactor_AlwaysAllows(t *testing.T) {
 		t.Error("PIIRedactor should always allow (redact, not block)")
 	}
 }
+
+func TestPIIRedactor_ReportsEntityCounts(t *testing.T) {
+	r := NewPIIRedactor(DefaultPIIPatterns...)
+	result, err := r.Validate(context.Background(), GuardInput{
+		Content: "mail a@b.com or c@d.org, ssn 123-45-6789",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, ok := result.Metadata[MetadataPIIEntities].(map[string]int)
+	if !ok {
+		t.Fatalf("Metadata[%q] = %T, want map[string]int", MetadataPIIEntities, result.Metadata[MetadataPIIEntities])
+	}
+	if counts["email"] != 2 || counts["ssn"] != 1 || len(counts) != 2 {
+		t.Errorf("counts = %v, want email:2 ssn:1", counts)
+	}
+}
+
+func TestNewPIIRedactorWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []PIIOption
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "defaults unchanged",
+			input: "a@b.com GB82 WEST 1234 5698 7654 32",
+			want:  "[EMAIL] GB82 WEST 1234 5698 7654 32",
+		},
+		{
+			name:  "enable iban",
+			opts:  []PIIOption{WithEntities("iban")},
+			input: "a@b.com GB82 WEST 1234 5698 7654 32",
+			want:  "a@b.com [IBAN]",
+		},
+		{
+			name:  "passport keeps label",
+			opts:  []PIIOption{WithEntities("passport")},
+			input: "Passport No: X1234567 issued",
+			want:  "Passport No: [PASSPORT] issued",
+		},
+		{
+			name:  "uk phone",
+			opts:  []PIIOption{WithEntities("phone_uk")},
+			input: "call +44 7911 123 456",
+			want:  "call [PHONE]",
+		},
+		{
+			name:  "disable default entity",
+			opts:  []PIIOption{WithoutEntities("email")},
+			input: "a@b.com 123-45-6789",
+			want:  "a@b.com [SSN]",
+		},
+		{
+			name:  "custom pattern",
+			opts:  []PIIOption{WithEntities("email"), WithCustomPattern("employee_id", regexp.MustCompile(`EMP-[0-9]{6}`), "[EMPLOYEE_ID]")},
+			input: "EMP-123456 a@b.com",
+			want:  "[EMPLOYEE_ID] [EMAIL]",
+		},
+		{name: "unknown entity", opts: []PIIOption{WithEntities("dna")}, wantErr: true},
+		{name: "nil custom regexp", opts: []PIIOption{WithCustomPattern("x", nil, "[X]")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewPIIRedactorWithOptions(tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := r.Validate(context.Background(), GuardInput{Content: tt.input})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Modified != tt.want {
+				t.Errorf("Modified = %q, want %q", result.Modified, tt.want)
+			}
+		})
+	}
+}
+
+func TestPIIRedactor_RegistryEntities(t *testing.T) {
+	g, err := New("pii_redactor", map[string]any{"entities": []string{"iban"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := g.Validate(context.Background(), GuardInput{Content: "DE89 3704 0044 0532 0130 00 a@b.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Modified != "[IBAN] a@b.com" {
+		t.Errorf("Modified = %q", result.Modified)
+	}
+}
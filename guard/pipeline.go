@@ -1,15 +1,24 @@
 package guard
 
-import "context"
+import (
+	"context"
+	"strings"
+	"sync"
+)
 
 // Pipeline orchestrates the three-stage guard validation: input guards run on
 // user messages, output guards run on model responses, and tool guards run on
 // tool call arguments. Guards within each stage execute in order; the first
 // guard that blocks stops the pipeline for that stage.
+//
+// Stages enabled with WithParallelStage run read-only guards concurrently
+// instead; see WithParallelStage for the ordering guarantees.
 type Pipeline struct {
 	inputGuards  []Guard
 	outputGuards []Guard
 	toolGuards   []Guard
+
+	parallel map[string]bool
 }
 
 // PipelineOption configures a Pipeline during construction.
@@ -45,9 +54,54 @@ func Tool(guards ...Guard) PipelineOption {
 	}
 }
 
+// WithParallelStage enables concurrent execution for the named stages
+// ("input", "output", "tool"), or for every stage when no names are given.
+//
+// In a parallel stage, guards that report MutatesContent() == false run
+// concurrently, while mutating guards (including guards that do not
+// implement ContentMutator) still run sequentially in declared order, each
+// receiving the content produced by the mutating guards before it. Every
+// read-only guard sees exactly the content it would see in a sequential
+// run: the output of all mutating guards declared before it. Modified
+// content returned by a read-only guard is ignored.
+//
+// Unlike sequential stages, a parallel stage does not stop at the first
+// block: all guards run and every block is collected. The result reports
+// the first blocking guard in declared order as GuardName, Code, and
+// Remediation, joins all block reasons in Reason, and lists every finding
+// in Findings. If any guard returns an error, the error from the earliest
+// such guard in declared order is returned.
+func WithParallelStage(stages ...string) PipelineOption {
+	return func(p *Pipeline) {
+		if p.parallel == nil {
+			p.parallel = make(map[string]bool)
+		}
+		if len(stages) == 0 {
+			stages = []string{"input", "output", "tool"}
+		}
+		for _, s := range stages {
+			p.parallel[s] = true
+		}
+	}
+}
+
+// ReadOnly wraps g so that it reports MutatesContent() == false, allowing a
+// parallel stage to run it concurrently. Use it for third-party guards that
+// never modify content but do not implement ContentMutator.
+func ReadOnly(g Guard) Guard {
+	return readOnlyGuard{g}
+}
+
+type readOnlyGuard struct {
+	Guard
+}
+
+func (readOnlyGuard) MutatesContent() bool { return false }
+
 // ValidateInput runs all input guards sequentially against the given content.
 // It returns the first blocking result or an aggregate allowed result. If any
 // guard modifies the content, subsequent guards see the modified version.
+// Either way, the result's Findings report every notable guard result.
 func (p *Pipeline) ValidateInput(ctx context.Context, content string) (GuardResult, error) {
 	return p.runGuards(ctx, p.inputGuards, content, "input", nil)
 }
@@ -69,9 +123,14 @@ func (p *Pipeline) ValidateTool(ctx context.Context, toolName, input string) (Gu
 // runGuards executes guards in order. Each guard receives the (possibly
 // modified) content from the previous guard. The first non-allowed result
 // stops the chain. If all guards allow, the final (possibly modified) content
-// is returned.
+// is returned. Notable results are collected into Findings.
 func (p *Pipeline) runGuards(ctx context.Context, guards []Guard, content, role string, meta map[string]any) (GuardResult, error) {
+	if p.parallel[role] {
+		return p.runParallel(ctx, guards, content, role, meta)
+	}
+
 	current := content
+	var findings []GuardResult
 	for _, g := range guards {
 		select {
 		case <-ctx.Done():
@@ -87,9 +146,11 @@ func (p *Pipeline) runGuards(ctx context.Context, guards []Guard, content, role
 		if err != nil {
 			return GuardResult{}, err
 		}
+		if f, ok := asFinding(g, result); ok {
+			findings = append(findings, f)
+		}
 		if !result.Allowed {
-			result.GuardName = g.Name()
-			return result, nil
+			return blockReport(findings), nil
 		}
 		// If the guard modified the content, pass the modified version
 		// to subsequent guards.
@@ -99,9 +160,126 @@ func (p *Pipeline) runGuards(ctx context.Context, guards []Guard, content, role
 	}
 
 	// All guards passed.
-	result := GuardResult{Allowed: true}
+	return allowReport(findings, content, current), nil
+}
+
+// asFinding normalizes a guard result for inclusion in a stage report. It
+// reports false for plain allow results that carry nothing worth surfacing.
+func asFinding(g Guard, r GuardResult) (GuardResult, bool) {
+	if r.Allowed && r.Modified == "" && r.Reason == "" && r.Severity == "" {
+		return GuardResult{}, false
+	}
+	r.GuardName = g.Name()
+	r.Findings = nil
+	if r.Severity == "" {
+		if r.Allowed {
+			r.Severity = SeverityInfo
+		} else {
+			r.Severity = SeverityBlock
+		}
+	}
+	return r, true
+}
+
+// blockReport builds the result for a blocked stage. The first blocking
+// finding supplies GuardName, Code, and Remediation; the reasons of all
+// blocking findings are joined.
+func blockReport(findings []GuardResult) GuardResult {
+	var result GuardResult
+	var reasons []string
+	for _, f := range findings {
+		if f.Allowed {
+			continue
+		}
+		if len(reasons) == 0 {
+			result = f
+		}
+		reasons = append(reasons, f.Reason)
+		result.Score = max(result.Score, f.Score)
+	}
+	result.Reason = strings.Join(reasons, "; ")
+	result.Severity = maxSeverity(findings)
+	result.Findings = findings
+	return result
+}
+
+// allowReport builds the result for a stage in which every guard allowed.
+func allowReport(findings []GuardResult, content, current string) GuardResult {
+	result := GuardResult{Allowed: true, Findings: findings}
 	if current != content {
 		result.Modified = current
 	}
-	return result, nil
+	result.Severity = maxSeverity(findings)
+	return result
+}
+
+// maxSeverity returns the highest severity among findings.
+func maxSeverity(findings []GuardResult) Severity {
+	var s Severity
+	for _, f := range findings {
+		if f.Severity.rank() > s.rank() {
+			s = f.Severity
+		}
+	}
+	return s
+}
+
+// isMutating reports whether g may modify content. Guards that do not
+// implement ContentMutator are conservatively treated as mutating.
+func isMutating(g Guard) bool {
+	m, ok := g.(ContentMutator)
+	return !ok || m.MutatesContent()
+}
+
+// runParallel executes a parallel stage. Mutating guards run inline in
+// declared order; each read-only guard is launched in its own goroutine with
+// a snapshot of the content at its declared position.
+func (p *Pipeline) runParallel(ctx context.Context, guards []Guard, content, role string, meta map[string]any) (GuardResult, error) {
+	results := make([]GuardResult, len(guards))
+	errs := make([]error, len(guards))
+
+	var wg sync.WaitGroup
+	current := content
+	for i, g := range guards {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			break
+		}
+		input := GuardInput{Content: current, Role: role, Metadata: meta}
+		if !isMutating(g) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = g.Validate(ctx, input)
+			}()
+			continue
+		}
+		results[i], errs[i] = g.Validate(ctx, input)
+		if errs[i] == nil && results[i].Allowed && results[i].Modified != "" {
+			current = results[i].Modified
+		}
+	}
+	wg.Wait()
+
+	var findings []GuardResult
+	blocked := false
+	for i, g := range guards {
+		if errs[i] != nil {
+			return GuardResult{}, errs[i]
+		}
+		r := results[i]
+		if !isMutating(g) {
+			// Modified content from read-only guards is ignored.
+			r.Modified = ""
+		}
+		if f, ok := asFinding(g, r); ok {
+			findings = append(findings, f)
+		}
+		blocked = blocked || !r.Allowed
+	}
+
+	if blocked {
+		return blockReport(findings), nil
+	}
+	return allowReport(findings, content, current), nil
 }
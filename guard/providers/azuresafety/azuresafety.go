@@ -126,6 +126,9 @@ func (g *Guard) Validate(ctx context.Context, input guard.GuardInput) (guard.Gua
 
 	if !result.Allowed {
 		result.Reason = "flagged: " + strings.Join(flagged, ", ")
+		result.Severity = guard.SeverityBlock
+		result.Code = guard.CodeContentFiltered
+		result.Remediation = "Rephrase the content without the flagged material."
 	}
 
 	return result, nil
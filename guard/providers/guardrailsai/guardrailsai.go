@@ -139,10 +139,17 @@ func (g *Guard) Validate(ctx context.Context, input guard.GuardInput) (guard.Gua
 		} else {
 			result.Reason = "validation failed"
 		}
+		result.Severity = guard.SeverityBlock
+		result.Code = guard.CodeContentFiltered
+		result.Remediation = "Revise the content so that it passes the configured validators."
 	}
 
 	if resp.ValidatedOutput != "" && resp.ValidatedOutput != input.Content {
 		result.Modified = resp.ValidatedOutput
+		if allowed {
+			result.Severity = guard.SeverityWarn
+			result.Code = guard.CodeContentFiltered
+		}
 	}
 
 	return result, nil
@@ -96,6 +96,7 @@ func TestValidate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		assert.False(t, result.Allowed)
+		assert.Equal(t, guard.CodeContentFiltered, result.Code)
 		assert.Equal(t, "toxic content detected", result.Reason)
 	})
 
@@ -2,6 +2,7 @@ package lakera
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"time"
 
@@ -118,10 +119,26 @@ func (g *Guard) Validate(ctx context.Context, input guard.GuardInput) (guard.Gua
 		} else {
 			result.Reason = "blocked by Lakera Guard"
 		}
+		result.Severity = guard.SeverityBlock
+		result.Code, result.Remediation = categoryCode(flagged)
 	}
 
 	return result, nil
 }
 
+// categoryCode maps flagged Lakera categories to a guard result code and a
+// remediation, preferring prompt injection over jailbreak over other
+// categories.
+func categoryCode(flagged []string) (code, remediation string) {
+	switch {
+	case slices.Contains(flagged, "prompt_injection"):
+		return guard.CodePromptInjection, "Rephrase the request without instructions that try to override the assistant's rules."
+	case slices.Contains(flagged, "jailbreak"):
+		return guard.CodeJailbreak, "Rephrase the request without role-play or instructions that try to bypass the assistant's rules."
+	default:
+		return guard.CodeContentFiltered, "Rephrase the content without the flagged material."
+	}
+}
+
 // compile-time interface check
 var _ guard.Guard = (*Guard)(nil)
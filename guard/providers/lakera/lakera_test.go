@@ -97,6 +97,9 @@ func TestValidate(t *testing.T) {
 		assert.False(t, result.Allowed)
 		assert.Contains(t, result.Reason, "prompt_injection")
 		assert.Contains(t, result.Reason, "jailbreak")
+		assert.Equal(t, guard.SeverityBlock, result.Severity)
+		assert.Equal(t, guard.CodePromptInjection, result.Code)
+		assert.NotEmpty(t, result.Remediation)
 	})
 
 	t.Run("flagged without categories", func(t *testing.T) {
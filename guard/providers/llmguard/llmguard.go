@@ -109,8 +109,15 @@ func (g *Guard) Validate(ctx context.Context, input guard.GuardInput) (guard.Gua
 
 	if !resp.IsValid {
 		result.Reason = findFailureReason(resp.Scanners)
+		result.Severity = guard.SeverityBlock
+		result.Code = guard.CodeContentFiltered
+		result.Remediation = "Rephrase the content without the flagged material."
 	}
 	result.Modified = extractSanitized(input.Role, input.Content, resp)
+	if resp.IsValid && result.Modified != "" {
+		result.Severity = guard.SeverityWarn
+		result.Code = guard.CodeContentFiltered
+	}
 
 	return result, nil
 }
@@ -87,6 +87,7 @@ func TestValidate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		assert.False(t, result.Allowed)
+		assert.Equal(t, guard.CodeContentFiltered, result.Code)
 		assert.Contains(t, result.Reason, "PromptInjection")
 	})
 
@@ -138,6 +138,9 @@ func (g *Guard) Validate(ctx context.Context, input guard.GuardInput) (guard.Gua
 		if result.Reason == "" {
 			result.Reason = "blocked by NeMo Guardrails"
 		}
+		result.Severity = guard.SeverityBlock
+		result.Code = guard.CodeContentFiltered
+		result.Remediation = "Rephrase the content so that it complies with the configured rails."
 	}
 
 	// If NeMo returned a modified response, use it.
@@ -91,6 +91,7 @@ func TestValidate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		assert.False(t, result.Allowed)
+		assert.Equal(t, guard.CodeContentFiltered, result.Code)
 		assert.Equal(t, "jailbreak attempt detected", result.Reason)
 	})
 
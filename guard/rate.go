@@ -0,0 +1,425 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/state"
+)
+
+// rateKeyPrefix namespaces RateGuard records in the backing state.Store.
+const rateKeyPrefix = "guard:rate:"
+
+// maxCASAttempts bounds the optimistic update loop used with a
+// state.VersionedStore.
+const maxCASAttempts = 8
+
+// rateRecord is the per-subject state persisted by RateGuard. Fields are
+// exported with JSON tags so serializing stores can round-trip it.
+type rateRecord struct {
+	Requests     []time.Time `json:"requests,omitempty"`
+	Blocks       []time.Time `json:"blocks,omitempty"`
+	Offenses     int         `json:"offenses,omitempty"`
+	LastOffense  time.Time   `json:"last_offense,omitempty"`
+	BlockedUntil time.Time   `json:"blocked_until,omitempty"`
+}
+
+// RateGuard is a Guard that mitigates abuse by tracking per-subject request
+// patterns in a state.Store. It counts requests and block verdicts over a
+// sliding window and blocks a subject once either threshold is exceeded.
+// Each offense blocks the subject for an escalating timeout that doubles
+// with every repeat offense, up to a maximum. The offense count resets
+// after a quiet period with no offenses.
+//
+// The subject is read from GuardInput.Metadata (key "subject" by default)
+// or, when the metadata has none, from the context via WithSubject, which is
+// how callers of Pipeline.ValidateInput and ValidateOutput supply it. Inputs
+// without a subject are allowed and not tracked.
+//
+// Block verdicts from other guards are fed in with RecordBlock or by
+// wrapping those guards with Track, so subjects that repeatedly probe for
+// jailbreaks are cut off even when each individual request is blocked.
+//
+// When the store implements state.VersionedStore, updates use
+// compare-and-swap so several processes can share one store. Otherwise
+// updates are serialized within this RateGuard only.
+type RateGuard struct {
+	store       state.Store
+	subjectKey  string
+	window      time.Duration
+	maxRequests int
+	maxBlocks   int
+	baseTimeout time.Duration
+	maxTimeout  time.Duration
+	decay       time.Duration
+	now         func() time.Time // injectable for testing
+
+	mu sync.Mutex
+}
+
+// RateOption configures a RateGuard.
+type RateOption func(*RateGuard)
+
+// WithSubjectKey sets the GuardInput.Metadata key that identifies the
+// subject (user, session, API key). The default is "subject".
+func WithSubjectKey(key string) RateOption {
+	return func(g *RateGuard) {
+		if key != "" {
+			g.subjectKey = key
+		}
+	}
+}
+
+// WithRateWindow sets the sliding window over which requests and blocks are
+// counted. The default is one minute.
+func WithRateWindow(d time.Duration) RateOption {
+	return func(g *RateGuard) {
+		if d > 0 {
+			g.window = d
+		}
+	}
+}
+
+// WithMaxRequests sets the number of requests a subject may make within the
+// window. The default is 60.
+func WithMaxRequests(n int) RateOption {
+	return func(g *RateGuard) {
+		if n > 0 {
+			g.maxRequests = n
+		}
+	}
+}
+
+// WithMaxBlocks sets the number of block verdicts a subject may accumulate
+// within the window before being timed out. The default is 5.
+func WithMaxBlocks(n int) RateOption {
+	return func(g *RateGuard) {
+		if n > 0 {
+			g.maxBlocks = n
+		}
+	}
+}
+
+// WithTimeouts sets the timeout applied on a first offense and the cap for
+// escalated timeouts. Each repeat offense doubles the timeout. The defaults
+// are one minute and one hour.
+func WithTimeouts(base, maxTimeout time.Duration) RateOption {
+	return func(g *RateGuard) {
+		if base > 0 {
+			g.baseTimeout = base
+		}
+		if maxTimeout >= g.baseTimeout {
+			g.maxTimeout = maxTimeout
+		}
+	}
+}
+
+// WithOffenseDecay sets how long a subject must go without offending before
+// its offense count resets. The default is 24 hours.
+func WithOffenseDecay(d time.Duration) RateOption {
+	return func(g *RateGuard) {
+		if d > 0 {
+			g.decay = d
+		}
+	}
+}
+
+// NewRateGuard creates a RateGuard that persists per-subject counters in
+// store.
+//
+// Usage:
+//
+//	rg := guard.NewRateGuard(store,
+//	    guard.WithMaxRequests(30),
+//	    guard.WithMaxBlocks(3),
+//	    guard.WithTimeouts(time.Minute, time.Hour),
+//	)
+//	p := guard.NewPipeline(guard.Input(rg, rg.Track(guard.NewPromptInjectionDetector())))
+//	result, err := p.ValidateInput(guard.WithSubject(ctx, userID), userMessage)
+func NewRateGuard(store state.Store, opts ...RateOption) *RateGuard {
+	g := &RateGuard{
+		store:       store,
+		subjectKey:  "subject",
+		window:      time.Minute,
+		maxRequests: 60,
+		maxBlocks:   5,
+		baseTimeout: time.Minute,
+		maxTimeout:  time.Hour,
+		decay:       24 * time.Hour,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Name returns "rate_guard".
+func (g *RateGuard) Name() string {
+	return "rate_guard"
+}
+
+// MutatesContent returns false: RateGuard only inspects request metadata.
+func (g *RateGuard) MutatesContent() bool {
+	return false
+}
+
+// Validate records a request for the input's subject and blocks it when the
+// subject is timed out or has just exceeded a threshold.
+func (g *RateGuard) Validate(ctx context.Context, input GuardInput) (GuardResult, error) {
+	subject := g.subject(ctx, input)
+	if subject == "" {
+		return GuardResult{Allowed: true}, nil
+	}
+
+	var reason, code string
+	var remaining time.Duration
+	err := g.update(ctx, subject, func(rec *rateRecord, now time.Time) {
+		if now.Before(rec.BlockedUntil) {
+			remaining = rec.BlockedUntil.Sub(now).Round(time.Second)
+			reason = fmt.Sprintf("subject timed out for %s", remaining)
+			code = CodeRateLimited
+			return
+		}
+		rec.Requests = append(rec.Requests, now)
+		switch {
+		case len(rec.Requests) > g.maxRequests:
+			reason = fmt.Sprintf("rate limit exceeded: %d requests in %s", len(rec.Requests), g.window)
+			code = CodeRateLimited
+		case len(rec.Blocks) >= g.maxBlocks:
+			reason = fmt.Sprintf("abuse detected: %d blocked requests in %s", len(rec.Blocks), g.window)
+			code = CodeAbuseDetected
+		default:
+			return
+		}
+		remaining = g.offend(rec, now)
+		reason += fmt.Sprintf("; timed out for %s", remaining)
+	})
+	if err != nil {
+		return GuardResult{}, err
+	}
+
+	if reason == "" {
+		return GuardResult{Allowed: true}, nil
+	}
+	return GuardResult{
+		Allowed:     false,
+		Reason:      reason,
+		GuardName:   g.Name(),
+		Severity:    SeverityBlock,
+		Code:        code,
+		Remediation: fmt.Sprintf("Wait %s before sending another request.", remaining),
+	}, nil
+}
+
+// RecordBlock records a block verdict for subject, counting towards the
+// block threshold checked on the subject's next request.
+func (g *RateGuard) RecordBlock(ctx context.Context, subject string) error {
+	if subject == "" {
+		return nil
+	}
+	return g.update(ctx, subject, func(rec *rateRecord, now time.Time) {
+		rec.Blocks = append(rec.Blocks, now)
+	})
+}
+
+// Track wraps inner so that each of its block verdicts is recorded against
+// the input's subject via RecordBlock.
+func (g *RateGuard) Track(inner Guard) Guard {
+	return &trackedGuard{inner: inner, rate: g}
+}
+
+// offend registers an offense, starts the escalated timeout, and clears
+// the window so the subject starts fresh once the timeout expires.
+func (g *RateGuard) offend(rec *rateRecord, now time.Time) time.Duration {
+	rec.Offenses++
+	rec.LastOffense = now
+	timeout := g.baseTimeout
+	for i := 1; i < rec.Offenses && timeout < g.maxTimeout; i++ {
+		timeout *= 2
+	}
+	timeout = min(timeout, g.maxTimeout)
+	rec.BlockedUntil = now.Add(timeout)
+	rec.Requests = nil
+	rec.Blocks = nil
+	return timeout
+}
+
+// subject extracts the subject identifier from the input metadata, falling
+// back to the subject carried by ctx.
+func (g *RateGuard) subject(ctx context.Context, input GuardInput) string {
+	if s, _ := input.Metadata[g.subjectKey].(string); s != "" {
+		return s
+	}
+	return SubjectFromContext(ctx)
+}
+
+type subjectKey struct{}
+
+// WithSubject returns a new context carrying the subject (user, session, API
+// key) that RateGuard tracks requests against.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext extracts the subject set with WithSubject. Returns an
+// empty string if not set.
+func SubjectFromContext(ctx context.Context) string {
+	s, _ := ctx.Value(subjectKey{}).(string)
+	return s
+}
+
+// update applies fn to the subject's record after pruning expired entries,
+// and persists the result.
+func (g *RateGuard) update(ctx context.Context, subject string, fn func(rec *rateRecord, now time.Time)) error {
+	key := rateKeyPrefix + subject
+	apply := func(raw any) (rateRecord, error) {
+		rec, err := decodeRateRecord(raw)
+		if err != nil {
+			return rec, err
+		}
+		now := g.now()
+		g.prune(&rec, now)
+		fn(&rec, now)
+		return rec, nil
+	}
+
+	if vs, ok := g.store.(state.VersionedStore); ok {
+		for attempt := 0; attempt < maxCASAttempts; attempt++ {
+			raw, version, err := vs.GetVersioned(ctx, key)
+			if err != nil {
+				return core.Errorf(core.ErrProviderDown, "guard: rate: load %s: %w", subject, err)
+			}
+			rec, err := apply(raw)
+			if err != nil {
+				return err
+			}
+			_, err = vs.CompareAndSwap(ctx, key, version, rec)
+			if errors.Is(err, state.ErrVersionMismatch) {
+				continue
+			}
+			if err != nil {
+				return core.Errorf(core.ErrProviderDown, "guard: rate: store %s: %w", subject, err)
+			}
+			return nil
+		}
+		return core.Errorf(core.ErrRateLimit, "guard: rate: too much contention updating %s", subject)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	raw, err := g.store.Get(ctx, key)
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "guard: rate: load %s: %w", subject, err)
+	}
+	rec, err := apply(raw)
+	if err != nil {
+		return err
+	}
+	if err := g.store.Set(ctx, key, rec); err != nil {
+		return core.Errorf(core.ErrProviderDown, "guard: rate: store %s: %w", subject, err)
+	}
+	return nil
+}
+
+// prune drops requests and blocks outside the window and resets the offense
+// count after the decay period.
+func (g *RateGuard) prune(rec *rateRecord, now time.Time) {
+	cutoff := now.Add(-g.window)
+	rec.Requests = dropBefore(rec.Requests, cutoff)
+	rec.Blocks = dropBefore(rec.Blocks, cutoff)
+	if rec.Offenses > 0 && now.Sub(rec.LastOffense) > g.decay {
+		rec.Offenses = 0
+	}
+}
+
+// dropBefore returns the suffix of ts (sorted ascending) at or after cutoff.
+func dropBefore(ts []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(ts) && ts[i].Before(cutoff) {
+		i++
+	}
+	return append([]time.Time(nil), ts[i:]...)
+}
+
+// decodeRateRecord converts a stored value back into a rateRecord. Values
+// written by this process are returned directly; anything else (for example
+// a map or JSON bytes from a serializing store) is decoded through JSON.
+func decodeRateRecord(raw any) (rateRecord, error) {
+	switch v := raw.(type) {
+	case nil:
+		return rateRecord{}, nil
+	case rateRecord:
+		return v, nil
+	case *rateRecord:
+		return *v, nil
+	case []byte:
+		var rec rateRecord
+		if err := json.Unmarshal(v, &rec); err != nil {
+			return rec, core.Errorf(core.ErrInvalidInput, "guard: rate: decode record: %w", err)
+		}
+		return rec, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return rateRecord{}, core.Errorf(core.ErrInvalidInput, "guard: rate: decode record: %w", err)
+		}
+		return decodeRateRecord(data)
+	}
+}
+
+// trackedGuard forwards to an inner guard and reports its blocks to a
+// RateGuard.
+type trackedGuard struct {
+	inner Guard
+	rate  *RateGuard
+}
+
+func (t *trackedGuard) Name() string { return t.inner.Name() }
+
+func (t *trackedGuard) MutatesContent() bool { return isMutating(t.inner) }
+
+func (t *trackedGuard) Validate(ctx context.Context, input GuardInput) (GuardResult, error) {
+	result, err := t.inner.Validate(ctx, input)
+	if err != nil || result.Allowed {
+		return result, err
+	}
+	if err := t.rate.RecordBlock(ctx, t.rate.subject(ctx, input)); err != nil {
+		return GuardResult{}, err
+	}
+	return result, nil
+}
+
+// compile-time interface checks
+var (
+	_ Guard = (*RateGuard)(nil)
+	_ Guard = (*trackedGuard)(nil)
+)
+
+func init() {
+	Register("rate_guard", func(cfg map[string]any) (Guard, error) {
+		store, ok := cfg["store"].(state.Store)
+		if !ok || store == nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "guard: rate_guard requires cfg[\"store\"] of type state.Store")
+		}
+		var opts []RateOption
+		if k, ok := cfg["subject_key"].(string); ok {
+			opts = append(opts, WithSubjectKey(k))
+		}
+		if d, ok := cfg["window"].(time.Duration); ok {
+			opts = append(opts, WithRateWindow(d))
+		}
+		if n, ok := cfg["max_requests"].(int); ok {
+			opts = append(opts, WithMaxRequests(n))
+		}
+		if n, ok := cfg["max_blocks"].(int); ok {
+			opts = append(opts, WithMaxBlocks(n))
+		}
+		return NewRateGuard(store, opts...), nil
+	})
+}
@@ -0,0 +1,215 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/jsonutil"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// Result codes populated by SchemaGuard.
+const (
+	CodeUnknownTool     = "unknown_tool"
+	CodeInvalidToolArgs = "invalid_tool_args"
+	CodeToolPolicy      = "tool_policy_violation"
+)
+
+// ToolPolicy inspects the decoded arguments of a call to a specific tool and
+// returns a non-nil error describing the violation when the call must be
+// blocked. Policies run after schema validation succeeds.
+type ToolPolicy func(ctx context.Context, toolName string, args map[string]any) error
+
+// SchemaGuard is a Guard for the tool stage that validates tool-call
+// arguments against the InputSchema of the tool registered in a
+// tool.Registry, then applies optional per-tool policies.
+//
+// The tool name is read from GuardInput.Metadata["tool_name"] (as set by
+// Pipeline.ValidateTool) and the arguments from GuardInput.Content as a
+// JSON object. The supported JSON Schema keywords are type, properties,
+// required, additionalProperties (boolean), enum, const, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern,
+// items, minItems, and maxItems.
+type SchemaGuard struct {
+	registry     *tool.Registry
+	policies     map[string][]ToolPolicy
+	allowUnknown bool
+}
+
+// SchemaGuardOption configures a SchemaGuard.
+type SchemaGuardOption func(*SchemaGuard)
+
+// WithToolPolicy registers a policy for the named tool. Use "*" to apply a
+// policy to every tool. Multiple policies run in registration order, with
+// wildcard policies first.
+func WithToolPolicy(toolName string, policy ToolPolicy) SchemaGuardOption {
+	return func(g *SchemaGuard) {
+		g.policies[toolName] = append(g.policies[toolName], policy)
+	}
+}
+
+// WithAllowUnknownTools controls whether calls to tools missing from the
+// registry are allowed. By default they are blocked.
+func WithAllowUnknownTools(allow bool) SchemaGuardOption {
+	return func(g *SchemaGuard) {
+		g.allowUnknown = allow
+	}
+}
+
+// NewSchemaGuard creates a SchemaGuard that looks tools up in reg.
+//
+// Usage:
+//
+//	g := guard.NewSchemaGuard(tools,
+//	    guard.WithToolPolicy("sql_query", guard.DenyKeywords("query", "DROP", "TRUNCATE")),
+//	    guard.WithToolPolicy("read_file", guard.PathAllowlist("path", "/srv/data")),
+//	)
+//	p := guard.NewPipeline(guard.Tool(g))
+func NewSchemaGuard(reg *tool.Registry, opts ...SchemaGuardOption) *SchemaGuard {
+	g := &SchemaGuard{
+		registry: reg,
+		policies: make(map[string][]ToolPolicy),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Name returns "schema_guard".
+func (g *SchemaGuard) Name() string {
+	return "schema_guard"
+}
+
+// MutatesContent returns false: SchemaGuard only inspects arguments.
+func (g *SchemaGuard) MutatesContent() bool {
+	return false
+}
+
+// Validate checks the tool call arguments against the tool's schema and
+// policies. Calls with malformed, out-of-range, or disallowed arguments are
+// blocked.
+func (g *SchemaGuard) Validate(ctx context.Context, input GuardInput) (GuardResult, error) {
+	name, _ := input.Metadata["tool_name"].(string)
+	t, err := g.registry.Get(name)
+	if err != nil {
+		if g.allowUnknown {
+			return GuardResult{Allowed: true}, nil
+		}
+		return g.block(CodeUnknownTool, fmt.Sprintf("tool %q is not registered", name),
+			"Call one of the registered tools."), nil
+	}
+
+	var args map[string]any
+	if strings.TrimSpace(input.Content) != "" {
+		if err := json.Unmarshal([]byte(input.Content), &args); err != nil {
+			return g.block(CodeInvalidToolArgs, fmt.Sprintf("tool %q arguments are not a JSON object: %v", name, err),
+				"Provide the tool arguments as a JSON object."), nil
+		}
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+
+	if sch := t.InputSchema(); sch != nil {
+		if errs := jsonutil.ValidateSchema(args, sch, "args"); len(errs) > 0 {
+			return g.block(CodeInvalidToolArgs,
+				fmt.Sprintf("tool %q arguments invalid: %s", name, strings.Join(errs, "; ")),
+				"Correct the arguments to match the tool's input schema."), nil
+		}
+	}
+
+	policies := append(append([]ToolPolicy(nil), g.policies["*"]...), g.policies[name]...)
+	for _, policy := range policies {
+		if err := policy(ctx, name, args); err != nil {
+			msg := err.Error()
+			var ce *core.Error
+			if errors.As(err, &ce) {
+				msg = ce.Message
+			}
+			return g.block(CodeToolPolicy, fmt.Sprintf("tool %q call rejected: %s", name, msg),
+				"Adjust the arguments to comply with the tool's usage policy."), nil
+		}
+	}
+	return GuardResult{Allowed: true}, nil
+}
+
+func (g *SchemaGuard) block(code, reason, remediation string) GuardResult {
+	return GuardResult{
+		Allowed:     false,
+		Reason:      reason,
+		GuardName:   g.Name(),
+		Severity:    SeverityBlock,
+		Code:        code,
+		Remediation: remediation,
+	}
+}
+
+// DenyKeywords returns a ToolPolicy that rejects calls whose string argument
+// field contains any of the given keywords as a whole word,
+// case-insensitively. It is suited to blocking destructive SQL such as DROP
+// or TRUNCATE.
+func DenyKeywords(field string, keywords ...string) ToolPolicy {
+	quoted := make([]string, len(keywords))
+	for i, k := range keywords {
+		quoted[i] = regexp.QuoteMeta(k)
+	}
+	re := regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	return func(_ context.Context, _ string, args map[string]any) error {
+		s, _ := args[field].(string)
+		if m := re.FindString(s); m != "" {
+			return core.Errorf(core.ErrGuardBlocked, "%s contains disallowed keyword %q", field, m)
+		}
+		return nil
+	}
+}
+
+// PathAllowlist returns a ToolPolicy that rejects calls whose string
+// argument field is not a path inside one of the allowed directories. Paths
+// are cleaned before comparison, so traversal with ".." is caught. Relative
+// paths are rejected.
+func PathAllowlist(field string, dirs ...string) ToolPolicy {
+	cleaned := make([]string, len(dirs))
+	for i, d := range dirs {
+		cleaned[i] = filepath.Clean(d)
+	}
+	return func(_ context.Context, _ string, args map[string]any) error {
+		p, ok := args[field].(string)
+		if !ok {
+			return nil
+		}
+		if !filepath.IsAbs(p) {
+			return core.Errorf(core.ErrGuardBlocked, "%s must be an absolute path", field)
+		}
+		clean := filepath.Clean(p)
+		for _, d := range cleaned {
+			if clean == d || strings.HasPrefix(clean, d+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		return core.Errorf(core.ErrGuardBlocked, "%s %q is outside the allowed directories", field, p)
+	}
+}
+
+// compile-time interface check
+var _ Guard = (*SchemaGuard)(nil)
+
+func init() {
+	Register("schema_guard", func(cfg map[string]any) (Guard, error) {
+		reg, ok := cfg["registry"].(*tool.Registry)
+		if !ok || reg == nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "guard: schema_guard requires cfg[\"registry\"] of type *tool.Registry")
+		}
+		var opts []SchemaGuardOption
+		if allow, ok := cfg["allow_unknown_tools"].(bool); ok {
+			opts = append(opts, WithAllowUnknownTools(allow))
+		}
+		return NewSchemaGuard(reg, opts...), nil
+	})
+}
@@ -0,0 +1,144 @@
+package guard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// schemaTool is a minimal tool.Tool exposing a fixed input schema.
+type schemaTool struct {
+	name   string
+	schema map[string]any
+}
+
+func (t *schemaTool) Name() string                { return t.name }
+func (t *schemaTool) Description() string         { return t.name }
+func (t *schemaTool) InputSchema() map[string]any { return t.schema }
+func (t *schemaTool) Execute(context.Context, map[string]any) (*tool.Result, error) {
+	return tool.TextResult("ok"), nil
+}
+
+func newSchemaTestRegistry(t *testing.T) *tool.Registry {
+	t.Helper()
+	reg := tool.NewRegistry()
+	tools := []tool.Tool{
+		&schemaTool{name: "transfer", schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"amount":   map[string]any{"type": "number", "minimum": 0, "maximum": 1000},
+				"currency": map[string]any{"type": "string", "enum": []string{"USD", "EUR"}},
+				"memo":     map[string]any{"type": "string", "maxLength": 10},
+				"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "maxItems": 2},
+			},
+			"required":             []string{"amount", "currency"},
+			"additionalProperties": false,
+		}},
+		&schemaTool{name: "sql", schema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"query": map[string]any{"type": "string"}},
+		}},
+		&schemaTool{name: "read_file", schema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"path": map[string]any{"type": "string", "pattern": "^/"}},
+		}},
+	}
+	for _, tl := range tools {
+		if err := reg.Add(tl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func TestSchemaGuard_Validate(t *testing.T) {
+	g := NewSchemaGuard(newSchemaTestRegistry(t),
+		WithToolPolicy("sql", DenyKeywords("query", "DROP", "TRUNCATE")),
+		WithToolPolicy("read_file", PathAllowlist("path", "/srv/data")),
+	)
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     string
+		wantCode string
+		wantMsg  string
+	}{
+		{name: "valid", tool: "transfer", args: `{"amount": 10, "currency": "USD"}`},
+		{name: "unknown tool", tool: "rm", args: `{}`, wantCode: CodeUnknownTool},
+		{name: "not json", tool: "transfer", args: `amount=10`, wantCode: CodeInvalidToolArgs},
+		{name: "missing required", tool: "transfer", args: `{"amount": 10}`, wantCode: CodeInvalidToolArgs, wantMsg: "args.currency: required"},
+		{name: "above maximum", tool: "transfer", args: `{"amount": 5000, "currency": "USD"}`, wantCode: CodeInvalidToolArgs, wantMsg: "exceeds maximum"},
+		{name: "below minimum", tool: "transfer", args: `{"amount": -1, "currency": "USD"}`, wantCode: CodeInvalidToolArgs, wantMsg: "below minimum"},
+		{name: "enum", tool: "transfer", args: `{"amount": 1, "currency": "BTC"}`, wantCode: CodeInvalidToolArgs, wantMsg: "not one of"},
+		{name: "wrong type", tool: "transfer", args: `{"amount": "1", "currency": "USD"}`, wantCode: CodeInvalidToolArgs, wantMsg: "expected number"},
+		{name: "max length", tool: "transfer", args: `{"amount": 1, "currency": "USD", "memo": "way too long memo"}`, wantCode: CodeInvalidToolArgs},
+		{name: "array items", tool: "transfer", args: `{"amount": 1, "currency": "USD", "tags": ["a", 2]}`, wantCode: CodeInvalidToolArgs, wantMsg: "args.tags[1]"},
+		{name: "additional property", tool: "transfer", args: `{"amount": 1, "currency": "USD", "to": "x"}`, wantCode: CodeInvalidToolArgs, wantMsg: "unexpected property"},
+		{name: "sql allowed", tool: "sql", args: `{"query": "SELECT * FROM dropped_items"}`},
+		{name: "sql drop", tool: "sql", args: `{"query": "drop table users"}`, wantCode: CodeToolPolicy, wantMsg: `disallowed keyword "drop"`},
+		{name: "path allowed", tool: "read_file", args: `{"path": "/srv/data/a.txt"}`},
+		{name: "path traversal", tool: "read_file", args: `{"path": "/srv/data/../../etc/passwd"}`, wantCode: CodeToolPolicy},
+		{name: "path pattern", tool: "read_file", args: `{"path": "etc/passwd"}`, wantCode: CodeInvalidToolArgs, wantMsg: "pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := NewPipeline(Tool(g)).ValidateTool(context.Background(), tt.tool, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCode == "" {
+				if !res.Allowed {
+					t.Errorf("blocked: %s", res.Reason)
+				}
+				return
+			}
+			if res.Allowed {
+				t.Fatalf("Allowed = true, want block %s", tt.wantCode)
+			}
+			if res.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q (reason %q)", res.Code, tt.wantCode, res.Reason)
+			}
+			if !strings.Contains(res.Reason, tt.wantMsg) {
+				t.Errorf("Reason = %q, want to contain %q", res.Reason, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestSchemaGuard_WildcardPolicyAndUnknownTools(t *testing.T) {
+	calls := 0
+	g := NewSchemaGuard(newSchemaTestRegistry(t),
+		WithAllowUnknownTools(true),
+		WithToolPolicy("*", func(_ context.Context, _ string, _ map[string]any) error {
+			calls++
+			return nil
+		}),
+	)
+	res, err := g.Validate(context.Background(), GuardInput{Content: `{}`, Metadata: map[string]any{"tool_name": "other"}})
+	if err != nil || !res.Allowed {
+		t.Fatalf("unknown tool = %+v, %v; want allowed", res, err)
+	}
+	if _, err := g.Validate(context.Background(), GuardInput{Content: `{"query":"x"}`, Metadata: map[string]any{"tool_name": "sql"}}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("wildcard policy calls = %d, want 1", calls)
+	}
+}
+
+func TestSchemaGuard_Registry(t *testing.T) {
+	if _, err := New("schema_guard", nil); err == nil {
+		t.Error("expected error without registry")
+	}
+	g, err := New("schema_guard", map[string]any{"registry": tool.NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Name() != "schema_guard" {
+		t.Errorf("Name() = %q", g.Name())
+	}
+}
@@ -0,0 +1,272 @@
+package guard
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// MetadataSecrets is the GuardResult.Metadata key under which
+// SecretLeakDetector reports a map[string]int of detected secret kinds to
+// match counts.
+const MetadataSecrets = "secrets"
+
+// secretPattern pairs a secret kind with the regexp that detects it.
+type secretPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// defaultSecretPatterns detect well-known credential formats. They run
+// before the entropy scan so known formats are reported by kind.
+var defaultSecretPatterns = []secretPattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----(?s:.*?)(?:-----END (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----|$)`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_key", regexp.MustCompile(`(?i)(?P<keep>aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}\b`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"stripe_key", regexp.MustCompile(`\b[sr]k_(?:live|test)_[A-Za-z0-9]{20,}\b`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"connection_string", regexp.MustCompile(`\b(?P<keep>[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:)[^\s@/]+(?P<keep>@[^\s/]+)`)},
+}
+
+// entropyCandidate matches tokens eligible for the entropy scan.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+
+// SecretLeakDetector is a Guard for the output stage that detects
+// credentials echoed by a model and redacts them. It recognizes known key
+// formats (AWS, GitHub, JWT, PEM private keys, Slack, Stripe, Google API
+// keys, and credentials embedded in connection strings) and, as a fallback,
+// any long token whose Shannon entropy exceeds a threshold.
+//
+// Matches are replaced with "[REDACTED:<kind>]"; high-entropy tokens use
+// the kind "high_entropy". Connection strings keep their scheme, user, and
+// host, with only the password redacted. Text matching an allowlist pattern
+// is never redacted.
+type SecretLeakDetector struct {
+	patterns  []secretPattern
+	allow     []*regexp.Regexp
+	entropy   float64
+	minLength int
+
+	// err is the first invalid option, reported by NewSecretLeakDetector.
+	err error
+}
+
+// SecretOption configures a SecretLeakDetector.
+type SecretOption func(*SecretLeakDetector)
+
+// WithEntropyThreshold sets the minimum Shannon entropy, in bits per
+// character, at which an unrecognized token is treated as a secret. The
+// default is 4.0. Zero or negative values disable the entropy scan.
+func WithEntropyThreshold(bits float64) SecretOption {
+	return func(d *SecretLeakDetector) {
+		d.entropy = bits
+	}
+}
+
+// WithMinSecretLength sets the minimum length of tokens considered by the
+// entropy scan. The default is 20.
+func WithMinSecretLength(n int) SecretOption {
+	return func(d *SecretLeakDetector) {
+		if n > 0 {
+			d.minLength = n
+		}
+	}
+}
+
+// WithAllowPattern exempts text matching pattern from redaction, for
+// example known-safe identifiers such as commit hashes or public keys. An
+// invalid pattern makes NewSecretLeakDetector fail.
+func WithAllowPattern(pattern string) SecretOption {
+	return func(d *SecretLeakDetector) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			d.fail(core.Errorf(core.ErrInvalidInput, "guard: invalid secret allow pattern %q: %w", pattern, err))
+			return
+		}
+		d.allow = append(d.allow, re)
+	}
+}
+
+// WithSecretPattern adds a custom secret format detected under kind. The
+// whole match is redacted, except for text captured by groups named "keep",
+// which is left in place as context; for example `(?P<keep>token=)\w+`
+// redacts only the token's value. An invalid pattern makes
+// NewSecretLeakDetector fail.
+func WithSecretPattern(kind, pattern string) SecretOption {
+	return func(d *SecretLeakDetector) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			d.fail(core.Errorf(core.ErrInvalidInput, "guard: invalid secret pattern %q for %s: %w", pattern, kind, err))
+			return
+		}
+		d.patterns = append(d.patterns, secretPattern{kind: kind, pattern: re})
+	}
+}
+
+// fail records err unless an earlier option already failed.
+func (d *SecretLeakDetector) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// NewSecretLeakDetector creates a SecretLeakDetector with the built-in
+// secret formats, optionally modified by the given options. It returns an
+// error if an option's pattern does not compile.
+//
+// Usage:
+//
+//	detector, err := guard.NewSecretLeakDetector(
+//	    guard.WithAllowPattern(`^[0-9a-f]{40}$`), // git SHAs
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	p := guard.NewPipeline(guard.Output(detector))
+func NewSecretLeakDetector(opts ...SecretOption) (*SecretLeakDetector, error) {
+	d := &SecretLeakDetector{
+		patterns:  make([]secretPattern, len(defaultSecretPatterns)),
+		entropy:   4.0,
+		minLength: 20,
+	}
+	copy(d.patterns, defaultSecretPatterns)
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d, nil
+}
+
+// Name returns "secret_leak_detector".
+func (d *SecretLeakDetector) Name() string {
+	return "secret_leak_detector"
+}
+
+// MutatesContent returns true because SecretLeakDetector rewrites content.
+func (d *SecretLeakDetector) MutatesContent() bool {
+	return true
+}
+
+// Validate scans the content for secrets and returns the redacted content
+// in Modified. The result is always Allowed because redaction makes the
+// content safe to pass through. When anything was redacted,
+// Metadata[MetadataSecrets] holds the per-kind match counts.
+func (d *SecretLeakDetector) Validate(_ context.Context, input GuardInput) (GuardResult, error) {
+	modified := input.Content
+	counts := make(map[string]int)
+
+	for _, p := range d.patterns {
+		modified = p.pattern.ReplaceAllStringFunc(modified, func(match string) string {
+			if d.allowed(match) {
+				return match
+			}
+			counts[p.kind]++
+			return d.redact(p, match)
+		})
+	}
+
+	if d.entropy > 0 {
+		modified = entropyCandidate.ReplaceAllStringFunc(modified, func(tok string) string {
+			if len(tok) < d.minLength || d.allowed(tok) || shannonEntropy(tok) < d.entropy {
+				return tok
+			}
+			counts["high_entropy"]++
+			return "[REDACTED:high_entropy]"
+		})
+	}
+
+	result := GuardResult{Allowed: true}
+	if len(counts) > 0 {
+		result.Modified = modified
+		result.Reason = "secrets redacted"
+		result.GuardName = d.Name()
+		result.Metadata = map[string]any{MetadataSecrets: counts}
+		result.Severity = SeverityWarn
+		result.Code = CodeSecretRedacted
+		result.Remediation = "Rotate any exposed credentials and keep secrets out of model context."
+	}
+	return result, nil
+}
+
+// redact returns the replacement for a pattern match: the text captured by
+// groups named "keep" (such as a key name or connection string host), with
+// a placeholder in place of everything else. Without such groups the whole
+// match is replaced.
+func (d *SecretLeakDetector) redact(p secretPattern, match string) string {
+	placeholder := "[REDACTED:" + p.kind + "]"
+	loc := p.pattern.FindStringSubmatchIndex(match)
+	if loc == nil {
+		return placeholder
+	}
+	var b strings.Builder
+	pos := 0
+	for i, name := range p.pattern.SubexpNames() {
+		start, end := loc[2*i], loc[2*i+1]
+		if name != "keep" || start < pos {
+			continue
+		}
+		if start > pos {
+			b.WriteString(placeholder)
+		}
+		b.WriteString(match[start:end])
+		pos = end
+	}
+	if pos < len(match) {
+		b.WriteString(placeholder)
+	}
+	return b.String()
+}
+
+// allowed reports whether s matches any allowlist pattern.
+func (d *SecretLeakDetector) allowed(s string) bool {
+	for _, re := range d.allow {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var freq [256]int
+	for i := 0; i < len(s); i++ {
+		freq[s[i]]++
+	}
+	n := float64(len(s))
+	var h float64
+	for _, c := range freq {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+func init() {
+	Register("secret_leak_detector", func(cfg map[string]any) (Guard, error) {
+		var opts []SecretOption
+		if t, ok := cfg["entropy_threshold"].(float64); ok {
+			opts = append(opts, WithEntropyThreshold(t))
+		}
+		if allow, ok := cfg["allow"].([]string); ok {
+			for _, a := range allow {
+				opts = append(opts, WithAllowPattern(a))
+			}
+		}
+		return NewSecretLeakDetector(opts...)
+	})
+}
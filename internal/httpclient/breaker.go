@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+)
+
+// errServerFailure marks a 5xx response as a failure for the circuit
+// breaker; the response itself is still returned to the caller.
+var errServerFailure = errors.New("httpclient: server error")
+
+// WithCircuitBreaker guards each host the client talks to with its own
+// resilience.CircuitBreaker. After failureThreshold consecutive failures,
+// network errors or 5xx responses, requests to the host fail immediately
+// with an error matching resilience.ErrCircuitOpen instead of waiting for
+// the timeout, until resetTimeout has passed and a probe request succeeds.
+// Responses with other status codes, including 429, count as successes as
+// they show the host is up. Circuit-open errors are not retried.
+//
+// opts configure each breaker; breakers are named by host in metrics.
+func WithCircuitBreaker(failureThreshold int, resetTimeout time.Duration, opts ...resilience.CircuitBreakerOption) Option {
+	return func(c *Client) {
+		c.breakers = &hostBreakers{
+			failureThreshold: failureThreshold,
+			resetTimeout:     resetTimeout,
+			opts:             opts,
+			byHost:           make(map[string]*resilience.CircuitBreaker),
+		}
+	}
+}
+
+// CircuitBreaker returns the circuit breaker guarding host, or nil if the
+// client has no circuit breaker or has not sent a request to host.
+func (c *Client) CircuitBreaker(host string) *resilience.CircuitBreaker {
+	if c.breakers == nil {
+		return nil
+	}
+	c.breakers.mu.Lock()
+	defer c.breakers.mu.Unlock()
+	return c.breakers.byHost[host]
+}
+
+// hostBreakers holds the circuit breakers of a client, one per host,
+// created on first use.
+type hostBreakers struct {
+	failureThreshold int
+	resetTimeout     time.Duration
+	opts             []resilience.CircuitBreakerOption
+
+	mu     sync.Mutex
+	byHost map[string]*resilience.CircuitBreaker
+}
+
+// get returns the breaker for host, creating it if needed.
+func (b *hostBreakers) get(host string) *resilience.CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cb, ok := b.byHost[host]
+	if !ok {
+		opts := append([]resilience.CircuitBreakerOption{resilience.WithCircuitBreakerName(host)}, b.opts...)
+		cb = resilience.NewCircuitBreaker(b.failureThreshold, b.resetTimeout, opts...)
+		b.byHost[host] = cb
+	}
+	return cb
+}
+
+// applyCircuitBreaker wraps the client's transport with its circuit
+// breakers, if any.
+func (c *Client) applyCircuitBreaker() {
+	if c.breakers == nil {
+		return
+	}
+	c.http.Transport = &breakerTransport{next: c.transport(), breakers: c.breakers}
+}
+
+// breakerTransport sends requests through the circuit breaker of their
+// host.
+type breakerTransport struct {
+	next     http.RoundTripper
+	breakers *hostBreakers
+}
+
+// RoundTrip implements http.RoundTripper. A request whose context ends
+// before the host answers fails with the transport's error but counts as
+// neither a success nor a failure, and does not use up a half-open probe.
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cb := t.breakers.get(req.URL.Host)
+	result, err := cb.Execute(req.Context(), func(context.Context) (any, error) {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil && req.Context().Err() != nil {
+			// The caller gave up; that says nothing about the host.
+			return nil, resilience.Ignore(err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return resp, errServerFailure
+		}
+		return resp, nil
+	})
+	if errors.Is(err, errServerFailure) {
+		return result.(*http.Response), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*http.Response), nil
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *breakerTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
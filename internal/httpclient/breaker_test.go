@@ -0,0 +1,207 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+)
+
+func TestWithCircuitBreaker_OpensOnServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCircuitBreaker(2, time.Hour))
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	}
+
+	_, err := DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	assert.ErrorIs(t, err, resilience.ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+
+	host := mustHost(t, srv.URL)
+	cb := c.CircuitBreaker(host)
+	require.NotNil(t, cb)
+	assert.Equal(t, resilience.StateOpen, cb.State())
+}
+
+func TestWithCircuitBreaker_OpenIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithRetries(5), WithBackoff(time.Millisecond), WithCircuitBreaker(2, time.Hour))
+
+	start := time.Now()
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	assert.ErrorIs(t, err, resilience.ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWithCircuitBreaker_NetworkErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	c := New(WithBaseURL(addr), WithCircuitBreaker(1, time.Hour))
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, resilience.ErrCircuitOpen)
+
+	var events []string
+	for _, err := range StreamSSE(context.Background(), c, "/") {
+		require.Error(t, err)
+		events = append(events, err.Error())
+		assert.ErrorIs(t, err, resilience.ErrCircuitOpen)
+	}
+	assert.Len(t, events, 1)
+}
+
+func TestWithCircuitBreaker_ClientErrorsAreSuccesses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCircuitBreaker(1, time.Hour))
+	for range 3 {
+		_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+	}
+	assert.Equal(t, resilience.StateClosed, c.CircuitBreaker(mustHost(t, srv.URL)).State())
+}
+
+func TestWithCircuitBreaker_Recovers(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"name":"up"}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCircuitBreaker(1, 20*time.Millisecond))
+	ctx := context.Background()
+
+	_, err := DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	require.Error(t, err)
+	_, err = DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	require.ErrorIs(t, err, resilience.ErrCircuitOpen)
+
+	healthy.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	resp, err := DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "up", resp.Name)
+	assert.Equal(t, resilience.StateClosed, c.CircuitBreaker(mustHost(t, srv.URL)).State())
+}
+
+func TestWithCircuitBreaker_PerHost(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"up"}`)
+	}))
+	defer up.Close()
+
+	c := New(WithCircuitBreaker(1, time.Hour))
+	ctx := context.Background()
+	_, err := DoJSON[testResponse](ctx, c, http.MethodGet, down.URL, nil)
+	require.Error(t, err)
+	_, err = DoJSON[testResponse](ctx, c, http.MethodGet, down.URL, nil)
+	require.ErrorIs(t, err, resilience.ErrCircuitOpen)
+
+	resp, err := DoJSON[testResponse](ctx, c, http.MethodGet, up.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "up", resp.Name)
+}
+
+func TestWithCircuitBreaker_CancellationIsNotAFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCircuitBreaker(1, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	require.Error(t, err)
+	assert.Equal(t, resilience.StateClosed, c.CircuitBreaker(mustHost(t, srv.URL)).State())
+}
+
+func TestWithCircuitBreaker_CancellationKeepsHalfOpen(t *testing.T) {
+	var hang atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprint(w, `{"name":"up"}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCircuitBreaker(1, 20*time.Millisecond))
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	cb := c.CircuitBreaker(mustHost(t, srv.URL))
+	require.NotNil(t, cb)
+	cb.Trip()
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, resilience.StateHalfOpen, cb.State())
+
+	// A canceled probe neither closes the breaker nor uses up the probe.
+	hang.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = DoJSON[testResponse](ctx, c, http.MethodGet, "/", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, resilience.StateHalfOpen, cb.State())
+
+	hang.Store(false)
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "up", resp.Name)
+	assert.Equal(t, resilience.StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_NotConfigured(t *testing.T) {
+	c := New()
+	assert.Nil(t, c.CircuitBreaker("example.com"))
+}
+
+// mustHost returns the host of rawURL.
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return u.Host
+}
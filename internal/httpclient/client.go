@@ -26,6 +26,12 @@ type Client struct {
 	headers map[string]string
 	retries int
 	backoff time.Duration
+
+	compression     bool
+	requestEncoding string
+	middleware      []Middleware
+	breakers        *hostBreakers
+	stats           *poolStats
 }
 
 // Option configures a Client.
@@ -80,13 +86,38 @@ func New(opts ...Option) *Client {
 		headers: make(map[string]string),
 		retries: 0,
 		backoff: 500 * time.Millisecond,
+
+		compression: true,
+		stats:       &poolStats{},
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	// Layers from the innermost out: pool metrics see every connection,
+	// the circuit breaker sees raw responses, and middleware sees
+	// decompressed bodies.
+	c.applyMetrics()
+	c.applyCircuitBreaker()
+	c.applyCompression()
+	c.applyMiddleware()
 	return c
 }
 
+// transport returns the client's current transport.
+func (c *Client) transport() http.RoundTripper {
+	if c.http.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.http.Transport
+}
+
+// closeIdleConnections closes the idle connections of rt, if it supports it.
+func closeIdleConnections(rt http.RoundTripper) {
+	if c, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
 // APIError represents an HTTP error response from an API.
 type APIError struct {
 	StatusCode int
@@ -104,11 +135,6 @@ func (e *APIError) Error() string {
 // Do sends an HTTP request and returns the raw response.
 // The caller is responsible for closing the response body.
 func (c *Client) Do(ctx context.Context, method, path string, body any, headers map[string]string) (*http.Response, error) {
-	url := path
-	if c.baseURL != "" && !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
-		url = c.baseURL + "/" + strings.TrimLeft(path, "/")
-	}
-
 	var bodyReader io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
@@ -118,7 +144,7 @@ func (c *Client) Do(ctx context.Context, method, path string, body any, headers
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("httpclient: create request: %w", err)
 	}
@@ -137,6 +163,14 @@ func (c *Client) Do(ctx context.Context, method, path string, body any, headers
 	return c.http.Do(req)
 }
 
+// url resolves path against the base URL. Absolute URLs are used as is.
+func (c *Client) url(path string) string {
+	if c.baseURL == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return c.baseURL + "/" + strings.TrimLeft(path, "/")
+}
+
 // isRetryable returns true if the status code warrants a retry.
 func isRetryable(statusCode int) bool {
 	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
@@ -220,24 +254,24 @@ func doJSONAttempt[T any](ctx context.Context, c *Client, method, path string, b
 
 	resp, err := c.Do(ctx, method, path, body, nil)
 	if err != nil {
-		return zero, err, !shouldRetryNetErr(ctx, c, err, attempt)
+		return zero, err, !shouldRetryNetErr(ctx, c.backoff, c.retries, err, attempt)
 	}
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return decodeJSONResponse[T](resp)
 	}
 
-	apiErr, shouldRetry := handleErrorResponse(ctx, c, resp, attempt)
+	apiErr, shouldRetry := handleErrorResponse(ctx, c.backoff, c.retries, resp, attempt)
 	return zero, apiErr, !shouldRetry
 }
 
 // shouldRetryNetErr checks if a network error should be retried, and waits if so.
 // Returns true if the caller should retry (continue to next attempt).
-func shouldRetryNetErr(ctx context.Context, c *Client, err error, attempt int) bool {
-	if !isNetworkError(err) || attempt >= c.retries {
+func shouldRetryNetErr(ctx context.Context, backoff time.Duration, retries int, err error, attempt int) bool {
+	if !isNetworkError(err) || attempt >= retries {
 		return false
 	}
-	return waitForRetry(ctx, nil, c.backoff, attempt)
+	return waitForRetry(ctx, nil, backoff, attempt)
 }
 
 // decodeJSONResponse reads and decodes a successful JSON response.
@@ -252,12 +286,12 @@ func decodeJSONResponse[T any](resp *http.Response) (T, error, bool) {
 
 // handleErrorResponse processes a non-2xx response, retrying if appropriate.
 // Returns the error and whether the caller should retry.
-func handleErrorResponse(ctx context.Context, c *Client, resp *http.Response, attempt int) (error, bool) {
+func handleErrorResponse(ctx context.Context, backoff time.Duration, retries int, resp *http.Response, attempt int) (error, bool) {
 	respBody, _ := io.ReadAll(resp.Body)
 	_ = resp.Body.Close() // #nosec G104 -- close error in error-handling path is not actionable
 
-	if isRetryable(resp.StatusCode) && attempt < c.retries {
-		if waitForRetry(ctx, resp, c.backoff, attempt) {
+	if isRetryable(resp.StatusCode) && attempt < retries {
+		if waitForRetry(ctx, resp, backoff, attempt) {
 			return newAPIError(resp.StatusCode, respBody), true
 		}
 		// Context was cancelled during retry wait.
@@ -345,6 +379,14 @@ func StreamSSEWithBody(ctx context.Context, c *Client, method, path string, body
 	}
 }
 
+// ScanSSE returns an iterator over the Server-Sent Events read from r. It is
+// for callers that issue the HTTP request themselves.
+func ScanSSE(ctx context.Context, r io.Reader) iter.Seq2[SSEEvent, error] {
+	return func(yield func(SSEEvent, error) bool) {
+		scanSSEStream(ctx, r, yield)
+	}
+}
+
 // scanSSEStream reads SSE events from a reader and yields them.
 func scanSSEStream(ctx context.Context, r io.Reader, yield func(SSEEvent, error) bool) {
 	scanner := bufio.NewScanner(r)
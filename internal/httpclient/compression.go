@@ -0,0 +1,248 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content encodings supported for request and response bodies.
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+)
+
+// acceptEncoding is the Accept-Encoding header sent when compression is
+// enabled, in order of preference.
+const acceptEncoding = "br, gzip, deflate"
+
+// WithCompression enables or disables response compression. When enabled,
+// the default, the client advertises gzip, deflate, and brotli support and
+// transparently decompresses responses, so DoJSON decodes and StreamSSE
+// scans the decompressed stream. Disabling it requests uncompressed
+// responses, which is useful when inspecting traffic while debugging.
+//
+// A request that sets its own Accept-Encoding header, for example from a
+// middleware, receives the response body as sent by the server.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// WithRequestCompression compresses request bodies with encoding, one of
+// EncodingGzip, EncodingDeflate, or EncodingBrotli, and sets the
+// Content-Encoding header accordingly. Streamed bodies, such as DoMultipart
+// uploads, are not compressed. Only use it with servers known to
+// accept compressed requests; by default request bodies are sent
+// uncompressed.
+func WithRequestCompression(encoding string) Option {
+	return func(c *Client) {
+		c.requestEncoding = encoding
+	}
+}
+
+// applyCompression wraps the client's transport with request compression
+// and response decompression.
+func (c *Client) applyCompression() {
+	c.http.Transport = &compressionTransport{
+		next:            c.transport(),
+		negotiate:       c.compression,
+		requestEncoding: c.requestEncoding,
+	}
+}
+
+// compressionTransport compresses request bodies and negotiates and
+// decompresses response bodies.
+type compressionTransport struct {
+	next            http.RoundTripper
+	negotiate       bool
+	requestEncoding string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var err error
+	if req, err = t.compressRequest(req); err != nil {
+		return nil, err
+	}
+
+	decode := false
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = cloneRequest(req)
+		if t.negotiate {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+			decode = true
+		} else {
+			// Prevent the transport from negotiating gzip on its own.
+			req.Header.Set("Accept-Encoding", "identity")
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !decode {
+		return resp, err
+	}
+	return decompressResponse(resp)
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *compressionTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+// compressRequest returns req with its body compressed, if request
+// compression is enabled and the body is not already encoded.
+func (t *compressionTransport) compressRequest(req *http.Request) (*http.Request, error) {
+	// Streamed bodies, which cannot be replayed, are sent as is rather than
+	// buffered for compression.
+	if t.requestEncoding == "" || req.Body == nil || req.Body == http.NoBody || req.GetBody == nil || req.Header.Get("Content-Encoding") != "" {
+		return req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close() // #nosec G104 -- body fully read
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: read request body: %w", err)
+	}
+	compressed, err := compress(t.requestEncoding, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req = cloneRequest(req)
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", t.requestEncoding)
+	return req, nil
+}
+
+// cloneRequest returns a shallow copy of req with its own headers, as
+// RoundTrippers must not modify the caller's request.
+func cloneRequest(req *http.Request) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.Header = req.Header.Clone()
+	return r
+}
+
+// compress encodes data with the named content encoding.
+func compress(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case EncodingGzip:
+		w = gzip.NewWriter(&buf)
+	case EncodingDeflate:
+		w = zlib.NewWriter(&buf)
+	case EncodingBrotli:
+		w = brotli.NewWriter(&buf)
+	default:
+		return nil, fmt.Errorf("httpclient: unsupported request encoding %q", encoding)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("httpclient: compress request body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("httpclient: compress request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressResponse replaces the body of resp with a decoder for its
+// Content-Encoding. Responses with other encodings are returned unchanged.
+func decompressResponse(resp *http.Response) (*http.Response, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case EncodingGzip, "x-gzip", EncodingDeflate, EncodingBrotli:
+	default:
+		return resp, nil
+	}
+
+	resp.Body = &decodingReader{body: resp.Body, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decodingReader decompresses a response body. The decoder is created on
+// the first Read, so that it reads the stream header only when the caller
+// consumes the body; this keeps empty bodies and slow event streams from
+// failing or blocking early.
+type decodingReader struct {
+	body     io.ReadCloser
+	encoding string
+	r        io.Reader
+	err      error
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		d.r, d.err = newDecoder(d.encoding, d.body)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.r.Read(p)
+}
+
+func (d *decodingReader) Close() error {
+	if c, ok := d.r.(io.Closer); ok {
+		_ = c.Close() // #nosec G104 -- the underlying body is closed below
+	}
+	return d.body.Close()
+}
+
+// newDecoder returns a reader decompressing r with the named encoding.
+func newDecoder(encoding string, r io.Reader) (io.Reader, error) {
+	switch encoding {
+	case EncodingBrotli:
+		return brotli.NewReader(r), nil
+	case EncodingDeflate:
+		// "deflate" is specified as zlib-wrapped, but some servers send raw
+		// deflate data; tell them apart by the zlib header.
+		br := bufio.NewReader(r)
+		header, err := br.Peek(2)
+		if err == io.EOF && len(header) == 0 {
+			return nil, io.EOF
+		}
+		if err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("httpclient: deflate response: %w", err)
+			}
+			return zr, nil
+		}
+		return flate.NewReader(br), nil
+	default:
+		gr, err := gzip.NewReader(r)
+		if err == io.EOF {
+			// Empty body.
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("httpclient: gzip response: %w", err)
+		}
+		return gr, nil
+	}
+}
+
+// isZlibHeader reports whether b starts a zlib stream: deflate compression
+// and a header checksum divisible by 31.
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
@@ -0,0 +1,231 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodingWriter returns a compressing writer for encoding, with raw
+// selecting raw deflate instead of zlib.
+func encodingWriter(t *testing.T, encoding string, w io.Writer, raw bool) io.WriteCloser {
+	t.Helper()
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w)
+	case EncodingDeflate:
+		if raw {
+			fw, err := flate.NewWriter(w, flate.DefaultCompression)
+			require.NoError(t, err)
+			return fw
+		}
+		return zlib.NewWriter(w)
+	case EncodingBrotli:
+		return brotli.NewWriter(w)
+	}
+	t.Fatalf("unknown encoding %q", encoding)
+	return nil
+}
+
+// compressedServer serves body compressed with encoding and records the
+// Accept-Encoding header of the last request.
+func compressedServer(t *testing.T, encoding string, raw bool, body string, accept *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*accept = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", encoding)
+		zw := encodingWriter(t, encoding, w, raw)
+		io.WriteString(zw, body)
+		zw.Close()
+	}))
+}
+
+func TestCompression_DoJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		raw      bool
+	}{
+		{name: "gzip", encoding: EncodingGzip},
+		{name: "deflate", encoding: EncodingDeflate},
+		{name: "raw deflate", encoding: EncodingDeflate, raw: true},
+		{name: "brotli", encoding: EncodingBrotli},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accept string
+			srv := compressedServer(t, tt.encoding, tt.raw, `{"name":"compressed","value":7}`, &accept)
+			defer srv.Close()
+
+			c := New(WithBaseURL(srv.URL))
+			resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			assert.Equal(t, testResponse{Name: "compressed", Value: 7}, resp)
+			assert.Equal(t, acceptEncoding, accept)
+		})
+	}
+}
+
+func TestCompression_ResponseHeaders(t *testing.T) {
+	var accept string
+	srv := compressedServer(t, EncodingGzip, false, "hello", &accept)
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	resp, err := c.Do(context.Background(), http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	assert.True(t, resp.Uncompressed)
+	assert.Equal(t, int64(-1), resp.ContentLength)
+}
+
+func TestCompression_EmptyBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", EncodingGzip)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	resp, err := c.Do(context.Background(), http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Empty(t, body)
+}
+
+func TestCompression_StreamSSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Encoding", EncodingGzip)
+		gw := gzip.NewWriter(w)
+		for i := range 3 {
+			fmt.Fprintf(gw, "data: chunk %d\n\n", i)
+			require.NoError(t, gw.Flush())
+			w.(http.Flusher).Flush()
+		}
+		gw.Close()
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	var data []string
+	for event, err := range StreamSSE(context.Background(), c, "/") {
+		require.NoError(t, err)
+		data = append(data, event.Data)
+	}
+	assert.Equal(t, []string{"chunk 0", "chunk 1", "chunk 2"}, data)
+}
+
+func TestCompression_Disabled(t *testing.T) {
+	var accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept-Encoding")
+		fmt.Fprint(w, `{"name":"plain"}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithCompression(false))
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "plain", resp.Name)
+	assert.Equal(t, "identity", accept)
+}
+
+func TestCompression_ExplicitAcceptEncoding(t *testing.T) {
+	var accept string
+	srv := compressedServer(t, EncodingGzip, false, "raw", &accept)
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	resp, err := c.Do(context.Background(), http.MethodGet, "/", nil, map[string]string{"Accept-Encoding": "gzip"})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The caller negotiated the encoding, so the body is left compressed.
+	assert.Equal(t, EncodingGzip, resp.Header.Get("Content-Encoding"))
+	gr, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, "raw", string(body))
+}
+
+func TestRequestCompression(t *testing.T) {
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli} {
+		t.Run(encoding, func(t *testing.T) {
+			var gotEncoding string
+			var gotBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotEncoding = r.Header.Get("Content-Encoding")
+				r2, err := newDecoder(gotEncoding, r.Body)
+				require.NoError(t, err)
+				gotBody, err = io.ReadAll(r2)
+				require.NoError(t, err)
+				fmt.Fprint(w, `{"name":"ok"}`)
+			}))
+			defer srv.Close()
+
+			c := New(WithBaseURL(srv.URL), WithRequestCompression(encoding))
+			_, err := DoJSON[testResponse](context.Background(), c, http.MethodPost, "/", map[string]string{"prompt": "hello"})
+			require.NoError(t, err)
+			assert.Equal(t, encoding, gotEncoding)
+			assert.JSONEq(t, `{"prompt":"hello"}`, string(gotBody))
+		})
+	}
+}
+
+func TestRequestCompression_MiddlewareSeesUncompressedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"ok"}`)
+	}))
+	defer srv.Close()
+
+	var logged []byte
+	logBody := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := req.GetBody()
+			require.NoError(t, err)
+			logged, _ = io.ReadAll(body)
+			return next.RoundTrip(req)
+		})
+	}
+	c := New(WithBaseURL(srv.URL), WithRequestCompression(EncodingGzip), WithMiddleware(logBody))
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodPost, "/", map[string]int{"n": 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"n":1}`, string(logged))
+}
+
+func TestRequestCompression_UnsupportedEncoding(t *testing.T) {
+	c := New(WithBaseURL("http://localhost"), WithRequestCompression("zstd"))
+	_, err := c.Do(context.Background(), http.MethodPost, "/", map[string]int{"n": 1}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported request encoding "zstd"`)
+}
+
+func TestIsZlibHeader(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte("x"))
+	zw.Close()
+	assert.True(t, isZlibHeader(buf.Bytes()[:2]))
+	assert.False(t, isZlibHeader([]byte{0x4b, 0xac}))
+}
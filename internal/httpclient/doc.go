@@ -20,6 +20,37 @@
 //	    httpclient.WithTimeout(30 * time.Second),
 //	)
 //
+// # Middleware
+//
+// [WithMiddleware] wraps the client's transport in a chain of [Middleware]
+// functions, each an http.RoundTripper decorator, for tracing headers,
+// request logging, or non-bearer auth such as refreshing an expiring token.
+// The first middleware is outermost. Middleware runs inside the retry loop,
+// so every attempt passes through the chain:
+//
+//	c := httpclient.New(httpclient.WithMiddleware(tracing, signRequest))
+//
+// # Compression
+//
+// The client negotiates compressed responses (brotli, gzip, and deflate) and
+// decompresses them transparently, beneath any middleware, so DoJSON,
+// StreamSSE, and middleware all see plain bodies. [WithCompression](false)
+// turns negotiation off for debugging, and [WithRequestCompression]
+// compresses request bodies for providers that accept them.
+//
+// # Circuit Breaking and Pool Metrics
+//
+// [WithCircuitBreaker] guards each host with a resilience.CircuitBreaker.
+// Once a host has failed repeatedly with network errors or 5xx responses,
+// DoJSON and StreamSSE fail fast with an error matching
+// resilience.ErrCircuitOpen rather than waiting for the timeout:
+//
+//	c := httpclient.New(httpclient.WithCircuitBreaker(5, 30*time.Second))
+//
+// Every client records connection pool usage, available from
+// [Client.Stats] and reported as o11y metrics: in-flight requests, new
+// versus reused connections, and DNS lookup time.
+//
 // # Typed JSON Requests
 //
 // The [DoJSON] generic function sends an HTTP request with a JSON body and
@@ -29,6 +60,21 @@
 //	type Response struct { Result string `json:"result"` }
 //	resp, err := httpclient.DoJSON[Response](ctx, client, "POST", "/chat", reqBody)
 //
+// # Multipart Uploads
+//
+// [DoMultipart] streams a multipart/form-data body built from [Part]
+// values, such as audio for transcription or images for vision models,
+// without buffering files in memory, and reports progress as the body is
+// sent. Uploads are only retried when every part can be reopened, as with
+// [FieldPart] and [LocalFilePart]; since a retried upload may repeat a
+// non-idempotent operation, disable retries where that matters:
+//
+//	resp, err := httpclient.DoMultipart[Transcription](ctx, client, "POST", "/audio/transcriptions",
+//	    []httpclient.Part{
+//	        httpclient.FieldPart("model", "whisper-1"),
+//	        httpclient.FilePart("file", "speech.wav", "audio/wav", audio),
+//	    }, nil)
+//
 // # Server-Sent Events
 //
 // The [StreamSSE] and [StreamSSEWithBody] functions open SSE connections and
@@ -50,6 +96,43 @@
 //	defer ws.Close()
 //	err = ws.WriteJSON(ctx, request)
 //
+// [AcceptWS] is the server-side counterpart, upgrading an incoming request.
+//
+// For long-lived realtime sessions, [WithReconnect] makes a dialed WSConn
+// replace a dropped connection transparently, [WithResubscribe] replays
+// initialization messages on the new connection, and WriteJSON waits
+// briefly for the reconnection instead of failing. [WithPingInterval] and
+// [WithReadTimeout] detect dead connections, and [WithStateChange] reports
+// connected, reconnecting and closed transitions:
+//
+//	ws, err := httpclient.DialWS(ctx, url, headers,
+//	    httpclient.WithReconnect(5, time.Second),
+//	    httpclient.WithResubscribe(func(ctx context.Context, c *httpclient.WSConn) error {
+//	        return c.WriteJSON(ctx, sessionConfig)
+//	    }),
+//	    httpclient.WithPingInterval(15*time.Second),
+//	)
+//
+// # HTTP/2 and gRPC
+//
+// [WithHTTP2] switches the client to HTTP/2: https:// URLs negotiate h2 via
+// TLS ALPN with HTTP/1.1 fallback, and http:// URLs use h2c with prior
+// knowledge. DoJSON and StreamSSE work unchanged, and concurrent requests to
+// a host are multiplexed over a single reused connection. Idle connections
+// are health-checked with PING frames after 30 seconds, closed if the ping
+// goes unanswered for 15 seconds, and dropped after 90 seconds idle:
+//
+//	c := httpclient.New(httpclient.WithBaseURL(url), httpclient.WithHTTP2())
+//
+// [Client.DialGRPC] creates a gRPC connection with the client's retry and
+// backoff semantics, retrying Unavailable and ResourceExhausted, and its
+// default headers sent as metadata. Keep-alive pings are tuned with
+// grpc.WithKeepaliveParams:
+//
+//	conn, err := c.DialGRPC("api.example.com:443",
+//	    grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 30 * time.Second}),
+//	)
+//
 // # Error Handling
 //
 // API errors are returned as [*APIError] with the HTTP status code and
@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DialGRPC creates a gRPC client connection to target that behaves like the
+// client's HTTP requests: calls failing with Unavailable or
+// ResourceExhausted (the gRPC counterparts of 503 and 429) are retried up to
+// the client's retry count with the same exponential backoff and jitter,
+// and the client's default headers, such as the bearer token, are sent as
+// metadata. For streaming calls only opening the stream is retried.
+//
+// Connections use TLS with the system roots unless opts sets other
+// transport credentials, such as insecure.NewCredentials() for a plaintext
+// local server. gRPC runs over HTTP/2, multiplexing all calls on one
+// connection; use grpc.WithKeepaliveParams in opts to tune keep-alive
+// pings. The connection is established lazily on the first call.
+func (c *Client) DialGRPC(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(nil)),
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(c.streamInterceptor),
+	}
+	// Later options take precedence, so callers can replace the credentials.
+	dialOpts = append(dialOpts, opts...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: grpc dial: %w", err)
+	}
+	return conn, nil
+}
+
+// unaryInterceptor adds the default headers to unary calls and retries
+// them.
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = c.outgoingMetadata(ctx)
+	for attempt := 0; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !c.shouldRetryGRPC(ctx, err, attempt) {
+			return err
+		}
+	}
+}
+
+// streamInterceptor adds the default headers to streaming calls and
+// retries opening the stream.
+func (c *Client) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx = c.outgoingMetadata(ctx)
+	for attempt := 0; ; attempt++ {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if !c.shouldRetryGRPC(ctx, err, attempt) {
+			return stream, err
+		}
+	}
+}
+
+// shouldRetryGRPC reports whether a call that failed with err should be
+// retried, waiting for the backoff delay if so.
+func (c *Client) shouldRetryGRPC(ctx context.Context, err error, attempt int) bool {
+	if err == nil || attempt >= c.retries {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return waitForRetry(ctx, nil, c.backoff, attempt)
+	default:
+		return false
+	}
+}
+
+// outgoingMetadata adds the client's default headers to the outgoing
+// metadata of ctx. gRPC metadata keys are lowercase.
+func (c *Client) outgoingMetadata(ctx context.Context) context.Context {
+	if len(c.headers) == 0 {
+		return ctx
+	}
+	kv := make([]string, 0, 2*len(c.headers))
+	for k, v := range c.headers {
+		kv = append(kv, strings.ToLower(k), v)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
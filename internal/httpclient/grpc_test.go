@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// flakyHealthServer fails the first failures calls with code.
+type flakyHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	failures int32
+	code     codes.Code
+	calls    atomic.Int32
+	auth     atomic.Value
+}
+
+func (s *flakyHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			s.auth.Store(v[0])
+		}
+	}
+	if s.calls.Add(1) <= s.failures {
+		return nil, status.Error(s.code, "try again")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *flakyHealthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+// dialFlaky serves hs over an in-memory listener and dials it with c.
+func dialFlaky(t *testing.T, c *Client, hs *flakyHealthServer) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := c.DialGRPC("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestDialGRPC_RetriesUnavailable(t *testing.T) {
+	hs := &flakyHealthServer{failures: 2, code: codes.Unavailable}
+	c := New(WithRetries(3), WithBackoff(time.Millisecond), WithBearerToken("tok"))
+	client := dialFlaky(t, c, hs)
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	assert.Equal(t, int32(3), hs.calls.Load())
+	assert.Equal(t, "Bearer tok", hs.auth.Load())
+}
+
+func TestDialGRPC_RetriesExhausted(t *testing.T) {
+	hs := &flakyHealthServer{failures: 10, code: codes.ResourceExhausted}
+	c := New(WithRetries(2), WithBackoff(time.Millisecond))
+	client := dialFlaky(t, c, hs)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, int32(3), hs.calls.Load())
+}
+
+func TestDialGRPC_NoRetryOnOtherCodes(t *testing.T) {
+	hs := &flakyHealthServer{failures: 10, code: codes.InvalidArgument}
+	c := New(WithRetries(3), WithBackoff(time.Millisecond))
+	client := dialFlaky(t, c, hs)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, int32(1), hs.calls.Load())
+}
+
+func TestDialGRPC_ContextCanceledDuringBackoff(t *testing.T) {
+	hs := &flakyHealthServer{failures: 10, code: codes.Unavailable}
+	c := New(WithRetries(5), WithBackoff(time.Hour))
+	client := dialFlaky(t, c, hs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), hs.calls.Load())
+}
+
+func TestDialGRPC_Stream(t *testing.T) {
+	hs := &flakyHealthServer{}
+	c := New(WithBearerToken("tok"))
+	client := dialFlaky(t, c, hs)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
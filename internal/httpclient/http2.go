@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTP/2 keep-alive defaults used by WithHTTP2.
+const (
+	// http2PingInterval is how long a connection may be idle before a PING
+	// frame checks that it is still alive.
+	http2PingInterval = 30 * time.Second
+
+	// http2PingTimeout is how long to wait for the PING response before
+	// closing the connection.
+	http2PingTimeout = 15 * time.Second
+
+	// idleConnTimeout is how long an idle connection is kept for reuse.
+	idleConnTimeout = 90 * time.Second
+)
+
+// WithHTTP2 configures the client for HTTP/2. Requests to https:// URLs
+// negotiate HTTP/2 through TLS ALPN, falling back to HTTP/1.1 for servers
+// without HTTP/2 support. Requests to http:// URLs use unencrypted HTTP/2
+// (h2c) with prior knowledge, so the server must accept h2c.
+//
+// Over HTTP/2 all requests to a host share one connection, multiplexing
+// concurrent requests and streams, including DoJSON and StreamSSE calls,
+// without the head-of-line blocking of HTTP/1.1 connection pools. Idle
+// connections are pinged after 30 seconds and closed if the ping is not
+// answered within 15 seconds, so dead connections are detected before a
+// request is sent on them; connections idle for 90 seconds are closed.
+func WithHTTP2() Option {
+	return func(c *Client) {
+		c.http.Transport = newHTTP2Transport()
+	}
+}
+
+// http2Transport sends requests over HTTP/2, choosing the transport by URL
+// scheme: h2 with HTTP/1.1 fallback over TLS, h2c otherwise.
+type http2Transport struct {
+	tls *http.Transport
+	h2c *http.Transport
+}
+
+// newHTTP2Transport creates an http2Transport with the package's keep-alive
+// defaults.
+func newHTTP2Transport() *http2Transport {
+	newTransport := func(protocols *http.Protocols) *http.Transport {
+		return &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     idleConnTimeout,
+			MaxIdleConns:        100,
+			Protocols:           protocols,
+			HTTP2: &http.HTTP2Config{
+				SendPingTimeout: http2PingInterval,
+				PingTimeout:     http2PingTimeout,
+			},
+		}
+	}
+
+	tlsProtocols := new(http.Protocols)
+	tlsProtocols.SetHTTP1(true)
+	tlsProtocols.SetHTTP2(true)
+	h2cProtocols := new(http.Protocols)
+	h2cProtocols.SetUnencryptedHTTP2(true)
+
+	return &http2Transport{
+		tls: newTransport(tlsProtocols),
+		h2c: newTransport(h2cProtocols),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *http2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return t.h2c.RoundTrip(req)
+	}
+	return t.tls.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of both transports.
+func (t *http2Transport) CloseIdleConnections() {
+	t.tls.CloseIdleConnections()
+	t.h2c.CloseIdleConnections()
+}
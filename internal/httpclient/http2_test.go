@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// protoHandler serves a JSON response and an SSE stream reporting the
+// request's protocol.
+func protoHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name":%q,"value":%d}`, r.Proto, r.ProtoMajor)
+	})
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\ndata: done\n\n", r.Proto)
+	})
+	return mux
+}
+
+func TestWithHTTP2_TLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(protoHandler())
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithHTTP2())
+	// Trust the test server's certificate.
+	h2Transport(c).tls.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", resp.Name)
+
+	var data []string
+	for event, err := range StreamSSE(context.Background(), c, "/sse") {
+		require.NoError(t, err)
+		data = append(data, event.Data)
+	}
+	assert.Equal(t, []string{"HTTP/2.0", "done"}, data)
+}
+
+func TestWithHTTP2_TLSFallback(t *testing.T) {
+	srv := httptest.NewTLSServer(protoHandler())
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithHTTP2())
+	h2Transport(c).tls.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", resp.Name)
+}
+
+func TestWithHTTP2_H2C(t *testing.T) {
+	srv := httptest.NewUnstartedServer(protoHandler())
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithHTTP2())
+
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", resp.Name)
+
+	var data []string
+	for event, err := range StreamSSE(context.Background(), c, "/sse") {
+		require.NoError(t, err)
+		data = append(data, event.Data)
+	}
+	assert.Equal(t, []string{"HTTP/2.0", "done"}, data)
+
+	c.http.CloseIdleConnections()
+}
+
+// h2Transport returns the HTTP/2 transport beneath the client's compression
+// and metrics layers.
+func h2Transport(c *Client) *http2Transport {
+	return c.http.Transport.(*compressionTransport).next.(*metricsTransport).next.(*http2Transport)
+}
@@ -0,0 +1,52 @@
+package httpclient
+
+import "net/http"
+
+// Middleware wraps an http.RoundTripper to intercept requests and responses,
+// for example to add tracing headers, log bodies, or refresh an expiring
+// token.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to the http.RoundTripper interface.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middleware to the client's transport. Middlewares are
+// applied outside-in: the first middleware sees each request first and its
+// response last. Repeated WithMiddleware options append to the chain.
+//
+// Middleware runs inside the retry loop of DoJSON, so each attempt passes
+// through the whole chain and a retried request carries freshly injected
+// headers. A middleware may resend a request itself, such as after
+// refreshing a token on a 401; request bodies can be replayed with
+// req.GetBody.
+//
+//	refresh := func(next http.RoundTripper) http.RoundTripper {
+//	    return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//	        req = req.Clone(req.Context())
+//	        req.Header.Set("X-Api-Token", tokens.Current())
+//	        return next.RoundTrip(req)
+//	    })
+//	}
+//	c := httpclient.New(httpclient.WithMiddleware(refresh))
+func WithMiddleware(mws ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mws...)
+	}
+}
+
+// applyMiddleware wraps the client's transport with its middleware.
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 {
+		return
+	}
+	rt := c.transport()
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	c.http.Transport = rt
+}
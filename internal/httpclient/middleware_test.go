@@ -0,0 +1,163 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordMiddleware appends name to order on each request and response.
+func recordMiddleware(name string, order *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*order = append(*order, name+":req")
+			resp, err := next.RoundTrip(req)
+			*order = append(*order, name+":resp")
+			return resp, err
+		})
+	}
+}
+
+func TestWithMiddleware_Order(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"ok","value":1}`)
+	}))
+	defer srv.Close()
+
+	var order []string
+	c := New(
+		WithBaseURL(srv.URL),
+		WithMiddleware(recordMiddleware("a", &order), recordMiddleware("b", &order)),
+		WithMiddleware(recordMiddleware("c", &order)),
+	)
+
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:req", "b:req", "c:req", "c:resp", "b:resp", "a:resp"}, order)
+}
+
+func TestWithMiddleware_InjectsHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":%q}`, r.Header.Get("X-Trace-Id"))
+	}))
+	defer srv.Close()
+
+	trace := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return next.RoundTrip(req)
+		})
+	}
+	c := New(WithBaseURL(srv.URL), WithMiddleware(trace))
+
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "trace-1", resp.Name)
+}
+
+func TestWithMiddleware_RunsOnEachRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"name":"ok"}`)
+	}))
+	defer srv.Close()
+
+	var seen atomic.Int32
+	count := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			seen.Add(1)
+			return next.RoundTrip(req)
+		})
+	}
+	c := New(WithBaseURL(srv.URL), WithRetries(3), WithBackoff(time.Millisecond), WithMiddleware(count))
+
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), seen.Load())
+}
+
+func TestWithMiddleware_RefreshTokenAndResend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Token") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"name":%q}`, body)
+	}))
+	defer srv.Close()
+
+	token := "stale"
+	refresh := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			send := func() (*http.Response, error) {
+				r := req.Clone(req.Context())
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					r.Body = body
+				}
+				r.Header.Set("X-Api-Token", token)
+				return next.RoundTrip(r)
+			}
+			resp, err := send()
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+			resp.Body.Close()
+			token = "fresh"
+			return send()
+		})
+	}
+	c := New(WithBaseURL(srv.URL), WithMiddleware(refresh))
+
+	resp, err := DoJSON[testResponse](context.Background(), c, http.MethodPost, "/", map[string]string{"q": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"q":"hi"}`, resp.Name)
+}
+
+func TestWithMiddleware_StreamSSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", r.Header.Get("X-Trace-Id"))
+	}))
+	defer srv.Close()
+
+	trace := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Trace-Id", "trace-2")
+			return next.RoundTrip(req)
+		})
+	}
+	c := New(WithBaseURL(srv.URL), WithMiddleware(trace))
+
+	var data []string
+	for event, err := range StreamSSE(context.Background(), c, "/") {
+		require.NoError(t, err)
+		data = append(data, event.Data)
+	}
+	assert.Equal(t, []string{"trace-2"}, data)
+}
+
+func TestWithMiddleware_WrapsHTTP2Transport(t *testing.T) {
+	var order []string
+	c := New(WithMiddleware(recordMiddleware("a", &order)), WithHTTP2())
+	_, isH2 := c.http.Transport.(*http2Transport)
+	assert.False(t, isH2, "middleware should wrap the HTTP/2 transport regardless of option order")
+}
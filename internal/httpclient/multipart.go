@@ -0,0 +1,219 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Part is one part of a multipart/form-data request body. Its content is
+// streamed from Content, or from the reader returned by Open, without
+// buffering it in memory.
+type Part struct {
+	// Name is the form field name.
+	Name string
+	// FileName, if set, makes the part a file upload.
+	FileName string
+	// ContentType is the part's media type. File parts default to
+	// application/octet-stream.
+	ContentType string
+	// Content is read once to produce the part's content.
+	Content io.Reader
+	// Open, if set instead of Content, opens the part's content for each
+	// attempt, allowing the upload to be retried.
+	Open func() (io.ReadCloser, error)
+}
+
+// FieldPart returns a plain form field part.
+func FieldPart(name, value string) Part {
+	return Part{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(value)), nil
+		},
+	}
+}
+
+// FilePart returns a file upload part streamed from r. The upload cannot
+// be retried, as r can be read only once.
+func FilePart(name, fileName, contentType string, r io.Reader) Part {
+	return Part{Name: name, FileName: filepath.Base(fileName), ContentType: contentType, Content: r}
+}
+
+// LocalFilePart returns a file upload part streamed from the file at path,
+// reopened for every attempt.
+func LocalFilePart(name, path, contentType string) Part {
+	return Part{
+		Name:        name,
+		FileName:    filepath.Base(path),
+		ContentType: contentType,
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path) // #nosec G304 -- the caller chooses the file to upload
+		},
+	}
+}
+
+// ProgressFunc is called as a request body is sent, with the number of
+// bytes sent so far in the current attempt.
+type ProgressFunc func(sent int64)
+
+// DoMultipart sends a multipart/form-data request streaming parts and
+// decodes the JSON response into T. Parts are encoded while the request is
+// sent, so large files are never held in memory. progress, if not nil, is
+// called as the body is sent.
+//
+// Uploads are retried like DoJSON, on 429 and 503 responses and network
+// errors, but only when every part can be replayed, that is each part sets
+// Open rather than Content; otherwise a single attempt is made. An upload
+// that failed with a network error may still have been processed by the
+// server, so a retry can repeat a non-idempotent operation. Disable retries
+// with WithRetries(0), or use Content readers, where that is unacceptable.
+// On retry the progress count restarts from zero.
+func DoMultipart[T any](ctx context.Context, c *Client, method, path string, parts []Part, progress ProgressFunc) (T, error) {
+	var zero T
+
+	retries := c.retries
+	for _, p := range parts {
+		if p.Open == nil {
+			retries = 0
+			break
+		}
+	}
+
+	for attempt := range retries + 1 {
+		resp, err := c.sendMultipart(ctx, method, path, newMultipartBody(parts, progress))
+		if err != nil {
+			if shouldRetryNetErr(ctx, c.backoff, retries, err, attempt) {
+				continue
+			}
+			return zero, err
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			result, err, _ := decodeJSONResponse[T](resp)
+			return result, err
+		}
+
+		apiErr, shouldRetry := handleErrorResponse(ctx, c.backoff, retries, resp, attempt)
+		if !shouldRetry {
+			return zero, apiErr
+		}
+	}
+
+	return zero, fmt.Errorf("httpclient: exhausted retries")
+}
+
+// multipartBody is a request body that encodes parts as it is read.
+type multipartBody struct {
+	contentType string
+	io.ReadCloser
+}
+
+// newMultipartBody starts encoding parts into a pipe read by the request.
+// Encoding stops when the request closes the body.
+func newMultipartBody(parts []Part, progress ProgressFunc) *multipartBody {
+	pr, pw := io.Pipe()
+	var w io.Writer = pw
+	if progress != nil {
+		w = &progressWriter{w: pw, progress: progress}
+	}
+	mw := multipart.NewWriter(w)
+
+	go func() {
+		err := writeParts(mw, parts)
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return &multipartBody{contentType: mw.FormDataContentType(), ReadCloser: pr}
+}
+
+// writeParts writes parts to mw.
+func writeParts(mw *multipart.Writer, parts []Part) error {
+	for _, p := range parts {
+		if err := writePart(mw, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePart writes a single part to mw.
+func writePart(mw *multipart.Writer, p Part) error {
+	h := make(textproto.MIMEHeader)
+	if p.FileName != "" {
+		h.Set("Content-Disposition", multipart.FileContentDisposition(p.Name, p.FileName))
+		contentType := p.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h.Set("Content-Type", contentType)
+	} else {
+		h.Set("Content-Disposition", `form-data; name="`+quoteEscaper.Replace(p.Name)+`"`)
+		if p.ContentType != "" {
+			h.Set("Content-Type", p.ContentType)
+		}
+	}
+	w, err := mw.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("httpclient: multipart part %q: %w", p.Name, err)
+	}
+
+	content := p.Content
+	if p.Open != nil {
+		rc, err := p.Open()
+		if err != nil {
+			return fmt.Errorf("httpclient: multipart part %q: %w", p.Name, err)
+		}
+		defer rc.Close()
+		content = rc
+	}
+	if content == nil {
+		return nil
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		return fmt.Errorf("httpclient: multipart part %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// quoteEscaper escapes a quoted Content-Disposition parameter value.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// progressWriter reports the bytes written through it.
+type progressWriter struct {
+	w        io.Writer
+	sent     int64
+	progress ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.progress(p.sent)
+	}
+	return n, err
+}
+
+// sendMultipart sends a request with a multipart body and the client's
+// default headers.
+func (c *Client) sendMultipart(ctx context.Context, method, path string, body *multipartBody) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), body)
+	if err != nil {
+		_ = body.Close() // #nosec G104 -- stops the encoder
+		return nil, fmt.Errorf("httpclient: create request: %w", err)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", body.contentType)
+	return c.http.Do(req)
+}
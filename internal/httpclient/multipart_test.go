@@ -0,0 +1,177 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadResponse echoes what an upload handler received.
+type uploadResponse struct {
+	Model    string `json:"model"`
+	FileName string `json:"file_name"`
+	Type     string `json:"type"`
+	Size     int    `json:"size"`
+}
+
+// uploadHandler parses a multipart request with a "model" field and a
+// "file" part, and echoes them.
+func uploadHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var resp uploadResponse
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			data, err := io.ReadAll(p)
+			require.NoError(t, err)
+			switch p.FormName() {
+			case "model":
+				resp.Model = string(data)
+			case "file":
+				resp.FileName = p.FileName()
+				resp.Type = p.Header.Get("Content-Type")
+				resp.Size = len(data)
+			}
+		}
+		fmt.Fprintf(w, `{"model":%q,"file_name":%q,"type":%q,"size":%d}`, resp.Model, resp.FileName, resp.Type, resp.Size)
+	}
+}
+
+func TestDoMultipart(t *testing.T) {
+	srv := httptest.NewServer(uploadHandler(t))
+	defer srv.Close()
+
+	audio := bytes.Repeat([]byte("a"), 1<<20)
+	var sent atomic.Int64
+	c := New(WithBaseURL(srv.URL))
+	resp, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/transcriptions",
+		[]Part{
+			FieldPart("model", "whisper-1"),
+			FilePart("file", "dir/speech.wav", "audio/wav", bytes.NewReader(audio)),
+		},
+		func(n int64) { sent.Store(n) },
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uploadResponse{Model: "whisper-1", FileName: "speech.wav", Type: "audio/wav", Size: len(audio)}, resp)
+	assert.Greater(t, sent.Load(), int64(len(audio)))
+}
+
+func TestDoMultipart_DefaultFileContentType(t *testing.T) {
+	srv := httptest.NewServer(uploadHandler(t))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	resp, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/",
+		[]Part{FilePart("file", "image.bin", "", strings.NewReader("xyz"))}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "application/octet-stream", resp.Type)
+}
+
+func TestDoMultipart_RetriesReplayableParts(t *testing.T) {
+	var calls atomic.Int32
+	upload := uploadHandler(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		upload(w, r)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "clip.mp3")
+	require.NoError(t, os.WriteFile(path, []byte("mp3 data"), 0o600))
+
+	c := New(WithBaseURL(srv.URL), WithRetries(2), WithBackoff(time.Millisecond))
+	resp, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/",
+		[]Part{
+			FieldPart("model", "whisper-1"),
+			LocalFilePart("file", path, "audio/mpeg"),
+		}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uploadResponse{Model: "whisper-1", FileName: "clip.mp3", Type: "audio/mpeg", Size: 8}, resp)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestDoMultipart_NoRetryForStreamedParts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"message":"overloaded"}}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithRetries(3), WithBackoff(time.Millisecond))
+	_, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/",
+		[]Part{FilePart("file", "a.wav", "audio/wav", strings.NewReader("data"))}, nil)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "overloaded", apiErr.Message)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDoMultipart_OpenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	_, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/",
+		[]Part{LocalFilePart("file", filepath.Join(t.TempDir(), "missing.wav"), "audio/wav")}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `multipart part "file"`)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestDoMultipart_Headers(t *testing.T) {
+	var auth, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		io.Copy(io.Discard, r.Body)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithBearerToken("tok"), WithRequestCompression(EncodingGzip))
+	_, err := DoMultipart[uploadResponse](context.Background(), c, http.MethodPost, "/",
+		[]Part{FieldPart("a", "b")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer tok", auth)
+	assert.True(t, strings.HasPrefix(contentType, "multipart/form-data; boundary="))
+}
+
+func TestWritePart_QuotedFieldName(t *testing.T) {
+	var buf bytes.Buffer
+	body := newMultipartBody([]Part{FieldPart(`we"ird`, "v")}, nil)
+	_, err := io.Copy(&buf, body)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `Content-Disposition: form-data; name="we\"ird"`)
+}
@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+)
+
+// PoolStats is a snapshot of a Client's connection usage since creation.
+type PoolStats struct {
+	// InFlight is the number of requests whose response body has not yet
+	// been closed.
+	InFlight int64
+	// Requests counts requests sent, including retries.
+	Requests uint64
+	// NewConns and ReusedConns count the connections requests were sent
+	// on, split by whether the connection was newly dialed or reused from
+	// the pool.
+	NewConns    uint64
+	ReusedConns uint64
+	// DNSLookups counts DNS lookups and DNSTime is their total duration.
+	DNSLookups uint64
+	DNSTime    time.Duration
+}
+
+// Stats returns a snapshot of the client's connection pool usage. The same
+// figures are reported as o11y metrics, tagged with the request's host:
+// "httpclient.requests.in_flight", "httpclient.connections" (with a
+// boolean "reused" attribute) and "httpclient.dns.duration" in
+// milliseconds.
+func (c *Client) Stats() PoolStats {
+	s := c.stats
+	return PoolStats{
+		InFlight:    s.inFlight.Load(),
+		Requests:    s.requests.Load(),
+		NewConns:    s.newConns.Load(),
+		ReusedConns: s.reusedConns.Load(),
+		DNSLookups:  s.dnsLookups.Load(),
+		DNSTime:     time.Duration(s.dnsTime.Load()),
+	}
+}
+
+// poolStats holds the counters reported by Client.Stats.
+type poolStats struct {
+	inFlight    atomic.Int64
+	requests    atomic.Uint64
+	newConns    atomic.Uint64
+	reusedConns atomic.Uint64
+	dnsLookups  atomic.Uint64
+	dnsTime     atomic.Int64
+}
+
+// applyMetrics wraps the client's transport to record connection pool
+// metrics.
+func (c *Client) applyMetrics() {
+	c.http.Transport = &metricsTransport{next: c.transport(), stats: c.stats}
+}
+
+// metricsTransport records connection pool statistics through
+// net/http/httptrace.
+type metricsTransport struct {
+	next  http.RoundTripper
+	stats *poolStats
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attrs := o11y.Attrs{"host": req.URL.Host}
+
+	var dnsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			d := time.Since(dnsStart)
+			t.stats.dnsLookups.Add(1)
+			t.stats.dnsTime.Add(int64(d))
+			o11y.HistogramWithAttrs(ctx, "httpclient.dns.duration", float64(d)/float64(time.Millisecond), attrs)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.stats.reusedConns.Add(1)
+			} else {
+				t.stats.newConns.Add(1)
+			}
+			o11y.CounterWithAttrs(ctx, "httpclient.connections", 1, o11y.Attrs{
+				"host":   req.URL.Host,
+				"reused": info.Reused,
+			})
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	t.stats.requests.Add(1)
+	t.stats.inFlight.Add(1)
+	o11y.UpDownCounterWithAttrs(ctx, "httpclient.requests.in_flight", 1, attrs)
+	done := sync.OnceFunc(func() {
+		t.stats.inFlight.Add(-1)
+		o11y.UpDownCounterWithAttrs(context.WithoutCancel(ctx), "httpclient.requests.in_flight", -1, attrs)
+	})
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	// The request stays in flight until its body, possibly a long-lived
+	// stream, is closed.
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: done}
+	return resp, nil
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *metricsTransport) CloseIdleConnections() {
+	closeIdleConnections(t.next)
+}
+
+// trackedBody calls done when the response body is closed.
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *trackedBody) Close() error {
+	defer b.done()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_ConnectionReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"ok"}`)
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	for range 3 {
+		_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+		require.NoError(t, err)
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(3), stats.Requests)
+	assert.Equal(t, uint64(1), stats.NewConns)
+	assert.Equal(t, uint64(2), stats.ReusedConns)
+	assert.Equal(t, int64(0), stats.InFlight)
+}
+
+func TestStats_InFlightUntilBodyClosed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: x\n\n")
+	}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL))
+	resp, err := c.Do(context.Background(), http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), c.Stats().InFlight)
+
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, int64(0), c.Stats().InFlight)
+
+	for _, err := range StreamSSE(context.Background(), c, "/") {
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), c.Stats().InFlight)
+	}
+	assert.Equal(t, int64(0), c.Stats().InFlight)
+}
+
+func TestStats_DNSLookups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"ok"}`)
+	}))
+	defer srv.Close()
+
+	// Address the server by name to force a DNS lookup.
+	c := New(WithBaseURL(strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)))
+	_, err := DoJSON[testResponse](context.Background(), c, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.DNSLookups)
+	assert.Positive(t, stats.DNSTime)
+}
+
+func TestStats_FailedRequest(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	c := New(WithBaseURL(addr))
+	_, err := c.Do(context.Background(), http.MethodGet, "/", nil, nil)
+	require.Error(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Requests)
+	assert.Equal(t, int64(0), stats.InFlight)
+}
@@ -3,19 +3,154 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/coder/websocket"
 )
 
-// WSConn wraps a WebSocket connection with typed JSON helpers.
+// WSState is the connection state of a WSConn.
+type WSState string
+
+const (
+	// WSStateConnected means the connection is open.
+	WSStateConnected WSState = "connected"
+
+	// WSStateReconnecting means the connection was lost and is being
+	// re-established.
+	WSStateReconnecting WSState = "reconnecting"
+
+	// WSStateClosed means the connection was closed, or could not be
+	// re-established.
+	WSStateClosed WSState = "closed"
+)
+
+// defaultWriteBufferTimeout is how long WriteJSON waits for a reconnection
+// by default.
+const defaultWriteBufferTimeout = 5 * time.Second
+
+// WSOption configures a WSConn opened by DialWS.
+type WSOption func(*wsOptions)
+
+// wsOptions holds the configuration of a WSConn.
+type wsOptions struct {
+	maxReconnects      int
+	reconnectBackoff   time.Duration
+	resubscribe        func(ctx context.Context, conn *WSConn) error
+	pingInterval       time.Duration
+	readTimeout        time.Duration
+	writeBufferTimeout time.Duration
+	onStateChange      func(WSState)
+}
+
+// WithReconnect enables automatic reconnection. When the connection drops,
+// for any reason other than a normal closure, the WSConn redials up to
+// maxAttempts times with exponential backoff from backoff, and ReadJSON and
+// WriteJSON continue on the new connection. Messages in flight when the
+// connection dropped are lost; use WithResubscribe to restore session
+// state.
+func WithReconnect(maxAttempts int, backoff time.Duration) WSOption {
+	return func(o *wsOptions) {
+		o.maxReconnects = maxAttempts
+		o.reconnectBackoff = backoff
+	}
+}
+
+// WithResubscribe sets a callback run on every new connection after a
+// reconnect, before other reads and writes resume, to replay initialization
+// messages such as session configuration or subscriptions. conn writes
+// directly to the new connection. If fn fails, the reconnection attempt
+// counts as failed.
+func WithResubscribe(fn func(ctx context.Context, conn *WSConn) error) WSOption {
+	return func(o *wsOptions) {
+		o.resubscribe = fn
+	}
+}
+
+// WithPingInterval sends a ping every interval and treats a pong not
+// received within the interval as a lost connection. Pongs are only
+// processed while a ReadJSON call is in progress, so pings suit connections
+// that are read continuously.
+func WithPingInterval(interval time.Duration) WSOption {
+	return func(o *wsOptions) {
+		o.pingInterval = interval
+	}
+}
+
+// WithReadTimeout sets a deadline for each ReadJSON call. A connection that
+// delivers no message within d is considered lost and closed, and
+// reconnected if WithReconnect is set.
+func WithReadTimeout(d time.Duration) WSOption {
+	return func(o *wsOptions) {
+		o.readTimeout = d
+	}
+}
+
+// WithWriteBufferTimeout sets how long WriteJSON waits for a reconnection
+// to complete before failing. Default is 5 seconds.
+func WithWriteBufferTimeout(d time.Duration) WSOption {
+	return func(o *wsOptions) {
+		o.writeBufferTimeout = d
+	}
+}
+
+// WithStateChange registers a callback invoked on every connection state
+// change. It is called while the connection's lock is held, so it must be
+// fast and must not call methods of the WSConn.
+func WithStateChange(fn func(WSState)) WSOption {
+	return func(o *wsOptions) {
+		o.onStateChange = fn
+	}
+}
+
+// errWSClosed is returned by reads and writes on a WSConn closed during a
+// reconnection.
+var errWSClosed = errors.New("connection closed")
+
+// WSConn wraps a WebSocket connection with typed JSON helpers. A WSConn
+// opened with WithReconnect transparently replaces a lost connection. It is
+// safe for one concurrent reader and any number of concurrent writers.
 type WSConn struct {
-	conn *websocket.Conn
+	url     string
+	headers http.Header
+	opts    wsOptions
+
+	// ctx bounds background work and is cancelled by Close.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	conn  *websocket.Conn // nil while reconnecting or after reconnecting failed
+	gen   uint64          // incremented for every lost connection
+	state WSState
+	// ready is closed when a reconnection completes or fails.
+	ready chan struct{}
+	// err is the reason the connection could not be re-established.
+	err        error
+	closed     bool
+	cancelPing context.CancelFunc
 }
 
 // DialWS opens a WebSocket connection.
-func DialWS(ctx context.Context, url string, headers http.Header) (*WSConn, error) {
+func DialWS(ctx context.Context, url string, headers http.Header, opts ...WSOption) (*WSConn, error) {
+	conn, err := dialWS(ctx, url, headers)
+	if err != nil {
+		return nil, err
+	}
+	ws := newWSConn(conn, opts)
+	ws.url = url
+	ws.headers = headers
+	ws.mu.Lock()
+	ws.startPingLocked()
+	ws.mu.Unlock()
+	return ws, nil
+}
+
+// dialWS dials a WebSocket connection.
+func dialWS(ctx context.Context, url string, headers http.Header) (*websocket.Conn, error) {
 	opts := &websocket.DialOptions{}
 	if headers != nil {
 		opts.HTTPHeader = headers
@@ -24,34 +159,311 @@ func DialWS(ctx context.Context, url string, headers http.Header) (*WSConn, erro
 	if err != nil {
 		return nil, fmt.Errorf("httpclient: websocket dial: %w", err)
 	}
-	return &WSConn{conn: conn}, nil
+	return conn, nil
 }
 
-// ReadJSON reads and decodes a JSON message from the WebSocket.
-func (ws *WSConn) ReadJSON(ctx context.Context, v any) error {
-	_, data, err := ws.conn.Read(ctx)
+// newWSConn creates a connected WSConn for conn.
+func newWSConn(conn *websocket.Conn, opts []WSOption) *WSConn {
+	o := wsOptions{writeBufferTimeout: defaultWriteBufferTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WSConn{
+		opts:   o,
+		ctx:    ctx,
+		cancel: cancel,
+		conn:   conn,
+		state:  WSStateConnected,
+		ready:  make(chan struct{}),
+	}
+}
+
+// AcceptWS upgrades an incoming HTTP request to a WebSocket connection on
+// the server side. Cross-origin requests are rejected unless their Origin
+// host matches one of originPatterns (path.Match syntax, e.g.
+// "*.example.com"). On failure the handshake has already written an HTTP
+// error response.
+func AcceptWS(w http.ResponseWriter, r *http.Request, originPatterns ...string) (*WSConn, error) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: originPatterns})
 	if err != nil {
-		return fmt.Errorf("httpclient: websocket read: %w", err)
+		return nil, fmt.Errorf("httpclient: websocket accept: %w", err)
 	}
-	if err := json.Unmarshal(data, v); err != nil {
-		return fmt.Errorf("httpclient: websocket unmarshal: %w", err)
+	return newWSConn(conn, nil), nil
+}
+
+// State returns the current connection state.
+func (ws *WSConn) State() WSState {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.state
+}
+
+// ReadJSON reads and decodes a JSON message from the WebSocket. With
+// WithReconnect, a lost connection is re-established and the read resumes
+// on the new connection.
+func (ws *WSConn) ReadJSON(ctx context.Context, v any) error {
+	for {
+		conn, gen, err := ws.current(ctx, 0)
+		if err != nil {
+			return fmt.Errorf("httpclient: websocket read: %w", err)
+		}
+
+		data, err := ws.read(ctx, conn)
+		if err != nil {
+			// Cancelling ctx closes the connection, so reconnect even
+			// though the caller gets the error.
+			if ws.reconnect(gen, err) && ctx.Err() == nil {
+				continue
+			}
+			return fmt.Errorf("httpclient: websocket read: %w", err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("httpclient: websocket unmarshal: %w", err)
+		}
+		return nil
 	}
-	return nil
 }
 
-// WriteJSON encodes and sends a JSON message over the WebSocket.
+// read reads a message from conn within the read timeout.
+func (ws *WSConn) read(ctx context.Context, conn *websocket.Conn) ([]byte, error) {
+	if ws.opts.readTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ws.opts.readTimeout)
+		defer cancel()
+	}
+	_, data, err := conn.Read(ctx)
+	return data, err
+}
+
+// WriteJSON encodes and sends a JSON message over the WebSocket. While a
+// WithReconnect connection is being re-established, WriteJSON waits for the
+// new connection for up to the write buffer timeout.
 func (ws *WSConn) WriteJSON(ctx context.Context, v any) error {
 	data, err := json.Marshal(v)
 	if err != nil {
 		return fmt.Errorf("httpclient: websocket marshal: %w", err)
 	}
-	if err := ws.conn.Write(ctx, websocket.MessageText, data); err != nil {
+	for {
+		conn, gen, err := ws.current(ctx, ws.opts.writeBufferTimeout)
+		if err != nil {
+			return fmt.Errorf("httpclient: websocket write: %w", err)
+		}
+		err = conn.Write(ctx, websocket.MessageText, data)
+		if err == nil {
+			return nil
+		}
+		if ws.reconnect(gen, err) && ctx.Err() == nil {
+			continue
+		}
 		return fmt.Errorf("httpclient: websocket write: %w", err)
 	}
-	return nil
 }
 
 // Close gracefully closes the WebSocket connection.
 func (ws *WSConn) Close() error {
-	return ws.conn.Close(websocket.StatusNormalClosure, "")
+	return ws.CloseWith(websocket.StatusNormalClosure, "")
+}
+
+// CloseWith closes the WebSocket connection with the given status code and
+// reason.
+func (ws *WSConn) CloseWith(code websocket.StatusCode, reason string) error {
+	ws.mu.Lock()
+	conn := ws.conn
+	if !ws.closed {
+		if ws.state == WSStateReconnecting {
+			close(ws.ready)
+		}
+		ws.closed = true
+		ws.cancel()
+		ws.stopPingLocked()
+		ws.setStateLocked(WSStateClosed)
+	}
+	ws.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close(code, reason)
+}
+
+// current returns the open connection and its generation, waiting for a
+// reconnection in progress. Writers pass a timeout bounding the wait.
+func (ws *WSConn) current(ctx context.Context, timeout time.Duration) (*websocket.Conn, uint64, error) {
+	var expired <-chan time.Time
+	for {
+		ws.mu.Lock()
+		conn, gen, ready, err := ws.conn, ws.gen, ws.ready, ws.err
+		closed := ws.closed
+		ws.mu.Unlock()
+
+		switch {
+		case conn != nil:
+			// A closed connection reports its own error on use.
+			return conn, gen, nil
+		case err != nil:
+			return nil, 0, err
+		case closed:
+			return nil, 0, errWSClosed
+		}
+
+		if expired == nil && timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-expired:
+			return nil, 0, fmt.Errorf("reconnection did not complete within %v", timeout)
+		}
+	}
+}
+
+// reconnect starts re-establishing connection generation gen, lost with
+// cause, and reports whether the caller should retry on a new connection.
+// Only the first caller to report a lost generation starts a reconnection.
+func (ws *WSConn) reconnect(gen uint64, cause error) bool {
+	if ws.opts.maxReconnects <= 0 || ws.url == "" || websocket.CloseStatus(cause) == websocket.StatusNormalClosure {
+		return false
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closed {
+		return false
+	}
+	if gen != ws.gen {
+		// Already reconnecting or reconnected.
+		return true
+	}
+
+	old := ws.conn
+	ws.conn = nil
+	ws.gen++
+	ws.ready = make(chan struct{})
+	ws.stopPingLocked()
+	ws.setStateLocked(WSStateReconnecting)
+	go func() {
+		_ = old.CloseNow() // #nosec G104 -- the connection is already lost
+	}()
+	go ws.redial(ws.gen, ws.ready, cause)
+	return true
+}
+
+// redial re-establishes the connection for generation gen, closing ready
+// when done.
+func (ws *WSConn) redial(gen uint64, ready chan struct{}, cause error) {
+	lastErr := cause
+	for attempt := range ws.opts.maxReconnects {
+		if !waitForRetry(ws.ctx, nil, ws.opts.reconnectBackoff, attempt) {
+			return
+		}
+		conn, err := ws.dialAndResubscribe()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		ws.mu.Lock()
+		if ws.closed || ws.gen != gen {
+			ws.mu.Unlock()
+			_ = conn.CloseNow() // #nosec G104 -- discarding an unused connection
+			return
+		}
+		ws.conn = conn
+		ws.setStateLocked(WSStateConnected)
+		ws.startPingLocked()
+		close(ready)
+		ws.mu.Unlock()
+		return
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closed || ws.gen != gen {
+		return
+	}
+	ws.err = fmt.Errorf("reconnect failed after %d attempts: %w", ws.opts.maxReconnects, lastErr)
+	ws.closed = true
+	ws.cancel()
+	ws.setStateLocked(WSStateClosed)
+	close(ready)
+}
+
+// dialAndResubscribe dials a new connection and runs the resubscribe
+// callback on it.
+func (ws *WSConn) dialAndResubscribe() (*websocket.Conn, error) {
+	conn, err := dialWS(ws.ctx, ws.url, ws.headers)
+	if err != nil {
+		return nil, err
+	}
+	if ws.opts.resubscribe != nil {
+		if err := ws.opts.resubscribe(ws.ctx, newWSConn(conn, nil)); err != nil {
+			_ = conn.CloseNow() // #nosec G104 -- the attempt has failed
+			return nil, fmt.Errorf("httpclient: websocket resubscribe: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// startPingLocked starts the keepalive pings for the current connection.
+// Caller must hold ws.mu.
+func (ws *WSConn) startPingLocked() {
+	if ws.opts.pingInterval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(ws.ctx)
+	ws.cancelPing = cancel
+	go ws.ping(ctx, ws.conn, ws.gen)
+}
+
+// stopPingLocked stops the keepalive pings. Caller must hold ws.mu.
+func (ws *WSConn) stopPingLocked() {
+	if ws.cancelPing != nil {
+		ws.cancelPing()
+		ws.cancelPing = nil
+	}
+}
+
+// ping pings conn until ctx is cancelled, reporting the connection as lost
+// when a pong does not arrive in time.
+func (ws *WSConn) ping(ctx context.Context, conn *websocket.Conn, gen uint64) {
+	ticker := time.NewTicker(ws.opts.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, ws.opts.pingInterval)
+		err := conn.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !ws.reconnect(gen, err) {
+			// Unblock readers of the dead connection.
+			_ = conn.CloseNow() // #nosec G104 -- the connection is already lost
+		}
+		return
+	}
+}
+
+// setStateLocked records a state change and notifies the callback. Caller
+// must hold ws.mu.
+func (ws *WSConn) setStateLocked(to WSState) {
+	if ws.state == to {
+		return
+	}
+	ws.state = to
+	if ws.opts.onStateChange != nil {
+		ws.opts.onStateChange(to)
+	}
 }
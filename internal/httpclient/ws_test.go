@@ -2,13 +2,17 @@ package httpclient
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -212,3 +216,293 @@ func TestDialWS_ContextCanceled(t *testing.T) {
 	_, err := DialWS(ctx, wsURL, nil)
 	require.Error(t, err)
 }
+
+func TestAcceptWS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := AcceptWS(w, r)
+		if err != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var msg wsTestMsg
+		if err := ws.ReadJSON(ctx, &msg); err != nil {
+			return
+		}
+		msg.Payload = "ack:" + msg.Payload
+		_ = ws.WriteJSON(ctx, msg)
+		_ = ws.CloseWith(websocket.StatusGoingAway, "bye")
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+	ws, err := DialWS(context.Background(), wsURL, nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	ctx := context.Background()
+	require.NoError(t, ws.WriteJSON(ctx, wsTestMsg{Type: "ping", Payload: "x"}))
+	var got wsTestMsg
+	require.NoError(t, ws.ReadJSON(ctx, &got))
+	assert.Equal(t, "ack:x", got.Payload)
+
+	err = ws.ReadJSON(ctx, &got)
+	require.Error(t, err)
+	assert.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(err))
+}
+
+func TestAcceptWS_NotUpgrade(t *testing.T) {
+	rec := httptest.NewRecorder()
+	_, err := AcceptWS(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "websocket accept")
+}
+
+// newReconnectTestServer serves WebSocket connections with handler,
+// passing the 1-based connection number.
+func newReconnectTestServer(t *testing.T, handler func(n int, conn *websocket.Conn)) (*httptest.Server, string) {
+	t.Helper()
+	var conns atomic.Int32
+	srv := newWSTestServer(t, func(conn *websocket.Conn) {
+		handler(int(conns.Add(1)), conn)
+	})
+	return srv, "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestWSReconnect_ReadResumesWithResubscribe(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		ctx := context.Background()
+		if n == 1 {
+			wsjson.Write(ctx, conn, wsTestMsg{Type: "first"})
+			conn.CloseNow()
+			return
+		}
+		// Expect the session to be re-initialized before sending.
+		var init wsTestMsg
+		if err := wsjson.Read(ctx, conn, &init); err != nil {
+			return
+		}
+		wsjson.Write(ctx, conn, wsTestMsg{Type: "second", Payload: init.Type})
+		conn.Close(websocket.StatusNormalClosure, "")
+	})
+	defer srv.Close()
+
+	var (
+		mu     sync.Mutex
+		states []WSState
+	)
+	resubscribed := 0
+	ws, err := DialWS(context.Background(), wsURL, nil,
+		WithReconnect(3, time.Millisecond),
+		WithResubscribe(func(ctx context.Context, conn *WSConn) error {
+			resubscribed++
+			return conn.WriteJSON(ctx, wsTestMsg{Type: "session.update"})
+		}),
+		WithStateChange(func(s WSState) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, s)
+		}),
+	)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var msg wsTestMsg
+	require.NoError(t, ws.ReadJSON(context.Background(), &msg))
+	assert.Equal(t, "first", msg.Type)
+
+	require.NoError(t, ws.ReadJSON(context.Background(), &msg))
+	assert.Equal(t, wsTestMsg{Type: "second", Payload: "session.update"}, msg)
+	assert.Equal(t, 1, resubscribed)
+	assert.Equal(t, WSStateConnected, ws.State())
+
+	mu.Lock()
+	assert.Equal(t, []WSState{WSStateReconnecting, WSStateConnected}, states)
+	mu.Unlock()
+
+	// A normal closure is final.
+	err = ws.ReadJSON(context.Background(), &msg)
+	require.Error(t, err)
+	assert.Equal(t, websocket.StatusNormalClosure, websocket.CloseStatus(err))
+}
+
+func TestWSReconnect_WriteWaitsForReconnect(t *testing.T) {
+	received := make(chan wsTestMsg, 1)
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		ctx := context.Background()
+		if n == 1 {
+			conn.CloseNow()
+			return
+		}
+		var msg wsTestMsg
+		if err := wsjson.Read(ctx, conn, &msg); err == nil {
+			received <- msg
+		}
+		conn.Close(websocket.StatusNormalClosure, "")
+	})
+	defer srv.Close()
+
+	ws, err := DialWS(context.Background(), wsURL, nil, WithReconnect(3, 20*time.Millisecond))
+	require.NoError(t, err)
+	defer ws.Close()
+
+	// The read notices the lost connection and starts reconnecting.
+	readCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		var msg wsTestMsg
+		ws.ReadJSON(readCtx, &msg)
+	}()
+	defer cancel()
+	require.Eventually(t, func() bool { return ws.State() == WSStateReconnecting }, time.Second, time.Millisecond)
+
+	require.NoError(t, ws.WriteJSON(context.Background(), wsTestMsg{Type: "buffered"}))
+	select {
+	case msg := <-received:
+		assert.Equal(t, "buffered", msg.Type)
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered after reconnect")
+	}
+}
+
+func TestWSReconnect_GivesUp(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		conn.CloseNow()
+	})
+
+	var closed atomic.Bool
+	ws, err := DialWS(context.Background(), wsURL, nil,
+		WithReconnect(2, time.Millisecond),
+		WithStateChange(func(s WSState) {
+			if s == WSStateClosed {
+				closed.Store(true)
+			}
+		}),
+	)
+	require.NoError(t, err)
+	srv.Close()
+
+	var msg wsTestMsg
+	err = ws.ReadJSON(context.Background(), &msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconnect failed after 2 attempts")
+	assert.Equal(t, WSStateClosed, ws.State())
+	assert.True(t, closed.Load())
+
+	err = ws.WriteJSON(context.Background(), wsTestMsg{Type: "late"})
+	assert.Contains(t, err.Error(), "reconnect failed")
+}
+
+func TestWSReconnect_FailedResubscribeRetries(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			conn.CloseNow()
+			return
+		}
+		wsjson.Write(context.Background(), conn, wsTestMsg{Type: "hello"})
+		conn.Close(websocket.StatusNormalClosure, "")
+	})
+	defer srv.Close()
+
+	var attempts atomic.Int32
+	ws, err := DialWS(context.Background(), wsURL, nil,
+		WithReconnect(3, time.Millisecond),
+		WithResubscribe(func(ctx context.Context, conn *WSConn) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("not yet")
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var msg wsTestMsg
+	require.NoError(t, ws.ReadJSON(context.Background(), &msg))
+	assert.Equal(t, "hello", msg.Type)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestWSReadTimeout(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			// Stay silent.
+			conn.Read(context.Background())
+			return
+		}
+		wsjson.Write(context.Background(), conn, wsTestMsg{Type: "fresh"})
+		conn.Close(websocket.StatusNormalClosure, "")
+	})
+	defer srv.Close()
+
+	ws, err := DialWS(context.Background(), wsURL, nil,
+		WithReadTimeout(50*time.Millisecond),
+		WithReconnect(2, time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var msg wsTestMsg
+	require.NoError(t, ws.ReadJSON(context.Background(), &msg))
+	assert.Equal(t, "fresh", msg.Type)
+}
+
+func TestWSReadTimeout_WithoutReconnect(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		conn.Read(context.Background())
+	})
+	defer srv.Close()
+
+	ws, err := DialWS(context.Background(), wsURL, nil, WithReadTimeout(20*time.Millisecond))
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var msg wsTestMsg
+	err = ws.ReadJSON(context.Background(), &msg)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWSPingInterval(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		// Reading processes pings and replies with pongs.
+		conn.Read(context.Background())
+	})
+	defer srv.Close()
+
+	ws, err := DialWS(context.Background(), wsURL, nil, WithPingInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	readCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var msg wsTestMsg
+	err = ws.ReadJSON(readCtx, &msg)
+	// Pings were answered, so the read only ends with its context.
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	ws.Close()
+	assert.Equal(t, WSStateClosed, ws.State())
+}
+
+func TestWSClose_DuringReconnect(t *testing.T) {
+	srv, wsURL := newReconnectTestServer(t, func(n int, conn *websocket.Conn) {
+		conn.CloseNow()
+	})
+	defer srv.Close()
+
+	ws, err := DialWS(context.Background(), wsURL, nil, WithReconnect(5, time.Hour))
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() {
+		var msg wsTestMsg
+		errc <- ws.ReadJSON(context.Background(), &msg)
+	}()
+	require.Eventually(t, func() bool { return ws.State() == WSStateReconnecting }, time.Second, time.Millisecond)
+
+	require.NoError(t, ws.Close())
+	select {
+	case err := <-errc:
+		assert.Contains(t, err.Error(), "connection closed")
+	case <-time.After(time.Second):
+		t.Fatal("ReadJSON not unblocked by Close")
+	}
+}
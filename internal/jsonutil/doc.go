@@ -1,9 +1,11 @@
 // Package jsonutil provides JSON utilities for the Beluga AI framework,
-// including JSON Schema generation from Go struct types via reflection.
+// including JSON Schema generation from Go struct types via reflection and
+// validation of values against a JSON Schema.
 //
 // This is an internal package and is not part of the public API. It is used by
 // the tool system and structured output packages to automatically generate
-// JSON Schema definitions from Go types.
+// JSON Schema definitions from Go types, and by the guard package and the
+// Google structured output provider to validate values against them.
 //
 // # Schema Generation
 //
@@ -30,4 +32,19 @@
 //	}
 //	schema := jsonutil.GenerateSchema(SearchInput{})
 //	// schema is a map[string]any representing the JSON Schema
+//
+// # Schema Validation
+//
+// [ValidateSchema] checks a value, in the form encoding/json decodes into an
+// any, against a JSON Schema and returns the violations found as
+// human-readable strings. [NullOptional] treats null optional properties as
+// absent.
+//
+//	var v any
+//	if err := json.Unmarshal(data, &v); err != nil {
+//	    return err
+//	}
+//	if errs := jsonutil.ValidateSchema(v, schema, "output"); len(errs) > 0 {
+//	    return fmt.Errorf("invalid output: %s", strings.Join(errs, "; "))
+//	}
 package jsonutil
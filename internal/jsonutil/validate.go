@@ -0,0 +1,287 @@
+package jsonutil
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ValidateSchema validates v against the JSON Schema sch and returns
+// human-readable violations prefixed with path; an empty result means v is
+// valid. v must be in the form encoding/json decodes into an any.
+//
+// The supported keywords are type, properties, required,
+// additionalProperties (boolean), enum, const, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern, items,
+// minItems and maxItems. Numeric keywords and enum values may use Go numeric
+// types as well as float64, and required and enum may be []string. Numeric
+// keywords may also be numeric strings, as GenerateSchema records them.
+func ValidateSchema(v any, sch map[string]any, path string, opts ...ValidateOption) []string {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.validate(v, sch, path)
+}
+
+// ValidateOption configures ValidateSchema.
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	nullOptional bool
+}
+
+// NullOptional treats a null property that the schema does not require as
+// absent, as models producing structured output often send null for
+// optional fields they leave out.
+func NullOptional() ValidateOption {
+	return func(o *validateOptions) {
+		o.nullOptional = true
+	}
+}
+
+func (o validateOptions) validate(v any, sch map[string]any, path string) []string {
+	if want, ok := sch["const"]; ok && !jsonEqual(v, want) {
+		return []string{fmt.Sprintf("%s: must equal %v", path, want)}
+	}
+	if enum, ok := schemaEnum(sch["enum"]); ok && !inEnum(v, enum) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, v, enum)}
+	}
+
+	typ, _ := sch["type"].(string)
+	if typ != "" && !matchesType(v, typ) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, typ, jsonTypeName(v))}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		return o.validateObject(val, sch, path)
+	case []any:
+		return o.validateArray(val, sch, path)
+	case string:
+		return validateString(val, sch, path)
+	case float64:
+		return validateNumber(val, sch, path)
+	}
+	return nil
+}
+
+func (o validateOptions) validateObject(obj map[string]any, sch map[string]any, path string) []string {
+	var errs []string
+	required := schemaStrings(sch["required"])
+	for _, r := range required {
+		if _, ok := obj[r]; !ok {
+			errs = append(errs, fmt.Sprintf("%s.%s: required", path, r))
+		}
+	}
+
+	props, _ := sch["properties"].(map[string]any)
+	ap, apSet := sch["additionalProperties"].(bool)
+	rejectAdditional := apSet && !ap
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propSchema, ok := props[k].(map[string]any)
+		if !ok {
+			if rejectAdditional {
+				errs = append(errs, fmt.Sprintf("%s.%s: unexpected property", path, k))
+			}
+			continue
+		}
+		if obj[k] == nil && o.nullOptional && !slices.Contains(required, k) {
+			continue
+		}
+		errs = append(errs, o.validate(obj[k], propSchema, path+"."+k)...)
+	}
+	return errs
+}
+
+func (o validateOptions) validateArray(arr []any, sch map[string]any, path string) []string {
+	var errs []string
+	if n, ok := schemaBound(sch["minItems"]); ok && float64(len(arr)) < n {
+		errs = append(errs, fmt.Sprintf("%s: must have at least %v items", path, n))
+	}
+	if n, ok := schemaBound(sch["maxItems"]); ok && float64(len(arr)) > n {
+		errs = append(errs, fmt.Sprintf("%s: must have at most %v items", path, n))
+	}
+	if items, ok := sch["items"].(map[string]any); ok {
+		for i, item := range arr {
+			errs = append(errs, o.validate(item, items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return errs
+}
+
+func validateString(s string, sch map[string]any, path string) []string {
+	var errs []string
+	length := float64(utf8.RuneCountInString(s))
+	if n, ok := schemaBound(sch["minLength"]); ok && length < n {
+		errs = append(errs, fmt.Sprintf("%s: must be at least %v characters", path, n))
+	}
+	if n, ok := schemaBound(sch["maxLength"]); ok && length > n {
+		errs = append(errs, fmt.Sprintf("%s: must be at most %v characters", path, n))
+	}
+	if p, ok := sch["pattern"].(string); ok {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: schema pattern %q is invalid", path, p))
+		} else if !re.MatchString(s) {
+			errs = append(errs, fmt.Sprintf("%s: does not match pattern %q", path, p))
+		}
+	}
+	return errs
+}
+
+func validateNumber(f float64, sch map[string]any, path string) []string {
+	var errs []string
+	if n, ok := schemaBound(sch["minimum"]); ok && f < n {
+		errs = append(errs, fmt.Sprintf("%s: %v is below minimum %v", path, f, n))
+	}
+	if n, ok := schemaBound(sch["maximum"]); ok && f > n {
+		errs = append(errs, fmt.Sprintf("%s: %v exceeds maximum %v", path, f, n))
+	}
+	if n, ok := schemaBound(sch["exclusiveMinimum"]); ok && f <= n {
+		errs = append(errs, fmt.Sprintf("%s: %v must be greater than %v", path, f, n))
+	}
+	if n, ok := schemaBound(sch["exclusiveMaximum"]); ok && f >= n {
+		errs = append(errs, fmt.Sprintf("%s: %v must be less than %v", path, f, n))
+	}
+	return errs
+}
+
+// matchesType reports whether v, as decoded by encoding/json, has the JSON
+// Schema type typ.
+func matchesType(v any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// schemaNumber converts a numeric schema keyword value to float64. Schemas
+// built in Go may use int literals, while decoded JSON uses float64.
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// schemaBound converts a numeric keyword such as minimum or maxLength to
+// float64, accepting the numeric strings GenerateSchema records as well as
+// numbers.
+func schemaBound(v any) (float64, bool) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	return schemaNumber(v)
+}
+
+// schemaStrings converts a "required"-style keyword to a string slice.
+func schemaStrings(v any) []string {
+	switch s := v.(type) {
+	case []string:
+		return s
+	case []any:
+		out := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// schemaEnum converts an "enum" keyword to a slice, accepting the []string
+// form common in schemas built in Go.
+func schemaEnum(v any) ([]any, bool) {
+	switch e := v.(type) {
+	case []any:
+		return e, true
+	case []string:
+		out := make([]any, len(e))
+		for i, s := range e {
+			out[i] = s
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func inEnum(v any, enum []any) bool {
+	for _, e := range enum {
+		if jsonEqual(v, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares a decoded JSON value with a schema value that may have
+// been written with Go numeric types.
+func jsonEqual(a, b any) bool {
+	if fa, ok := schemaNumber(a); ok {
+		fb, ok := schemaNumber(b)
+		return ok && fa == fb
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
@@ -0,0 +1,108 @@
+package jsonutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSchema(t *testing.T) {
+	sch := map[string]any{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"age":   map[string]any{"type": "integer", "minimum": 0, "maximum": 150},
+			"color": map[string]any{"enum": []string{"red", "green"}},
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "maxItems": 2},
+		},
+	}
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "valid", value: map[string]any{"name": "ada", "age": 36.0, "tags": []any{"x"}}},
+		{name: "missing required", value: map[string]any{}, want: "v.name: required"},
+		{name: "wrong type", value: map[string]any{"name": 1.0}, want: "v.name: expected string, got number"},
+		{name: "not integer", value: map[string]any{"name": "ada", "age": 1.5}, want: "v.age: expected integer"},
+		{name: "above maximum", value: map[string]any{"name": "ada", "age": 200.0}, want: "exceeds maximum"},
+		{name: "too short", value: map[string]any{"name": "a"}, want: "at least 2 characters"},
+		{name: "pattern", value: map[string]any{"name": "Ada"}, want: "does not match pattern"},
+		{name: "enum", value: map[string]any{"name": "ada", "color": "blue"}, want: "not one of"},
+		{name: "array item", value: map[string]any{"name": "ada", "tags": []any{"x", 1.0}}, want: "v.tags[1]"},
+		{name: "too many items", value: map[string]any{"name": "ada", "tags": []any{"x", "y", "z"}}, want: "at most 2 items"},
+		{name: "not object", value: "ada", want: "v: expected object, got string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSchema(tt.value, sch, "v")
+			got := strings.Join(errs, "; ")
+			if tt.want == "" {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %s", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("errors %q do not contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_AdditionalProperties(t *testing.T) {
+	props := map[string]any{"a": map[string]any{"type": "string"}}
+	value := map[string]any{"a": "x", "b": "y"}
+
+	tests := []struct {
+		name string
+		sch  map[string]any
+		want bool
+	}{
+		{name: "allowed by default", sch: map[string]any{"properties": props}},
+		{name: "rejected by schema", sch: map[string]any{"properties": props, "additionalProperties": false}, want: true},
+		{name: "allowed by schema", sch: map[string]any{"properties": props, "additionalProperties": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSchema(value, tt.sch, "v")
+			if got := len(errs) > 0; got != tt.want {
+				t.Errorf("rejected = %v, want %v (errors %v)", got, tt.want, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_GeneratedBounds(t *testing.T) {
+	type input struct {
+		Count int `json:"count" minimum:"1" maximum:"10"`
+	}
+	sch := GenerateSchema(input{})
+	if errs := ValidateSchema(map[string]any{"count": 11.0}, sch, "v"); len(errs) == 0 {
+		t.Error("expected a violation of the generated maximum")
+	}
+	if errs := ValidateSchema(map[string]any{"count": 5.0}, sch, "v"); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateSchema_NullOptional(t *testing.T) {
+	sch := map[string]any{
+		"type":     "object",
+		"required": []any{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"note": map[string]any{"type": "string"},
+		},
+	}
+	value := map[string]any{"name": "x", "note": nil}
+	if errs := ValidateSchema(value, sch, "v"); len(errs) == 0 {
+		t.Error("expected null to violate the type by default")
+	}
+	if errs := ValidateSchema(value, sch, "v", NullOptional()); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs := ValidateSchema(map[string]any{"name": nil}, sch, "v", NullOptional()); len(errs) == 0 {
+		t.Error("expected a required null to be rejected")
+	}
+}
@@ -0,0 +1,212 @@
+package openaicompat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/openai/openai-go"
+)
+
+// defaultBatchPollInterval is how often BatchGenerate checks whether a
+// batch has finished.
+const defaultBatchPollInterval = 30 * time.Second
+
+// Compile-time interface check.
+var _ llm.BatchGenerator = (*Model)(nil)
+
+// batchInputLine is one line of a batch input file.
+type batchInputLine struct {
+	CustomID string                         `json:"custom_id"`
+	Method   string                         `json:"method"`
+	URL      string                         `json:"url"`
+	Body     openai.ChatCompletionNewParams `json:"body"`
+}
+
+// batchOutputLine is one line of a batch output or error file.
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// BatchGenerate submits requests to the Batch API, which bills at half the
+// price of Generate, and waits for the batch to finish. The requests are
+// uploaded as a JSONL file of chat completions. Results are returned in
+// request order and correlated by custom ID; requests that failed, or that
+// were left unprocessed when the batch expired or was cancelled, carry an
+// error in their result. If ctx is done before the batch ends, the error
+// names the batch, which keeps running. The backend must support the
+// OpenAI Batch API.
+func (m *Model) BatchGenerate(ctx context.Context, requests []llm.BatchRequest) ([]llm.BatchResult, error) {
+	if err := llm.ValidateBatch(requests); err != nil {
+		return nil, err
+	}
+
+	var input bytes.Buffer
+	enc := json.NewEncoder(&input)
+	for _, req := range requests {
+		params, err := m.buildParams(ctx, req.Messages, req.Options)
+		if err != nil {
+			return nil, fmt.Errorf("openaicompat: batch request %q: %w", req.CustomID, err)
+		}
+		line := batchInputLine{
+			CustomID: req.CustomID,
+			Method:   "POST",
+			URL:      string(openai.BatchNewParamsEndpointV1ChatCompletions),
+			Body:     params,
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, fmt.Errorf("openaicompat: batch request %q: %w", req.CustomID, err)
+		}
+	}
+
+	file, err := m.client.Files.New(ctx, openai.FileNewParams{
+		File:    openai.File(&input, "batch.jsonl", "application/jsonl"),
+		Purpose: openai.FilePurposeBatch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openaicompat: batch upload failed: %w", err)
+	}
+	batch, err := m.client.Batches.New(ctx, openai.BatchNewParams{
+		InputFileID:      file.ID,
+		Endpoint:         openai.BatchNewParamsEndpointV1ChatCompletions,
+		CompletionWindow: openai.BatchNewParamsCompletionWindow24h,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openaicompat: batch submit failed: %w", err)
+	}
+
+	batch, err = m.waitBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == openai.BatchStatusFailed {
+		return nil, batchFailure(batch)
+	}
+	return m.batchResults(ctx, batch, requests)
+}
+
+// waitBatch polls batch until it reaches a terminal status.
+func (m *Model) waitBatch(ctx context.Context, batch *openai.Batch) (*openai.Batch, error) {
+	id := batch.ID
+	for !batchDone(batch.Status) {
+		timer := time.NewTimer(m.batchPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("openaicompat: waiting for batch %s: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+
+		var err error
+		batch, err = m.client.Batches.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("openaicompat: batch %s status failed: %w", id, err)
+		}
+	}
+	return batch, nil
+}
+
+// batchDone reports whether status is terminal.
+func batchDone(status openai.BatchStatus) bool {
+	switch status {
+	case openai.BatchStatusCompleted, openai.BatchStatusFailed,
+		openai.BatchStatusExpired, openai.BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// batchFailure returns the error of a batch that failed validation.
+func batchFailure(batch *openai.Batch) error {
+	errs := make([]error, 0, len(batch.Errors.Data))
+	for _, e := range batch.Errors.Data {
+		errs = append(errs, fmt.Errorf("line %d: %s: %s", e.Line, e.Code, e.Message))
+	}
+	return fmt.Errorf("openaicompat: batch %s failed: %w", batch.ID, errors.Join(errs...))
+}
+
+// batchResults reads the output and error files of the finished batch and
+// orders their results like requests.
+func (m *Model) batchResults(ctx context.Context, batch *openai.Batch, requests []llm.BatchRequest) ([]llm.BatchResult, error) {
+	results := make([]llm.BatchResult, len(requests))
+	index := make(map[string]int, len(requests))
+	for i, req := range requests {
+		index[req.CustomID] = i
+		results[i] = llm.BatchResult{
+			CustomID: req.CustomID,
+			Err:      fmt.Errorf("openaicompat: batch %s has no result for %q, batch status %s", batch.ID, req.CustomID, batch.Status),
+		}
+	}
+
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := m.readBatchFile(ctx, fileID, func(line batchOutputLine) {
+			if i, ok := index[line.CustomID]; ok {
+				results[i].Response, results[i].Err = batchLineResult(line)
+			}
+		}); err != nil {
+			return nil, fmt.Errorf("openaicompat: batch %s results failed: %w", batch.ID, err)
+		}
+	}
+	return results, nil
+}
+
+// readBatchFile calls fn with each line of the batch output file fileID.
+func (m *Model) readBatchFile(ctx context.Context, fileID string, fn func(batchOutputLine)) error {
+	resp, err := m.client.Files.Content(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line batchOutputLine
+		if err := dec.Decode(&line); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fn(line)
+	}
+}
+
+// batchLineResult converts one line of a batch output or error file.
+func batchLineResult(line batchOutputLine) (*schema.AIMessage, error) {
+	if line.Error != nil {
+		return nil, fmt.Errorf("openaicompat: batch request failed: %s: %s", line.Error.Code, line.Error.Message)
+	}
+	if line.Response == nil {
+		return nil, fmt.Errorf("openaicompat: batch request has no response")
+	}
+	if line.Response.StatusCode != 200 {
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(line.Response.Body, &body)
+		return nil, fmt.Errorf("openaicompat: batch request failed with status %d: %s", line.Response.StatusCode, body.Error.Message)
+	}
+	var completion openai.ChatCompletion
+	if err := json.Unmarshal(line.Response.Body, &completion); err != nil {
+		return nil, fmt.Errorf("openaicompat: batch response: %w", err)
+	}
+	return ConvertResponse(&completion), nil
+}
@@ -0,0 +1,162 @@
+package openaicompat
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+func batchJSON(status, outputFile, errorFile string) string {
+	return fmt.Sprintf(`{"id":"batch_1","object":"batch","endpoint":"/v1/chat/completions","input_file_id":"file_in","completion_window":"24h","created_at":1,"status":%q,"output_file_id":%q,"error_file_id":%q}`,
+		status, outputFile, errorFile)
+}
+
+func batchOutput(customID string, status int, body string) string {
+	return fmt.Sprintf(`{"id":"req_%s","custom_id":%q,"response":{"status_code":%d,"body":%s},"error":null}`+"\n", customID, customID, status, body)
+}
+
+func TestBatchGenerate(t *testing.T) {
+	var inputLines []map[string]any
+	var polls atomic.Int32
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/files":
+			if r.FormValue("purpose") != "batch" {
+				t.Errorf("purpose = %q, want batch", r.FormValue("purpose"))
+			}
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("FormFile: %v", err)
+			}
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				var line map[string]any
+				json.Unmarshal(sc.Bytes(), &line)
+				inputLines = append(inputLines, line)
+			}
+			fmt.Fprint(w, `{"id":"file_in","object":"file","bytes":1,"created_at":1,"filename":"batch.jsonl","purpose":"batch","status":"processed"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/batches":
+			fmt.Fprint(w, batchJSON("validating", "", ""))
+		case r.URL.Path == "/batches/batch_1":
+			if polls.Add(1) == 1 {
+				fmt.Fprint(w, batchJSON("in_progress", "", ""))
+				return
+			}
+			fmt.Fprint(w, batchJSON("completed", "file_out", "file_err"))
+		case r.URL.Path == "/files/file_out/content":
+			fmt.Fprint(w, batchOutput("b", 200, chatCompletionResponse("answer b", nil)))
+			fmt.Fprint(w, batchOutput("a", 200, chatCompletionResponse("answer a", nil)))
+		case r.URL.Path == "/files/file_err/content":
+			fmt.Fprint(w, batchOutput("c", 400, `{"error":{"message":"bad prompt"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	defer ts.Close()
+	m.batchPollInterval = time.Millisecond
+
+	results, err := m.BatchGenerate(context.Background(), []llm.BatchRequest{
+		{CustomID: "a", Messages: []schema.Message{schema.NewHumanMessage("A?")}, Options: []llm.GenerateOption{llm.WithMaxTokens(50)}},
+		{CustomID: "b", Messages: []schema.Message{schema.NewHumanMessage("B?")}},
+		{CustomID: "c", Messages: []schema.Message{schema.NewHumanMessage("C?")}},
+		{CustomID: "d", Messages: []schema.Message{schema.NewHumanMessage("D?")}},
+	})
+	if err != nil {
+		t.Fatalf("BatchGenerate() error: %v", err)
+	}
+
+	if len(inputLines) != 4 {
+		t.Fatalf("uploaded %d lines, want 4", len(inputLines))
+	}
+	body, _ := inputLines[0]["body"].(map[string]any)
+	if inputLines[0]["custom_id"] != "a" || inputLines[0]["url"] != "/v1/chat/completions" ||
+		body["model"] != "gpt-4o" || body["max_completion_tokens"] != float64(50) {
+		t.Errorf("first line = %v", inputLines[0])
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if results[i].CustomID != id {
+			t.Errorf("results[%d].CustomID = %q, want %q", i, results[i].CustomID, id)
+		}
+	}
+	if results[0].Err != nil || results[0].Response.Text() != "answer a" {
+		t.Errorf("results[0] = %+v, want answer a", results[0])
+	}
+	if results[1].Err != nil || results[1].Response.Text() != "answer b" {
+		t.Errorf("results[1] = %+v, want answer b", results[1])
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "bad prompt") {
+		t.Errorf("results[2].Err = %v, want the request error", results[2].Err)
+	}
+	if results[3].Err == nil || results[3].Response != nil {
+		t.Errorf("results[3] = %+v, want a missing result error", results[3])
+	}
+}
+
+func TestBatchGenerate_Failed(t *testing.T) {
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/files" {
+			fmt.Fprint(w, `{"id":"file_in","object":"file","purpose":"batch"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"batch_1","object":"batch","status":"failed","errors":{"object":"list","data":[{"code":"invalid_request","line":1,"message":"bad line"}]}}`)
+	})
+	defer ts.Close()
+
+	_, err := m.BatchGenerate(context.Background(), []llm.BatchRequest{
+		{CustomID: "a", Messages: []schema.Message{schema.NewHumanMessage("A?")}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "bad line") {
+		t.Errorf("err = %v, want the batch errors", err)
+	}
+}
+
+func TestBatchGenerate_ContextDone(t *testing.T) {
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/files" {
+			fmt.Fprint(w, `{"id":"file_in","object":"file","purpose":"batch"}`)
+			return
+		}
+		fmt.Fprint(w, batchJSON("in_progress", "", ""))
+	})
+	defer ts.Close()
+	m.batchPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := m.BatchGenerate(ctx, []llm.BatchRequest{
+		{CustomID: "a", Messages: []schema.Message{schema.NewHumanMessage("A?")}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "batch_1") {
+		t.Errorf("err = %v, want an error naming the batch", err)
+	}
+}
+
+func TestBatchGenerate_InvalidRequests(t *testing.T) {
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	defer ts.Close()
+	if _, err := m.BatchGenerate(context.Background(), nil); err == nil {
+		t.Error("expected error for an empty batch")
+	}
+	if _, err := m.BatchGenerate(context.Background(), []llm.BatchRequest{{CustomID: "a"}, {CustomID: "a"}}); err == nil {
+		t.Error("expected error for duplicate custom IDs")
+	}
+}
@@ -153,5 +153,46 @@ func ConvertResponse(resp *openai.ChatCompletion) *schema.AIMessage {
 			}
 		}
 	}
+	ai.LogProbs = convertLogProbs(choice.Logprobs.Content)
 	return ai
 }
+
+// convertLogProbs converts the log probabilities of a choice's content
+// tokens. It returns nil if there are none.
+func convertLogProbs(content []openai.ChatCompletionTokenLogprob) []schema.TokenLogProb {
+	if len(content) == 0 {
+		return nil
+	}
+	out := make([]schema.TokenLogProb, len(content))
+	for i, lp := range content {
+		out[i] = schema.TokenLogProb{
+			Token:   lp.Token,
+			LogProb: lp.Logprob,
+			Bytes:   logProbBytes(lp.Bytes),
+		}
+		if len(lp.TopLogprobs) > 0 {
+			out[i].TopLogProbs = make([]schema.TokenLogProb, len(lp.TopLogprobs))
+			for j, top := range lp.TopLogprobs {
+				out[i].TopLogProbs[j] = schema.TokenLogProb{
+					Token:   top.Token,
+					LogProb: top.Logprob,
+					Bytes:   logProbBytes(top.Bytes),
+				}
+			}
+		}
+	}
+	return out
+}
+
+// logProbBytes converts the byte values of a token, which the API sends as
+// a JSON array of integers.
+func logProbBytes(b []int64) []byte {
+	if b == nil {
+		return nil
+	}
+	out := make([]byte, len(b))
+	for i, v := range b {
+		out[i] = byte(v)
+	}
+	return out
+}
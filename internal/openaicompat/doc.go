@@ -29,6 +29,9 @@
 // [ConvertResponse] translates OpenAI ChatCompletion responses back into
 // Beluga schema.AIMessage, including tool calls and usage statistics.
 //
+// Before conversion, messages that would overflow the model's context window
+// are trimmed when the request sets llm.WithAutoTrim.
+//
 // # Tool Conversion
 //
 // [ConvertTools] translates Beluga schema.ToolDefinition slices into OpenAI
@@ -38,5 +41,27 @@
 //
 // [StreamToSeq] converts an openai-go SSE stream into a Beluga
 // iter.Seq2[schema.StreamChunk, error] iterator, handling text deltas,
-// tool call accumulation, finish reasons, and token usage.
+// tool call accumulation, finish reasons, and token usage. Tool calls are
+// tracked by stream index and ID, and each argument fragment carries the ID
+// and name of its call, so parallel calls whose deltas interleave are
+// assembled by concatenating the Arguments of the fragments sharing an ID.
+//
+// # Log Probabilities
+//
+// llm.WithLogProbs sets the logprobs and top_logprobs request parameters.
+// [ConvertResponse] returns the log probability of every content token in
+// AIMessage.LogProbs, and [StreamToSeq] sets StreamChunk.LogProbs to those
+// of the chunk's delta, so concatenating the chunks' LogProbs yields the
+// same sequence. Whether they are returned depends on the backend. Among
+// the registered providers, openai, azure, together, fireworks and
+// deepseek (deepseek-chat only) return them, and openrouter and litellm
+// pass them through from upstream models that do. groq rejects the
+// parameters. Other backends may ignore them, leaving LogProbs nil.
+//
+// # Batches
+//
+// [Model] implements llm.BatchGenerator with the Batch API: requests are
+// uploaded as a JSONL file, the batch is polled every 30 seconds, and the
+// output and error files are read back. Only backends that implement the
+// Batch API, such as OpenAI, support it; others fail on upload.
 package openaicompat
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/config"
 	"github.com/lookatitude/beluga-ai/v2/llm"
@@ -20,6 +21,8 @@ type Model struct {
 	model   string
 	tools   []schema.ToolDefinition
 	options []option.RequestOption
+
+	batchPollInterval time.Duration
 }
 
 // Compile-time interface check.
@@ -43,9 +46,10 @@ func New(cfg config.ProviderConfig) (*Model, error) {
 	}
 	client := openai.NewClient(opts...)
 	return &Model{
-		client:  client,
-		model:   cfg.Model,
-		options: opts,
+		client:            client,
+		model:             cfg.Model,
+		options:           opts,
+		batchPollInterval: defaultBatchPollInterval,
 	}, nil
 }
 
@@ -63,7 +67,7 @@ func NewWithOptions(cfg config.ProviderConfig, extraOpts ...option.RequestOption
 
 // Generate sends messages and returns a complete AI response.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	params, err := m.buildParams(msgs, opts)
+	params, err := m.buildParams(ctx, msgs, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +80,7 @@ func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm
 
 // Stream sends messages and returns an iterator of response chunks.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	params, err := m.buildParams(msgs, opts)
+	params, err := m.buildParams(ctx, msgs, opts)
 	if err != nil {
 		return func(yield func(schema.StreamChunk, error) bool) {
 			yield(schema.StreamChunk{}, err)
@@ -102,8 +106,9 @@ func (m *Model) ModelID() string {
 	return m.model
 }
 
-func (m *Model) buildParams(msgs []schema.Message, opts []llm.GenerateOption) (openai.ChatCompletionNewParams, error) {
-	converted, err := ConvertMessages(msgs)
+func (m *Model) buildParams(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) (openai.ChatCompletionNewParams, error) {
+	genOpts := llm.ApplyOptions(opts...)
+	converted, err := ConvertMessages(llm.TrimToContextWindow(ctx, m.model, msgs, genOpts))
 	if err != nil {
 		return openai.ChatCompletionNewParams{}, err
 	}
@@ -114,7 +119,6 @@ func (m *Model) buildParams(msgs []schema.Message, opts []llm.GenerateOption) (o
 	if len(m.tools) > 0 {
 		params.Tools = ConvertTools(m.tools)
 	}
-	genOpts := llm.ApplyOptions(opts...)
 	applyGenerateOptions(&params, genOpts)
 	return params, nil
 }
@@ -134,6 +138,12 @@ func applyGenerateOptions(params *openai.ChatCompletionNewParams, opts llm.Gener
 			OfStringArray: opts.StopSequences,
 		}
 	}
+	if opts.LogProbs {
+		params.Logprobs = openai.Bool(true)
+		if opts.TopLogProbs > 0 {
+			params.TopLogprobs = openai.Int(int64(opts.TopLogProbs))
+		}
+	}
 	applyToolChoice(params, opts)
 	if opts.Format != nil {
 		applyResponseFormat(params, opts.Format)
@@ -111,6 +111,9 @@ func streamChunks(deltas []streamDelta) string {
 			delta["tool_calls"] = tcs
 		}
 		choice["delta"] = delta
+		if d.LogProbs != nil {
+			choice["logprobs"] = map[string]any{"content": d.LogProbs}
+		}
 		chunk["choices"] = []map[string]any{choice}
 		if d.Usage != nil {
 			chunk["usage"] = d.Usage
@@ -130,6 +133,7 @@ type streamDelta struct {
 	FinishReason any // nil → JSON null, string → value
 	ToolCalls    []streamToolCall
 	Usage        map[string]any
+	LogProbs     []map[string]any
 }
 
 type streamToolCall struct {
@@ -328,6 +332,106 @@ func TestStreamWithToolCalls(t *testing.T) {
 	}
 }
 
+// assembleToolCalls concatenates the arguments of streamed tool call
+// fragments by call ID, returning the calls in order of first appearance.
+func assembleToolCalls(fragments []schema.ToolCall) []schema.ToolCall {
+	var calls []schema.ToolCall
+	index := make(map[string]int)
+	for _, f := range fragments {
+		i, ok := index[f.ID]
+		if !ok {
+			i = len(calls)
+			index[f.ID] = i
+			calls = append(calls, schema.ToolCall{ID: f.ID, Name: f.Name})
+		}
+		calls[i].Arguments += f.Arguments
+	}
+	return calls
+}
+
+func TestStreamParallelToolCalls(t *testing.T) {
+	tests := []struct {
+		name   string
+		deltas []streamDelta
+	}{
+		{
+			name: "interleaved by index",
+			deltas: []streamDelta{
+				{Role: "assistant", ToolCalls: []streamToolCall{
+					{Index: 0, ID: "call_1", Name: "get_weather", Arguments: `{"loc`},
+				}},
+				{ToolCalls: []streamToolCall{
+					{Index: 1, ID: "call_2", Name: "get_time", Arguments: `{"tz":`},
+				}},
+				{ToolCalls: []streamToolCall{
+					{Index: 0, Arguments: `ation":"NYC"}`},
+					{Index: 1, Arguments: `"EST"}`},
+				}},
+				{FinishReason: "tool_calls"},
+			},
+		},
+		{
+			name: "index reused with new id",
+			deltas: []streamDelta{
+				{Role: "assistant", ToolCalls: []streamToolCall{
+					{Index: 0, ID: "call_1", Name: "get_weather", Arguments: `{"loc`},
+				}},
+				{ToolCalls: []streamToolCall{
+					{Index: 0, Arguments: `ation":"NYC"}`},
+				}},
+				{ToolCalls: []streamToolCall{
+					{Index: 0, ID: "call_2", Name: "get_time", Arguments: `{"tz":`},
+				}},
+				{ToolCalls: []streamToolCall{
+					{Index: 0, Arguments: `"EST"}`},
+				}},
+				{FinishReason: "tool_calls"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, streamChunks(tt.deltas))
+			})
+			defer ts.Close()
+
+			var fragments []schema.ToolCall
+			for chunk, err := range m.Stream(context.Background(), []schema.Message{
+				schema.NewHumanMessage("Weather and time?"),
+			}) {
+				if err != nil {
+					t.Fatalf("Stream() error: %v", err)
+				}
+				fragments = append(fragments, chunk.ToolCalls...)
+			}
+			for _, f := range fragments {
+				if f.ID == "" || f.Name == "" {
+					t.Errorf("fragment %+v is not attributed to a call", f)
+				}
+			}
+
+			calls := assembleToolCalls(fragments)
+			want := []schema.ToolCall{
+				{ID: "call_1", Name: "get_weather", Arguments: `{"location":"NYC"}`},
+				{ID: "call_2", Name: "get_time", Arguments: `{"tz":"EST"}`},
+			}
+			if len(calls) != len(want) {
+				t.Fatalf("calls = %+v, want %+v", calls, want)
+			}
+			for i := range want {
+				if calls[i] != want[i] {
+					t.Errorf("calls[%d] = %+v, want %+v", i, calls[i], want[i])
+				}
+				if !json.Valid([]byte(calls[i].Arguments)) {
+					t.Errorf("calls[%d].Arguments = %q, not valid JSON", i, calls[i].Arguments)
+				}
+			}
+		})
+	}
+}
+
 func TestConvertMessages(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -946,3 +1050,153 @@ func TestStreamUsage(t *testing.T) {
 		t.Errorf("CachedTokens = %d, want 3", lastUsage.CachedTokens)
 	}
 }
+
+func TestLogProbs(t *testing.T) {
+	var capturedBody map[string]any
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &capturedBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"id": "chatcmpl-lp", "object": "chat.completion", "created": 1700000000, "model": "gpt-4o",
+			"choices": [{
+				"index": 0, "finish_reason": "stop",
+				"message": {"role": "assistant", "content": "Hi!"},
+				"logprobs": {"content": [
+					{"token": "Hi", "logprob": -0.1, "bytes": [72, 105], "top_logprobs": [
+						{"token": "Hi", "logprob": -0.1, "bytes": [72, 105]},
+						{"token": "Hello", "logprob": -2.5, "bytes": [72, 101, 108, 108, 111]}
+					]},
+					{"token": "!", "logprob": -0.3, "bytes": [33], "top_logprobs": []}
+				]}
+			}],
+			"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+		}`)
+	})
+	defer ts.Close()
+
+	resp, err := m.Generate(context.Background(), []schema.Message{
+		schema.NewHumanMessage("Hi"),
+	}, llm.WithLogProbs(2))
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if capturedBody["logprobs"] != true {
+		t.Errorf("logprobs = %v, want true", capturedBody["logprobs"])
+	}
+	if n, ok := capturedBody["top_logprobs"].(float64); !ok || n != 2 {
+		t.Errorf("top_logprobs = %v, want 2", capturedBody["top_logprobs"])
+	}
+
+	if len(resp.LogProbs) != 2 {
+		t.Fatalf("LogProbs = %+v, want 2 tokens", resp.LogProbs)
+	}
+	first := resp.LogProbs[0]
+	if first.Token != "Hi" || first.LogProb != -0.1 || string(first.Bytes) != "Hi" {
+		t.Errorf("LogProbs[0] = %+v", first)
+	}
+	if len(first.TopLogProbs) != 2 || first.TopLogProbs[1].Token != "Hello" || first.TopLogProbs[1].LogProb != -2.5 {
+		t.Errorf("TopLogProbs = %+v", first.TopLogProbs)
+	}
+	if resp.LogProbs[1].Token != "!" || resp.LogProbs[1].TopLogProbs != nil {
+		t.Errorf("LogProbs[1] = %+v", resp.LogProbs[1])
+	}
+}
+
+func TestLogProbs_NotRequested(t *testing.T) {
+	var capturedBody map[string]any
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &capturedBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, chatCompletionResponse("ok", nil))
+	})
+	defer ts.Close()
+
+	resp, err := m.Generate(context.Background(), []schema.Message{
+		schema.NewHumanMessage("Hi"),
+	}, llm.WithLogProbs(0))
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if capturedBody["logprobs"] != true {
+		t.Errorf("logprobs = %v, want true", capturedBody["logprobs"])
+	}
+	if _, ok := capturedBody["top_logprobs"]; ok {
+		t.Errorf("top_logprobs = %v, want unset", capturedBody["top_logprobs"])
+	}
+	if resp.LogProbs != nil {
+		t.Errorf("LogProbs = %+v, want nil", resp.LogProbs)
+	}
+}
+
+func TestStreamLogProbs(t *testing.T) {
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, streamChunks([]streamDelta{
+			{Role: "assistant", Content: "Hello", LogProbs: []map[string]any{
+				{"token": "Hello", "logprob": -0.2, "bytes": []int{72, 101, 108, 108, 111}, "top_logprobs": []any{}},
+			}},
+			{Content: " world", LogProbs: []map[string]any{
+				{"token": " world", "logprob": -0.4, "bytes": []int{32, 119, 111, 114, 108, 100}, "top_logprobs": []any{}},
+			}},
+			{FinishReason: "stop"},
+		}))
+	})
+	defer ts.Close()
+
+	var logProbs []schema.TokenLogProb
+	for chunk, err := range m.Stream(context.Background(), []schema.Message{
+		schema.NewHumanMessage("Hi"),
+	}, llm.WithLogProbs(0)) {
+		if err != nil {
+			t.Fatalf("Stream() error: %v", err)
+		}
+		if len(chunk.LogProbs) > 0 && chunk.LogProbs[0].Token != chunk.Delta {
+			t.Errorf("chunk LogProbs = %+v, delta %q", chunk.LogProbs, chunk.Delta)
+		}
+		logProbs = append(logProbs, chunk.LogProbs...)
+	}
+	if len(logProbs) != 2 || logProbs[0].LogProb != -0.2 || string(logProbs[1].Bytes) != " world" {
+		t.Errorf("LogProbs = %+v", logProbs)
+	}
+}
+
+func TestAutoTrim(t *testing.T) {
+	var capturedBody struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	ts, m := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&capturedBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, chatCompletionResponse("ok", nil))
+	})
+	defer ts.Close()
+	llm.RegisterContextWindow("test-autotrim-", 100)
+	m.model = "test-autotrim-model"
+
+	long := strings.Repeat("x", 80)
+	msgs := []schema.Message{
+		schema.NewSystemMessage("Be brief."),
+		schema.NewHumanMessage(long),
+		schema.NewAIMessage(long),
+		schema.NewHumanMessage("latest"),
+	}
+
+	if _, err := m.Generate(context.Background(), msgs, llm.WithMaxTokens(70)); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if len(capturedBody.Messages) != len(msgs) {
+		t.Errorf("sent %d messages without auto-trim, want %d", len(capturedBody.Messages), len(msgs))
+	}
+
+	if _, err := m.Generate(context.Background(), msgs, llm.WithMaxTokens(70), llm.WithAutoTrim()); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if len(capturedBody.Messages) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(capturedBody.Messages))
+	}
+	if capturedBody.Messages[0]["role"] != "system" || capturedBody.Messages[1]["content"] != "latest" {
+		t.Errorf("messages = %v", capturedBody.Messages)
+	}
+}
@@ -9,12 +9,19 @@ import (
 )
 
 // StreamToSeq converts an openai-go SSE stream into a Beluga iter.Seq2 of StreamChunks.
-// It handles text deltas, tool call accumulation by index, finish reasons, and usage.
+// It handles text deltas, tool call accumulation by index, finish reasons, usage,
+// and log probabilities, which each chunk carries for the tokens of its delta.
+//
+// Every tool call fragment is yielded with the ID and name of the call it
+// belongs to, even when the API sends them only with the call's first
+// fragment, so concatenating the Arguments of the fragments that share an ID
+// assembles each call's arguments, however the calls' deltas interleave.
 func StreamToSeq(stream *ssestream.Stream[openai.ChatCompletionChunk], modelID string) iter.Seq2[schema.StreamChunk, error] {
 	return func(yield func(schema.StreamChunk, error) bool) {
 		defer stream.Close()
+		var calls toolCallTracker
 		for stream.Next() {
-			sc := convertChunk(stream.Current(), modelID)
+			sc := convertChunk(stream.Current(), modelID, &calls)
 			if !yield(sc, nil) {
 				return
 			}
@@ -25,21 +32,19 @@ func StreamToSeq(stream *ssestream.Stream[openai.ChatCompletionChunk], modelID s
 	}
 }
 
-// convertChunk converts an OpenAI stream chunk to a Beluga StreamChunk.
-func convertChunk(chunk openai.ChatCompletionChunk, modelID string) schema.StreamChunk {
+// convertChunk converts an OpenAI stream chunk to a Beluga StreamChunk,
+// attributing its tool call deltas with calls.
+func convertChunk(chunk openai.ChatCompletionChunk, modelID string, calls *toolCallTracker) schema.StreamChunk {
 	sc := schema.StreamChunk{ModelID: modelID}
 	if len(chunk.Choices) > 0 {
 		delta := chunk.Choices[0].Delta
 		sc.Delta = delta.Content
 		sc.FinishReason = chunk.Choices[0].FinishReason
+		sc.LogProbs = convertLogProbs(chunk.Choices[0].Logprobs.Content)
 		if len(delta.ToolCalls) > 0 {
 			sc.ToolCalls = make([]schema.ToolCall, len(delta.ToolCalls))
 			for i, tc := range delta.ToolCalls {
-				sc.ToolCalls[i] = schema.ToolCall{
-					ID:        tc.ID,
-					Name:      tc.Function.Name,
-					Arguments: tc.Function.Arguments,
-				}
+				sc.ToolCalls[i] = calls.attribute(tc)
 			}
 		}
 	}
@@ -53,3 +58,35 @@ func convertChunk(chunk openai.ChatCompletionChunk, modelID string) schema.Strea
 	}
 	return sc
 }
+
+// toolCallTracker records the ID and name of each tool call of a stream,
+// keyed by the call's stream index.
+type toolCallTracker struct {
+	byIndex map[int64]*schema.ToolCall
+}
+
+// attribute returns the fragment tc with the ID and name of its call. A
+// fragment belongs to the call at its index, unless it carries a different
+// ID: some backends number every call 0, so a new ID at a known index starts
+// a new call.
+func (t *toolCallTracker) attribute(tc openai.ChatCompletionChunkChoiceDeltaToolCall) schema.ToolCall {
+	if t.byIndex == nil {
+		t.byIndex = make(map[int64]*schema.ToolCall)
+	}
+	call, ok := t.byIndex[tc.Index]
+	if !ok || (tc.ID != "" && call.ID != "" && tc.ID != call.ID) {
+		call = &schema.ToolCall{}
+		t.byIndex[tc.Index] = call
+	}
+	if call.ID == "" {
+		call.ID = tc.ID
+	}
+	if call.Name == "" {
+		call.Name = tc.Function.Name
+	}
+	return schema.ToolCall{
+		ID:        call.ID,
+		Name:      call.Name,
+		Arguments: tc.Function.Arguments,
+	}
+}
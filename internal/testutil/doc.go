@@ -42,4 +42,5 @@
 //   - [github.com/lookatitude/beluga-ai/v2/internal/testutil/mockembedder] — mock Embedder
 //   - [github.com/lookatitude/beluga-ai/v2/internal/testutil/mockstore] — mock VectorStore
 //   - [github.com/lookatitude/beluga-ai/v2/internal/testutil/mockworkflow] — mock WorkflowStore
+//   - [github.com/lookatitude/beluga-ai/v2/internal/testutil/mocks2s] — mock S2S engine
 package testutil
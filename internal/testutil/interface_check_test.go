@@ -2,10 +2,12 @@ package testutil
 
 import (
 	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mockembedder"
+	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mocks2s"
 	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mockstore"
 	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mockworkflow"
 	"github.com/lookatitude/beluga-ai/v2/rag/embedding"
 	"github.com/lookatitude/beluga-ai/v2/rag/vectorstore"
+	"github.com/lookatitude/beluga-ai/v2/voice/s2s"
 	"github.com/lookatitude/beluga-ai/v2/workflow"
 )
 
@@ -14,4 +16,5 @@ var (
 	_ embedding.Embedder      = (*mockembedder.MockEmbedder)(nil)
 	_ vectorstore.VectorStore = (*mockstore.MockVectorStore)(nil)
 	_ workflow.WorkflowStore  = (*mockworkflow.MockWorkflowStore)(nil)
+	_ s2s.S2S                 = (*mocks2s.MockEngine)(nil)
 )
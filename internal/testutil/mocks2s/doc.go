@@ -0,0 +1,34 @@
+// Package mocks2s provides an in-memory S2S engine for testing code that
+// depends on the voice/s2s package, such as hybrid pipelines and
+// s2s.AsFrameProcessor wiring, without provider credentials.
+//
+// This is an internal package and is not part of the public API.
+//
+// # MockEngine
+//
+// [MockEngine] implements s2s.S2S. Its sessions answer each SendAudio and
+// SendText call with scripted events; with no script they echo the input
+// back as EventAudioOutput or EventTextOutput followed by EventTurnEnd:
+//
+//	engine := mocks2s.New(
+//	    mocks2s.WithAudioResponse(
+//	        s2s.SessionEvent{Type: s2s.EventTranscript, Text: "weather?"},
+//	        s2s.SessionEvent{Type: s2s.EventToolCall, ToolCall: &schema.ToolCall{ID: "1", Name: "weather"}},
+//	        s2s.SessionEvent{Type: s2s.EventTurnEnd},
+//	    ),
+//	    mocks2s.WithLatency(10*time.Millisecond),
+//	)
+//	proc := s2s.AsFrameProcessor(engine)
+//
+// Configure error injection:
+//
+//	mocks2s.New(mocks2s.WithStartError(err))  // Start fails
+//	mocks2s.New(mocks2s.WithSendError(err))   // SendAudio/SendText fail
+//	mocks2s.New(mocks2s.WithDropAfter(3, err)) // connection drops after 3 events
+//
+// Interrupt discards scripted events not yet delivered and, like the real
+// providers, ends an interrupted response with EventTurnEnd. Sessions record
+// their input for assertions ([MockSession.AudioSent], [MockSession.TextSent],
+// [MockSession.ToolResults], [MockSession.Interrupts]); [MockEngine.Sessions]
+// returns every session started. The mock is safe for concurrent use.
+package mocks2s
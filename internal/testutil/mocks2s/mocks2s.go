@@ -0,0 +1,401 @@
+package mocks2s
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/voice/s2s"
+)
+
+// errClosed is returned by sends on a closed or dropped session.
+var errClosed = errors.New("mocks2s: session closed")
+
+// MockEngine is a scripted, in-memory implementation of s2s.S2S.
+type MockEngine struct {
+	mu sync.Mutex
+
+	audioResponse      []s2s.SessionEvent
+	textResponse       []s2s.SessionEvent
+	toolResultResponse []s2s.SessionEvent
+	latency            time.Duration
+	startErr           error
+	sendErr            error
+	dropAfter          int
+	dropErr            error
+	format             s2s.AudioFormat
+
+	sessions []*MockSession
+	configs  []s2s.Config
+}
+
+var _ s2s.S2S = (*MockEngine)(nil)
+
+// Option configures a MockEngine.
+type Option func(*MockEngine)
+
+// New creates a MockEngine with the given options.
+func New(opts ...Option) *MockEngine {
+	e := &MockEngine{
+		format: s2s.AudioFormat{SampleRate: 24000, Encoding: s2s.EncodingPCM16},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// WithAudioResponse sets the events emitted after each SendAudio call,
+// replacing the default echo.
+func WithAudioResponse(events ...s2s.SessionEvent) Option {
+	return func(e *MockEngine) {
+		e.audioResponse = events
+	}
+}
+
+// WithTextResponse sets the events emitted after each SendText call,
+// replacing the default echo.
+func WithTextResponse(events ...s2s.SessionEvent) Option {
+	return func(e *MockEngine) {
+		e.textResponse = events
+	}
+}
+
+// WithToolResultResponse sets the events emitted after each SendToolResult
+// call. By default nothing is emitted.
+func WithToolResultResponse(events ...s2s.SessionEvent) Option {
+	return func(e *MockEngine) {
+		e.toolResultResponse = events
+	}
+}
+
+// WithLatency delays each emitted event by d.
+func WithLatency(d time.Duration) Option {
+	return func(e *MockEngine) {
+		e.latency = d
+	}
+}
+
+// WithStartError makes Start fail with err.
+func WithStartError(err error) Option {
+	return func(e *MockEngine) {
+		e.startErr = err
+	}
+}
+
+// WithSendError makes SendAudio and SendText fail with err.
+func WithSendError(err error) Option {
+	return func(e *MockEngine) {
+		e.sendErr = err
+	}
+}
+
+// WithDropAfter simulates a dropped connection: after n events a session
+// emits an EventError carrying err and ends its event stream, the way the
+// real providers report a lost connection.
+func WithDropAfter(n int, err error) Option {
+	return func(e *MockEngine) {
+		e.dropAfter = n
+		e.dropErr = err
+	}
+}
+
+// WithFormat sets the native format reported by the sessions' InputFormat
+// and OutputFormat. The default is 24 kHz PCM16.
+func WithFormat(f s2s.AudioFormat) Option {
+	return func(e *MockEngine) {
+		e.format = f
+	}
+}
+
+// Start creates a new MockSession. Start options are recorded and applied
+// the way a real provider applies them, including s2s.WithAudioFormat and
+// s2s.WithToolRegistry.
+func (e *MockEngine) Start(ctx context.Context, opts ...s2s.Option) (s2s.Session, error) {
+	cfg := s2s.ApplyOptions(opts...)
+
+	e.mu.Lock()
+	e.configs = append(e.configs, cfg)
+	if e.startErr != nil {
+		e.mu.Unlock()
+		return nil, e.startErr
+	}
+	sess := &MockSession{
+		engine: e,
+		queue:  make(chan batch, 64),
+		events: make(chan s2s.SessionEvent, 64),
+		done:   make(chan struct{}),
+	}
+	e.sessions = append(e.sessions, sess)
+	e.mu.Unlock()
+
+	go sess.run()
+	return s2s.WrapSession(sess, cfg)
+}
+
+// Sessions returns the sessions started so far, oldest first.
+func (e *MockEngine) Sessions() []*MockSession {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*MockSession(nil), e.sessions...)
+}
+
+// Configs returns the Config of each Start call, oldest first.
+func (e *MockEngine) Configs() []s2s.Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]s2s.Config(nil), e.configs...)
+}
+
+// batch is a scripted response queued by one send.
+type batch struct {
+	events []s2s.SessionEvent
+	gen    int
+}
+
+// MockSession is the s2s.Session created by MockEngine.
+type MockSession struct {
+	engine *MockEngine
+	queue  chan batch
+	events chan s2s.SessionEvent
+	done   chan struct{}
+	once   sync.Once
+
+	mu          sync.Mutex
+	gen         int  // incremented by Interrupt
+	responding  bool // output delivered since the last EventTurnEnd
+	emitted     int
+	dropped     bool
+	audio       [][]byte
+	text        []string
+	toolResults []schema.ToolResult
+	interrupts  int
+}
+
+var _ s2s.Session = (*MockSession)(nil)
+
+// run delivers queued responses. It is the sole writer to s.events.
+func (s *MockSession) run() {
+	defer close(s.events)
+	e := s.engine
+	for {
+		var b batch
+		select {
+		case b = <-s.queue:
+		case <-s.done:
+			return
+		}
+		for _, ev := range b.events {
+			if e.latency > 0 {
+				select {
+				case <-time.After(e.latency):
+				case <-s.done:
+					return
+				}
+			}
+			if !s.deliver(b.gen, ev) {
+				return
+			}
+		}
+	}
+}
+
+// deliver emits ev unless it was interrupted. It returns false once the
+// stream must end.
+func (s *MockSession) deliver(gen int, ev s2s.SessionEvent) bool {
+	s.mu.Lock()
+	if gen != s.gen {
+		s.mu.Unlock()
+		return true
+	}
+	drop := s.engine.dropAfter > 0 && s.emitted >= s.engine.dropAfter
+	if drop {
+		s.dropped = true
+		ev = s2s.SessionEvent{Type: s2s.EventError, Error: s.engine.dropErr}
+	}
+	s.emitted++
+	switch ev.Type {
+	case s2s.EventAudioOutput, s2s.EventTextOutput:
+		s.responding = true
+	case s2s.EventTurnEnd:
+		s.responding = false
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.events <- ev:
+	case <-s.done:
+		return false
+	}
+	return !drop
+}
+
+// respond queues a scripted response to one send.
+func (s *MockSession) respond(events []s2s.SessionEvent) {
+	if len(events) == 0 {
+		return
+	}
+	s.mu.Lock()
+	b := batch{events: events, gen: s.gen}
+	s.mu.Unlock()
+	select {
+	case s.queue <- b:
+	case <-s.done:
+	}
+}
+
+// checkSend reports the error a send should return, if any.
+func (s *MockSession) checkSend() error {
+	select {
+	case <-s.done:
+		return errClosed
+	default:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dropped {
+		return errClosed
+	}
+	return s.engine.sendErr
+}
+
+// SendAudio records audio and queues the audio response.
+func (s *MockSession) SendAudio(_ context.Context, audio []byte) error {
+	if err := s.checkSend(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.audio = append(s.audio, append([]byte(nil), audio...))
+	s.mu.Unlock()
+
+	events := s.engine.audioResponse
+	if events == nil {
+		events = []s2s.SessionEvent{
+			{Type: s2s.EventAudioOutput, Audio: append([]byte(nil), audio...)},
+			{Type: s2s.EventTurnEnd},
+		}
+	}
+	s.respond(events)
+	return nil
+}
+
+// SendText records text and queues the text response.
+func (s *MockSession) SendText(_ context.Context, text string) error {
+	if err := s.checkSend(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.text = append(s.text, text)
+	s.mu.Unlock()
+
+	events := s.engine.textResponse
+	if events == nil {
+		events = []s2s.SessionEvent{
+			{Type: s2s.EventTextOutput, Text: text},
+			{Type: s2s.EventTurnEnd},
+		}
+	}
+	s.respond(events)
+	return nil
+}
+
+// SendToolResult records result and queues the tool result response.
+func (s *MockSession) SendToolResult(_ context.Context, result schema.ToolResult) error {
+	select {
+	case <-s.done:
+		return errClosed
+	default:
+	}
+	s.mu.Lock()
+	s.toolResults = append(s.toolResults, result)
+	s.mu.Unlock()
+	s.respond(s.engine.toolResultResponse)
+	return nil
+}
+
+// Recv returns an iterator over the session's events. It ends when the
+// session is closed, the connection is dropped, or ctx is cancelled.
+func (s *MockSession) Recv(ctx context.Context) iter.Seq2[s2s.SessionEvent, error] {
+	return func(yield func(s2s.SessionEvent, error) bool) {
+		for {
+			select {
+			case ev, ok := <-s.events:
+				if !ok {
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Interrupt discards scripted events not yet delivered. Like the real
+// providers, it ends a response that was being delivered with EventTurnEnd.
+func (s *MockSession) Interrupt(_ context.Context) error {
+	s.mu.Lock()
+	s.interrupts++
+	s.gen++
+	responding := s.responding
+	s.mu.Unlock()
+	if responding {
+		s.respond([]s2s.SessionEvent{{Type: s2s.EventTurnEnd}})
+	}
+	return nil
+}
+
+// InputFormat reports the engine's configured format.
+func (s *MockSession) InputFormat() s2s.AudioFormat { return s.engine.format }
+
+// OutputFormat reports the engine's configured format.
+func (s *MockSession) OutputFormat() s2s.AudioFormat { return s.engine.format }
+
+// Close ends the session. It is idempotent.
+func (s *MockSession) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
+
+// Closed reports whether Close has been called.
+func (s *MockSession) Closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// AudioSent returns the audio chunks passed to SendAudio.
+func (s *MockSession) AudioSent() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.audio...)
+}
+
+// TextSent returns the text passed to SendText.
+func (s *MockSession) TextSent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.text...)
+}
+
+// ToolResults returns the results passed to SendToolResult.
+func (s *MockSession) ToolResults() []schema.ToolResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]schema.ToolResult(nil), s.toolResults...)
+}
+
+// Interrupts returns the number of Interrupt calls.
+func (s *MockSession) Interrupts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interrupts
+}
@@ -0,0 +1,267 @@
+package mocks2s
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/voice"
+	"github.com/lookatitude/beluga-ai/v2/voice/s2s"
+)
+
+// recvN reads n events from sess, failing the test after a timeout.
+func recvN(t *testing.T, sess s2s.Session, n int) []s2s.SessionEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var events []s2s.SessionEvent
+	if n == 0 {
+		return events
+	}
+	for ev, err := range sess.Recv(ctx) {
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		events = append(events, ev)
+		if len(events) == n {
+			return events
+		}
+	}
+	t.Fatalf("got %d events, want %d", len(events), n)
+	return nil
+}
+
+func TestMockEngine_Echo(t *testing.T) {
+	ctx := context.Background()
+	engine := New()
+	sess, err := engine.Start(ctx, s2s.WithInstructions("be brief"))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer sess.Close()
+
+	if err := sess.SendAudio(ctx, []byte{1, 2}); err != nil {
+		t.Fatalf("SendAudio: %v", err)
+	}
+	if err := sess.SendText(ctx, "hi"); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+
+	events := recvN(t, sess, 4)
+	want := []s2s.SessionEventType{s2s.EventAudioOutput, s2s.EventTurnEnd, s2s.EventTextOutput, s2s.EventTurnEnd}
+	for i, ev := range events {
+		if ev.Type != want[i] {
+			t.Errorf("event %d = %q, want %q", i, ev.Type, want[i])
+		}
+	}
+	if string(events[0].Audio) != "\x01\x02" || events[2].Text != "hi" {
+		t.Errorf("echo mismatch: %v %q", events[0].Audio, events[2].Text)
+	}
+
+	mock := engine.Sessions()[0]
+	if got := mock.TextSent(); len(got) != 1 || got[0] != "hi" {
+		t.Errorf("TextSent = %v", got)
+	}
+	if got := mock.AudioSent(); len(got) != 1 {
+		t.Errorf("AudioSent = %v", got)
+	}
+	if got := engine.Configs()[0].Instructions; got != "be brief" {
+		t.Errorf("Instructions = %q", got)
+	}
+}
+
+func TestMockEngine_ScriptedWithLatency(t *testing.T) {
+	ctx := context.Background()
+	engine := New(
+		WithAudioResponse(
+			s2s.SessionEvent{Type: s2s.EventTranscript, Text: "weather?"},
+			s2s.SessionEvent{Type: s2s.EventToolCall, ToolCall: &schema.ToolCall{ID: "1", Name: "weather"}},
+		),
+		WithToolResultResponse(
+			s2s.SessionEvent{Type: s2s.EventTextOutput, Text: "sunny"},
+			s2s.SessionEvent{Type: s2s.EventTurnEnd},
+		),
+		WithLatency(20*time.Millisecond),
+	)
+	sess, err := engine.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer sess.Close()
+
+	start := time.Now()
+	_ = sess.SendAudio(ctx, []byte{0, 0})
+	events := recvN(t, sess, 2)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("events arrived after %v, want >= 40ms", elapsed)
+	}
+	if events[1].ToolCall == nil || events[1].ToolCall.Name != "weather" {
+		t.Fatalf("event 1 = %+v, want weather tool call", events[1])
+	}
+
+	_ = sess.SendToolResult(ctx, schema.ToolResult{CallID: "1"})
+	events = recvN(t, sess, 2)
+	if events[0].Text != "sunny" || events[1].Type != s2s.EventTurnEnd {
+		t.Errorf("tool result response = %+v", events)
+	}
+	if got := engine.Sessions()[0].ToolResults(); len(got) != 1 || got[0].CallID != "1" {
+		t.Errorf("ToolResults = %v", got)
+	}
+}
+
+func TestMockEngine_InterruptDiscardsPending(t *testing.T) {
+	ctx := context.Background()
+	engine := New(
+		WithTextResponse(
+			s2s.SessionEvent{Type: s2s.EventTextOutput, Text: "one"},
+			s2s.SessionEvent{Type: s2s.EventTextOutput, Text: "two"},
+			s2s.SessionEvent{Type: s2s.EventTextOutput, Text: "three"},
+		),
+		WithLatency(30*time.Millisecond),
+	)
+	sess, _ := engine.Start(ctx)
+	defer sess.Close()
+
+	_ = sess.SendText(ctx, "go")
+	first := recvN(t, sess, 1)
+	if first[0].Text != "one" {
+		t.Fatalf("first = %q", first[0].Text)
+	}
+	_ = sess.Interrupt(ctx)
+
+	// The interruption is confirmed, then the next response arrives
+	// without the rest of the interrupted one.
+	_ = sess.SendText(ctx, "again")
+	next := recvN(t, sess, 3)
+	if next[0].Type != s2s.EventInterrupted || !next[0].Interruption.FlushedOutput {
+		t.Errorf("after interrupt got %+v, want flushed EventInterrupted", next[0])
+	}
+	if next[1].Type != s2s.EventTurnEnd {
+		t.Errorf("after interrupt got %q, want %q", next[1].Type, s2s.EventTurnEnd)
+	}
+	if next[2].Text != "one" {
+		t.Errorf("after interrupt got %q, want %q", next[2].Text, "one")
+	}
+	if got := engine.Sessions()[0].Interrupts(); got != 1 {
+		t.Errorf("Interrupts = %d, want 1", got)
+	}
+}
+
+func TestMockEngine_Errors(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	if _, err := New(WithStartError(boom)).Start(ctx); !errors.Is(err, boom) {
+		t.Errorf("Start error = %v, want boom", err)
+	}
+
+	sess, _ := New(WithSendError(boom)).Start(ctx)
+	if err := sess.SendAudio(ctx, []byte{1}); !errors.Is(err, boom) {
+		t.Errorf("SendAudio error = %v, want boom", err)
+	}
+	if err := sess.SendText(ctx, "x"); !errors.Is(err, boom) {
+		t.Errorf("SendText error = %v, want boom", err)
+	}
+	_ = sess.Close()
+	if err := sess.SendText(ctx, "x"); err == nil {
+		t.Error("expected send to fail after Close")
+	}
+}
+
+func TestMockEngine_DropAfter(t *testing.T) {
+	ctx := context.Background()
+	lost := errors.New("connection reset")
+	engine := New(WithDropAfter(2, lost))
+	sess, _ := engine.Start(ctx)
+	defer sess.Close()
+
+	_ = sess.SendText(ctx, "a")
+	_ = sess.SendText(ctx, "b")
+
+	var events []s2s.SessionEvent
+	for ev, err := range sess.Recv(ctx) {
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if last := events[2]; last.Type != s2s.EventError || !errors.Is(last.Error, lost) {
+		t.Errorf("last event = %+v, want connection error", last)
+	}
+	if err := sess.SendText(ctx, "c"); err == nil {
+		t.Error("expected send to fail after drop")
+	}
+}
+
+func TestMockEngine_AsFrameProcessor(t *testing.T) {
+	engine := New(WithFormat(s2s.AudioFormat{SampleRate: 16000}))
+	proc := s2s.AsFrameProcessor(engine)
+
+	in := func(yield func(voice.Frame, error) bool) {
+		yield(voice.NewAudioFrame([]byte{7, 0}, 16000), nil)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var frames []voice.Frame
+	for frame, err := range proc.Process(ctx, iter.Seq2[voice.Frame, error](in)) {
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		frames = append(frames, frame)
+		if frame.Type == voice.FrameControl {
+			break
+		}
+	}
+	if len(frames) < 1 || frames[0].Type != voice.FrameAudio {
+		t.Fatalf("frames = %+v, want audio first", frames)
+	}
+	if got := frames[0].Metadata["sample_rate"]; got != 16000 {
+		t.Errorf("sample_rate = %v, want 16000", got)
+	}
+	if !engine.Sessions()[0].Closed() {
+		t.Error("session not closed after processing")
+	}
+}
+
+func TestMockEngine_HybridSwitchPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		toolCalls int
+		wantMode  voice.PipelineMode
+		sessions  int
+	}{
+		{name: "stays in s2s", toolCalls: 1, wantMode: voice.ModeS2S, sessions: 1},
+		{name: "switches to cascade", toolCalls: 3, wantMode: voice.ModeCascade, sessions: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := New()
+			hp := voice.NewHybridPipeline(
+				voice.WithS2S(s2s.AsFrameProcessor(engine)),
+				voice.WithHybridSession(voice.NewSession("hybrid")),
+			)
+			hp.UpdateState(tt.toolCalls, 1)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			// Neither mode runs to completion here: the S2S session waits
+			// for input until ctx expires, and no cascade is configured.
+			if err := hp.Run(ctx); err == nil {
+				t.Fatal("expected Run to fail")
+			}
+			if got := hp.CurrentMode(); got != tt.wantMode {
+				t.Errorf("CurrentMode = %q, want %q", got, tt.wantMode)
+			}
+			if got := len(engine.Sessions()); got != tt.sessions {
+				t.Errorf("sessions started = %d, want %d", got, tt.sessions)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// BatchRequest is one request of a batch submitted with BatchGenerate.
+type BatchRequest struct {
+	// CustomID identifies the request within the batch. It must be non-empty
+	// and unique; the matching BatchResult carries the same ID.
+	CustomID string
+	// Messages is the conversation to send.
+	Messages []schema.Message
+	// Options configure the request as they would a Generate call.
+	Options []GenerateOption
+}
+
+// BatchResult is the outcome of one BatchRequest.
+type BatchResult struct {
+	// CustomID is the CustomID of the request.
+	CustomID string
+	// Response is the model's response. Nil if Err is set.
+	Response *schema.AIMessage
+	// Err is the error of this request, if it failed, expired or was
+	// cancelled.
+	Err error
+}
+
+// BatchGenerator is implemented by ChatModels whose provider offers an
+// asynchronous batch API, which processes large offline jobs at a discount
+// in exchange for latency of up to a day. Callers type-assert a ChatModel
+// to find out whether it supports batches:
+//
+//	if bg, ok := model.(llm.BatchGenerator); ok {
+//	    results, err := bg.BatchGenerate(ctx, requests)
+//	}
+type BatchGenerator interface {
+	// BatchGenerate submits requests as one batch, waits for the batch to
+	// finish and returns a result for each request, in request order. It
+	// returns an error only if the batch as a whole fails; failures of
+	// individual requests are reported in their BatchResult. Cancelling ctx
+	// stops waiting but does not cancel the submitted batch.
+	BatchGenerate(ctx context.Context, requests []BatchRequest) ([]BatchResult, error)
+}
+
+// ValidateBatch checks that requests is non-empty and that every request
+// has a unique, non-empty CustomID, as BatchGenerate requires. It is
+// intended for BatchGenerator implementations.
+func ValidateBatch(requests []BatchRequest) error {
+	if len(requests) == 0 {
+		return core.Errorf(core.ErrInvalidInput, "llm: batch has no requests")
+	}
+	seen := make(map[string]bool, len(requests))
+	for i, req := range requests {
+		if req.CustomID == "" {
+			return core.Errorf(core.ErrInvalidInput, "llm: batch request %d has no custom ID", i)
+		}
+		if seen[req.CustomID] {
+			return core.Errorf(core.ErrInvalidInput, "llm: duplicate batch custom ID %q", req.CustomID)
+		}
+		seen[req.CustomID] = true
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package llm
+
+import (
+	"testing"
+)
+
+func TestValidateBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []BatchRequest
+		wantErr  bool
+	}{
+		{name: "valid", requests: []BatchRequest{{CustomID: "a"}, {CustomID: "b"}}},
+		{name: "empty", wantErr: true},
+		{name: "missing ID", requests: []BatchRequest{{CustomID: "a"}, {}}, wantErr: true},
+		{name: "duplicate ID", requests: []BatchRequest{{CustomID: "a"}, {CustomID: "a"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBatch(tt.requests)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
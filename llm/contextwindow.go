@@ -0,0 +1,148 @@
+package llm
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// defaultOutputReserve is the room TrimToContextWindow leaves for the
+// response when the request sets no max tokens.
+const defaultOutputReserve = 4096
+
+var (
+	contextWindowMu sync.RWMutex
+	// contextWindows maps model name prefixes to context window sizes in
+	// tokens. The longest matching prefix wins.
+	contextWindows = map[string]int{
+		// OpenAI
+		"gpt-3.5-turbo": 16385,
+		"gpt-4":         8192,
+		"gpt-4-turbo":   128000,
+		"gpt-4o":        128000,
+		"gpt-4.1":       1047576,
+		"gpt-5":         400000,
+		"o1":            200000,
+		"o3":            200000,
+		"o4-mini":       200000,
+		// Anthropic
+		"claude-": 200000,
+		// Google
+		"gemini-1.5-flash": 1048576,
+		"gemini-1.5-pro":   2097152,
+		"gemini-2":         1048576,
+		// Cohere
+		"command-r": 128000,
+		"command-a": 256000,
+		// Mistral
+		"mistral-large":  131072,
+		"mistral-medium": 131072,
+		"mistral-small":  32768,
+		"codestral":      256000,
+		// Others
+		"deepseek-": 65536,
+		"grok-":     131072,
+		"grok-4":    256000,
+		"llama-3.1": 131072,
+		"llama-3.3": 131072,
+		"qwen-":     131072,
+	}
+)
+
+// RegisterContextWindow sets the context window size, in tokens, of the
+// models whose name starts with prefix. Registering a prefix again replaces
+// its size.
+func RegisterContextWindow(prefix string, tokens int) {
+	contextWindowMu.Lock()
+	defer contextWindowMu.Unlock()
+	contextWindows[prefix] = tokens
+}
+
+// ContextWindow returns the context window size, in tokens, registered for
+// model under the longest matching prefix. Provider and region prefixes
+// separated by "/" or ".", as in "openai/gpt-4o" or
+// "us.anthropic.claude-sonnet-4-5", are skipped when the full name does not
+// match. It reports false for unknown models.
+func ContextWindow(model string) (int, bool) {
+	contextWindowMu.RLock()
+	defer contextWindowMu.RUnlock()
+	for {
+		bestLen := -1
+		var best int
+		for prefix, n := range contextWindows {
+			if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+				best, bestLen = n, len(prefix)
+			}
+		}
+		if bestLen >= 0 {
+			return best, true
+		}
+		i := strings.IndexAny(model, "/.")
+		if i < 0 {
+			return 0, false
+		}
+		model = model[i+1:]
+	}
+}
+
+// TrimToContextWindow implements WithAutoTrim for providers, which call it
+// on the messages of a request before converting them. Unless opts enable
+// auto-trim, or when model's context window is unknown, it returns msgs
+// unchanged. Otherwise it estimates the tokens of msgs with the tokenizer
+// registered for model and, if they do not fit the window less the room
+// reserved for the response (opts.MaxTokens, or 4096 tokens), drops the
+// oldest non-system messages, along with tool results whose calls were
+// dropped, and logs a warning.
+func TrimToContextWindow(ctx context.Context, model string, msgs []schema.Message, opts GenerateOptions) []schema.Message {
+	if !opts.AutoTrim {
+		return msgs
+	}
+	window, ok := ContextWindow(model)
+	if !ok {
+		return msgs
+	}
+	reserve := opts.MaxTokens
+	if reserve <= 0 {
+		reserve = min(defaultOutputReserve, window/4)
+	}
+	budget := window - reserve
+	if budget <= 0 {
+		return msgs
+	}
+
+	tokenizer := TokenizerFor(model)
+	strategy := &TruncateStrategy{tokenizer: tokenizer, keepSystem: true}
+	fitted, err := strategy.Fit(ctx, msgs, budget)
+	if err != nil || len(fitted) == len(msgs) {
+		return msgs
+	}
+	fitted = dropOrphanedToolResults(fitted)
+
+	slog.WarnContext(ctx, "llm: dropped oldest messages to fit the context window",
+		"model", model,
+		"dropped", len(msgs)-len(fitted),
+		"estimated_tokens", tokenizer.CountMessages(msgs),
+		"context_window", window,
+	)
+	return fitted
+}
+
+// dropOrphanedToolResults removes the tool results that lead the
+// non-system messages of msgs, whose calls were trimmed away.
+func dropOrphanedToolResults(msgs []schema.Message) []schema.Message {
+	i := 0
+	for i < len(msgs) && msgs[i].GetRole() == schema.RoleSystem {
+		i++
+	}
+	j := i
+	for j < len(msgs) && msgs[j].GetRole() == schema.RoleTool {
+		j++
+	}
+	if j == i {
+		return msgs
+	}
+	return append(msgs[:i:i], msgs[j:]...)
+}
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model  string
+		want   int
+		wantOK bool
+	}{
+		{model: "gpt-4o-mini", want: 128000, wantOK: true},
+		{model: "gpt-4-0613", want: 8192, wantOK: true},
+		{model: "gpt-4.1-mini", want: 1047576, wantOK: true},
+		{model: "claude-sonnet-4-5-20250929", want: 200000, wantOK: true},
+		{model: "openai/gpt-4o", want: 128000, wantOK: true},
+		{model: "us.anthropic.claude-sonnet-4-5-20250929-v1:0", want: 200000, wantOK: true},
+		{model: "my-fine-tune", wantOK: false},
+		{model: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got, ok := ContextWindow(tt.model)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ContextWindow(%q) = %d, %v, want %d, %v", tt.model, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRegisterContextWindow(t *testing.T) {
+	RegisterContextWindow("test-register-", 1000)
+	RegisterContextWindow("test-register-large", 5000)
+	if got, _ := ContextWindow("test-register-small"); got != 1000 {
+		t.Errorf("ContextWindow = %d, want 1000", got)
+	}
+	if got, _ := ContextWindow("test-register-large-v2"); got != 5000 {
+		t.Errorf("ContextWindow = %d, want 5000", got)
+	}
+}
+
+// captureWarnings redirects the default logger to a buffer for the rest of
+// the test.
+func captureWarnings(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestTrimToContextWindow(t *testing.T) {
+	RegisterContextWindow("test-trim-", 200)
+	// 197 tokens: 5 for the system message and 24 for each of the others.
+	msgs := []schema.Message{schema.NewSystemMessage("s")}
+	for range 8 {
+		msgs = append(msgs, schema.NewHumanMessage(strings.Repeat("x", 80)))
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		got := TrimToContextWindow(context.Background(), "test-trim-model", msgs, ApplyOptions(WithMaxTokens(160)))
+		if len(got) != len(msgs) {
+			t.Errorf("len = %d, want %d", len(got), len(msgs))
+		}
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		got := TrimToContextWindow(context.Background(), "unknown-model", msgs, ApplyOptions(WithAutoTrim(), WithMaxTokens(60)))
+		if len(got) != len(msgs) {
+			t.Errorf("len = %d, want %d", len(got), len(msgs))
+		}
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		logs := captureWarnings(t)
+		got := TrimToContextWindow(context.Background(), "test-trim-model", msgs, ApplyOptions(WithAutoTrim(), WithMaxTokens(1)))
+		if len(got) != len(msgs) {
+			t.Errorf("len = %d, want %d", len(got), len(msgs))
+		}
+		if logs.Len() != 0 {
+			t.Errorf("unexpected warning: %s", logs)
+		}
+	})
+
+	t.Run("trims oldest", func(t *testing.T) {
+		logs := captureWarnings(t)
+		// 40 tokens left for messages: the system message and one more.
+		got := TrimToContextWindow(context.Background(), "test-trim-model", msgs, ApplyOptions(WithAutoTrim(), WithMaxTokens(160)))
+		if len(got) != 2 || got[0] != msgs[0] || got[1] != msgs[8] {
+			t.Errorf("got %v, want the system and last messages", got)
+		}
+		if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "dropped=7") {
+			t.Errorf("warning = %q", logs)
+		}
+	})
+
+	t.Run("default reserve", func(t *testing.T) {
+		captureWarnings(t)
+		// Without max tokens, a quarter of the window is reserved.
+		got := TrimToContextWindow(context.Background(), "test-trim-model", msgs, ApplyOptions(WithAutoTrim()))
+		if len(got) != 7 || got[1] != msgs[3] {
+			t.Errorf("got %d messages, want the system and last six", len(got))
+		}
+	})
+}
+
+func TestTrimToContextWindow_OrphanedToolResults(t *testing.T) {
+	captureWarnings(t)
+	RegisterContextWindow("test-orphan-", 100)
+	call := &schema.AIMessage{ToolCalls: []schema.ToolCall{{ID: "call_1", Name: "search"}}}
+	last := schema.NewHumanMessage(strings.Repeat("x", 80))
+	msgs := []schema.Message{
+		schema.NewSystemMessage("s"),
+		schema.NewHumanMessage(strings.Repeat("x", 80)),
+		call,
+		schema.NewToolMessage("call_1", "ok"),
+		last,
+	}
+	// 30 tokens left after the system message: the tool call is dropped
+	// but its result would fit.
+	got := TrimToContextWindow(context.Background(), "test-orphan-model", msgs, ApplyOptions(WithAutoTrim(), WithMaxTokens(65)))
+	if len(got) != 2 || got[0] != msgs[0] || got[1] != last {
+		t.Errorf("got %v, want the system and last messages", got)
+	}
+}
@@ -75,12 +75,24 @@
 //	)
 //	fitted, err := cm.Fit(ctx, msgs, 4096)
 //
+// Providers can also trim for the caller: with [WithAutoTrim], openaicompat
+// based providers and the anthropic, bedrock, cohere, google and mistral
+// providers drop the oldest non-system messages that would overflow the
+// model's context window, logging a warning, instead of failing with a
+// context length error. Window sizes come from a per-model table, looked up
+// with [ContextWindow] and extended with [RegisterContextWindow]:
+//
+//	llm.RegisterContextWindow("my-fine-tune", 32768)
+//	resp, err := model.Generate(ctx, msgs, llm.WithAutoTrim())
+//
 // # Tokenizer
 //
 // [Tokenizer] provides token counting and encoding/decoding.
 // [SimpleTokenizer] is a built-in word-based approximation (1 token per
 // 4 characters) suitable for budget estimation when a model-specific
-// tokenizer is unavailable.
+// tokenizer is unavailable. Model-specific tokenizers are registered by
+// model name prefix with [RegisterTokenizer] and looked up with
+// [TokenizerFor], which falls back to SimpleTokenizer.
 //
 // # Routing
 //
@@ -103,7 +115,22 @@
 //
 // [GenerateOption] functional options configure individual Generate/Stream
 // calls: temperature, max tokens, top-p, stop sequences, response format,
-// tool choice, and provider-specific metadata.
+// tool choice, log probabilities, and provider-specific metadata.
+//
+// # Batch Generation
+//
+// Models whose provider offers a discounted asynchronous batch API
+// implement [BatchGenerator]. BatchGenerate submits [BatchRequest] values,
+// each with a unique custom ID, waits for the batch to finish and returns a
+// [BatchResult] per request in request order. Middleware does not forward
+// BatchGenerate, so type-assert the provider's model itself:
+//
+//	if bg, ok := model.(llm.BatchGenerator); ok {
+//	    results, err := bg.BatchGenerate(ctx, []llm.BatchRequest{
+//	        {CustomID: "q1", Messages: msgs1},
+//	        {CustomID: "q2", Messages: msgs2},
+//	    })
+//	}
 //
 // # Streaming
 //
@@ -69,6 +69,14 @@ type GenerateOptions struct {
 	// Reasoning configures reasoning/chain-of-thought behaviour. Nil means
 	// no reasoning configuration (provider default).
 	Reasoning *ReasoningConfig
+	// LogProbs requests the log probability of each generated token.
+	LogProbs bool
+	// TopLogProbs is the number of most likely alternatives to return for
+	// each token position, when LogProbs is set. 0 means none.
+	TopLogProbs int
+	// AutoTrim drops the oldest messages of a request that would overflow
+	// the model's context window. See WithAutoTrim.
+	AutoTrim bool
 	// Metadata holds provider-specific options that don't map to standard fields.
 	Metadata map[string]any
 }
@@ -131,6 +139,29 @@ func WithSpecificTool(name string) GenerateOption {
 	}
 }
 
+// WithLogProbs requests the log probability of each generated token, and
+// of the topN most likely alternatives at each position, which providers
+// that support it return in AIMessage.LogProbs and StreamChunk.LogProbs.
+// Pass 0 for no alternatives.
+func WithLogProbs(topN int) GenerateOption {
+	return func(o *GenerateOptions) {
+		o.LogProbs = true
+		o.TopLogProbs = topN
+	}
+}
+
+// WithAutoTrim makes the provider drop the oldest non-system messages of a
+// conversation that would not fit the model's context window, leaving room
+// for the response, instead of failing with a context length error. Token
+// counts are estimated with the tokenizer registered for the model, and a
+// warning is logged when messages are dropped. Models whose context window
+// is unknown (see ContextWindow) are sent the conversation unchanged.
+func WithAutoTrim() GenerateOption {
+	return func(o *GenerateOptions) {
+		o.AutoTrim = true
+	}
+}
+
 // WithReasoning sets the full reasoning configuration.
 func WithReasoning(cfg ReasoningConfig) GenerateOption {
 	return func(o *GenerateOptions) {
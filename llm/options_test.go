@@ -181,6 +181,25 @@ func TestWithSpecificTool(t *testing.T) {
 	}
 }
 
+func TestWithLogProbs(t *testing.T) {
+	opts := ApplyOptions(WithLogProbs(5))
+	if !opts.LogProbs {
+		t.Error("LogProbs = false, want true")
+	}
+	if opts.TopLogProbs != 5 {
+		t.Errorf("TopLogProbs = %d, want 5", opts.TopLogProbs)
+	}
+}
+
+func TestWithAutoTrim(t *testing.T) {
+	if ApplyOptions().AutoTrim {
+		t.Error("AutoTrim = true by default")
+	}
+	if !ApplyOptions(WithAutoTrim()).AutoTrim {
+		t.Error("AutoTrim = false, want true")
+	}
+}
+
 func TestWithMetadata(t *testing.T) {
 	opts := ApplyOptions(WithMetadata(map[string]any{
 		"key1": "value1",
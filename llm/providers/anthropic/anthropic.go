@@ -5,6 +5,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"iter"
+	"strings"
+	"time"
 
 	anthropicSDK "github.com/anthropics/anthropic-sdk-go"
 	anthropicOption "github.com/anthropics/anthropic-sdk-go/option"
@@ -16,6 +18,18 @@ import (
 
 const defaultMaxTokens = 4096
 
+// minThinkingBudget is the smallest extended thinking budget the API
+// accepts.
+const minThinkingBudget = 1024
+
+// effortBudgets maps reasoning efforts to extended thinking budgets, for
+// requests that set an effort without a budget.
+var effortBudgets = map[llm.ReasoningEffort]int64{
+	llm.ReasoningEffortLow:    minThinkingBudget,
+	llm.ReasoningEffortMedium: 4096,
+	llm.ReasoningEffortHigh:   16384,
+}
+
 func init() {
 	llm.Register("anthropic", func(cfg config.ProviderConfig) (llm.ChatModel, error) {
 		return New(cfg)
@@ -27,6 +41,8 @@ type Model struct {
 	client anthropicSDK.Client
 	model  string
 	tools  []schema.ToolDefinition
+
+	batchPollInterval time.Duration
 }
 
 // Compile-time interface check.
@@ -50,14 +66,18 @@ func New(cfg config.ProviderConfig) (*Model, error) {
 	opts = append(opts, anthropicOption.WithMaxRetries(0))
 	client := anthropicSDK.NewClient(opts...)
 	return &Model{
-		client: client,
-		model:  cfg.Model,
+		client:            client,
+		model:             cfg.Model,
+		batchPollInterval: defaultBatchPollInterval,
 	}, nil
 }
 
-// Generate sends messages and returns a complete AI response.
+// Generate sends messages and returns a complete AI response. With
+// extended thinking enabled, through llm.WithReasoningBudget or
+// llm.WithReasoningEffort, the reasoning is returned as schema.ThinkingPart
+// parts ahead of the answer text.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	params, err := m.buildParams(msgs, opts)
+	params, err := m.buildParams(ctx, msgs, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +89,10 @@ func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm
 }
 
 // Stream sends messages and returns an iterator of response chunks.
+// Extended thinking arrives in the chunks' ReasoningDelta, separately from
+// the answer text in Delta.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	params, err := m.buildParams(msgs, opts)
+	params, err := m.buildParams(ctx, msgs, opts)
 	if err != nil {
 		return func(yield func(schema.StreamChunk, error) bool) {
 			yield(schema.StreamChunk{}, err)
@@ -79,12 +101,17 @@ func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.G
 	stream := m.client.Messages.NewStreaming(ctx, params)
 	return func(yield func(schema.StreamChunk, error) bool) {
 		defer stream.Close()
+		var thinking strings.Builder
 		for stream.Next() {
 			event := stream.Current()
 			chunk := convertStreamEvent(event, m.model)
 			if chunk == nil {
 				continue
 			}
+			thinking.WriteString(chunk.ReasoningDelta)
+			if chunk.Usage != nil {
+				chunk.Usage.ReasoningTokens = reasoningTokens(thinking.String(), chunk.Usage.OutputTokens)
+			}
 			if !yield(*chunk, nil) {
 				return
 			}
@@ -108,14 +135,33 @@ func (m *Model) ModelID() string {
 	return m.model
 }
 
-func (m *Model) buildParams(msgs []schema.Message, opts []llm.GenerateOption) (anthropicSDK.MessageNewParams, error) {
+func (m *Model) buildParams(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) (anthropicSDK.MessageNewParams, error) {
 	genOpts := llm.ApplyOptions(opts...)
 	maxTokens := int64(defaultMaxTokens)
 	if genOpts.MaxTokens > 0 {
 		maxTokens = int64(genOpts.MaxTokens)
 	}
 
-	converted, system, err := convertMessages(msgs)
+	budget := thinkingBudget(genOpts.Reasoning)
+	if budget > 0 {
+		if budget < minThinkingBudget {
+			return anthropicSDK.MessageNewParams{}, core.Errorf(core.ErrInvalidInput,
+				"anthropic: thinking budget must be at least %d tokens, got %d", minThinkingBudget, budget)
+		}
+		// Thinking counts towards max tokens, so make room for the answer
+		// unless the caller chose a limit.
+		if genOpts.MaxTokens == 0 {
+			maxTokens += budget
+		} else if maxTokens <= budget {
+			return anthropicSDK.MessageNewParams{}, core.Errorf(core.ErrInvalidInput,
+				"anthropic: max tokens (%d) must exceed the thinking budget (%d)", maxTokens, budget)
+		}
+	}
+
+	// Reserve room for the response, thinking included.
+	trimOpts := genOpts
+	trimOpts.MaxTokens = int(maxTokens)
+	converted, system, err := convertMessages(llm.TrimToContextWindow(ctx, m.model, msgs, trimOpts))
 	if err != nil {
 		return anthropicSDK.MessageNewParams{}, err
 	}
@@ -130,6 +176,10 @@ func (m *Model) buildParams(msgs []schema.Message, opts []llm.GenerateOption) (a
 		params.System = system
 	}
 
+	if budget > 0 {
+		params.Thinking = anthropicSDK.ThinkingConfigParamOfEnabled(budget)
+	}
+
 	if len(m.tools) > 0 {
 		params.Tools = convertTools(m.tools)
 	}
@@ -169,6 +219,26 @@ func (m *Model) buildParams(msgs []schema.Message, opts []llm.GenerateOption) (a
 	return params, nil
 }
 
+// thinkingBudget returns the extended thinking budget requested by cfg: its
+// BudgetTokens, or the budget of its Effort. Zero disables thinking.
+func thinkingBudget(cfg *llm.ReasoningConfig) int64 {
+	if cfg == nil {
+		return 0
+	}
+	if cfg.BudgetTokens > 0 {
+		return int64(cfg.BudgetTokens)
+	}
+	return effortBudgets[cfg.Effort]
+}
+
+// reasoningTokens estimates the tokens spent on thinking. The API counts
+// thinking within the output tokens without breaking it out, so the
+// estimate is capped at outputTokens.
+func reasoningTokens(thinking string, outputTokens int) int {
+	n := (&llm.SimpleTokenizer{}).Count(thinking)
+	return min(n, outputTokens)
+}
+
 func convertMessages(msgs []schema.Message) ([]anthropicSDK.MessageParam, []anthropicSDK.TextBlockParam, error) {
 	var system []anthropicSDK.TextBlockParam
 	out := make([]anthropicSDK.MessageParam, 0, len(msgs))
@@ -212,13 +282,39 @@ func convertContentParts(parts []schema.ContentPart) []anthropicSDK.ContentBlock
 				encoded := base64.StdEncoding.EncodeToString(cp.Data)
 				blocks = append(blocks, anthropicSDK.NewImageBlockBase64(mime, encoded))
 			}
+		case schema.DocumentPart:
+			blocks = append(blocks, convertDocument(cp))
 		}
 	}
 	return blocks
 }
 
+// convertAIContentParts converts an assistant message to content blocks.
+// Signed thinking parts are sent back first, as the API requires when a
+// tool use follows thinking; unsigned ones, from other providers, are
+// dropped.
+// convertDocument converts a document to a plain text document block with
+// citations enabled.
+func convertDocument(d schema.DocumentPart) anthropicSDK.ContentBlockParamUnion {
+	block := anthropicSDK.NewDocumentBlock(anthropicSDK.PlainTextSourceParam{Data: d.Text})
+	doc := block.OfDocument
+	doc.Citations = anthropicSDK.CitationsConfigParam{Enabled: anthropicSDK.Bool(true)}
+	if d.Title != "" {
+		doc.Title = anthropicSDK.String(d.Title)
+	}
+	if d.Context != "" {
+		doc.Context = anthropicSDK.String(d.Context)
+	}
+	return block
+}
+
 func convertAIContentParts(m *schema.AIMessage) []anthropicSDK.ContentBlockParamUnion {
 	var blocks []anthropicSDK.ContentBlockParamUnion
+	for _, p := range m.Parts {
+		if tp, ok := p.(schema.ThinkingPart); ok && tp.Signature != "" {
+			blocks = append(blocks, anthropicSDK.NewThinkingBlock(tp.Signature, tp.Text))
+		}
+	}
 	text := m.Text()
 	if text != "" {
 		blocks = append(blocks, anthropicSDK.NewTextBlock(text))
@@ -270,10 +366,17 @@ func convertResponse(resp *anthropicSDK.Message) *schema.AIMessage {
 			CachedTokens: int(resp.Usage.CacheReadInputTokens),
 		},
 	}
+	var thinking strings.Builder
 	for _, block := range resp.Content {
 		switch block.Type {
+		case "thinking":
+			ai.Parts = append(ai.Parts, schema.ThinkingPart{Text: block.Thinking, Signature: block.Signature})
+			thinking.WriteString(block.Thinking)
 		case "text":
-			ai.Parts = append(ai.Parts, schema.TextPart{Text: block.Text})
+			start := appendText(ai, block.Text)
+			for _, c := range block.Citations {
+				ai.Citations = append(ai.Citations, convertCitation(c, start, start+len(block.Text)))
+			}
 		case "tool_use":
 			args, _ := json.Marshal(block.Input)
 			ai.ToolCalls = append(ai.ToolCalls, schema.ToolCall{
@@ -283,15 +386,64 @@ func convertResponse(resp *anthropicSDK.Message) *schema.AIMessage {
 			})
 		}
 	}
+	ai.Usage.ReasoningTokens = reasoningTokens(thinking.String(), ai.Usage.OutputTokens)
 	return ai
 }
 
+// appendText appends text to the answer of ai and returns its byte offset
+// in ai.Text(). Consecutive text blocks, which the API splits at citation
+// boundaries, are joined into one part.
+func appendText(ai *schema.AIMessage, text string) int {
+	n := len(ai.Text())
+	if last := len(ai.Parts) - 1; last >= 0 {
+		if tp, ok := ai.Parts[last].(schema.TextPart); ok {
+			ai.Parts[last] = schema.TextPart{Text: tp.Text + text}
+			return n
+		}
+	}
+	ai.Parts = append(ai.Parts, schema.TextPart{Text: text})
+	if n > 0 {
+		// Text joins separate text parts with a newline.
+		n++
+	}
+	return n
+}
+
+// convertCitation converts a citation of the answer span [start, end).
+func convertCitation(c anthropicSDK.TextCitationUnion, start, end int) schema.Citation {
+	cit := schema.Citation{
+		Start:         start,
+		End:           end,
+		DocumentIndex: -1,
+		CitedText:     c.CitedText,
+	}
+	switch c.Type {
+	case "char_location":
+		cit.DocumentIndex = int(c.DocumentIndex)
+		cit.DocumentTitle = c.DocumentTitle
+		cit.DocumentStart = int(c.StartCharIndex)
+		cit.DocumentEnd = int(c.EndCharIndex)
+	case "page_location", "content_block_location":
+		cit.DocumentIndex = int(c.DocumentIndex)
+		cit.DocumentTitle = c.DocumentTitle
+	case "web_search_result_location":
+		cit.DocumentTitle = c.Title
+		cit.URI = c.URL
+	case "search_result_location":
+		cit.DocumentTitle = c.Title
+		cit.URI = c.Source
+	}
+	return cit
+}
+
 func convertStreamEvent(event anthropicSDK.MessageStreamEventUnion, modelID string) *schema.StreamChunk {
 	switch event.Type {
 	case "content_block_delta":
 		chunk := &schema.StreamChunk{ModelID: modelID}
 		if event.Delta.Type == "text_delta" {
 			chunk.Delta = event.Delta.Text
+		} else if event.Delta.Type == "thinking_delta" {
+			chunk.ReasoningDelta = event.Delta.Thinking
 		} else if event.Delta.Type == "input_json_delta" {
 			chunk.ToolCalls = []schema.ToolCall{{
 				Arguments: event.Delta.PartialJSON,
@@ -576,7 +576,7 @@ func TestBuildParams_ToolChoiceAuto(t *testing.T) {
 	m, _ := New(config.ProviderConfig{
 		Model: "claude-sonnet-4-5-20250929", APIKey: "test",
 	})
-	params, err := m.buildParams([]schema.Message{
+	params, err := m.buildParams(context.Background(), []schema.Message{
 		schema.NewHumanMessage("test"),
 	}, []llm.GenerateOption{
 		llm.WithToolChoice(llm.ToolChoiceAuto),
@@ -594,7 +594,7 @@ func TestBuildParams_ToolChoiceNone(t *testing.T) {
 	m, _ := New(config.ProviderConfig{
 		Model: "claude-sonnet-4-5-20250929", APIKey: "test",
 	})
-	params, err := m.buildParams([]schema.Message{
+	params, err := m.buildParams(context.Background(), []schema.Message{
 		schema.NewHumanMessage("test"),
 	}, []llm.GenerateOption{
 		llm.WithToolChoice(llm.ToolChoiceNone),
@@ -612,7 +612,7 @@ func TestBuildParams_ToolChoiceRequired(t *testing.T) {
 	m, _ := New(config.ProviderConfig{
 		Model: "claude-sonnet-4-5-20250929", APIKey: "test",
 	})
-	params, err := m.buildParams([]schema.Message{
+	params, err := m.buildParams(context.Background(), []schema.Message{
 		schema.NewHumanMessage("test"),
 	}, []llm.GenerateOption{
 		llm.WithToolChoice(llm.ToolChoiceRequired),
@@ -630,7 +630,7 @@ func TestBuildParams_SpecificTool(t *testing.T) {
 	m, _ := New(config.ProviderConfig{
 		Model: "claude-sonnet-4-5-20250929", APIKey: "test",
 	})
-	params, err := m.buildParams([]schema.Message{
+	params, err := m.buildParams(context.Background(), []schema.Message{
 		schema.NewHumanMessage("test"),
 	}, []llm.GenerateOption{
 		llm.WithSpecificTool("get_weather"),
@@ -651,7 +651,7 @@ func TestBuildParams_StopSequences(t *testing.T) {
 	m, _ := New(config.ProviderConfig{
 		Model: "claude-sonnet-4-5-20250929", APIKey: "test",
 	})
-	params, err := m.buildParams([]schema.Message{
+	params, err := m.buildParams(context.Background(), []schema.Message{
 		schema.NewHumanMessage("test"),
 	}, []llm.GenerateOption{
 		llm.WithStopSequences("STOP", "END"),
@@ -822,3 +822,271 @@ func TestGenerateWithToolMessage(t *testing.T) {
 		t.Errorf("text = %q", resp.Text())
 	}
 }
+
+func mockAnthropicThinkingResponse() string {
+	resp := map[string]any{
+		"id":            "msg_thinking",
+		"type":          "message",
+		"role":          "assistant",
+		"model":         "claude-sonnet-4-5-20250929",
+		"stop_reason":   "end_turn",
+		"stop_sequence": nil,
+		"content": []map[string]any{
+			{"type": "thinking", "thinking": "2 plus 2 makes 4.", "signature": "sig_abc"},
+			{"type": "text", "text": "4"},
+		},
+		"usage": map[string]any{
+			"input_tokens":  10,
+			"output_tokens": 30,
+		},
+	}
+	b, _ := json.Marshal(resp)
+	return string(b)
+}
+
+func TestGenerate_Thinking(t *testing.T) {
+	var capturedBody map[string]any
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &capturedBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, mockAnthropicThinkingResponse())
+	})
+	defer ts.Close()
+
+	resp, err := m.Generate(context.Background(), []schema.Message{
+		schema.NewHumanMessage("What is 2+2?"),
+	}, llm.WithReasoningBudget(2048))
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	thinking, _ := capturedBody["thinking"].(map[string]any)
+	if thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2048) {
+		t.Errorf("thinking = %v", capturedBody["thinking"])
+	}
+	if maxT, _ := capturedBody["max_tokens"].(float64); int(maxT) != defaultMaxTokens+2048 {
+		t.Errorf("max_tokens = %v, want %d", capturedBody["max_tokens"], defaultMaxTokens+2048)
+	}
+
+	if resp.Text() != "4" {
+		t.Errorf("text = %q, want %q", resp.Text(), "4")
+	}
+	if len(resp.Parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(resp.Parts))
+	}
+	tp, ok := resp.Parts[0].(schema.ThinkingPart)
+	if !ok || tp.Text != "2 plus 2 makes 4." || tp.Signature != "sig_abc" {
+		t.Errorf("first part = %#v, want the thinking part", resp.Parts[0])
+	}
+	// 17 characters of thinking, at 4 characters per token.
+	if resp.Usage.ReasoningTokens != 5 {
+		t.Errorf("ReasoningTokens = %d, want 5", resp.Usage.ReasoningTokens)
+	}
+}
+
+func TestBuildParams_Thinking(t *testing.T) {
+	m, _ := New(config.ProviderConfig{Model: "claude-sonnet-4-5-20250929", APIKey: "test"})
+	msgs := []schema.Message{schema.NewHumanMessage("Hi")}
+
+	tests := []struct {
+		name          string
+		opts          []llm.GenerateOption
+		wantBudget    int64
+		wantMaxTokens int64
+		wantErr       bool
+	}{
+		{name: "disabled", wantMaxTokens: defaultMaxTokens},
+		{name: "effort", opts: []llm.GenerateOption{llm.WithReasoningEffort(llm.ReasoningEffortHigh)}, wantBudget: 16384, wantMaxTokens: defaultMaxTokens + 16384},
+		{name: "budget wins", opts: []llm.GenerateOption{llm.WithReasoningEffort(llm.ReasoningEffortHigh), llm.WithReasoningBudget(2000)}, wantBudget: 2000, wantMaxTokens: defaultMaxTokens + 2000},
+		{name: "explicit max tokens", opts: []llm.GenerateOption{llm.WithReasoningBudget(2000), llm.WithMaxTokens(8000)}, wantBudget: 2000, wantMaxTokens: 8000},
+		{name: "max tokens too small", opts: []llm.GenerateOption{llm.WithReasoningBudget(2000), llm.WithMaxTokens(2000)}, wantErr: true},
+		{name: "budget too small", opts: []llm.GenerateOption{llm.WithReasoningBudget(100)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := m.buildParams(context.Background(), msgs, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildParams() error: %v", err)
+			}
+			var budget int64
+			if params.Thinking.OfEnabled != nil {
+				budget = params.Thinking.OfEnabled.BudgetTokens
+			}
+			if budget != tt.wantBudget {
+				t.Errorf("budget = %d, want %d", budget, tt.wantBudget)
+			}
+			if params.MaxTokens != tt.wantMaxTokens {
+				t.Errorf("MaxTokens = %d, want %d", params.MaxTokens, tt.wantMaxTokens)
+			}
+		})
+	}
+}
+
+func TestConvertAIContentParts_Thinking(t *testing.T) {
+	aiMsg := &schema.AIMessage{
+		Parts: []schema.ContentPart{
+			schema.ThinkingPart{Text: "Need the weather.", Signature: "sig_1"},
+			schema.ThinkingPart{Text: "Unsigned reasoning from another provider."},
+			schema.TextPart{Text: "Checking."},
+		},
+		ToolCalls: []schema.ToolCall{{ID: "toolu_1", Name: "get_weather", Arguments: `{}`}},
+	}
+	blocks := convertAIContentParts(aiMsg)
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(blocks))
+	}
+	if b := blocks[0].OfThinking; b == nil || b.Thinking != "Need the weather." || b.Signature != "sig_1" {
+		t.Errorf("first block = %v, want the signed thinking block", blocks[0])
+	}
+	if blocks[1].OfText == nil || blocks[2].OfToolUse == nil {
+		t.Errorf("blocks = %v, want text then tool_use", blocks)
+	}
+}
+
+func TestStream_Thinking(t *testing.T) {
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []map[string]any{
+			{"type": "message_start", "message": map[string]any{
+				"id": "msg_stream", "type": "message", "role": "assistant",
+				"model": "claude-sonnet-4-5-20250929", "content": []any{},
+				"usage": map[string]any{"input_tokens": 10, "output_tokens": 0},
+			}},
+			{"type": "content_block_start", "index": 0, "content_block": map[string]any{"type": "thinking", "thinking": ""}},
+			{"type": "content_block_delta", "index": 0, "delta": map[string]any{"type": "thinking_delta", "thinking": "Adding "}},
+			{"type": "content_block_delta", "index": 0, "delta": map[string]any{"type": "thinking_delta", "thinking": "two and two."}},
+			{"type": "content_block_delta", "index": 0, "delta": map[string]any{"type": "signature_delta", "signature": "sig"}},
+			{"type": "content_block_stop", "index": 0},
+			{"type": "content_block_start", "index": 1, "content_block": map[string]any{"type": "text", "text": ""}},
+			{"type": "content_block_delta", "index": 1, "delta": map[string]any{"type": "text_delta", "text": "4"}},
+			{"type": "content_block_stop", "index": 1},
+			{"type": "message_delta", "delta": map[string]any{"stop_reason": "end_turn"}, "usage": map[string]any{"output_tokens": 12}},
+			{"type": "message_stop"},
+		}
+		for _, e := range events {
+			b, _ := json.Marshal(e)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e["type"], b)
+		}
+	})
+	defer ts.Close()
+
+	var text, reasoning strings.Builder
+	var usage *schema.Usage
+	for chunk, err := range m.Stream(context.Background(), []schema.Message{
+		schema.NewHumanMessage("What is 2+2?"),
+	}, llm.WithReasoningBudget(1024)) {
+		if err != nil {
+			t.Fatalf("Stream() error: %v", err)
+		}
+		text.WriteString(chunk.Delta)
+		reasoning.WriteString(chunk.ReasoningDelta)
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+	if text.String() != "4" {
+		t.Errorf("text = %q, want %q", text.String(), "4")
+	}
+	if reasoning.String() != "Adding two and two." {
+		t.Errorf("reasoning = %q, want %q", reasoning.String(), "Adding two and two.")
+	}
+	// 19 characters of thinking, at 4 characters per token.
+	if usage == nil || usage.ReasoningTokens != 5 {
+		t.Errorf("usage = %+v, want 5 reasoning tokens", usage)
+	}
+}
+
+func TestGenerate_Citations(t *testing.T) {
+	var capturedBody map[string]any
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &capturedBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"id": "msg_cite", "type": "message", "role": "assistant",
+			"model": "claude-sonnet-4-5-20250929", "stop_reason": "end_turn",
+			"content": [
+				{"type": "text", "text": "According to the handbook, "},
+				{"type": "text", "text": "employees get 25 days of leave", "citations": [{
+					"type": "char_location", "cited_text": "Employees get 25 days of leave.",
+					"document_index": 1, "document_title": "Handbook",
+					"start_char_index": 0, "end_char_index": 31
+				}]},
+				{"type": "text", "text": "."}
+			],
+			"usage": {"input_tokens": 10, "output_tokens": 20}
+		}`)
+	})
+	defer ts.Close()
+
+	resp, err := m.Generate(context.Background(), []schema.Message{
+		&schema.HumanMessage{Parts: []schema.ContentPart{
+			schema.DocumentPart{Title: "Memo", Text: "The office is closed on Fridays."},
+			schema.DocumentPart{Title: "Handbook", Context: "HR policy", Text: "Employees get 25 days of leave."},
+			schema.TextPart{Text: "How much leave do employees get?"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	msgs, _ := capturedBody["messages"].([]any)
+	first, _ := msgs[0].(map[string]any)
+	content, _ := first["content"].([]any)
+	doc, _ := content[1].(map[string]any)
+	source, _ := doc["source"].(map[string]any)
+	citations, _ := doc["citations"].(map[string]any)
+	if doc["type"] != "document" || doc["title"] != "Handbook" || doc["context"] != "HR policy" ||
+		source["data"] != "Employees get 25 days of leave." || citations["enabled"] != true {
+		t.Errorf("document block = %v", doc)
+	}
+
+	text := resp.Text()
+	if text != "According to the handbook, employees get 25 days of leave." {
+		t.Errorf("text = %q", text)
+	}
+	if len(resp.Citations) != 1 {
+		t.Fatalf("got %d citations, want 1", len(resp.Citations))
+	}
+	c := resp.Citations[0]
+	if text[c.Start:c.End] != "employees get 25 days of leave" {
+		t.Errorf("cited span = %q", text[c.Start:c.End])
+	}
+	if c.DocumentIndex != 1 || c.DocumentTitle != "Handbook" || c.CitedText != "Employees get 25 days of leave." ||
+		c.DocumentStart != 0 || c.DocumentEnd != 31 {
+		t.Errorf("citation = %+v", c)
+	}
+}
+
+func TestConvertResponse_CitationAfterThinking(t *testing.T) {
+	var resp anthropicSDK.Message
+	if err := json.Unmarshal([]byte(`{
+		"model": "claude-sonnet-4-5-20250929",
+		"content": [
+			{"type": "text", "text": "Intro."},
+			{"type": "thinking", "thinking": "Check the source.", "signature": "sig"},
+			{"type": "text", "text": "Cited.", "citations": [{
+				"type": "web_search_result_location", "cited_text": "Source text",
+				"url": "https://example.com", "title": "Example"
+			}]}
+		]
+	}`), &resp); err != nil {
+		t.Fatal(err)
+	}
+	ai := convertResponse(&resp)
+	text := ai.Text()
+	if text != "Intro.\nCited." {
+		t.Fatalf("text = %q", text)
+	}
+	c := ai.Citations[0]
+	if text[c.Start:c.End] != "Cited." || c.DocumentIndex != -1 || c.URI != "https://example.com" || c.DocumentTitle != "Example" {
+		t.Errorf("citation = %+v", c)
+	}
+}
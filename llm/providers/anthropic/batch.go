@@ -0,0 +1,142 @@
+package anthropic
+
+import (
+	"context"
+	"time"
+
+	anthropicSDK "github.com/anthropics/anthropic-sdk-go"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+)
+
+// defaultBatchPollInterval is how often BatchGenerate checks whether a
+// batch has finished.
+const defaultBatchPollInterval = 30 * time.Second
+
+// Compile-time interface check.
+var _ llm.BatchGenerator = (*Model)(nil)
+
+// BatchGenerate submits requests to the Message Batches API, which bills at
+// half the price of Generate, and waits for the batch to finish. Results are
+// returned in request order and correlated by custom ID. Requests that
+// errored, expired or were cancelled carry an error in their result. If ctx
+// is done before the batch ends, the error names the batch, which keeps
+// running.
+func (m *Model) BatchGenerate(ctx context.Context, requests []llm.BatchRequest) ([]llm.BatchResult, error) {
+	if err := llm.ValidateBatch(requests); err != nil {
+		return nil, err
+	}
+
+	batchReqs := make([]anthropicSDK.MessageBatchNewParamsRequest, len(requests))
+	for i, req := range requests {
+		params, err := m.buildParams(ctx, req.Messages, req.Options)
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "anthropic: batch request %q: %w", req.CustomID, err)
+		}
+		batchReqs[i] = anthropicSDK.MessageBatchNewParamsRequest{
+			CustomID: req.CustomID,
+			Params:   batchParams(params),
+		}
+	}
+
+	batch, err := m.client.Messages.Batches.New(ctx, anthropicSDK.MessageBatchNewParams{Requests: batchReqs})
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "anthropic: batch submit failed: %w", err)
+	}
+	if err := m.waitBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	return m.batchResults(ctx, batch.ID, requests)
+}
+
+// waitBatch polls batch until its processing has ended.
+func (m *Model) waitBatch(ctx context.Context, batch *anthropicSDK.MessageBatch) error {
+	id := batch.ID
+	for batch.ProcessingStatus != anthropicSDK.MessageBatchProcessingStatusEnded {
+		timer := time.NewTimer(m.batchPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return core.Errorf(core.ErrTimeout, "anthropic: waiting for batch %s: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+
+		var err error
+		batch, err = m.client.Messages.Batches.Get(ctx, id)
+		if err != nil {
+			return core.Errorf(core.ErrProviderDown, "anthropic: batch %s status failed: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// batchResults fetches the results of the ended batch id and orders them
+// like requests.
+func (m *Model) batchResults(ctx context.Context, id string, requests []llm.BatchRequest) ([]llm.BatchResult, error) {
+	results := make([]llm.BatchResult, len(requests))
+	index := make(map[string]int, len(requests))
+	for i, req := range requests {
+		index[req.CustomID] = i
+		results[i] = llm.BatchResult{
+			CustomID: req.CustomID,
+			Err:      core.Errorf(core.ErrProviderDown, "anthropic: batch %s has no result for %q", id, req.CustomID),
+		}
+	}
+
+	stream := m.client.Messages.Batches.ResultsStreaming(ctx, id)
+	defer stream.Close()
+	for stream.Next() {
+		resp := stream.Current()
+		i, ok := index[resp.CustomID]
+		if !ok {
+			continue
+		}
+		switch result := resp.Result; result.Type {
+		case "succeeded":
+			results[i].Response = convertResponse(&result.Message)
+			results[i].Err = nil
+		case "errored":
+			apiErr := result.Error.Error
+			results[i].Err = core.Errorf(batchErrorCode(apiErr.Type), "anthropic: batch request failed: %s: %s", apiErr.Type, apiErr.Message)
+		default:
+			results[i].Err = core.Errorf(core.ErrProviderDown, "anthropic: batch request %s", result.Type)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "anthropic: batch %s results failed: %w", id, err)
+	}
+	return results, nil
+}
+
+// batchErrorCode maps an API error type to an error code.
+func batchErrorCode(errType string) core.ErrorCode {
+	switch errType {
+	case "invalid_request_error", "not_found_error":
+		return core.ErrInvalidInput
+	case "authentication_error", "permission_error":
+		return core.ErrAuth
+	case "rate_limit_error":
+		return core.ErrRateLimit
+	case "timeout_error":
+		return core.ErrTimeout
+	default:
+		return core.ErrProviderDown
+	}
+}
+
+// batchParams converts the parameters built for a Messages request to those
+// of a batch request.
+func batchParams(p anthropicSDK.MessageNewParams) anthropicSDK.MessageBatchNewParamsRequestParams {
+	return anthropicSDK.MessageBatchNewParamsRequestParams{
+		MaxTokens:     p.MaxTokens,
+		Messages:      p.Messages,
+		Model:         p.Model,
+		System:        p.System,
+		Temperature:   p.Temperature,
+		TopP:          p.TopP,
+		StopSequences: p.StopSequences,
+		Thinking:      p.Thinking,
+		Tools:         p.Tools,
+		ToolChoice:    p.ToolChoice,
+	}
+}
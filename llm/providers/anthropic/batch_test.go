@@ -0,0 +1,144 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+func batchJSON(status string) string {
+	return fmt.Sprintf(`{"id":"msgbatch_1","type":"message_batch","processing_status":%q,"request_counts":{}}`, status)
+}
+
+func batchResultLine(customID string, result map[string]any) string {
+	b, _ := json.Marshal(map[string]any{"custom_id": customID, "result": result})
+	return string(b) + "\n"
+}
+
+func TestBatchGenerate(t *testing.T) {
+	var submitted map[string]any
+	var polls atomic.Int32
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/messages/batches":
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &submitted)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, batchJSON("in_progress"))
+		case r.URL.Path == "/v1/messages/batches/msgbatch_1":
+			status := "in_progress"
+			if polls.Add(1) > 1 {
+				status = "ended"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, batchJSON(status))
+		case r.URL.Path == "/v1/messages/batches/msgbatch_1/results":
+			w.Header().Set("Content-Type", "application/x-jsonl")
+			// Results arrive in any order.
+			fmt.Fprint(w, batchResultLine("b", map[string]any{
+				"type":  "errored",
+				"error": map[string]any{"type": "error", "error": map[string]any{"type": "invalid_request_error", "message": "bad prompt"}},
+			}))
+			var msg map[string]any
+			json.Unmarshal([]byte(mockAnthropicResponse("answer a")), &msg)
+			fmt.Fprint(w, batchResultLine("a", map[string]any{"type": "succeeded", "message": msg}))
+			fmt.Fprint(w, batchResultLine("c", map[string]any{"type": "expired"}))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	defer ts.Close()
+	m.batchPollInterval = time.Millisecond
+
+	results, err := m.BatchGenerate(context.Background(), []llm.BatchRequest{
+		{CustomID: "a", Messages: []schema.Message{schema.NewHumanMessage("A?")}, Options: []llm.GenerateOption{llm.WithMaxTokens(50)}},
+		{CustomID: "b", Messages: []schema.Message{schema.NewHumanMessage("B?")}},
+		{CustomID: "c", Messages: []schema.Message{schema.NewHumanMessage("C?")}},
+		{CustomID: "d", Messages: []schema.Message{schema.NewHumanMessage("D?")}},
+	})
+	if err != nil {
+		t.Fatalf("BatchGenerate() error: %v", err)
+	}
+
+	reqs, _ := submitted["requests"].([]any)
+	if len(reqs) != 4 {
+		t.Fatalf("submitted %d requests, want 4", len(reqs))
+	}
+	first, _ := reqs[0].(map[string]any)
+	params, _ := first["params"].(map[string]any)
+	if first["custom_id"] != "a" || params["max_tokens"] != float64(50) || params["model"] != "claude-sonnet-4-5-20250929" {
+		t.Errorf("first request = %v", first)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if results[i].CustomID != id {
+			t.Errorf("results[%d].CustomID = %q, want %q", i, results[i].CustomID, id)
+		}
+	}
+	if results[0].Err != nil || results[0].Response.Text() != "answer a" {
+		t.Errorf("results[0] = %+v, want the answer", results[0])
+	}
+	var coreErr *core.Error
+	if !errors.As(results[1].Err, &coreErr) || coreErr.Code != core.ErrInvalidInput || !strings.Contains(coreErr.Error(), "bad prompt") {
+		t.Errorf("results[1].Err = %v, want an invalid input error", results[1].Err)
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "expired") {
+		t.Errorf("results[2].Err = %v, want expired", results[2].Err)
+	}
+	if results[3].Err == nil || results[3].Response != nil {
+		t.Errorf("results[3] = %+v, want a missing result error", results[3])
+	}
+}
+
+func TestBatchGenerate_ContextDone(t *testing.T) {
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, batchJSON("in_progress"))
+	})
+	defer ts.Close()
+	m.batchPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := m.BatchGenerate(ctx, []llm.BatchRequest{
+		{CustomID: "a", Messages: []schema.Message{schema.NewHumanMessage("A?")}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "msgbatch_1") {
+		t.Errorf("err = %v, want an error naming the batch", err)
+	}
+}
+
+func TestBatchGenerate_InvalidRequests(t *testing.T) {
+	m, _ := New(config.ProviderConfig{Model: "claude-sonnet-4-5-20250929", APIKey: "test"})
+	tests := []struct {
+		name     string
+		requests []llm.BatchRequest
+	}{
+		{name: "empty"},
+		{name: "duplicate ID", requests: []llm.BatchRequest{{CustomID: "a"}, {CustomID: "a"}}},
+		{name: "bad options", requests: []llm.BatchRequest{{CustomID: "a", Options: []llm.GenerateOption{llm.WithReasoningBudget(10)}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.BatchGenerate(context.Background(), tt.requests); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
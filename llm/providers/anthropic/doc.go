@@ -35,6 +35,56 @@
 //   - BaseURL: optional, defaults to Anthropic's API endpoint
 //   - Timeout: optional request timeout
 //
+// # Extended Thinking
+//
+// Extended thinking is enabled per request with llm.WithReasoningBudget, or
+// with llm.WithReasoningEffort, which maps low, medium and high to budgets
+// of 1024, 4096 and 16384 tokens. The budget must be at least 1024 tokens.
+// Unless llm.WithMaxTokens is set, max tokens grows by the budget so that
+// thinking does not crowd out the answer.
+//
+// Generate returns the reasoning as [schema.ThinkingPart] parts before the
+// answer; AIMessage.Text returns only the answer. Signed thinking parts are
+// sent back to the API when the message is part of a later request, as
+// tool use requires. Stream emits reasoning in StreamChunk.ReasoningDelta
+// and the answer in Delta. Usage.ReasoningTokens is an estimate, since the
+// API includes thinking in the output tokens without a separate count:
+//
+//	resp, err := model.Generate(ctx, msgs, llm.WithReasoningBudget(8192))
+//	for _, part := range resp.Parts {
+//	    if tp, ok := part.(schema.ThinkingPart); ok {
+//	        fmt.Println("thinking:", tp.Text)
+//	    }
+//	}
+//	fmt.Println(resp.Text())
+//
+// # Citations
+//
+// [schema.DocumentPart] parts become plain text document blocks with
+// citations enabled. The answer's text blocks are joined into a single text
+// part, and each citation becomes a [schema.Citation] locating the cited
+// span of the answer, the document by its index among the request's
+// documents, and the cited passage:
+//
+//	resp, err := model.Generate(ctx, []schema.Message{
+//	    &schema.HumanMessage{Parts: []schema.ContentPart{
+//	        schema.DocumentPart{Title: "Handbook", Text: handbook},
+//	        schema.TextPart{Text: "How much leave do employees get?"},
+//	    }},
+//	})
+//	for _, c := range resp.Citations {
+//	    fmt.Printf("%q cites %s: %q\n", resp.Text()[c.Start:c.End], c.DocumentTitle, c.CitedText)
+//	}
+//
+// Streams do not report citations.
+//
+// # Batches
+//
+// [Model] implements [llm.BatchGenerator] with the Message Batches API,
+// which costs half as much as Generate for jobs that can wait. The batch is
+// polled every 30 seconds until it ends; individual requests that errored,
+// expired or were cancelled report an error in their result.
+//
 // # Key Types
 //
 //   - [Model]: the ChatModel implementation with Generate, Stream, BindTools, and ModelID methods
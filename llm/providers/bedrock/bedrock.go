@@ -33,7 +33,7 @@ type ConverseAPI interface {
 
 // Model implements llm.ChatModel using the AWS Bedrock Converse API.
 type Model struct {
-	client  ConverseAPI
+	regions *regionPool
 	modelID string
 	tools   []schema.ToolDefinition
 }
@@ -41,19 +41,21 @@ type Model struct {
 // Compile-time interface check.
 var _ llm.ChatModel = (*Model)(nil)
 
-// New creates a new Bedrock ChatModel.
+// New creates a new Bedrock ChatModel with a client for each configured
+// region.
 func New(cfg cfgpkg.ProviderConfig) (*Model, error) {
 	if cfg.Model == "" {
 		return nil, core.Errorf(core.ErrInvalidInput, "bedrock: model is required")
 	}
 
-	region, _ := cfgpkg.GetOption[string](cfg, "region")
-	if region == "" {
-		region = "us-east-1"
+	regions, err := parseRegions(cfg)
+	if err != nil {
+		return nil, err
 	}
+	profile, _ := cfgpkg.GetOption[string](cfg, "inference_profile")
 
 	var awsOpts []func(*awsconfig.LoadOptions) error
-	awsOpts = append(awsOpts, awsconfig.WithRegion(region))
+	awsOpts = append(awsOpts, awsconfig.WithRegion(regions[0]))
 
 	if cfg.APIKey != "" {
 		secretKey, _ := cfgpkg.GetOption[string](cfg, "secret_key")
@@ -67,46 +69,67 @@ func New(cfg cfgpkg.ProviderConfig) (*Model, error) {
 		return nil, core.Errorf(core.ErrProviderDown, "bedrock: failed to load AWS config: %w", err)
 	}
 
-	var brOpts []func(*bedrockruntime.Options)
-	if cfg.BaseURL != "" {
-		brOpts = append(brOpts, func(o *bedrockruntime.Options) {
-			o.BaseEndpoint = aws.String(cfg.BaseURL)
-		})
+	clients := make([]RegionClient, len(regions))
+	for i, region := range regions {
+		modelID, err := inferenceProfileID(cfg.Model, region, profile)
+		if err != nil {
+			return nil, err
+		}
+		brOpts := []func(*bedrockruntime.Options){func(o *bedrockruntime.Options) {
+			o.Region = region
+		}}
+		if cfg.BaseURL != "" {
+			brOpts = append(brOpts, func(o *bedrockruntime.Options) {
+				o.BaseEndpoint = aws.String(cfg.BaseURL)
+			})
+		}
+		clients[i] = RegionClient{
+			Region:  region,
+			Client:  bedrockruntime.NewFromConfig(awsCfg, brOpts...),
+			ModelID: modelID,
+		}
 	}
 
-	client := bedrockruntime.NewFromConfig(awsCfg, brOpts...)
-
-	return &Model{
-		client:  client,
-		modelID: cfg.Model,
-	}, nil
+	return newRegionModel(clients), nil
 }
 
 // NewWithClient creates a Model with a custom ConverseAPI implementation.
 // This is useful for testing.
 func NewWithClient(client ConverseAPI, modelID string) *Model {
-	return &Model{
-		client:  client,
-		modelID: modelID,
-	}
+	return newRegionModel([]RegionClient{{Client: client, ModelID: modelID}})
 }
 
-// Generate sends messages and returns a complete AI response.
+// Generate sends messages and returns a complete AI response. If a region
+// throttles the request, it is retried in the next configured region. The
+// region that served the response is reported in its "region" metadata.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	input, err := m.buildInput(msgs, opts)
+	input, err := m.buildInput(ctx, msgs, opts)
 	if err != nil {
 		return nil, err
 	}
-	output, err := m.client.Converse(ctx, input)
+	output, rc, err := inRegions(ctx, m.regions, func(c RegionClient) (*bedrockruntime.ConverseOutput, error) {
+		in := *input
+		in.ModelId = aws.String(c.ModelID)
+		return c.Client.Converse(ctx, &in)
+	})
 	if err != nil {
+		if isThrottled(err) {
+			return nil, core.Errorf(core.ErrRateLimit, "bedrock: converse throttled: %w", err)
+		}
 		return nil, core.Errorf(core.ErrProviderDown, "bedrock: converse failed: %w", err)
 	}
-	return convertOutput(output, m.modelID), nil
+	resp := convertOutput(output, rc.ModelID)
+	if rc.Region != "" {
+		resp.Metadata["region"] = rc.Region
+	}
+	return resp, nil
 }
 
-// Stream sends messages and returns an iterator of response chunks.
+// Stream sends messages and returns an iterator of response chunks. A
+// stream that a region throttles before it opens is retried in the next
+// configured region.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	input, err := m.buildStreamInput(msgs, opts)
+	input, err := m.buildStreamInput(ctx, msgs, opts)
 	if err != nil {
 		return func(yield func(schema.StreamChunk, error) bool) {
 			yield(schema.StreamChunk{}, err)
@@ -114,17 +137,26 @@ func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.G
 	}
 
 	return func(yield func(schema.StreamChunk, error) bool) {
-		consumeBedrockStream(ctx, m.client, input, m.modelID, yield)
+		output, rc, err := inRegions(ctx, m.regions, func(c RegionClient) (*bedrockruntime.ConverseStreamOutput, error) {
+			in := *input
+			in.ModelId = aws.String(c.ModelID)
+			return c.Client.ConverseStream(ctx, &in)
+		})
+		if err != nil {
+			if isThrottled(err) {
+				yield(schema.StreamChunk{}, core.Errorf(core.ErrRateLimit, "bedrock: stream throttled: %w", err))
+				return
+			}
+			yield(schema.StreamChunk{}, core.Errorf(core.ErrProviderDown, "bedrock: stream failed: %w", err))
+			return
+		}
+		consumeBedrockStream(output, rc.ModelID, yield)
 	}
 }
 
-// consumeBedrockStream opens a Bedrock converse stream and yields chunks to the caller.
-func consumeBedrockStream(ctx context.Context, client ConverseAPI, input *bedrockruntime.ConverseStreamInput, modelID string, yield func(schema.StreamChunk, error) bool) {
-	output, err := client.ConverseStream(ctx, input)
-	if err != nil {
-		yield(schema.StreamChunk{}, core.Errorf(core.ErrProviderDown, "bedrock: stream failed: %w", err))
-		return
-	}
+// consumeBedrockStream yields the chunks of an open Bedrock converse stream
+// to the caller.
+func consumeBedrockStream(output *bedrockruntime.ConverseStreamOutput, modelID string, yield func(schema.StreamChunk, error) bool) {
 	stream := output.GetStream()
 	if stream == nil {
 		return
@@ -158,12 +190,12 @@ func (m *Model) ModelID() string {
 	return m.modelID
 }
 
-func (m *Model) buildInput(msgs []schema.Message, opts []llm.GenerateOption) (*bedrockruntime.ConverseInput, error) {
-	converted, system, err := convertMessages(msgs)
+func (m *Model) buildInput(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) (*bedrockruntime.ConverseInput, error) {
+	genOpts := llm.ApplyOptions(opts...)
+	converted, system, err := convertMessages(llm.TrimToContextWindow(ctx, m.modelID, msgs, genOpts))
 	if err != nil {
 		return nil, err
 	}
-	genOpts := llm.ApplyOptions(opts...)
 	input := &bedrockruntime.ConverseInput{
 		ModelId:  aws.String(m.modelID),
 		Messages: converted,
@@ -178,12 +210,12 @@ func (m *Model) buildInput(msgs []schema.Message, opts []llm.GenerateOption) (*b
 	return input, nil
 }
 
-func (m *Model) buildStreamInput(msgs []schema.Message, opts []llm.GenerateOption) (*bedrockruntime.ConverseStreamInput, error) {
-	converted, system, err := convertMessages(msgs)
+func (m *Model) buildStreamInput(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) (*bedrockruntime.ConverseStreamInput, error) {
+	genOpts := llm.ApplyOptions(opts...)
+	converted, system, err := convertMessages(llm.TrimToContextWindow(ctx, m.modelID, msgs, genOpts))
 	if err != nil {
 		return nil, err
 	}
-	genOpts := llm.ApplyOptions(opts...)
 	input := &bedrockruntime.ConverseStreamInput{
 		ModelId:  aws.String(m.modelID),
 		Messages: converted,
@@ -231,12 +263,24 @@ func buildInferenceConfig(opts llm.GenerateOptions) *brtypes.InferenceConfigurat
 	return cfg
 }
 
+// cacheBreakpointKey is the message metadata key that marks a cache
+// breakpoint, as set by prompt.WithCacheBreakpoint.
+const cacheBreakpointKey = "cache_breakpoint"
+
+// convertMessages converts msgs to Bedrock messages and system blocks. A
+// message whose metadata sets cacheBreakpointKey ends the cached prefix: a
+// cache point is inserted after it. Empty system messages marking a
+// breakpoint, as built by prompt.Builder, only insert the cache point.
 func convertMessages(msgs []schema.Message) ([]brtypes.Message, []brtypes.SystemContentBlock, error) {
 	var system []brtypes.SystemContentBlock
 	var out []brtypes.Message
 	for _, msg := range msgs {
 		switch m := msg.(type) {
 		case *schema.SystemMessage:
+			if isCacheBreakpoint(m) && m.Text() == "" {
+				out, system = addCachePoint(out, system, len(out) == 0)
+				continue
+			}
 			system = append(system, &brtypes.SystemContentBlockMemberText{
 				Value: m.Text(),
 			})
@@ -271,10 +315,34 @@ func convertMessages(msgs []schema.Message) ([]brtypes.Message, []brtypes.System
 		default:
 			return nil, nil, core.Errorf(core.ErrInvalidInput, "bedrock: unsupported message type %T", msg)
 		}
+		if isCacheBreakpoint(msg) {
+			out, system = addCachePoint(out, system, msg.GetRole() == schema.RoleSystem)
+		}
 	}
 	return out, system, nil
 }
 
+// isCacheBreakpoint reports whether msg marks a cache breakpoint.
+func isCacheBreakpoint(msg schema.Message) bool {
+	v, _ := msg.GetMetadata()[cacheBreakpointKey].(bool)
+	return v
+}
+
+// addCachePoint appends a cache point to the system blocks if toSystem is
+// set, or else to the content of the last message. A cache point with
+// nothing before it is dropped, since Bedrock rejects it.
+func addCachePoint(out []brtypes.Message, system []brtypes.SystemContentBlock, toSystem bool) ([]brtypes.Message, []brtypes.SystemContentBlock) {
+	point := brtypes.CachePointBlock{Type: brtypes.CachePointTypeDefault}
+	switch {
+	case toSystem && len(system) > 0:
+		system = append(system, &brtypes.SystemContentBlockMemberCachePoint{Value: point})
+	case !toSystem && len(out) > 0:
+		last := &out[len(out)-1]
+		last.Content = append(last.Content, &brtypes.ContentBlockMemberCachePoint{Value: point})
+	}
+	return out, system
+}
+
 func convertHumanParts(parts []schema.ContentPart) []brtypes.ContentBlock {
 	var blocks []brtypes.ContentBlock
 	for _, p := range parts {
@@ -437,6 +505,7 @@ func convertStreamEvent(event brtypes.ConverseStreamOutput, modelID string) *sch
 					InputTokens:  int(aws.ToInt32(e.Value.Usage.InputTokens)),
 					OutputTokens: int(aws.ToInt32(e.Value.Usage.OutputTokens)),
 					TotalTokens:  int(aws.ToInt32(e.Value.Usage.TotalTokens)),
+					CachedTokens: int(aws.ToInt32(e.Value.Usage.CacheReadInputTokens)),
 				},
 			}
 		}
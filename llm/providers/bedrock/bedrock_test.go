@@ -15,6 +15,7 @@ import (
 	"github.com/lookatitude/beluga-ai/v2/config"
 	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/prompt"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
@@ -409,6 +410,26 @@ func TestConvertStreamEvent_Metadata(t *testing.T) {
 	}
 }
 
+func TestConvertStreamEvent_MetadataCacheReadTokens(t *testing.T) {
+	event := &brtypes.ConverseStreamOutputMemberMetadata{
+		Value: brtypes.ConverseStreamMetadataEvent{
+			Usage: &brtypes.TokenUsage{
+				InputTokens:          aws.Int32(10),
+				OutputTokens:         aws.Int32(5),
+				TotalTokens:          aws.Int32(15),
+				CacheReadInputTokens: aws.Int32(8),
+			},
+		},
+	}
+	chunk := convertStreamEvent(event, "test-model")
+	if chunk == nil || chunk.Usage == nil {
+		t.Fatal("expected chunk with usage")
+	}
+	if chunk.Usage.CachedTokens != 8 {
+		t.Errorf("CachedTokens = %d, want 8", chunk.Usage.CachedTokens)
+	}
+}
+
 func TestRegistryNew(t *testing.T) {
 	// This tests that the init() registration works.
 	// We can't fully test llm.New("bedrock", ...) without AWS credentials,
@@ -648,6 +669,101 @@ func TestCacheReadTokens(t *testing.T) {
 	}
 }
 
+func TestConvertMessages_CacheBreakpoint(t *testing.T) {
+	msgs, err := prompt.NewBuilder(
+		prompt.WithSystemPrompt("You are a helpful assistant."),
+		prompt.WithStaticContext([]string{"Long reference document."}),
+		prompt.WithCacheBreakpoint(),
+		prompt.WithUserInput(schema.NewHumanMessage("Summarize it.")),
+	).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	out, system, err := convertMessages(msgs)
+	if err != nil {
+		t.Fatalf("convertMessages() error: %v", err)
+	}
+	if len(system) != 3 {
+		t.Fatalf("len(system) = %d, want 3", len(system))
+	}
+	for i, block := range system[:2] {
+		if _, ok := block.(*brtypes.SystemContentBlockMemberText); !ok {
+			t.Errorf("system[%d] = %T, want text", i, block)
+		}
+	}
+	cp, ok := system[2].(*brtypes.SystemContentBlockMemberCachePoint)
+	if !ok {
+		t.Fatalf("system[2] = %T, want cache point", system[2])
+	}
+	if cp.Value.Type != brtypes.CachePointTypeDefault {
+		t.Errorf("cache point type = %q", cp.Value.Type)
+	}
+	if len(out) != 1 || len(out[0].Content) != 1 {
+		t.Fatalf("messages = %+v, want one single-block message", out)
+	}
+}
+
+func TestConvertMessages_CacheBreakpointAfterMessages(t *testing.T) {
+	marker := &schema.SystemMessage{
+		Parts:    []schema.ContentPart{schema.TextPart{Text: ""}},
+		Metadata: map[string]any{"cache_breakpoint": true},
+	}
+	tagged := schema.NewHumanMessage("Earlier question")
+	tagged.Metadata = map[string]any{"cache_breakpoint": true}
+
+	tests := []struct {
+		name       string
+		msgs       []schema.Message
+		wantSystem int
+		wantBlocks []int
+		cacheAt    int // index of the message ending with a cache point, or -1
+	}{
+		{
+			name:       "marker after conversation",
+			msgs:       []schema.Message{schema.NewHumanMessage("Hi"), schema.NewAIMessage("Hello"), marker, schema.NewHumanMessage("Next")},
+			wantBlocks: []int{1, 2, 1},
+			cacheAt:    1,
+		},
+		{
+			name:       "tagged message",
+			msgs:       []schema.Message{schema.NewSystemMessage("sys"), tagged, schema.NewAIMessage("Answer")},
+			wantSystem: 1,
+			wantBlocks: []int{2, 1},
+			cacheAt:    0,
+		},
+		{
+			name:       "marker with nothing before it",
+			msgs:       []schema.Message{marker, schema.NewHumanMessage("Hi")},
+			wantBlocks: []int{1},
+			cacheAt:    -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, system, err := convertMessages(tt.msgs)
+			if err != nil {
+				t.Fatalf("convertMessages() error: %v", err)
+			}
+			if len(system) != tt.wantSystem {
+				t.Errorf("len(system) = %d, want %d", len(system), tt.wantSystem)
+			}
+			if len(out) != len(tt.wantBlocks) {
+				t.Fatalf("len(out) = %d, want %d", len(out), len(tt.wantBlocks))
+			}
+			for i, msg := range out {
+				if len(msg.Content) != tt.wantBlocks[i] {
+					t.Errorf("len(out[%d].Content) = %d, want %d", i, len(msg.Content), tt.wantBlocks[i])
+				}
+				_, isCache := msg.Content[len(msg.Content)-1].(*brtypes.ContentBlockMemberCachePoint)
+				if isCache != (i == tt.cacheAt) {
+					t.Errorf("out[%d] ends with cache point = %v, want %v", i, isCache, i == tt.cacheAt)
+				}
+			}
+		})
+	}
+}
+
 // TestConvertMessages_UnsupportedType tests that unsupported message types return errors.
 func TestConvertMessages_UnsupportedType(t *testing.T) {
 	type unsupportedMsg struct {
@@ -675,7 +791,7 @@ func TestBuildStreamInput_WithSystemAndTools(t *testing.T) {
 	bound := m.BindTools([]schema.ToolDefinition{
 		{Name: "get_weather", Description: "Get weather info", InputSchema: map[string]any{"type": "object"}},
 	})
-	input, err := bound.(*Model).buildStreamInput([]schema.Message{
+	input, err := bound.(*Model).buildStreamInput(context.Background(), []schema.Message{
 		schema.NewSystemMessage("Be helpful"),
 		schema.NewHumanMessage("Hi"),
 	}, []llm.GenerateOption{
@@ -714,7 +830,7 @@ func TestBuildStreamInput_Error(t *testing.T) {
 		schema.Message
 	}
 	m := NewWithClient(&mockClient{}, "test-model")
-	_, err := m.buildStreamInput([]schema.Message{
+	_, err := m.buildStreamInput(context.Background(), []schema.Message{
 		&unsupportedMsg{},
 	}, nil)
 	if err == nil {
@@ -878,7 +994,7 @@ func TestDocumentToJSON_MarshalError(t *testing.T) {
 // TestBuildInput_NoTools tests buildInput without tools.
 func TestBuildInput_NoTools(t *testing.T) {
 	m := NewWithClient(&mockClient{}, "test-model")
-	input, err := m.buildInput([]schema.Message{
+	input, err := m.buildInput(context.Background(), []schema.Message{
 		schema.NewHumanMessage("Hello"),
 	}, nil)
 	if err != nil {
@@ -34,14 +34,64 @@
 //   - APIKey: optional AWS access key ID (uses default credentials if unset)
 //   - BaseURL: optional custom Bedrock endpoint
 //   - Options["region"]: AWS region (defaults to "us-east-1")
+//   - Options["regions"]: further regions to fail over to, in order
+//   - Options["inference_profile"]: cross-region inference profile; see below
 //   - Options["secret_key"]: AWS secret access key (used with APIKey)
 //
+// # Region Failover
+//
+// Bedrock throttles per region. When several regions are configured, a
+// request that a region rejects with a ThrottlingException or
+// ServiceUnavailableException is retried in the next region, wrapping
+// around; other errors are returned at once. Calls start in the region that
+// served the last request, so traffic stays off a throttling region until
+// the one that took over throttles in turn. If every region throttles, the
+// error has code core.ErrRateLimit. The "region" metadata of a Generate
+// response names the region that served it. A stream fails over only when
+// it cannot be opened.
+//
+// Cross-region inference profiles spread load further within a geography.
+// With Options["inference_profile"] set to "auto", each region requests the
+// profile of its geography ("us", "us-gov", "eu" or "apac"), replacing any
+// geography prefix in Model; any other value, such as "global", is used as
+// the prefix in every region:
+//
+//	model, err := llm.New("bedrock", config.ProviderConfig{
+//	    Model: "anthropic.claude-sonnet-4-5-20250929-v1:0",
+//	    Options: map[string]any{
+//	        "regions":           []string{"us-east-1", "us-west-2", "eu-west-1"},
+//	        "inference_profile": "auto",
+//	    },
+//	})
+//	resp, err := model.Generate(ctx, msgs)
+//	fmt.Println(resp.Metadata["region"])
+//
+// # Prompt Caching
+//
+// Cache breakpoints set with prompt.WithCacheBreakpoint become Converse
+// cachePoint blocks, so models that support prompt caching, such as Claude,
+// reuse the prefix up to the breakpoint across requests. A breakpoint after
+// the system prompt and static context caches them; any other message whose
+// metadata sets "cache_breakpoint" to true gets a cache point after it.
+// Usage.CachedTokens reports the input tokens read from the cache, for both
+// Generate and Stream:
+//
+//	msgs, err := prompt.NewBuilder(
+//	    prompt.WithSystemPrompt(instructions),
+//	    prompt.WithStaticContext(documents),
+//	    prompt.WithCacheBreakpoint(),
+//	    prompt.WithUserInput(schema.NewHumanMessage(question)),
+//	).Build()
+//	resp, err := model.Generate(ctx, msgs)
+//	fmt.Println(resp.Usage.CachedTokens)
+//
 // # Key Types
 //
 //   - [Model]: the ChatModel implementation using the Bedrock Converse API
 //   - [ConverseAPI]: interface for the subset of bedrockruntime.Client methods used, enabling mock injection for tests
 //   - [New]: constructor from [config.ProviderConfig]
 //   - [NewWithClient]: constructor accepting a custom [ConverseAPI] implementation for testing
+//   - [RegionClient], [NewWithRegionClients]: a client per region, for testing region failover
 //
 // # Implementation Notes
 //
@@ -0,0 +1,185 @@
+package bedrock
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	brtypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	cfgpkg "github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// RegionClient is a ConverseAPI client for one AWS region, together with
+// the model or inference profile ID to request there.
+type RegionClient struct {
+	// Region is the AWS region the client calls, reported in the "region"
+	// metadata of responses.
+	Region string
+	// Client is the Bedrock client for Region.
+	Client ConverseAPI
+	// ModelID is the model or inference profile ID to request in Region.
+	ModelID string
+}
+
+// NewWithRegionClients creates a Model that fails over across the given
+// regional clients, in order, when a region throttles. This is useful for
+// testing. It returns an ErrInvalidInput error if clients is empty.
+func NewWithRegionClients(clients ...RegionClient) (*Model, error) {
+	if len(clients) == 0 {
+		return nil, core.Errorf(core.ErrInvalidInput, "bedrock: NewWithRegionClients requires at least one client")
+	}
+	return newRegionModel(clients), nil
+}
+
+// newRegionModel creates a Model over a non-empty list of regional clients.
+func newRegionModel(clients []RegionClient) *Model {
+	return &Model{
+		modelID: clients[0].ModelID,
+		regions: &regionPool{clients: slices.Clone(clients)},
+	}
+}
+
+// regionPool holds the regional clients of a Model. Calls start in the
+// region that last served a request, so that once a region throttles, later
+// calls go straight to the region that took over.
+type regionPool struct {
+	clients []RegionClient
+	start   atomic.Int32
+}
+
+// order returns the clients in the order to try them.
+func (p *regionPool) order() []RegionClient {
+	start := int(p.start.Load())
+	return append(slices.Clip(p.clients[start:]), p.clients[:start]...)
+}
+
+// served records that the client with the given region served a request.
+func (p *regionPool) served(region string) {
+	for i, c := range p.clients {
+		if c.Region == region {
+			p.start.Store(int32(i)) // #nosec G115 -- bounded by the number of regions
+			return
+		}
+	}
+}
+
+// inRegions calls fn with each regional client in turn until one does not
+// throttle. It returns fn's result and the client that produced it. When
+// every region throttles, the last error is returned.
+func inRegions[T any](ctx context.Context, p *regionPool, fn func(RegionClient) (T, error)) (T, RegionClient, error) {
+	var (
+		zero T
+		err  error
+	)
+	clients := p.order()
+	for i, c := range clients {
+		if i > 0 && ctx.Err() != nil {
+			return zero, c, ctx.Err()
+		}
+		var res T
+		res, err = fn(c)
+		if err == nil {
+			p.served(c.Region)
+			return res, c, nil
+		}
+		if !isThrottled(err) {
+			return zero, c, err
+		}
+	}
+	return zero, clients[len(clients)-1], err
+}
+
+// isThrottled reports whether err means that a region is over capacity for
+// the request, so that another region may serve it.
+func isThrottled(err error) bool {
+	var throttling *brtypes.ThrottlingException
+	var unavailable *brtypes.ServiceUnavailableException
+	return errors.As(err, &throttling) || errors.As(err, &unavailable)
+}
+
+// parseRegions returns the regions to call, in order: Options["region"]
+// followed by Options["regions"]. It defaults to "us-east-1".
+func parseRegions(cfg cfgpkg.ProviderConfig) ([]string, error) {
+	var regions []string
+	if region, _ := cfgpkg.GetOption[string](cfg, "region"); region != "" {
+		regions = append(regions, region)
+	}
+
+	switch list := cfg.Options["regions"].(type) {
+	case nil:
+	case []string:
+		regions = append(regions, list...)
+	case []any:
+		for i, v := range list {
+			region, ok := v.(string)
+			if !ok {
+				return nil, core.Errorf(core.ErrInvalidInput, "bedrock: regions[%d] must be a string, got %T", i, v)
+			}
+			regions = append(regions, region)
+		}
+	default:
+		return nil, core.Errorf(core.ErrInvalidInput, "bedrock: regions must be a list, got %T", list)
+	}
+
+	var out []string
+	for _, region := range regions {
+		if region != "" && !slices.Contains(out, region) {
+			out = append(out, region)
+		}
+	}
+	if len(out) == 0 {
+		out = []string{"us-east-1"}
+	}
+	return out, nil
+}
+
+// profilePrefixes are the geography prefixes of cross-region inference
+// profile IDs.
+var profilePrefixes = []string{"us", "us-gov", "eu", "apac", "jp", "au", "ca", "global"}
+
+// inferenceProfileID returns the ID to request modelID with in region.
+// profile is Options["inference_profile"]: empty to use modelID as is,
+// "auto" for the cross-region inference profile of the region's geography,
+// or a geography prefix such as "us" or "global" to use for every region.
+// A geography prefix already present in modelID is replaced.
+func inferenceProfileID(modelID, region, profile string) (string, error) {
+	if profile == "" || strings.HasPrefix(modelID, "arn:") {
+		return modelID, nil
+	}
+
+	base := modelID
+	if geo, rest, ok := strings.Cut(modelID, "."); ok && slices.Contains(profilePrefixes, geo) {
+		if geo == "global" && profile == "auto" {
+			return modelID, nil
+		}
+		base = rest
+	}
+
+	geo := profile
+	if profile == "auto" {
+		geo = regionGeography(region)
+		if geo == "" {
+			return "", core.Errorf(core.ErrInvalidInput, "bedrock: no cross-region inference profile for region %q", region)
+		}
+	}
+	return geo + "." + base, nil
+}
+
+// regionGeography returns the geography prefix of the cross-region
+// inference profiles available in region, or "" if it is unknown.
+func regionGeography(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "us-gov"
+	case strings.HasPrefix(region, "us-"):
+		return "us"
+	case strings.HasPrefix(region, "eu-"):
+		return "eu"
+	case strings.HasPrefix(region, "ap-"):
+		return "apac"
+	}
+	return ""
+}
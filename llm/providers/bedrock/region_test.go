@@ -0,0 +1,295 @@
+package bedrock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	brtypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// regionalMock returns a mockClient for region that throttles when
+// *throttle is true, recording the model IDs it is called with.
+func regionalMock(region string, throttle *bool, calls *[]string) *mockClient {
+	return &mockClient{
+		converseFunc: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+			*calls = append(*calls, region+"/"+aws.ToString(params.ModelId))
+			if *throttle {
+				return nil, &brtypes.ThrottlingException{Message: aws.String("Too many requests")}
+			}
+			return &bedrockruntime.ConverseOutput{
+				Output: &brtypes.ConverseOutputMemberMessage{
+					Value: brtypes.Message{
+						Role:    brtypes.ConversationRoleAssistant,
+						Content: []brtypes.ContentBlock{&brtypes.ContentBlockMemberText{Value: "from " + region}},
+					},
+				},
+				StopReason: brtypes.StopReasonEndTurn,
+			}, nil
+		},
+		converseStreamFunc: func(ctx context.Context, params *bedrockruntime.ConverseStreamInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseStreamOutput, error) {
+			*calls = append(*calls, region+"/"+aws.ToString(params.ModelId))
+			if *throttle {
+				return nil, &brtypes.ThrottlingException{Message: aws.String("Too many requests")}
+			}
+			return &bedrockruntime.ConverseStreamOutput{}, nil
+		},
+	}
+}
+
+// newRegionTestModel creates a Model over clients, failing the test on error.
+func newRegionTestModel(t *testing.T, clients ...RegionClient) *Model {
+	t.Helper()
+	m, err := NewWithRegionClients(clients...)
+	if err != nil {
+		t.Fatalf("NewWithRegionClients() error: %v", err)
+	}
+	return m
+}
+
+func TestNewWithRegionClients_Empty(t *testing.T) {
+	m, err := NewWithRegionClients()
+	var cerr *core.Error
+	if m != nil || !errors.As(err, &cerr) || cerr.Code != core.ErrInvalidInput {
+		t.Errorf("NewWithRegionClients() = %v, %v, want an %s error", m, err, core.ErrInvalidInput)
+	}
+}
+
+func TestGenerate_RegionFailover(t *testing.T) {
+	var calls []string
+	eastThrottled, westThrottled := true, false
+	m := newRegionTestModel(t,
+		RegionClient{Region: "us-east-1", Client: regionalMock("us-east-1", &eastThrottled, &calls), ModelID: "us.model"},
+		RegionClient{Region: "us-west-2", Client: regionalMock("us-west-2", &westThrottled, &calls), ModelID: "us.model"},
+	)
+	msgs := []schema.Message{schema.NewHumanMessage("Hi")}
+
+	resp, err := m.Generate(context.Background(), msgs)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if resp.Text() != "from us-west-2" {
+		t.Errorf("text = %q, want %q", resp.Text(), "from us-west-2")
+	}
+	if resp.Metadata["region"] != "us-west-2" {
+		t.Errorf("region = %v, want us-west-2", resp.Metadata["region"])
+	}
+
+	// The next call starts in the region that served the last one.
+	calls = nil
+	eastThrottled = false
+	resp, err = m.Generate(context.Background(), msgs)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if resp.Metadata["region"] != "us-west-2" {
+		t.Errorf("region = %v, want us-west-2", resp.Metadata["region"])
+	}
+	if len(calls) != 1 || calls[0] != "us-west-2/us.model" {
+		t.Errorf("calls = %v, want [us-west-2/us.model]", calls)
+	}
+
+	// When that region throttles, calls move on, wrapping around.
+	calls = nil
+	westThrottled = true
+	resp, err = m.Generate(context.Background(), msgs)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if resp.Metadata["region"] != "us-east-1" {
+		t.Errorf("region = %v, want us-east-1", resp.Metadata["region"])
+	}
+	if fmt.Sprint(calls) != "[us-west-2/us.model us-east-1/us.model]" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestGenerate_AllRegionsThrottled(t *testing.T) {
+	var calls []string
+	throttled := true
+	m := newRegionTestModel(t,
+		RegionClient{Region: "us-east-1", Client: regionalMock("us-east-1", &throttled, &calls), ModelID: "m"},
+		RegionClient{Region: "eu-west-1", Client: regionalMock("eu-west-1", &throttled, &calls), ModelID: "m"},
+	)
+	_, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Code != core.ErrRateLimit {
+		t.Fatalf("err = %v, want a %s error", err, core.ErrRateLimit)
+	}
+	if len(calls) != 2 {
+		t.Errorf("calls = %v, want one per region", calls)
+	}
+}
+
+func TestGenerate_NoFailoverOnOtherErrors(t *testing.T) {
+	var westCalls int
+	m := newRegionTestModel(t,
+		RegionClient{Region: "us-east-1", ModelID: "m", Client: &mockClient{
+			converseFunc: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				return nil, &brtypes.ValidationException{Message: aws.String("bad request")}
+			},
+		}},
+		RegionClient{Region: "us-west-2", ModelID: "m", Client: &mockClient{
+			converseFunc: func(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error) {
+				westCalls++
+				return nil, nil
+			},
+		}},
+	)
+	_, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Code != core.ErrProviderDown {
+		t.Fatalf("err = %v, want a %s error", err, core.ErrProviderDown)
+	}
+	if westCalls != 0 {
+		t.Errorf("us-west-2 called %d times, want 0", westCalls)
+	}
+}
+
+func TestStream_RegionFailover(t *testing.T) {
+	var calls []string
+	eastThrottled, westThrottled := true, false
+	m := newRegionTestModel(t,
+		RegionClient{Region: "us-east-1", Client: regionalMock("us-east-1", &eastThrottled, &calls), ModelID: "m"},
+		RegionClient{Region: "us-west-2", Client: regionalMock("us-west-2", &westThrottled, &calls), ModelID: "m"},
+	)
+	for _, err := range m.Stream(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")}) {
+		if err != nil {
+			t.Fatalf("Stream() error: %v", err)
+		}
+	}
+	if fmt.Sprint(calls) != "[us-east-1/m us-west-2/m]" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	calls = nil
+	westThrottled = true
+	var streamErr error
+	for _, err := range m.Stream(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")}) {
+		streamErr = err
+	}
+	var coreErr *core.Error
+	if !errors.As(streamErr, &coreErr) || coreErr.Code != core.ErrRateLimit {
+		t.Fatalf("err = %v, want a %s error", streamErr, core.ErrRateLimit)
+	}
+}
+
+func TestGenerate_SingleClientNoRegionMetadata(t *testing.T) {
+	var calls []string
+	throttled := false
+	m := NewWithClient(regionalMock("", &throttled, &calls), "test-model")
+	resp, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if _, ok := resp.Metadata["region"]; ok {
+		t.Errorf("region metadata = %v, want none", resp.Metadata["region"])
+	}
+}
+
+func TestParseRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: "[us-east-1]"},
+		{name: "region", options: map[string]any{"region": "eu-west-1"}, want: "[eu-west-1]"},
+		{name: "regions", options: map[string]any{"regions": []string{"us-east-1", "us-west-2"}}, want: "[us-east-1 us-west-2]"},
+		{name: "region first", options: map[string]any{"region": "us-west-2", "regions": []any{"us-east-1", "us-west-2"}}, want: "[us-west-2 us-east-1]"},
+		{name: "not a list", options: map[string]any{"regions": "us-east-1"}, wantErr: true},
+		{name: "not a string", options: map[string]any{"regions": []any{"us-east-1", 2}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRegions(config.ProviderConfig{Options: tt.options})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseRegions() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRegions() error: %v", err)
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("parseRegions() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferenceProfileID(t *testing.T) {
+	const claude = "anthropic.claude-sonnet-4-5-20250929-v1:0"
+	tests := []struct {
+		name    string
+		modelID string
+		region  string
+		profile string
+		want    string
+		wantErr bool
+	}{
+		{name: "disabled", modelID: claude, region: "eu-west-1", want: claude},
+		{name: "auto us", modelID: claude, region: "us-east-1", profile: "auto", want: "us." + claude},
+		{name: "auto eu", modelID: claude, region: "eu-central-1", profile: "auto", want: "eu." + claude},
+		{name: "auto apac", modelID: claude, region: "ap-northeast-1", profile: "auto", want: "apac." + claude},
+		{name: "auto gov", modelID: claude, region: "us-gov-west-1", profile: "auto", want: "us-gov." + claude},
+		{name: "auto replaces prefix", modelID: "us." + claude, region: "eu-west-1", profile: "auto", want: "eu." + claude},
+		{name: "auto keeps global", modelID: "global." + claude, region: "eu-west-1", profile: "auto", want: "global." + claude},
+		{name: "explicit", modelID: claude, region: "eu-west-1", profile: "global", want: "global." + claude},
+		{name: "arn", modelID: "arn:aws:bedrock:us-east-1:123:inference-profile/x", region: "us-east-1", profile: "auto", want: "arn:aws:bedrock:us-east-1:123:inference-profile/x"},
+		{name: "unknown region", modelID: claude, region: "sa-east-1", profile: "auto", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inferenceProfileID(tt.modelID, tt.region, tt.profile)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("inferenceProfileID() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("inferenceProfileID() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("inferenceProfileID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_Regions(t *testing.T) {
+	m, err := New(config.ProviderConfig{
+		Model: "anthropic.claude-v2",
+		Options: map[string]any{
+			"regions":           []any{"us-east-1", "eu-west-1"},
+			"inference_profile": "auto",
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if m.ModelID() != "us.anthropic.claude-v2" {
+		t.Errorf("ModelID = %q, want %q", m.ModelID(), "us.anthropic.claude-v2")
+	}
+	clients := m.regions.clients
+	if len(clients) != 2 || clients[1].Region != "eu-west-1" || clients[1].ModelID != "eu.anthropic.claude-v2" {
+		t.Errorf("clients = %+v", clients)
+	}
+
+	_, err = New(config.ProviderConfig{
+		Model:   "anthropic.claude-v2",
+		Options: map[string]any{"region": "sa-east-1", "inference_profile": "auto"},
+	})
+	if err == nil {
+		t.Fatal("expected error for a region without inference profiles")
+	}
+}
@@ -43,22 +43,27 @@ func New(cfg config.ProviderConfig) (*Model, error) {
 	if model == "" {
 		model = defaultModel
 	}
+	return &Model{
+		client: newClient(cfg),
+		model:  model,
+	}, nil
+}
+
+// newClient creates the Cohere SDK client for cfg, shared by the chat model
+// and the embedder.
+func newClient(cfg config.ProviderConfig) *cohereclient.Client {
 	opts := []option.RequestOption{
 		cohereclient.WithToken(cfg.APIKey),
 	}
 	if cfg.BaseURL != "" {
 		opts = append(opts, cohereclient.WithBaseURL(cfg.BaseURL))
 	}
-	client := cohereclient.NewClient(opts...)
-	return &Model{
-		client: client,
-		model:  model,
-	}, nil
+	return cohereclient.NewClient(opts...)
 }
 
 // Generate sends messages and returns a complete AI response.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	req := m.buildRequest(msgs, opts)
+	req := m.buildRequest(ctx, msgs, opts)
 	resp, err := m.client.Chat(ctx, req)
 	if err != nil {
 		return nil, core.Errorf(core.ErrProviderDown, "cohere: generate failed: %w", err)
@@ -68,7 +73,7 @@ func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm
 
 // Stream sends messages and returns an iterator of response chunks.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	streamReq := m.buildStreamRequest(msgs, opts)
+	streamReq := m.buildStreamRequest(ctx, msgs, opts)
 
 	stream, err := m.client.ChatStream(ctx, streamReq)
 	if err != nil {
@@ -136,9 +141,9 @@ func (m *Model) ModelID() string {
 // buildRequest constructs a Cohere ChatRequest from Beluga messages.
 // Cohere's v1 chat API splits messages into: preamble (system), message (last user),
 // and chat_history (everything in between).
-func (m *Model) buildRequest(msgs []schema.Message, opts []llm.GenerateOption) *coherego.ChatRequest {
-	preamble, chatHistory, message := splitMessages(msgs)
+func (m *Model) buildRequest(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) *coherego.ChatRequest {
 	genOpts := llm.ApplyOptions(opts...)
+	preamble, chatHistory, message := splitMessages(llm.TrimToContextWindow(ctx, m.model, msgs, genOpts))
 
 	req := &coherego.ChatRequest{
 		Message: message,
@@ -157,9 +162,9 @@ func (m *Model) buildRequest(msgs []schema.Message, opts []llm.GenerateOption) *
 	return req
 }
 
-func (m *Model) buildStreamRequest(msgs []schema.Message, opts []llm.GenerateOption) *coherego.ChatStreamRequest {
-	preamble, chatHistory, message := splitMessages(msgs)
+func (m *Model) buildStreamRequest(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) *coherego.ChatStreamRequest {
 	genOpts := llm.ApplyOptions(opts...)
+	preamble, chatHistory, message := splitMessages(llm.TrimToContextWindow(ctx, m.model, msgs, genOpts))
 
 	req := &coherego.ChatStreamRequest{
 		Message: message,
@@ -530,7 +530,7 @@ func TestBuildStreamRequest_WithOptions(t *testing.T) {
 		llm.WithTopP(0.9),
 		llm.WithStopSequences("STOP"),
 	}
-	req := m.buildStreamRequest(msgs, opts)
+	req := m.buildStreamRequest(context.Background(), msgs, opts)
 	if req.Temperature == nil || *req.Temperature != 0.8 {
 		t.Errorf("Temperature = %v, want 0.8", req.Temperature)
 	}
@@ -553,7 +553,7 @@ func TestBuildStreamRequest_WithTools(t *testing.T) {
 		{Name: "search", Description: "search the web"},
 	}).(*Model)
 	msgs := []schema.Message{schema.NewHumanMessage("Hi")}
-	req := bound.buildStreamRequest(msgs, nil)
+	req := bound.buildStreamRequest(context.Background(), msgs, nil)
 	if len(req.Tools) != 1 {
 		t.Errorf("Tools len = %d, want 1", len(req.Tools))
 	}
@@ -38,10 +38,40 @@
 //   - APIKey: the Cohere API key (required)
 //   - BaseURL: optional, overrides the default Cohere API endpoint
 //
+// # Embeddings
+//
+// The package also registers an [Embedder] as "cohere-sdk" in the embedding
+// registry, so the chat import serves embeddings too. The name differs from
+// the "cohere" embedder of rag/embedding/providers/cohere, so both can be
+// imported. Embed sends at most 96
+// texts per request, the API's limit, splitting larger inputs. Besides
+// APIKey and BaseURL, it reads:
+//
+//   - Model: the embedding model (defaults to "embed-english-v3.0")
+//   - Options["input_type"]: "search_document" (default), "search_query",
+//     "classification" or "clustering"
+//   - Options["dimensions"]: overrides the model's default dimensionality
+//   - Options["batch_size"]: lowers the texts sent per request
+//
+// Documents and the queries matched against them should be embedded with
+// their input types; [Embedder.WithInputType] derives a query embedder that
+// shares the client:
+//
+//	docs, err := embedding.New("cohere-sdk", config.ProviderConfig{APIKey: key})
+//	if err != nil {
+//	    return err
+//	}
+//	ce, ok := docs.(*cohere.Embedder)
+//	if !ok {
+//	    return fmt.Errorf("unexpected embedder %T", docs)
+//	}
+//	queries, err := ce.WithInputType("search_query")
+//
 // # Key Types
 //
 //   - [Model]: the ChatModel implementation using the Cohere SDK
 //   - [New]: constructor from [config.ProviderConfig]
+//   - [Embedder], [NewEmbedder]: the embedding.Embedder implementation
 //
 // # Implementation Notes
 //
@@ -0,0 +1,167 @@
+package cohere
+
+import (
+	"context"
+
+	coherego "github.com/cohere-ai/cohere-go/v2"
+	cohereclient "github.com/cohere-ai/cohere-go/v2/client"
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/rag/embedding"
+)
+
+const (
+	defaultEmbeddingModel = "embed-english-v3.0"
+	defaultDimensions     = 1024
+
+	// maxEmbedBatch is the most texts the Embed API accepts per request.
+	maxEmbedBatch = 96
+)
+
+// embeddingDimensions maps known Cohere embedding models to their default
+// dimensions.
+var embeddingDimensions = map[string]int{
+	"embed-v4.0":                    1536,
+	"embed-english-v3.0":            1024,
+	"embed-multilingual-v3.0":       1024,
+	"embed-english-light-v3.0":      384,
+	"embed-multilingual-light-v3.0": 384,
+	"embed-english-v2.0":            4096,
+}
+
+func init() {
+	embedding.Register("cohere-sdk", func(cfg config.ProviderConfig) (embedding.Embedder, error) {
+		return NewEmbedder(cfg)
+	})
+}
+
+// Embedder implements embedding.Embedder using the Cohere SDK.
+type Embedder struct {
+	client    *cohereclient.Client
+	model     string
+	dims      int
+	inputType coherego.EmbedInputType
+	batchSize int
+}
+
+// Compile-time interface check.
+var _ embedding.Embedder = (*Embedder)(nil)
+
+// NewEmbedder creates a new Cohere Embedder.
+func NewEmbedder(cfg config.ProviderConfig) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, core.Errorf(core.ErrInvalidInput, "cohere: api_key is required")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+
+	dims := defaultDimensions
+	if d, ok := embeddingDimensions[model]; ok {
+		dims = d
+	}
+	if d, ok := config.GetOption[float64](cfg, "dimensions"); ok && d > 0 {
+		dims = int(d)
+	}
+
+	inputType := coherego.EmbedInputTypeSearchDocument
+	if it, ok := config.GetOption[string](cfg, "input_type"); ok && it != "" {
+		var err error
+		if inputType, err = coherego.NewEmbedInputTypeFromString(it); err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "cohere: %w", err)
+		}
+	}
+
+	batchSize := maxEmbedBatch
+	if n, ok := config.GetOption[float64](cfg, "batch_size"); ok && n > 0 && int(n) < maxEmbedBatch {
+		batchSize = int(n)
+	}
+
+	return &Embedder{
+		client:    newClient(cfg),
+		model:     model,
+		dims:      dims,
+		inputType: inputType,
+		batchSize: batchSize,
+	}, nil
+}
+
+// WithInputType returns a copy of e that embeds texts as inputType, such as
+// "search_query" for the queries matched against documents embedded as
+// "search_document". The copy shares e's client.
+func (e *Embedder) WithInputType(inputType string) (*Embedder, error) {
+	it, err := coherego.NewEmbedInputTypeFromString(inputType)
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "cohere: %w", err)
+	}
+	c := *e
+	c.inputType = it
+	return &c, nil
+}
+
+// Embed produces embeddings for a batch of texts, sending them in requests
+// of at most 96 texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.batchSize {
+		end := min(start+e.batchSize, len(texts))
+		vecs, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+// embedBatch embeds texts in one request.
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.client.Embed(ctx, &coherego.EmbedRequest{
+		Texts:          texts,
+		Model:          coherego.String(e.model),
+		InputType:      e.inputType.Ptr(),
+		EmbeddingTypes: []coherego.EmbeddingType{coherego.EmbeddingTypeFloat},
+	})
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "cohere: embed failed: %w", err)
+	}
+
+	var floats [][]float64
+	switch {
+	case resp.EmbeddingsByType != nil && resp.EmbeddingsByType.Embeddings != nil:
+		floats = resp.EmbeddingsByType.Embeddings.Float
+	case resp.EmbeddingsFloats != nil:
+		floats = resp.EmbeddingsFloats.Embeddings
+	}
+	if len(floats) != len(texts) {
+		return nil, core.Errorf(core.ErrProviderDown, "cohere: embed returned %d embeddings for %d texts", len(floats), len(texts))
+	}
+	return toFloat32(floats), nil
+}
+
+// EmbedSingle produces an embedding for a single text.
+func (e *Embedder) EmbedSingle(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// Dimensions returns the dimensionality of the embeddings.
+func (e *Embedder) Dimensions() int {
+	return e.dims
+}
+
+// toFloat32 converts the SDK's float64 vectors.
+func toFloat32(vecs [][]float64) [][]float32 {
+	out := make([][]float32, len(vecs))
+	for i, v := range vecs {
+		out[i] = make([]float32, len(v))
+		for j, x := range v {
+			out[i][j] = float32(x)
+		}
+	}
+	return out
+}
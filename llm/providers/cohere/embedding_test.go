@@ -0,0 +1,163 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/rag/embedding"
+)
+
+// embedServer returns a server that answers embed requests with one vector
+// per text, whose only element is the text's index across all requests, and
+// records the request bodies.
+func embedServer(t *testing.T, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+	var n int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embed" {
+			t.Errorf("path = %q, want /v1/embed", r.URL.Path)
+		}
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+
+		texts, _ := req["texts"].([]any)
+		vecs := make([][]float64, len(texts))
+		for i := range texts {
+			vecs[i] = []float64{float64(n)}
+			n++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		b, _ := json.Marshal(map[string]any{
+			"response_type": "embeddings_by_type",
+			"id":            "emb-123",
+			"embeddings":    map[string]any{"float": vecs},
+		})
+		w.Write(b)
+	}))
+}
+
+func TestEmbedder_Embed(t *testing.T) {
+	var requests []map[string]any
+	ts := embedServer(t, &requests)
+	defer ts.Close()
+
+	e, err := NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: ts.URL})
+	if err != nil {
+		t.Fatalf("NewEmbedder() error: %v", err)
+	}
+	vecs, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if fmt.Sprint(vecs) != "[[0] [1]]" {
+		t.Errorf("Embed() = %v", vecs)
+	}
+	req := requests[0]
+	if req["model"] != defaultEmbeddingModel || req["input_type"] != "search_document" {
+		t.Errorf("request = %v", req)
+	}
+	if fmt.Sprint(req["embedding_types"]) != "[float]" {
+		t.Errorf("embedding_types = %v, want [float]", req["embedding_types"])
+	}
+	if e.Dimensions() != 1024 {
+		t.Errorf("Dimensions() = %d, want 1024", e.Dimensions())
+	}
+}
+
+func TestEmbedder_Batches(t *testing.T) {
+	var requests []map[string]any
+	ts := embedServer(t, &requests)
+	defer ts.Close()
+
+	e, _ := NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: ts.URL})
+	texts := make([]string, 200)
+	for i := range texts {
+		texts[i] = fmt.Sprint(i)
+	}
+	vecs, err := e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("requests = %d, want 3", len(requests))
+	}
+	if len(vecs) != len(texts) {
+		t.Fatalf("len(Embed()) = %d, want %d", len(vecs), len(texts))
+	}
+	for i, v := range vecs {
+		if int(v[0]) != i {
+			t.Fatalf("vecs[%d] = %v, want [%d]", i, v, i)
+		}
+	}
+}
+
+func TestEmbedder_InputType(t *testing.T) {
+	var requests []map[string]any
+	ts := embedServer(t, &requests)
+	defer ts.Close()
+
+	docs, err := NewEmbedder(config.ProviderConfig{
+		APIKey:  "test",
+		BaseURL: ts.URL,
+		Model:   "embed-v4.0",
+		Options: map[string]any{"input_type": "classification"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmbedder() error: %v", err)
+	}
+	queries, err := docs.WithInputType("search_query")
+	if err != nil {
+		t.Fatalf("WithInputType() error: %v", err)
+	}
+	if _, err := queries.EmbedSingle(context.Background(), "q"); err != nil {
+		t.Fatalf("EmbedSingle() error: %v", err)
+	}
+	if _, err := docs.EmbedSingle(context.Background(), "d"); err != nil {
+		t.Fatalf("EmbedSingle() error: %v", err)
+	}
+	if requests[0]["input_type"] != "search_query" || requests[1]["input_type"] != "classification" {
+		t.Errorf("input types = %v, %v", requests[0]["input_type"], requests[1]["input_type"])
+	}
+	if queries.Dimensions() != 1536 {
+		t.Errorf("Dimensions() = %d, want 1536", queries.Dimensions())
+	}
+
+	if _, err := docs.WithInputType("bogus"); err == nil {
+		t.Error("expected error for unknown input type")
+	}
+	if _, err := NewEmbedder(config.ProviderConfig{APIKey: "test", Options: map[string]any{"input_type": "bogus"}}); err == nil {
+		t.Error("expected error for unknown input_type option")
+	}
+}
+
+func TestEmbedder_Errors(t *testing.T) {
+	if _, err := NewEmbedder(config.ProviderConfig{}); err == nil {
+		t.Error("expected error for missing api key")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"response_type": "embeddings_by_type", "id": "x", "embeddings": {"float": [[1]]}}`)
+	}))
+	defer ts.Close()
+	e, _ := NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: ts.URL})
+	if _, err := e.Embed(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("expected error for a missing embedding")
+	}
+}
+
+func TestEmbedder_Registry(t *testing.T) {
+	e, err := embedding.New("cohere-sdk", config.ProviderConfig{APIKey: "test"})
+	if err != nil {
+		t.Fatalf("embedding.New() error: %v", err)
+	}
+	if _, ok := e.(*Embedder); !ok {
+		t.Errorf("embedding.New() = %T, want *Embedder", e)
+	}
+}
@@ -26,6 +26,30 @@
 //	    schema.NewHumanMessage("Summarize this document"),
 //	})
 //
+// # Structured Output
+//
+// The "json_object" and "json_schema" response formats set with
+// llm.WithResponseFormat switch Gemini to JSON mode, with the schema of the
+// latter sent as the response schema; llm.NewStructured therefore gets
+// schema-constrained output natively. GenerateStructured derives the schema
+// from a Go type, validates the response against it, retrying once on a
+// violation, and returns the decoded value:
+//
+//	type Invoice struct {
+//	    Vendor string  `json:"vendor" required:"true"`
+//	    Total  float64 `json:"total" required:"true"`
+//	}
+//	inv, err := google.GenerateStructured[Invoice](ctx, model, msgs)
+//
+// # Citations
+//
+// Grounding metadata and recitation sources become [schema.Citation] values
+// in AIMessage.Citations, locating the supported span of the answer text
+// and the source's title and URI. Gemini cites the sources it retrieves or
+// searches rather than request documents, so DocumentIndex is -1;
+// [schema.DocumentPart] parts are sent as text. Streams do not report
+// citations.
+//
 // # Configuration
 //
 // The following [config.ProviderConfig] fields are used:
@@ -39,6 +63,7 @@
 //   - [Model]: the ChatModel implementation
 //   - [New]: constructor from [config.ProviderConfig]
 //   - [NewWithHTTPClient]: constructor accepting a custom *http.Client for testing
+//   - [GenerateStructured]: typed, schema-validated generation
 //
 // # Implementation Notes
 //
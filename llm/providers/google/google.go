@@ -3,9 +3,11 @@ package google
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"iter"
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/lookatitude/beluga-ai/v2/config"
 	"github.com/lookatitude/beluga-ai/v2/core"
@@ -68,7 +70,7 @@ func NewWithHTTPClient(cfg config.ProviderConfig, httpClient *http.Client) (*Mod
 
 // Generate sends messages and returns a complete AI response.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	contents, gcConfig := m.buildRequest(msgs, opts)
+	contents, gcConfig := m.buildRequest(ctx, msgs, opts)
 	resp, err := m.client.Models.GenerateContent(ctx, m.model, contents, gcConfig)
 	if err != nil {
 		return nil, core.Errorf(core.ErrProviderDown, "google: generate failed: %w", err)
@@ -78,7 +80,7 @@ func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm
 
 // Stream sends messages and returns an iterator of response chunks.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	contents, gcConfig := m.buildRequest(msgs, opts)
+	contents, gcConfig := m.buildRequest(ctx, msgs, opts)
 	return func(yield func(schema.StreamChunk, error) bool) {
 		for resp, err := range m.client.Models.GenerateContentStream(ctx, m.model, contents, gcConfig) {
 			if err != nil {
@@ -106,9 +108,9 @@ func (m *Model) ModelID() string {
 	return m.model
 }
 
-func (m *Model) buildRequest(msgs []schema.Message, opts []llm.GenerateOption) ([]*genai.Content, *genai.GenerateContentConfig) {
-	contents, systemInstruction := convertMessages(msgs)
+func (m *Model) buildRequest(ctx context.Context, msgs []schema.Message, opts []llm.GenerateOption) ([]*genai.Content, *genai.GenerateContentConfig) {
 	genOpts := llm.ApplyOptions(opts...)
+	contents, systemInstruction := convertMessages(llm.TrimToContextWindow(ctx, m.model, msgs, genOpts))
 
 	gcConfig := &genai.GenerateContentConfig{}
 	if systemInstruction != nil {
@@ -135,6 +137,10 @@ func (m *Model) buildRequest(msgs []schema.Message, opts []llm.GenerateOption) (
 		gcConfig.StopSequences = genOpts.StopSequences
 	}
 
+	if genOpts.Format != nil {
+		applyResponseFormat(gcConfig, *genOpts.Format)
+	}
+
 	if len(m.tools) > 0 {
 		gcConfig.Tools = convertTools(m.tools)
 	}
@@ -146,6 +152,21 @@ func (m *Model) buildRequest(msgs []schema.Message, opts []llm.GenerateOption) (
 	return contents, gcConfig
 }
 
+// applyResponseFormat sets Gemini's JSON mode for the "json_object" and
+// "json_schema" formats, passing the schema of the latter as the response
+// JSON schema.
+func applyResponseFormat(cfg *genai.GenerateContentConfig, format llm.ResponseFormat) {
+	switch format.Type {
+	case "json_object":
+		cfg.ResponseMIMEType = "application/json"
+	case "json_schema":
+		cfg.ResponseMIMEType = "application/json"
+		if format.Schema != nil {
+			cfg.ResponseJsonSchema = format.Schema
+		}
+	}
+}
+
 func applyToolChoice(cfg *genai.GenerateContentConfig, genOpts llm.GenerateOptions) {
 	switch genOpts.ToolChoice {
 	case llm.ToolChoiceAuto:
@@ -227,6 +248,8 @@ func convertHumanParts(parts []schema.ContentPart) []*genai.Part {
 		switch cp := p.(type) {
 		case schema.TextPart:
 			result = append(result, &genai.Part{Text: cp.Text})
+		case schema.DocumentPart:
+			result = append(result, &genai.Part{Text: documentText(cp)})
 		case schema.ImagePart:
 			if len(cp.Data) > 0 {
 				mime := cp.MimeType
@@ -252,6 +275,24 @@ func convertHumanParts(parts []schema.ContentPart) []*genai.Part {
 	return result
 }
 
+// documentText renders a document as text, since Gemini has no document
+// content type.
+func documentText(d schema.DocumentPart) string {
+	var b strings.Builder
+	b.WriteString("<document")
+	if d.Title != "" {
+		fmt.Fprintf(&b, " title=%q", d.Title)
+	}
+	b.WriteString(">\n")
+	if d.Context != "" {
+		b.WriteString(d.Context)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(d.Text)
+	b.WriteString("\n</document>")
+	return b.String()
+}
+
 func convertAIParts(m *schema.AIMessage) []*genai.Part {
 	var parts []*genai.Part
 	text := m.Text()
@@ -310,8 +351,17 @@ func convertResponse(resp *genai.GenerateContentResponse, modelID string) *schem
 		return ai
 	}
 
-	for _, part := range candidate.Content.Parts {
+	// offsets maps the index of each text part of the candidate to its byte
+	// offset in ai.Text(), which joins the parts with newlines.
+	offsets := make(map[int]int, len(candidate.Content.Parts))
+	textLen := 0
+	for i, part := range candidate.Content.Parts {
 		if part.Text != "" {
+			if textLen > 0 {
+				textLen++
+			}
+			offsets[i] = textLen
+			textLen += len(part.Text)
 			ai.Parts = append(ai.Parts, schema.TextPart{Text: part.Text})
 		}
 		if part.FunctionCall != nil {
@@ -323,10 +373,64 @@ func convertResponse(resp *genai.GenerateContentResponse, modelID string) *schem
 			})
 		}
 	}
+	ai.Citations = convertCitations(candidate, offsets)
 
 	return ai
 }
 
+// convertCitations returns the citations of candidate: a citation per
+// grounding chunk that supports a segment of the answer, followed by the
+// sources the answer recites. offsets maps candidate part indices to their
+// offsets in the answer text. Gemini cites the sources it retrieves or
+// searches, not request documents, so DocumentIndex is always -1.
+func convertCitations(candidate *genai.Candidate, offsets map[int]int) []schema.Citation {
+	var citations []schema.Citation
+	if gm := candidate.GroundingMetadata; gm != nil {
+		for _, support := range gm.GroundingSupports {
+			if support == nil || support.Segment == nil {
+				continue
+			}
+			seg := support.Segment
+			off := offsets[int(seg.PartIndex)]
+			for _, idx := range support.GroundingChunkIndices {
+				if idx < 0 || int(idx) >= len(gm.GroundingChunks) || gm.GroundingChunks[idx] == nil {
+					continue
+				}
+				cit := schema.Citation{
+					Start:         off + int(seg.StartIndex),
+					End:           off + int(seg.EndIndex),
+					DocumentIndex: -1,
+				}
+				switch chunk := gm.GroundingChunks[idx]; {
+				case chunk.RetrievedContext != nil:
+					cit.DocumentTitle = chunk.RetrievedContext.Title
+					cit.URI = chunk.RetrievedContext.URI
+					cit.CitedText = chunk.RetrievedContext.Text
+				case chunk.Web != nil:
+					cit.DocumentTitle = chunk.Web.Title
+					cit.URI = chunk.Web.URI
+				}
+				citations = append(citations, cit)
+			}
+		}
+	}
+	if cm := candidate.CitationMetadata; cm != nil {
+		for _, c := range cm.Citations {
+			if c == nil {
+				continue
+			}
+			citations = append(citations, schema.Citation{
+				Start:         int(c.StartIndex),
+				End:           int(c.EndIndex),
+				DocumentIndex: -1,
+				DocumentTitle: c.Title,
+				URI:           c.URI,
+			})
+		}
+	}
+	return citations
+}
+
 func convertStreamResponse(resp *genai.GenerateContentResponse, modelID string) schema.StreamChunk {
 	chunk := schema.StreamChunk{ModelID: modelID}
 	if resp == nil {
@@ -825,3 +825,58 @@ func TestStreamWithFunctionCalls(t *testing.T) {
 		t.Errorf("ToolCall.Name = %q, want %q", toolCalls[0].Name, "get_weather")
 	}
 }
+
+func TestConvertHumanParts_Document(t *testing.T) {
+	parts := convertHumanParts([]schema.ContentPart{
+		schema.DocumentPart{Title: "Handbook", Context: "HR policy, 2026", Text: "Employees get 25 days of leave."},
+	})
+	if len(parts) != 1 {
+		t.Fatalf("got %d parts, want 1", len(parts))
+	}
+	want := "<document title=\"Handbook\">\nHR policy, 2026\n\nEmployees get 25 days of leave.\n</document>"
+	if parts[0].Text != want {
+		t.Errorf("Text = %q, want %q", parts[0].Text, want)
+	}
+}
+
+func TestConvertResponse_Citations(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{Parts: []*genai.Part{
+				{Text: "Go was released in 2009."},
+				{FunctionCall: &genai.FunctionCall{Name: "noop"}},
+				{Text: "It is garbage collected."},
+			}},
+			GroundingMetadata: &genai.GroundingMetadata{
+				GroundingChunks: []*genai.GroundingChunk{
+					{Web: &genai.GroundingChunkWeb{URI: "https://go.dev", Title: "go.dev"}},
+					{RetrievedContext: &genai.GroundingChunkRetrievedContext{URI: "gs://docs/gc.md", Title: "GC", Text: "Go has a garbage collector."}},
+				},
+				GroundingSupports: []*genai.GroundingSupport{
+					{Segment: &genai.Segment{StartIndex: 0, EndIndex: 24}, GroundingChunkIndices: []int32{0}},
+					{Segment: &genai.Segment{PartIndex: 2, StartIndex: 6, EndIndex: 23}, GroundingChunkIndices: []int32{1, 7}},
+				},
+			},
+			CitationMetadata: &genai.CitationMetadata{Citations: []*genai.Citation{
+				{StartIndex: 0, EndIndex: 10, URI: "https://example.com/quote", Title: "Quote"},
+			}},
+		}},
+	}
+	ai := convertResponse(resp, "test-model")
+	text := ai.Text()
+
+	if len(ai.Citations) != 3 {
+		t.Fatalf("got %d citations, want 3: %+v", len(ai.Citations), ai.Citations)
+	}
+	web := ai.Citations[0]
+	if web.URI != "https://go.dev" || web.DocumentIndex != -1 || text[web.Start:web.End] != "Go was released in 2009." {
+		t.Errorf("web citation = %+v", web)
+	}
+	rc := ai.Citations[1]
+	if rc.URI != "gs://docs/gc.md" || rc.CitedText != "Go has a garbage collector." || text[rc.Start:rc.End] != "garbage collected" {
+		t.Errorf("retrieved context citation = %+v, span %q", rc, text[rc.Start:rc.End])
+	}
+	if ai.Citations[2].URI != "https://example.com/quote" || ai.Citations[2].End != 10 {
+		t.Errorf("recitation = %+v", ai.Citations[2])
+	}
+}
@@ -0,0 +1,81 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/jsonutil"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// GenerateStructured asks m for a value of type T. The JSON schema of T,
+// derived from its struct tags as by llm.NewStructured, is sent as
+// Gemini's response schema in JSON mode, so the model returns conforming
+// JSON that is unmarshaled into T. The response is also validated against
+// the schema; if it does not conform, the request is retried once with the
+// violation appended to the conversation. opts apply to every attempt.
+func GenerateStructured[T any](ctx context.Context, m *Model, msgs []schema.Message, opts ...llm.GenerateOption) (T, error) {
+	var zero T
+	sch := jsonutil.GenerateSchema(zero)
+	opts = append(slices.Clip(opts), llm.WithResponseFormat(llm.ResponseFormat{
+		Type:   "json_schema",
+		Schema: sch,
+	}))
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, err := m.Generate(ctx, msgs, opts...)
+		if err != nil {
+			return zero, err
+		}
+
+		text := resp.Text()
+		result, err := decodeStructured[T](text, sch)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		msgs = append(slices.Clip(msgs),
+			schema.NewAIMessage(text),
+			schema.NewHumanMessage(fmt.Sprintf(
+				"Your response did not match the JSON schema: %s\nPlease respond with JSON matching the schema.",
+				err.Error(),
+			)),
+		)
+	}
+
+	return zero, core.NewError("google.generate_structured", core.ErrInvalidInput,
+		"response does not match the schema after 2 attempts", lastErr)
+}
+
+// decodeStructured validates text against sch and unmarshals it into T.
+func decodeStructured[T any](text string, sch map[string]any) (T, error) {
+	var result T
+	var raw any
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return result, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := validateJSON(raw, sch); err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return result, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return result, nil
+}
+
+// validateJSON checks a decoded JSON value against sch. Null optional
+// properties are accepted, since Gemini may return null for fields it
+// leaves out.
+func validateJSON(value any, sch map[string]any) error {
+	if errs := jsonutil.ValidateSchema(value, sch, "root", jsonutil.NullOptional()); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
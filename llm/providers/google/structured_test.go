@@ -0,0 +1,186 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+type invoice struct {
+	Vendor string   `json:"vendor" required:"true"`
+	Total  float64  `json:"total" required:"true" minimum:"0"`
+	Status string   `json:"status" enum:"paid,due"`
+	Lines  []string `json:"lines"`
+	Notes  *string  `json:"notes"`
+}
+
+// structuredServer answers with the given texts in turn and records the
+// request bodies.
+func structuredServer(t *testing.T, texts ...string) (*Model, *[]map[string]any, func()) {
+	t.Helper()
+	var calls atomic.Int32
+	var bodies []map[string]any
+	ts, m := newTestModel(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		bodies = append(bodies, body)
+		i := int(calls.Add(1)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, geminiResponse(texts[min(i, len(texts)-1)], nil))
+	})
+	return m, &bodies, ts.Close
+}
+
+func TestGenerateStructured(t *testing.T) {
+	m, bodies, done := structuredServer(t, `{"vendor":"ACME","total":12.5,"status":"paid","lines":["bolts"],"notes":null}`)
+	defer done()
+
+	got, err := GenerateStructured[invoice](context.Background(), m, []schema.Message{
+		schema.NewHumanMessage("Extract the invoice"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateStructured() error: %v", err)
+	}
+	if got.Vendor != "ACME" || got.Total != 12.5 || got.Status != "paid" || len(got.Lines) != 1 || got.Notes != nil {
+		t.Errorf("result = %+v", got)
+	}
+
+	if len(*bodies) != 1 {
+		t.Fatalf("requests = %d, want 1", len(*bodies))
+	}
+	genCfg, _ := (*bodies)[0]["generationConfig"].(map[string]any)
+	if genCfg["responseMimeType"] != "application/json" {
+		t.Errorf("responseMimeType = %v", genCfg["responseMimeType"])
+	}
+	sch, _ := genCfg["responseJsonSchema"].(map[string]any)
+	props, _ := sch["properties"].(map[string]any)
+	if _, ok := props["vendor"]; !ok {
+		t.Errorf("responseJsonSchema = %v, want invoice properties", sch)
+	}
+}
+
+func TestGenerateStructured_RetriesOnViolation(t *testing.T) {
+	m, bodies, done := structuredServer(t,
+		`{"vendor":"ACME","total":-3}`,
+		`{"vendor":"ACME","total":3}`,
+	)
+	defer done()
+
+	got, err := GenerateStructured[invoice](context.Background(), m, []schema.Message{
+		schema.NewHumanMessage("Extract the invoice"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateStructured() error: %v", err)
+	}
+	if got.Total != 3 {
+		t.Errorf("Total = %v, want 3", got.Total)
+	}
+	if len(*bodies) != 2 {
+		t.Fatalf("requests = %d, want 2", len(*bodies))
+	}
+	retry, _ := json.Marshal((*bodies)[1]["contents"])
+	if !strings.Contains(string(retry), "below minimum") {
+		t.Errorf("retry contents = %s, want the violation", retry)
+	}
+}
+
+func TestGenerateStructured_FailsAfterRetry(t *testing.T) {
+	m, bodies, done := structuredServer(t, `{"total":1}`)
+	defer done()
+
+	_, err := GenerateStructured[invoice](context.Background(), m, []schema.Message{
+		schema.NewHumanMessage("Extract the invoice"),
+	})
+	var cerr *core.Error
+	if !errors.As(err, &cerr) || cerr.Code != core.ErrInvalidInput {
+		t.Fatalf("error = %v, want invalid_input", err)
+	}
+	if !strings.Contains(err.Error(), "root.vendor: required") {
+		t.Errorf("error = %v, want the violation", err)
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("requests = %d, want 2", len(*bodies))
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	sch := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"count": map[string]any{"type": "integer", "maximum": "10"},
+			"kind":  map[string]any{"type": "string", "enum": []any{"a", "b"}},
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"ok":    map[string]any{"type": "boolean"},
+		},
+		"required": []any{"name"},
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "valid", value: `{"name":"x","count":3,"kind":"a","tags":["t"],"ok":true}`},
+		{name: "optional null", value: `{"name":"x","count":null}`},
+		{name: "not an object", value: `[1]`, wantErr: "root: expected object, got array"},
+		{name: "missing required", value: `{}`, wantErr: "root.name: required"},
+		{name: "required null", value: `{"name":null}`, wantErr: "name: expected string, got null"},
+		{name: "fractional integer", value: `{"name":"x","count":1.5}`, wantErr: "count: expected integer"},
+		{name: "above maximum", value: `{"name":"x","count":11}`, wantErr: "exceeds maximum"},
+		{name: "not in enum", value: `{"name":"x","kind":"c"}`, wantErr: "kind: c is not one of"},
+		{name: "bad item", value: `{"name":"x","tags":["t",2]}`, wantErr: "tags[1]: expected string, got number"},
+		{name: "bad boolean", value: `{"name":"x","ok":"yes"}`, wantErr: "ok: expected boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(tt.value), &v); err != nil {
+				t.Fatal(err)
+			}
+			err := validateJSON(v, sch)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateJSON() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateJSON() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildRequest_ResponseFormat(t *testing.T) {
+	m := &Model{model: "gemini-2.5-flash"}
+	msgs := []schema.Message{schema.NewHumanMessage("Hi")}
+
+	_, cfg := m.buildRequest(context.Background(), msgs, []llm.GenerateOption{llm.WithResponseFormat(llm.ResponseFormat{Type: "json_object"})})
+	if cfg.ResponseMIMEType != "application/json" || cfg.ResponseJsonSchema != nil {
+		t.Errorf("json_object: mime = %q, schema = %v", cfg.ResponseMIMEType, cfg.ResponseJsonSchema)
+	}
+
+	sch := map[string]any{"type": "object"}
+	_, cfg = m.buildRequest(context.Background(), msgs, []llm.GenerateOption{llm.WithResponseFormat(llm.ResponseFormat{Type: "json_schema", Schema: sch})})
+	if cfg.ResponseMIMEType != "application/json" || cfg.ResponseJsonSchema == nil {
+		t.Errorf("json_schema: mime = %q, schema = %v", cfg.ResponseMIMEType, cfg.ResponseJsonSchema)
+	}
+
+	_, cfg = m.buildRequest(context.Background(), msgs, nil)
+	if cfg.ResponseMIMEType != "" {
+		t.Errorf("default: mime = %q, want empty", cfg.ResponseMIMEType)
+	}
+}
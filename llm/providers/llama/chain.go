@@ -0,0 +1,212 @@
+package llama
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net"
+	"net/http"
+
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/openai/openai-go"
+)
+
+// backendSpec is one entry of Options["backends"].
+type backendSpec struct {
+	name    string
+	model   string
+	apiKey  string
+	baseURL string
+}
+
+// parseBackends reads Options["backends"]: a list of backend names, or of
+// objects with a "backend" name and optional "model", "api_key" and
+// "base_url" overrides.
+func parseBackends(v any) ([]backendSpec, error) {
+	var entries []any
+	switch list := v.(type) {
+	case []string:
+		for _, name := range list {
+			entries = append(entries, name)
+		}
+	case []any:
+		entries = list
+	default:
+		return nil, core.Errorf(core.ErrInvalidInput, "llama: backends must be a list, got %T", v)
+	}
+	if len(entries) == 0 {
+		return nil, core.Errorf(core.ErrInvalidInput, "llama: backends must not be empty")
+	}
+
+	specs := make([]backendSpec, len(entries))
+	for i, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			specs[i].name = e
+		case map[string]any:
+			specs[i].name, _ = e["backend"].(string)
+			specs[i].model, _ = e["model"].(string)
+			specs[i].apiKey, _ = e["api_key"].(string)
+			specs[i].baseURL, _ = e["base_url"].(string)
+		default:
+			return nil, core.Errorf(core.ErrInvalidInput, "llama: backends[%d] must be a name or an object, got %T", i, entry)
+		}
+	}
+	return specs, nil
+}
+
+// newChain creates a chainModel over the backends listed in specs. Each
+// backend uses the model, API key and base URL of its entry, falling back
+// to cfg's model and API key and to the backend's default URL.
+func newChain(cfg config.ProviderConfig, specs []backendSpec) (*chainModel, error) {
+	c := &chainModel{
+		names:  make([]string, len(specs)),
+		models: make([]llm.ChatModel, len(specs)),
+	}
+	for i, spec := range specs {
+		baseURL, ok := backends[spec.name]
+		if !ok {
+			return nil, core.Errorf(core.ErrInvalidInput, "llama: unsupported backend %q in backends[%d], supported: together, fireworks, groq, sambanova, cerebras, ollama", spec.name, i)
+		}
+		bcfg := cfg
+		bcfg.BaseURL = baseURL
+		if spec.baseURL != "" {
+			bcfg.BaseURL = spec.baseURL
+		}
+		if spec.model != "" {
+			bcfg.Model = spec.model
+		}
+		if spec.apiKey != "" {
+			bcfg.APIKey = spec.apiKey
+		}
+		if bcfg.Model == "" {
+			return nil, core.Errorf(core.ErrInvalidInput, "llama: model is required for backends[%d]", i)
+		}
+
+		m, err := llm.New(spec.name, bcfg)
+		if err != nil {
+			return nil, err
+		}
+		c.names[i] = spec.name
+		c.models[i] = m
+	}
+	return c, nil
+}
+
+// chainModel tries its backends in order, moving on to the next one when a
+// backend fails with a transient error.
+type chainModel struct {
+	names  []string
+	models []llm.ChatModel
+}
+
+// Compile-time interface check.
+var _ llm.ChatModel = (*chainModel)(nil)
+
+// Generate returns the response of the first backend that succeeds, with
+// the backend's name in its "backend" metadata.
+func (c *chainModel) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
+	steps := make([]resilience.FallbackStep[*schema.AIMessage], len(c.models))
+	for i, m := range c.models {
+		steps[i] = resilience.FallbackStep[*schema.AIMessage]{
+			Fn: func(ctx context.Context) (*schema.AIMessage, error) {
+				resp, err := m.Generate(ctx, msgs, opts...)
+				if err != nil {
+					return nil, err
+				}
+				if resp.Metadata == nil {
+					resp.Metadata = make(map[string]any)
+				}
+				resp.Metadata["backend"] = c.names[i]
+				return resp, nil
+			},
+			ShouldAdvance: shouldFallback,
+		}
+	}
+	return resilience.FallbackSteps(ctx, steps...)
+}
+
+// openedStream is a backend stream whose first chunk has been read.
+type openedStream struct {
+	first schema.StreamChunk
+	empty bool
+	next  func() (schema.StreamChunk, error, bool)
+	stop  func()
+}
+
+// Stream streams from the first backend whose stream starts without error.
+// Once a backend has produced a chunk, later errors are passed through
+// rather than falling back, since the caller has already seen output.
+func (c *chainModel) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
+	return func(yield func(schema.StreamChunk, error) bool) {
+		steps := make([]resilience.FallbackStep[*openedStream], len(c.models))
+		for i, m := range c.models {
+			steps[i] = resilience.FallbackStep[*openedStream]{
+				Fn: func(ctx context.Context) (*openedStream, error) {
+					next, stop := iter.Pull2(m.Stream(ctx, msgs, opts...))
+					first, err, ok := next()
+					if err != nil {
+						stop()
+						return nil, err
+					}
+					return &openedStream{first: first, empty: !ok, next: next, stop: stop}, nil
+				},
+				ShouldAdvance: shouldFallback,
+			}
+		}
+
+		s, err := resilience.FallbackSteps(ctx, steps...)
+		if err != nil {
+			yield(schema.StreamChunk{}, err)
+			return
+		}
+		defer s.stop()
+		if s.empty || !yield(s.first, nil) {
+			return
+		}
+		for {
+			chunk, err, ok := s.next()
+			if !ok || !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// BindTools returns a chain whose backends all include the given tools.
+func (c *chainModel) BindTools(tools []schema.ToolDefinition) llm.ChatModel {
+	bound := &chainModel{names: c.names, models: make([]llm.ChatModel, len(c.models))}
+	for i, m := range c.models {
+		bound.models[i] = m.BindTools(tools)
+	}
+	return bound
+}
+
+// ModelID returns the model identifier of the first backend.
+func (c *chainModel) ModelID() string {
+	return c.models[0].ModelID()
+}
+
+// shouldFallback reports whether err is transient, so that another backend
+// may succeed: any error resilience.ShouldFallback accepts, a rate limit,
+// timeout or server error status from an OpenAI-compatible backend, or a
+// network error.
+func shouldFallback(err error) bool {
+	if resilience.ShouldFallback(err) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		s := apiErr.StatusCode
+		return s == http.StatusRequestTimeout || s == http.StatusTooManyRequests || s >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,237 @@
+package llama
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/openai/openai-go"
+)
+
+// failingServer answers every request with status and counts the requests.
+func failingServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-should-retry", "false")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"error":{"message":"unavailable"}}`)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+// okServer answers chat completions and streams with text.
+func okServer(t *testing.T, text string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"stream":true`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, streamResponse([]string{text}))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, mockResponse(text))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func newChainModel(t *testing.T, entries ...any) llm.ChatModel {
+	t.Helper()
+	m, err := New(config.ProviderConfig{
+		Model:   "llama3.1",
+		APIKey:  "test",
+		Options: map[string]any{"backends": entries},
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return m
+}
+
+func TestChain_GenerateFallsBack(t *testing.T) {
+	down, downCalls := failingServer(t, http.StatusServiceUnavailable)
+	up := okServer(t, "Hello from Fireworks!")
+
+	m := newChainModel(t,
+		map[string]any{"backend": "together", "base_url": down.URL},
+		map[string]any{"backend": "fireworks", "base_url": up.URL, "model": "llama-v3p1-70b"},
+	)
+	resp, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if resp.Text() != "Hello from Fireworks!" {
+		t.Errorf("text = %q", resp.Text())
+	}
+	if resp.Metadata["backend"] != "fireworks" {
+		t.Errorf("backend = %v, want fireworks", resp.Metadata["backend"])
+	}
+	if downCalls.Load() != 1 {
+		t.Errorf("together calls = %d, want 1", downCalls.Load())
+	}
+	if m.ModelID() != "llama3.1" {
+		t.Errorf("ModelID() = %q, want the first backend's model", m.ModelID())
+	}
+}
+
+func TestChain_GenerateFirstBackendServes(t *testing.T) {
+	up := okServer(t, "Hello from Together!")
+	other, otherCalls := failingServer(t, http.StatusServiceUnavailable)
+
+	m := newChainModel(t,
+		map[string]any{"backend": "together", "base_url": up.URL},
+		map[string]any{"backend": "fireworks", "base_url": other.URL},
+	)
+	resp, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if resp.Metadata["backend"] != "together" {
+		t.Errorf("backend = %v, want together", resp.Metadata["backend"])
+	}
+	if otherCalls.Load() != 0 {
+		t.Errorf("fireworks calls = %d, want 0", otherCalls.Load())
+	}
+}
+
+func TestChain_GenerateStopsOnClientError(t *testing.T) {
+	bad, _ := failingServer(t, http.StatusBadRequest)
+	next := okServer(t, "unused")
+
+	m := newChainModel(t,
+		map[string]any{"backend": "together", "base_url": bad.URL},
+		map[string]any{"backend": "fireworks", "base_url": next.URL},
+	)
+	_, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("error = %v, want the 400 from the first backend", err)
+	}
+}
+
+func TestChain_GenerateAllFail(t *testing.T) {
+	a, aCalls := failingServer(t, http.StatusTooManyRequests)
+	b, bCalls := failingServer(t, http.StatusBadGateway)
+
+	m := newChainModel(t,
+		map[string]any{"backend": "together", "base_url": a.URL},
+		map[string]any{"backend": "fireworks", "base_url": b.URL},
+	)
+	_, err := m.Generate(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")})
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("error = %v, want the last backend's error", err)
+	}
+	if aCalls.Load() != 1 || bCalls.Load() != 1 {
+		t.Errorf("calls = %d, %d, want 1, 1", aCalls.Load(), bCalls.Load())
+	}
+}
+
+func TestChain_StreamFallsBack(t *testing.T) {
+	down, _ := failingServer(t, http.StatusServiceUnavailable)
+	up := okServer(t, "Llama")
+
+	m := newChainModel(t,
+		map[string]any{"backend": "together", "base_url": down.URL},
+		map[string]any{"backend": "fireworks", "base_url": up.URL},
+	)
+	var text strings.Builder
+	for chunk, err := range m.Stream(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")}) {
+		if err != nil {
+			t.Fatalf("Stream() error: %v", err)
+		}
+		text.WriteString(chunk.Delta)
+	}
+	if text.String() != "Llama" {
+		t.Errorf("text = %q, want %q", text.String(), "Llama")
+	}
+}
+
+func TestChain_StreamAllFail(t *testing.T) {
+	a, _ := failingServer(t, http.StatusServiceUnavailable)
+
+	m := newChainModel(t, map[string]any{"backend": "together", "base_url": a.URL})
+	var errs int
+	for _, err := range m.Stream(context.Background(), []schema.Message{schema.NewHumanMessage("Hi")}) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("errors = %d, want 1", errs)
+	}
+}
+
+func TestChain_BindTools(t *testing.T) {
+	m := newChainModel(t, "together", "fireworks")
+	bound := m.BindTools([]schema.ToolDefinition{{Name: "search", Description: "search"}})
+	c, ok := bound.(*chainModel)
+	if !ok || len(c.models) != 2 || c.names[1] != "fireworks" {
+		t.Errorf("bound = %#v, want a two-backend chain", bound)
+	}
+}
+
+func TestNew_BackendsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		backends any
+	}{
+		{name: "not a list", model: "m", backends: "together"},
+		{name: "empty", model: "m", backends: []any{}},
+		{name: "bad entry", model: "m", backends: []any{42}},
+		{name: "unknown backend", model: "m", backends: []string{"together", "nope"}},
+		{name: "missing model", backends: []any{map[string]any{"backend": "together", "model": "m"}, "fireworks"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(config.ProviderConfig{
+				Model:   tt.model,
+				Options: map[string]any{"backends": tt.backends},
+			})
+			var cerr *core.Error
+			if !errors.As(err, &cerr) || cerr.Code != core.ErrInvalidInput {
+				t.Errorf("error = %v, want invalid_input", err)
+			}
+		})
+	}
+}
+
+func TestShouldFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "provider down", err: core.NewError("x", core.ErrProviderDown, "down", nil), want: true},
+		{name: "invalid input", err: core.NewError("x", core.ErrInvalidInput, "bad", nil), want: false},
+		{name: "rate limited", err: &openai.Error{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "server error", err: fmt.Errorf("wrapped: %w", &openai.Error{StatusCode: http.StatusInternalServerError}), want: true},
+		{name: "unauthorized", err: &openai.Error{StatusCode: http.StatusUnauthorized}, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+		{name: "plain", err: errors.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldFallback(tt.err); got != tt.want {
+				t.Errorf("shouldFallback(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -36,6 +36,30 @@
 //   - APIKey: the API key for the chosen backend
 //   - BaseURL: optional, overrides the backend's default URL
 //   - Options["backend"]: hosting backend to use (defaults to "together"; supported: "together", "fireworks", "groq", "sambanova", "cerebras", "ollama")
+//   - Options["backends"]: ordered fallback chain of backends; see below
+//
+// # Fallback Chain
+//
+// When Options["backends"] lists several backends, Generate and Stream try
+// them in order and return the first success. A backend is skipped only
+// when it fails transiently — a rate limit, timeout, server error or
+// network failure — so invalid requests fail fast. Entries are backend
+// names or objects overriding the model, API key and base URL, which
+// usually differ between hosts; Model and APIKey serve as defaults, and
+// each backend uses its own default URL. The "backend" metadata of a
+// Generate response names the backend that served it:
+//
+//	model, err := llm.New("llama", config.ProviderConfig{
+//	    Model: "meta-llama/Llama-3.3-70B-Instruct-Turbo",
+//	    Options: map[string]any{"backends": []any{
+//	        map[string]any{"backend": "together", "api_key": togetherKey},
+//	        map[string]any{"backend": "groq", "api_key": groqKey, "model": "llama-3.3-70b-versatile"},
+//	    }},
+//	})
+//	resp, err := model.Generate(ctx, msgs)
+//	fmt.Println(resp.Metadata["backend"])
+//
+// A stream falls back only before it has produced its first chunk.
 //
 // # Direct Construction
 //
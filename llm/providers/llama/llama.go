@@ -25,7 +25,18 @@ func init() {
 }
 
 // New creates a new Llama ChatModel by delegating to a hosting provider.
+// If Options["backends"] lists several backends, the model tries them in
+// order, falling back to the next one when a backend fails with a transient
+// error.
 func New(cfg config.ProviderConfig) (llm.ChatModel, error) {
+	if list, ok := cfg.Options["backends"]; ok {
+		specs, err := parseBackends(list)
+		if err != nil {
+			return nil, err
+		}
+		return newChain(cfg, specs)
+	}
+
 	if cfg.Model == "" {
 		return nil, core.Errorf(core.ErrInvalidInput, "llama: model is required")
 	}
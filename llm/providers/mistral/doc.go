@@ -35,10 +35,27 @@
 //   - BaseURL: optional, defaults to "https://api.mistral.ai"
 //   - Timeout: optional request timeout (defaults to 30s)
 //
+// # Embeddings
+//
+// The package also registers an [Embedder] as "mistral-sdk" in the embedding
+// registry, so the chat import serves embeddings too. The name differs from
+// the "mistral" embedder of rag/embedding/providers/mistral, so both can be
+// imported:
+//
+//	emb, err := embedding.New("mistral-sdk", config.ProviderConfig{APIKey: key})
+//	vecs, err := emb.Embed(ctx, texts)
+//
+// Model defaults to "mistral-embed". Embed sends all texts in one request
+// unless Options["batch_size"] limits the texts per request, and
+// Options["dimensions"] overrides the model's default dimensionality. The
+// Mistral SDK takes no context, so cancellation is only checked between
+// requests.
+//
 // # Key Types
 //
 //   - [Model]: the ChatModel implementation using the Mistral SDK
 //   - [New]: constructor from [config.ProviderConfig]
+//   - [Embedder], [NewEmbedder]: the embedding.Embedder implementation
 //
 // # Implementation Notes
 //
@@ -0,0 +1,134 @@
+package mistral
+
+import (
+	"context"
+
+	"github.com/gage-technologies/mistral-go"
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/rag/embedding"
+)
+
+const (
+	defaultEmbeddingModel = "mistral-embed"
+	defaultDimensions     = 1024
+)
+
+// embeddingDimensions maps known Mistral embedding models to their default
+// dimensions.
+var embeddingDimensions = map[string]int{
+	"mistral-embed":        1024,
+	"codestral-embed":      1536,
+	"codestral-embed-2505": 1536,
+}
+
+func init() {
+	embedding.Register("mistral-sdk", func(cfg config.ProviderConfig) (embedding.Embedder, error) {
+		return NewEmbedder(cfg)
+	})
+}
+
+// Embedder implements embedding.Embedder using the Mistral AI SDK.
+type Embedder struct {
+	client    *mistral.MistralClient
+	model     string
+	dims      int
+	batchSize int
+}
+
+// Compile-time interface check.
+var _ embedding.Embedder = (*Embedder)(nil)
+
+// NewEmbedder creates a new Mistral Embedder.
+func NewEmbedder(cfg config.ProviderConfig) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, core.Errorf(core.ErrInvalidInput, "mistral: api_key is required")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+
+	dims := defaultDimensions
+	if d, ok := embeddingDimensions[model]; ok {
+		dims = d
+	}
+	if d, ok := config.GetOption[float64](cfg, "dimensions"); ok && d > 0 {
+		dims = int(d)
+	}
+
+	var batchSize int
+	if n, ok := config.GetOption[float64](cfg, "batch_size"); ok && n > 0 {
+		batchSize = int(n)
+	}
+
+	return &Embedder{
+		client:    newClient(cfg),
+		model:     model,
+		dims:      dims,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Embed produces embeddings for a batch of texts. They are sent in one
+// request unless Options["batch_size"] limits the texts per request.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	size := e.batchSize
+	if size == 0 {
+		size = max(len(texts), 1)
+	}
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += size {
+		end := min(start+size, len(texts))
+		vecs, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+// embedBatch embeds texts in one request. The SDK does not take a context,
+// so ctx is only checked before the request is sent.
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, core.Errorf(core.ErrTimeout, "mistral: embed: %w", err)
+	}
+	resp, err := e.client.Embeddings(e.model, texts)
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "mistral: embed failed: %w", err)
+	}
+
+	out := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			continue
+		}
+		vec := make([]float32, len(d.Embedding))
+		for i, x := range d.Embedding {
+			vec[i] = float32(x)
+		}
+		out[d.Index] = vec
+	}
+	for i, vec := range out {
+		if vec == nil {
+			return nil, core.Errorf(core.ErrProviderDown, "mistral: embed returned no embedding for text %d", i)
+		}
+	}
+	return out, nil
+}
+
+// EmbedSingle produces an embedding for a single text.
+func (e *Embedder) EmbedSingle(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// Dimensions returns the dimensionality of the embeddings.
+func (e *Embedder) Dimensions() int {
+	return e.dims
+}
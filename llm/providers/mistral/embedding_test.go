@@ -0,0 +1,137 @@
+package mistral
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/rag/embedding"
+)
+
+// embedServer returns a server that answers embeddings requests with one
+// vector per input, in reverse order, whose only element is the input's
+// index across all requests, and records the request bodies.
+func embedServer(t *testing.T, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+	var n int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("path = %q, want /v1/embeddings", r.URL.Path)
+		}
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+
+		input, _ := req["input"].([]any)
+		data := make([]map[string]any, len(input))
+		for i := range input {
+			data[len(input)-1-i] = map[string]any{
+				"object":    "embedding",
+				"embedding": []float64{float64(n + i)},
+				"index":     i,
+			}
+		}
+		n += len(input)
+		w.Header().Set("Content-Type", "application/json")
+		b, _ := json.Marshal(map[string]any{
+			"id": "emb-123", "object": "list", "model": req["model"], "data": data,
+			"usage": map[string]any{"prompt_tokens": 4, "total_tokens": 4},
+		})
+		w.Write(b)
+	}))
+}
+
+func TestEmbedder_Embed(t *testing.T) {
+	var requests []map[string]any
+	ts := embedServer(t, &requests)
+	defer ts.Close()
+
+	e, err := NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: ts.URL})
+	if err != nil {
+		t.Fatalf("NewEmbedder() error: %v", err)
+	}
+	vecs, err := e.Embed(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if fmt.Sprint(vecs) != "[[0] [1] [2]]" {
+		t.Errorf("Embed() = %v", vecs)
+	}
+	if len(requests) != 1 || requests[0]["model"] != defaultEmbeddingModel {
+		t.Errorf("requests = %v", requests)
+	}
+	if e.Dimensions() != 1024 {
+		t.Errorf("Dimensions() = %d, want 1024", e.Dimensions())
+	}
+
+	vec, err := e.EmbedSingle(context.Background(), "d")
+	if err != nil {
+		t.Fatalf("EmbedSingle() error: %v", err)
+	}
+	if fmt.Sprint(vec) != "[3]" {
+		t.Errorf("EmbedSingle() = %v", vec)
+	}
+}
+
+func TestEmbedder_BatchSize(t *testing.T) {
+	var requests []map[string]any
+	ts := embedServer(t, &requests)
+	defer ts.Close()
+
+	e, _ := NewEmbedder(config.ProviderConfig{
+		APIKey:  "test",
+		BaseURL: ts.URL,
+		Model:   "codestral-embed",
+		Options: map[string]any{"batch_size": float64(2)},
+	})
+	vecs, err := e.Embed(context.Background(), []string{"a", "b", "c", "d", "e"})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("requests = %d, want 3", len(requests))
+	}
+	if fmt.Sprint(vecs) != "[[0] [1] [2] [3] [4]]" {
+		t.Errorf("Embed() = %v", vecs)
+	}
+	if e.Dimensions() != 1536 {
+		t.Errorf("Dimensions() = %d, want 1536", e.Dimensions())
+	}
+}
+
+func TestEmbedder_Errors(t *testing.T) {
+	if _, err := NewEmbedder(config.ProviderConfig{}); err == nil {
+		t.Error("expected error for missing api key")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e, _ := NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: "http://127.0.0.1:0"})
+	if _, err := e.Embed(ctx, []string{"a"}); err == nil {
+		t.Error("expected error for a cancelled context")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "x", "object": "list", "data": [{"object": "embedding", "embedding": [1], "index": 0}]}`)
+	}))
+	defer ts.Close()
+	e, _ = NewEmbedder(config.ProviderConfig{APIKey: "test", BaseURL: ts.URL})
+	if _, err := e.Embed(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("expected error for a missing embedding")
+	}
+}
+
+func TestEmbedder_Registry(t *testing.T) {
+	e, err := embedding.New("mistral-sdk", config.ProviderConfig{APIKey: "test"})
+	if err != nil {
+		t.Fatalf("embedding.New() error: %v", err)
+	}
+	if _, ok := e.(*Embedder); !ok {
+		t.Errorf("embedding.New() = %T, want *Embedder", e)
+	}
+}
@@ -43,6 +43,15 @@ func New(cfg config.ProviderConfig) (*Model, error) {
 	if model == "" {
 		model = defaultModel
 	}
+	return &Model{
+		client: newClient(cfg),
+		model:  model,
+	}, nil
+}
+
+// newClient creates the Mistral SDK client for cfg, shared by the chat model
+// and the embedder.
+func newClient(cfg config.ProviderConfig) *mistral.MistralClient {
 	endpoint := cfg.BaseURL
 	if endpoint == "" {
 		endpoint = defaultEndpoint
@@ -51,16 +60,12 @@ func New(cfg config.ProviderConfig) (*Model, error) {
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
-	client := mistral.NewMistralClient(cfg.APIKey, endpoint, 3, timeout)
-	return &Model{
-		client: client,
-		model:  model,
-	}, nil
+	return mistral.NewMistralClient(cfg.APIKey, endpoint, 3, timeout)
 }
 
 // Generate sends messages and returns a complete AI response.
 func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) (*schema.AIMessage, error) {
-	chatMsgs := convertMessages(msgs)
+	chatMsgs := convertMessages(llm.TrimToContextWindow(ctx, m.model, msgs, llm.ApplyOptions(opts...)))
 	params := m.buildParams(opts)
 
 	resp, err := m.client.Chat(m.model, chatMsgs, params)
@@ -72,7 +77,7 @@ func (m *Model) Generate(ctx context.Context, msgs []schema.Message, opts ...llm
 
 // Stream sends messages and returns an iterator of response chunks.
 func (m *Model) Stream(ctx context.Context, msgs []schema.Message, opts ...llm.GenerateOption) iter.Seq2[schema.StreamChunk, error] {
-	chatMsgs := convertMessages(msgs)
+	chatMsgs := convertMessages(llm.TrimToContextWindow(ctx, m.model, msgs, llm.ApplyOptions(opts...)))
 	params := m.buildParams(opts)
 
 	ch, err := m.client.ChatStream(m.model, chatMsgs, params)
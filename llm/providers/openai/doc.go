@@ -34,6 +34,13 @@
 //   - APIKey: the OpenAI API key
 //   - BaseURL: optional, defaults to "https://api.openai.com/v1"
 //
+// # Batches
+//
+// The returned model implements [llm.BatchGenerator], submitting requests to
+// the Batch API at half the price of Generate:
+//
+//	results, err := model.(llm.BatchGenerator).BatchGenerate(ctx, requests)
+//
 // # Direct Construction
 //
 // Use [New] to create a ChatModel directly without going through the registry.
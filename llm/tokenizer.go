@@ -2,6 +2,7 @@ package llm
 
 import (
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/lookatitude/beluga-ai/v2/schema"
@@ -87,3 +88,38 @@ func (t *SimpleTokenizer) Decode(tokens []int) string {
 	}
 	return strings.Join(parts, " ")
 }
+
+var (
+	tokenizerMu       sync.RWMutex
+	tokenizerRegistry = make(map[string]Tokenizer)
+)
+
+// RegisterTokenizer registers a tokenizer for the models whose name starts
+// with prefix, such as "gpt-4o" or "claude-". It is typically called from
+// a provider's init function. Registering a prefix again replaces its
+// tokenizer.
+func RegisterTokenizer(prefix string, t Tokenizer) {
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+	tokenizerRegistry[prefix] = t
+}
+
+// TokenizerFor returns the tokenizer registered for model under the longest
+// matching prefix, or a SimpleTokenizer when none matches.
+func TokenizerFor(model string) Tokenizer {
+	tokenizerMu.RLock()
+	defer tokenizerMu.RUnlock()
+	var (
+		best    Tokenizer
+		bestLen = -1
+	)
+	for prefix, t := range tokenizerRegistry {
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+			best, bestLen = t, len(prefix)
+		}
+	}
+	if best == nil {
+		return &SimpleTokenizer{}
+	}
+	return best
+}
@@ -144,3 +144,34 @@ func TestSimpleTokenizer_RoundTrip(t *testing.T) {
 		t.Errorf("Decode = %q, want %q", decoded, "x x x")
 	}
 }
+
+// fixedTokenizer counts every text as n tokens.
+type fixedTokenizer struct {
+	SimpleTokenizer
+	n int
+}
+
+func (f *fixedTokenizer) Count(string) int { return f.n }
+
+func TestTokenizerFor(t *testing.T) {
+	gpt := &fixedTokenizer{n: 1}
+	gpt4o := &fixedTokenizer{n: 2}
+	RegisterTokenizer("test-gpt", gpt)
+	RegisterTokenizer("test-gpt-4o", gpt4o)
+
+	tests := []struct {
+		model string
+		want  Tokenizer
+	}{
+		{model: "test-gpt-3.5", want: gpt},
+		{model: "test-gpt-4o-mini", want: gpt4o},
+	}
+	for _, tt := range tests {
+		if got := TokenizerFor(tt.model); got != tt.want {
+			t.Errorf("TokenizerFor(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+	if _, ok := TokenizerFor("unknown-model").(*SimpleTokenizer); !ok {
+		t.Error("TokenizerFor(unknown) should return a SimpleTokenizer")
+	}
+}
@@ -35,7 +35,9 @@
 //	o11y.Cost(ctx, estimatedUSD)
 //
 // [InitMeter] configures the package-level meter with a service name.
-// Generic [Counter] and [Histogram] functions allow recording custom metrics.
+// Generic [Counter] and [Histogram] functions allow recording custom metrics;
+// their Attrs variants, and [UpDownCounterWithAttrs] for gauges of concurrent
+// work, tag the values with attributes.
 //
 // # Logging
 //
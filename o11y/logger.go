@@ -21,6 +21,7 @@ type LogOption func(*loggerConfig)
 type loggerConfig struct {
 	level   slog.Level
 	handler slog.Handler
+	custom  slog.Handler
 }
 
 // WithLogLevel sets the minimum log level. Accepted values: "debug", "info",
@@ -49,6 +50,15 @@ func WithJSON() LogOption {
 	}
 }
 
+// WithLogHandler sends log records to h, for example a handler writing to a
+// file or forwarding to another logging system. It takes precedence over
+// WithJSON, and h applies its own level filtering.
+func WithLogHandler(h slog.Handler) LogOption {
+	return func(cfg *loggerConfig) {
+		cfg.custom = h
+	}
+}
+
 // NewLogger creates a Logger with the given options. Without options it
 // defaults to info-level text output on stdout.
 func NewLogger(opts ...LogOption) *Logger {
@@ -58,6 +68,9 @@ func NewLogger(opts ...LogOption) *Logger {
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.custom != nil {
+		cfg.handler = cfg.custom
+	}
 	if cfg.handler == nil {
 		cfg.handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 			Level: cfg.level,
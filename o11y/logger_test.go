@@ -1,7 +1,10 @@
 package o11y
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -52,6 +55,17 @@ func TestNewLogger(t *testing.T) {
 	})
 }
 
+func TestWithLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := NewLogger(WithJSON(), WithLogHandler(h))
+
+	logger.Debug(context.Background(), "custom handler", "key", "value")
+	if out := buf.String(); !strings.Contains(out, `"msg":"custom handler"`) || !strings.Contains(out, `"key":"value"`) {
+		t.Errorf("output = %q", out)
+	}
+}
+
 func TestLoggerMethods(t *testing.T) {
 	// Verify the log methods do not panic. We cannot easily capture slog
 	// output in tests without a custom handler, but we can ensure no panics.
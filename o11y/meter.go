@@ -131,6 +131,16 @@ func Counter(ctx context.Context, name string, value int64) {
 	c.Add(ctx, value)
 }
 
+// CounterWithAttrs records an increment to a named counter metric, tagged
+// with attrs.
+func CounterWithAttrs(ctx context.Context, name string, value int64, attrs Attrs) {
+	c, err := meter.Int64Counter(name)
+	if err != nil {
+		return
+	}
+	c.Add(ctx, value, metric.WithAttributes(attrsToOTel(attrs)...))
+}
+
 // Histogram records a value to a named histogram metric.
 func Histogram(ctx context.Context, name string, value float64) {
 	h, err := meter.Float64Histogram(name)
@@ -139,3 +149,24 @@ func Histogram(ctx context.Context, name string, value float64) {
 	}
 	h.Record(ctx, value)
 }
+
+// HistogramWithAttrs records a value to a named histogram metric, tagged
+// with attrs.
+func HistogramWithAttrs(ctx context.Context, name string, value float64, attrs Attrs) {
+	h, err := meter.Float64Histogram(name)
+	if err != nil {
+		return
+	}
+	h.Record(ctx, value, metric.WithAttributes(attrsToOTel(attrs)...))
+}
+
+// UpDownCounterWithAttrs adds value, which may be negative, to a named
+// up-down counter metric tagged with attrs. It suits gauges of concurrent
+// work such as in-flight requests.
+func UpDownCounterWithAttrs(ctx context.Context, name string, value int64, attrs Attrs) {
+	c, err := meter.Int64UpDownCounter(name)
+	if err != nil {
+		return
+	}
+	c.Add(ctx, value, metric.WithAttributes(attrsToOTel(attrs)...))
+}
@@ -151,6 +151,71 @@ func TestCounter_WithInMemoryReader(t *testing.T) {
 	assert.NotEmpty(t, rm.ScopeMetrics)
 }
 
+func TestCounterWithAttrs_WithInMemoryReader(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter = provider.Meter("github.com/lookatitude/beluga-ai/v2/o11y")
+
+	ctx := context.Background()
+	CounterWithAttrs(ctx, "custom.counter.attrs", 3, Attrs{"dependency": "openai"})
+
+	rm := metricdata.ResourceMetrics{}
+	err := reader.Collect(ctx, &rm)
+	require.NoError(t, err)
+	require.NotEmpty(t, rm.ScopeMetrics)
+	require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	v, ok := sum.DataPoints[0].Attributes.Value("dependency")
+	require.True(t, ok)
+	assert.Equal(t, "openai", v.AsString())
+	assert.Equal(t, int64(3), sum.DataPoints[0].Value)
+}
+
+func TestHistogramWithAttrs_WithInMemoryReader(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter = provider.Meter("github.com/lookatitude/beluga-ai/v2/o11y")
+
+	ctx := context.Background()
+	HistogramWithAttrs(ctx, "custom.histogram.attrs", 2.5, Attrs{"host": "api.example.com"})
+
+	rm := metricdata.ResourceMetrics{}
+	err := reader.Collect(ctx, &rm)
+	require.NoError(t, err)
+	require.NotEmpty(t, rm.ScopeMetrics)
+	require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	v, ok := hist.DataPoints[0].Attributes.Value("host")
+	require.True(t, ok)
+	assert.Equal(t, "api.example.com", v.AsString())
+	assert.Equal(t, 2.5, hist.DataPoints[0].Sum)
+}
+
+func TestUpDownCounterWithAttrs_WithInMemoryReader(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter = provider.Meter("github.com/lookatitude/beluga-ai/v2/o11y")
+
+	ctx := context.Background()
+	UpDownCounterWithAttrs(ctx, "custom.updown.attrs", 3, Attrs{"host": "a"})
+	UpDownCounterWithAttrs(ctx, "custom.updown.attrs", -2, Attrs{"host": "a"})
+
+	rm := metricdata.ResourceMetrics{}
+	err := reader.Collect(ctx, &rm)
+	require.NoError(t, err)
+	require.NotEmpty(t, rm.ScopeMetrics)
+	require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.False(t, sum.IsMonotonic)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+}
+
 func TestHistogram_WithInMemoryReader(t *testing.T) {
 	reader := sdkmetric.NewManualReader()
 	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
@@ -1,6 +1,9 @@
 package prompt
 
 import (
+	"fmt"
+
+	"github.com/lookatitude/beluga-ai/v2/llm"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
@@ -16,7 +19,8 @@ import (
 //  6. User input (always changes)
 //
 // Use NewBuilder with functional options to configure each slot, then call
-// Build to produce the ordered message list.
+// Build to produce the ordered message list. With WithTokenBudget, Build
+// also trims the dynamic context to fit the model's context window.
 type Builder struct {
 	systemPrompt    string
 	toolDefs        []schema.ToolDefinition
@@ -24,6 +28,8 @@ type Builder struct {
 	cacheBreakpoint bool
 	dynamicContext  []schema.Message
 	userInput       schema.Message
+	maxTokens       int
+	tokenizer       llm.Tokenizer
 }
 
 // BuilderOption configures a Builder slot.
@@ -87,10 +93,25 @@ func WithUserInput(msg schema.Message) BuilderOption {
 	}
 }
 
+// WithTokenBudget limits the built prompt to maxTokens tokens, as estimated by
+// the tokenizer registered for model (see llm.TokenizerFor). Build drops
+// dynamic context messages, oldest first, until the prompt fits; the system
+// prompt, tool definitions, static context and user input are never
+// trimmed. A maxTokens of zero or less disables the budget.
+func WithTokenBudget(maxTokens int, model string) BuilderOption {
+	return func(b *Builder) {
+		b.maxTokens = maxTokens
+		b.tokenizer = llm.TokenizerFor(model)
+	}
+}
+
 // Build produces the ordered message list. Messages are arranged in
 // cache-optimal order: system prompt → tool definitions → static context →
 // cache breakpoint → dynamic context → user input. Nil/empty slots are skipped.
-func (b *Builder) Build() []schema.Message {
+// When a token budget is set, the oldest dynamic context messages are
+// dropped to fit it, and Build returns an error if the other slots alone
+// exceed it.
+func (b *Builder) Build() ([]schema.Message, error) {
 	var msgs []schema.Message
 
 	// Slot 1: System prompt
@@ -121,14 +142,50 @@ func (b *Builder) Build() []schema.Message {
 	}
 
 	// Slot 5: Dynamic context messages
-	msgs = append(msgs, b.dynamicContext...)
+	dynamic := b.dynamicContext
+	if b.maxTokens > 0 {
+		var err error
+		if dynamic, err = b.fitDynamic(msgs); err != nil {
+			return nil, err
+		}
+	}
+	msgs = append(msgs, dynamic...)
 
 	// Slot 6: User input
 	if b.userInput != nil {
 		msgs = append(msgs, b.userInput)
 	}
 
-	return msgs
+	return msgs, nil
+}
+
+// fitDynamic returns the newest dynamic context messages that fit in the
+// token budget alongside the protected messages of slots 1–4 and the user
+// input.
+func (b *Builder) fitDynamic(protected []schema.Message) ([]schema.Message, error) {
+	if b.userInput != nil {
+		protected = append(protected[:len(protected):len(protected)], b.userInput)
+	}
+	used := b.tokenizer.CountMessages(protected)
+	if used > b.maxTokens {
+		return nil, fmt.Errorf("prompt: system prompt, tools, static context and user input need %d tokens, over the budget of %d", used, b.maxTokens)
+	}
+
+	remaining := b.maxTokens - used
+	start := len(b.dynamicContext)
+	for start > 0 {
+		n := b.tokenizer.CountMessages(b.dynamicContext[start-1 : start])
+		if n > remaining {
+			break
+		}
+		remaining -= n
+		start--
+	}
+	// Tool results whose tool call was dropped cannot be sent alone.
+	for start < len(b.dynamicContext) && b.dynamicContext[start].GetRole() == schema.RoleTool {
+		start++
+	}
+	return b.dynamicContext[start:], nil
 }
 
 // formatToolDefinitions renders tool definitions as a text description.
@@ -21,7 +21,7 @@ func TestBuilder_Build_FullOrder(t *testing.T) {
 		WithUserInput(schema.NewHumanMessage("current question")),
 	)
 
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 
 	// Expected order:
 	// 0: system prompt
@@ -86,7 +86,7 @@ func TestBuilder_Build_FullOrder(t *testing.T) {
 
 func TestBuilder_Build_EmptyBuilder(t *testing.T) {
 	b := NewBuilder()
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 0 {
 		t.Errorf("expected 0 messages for empty builder, got %d", len(msgs))
 	}
@@ -94,7 +94,7 @@ func TestBuilder_Build_EmptyBuilder(t *testing.T) {
 
 func TestBuilder_Build_SystemPromptOnly(t *testing.T) {
 	b := NewBuilder(WithSystemPrompt("system only"))
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(msgs))
 	}
@@ -106,7 +106,7 @@ func TestBuilder_Build_SystemPromptOnly(t *testing.T) {
 
 func TestBuilder_Build_UserInputOnly(t *testing.T) {
 	b := NewBuilder(WithUserInput(schema.NewHumanMessage("just user")))
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(msgs))
 	}
@@ -121,7 +121,7 @@ func TestBuilder_Build_ToolDefinitions(t *testing.T) {
 		{Name: "calculate", Description: "Do math"},
 	}
 	b := NewBuilder(WithToolDefinitions(tools))
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(msgs))
 	}
@@ -132,7 +132,7 @@ func TestBuilder_Build_ToolDefinitions(t *testing.T) {
 
 func TestBuilder_Build_StaticContextSkipsEmpty(t *testing.T) {
 	b := NewBuilder(WithStaticContext([]string{"doc1", "", "doc2"}))
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	// Empty strings should be skipped.
 	if len(msgs) != 2 {
 		t.Fatalf("expected 2 messages (skipping empty), got %d", len(msgs))
@@ -141,7 +141,7 @@ func TestBuilder_Build_StaticContextSkipsEmpty(t *testing.T) {
 
 func TestBuilder_Build_CacheBreakpointOnly(t *testing.T) {
 	b := NewBuilder(WithCacheBreakpoint())
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(msgs))
 	}
@@ -158,7 +158,7 @@ func TestBuilder_Build_DynamicContextOnly(t *testing.T) {
 		schema.NewHumanMessage("q2"),
 	}
 	b := NewBuilder(WithDynamicContext(dynamic))
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 3 {
 		t.Fatalf("expected 3 messages, got %d", len(msgs))
 	}
@@ -175,7 +175,7 @@ func TestBuilder_Build_StaticBeforeDynamic(t *testing.T) {
 		WithStaticContext([]string{"static doc"}),
 		WithDynamicContext([]schema.Message{schema.NewHumanMessage("dynamic msg")}),
 	)
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 2 {
 		t.Fatalf("expected 2 messages, got %d", len(msgs))
 	}
@@ -193,7 +193,7 @@ func TestBuilder_Build_Ordering_SystemBeforeTools(t *testing.T) {
 		WithToolDefinitions([]schema.ToolDefinition{{Name: "t1"}}),
 		WithSystemPrompt("sys"),
 	)
-	msgs := b.Build()
+	msgs := mustBuild(t, b)
 	if len(msgs) != 2 {
 		t.Fatalf("expected 2 messages, got %d", len(msgs))
 	}
@@ -223,3 +223,97 @@ func contains(s, sub string) bool {
 	}
 	return false
 }
+
+// mustBuild builds b and fails the test on error.
+func mustBuild(t *testing.T, b *Builder) []schema.Message {
+	t.Helper()
+	msgs, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return msgs
+}
+
+func TestBuilder_Build_TokenBudget(t *testing.T) {
+	// SimpleTokenizer: 1 token per 4 characters plus 4 per message.
+	history := []schema.Message{
+		schema.NewHumanMessage("oldest question."), // 4 + 4
+		schema.NewAIMessage("oldest answer..."),    // 4 + 4
+		schema.NewHumanMessage("newer question.."), // 4 + 4
+		schema.NewAIMessage("newest answer..."),    // 4 + 4
+	}
+	opts := []BuilderOption{
+		WithSystemPrompt("You are an assistant"), // 5 + 4
+		WithDynamicContext(history),
+		WithUserInput(schema.NewHumanMessage("current question")), // 4 + 4
+	}
+
+	tests := []struct {
+		name   string
+		budget int
+		want   int // dynamic context messages kept
+	}{
+		{name: "fits", budget: 100, want: 4},
+		{name: "exact fit", budget: 17 + 32, want: 4},
+		{name: "drops oldest", budget: 17 + 31, want: 3},
+		{name: "keeps newest", budget: 17 + 8, want: 1},
+		{name: "drops all", budget: 17, want: 0},
+		{name: "disabled", budget: 0, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder(append(opts, WithTokenBudget(tt.budget, "unregistered-model"))...)
+			msgs := mustBuild(t, b)
+			if len(msgs) != 2+tt.want {
+				t.Fatalf("expected %d messages, got %d", 2+tt.want, len(msgs))
+			}
+			assertTextContains(t, msgs[0], "assistant")
+			assertTextContains(t, msgs[len(msgs)-1], "current question")
+			for i, msg := range msgs[1 : len(msgs)-1] {
+				if msg != history[len(history)-tt.want+i] {
+					t.Errorf("dynamic message %d is not the newest kept", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_Build_TokenBudgetDropsOrphanToolResults(t *testing.T) {
+	call := &schema.AIMessage{
+		Parts:     []schema.ContentPart{schema.TextPart{Text: "calling the search tool now"}},
+		ToolCalls: []schema.ToolCall{{ID: "1", Name: "search"}},
+	}
+	b := NewBuilder(
+		WithDynamicContext([]schema.Message{
+			call,
+			schema.NewToolMessage("1", "result"),
+			schema.NewAIMessage("done"),
+		}),
+		WithUserInput(schema.NewHumanMessage("next")),
+		// Fits the tool result but not its call.
+		WithTokenBudget(5+6+5, ""),
+	)
+	msgs := mustBuild(t, b)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[0].GetRole() != schema.RoleAI {
+		t.Errorf("first message role = %s, want ai", msgs[0].GetRole())
+	}
+}
+
+func TestBuilder_Build_TokenBudgetExceeded(t *testing.T) {
+	b := NewBuilder(
+		WithSystemPrompt("You are an assistant with a long system prompt."),
+		WithStaticContext([]string{"Some reference material."}),
+		WithUserInput(schema.NewHumanMessage("question")),
+		WithTokenBudget(10, ""),
+	)
+	msgs, err := b.Build()
+	if err == nil {
+		t.Fatalf("expected error, got %d messages", len(msgs))
+	}
+	if !contains(err.Error(), "over the budget of 10") {
+		t.Errorf("error = %v", err)
+	}
+}
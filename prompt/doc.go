@@ -10,6 +10,32 @@
 // Use Validate to check that the template content is parseable, and Render
 // to execute the template with provided variables.
 //
+// Other template syntaxes are supported through the Engine interface:
+// engines register under a syntax name with RegisterEngine, and a
+// template's Syntax field selects one. Importing prompt/engines/jinja2
+// registers the "jinja2" syntax, for prompts migrated from Python.
+//
+// RequiredVars lists the variables a template references, and MissingVars
+// those that a set of variables and the defaults leave unset, so inputs can
+// be validated before rendering. RenderWithPlaceholders renders missing
+// variables as "{{.name}}" placeholders and reports which were absent.
+// PartialRender pre-fills some variables and returns a new Template awaiting
+// the rest, for prompts assembled in several stages:
+//
+//	base, err := tmpl.PartialRender(map[string]any{"persona": "a tutor"})
+//	// later, per request:
+//	result, err := base.Render(map[string]any{"question": q})
+//
+// # Fragments and Inheritance
+//
+// RenderWithFragments renders templates that reuse other templates of a
+// PromptManager (any FragmentResolver). The include function renders a
+// shared fragment, such as {{include "safety_rules"}}, and a template whose
+// Extends field names a base template fills in the base's {{block}}
+// sections with its own {{define}} definitions. Circular references are
+// detected. PromptManager implementations resolve fragments from their own
+// templates when rendering.
+//
 // # PromptManager Interface
 //
 // The PromptManager interface provides versioned access to prompt templates:
@@ -20,6 +46,15 @@
 //
 // Implementations include the filesystem-based provider in prompt/providers/file.
 //
+// # A/B Testing
+//
+// WithVersionSelector wraps a PromptManager so that Render serves the
+// template version chosen by a VersionSelector. WeightedVersionSelector
+// splits traffic between versions by weight, assigning each key (such as a
+// user ID taken from the render variables) a stable version by hash. The
+// chosen version is recorded in the rendered message's metadata under
+// MetadataTemplateVersion for analysis.
+//
 // # Builder
 //
 // Builder constructs a prompt message sequence in cache-optimal order. LLM
@@ -33,6 +68,10 @@
 //  5. Dynamic context messages (per-session)
 //  6. User input (always changes)
 //
+// WithTokenBudget keeps the prompt within a model's context window: Build
+// drops the oldest dynamic context messages until the prompt fits, and
+// fails if the other slots alone exceed the budget.
+//
 // # Usage
 //
 // Template rendering:
@@ -50,11 +89,12 @@
 //
 // Cache-optimized prompt building:
 //
-//	msgs := prompt.NewBuilder(
+//	msgs, err := prompt.NewBuilder(
 //	    prompt.WithSystemPrompt("You are a helpful assistant."),
 //	    prompt.WithStaticContext([]string{"Reference: ..."}),
 //	    prompt.WithCacheBreakpoint(),
 //	    prompt.WithDynamicContext(history),
 //	    prompt.WithUserInput(schema.NewHumanMessage("Hello")),
+//	    prompt.WithTokenBudget(8192, "gpt-4o"),
 //	).Build()
 package prompt
@@ -0,0 +1,90 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SyntaxGoTemplate is the syntax of Go's text/template, the default
+// template syntax.
+const SyntaxGoTemplate = "go"
+
+// Engine parses and renders template content written in one syntax.
+// Engines are registered by syntax name with RegisterEngine, typically from
+// an init function, and selected by Template.Syntax.
+type Engine interface {
+	// Validate checks that content parses.
+	Validate(name, content string) error
+
+	// Render executes content with vars and returns the output.
+	Render(name, content string, vars map[string]any) (string, error)
+}
+
+var (
+	engineMu sync.RWMutex
+	engines  = map[string]Engine{SyntaxGoTemplate: goEngine{}}
+)
+
+// RegisterEngine registers the engine for a template syntax. It is intended
+// to be called from engine init() functions. Duplicate registrations for the
+// same syntax silently overwrite the previous engine.
+func RegisterEngine(syntax string, e Engine) {
+	engineMu.Lock()
+	defer engineMu.Unlock()
+	engines[syntax] = e
+}
+
+// ListEngines returns the names of all registered template syntaxes, sorted
+// alphabetically.
+func ListEngines() []string {
+	engineMu.RLock()
+	defer engineMu.RUnlock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// engine returns the engine for the template's syntax.
+func (t *Template) engine() (Engine, error) {
+	syntax := t.Syntax
+	if syntax == "" {
+		syntax = SyntaxGoTemplate
+	}
+	engineMu.RLock()
+	e, ok := engines[syntax]
+	engineMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("prompt: template %q has unknown syntax %q (registered: %v)", t.Name, syntax, ListEngines())
+	}
+	return e, nil
+}
+
+// isGoTemplate reports whether the template uses Go text/template syntax.
+func (t *Template) isGoTemplate() bool {
+	return t.Syntax == "" || t.Syntax == SyntaxGoTemplate
+}
+
+// goEngine is the Engine for Go text/template syntax.
+type goEngine struct{}
+
+func (goEngine) Validate(name, content string) error {
+	_, err := newGoTemplate(name).Parse(content)
+	return err
+}
+
+func (goEngine) Render(name, content string, vars map[string]any) (string, error) {
+	tmpl, err := newGoTemplate(name).Parse(content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,69 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// upperEngine is an Engine that renders content upper-cased, ignoring vars.
+type upperEngine struct{}
+
+func (upperEngine) Validate(_, content string) error {
+	if strings.Contains(content, "!") {
+		return errors.New("bang")
+	}
+	return nil
+}
+
+func (upperEngine) Render(_, content string, _ map[string]any) (string, error) {
+	return strings.ToUpper(content), nil
+}
+
+func TestRegisterEngine(t *testing.T) {
+	RegisterEngine("test-upper", upperEngine{})
+
+	names := ListEngines()
+	if !strings.Contains(strings.Join(names, ","), "test-upper") || !strings.Contains(strings.Join(names, ","), SyntaxGoTemplate) {
+		t.Errorf("ListEngines() = %v", names)
+	}
+
+	tmpl := Template{Name: "t", Syntax: "test-upper", Content: "hello {{.name}}"}
+	got, err := tmpl.Render(map[string]any{"name": "x"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got != "HELLO {{.NAME}}" {
+		t.Errorf("Render() = %q", got)
+	}
+
+	tmpl.Content = "hello!"
+	if err := tmpl.Validate(); err == nil || !strings.Contains(err.Error(), "parse error: bang") {
+		t.Errorf("Validate error = %v", err)
+	}
+
+	if _, err := tmpl.PartialRender(nil); err == nil {
+		t.Error("expected PartialRender error for non-Go syntax")
+	}
+	if vars := (&Template{Name: "t", Syntax: "test-upper", Content: "{{.a}}"}).RequiredVars(); vars != nil {
+		t.Errorf("RequiredVars() = %v, want nil", vars)
+	}
+}
+
+func TestTemplate_UnknownSyntax(t *testing.T) {
+	tmpl := Template{Name: "t", Syntax: "mustache", Content: "Hello {{name}}"}
+	if err := tmpl.Validate(); err == nil || !strings.Contains(err.Error(), `unknown syntax "mustache"`) {
+		t.Errorf("Validate error = %v", err)
+	}
+	if _, err := tmpl.Render(nil); err == nil {
+		t.Error("expected Render error")
+	}
+}
+
+func TestTemplate_ExplicitGoSyntax(t *testing.T) {
+	tmpl := Template{Name: "t", Syntax: SyntaxGoTemplate, Content: "Hello, {{.name}}!"}
+	got, err := tmpl.Render(map[string]any{"name": "Alice"})
+	if err != nil || got != "Hello, Alice!" {
+		t.Errorf("Render() = %q, %v", got, err)
+	}
+}
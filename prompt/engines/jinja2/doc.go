@@ -0,0 +1,38 @@
+// Package jinja2 provides a Jinja2 template engine for prompt templates,
+// easing the migration of prompt libraries from Python frameworks such as
+// LangChain. It is backed by gonja, a Go implementation of Jinja2, and
+// registers itself under the "jinja2" syntax on import.
+//
+// # Usage
+//
+//	import _ "github.com/lookatitude/beluga-ai/v2/prompt/engines/jinja2"
+//
+//	tmpl := &prompt.Template{
+//	    Name:    "summary",
+//	    Syntax:  "jinja2",
+//	    Content: "Summarize for {{ audience | default('everyone') }}:\n" +
+//	        "{% for doc in docs %}- {{ doc.title }}\n{% endfor %}",
+//	}
+//	result, err := tmpl.Render(map[string]any{"docs": docs})
+//
+// Templates in files loaded by the file provider select the engine with the
+// "syntax" field:
+//
+//	{
+//	    "name": "greeting",
+//	    "version": "1.0.0",
+//	    "syntax": "jinja2",
+//	    "content": "Hello, {{ name }}!"
+//	}
+//
+// Validate and Render behave as for Go templates. Template.RequiredVars and
+// Template.PartialRender support only Go template syntax.
+//
+// # Sandboxing
+//
+// Templates are rendered without filesystem access: include, import and
+// extends tags fail. Register an engine created with New and options under
+// Syntax to change the rendering configuration:
+//
+//	prompt.RegisterEngine(jinja2.Syntax, jinja2.New(jinja2.WithStrictUndefined()))
+package jinja2
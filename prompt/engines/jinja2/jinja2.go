@@ -0,0 +1,86 @@
+package jinja2
+
+import (
+	"github.com/lookatitude/beluga-ai/v2/prompt"
+	"github.com/nikolalohinski/gonja/v2"
+	"github.com/nikolalohinski/gonja/v2/config"
+	"github.com/nikolalohinski/gonja/v2/exec"
+	"github.com/nikolalohinski/gonja/v2/loaders"
+)
+
+// Syntax is the template syntax name under which the engine is registered.
+const Syntax = "jinja2"
+
+func init() {
+	prompt.RegisterEngine(Syntax, New())
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithStrictUndefined makes rendering fail when the template references an
+// undefined variable or attribute, like Jinja2's StrictUndefined. By default
+// undefined values render as empty.
+func WithStrictUndefined() Option {
+	return func(e *Engine) {
+		e.cfg.StrictUndefined = true
+	}
+}
+
+// WithTrimBlocks removes the first newline after a block tag, like Jinja2's
+// trim_blocks and lstrip_blocks together: leading whitespace before a block
+// tag on its line is stripped as well.
+func WithTrimBlocks() Option {
+	return func(e *Engine) {
+		e.cfg.TrimBlocks = true
+		e.cfg.LeftStripBlocks = true
+	}
+}
+
+// Engine is a prompt.Engine for Jinja2 template syntax. Templates cannot
+// include, import or extend other templates, so rendering never reads
+// files.
+type Engine struct {
+	cfg *config.Config
+}
+
+// Compile-time interface check.
+var _ prompt.Engine = (*Engine)(nil)
+
+// New creates a Jinja2 Engine with the given options. The registered engine
+// uses the defaults; register one created with options under Syntax to
+// change them for all templates.
+func New(opts ...Option) *Engine {
+	e := &Engine{cfg: config.New()}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Validate checks that content parses as a Jinja2 template.
+func (e *Engine) Validate(name, content string) error {
+	_, err := e.parse(name, content)
+	return err
+}
+
+// Render executes content with vars and returns the output.
+func (e *Engine) Render(name, content string, vars map[string]any) (string, error) {
+	tmpl, err := e.parse(name, content)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.ExecuteToString(exec.NewContext(vars))
+}
+
+// parse parses content from a loader holding only content, so that include,
+// import and extends tags fail instead of reading the filesystem.
+func (e *Engine) parse(name, content string) (*exec.Template, error) {
+	// Memory loader keys are absolute paths.
+	key := "/" + name
+	loader, err := loaders.NewMemoryLoader(map[string]string{key: content})
+	if err != nil {
+		return nil, err
+	}
+	return exec.NewTemplate(key, e.cfg, loader, gonja.DefaultEnvironment)
+}
@@ -0,0 +1,131 @@
+package jinja2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/prompt"
+)
+
+func TestRegistered(t *testing.T) {
+	found := false
+	for _, name := range prompt.ListEngines() {
+		if name == Syntax {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ListEngines() = %v, want %q registered", prompt.ListEngines(), Syntax)
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    prompt.Template
+		vars    map[string]any
+		want    string
+		wantErr string
+	}{
+		{
+			name: "variables and defaults",
+			tmpl: prompt.Template{
+				Name:      "greeting",
+				Content:   "Hello, {{ name }}! Welcome to {{ system }}.",
+				Variables: map[string]string{"system": "Beluga"},
+			},
+			vars: map[string]any{"name": "Alice"},
+			want: "Hello, Alice! Welcome to Beluga.",
+		},
+		{
+			name: "loops, filters and conditionals",
+			tmpl: prompt.Template{
+				Name:    "list",
+				Content: "{% for doc in docs %}{{ loop.index }}. {{ doc.title | upper }}{% if not loop.last %}, {% endif %}{% endfor %}",
+			},
+			vars: map[string]any{"docs": []map[string]any{{"title": "a"}, {"title": "b"}}},
+			want: "1. A, 2. B",
+		},
+		{
+			name: "default filter for missing variable",
+			tmpl: prompt.Template{
+				Name:    "default",
+				Content: "For {{ audience | default('everyone') }}",
+			},
+			want: "For everyone",
+		},
+		{
+			name: "parse error",
+			tmpl: prompt.Template{
+				Name:    "bad",
+				Content: "{% for x in items %}unclosed",
+			},
+			wantErr: "parse error",
+		},
+		{
+			name: "no filesystem access",
+			tmpl: prompt.Template{
+				Name:    "include",
+				Content: "{% include '/etc/hostname' %}",
+			},
+			wantErr: "prompt: template \"include\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tmpl.Syntax = Syntax
+			got, err := tt.tmpl.Render(tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	valid := prompt.Template{Name: "ok", Syntax: Syntax, Content: "{% if x %}{{ x }}{% endif %}"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	invalid := prompt.Template{Name: "bad", Syntax: Syntax, Content: "{{ x "}
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("Validate error = %v, want parse error", err)
+	}
+	// Go template syntax is not valid Jinja2.
+	goSyntax := prompt.Template{Name: "go", Syntax: Syntax, Content: "{{.name}}"}
+	if err := goSyntax.Validate(); err == nil {
+		t.Error("expected error for Go template syntax")
+	}
+}
+
+func TestEngine_StrictUndefined(t *testing.T) {
+	content := "Hello, {{ name }}!"
+	out, err := New().Render("t", content, nil)
+	if err != nil || out != "Hello, !" {
+		t.Errorf("lenient Render() = %q, %v", out, err)
+	}
+	if _, err := New(WithStrictUndefined()).Render("t", content, nil); err == nil {
+		t.Error("expected error for undefined variable")
+	}
+}
+
+func TestEngine_TrimBlocks(t *testing.T) {
+	content := "{% for x in items %}\n  {{ x }}\n  {% endfor %}\n"
+	out, err := New(WithTrimBlocks()).Render("t", content, map[string]any{"items": []int{1, 2}})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if out != "  1\n  2\n" {
+		t.Errorf("Render() = %q", out)
+	}
+}
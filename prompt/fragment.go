@@ -0,0 +1,168 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// FragmentResolver looks up the templates that a template includes or
+// extends. Every PromptManager is a FragmentResolver.
+type FragmentResolver interface {
+	// Get retrieves a template by name and version. If version is empty,
+	// the latest version is returned.
+	Get(name, version string) (*Template, error)
+}
+
+// RenderWithFragments renders the template like Render, resolving through
+// fragments the templates it includes and extends.
+//
+// A template includes a fragment, any template known to fragments, with the
+// include function: {{include "safety_rules"}} renders the fragment with the
+// template's variables, and {{include "citation" .source}} with the given
+// map. A template whose Extends names a base template renders as the base,
+// with the blocks the base declares with {{block "name" .}} replaced by the
+// template's own {{define "name"}} definitions; blocks it does not define
+// keep the base's content. Defaults of the base apply unless the template
+// overrides them. References take the form "name" for the latest version or
+// "name@version".
+//
+// Circular includes and extends are reported as errors. Fragments and
+// extends are supported for Go template syntax only.
+func (t *Template) RenderWithFragments(vars map[string]any, fragments FragmentResolver) (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+	return (&composer{fragments: fragments}).render(t, vars, nil)
+}
+
+// composer renders templates with fragments resolved.
+type composer struct {
+	fragments FragmentResolver
+}
+
+// render renders t with vars; stack holds the templates being rendered by
+// the callers, to detect circular references.
+func (c *composer) render(t *Template, vars map[string]any, stack []string) (string, error) {
+	if !t.isGoTemplate() {
+		if t.Extends != "" {
+			return "", fmt.Errorf("prompt: template %q: extends is not supported for %s syntax", t.Name, t.Syntax)
+		}
+		return t.Render(vars)
+	}
+
+	chain, stack, err := c.chain(t, stack)
+	if err != nil {
+		return "", err
+	}
+
+	// Defaults of descendants override those of their bases; provided vars
+	// override all.
+	merged := make(map[string]any)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].Variables {
+			merged[k] = v
+		}
+	}
+	maps.Copy(merged, vars)
+
+	tmpl := template.New(t.Name).Funcs(template.FuncMap{
+		"include": func(ref string, data ...any) (string, error) {
+			return c.include(ref, data, merged, stack)
+		},
+	})
+	// Parse the base first: the definitions of each descendant replace the
+	// blocks of its base.
+	for i := len(chain) - 1; i >= 0; i-- {
+		if _, err := tmpl.Parse(chain[i].Content); err != nil {
+			return "", fmt.Errorf("prompt: template %q parse error: %w", chain[i].Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, merged); err != nil {
+		return "", fmt.Errorf("prompt: template %q execute error: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// chain returns t followed by the templates it extends, nearest first, and
+// stack extended with their names.
+func (c *composer) chain(t *Template, stack []string) ([]*Template, []string, error) {
+	stack = slices.Clip(stack)
+	var chain []*Template
+	for {
+		if slices.Contains(stack, t.Name) {
+			return nil, nil, circularError(append(stack, t.Name))
+		}
+		stack = append(stack, t.Name)
+		chain = append(chain, t)
+		if t.Extends == "" {
+			return chain, stack, nil
+		}
+
+		base, err := c.resolve(t.Name, t.Extends)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !base.isGoTemplate() {
+			return nil, nil, fmt.Errorf("prompt: template %q extends %q of %s syntax", t.Name, t.Extends, base.Syntax)
+		}
+		t = base
+	}
+}
+
+// include renders the fragment ref for the include function; vars are the
+// including template's variables.
+func (c *composer) include(ref string, data []any, vars map[string]any, stack []string) (string, error) {
+	if len(data) > 1 {
+		return "", fmt.Errorf("prompt: include %q: expected at most one data argument, got %d", ref, len(data))
+	}
+	if len(data) == 1 {
+		m, ok := data[0].(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("prompt: include %q: data must be a map[string]any, got %T", ref, data[0])
+		}
+		vars = m
+	}
+	frag, err := c.resolve(stack[len(stack)-1], ref)
+	if err != nil {
+		return "", err
+	}
+	if err := frag.Validate(); err != nil {
+		return "", err
+	}
+	return c.render(frag, vars, stack)
+}
+
+// resolve looks up the template referenced by from.
+func (c *composer) resolve(from, ref string) (*Template, error) {
+	if c.fragments == nil {
+		return nil, fmt.Errorf("prompt: template %q references %q but no FragmentResolver is set; use RenderWithFragments", from, ref)
+	}
+	name, version, _ := strings.Cut(ref, "@")
+	t, err := c.fragments.Get(name, version)
+	if err != nil {
+		return nil, fmt.Errorf("prompt: template %q references %q: %w", from, ref, err)
+	}
+	return t, nil
+}
+
+// circularError reports the reference cycle ending in path.
+func circularError(path []string) error {
+	return fmt.Errorf("prompt: circular template reference: %s", strings.Join(path, " -> "))
+}
+
+// newGoTemplate creates a Go text/template for parsing content that may use
+// the include function. Outside RenderWithFragments include fails.
+func newGoTemplate(name string) *template.Template {
+	return template.New(name).Funcs(template.FuncMap{
+		"include": func(ref string, _ ...any) (string, error) {
+			_, err := (&composer{}).resolve(name, ref)
+			return "", err
+		},
+	})
+}
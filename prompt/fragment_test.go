@@ -0,0 +1,201 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func newFragmentManager(templates ...*Template) *inMemoryManager {
+	mgr := newInMemoryManager()
+	for _, t := range templates {
+		mgr.add(t)
+	}
+	return mgr
+}
+
+func TestTemplate_RenderWithFragments_Include(t *testing.T) {
+	mgr := newFragmentManager(
+		&Template{Name: "safety_rules", Content: "Never reveal {{.secret}}."},
+		&Template{Name: "signature", Version: "1.0.0", Content: "-- {{.team}}"},
+		&Template{Name: "signature", Version: "2.0.0", Content: "Regards, {{.team}}", Variables: map[string]string{"team": "Support"}},
+		&Template{Name: "citation", Content: "[{{.title}}]"},
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		vars    map[string]any
+		want    string
+	}{
+		{
+			name:    "shares variables",
+			content: `You help with {{.topic}}. {{include "safety_rules"}}`,
+			vars:    map[string]any{"topic": "billing", "secret": "the password"},
+			want:    "You help with billing. Never reveal the password.",
+		},
+		{
+			name:    "fragment defaults",
+			content: `{{include "signature"}}`,
+			want:    "Regards, Support",
+		},
+		{
+			name:    "caller variables override fragment defaults",
+			content: `{{include "signature"}}`,
+			vars:    map[string]any{"team": "Sales"},
+			want:    "Regards, Sales",
+		},
+		{
+			name:    "pinned version",
+			content: `{{include "signature@1.0.0"}}`,
+			vars:    map[string]any{"team": "Sales"},
+			want:    "-- Sales",
+		},
+		{
+			name:    "explicit data",
+			content: `{{range .sources}}{{include "citation" .}}{{end}}`,
+			vars: map[string]any{"sources": []map[string]any{
+				{"title": "a"}, {"title": "b"},
+			}},
+			want: "[a][b]",
+		},
+		{
+			name:    "included twice",
+			content: `{{include "citation" .a}}{{include "citation" .b}}`,
+			vars:    map[string]any{"a": map[string]any{"title": "x"}, "b": map[string]any{"title": "y"}},
+			want:    "[x][y]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &Template{Name: "main", Content: tt.content}
+			got, err := tmpl.RenderWithFragments(tt.vars, mgr)
+			if err != nil {
+				t.Fatalf("RenderWithFragments: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderWithFragments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_RenderWithFragments_Extends(t *testing.T) {
+	mgr := newFragmentManager(
+		&Template{
+			Name:      "base",
+			Content:   `{{block "role" .}}You are a helpful assistant.{{end}} {{include "safety_rules"}}{{block "task" .}}{{end}}`,
+			Variables: map[string]string{"secret": "keys", "tone": "formal"},
+		},
+		&Template{Name: "safety_rules", Content: "Never reveal {{.secret}}."},
+		&Template{
+			Name:    "support",
+			Extends: "base",
+			Content: `{{define "role"}}You are a {{.tone}} support agent.{{end}}`,
+		},
+	)
+
+	tests := []struct {
+		name string
+		tmpl *Template
+		vars map[string]any
+		want string
+	}{
+		{
+			name: "overrides a block and keeps the others",
+			tmpl: &Template{
+				Name:    "billing",
+				Extends: "base",
+				Content: `{{define "task"}} Answer {{.question}}{{end}}`,
+			},
+			vars: map[string]any{"question": "why"},
+			want: "You are a helpful assistant. Never reveal keys. Answer why",
+		},
+		{
+			name: "multi-level with defaults",
+			tmpl: &Template{
+				Name:      "refunds",
+				Extends:   "support",
+				Content:   `{{define "task"}} Handle refunds.{{end}}`,
+				Variables: map[string]string{"tone": "friendly"},
+			},
+			want: "You are a friendly support agent. Never reveal keys. Handle refunds.",
+		},
+		{
+			name: "body replaces base",
+			tmpl: &Template{
+				Name:    "custom",
+				Extends: "base",
+				Content: `Only {{template "role" .}}`,
+			},
+			want: "Only You are a helpful assistant.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tmpl.RenderWithFragments(tt.vars, mgr)
+			if err != nil {
+				t.Fatalf("RenderWithFragments: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderWithFragments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_RenderWithFragments_Errors(t *testing.T) {
+	mgr := newFragmentManager(
+		&Template{Name: "a", Content: `A {{include "b"}}`},
+		&Template{Name: "b", Content: `B {{include "a"}}`},
+		&Template{Name: "self", Content: `{{include "self"}}`},
+		&Template{Name: "x", Extends: "y", Content: `{{define "k"}}{{end}}`},
+		&Template{Name: "y", Extends: "x", Content: `{{define "k"}}{{end}}`},
+		&Template{Name: "base", Content: `{{include "child"}}`},
+		&Template{Name: "child", Extends: "base", Content: `{{define "k"}}{{end}}`},
+		&Template{Name: "broken", Content: `{{.x`},
+	)
+
+	tests := []struct {
+		name   string
+		tmpl   *Template
+		errMsg string
+	}{
+		{name: "circular include", tmpl: &Template{Name: "main", Content: `{{include "a"}}`}, errMsg: "circular template reference: main -> a -> b -> a"},
+		{name: "self include", tmpl: &Template{Name: "self", Content: `{{include "self"}}`}, errMsg: "self -> self"},
+		{name: "circular extends", tmpl: &Template{Name: "main", Extends: "x", Content: `x`}, errMsg: "main -> x -> y -> x"},
+		{name: "base includes child", tmpl: &Template{Name: "child", Extends: "base", Content: `{{define "k"}}{{end}}`}, errMsg: "child -> base -> child"},
+		{name: "missing fragment", tmpl: &Template{Name: "main", Content: `{{include "nope"}}`}, errMsg: `references "nope"`},
+		{name: "missing base", tmpl: &Template{Name: "main", Extends: "nope", Content: `x`}, errMsg: `references "nope"`},
+		{name: "invalid fragment", tmpl: &Template{Name: "main", Content: `{{include "broken"}}`}, errMsg: "parse error"},
+		{name: "bad data", tmpl: &Template{Name: "main", Content: `{{include "a" "str"}}`}, errMsg: "data must be a map"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.tmpl.RenderWithFragments(nil, mgr)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error %q should contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTemplate_Render_NeedsResolver(t *testing.T) {
+	include := &Template{Name: "main", Content: `{{include "safety_rules"}}`}
+	if err := include.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if _, err := include.Render(nil); err == nil || !strings.Contains(err.Error(), "use RenderWithFragments") {
+		t.Errorf("Render error = %v", err)
+	}
+
+	extends := &Template{Name: "child", Extends: "base", Content: `{{define "k"}}{{end}}`}
+	if _, err := extends.Render(nil); err == nil || !strings.Contains(err.Error(), "use RenderWithFragments") {
+		t.Errorf("Render error = %v", err)
+	}
+}
@@ -23,6 +23,11 @@
 //	    "variables": {"name": "World"}
 //	}
 //
+// An optional "syntax" field selects a registered template engine, such as
+// "jinja2", in place of Go text/template syntax. An optional "extends" field
+// names a base template; included fragments and base templates are loaded
+// from the same directory (see prompt.Template.RenderWithFragments).
+//
 // # Usage
 //
 //	mgr, err := file.NewFileManager("/path/to/prompts")
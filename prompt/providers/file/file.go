@@ -112,14 +112,16 @@ func (fm *FileManager) Get(name string, version string) (*prompt.Template, error
 }
 
 // Render retrieves a template by name (latest version), renders it with the
-// given variables, and returns the result as a single SystemMessage.
+// given variables, and returns the result as a single SystemMessage. The
+// fragments the template includes and the template it extends are resolved
+// from the same directory.
 func (fm *FileManager) Render(name string, vars map[string]any) ([]schema.Message, error) {
 	tmpl, err := fm.Get(name, "")
 	if err != nil {
 		return nil, err
 	}
 
-	rendered, err := tmpl.Render(vars)
+	rendered, err := tmpl.RenderWithFragments(vars, fm)
 	if err != nil {
 		return nil, err
 	}
@@ -537,3 +537,35 @@ func TestTemplateWithMetadata(t *testing.T) {
 	assert.NotNil(t, tmpl.Metadata)
 	assert.Equal(t, "test-author", tmpl.Metadata["author"])
 }
+
+func TestRender_Fragments(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTemplate(t, dir, "safety.json", prompt.Template{
+		Name:    "safety_rules",
+		Version: "1.0.0",
+		Content: "Never share {{.secret}}.",
+	})
+	writeTemplate(t, dir, "base.json", prompt.Template{
+		Name:      "base",
+		Version:   "1.0.0",
+		Content:   `{{block "role" .}}You are an assistant.{{end}} {{include "safety_rules"}}`,
+		Variables: map[string]string{"secret": "credentials"},
+	})
+	writeTemplate(t, dir, "support.json", prompt.Template{
+		Name:    "support",
+		Version: "1.0.0",
+		Extends: "base",
+		Content: `{{define "role"}}You are a support agent for {{.product}}.{{end}}`,
+	})
+
+	fm, err := NewFileManager(dir)
+	require.NoError(t, err)
+
+	msgs, err := fm.Render("support", map[string]any{"product": "Beluga"})
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	sm, ok := msgs[0].(*schema.SystemMessage)
+	require.True(t, ok)
+	assert.Equal(t, "You are a support agent for Beluga. Never share credentials.", sm.Text())
+}
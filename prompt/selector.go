@@ -0,0 +1,206 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// Metadata keys recorded on messages rendered by a manager wrapped with
+// WithVersionSelector.
+const (
+	// MetadataTemplateName is the name of the rendered template.
+	MetadataTemplateName = "prompt_name"
+	// MetadataTemplateVersion is the version of the rendered template.
+	MetadataTemplateVersion = "prompt_version"
+)
+
+// VersionSelector chooses which version of a template to serve, enabling
+// prompt A/B testing.
+type VersionSelector interface {
+	// SelectVersion returns the version of the named template to serve for
+	// key, such as a user ID, or "" to serve the latest version. Selectors
+	// should return the same version for the same non-empty key; an empty
+	// key may be assigned any version.
+	SelectVersion(name, key string) (string, error)
+}
+
+// VersionWeight is a version of a template and its share of traffic.
+type VersionWeight struct {
+	// Version is the template version.
+	Version string
+	// Weight is the version's relative share of traffic. Weights need not
+	// sum to 1.
+	Weight float64
+}
+
+// WeightedVersionSelector is a VersionSelector that splits traffic between
+// versions of a template by weight. Keys are assigned a version by a hash of
+// the key and template name, so a user keeps seeing the same version as
+// long as the weights do not change; requests without a key are assigned
+// at random. Templates without weights are served their latest version.
+// It is safe for concurrent use.
+type WeightedVersionSelector struct {
+	mu      sync.RWMutex
+	weights map[string][]VersionWeight
+}
+
+// Compile-time interface check.
+var _ VersionSelector = (*WeightedVersionSelector)(nil)
+
+// NewWeightedVersionSelector creates a WeightedVersionSelector with no
+// experiments.
+func NewWeightedVersionSelector() *WeightedVersionSelector {
+	return &WeightedVersionSelector{weights: make(map[string][]VersionWeight)}
+}
+
+// SetWeights sets the traffic split between versions of the named template.
+// Weights must be non-negative and not all zero. Calling SetWeights with no
+// weights ends the experiment, serving the latest version again.
+func (s *WeightedVersionSelector) SetWeights(name string, weights ...VersionWeight) error {
+	if name == "" {
+		return errors.New("prompt: template name is required")
+	}
+	var total float64
+	for _, w := range weights {
+		if w.Version == "" {
+			return fmt.Errorf("prompt: template %q: weighted version is required", name)
+		}
+		if w.Weight < 0 {
+			return fmt.Errorf("prompt: template %q: version %q has negative weight %v", name, w.Version, w.Weight)
+		}
+		total += w.Weight
+	}
+	if len(weights) > 0 && total == 0 {
+		return fmt.Errorf("prompt: template %q: weights sum to zero", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(weights) == 0 {
+		delete(s.weights, name)
+		return nil
+	}
+	s.weights[name] = append([]VersionWeight(nil), weights...)
+	return nil
+}
+
+// SelectVersion returns the version of the named template to serve for key.
+func (s *WeightedVersionSelector) SelectVersion(name, key string) (string, error) {
+	s.mu.RLock()
+	weights := s.weights[name]
+	s.mu.RUnlock()
+	if len(weights) == 0 {
+		return "", nil
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w.Weight
+	}
+	var point float64
+	if key == "" {
+		point = rand.Float64() // #nosec G404 -- traffic splitting, not security sensitive
+	} else {
+		point = hashPoint(name, key)
+	}
+	point *= total
+
+	for _, w := range weights {
+		if point < w.Weight {
+			return w.Version, nil
+		}
+		point -= w.Weight
+	}
+	// Guard against rounding: serve the last version with weight.
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i].Weight > 0 {
+			return weights[i].Version, nil
+		}
+	}
+	return "", nil
+}
+
+// hashPoint maps name and key to a point in [0, 1).
+func hashPoint(name, key string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
+
+// WithVersionSelector returns middleware that serves the versions of
+// templates chosen by sel. Render selects a version for the value of the
+// variable named keyVar, such as "user_id", and records the template's name
+// and version in the message metadata under MetadataTemplateName and
+// MetadataTemplateVersion. Get selects a version, without a key, when none
+// is requested. With an empty keyVar, or when the variable is not set,
+// versions are selected without a key.
+//
+//	sel := prompt.NewWeightedVersionSelector()
+//	err := sel.SetWeights("greeting",
+//	    prompt.VersionWeight{Version: "1.0.0", Weight: 0.9},
+//	    prompt.VersionWeight{Version: "2.0.0", Weight: 0.1},
+//	)
+//	mgr = prompt.ApplyMiddleware(mgr, prompt.WithVersionSelector(sel, "user_id"))
+func WithVersionSelector(sel VersionSelector, keyVar string) Middleware {
+	return func(next PromptManager) PromptManager {
+		return &selectingManager{next: next, sel: sel, keyVar: keyVar}
+	}
+}
+
+// selectingManager wraps a PromptManager and serves selected versions.
+type selectingManager struct {
+	next   PromptManager
+	sel    VersionSelector
+	keyVar string
+}
+
+func (m *selectingManager) Get(name, version string) (*Template, error) {
+	if version == "" {
+		var err error
+		version, err = m.sel.SelectVersion(name, "")
+		if err != nil {
+			return nil, fmt.Errorf("prompt: selecting version of %q: %w", name, err)
+		}
+	}
+	return m.next.Get(name, version)
+}
+
+func (m *selectingManager) Render(name string, vars map[string]any) ([]schema.Message, error) {
+	var key string
+	if v, ok := vars[m.keyVar]; ok && m.keyVar != "" && v != nil {
+		key = fmt.Sprint(v)
+	}
+	version, err := m.sel.SelectVersion(name, key)
+	if err != nil {
+		return nil, fmt.Errorf("prompt: selecting version of %q: %w", name, err)
+	}
+	tmpl, err := m.next.Get(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := tmpl.RenderWithFragments(vars, m.next)
+	if err != nil {
+		return nil, err
+	}
+	msg := schema.NewSystemMessage(rendered)
+	msg.Metadata = map[string]any{
+		MetadataTemplateName:    tmpl.Name,
+		MetadataTemplateVersion: tmpl.Version,
+	}
+	return []schema.Message{msg}, nil
+}
+
+func (m *selectingManager) List() []TemplateInfo {
+	return m.next.List()
+}
+
+// Ensure selectingManager implements PromptManager at compile time.
+var _ PromptManager = (*selectingManager)(nil)
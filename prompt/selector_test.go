@@ -0,0 +1,185 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWeightedVersionSelector_SetWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		weights []VersionWeight
+		errMsg  string
+	}{
+		{name: "valid", tmpl: "t", weights: []VersionWeight{{"1", 1}, {"2", 0}}},
+		{name: "empty name", tmpl: "", weights: []VersionWeight{{"1", 1}}, errMsg: "name is required"},
+		{name: "empty version", tmpl: "t", weights: []VersionWeight{{"", 1}}, errMsg: "version is required"},
+		{name: "negative weight", tmpl: "t", weights: []VersionWeight{{"1", -1}}, errMsg: "negative weight"},
+		{name: "zero total", tmpl: "t", weights: []VersionWeight{{"1", 0}}, errMsg: "sum to zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewWeightedVersionSelector().SetWeights(tt.tmpl, tt.weights...)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestWeightedVersionSelector_SelectVersion(t *testing.T) {
+	sel := NewWeightedVersionSelector()
+
+	v, err := sel.SelectVersion("greeting", "user-1")
+	if err != nil || v != "" {
+		t.Fatalf("no experiment: version = %q, err = %v", v, err)
+	}
+
+	if err := sel.SetWeights("greeting",
+		VersionWeight{Version: "1.0.0", Weight: 3},
+		VersionWeight{Version: "2.0.0", Weight: 1},
+		VersionWeight{Version: "3.0.0", Weight: 0},
+	); err != nil {
+		t.Fatalf("SetWeights: %v", err)
+	}
+
+	// The same key always gets the same version.
+	first, _ := sel.SelectVersion("greeting", "user-1")
+	for range 10 {
+		if v, _ := sel.SelectVersion("greeting", "user-1"); v != first {
+			t.Fatalf("user-1 got %q, then %q", first, v)
+		}
+	}
+
+	// Keys and random selections split traffic by weight.
+	for _, keyed := range []bool{true, false} {
+		const n = 4000
+		counts := make(map[string]int)
+		for i := range n {
+			key := ""
+			if keyed {
+				key = fmt.Sprintf("user-%d", i)
+			}
+			v, err := sel.SelectVersion("greeting", key)
+			if err != nil {
+				t.Fatalf("SelectVersion: %v", err)
+			}
+			counts[v]++
+		}
+		if counts["3.0.0"] != 0 {
+			t.Errorf("keyed=%v: zero-weight version served %d times", keyed, counts["3.0.0"])
+		}
+		if share := float64(counts["1.0.0"]) / n; math.Abs(share-0.75) > 0.05 {
+			t.Errorf("keyed=%v: 1.0.0 share = %.3f, want about 0.75 (counts %v)", keyed, share, counts)
+		}
+	}
+
+	// Ending the experiment serves the latest version again.
+	if err := sel.SetWeights("greeting"); err != nil {
+		t.Fatalf("SetWeights: %v", err)
+	}
+	if v, _ := sel.SelectVersion("greeting", "user-1"); v != "" {
+		t.Errorf("after experiment: version = %q", v)
+	}
+}
+
+func newVersionedManager() *inMemoryManager {
+	mgr := newInMemoryManager()
+	mgr.add(&Template{Name: "greeting", Version: "1.0.0", Content: "Hello, {{.user_id}}!"})
+	mgr.add(&Template{Name: "greeting", Version: "2.0.0", Content: "Hi there, {{.user_id}}!"})
+	return mgr
+}
+
+func TestWithVersionSelector_Render(t *testing.T) {
+	sel := NewWeightedVersionSelector()
+	if err := sel.SetWeights("greeting",
+		VersionWeight{Version: "1.0.0", Weight: 1},
+		VersionWeight{Version: "2.0.0", Weight: 1},
+	); err != nil {
+		t.Fatalf("SetWeights: %v", err)
+	}
+	mgr := ApplyMiddleware(newVersionedManager(), WithVersionSelector(sel, "user_id"))
+
+	seen := make(map[string]bool)
+	for i := range 50 {
+		user := fmt.Sprintf("user-%d", i)
+		want, _ := sel.SelectVersion("greeting", user)
+
+		msgs, err := mgr.Render("greeting", map[string]any{"user_id": user})
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		if len(msgs) != 1 {
+			t.Fatalf("expected 1 message, got %d", len(msgs))
+		}
+		meta := msgs[0].GetMetadata()
+		if meta[MetadataTemplateName] != "greeting" || meta[MetadataTemplateVersion] != want {
+			t.Errorf("%s: metadata = %v, want version %q", user, meta, want)
+		}
+		text := msgs[0].(interface{ Text() string }).Text()
+		if (want == "1.0.0") != strings.HasPrefix(text, "Hello") {
+			t.Errorf("%s: version %s rendered %q", user, want, text)
+		}
+		seen[want] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("versions served = %v, want both", seen)
+	}
+}
+
+func TestWithVersionSelector_NoExperiment(t *testing.T) {
+	mgr := ApplyMiddleware(newVersionedManager(), WithVersionSelector(NewWeightedVersionSelector(), ""))
+
+	msgs, err := mgr.Render("greeting", map[string]any{"user_id": "bob"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if v := msgs[0].GetMetadata()[MetadataTemplateVersion]; v != "2.0.0" {
+		t.Errorf("version = %v, want latest 2.0.0", v)
+	}
+
+	tmpl, err := mgr.Get("greeting", "1.0.0")
+	if err != nil || tmpl.Version != "1.0.0" {
+		t.Errorf("Get explicit version = %v, %v", tmpl, err)
+	}
+	if len(mgr.List()) != 1 {
+		t.Errorf("List() = %v", mgr.List())
+	}
+}
+
+// failingSelector is a VersionSelector that always fails.
+type failingSelector struct{}
+
+func (failingSelector) SelectVersion(string, string) (string, error) {
+	return "", errors.New("selector down")
+}
+
+func TestWithVersionSelector_Errors(t *testing.T) {
+	mgr := ApplyMiddleware(newVersionedManager(), WithVersionSelector(failingSelector{}, "user_id"))
+	if _, err := mgr.Render("greeting", nil); err == nil || !strings.Contains(err.Error(), "selector down") {
+		t.Errorf("Render error = %v", err)
+	}
+	if _, err := mgr.Get("greeting", ""); err == nil {
+		t.Error("expected Get error")
+	}
+
+	sel := NewWeightedVersionSelector()
+	if err := sel.SetWeights("greeting", VersionWeight{Version: "9.0.0", Weight: 1}); err != nil {
+		t.Fatalf("SetWeights: %v", err)
+	}
+	mgr = ApplyMiddleware(newVersionedManager(), WithVersionSelector(sel, "user_id"))
+	if _, err := mgr.Render("greeting", map[string]any{"user_id": "u"}); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
@@ -1,29 +1,35 @@
 package prompt
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"text/template"
 )
 
-// Template represents a versioned prompt template with Go text/template syntax.
+// Template represents a versioned prompt template. Its content uses Go
+// text/template syntax unless Syntax selects another registered Engine.
 // Templates can define default variable values and carry arbitrary metadata.
 type Template struct {
 	// Name uniquely identifies this template.
 	Name string `json:"name"`
 	// Version is the semantic version of this template (e.g., "1.0.0").
 	Version string `json:"version"`
-	// Content is the template body using Go text/template syntax.
+	// Content is the template body, in the template's syntax.
 	Content string `json:"content"`
+	// Extends names the base template this template extends, as "name" or
+	// "name@version". See RenderWithFragments.
+	Extends string `json:"extends,omitempty"`
+	// Syntax names the Engine that parses and renders Content, such as
+	// "jinja2". Empty means SyntaxGoTemplate.
+	Syntax string `json:"syntax,omitempty"`
 	// Variables holds default values for template variables.
 	Variables map[string]string `json:"variables,omitempty"`
 	// Metadata holds arbitrary key-value pairs for template organization.
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
-// Validate checks that the template's content is parseable as a Go text/template.
-// It returns an error if the name or content is empty, or if parsing fails.
+// Validate checks that the template's content is parseable in its syntax.
+// It returns an error if the name or content is empty, if the syntax has no
+// registered Engine, or if parsing fails.
 func (t *Template) Validate() error {
 	if t.Name == "" {
 		return errors.New("prompt: template name is required")
@@ -31,8 +37,11 @@ func (t *Template) Validate() error {
 	if t.Content == "" {
 		return errors.New("prompt: template content is required")
 	}
-	_, err := template.New(t.Name).Parse(t.Content)
+	e, err := t.engine()
 	if err != nil {
+		return err
+	}
+	if err := e.Validate(t.Name, t.Content); err != nil {
 		return fmt.Errorf("prompt: template %q parse error: %w", t.Name, err)
 	}
 	return nil
@@ -45,6 +54,9 @@ func (t *Template) Render(vars map[string]any) (string, error) {
 	if err := t.Validate(); err != nil {
 		return "", err
 	}
+	if t.Extends != "" {
+		return "", fmt.Errorf("prompt: template %q extends %q; use RenderWithFragments", t.Name, t.Extends)
+	}
 
 	// Merge defaults with provided vars; provided vars take precedence.
 	merged := make(map[string]any, len(t.Variables)+len(vars))
@@ -55,15 +67,13 @@ func (t *Template) Render(vars map[string]any) (string, error) {
 		merged[k] = v
 	}
 
-	tmpl, err := template.New(t.Name).Parse(t.Content)
+	e, err := t.engine()
 	if err != nil {
-		return "", fmt.Errorf("prompt: template %q parse error: %w", t.Name, err)
+		return "", err
 	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, merged); err != nil {
+	out, err := e.Render(t.Name, t.Content, merged)
+	if err != nil {
 		return "", fmt.Errorf("prompt: template %q execute error: %w", t.Name, err)
 	}
-
-	return buf.String(), nil
+	return out, nil
 }
@@ -0,0 +1,421 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// RequiredVars returns the sorted names of the variables the template
+// references, such as "name" for {{.name}}, {{.name.first}} or {{$.name}}.
+// Fields referenced inside range and with blocks, where dot is rebound, are
+// not variables of the template and are not included. It returns nil when
+// the content cannot be parsed or is not in Go template syntax.
+func (t *Template) RequiredVars() []string {
+	if !t.isGoTemplate() {
+		return nil
+	}
+	tmpl, err := newGoTemplate(t.Name).Parse(t.Content)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, tt := range tmpl.Templates() {
+		if tt.Tree != nil {
+			for _, name := range nodeRefs(tt.Tree.Root, true).vars {
+				seen[name] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// MissingVars returns the sorted names of the variables the template
+// references that are neither in vars nor given a default value.
+func (t *Template) MissingVars(vars map[string]any) []string {
+	var missing []string
+	for _, name := range t.RequiredVars() {
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		if _, ok := t.Variables[name]; ok {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// RenderWithPlaceholders renders the template like Render, except that each
+// missing variable (see MissingVars) is rendered as the placeholder
+// "{{.name}}" rather than "<no value>". It also returns the names of the
+// missing variables. Placeholders are non-empty strings, so conditions on
+// missing variables hold.
+func (t *Template) RenderWithPlaceholders(vars map[string]any) (string, []string, error) {
+	if err := t.Validate(); err != nil {
+		return "", nil, err
+	}
+	missing := t.MissingVars(vars)
+	if len(missing) == 0 {
+		out, err := t.Render(vars)
+		return out, nil, err
+	}
+
+	filled := make(map[string]any, len(vars)+len(missing))
+	maps.Copy(filled, vars)
+	for _, name := range missing {
+		filled[name] = "{{." + name + "}}"
+	}
+	out, err := t.Render(filled)
+	if err != nil {
+		return "", nil, err
+	}
+	return out, missing, nil
+}
+
+// PartialRender pre-fills the template with vars and returns a new template
+// awaiting the remaining variables, with the same name, version, defaults
+// and metadata. Parts of the content that depend only on vars are rendered;
+// other references to vars are replaced with their values, which must then
+// be strings, booleans or numbers. Partial rendering of templates that
+// define nested templates, or are not in Go template syntax, is not
+// supported.
+func (t *Template) PartialRender(vars map[string]any) (*Template, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if !t.isGoTemplate() {
+		return nil, fmt.Errorf("prompt: template %q: partial rendering of %s syntax is not supported", t.Name, t.Syntax)
+	}
+	tmpl, err := newGoTemplate(t.Name).Parse(t.Content)
+	if err != nil {
+		return nil, fmt.Errorf("prompt: template %q parse error: %w", t.Name, err)
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("prompt: template %q: partial rendering of nested templates is not supported", t.Name)
+	}
+
+	out := &Template{
+		Name:      t.Name,
+		Version:   t.Version,
+		Syntax:    t.Syntax,
+		Variables: maps.Clone(t.Variables),
+		Metadata:  maps.Clone(t.Metadata),
+	}
+	if tmpl.Tree == nil {
+		// The content is only whitespace and comments.
+		out.Content = t.Content
+		return out, nil
+	}
+	p := &partial{name: t.Name, vars: vars}
+	if err := p.list(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+	out.Content = tmpl.Tree.Root.String()
+	return out, nil
+}
+
+// partial pre-fills a parse tree with variables.
+type partial struct {
+	name string
+	vars map[string]any
+}
+
+// list pre-fills the nodes of l in place. Dot is the template's data in l.
+func (p *partial) list(l *parse.ListNode) error {
+	if l == nil {
+		return nil
+	}
+	for i, node := range l.Nodes {
+		refs := nodeRefs(node, true)
+		if len(refs.vars) > 0 && !refs.opaque && p.provides(refs.vars) {
+			text, err := p.exec(node)
+			if err != nil {
+				return err
+			}
+			l.Nodes[i] = &parse.TextNode{NodeType: parse.NodeText, Pos: node.Position(), Text: []byte(text)}
+			continue
+		}
+
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			if err := p.pipe(n.Pipe, true); err != nil {
+				return err
+			}
+		case *parse.IfNode:
+			if err := p.pipe(n.Pipe, true); err != nil {
+				return err
+			}
+			if err := p.list(n.List); err != nil {
+				return err
+			}
+			if err := p.list(n.ElseList); err != nil {
+				return err
+			}
+		case *parse.RangeNode:
+			if err := p.branch(&n.BranchNode); err != nil {
+				return err
+			}
+		case *parse.WithNode:
+			if err := p.branch(&n.BranchNode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// branch replaces references to the variables in a range or with block,
+// whose body is evaluated with dot rebound.
+func (p *partial) branch(b *parse.BranchNode) error {
+	if err := p.pipe(b.Pipe, true); err != nil {
+		return err
+	}
+	if err := p.values(b.List, false); err != nil {
+		return err
+	}
+	return p.values(b.ElseList, true)
+}
+
+// values replaces references to the variables in every pipeline under node.
+func (p *partial) values(node parse.Node, root bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := p.values(child, root); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return p.pipe(n.Pipe, root)
+	case *parse.IfNode:
+		if err := p.pipe(n.Pipe, root); err != nil {
+			return err
+		}
+		if err := p.values(n.List, root); err != nil {
+			return err
+		}
+		return p.values(n.ElseList, root)
+	case *parse.RangeNode:
+		if err := p.pipe(n.Pipe, root); err != nil {
+			return err
+		}
+		if err := p.values(n.List, false); err != nil {
+			return err
+		}
+		return p.values(n.ElseList, root)
+	case *parse.WithNode:
+		if err := p.pipe(n.Pipe, root); err != nil {
+			return err
+		}
+		if err := p.values(n.List, false); err != nil {
+			return err
+		}
+		return p.values(n.ElseList, root)
+	}
+	return nil
+}
+
+// pipe replaces references to the variables in a pipeline with literals.
+func (p *partial) pipe(pipe *parse.PipeNode, root bool) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, cmd := range pipe.Cmds {
+		for i, arg := range cmd.Args {
+			lit, err := p.literal(arg, root)
+			if err != nil {
+				return err
+			}
+			if lit != nil {
+				cmd.Args[i] = lit
+				continue
+			}
+			if chain, ok := arg.(*parse.ChainNode); ok {
+				arg = chain.Node
+			}
+			if nested, ok := arg.(*parse.PipeNode); ok {
+				if err := p.pipe(nested, root); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// literal returns the literal replacing arg, or nil when arg does not
+// reference one of the variables.
+func (p *partial) literal(arg parse.Node, root bool) (parse.Node, error) {
+	var ident []string
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if !root {
+			return nil, nil
+		}
+		ident = n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) < 2 || n.Ident[0] != "$" {
+			return nil, nil
+		}
+		ident = n.Ident[1:]
+	default:
+		return nil, nil
+	}
+
+	value, ok := p.vars[ident[0]]
+	if !ok {
+		return nil, nil
+	}
+	if len(ident) == 1 {
+		pos := arg.Position()
+		switch v := value.(type) {
+		case string:
+			return &parse.StringNode{NodeType: parse.NodeString, Pos: pos, Quoted: strconv.Quote(v), Text: v}, nil
+		case bool:
+			return &parse.BoolNode{NodeType: parse.NodeBool, Pos: pos, True: v}, nil
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return &parse.NumberNode{NodeType: parse.NodeNumber, Pos: pos, Text: fmt.Sprint(v)}, nil
+		}
+	}
+	return nil, fmt.Errorf("prompt: template %q: cannot pre-fill %q of type %T where other variables are needed", p.name, arg.String(), value)
+}
+
+// provides reports whether all of names are variables being pre-filled.
+func (p *partial) provides(names []string) bool {
+	for _, name := range names {
+		if _, ok := p.vars[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// exec renders node on its own and escapes the output as template text.
+func (p *partial) exec(node parse.Node) (string, error) {
+	tmpl, err := newGoTemplate(p.name).Parse(node.String())
+	if err != nil {
+		return "", fmt.Errorf("prompt: template %q parse error: %w", p.name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p.vars); err != nil {
+		return "", fmt.Errorf("prompt: template %q execute error: %w", p.name, err)
+	}
+	return strings.ReplaceAll(buf.String(), "{{", `{{"{{"}}`), nil
+}
+
+// refs describes the data a node depends on.
+type refs struct {
+	// vars are the variables referenced, in order of first reference.
+	vars []string
+
+	// opaque is set when the node also depends on something other than
+	// variables, such as $variables declared before it or dot as a whole,
+	// or declares $variables used after it, so cannot be rendered alone.
+	opaque bool
+
+	// locals are the $variables declared within the node.
+	locals map[string]bool
+
+	// depth is how deep in the node's blocks the walk is.
+	depth int
+}
+
+// nodeRefs returns the data node depends on. root reports whether dot is
+// the template's data at node.
+func nodeRefs(node parse.Node, root bool) refs {
+	r := refs{locals: make(map[string]bool)}
+	r.node(node, root)
+	return r
+}
+
+func (r *refs) node(node parse.Node, root bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			r.node(child, root)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 && r.depth == 0 {
+			r.opaque = true
+		}
+		r.pipe(n.Pipe, root)
+	case *parse.IfNode:
+		r.branch(&n.BranchNode, root, root)
+	case *parse.RangeNode:
+		r.branch(&n.BranchNode, root, false)
+	case *parse.WithNode:
+		r.branch(&n.BranchNode, root, false)
+	case *parse.TemplateNode:
+		r.opaque = true
+		r.pipe(n.Pipe, root)
+	}
+}
+
+// branch walks an if, range or with block; bodyRoot reports whether dot is
+// the template's data in its body.
+func (r *refs) branch(b *parse.BranchNode, root, bodyRoot bool) {
+	r.pipe(b.Pipe, root)
+	r.depth++
+	r.node(b.List, bodyRoot)
+	r.node(b.ElseList, root)
+	r.depth--
+}
+
+func (r *refs) pipe(pipe *parse.PipeNode, root bool) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			r.arg(arg, root)
+		}
+	}
+	for _, v := range pipe.Decl {
+		r.locals[v.Ident[0]] = true
+	}
+}
+
+func (r *refs) arg(arg parse.Node, root bool) {
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if root {
+			r.add(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		switch {
+		case n.Ident[0] != "$":
+			if !r.locals[n.Ident[0]] {
+				r.opaque = true
+			}
+		case len(n.Ident) > 1:
+			r.add(n.Ident[1])
+		default:
+			r.opaque = true
+		}
+	case *parse.DotNode:
+		if root {
+			r.opaque = true
+		}
+	case *parse.ChainNode:
+		r.arg(n.Node, root)
+	case *parse.PipeNode:
+		r.pipe(n, root)
+	}
+}
+
+func (r *refs) add(name string) {
+	if !slices.Contains(r.vars, name) {
+		r.vars = append(r.vars, name)
+	}
+}
@@ -0,0 +1,293 @@
+package prompt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplate_RequiredVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "fields",
+			content: "Hello, {{.name}}! You are {{.user.role}}.",
+			want:    []string{"name", "user"},
+		},
+		{
+			name:    "repeated and root variable",
+			content: "{{.b}} {{.a}} {{$.b}} {{printf \"%s\" .c | print}}",
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "conditionals",
+			content: "{{if .verbose}}{{.detail}}{{else if .brief}}short{{end}}",
+			want:    []string{"brief", "detail", "verbose"},
+		},
+		{
+			name:    "range rebinds dot",
+			content: "{{range .items}}{{.title}} by {{$.author}}{{else}}{{.empty}}{{end}}",
+			want:    []string{"author", "empty", "items"},
+		},
+		{
+			name:    "with rebinds dot",
+			content: "{{with .user}}{{.name}}{{end}}",
+			want:    []string{"user"},
+		},
+		{
+			name:    "nested template",
+			content: "{{define \"sig\"}}{{.signature}}{{end}}{{.body}}{{template \"sig\" .}}",
+			want:    []string{"body", "signature"},
+		},
+		{
+			name:    "no variables",
+			content: "Hello, {{\"world\"}}!",
+			want:    []string{},
+		},
+		{
+			name:    "invalid template",
+			content: "Hello {{.name",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := Template{Name: "test", Content: tt.content}
+			got := tmpl.RequiredVars()
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("RequiredVars() = %v, want nil", got)
+				}
+				return
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("RequiredVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_MissingVars(t *testing.T) {
+	tmpl := Template{
+		Name:      "test",
+		Content:   "{{.greeting}}, {{.name}}! {{.question}}",
+		Variables: map[string]string{"greeting": "Hello"},
+	}
+	got := tmpl.MissingVars(map[string]any{"name": "Alice"})
+	if !reflect.DeepEqual(got, []string{"question"}) {
+		t.Errorf("MissingVars() = %v, want [question]", got)
+	}
+	if got := tmpl.MissingVars(map[string]any{"name": "Alice", "question": nil}); got != nil {
+		t.Errorf("MissingVars() = %v, want nil", got)
+	}
+}
+
+func TestTemplate_RenderWithPlaceholders(t *testing.T) {
+	tmpl := Template{
+		Name:      "test",
+		Content:   "{{.greeting}}, {{.name}}! {{.question}}",
+		Variables: map[string]string{"greeting": "Hello"},
+	}
+
+	out, missing, err := tmpl.RenderWithPlaceholders(map[string]any{"question": "How are you?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "Hello, {{.name}}! How are you?" {
+		t.Errorf("output = %q", out)
+	}
+	if !reflect.DeepEqual(missing, []string{"name"}) {
+		t.Errorf("missing = %v, want [name]", missing)
+	}
+
+	out, missing, err = tmpl.RenderWithPlaceholders(map[string]any{"name": "Alice", "question": "Hi?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "Hello, Alice! Hi?" || missing != nil {
+		t.Errorf("output = %q, missing = %v", out, missing)
+	}
+
+	bad := Template{Name: "bad", Content: "{{.name"}
+	if _, _, err := bad.RenderWithPlaceholders(nil); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestTemplate_PartialRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		first    map[string]any
+		partial  string
+		required []string
+		rest     map[string]any
+		want     string
+	}{
+		{
+			name:     "fields",
+			content:  "You are {{.role}}. Answer {{.question}} in {{.language}}.",
+			first:    map[string]any{"role": "a tutor", "language": "French"},
+			partial:  "You are a tutor. Answer {{.question}} in French.",
+			required: []string{"question"},
+			rest:     map[string]any{"question": "why"},
+			want:     "You are a tutor. Answer why in French.",
+		},
+		{
+			name:     "mixed pipeline",
+			content:  `{{printf "%s: %s" .speaker .text}}`,
+			first:    map[string]any{"speaker": "Alice"},
+			partial:  `{{printf "%s: %s" "Alice" .text}}`,
+			required: []string{"text"},
+			rest:     map[string]any{"text": "hi"},
+			want:     "Alice: hi",
+		},
+		{
+			name:     "conditional on provided",
+			content:  "{{if .formal}}Dear {{.name}}{{else}}Hi {{.name}}{{end}}",
+			first:    map[string]any{"formal": true},
+			partial:  "{{if true}}Dear {{.name}}{{else}}Hi {{.name}}{{end}}",
+			required: []string{"name"},
+			rest:     map[string]any{"name": "Bob"},
+			want:     "Dear Bob",
+		},
+		{
+			name:     "rendered block",
+			content:  "{{range .items}}- {{.}}\n{{end}}{{.footer}}",
+			first:    map[string]any{"items": []string{"a", "b"}},
+			partial:  "- a\n- b\n{{.footer}}",
+			required: []string{"footer"},
+			rest:     map[string]any{"footer": "end"},
+			want:     "- a\n- b\nend",
+		},
+		{
+			name:     "range with local variables",
+			content:  "{{range $i, $x := .items}}{{$i}}={{$x}} {{end}}{{.n}}",
+			first:    map[string]any{"items": []int{7, 8}},
+			partial:  "0=7 1=8 {{.n}}",
+			required: []string{"n"},
+			rest:     map[string]any{"n": 2},
+			want:     "0=7 1=8 2",
+		},
+		{
+			name:     "root variable in range",
+			content:  "{{range .items}}{{.}}{{$.sep}}{{end}}",
+			first:    map[string]any{"sep": ","},
+			partial:  `{{range .items}}{{.}}{{","}}{{end}}`,
+			required: []string{"items"},
+			rest:     map[string]any{"items": []string{"x", "y"}},
+			want:     "x,y,",
+		},
+		{
+			name:     "template syntax in value",
+			content:  "{{.a}} {{.b}}",
+			first:    map[string]any{"a": "{{.b}}"},
+			required: []string{"b"},
+			rest:     map[string]any{"b": "B"},
+			want:     "{{.b}} B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &Template{
+				Name:     "test",
+				Version:  "1.0.0",
+				Content:  tt.content,
+				Metadata: map[string]any{"k": "v"},
+			}
+			partial, err := tmpl.PartialRender(tt.first)
+			if err != nil {
+				t.Fatalf("PartialRender: %v", err)
+			}
+			if tt.partial != "" && partial.Content != tt.partial {
+				t.Errorf("partial content = %q, want %q", partial.Content, tt.partial)
+			}
+			if partial.Name != "test" || partial.Version != "1.0.0" || partial.Metadata["k"] != "v" {
+				t.Errorf("partial = %+v", partial)
+			}
+			if got := partial.RequiredVars(); !reflect.DeepEqual(got, tt.required) {
+				t.Errorf("RequiredVars() = %v, want %v", got, tt.required)
+			}
+			got, err := partial.Render(tt.rest)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_PartialRender_KeepsDefaults(t *testing.T) {
+	tmpl := &Template{
+		Name:      "test",
+		Content:   "{{.greeting}}, {{.name}}!",
+		Variables: map[string]string{"greeting": "Hello"},
+	}
+	partial, err := tmpl.PartialRender(map[string]any{"name": "Alice"})
+	if err != nil {
+		t.Fatalf("PartialRender: %v", err)
+	}
+	got, err := partial.Render(map[string]any{"greeting": "Hi"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got != "Hi, Alice!" {
+		t.Errorf("Render() = %q", got)
+	}
+	partial.Variables["greeting"] = "changed"
+	if tmpl.Variables["greeting"] != "Hello" {
+		t.Error("PartialRender shares defaults with the original")
+	}
+}
+
+func TestTemplate_PartialRender_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		vars    map[string]any
+		errMsg  string
+	}{
+		{
+			name:    "invalid template",
+			content: "{{.name",
+			errMsg:  "parse error",
+		},
+		{
+			name:    "nested template",
+			content: "{{define \"x\"}}x{{end}}{{template \"x\"}}",
+			errMsg:  "not supported",
+		},
+		{
+			name:    "non-scalar value needed with other variables",
+			content: "{{range .items}}{{.}}{{$.sep}}{{end}}",
+			vars:    map[string]any{"items": []string{"a"}},
+			errMsg:  "cannot pre-fill",
+		},
+		{
+			name:    "execute error",
+			content: "{{.a.b}}",
+			vars:    map[string]any{"a": "text"},
+			errMsg:  "execute error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &Template{Name: "test", Content: tt.content}
+			_, err := tmpl.PartialRender(tt.vars)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error %q should contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
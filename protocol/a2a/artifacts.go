@@ -0,0 +1,290 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+const (
+	opUploadArtifact   = "a2a/upload_artifact: "
+	opDownloadArtifact = "a2a/download_artifact: "
+
+	defaultMimeType = "application/octet-stream"
+)
+
+// storedArtifact is an artifact held by the server.
+type storedArtifact struct {
+	meta  Artifact
+	data  []byte
+	owner string // subject that created the artifact
+}
+
+// storeArtifact stores data as a new artifact owned by owner and returns
+// its description.
+func (s *A2AServer) storeArtifact(owner string, data []byte, name, mimeType string) Artifact {
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	meta := Artifact{
+		ID:       uuid.New().String(),
+		Name:     name,
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+	}
+
+	s.mu.Lock()
+	s.artifacts[meta.ID] = &storedArtifact{meta: meta, data: data, owner: owner}
+	s.mu.Unlock()
+	return meta
+}
+
+// inputParts converts the parts of a task request by owner to content
+// parts, filling in the data of parts that reference its artifacts.
+func (s *A2AServer) inputParts(owner string, parts []Part) ([]schema.ContentPart, error) {
+	out := make([]schema.ContentPart, 0, len(parts))
+	for _, p := range parts {
+		if p.ArtifactID != "" {
+			s.mu.RLock()
+			a, ok := s.artifacts[p.ArtifactID]
+			s.mu.RUnlock()
+			if !ok || a.owner != owner {
+				return nil, core.Errorf(core.ErrNotFound, "unknown artifact %q", p.ArtifactID)
+			}
+			p.Data = a.data
+			if p.MimeType == "" {
+				p.MimeType = a.meta.MimeType
+			}
+			if p.Name == "" {
+				p.Name = a.meta.Name
+			}
+		}
+		cp, err := p.ContentPart()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cp)
+	}
+	return out, nil
+}
+
+// outputParts converts an agent's output parts to wire parts, storing data
+// larger than the inline limit as artifacts owned by owner.
+func (s *A2AServer) outputParts(owner string, parts []schema.ContentPart) ([]Part, error) {
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	wire, err := NewParts(parts)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range wire {
+		if len(p.Data) <= s.inlineLimit {
+			continue
+		}
+		meta := s.storeArtifact(owner, p.Data, p.Name, partMimeType(p))
+		wire[i].Data = nil
+		wire[i].ArtifactID = meta.ID
+		wire[i].Size = meta.Size
+	}
+	return wire, nil
+}
+
+// partMimeType returns the MIME type of the data of p, if known.
+func partMimeType(p Part) string {
+	if p.MimeType != "" {
+		return p.MimeType
+	}
+	if p.Type == schema.ContentAudio && p.Format != "" {
+		return "audio/" + p.Format
+	}
+	return ""
+}
+
+// handleUploadArtifact stores the request body as an artifact. The body's
+// Content-Type is recorded, defaulting to application/octet-stream, and the
+// optional "name" query parameter names it.
+func (s *A2AServer) handleUploadArtifact(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
+	mimeType := r.Header.Get(contentTypeHeader)
+	if mimeType == "" {
+		mimeType = defaultMimeType
+	}
+	if _, _, err := mime.ParseMediaType(mimeType); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid content type: "+err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxArtifactSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("artifact exceeds %d bytes", s.maxArtifactSize))
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, "read body: "+err.Error())
+		return
+	}
+
+	meta := s.storeArtifact(caller(r.Context()), data, r.URL.Query().Get("name"), mimeType)
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(ArtifactResponse{Artifact: meta})
+}
+
+// handleDownloadArtifact serves the content of an artifact with its
+// recorded content type.
+func (s *A2AServer) handleDownloadArtifact(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	a, ok := s.artifacts[r.PathValue("id")]
+	s.mu.RUnlock()
+	if !ok || a.owner != caller(r.Context()) {
+		writeJSONError(w, http.StatusNotFound, "artifact not found")
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, a.meta.MimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.meta.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if a.meta.Name != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.meta.Name}))
+	}
+	_, _ = w.Write(a.data)
+}
+
+// UploadArtifact stores data on the server as an artifact with the given
+// name and MIME type, which default to none and application/octet-stream.
+// Reference the returned artifact from a Part by its ID.
+func (c *A2AClient) UploadArtifact(ctx context.Context, data []byte, name, mimeType string) (*Artifact, error) {
+	if mimeType == "" {
+		mimeType = defaultMimeType
+	}
+	path := "/artifacts"
+	if name != "" {
+		path += "?name=" + url.QueryEscape(name)
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(data))
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, opUploadArtifact+"%w", err)
+	}
+	req.Header.Set(contentTypeHeader, mimeType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, opUploadArtifact+"%w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := authError(opUploadArtifact, resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		var errResp ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		code := core.ErrProviderDown
+		if resp.StatusCode == http.StatusRequestEntityTooLarge || resp.StatusCode == http.StatusBadRequest {
+			code = core.ErrInvalidInput
+		}
+		return nil, core.Errorf(code, opUploadArtifact+unexpectedStatusFmt+": %s", resp.StatusCode, errResp.Error)
+	}
+
+	var artResp ArtifactResponse
+	if err := json.NewDecoder(resp.Body).Decode(&artResp); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, opUploadArtifact+"%w", err)
+	}
+	return &artResp.Artifact, nil
+}
+
+// DownloadArtifact returns the content and MIME type of an artifact.
+func (c *A2AClient) DownloadArtifact(ctx context.Context, id string) ([]byte, string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/artifacts/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, "", core.Errorf(core.ErrInvalidInput, opDownloadArtifact+"%w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", core.Errorf(core.ErrProviderDown, opDownloadArtifact+"%w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := authError(opDownloadArtifact, resp); err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", core.Errorf(core.ErrNotFound, opDownloadArtifact+"artifact not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", core.Errorf(core.ErrProviderDown, opDownloadArtifact+unexpectedStatusFmt, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", core.Errorf(core.ErrProviderDown, opDownloadArtifact+"%w", err)
+	}
+	return data, resp.Header.Get(contentTypeHeader), nil
+}
+
+// UploadParts converts content parts to wire parts for a TaskRequest,
+// uploading data larger than DefaultInlineLimit as artifacts.
+func (c *A2AClient) UploadParts(ctx context.Context, parts []schema.ContentPart) ([]Part, error) {
+	wire, err := NewParts(parts)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range wire {
+		if len(p.Data) <= DefaultInlineLimit {
+			continue
+		}
+		meta, err := c.UploadArtifact(ctx, p.Data, p.Name, partMimeType(p))
+		if err != nil {
+			return nil, err
+		}
+		wire[i].Data = nil
+		wire[i].ArtifactID = meta.ID
+		wire[i].Size = meta.Size
+	}
+	return wire, nil
+}
+
+// ResolveParts converts wire parts from a task to content parts,
+// downloading the data of parts that reference artifacts.
+func (c *A2AClient) ResolveParts(ctx context.Context, parts []Part) ([]schema.ContentPart, error) {
+	out := make([]schema.ContentPart, 0, len(parts))
+	for _, p := range parts {
+		if p.ArtifactID != "" && p.Data == nil {
+			data, mimeType, err := c.DownloadArtifact(ctx, p.ArtifactID)
+			if err != nil {
+				return nil, err
+			}
+			p.Data = data
+			if p.MimeType == "" && p.Type != schema.ContentAudio {
+				p.MimeType = mimeType
+			}
+		}
+		cp, err := p.ContentPart()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cp)
+	}
+	return out, nil
+}
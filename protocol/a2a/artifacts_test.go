@@ -0,0 +1,301 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+func newArtifactServer(t *testing.T, opts ...ServerOption) (*httptest.Server, *A2AClient) {
+	t.Helper()
+	ts := httptest.NewServer(NewServer(&mockAgent{id: "files"}, AgentCard{Name: "files"}, opts...).Handler())
+	t.Cleanup(ts.Close)
+	return ts, NewClient(ts.URL)
+}
+
+func assertCode(t *testing.T, err error, code core.ErrorCode) {
+	t.Helper()
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) {
+		t.Fatalf("expected *core.Error, got %v", err)
+	}
+	if coreErr.Code != code {
+		t.Errorf("code = %q, want %q", coreErr.Code, code)
+	}
+}
+
+func TestArtifact_UploadDownload(t *testing.T) {
+	ts, client := newArtifactServer(t)
+	ctx := context.Background()
+
+	data := []byte("%PDF-1.7 report")
+	art, err := client.UploadArtifact(ctx, data, "report.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("UploadArtifact: %v", err)
+	}
+	if art.ID == "" || art.Name != "report.pdf" || art.MimeType != "application/pdf" || art.Size != int64(len(data)) {
+		t.Errorf("unexpected artifact %+v", art)
+	}
+
+	got, mimeType, err := client.DownloadArtifact(ctx, art.ID)
+	if err != nil {
+		t.Fatalf("DownloadArtifact: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("data = %q, want %q", got, data)
+	}
+	if mimeType != "application/pdf" {
+		t.Errorf("mime type = %q, want application/pdf", mimeType)
+	}
+
+	resp, err := http.Get(ts.URL + "/artifacts/" + art.ID)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename=report.pdf` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+}
+
+func TestArtifact_DefaultContentType(t *testing.T) {
+	ts, client := newArtifactServer(t)
+
+	resp, err := http.Post(ts.URL+"/artifacts", "", strings.NewReader("raw"))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+
+	art, err := client.UploadArtifact(context.Background(), []byte("raw"), "", "")
+	if err != nil {
+		t.Fatalf("UploadArtifact: %v", err)
+	}
+	if art.MimeType != "application/octet-stream" {
+		t.Errorf("mime type = %q, want application/octet-stream", art.MimeType)
+	}
+}
+
+func TestArtifact_InvalidContentType(t *testing.T) {
+	ts, _ := newArtifactServer(t)
+
+	resp, err := http.Post(ts.URL+"/artifacts", "not a/type;;", strings.NewReader("x"))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestArtifact_TooLarge(t *testing.T) {
+	ts, client := newArtifactServer(t, WithMaxArtifactSize(8))
+
+	resp, err := http.Post(ts.URL+"/artifacts", "text/plain", strings.NewReader("123456789"))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+
+	_, err = client.UploadArtifact(context.Background(), []byte("123456789"), "", "text/plain")
+	assertCode(t, err, core.ErrInvalidInput)
+
+	if _, err := client.UploadArtifact(context.Background(), []byte("12345678"), "", "text/plain"); err != nil {
+		t.Errorf("UploadArtifact at limit: %v", err)
+	}
+}
+
+func TestArtifact_DownloadNotFound(t *testing.T) {
+	_, client := newArtifactServer(t)
+
+	_, _, err := client.DownloadArtifact(context.Background(), "missing")
+	assertCode(t, err, core.ErrNotFound)
+}
+
+func TestServer_CreateTask_PartsOnly(t *testing.T) {
+	_, client := newArtifactServer(t)
+
+	task, err := client.CreateTask(context.Background(), TaskRequest{
+		Parts: []Part{{Type: schema.ContentText, Text: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if len(task.InputParts) != 1 {
+		t.Errorf("input parts = %d, want 1", len(task.InputParts))
+	}
+}
+
+func TestServer_CreateTask_UnknownArtifact(t *testing.T) {
+	_, client := newArtifactServer(t)
+
+	_, err := client.CreateTask(context.Background(), TaskRequest{
+		Input: "summarize",
+		Parts: []Part{{Type: schema.ContentFile, ArtifactID: "missing"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown artifact")
+	}
+}
+
+func TestParts_RoundTrip(t *testing.T) {
+	parts := []schema.ContentPart{
+		schema.TextPart{Text: "hi"},
+		schema.ImagePart{Data: []byte{1, 2}, MimeType: "image/png"},
+		schema.AudioPart{Data: []byte{3}, Format: "wav", SampleRate: 16000},
+		schema.FilePart{Data: []byte("a,b"), Name: "t.csv", MimeType: "text/csv"},
+	}
+	wire, err := NewParts(parts)
+	if err != nil {
+		t.Fatalf("NewParts: %v", err)
+	}
+	got, err := ContentParts(wire)
+	if err != nil {
+		t.Fatalf("ContentParts: %v", err)
+	}
+	if len(got) != len(parts) {
+		t.Fatalf("got %d parts, want %d", len(got), len(parts))
+	}
+	if f, ok := got[3].(schema.FilePart); !ok || f.Name != "t.csv" || string(f.Data) != "a,b" {
+		t.Errorf("file part = %#v", got[3])
+	}
+
+	if _, err := NewPart(nil); err == nil {
+		t.Error("expected error for nil part")
+	}
+	if _, err := (Part{Type: "unknown"}).ContentPart(); err == nil {
+		t.Error("expected error for unknown part type")
+	}
+}
+
+// newPartsAgent returns an agent that echoes its input parts as output
+// parts.
+func newPartsAgent() *mockAgent {
+	return &mockAgent{id: "parts", invokeFn: func(ctx context.Context, input string) (string, error) {
+		AddOutputParts(ctx, InputParts(ctx)...)
+		return "received: " + input, nil
+	}}
+}
+
+func TestRemoteAgent_Parts(t *testing.T) {
+	a := newPartsAgent()
+	ts := httptest.NewServer(NewServer(a, AgentCard{Name: "parts"}, WithInlineLimit(4)).Handler())
+	defer ts.Close()
+
+	remote, err := NewRemoteAgent(ts.URL)
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+
+	large := bytes.Repeat([]byte("x"), DefaultInlineLimit+1)
+	in := []schema.ContentPart{
+		schema.TextPart{Text: "note"},
+		schema.ImagePart{Data: []byte{1, 2, 3, 4, 5}, MimeType: "image/png"},
+		schema.FilePart{Data: large, Name: "big.bin", MimeType: "application/octet-stream"},
+	}
+	outputs := &Outputs{}
+	ctx := WithOutputs(WithInputParts(context.Background(), in...), outputs)
+
+	out, err := remote.Invoke(ctx, "files")
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out != "received: files" {
+		t.Errorf("output = %q", out)
+	}
+
+	got := outputs.Parts()
+	if len(got) != len(in) {
+		t.Fatalf("got %d output parts, want %d", len(got), len(in))
+	}
+	if img, ok := got[1].(schema.ImagePart); !ok || !bytes.Equal(img.Data, []byte{1, 2, 3, 4, 5}) || img.MimeType != "image/png" {
+		t.Errorf("image part = %#v", got[1])
+	}
+	if f, ok := got[2].(schema.FilePart); !ok || !bytes.Equal(f.Data, large) || f.Name != "big.bin" {
+		t.Errorf("file part not round-tripped")
+	}
+}
+
+func TestRemoteAgent_Parts_DoneMetadata(t *testing.T) {
+	a := newPartsAgent()
+	ts := httptest.NewServer(NewServer(a, AgentCard{Name: "parts"}).Handler())
+	defer ts.Close()
+
+	remote, err := NewRemoteAgent(ts.URL)
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+
+	ctx := WithInputParts(context.Background(), schema.TextPart{Text: "note"})
+	var done agent.Event
+	for event, err := range remote.Stream(ctx, "files") {
+		if err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		if event.Type == agent.EventDone {
+			done = event
+		}
+	}
+	parts, ok := done.Metadata["parts"].([]schema.ContentPart)
+	if !ok || len(parts) != 1 {
+		t.Fatalf("done metadata parts = %#v", done.Metadata["parts"])
+	}
+}
+
+func TestServer_OutputParts_InlineLimit(t *testing.T) {
+	ts := httptest.NewServer(NewServer(newPartsAgent(), AgentCard{Name: "parts"}, WithInlineLimit(4)).Handler())
+	defer ts.Close()
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, TaskRequest{Parts: []Part{
+		{Type: schema.ContentFile, Data: []byte("abcd"), Name: "small.txt"},
+		{Type: schema.ContentFile, Data: []byte("abcde"), Name: "large.txt", MimeType: "text/plain"},
+	}})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	var final Task
+	for tk, err := range client.SubscribeTask(ctx, task.ID) {
+		if err != nil {
+			t.Fatalf("SubscribeTask: %v", err)
+		}
+		final = tk
+	}
+	if final.Status != StatusCompleted || len(final.OutputParts) != 2 {
+		t.Fatalf("task = %+v", final)
+	}
+	if p := final.OutputParts[0]; p.ArtifactID != "" || string(p.Data) != "abcd" {
+		t.Errorf("small part = %+v, want inline", p)
+	}
+	p := final.OutputParts[1]
+	if p.ArtifactID == "" || p.Data != nil || p.Size != 5 {
+		t.Fatalf("large part = %+v, want artifact reference", p)
+	}
+	data, mimeType, err := client.DownloadArtifact(ctx, p.ArtifactID)
+	if err != nil {
+		t.Fatalf("DownloadArtifact: %v", err)
+	}
+	if string(data) != "abcde" || mimeType != "text/plain" {
+		t.Errorf("artifact = %q (%s)", data, mimeType)
+	}
+}
@@ -0,0 +1,296 @@
+package a2a
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// Auth schemes advertised in AgentCard.AuthSchemes.
+const (
+	// AuthSchemeBearer authenticates with a bearer token in the
+	// Authorization header.
+	AuthSchemeBearer = "bearer"
+
+	// AuthSchemeAPIKey authenticates with an API key in a request header.
+	AuthSchemeAPIKey = "apiKey"
+
+	// AuthSchemeMTLS authenticates with a TLS client certificate.
+	AuthSchemeMTLS = "mtls"
+)
+
+// DefaultAPIKeyHeader is the header carrying API keys when none is given.
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// ErrNoCredentials is returned by an Authenticator when a request carries no
+// credentials of its scheme, so that the next authenticator is tried.
+var ErrNoCredentials = errors.New("a2a: no credentials")
+
+// Authenticator verifies the credentials of requests to an A2AServer.
+type Authenticator interface {
+	// Scheme returns the auth scheme advertised in the Agent Card, such as
+	// AuthSchemeBearer.
+	Scheme() string
+
+	// Authenticate verifies the credentials of r. It returns the caller's
+	// subject and a context derived from r's carrying the verified
+	// credentials for the auth policy. It returns ErrNoCredentials when r
+	// has no credentials of this scheme, and an ErrAuth error when they are
+	// invalid.
+	Authenticate(r *http.Request) (context.Context, string, error)
+}
+
+// TokenVerifier verifies bearer tokens. *auth.JWTPolicy implements it.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Claims, error)
+}
+
+// NewBearerAuthenticator returns an Authenticator that verifies bearer
+// tokens in the Authorization header with v. The caller's subject is the
+// token's sub claim; the context carries the token and its claims (see
+// auth.BearerTokenFromContext and auth.ClaimsFromContext).
+func NewBearerAuthenticator(v TokenVerifier) Authenticator {
+	return &bearerAuthenticator{verifier: v}
+}
+
+type bearerAuthenticator struct {
+	verifier TokenVerifier
+}
+
+func (a *bearerAuthenticator) Scheme() string { return AuthSchemeBearer }
+
+func (a *bearerAuthenticator) Authenticate(r *http.Request) (context.Context, string, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, "", ErrNoCredentials
+	}
+	claims, err := a.verifier.Verify(r.Context(), token)
+	if err != nil {
+		return nil, "", core.Errorf(core.ErrAuth, "a2a/auth: %w", err)
+	}
+	ctx := auth.WithClaims(auth.WithBearerToken(r.Context(), token), claims)
+	return ctx, claims.Subject(), nil
+}
+
+// NewAPIKeyAuthenticator returns an Authenticator that accepts the API keys
+// in keys, mapped to the subject of the caller holding each, sent in the
+// given header (DefaultAPIKeyHeader when empty). Keys are compared in
+// constant time.
+func NewAPIKeyAuthenticator(header string, keys map[string]string) Authenticator {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	return &apiKeyAuthenticator{header: header, keys: keys}
+}
+
+type apiKeyAuthenticator struct {
+	header string
+	keys   map[string]string
+}
+
+func (a *apiKeyAuthenticator) Scheme() string { return AuthSchemeAPIKey }
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (context.Context, string, error) {
+	key := r.Header.Get(a.header)
+	if key == "" {
+		return nil, "", ErrNoCredentials
+	}
+	subject, found := "", false
+	for k, sub := range a.keys {
+		// Compare against every key so that timing does not reveal which
+		// one matched.
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			subject, found = sub, true
+		}
+	}
+	if !found {
+		return nil, "", core.Errorf(core.ErrAuth, "a2a/auth: invalid API key")
+	}
+	return r.Context(), subject, nil
+}
+
+// NewMTLSAuthenticator returns an Authenticator that accepts requests made
+// with a verified TLS client certificate. The caller's subject is the
+// certificate's common name. The server must verify client certificates:
+// serve with WithTLSConfig and a tls.Config whose ClientAuth is
+// tls.VerifyClientCertIfGiven or tls.RequireAndVerifyClientCert and whose
+// ClientCAs holds the trusted roots.
+func NewMTLSAuthenticator() Authenticator {
+	return mtlsAuthenticator{}
+}
+
+type mtlsAuthenticator struct{}
+
+func (mtlsAuthenticator) Scheme() string { return AuthSchemeMTLS }
+
+func (mtlsAuthenticator) Authenticate(r *http.Request) (context.Context, string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, "", ErrNoCredentials
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	if cert.Subject.CommonName == "" {
+		return nil, "", core.Errorf(core.ErrAuth, "a2a/auth: client certificate has no common name")
+	}
+	return r.Context(), cert.Subject.CommonName, nil
+}
+
+// WithAuthenticators requires requests to every endpoint except the Agent
+// Card to authenticate with one of authn, tried in order. Requests without
+// valid credentials are rejected with 401 Unauthorized. Unless the card
+// lists its own, the schemes of authn are advertised in
+// AgentCard.AuthSchemes.
+//
+// Tasks and artifacts are visible only to the subject that created them.
+func WithAuthenticators(authn ...Authenticator) ServerOption {
+	return func(s *A2AServer) {
+		s.authenticators = append(s.authenticators, authn...)
+	}
+}
+
+// WithAuthPolicy authorizes task creation and artifact upload with policy,
+// which is asked whether the authenticated subject holds
+// auth.PermAgentDelegate on the agent, named by its card. Denied requests
+// are rejected with 403 Forbidden.
+func WithAuthPolicy(policy auth.Policy) ServerOption {
+	return func(s *A2AServer) {
+		s.policy = policy
+	}
+}
+
+// WithTLSConfig makes Serve accept TLS connections configured by cfg, which
+// must hold a certificate. Set cfg.ClientAuth and cfg.ClientCAs to verify
+// client certificates for NewMTLSAuthenticator.
+func WithTLSConfig(cfg *tls.Config) ServerOption {
+	return func(s *A2AServer) {
+		s.tlsConfig = cfg
+	}
+}
+
+type callerKey struct{}
+
+// caller returns the subject authenticated for the request of ctx, or "" if
+// the server does not authenticate requests.
+func caller(ctx context.Context) string {
+	sub, _ := ctx.Value(callerKey{}).(string)
+	return sub
+}
+
+// authenticate wraps next so that it runs only for authenticated requests,
+// with the caller's subject and credentials in the request context.
+func (s *A2AServer) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	if len(s.authenticators) == 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, a := range s.authenticators {
+			ctx, subject, err := a.Authenticate(r)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				s.unauthorized(w, "invalid credentials")
+				return
+			}
+			next(w, r.WithContext(context.WithValue(ctx, callerKey{}, subject)))
+			return
+		}
+		s.unauthorized(w, "authentication required")
+	}
+}
+
+// unauthorized writes a 401 response, challenging for a bearer token when
+// bearer tokens are accepted.
+func (s *A2AServer) unauthorized(w http.ResponseWriter, message string) {
+	for _, a := range s.authenticators {
+		if a.Scheme() == AuthSchemeBearer {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="a2a"`)
+			break
+		}
+	}
+	writeJSONError(w, http.StatusUnauthorized, message)
+}
+
+// authorize checks that the caller of r may delegate work to the agent. It
+// writes an error response and returns false if not.
+func (s *A2AServer) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if s.policy == nil {
+		return true
+	}
+	allowed, err := s.policy.Authorize(r.Context(), caller(r.Context()), auth.PermAgentDelegate, s.card.Name)
+	if err != nil {
+		var coreErr *core.Error
+		if errors.As(err, &coreErr) && coreErr.Code == core.ErrAuth {
+			s.unauthorized(w, "invalid credentials")
+			return false
+		}
+		writeJSONError(w, http.StatusInternalServerError, "authorization failed")
+		return false
+	}
+	if !allowed {
+		writeJSONError(w, http.StatusForbidden, "forbidden")
+		return false
+	}
+	return true
+}
+
+// ClientOption configures an A2AClient.
+type ClientOption func(*A2AClient)
+
+// WithBearerToken sends token in the Authorization header of every request.
+func WithBearerToken(token string) ClientOption {
+	return func(c *A2AClient) {
+		c.header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithAPIKey sends key in the given header (DefaultAPIKeyHeader when empty)
+// of every request.
+func WithAPIKey(header, key string) ClientOption {
+	return func(c *A2AClient) {
+		if header == "" {
+			header = DefaultAPIKeyHeader
+		}
+		c.header.Set(header, key)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. Use a client whose
+// transport presents a TLS client certificate to authenticate with mTLS.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *A2AClient) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// newRequest creates a request to path on the server with the client's
+// credentials.
+func (c *A2AClient) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	return req, nil
+}
+
+// authError returns an ErrAuth error for 401 and 403 responses, else nil.
+func authError(op string, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return core.Errorf(core.ErrAuth, "%sunauthorized", op)
+	case http.StatusForbidden:
+		return core.Errorf(core.ErrAuth, "%sforbidden", op)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package a2a
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// staticVerifier accepts the tokens in its map, issued to the mapped
+// subject.
+type staticVerifier map[string]string
+
+func (v staticVerifier) Verify(_ context.Context, token string) (auth.Claims, error) {
+	sub, ok := v[token]
+	if !ok {
+		return nil, core.Errorf(core.ErrAuth, "bad token: %w", auth.ErrInvalidToken)
+	}
+	return auth.Claims{"sub": sub}, nil
+}
+
+// subjectPolicy allows PermAgentDelegate to the listed subjects.
+type subjectPolicy []string
+
+func (p subjectPolicy) Name() string { return "subjects" }
+
+func (p subjectPolicy) Authorize(_ context.Context, subject string, perm auth.Permission, _ string) (bool, error) {
+	return perm == auth.PermAgentDelegate && slices.Contains(p, subject), nil
+}
+
+func newAuthServer(t *testing.T, opts ...ServerOption) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(NewServer(&mockAgent{id: "secure"}, AgentCard{Name: "secure"}, opts...).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	ts := newAuthServer(t, WithAuthenticators(NewAPIKeyAuthenticator("", map[string]string{"k1": "alice"})))
+	ctx := context.Background()
+
+	card, err := NewClient(ts.URL).GetCard(ctx)
+	if err != nil {
+		t.Fatalf("GetCard without credentials: %v", err)
+	}
+	if !slices.Equal(card.AuthSchemes, []string{AuthSchemeAPIKey}) {
+		t.Errorf("auth schemes = %v", card.AuthSchemes)
+	}
+
+	_, err = NewClient(ts.URL).CreateTask(ctx, TaskRequest{Input: "hi"})
+	assertCode(t, err, core.ErrAuth)
+
+	_, err = NewClient(ts.URL, WithAPIKey("", "wrong")).CreateTask(ctx, TaskRequest{Input: "hi"})
+	assertCode(t, err, core.ErrAuth)
+
+	if _, err := NewClient(ts.URL, WithAPIKey("", "k1")).CreateTask(ctx, TaskRequest{Input: "hi"}); err != nil {
+		t.Errorf("CreateTask with key: %v", err)
+	}
+}
+
+func TestAuth_Bearer(t *testing.T) {
+	ts := newAuthServer(t, WithAuthenticators(NewBearerAuthenticator(staticVerifier{"tok": "alice"})))
+
+	resp, err := http.Post(ts.URL+"/tasks", contentTypeJSON, nil)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", resp.StatusCode)
+	}
+	if got := resp.Header.Get("WWW-Authenticate"); got != `Bearer realm="a2a"` {
+		t.Errorf("WWW-Authenticate = %q", got)
+	}
+
+	remote, err := NewRemoteAgent(ts.URL, WithBearerToken("tok"))
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	out, err := remote.Invoke(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out != "response: hello" {
+		t.Errorf("output = %q", out)
+	}
+
+	remote, err = NewRemoteAgent(ts.URL, WithBearerToken("forged"))
+	if err != nil {
+		t.Fatalf("NewRemoteAgent: %v", err)
+	}
+	_, err = remote.Invoke(context.Background(), "hello")
+	assertCode(t, err, core.ErrAuth)
+}
+
+func TestAuth_Policy(t *testing.T) {
+	ts := newAuthServer(t,
+		WithAuthenticators(NewAPIKeyAuthenticator("", map[string]string{"k1": "alice", "k2": "bob"})),
+		WithAuthPolicy(subjectPolicy{"alice"}),
+	)
+	ctx := context.Background()
+
+	if _, err := NewClient(ts.URL, WithAPIKey("", "k1")).CreateTask(ctx, TaskRequest{Input: "hi"}); err != nil {
+		t.Errorf("CreateTask as alice: %v", err)
+	}
+
+	bob := NewClient(ts.URL, WithAPIKey("", "k2"))
+	_, err := bob.CreateTask(ctx, TaskRequest{Input: "hi"})
+	assertCode(t, err, core.ErrAuth)
+	_, err = bob.UploadArtifact(ctx, []byte("x"), "", "")
+	assertCode(t, err, core.ErrAuth)
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/tasks", nil)
+	req.Header.Set(DefaultAPIKeyHeader, "k2")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestAuth_PolicyError(t *testing.T) {
+	jwt := auth.NewJWTPolicy("jwt", auth.StaticJWKS{}, subjectPolicy{"alice"})
+	ts := newAuthServer(t,
+		WithAuthenticators(NewAPIKeyAuthenticator("", map[string]string{"k1": "alice"})),
+		WithAuthPolicy(jwt),
+	)
+
+	// The JWT policy finds no bearer token in an API-key request.
+	_, err := NewClient(ts.URL, WithAPIKey("", "k1")).CreateTask(context.Background(), TaskRequest{Input: "hi"})
+	assertCode(t, err, core.ErrAuth)
+}
+
+func TestAuth_Ownership(t *testing.T) {
+	ts := newAuthServer(t, WithAuthenticators(NewAPIKeyAuthenticator("", map[string]string{"k1": "alice", "k2": "bob"})))
+	ctx := context.Background()
+	alice := NewClient(ts.URL, WithAPIKey("", "k1"))
+	bob := NewClient(ts.URL, WithAPIKey("", "k2"))
+
+	task, err := alice.CreateTask(ctx, TaskRequest{Input: "hi"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := alice.GetTask(ctx, task.ID); err != nil {
+		t.Errorf("GetTask as owner: %v", err)
+	}
+	_, err = bob.GetTask(ctx, task.ID)
+	assertCode(t, err, core.ErrNotFound)
+	assertCode(t, bob.CancelTask(ctx, task.ID), core.ErrNotFound)
+
+	art, err := alice.UploadArtifact(ctx, []byte("secret"), "s.txt", "text/plain")
+	if err != nil {
+		t.Fatalf("UploadArtifact: %v", err)
+	}
+	_, _, err = bob.DownloadArtifact(ctx, art.ID)
+	assertCode(t, err, core.ErrNotFound)
+	_, err = bob.CreateTask(ctx, TaskRequest{Input: "read", Parts: []Part{{Type: "file", ArtifactID: art.ID}}})
+	if err == nil {
+		t.Error("expected error referencing another caller's artifact")
+	}
+}
+
+// newCert creates a certificate for cn signed by parent, or self-signed
+// when parent is nil.
+func newCert(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestAuth_MTLS(t *testing.T) {
+	ca, caKey := newCert(t, "test-ca", nil, nil)
+	clientCert, clientKey := newCert(t, "svc-a", ca, caKey)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	srv := NewServer(&mockAgent{id: "secure"}, AgentCard{Name: "secure"},
+		WithAuthenticators(NewMTLSAuthenticator()),
+		WithAuthPolicy(subjectPolicy{"svc-a"}),
+	)
+	ts := httptest.NewUnstartedServer(srv.Handler())
+	ts.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: pool}
+	ts.StartTLS()
+	defer ts.Close()
+
+	ctx := context.Background()
+	_, err := NewClient(ts.URL, WithHTTPClient(ts.Client())).CreateTask(ctx, TaskRequest{Input: "hi"})
+	assertCode(t, err, core.ErrAuth)
+
+	transport := ts.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.Certificates = []tls.Certificate{{
+		Certificate: [][]byte{clientCert.Raw},
+		PrivateKey:  clientKey,
+		Leaf:        clientCert,
+	}}
+	hc := &http.Client{Transport: transport}
+	task, err := NewClient(ts.URL, WithHTTPClient(hc)).CreateTask(ctx, TaskRequest{Input: "hi"})
+	if err != nil {
+		t.Fatalf("CreateTask with client certificate: %v", err)
+	}
+	if task.ID == "" {
+		t.Error("expected task ID")
+	}
+}
+
+func TestAuth_ErrNoCredentials(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/tasks/x", nil)
+	for _, a := range []Authenticator{
+		NewBearerAuthenticator(staticVerifier{}),
+		NewAPIKeyAuthenticator("", nil),
+		NewMTLSAuthenticator(),
+	} {
+		if _, _, err := a.Authenticate(r); !errors.Is(err, ErrNoCredentials) {
+			t.Errorf("%s: err = %v, want ErrNoCredentials", a.Scheme(), err)
+		}
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"iter"
+	"mime"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/agent"
 	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
 	"github.com/lookatitude/beluga-ai/v2/tool"
 )
 
@@ -18,6 +22,7 @@ const (
 	opCreateTask = "a2a/create_task: "
 	opGetTask    = "a2a/get_task: "
 	opCancelTask = "a2a/cancel_task: "
+	opSubscribe  = "a2a/subscribe_task: "
 	opInvoke     = "a2a/invoke: "
 
 	unexpectedStatusFmt = "unexpected status %d"
@@ -27,19 +32,25 @@ const (
 type A2AClient struct {
 	baseURL    string
 	httpClient *http.Client
+	header     http.Header
 }
 
 // NewClient creates a new A2A client pointing at the given base URL.
-func NewClient(baseURL string) *A2AClient {
-	return &A2AClient{
+func NewClient(baseURL string, opts ...ClientOption) *A2AClient {
+	c := &A2AClient{
 		baseURL:    baseURL,
 		httpClient: http.DefaultClient,
+		header:     make(http.Header),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetCard retrieves the Agent Card from the remote agent.
 func (c *A2AClient) GetCard(ctx context.Context) (*AgentCard, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/.well-known/agent.json", nil)
+	req, err := c.newRequest(ctx, http.MethodGet, "/.well-known/agent.json", nil)
 	if err != nil {
 		return nil, core.Errorf(core.ErrInvalidInput, opGetCard+"%w", err)
 	}
@@ -68,7 +79,7 @@ func (c *A2AClient) CreateTask(ctx context.Context, req TaskRequest) (*Task, err
 		return nil, core.Errorf(core.ErrInvalidInput, opCreateTask+"%w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/tasks", bytes.NewReader(body))
+	httpReq, err := c.newRequest(ctx, http.MethodPost, "/tasks", bytes.NewReader(body))
 	if err != nil {
 		return nil, core.Errorf(core.ErrInvalidInput, opCreateTask+"%w", err)
 	}
@@ -80,6 +91,10 @@ func (c *A2AClient) CreateTask(ctx context.Context, req TaskRequest) (*Task, err
 	}
 	defer resp.Body.Close()
 
+	if err := authError(opCreateTask, resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusCreated {
 		var errResp ErrorResponse
 		_ = json.NewDecoder(resp.Body).Decode(&errResp)
@@ -95,7 +110,7 @@ func (c *A2AClient) CreateTask(ctx context.Context, req TaskRequest) (*Task, err
 
 // GetTask retrieves the current state of a task.
 func (c *A2AClient) GetTask(ctx context.Context, taskID string) (*Task, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/tasks/"+taskID, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, "/tasks/"+taskID, nil)
 	if err != nil {
 		return nil, core.Errorf(core.ErrInvalidInput, opGetTask+"%w", err)
 	}
@@ -106,6 +121,10 @@ func (c *A2AClient) GetTask(ctx context.Context, taskID string) (*Task, error) {
 	}
 	defer resp.Body.Close()
 
+	if err := authError(opGetTask, resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, core.Errorf(core.ErrNotFound, opGetTask+"task not found")
 	}
@@ -122,7 +141,7 @@ func (c *A2AClient) GetTask(ctx context.Context, taskID string) (*Task, error) {
 
 // CancelTask requests cancellation of a running task.
 func (c *A2AClient) CancelTask(ctx context.Context, taskID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/tasks/"+taskID+"/cancel", nil)
+	req, err := c.newRequest(ctx, http.MethodPost, "/tasks/"+taskID+"/cancel", nil)
 	if err != nil {
 		return core.Errorf(core.ErrInvalidInput, opCancelTask+"%w", err)
 	}
@@ -133,6 +152,10 @@ func (c *A2AClient) CancelTask(ctx context.Context, taskID string) error {
 	}
 	defer resp.Body.Close()
 
+	if err := authError(opCancelTask, resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		return core.Errorf(core.ErrNotFound, opCancelTask+"task not found")
 	}
@@ -143,10 +166,135 @@ func (c *A2AClient) CancelTask(ctx context.Context, taskID string) error {
 	return nil
 }
 
+// SubscribeTask returns an iterator over the states of a task, from its
+// current state until it completes, fails or is canceled. It streams updates
+// from GET /tasks/{id}/stream, yielding each status change and each update to
+// the partial output. Against servers without the streaming endpoint, or if
+// the stream drops before the task finishes, it falls back to polling
+// GetTask and yields the task whenever it changes.
+func (c *A2AClient) SubscribeTask(ctx context.Context, taskID string) iter.Seq2[Task, error] {
+	return func(yield func(Task, error) bool) {
+		last, done, ok := c.streamTask(ctx, taskID, yield)
+		if done || !ok {
+			return
+		}
+		c.pollTask(ctx, taskID, last, yield)
+	}
+}
+
+// streamTask yields the task states streamed by the server. It returns the
+// last state yielded, whether the stream delivered a final state, and false
+// if iteration must stop. Responses other than an event stream yield
+// nothing, leaving the caller to poll.
+func (c *A2AClient) streamTask(ctx context.Context, taskID string, yield func(Task, error) bool) (Task, bool, bool) {
+	var last Task
+	req, err := c.newRequest(ctx, http.MethodGet, "/tasks/"+taskID+"/stream", nil)
+	if err != nil {
+		yield(Task{}, core.Errorf(core.ErrInvalidInput, opSubscribe+"%w", err))
+		return last, false, false
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			yield(Task{}, ctx.Err())
+			return last, false, false
+		}
+		return last, false, true
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get(contentTypeHeader))
+	if resp.StatusCode != http.StatusOK || mediaType != "text/event-stream" {
+		return last, false, true
+	}
+
+	for event, err := range httpclient.ScanSSE(ctx, resp.Body) {
+		if err != nil {
+			if ctx.Err() != nil {
+				yield(Task{}, ctx.Err())
+				return last, false, false
+			}
+			// The connection dropped; resume by polling.
+			return last, false, true
+		}
+		if event.Event != "task" {
+			continue
+		}
+		var taskResp TaskResponse
+		if err := json.Unmarshal([]byte(event.Data), &taskResp); err != nil {
+			yield(Task{}, core.Errorf(core.ErrProviderDown, opSubscribe+"decode event: %w", err))
+			return last, false, false
+		}
+		last = taskResp.Task
+		if !yield(last, nil) {
+			return last, false, false
+		}
+		if last.Status.Terminal() {
+			return last, true, true
+		}
+	}
+	if ctx.Err() != nil {
+		yield(Task{}, ctx.Err())
+		return last, false, false
+	}
+	return last, false, true
+}
+
+// pollTask polls GetTask with exponential backoff, yielding the task each
+// time its status or output differs from last, until it is finished.
+func (c *A2AClient) pollTask(ctx context.Context, taskID string, last Task, yield func(Task, error) bool) {
+	delay := 100 * time.Millisecond
+	const maxDelay = 5 * time.Second
+
+	for first := true; ; first = false {
+		if !first {
+			select {
+			case <-ctx.Done():
+				yield(Task{}, ctx.Err())
+				return
+			case <-time.After(delay):
+			}
+			// Exponential backoff, capped at maxDelay.
+			delay = min(delay*2, maxDelay)
+		}
+
+		task, err := c.GetTask(ctx, taskID)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			yield(Task{}, err)
+			return
+		}
+		if task.Status != last.Status || task.Output != last.Output {
+			last = *task
+			if !yield(last, nil) {
+				return
+			}
+		}
+		if task.Status.Terminal() {
+			return
+		}
+	}
+}
+
+// invokeCode returns the error code for a failed remote invocation: ErrAuth
+// when the server rejected the client's credentials, else ErrProviderDown.
+func invokeCode(err error) core.ErrorCode {
+	var coreErr *core.Error
+	if errors.As(err, &coreErr) && coreErr.Code == core.ErrAuth {
+		return core.ErrAuth
+	}
+	return core.ErrProviderDown
+}
+
 // NewRemoteAgent wraps an A2A endpoint as a local agent.Agent.
-// It fetches the Agent Card to populate the agent's identity.
-func NewRemoteAgent(baseURL string) (agent.Agent, error) {
-	client := NewClient(baseURL)
+// It fetches the Agent Card to populate the agent's identity. Options
+// configure the underlying client, for example its credentials.
+func NewRemoteAgent(baseURL string, opts ...ClientOption) (agent.Agent, error) {
+	client := NewClient(baseURL, opts...)
 	ctx := context.Background()
 
 	card, err := client.GetCard(ctx)
@@ -178,60 +326,83 @@ func (a *remoteAgent) Persona() agent.Persona {
 func (a *remoteAgent) Tools() []tool.Tool      { return nil }
 func (a *remoteAgent) Children() []agent.Agent { return nil }
 
-func (a *remoteAgent) Invoke(ctx context.Context, input string, _ ...agent.Option) (string, error) {
-	task, err := a.client.CreateTask(ctx, TaskRequest{Input: input})
-	if err != nil {
-		return "", core.Errorf(core.ErrProviderDown, opInvoke+"%w", err)
-	}
-
-	// Poll until terminal state with exponential backoff.
-	delay := 100 * time.Millisecond
-	const maxDelay = 5 * time.Second
-
-	for {
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(delay):
-		}
-
-		task, err = a.client.GetTask(ctx, task.ID)
+func (a *remoteAgent) Invoke(ctx context.Context, input string, opts ...agent.Option) (string, error) {
+	var output strings.Builder
+	for event, err := range a.Stream(ctx, input, opts...) {
 		if err != nil {
-			return "", core.Errorf(core.ErrProviderDown, opInvoke+"%w", err)
+			return "", err
 		}
-
-		switch task.Status {
-		case StatusCompleted:
-			return task.Output, nil
-		case StatusFailed:
-			return "", core.Errorf(core.ErrProviderDown, opInvoke+"task failed: %s", task.Error)
-		case StatusCanceled:
-			return "", core.Errorf(core.ErrTimeout, opInvoke+"task canceled")
-		}
-
-		// Exponential backoff, capped at maxDelay.
-		delay = delay * 2
-		if delay > maxDelay {
-			delay = maxDelay
+		if event.Type == agent.EventText {
+			output.WriteString(event.Text)
 		}
 	}
+	return output.String(), nil
 }
 
-func (a *remoteAgent) Stream(ctx context.Context, input string, opts ...agent.Option) iter.Seq2[agent.Event, error] {
+// Stream creates a task and yields its output as it is produced, followed by
+// a done event once the task completes. Content parts attached to ctx with
+// WithInputParts are sent with the task, uploading large data as artifacts.
+// The task's output parts are resolved, added to the Outputs attached to ctx
+// with WithOutputs, and set as the done event's "parts" metadata.
+func (a *remoteAgent) Stream(ctx context.Context, input string, _ ...agent.Option) iter.Seq2[agent.Event, error] {
 	return func(yield func(agent.Event, error) bool) {
-		result, err := a.Invoke(ctx, input, opts...)
+		req := TaskRequest{Input: input}
+		if parts := InputParts(ctx); len(parts) > 0 {
+			wire, err := a.client.UploadParts(ctx, parts)
+			if err != nil {
+				yield(agent.Event{}, core.Errorf(core.ErrInvalidInput, opInvoke+"%w", err))
+				return
+			}
+			req.Parts = wire
+		}
+
+		task, err := a.client.CreateTask(ctx, req)
 		if err != nil {
-			yield(agent.Event{}, err)
+			yield(agent.Event{}, core.Errorf(invokeCode(err), opInvoke+"%w", err))
 			return
 		}
-		yield(agent.Event{
-			Type:    agent.EventText,
-			Text:    result,
-			AgentID: a.ID(),
-		}, nil)
-		yield(agent.Event{
-			Type:    agent.EventDone,
-			AgentID: a.ID(),
-		}, nil)
+
+		var sent string // output already yielded
+		for t, err := range a.client.SubscribeTask(ctx, task.ID) {
+			if err != nil {
+				if ctx.Err() != nil {
+					yield(agent.Event{}, ctx.Err())
+					return
+				}
+				yield(agent.Event{}, core.Errorf(invokeCode(err), opInvoke+"%w", err))
+				return
+			}
+			if len(t.Output) > len(sent) && strings.HasPrefix(t.Output, sent) {
+				if !yield(agent.Event{Type: agent.EventText, Text: t.Output[len(sent):], AgentID: a.ID()}, nil) {
+					return
+				}
+				sent = t.Output
+			}
+
+			switch t.Status {
+			case StatusCompleted:
+				done := agent.Event{Type: agent.EventDone, AgentID: a.ID()}
+				if len(t.OutputParts) > 0 {
+					parts, err := a.client.ResolveParts(ctx, t.OutputParts)
+					if err != nil {
+						yield(agent.Event{}, core.Errorf(invokeCode(err), opInvoke+"%w", err))
+						return
+					}
+					AddOutputParts(ctx, parts...)
+					done.Metadata = map[string]any{"parts": parts}
+				}
+				yield(done, nil)
+				return
+			case StatusFailed:
+				yield(agent.Event{}, core.Errorf(core.ErrProviderDown, opInvoke+"task failed: %s", t.Error))
+				return
+			case StatusCanceled:
+				yield(agent.Event{}, core.Errorf(core.ErrTimeout, opInvoke+"task canceled"))
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			yield(agent.Event{}, ctx.Err())
+		}
 	}
 }
@@ -25,7 +25,52 @@
 //   - GET  /.well-known/agent.json — returns the Agent Card
 //   - POST /tasks — creates a new task
 //   - GET  /tasks/{id} — returns task status
+//   - GET  /tasks/{id}/stream — streams status changes and partial output as
+//     Server-Sent Events until the task finishes
 //   - POST /tasks/{id}/cancel — cancels a running task
+//   - POST /artifacts — stores the request body as an artifact
+//   - GET  /artifacts/{id} — returns the content of an artifact
+//
+// # Persistence and Recovery
+//
+// The server saves each task to a TaskStore when it is created, starts and
+// finishes; finished tasks, with their results, are then served from the
+// store for WithTaskTTL (DefaultTaskTTL, 24 hours, by default). The default
+// InMemoryTaskStore does not survive restarts; WithTaskStore with a
+// persistent store, such as the database/sql implementation in the
+// sqlstore subpackage, does. On start-up Serve calls Recover, which runs
+// tasks that were still submitted or working again from their input, so
+// clients polling GetTask see them complete instead of receiving 404.
+//
+//	store, err := sqlstore.New(sqlstore.Config{DB: db})
+//	srv := a2a.NewServer(myAgent, card, a2a.WithTaskStore(store), a2a.WithTaskTTL(7*24*time.Hour))
+//
+// # Authentication
+//
+// By default anyone who can reach the server may submit tasks. Before
+// exposing an agent across a trust boundary, require credentials with
+// WithAuthenticators: NewBearerAuthenticator verifies bearer tokens (for
+// example with an auth.JWTPolicy), NewAPIKeyAuthenticator checks an API-key
+// header and NewMTLSAuthenticator accepts verified TLS client certificates
+// (serve with WithTLSConfig). Every endpoint except the Agent Card then
+// answers 401 Unauthorized to requests without valid credentials, and tasks
+// and artifacts are visible only to the caller that created them.
+//
+// WithAuthPolicy additionally authorizes task creation and artifact upload
+// with an auth.Policy, checking auth.PermAgentDelegate on the agent;
+// denied requests receive 403 Forbidden. The card advertises the accepted
+// schemes in AuthSchemes.
+//
+//	srv := a2a.NewServer(myAgent, card,
+//	    a2a.WithAuthenticators(a2a.NewBearerAuthenticator(jwtPolicy)),
+//	    a2a.WithAuthPolicy(rbac),
+//	)
+//
+// Clients send credentials with WithBearerToken or WithAPIKey, or present a
+// client certificate through WithHTTPClient; rejected requests return
+// core.ErrAuth errors:
+//
+//	remote, err := a2a.NewRemoteAgent(url, a2a.WithBearerToken(token))
 //
 // # Client
 //
@@ -37,14 +82,77 @@
 //	task, err := client.CreateTask(ctx, a2a.TaskRequest{Input: "Hello"})
 //	task, err = client.GetTask(ctx, task.ID)
 //
+// SubscribeTask follows a task in real time over the streaming endpoint,
+// falling back to polling against servers without it:
+//
+//	for t, err := range client.SubscribeTask(ctx, task.ID) {
+//	    if err != nil { break }
+//	    fmt.Println(t.Status, t.Output)
+//	}
+//
 // # Remote Agent
 //
 // NewRemoteAgent wraps an A2A endpoint as a local agent.Agent, enabling
-// transparent use of remote agents in local orchestration:
+// transparent use of remote agents in local orchestration. Its Stream method
+// yields the task output as the remote agent produces it:
 //
 //	remote, err := a2a.NewRemoteAgent("http://localhost:9090")
 //	result, err := remote.Invoke(ctx, "Hello")
 //
+// # Routing
+//
+// A Router dispatches tasks among several remote agents by the skills their
+// Agent Cards advertise. It picks a skill for the task's input with a
+// Classifier — by default KeywordClassifier, which matches skill tags
+// (AgentSkill.Tags) and names against the input; LLMClassifier asks a chat
+// model instead. Agents offering the same skill are rotated round-robin,
+// and when one fails the router tries the next, then the agents added with
+// WithFallback, which also receive tasks that match no skill:
+//
+//	router, err := a2a.NewRouter(ctx, []string{mathURL1, mathURL2, translateURL},
+//	    a2a.WithClassifier(a2a.LLMClassifier(model)),
+//	    a2a.WithFallback(generalURL),
+//	)
+//	routed, err := router.RouteTask(ctx, a2a.TaskRequest{Input: "Solve 2x + 3 = 7"})
+//
+// Router implements agent.Agent, so it can be used in local orchestration
+// like any other agent.
+//
+// # Content Parts and Artifacts
+//
+// Besides text, a task carries schema.ContentPart values — text, image,
+// audio, video, file and thinking parts — in TaskRequest.Parts and
+// Task.OutputParts, in the wire form Part. Binary data up to
+// DefaultInlineLimit (64 KiB) is inlined as base64; larger data is uploaded
+// as an artifact and referenced by Part.ArtifactID. UploadParts and
+// ResolveParts convert parts in either direction, moving data to and from
+// artifacts as needed.
+//
+// The agent serving a task reads its input parts with InputParts and returns
+// output parts with AddOutputParts; the server stores output data above its
+// inline limit (WithInlineLimit) as artifacts. The remote agent maps parts
+// the same way, so parts flow through agent.Agent's Invoke signature:
+//
+//	outputs := &a2a.Outputs{}
+//	ctx = a2a.WithOutputs(a2a.WithInputParts(ctx, schema.FilePart{Data: pdf, Name: "report.pdf", MimeType: "application/pdf"}), outputs)
+//	summary, err := remote.Invoke(ctx, "Summarize the attached report")
+//	charts := outputs.Parts()
+//
+// # Size Limits and Content Types
+//
+// Uploaded artifacts and task request bodies are limited to
+// DefaultMaxArtifactSize (32 MiB) unless changed with WithMaxArtifactSize;
+// larger requests are rejected with 413 Request Entity Too Large. Artifacts
+// are stored in memory for the lifetime of the server and are not
+// persisted with tasks.
+//
+// An artifact's MIME type is the Content-Type of its upload, which must be a
+// valid media type and defaults to application/octet-stream; output data
+// without a known type is sniffed with http.DetectContentType. Downloads are
+// served with the recorded Content-Type, "X-Content-Type-Options: nosniff"
+// and, for named artifacts, an attachment Content-Disposition, so browsers
+// do not render untrusted content inline.
+//
 // # Key Types
 //
 //   - A2AServer — serves a Beluga agent via the A2A protocol
@@ -52,5 +160,10 @@
 //   - AgentCard — describes a remote agent's identity and capabilities
 //   - Task — represents an A2A task with lifecycle state
 //   - TaskStatus — lifecycle state (submitted, working, completed, failed, canceled)
+//   - TaskStore / InMemoryTaskStore — task persistence for recovery
+//   - Authenticator — verifies request credentials (bearer, API key, mTLS)
+//   - Router — routes tasks among remote agents by skill
+//   - Part — wire form of a content part, inline or by artifact reference
+//   - Artifact — metadata of stored binary content
 //   - TaskRequest / TaskResponse / ErrorResponse — API message types
 package a2a
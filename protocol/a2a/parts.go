@@ -0,0 +1,156 @@
+package a2a
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// DefaultInlineLimit is the largest part data, in bytes, that is inlined in
+// task JSON. Larger data is sent as an artifact and referenced by ID.
+const DefaultInlineLimit = 64 << 10
+
+// Part is the wire form of a schema.ContentPart. Binary data is inlined as
+// base64 in Data, or stored as an artifact and referenced by ArtifactID.
+type Part struct {
+	Type       schema.ContentType `json:"type"`
+	Text       string             `json:"text,omitempty"`
+	Data       []byte             `json:"data,omitempty"`
+	ArtifactID string             `json:"artifact_id,omitempty"`
+	Size       int64              `json:"size,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	MimeType   string             `json:"mime_type,omitempty"`
+	URL        string             `json:"url,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	SampleRate int                `json:"sample_rate,omitempty"`
+}
+
+// NewPart converts a content part to its wire form. Text, image, audio,
+// video, file and thinking parts are supported; other parts return an
+// ErrInvalidInput error.
+func NewPart(p schema.ContentPart) (Part, error) {
+	switch p := p.(type) {
+	case schema.TextPart:
+		return Part{Type: schema.ContentText, Text: p.Text}, nil
+	case schema.ImagePart:
+		return Part{Type: schema.ContentImage, Data: p.Data, MimeType: p.MimeType, URL: p.URL}, nil
+	case schema.AudioPart:
+		return Part{Type: schema.ContentAudio, Data: p.Data, Format: p.Format, SampleRate: p.SampleRate}, nil
+	case schema.VideoPart:
+		return Part{Type: schema.ContentVideo, Data: p.Data, MimeType: p.MimeType, URL: p.URL}, nil
+	case schema.FilePart:
+		return Part{Type: schema.ContentFile, Data: p.Data, Name: p.Name, MimeType: p.MimeType}, nil
+	case schema.ThinkingPart:
+		return Part{Type: schema.ContentThinking, Text: p.Text}, nil
+	case nil:
+		return Part{}, core.Errorf(core.ErrInvalidInput, "a2a: nil content part")
+	}
+	return Part{}, core.Errorf(core.ErrInvalidInput, "a2a: unsupported content part %T", p)
+}
+
+// ContentPart converts p to a schema.ContentPart. Parts that reference an
+// artifact must be resolved first (see A2AClient.ResolveParts); otherwise
+// their data is empty.
+func (p Part) ContentPart() (schema.ContentPart, error) {
+	switch p.Type {
+	case schema.ContentText:
+		return schema.TextPart{Text: p.Text}, nil
+	case schema.ContentImage:
+		return schema.ImagePart{Data: p.Data, MimeType: p.MimeType, URL: p.URL}, nil
+	case schema.ContentAudio:
+		return schema.AudioPart{Data: p.Data, Format: p.Format, SampleRate: p.SampleRate}, nil
+	case schema.ContentVideo:
+		return schema.VideoPart{Data: p.Data, MimeType: p.MimeType, URL: p.URL}, nil
+	case schema.ContentFile:
+		return schema.FilePart{Data: p.Data, Name: p.Name, MimeType: p.MimeType}, nil
+	case schema.ContentThinking:
+		return schema.ThinkingPart{Text: p.Text}, nil
+	}
+	return nil, core.Errorf(core.ErrInvalidInput, "a2a: unsupported part type %q", p.Type)
+}
+
+// NewParts converts content parts to their wire form.
+func NewParts(parts []schema.ContentPart) ([]Part, error) {
+	out := make([]Part, 0, len(parts))
+	for _, p := range parts {
+		wp, err := NewPart(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, wp)
+	}
+	return out, nil
+}
+
+// ContentParts converts wire parts to content parts.
+func ContentParts(parts []Part) ([]schema.ContentPart, error) {
+	out := make([]schema.ContentPart, 0, len(parts))
+	for _, p := range parts {
+		cp, err := p.ContentPart()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cp)
+	}
+	return out, nil
+}
+
+type inputPartsKey struct{}
+
+type outputsKey struct{}
+
+// WithInputParts returns a context carrying content parts that accompany an
+// agent's string input. The A2A server sets them for the agent serving a
+// task, and the remote agent returned by NewRemoteAgent sends them with the
+// task it creates.
+func WithInputParts(ctx context.Context, parts ...schema.ContentPart) context.Context {
+	return context.WithValue(ctx, inputPartsKey{}, parts)
+}
+
+// InputParts returns the content parts set with WithInputParts, if any.
+func InputParts(ctx context.Context) []schema.ContentPart {
+	parts, _ := ctx.Value(inputPartsKey{}).([]schema.ContentPart)
+	return parts
+}
+
+// Outputs collects content parts produced alongside an agent's text output.
+// It is safe for concurrent use.
+type Outputs struct {
+	mu    sync.Mutex
+	parts []schema.ContentPart
+}
+
+// Add appends parts to the outputs.
+func (o *Outputs) Add(parts ...schema.ContentPart) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.parts = append(o.parts, parts...)
+}
+
+// Parts returns the collected parts.
+func (o *Outputs) Parts() []schema.ContentPart {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]schema.ContentPart(nil), o.parts...)
+}
+
+// WithOutputs returns a context that collects output parts into o. The A2A
+// server sets it for the agent serving a task and returns the parts as the
+// task's OutputParts; the remote agent adds the output parts of its task
+// to it.
+func WithOutputs(ctx context.Context, o *Outputs) context.Context {
+	return context.WithValue(ctx, outputsKey{}, o)
+}
+
+// AddOutputParts adds parts to the Outputs of ctx. It reports false, and
+// does nothing, when ctx has no Outputs.
+func AddOutputParts(ctx context.Context, parts ...schema.ContentPart) bool {
+	o, ok := ctx.Value(outputsKey{}).(*Outputs)
+	if !ok {
+		return false
+	}
+	o.Add(parts...)
+	return true
+}
@@ -0,0 +1,449 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/llm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+const opRoute = "a2a/route: "
+
+// Classifier picks the skill best suited to a task's input from the skills
+// advertised by a Router's agents. It returns the skill's name, or "" when
+// no skill fits.
+type Classifier func(ctx context.Context, input string, skills []AgentSkill) (string, error)
+
+// KeywordClassifier returns the default Classifier. It picks the skill
+// whose tags and name words occur most in the input, as whole words and
+// ignoring case; a matching tag counts twice as much as a name word. Ties
+// go to the first skill.
+func KeywordClassifier() Classifier {
+	return func(_ context.Context, input string, skills []AgentSkill) (string, error) {
+		words := splitWords(input)
+		best, bestScore := "", 0
+		for _, skill := range skills {
+			score := 0
+			for _, tag := range skill.Tags {
+				if containsPhrase(words, splitWords(tag)) {
+					score += 2
+				}
+			}
+			for _, w := range uniqueWords(skill.Name) {
+				if containsPhrase(words, []string{w}) {
+					score++
+				}
+			}
+			if score > bestScore {
+				best, bestScore = skill.Name, score
+			}
+		}
+		return best, nil
+	}
+}
+
+// LLMClassifier returns a Classifier that asks model to pick the skill for
+// the input from the skills' names, descriptions and tags. Replies that
+// name no offered skill select none.
+func LLMClassifier(model llm.ChatModel) Classifier {
+	return func(ctx context.Context, input string, skills []AgentSkill) (string, error) {
+		var b strings.Builder
+		b.WriteString("You route tasks to the agent skill best suited to perform them. Available skills:\n")
+		for _, s := range skills {
+			fmt.Fprintf(&b, "- %s: %s", s.Name, s.Description)
+			if len(s.Tags) > 0 {
+				fmt.Fprintf(&b, " (tags: %s)", strings.Join(s.Tags, ", "))
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("Reply with the name of the best skill only, or \"none\" if no skill fits.")
+
+		resp, err := model.Generate(ctx, []schema.Message{
+			schema.NewSystemMessage(b.String()),
+			schema.NewHumanMessage(input),
+		})
+		if err != nil {
+			return "", err
+		}
+		reply := strings.Trim(strings.TrimSpace(resp.Text()), "\"'`.")
+		for _, s := range skills {
+			if strings.EqualFold(reply, s.Name) {
+				return s.Name, nil
+			}
+		}
+		return "", nil
+	}
+}
+
+// RouterOption configures a Router.
+type RouterOption func(*Router)
+
+// WithClassifier sets how the router picks a skill for a task. The default
+// is KeywordClassifier.
+func WithClassifier(c Classifier) RouterOption {
+	return func(r *Router) {
+		if c != nil {
+			r.classifier = c
+		}
+	}
+}
+
+// WithFallback routes tasks to the agent at endpoint when no skill fits
+// and, after the matching agents, when they all fail. Several fallbacks are
+// tried in the order given.
+func WithFallback(endpoint string) RouterOption {
+	return func(r *Router) {
+		r.fallbacks = append(r.fallbacks, endpoint)
+	}
+}
+
+// WithRouterClientOptions configures the clients of the remote agents, for
+// example with credentials.
+func WithRouterClientOptions(opts ...ClientOption) RouterOption {
+	return func(r *Router) {
+		r.clientOpts = append(r.clientOpts, opts...)
+	}
+}
+
+// WithRouterName sets the router's agent ID. The default is "a2a-router".
+func WithRouterName(name string) RouterOption {
+	return func(r *Router) {
+		r.name = name
+	}
+}
+
+// route is a remote agent known to a Router.
+type route struct {
+	endpoint string
+	remote   *remoteAgent
+}
+
+// Router dispatches tasks among several remote A2A agents by the skills
+// their Agent Cards advertise. Agents offering the chosen skill are
+// equivalent: the router rotates among them to spread load and fails over
+// to the next, then to the fallbacks, when one fails before producing
+// output.
+//
+// Router implements agent.Agent, so it can be used wherever a local agent
+// can. It is safe for concurrent use.
+type Router struct {
+	name       string
+	endpoints  []string
+	fallbacks  []string
+	classifier Classifier
+	clientOpts []ClientOption
+
+	mu     sync.RWMutex
+	routes map[string]*route // by endpoint
+	next   atomic.Uint64
+}
+
+// Compile-time interface check.
+var _ agent.Agent = (*Router)(nil)
+
+// NewRouter creates a Router over the agents at endpoints and fetches
+// their Agent Cards. Endpoints whose card cannot be fetched are left out
+// until the next Refresh; it fails only when no card can be fetched.
+func NewRouter(ctx context.Context, endpoints []string, opts ...RouterOption) (*Router, error) {
+	r := &Router{
+		name:       "a2a-router",
+		endpoints:  endpoints,
+		classifier: KeywordClassifier(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if err := r.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Refresh fetches the Agent Cards of the router's endpoints again,
+// replacing the skill index.
+func (r *Router) Refresh(ctx context.Context) error {
+	routes := make(map[string]*route)
+	var errs []error
+	for _, ep := range append(append([]string(nil), r.endpoints...), r.fallbacks...) {
+		if _, ok := routes[ep]; ok {
+			continue
+		}
+		client := NewClient(ep, r.clientOpts...)
+		card, err := client.GetCard(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		routes[ep] = &route{endpoint: ep, remote: &remoteAgent{client: client, card: *card}}
+	}
+	if len(routes) == 0 {
+		return core.Errorf(core.ErrProviderDown, opRoute+"no agent reachable: %w", errors.Join(errs...))
+	}
+
+	r.mu.Lock()
+	r.routes = routes
+	r.mu.Unlock()
+	return nil
+}
+
+// Agents returns the Agent Cards of the reachable agents in endpoint order.
+func (r *Router) Agents() []AgentCard {
+	var cards []AgentCard
+	for _, rt := range r.ordered() {
+		cards = append(cards, rt.remote.card)
+	}
+	return cards
+}
+
+// Skills returns the skills advertised by the reachable agents, once per
+// name (ignoring case). Skills offered by several agents have the tags of
+// all of them and the first non-empty description.
+func (r *Router) Skills() []AgentSkill {
+	var skills []AgentSkill
+	index := make(map[string]int)
+	for _, rt := range r.ordered() {
+		for _, s := range rt.remote.card.Skills {
+			key := strings.ToLower(s.Name)
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(skills)
+				s.Tags = slices.Clone(s.Tags)
+				skills = append(skills, s)
+				continue
+			}
+			if skills[i].Description == "" {
+				skills[i].Description = s.Description
+			}
+			for _, tag := range s.Tags {
+				if !slices.Contains(skills[i].Tags, tag) {
+					skills[i].Tags = append(skills[i].Tags, tag)
+				}
+			}
+		}
+	}
+	return skills
+}
+
+// RoutedTask is a task dispatched by a Router.
+type RoutedTask struct {
+	// Task is the created task.
+	Task Task
+
+	// Agent is the card of the agent running the task.
+	Agent AgentCard
+
+	// Client is connected to the agent running the task; use it to follow
+	// or cancel the task.
+	Client *A2AClient
+}
+
+// RouteTask selects the agents best suited to req and creates the task on
+// the first that accepts it.
+func (r *Router) RouteTask(ctx context.Context, req TaskRequest) (*RoutedTask, error) {
+	candidates, err := r.candidates(ctx, req.Input)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, rt := range candidates {
+		task, err := rt.remote.client.CreateTask(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			errs = append(errs, err)
+			continue
+		}
+		return &RoutedTask{Task: *task, Agent: rt.remote.card, Client: rt.remote.client}, nil
+	}
+	return nil, core.Errorf(core.ErrProviderDown, opRoute+"all agents failed: %w", errors.Join(errs...))
+}
+
+// ID returns the router's name.
+func (r *Router) ID() string { return r.name }
+
+// Persona describes the router.
+func (r *Router) Persona() agent.Persona {
+	return agent.Persona{
+		Role: r.name,
+		Goal: "Route each task to the remote agent whose skills suit it best",
+	}
+}
+
+// Tools returns nil; the router has no tools of its own.
+func (r *Router) Tools() []tool.Tool { return nil }
+
+// Children returns the remote agents the router dispatches to.
+func (r *Router) Children() []agent.Agent {
+	var children []agent.Agent
+	for _, rt := range r.ordered() {
+		children = append(children, rt.remote)
+	}
+	return children
+}
+
+// Invoke routes input to a remote agent and returns its output.
+func (r *Router) Invoke(ctx context.Context, input string, opts ...agent.Option) (string, error) {
+	var output strings.Builder
+	for event, err := range r.Stream(ctx, input, opts...) {
+		if err != nil {
+			return "", err
+		}
+		if event.Type == agent.EventText {
+			output.WriteString(event.Text)
+		}
+	}
+	return output.String(), nil
+}
+
+// Stream routes input to a remote agent and yields its events. When an
+// agent fails before yielding any event, the next candidate is tried.
+func (r *Router) Stream(ctx context.Context, input string, opts ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		candidates, err := r.candidates(ctx, input)
+		if err != nil {
+			yield(agent.Event{}, err)
+			return
+		}
+
+		var errs []error
+		for _, rt := range candidates {
+			started := false
+			var failed error
+			for event, err := range rt.remote.Stream(ctx, input, opts...) {
+				if err != nil && !started && ctx.Err() == nil {
+					failed = err
+					break
+				}
+				started = true
+				if !yield(event, err) || err != nil {
+					return
+				}
+			}
+			if failed == nil {
+				return
+			}
+			errs = append(errs, failed)
+		}
+		if ctx.Err() != nil {
+			yield(agent.Event{}, ctx.Err())
+			return
+		}
+		yield(agent.Event{}, core.Errorf(core.ErrProviderDown, opRoute+"all agents failed: %w", errors.Join(errs...)))
+	}
+}
+
+// candidates returns the agents to try for input in order: the agents
+// offering the classified skill, rotated for load balancing, then the
+// fallbacks.
+func (r *Router) candidates(ctx context.Context, input string) ([]*route, error) {
+	skill, err := r.classifier(ctx, input, r.Skills())
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, opRoute+"classifier: %w", err)
+	}
+
+	var matched []*route
+	if skill != "" {
+		for _, rt := range r.ordered() {
+			if offersSkill(rt.remote.card, skill) {
+				matched = append(matched, rt)
+			}
+		}
+	}
+	if n := len(matched); n > 1 {
+		start := int(r.next.Add(1) % uint64(n))
+		matched = append(matched[start:], matched[:start]...)
+	}
+
+	r.mu.RLock()
+	for _, ep := range r.fallbacks {
+		if rt, ok := r.routes[ep]; ok && !slices.Contains(matched, rt) {
+			matched = append(matched, rt)
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(matched) == 0 {
+		if skill == "" {
+			return nil, core.Errorf(core.ErrNotFound, opRoute+"no agent has a skill for the task and no fallback is set")
+		}
+		return nil, core.Errorf(core.ErrNotFound, opRoute+"no agent offers skill %q and no fallback is set", skill)
+	}
+	return matched, nil
+}
+
+// ordered returns the reachable routes in endpoint order.
+func (r *Router) ordered() []*route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []*route
+	for _, ep := range r.endpoints {
+		if rt, ok := r.routes[ep]; ok && !slices.Contains(out, rt) {
+			out = append(out, rt)
+		}
+	}
+	return out
+}
+
+// offersSkill reports whether card lists the named skill, ignoring case.
+func offersSkill(card AgentCard, name string) bool {
+	for _, s := range card.Skills {
+		if strings.EqualFold(s.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords returns the lowercase words of s, splitting on anything other
+// than letters and digits.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// uniqueWords returns the distinct words of s.
+func uniqueWords(s string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, w := range splitWords(s) {
+		if !seen[w] {
+			seen[w] = true
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// containsPhrase reports whether phrase occurs as consecutive words in
+// words.
+func containsPhrase(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, w := range phrase {
+			if words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/testutil/mockllm"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// newSkillServer starts an agent named name offering skills, replying
+// "<name>: <input>".
+func newSkillServer(t *testing.T, name string, skills ...AgentSkill) string {
+	t.Helper()
+	a := &mockAgent{id: name, invokeFn: func(_ context.Context, input string) (string, error) {
+		return name + ": " + input, nil
+	}}
+	ts := httptest.NewServer(NewServer(a, AgentCard{Name: name, Skills: skills}).Handler())
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+var (
+	translateSkill = AgentSkill{Name: "translate", Description: "Translates text", Tags: []string{"translation", "french"}}
+	mathSkill      = AgentSkill{Name: "math", Description: "Solves equations", Tags: []string{"equation", "solve"}}
+)
+
+func TestKeywordClassifier(t *testing.T) {
+	classify := KeywordClassifier()
+	skills := []AgentSkill{translateSkill, mathSkill, {Name: "code review", Tags: []string{"pull request"}}}
+	tests := []struct {
+		input, want string
+	}{
+		{"Please solve this equation: x+1=2", "math"},
+		{"Translate 'hello' to French", "translate"},
+		{"Review this pull request", "code review"},
+		{"Write a poem", ""},
+		{"mathematics", ""}, // whole words only
+	}
+	for _, tt := range tests {
+		got, err := classify(context.Background(), tt.input, skills)
+		if err != nil {
+			t.Fatalf("classify: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("classify(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLLMClassifier(t *testing.T) {
+	model := mockllm.New(mockllm.WithResponse(schema.NewAIMessage(" Math.\n")))
+	got, err := LLMClassifier(model)(context.Background(), "what is 2+2", []AgentSkill{translateSkill, mathSkill})
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+	if got != "math" {
+		t.Errorf("skill = %q, want math", got)
+	}
+	if prompt := model.LastMessages()[0].(*schema.SystemMessage).Text(); !strings.Contains(prompt, "- translate: Translates text (tags: translation, french)") {
+		t.Errorf("prompt missing skills:\n%s", prompt)
+	}
+
+	model.SetResponse(schema.NewAIMessage("none"))
+	if got, _ := LLMClassifier(model)(context.Background(), "poem", []AgentSkill{mathSkill}); got != "" {
+		t.Errorf("skill = %q, want none", got)
+	}
+
+	model.SetError(errors.New("boom"))
+	if _, err := LLMClassifier(model)(context.Background(), "x", []AgentSkill{mathSkill}); err == nil {
+		t.Error("expected model error")
+	}
+}
+
+func TestRouter_Invoke(t *testing.T) {
+	translator := newSkillServer(t, "translator", translateSkill)
+	calculator := newSkillServer(t, "calculator", mathSkill)
+
+	r, err := NewRouter(context.Background(), []string{translator, calculator})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	if len(r.Agents()) != 2 || len(r.Skills()) != 2 || len(r.Children()) != 2 {
+		t.Errorf("agents = %v, skills = %v", r.Agents(), r.Skills())
+	}
+
+	out, err := r.Invoke(context.Background(), "solve the equation 2x = 4")
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out != "calculator: solve the equation 2x = 4" {
+		t.Errorf("output = %q", out)
+	}
+
+	_, err = r.Invoke(context.Background(), "write a poem")
+	assertCode(t, err, core.ErrNotFound)
+}
+
+func TestRouter_LoadBalancing(t *testing.T) {
+	a := newSkillServer(t, "a", mathSkill)
+	b := newSkillServer(t, "b", mathSkill)
+
+	r, err := NewRouter(context.Background(), []string{a, b})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	counts := make(map[string]int)
+	for range 4 {
+		routed, err := r.RouteTask(context.Background(), TaskRequest{Input: "do some math"})
+		if err != nil {
+			t.Fatalf("RouteTask: %v", err)
+		}
+		counts[routed.Agent.Name]++
+	}
+	if counts["a"] != 2 || counts["b"] != 2 {
+		t.Errorf("tasks per agent = %v, want 2 each", counts)
+	}
+}
+
+func TestRouter_Fallback(t *testing.T) {
+	calculator := newSkillServer(t, "calculator", mathSkill)
+	general := newSkillServer(t, "general")
+
+	// The second math agent is reachable for its card but then fails.
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/agent.json" {
+			_, _ = w.Write([]byte(`{"name":"broken","skills":[{"name":"math"}]}`))
+			return
+		}
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	r, err := NewRouter(context.Background(), []string{broken.URL, calculator}, WithFallback(general))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	// Whichever math agent is tried first, the task ends on calculator.
+	for range 2 {
+		out, err := r.Invoke(context.Background(), "solve x")
+		if err != nil {
+			t.Fatalf("Invoke: %v", err)
+		}
+		if out != "calculator: solve x" {
+			t.Errorf("output = %q", out)
+		}
+	}
+
+	out, err := r.Invoke(context.Background(), "write a poem")
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if out != "general: write a poem" {
+		t.Errorf("fallback output = %q", out)
+	}
+}
+
+func TestRouter_AllFail(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/agent.json" {
+			_, _ = w.Write([]byte(`{"name":"broken","skills":[{"name":"math"}]}`))
+			return
+		}
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	r, err := NewRouter(context.Background(), []string{broken.URL})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	_, err = r.RouteTask(context.Background(), TaskRequest{Input: "do some math"})
+	assertCode(t, err, core.ErrProviderDown)
+	_, err = r.Invoke(context.Background(), "do some math")
+	assertCode(t, err, core.ErrProviderDown)
+}
+
+func TestNewRouter_Unreachable(t *testing.T) {
+	_, err := NewRouter(context.Background(), []string{"http://127.0.0.1:0"})
+	assertCode(t, err, core.ErrProviderDown)
+}
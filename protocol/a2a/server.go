@@ -2,16 +2,22 @@ package a2a
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/auth"
 	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
 const (
@@ -19,39 +25,109 @@ const (
 	contentTypeJSON   = "application/json"
 )
 
+// DefaultMaxArtifactSize is the default limit, in bytes, on uploaded
+// artifacts and task request bodies.
+const DefaultMaxArtifactSize = 32 << 20
+
 // A2AServer exposes a Beluga agent as an A2A remote agent via HTTP.
 // It provides endpoints for the Agent Card, task creation, status, and cancellation.
 type A2AServer struct {
-	agent  agent.Agent
-	card   AgentCard
-	tasks  map[string]*Task
-	cancel map[string]context.CancelFunc
-	mu     sync.RWMutex
+	agent     agent.Agent
+	card      AgentCard
+	tasks     map[string]*Task
+	cancel    map[string]context.CancelFunc
+	subs      map[string][]chan Task
+	artifacts map[string]*storedArtifact
+	mu        sync.RWMutex
+
+	maxArtifactSize int64
+	inlineLimit     int
+
+	authenticators []Authenticator
+	policy         auth.Policy
+	tlsConfig      *tls.Config
+	owners         map[string]string // task ID -> subject that created it
+
+	store     TaskStore
+	taskTTL   time.Duration
+	lastPrune time.Time
+}
+
+// ServerOption configures an A2AServer.
+type ServerOption func(*A2AServer)
+
+// WithMaxArtifactSize limits uploaded artifacts, and task request bodies
+// with inline part data, to n bytes. Larger requests are rejected with 413
+// Request Entity Too Large. The default is DefaultMaxArtifactSize.
+func WithMaxArtifactSize(n int64) ServerOption {
+	return func(s *A2AServer) {
+		if n > 0 {
+			s.maxArtifactSize = n
+		}
+	}
+}
+
+// WithInlineLimit sets the largest output part data, in bytes, inlined in
+// task JSON. Larger data is stored as an artifact and referenced by ID. The
+// default is DefaultInlineLimit.
+func WithInlineLimit(n int) ServerOption {
+	return func(s *A2AServer) {
+		if n >= 0 {
+			s.inlineLimit = n
+		}
+	}
 }
 
 // NewServer creates a new A2A server wrapping the given agent and card.
-func NewServer(a agent.Agent, card AgentCard) *A2AServer {
-	return &A2AServer{
-		agent:  a,
-		card:   card,
-		tasks:  make(map[string]*Task),
-		cancel: make(map[string]context.CancelFunc),
+func NewServer(a agent.Agent, card AgentCard, opts ...ServerOption) *A2AServer {
+	s := &A2AServer{
+		agent:           a,
+		card:            card,
+		tasks:           make(map[string]*Task),
+		cancel:          make(map[string]context.CancelFunc),
+		subs:            make(map[string][]chan Task),
+		artifacts:       make(map[string]*storedArtifact),
+		maxArtifactSize: DefaultMaxArtifactSize,
+		inlineLimit:     DefaultInlineLimit,
+		owners:          make(map[string]string),
+		store:           NewInMemoryTaskStore(),
+		taskTTL:         DefaultTaskTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.card.AuthSchemes) == 0 {
+		for _, a := range s.authenticators {
+			if !slices.Contains(s.card.AuthSchemes, a.Scheme()) {
+				s.card.AuthSchemes = append(s.card.AuthSchemes, a.Scheme())
+			}
+		}
 	}
+	return s
 }
 
 // Handler returns an http.Handler for the A2A server.
 func (s *A2AServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /.well-known/agent.json", s.handleCard)
-	mux.HandleFunc("POST /tasks", s.handleCreateTask)
-	mux.HandleFunc("GET /tasks/", s.handleGetTask)
-	mux.HandleFunc("POST /tasks/", s.handleTaskAction)
+	mux.HandleFunc("POST /tasks", s.authenticate(s.handleCreateTask))
+	mux.HandleFunc("GET /tasks/", s.authenticate(s.handleGetTask))
+	mux.HandleFunc("GET /tasks/{id}/stream", s.authenticate(s.handleStreamTask))
+	mux.HandleFunc("POST /artifacts", s.authenticate(s.handleUploadArtifact))
+	mux.HandleFunc("GET /artifacts/{id}", s.authenticate(s.handleDownloadArtifact))
+	mux.HandleFunc("POST /tasks/", s.authenticate(s.handleTaskAction))
 	return mux
 }
 
-// Serve starts the A2A server on the given address. It blocks until the context
-// is canceled or an error occurs.
+// Serve starts the A2A server on the given address, serving TLS when
+// configured with WithTLSConfig. It first restarts unfinished tasks from the
+// task store (see Recover). It blocks until the context is canceled or an
+// error occurs.
 func (s *A2AServer) Serve(ctx context.Context, addr string) error {
+	if err := s.Recover(ctx); err != nil {
+		return err
+	}
+
 	srv := &http.Server{
 		Addr:              addr,
 		Handler:           s.Handler(),
@@ -62,6 +138,9 @@ func (s *A2AServer) Serve(ctx context.Context, addr string) error {
 	if err != nil {
 		return core.Errorf(core.ErrProviderDown, "a2a/serve: %w", err)
 	}
+	if s.tlsConfig != nil {
+		ln = tls.NewListener(ln, s.tlsConfig)
+	}
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -88,71 +167,258 @@ func (s *A2AServer) handleCard(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (s *A2AServer) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	if !s.authorize(w, r) {
+		return
+	}
+
 	var req TaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxArtifactSize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
 		writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
 		return
 	}
 
-	if req.Input == "" {
+	if req.Input == "" && len(req.Parts) == 0 {
 		writeJSONError(w, http.StatusBadRequest, "input is required")
 		return
 	}
 
+	parts, err := s.inputParts(caller(r.Context()), req.Parts)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	task := &Task{
-		ID:       uuid.New().String(),
-		Status:   StatusSubmitted,
-		Input:    req.Input,
-		Metadata: req.Metadata,
+		ID:         uuid.New().String(),
+		Status:     StatusSubmitted,
+		Input:      req.Input,
+		InputParts: req.Parts,
+		Metadata:   req.Metadata,
+	}
+
+	owner := caller(r.Context())
+	if err := s.save(task, owner); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to store task")
+		return
 	}
+	s.prune(r.Context())
 
-	// Use background context since the task runs asynchronously beyond the
-	// lifetime of the HTTP request. The cancel function is stored in
-	// s.cancel[task.ID] and invoked later by handleTaskAction when the task
-	// is cancelled, or implicitly when the task completes and is reaped.
-	ctx, cancel := context.WithCancel(context.Background()) // #nosec G118 -- cancel stored in s.cancel map, invoked later
+	base := context.Background()
+	if claims := auth.ClaimsFromContext(r.Context()); claims != nil {
+		base = auth.WithClaims(base, claims)
+	}
 
 	// Take a snapshot for the response before the goroutine can modify the task.
 	snapshot := *task
-
-	s.mu.Lock()
-	s.tasks[task.ID] = task
-	s.cancel[task.ID] = cancel
-	s.mu.Unlock()
-
-	// Run the agent asynchronously.
-	go s.runTask(ctx, task)
+	s.start(base, task, owner, parts)
 
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(TaskResponse{Task: snapshot})
 }
 
-func (s *A2AServer) runTask(ctx context.Context, task *Task) {
+func (s *A2AServer) runTask(ctx context.Context, task *Task, owner string, parts []schema.ContentPart) {
+	// Hand the input parts to the agent, and collect its output parts,
+	// through the context.
+	if len(parts) > 0 {
+		ctx = WithInputParts(ctx, parts...)
+	}
+	outputs := &Outputs{}
+	ctx = WithOutputs(ctx, outputs)
+
 	s.mu.Lock()
 	task.Status = StatusWorking
+	s.notifyLocked(task)
+	// A failed save leaves the task submitted in the store, which recovery
+	// treats the same.
+	_ = s.save(task, owner)
 	s.mu.Unlock()
 
-	result, err := s.agent.Invoke(ctx, task.Input)
+	// Stream the agent so that subscribers see output as it is produced.
+	// The output is the concatenated text events, as returned by Invoke.
+	var output strings.Builder
+	var err error
+	for event, streamErr := range s.agent.Stream(ctx, task.Input) {
+		if streamErr != nil {
+			err = streamErr
+			break
+		}
+		switch event.Type {
+		case agent.EventText:
+			if event.Text == "" {
+				continue
+			}
+			output.WriteString(event.Text)
+			s.mu.Lock()
+			if task.Status == StatusWorking {
+				task.Output = output.String()
+				s.notifyLocked(task)
+			}
+			s.mu.Unlock()
+		case agent.EventError:
+			err = core.Errorf(core.ErrProviderDown, "agent error: %s", event.Text)
+		}
+	}
+
+	var outputParts []Part
+	if err == nil {
+		outputParts, err = s.outputParts(owner, outputs.Parts())
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	delete(s.cancel, task.ID)
 
-	if ctx.Err() != nil {
-		task.Status = StatusCanceled
+	if task.Status.Terminal() {
+		// Canceled by handleTaskAction, which finished the task.
 		return
 	}
 
-	if err != nil {
+	switch {
+	case ctx.Err() != nil:
+		task.Status = StatusCanceled
+	case err != nil:
 		task.Status = StatusFailed
 		task.Error = err.Error()
+	default:
+		task.Status = StatusCompleted
+		task.Output = output.String()
+		task.OutputParts = outputParts
+	}
+	s.finishLocked(task, owner)
+}
+
+// notifyLocked sends a snapshot of task to its stream subscribers and, once
+// the task is finished, ends their streams. Subscribers that fall behind
+// receive only the latest snapshot. The caller must hold s.mu.
+func (s *A2AServer) notifyLocked(task *Task) {
+	snapshot := *task
+	for _, ch := range s.subs[task.ID] {
+		select {
+		case ch <- snapshot:
+		default:
+			// Replace the undelivered snapshot; snapshots carry the full
+			// state, so skipping one loses nothing.
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- snapshot
+		}
+		if task.Status.Terminal() {
+			close(ch)
+		}
+	}
+	if task.Status.Terminal() {
+		delete(s.subs, task.ID)
+	}
+}
+
+// unsubscribe removes ch from the stream subscribers of a task.
+func (s *A2AServer) unsubscribe(taskID string, ch chan Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.subs[taskID]
+	for i, c := range subs {
+		if c == ch {
+			s.subs[taskID] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(s.subs[taskID]) == 0 {
+		delete(s.subs, taskID)
+	}
+}
+
+// handleStreamTask streams the state of a task as Server-Sent Events: the
+// current state, then every status change and output update, ending once
+// the task is finished. Each event is a "task" event whose data is a
+// TaskResponse.
+func (s *A2AServer) handleStreamTask(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusNotImplemented, "streaming not supported")
 		return
 	}
 
-	task.Status = StatusCompleted
-	task.Output = result
+	taskID := r.PathValue("id")
+
+	s.mu.Lock()
+	var snapshot Task
+	var ch chan Task
+	task, live := s.tasks[taskID]
+	if live {
+		if !s.ownsLocked(r, taskID) {
+			s.mu.Unlock()
+			writeJSONError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		snapshot = *task
+		if !snapshot.Status.Terminal() {
+			ch = make(chan Task, 1)
+			s.subs[taskID] = append(s.subs[taskID], ch)
+		}
+	}
+	s.mu.Unlock()
+
+	if !live {
+		// A finished task: stream its final state.
+		var ok bool
+		var err error
+		snapshot, ok, err = s.findTask(r, taskID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to load task")
+			return
+		}
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "task not found")
+			return
+		}
+	}
+
+	if ch != nil {
+		defer s.unsubscribe(taskID, ch)
+	}
+
+	w.Header().Set(contentTypeHeader, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	if !writeTaskEvent(w, flusher, snapshot) || ch == nil {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case snapshot, ok := <-ch:
+			if !ok || !writeTaskEvent(w, flusher, snapshot) {
+				return
+			}
+		}
+	}
+}
+
+// writeTaskEvent writes task as a "task" SSE event and reports whether the
+// write succeeded.
+func writeTaskEvent(w http.ResponseWriter, flusher http.Flusher, task Task) bool {
+	data, err := json.Marshal(TaskResponse{Task: task})
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(w, "event: task\ndata: %s\n\n", data); err != nil {
+		return false
+	}
+	flusher.Flush()
+	return true
 }
 
 func (s *A2AServer) handleGetTask(w http.ResponseWriter, r *http.Request) {
@@ -168,14 +434,11 @@ func (s *A2AServer) handleGetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.mu.RLock()
-	task, ok := s.tasks[taskID]
-	var snapshot Task
-	if ok {
-		snapshot = *task
+	snapshot, ok, err := s.findTask(r, taskID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to load task")
+		return
 	}
-	s.mu.RUnlock()
-
 	if !ok {
 		writeJSONError(w, http.StatusNotFound, "task not found")
 		return
@@ -197,20 +460,35 @@ func (s *A2AServer) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 	taskID := parts[0]
 
 	s.mu.Lock()
-	task, ok := s.tasks[taskID]
-	if !ok {
+	task, live := s.tasks[taskID]
+	if !live {
+		s.mu.Unlock()
+		// Canceling a finished task has no effect.
+		snapshot, ok, err := s.findTask(r, taskID)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to load task")
+			return
+		}
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(TaskResponse{Task: snapshot})
+		return
+	}
+	if !s.ownsLocked(r, taskID) {
 		s.mu.Unlock()
 		writeJSONError(w, http.StatusNotFound, "task not found")
 		return
 	}
 
-	cancelFn, hasCancel := s.cancel[taskID]
-	if hasCancel {
+	if cancelFn, ok := s.cancel[taskID]; ok {
 		cancelFn()
-		delete(s.cancel, taskID)
 	}
 
 	task.Status = StatusCanceled
+	s.finishLocked(task, s.owners[taskID])
 	snapshot := *task
 	s.mu.Unlock()
 
@@ -218,6 +496,12 @@ func (s *A2AServer) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(TaskResponse{Task: snapshot})
 }
 
+// ownsLocked reports whether the caller of r created a task. Tasks of other
+// callers are reported as not found. s.mu must be held.
+func (s *A2AServer) ownsLocked(r *http.Request, taskID string) bool {
+	return s.owners[taskID] == caller(r.Context())
+}
+
 func extractTaskID(path string) string {
 	// path = /tasks/{id} or /tasks/{id}/...
 	trimmed := strings.TrimPrefix(path, "/tasks/")
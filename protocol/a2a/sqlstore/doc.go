@@ -0,0 +1,39 @@
+// Package sqlstore provides a database/sql implementation of
+// [a2a.TaskStore], so that the tasks of an [a2a.A2AServer] survive
+// restarts. Each task is stored as one row holding its JSON state. It works
+// with SQLite (for example the pure-Go modernc.org/sqlite driver) and, with
+// DialectPostgres, PostgreSQL; it serves as an example for other databases.
+//
+// # Usage
+//
+//	import (
+//	    "database/sql"
+//	    _ "modernc.org/sqlite"
+//	    "github.com/lookatitude/beluga-ai/v2/protocol/a2a/sqlstore"
+//	)
+//
+//	db, err := sql.Open("sqlite", "tasks.db")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	store, err := sqlstore.New(sqlstore.Config{DB: db})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = store.EnsureTable(ctx) // auto-create table if needed
+//
+//	srv := a2a.NewServer(myAgent, card, a2a.WithTaskStore(store))
+//	err = srv.Serve(ctx, ":9090") // restarts unfinished tasks first
+//
+// # Schema
+//
+// The auto-created table has the following columns:
+//
+//   - id: TEXT PRIMARY KEY (task ID)
+//   - owner: TEXT NOT NULL (authenticated subject that created the task)
+//   - status: TEXT NOT NULL
+//   - task: TEXT NOT NULL (JSON)
+//   - updated_at: BIGINT NOT NULL (Unix nanoseconds)
+//
+// Use [TaskStore.EnsureTable] to create it automatically.
+package sqlstore
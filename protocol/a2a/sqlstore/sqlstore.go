@@ -0,0 +1,198 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/protocol/a2a"
+)
+
+// validTableName matches SQL identifiers: letter/underscore followed by
+// letters, digits, or underscores. Rejects everything else so interpolation
+// into DDL/DML via fmt.Sprintf below is safe.
+var validTableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Dialect selects the SQL placeholder style.
+type Dialect string
+
+const (
+	// DialectSQLite uses "?" placeholders, as do MySQL drivers. It is the
+	// default.
+	DialectSQLite Dialect = "sqlite"
+
+	// DialectPostgres uses "$1", "$2", ... placeholders.
+	DialectPostgres Dialect = "postgres"
+)
+
+// Config holds configuration for the SQL TaskStore.
+type Config struct {
+	// DB is the sql.DB connection to use. Required.
+	DB *sql.DB
+	// Table is the name of the tasks table. Defaults to "a2a_tasks".
+	Table string
+	// Dialect is the placeholder style. Defaults to DialectSQLite.
+	Dialect Dialect
+}
+
+// TaskStore is a database/sql implementation of a2a.TaskStore.
+type TaskStore struct {
+	db      *sql.DB
+	table   string
+	dialect Dialect
+}
+
+// Compile-time interface check.
+var _ a2a.TaskStore = (*TaskStore)(nil)
+
+// New creates a new SQL TaskStore with the given config.
+func New(cfg Config) (*TaskStore, error) {
+	if cfg.DB == nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "sqlstore: db is required")
+	}
+	table := cfg.Table
+	if table == "" {
+		table = "a2a_tasks"
+	}
+	if !validTableName.MatchString(table) {
+		return nil, core.Errorf(core.ErrInvalidInput, "sqlstore: invalid table name %q (must match ^[a-zA-Z_][a-zA-Z0-9_]*$)", table)
+	}
+	dialect := cfg.Dialect
+	switch dialect {
+	case "":
+		dialect = DialectSQLite
+	case DialectSQLite, DialectPostgres:
+	default:
+		return nil, core.Errorf(core.ErrInvalidInput, "sqlstore: unknown dialect %q", dialect)
+	}
+	return &TaskStore{db: cfg.DB, table: table, dialect: dialect}, nil
+}
+
+// EnsureTable creates the tasks table if it does not exist.
+func (s *TaskStore) EnsureTable(ctx context.Context) error {
+	// #nosec G201 -- table name validated in New() against ^[a-zA-Z_][a-zA-Z0-9_]*$
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		status TEXT NOT NULL,
+		task TEXT NOT NULL,
+		updated_at BIGINT NOT NULL
+	)`, s.table)
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return core.Errorf(core.ErrProviderDown, "sqlstore: create table: %w", err)
+	}
+	return nil
+}
+
+// Save creates or replaces the record of rec.Task.ID.
+func (s *TaskStore) Save(ctx context.Context, rec a2a.TaskRecord) error {
+	taskJSON, err := json.Marshal(rec.Task)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "sqlstore: marshal task: %w", err)
+	}
+
+	// #nosec G201 -- table name validated in New() against ^[a-zA-Z_][a-zA-Z0-9_]*$
+	query := s.rebind(fmt.Sprintf(`INSERT INTO %s (id, owner, status, task, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, status = excluded.status,
+		task = excluded.task, updated_at = excluded.updated_at`, s.table))
+	_, err = s.db.ExecContext(ctx, query, rec.Task.ID, rec.Owner, string(rec.Task.Status), string(taskJSON), rec.UpdatedAt.UnixNano())
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "sqlstore: save: %w", err)
+	}
+	return nil
+}
+
+// Get returns the record of a task, or an ErrNotFound error.
+func (s *TaskStore) Get(ctx context.Context, id string) (a2a.TaskRecord, error) {
+	// #nosec G201 -- table name validated in New() against ^[a-zA-Z_][a-zA-Z0-9_]*$
+	query := s.rebind(fmt.Sprintf("SELECT owner, task, updated_at FROM %s WHERE id = ?", s.table))
+	rec, err := scanRecord(s.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return a2a.TaskRecord{}, core.Errorf(core.ErrNotFound, "sqlstore: task %q not found", id)
+	}
+	if err != nil {
+		return a2a.TaskRecord{}, core.Errorf(core.ErrProviderDown, "sqlstore: get: %w", err)
+	}
+	return rec, nil
+}
+
+// Unfinished returns the records of tasks that are submitted or working,
+// oldest first.
+func (s *TaskStore) Unfinished(ctx context.Context) ([]a2a.TaskRecord, error) {
+	// #nosec G201 -- table name validated in New() against ^[a-zA-Z_][a-zA-Z0-9_]*$
+	query := s.rebind(fmt.Sprintf(
+		"SELECT owner, task, updated_at FROM %s WHERE status IN (?, ?) ORDER BY updated_at ASC", s.table))
+	rows, err := s.db.QueryContext(ctx, query, string(a2a.StatusSubmitted), string(a2a.StatusWorking))
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "sqlstore: unfinished: %w", err)
+	}
+	defer rows.Close()
+
+	var recs []a2a.TaskRecord
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, core.Errorf(core.ErrProviderDown, "sqlstore: unfinished: %w", err)
+		}
+		recs = append(recs, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "sqlstore: unfinished: %w", err)
+	}
+	return recs, nil
+}
+
+// Prune deletes the records of finished tasks last saved before the given
+// time.
+func (s *TaskStore) Prune(ctx context.Context, before time.Time) error {
+	// #nosec G201 -- table name validated in New() against ^[a-zA-Z_][a-zA-Z0-9_]*$
+	query := s.rebind(fmt.Sprintf("DELETE FROM %s WHERE status IN (?, ?, ?) AND updated_at < ?", s.table))
+	_, err := s.db.ExecContext(ctx, query,
+		string(a2a.StatusCompleted), string(a2a.StatusFailed), string(a2a.StatusCanceled), before.UnixNano())
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "sqlstore: prune: %w", err)
+	}
+	return nil
+}
+
+// rebind rewrites "?" placeholders for the store's dialect.
+func (s *TaskStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// scanRecord scans an owner, task, updated_at row.
+func scanRecord(row interface{ Scan(...any) error }) (a2a.TaskRecord, error) {
+	var (
+		rec       a2a.TaskRecord
+		taskJSON  string
+		updatedAt int64
+	)
+	if err := row.Scan(&rec.Owner, &taskJSON, &updatedAt); err != nil {
+		return a2a.TaskRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(taskJSON), &rec.Task); err != nil {
+		return a2a.TaskRecord{}, fmt.Errorf("decode task: %w", err)
+	}
+	rec.UpdatedAt = time.Unix(0, updatedAt)
+	return rec, nil
+}
@@ -0,0 +1,237 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/protocol/a2a"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestStore(t *testing.T, dsn string) *TaskStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := New(Config{DB: db})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := store.EnsureTable(context.Background()); err != nil {
+		t.Fatalf("EnsureTable: %v", err)
+	}
+	return store
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected error for nil db")
+	}
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	if _, err := New(Config{DB: db, Table: "bad;name"}); err == nil {
+		t.Error("expected error for invalid table name")
+	}
+	if _, err := New(Config{DB: db, Dialect: "oracle"}); err == nil {
+		t.Error("expected error for unknown dialect")
+	}
+}
+
+func TestTaskStore_SaveGet(t *testing.T) {
+	store := newTestStore(t, ":memory:")
+	ctx := context.Background()
+	now := time.Now()
+
+	rec := a2a.TaskRecord{
+		Task: a2a.Task{
+			ID:         "t1",
+			Status:     a2a.StatusSubmitted,
+			Input:      "hello",
+			InputParts: []a2a.Part{{Type: schema.ContentFile, Data: []byte("data"), Name: "f.txt"}},
+			Metadata:   map[string]any{"k": "v"},
+		},
+		Owner:     "alice",
+		UpdatedAt: now,
+	}
+	if err := store.Save(ctx, rec); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	rec.Task.Status = a2a.StatusCompleted
+	rec.Task.Output = "done"
+	if err := store.Save(ctx, rec); err != nil {
+		t.Fatalf("Save update: %v", err)
+	}
+
+	got, err := store.Get(ctx, "t1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Owner != "alice" || got.Task.Status != a2a.StatusCompleted || got.Task.Output != "done" {
+		t.Errorf("record = %+v", got)
+	}
+	if string(got.Task.InputParts[0].Data) != "data" || got.Task.Metadata["k"] != "v" {
+		t.Errorf("task = %+v", got.Task)
+	}
+	if !got.UpdatedAt.Equal(time.Unix(0, now.UnixNano())) {
+		t.Errorf("updated at = %v, want %v", got.UpdatedAt, now)
+	}
+
+	_, err = store.Get(ctx, "missing")
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Code != core.ErrNotFound {
+		t.Errorf("Get missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTaskStore_UnfinishedPrune(t *testing.T) {
+	store := newTestStore(t, ":memory:")
+	ctx := context.Background()
+	base := time.Now()
+
+	for i, st := range []a2a.TaskStatus{a2a.StatusWorking, a2a.StatusCompleted, a2a.StatusSubmitted, a2a.StatusFailed} {
+		rec := a2a.TaskRecord{
+			Task:      a2a.Task{ID: string(rune('a' + i)), Status: st},
+			UpdatedAt: base.Add(time.Duration(i) * time.Second),
+		}
+		if err := store.Save(ctx, rec); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	unfinished, err := store.Unfinished(ctx)
+	if err != nil {
+		t.Fatalf("Unfinished: %v", err)
+	}
+	if len(unfinished) != 2 || unfinished[0].Task.ID != "a" || unfinished[1].Task.ID != "c" {
+		t.Errorf("unfinished = %+v", unfinished)
+	}
+
+	// Prunes "b" (completed at +1s) but not "d" (failed at +3s) or the
+	// unfinished tasks.
+	if err := store.Prune(ctx, base.Add(2*time.Second)); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, err := store.Get(ctx, "b"); err == nil {
+		t.Error("expected b to be pruned")
+	}
+	for _, id := range []string{"a", "c", "d"} {
+		if _, err := store.Get(ctx, id); err != nil {
+			t.Errorf("Get %s: %v", id, err)
+		}
+	}
+}
+
+func TestTaskStore_Rebind(t *testing.T) {
+	s := &TaskStore{dialect: DialectPostgres}
+	if got := s.rebind("a = ? AND b IN (?, ?)"); got != "a = $1 AND b IN ($2, $3)" {
+		t.Errorf("rebind = %q", got)
+	}
+}
+
+// echoAgent is an agent.Agent that echoes its input.
+type echoAgent struct{}
+
+func (echoAgent) ID() string              { return "echo" }
+func (echoAgent) Persona() agent.Persona  { return agent.Persona{Role: "echo"} }
+func (echoAgent) Tools() []tool.Tool      { return nil }
+func (echoAgent) Children() []agent.Agent { return nil }
+
+func (echoAgent) Invoke(_ context.Context, input string, _ ...agent.Option) (string, error) {
+	return "echo: " + input, nil
+}
+
+func (a echoAgent) Stream(ctx context.Context, input string, opts ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		out, err := a.Invoke(ctx, input, opts...)
+		if err != nil {
+			yield(agent.Event{}, err)
+			return
+		}
+		yield(agent.Event{Type: agent.EventText, Text: out}, nil)
+	}
+}
+
+// newServer starts an A2A server for echoAgent backed by store.
+func newServer(t *testing.T, store a2a.TaskStore) (*a2a.A2AServer, *a2a.A2AClient) {
+	t.Helper()
+	srv := a2a.NewServer(echoAgent{}, a2a.AgentCard{Name: "echo"}, a2a.WithTaskStore(store))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return srv, a2a.NewClient(ts.URL)
+}
+
+func TestTaskStore_ServerRestart(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "tasks.db")
+	ctx := context.Background()
+
+	// A task completed before the restart stays readable, and one left
+	// working is run again.
+	store := newTestStore(t, dsn)
+	_, client := newServer(t, store)
+	task, err := client.CreateTask(ctx, a2a.TaskRequest{Input: "before"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	waitStatus(t, client, task.ID, a2a.StatusCompleted)
+	interrupted := a2a.TaskRecord{
+		Task:      a2a.Task{ID: "interrupted", Status: a2a.StatusWorking, Input: "again"},
+		UpdatedAt: time.Now(),
+	}
+	if err := store.Save(ctx, interrupted); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	srv, client := newServer(t, newTestStore(t, dsn))
+	got, err := client.GetTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask after restart: %v", err)
+	}
+	if got.Status != a2a.StatusCompleted || got.Output != "echo: before" {
+		t.Errorf("task = %+v", got)
+	}
+
+	if err := srv.Recover(ctx); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	got = waitStatus(t, client, "interrupted", a2a.StatusCompleted)
+	if got.Output != "echo: again" {
+		t.Errorf("recovered output = %q", got.Output)
+	}
+}
+
+// waitStatus polls a task until it reaches status.
+func waitStatus(t *testing.T, client *a2a.A2AClient, id string, status a2a.TaskStatus) *a2a.Task {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err := client.GetTask(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetTask: %v", err)
+		}
+		if task.Status == status {
+			return task
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task %s status = %s, want %s", id, task.Status, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
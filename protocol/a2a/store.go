@@ -0,0 +1,271 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// DefaultTaskTTL is how long finished tasks are retained by default.
+const DefaultTaskTTL = 24 * time.Hour
+
+// TaskRecord is the persisted state of a task.
+type TaskRecord struct {
+	// Task is the task's state. Partial output of a running task is not
+	// persisted.
+	Task Task
+
+	// Owner is the authenticated subject that created the task, or "" when
+	// the server does not authenticate requests.
+	Owner string
+
+	// UpdatedAt is when the record was last saved.
+	UpdatedAt time.Time
+}
+
+// TaskStore persists the tasks of an A2AServer so that they survive
+// restarts. The server saves a task when it is created, starts running and
+// finishes. Implementations must be safe for concurrent use; the server
+// calls them while holding its lock, so they should be fast.
+type TaskStore interface {
+	// Save creates or replaces the record of rec.Task.ID.
+	Save(ctx context.Context, rec TaskRecord) error
+
+	// Get returns the record of a task. It returns an ErrNotFound error
+	// when there is none.
+	Get(ctx context.Context, id string) (TaskRecord, error)
+
+	// Unfinished returns the records of tasks that are not in a terminal
+	// status, oldest first.
+	Unfinished(ctx context.Context) ([]TaskRecord, error)
+
+	// Prune deletes the records of finished tasks last saved before the
+	// given time.
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// InMemoryTaskStore is a TaskStore that keeps records in memory. It is the
+// default store: tasks survive until their TTL expires but not a restart.
+type InMemoryTaskStore struct {
+	mu      sync.RWMutex
+	records map[string]TaskRecord
+}
+
+// Compile-time interface check.
+var _ TaskStore = (*InMemoryTaskStore)(nil)
+
+// NewInMemoryTaskStore creates an empty InMemoryTaskStore.
+func NewInMemoryTaskStore() *InMemoryTaskStore {
+	return &InMemoryTaskStore{records: make(map[string]TaskRecord)}
+}
+
+// Save creates or replaces the record of rec.Task.ID.
+func (m *InMemoryTaskStore) Save(_ context.Context, rec TaskRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[rec.Task.ID] = rec
+	return nil
+}
+
+// Get returns the record of a task.
+func (m *InMemoryTaskStore) Get(_ context.Context, id string) (TaskRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rec, ok := m.records[id]
+	if !ok {
+		return TaskRecord{}, core.Errorf(core.ErrNotFound, "a2a: task %q not found", id)
+	}
+	return rec, nil
+}
+
+// Unfinished returns the records of tasks not in a terminal status, oldest
+// first.
+func (m *InMemoryTaskStore) Unfinished(_ context.Context) ([]TaskRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []TaskRecord
+	for _, rec := range m.records {
+		if !rec.Task.Status.Terminal() {
+			out = append(out, rec)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	return out, nil
+}
+
+// Prune deletes the records of finished tasks last saved before the given
+// time.
+func (m *InMemoryTaskStore) Prune(_ context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, rec := range m.records {
+		if rec.Task.Status.Terminal() && rec.UpdatedAt.Before(before) {
+			delete(m.records, id)
+		}
+	}
+	return nil
+}
+
+// WithTaskStore persists tasks in store instead of memory, so that they
+// survive restarts (see A2AServer.Recover).
+func WithTaskStore(store TaskStore) ServerOption {
+	return func(s *A2AServer) {
+		if store != nil {
+			s.store = store
+		}
+	}
+}
+
+// WithTaskTTL sets how long finished tasks, and their results, are retained
+// after they finish. Zero retains them indefinitely. The default is
+// DefaultTaskTTL.
+func WithTaskTTL(d time.Duration) ServerOption {
+	return func(s *A2AServer) {
+		if d >= 0 {
+			s.taskTTL = d
+		}
+	}
+}
+
+// Recover restarts the unfinished tasks in the task store, such as those
+// interrupted by a restart, running each again from its input. Agents
+// serving recoverable tasks should therefore tolerate being run twice.
+// Tasks whose input references an artifact, which is not persisted, fail.
+// Serve calls Recover before accepting connections; call it yourself when
+// serving Handler by other means.
+func (s *A2AServer) Recover(ctx context.Context) error {
+	recs, err := s.store.Unfinished(ctx)
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "a2a/recover: %w", err)
+	}
+	for _, rec := range recs {
+		s.mu.RLock()
+		_, live := s.tasks[rec.Task.ID]
+		s.mu.RUnlock()
+		if live {
+			continue
+		}
+
+		task := rec.Task
+		task.Status = StatusSubmitted
+		task.Output = ""
+		task.OutputParts = nil
+		task.Error = ""
+
+		parts, err := s.inputParts(rec.Owner, task.InputParts)
+		if err != nil {
+			task.Status = StatusFailed
+			task.Error = "recover: " + err.Error()
+			if err := s.save(&task, rec.Owner); err != nil {
+				return core.Errorf(core.ErrProviderDown, "a2a/recover: %w", err)
+			}
+			continue
+		}
+		if err := s.save(&task, rec.Owner); err != nil {
+			return core.Errorf(core.ErrProviderDown, "a2a/recover: %w", err)
+		}
+		s.start(context.Background(), &task, rec.Owner, parts)
+	}
+	return nil
+}
+
+// save persists the state of task.
+func (s *A2AServer) save(task *Task, owner string) error {
+	return s.store.Save(context.Background(), TaskRecord{Task: *task, Owner: owner, UpdatedAt: time.Now()})
+}
+
+// finishLocked notifies subscribers that task finished, persists it and,
+// once persisted, serves it from the store instead of memory. s.mu must be
+// held.
+func (s *A2AServer) finishLocked(task *Task, owner string) {
+	s.notifyLocked(task)
+	if err := s.save(task, owner); err != nil {
+		// Keep serving the result from memory rather than lose it.
+		return
+	}
+	delete(s.tasks, task.ID)
+	delete(s.owners, task.ID)
+	delete(s.cancel, task.ID)
+}
+
+// findTask returns the state of a task created by the caller of r: from
+// memory while it runs, else from the store unless its TTL has expired.
+func (s *A2AServer) findTask(r *http.Request, id string) (Task, bool, error) {
+	s.mu.RLock()
+	task, ok := s.tasks[id]
+	var snapshot Task
+	if ok {
+		snapshot = *task
+		ok = s.ownsLocked(r, id)
+	}
+	s.mu.RUnlock()
+	if task != nil {
+		return snapshot, ok, nil
+	}
+
+	rec, err := s.store.Get(r.Context(), id)
+	if err != nil {
+		if isNotFound(err) {
+			return Task{}, false, nil
+		}
+		return Task{}, false, err
+	}
+	if rec.Owner != caller(r.Context()) || s.expired(rec) {
+		return Task{}, false, nil
+	}
+	return rec.Task, true, nil
+}
+
+// expired reports whether a finished task has outlived the task TTL.
+func (s *A2AServer) expired(rec TaskRecord) bool {
+	return s.taskTTL > 0 && rec.Task.Status.Terminal() && time.Since(rec.UpdatedAt) > s.taskTTL
+}
+
+// prune deletes expired tasks from the store, at most once per minute (or
+// per TTL, if shorter).
+func (s *A2AServer) prune(ctx context.Context) {
+	if s.taskTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < min(s.taskTTL, time.Minute) {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+	// Expired tasks are hidden by findTask, so a failed prune is retried on
+	// a later request without affecting clients.
+	_ = s.store.Prune(ctx, now.Add(-s.taskTTL))
+}
+
+// isNotFound reports whether err is an ErrNotFound error.
+func isNotFound(err error) bool {
+	var coreErr *core.Error
+	return errors.As(err, &coreErr) && coreErr.Code == core.ErrNotFound
+}
+
+// start registers task as running and runs it in the background under a
+// context derived from base.
+func (s *A2AServer) start(base context.Context, task *Task, owner string, parts []schema.ContentPart) {
+	// The task runs asynchronously beyond the lifetime of the HTTP request.
+	// The cancel function is stored in s.cancel[task.ID] and invoked by
+	// handleTaskAction when the task is cancelled, or discarded when it
+	// finishes.
+	ctx, cancel := context.WithCancel(base) // #nosec G118 -- cancel stored in s.cancel map, invoked later
+
+	s.mu.Lock()
+	s.tasks[task.ID] = task
+	s.cancel[task.ID] = cancel
+	s.owners[task.ID] = owner
+	s.mu.Unlock()
+
+	go s.runTask(ctx, task, owner, parts)
+}
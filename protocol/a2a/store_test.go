@@ -0,0 +1,126 @@
+package a2a
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// waitFinished follows a task until it finishes and returns its final
+// state.
+func waitFinished(t *testing.T, client *A2AClient, id string) Task {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var last Task
+	for task, err := range client.SubscribeTask(ctx, id) {
+		if err != nil {
+			t.Fatalf("SubscribeTask: %v", err)
+		}
+		last = task
+	}
+	return last
+}
+
+func TestTaskStore_FinishedTasksServedFromStore(t *testing.T) {
+	store := NewInMemoryTaskStore()
+	srv := NewServer(&mockAgent{id: "a"}, AgentCard{Name: "a"}, WithTaskStore(store))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, TaskRequest{Input: "hi"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if final := waitFinished(t, client, task.ID); final.Status != StatusCompleted {
+		t.Fatalf("status = %s", final.Status)
+	}
+
+	rec, err := store.Get(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("store.Get: %v", err)
+	}
+	if rec.Task.Output != "response: hi" {
+		t.Errorf("stored output = %q", rec.Task.Output)
+	}
+
+	// Streaming and canceling a finished task return its final state.
+	if final := waitFinished(t, client, task.ID); final.Output != "response: hi" {
+		t.Errorf("streamed output = %q", final.Output)
+	}
+	if err := client.CancelTask(ctx, task.ID); err != nil {
+		t.Fatalf("CancelTask: %v", err)
+	}
+	got, err := client.GetTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Status != StatusCompleted {
+		t.Errorf("status after cancel = %s, want completed", got.Status)
+	}
+}
+
+func TestTaskStore_TTL(t *testing.T) {
+	store := NewInMemoryTaskStore()
+	srv := NewServer(&mockAgent{id: "a"}, AgentCard{Name: "a"}, WithTaskStore(store), WithTaskTTL(time.Hour))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	old := TaskRecord{Task: Task{ID: "old", Status: StatusCompleted}, UpdatedAt: time.Now().Add(-2 * time.Hour)}
+	fresh := TaskRecord{Task: Task{ID: "fresh", Status: StatusCompleted}, UpdatedAt: time.Now()}
+	for _, rec := range []TaskRecord{old, fresh} {
+		if err := store.Save(ctx, rec); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	_, err := client.GetTask(ctx, "old")
+	assertCode(t, err, core.ErrNotFound)
+	if _, err := client.GetTask(ctx, "fresh"); err != nil {
+		t.Errorf("GetTask fresh: %v", err)
+	}
+
+	// Creating a task prunes expired records.
+	if _, err := client.CreateTask(ctx, TaskRequest{Input: "hi"}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := store.Get(ctx, "old"); err == nil {
+		t.Error("expected expired task to be pruned")
+	}
+}
+
+func TestTaskStore_Recover(t *testing.T) {
+	store := NewInMemoryTaskStore()
+	ctx := context.Background()
+	recs := []TaskRecord{
+		{Task: Task{ID: "w", Status: StatusWorking, Input: "again", Output: "partial"}, UpdatedAt: time.Now()},
+		{Task: Task{ID: "art", Status: StatusSubmitted, Input: "x", InputParts: []Part{{Type: "file", ArtifactID: "gone"}}}, UpdatedAt: time.Now()},
+	}
+	for _, rec := range recs {
+		if err := store.Save(ctx, rec); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	srv := NewServer(&mockAgent{id: "a"}, AgentCard{Name: "a"}, WithTaskStore(store))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	client := NewClient(ts.URL)
+
+	if err := srv.Recover(ctx); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if final := waitFinished(t, client, "w"); final.Status != StatusCompleted || final.Output != "response: again" {
+		t.Errorf("recovered task = %+v", final)
+	}
+	if final := waitFinished(t, client, "art"); final.Status != StatusFailed {
+		t.Errorf("task with lost artifact = %+v, want failed", final)
+	}
+}
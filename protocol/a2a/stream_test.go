@@ -0,0 +1,275 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// streamingAgent streams each string received on chunks as a text event
+// until chunks is closed.
+type streamingAgent struct {
+	mockAgent
+	chunks chan string
+}
+
+func (a *streamingAgent) Stream(ctx context.Context, _ string, _ ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				yield(agent.Event{}, ctx.Err())
+				return
+			case text, ok := <-a.chunks:
+				if !ok {
+					return
+				}
+				if !yield(agent.Event{Type: agent.EventText, Text: text}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newStreamingServer(t *testing.T) (*streamingAgent, *A2AClient) {
+	t.Helper()
+	a := &streamingAgent{mockAgent: mockAgent{id: "streamer"}, chunks: make(chan string)}
+	ts := httptest.NewServer(NewServer(a, AgentCard{Name: "streamer"}).Handler())
+	t.Cleanup(ts.Close)
+	return a, NewClient(ts.URL)
+}
+
+// nextTask returns the next task state from next, failing on errors and
+// timeouts.
+func nextTask(t *testing.T, next func() (Task, error, bool)) Task {
+	t.Helper()
+	type result struct {
+		task Task
+		err  error
+		ok   bool
+	}
+	ch := make(chan result, 1)
+	go func() {
+		task, err, ok := next()
+		ch <- result{task, err, ok}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("SubscribeTask: %v", r.err)
+		}
+		if !r.ok {
+			t.Fatal("SubscribeTask ended early")
+		}
+		return r.task
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for task update")
+	}
+	return Task{}
+}
+
+func TestSubscribeTask_Stream(t *testing.T) {
+	a, client := newStreamingServer(t)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, TaskRequest{Input: "go"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	next, stop := iter.Pull2(client.SubscribeTask(ctx, task.ID))
+	defer stop()
+
+	// Skip the initial submitted state, if the server sent it.
+	got := nextTask(t, next)
+	for got.Status != StatusWorking {
+		got = nextTask(t, next)
+	}
+
+	a.chunks <- "Hello"
+	for got.Output != "Hello" {
+		got = nextTask(t, next)
+	}
+	if got.Status != StatusWorking {
+		t.Errorf("expected working with partial output, got %s", got.Status)
+	}
+
+	a.chunks <- ", world"
+	close(a.chunks)
+	for !got.Status.Terminal() {
+		got = nextTask(t, next)
+	}
+	if got.Status != StatusCompleted || got.Output != "Hello, world" {
+		t.Errorf("expected completed %q, got %s %q", "Hello, world", got.Status, got.Output)
+	}
+	if _, _, ok := next(); ok {
+		t.Error("expected the stream to end after the final state")
+	}
+}
+
+func TestSubscribeTask_Cancel(t *testing.T) {
+	_, client := newStreamingServer(t)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, TaskRequest{Input: "go"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	next, stop := iter.Pull2(client.SubscribeTask(ctx, task.ID))
+	defer stop()
+	nextTask(t, next)
+
+	if err := client.CancelTask(ctx, task.ID); err != nil {
+		t.Fatalf("CancelTask: %v", err)
+	}
+	got := nextTask(t, next)
+	for !got.Status.Terminal() {
+		got = nextTask(t, next)
+	}
+	if got.Status != StatusCanceled {
+		t.Errorf("expected canceled, got %s", got.Status)
+	}
+}
+
+func TestSubscribeTask_Finished(t *testing.T) {
+	_, ts := setupA2ATestServer()
+	defer ts.Close()
+	client := NewClient(ts.URL)
+
+	task, err := client.CreateTask(context.Background(), TaskRequest{Input: "hi"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	var last Task
+	for tk, err := range client.SubscribeTask(context.Background(), task.ID) {
+		if err != nil {
+			t.Fatalf("SubscribeTask: %v", err)
+		}
+		last = tk
+	}
+	if last.Status != StatusCompleted || last.Output != "response: hi" {
+		t.Errorf("expected completed task, got %+v", last)
+	}
+}
+
+func TestSubscribeTask_NotFound(t *testing.T) {
+	_, ts := setupA2ATestServer()
+	defer ts.Close()
+
+	var gotErr error
+	for _, err := range NewClient(ts.URL).SubscribeTask(context.Background(), "missing") {
+		gotErr = err
+	}
+	var coreErr *core.Error
+	if !errors.As(gotErr, &coreErr) || coreErr.Code != core.ErrNotFound {
+		t.Errorf("expected not found error, got %v", gotErr)
+	}
+}
+
+func TestSubscribeTask_PollingFallback(t *testing.T) {
+	// A server without the streaming endpoint.
+	var polls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tasks/t1" {
+			writeJSONError(w, http.StatusNotFound, "not found")
+			return
+		}
+		task := Task{ID: "t1", Status: StatusWorking}
+		if polls.Add(1) > 2 {
+			task.Status = StatusCompleted
+			task.Output = "done"
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		_ = json.NewEncoder(w).Encode(TaskResponse{Task: task})
+	}))
+	defer ts.Close()
+
+	var states []TaskStatus
+	for tk, err := range NewClient(ts.URL).SubscribeTask(context.Background(), "t1") {
+		if err != nil {
+			t.Fatalf("SubscribeTask: %v", err)
+		}
+		states = append(states, tk.Status)
+	}
+	// Unchanged polls are not yielded.
+	if len(states) != 2 || states[0] != StatusWorking || states[1] != StatusCompleted {
+		t.Errorf("expected [working completed], got %v", states)
+	}
+}
+
+func TestServer_StreamTask_Events(t *testing.T) {
+	_, ts := setupA2ATestServer()
+	defer ts.Close()
+
+	task, err := NewClient(ts.URL).CreateTask(context.Background(), TaskRequest{Input: "hi"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	resp, err := http.Get(ts.URL + "/tasks/" + task.ID + "/stream")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get(contentTypeHeader); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+	var body strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buf)
+		body.WriteString(string(buf[:n]))
+		if err != nil {
+			break
+		}
+	}
+	if !strings.Contains(body.String(), "event: task\ndata: {\"task\":") {
+		t.Errorf("expected task events, got %q", body.String())
+	}
+	if !strings.Contains(body.String(), `"status":"completed"`) {
+		t.Errorf("expected a completed event, got %q", body.String())
+	}
+}
+
+func TestRemoteAgent_Stream_Partial(t *testing.T) {
+	a, client := newStreamingServer(t)
+	remote := &remoteAgent{client: client, card: AgentCard{Name: "streamer"}}
+
+	go func() {
+		a.chunks <- "Hel"
+		time.Sleep(50 * time.Millisecond)
+		a.chunks <- "lo"
+		close(a.chunks)
+	}()
+
+	var text strings.Builder
+	var done bool
+	for event, err := range remote.Stream(context.Background(), "go") {
+		if err != nil {
+			t.Fatalf("Stream: %v", err)
+		}
+		switch event.Type {
+		case agent.EventText:
+			text.WriteString(event.Text)
+		case agent.EventDone:
+			done = true
+		}
+	}
+	if text.String() != "Hello" || !done {
+		t.Errorf("expected %q and done, got %q done=%v", "Hello", text.String(), done)
+	}
+}
@@ -16,6 +16,11 @@ const (
 	StatusCanceled TaskStatus = "canceled"
 )
 
+// Terminal reports whether s is a final state: completed, failed or canceled.
+func (s TaskStatus) Terminal() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCanceled
+}
+
 // AgentCard describes a remote agent's identity and capabilities.
 // It is served at the well-known URL /.well-known/agent.json.
 type AgentCard struct {
@@ -25,30 +30,56 @@ type AgentCard struct {
 	Capabilities []string     `json:"capabilities,omitempty"`
 	Endpoint     string       `json:"endpoint"`
 	Skills       []AgentSkill `json:"skills,omitempty"`
+
+	// AuthSchemes lists the auth schemes the agent accepts, such as
+	// AuthSchemeBearer. Empty means requests need no credentials.
+	AuthSchemes []string `json:"auth_schemes,omitempty"`
 }
 
 // AgentSkill describes a specific skill or capability of an agent.
 type AgentSkill struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+
+	// Tags are keywords describing the tasks the skill handles, used by
+	// Router to match tasks to agents.
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Task represents an A2A task with its lifecycle state and results.
 type Task struct {
-	ID       string         `json:"id"`
-	Status   TaskStatus     `json:"status"`
-	Input    string         `json:"input,omitempty"`
-	Output   string         `json:"output,omitempty"`
-	Error    string         `json:"error,omitempty"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	ID          string         `json:"id"`
+	Status      TaskStatus     `json:"status"`
+	Input       string         `json:"input,omitempty"`
+	InputParts  []Part         `json:"input_parts,omitempty"`
+	Output      string         `json:"output,omitempty"`
+	OutputParts []Part         `json:"output_parts,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
-// TaskRequest is the payload for creating a new A2A task.
+// TaskRequest is the payload for creating a new A2A task. At least one of
+// Input and Parts is required.
 type TaskRequest struct {
 	Input    string         `json:"input"`
+	Parts    []Part         `json:"parts,omitempty"`
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// Artifact describes binary data stored on an A2A server, such as a large
+// file part. Its content is served at GET /artifacts/{id}.
+type Artifact struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+// ArtifactResponse wraps an Artifact in an API response.
+type ArtifactResponse struct {
+	Artifact Artifact `json:"artifact"`
+}
+
 // TaskResponse wraps a Task in an API response.
 type TaskResponse struct {
 	Task Task `json:"task"`
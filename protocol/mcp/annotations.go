@@ -0,0 +1,47 @@
+package mcp
+
+import "github.com/lookatitude/beluga-ai/v2/tool"
+
+// newToolAnnotations converts Beluga tool annotations to MCP annotations.
+func newToolAnnotations(a tool.Annotations) *ToolAnnotations {
+	return &ToolAnnotations{
+		ReadOnlyHint:    &a.ReadOnly,
+		DestructiveHint: &a.Destructive,
+		IdempotentHint:  &a.Idempotent,
+	}
+}
+
+// toAnnotations converts MCP annotations to Beluga tool annotations,
+// applying the MCP defaults for unset hints. A nil receiver yields the
+// defaults, so tools that say nothing are treated as destructive.
+func (a *ToolAnnotations) toAnnotations() tool.Annotations {
+	out := tool.Annotations{Destructive: true}
+	if a == nil {
+		return out
+	}
+	if a.ReadOnlyHint != nil {
+		out.ReadOnly = *a.ReadOnlyHint
+	}
+	if a.DestructiveHint != nil {
+		out.Destructive = *a.DestructiveHint
+	}
+	if a.IdempotentHint != nil {
+		out.Idempotent = *a.IdempotentHint
+	}
+	return out
+}
+
+// newToolInfo describes t for MCP tool listings, with its annotations and
+// output schema if it has them.
+func newToolInfo(t tool.Tool) ToolInfo {
+	info := ToolInfo{
+		Name:         t.Name(),
+		Description:  t.Description(),
+		InputSchema:  t.InputSchema(),
+		OutputSchema: tool.OutputSchemaOf(t),
+	}
+	if a, ok := tool.AnnotationsOf(t); ok {
+		info.Annotations = newToolAnnotations(a)
+	}
+	return info
+}
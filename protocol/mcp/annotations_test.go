@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+var resultSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"matches": map[string]any{"type": "array"},
+	},
+}
+
+func setupAnnotatedServer() *httptest.Server {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(tool.ApplyMiddleware(newTestTool(),
+		tool.WithAnnotations(tool.Annotations{ReadOnly: true, Idempotent: true}),
+		tool.WithOutputSchema(resultSchema),
+	))
+	srv.AddTool(&mockTool{name: "plain", inputSchema: map[string]any{"type": "object"}})
+	return httptest.NewServer(srv.Handler())
+}
+
+func TestServer_ToolsList_Annotations(t *testing.T) {
+	ts := setupAnnotatedServer()
+	defer ts.Close()
+
+	tools, err := NewClient(ts.URL).ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools error: %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d", len(tools))
+	}
+
+	echo := tools[0]
+	if echo.Annotations == nil {
+		t.Fatal("expected annotations on echo")
+	}
+	if !*echo.Annotations.ReadOnlyHint || *echo.Annotations.DestructiveHint || !*echo.Annotations.IdempotentHint {
+		t.Errorf("annotations = readOnly %v destructive %v idempotent %v",
+			*echo.Annotations.ReadOnlyHint, *echo.Annotations.DestructiveHint, *echo.Annotations.IdempotentHint)
+	}
+	if echo.OutputSchema["type"] != "object" {
+		t.Errorf("outputSchema = %v", echo.OutputSchema)
+	}
+
+	if tools[1].Annotations != nil || tools[1].OutputSchema != nil {
+		t.Errorf("plain tool has annotations %+v, outputSchema %v", tools[1].Annotations, tools[1].OutputSchema)
+	}
+}
+
+func TestFromMCP_PreservesAnnotations(t *testing.T) {
+	ts := setupAnnotatedServer()
+	defer ts.Close()
+
+	tools, err := FromMCP(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("FromMCP error: %v", err)
+	}
+
+	a, ok := tool.AnnotationsOf(tools[0])
+	if !ok {
+		t.Fatal("expected annotations on the wrapped tool")
+	}
+	if want := (tool.Annotations{ReadOnly: true, Idempotent: true}); a != want {
+		t.Errorf("annotations = %+v, want %+v", a, want)
+	}
+	if got := tool.OutputSchemaOf(tools[0]); got["type"] != "object" {
+		t.Errorf("output schema = %v", got)
+	}
+
+	// Tools without annotations take the MCP defaults.
+	a, _ = tool.AnnotationsOf(tools[1])
+	if want := (tool.Annotations{Destructive: true}); a != want {
+		t.Errorf("default annotations = %+v, want %+v", a, want)
+	}
+	if got := tool.OutputSchemaOf(tools[1]); got != nil {
+		t.Errorf("output schema = %v, want nil", got)
+	}
+}
+
+func TestToolAnnotations_PartialHints(t *testing.T) {
+	readOnly := true
+	a := (&ToolAnnotations{ReadOnlyHint: &readOnly}).toAnnotations()
+	if want := (tool.Annotations{ReadOnly: true, Destructive: true}); !reflect.DeepEqual(a, want) {
+		t.Errorf("toAnnotations() = %+v, want %+v", a, want)
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// TokenVerifier verifies bearer tokens. *auth.JWTPolicy implements it.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Claims, error)
+}
+
+// ServerOption configures an MCPServer.
+type ServerOption func(*MCPServer)
+
+// WithBearerAuth requires every HTTP request to carry a bearer token in the
+// Authorization header that v accepts, as the MCP authorization
+// specification requires of servers exposed over HTTP. Requests without a
+// valid token are rejected with 401 Unauthorized, a WWW-Authenticate
+// challenge and a CodeUnauthorized JSON-RPC error before any method runs.
+// The caller is the token's sub claim; tools can read the token and its
+// claims with auth.BearerTokenFromContext and auth.ClaimsFromContext.
+// Sessions are bound to the caller that created them.
+//
+// The initialize result advertises the requirement in the oauth
+// capability. ServeStdio is not affected: stdio servers take their
+// credentials from the environment.
+func WithBearerAuth(v TokenVerifier) ServerOption {
+	return func(s *MCPServer) {
+		s.verifier = v
+	}
+}
+
+// WithOAuthCapability advertises the authorization server from which
+// clients obtain tokens in the oauth capability of the initialize result.
+func WithOAuthCapability(c OAuthCapability) ServerOption {
+	return func(s *MCPServer) {
+		s.capabilities.OAuth = &c
+	}
+}
+
+// WithAuthPolicy authorizes tool calls with policy, which is asked whether
+// the caller holds auth.PermToolExec on the tool, named by its name. Denied
+// calls fail with a CodeForbidden JSON-RPC error. Without WithBearerAuth,
+// and over stdio, the caller is the empty subject.
+func WithAuthPolicy(policy auth.Policy) ServerOption {
+	return func(s *MCPServer) {
+		s.policy = policy
+	}
+}
+
+type callerKey struct{}
+
+// caller returns the subject authenticated for the request of ctx, or "" if
+// the server does not authenticate requests.
+func caller(ctx context.Context) string {
+	sub, _ := ctx.Value(callerKey{}).(string)
+	return sub
+}
+
+// authenticate verifies the bearer token of r and returns a context
+// carrying the caller, the token and its claims. It writes a 401 response
+// and returns false if the token is missing or invalid.
+func (s *MCPServer) authenticate(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		unauthorized(w, `Bearer realm="mcp"`, "authentication required")
+		return nil, false
+	}
+	claims, err := s.verifier.Verify(r.Context(), token)
+	if err != nil {
+		unauthorized(w, `Bearer realm="mcp", error="invalid_token"`, "invalid token")
+		return nil, false
+	}
+	ctx := auth.WithClaims(auth.WithBearerToken(r.Context(), token), claims)
+	return context.WithValue(ctx, callerKey{}, claims.Subject()), true
+}
+
+// unauthorized writes a 401 response with the given challenge.
+func unauthorized(w http.ResponseWriter, challenge, message string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeErrorStatus(w, http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// authorizeTool checks that the caller of ctx may call the named tool.
+func (s *MCPServer) authorizeTool(ctx context.Context, name string) *RPCError {
+	if s.policy == nil {
+		return nil
+	}
+	allowed, err := s.policy.Authorize(ctx, caller(ctx), auth.PermToolExec, name)
+	if err != nil {
+		var coreErr *core.Error
+		if errors.As(err, &coreErr) && coreErr.Code == core.ErrAuth {
+			return &RPCError{Code: CodeUnauthorized, Message: "unauthorized"}
+		}
+		return &RPCError{Code: CodeInternalError, Message: "authorization failed"}
+	}
+	if !allowed {
+		return &RPCError{Code: CodeForbidden, Message: "forbidden: " + name}
+	}
+	return nil
+}
+
+// WithBearerToken sends token in the Authorization header of every HTTP
+// request.
+func WithBearerToken(token string) ClientOption {
+	return func(c *MCPClient) {
+		c.header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// authError returns an ErrAuth error for 401 and 403 responses, else nil.
+func authError(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return core.Errorf(core.ErrAuth, "unauthorized")
+	case http.StatusForbidden:
+		return core.Errorf(core.ErrAuth, "forbidden")
+	}
+	return nil
+}
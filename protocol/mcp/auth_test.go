@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// tokenVerifier accepts the tokens in its map.
+type tokenVerifier map[string]auth.Claims
+
+func (v tokenVerifier) Verify(_ context.Context, token string) (auth.Claims, error) {
+	claims, ok := v[token]
+	if !ok {
+		return nil, core.Errorf(core.ErrAuth, "invalid token")
+	}
+	return claims, nil
+}
+
+// toolPolicy allows subjects to execute the tools listed for them.
+type toolPolicy map[string][]string
+
+func (p toolPolicy) Name() string { return "tools" }
+
+func (p toolPolicy) Authorize(_ context.Context, subject string, permission auth.Permission, resource string) (bool, error) {
+	if permission != auth.PermToolExec {
+		return false, nil
+	}
+	for _, name := range p[subject] {
+		if name == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+var testTokens = tokenVerifier{
+	"alice-token": {"sub": "alice"},
+	"bob-token":   {"sub": "bob"},
+}
+
+func setupAuthServer(opts ...ServerOption) *httptest.Server {
+	srv := NewServer("test-server", "1.0.0", append([]ServerOption{WithBearerAuth(testTokens)}, opts...)...)
+	srv.AddTool(newTestTool())
+	return httptest.NewServer(srv.Handler())
+}
+
+func isAuthError(err error) bool {
+	return errors.Is(err, &core.Error{Code: core.ErrAuth})
+}
+
+func TestServer_BearerAuth_Challenge(t *testing.T) {
+	ts := setupAuthServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantChallenge string
+	}{
+		{name: "missing", wantChallenge: `Bearer realm="mcp"`},
+		{name: "wrong scheme", authorization: "Basic YWxpY2U6c2VjcmV0", wantChallenge: `Bearer realm="mcp"`},
+		{name: "invalid", authorization: "Bearer nope", wantChallenge: `Bearer realm="mcp", error="invalid_token"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", resp.StatusCode)
+			}
+			if got := resp.Header.Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+			var rpcResp Response
+			if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+				t.Fatal(err)
+			}
+			if rpcResp.Error == nil || rpcResp.Error.Code != CodeUnauthorized {
+				t.Errorf("error = %+v, want code %d", rpcResp.Error, CodeUnauthorized)
+			}
+		})
+	}
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	ts := setupAuthServer()
+	defer ts.Close()
+	ctx := context.Background()
+
+	client := NewClient(ts.URL, WithBearerToken("alice-token"))
+	caps, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if caps.OAuth == nil || !caps.OAuth.Required {
+		t.Errorf("oauth capability = %+v, want Required", caps.OAuth)
+	}
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "echo: hi" {
+		t.Errorf("result = %+v", result)
+	}
+
+	if _, err := NewClient(ts.URL).ListTools(ctx); !isAuthError(err) {
+		t.Errorf("ListTools without token error = %v, want ErrAuth", err)
+	}
+	if _, err := NewClient(ts.URL, WithBearerToken("nope")).Initialize(ctx); !isAuthError(err) {
+		t.Errorf("Initialize with invalid token error = %v, want ErrAuth", err)
+	}
+}
+
+func TestServer_BearerAuth_OAuthCapability(t *testing.T) {
+	ts := setupAuthServer(WithOAuthCapability(OAuthCapability{AuthURL: "https://auth.example.com/authorize"}))
+	defer ts.Close()
+
+	caps, err := NewClient(ts.URL, WithBearerToken("alice-token")).Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if caps.OAuth == nil || !caps.OAuth.Required || caps.OAuth.AuthURL != "https://auth.example.com/authorize" {
+		t.Errorf("oauth capability = %+v", caps.OAuth)
+	}
+}
+
+func TestServer_AuthPolicy(t *testing.T) {
+	ts := setupAuthServer(WithAuthPolicy(toolPolicy{"alice": {"echo"}}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	if _, err := NewClient(ts.URL, WithBearerToken("alice-token")).CallTool(ctx, "echo", map[string]any{"text": "hi"}); err != nil {
+		t.Fatalf("CallTool as alice error: %v", err)
+	}
+
+	bob := NewClient(ts.URL, WithBearerToken("bob-token"))
+	_, err := bob.CallTool(ctx, "echo", map[string]any{"text": "hi"})
+	if !isAuthError(err) {
+		t.Fatalf("CallTool as bob error = %v, want ErrAuth", err)
+	}
+	if !strings.Contains(err.Error(), "-32003") {
+		t.Errorf("error = %v, want code %d", err, CodeForbidden)
+	}
+	// Listing is not restricted by the policy.
+	if _, err := bob.ListTools(ctx); err != nil {
+		t.Errorf("ListTools as bob error: %v", err)
+	}
+}
+
+func TestServer_BearerAuth_SessionBoundToSubject(t *testing.T) {
+	ts := setupAuthServer()
+	defer ts.Close()
+	ctx := context.Background()
+
+	alice := NewClient(ts.URL, WithBearerToken("alice-token"))
+	if _, err := alice.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	req.Header.Set("Authorization", "Bearer bob-token")
+	req.Header.Set(SessionHeader, alice.session())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 for another subject's session", resp.StatusCode)
+	}
+
+	if _, err := alice.ListTools(ctx); err != nil {
+		t.Errorf("ListTools in own session error: %v", err)
+	}
+}
+
+func TestServeStdio_IgnoresBearerAuth(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0", WithBearerAuth(testTokens))
+	srv.AddTool(newTestTool())
+	client, _ := connectStdio(t, srv)
+	ctx := context.Background()
+	defer client.Close(ctx)
+
+	if _, err := client.CallTool(ctx, "echo", map[string]any{"text": "hi"}); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+}
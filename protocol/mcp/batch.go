@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// maxBatchSize is the largest JSON-RPC batch the server accepts.
+const maxBatchSize = 100
+
+// isBatch reports whether data holds a JSON array, that is, a JSON-RPC
+// batch rather than a single message.
+func isBatch(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '['
+}
+
+// parseBatch splits a JSON-RPC batch into its messages. An empty, oversized
+// or malformed batch is answered with the returned error as a single
+// response.
+func parseBatch(data []byte) ([]json.RawMessage, *RPCError) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, &RPCError{Code: CodeParseError, Message: "invalid JSON: " + err.Error()}
+	}
+	switch {
+	case len(batch) == 0:
+		return nil, &RPCError{Code: CodeInvalidRequest, Message: "empty batch"}
+	case len(batch) > maxBatchSize:
+		return nil, &RPCError{Code: CodeInvalidRequest, Message: "batch too large"}
+	}
+	return batch, nil
+}
+
+// handleBatch dispatches the requests of a JSON-RPC batch concurrently with
+// call and returns their responses in request order. Notifications in the
+// batch get no response and, as over stdio, are not processed, so the
+// result is empty for a batch of notifications.
+func (s *MCPServer) handleBatch(ctx context.Context, batch []json.RawMessage, call func(context.Context, Request) Response) []Response {
+	responses := make([]*Response, len(batch))
+	var wg sync.WaitGroup
+	for i, raw := range batch {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses[i] = &Response{JSONRPC: "2.0", Error: &RPCError{Code: CodeInvalidRequest, Message: "invalid request: " + err.Error()}}
+			continue
+		}
+		switch {
+		case req.JSONRPC != "2.0":
+			responses[i] = &Response{JSONRPC: "2.0", ID: req.ID, Error: &RPCError{Code: CodeInvalidRequest, Message: "jsonrpc must be \"2.0\""}}
+		case req.ID == nil || req.Method == "":
+			// Notifications and responses from the client need no answer.
+		case req.Method == "initialize":
+			responses[i] = &Response{JSONRPC: "2.0", ID: req.ID, Error: &RPCError{Code: CodeInvalidRequest, Message: "initialize must not be part of a batch"}}
+		default:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp := call(ctx, req)
+				responses[i] = &resp
+			}()
+		}
+	}
+	wg.Wait()
+
+	out := make([]Response, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			out = append(out, *resp)
+		}
+	}
+	return out
+}
+
+// writeBatch writes the responses to a batch, or 202 Accepted if there are
+// none.
+func writeBatch(w http.ResponseWriter, responses []Response) {
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(responses)
+}
+
+// BatchCall is one request of a batch sent with CallBatch. Result, if
+// non-nil, is a pointer the request's result is decoded into, and Err is
+// set to the request's error.
+type BatchCall struct {
+	Method string
+	Params any
+	Result any
+	Err    error
+}
+
+// CallBatch sends calls to the server as a single JSON-RPC batch, saving
+// round trips when a client needs several independent results:
+//
+//	var weather, news mcp.ToolCallResult
+//	calls := []mcp.BatchCall{
+//	    {Method: "tools/call", Params: mcp.ToolCallParams{Name: "weather"}, Result: &weather},
+//	    {Method: "tools/call", Params: mcp.ToolCallParams{Name: "news"}, Result: &news},
+//	}
+//	if err := client.CallBatch(ctx, calls); err != nil { ... }
+//	if calls[0].Err != nil { ... }
+//
+// The error reports a failure of the batch as a whole; the outcome of each
+// call is in its Err field. The server runs the calls concurrently and in
+// no particular order, and initialize cannot be batched. A batch holds at
+// most 100 calls. Over HTTP, calls in a batch receive no progress
+// notifications.
+func (c *MCPClient) CallBatch(ctx context.Context, calls []BatchCall) error {
+	switch {
+	case len(calls) == 0:
+		return nil
+	case len(calls) > maxBatchSize:
+		return core.Errorf(core.ErrInvalidInput, "mcp/call_batch: %d calls exceed the batch limit of %d", len(calls), maxBatchSize)
+	}
+	reqs := make([]Request, len(calls))
+	for i, call := range calls {
+		reqs[i] = Request{
+			JSONRPC: "2.0",
+			ID:      c.nextID.Add(1),
+			Method:  call.Method,
+			Params:  call.Params,
+		}
+	}
+
+	var (
+		resps []incomingMessage
+		err   error
+	)
+	if c.stdio != nil {
+		resps, err = c.stdio.roundTripBatch(ctx, reqs)
+	} else {
+		resps, err = c.postBatch(ctx, reqs)
+	}
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp/call_batch: %w", err)
+	}
+
+	byID := make(map[string]incomingMessage, len(resps))
+	for _, resp := range resps {
+		byID[idKey(resp.ID)] = resp
+	}
+	for i := range calls {
+		resp, ok := byID[idKey(reqs[i].ID)]
+		if !ok {
+			calls[i].Err = core.Errorf(core.ErrProviderDown, "mcp/call_batch: no response to %s", calls[i].Method)
+			continue
+		}
+		calls[i].Err = decodeResponse(resp, calls[i].Result)
+	}
+	return nil
+}
+
+// postBatch sends reqs over HTTP as a batch and returns the responses.
+func (c *MCPClient) postBatch(ctx context.Context, reqs []Request) ([]incomingMessage, error) {
+	httpResp, err := c.send(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	return decodeBatch(httpResp.Body)
+}
+
+// decodeBatch decodes the responses to a batch. A server that rejects the
+// batch as a whole answers with a single error response instead.
+func decodeBatch(r io.Reader) ([]incomingMessage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "read batch response: %w", err)
+	}
+	if !isBatch(data) {
+		var resp incomingMessage
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, core.Errorf(core.ErrProviderDown, "decode batch response: %w", err)
+		}
+		if err := decodeResponse(resp, nil); err != nil {
+			return nil, err
+		}
+		return nil, core.Errorf(core.ErrProviderDown, "unexpected batch response: %s", data)
+	}
+	var resps []incomingMessage
+	if err := json.Unmarshal(data, &resps); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "decode batch response: %w", err)
+	}
+	return resps, nil
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func postRaw(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServer_Batch(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	resp := postRaw(t, ts.URL, `[
+		{"jsonrpc":"2.0","id":1,"method":"tools/list"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}},
+		{"jsonrpc":"2.0","id":3,"method":"bogus"},
+		{"jsonrpc":"1.0","id":4,"method":"tools/list"},
+		{"jsonrpc":"2.0","id":5,"method":"initialize"},
+		42
+	]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var responses []incomingMessage
+	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 6 {
+		t.Fatalf("got %d responses, want 6: %+v", len(responses), responses)
+	}
+
+	if responses[0].Error != nil || !strings.Contains(string(responses[0].Result), `"echo"`) {
+		t.Errorf("tools/list response = %+v", responses[0])
+	}
+	var result ToolCallResult
+	if err := decodeResponse(responses[1], &result); err != nil || responses[1].ID != "call" {
+		t.Fatalf("tools/call response = %+v, err %v", responses[1], err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "echo: hi" {
+		t.Errorf("tools/call result = %+v", result)
+	}
+
+	wantCodes := []int{CodeMethodNotFound, CodeInvalidRequest, CodeInvalidRequest, CodeInvalidRequest}
+	for i, code := range wantCodes {
+		got := responses[i+2]
+		if got.Error == nil || got.Error.Code != code {
+			t.Errorf("response %d = %+v, want error code %d", i+2, got, code)
+		}
+	}
+}
+
+func TestServer_Batch_Invalid(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "empty", body: `[]`, wantCode: CodeInvalidRequest},
+		{name: "malformed", body: `[{"jsonrpc":"2.0",`, wantCode: CodeParseError},
+		{name: "too large", body: "[" + strings.Repeat(`{"jsonrpc":"2.0","id":1,"method":"tools/list"},`, maxBatchSize) + "1]", wantCode: CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Response
+			if err := json.NewDecoder(postRaw(t, ts.URL, tt.body).Body).Decode(&got); err != nil {
+				t.Fatalf("expected a single response object: %v", err)
+			}
+			if got.Error == nil || got.Error.Code != tt.wantCode {
+				t.Errorf("response = %+v, want error code %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServer_Batch_OnlyNotifications(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	resp := postRaw(t, ts.URL, `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", resp.StatusCode)
+	}
+}
+
+func testCallBatch(t *testing.T, client *MCPClient) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+
+	var (
+		tools struct {
+			Tools []ToolInfo `json:"tools"`
+		}
+		first, second ToolCallResult
+	)
+	calls := []BatchCall{
+		{Method: "tools/list", Result: &tools},
+		{Method: "tools/call", Params: ToolCallParams{Name: "echo", Arguments: map[string]any{"text": "one"}}, Result: &first},
+		{Method: "tools/call", Params: ToolCallParams{Name: "echo", Arguments: map[string]any{"text": "two"}}, Result: &second},
+		{Method: "bogus"},
+	}
+	if err := client.CallBatch(ctx, calls); err != nil {
+		t.Fatalf("CallBatch error: %v", err)
+	}
+
+	for i, call := range calls[:3] {
+		if call.Err != nil {
+			t.Errorf("calls[%d].Err = %v", i, call.Err)
+		}
+	}
+	if calls[3].Err == nil {
+		t.Error("expected an error for the unknown method")
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", tools)
+	}
+	if len(first.Content) != 1 || first.Content[0].Text != "echo: one" {
+		t.Errorf("first = %+v", first)
+	}
+	if len(second.Content) != 1 || second.Content[0].Text != "echo: two" {
+		t.Errorf("second = %+v", second)
+	}
+}
+
+func TestClient_CallBatch(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+	testCallBatch(t, NewClient(ts.URL))
+}
+
+func TestClient_CallBatch_Stdio(t *testing.T) {
+	srv, ts := setupTestServer()
+	ts.Close()
+	client, _ := connectStdio(t, srv)
+	defer client.Close(context.Background())
+	testCallBatch(t, client)
+}
+
+func TestClient_CallBatch_Limits(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	if err := client.CallBatch(ctx, nil); err != nil {
+		t.Errorf("CallBatch(nil) error: %v", err)
+	}
+	if err := client.CallBatch(ctx, make([]BatchCall, maxBatchSize+1)); err == nil {
+		t.Error("expected error for an oversized batch")
+	}
+}
+
+func TestClient_CallBatch_ConnectionError(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1")
+	if err := client.CallBatch(context.Background(), []BatchCall{{Method: "tools/list"}}); err == nil {
+		t.Error("expected connection error")
+	}
+}
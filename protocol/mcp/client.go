@@ -4,30 +4,64 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"iter"
+	"mime"
 	"net/http"
 	"net/url"
+	"sync"
 	"sync/atomic"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 	"github.com/lookatitude/beluga-ai/v2/tool"
 )
 
 // MCPClient connects to a remote MCP server over Streamable HTTP transport.
 type MCPClient struct {
-	serverURL  string
-	httpClient *http.Client
-	nextID     atomic.Int64
+	serverURL      string
+	httpClient     *http.Client
+	header         http.Header
+	nextID         atomic.Int64
+	onNotification func(ctx context.Context, n Notification)
+
+	// stdio, if set, carries requests instead of HTTP.
+	stdio *stdioTransport
+
+	mu        sync.Mutex
+	sessionID string
+	// subscriptions counts the SubscribeResource iterators per URI.
+	subscriptions map[string]int
+}
+
+// ClientOption configures an MCPClient.
+type ClientOption func(*MCPClient)
+
+// WithNotificationHandler sets a callback receiving the notifications the
+// server sends, such as progress and log messages, both while a request is
+// in progress and on the stream opened by Listen. The callback runs on the
+// goroutine reading the stream, so it should return quickly.
+func WithNotificationHandler(fn func(ctx context.Context, n Notification)) ClientOption {
+	return func(c *MCPClient) {
+		c.onNotification = fn
+	}
 }
 
 // NewClient creates a new MCP client pointing at the given server URL.
 // serverURL is validated at request time by validateServerURL, which
 // rejects anything that does not parse as an http(s) URL with a host.
-func NewClient(serverURL string) *MCPClient {
-	return &MCPClient{
-		serverURL:  serverURL,
-		httpClient: http.DefaultClient,
+func NewClient(serverURL string, opts ...ClientOption) *MCPClient {
+	c := &MCPClient{
+		serverURL:     serverURL,
+		httpClient:    http.DefaultClient,
+		header:        make(http.Header),
+		subscriptions: make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // validateServerURL parses raw and returns its canonical form if it is a
@@ -82,7 +116,286 @@ func (c *MCPClient) CallTool(ctx context.Context, name string, args map[string]a
 	return &result, nil
 }
 
+// CallToolWithProgress invokes a named tool like CallTool, passing the
+// progress notifications the server sends for the call to onProgress. The
+// call's progress token is generated by the client.
+func (c *MCPClient) CallToolWithProgress(ctx context.Context, name string, args map[string]any, onProgress func(ProgressParams)) (*ToolCallResult, error) {
+	token := fmt.Sprintf("progress-%d", c.nextID.Add(1))
+	params := ToolCallParams{
+		Name:      name,
+		Arguments: args,
+		Meta:      &RequestMeta{ProgressToken: token},
+	}
+	onNotification := func(n Notification) {
+		if n.Method != MethodProgress || onProgress == nil {
+			return
+		}
+		var p ProgressParams
+		if json.Unmarshal(n.Params, &p) == nil && fmt.Sprint(p.ProgressToken) == token {
+			onProgress(p)
+		}
+	}
+	var result ToolCallResult
+	if err := c.roundTrip(ctx, "tools/call", params, &result, onNotification); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "mcp/call_tool: %w", err)
+	}
+	return &result, nil
+}
+
+// SetLogLevel asks the server to send only log messages at level or above.
+func (c *MCPClient) SetLogLevel(ctx context.Context, level LoggingLevel) error {
+	var result struct{}
+	if err := c.call(ctx, "logging/setLevel", SetLevelParams{Level: level}, &result); err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp/set_log_level: %w", err)
+	}
+	return nil
+}
+
+// Listen opens the server's event stream and passes the notifications the
+// server sends outside of any request, such as log messages, to the
+// notification handler. It blocks until ctx is cancelled or the server
+// closes the stream. Over stdio, where the handler receives every
+// notification as it arrives, Listen only waits.
+func (c *MCPClient) Listen(ctx context.Context) error {
+	if c.stdio != nil {
+		return c.stdio.wait(ctx)
+	}
+
+	notifications, stop, err := c.openNotifications(ctx)
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp/listen: %w", err)
+	}
+	defer stop()
+
+	for n, err := range notifications {
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return core.Errorf(core.ErrProviderDown, "mcp/listen: %w", err)
+		}
+		c.notify(ctx, n, nil)
+	}
+	return ctx.Err()
+}
+
+// SubscribeResource subscribes to the resource at uri and returns an
+// iterator over its updates, starting a session with Initialize if the
+// client has none. Each update only says that the resource changed; re-read
+// it to get the new content. The iterator ends without error when ctx is
+// cancelled, and unsubscribes when the last iterator for uri stops.
+func (c *MCPClient) SubscribeResource(ctx context.Context, uri string) iter.Seq2[ResourceUpdatedParams, error] {
+	return func(yield func(ResourceUpdatedParams, error) bool) {
+		if c.stdio == nil && c.session() == "" {
+			if _, err := c.Initialize(ctx); err != nil {
+				yield(ResourceUpdatedParams{}, core.Errorf(core.ErrProviderDown, "mcp/subscribe_resource: %w", err))
+				return
+			}
+		}
+
+		// Open the stream first so that no update is missed.
+		notifications, stop, err := c.openNotifications(ctx)
+		if err != nil {
+			yield(ResourceUpdatedParams{}, core.Errorf(core.ErrProviderDown, "mcp/subscribe_resource: %w", err))
+			return
+		}
+		defer stop()
+
+		if err := c.subscribe(ctx, uri); err != nil {
+			yield(ResourceUpdatedParams{}, core.Errorf(core.ErrProviderDown, "mcp/subscribe_resource: %w", err))
+			return
+		}
+		defer c.unsubscribe(context.WithoutCancel(ctx), uri)
+
+		for n, err := range notifications {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				yield(ResourceUpdatedParams{}, core.Errorf(core.ErrProviderDown, "mcp/subscribe_resource: %w", err))
+				return
+			}
+			if n.Method != MethodResourceUpdated {
+				continue
+			}
+			var update ResourceUpdatedParams
+			if json.Unmarshal(n.Params, &update) != nil || update.URI != uri {
+				continue
+			}
+			if !yield(update, nil) {
+				return
+			}
+		}
+		if ctx.Err() == nil {
+			yield(ResourceUpdatedParams{}, core.Errorf(core.ErrProviderDown, "mcp/subscribe_resource: server closed the event stream"))
+		}
+	}
+}
+
+// openNotifications opens the stream of notifications the server sends
+// outside of any request: the GET event stream, or over stdio the
+// notifications read from the server. Call stop when done.
+func (c *MCPClient) openNotifications(ctx context.Context) (notifications iter.Seq2[Notification, error], stop func(), err error) {
+	if c.stdio != nil {
+		notifications, stop = c.stdio.watch(ctx)
+		return notifications, stop, nil
+	}
+
+	httpResp, err := c.openEventStream(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	notifications = func(yield func(Notification, error) bool) {
+		for event, err := range httpclient.ScanSSE(ctx, httpResp.Body) {
+			if err != nil {
+				yield(Notification{}, err)
+				return
+			}
+			var msg incomingMessage
+			if json.Unmarshal([]byte(event.Data), &msg) != nil || msg.Method == "" {
+				continue
+			}
+			if !yield(msg.notification(), nil) {
+				return
+			}
+		}
+	}
+	return notifications, func() { _ = httpResp.Body.Close() }, nil
+}
+
+// subscribe subscribes the session to uri, unless another iterator already
+// has.
+func (c *MCPClient) subscribe(ctx context.Context, uri string) error {
+	c.mu.Lock()
+	c.subscriptions[uri]++
+	first := c.subscriptions[uri] == 1
+	c.mu.Unlock()
+	if !first {
+		return nil
+	}
+
+	var result struct{}
+	if err := c.call(ctx, "resources/subscribe", SubscribeParams{URI: uri}, &result); err != nil {
+		c.mu.Lock()
+		c.subscriptions[uri]--
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// unsubscribe unsubscribes the session from uri once no iterator needs it.
+func (c *MCPClient) unsubscribe(ctx context.Context, uri string) {
+	c.mu.Lock()
+	c.subscriptions[uri]--
+	last := c.subscriptions[uri] == 0
+	if last {
+		delete(c.subscriptions, uri)
+	}
+	c.mu.Unlock()
+	if !last {
+		return
+	}
+
+	var result struct{}
+	_ = c.call(ctx, "resources/unsubscribe", SubscribeParams{URI: uri}, &result) // #nosec G104 -- best effort; the session may be gone
+}
+
+// Close ends the client's session with the server, if any, discarding its
+// subscriptions. A stdio client closes the server's stdin and waits for it
+// to exit, killing it if ctx ends first.
+func (c *MCPClient) Close(ctx context.Context) error {
+	if c.stdio != nil {
+		return c.stdio.close(ctx)
+	}
+
+	id := c.session()
+	if id == "" {
+		return nil
+	}
+	validatedURL, err := validateServerURL(c.serverURL)
+	if err != nil {
+		return err
+	}
+	// #nosec G704 -- validatedURL has been parsed and scheme-checked by validateServerURL
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, validatedURL, nil)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "mcp/close: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	// #nosec G704 -- httpReq uses validatedURL, sanitised above
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp/close: %w", err)
+	}
+	_ = httpResp.Body.Close()
+	if err := authError(httpResp); err != nil {
+		return core.Errorf(core.ErrAuth, "mcp/close: %w", err)
+	}
+
+	c.mu.Lock()
+	c.sessionID = ""
+	c.mu.Unlock()
+	return nil
+}
+
+// session returns the client's session ID, or "" before Initialize.
+func (c *MCPClient) session() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+// setHeaders adds the client's credentials and session ID, if any, to req.
+func (c *MCPClient) setHeaders(req *http.Request) {
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if id := c.session(); id != "" {
+		req.Header.Set(SessionHeader, id)
+	}
+}
+
+// openEventStream opens the server's GET event stream.
+func (c *MCPClient) openEventStream(ctx context.Context) (*http.Response, error) {
+	validatedURL, err := validateServerURL(c.serverURL)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G704 -- validatedURL has been parsed and scheme-checked by validateServerURL
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, validatedURL, nil)
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	c.setHeaders(httpReq)
+
+	// #nosec G704 -- httpReq uses validatedURL, sanitised above
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "open event stream: %w", err)
+	}
+	if err := authError(httpResp); err != nil {
+		_ = httpResp.Body.Close()
+		return nil, err
+	}
+	if !isEventStream(httpResp) {
+		_ = httpResp.Body.Close()
+		return nil, core.Errorf(core.ErrProviderDown, "server does not offer an event stream (status %d)", httpResp.StatusCode)
+	}
+	return httpResp, nil
+}
+
 func (c *MCPClient) call(ctx context.Context, method string, params any, result any) error {
+	return c.roundTrip(ctx, method, params, result, nil)
+}
+
+// roundTrip sends a request and decodes its result into result. The server
+// may answer with an event stream of notifications, which are passed to
+// onNotification and the client's notification handler, followed by the
+// response.
+func (c *MCPClient) roundTrip(ctx context.Context, method string, params any, result any, onNotification func(Notification)) error {
 	id := c.nextID.Add(1)
 	req := Request{
 		JSONRPC: "2.0",
@@ -91,48 +404,153 @@ func (c *MCPClient) call(ctx context.Context, method string, params any, result
 		Params:  params,
 	}
 
-	body, err := json.Marshal(req)
+	var (
+		resp incomingMessage
+		err  error
+	)
+	if c.stdio != nil {
+		resp, err = c.stdio.roundTrip(ctx, req, onNotification)
+	} else {
+		resp, err = c.post(ctx, req, onNotification)
+	}
 	if err != nil {
-		return core.Errorf(core.ErrInvalidInput, "marshal request: %w", err)
+		return err
+	}
+	return decodeResponse(resp, result)
+}
+
+// decodeResponse decodes the result of resp into result, or returns its
+// error.
+func decodeResponse(resp incomingMessage, result any) error {
+	if resp.Error != nil {
+		code := core.ErrProviderDown
+		if resp.Error.Code == CodeUnauthorized || resp.Error.Code == CodeForbidden {
+			code = core.ErrAuth
+		}
+		return core.Errorf(code, "rpc error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+	if len(resp.Result) == 0 || result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return core.Errorf(core.ErrInvalidInput, "decode result: %w", err)
+	}
+
+	return nil
+}
+
+// post sends req over HTTP and returns the response.
+func (c *MCPClient) post(ctx context.Context, req Request, onNotification func(Notification)) (incomingMessage, error) {
+	httpResp, err := c.send(ctx, req)
+	if err != nil {
+		return incomingMessage{}, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if isEventStream(httpResp) {
+		return c.readEventStream(ctx, httpResp, onNotification)
+	}
+	var resp incomingMessage
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return incomingMessage{}, core.Errorf(core.ErrProviderDown, "decode response: %w", err)
+	}
+	return resp, nil
+}
+
+// send POSTs msg, a request or a batch, to the server and returns the
+// HTTP response, recording the session ID the server assigns.
+func (c *MCPClient) send(ctx context.Context, msg any) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "marshal request: %w", err)
 	}
 
 	validatedURL, err := validateServerURL(c.serverURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// #nosec G704 -- validatedURL has been parsed and scheme-checked by validateServerURL
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, validatedURL, bytes.NewReader(body))
 	if err != nil {
-		return core.Errorf(core.ErrInvalidInput, "create request: %w", err)
+		return nil, core.Errorf(core.ErrInvalidInput, "create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	c.setHeaders(httpReq)
 
 	// #nosec G704 -- httpReq uses validatedURL, sanitised above
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return core.Errorf(core.ErrProviderDown, "send request: %w", err)
+		return nil, core.Errorf(core.ErrProviderDown, "send request: %w", err)
 	}
-	defer func() { _ = httpResp.Body.Close() }()
-
-	var resp Response
-	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
-		return core.Errorf(core.ErrProviderDown, "decode response: %w", err)
+	if err := authError(httpResp); err != nil {
+		_ = httpResp.Body.Close()
+		return nil, err
 	}
+	if id := httpResp.Header.Get(SessionHeader); id != "" {
+		c.mu.Lock()
+		c.sessionID = id
+		c.mu.Unlock()
+	}
+	return httpResp, nil
+}
 
-	if resp.Error != nil {
-		return core.Errorf(core.ErrProviderDown, "rpc error %d: %s", resp.Error.Code, resp.Error.Message)
+// readEventStream reads the notifications of an event stream response up
+// to the response message.
+func (c *MCPClient) readEventStream(ctx context.Context, httpResp *http.Response, onNotification func(Notification)) (incomingMessage, error) {
+	for event, err := range httpclient.ScanSSE(ctx, httpResp.Body) {
+		if err != nil {
+			return incomingMessage{}, core.Errorf(core.ErrProviderDown, "read event stream: %w", err)
+		}
+		var msg incomingMessage
+		if err := json.Unmarshal([]byte(event.Data), &msg); err != nil {
+			return incomingMessage{}, core.Errorf(core.ErrProviderDown, "decode event: %w", err)
+		}
+		if msg.Method != "" {
+			c.notify(ctx, msg.notification(), onNotification)
+			continue
+		}
+		return msg, nil
 	}
+	return incomingMessage{}, core.Errorf(core.ErrProviderDown, "event stream ended without a response")
+}
 
-	// Re-marshal the result to decode into the target type.
-	resultBytes, err := json.Marshal(resp.Result)
-	if err != nil {
-		return core.Errorf(core.ErrInvalidInput, "marshal result: %w", err)
+// notify passes a notification to onNotification and the client's handler.
+func (c *MCPClient) notify(ctx context.Context, n Notification, onNotification func(Notification)) {
+	if onNotification != nil {
+		onNotification(n)
 	}
-	if err := json.Unmarshal(resultBytes, result); err != nil {
-		return core.Errorf(core.ErrInvalidInput, "decode result: %w", err)
+	if c.onNotification != nil {
+		c.onNotification(ctx, n)
 	}
+}
 
-	return nil
+// incomingMessage is a JSON-RPC message received from the server: a
+// response, or a notification when Method is set.
+type incomingMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      any             `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// idKey returns the key matching a response ID to its request ID, which
+// may have been decoded as a different numeric type.
+func idKey(id any) string {
+	return fmt.Sprint(id)
+}
+
+// notification returns the message as a Notification.
+func (m incomingMessage) notification() Notification {
+	return Notification{JSONRPC: m.JSONRPC, Method: m.Method, Params: m.Params}
+}
+
+// isEventStream reports whether resp has a text/event-stream body.
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/event-stream"
 }
 
 // FromMCP connects to an MCP server and returns its tools as native tool.Tool instances.
@@ -168,6 +586,13 @@ func (t *mcpTool) Name() string                { return t.info.Name }
 func (t *mcpTool) Description() string         { return t.info.Description }
 func (t *mcpTool) InputSchema() map[string]any { return t.info.InputSchema }
 
+// Annotations returns the tool's MCP annotations, with the MCP defaults for
+// hints the server left unset.
+func (t *mcpTool) Annotations() tool.Annotations { return t.info.Annotations.toAnnotations() }
+
+// OutputSchema returns the tool's MCP output schema, or nil.
+func (t *mcpTool) OutputSchema() map[string]any { return t.info.OutputSchema }
+
 func (t *mcpTool) Execute(ctx context.Context, input map[string]any) (*tool.Result, error) {
 	result, err := t.client.CallTool(ctx, t.info.Name, input)
 	if err != nil {
@@ -1,7 +1,8 @@
 // Package mcp implements the Model Context Protocol (MCP) for tool and resource
 // sharing between AI systems. It supports the Streamable HTTP transport per the
 // March 2025 MCP specification, using a single HTTP endpoint for JSON-RPC 2.0
-// messages.
+// messages, and the stdio transport used by hosts that launch servers as
+// subprocesses.
 //
 // MCP enables AI applications to expose and consume tools, resources, and prompt
 // templates across process and network boundaries. The protocol uses JSON-RPC 2.0
@@ -34,6 +35,98 @@
 //	tools, err := client.ListTools(ctx)
 //	result, err := client.CallTool(ctx, "search", map[string]any{"query": "hello"})
 //
+// # Stdio Transport
+//
+// ServeStdio serves the same tools, resources, and prompts over
+// newline-delimited JSON-RPC on a reader and writer, typically stdin and
+// stdout, for IDEs and desktop hosts that launch MCP servers:
+//
+//	if err := srv.ServeStdio(ctx, os.Stdin, os.Stdout); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// NewStdioClient does the reverse, starting a server command and talking to
+// it over its stdin and stdout with the usual MCPClient methods:
+//
+//	client, err := mcp.NewStdioClient(exec.Command("npx", "-y", "@modelcontextprotocol/server-everything"))
+//	defer client.Close(ctx)
+//
+// # Tool Annotations
+//
+// Tool listings carry the MCP annotations (readOnlyHint, destructiveHint,
+// idempotentHint) and outputSchema of tools that provide them through
+// tool.Annotated and tool.OutputSchemaProvider, for example via
+// tool.WithAnnotations. The tools returned by FromMCP report the server's
+// annotations, with the MCP defaults for unset hints, so a remote tool that
+// says nothing is treated as destructive.
+//
+// # Notifications and Progress
+//
+// Clients that accept text/event-stream receive a tools/call response as an
+// event stream, so that the tool can report progress and send log messages
+// before its result. A tool reports progress with ReportProgress and logs
+// with LogMessage, using the context it was called with. Progress is only
+// sent when the client passed a progress token in the _meta of the call,
+// which CallToolWithProgress does, correlating the notifications with the
+// call:
+//
+//	result, err := client.CallToolWithProgress(ctx, "index", args, func(p mcp.ProgressParams) {
+//	    fmt.Printf("%.0f/%.0f %s\n", p.Progress, p.Total, p.Message)
+//	})
+//
+// Messages unrelated to any request, sent with MCPServer.LogMessage, go to
+// clients listening on the GET event stream opened by MCPClient.Listen.
+// Clients receive notifications through WithNotificationHandler, and filter
+// log messages by severity with SetLogLevel.
+//
+// # Sessions and Resource Subscriptions
+//
+// The server starts a session for each initialize request and returns its
+// ID in the Mcp-Session-Id header, which MCPClient sends with later
+// requests. Sessions hold the client's resource subscriptions. When a
+// resource changes, MCPServer.NotifyResourceUpdated pushes a
+// notifications/resources/updated notification to every subscribed session
+// on its GET event stream. SubscribeResource handles the subscription and
+// the stream, yielding an update per change:
+//
+//	for update, err := range client.SubscribeResource(ctx, "file:///data.json") {
+//	    if err != nil { return err }
+//	    cache.Invalidate(update.URI)
+//	}
+//
+// MCPClient.Close ends the session.
+//
+// # Batches
+//
+// The server accepts JSON-RPC 2.0 batches over HTTP and stdio, dispatching
+// their requests concurrently and answering with an array of responses;
+// notifications in a batch get no response. CallBatch sends several calls
+// in one round trip and reports each call's outcome separately:
+//
+//	calls := []mcp.BatchCall{
+//	    {Method: "tools/list", Result: &tools},
+//	    {Method: "tools/call", Params: mcp.ToolCallParams{Name: "search"}, Result: &result},
+//	}
+//	err := client.CallBatch(ctx, calls)
+//
+// # Authorization
+//
+// WithBearerAuth makes an HTTP server require an OAuth bearer token on every
+// request, verified by a TokenVerifier such as *auth.JWTPolicy. Requests
+// without a valid token get 401 Unauthorized with a WWW-Authenticate
+// challenge, sessions are bound to the token's subject, and WithAuthPolicy
+// additionally checks auth.PermToolExec before each tool call:
+//
+//	srv := mcp.NewServer("my-server", "1.0.0",
+//	    mcp.WithBearerAuth(jwtPolicy),
+//	    mcp.WithAuthPolicy(rbac),
+//	)
+//
+// Clients send a token with WithBearerToken; rejected requests fail with
+// core.ErrAuth:
+//
+//	client := mcp.NewClient("http://localhost:8080/mcp", mcp.WithBearerToken(token))
+//
 // # Bridge Function
 //
 // FromMCP connects to an MCP server and returns its tools as native tool.Tool
@@ -48,6 +141,11 @@
 //   - MCPClient — connects to remote MCP servers
 //   - Request / Response — JSON-RPC 2.0 message types
 //   - ToolInfo / ToolCallParams / ToolCallResult — tool operation types
+//   - ToolAnnotations — tool behaviour hints
 //   - Resource / Prompt — MCP resource and prompt template types
 //   - ServerCapabilities — describes server feature support
+//   - TokenVerifier — verifies bearer tokens for WithBearerAuth
+//   - Notification / ProgressParams / LogMessageParams — server notifications
+//   - SubscribeParams / ResourceUpdatedParams — resource subscription types
+//   - BatchCall — one call of a batch sent with CallBatch
 package mcp
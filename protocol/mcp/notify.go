@@ -0,0 +1,331 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// Notification methods.
+const (
+	// MethodProgress reports the progress of a request.
+	MethodProgress = "notifications/progress"
+
+	// MethodLogMessage carries a log message from the server.
+	MethodLogMessage = "notifications/message"
+)
+
+// LoggingLevel is the severity of a log message, as defined by RFC 5424.
+type LoggingLevel string
+
+// Logging levels, from least to most severe.
+const (
+	LogDebug     LoggingLevel = "debug"
+	LogInfo      LoggingLevel = "info"
+	LogNotice    LoggingLevel = "notice"
+	LogWarning   LoggingLevel = "warning"
+	LogError     LoggingLevel = "error"
+	LogCritical  LoggingLevel = "critical"
+	LogAlert     LoggingLevel = "alert"
+	LogEmergency LoggingLevel = "emergency"
+)
+
+// logLevels lists the logging levels from least to most severe.
+var logLevels = []LoggingLevel{LogDebug, LogInfo, LogNotice, LogWarning, LogError, LogCritical, LogAlert, LogEmergency}
+
+// severity returns the rank of the level, or -1 if it is unknown.
+func (l LoggingLevel) severity() int {
+	return slices.Index(logLevels, l)
+}
+
+// ProgressParams are the params of a notifications/progress notification.
+type ProgressParams struct {
+	// ProgressToken is the token from the _meta of the request whose
+	// progress is reported.
+	ProgressToken any `json:"progressToken"`
+	// Progress is the work done so far. It increases with every
+	// notification.
+	Progress float64 `json:"progress"`
+	// Total is the total work, if known.
+	Total float64 `json:"total,omitempty"`
+	// Message describes the current step.
+	Message string `json:"message,omitempty"`
+}
+
+// LogMessageParams are the params of a notifications/message notification.
+type LogMessageParams struct {
+	Level  LoggingLevel `json:"level"`
+	Logger string       `json:"logger,omitempty"`
+	Data   any          `json:"data"`
+}
+
+// SetLevelParams are the params of the "logging/setLevel" method.
+type SetLevelParams struct {
+	Level LoggingLevel `json:"level"`
+}
+
+// ReportProgress reports the progress of the tool call running with ctx.
+// It is a no-op when the client did not ask for progress with a progress
+// token, or does not accept an event stream. progress must increase with
+// each call; total is 0 if unknown.
+func ReportProgress(ctx context.Context, progress, total float64, message string) error {
+	call, ok := ctx.Value(callKey{}).(*callNotifier)
+	if !ok || call.progressToken == nil {
+		return nil
+	}
+	return call.notify(MethodProgress, ProgressParams{
+		ProgressToken: call.progressToken,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
+// LogMessage sends a log message to the client of the tool call running
+// with ctx, if it accepts an event stream and level is at or above the
+// level the client set with logging/setLevel.
+func LogMessage(ctx context.Context, level LoggingLevel, logger string, data any) error {
+	call, ok := ctx.Value(callKey{}).(*callNotifier)
+	if !ok || !call.server.logEnabled(level) {
+		return nil
+	}
+	return call.notify(MethodLogMessage, LogMessageParams{Level: level, Logger: logger, Data: data})
+}
+
+// LogMessage sends a log message to all clients listening on the GET event
+// stream, if level is at or above the level set with logging/setLevel.
+// Messages to slow listeners are dropped.
+func (s *MCPServer) LogMessage(level LoggingLevel, logger string, data any) error {
+	if !s.logEnabled(level) {
+		return nil
+	}
+	n, err := newNotification(MethodLogMessage, LogMessageParams{Level: level, Logger: logger, Data: data})
+	if err != nil {
+		return err
+	}
+	s.broadcast(n)
+	return nil
+}
+
+// logEnabled reports whether messages at level should be sent.
+func (s *MCPServer) logEnabled(level LoggingLevel) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logLevel == "" || level.severity() >= s.logLevel.severity()
+}
+
+func (s *MCPServer) handleSetLogLevel(req Request) (any, *RPCError) {
+	var params SetLevelParams
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if params.Level.severity() < 0 {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: fmt.Sprintf("unknown logging level %q", params.Level)}
+	}
+
+	s.mu.Lock()
+	s.logLevel = params.Level
+	s.mu.Unlock()
+	return map[string]any{}, nil
+}
+
+// broadcast sends n to every listener.
+func (s *MCPServer) broadcast(n Notification) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	for l := range s.listeners {
+		l.enqueue(n)
+	}
+}
+
+// listener receives the notifications the server sends outside of any
+// request, on a GET event stream or a stdio connection.
+type listener struct {
+	queue chan Notification
+	// session is the listener's session, if any.
+	session *session
+}
+
+// enqueue queues n, dropping it if the listener is too slow.
+func (l *listener) enqueue(n Notification) {
+	select {
+	case l.queue <- n:
+	default:
+	}
+}
+
+// addListener registers a listener for sess, which may be nil.
+func (s *MCPServer) addListener(sess *session) *listener {
+	l := &listener{queue: make(chan Notification, 64), session: sess}
+	s.listenersMu.Lock()
+	s.listeners[l] = struct{}{}
+	s.listenersMu.Unlock()
+	return l
+}
+
+// removeListener unregisters l.
+func (s *MCPServer) removeListener(l *listener) {
+	s.listenersMu.Lock()
+	delete(s.listeners, l)
+	s.listenersMu.Unlock()
+}
+
+// handleEventStream serves the GET event stream, over which the server
+// sends notifications unrelated to any request until the client
+// disconnects. Streams opened with a session also receive the session's
+// resource updates.
+func (s *MCPServer) handleEventStream(w http.ResponseWriter, r *http.Request, sess *session) {
+	// Register before sending the headers, so that the client receives
+	// everything sent once it sees the response.
+	l := s.addListener(sess)
+	defer s.removeListener(l)
+
+	stream := newSSEStream(w)
+	stream.start()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case n := <-l.queue:
+			if err := stream.send(n); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// callKey is the context key of the callNotifier of a tool call.
+type callKey struct{}
+
+// sender sends JSON-RPC messages to a client.
+type sender interface {
+	send(msg any) error
+}
+
+// callNotifier sends notifications about a tool call to its client.
+type callNotifier struct {
+	server        *MCPServer
+	out           sender
+	progressToken any
+}
+
+// withNotifier returns ctx carrying a notifier sending over out.
+func withNotifier(ctx context.Context, s *MCPServer, out sender, token any) context.Context {
+	return context.WithValue(ctx, callKey{}, &callNotifier{server: s, out: out, progressToken: token})
+}
+
+// notify sends a notification with method and params.
+func (c *callNotifier) notify(method string, params any) error {
+	n, err := newNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return c.out.send(n)
+}
+
+// newNotification creates a notification with params encoded.
+func newNotification(method string, params any) (Notification, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return Notification{}, core.Errorf(core.ErrInvalidInput, "mcp: marshal %s params: %w", method, err)
+	}
+	return Notification{JSONRPC: "2.0", Method: method, Params: data}, nil
+}
+
+// progressToken returns the progress token in the _meta of request params,
+// or nil.
+func progressToken(params any) any {
+	var p struct {
+		Meta *RequestMeta `json:"_meta"`
+	}
+	if err := decodeParams(params, &p); err != nil || p.Meta == nil {
+		return nil
+	}
+	return p.Meta.ProgressToken
+}
+
+// acceptsEventStream reports whether the request accepts a
+// text/event-stream response.
+func acceptsEventStream(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		for part := range strings.SplitSeq(v, ",") {
+			mediaType, _, _ := strings.Cut(part, ";")
+			if strings.TrimSpace(mediaType) == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sseStream writes JSON-RPC messages as server-sent events. It is safe for
+// concurrent use.
+type sseStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	started bool
+	closed  bool
+}
+
+func newSSEStream(w http.ResponseWriter) *sseStream {
+	return &sseStream{w: w}
+}
+
+// send writes msg as an SSE message event. After close only the final
+// response can be sent.
+func (s *sseStream) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "mcp: marshal message: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := msg.(Notification); ok && s.closed {
+		return core.Errorf(core.ErrInvalidInput, "mcp: notification sent after the request completed")
+	}
+	s.startLocked()
+	if _, err := fmt.Fprintf(s.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp: write event: %w", err)
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// start sends the response headers.
+func (s *sseStream) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startLocked()
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// startLocked sends the response headers if not yet sent. Caller must hold
+// s.mu.
+func (s *sseStream) startLocked() {
+	if s.started {
+		return
+	}
+	s.started = true
+	h := s.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+// close stops further notifications on the stream.
+func (s *sseStream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
@@ -0,0 +1,275 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// newProgressTool returns a tool that reports three steps of progress and
+// logs a message at each level given.
+func newProgressTool(levels ...LoggingLevel) *mockTool {
+	return &mockTool{
+		name:        "work",
+		description: "Does some work",
+		inputSchema: map[string]any{"type": "object"},
+		executeFn: func(ctx context.Context, _ map[string]any) (*tool.Result, error) {
+			for i := 1; i <= 3; i++ {
+				if err := ReportProgress(ctx, float64(i), 3, "step"); err != nil {
+					return nil, err
+				}
+			}
+			for _, l := range levels {
+				if err := LogMessage(ctx, l, "work", string(l)); err != nil {
+					return nil, err
+				}
+			}
+			return tool.TextResult("done"), nil
+		},
+	}
+}
+
+// notificationRecorder records the notifications passed to its handler.
+type notificationRecorder struct {
+	mu    sync.Mutex
+	items []Notification
+}
+
+func (r *notificationRecorder) handle(_ context.Context, n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, n)
+}
+
+func (r *notificationRecorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var methods []string
+	for _, n := range r.items {
+		methods = append(methods, n.Method)
+	}
+	return methods
+}
+
+func TestClient_CallToolWithProgress(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(newProgressTool(LogInfo))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	rec := &notificationRecorder{}
+	client := NewClient(ts.URL, WithNotificationHandler(rec.handle))
+
+	var progress []ProgressParams
+	result, err := client.CallToolWithProgress(context.Background(), "work", nil, func(p ProgressParams) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("CallToolWithProgress error: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "done" {
+		t.Errorf("result = %+v, want text %q", result, "done")
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("got %d progress notifications, want 3", len(progress))
+	}
+	for i, p := range progress {
+		if p.Progress != float64(i+1) || p.Total != 3 || p.Message != "step" {
+			t.Errorf("progress[%d] = %+v", i, p)
+		}
+	}
+
+	want := []string{MethodProgress, MethodProgress, MethodProgress, MethodLogMessage}
+	if got := rec.methods(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("notifications = %v, want %v", got, want)
+	}
+}
+
+func TestClient_CallTool_NoProgressToken(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(newProgressTool(LogWarning))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	rec := &notificationRecorder{}
+	client := NewClient(ts.URL, WithNotificationHandler(rec.handle))
+
+	if _, err := client.CallTool(context.Background(), "work", nil); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if got := rec.methods(); len(got) != 1 || got[0] != MethodLogMessage {
+		t.Errorf("notifications = %v, want only a log message", got)
+	}
+}
+
+func TestServer_ToolsCall_JSONOnlyClient(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(newProgressTool(LogError))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"work","_meta":{"progressToken":"p1"}}}`
+	resp, err := http.Post(ts.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestClient_SetLogLevel(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(newProgressTool(LogDebug, LogInfo, LogWarning, LogCritical))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	var levels []LoggingLevel
+	client := NewClient(ts.URL, WithNotificationHandler(func(_ context.Context, n Notification) {
+		if n.Method != MethodLogMessage {
+			return
+		}
+		var p LogMessageParams
+		if err := decodeParams(n.Params, &p); err != nil {
+			t.Errorf("decode log message: %v", err)
+		}
+		levels = append(levels, p.Level)
+	}))
+
+	ctx := context.Background()
+	if err := client.SetLogLevel(ctx, LogWarning); err != nil {
+		t.Fatalf("SetLogLevel error: %v", err)
+	}
+	if _, err := client.CallTool(ctx, "work", nil); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if len(levels) != 2 || levels[0] != LogWarning || levels[1] != LogCritical {
+		t.Errorf("levels = %v, want [warning critical]", levels)
+	}
+}
+
+func TestClient_SetLogLevel_Invalid(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	err := client.SetLogLevel(context.Background(), "verbose")
+	if err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if !strings.Contains(err.Error(), "unknown logging level") {
+		t.Errorf("error = %v, want unknown logging level", err)
+	}
+}
+
+func TestNewServer_LoggingCapability(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	caps, err := NewClient(ts.URL).Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if caps.Logging == nil {
+		t.Error("expected logging capability")
+	}
+}
+
+func TestClient_Listen(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	received := make(chan Notification, 1)
+	client := NewClient(ts.URL, WithNotificationHandler(func(_ context.Context, n Notification) {
+		select {
+		case received <- n:
+		default:
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- client.Listen(ctx) }()
+
+	// Broadcast until the listener has registered.
+	deadline := time.After(5 * time.Second)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	var n Notification
+loop:
+	for {
+		select {
+		case n = <-received:
+			break loop
+		case <-ticker.C:
+			if err := srv.LogMessage(LogNotice, "server", "hello"); err != nil {
+				t.Fatalf("LogMessage error: %v", err)
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting for notification")
+		}
+	}
+
+	var p LogMessageParams
+	if err := decodeParams(n.Params, &p); err != nil {
+		t.Fatalf("decode log message: %v", err)
+	}
+	if n.Method != MethodLogMessage || p.Level != LogNotice || p.Logger != "server" || p.Data != "hello" {
+		t.Errorf("notification = %s %+v", n.Method, p)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Listen error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen did not return after cancel")
+	}
+}
+
+func TestClient_Listen_NoEventStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer ts.Close()
+
+	if err := NewClient(ts.URL).Listen(context.Background()); err == nil {
+		t.Fatal("expected error when the server offers no event stream")
+	}
+}
+
+func TestAcceptsEventStream(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"text/event-stream", true},
+		{"application/json, text/event-stream", true},
+		{"application/json,text/event-stream;q=0.5", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		if got := acceptsEventStream(r); got != tt.want {
+			t.Errorf("acceptsEventStream(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
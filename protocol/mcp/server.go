@@ -3,11 +3,13 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/lookatitude/beluga-ai/v2/auth"
 	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 	"github.com/lookatitude/beluga-ai/v2/tool"
@@ -23,20 +25,46 @@ type MCPServer struct {
 	resources    []Resource
 	prompts      []Prompt
 	capabilities ServerCapabilities
+	logLevel     LoggingLevel
+	verifier     TokenVerifier
+	policy       auth.Policy
 	mu           sync.RWMutex
+
+	// listeners receive notifications sent outside of any request.
+	listenersMu sync.Mutex
+	listeners   map[*listener]struct{}
+
+	// sessions are the client sessions by ID.
+	sessionsMu sync.Mutex
+	sessions   map[string]*session
 }
 
 // NewServer creates a new MCP server with the given name and version.
-func NewServer(name, version string) *MCPServer {
-	return &MCPServer{
+func NewServer(name, version string, opts ...ServerOption) *MCPServer {
+	s := &MCPServer{
 		name:    name,
 		version: version,
 		capabilities: ServerCapabilities{
 			Tools:     &ToolCapability{},
-			Resources: &ResourceCapability{},
+			Resources: &ResourceCapability{Subscribe: true},
 			Prompts:   &PromptCapability{},
+			Logging:   &LoggingCapability{},
 		},
+		listeners: make(map[*listener]struct{}),
+		sessions:  make(map[string]*session),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.verifier != nil {
+		oauth := OAuthCapability{}
+		if s.capabilities.OAuth != nil {
+			oauth = *s.capabilities.OAuth
+		}
+		oauth.Required = true
+		s.capabilities.OAuth = &oauth
 	}
+	return s
 }
 
 // AddTool registers a Beluga tool with the MCP server.
@@ -102,13 +130,54 @@ func (s *MCPServer) Serve(ctx context.Context, addr string) error {
 }
 
 func (s *MCPServer) handleRequest(w http.ResponseWriter, r *http.Request) {
+	if s.verifier != nil {
+		ctx, ok := s.authenticate(w, r)
+		if !ok {
+			return
+		}
+		r = r.WithContext(ctx)
+	}
+	sess, ok := s.lookupSession(r)
+	if !ok || (sess != nil && sess.subject != caller(r.Context())) {
+		writeErrorStatus(w, http.StatusNotFound, CodeInvalidRequest, "unknown session")
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && acceptsEventStream(r):
+		s.handleEventStream(w, r, sess)
+		return
+	case r.Method == http.MethodDelete && sess != nil:
+		s.endSession(sess)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
 	if r.Method != http.MethodPost {
 		writeError(w, nil, CodeInvalidRequest, "only POST is supported")
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, nil, CodeParseError, "read body: "+err.Error())
+		return
+	}
+	ctx := r.Context()
+	if sess != nil {
+		ctx = withSession(ctx, sess)
+	}
+
+	if isBatch(body) {
+		batch, rpcErr := parseBatch(body)
+		if rpcErr != nil {
+			writeResponse(w, Response{JSONRPC: "2.0", Error: rpcErr})
+			return
+		}
+		writeBatch(w, s.handleBatch(ctx, batch, s.dispatch))
+		return
+	}
+
 	var req Request
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		writeError(w, nil, CodeParseError, "invalid JSON: "+err.Error())
 		return
 	}
@@ -118,27 +187,67 @@ func (s *MCPServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Method == "initialize" {
+		if sess, err = s.newSession(caller(r.Context())); err != nil {
+			writeError(w, req.ID, CodeInternalError, "create session: "+err.Error())
+			return
+		}
+		w.Header().Set(SessionHeader, sess.id)
+		ctx = withSession(ctx, sess)
+	}
+
+	// Tool calls may send notifications while they run; stream them to
+	// clients that accept an event stream, ahead of the response.
+	if req.Method == "tools/call" && acceptsEventStream(r) {
+		stream := newSSEStream(w)
+		ctx = withNotifier(ctx, s, stream, progressToken(req.Params))
+		resp := s.dispatch(ctx, req)
+		stream.close()
+		_ = stream.send(resp)
+		return
+	}
+
+	writeResponse(w, s.dispatch(ctx, req))
+}
+
+// dispatch processes a JSON-RPC request and returns its response.
+func (s *MCPServer) dispatch(ctx context.Context, req Request) Response {
+	var (
+		result any
+		rpcErr *RPCError
+	)
 	switch req.Method {
 	case "initialize":
-		s.handleInitialize(w, req)
+		result = s.handleInitialize()
 	case "tools/list":
-		s.handleToolsList(w, req)
+		result = s.handleToolsList()
 	case "tools/call":
-		s.handleToolsCall(r.Context(), w, req)
+		result, rpcErr = s.handleToolsCall(ctx, req)
 	case "resources/list":
-		s.handleResourcesList(w, req)
+		result = s.handleResourcesList()
+	case "resources/subscribe":
+		result, rpcErr = s.handleSubscribe(ctx, req, true)
+	case "resources/unsubscribe":
+		result, rpcErr = s.handleSubscribe(ctx, req, false)
 	case "prompts/list":
-		s.handlePromptsList(w, req)
+		result = s.handlePromptsList()
+	case "logging/setLevel":
+		result, rpcErr = s.handleSetLogLevel(req)
 	default:
-		writeError(w, req.ID, CodeMethodNotFound, "unknown method: "+req.Method)
+		rpcErr = &RPCError{Code: CodeMethodNotFound, Message: "unknown method: " + req.Method}
 	}
+
+	if rpcErr != nil {
+		return Response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return Response{JSONRPC: "2.0", ID: req.ID, Result: result}
 }
 
-func (s *MCPServer) handleInitialize(w http.ResponseWriter, req Request) {
+func (s *MCPServer) handleInitialize() any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	result := InitializeResult{
+	return InitializeResult{
 		ProtocolVersion: "2025-03-26",
 		ServerInfo: ServerInfo{
 			Name:    s.name,
@@ -146,42 +255,27 @@ func (s *MCPServer) handleInitialize(w http.ResponseWriter, req Request) {
 		},
 		Capabilities: s.capabilities,
 	}
-
-	writeResult(w, req.ID, result)
 }
 
-func (s *MCPServer) handleToolsList(w http.ResponseWriter, req Request) {
+func (s *MCPServer) handleToolsList() any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	tools := make([]ToolInfo, len(s.tools))
 	for i, t := range s.tools {
-		tools[i] = ToolInfo{
-			Name:        t.Name(),
-			Description: t.Description(),
-			InputSchema: t.InputSchema(),
-		}
+		tools[i] = newToolInfo(t)
 	}
 
-	writeResult(w, req.ID, map[string]any{"tools": tools})
+	return map[string]any{"tools": tools}
 }
 
-func (s *MCPServer) handleToolsCall(ctx context.Context, w http.ResponseWriter, req Request) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	paramsBytes, err := json.Marshal(req.Params)
-	if err != nil {
-		writeError(w, req.ID, CodeInvalidParams, "invalid params: "+err.Error())
-		return
-	}
-
+func (s *MCPServer) handleToolsCall(ctx context.Context, req Request) (any, *RPCError) {
 	var params ToolCallParams
-	if err := json.Unmarshal(paramsBytes, &params); err != nil {
-		writeError(w, req.ID, CodeInvalidParams, "invalid params: "+err.Error())
-		return
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
 	}
 
+	s.mu.RLock()
 	var target tool.Tool
 	for _, t := range s.tools {
 		if t.Name() == params.Name {
@@ -189,16 +283,18 @@ func (s *MCPServer) handleToolsCall(ctx context.Context, w http.ResponseWriter,
 			break
 		}
 	}
+	s.mu.RUnlock()
 
 	if target == nil {
-		writeError(w, req.ID, CodeInvalidParams, "unknown tool: "+params.Name)
-		return
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+	if rpcErr := s.authorizeTool(ctx, params.Name); rpcErr != nil {
+		return nil, rpcErr
 	}
 
 	result, err := target.Execute(ctx, params.Arguments)
 	if err != nil {
-		writeError(w, req.ID, CodeInternalError, "tool execution failed: "+err.Error())
-		return
+		return nil, &RPCError{Code: CodeInternalError, Message: "tool execution failed: " + err.Error()}
 	}
 
 	content := make([]ContentItem, 0, len(result.Content))
@@ -208,48 +304,57 @@ func (s *MCPServer) handleToolsCall(ctx context.Context, w http.ResponseWriter,
 		}
 	}
 
-	writeResult(w, req.ID, ToolCallResult{
+	return ToolCallResult{
 		Content: content,
 		IsError: result.IsError,
-	})
+	}, nil
 }
 
-func (s *MCPServer) handleResourcesList(w http.ResponseWriter, req Request) {
+func (s *MCPServer) handleResourcesList() any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	resources := make([]Resource, len(s.resources))
 	copy(resources, s.resources)
 
-	writeResult(w, req.ID, map[string]any{"resources": resources})
+	return map[string]any{"resources": resources}
 }
 
-func (s *MCPServer) handlePromptsList(w http.ResponseWriter, req Request) {
+func (s *MCPServer) handlePromptsList() any {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	prompts := make([]Prompt, len(s.prompts))
 	copy(prompts, s.prompts)
 
-	writeResult(w, req.ID, map[string]any{"prompts": prompts})
+	return map[string]any{"prompts": prompts}
 }
 
-func writeResult(w http.ResponseWriter, id any, result any) {
-	resp := Response{
-		JSONRPC: "2.0",
-		ID:      id,
-		Result:  result,
+// decodeParams decodes JSON-RPC request params into v.
+func decodeParams(params any, v any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
 	}
+	return json.Unmarshal(data, v)
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// writeErrorStatus writes a JSON-RPC error response with an HTTP status.
+func writeErrorStatus(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	writeError(w, nil, code, message)
+}
+
 func writeError(w http.ResponseWriter, id any, code int, message string) {
-	resp := Response{
+	writeResponse(w, Response{
 		JSONRPC: "2.0",
 		ID:      id,
 		Error:   &RPCError{Code: code, Message: message},
-	}
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(resp)
+	})
 }
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// SessionHeader is the HTTP header carrying the session ID the server
+// assigns in its response to initialize.
+const SessionHeader = "Mcp-Session-Id"
+
+// MethodResourceUpdated notifies a client that a resource it subscribed to
+// has changed.
+const MethodResourceUpdated = "notifications/resources/updated"
+
+// SubscribeParams are the params of the "resources/subscribe" and
+// "resources/unsubscribe" methods.
+type SubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedParams are the params of a
+// notifications/resources/updated notification.
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
+// session is the state the server keeps for a client between requests.
+type session struct {
+	id string
+	// subject is the authenticated caller that started the session.
+	subject string
+
+	mu            sync.Mutex
+	subscriptions map[string]struct{}
+}
+
+// subscribed reports whether the session is subscribed to uri.
+func (s *session) subscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.subscriptions[uri]
+	return ok
+}
+
+// sessionKey is the context key of the session of a request.
+type sessionKey struct{}
+
+// withSession returns ctx carrying sess.
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFrom returns the session carried by ctx, or nil.
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// newSession creates and registers a session for subject.
+func (s *MCPServer) newSession(subject string) (*session, error) {
+	id, err := generateOpID()
+	if err != nil {
+		return nil, err
+	}
+	sess := &session{id: id, subject: subject, subscriptions: make(map[string]struct{})}
+
+	s.sessionsMu.Lock()
+	s.sessions[id] = sess
+	s.sessionsMu.Unlock()
+	return sess, nil
+}
+
+// lookupSession returns the session named by the request's session header.
+// ok is false if the header names an unknown or terminated session.
+func (s *MCPServer) lookupSession(r *http.Request) (sess *session, ok bool) {
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		return nil, true
+	}
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	sess, ok = s.sessions[id]
+	return sess, ok
+}
+
+// endSession terminates a session.
+func (s *MCPServer) endSession(sess *session) {
+	s.sessionsMu.Lock()
+	delete(s.sessions, sess.id)
+	s.sessionsMu.Unlock()
+}
+
+// NotifyResourceUpdated tells the clients subscribed to the resource at uri
+// that it has changed. Notifications are sent on the GET event streams or
+// stdio connections of the subscribed sessions; HTTP sessions without an
+// open stream miss them, and messages to slow listeners are dropped.
+func (s *MCPServer) NotifyResourceUpdated(uri string) error {
+	n, err := newNotification(MethodResourceUpdated, ResourceUpdatedParams{URI: uri})
+	if err != nil {
+		return err
+	}
+
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	for l := range s.listeners {
+		if l.session != nil && l.session.subscribed(uri) {
+			l.enqueue(n)
+		}
+	}
+	return nil
+}
+
+// handleSubscribe adds or removes a subscription of the request's session.
+func (s *MCPServer) handleSubscribe(ctx context.Context, req Request, subscribe bool) (any, *RPCError) {
+	sess := sessionFrom(ctx)
+	if sess == nil {
+		return nil, &RPCError{Code: CodeInvalidRequest, Message: req.Method + " requires a session; call initialize first"}
+	}
+
+	var params SubscribeParams
+	if err := decodeParams(req.Params, &params); err != nil {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if !subscribe {
+		delete(sess.subscriptions, params.URI)
+		return map[string]any{}, nil
+	}
+	if !s.hasResource(params.URI) {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown resource: " + params.URI}
+	}
+	sess.subscriptions[params.URI] = struct{}{}
+	return map[string]any{}, nil
+}
+
+// hasResource reports whether a resource with uri is registered.
+func (s *MCPServer) hasResource(uri string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, r := range s.resources {
+		if r.URI == uri {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sessionCount returns the number of open sessions on srv.
+func sessionCount(srv *MCPServer) int {
+	srv.sessionsMu.Lock()
+	defer srv.sessionsMu.Unlock()
+	return len(srv.sessions)
+}
+
+// subscribedSessions returns the number of sessions subscribed to uri.
+func subscribedSessions(srv *MCPServer, uri string) int {
+	srv.sessionsMu.Lock()
+	defer srv.sessionsMu.Unlock()
+	n := 0
+	for _, sess := range srv.sessions {
+		if sess.subscribed(uri) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestClient_SubscribeResource(t *testing.T) {
+	srv, ts := setupTestServer()
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Notify until the subscription is established and delivers.
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = srv.NotifyResourceUpdated("file:///other.txt")
+				_ = srv.NotifyResourceUpdated("file:///test.txt")
+			}
+		}
+	}()
+
+	var got []string
+	for update, err := range client.SubscribeResource(ctx, "file:///test.txt") {
+		if err != nil {
+			t.Fatalf("SubscribeResource error: %v", err)
+		}
+		got = append(got, update.URI)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != "file:///test.txt" || got[1] != "file:///test.txt" {
+		t.Errorf("updates = %v, want two for file:///test.txt", got)
+	}
+
+	if n := subscribedSessions(srv, "file:///test.txt"); n != 0 {
+		t.Errorf("%d sessions still subscribed after the iterator stopped", n)
+	}
+}
+
+func TestClient_SubscribeResource_ContextCancel(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for _, err := range NewClient(ts.URL).SubscribeResource(ctx, "file:///test.txt") {
+		t.Fatalf("unexpected update or error: %v", err)
+	}
+}
+
+func TestClient_SubscribeResource_UnknownResource(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	var err error
+	for _, err = range NewClient(ts.URL).SubscribeResource(context.Background(), "file:///missing.txt") {
+		break
+	}
+	if err == nil || !strings.Contains(err.Error(), "unknown resource") {
+		t.Errorf("error = %v, want unknown resource", err)
+	}
+}
+
+func TestServer_Subscribe_RequiresSession(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///test.txt"}}`
+	resp, err := http.Post(ts.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var rpcResp Response
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if rpcResp.Error == nil || rpcResp.Error.Code != CodeInvalidRequest {
+		t.Errorf("error = %+v, want invalid request", rpcResp.Error)
+	}
+}
+
+func TestServer_UnknownSession(t *testing.T) {
+	_, ts := setupTestServer()
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	req.Header.Set(SessionHeader, "nope")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	srv, ts := setupTestServer()
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if client.session() == "" {
+		t.Fatal("expected a session ID after Initialize")
+	}
+	if n := sessionCount(srv); n != 1 {
+		t.Fatalf("sessions = %d, want 1", n)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if n := sessionCount(srv); n != 0 {
+		t.Errorf("sessions = %d after Close, want 0", n)
+	}
+
+	// Without a session the client keeps working statelessly.
+	if _, err := client.ListTools(ctx); err != nil {
+		t.Errorf("ListTools after Close: %v", err)
+	}
+}
+
+func TestNewServer_ResourceSubscribeCapability(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	caps, err := NewClient(ts.URL).Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if caps.Resources == nil || !caps.Resources.Subscribe {
+		t.Error("expected resource subscribe capability")
+	}
+}
@@ -0,0 +1,514 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"os/exec"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ServeStdio serves MCP over the stdio transport, reading newline-delimited
+// JSON-RPC messages from r and writing responses and notifications to w,
+// as MCP hosts do with the stdin and stdout of a server they launch:
+//
+//	err := srv.ServeStdio(ctx, os.Stdin, os.Stdout)
+//
+// Requests and batches are dispatched exactly as over HTTP, concurrently,
+// and the connection forms a single session. ServeStdio returns nil when r reaches
+// EOF, after answering the requests in flight, or ctx.Err() when ctx is
+// cancelled; a Read blocked on r is not interrupted.
+func (s *MCPServer) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	out := &stdioWriter{w: w}
+	sess := &session{subscriptions: make(map[string]struct{})}
+
+	// Forward the notifications sent outside of any request.
+	l := s.addListener(sess)
+	var forwarder sync.WaitGroup
+	forwarder.Add(1)
+	go func() {
+		defer forwarder.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-l.queue:
+				_ = out.send(n) // #nosec G104 -- nothing to report to
+			}
+		}
+	}()
+
+	var requests sync.WaitGroup
+	defer func() {
+		requests.Wait()
+		cancel()
+		forwarder.Wait()
+		s.removeListener(l)
+	}()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return core.Errorf(core.ErrProviderDown, "mcp/serve_stdio: read: %w", err)
+		case line := <-lines:
+			if isBatch(line) {
+				requests.Add(1)
+				go func() {
+					defer requests.Done()
+					s.serveStdioBatch(ctx, sess, out, line)
+				}()
+				continue
+			}
+
+			var req Request
+			if err := json.Unmarshal(line, &req); err != nil {
+				_ = out.send(Response{JSONRPC: "2.0", Error: &RPCError{Code: CodeParseError, Message: "invalid JSON: " + err.Error()}})
+				continue
+			}
+			if req.JSONRPC != "2.0" {
+				_ = out.send(Response{JSONRPC: "2.0", ID: req.ID, Error: &RPCError{Code: CodeInvalidRequest, Message: "jsonrpc must be \"2.0\""}})
+				continue
+			}
+			// Notifications and responses from the client need no answer.
+			if req.ID == nil || req.Method == "" {
+				continue
+			}
+
+			requests.Add(1)
+			go func() {
+				defer requests.Done()
+				call := &stdioCall{out: out}
+				ctx := withNotifier(withSession(ctx, sess), s, call, progressToken(req.Params))
+				resp := s.dispatch(ctx, req)
+				call.close()
+				_ = out.send(resp)
+			}()
+		}
+	}
+}
+
+// serveStdioBatch answers a batch read from a stdio connection with a
+// single line, or none if the batch holds only notifications.
+func (s *MCPServer) serveStdioBatch(ctx context.Context, sess *session, out *stdioWriter, line []byte) {
+	batch, rpcErr := parseBatch(line)
+	if rpcErr != nil {
+		_ = out.send(Response{JSONRPC: "2.0", Error: rpcErr})
+		return
+	}
+	responses := s.handleBatch(withSession(ctx, sess), batch, func(ctx context.Context, req Request) Response {
+		call := &stdioCall{out: out}
+		defer call.close()
+		return s.dispatch(withNotifier(ctx, s, call, progressToken(req.Params)), req)
+	})
+	if len(responses) > 0 {
+		_ = out.send(responses)
+	}
+}
+
+// stdioWriter writes JSON-RPC messages as lines. It is safe for concurrent
+// use.
+type stdioWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// send writes msg followed by a newline.
+func (s *stdioWriter) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "mcp: marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(data); err != nil {
+		return core.Errorf(core.ErrProviderDown, "mcp: write message: %w", err)
+	}
+	return nil
+}
+
+// stdioCall sends the notifications of a request over a stdio connection
+// until the request completes.
+type stdioCall struct {
+	out *stdioWriter
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *stdioCall) send(msg any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return core.Errorf(core.ErrInvalidInput, "mcp: notification sent after the request completed")
+	}
+	return c.out.send(msg)
+}
+
+// close stops further notifications.
+func (c *stdioCall) close() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+}
+
+// NewStdioClient starts cmd as an MCP server on the stdio transport and
+// returns a client talking to it over the command's stdin and stdout, which
+// must not be set. The server's stderr is discarded unless cmd.Stderr is
+// set. Close the client to stop the server:
+//
+//	client, err := mcp.NewStdioClient(exec.Command("my-mcp-server", "--stdio"))
+//	if err != nil { return err }
+//	defer client.Close(ctx)
+//	caps, err := client.Initialize(ctx)
+//
+// Over stdio the notification handler receives every notification the
+// server sends as it arrives.
+func NewStdioClient(cmd *exec.Cmd, opts ...ClientOption) (*MCPClient, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "mcp/stdio: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "mcp/stdio: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "mcp/stdio: start server: %w", err)
+	}
+
+	c := NewClient("", opts...)
+	c.stdio = newStdioTransport(c, stdin, stdout, cmd)
+	return c, nil
+}
+
+// errServerClosed reports that the stdio server closed its output.
+var errServerClosed = errors.New("server closed the connection")
+
+// stdioTransport carries a client's requests over a stdio connection,
+// matching responses to requests by ID.
+type stdioTransport struct {
+	client *MCPClient
+	stdin  io.WriteCloser
+	// cmd is the server process, if the client started it.
+	cmd *exec.Cmd
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	pending  map[string]chan incomingMessage
+	watchers map[*watcher]struct{}
+	err      error
+
+	// done is closed when the server's output ends.
+	done chan struct{}
+}
+
+// watcher observes the notifications read from the server.
+type watcher struct {
+	fn func(Notification)
+}
+
+// newStdioTransport starts reading the server's messages from stdout.
+func newStdioTransport(c *MCPClient, stdin io.WriteCloser, stdout io.Reader, cmd *exec.Cmd) *stdioTransport {
+	t := &stdioTransport{
+		client:   c,
+		stdin:    stdin,
+		cmd:      cmd,
+		pending:  make(map[string]chan incomingMessage),
+		watchers: make(map[*watcher]struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.read(stdout)
+	return t
+}
+
+// read dispatches the messages read from r until it ends.
+func (t *stdioTransport) read(r io.Reader) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			t.handle(line)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errServerClosed
+			}
+			t.mu.Lock()
+			t.err = err
+			t.mu.Unlock()
+			close(t.done)
+			return
+		}
+	}
+}
+
+// handle dispatches a message or batch of messages from the server.
+func (t *stdioTransport) handle(line []byte) {
+	if isBatch(line) {
+		var batch []json.RawMessage
+		if json.Unmarshal(line, &batch) != nil {
+			return
+		}
+		for _, raw := range batch {
+			t.handle(raw)
+		}
+		return
+	}
+
+	var msg incomingMessage
+	if json.Unmarshal(line, &msg) != nil {
+		return
+	}
+
+	switch {
+	case msg.Method != "" && msg.ID != nil:
+		// A request from the server; only ping is supported.
+		resp := Response{JSONRPC: "2.0", ID: msg.ID}
+		if msg.Method == "ping" {
+			resp.Result = map[string]any{}
+		} else {
+			resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "unknown method: " + msg.Method}
+		}
+		_ = t.write(resp) // #nosec G104 -- a broken connection surfaces on the next request
+
+	case msg.Method != "":
+		n := msg.notification()
+		t.client.notify(context.Background(), n, nil)
+		t.mu.Lock()
+		watchers := make([]*watcher, 0, len(t.watchers))
+		for w := range t.watchers {
+			watchers = append(watchers, w)
+		}
+		t.mu.Unlock()
+		for _, w := range watchers {
+			w.fn(n)
+		}
+
+	default:
+		key := idKey(msg.ID)
+		t.mu.Lock()
+		ch, ok := t.pending[key]
+		delete(t.pending, key)
+		t.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	}
+}
+
+// write sends msg as a line.
+func (t *stdioTransport) write(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(data); err != nil {
+		return core.Errorf(core.ErrProviderDown, "write message: %w", err)
+	}
+	return nil
+}
+
+// addWatcher registers fn to observe notifications until the returned
+// function is called.
+func (t *stdioTransport) addWatcher(fn func(Notification)) (remove func()) {
+	w := &watcher{fn: fn}
+	t.mu.Lock()
+	t.watchers[w] = struct{}{}
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		delete(t.watchers, w)
+		t.mu.Unlock()
+	}
+}
+
+// roundTrip sends req and waits for its response, passing the notifications
+// read meanwhile to onNotification.
+func (t *stdioTransport) roundTrip(ctx context.Context, req Request, onNotification func(Notification)) (incomingMessage, error) {
+	key := idKey(req.ID)
+	ch := make(chan incomingMessage, 1)
+
+	t.mu.Lock()
+	if t.err != nil {
+		err := t.err
+		t.mu.Unlock()
+		return incomingMessage{}, core.Errorf(core.ErrProviderDown, "stdio: %w", err)
+	}
+	t.pending[key] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}()
+	if onNotification != nil {
+		defer t.addWatcher(onNotification)()
+	}
+
+	if err := t.write(req); err != nil {
+		return incomingMessage{}, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-ctx.Done():
+		return incomingMessage{}, ctx.Err()
+	case <-t.done:
+		select {
+		case resp := <-ch:
+			return resp, nil
+		default:
+		}
+		return incomingMessage{}, core.Errorf(core.ErrProviderDown, "stdio: %w", t.err)
+	}
+}
+
+// roundTripBatch sends reqs as a batch and waits for their responses.
+func (t *stdioTransport) roundTripBatch(ctx context.Context, reqs []Request) ([]incomingMessage, error) {
+	ch := make(chan incomingMessage, len(reqs))
+
+	t.mu.Lock()
+	if t.err != nil {
+		err := t.err
+		t.mu.Unlock()
+		return nil, core.Errorf(core.ErrProviderDown, "stdio: %w", err)
+	}
+	for _, req := range reqs {
+		t.pending[idKey(req.ID)] = ch
+	}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		for _, req := range reqs {
+			delete(t.pending, idKey(req.ID))
+		}
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(reqs); err != nil {
+		return nil, err
+	}
+
+	resps := make([]incomingMessage, 0, len(reqs))
+	for len(resps) < len(reqs) {
+		select {
+		case resp := <-ch:
+			resps = append(resps, resp)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.done:
+			for len(ch) > 0 {
+				resps = append(resps, <-ch)
+			}
+			if len(resps) < len(reqs) {
+				return nil, core.Errorf(core.ErrProviderDown, "stdio: %w", t.err)
+			}
+		}
+	}
+	return resps, nil
+}
+
+// watch returns the notifications read from the server until ctx ends or
+// the server's output ends. Notifications are dropped if the consumer falls
+// behind. Call stop when done.
+func (t *stdioTransport) watch(ctx context.Context) (notifications iter.Seq2[Notification, error], stop func()) {
+	ch := make(chan Notification, 64)
+	stop = t.addWatcher(func(n Notification) {
+		select {
+		case ch <- n:
+		default:
+		}
+	})
+	notifications = func(yield func(Notification, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				yield(Notification{}, ctx.Err())
+				return
+			case <-t.done:
+				return
+			case n := <-ch:
+				if !yield(n, nil) {
+					return
+				}
+			}
+		}
+	}
+	return notifications, stop
+}
+
+// wait blocks until ctx ends or the server's output ends.
+func (t *stdioTransport) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.done:
+		return nil
+	}
+}
+
+// close closes the server's stdin and waits for its output to end, then
+// for the process to exit. The process is killed if ctx ends first.
+func (t *stdioTransport) close(ctx context.Context) error {
+	_ = t.stdin.Close() // #nosec G104 -- closing an already broken pipe is harmless
+
+	var ctxErr error
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		ctxErr = ctx.Err()
+		if t.cmd == nil {
+			return ctxErr
+		}
+		_ = t.cmd.Process.Kill() // #nosec G104 -- the process may have exited already
+		<-t.done
+	}
+	if t.cmd == nil {
+		return nil
+	}
+
+	if err := t.cmd.Wait(); err != nil && ctxErr == nil {
+		return core.Errorf(core.ErrProviderDown, "mcp/close: server exited: %w", err)
+	}
+	return ctxErr
+}
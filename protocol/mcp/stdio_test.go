@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStdioHelperProcess is not a real test: it runs the test server over
+// stdio when the test binary is re-executed by TestNewStdioClient.
+func TestStdioHelperProcess(t *testing.T) {
+	if os.Getenv("MCP_STDIO_HELPER") != "1" {
+		t.Skip("helper process for TestNewStdioClient")
+	}
+	srv, ts := setupTestServer()
+	ts.Close()
+	if err := srv.ServeStdio(context.Background(), os.Stdin, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// connectStdio serves srv over in-memory pipes and returns a client talking
+// to it, and a channel receiving ServeStdio's result.
+func connectStdio(t *testing.T, srv *MCPServer, opts ...ClientOption) (*MCPClient, <-chan error) {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := srv.ServeStdio(context.Background(), serverR, serverW)
+		serverW.Close()
+		done <- err
+	}()
+
+	c := NewClient("", opts...)
+	c.stdio = newStdioTransport(c, clientW, clientR, nil)
+	return c, done
+}
+
+func TestServeStdio(t *testing.T) {
+	srv, ts := setupTestServer()
+	ts.Close()
+	client, done := connectStdio(t, srv)
+	ctx := context.Background()
+
+	caps, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	if caps.Tools == nil {
+		t.Error("expected tools capability")
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools error: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "echo" {
+		t.Errorf("tools = %+v", tools)
+	}
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "echo: hi" {
+		t.Errorf("result = %+v", result)
+	}
+
+	if _, err := client.CallTool(ctx, "missing", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeStdio error: %v", err)
+	}
+}
+
+func TestServeStdio_Notifications(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(newProgressTool(LogInfo))
+	srv.AddResource(Resource{URI: "file:///test.txt", Name: "test-file"})
+
+	rec := &notificationRecorder{}
+	client, _ := connectStdio(t, srv, WithNotificationHandler(rec.handle))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer client.Close(ctx)
+
+	var progress int
+	if _, err := client.CallToolWithProgress(ctx, "work", nil, func(ProgressParams) { progress++ }); err != nil {
+		t.Fatalf("CallToolWithProgress error: %v", err)
+	}
+	if progress != 3 {
+		t.Errorf("got %d progress notifications, want 3", progress)
+	}
+	if got := rec.methods(); len(got) != 4 || got[3] != MethodLogMessage {
+		t.Errorf("notifications = %v", got)
+	}
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = srv.NotifyResourceUpdated("file:///test.txt")
+			}
+		}
+	}()
+	for update, err := range client.SubscribeResource(ctx, "file:///test.txt") {
+		if err != nil {
+			t.Fatalf("SubscribeResource error: %v", err)
+		}
+		if update.URI != "file:///test.txt" {
+			t.Errorf("update URI = %q", update.URI)
+		}
+		break
+	}
+}
+
+func TestServeStdio_InvalidMessages(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	serverR, clientW := io.Pipe()
+	clientR, serverW := io.Pipe()
+	go func() {
+		_ = srv.ServeStdio(context.Background(), serverR, serverW)
+		serverW.Close()
+	}()
+
+	go func() {
+		io.WriteString(clientW, "not json\n")
+		io.WriteString(clientW, `{"jsonrpc":"1.0","id":1,"method":"tools/list"}`+"\n")
+		io.WriteString(clientW, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+		io.WriteString(clientW, `{"jsonrpc":"2.0","id":2,"method":"bogus"}`+"\n")
+		clientW.Close()
+	}()
+
+	var codes []int
+	scanner := bufio.NewScanner(clientR)
+	for scanner.Scan() {
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		if resp.Error == nil {
+			t.Fatalf("expected an error response, got %s", scanner.Text())
+		}
+		codes = append(codes, resp.Error.Code)
+	}
+	want := []int{CodeParseError, CodeInvalidRequest, CodeMethodNotFound}
+	if len(codes) != len(want) {
+		t.Fatalf("codes = %v, want %v", codes, want)
+	}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("codes = %v, want %v", codes, want)
+			break
+		}
+	}
+}
+
+func TestServeStdio_ContextCancel(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeStdio(ctx, r, io.Discard) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("ServeStdio error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeStdio did not return after cancel")
+	}
+}
+
+func TestNewStdioClient(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestStdioHelperProcess$")
+	cmd.Env = append(os.Environ(), "MCP_STDIO_HELPER=1")
+	client, err := NewStdioClient(cmd)
+	if err != nil {
+		t.Fatalf("NewStdioClient error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize error: %v", err)
+	}
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "subprocess"})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if len(result.Content) != 1 || !strings.Contains(result.Content[0].Text, "subprocess") {
+		t.Errorf("result = %+v", result)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if _, err := client.ListTools(ctx); err == nil {
+		t.Error("expected error after Close")
+	}
+}
+
+func TestNewStdioClient_StartError(t *testing.T) {
+	if _, err := NewStdioClient(exec.Command("/nonexistent/mcp-server")); err == nil {
+		t.Fatal("expected error for a missing command")
+	}
+}
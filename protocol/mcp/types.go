@@ -1,5 +1,7 @@
 package mcp
 
+import "encoding/json"
+
 // Request is a JSON-RPC 2.0 request message.
 type Request struct {
 	JSONRPC string `json:"jsonrpc"`
@@ -8,6 +10,14 @@ type Request struct {
 	Params  any    `json:"params,omitempty"`
 }
 
+// Notification is a JSON-RPC 2.0 notification: a message without an ID
+// that expects no response.
+type Notification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
 // Response is a JSON-RPC 2.0 response message.
 type Response struct {
 	JSONRPC string    `json:"jsonrpc"`
@@ -28,6 +38,7 @@ type ServerCapabilities struct {
 	Tools       *ToolCapability        `json:"tools,omitempty"`
 	Resources   *ResourceCapability    `json:"resources,omitempty"`
 	Prompts     *PromptCapability      `json:"prompts,omitempty"`
+	Logging     *LoggingCapability     `json:"logging,omitempty"`
 	Async       *AsyncCapability       `json:"async,omitempty"`
 	Elicitation *ElicitationCapability `json:"elicitation,omitempty"`
 	OAuth       *OAuthCapability       `json:"oauth,omitempty"`
@@ -56,6 +67,8 @@ type OAuthCapability struct {
 	TokenURL string `json:"tokenUrl,omitempty"`
 	// PKCE indicates whether PKCE is supported.
 	PKCE bool `json:"pkce,omitempty"`
+	// Required indicates that every request must carry a bearer token.
+	Required bool `json:"required,omitempty"`
 }
 
 // ToolCapability describes tool-related server capabilities.
@@ -74,6 +87,10 @@ type PromptCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// LoggingCapability indicates that the server sends log messages as
+// notifications/message notifications.
+type LoggingCapability struct{}
+
 // Resource describes an MCP resource.
 type Resource struct {
 	URI         string `json:"uri"`
@@ -98,10 +115,20 @@ type PromptArgument struct {
 
 // ToolInfo describes a tool as presented in MCP tool listings.
 type ToolInfo struct {
-	Name         string         `json:"name"`
-	Description  string         `json:"description,omitempty"`
-	InputSchema  map[string]any `json:"inputSchema"`
-	OutputSchema map[string]any `json:"outputSchema,omitempty"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	InputSchema  map[string]any   `json:"inputSchema"`
+	OutputSchema map[string]any   `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints describing a tool's behaviour. Unset hints take
+// the defaults defined by the MCP specification: not read-only, destructive,
+// and not idempotent.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool `json:"idempotentHint,omitempty"`
 }
 
 // InitializeResult is returned by the "initialize" method.
@@ -121,6 +148,15 @@ type ServerInfo struct {
 type ToolCallParams struct {
 	Name      string         `json:"name"`
 	Arguments map[string]any `json:"arguments,omitempty"`
+	Meta      *RequestMeta   `json:"_meta,omitempty"`
+}
+
+// RequestMeta is the "_meta" field of request params.
+type RequestMeta struct {
+	// ProgressToken, a string or number unique among the client's active
+	// requests, asks the server to report the request's progress in
+	// notifications/progress notifications carrying the same token.
+	ProgressToken any `json:"progressToken,omitempty"`
 }
 
 // ToolCallResult is returned by the "tools/call" method.
@@ -142,4 +178,9 @@ const (
 	CodeMethodNotFound = -32601
 	CodeInvalidParams  = -32602
 	CodeInternalError  = -32603
+
+	// CodeUnauthorized reports a missing or invalid bearer token.
+	CodeUnauthorized = -32001
+	// CodeForbidden reports that the caller may not perform the request.
+	CodeForbidden = -32003
 )
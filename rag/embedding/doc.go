@@ -45,6 +45,11 @@
 //   - "voyage" — Voyage AI embeddings
 //   - "inmemory" — Deterministic hash-based embedder for testing
 //
+// The llm/providers/cohere and llm/providers/mistral chat packages also
+// register "cohere-sdk" and "mistral-sdk" embedders built on their SDKs, so
+// one import serves both chat and embeddings. They accept the same
+// configuration as "cohere" and "mistral".
+//
 // # Middleware and Hooks
 //
 // Cross-cutting concerns like logging, caching, and tracing are layered
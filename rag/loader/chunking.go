@@ -0,0 +1,276 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/rag/splitter"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// defaultChunkSize is the chunk size used when a non-positive size is given
+// to NewChunkingTransformer.
+const defaultChunkSize = 1000
+
+// SplitStrategy selects how ChunkingTransformer divides document content.
+type SplitStrategy int
+
+const (
+	// SplitRecursive splits on the most significant separator present
+	// (paragraph, line, word, then character) using
+	// splitter.RecursiveSplitter. It is the zero value.
+	SplitRecursive SplitStrategy = iota
+
+	// SplitFixed cuts content into windows of exactly chunkSize bytes,
+	// adjusted to UTF-8 character boundaries, each starting overlap bytes
+	// before the end of the previous one.
+	SplitFixed
+
+	// SplitSentence packs whole sentences into chunks of at most chunkSize
+	// bytes, repeating trailing sentences of up to overlap bytes at the start
+	// of the next chunk. Sentences end at '.', '!' or '?' followed by
+	// whitespace, or at a blank line. Sentences longer than chunkSize are cut
+	// as with SplitFixed.
+	SplitSentence
+)
+
+// String returns the strategy name.
+func (s SplitStrategy) String() string {
+	switch s {
+	case SplitRecursive:
+		return "recursive"
+	case SplitFixed:
+		return "fixed"
+	case SplitSentence:
+		return "sentence"
+	}
+	return fmt.Sprintf("SplitStrategy(%d)", int(s))
+}
+
+// ChunkingTransformer splits documents into overlapping chunks for
+// embedding. Each chunk is a new document with ID "<parent ID>#chunk<n>" that
+// keeps the parent's metadata and adds "parent_id", "chunk_index" (0-based)
+// and "chunk_total", the same keys used by the splitter package.
+//
+// ChunkingTransformer implements SplittingTransformer, so a LoaderPipeline
+// replaces each document with its chunks.
+type ChunkingTransformer struct {
+	chunkSize int
+	overlap   int
+	strategy  SplitStrategy
+	recursive *splitter.RecursiveSplitter
+}
+
+// Compile-time interface check.
+var _ SplittingTransformer = (*ChunkingTransformer)(nil)
+
+// NewChunkingTransformer creates a ChunkingTransformer producing chunks of at
+// most chunkSize bytes that overlap by up to overlap bytes. A non-positive
+// chunkSize defaults to 1000; overlap is clamped to [0, chunkSize-1].
+// Unknown strategies use SplitRecursive.
+func NewChunkingTransformer(chunkSize, overlap int, strategy SplitStrategy) *ChunkingTransformer {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	overlap = min(max(overlap, 0), chunkSize-1)
+	return &ChunkingTransformer{
+		chunkSize: chunkSize,
+		overlap:   overlap,
+		strategy:  strategy,
+		recursive: splitter.NewRecursiveSplitter(
+			splitter.WithChunkSize(chunkSize),
+			splitter.WithChunkOverlap(overlap),
+		),
+	}
+}
+
+// Transform returns doc as a single chunk. It fails with ErrInvalidInput
+// when doc splits into more than one chunk; use TransformMany, or add the
+// transformer to a LoaderPipeline, to get every chunk. Documents without
+// content are returned unchanged.
+func (t *ChunkingTransformer) Transform(ctx context.Context, doc schema.Document) (schema.Document, error) {
+	chunks, err := t.TransformMany(ctx, doc)
+	if err != nil {
+		return schema.Document{}, err
+	}
+	switch len(chunks) {
+	case 0:
+		return doc, nil
+	case 1:
+		return chunks[0], nil
+	}
+	return schema.Document{}, core.Errorf(core.ErrInvalidInput, "loader: document %q splits into %d chunks; use TransformMany", doc.ID, len(chunks))
+}
+
+// TransformMany splits doc into chunks. Documents without content produce
+// no chunks.
+func (t *ChunkingTransformer) TransformMany(ctx context.Context, doc schema.Document) ([]schema.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	switch t.strategy {
+	case SplitFixed:
+		texts = t.splitFixed(doc.Content)
+	case SplitSentence:
+		texts = t.splitSentences(doc.Content)
+	default:
+		var err error
+		texts, err = t.recursive.Split(ctx, doc.Content)
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "loader: chunk document %q: %w", doc.ID, err)
+		}
+	}
+
+	chunks := make([]schema.Document, 0, len(texts))
+	for i, text := range texts {
+		meta := make(map[string]any, len(doc.Metadata)+3)
+		for k, v := range doc.Metadata {
+			meta[k] = v
+		}
+		meta["parent_id"] = doc.ID
+		meta["chunk_index"] = i
+		meta["chunk_total"] = len(texts)
+		chunks = append(chunks, schema.Document{
+			ID:       fmt.Sprintf("%s#chunk%d", doc.ID, i),
+			Content:  text,
+			Metadata: meta,
+		})
+	}
+	return chunks, nil
+}
+
+// splitFixed cuts text into windows of chunkSize bytes overlapping by
+// overlap bytes, never splitting a UTF-8 character. Windows of only
+// whitespace are dropped.
+func (t *ChunkingTransformer) splitFixed(text string) []string {
+	var chunks []string
+	for start := 0; start < len(text); {
+		end := min(start+t.chunkSize, len(text))
+		for end > start && end < len(text) && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == start {
+			// The chunk size is smaller than this character; take it whole.
+			_, width := utf8.DecodeRuneInString(text[start:])
+			end = start + width
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			chunks = append(chunks, text[start:end])
+		}
+		if end == len(text) {
+			break
+		}
+
+		next := max(end-t.overlap, start+1)
+		for next < end && !utf8.RuneStart(text[next]) {
+			next++
+		}
+		start = next
+	}
+	return chunks
+}
+
+// splitSentences packs the sentences of text into chunks.
+func (t *ChunkingTransformer) splitSentences(text string) []string {
+	var chunks []string
+	var current []string
+	size := 0 // len(strings.Join(current, " "))
+
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, " "))
+		}
+	}
+
+	for _, s := range sentences(text) {
+		if len(s) > t.chunkSize {
+			flush()
+			chunks = append(chunks, t.splitFixed(s)...)
+			current, size = nil, 0
+			continue
+		}
+		if len(current) > 0 && size+1+len(s) > t.chunkSize {
+			flush()
+			current = t.sentenceOverlap(current)
+			size = joinedLen(current)
+			for len(current) > 0 && size+1+len(s) > t.chunkSize {
+				current = current[1:]
+				size = joinedLen(current)
+			}
+		}
+		if len(current) > 0 {
+			size++
+		}
+		current = append(current, s)
+		size += len(s)
+	}
+	flush()
+	return chunks
+}
+
+// sentenceOverlap returns the trailing sentences of a finished chunk whose
+// joined length fits in the overlap, always leaving out the first sentence
+// so that chunks make progress.
+func (t *ChunkingTransformer) sentenceOverlap(chunk []string) []string {
+	n, size := 0, -1
+	for i := len(chunk) - 1; i > 0; i-- {
+		if size+1+len(chunk[i]) > t.overlap {
+			break
+		}
+		size += 1 + len(chunk[i])
+		n++
+	}
+	return append([]string(nil), chunk[len(chunk)-n:]...)
+}
+
+// joinedLen returns the length of the sentences joined with single spaces.
+func joinedLen(sentences []string) int {
+	if len(sentences) == 0 {
+		return 0
+	}
+	n := len(sentences) - 1
+	for _, s := range sentences {
+		n += len(s)
+	}
+	return n
+}
+
+// sentences splits text into trimmed sentences. A sentence ends at '.', '!'
+// or '?', plus any closing quotes or brackets, followed by whitespace or the
+// end of text, or at a blank line.
+func sentences(text string) []string {
+	var out []string
+	add := func(s string) {
+		if s = strings.Join(strings.Fields(s), " "); s != "" {
+			out = append(out, s)
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\n':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				add(text[start:i])
+				start = i
+			}
+		case '.', '!', '?':
+			j := i + 1
+			for j < len(text) && strings.IndexByte(`"')]`, text[j]) >= 0 {
+				j++
+			}
+			if j == len(text) || strings.IndexByte(" \t\r\n", text[j]) >= 0 {
+				add(text[start:j])
+				start = j
+				i = j - 1
+			}
+		}
+	}
+	add(text[start:])
+	return out
+}
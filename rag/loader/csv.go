@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 	"path/filepath"
 	"strings"
@@ -54,57 +56,86 @@ func NewCSVLoader(opts ...CSVLoaderOption) *CSVLoader {
 // treated as headers. Each row's values are stored in metadata, and the
 // content is either all columns or only the configured content columns.
 func (l *CSVLoader) Load(ctx context.Context, source string) ([]schema.Document, error) {
-	cleaned, err := cleanPath(source)
-	if err != nil {
-		return nil, err
-	}
-	// #nosec G304 -- path validated by cleanPath
-	f, err := os.Open(cleaned)
-	if err != nil {
-		return nil, core.Errorf(core.ErrProviderDown, "loader: csv open %q: %w", source, err)
+	var docs []schema.Document
+	for doc, err := range l.LoadStream(ctx, source) {
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
 	}
-	defer func() { _ = f.Close() }()
+	return docs, nil
+}
 
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, core.Errorf(core.ErrInvalidInput, "loader: csv parse error: %w", err)
-	}
+// LoadStream reads a CSV file one row at a time and yields the same
+// documents as Load, without holding the file in memory.
+func (l *CSVLoader) LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		cleaned, err := cleanPath(source)
+		if err != nil {
+			yield(schema.Document{}, err)
+			return
+		}
+		// #nosec G304 -- path validated by cleanPath
+		f, err := os.Open(cleaned)
+		if err != nil {
+			yield(schema.Document{}, core.Errorf(core.ErrProviderDown, "loader: csv open %q: %w", source, err))
+			return
+		}
+		defer func() { _ = f.Close() }()
 
-	if len(records) < 2 {
-		return nil, nil // No data rows.
-	}
+		reader := csv.NewReader(f)
+		headers, err := reader.Read()
+		if err == io.EOF {
+			return // Empty file.
+		}
+		if err != nil {
+			yield(schema.Document{}, core.Errorf(core.ErrInvalidInput, "loader: csv parse error: %w", err))
+			return
+		}
 
-	headers := records[0]
-	baseName := filepath.Base(source)
+		baseName := filepath.Base(source)
 
-	// Build column index for content extraction.
-	contentIdxs := l.resolveContentColumns(headers)
+		// Build column index for content extraction.
+		contentIdxs := l.resolveContentColumns(headers)
 
-	docs := make([]schema.Document, 0, len(records)-1)
-	for i, row := range records[1:] {
-		meta := map[string]any{
-			"source": source,
-			"format": "csv",
-			"name":   baseName,
-			"row":    i,
-		}
-		for j, header := range headers {
-			if j < len(row) {
-				meta[header] = row[j]
+		for i := 0; ; i++ {
+			if err := ctx.Err(); err != nil {
+				yield(schema.Document{}, err)
+				return
+			}
+			row, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(schema.Document{}, core.Errorf(core.ErrInvalidInput, "loader: csv parse error: %w", err))
+				return
 			}
-		}
 
-		content := l.buildContent(headers, row, contentIdxs)
+			meta := map[string]any{
+				"source": source,
+				"format": "csv",
+				"name":   baseName,
+				"row":    i,
+			}
+			for j, header := range headers {
+				if j < len(row) {
+					meta[header] = row[j]
+				}
+			}
 
-		doc := schema.Document{
-			ID:       fmt.Sprintf("%s#%d", source, i),
-			Content:  content,
-			Metadata: meta,
+			content := l.buildContent(headers, row, contentIdxs)
+
+			doc := schema.Document{
+				ID:       fmt.Sprintf("%s#%d", source, i),
+				Content:  content,
+				Metadata: meta,
+			}
+			if !yield(doc, nil) {
+				return
+			}
 		}
-		docs = append(docs, doc)
 	}
-	return docs, nil
 }
 
 // resolveContentColumns returns indices of columns to use for content.
@@ -16,6 +16,17 @@
 //	    Transform(ctx context.Context, doc schema.Document) (schema.Document, error)
 //	}
 //
+// Loaders that can read a source incrementally also implement
+// [StreamingLoader], which yields documents one at a time so that files
+// larger than memory can be indexed:
+//
+//	type StreamingLoader interface {
+//	    LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error]
+//	}
+//
+// The "csv" loader streams one document per row and the "text" loader one
+// document per non-blank line.
+//
 // # Registry
 //
 // The package follows Beluga's registry pattern. Providers register via
@@ -35,6 +46,26 @@
 //   - "json" — JSON files with configurable path extraction
 //   - "csv" — CSV files (one document per row)
 //   - "markdown" — Markdown files
+//   - "pdf" — PDF text extraction (one document per page, "page" metadata)
+//   - "html" — HTML main-content extraction without navigation and other
+//     boilerplate (one document per heading section, "section" metadata)
+//
+// Malformed PDF and HTML files return a core.ErrInvalidInput error.
+//
+// # Incremental Loading
+//
+// [LoadIncremental] re-indexes a file or directory efficiently. It hashes each
+// file, loads only files that are new or whose hash differs from the previous
+// [Manifest], and reports sources that were deleted:
+//
+//	docs, manifest, deleted, err := loader.LoadIncremental(ctx, "/corpus", prev)
+//	if err != nil {
+//	    return err
+//	}
+//	// Upsert docs, remove documents of deleted sources, then persist manifest.
+//
+// Files are read with the built-in loader for their extension; use
+// [WithExtensionLoader] to add or override one.
 //
 // # External Loaders
 //
@@ -63,6 +94,60 @@
 //	)
 //	docs, err := p.Load(ctx, "/path/to/files")
 //
+// A [SplittingTransformer] may replace a document with several. The built-in
+// [ChunkingTransformer] splits documents into overlapping chunks ready for
+// embedding, with [SplitRecursive], [SplitFixed] or [SplitSentence]
+// strategies. Chunks keep the parent's metadata and add "parent_id",
+// "chunk_index" and "chunk_total":
+//
+//	p := loader.NewPipeline(
+//	    loader.WithLoader(pdfLoader),
+//	    loader.WithTransformer(loader.NewChunkingTransformer(1000, 200, loader.SplitSentence)),
+//	)
+//
+// [LoaderPipeline.LoadStream] yields transformed documents one at a time,
+// consuming loaders that implement [StreamingLoader] lazily:
+//
+//	for doc, err := range p.LoadStream(ctx, "/path/to/huge.csv") {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    index(doc)
+//	}
+//
+// # Retries and Rate Limits
+//
+// [Middleware] wraps a loader transparently. [WithRetry] retries transient
+// failures with exponential backoff and [WithRateLimit] limits calls per
+// second, both built on the resilience package. Apply them to a single
+// loader with [ApplyMiddleware] or to every loader of a pipeline with
+// [WithMiddleware]:
+//
+//	p := loader.NewPipeline(
+//	    loader.WithLoader(notionLoader),
+//	    loader.WithMiddleware(
+//	        loader.WithRateLimit(3),
+//	        loader.WithRetry(resilience.DefaultRetryPolicy()),
+//	    ),
+//	)
+//
+// Both keep streaming loaders streaming: a stream counts against the rate
+// limit from its start to its end, and is retried only until it yields its
+// first document.
+//
+// [IsRetryable] decides which errors are retried. By loader:
+//   - "github", "notion", "confluence" — HTTP 408, 429 and 5xx responses and
+//     network errors (timeouts, refused or reset connections)
+//   - "firecrawl" — request timeouts, HTTP 429 and server errors, reported
+//     with a retryable core error code
+//   - "gdrive", "cloudstorage", "docling", "unstructured" — network errors
+//     only; HTTP error responses are not retried
+//   - file loaders — read errors are reported with core.ErrProviderDown and
+//     retried, except for missing files and permission errors
+//
+// Any other loader error carrying core.ErrRateLimit, core.ErrTimeout or
+// core.ErrProviderDown is retried.
+//
 // # Custom Provider
 //
 // To add a custom document loader:
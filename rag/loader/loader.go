@@ -2,6 +2,7 @@ package loader
 
 import (
 	"context"
+	"iter"
 	"sort"
 	"sync"
 
@@ -19,6 +20,16 @@ type DocumentLoader interface {
 	Load(ctx context.Context, source string) ([]schema.Document, error)
 }
 
+// StreamingLoader is implemented by loaders that can yield documents one at a
+// time as they read the source, so that files larger than memory can be
+// indexed. Consumers such as LoaderPipeline use LoadStream when a loader
+// implements it.
+type StreamingLoader interface {
+	// LoadStream reads content from the given source and yields documents as
+	// they are read. Iteration stops at the first error.
+	LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error]
+}
+
 // Transformer applies a transformation to a single document.
 // Transformers are used in pipelines to enrich or modify documents
 // after loading (e.g., adding metadata, cleaning content).
@@ -28,6 +39,17 @@ type Transformer interface {
 	Transform(ctx context.Context, doc schema.Document) (schema.Document, error)
 }
 
+// SplittingTransformer is a Transformer that may turn one document into
+// several, such as ChunkingTransformer. LoaderPipeline calls TransformMany
+// instead of Transform on transformers that implement it.
+type SplittingTransformer interface {
+	Transformer
+
+	// TransformMany transforms the given document into zero or more
+	// documents.
+	TransformMany(ctx context.Context, doc schema.Document) ([]schema.Document, error)
+}
+
 // TransformerFunc is a convenience type that implements Transformer.
 type TransformerFunc func(ctx context.Context, doc schema.Document) (schema.Document, error)
 
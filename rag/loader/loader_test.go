@@ -1,20 +1,35 @@
 package loader
 
 import (
+	"bytes"
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+	"github.com/lookatitude/beluga-ai/v2/resilience"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 )
 
 func TestRegistry(t *testing.T) {
 	names := List()
-	expected := []string{"csv", "json", "markdown", "text"}
+	expected := []string{"csv", "html", "json", "markdown", "pdf", "text"}
 	if len(names) != len(expected) {
 		t.Fatalf("expected %d loaders, got %d: %v", len(expected), len(names), names)
 	}
@@ -629,3 +644,964 @@ func TestJSONLoader_ExtractContentError(t *testing.T) {
 		t.Errorf("expected '[1,2,3]', got %q", docs[0].Content)
 	}
 }
+
+// buildPDF returns a minimal PDF with one page per entry of pages, each
+// showing its text in Helvetica, and the given document title.
+func buildPDF(title string, pages []string) []byte {
+	return buildPDFWithFonts(title, pages, nil)
+}
+
+// buildPDFWithFonts is like buildPDF, but page i names the font dictionary
+// fonts[i] as /F1 when it is set.
+func buildPDFWithFonts(title string, pages, fonts []string) []byte {
+	var objs []string
+	add := func(obj string) int {
+		objs = append(objs, obj)
+		return len(objs)
+	}
+	catalog := add("")
+	pagesObj := add("")
+	helvetica := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	var kids []string
+	for i, text := range pages {
+		stream := ""
+		if text != "" {
+			stream = fmt.Sprintf("BT /F1 12 Tf 72 712 Td (%s) Tj ET", text)
+		}
+		contents := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+		font := helvetica
+		if i < len(fonts) && fonts[i] != "" {
+			font = add(fonts[i])
+		}
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, font, contents))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	info := add(fmt.Sprintf("<< /Title (%s) >>", title))
+	objs[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objs[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, catalog, info, xref)
+	return buf.Bytes()
+}
+
+func TestPDFLoader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(path, buildPDF("Quarterly Report", []string{"First page", "", "Third page"}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New("pdf", config.ProviderConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := l.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 docs (empty page skipped), got %d", len(docs))
+	}
+
+	wantPages := []int{1, 3}
+	wantText := []string{"First page", "Third page"}
+	for i, doc := range docs {
+		if !strings.Contains(doc.Content, wantText[i]) {
+			t.Errorf("doc %d content = %q, want %q", i, doc.Content, wantText[i])
+		}
+		if doc.Metadata["page"] != wantPages[i] {
+			t.Errorf("doc %d page = %v, want %d", i, doc.Metadata["page"], wantPages[i])
+		}
+		if doc.Metadata["pages"] != 3 {
+			t.Errorf("doc %d pages = %v, want 3", i, doc.Metadata["pages"])
+		}
+		if doc.Metadata["format"] != "pdf" || doc.Metadata["title"] != "Quarterly Report" {
+			t.Errorf("doc %d metadata = %v", i, doc.Metadata)
+		}
+		if want := fmt.Sprintf("%s#%d", path, wantPages[i]); doc.ID != want {
+			t.Errorf("doc %d ID = %q, want %q", i, doc.ID, want)
+		}
+	}
+}
+
+func TestPDFLoader_FontsPerPage(t *testing.T) {
+	// Both pages name their font /F1, but page 2's font maps code A to the
+	// glyph B.
+	remapped := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding << /Differences [65 /B] >> >>"
+	data := buildPDFWithFonts("t", []string{"A", "A"}, []string{"", remapped})
+	path := filepath.Join(t.TempDir(), "fonts.pdf")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := NewPDFLoader().Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 docs, got %d", len(docs))
+	}
+	if docs[0].Content != "A" || docs[1].Content != "B" {
+		t.Errorf("contents = %q, %q, want each page decoded with its own font", docs[0].Content, docs[1].Content)
+	}
+}
+
+func TestPDFLoader_Malformed(t *testing.T) {
+	valid := buildPDF("t", []string{"hello"})
+	tests := map[string][]byte{
+		"not a pdf": []byte("hello, world"),
+		"truncated": valid[:len(valid)/2],
+		"bad xref":  bytes.Replace(valid, []byte("xref"), []byte("xxxx"), 1),
+		"empty":     {},
+	}
+	dir := t.TempDir()
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".pdf")
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := NewPDFLoader().Load(context.Background(), path)
+			if err == nil {
+				t.Fatal("expected error for malformed PDF")
+			}
+			if !strings.Contains(err.Error(), "loader: pdf parse") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPDFLoader_FileNotFound(t *testing.T) {
+	if _, err := NewPDFLoader().Load(context.Background(), "/nonexistent/file.pdf"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+const testHTML = `<!DOCTYPE html>
+<html>
+<head><title>  Guide  </title><style>body { color: red }</style></head>
+<body>
+<nav><a href="/">Home</a> <a href="/docs">Docs</a></nav>
+<div class="sidebar">Related links</div>
+<main>
+  <p>Intro   paragraph.</p>
+  <h1>Installation</h1>
+  <p>Run the <code>installer</code>.</p>
+  <script>track();</script>
+  <h3>Requirements</h3>
+  <ul><li>Go</li><li>Git</li></ul>
+  <h2>Usage</h2>
+  <table><tr><td>a</td><td>b</td></tr></table>
+  <div aria-hidden="true">hidden</div>
+</main>
+<footer>Copyright</footer>
+</body>
+</html>`
+
+func TestHTMLLoader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "guide.html")
+	if err := os.WriteFile(path, []byte(testHTML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New("html", config.ProviderConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := l.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 sections, got %d: %+v", len(docs), docs)
+	}
+
+	want := []struct {
+		heading string
+		content string
+	}{
+		{"", "Intro paragraph."},
+		{"Installation", "Installation\n\nRun the installer.\n\nRequirements\n\nGo\n\nGit"},
+		{"Usage", "Usage\n\na b"},
+	}
+	for i, doc := range docs {
+		if doc.Content != want[i].content {
+			t.Errorf("section %d content = %q, want %q", i+1, doc.Content, want[i].content)
+		}
+		heading, _ := doc.Metadata["heading"].(string)
+		if heading != want[i].heading {
+			t.Errorf("section %d heading = %q, want %q", i+1, heading, want[i].heading)
+		}
+		if doc.Metadata["section"] != i+1 || doc.Metadata["title"] != "Guide" || doc.Metadata["format"] != "html" {
+			t.Errorf("section %d metadata = %v", i+1, doc.Metadata)
+		}
+		for _, boilerplate := range []string{"Home", "Related", "Copyright", "track", "hidden", "color"} {
+			if strings.Contains(doc.Content, boilerplate) {
+				t.Errorf("section %d contains boilerplate %q: %q", i+1, boilerplate, doc.Content)
+			}
+		}
+	}
+}
+
+func TestHTMLLoader_SectionLevel(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "guide.html")
+	if err := os.WriteFile(path, []byte(testHTML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New("html", config.ProviderConfig{Options: map[string]any{"section_level": float64(0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := l.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected a single document, got %d", len(docs))
+	}
+	if !strings.Contains(docs[0].Content, "Intro paragraph.") || !strings.Contains(docs[0].Content, "Usage") {
+		t.Errorf("unexpected content %q", docs[0].Content)
+	}
+
+	docs, err = NewHTMLLoader(WithSectionLevel(3)).Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 4 {
+		t.Fatalf("expected 4 sections with h3 splitting, got %d", len(docs))
+	}
+}
+
+func TestHTMLLoader_NoMain(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plain.html")
+	if err := os.WriteFile(path, []byte("<p>just <b>text</b></p><header>site</header>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	docs, err := NewHTMLLoader().Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Content != "just text" {
+		t.Fatalf("unexpected docs %+v", docs)
+	}
+}
+
+func TestHTMLLoader_FileNotFound(t *testing.T) {
+	_, err := NewHTMLLoader().Load(context.Background(), "/nonexistent/file.html")
+	if err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+}
+
+func TestCSVLoader_LoadStream(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stream.csv")
+	if err := os.WriteFile(path, []byte("name,age\nAlice,30\nBob,25\nCarol,41\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewCSVLoader()
+	want, err := l.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []schema.Document
+	for doc, err := range l.LoadStream(context.Background(), path) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, doc)
+	}
+	if len(got) != 3 || len(got) != len(want) {
+		t.Fatalf("expected 3 docs, got %d (Load returned %d)", len(got), len(want))
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID || got[i].Content != want[i].Content {
+			t.Errorf("doc %d: stream %+v differs from Load %+v", i, got[i], want[i])
+		}
+	}
+
+	n := 0
+	for range l.LoadStream(context.Background(), path) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("expected iteration to stop after break, got %d docs", n)
+	}
+}
+
+func TestCSVLoader_LoadStreamParseError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n3,4,5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var docs int
+	var gotErr error
+	for _, err := range NewCSVLoader().LoadStream(context.Background(), path) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		docs++
+	}
+	if docs != 1 {
+		t.Errorf("expected the valid row before the error, got %d docs", docs)
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "csv parse error") {
+		t.Errorf("expected parse error, got %v", gotErr)
+	}
+}
+
+func TestTextLoader_LoadStream(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lines.txt")
+	long := strings.Repeat("x", 100_000)
+	if err := os.WriteFile(path, []byte("first\r\n\n  \n"+long+"\nlast"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var docs []schema.Document
+	for doc, err := range NewTextLoader().LoadStream(context.Background(), path) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 non-blank lines, got %d", len(docs))
+	}
+	if docs[0].Content != "first" || docs[0].Metadata["line"] != 1 {
+		t.Errorf("unexpected first doc %+v", docs[0])
+	}
+	if docs[1].Content != long || docs[1].Metadata["line"] != 4 {
+		t.Errorf("long line not loaded intact (len %d, line %v)", len(docs[1].Content), docs[1].Metadata["line"])
+	}
+	if docs[2].Content != "last" || docs[2].ID != path+"#5" {
+		t.Errorf("unexpected last doc %+v", docs[2])
+	}
+}
+
+func TestTextLoader_LoadStreamCancelled(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lines.txt")
+	if err := os.WriteFile(path, []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range NewTextLoader().LoadStream(ctx, path) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	}
+}
+
+func TestPipeline_LoadStream(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var transformed int
+	count := TransformerFunc(func(_ context.Context, doc schema.Document) (schema.Document, error) {
+		transformed++
+		doc.Content = strings.ToUpper(doc.Content)
+		return doc, nil
+	})
+	p := NewPipeline(
+		WithLoader(NewTextLoader()),
+		WithLoader(NewMarkdownLoader()),
+		WithTransformer(count),
+	)
+
+	var contents []string
+	for doc, err := range p.LoadStream(context.Background(), path) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, doc.Content)
+	}
+	// The text loader streams lines; the markdown loader is not streaming
+	// and yields the whole file.
+	want := []string{"ONE", "TWO", "ONE\nTWO\n"}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q, got %q", want, contents)
+	}
+
+	transformed = 0
+	for range p.LoadStream(context.Background(), path) {
+		break
+	}
+	if transformed != 1 {
+		t.Errorf("expected documents to be transformed lazily, got %d transforms", transformed)
+	}
+}
+
+func TestPipeline_LoadStreamErrors(t *testing.T) {
+	for _, err := range NewPipeline().LoadStream(context.Background(), "anything") {
+		if err == nil {
+			t.Error("expected error for empty pipeline")
+		}
+	}
+
+	var gotErr error
+	p := NewPipeline(WithLoader(NewTextLoader()))
+	for _, err := range p.LoadStream(context.Background(), "/nonexistent/file.txt") {
+		gotErr = err
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "pipeline loader 0") {
+		t.Errorf("expected loader error, got %v", gotErr)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p = NewPipeline(
+		WithLoader(NewTextLoader()),
+		WithTransformer(TransformerFunc(func(_ context.Context, doc schema.Document) (schema.Document, error) {
+			return doc, os.ErrInvalid
+		})),
+	)
+	gotErr = nil
+	for _, err := range p.LoadStream(context.Background(), path) {
+		gotErr = err
+	}
+	if !errors.Is(gotErr, os.ErrInvalid) {
+		t.Errorf("expected transformer error, got %v", gotErr)
+	}
+}
+
+func TestLoadIncremental(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.txt", "alpha")
+	write("sub/b.md", "# beta")
+	write("c.csv", "name\nx\ny\n")
+	write("image.png", "not loaded")
+
+	ctx := context.Background()
+	docs, manifest, deleted, err := LoadIncremental(ctx, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 4 {
+		t.Fatalf("expected 4 docs on first run, got %d", len(docs))
+	}
+	if len(manifest) != 3 || len(deleted) != 0 {
+		t.Fatalf("expected 3 manifest entries and no deletions, got %v %v", manifest, deleted)
+	}
+	if docs[0].Metadata["content_hash"] != manifest[docs[0].Metadata["source"].(string)] {
+		t.Errorf("expected content_hash metadata to match the manifest")
+	}
+
+	docs, again, deleted, err := LoadIncremental(ctx, dir, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 0 || len(deleted) != 0 || len(again) != 3 {
+		t.Fatalf("expected no changes, got %d docs, %v deleted", len(docs), deleted)
+	}
+
+	write("a.txt", "alpha two")
+	write("d.txt", "delta")
+	if err := os.Remove(filepath.Join(dir, "sub", "b.md")); err != nil {
+		t.Fatal(err)
+	}
+	docs, next, deleted, err := LoadIncremental(ctx, dir, again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, d := range docs {
+		contents = append(contents, d.Content)
+	}
+	if strings.Join(contents, "|") != "alpha two|delta" {
+		t.Errorf("expected changed and added files, got %q", contents)
+	}
+	if len(deleted) != 1 || deleted[0] != filepath.Join(dir, "sub", "b.md") {
+		t.Errorf("expected b.md deleted, got %v", deleted)
+	}
+	if len(next) != 3 || next[filepath.Join(dir, "a.txt")] == again[filepath.Join(dir, "a.txt")] {
+		t.Errorf("expected an updated manifest, got %v", next)
+	}
+}
+
+func TestLoadIncremental_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.log")
+	if err := os.WriteFile(path, []byte("entry"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := LoadIncremental(context.Background(), path, nil); err == nil {
+		t.Fatal("expected error for a file without a loader")
+	}
+
+	prev := Manifest{path: "stale", filepath.Join(dir, "other.txt"): "x"}
+	docs, manifest, deleted, err := LoadIncremental(context.Background(), path, prev, WithExtensionLoader(".LOG", NewTextLoader()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Content != "entry" || len(manifest) != 1 {
+		t.Fatalf("expected the file to be reloaded, got %+v %v", docs, manifest)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("entries outside the source should not be reported deleted, got %v", deleted)
+	}
+}
+
+func TestLoadIncremental_Errors(t *testing.T) {
+	if _, _, _, err := LoadIncremental(context.Background(), "/nonexistent/dir", nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := LoadIncremental(ctx, dir, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// flakyLoader fails with errs in turn, then succeeds.
+type flakyLoader struct {
+	errs  []error
+	calls int
+}
+
+func (l *flakyLoader) Load(_ context.Context, source string) ([]schema.Document, error) {
+	l.calls++
+	if len(l.errs) > 0 {
+		err := l.errs[0]
+		l.errs = l.errs[1:]
+		return nil, err
+	}
+	return []schema.Document{{ID: source, Content: "ok"}}, nil
+}
+
+func fastRetry(attempts int) resilience.RetryPolicy {
+	return resilience.RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+}
+
+func TestWithRetry(t *testing.T) {
+	transient := fmt.Errorf("notion: fetch page: %w", &httpclient.APIError{StatusCode: http.StatusServiceUnavailable})
+	fl := &flakyLoader{errs: []error{transient, core.Errorf(core.ErrRateLimit, "slow down")}}
+	l := ApplyMiddleware(fl, WithRetry(fastRetry(3)))
+
+	docs, err := l.Load(context.Background(), "page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || fl.calls != 3 {
+		t.Errorf("expected success on the third attempt, got %d docs after %d calls", len(docs), fl.calls)
+	}
+}
+
+func TestWithRetry_Exhausted(t *testing.T) {
+	transient := fmt.Errorf("github: fetch: %w", &httpclient.APIError{StatusCode: http.StatusBadGateway})
+	fl := &flakyLoader{errs: []error{transient, transient, transient}}
+	_, err := ApplyMiddleware(fl, WithRetry(fastRetry(2))).Load(context.Background(), "x")
+	if err != transient {
+		t.Errorf("expected the loader's own error after retries, got %v", err)
+	}
+	if fl.calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", fl.calls)
+	}
+}
+
+func TestWithRetry_Permanent(t *testing.T) {
+	permanent := fmt.Errorf("github: fetch: %w", &httpclient.APIError{StatusCode: http.StatusNotFound})
+	fl := &flakyLoader{errs: []error{permanent}}
+	_, err := ApplyMiddleware(fl, WithRetry(fastRetry(3))).Load(context.Background(), "x")
+	if err != permanent || fl.calls != 1 {
+		t.Errorf("expected no retry for a permanent error, got %v after %d calls", err, fl.calls)
+	}
+}
+
+func TestWithRetry_MissingFile(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "missing.txt")
+	fl := &flakyLoader{}
+	l := ApplyMiddleware(fileLoader{fl: fl, next: NewTextLoader()}, WithRetry(fastRetry(3)))
+	_, err := l.Load(context.Background(), source)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the missing-file error, got %v", err)
+	}
+	if fl.calls != 1 {
+		t.Errorf("expected no retry for a missing file, got %d calls", fl.calls)
+	}
+}
+
+// fileLoader counts calls to next in fl.
+type fileLoader struct {
+	fl   *flakyLoader
+	next DocumentLoader
+}
+
+func (l fileLoader) Load(ctx context.Context, source string) ([]schema.Document, error) {
+	l.fl.calls++
+	return l.next.Load(ctx, source)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	fl := &flakyLoader{}
+	l := ApplyMiddleware(fl, WithRateLimit(1000))
+	for range 3 {
+		if _, err := l.Load(context.Background(), "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fl.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", fl.calls)
+	}
+
+	if ApplyMiddleware(fl, WithRateLimit(0)) != DocumentLoader(fl) {
+		t.Error("expected a non-positive rate to leave the loader unwrapped")
+	}
+
+	// One call per minute: the second call waits and the context expires.
+	l = ApplyMiddleware(&flakyLoader{}, WithRateLimit(1.0/60))
+	if _, err := l.Load(context.Background(), "x"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Load(ctx, "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the rate limit to block, got %v", err)
+	}
+}
+
+// flakyStream fails with errs in turn before yielding anything, then yields
+// docs followed by tailErr, if set.
+type flakyStream struct {
+	flakyLoader
+	docs    []string
+	tailErr error
+}
+
+func (l *flakyStream) LoadStream(_ context.Context, _ string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		l.calls++
+		if len(l.errs) > 0 {
+			err := l.errs[0]
+			l.errs = l.errs[1:]
+			yield(schema.Document{}, err)
+			return
+		}
+		for _, id := range l.docs {
+			if !yield(schema.Document{ID: id}, nil) {
+				return
+			}
+		}
+		if l.tailErr != nil {
+			yield(schema.Document{}, l.tailErr)
+		}
+	}
+}
+
+func collectStream(t *testing.T, l DocumentLoader) ([]string, error) {
+	t.Helper()
+	sl, ok := l.(StreamingLoader)
+	if !ok {
+		t.Fatalf("%T does not implement StreamingLoader", l)
+	}
+	var ids []string
+	for doc, err := range sl.LoadStream(context.Background(), "x") {
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+func TestWithRetry_Stream(t *testing.T) {
+	st := &flakyStream{
+		flakyLoader: flakyLoader{errs: []error{core.Errorf(core.ErrTimeout, "timed out")}},
+		docs:        []string{"a", "b"},
+	}
+	ids, err := collectStream(t, ApplyMiddleware(st, WithRetry(fastRetry(3))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || st.calls != 2 {
+		t.Errorf("expected both documents after one retry, got %v after %d calls", ids, st.calls)
+	}
+
+	// Once a document was yielded, a failure is passed through.
+	tailErr := core.Errorf(core.ErrTimeout, "timed out")
+	st = &flakyStream{docs: []string{"a"}, tailErr: tailErr}
+	ids, err = collectStream(t, ApplyMiddleware(st, WithRetry(fastRetry(3))))
+	if err != tailErr || len(ids) != 1 || st.calls != 1 {
+		t.Errorf("expected no retry after the first document, got %v, %v after %d calls", ids, err, st.calls)
+	}
+
+	// A permanent error is returned unwrapped without retrying.
+	permanent := errors.New("bad source")
+	st = &flakyStream{flakyLoader: flakyLoader{errs: []error{permanent}}}
+	if _, err := collectStream(t, ApplyMiddleware(st, WithRetry(fastRetry(3)))); err != permanent || st.calls != 1 {
+		t.Errorf("expected no retry for a permanent error, got %v after %d calls", err, st.calls)
+	}
+}
+
+func TestWithRateLimit_Stream(t *testing.T) {
+	st := &flakyStream{docs: []string{"a", "b"}}
+	l := ApplyMiddleware(st, WithRateLimit(1.0/60))
+	ids, err := collectStream(t, l)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("expected both documents, got %v, %v", ids, err)
+	}
+
+	// The first stream used the only call allowed per minute.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	for _, err := range l.(StreamingLoader).LoadStream(ctx, "x") {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the rate limit to block, got %v", err)
+		}
+	}
+	if st.calls != 1 {
+		t.Errorf("expected the blocked stream not to start, got %d calls", st.calls)
+	}
+}
+
+func TestPipeline_WithMiddleware(t *testing.T) {
+	fl := &flakyLoader{errs: []error{core.Errorf(core.ErrTimeout, "timed out")}}
+	p := NewPipeline(WithMiddleware(WithRetry(fastRetry(2))), WithLoader(fl))
+	docs, err := p.Load(context.Background(), "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || fl.calls != 2 {
+		t.Errorf("expected the pipeline loader to be retried, got %d docs after %d calls", len(docs), fl.calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("bad source"), false},
+		{"core rate limit", core.Errorf(core.ErrRateLimit, "slow down"), true},
+		{"core invalid input", core.Errorf(core.ErrInvalidInput, "bad"), false},
+		{"api 429", &httpclient.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"api 500", fmt.Errorf("wrapped: %w", &httpclient.APIError{StatusCode: http.StatusInternalServerError}), true},
+		{"api 401", &httpclient.APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"unexpected eof", fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), true},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), false},
+		{"missing file", core.Errorf(core.ErrProviderDown, "read: %w", fs.ErrNotExist), false},
+		{"permission", core.Errorf(core.ErrProviderDown, "read: %w", fs.ErrPermission), false},
+		{"other read error", core.Errorf(core.ErrProviderDown, "read: %w", syscall.EIO), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkingTransformer_Fixed(t *testing.T) {
+	ct := NewChunkingTransformer(4, 1, SplitFixed)
+	doc := schema.Document{ID: "doc", Content: "abcdefghij", Metadata: map[string]any{"source": "a.txt"}}
+	chunks, err := ct.TransformMany(context.Background(), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Content)
+	}
+	if strings.Join(got, "|") != "abcd|defg|ghij" {
+		t.Errorf("unexpected chunks %q", got)
+	}
+	last := chunks[2]
+	if last.ID != "doc#chunk2" || last.Metadata["parent_id"] != "doc" || last.Metadata["chunk_index"] != 2 ||
+		last.Metadata["chunk_total"] != 3 || last.Metadata["source"] != "a.txt" {
+		t.Errorf("unexpected chunk %+v", last)
+	}
+	if _, ok := doc.Metadata["parent_id"]; ok {
+		t.Error("parent metadata should not be modified")
+	}
+}
+
+func TestChunkingTransformer_FixedUTF8(t *testing.T) {
+	ct := NewChunkingTransformer(5, 0, SplitFixed)
+	chunks, err := ct.TransformMany(context.Background(), schema.Document{ID: "d", Content: "héllo wörld"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var joined string
+	for _, c := range chunks {
+		if !utf8.ValidString(c.Content) || len(c.Content) > 5 {
+			t.Errorf("chunk %q is invalid or too long", c.Content)
+		}
+		joined += c.Content
+	}
+	if joined != "héllo wörld" {
+		t.Errorf("chunks without overlap should rebuild the text, got %q", joined)
+	}
+}
+
+func TestChunkingTransformer_Sentence(t *testing.T) {
+	text := "One is first. Two follows! Is three next? Four ends.\n\nA new paragraph"
+	ct := NewChunkingTransformer(30, 15, SplitSentence)
+	chunks, err := ct.TransformMany(context.Background(), schema.Document{ID: "d", Content: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"One is first. Two follows!",
+		"Two follows! Is three next?",
+		"Is three next? Four ends.",
+		"Four ends. A new paragraph",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.Content != want[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, want[i], c.Content)
+		}
+	}
+
+	// A sentence longer than the chunk size is cut.
+	chunks, err = NewChunkingTransformer(10, 0, SplitSentence).TransformMany(context.Background(), schema.Document{ID: "d", Content: "Short. " + strings.Repeat("x", 25) + "."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 4 || chunks[0].Content != "Short." {
+		t.Errorf("unexpected chunks %+v", chunks)
+	}
+}
+
+func TestChunkingTransformer_Recursive(t *testing.T) {
+	text := strings.Repeat("word ", 40) + "\n\n" + strings.Repeat("more ", 40)
+	ct := NewChunkingTransformer(100, 10, SplitRecursive)
+	chunks, err := ct.TransformMany(context.Background(), schema.Document{ID: "d", Content: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) < 4 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for _, c := range chunks {
+		if len(c.Content) > 100 {
+			t.Errorf("chunk of %d bytes exceeds the chunk size", len(c.Content))
+		}
+	}
+}
+
+func TestChunkingTransformer_Transform(t *testing.T) {
+	ct := NewChunkingTransformer(100, 0, SplitRecursive)
+	got, err := ct.Transform(context.Background(), schema.Document{ID: "d", Content: "short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "d#chunk0" || got.Content != "short" {
+		t.Errorf("unexpected chunk %+v", got)
+	}
+
+	empty := schema.Document{ID: "e"}
+	if got, err := ct.Transform(context.Background(), empty); err != nil || got.ID != "e" {
+		t.Errorf("expected an empty document unchanged, got %+v, %v", got, err)
+	}
+
+	_, err = NewChunkingTransformer(3, 0, SplitFixed).Transform(context.Background(), schema.Document{ID: "d", Content: "too long"})
+	if !errors.Is(err, core.Errorf(core.ErrInvalidInput, "")) {
+		t.Errorf("expected ErrInvalidInput for several chunks, got %v", err)
+	}
+}
+
+func TestChunkingTransformer_Defaults(t *testing.T) {
+	ct := NewChunkingTransformer(0, 5000, SplitStrategy(99))
+	if ct.chunkSize != defaultChunkSize || ct.overlap != defaultChunkSize-1 {
+		t.Errorf("expected defaults, got size %d overlap %d", ct.chunkSize, ct.overlap)
+	}
+	if SplitSentence.String() != "sentence" || SplitStrategy(99).String() != "SplitStrategy(99)" {
+		t.Error("unexpected strategy names")
+	}
+}
+
+func TestPipeline_Chunking(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+	if err := os.WriteFile(path, []byte("First sentence. Second sentence.\nThird line here."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPipeline(
+		WithLoader(NewTextLoader()),
+		WithTransformer(NewChunkingTransformer(20, 0, SplitSentence)),
+		WithTransformer(TransformerFunc(func(_ context.Context, doc schema.Document) (schema.Document, error) {
+			doc.Metadata["seen"] = true
+			return doc, nil
+		})),
+	)
+	docs, err := p.Load(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || docs[2].Content != "Third line here." || docs[2].Metadata["seen"] != true {
+		t.Fatalf("unexpected docs %+v", docs)
+	}
+
+	var streamed int
+	for doc, err := range p.LoadStream(context.Background(), path) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Metadata["parent_id"] == nil {
+			t.Errorf("expected chunk metadata, got %+v", doc)
+		}
+		streamed++
+	}
+	// The text loader streams one document per line, each chunked.
+	if streamed != 3 {
+		t.Errorf("expected 3 streamed chunks, got %d", streamed)
+	}
+}
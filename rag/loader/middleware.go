@@ -0,0 +1,237 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"iter"
+	"math"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// Middleware wraps a DocumentLoader to add cross-cutting behaviour.
+// Middlewares are composed via ApplyMiddleware and applied outside-in
+// (the last middleware in the list is the outermost wrapper). WithRetry and
+// WithRateLimit return a StreamingLoader when the loader they wrap is one.
+type Middleware func(DocumentLoader) DocumentLoader
+
+// ApplyMiddleware wraps l with the given middlewares in reverse order so
+// that the first middleware in the list is the outermost (first to execute).
+func ApplyMiddleware(l DocumentLoader, mws ...Middleware) DocumentLoader {
+	for i := len(mws) - 1; i >= 0; i-- {
+		l = mws[i](l)
+	}
+	return l
+}
+
+// retryOp marks errors whose retryability retryLoader overrode so that the
+// original error can be returned once retries are exhausted.
+const retryOp = "loader.retry"
+
+// WithRetry returns middleware that retries failed Load calls with
+// exponential backoff according to policy (see resilience.Retry). Errors for
+// which IsRetryable reports true are retried, as are errors carrying one of
+// policy.RetryableErrors other than missing files and permission errors;
+// all others are returned immediately.
+//
+// A LoadStream call is retried only until the wrapped stream yields its
+// first document; errors after that are passed through, as the documents
+// already yielded cannot be taken back.
+func WithRetry(policy resilience.RetryPolicy) Middleware {
+	return func(next DocumentLoader) DocumentLoader {
+		l := &retryLoader{next: next, policy: policy}
+		if sl, ok := next.(StreamingLoader); ok {
+			return &retryStreamingLoader{retryLoader: l, stream: sl}
+		}
+		return l
+	}
+}
+
+type retryLoader struct {
+	next   DocumentLoader
+	policy resilience.RetryPolicy
+}
+
+func (l *retryLoader) Load(ctx context.Context, source string) ([]schema.Document, error) {
+	docs, err := resilience.Retry(ctx, l.policy, func(ctx context.Context) ([]schema.Document, error) {
+		docs, err := l.next.Load(ctx, source)
+		return docs, tagRetryable(err)
+	})
+	return docs, untagRetryable(err)
+}
+
+type retryStreamingLoader struct {
+	*retryLoader
+	stream StreamingLoader
+}
+
+// pulledStream is a stream that has yielded its first element.
+type pulledStream struct {
+	next  func() (schema.Document, error, bool)
+	stop  func()
+	first schema.Document
+	ok    bool // false if the stream ended without yielding
+}
+
+func (l *retryStreamingLoader) LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		s, err := resilience.Retry(ctx, l.policy, func(ctx context.Context) (pulledStream, error) {
+			next, stop := iter.Pull2(l.stream.LoadStream(ctx, source))
+			doc, err, ok := next()
+			if err != nil {
+				stop()
+				return pulledStream{}, tagRetryable(err)
+			}
+			return pulledStream{next: next, stop: stop, first: doc, ok: ok}, nil
+		})
+		if err != nil {
+			yield(schema.Document{}, untagRetryable(err))
+			return
+		}
+		defer s.stop()
+		if !s.ok || !yield(s.first, nil) {
+			return
+		}
+		for {
+			doc, err, ok := s.next()
+			if !ok || !yield(doc, err) {
+				return
+			}
+		}
+	}
+}
+
+// tagRetryable wraps err so that resilience.Retry agrees with IsRetryable:
+// transient errors that carry no retryable core code are tagged as
+// retryable, and errors that IsRetryable rejects despite a retryable code,
+// such as a missing local file reported as core.ErrProviderDown, are tagged
+// as permanent.
+func tagRetryable(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case !core.IsRetryable(err) && IsRetryable(err):
+		return core.NewError(retryOp, core.ErrProviderDown, "transient error", err)
+	case isLocalFileError(err):
+		return core.NewError(retryOp, core.ErrInvalidInput, "permanent error", err)
+	}
+	return err
+}
+
+// untagRetryable returns the error tagRetryable wrapped.
+func untagRetryable(err error) error {
+	var tagged *core.Error
+	if errors.As(err, &tagged) && tagged.Op == retryOp {
+		return tagged.Err
+	}
+	return err
+}
+
+// WithRateLimit returns middleware that limits Load calls to qps per second
+// on average, with at most ceil(qps) calls in flight at once. The limit is
+// shared by every loader wrapped by the returned middleware. Calls block
+// until permitted or the context is done. A non-positive qps disables the
+// limit.
+//
+// A LoadStream call counts as one call when the stream starts and stays in
+// flight until the stream ends.
+func WithRateLimit(qps float64) Middleware {
+	if qps <= 0 {
+		return func(next DocumentLoader) DocumentLoader { return next }
+	}
+	limiter := resilience.NewRateLimiter(resilience.ProviderLimits{
+		RPM:           int(math.Ceil(qps * 60)),
+		MaxConcurrent: int(math.Ceil(qps)),
+	})
+	return func(next DocumentLoader) DocumentLoader {
+		l := &rateLimitedLoader{next: next, limiter: limiter}
+		if sl, ok := next.(StreamingLoader); ok {
+			return &rateLimitedStreamingLoader{rateLimitedLoader: l, stream: sl}
+		}
+		return l
+	}
+}
+
+type rateLimitedLoader struct {
+	next    DocumentLoader
+	limiter *resilience.RateLimiter
+}
+
+func (l *rateLimitedLoader) Load(ctx context.Context, source string) ([]schema.Document, error) {
+	if err := l.limiter.Allow(ctx); err != nil {
+		return nil, err
+	}
+	defer l.limiter.Release()
+	return l.next.Load(ctx, source)
+}
+
+type rateLimitedStreamingLoader struct {
+	*rateLimitedLoader
+	stream StreamingLoader
+}
+
+func (l *rateLimitedStreamingLoader) LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		if err := l.limiter.Allow(ctx); err != nil {
+			yield(schema.Document{}, err)
+			return
+		}
+		defer l.limiter.Release()
+		for doc, err := range l.stream.LoadStream(ctx, source) {
+			if !yield(doc, err) {
+				return
+			}
+		}
+	}
+}
+
+// IsRetryable reports whether a Load error is transient and worth retrying:
+// errors with a retryable core error code (rate limit, timeout, provider
+// unavailable), HTTP API errors with status 408, 429 or 5xx, network
+// timeouts, refused or reset connections, and connections closed mid-
+// response. Context cancellation and deadline errors are never retryable,
+// and neither are missing files and permission errors, whatever their code.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isLocalFileError(err) {
+		return false
+	}
+	if core.IsRetryable(err) {
+		return true
+	}
+
+	var apiErr *httpclient.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isLocalFileError reports whether err comes from a file that does not
+// exist or cannot be read, which retrying does not fix.
+func isLocalFileError(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)
+}
@@ -2,6 +2,7 @@ package loader
 
 import (
 	"context"
+	"iter"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/schema"
@@ -24,12 +25,25 @@ func WithTransformer(t Transformer) PipelineOption {
 	}
 }
 
+// WithMiddleware wraps every loader of the pipeline with the given
+// middlewares, for example WithRetry and WithRateLimit. It applies to loaders
+// added before or after it.
+func WithMiddleware(mws ...Middleware) PipelineOption {
+	return func(p *LoaderPipeline) {
+		p.middlewares = append(p.middlewares, mws...)
+	}
+}
+
 // LoaderPipeline chains multiple loaders and transformers. When Load is called,
 // all loaders are invoked and their results are concatenated. Then all
-// transformers are applied to each document in order.
+// transformers are applied to each document in order; a SplittingTransformer
+// may replace a document with several. LoadStream does the
+// same one document at a time, reading from loaders that implement
+// StreamingLoader as they yield.
 type LoaderPipeline struct {
 	loaders      []DocumentLoader
 	transformers []Transformer
+	middlewares  []Middleware
 }
 
 // NewPipeline creates a new LoaderPipeline with the given options.
@@ -38,6 +52,11 @@ func NewPipeline(opts ...PipelineOption) *LoaderPipeline {
 	for _, opt := range opts {
 		opt(p)
 	}
+	if len(p.middlewares) > 0 {
+		for i, l := range p.loaders {
+			p.loaders[i] = ApplyMiddleware(l, p.middlewares...)
+		}
+	}
 	return p
 }
 
@@ -60,14 +79,87 @@ func (p *LoaderPipeline) Load(ctx context.Context, source string) ([]schema.Docu
 	for i, t := range p.transformers {
 		transformed := make([]schema.Document, 0, len(docs))
 		for _, doc := range docs {
-			d, err := t.Transform(ctx, doc)
+			ds, err := transformDoc(ctx, t, doc)
 			if err != nil {
 				return nil, core.Errorf(core.ErrProviderDown, "loader: pipeline transformer %d: %w", i, err)
 			}
-			transformed = append(transformed, d)
+			transformed = append(transformed, ds...)
 		}
 		docs = transformed
 	}
 
 	return docs, nil
 }
+
+// LoadStream invokes the loaders on the source in order and yields each
+// document after applying all transformers to it. Loaders that implement
+// StreamingLoader are consumed lazily, so only one document is held at a
+// time; other loaders are loaded in full first.
+func (p *LoaderPipeline) LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		if len(p.loaders) == 0 {
+			yield(schema.Document{}, core.Errorf(core.ErrInvalidInput, "loader: pipeline has no loaders"))
+			return
+		}
+
+		for i, l := range p.loaders {
+			for doc, err := range loadStream(ctx, l, source) {
+				if err != nil {
+					yield(schema.Document{}, core.Errorf(core.ErrProviderDown, "loader: pipeline loader %d: %w", i, err))
+					return
+				}
+				docs := []schema.Document{doc}
+				for j, t := range p.transformers {
+					var transformed []schema.Document
+					for _, d := range docs {
+						ds, err := transformDoc(ctx, t, d)
+						if err != nil {
+							yield(schema.Document{}, core.Errorf(core.ErrProviderDown, "loader: pipeline transformer %d: %w", j, err))
+							return
+						}
+						transformed = append(transformed, ds...)
+					}
+					docs = transformed
+				}
+				for _, d := range docs {
+					if !yield(d, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// transformDoc applies t to doc, using TransformMany when t implements
+// SplittingTransformer.
+func transformDoc(ctx context.Context, t Transformer, doc schema.Document) ([]schema.Document, error) {
+	if st, ok := t.(SplittingTransformer); ok {
+		return st.TransformMany(ctx, doc)
+	}
+	d, err := t.Transform(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return []schema.Document{d}, nil
+}
+
+// loadStream returns the documents of l as a stream, using LoadStream when
+// l implements StreamingLoader.
+func loadStream(ctx context.Context, l DocumentLoader, source string) iter.Seq2[schema.Document, error] {
+	if sl, ok := l.(StreamingLoader); ok {
+		return sl.LoadStream(ctx, source)
+	}
+	return func(yield func(schema.Document, error) bool) {
+		docs, err := l.Load(ctx, source)
+		if err != nil {
+			yield(schema.Document{}, err)
+			return
+		}
+		for _, doc := range docs {
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
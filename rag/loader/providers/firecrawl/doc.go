@@ -19,4 +19,10 @@
 //
 // ProviderConfig fields:
 //   - APIKey — Firecrawl API key (required)
+//
+// # Errors
+//
+// Request timeouts, rate limits (HTTP 429) and server errors (HTTP 500, 502,
+// 503 and 504) are returned with a retryable core error code, so they are
+// retried by loader.WithRetry. Other failures are not retried.
 package firecrawl
@@ -3,8 +3,10 @@ package firecrawl
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/lookatitude/beluga-ai/v2/config"
+	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/rag/loader"
 	"github.com/lookatitude/beluga-ai/v2/schema"
 	fc "github.com/mendableai/firecrawl-go"
@@ -45,7 +47,7 @@ func (l *Loader) Load(ctx context.Context, source string) ([]schema.Document, er
 
 	result, err := l.client.ScrapeURL(source, nil)
 	if err != nil {
-		return nil, fmt.Errorf("firecrawl: scrape %q: %w", source, err)
+		return nil, scrapeError(source, err)
 	}
 
 	content := result.Markdown
@@ -74,3 +76,23 @@ func (l *Loader) Load(ctx context.Context, source string) ([]schema.Document, er
 		Metadata: meta,
 	}}, nil
 }
+
+// scrapeError wraps a scrape failure. The Firecrawl SDK reports HTTP errors
+// as plain messages, so transient ones (request timeouts, rate limits and
+// server errors) are recognised by their text and given a retryable core
+// error code.
+func scrapeError(source string, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "Request Timeout:"):
+		return core.Errorf(core.ErrTimeout, "firecrawl: scrape %q: %w", source, err)
+	case strings.Contains(msg, "Status code 429"):
+		return core.Errorf(core.ErrRateLimit, "firecrawl: scrape %q: %w", source, err)
+	case strings.HasPrefix(msg, "Internal Server Error:"),
+		strings.Contains(msg, "Status code 502"),
+		strings.Contains(msg, "Status code 503"),
+		strings.Contains(msg, "Status code 504"):
+		return core.Errorf(core.ErrProviderDown, "firecrawl: scrape %q: %w", source, err)
+	}
+	return fmt.Errorf("firecrawl: scrape %q: %w", source, err)
+}
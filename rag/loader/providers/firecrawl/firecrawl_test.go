@@ -185,3 +185,38 @@ func TestNew_InvalidURL(t *testing.T) {
 		t.Fatal("expected non-nil loader or error")
 	}
 }
+
+func TestLoad_RetryableErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusRequestTimeout, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusPaymentRequired, false},
+		{http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":"failed"}`)
+			}))
+			defer ts.Close()
+
+			l, err := New(config.ProviderConfig{APIKey: "fc-test", BaseURL: ts.URL})
+			if err != nil {
+				t.Fatalf("New() error: %v", err)
+			}
+			_, err = l.Load(context.Background(), "https://example.com")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := loader.IsRetryable(err); got != tt.retryable {
+				t.Errorf("IsRetryable(%v) = %v, want %v", err, got, tt.retryable)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package loader
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"io"
+	"iter"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/lookatitude/beluga-ai/v2/config"
 	"github.com/lookatitude/beluga-ai/v2/core"
@@ -18,7 +23,7 @@ func init() {
 
 // TextLoader reads plain text files and creates one Document per file.
 // The document content is the full file text, with the source path stored
-// in metadata.
+// in metadata. LoadStream instead yields one Document per line.
 type TextLoader struct{}
 
 // NewTextLoader creates a new TextLoader.
@@ -50,3 +55,56 @@ func (l *TextLoader) Load(ctx context.Context, source string) ([]schema.Document
 	}
 	return []schema.Document{doc}, nil
 }
+
+// LoadStream reads the text file at the given path one line at a time and
+// yields a Document per non-blank line, with its 1-based line number in the
+// "line" metadata key. Line endings are removed.
+func (l *TextLoader) LoadStream(ctx context.Context, source string) iter.Seq2[schema.Document, error] {
+	return func(yield func(schema.Document, error) bool) {
+		cleaned, err := cleanPath(source)
+		if err != nil {
+			yield(schema.Document{}, err)
+			return
+		}
+		// #nosec G304 -- path validated by cleanPath
+		f, err := os.Open(cleaned)
+		if err != nil {
+			yield(schema.Document{}, core.Errorf(core.ErrProviderDown, "loader: text open %q: %w", source, err))
+			return
+		}
+		defer func() { _ = f.Close() }()
+
+		baseName := filepath.Base(source)
+		reader := bufio.NewReader(f)
+		for n := 1; ; n++ {
+			if err := ctx.Err(); err != nil {
+				yield(schema.Document{}, err)
+				return
+			}
+			// ReadString has no line length limit, unlike bufio.Scanner.
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				yield(schema.Document{}, core.Errorf(core.ErrProviderDown, "loader: text read %q: %w", source, err))
+				return
+			}
+			if text := strings.TrimRight(line, "\r\n"); strings.TrimSpace(text) != "" {
+				doc := schema.Document{
+					ID:      fmt.Sprintf("%s#%d", source, n),
+					Content: text,
+					Metadata: map[string]any{
+						"source": source,
+						"format": "text",
+						"name":   baseName,
+						"line":   n,
+					},
+				}
+				if !yield(doc, nil) {
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// AdaptiveAlgorithm selects how an AdaptiveLimiter adjusts its limit.
+type AdaptiveAlgorithm string
+
+const (
+	// AlgorithmAIMD grows the limit by one per limit's worth of successful
+	// requests (additive increase) and multiplies it by BackoffRatio on
+	// overload (multiplicative decrease). Overload is an overload error or,
+	// when LatencyThreshold is set, a request slower than the threshold.
+	AlgorithmAIMD AdaptiveAlgorithm = "aimd"
+
+	// AlgorithmGradient compares short-term latency with a long-term
+	// baseline and shrinks the limit in proportion as latency rises above
+	// it, growing it while latency stays at the baseline. It needs no
+	// latency threshold. Overload errors multiply the limit by BackoffRatio.
+	AlgorithmGradient AdaptiveAlgorithm = "gradient"
+)
+
+// AdaptiveConfig configures an AdaptiveLimiter. Zero-value fields fall back
+// to their defaults.
+type AdaptiveConfig struct {
+	// Algorithm is the limit algorithm. Default is AlgorithmAIMD.
+	Algorithm AdaptiveAlgorithm
+
+	// InitialLimit is the concurrency limit to start with. Default is 10.
+	InitialLimit int
+
+	// MinLimit is the lowest the limit may fall. Default is 1.
+	MinLimit int
+
+	// MaxLimit is the highest the limit may rise. Default is 1000.
+	MaxLimit int
+
+	// LatencyThreshold, for AlgorithmAIMD, is the latency above which a
+	// successful request counts as overload. Zero means only errors do.
+	LatencyThreshold time.Duration
+
+	// BackoffRatio is the factor, between 0 and 1, the limit is multiplied
+	// by on overload. Default is 0.9.
+	BackoffRatio float64
+}
+
+// AdaptiveLimiter limits the number of in-flight requests like the
+// MaxConcurrent setting of RateLimiter, but adjusts the limit from observed
+// latency and errors instead of relying on a fixed value: it probes for more
+// capacity while a dependency is healthy and backs off as soon as it
+// degrades.
+//
+// Every successful Allow must be paired with a Release reporting the
+// request's latency and error. Errors for which core.IsRetryable is true
+// (rate limits, timeouts, unavailable providers) and context deadline
+// errors signal overload; other errors are not used to adjust the limit.
+//
+// AdaptiveLimiter is safe for concurrent use.
+type AdaptiveLimiter struct {
+	cfg AdaptiveConfig
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int
+	changed  chan struct{} // closed and replaced when a slot may be free
+
+	// Gradient state: exponentially weighted latency averages in
+	// nanoseconds.
+	shortRTT float64
+	longRTT  float64
+}
+
+// NewAdaptiveLimiter creates an AdaptiveLimiter with the given configuration.
+func NewAdaptiveLimiter(cfg AdaptiveConfig) *AdaptiveLimiter {
+	cfg = normalizeAdaptiveConfig(cfg)
+	return &AdaptiveLimiter{
+		cfg:     cfg,
+		limit:   float64(cfg.InitialLimit),
+		changed: make(chan struct{}),
+	}
+}
+
+// normalizeAdaptiveConfig fills zero-value fields with their defaults.
+func normalizeAdaptiveConfig(cfg AdaptiveConfig) AdaptiveConfig {
+	if cfg.Algorithm != AlgorithmGradient {
+		cfg.Algorithm = AlgorithmAIMD
+	}
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = 1
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = 1000
+	}
+	if cfg.MaxLimit < cfg.MinLimit {
+		cfg.MaxLimit = cfg.MinLimit
+	}
+	if cfg.InitialLimit <= 0 {
+		cfg.InitialLimit = 10
+	}
+	cfg.InitialLimit = min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit)
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		cfg.BackoffRatio = 0.9
+	}
+	return cfg
+}
+
+// Allow blocks until the number of in-flight requests is below the current
+// limit, or the context is cancelled. Call Release when the request
+// completes.
+func (l *AdaptiveLimiter) Allow(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Release frees the slot taken by Allow and adjusts the limit from the
+// request's latency and error.
+func (l *AdaptiveLimiter) Release(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case isOverload(err):
+		l.limit *= l.cfg.BackoffRatio
+	case err != nil:
+	case l.cfg.Algorithm == AlgorithmGradient:
+		l.gradient(latency)
+	case l.cfg.LatencyThreshold > 0 && latency > l.cfg.LatencyThreshold:
+		l.limit *= l.cfg.BackoffRatio
+	case l.inFlight*2 >= int(l.limit):
+		// Only grow while the limit is actually being used.
+		l.limit += 1 / l.limit
+	}
+	l.limit = min(max(l.limit, float64(l.cfg.MinLimit)), float64(l.cfg.MaxLimit))
+
+	if l.inFlight > 0 {
+		l.inFlight--
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// gradient applies a latency sample with the gradient algorithm. Caller must
+// hold l.mu.
+func (l *AdaptiveLimiter) gradient(latency time.Duration) {
+	rtt := float64(max(latency, time.Microsecond))
+	if l.longRTT == 0 {
+		l.shortRTT, l.longRTT = rtt, rtt
+		return
+	}
+	l.shortRTT = 0.9*l.shortRTT + 0.1*rtt
+	l.longRTT = 0.99*l.longRTT + 0.01*rtt
+	// Pull an inflated baseline back down once latency recovers, so that
+	// later increases are still detected.
+	l.longRTT = min(l.longRTT, 2*l.shortRTT)
+
+	g := min(max(l.longRTT/l.shortRTT, 0.5), 1)
+	target := l.limit * g
+	if l.inFlight*2 >= int(l.limit) {
+		// Allow a queue of sqrt(limit) requests above the current limit.
+		target += math.Sqrt(l.limit)
+	}
+	l.limit = 0.8*l.limit + 0.2*target
+}
+
+// isOverload reports whether err signals that the dependency is overloaded.
+func isOverload(err error) bool {
+	return err != nil && (core.IsRetryable(err) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// errPanicked is the error a slot is released with when fn panics, so the
+// call leaves the limit unchanged.
+var errPanicked = errors.New("resilience: call panicked")
+
+// Execute runs fn once Allow permits it and releases its slot with the
+// observed latency and error. The slot is released even if fn panics.
+func (l *AdaptiveLimiter) Execute(ctx context.Context, fn func(ctx context.Context) (any, error)) (result any, err error) {
+	if err := l.Allow(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	outcome := errPanicked
+	defer func() { l.Release(time.Since(start), outcome) }()
+	result, err = fn(ctx)
+	outcome = err
+	return result, err
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of requests holding a slot.
+func (l *AdaptiveLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
@@ -0,0 +1,206 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func TestNewAdaptiveLimiter_Defaults(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{})
+	if l.cfg.Algorithm != AlgorithmAIMD {
+		t.Errorf("Algorithm = %q, want %q", l.cfg.Algorithm, AlgorithmAIMD)
+	}
+	if l.Limit() != 10 {
+		t.Errorf("Limit() = %d, want 10", l.Limit())
+	}
+	if l.cfg.MinLimit != 1 || l.cfg.MaxLimit != 1000 || l.cfg.BackoffRatio != 0.9 {
+		t.Errorf("unexpected defaults %+v", l.cfg)
+	}
+
+	l = NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 50, MaxLimit: 20})
+	if l.Limit() != 20 {
+		t.Errorf("Limit() = %d, want InitialLimit clamped to 20", l.Limit())
+	}
+}
+
+// saturate takes every free slot of l.
+func saturate(t *testing.T, l *AdaptiveLimiter) {
+	t.Helper()
+	n := l.Limit() - l.InFlight()
+	for range n {
+		if err := l.Allow(context.Background()); err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+	}
+}
+
+func TestAdaptiveLimiter_AIMDIncrease(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 4})
+
+	// About a limit's worth of successes under load grows the limit by one.
+	for range 5 {
+		saturate(t, l)
+		l.Release(time.Millisecond, nil)
+	}
+	if got := l.Limit(); got != 5 {
+		t.Errorf("Limit() = %d, want 5", got)
+	}
+}
+
+func TestAdaptiveLimiter_AIMDNoIncreaseWhenIdle(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 4})
+	for range 20 {
+		if err := l.Allow(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.Release(time.Millisecond, nil)
+	}
+	if got := l.Limit(); got != 4 {
+		t.Errorf("Limit() = %d, want 4 while the limit is not used", got)
+	}
+}
+
+func TestAdaptiveLimiter_AIMDDecrease(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		err     error
+		want    int
+	}{
+		{name: "rate limit", err: core.NewError("op", core.ErrRateLimit, "throttled", nil), want: 9},
+		{name: "deadline", err: context.DeadlineExceeded, want: 9},
+		{name: "slow", latency: time.Second, want: 9},
+		{name: "other error", err: errors.New("bad request"), want: 10},
+		{name: "cancelled", err: context.Canceled, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewAdaptiveLimiter(AdaptiveConfig{LatencyThreshold: 100 * time.Millisecond})
+			if err := l.Allow(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			l.Release(tt.latency, tt.err)
+			if got := l.Limit(); got != tt.want {
+				t.Errorf("Limit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter_MinLimit(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 4, MinLimit: 2, BackoffRatio: 0.5})
+	overload := core.NewError("op", core.ErrProviderDown, "down", nil)
+	for range 10 {
+		if err := l.Allow(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		l.Release(time.Millisecond, overload)
+	}
+	if got := l.Limit(); got != 2 {
+		t.Errorf("Limit() = %d, want MinLimit 2", got)
+	}
+}
+
+func TestAdaptiveLimiter_AllowBlocksAtLimit(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 1, MaxLimit: 1})
+	if err := l.Allow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Allow(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Allow() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- l.Allow(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	l.Release(time.Millisecond, nil)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Allow() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Allow() did not unblock after Release")
+	}
+	if got := l.InFlight(); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+}
+
+func TestAdaptiveLimiter_Gradient(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{Algorithm: AlgorithmGradient, InitialLimit: 20})
+
+	// Steady latency under load grows the limit.
+	for range 20 {
+		saturate(t, l)
+		l.Release(10*time.Millisecond, nil)
+	}
+	grown := l.Limit()
+	if grown <= 20 {
+		t.Fatalf("Limit() = %d, want growth above 20 at steady latency", grown)
+	}
+
+	// Rising latency shrinks it as the outstanding requests complete.
+	for range 10 {
+		l.Release(100*time.Millisecond, nil)
+	}
+	if got := l.Limit(); got >= grown {
+		t.Errorf("Limit() = %d, want a decrease from %d as latency rises", got, grown)
+	}
+}
+
+func TestAdaptiveLimiter_Execute(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{})
+	result, err := l.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	})
+	if err != nil || result != "ok" {
+		t.Fatalf("Execute() = %v, %v, want ok", result, err)
+	}
+	if got := l.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %d, want 0", got)
+	}
+
+	_, err = l.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, core.NewError("op", core.ErrRateLimit, "throttled", nil)
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := l.Limit(); got != 9 {
+		t.Errorf("Limit() = %d, want 9 after an overload error", got)
+	}
+}
+
+func TestAdaptiveLimiter_ExecutePanicReleasesSlot(t *testing.T) {
+	l := NewAdaptiveLimiter(AdaptiveConfig{InitialLimit: 1})
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected fn's panic to propagate")
+			}
+		}()
+		_, _ = l.Execute(context.Background(), func(_ context.Context) (any, error) {
+			panic("boom")
+		})
+	}()
+	if got := l.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %d, want 0 after a panic", got)
+	}
+	if got := l.Limit(); got != 1 {
+		t.Errorf("Limit() = %d, want 1", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := l.Execute(ctx, func(_ context.Context) (any, error) { return "ok", nil }); err != nil {
+		t.Errorf("Execute() error = %v, want the slot available", err)
+	}
+}
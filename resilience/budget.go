@@ -0,0 +1,63 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBudgetExhausted is returned, wrapping the last attempt's error, when
+// Retry stops early because the remaining deadline budget cannot fit another
+// backoff and attempt.
+var ErrBudgetExhausted = errors.New("resilience: deadline budget exhausted")
+
+// Budget is an overall time allowance for an operation and everything it
+// retries or hedges. It is created by WithBudget and carried in the context.
+type Budget struct {
+	total    time.Duration
+	deadline time.Time
+}
+
+// budgetKey is the context key for the active Budget.
+type budgetKey struct{}
+
+// WithBudget returns a copy of ctx that carries a deadline budget of total,
+// starting now, and whose deadline is the end of the budget. If ctx already
+// has an earlier deadline, the budget ends there instead. The returned
+// Budget reports what is left, and Retry consults it between attempts so
+// that it fails fast rather than overshooting the deadline.
+//
+// Canceling the returned context releases its resources, so call cancel as
+// soon as the operation completes.
+func WithBudget(ctx context.Context, total time.Duration) (context.Context, *Budget, context.CancelFunc) {
+	b := &Budget{total: total, deadline: time.Now().Add(total)}
+	if d, ok := ctx.Deadline(); ok && d.Before(b.deadline) {
+		b.deadline = d
+	}
+	ctx, cancel := context.WithDeadline(ctx, b.deadline)
+	return context.WithValue(ctx, budgetKey{}, b), b, cancel
+}
+
+// BudgetFromContext returns the innermost Budget carried by ctx, or nil if
+// there is none.
+func BudgetFromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
+
+// Total returns the duration the budget was created with.
+func (b *Budget) Total() time.Duration { return b.total }
+
+// Deadline returns the time the budget runs out.
+func (b *Budget) Deadline() time.Time { return b.deadline }
+
+// Remaining returns the time left in the budget, or zero once it is spent.
+func (b *Budget) Remaining() time.Duration {
+	return max(time.Until(b.deadline), 0)
+}
+
+// Fits reports whether work expected to take d can complete before the
+// budget runs out.
+func (b *Budget) Fits(d time.Duration) bool {
+	return d < b.Remaining()
+}
@@ -0,0 +1,132 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func TestWithBudget(t *testing.T) {
+	ctx, b, cancel := WithBudget(context.Background(), time.Minute)
+	defer cancel()
+
+	if BudgetFromContext(ctx) != b {
+		t.Fatal("BudgetFromContext() did not return the budget")
+	}
+	if d, ok := ctx.Deadline(); !ok || !d.Equal(b.Deadline()) {
+		t.Errorf("context deadline = %v, want %v", d, b.Deadline())
+	}
+	if b.Total() != time.Minute {
+		t.Errorf("Total() = %v, want %v", b.Total(), time.Minute)
+	}
+	if r := b.Remaining(); r <= 0 || r > time.Minute {
+		t.Errorf("Remaining() = %v, want within (0, 1m]", r)
+	}
+	if !b.Fits(time.Second) || b.Fits(2*time.Minute) {
+		t.Error("Fits() did not compare against the remaining budget")
+	}
+	if BudgetFromContext(context.Background()) != nil {
+		t.Error("BudgetFromContext() on a plain context should be nil")
+	}
+}
+
+func TestWithBudget_ParentDeadline(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	_, b, cancel := WithBudget(parent, time.Hour)
+	defer cancel()
+
+	d, _ := parent.Deadline()
+	if !b.Deadline().Equal(d) {
+		t.Errorf("Deadline() = %v, want the parent's %v", b.Deadline(), d)
+	}
+}
+
+func TestWithBudget_Exhausted(t *testing.T) {
+	ctx, b, cancel := WithBudget(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	if b.Remaining() != 0 {
+		t.Errorf("Remaining() = %v, want 0", b.Remaining())
+	}
+	if b.Fits(0) {
+		t.Error("Fits(0) should be false once the budget is spent")
+	}
+}
+
+func TestRetry_StopsWhenBudgetCannotFitBackoff(t *testing.T) {
+	ctx, _, cancel := WithBudget(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var calls atomic.Int32
+	start := time.Now()
+	_, err := Retry(ctx, RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+	}, func(_ context.Context) (string, error) {
+		calls.Add(1)
+		return "", core.NewError("op", core.ErrRateLimit, "throttled", nil)
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("Retry() error = %v, want ErrBudgetExhausted", err)
+	}
+	var coreErr *core.Error
+	if !errors.As(err, &coreErr) || coreErr.Code != core.ErrRateLimit {
+		t.Errorf("Retry() error should wrap the last attempt's error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Retry() took %v, want it to fail fast", elapsed)
+	}
+}
+
+func TestRetry_BudgetAccountsForAttemptDuration(t *testing.T) {
+	ctx, _, cancel := WithBudget(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Each attempt takes 60ms and backoff is 10ms: after two attempts at most
+	// 70ms remain, which cannot fit the backoff plus a third attempt.
+	var calls atomic.Int32
+	_, err := Retry(ctx, RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 10 * time.Millisecond,
+		BackoffFactor:  1,
+	}, func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		time.Sleep(60 * time.Millisecond)
+		return "", core.NewError("op", core.ErrTimeout, "slow", nil)
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("Retry() error = %v, want ErrBudgetExhausted", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("Retry() should stop before the deadline passes")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestRetry_WithinBudget(t *testing.T) {
+	ctx, _, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+
+	var calls atomic.Int32
+	result, err := Retry(ctx, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func(_ context.Context) (string, error) {
+		if calls.Add(1) < 3 {
+			return "", core.NewError("op", core.ErrRateLimit, "throttled", nil)
+		}
+		return "ok", nil
+	})
+	if err != nil || result != "ok" {
+		t.Fatalf("Retry() = %q, %v, want ok", result, err)
+	}
+}
@@ -0,0 +1,132 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBulkheadFull is returned when a Bulkhead has no free execution slot and
+// its queue is saturated.
+var ErrBulkheadFull = errors.New("resilience: bulkhead is full")
+
+// Bulkhead isolates a dependency by limiting how many calls to it run
+// concurrently. Calls beyond the limit wait in a bounded queue; once the
+// queue is full they are rejected with ErrBulkheadFull instead of blocking,
+// so a slow dependency cannot tie up every goroutine of its callers.
+//
+// Use one Bulkhead per dependency. It composes with CircuitBreaker by
+// wrapping it, so that calls rejected by an open circuit release their slot
+// immediately:
+//
+//	result, err := bh.Execute(ctx, func(ctx context.Context) (any, error) {
+//	    return cb.Execute(ctx, callService)
+//	})
+type Bulkhead struct {
+	maxConcurrent int
+	maxQueue      int
+	slots         chan struct{}
+
+	mu       sync.Mutex
+	queued   int
+	rejected uint64
+}
+
+// BulkheadMetrics is a snapshot of a Bulkhead's load.
+type BulkheadMetrics struct {
+	// Active is the number of calls currently executing.
+	Active int
+	// Queued is the number of calls waiting for a slot.
+	Queued int
+	// Rejected is the total number of calls rejected with ErrBulkheadFull.
+	Rejected uint64
+	// MaxConcurrent and MaxQueue are the configured limits.
+	MaxConcurrent int
+	MaxQueue      int
+}
+
+// NewBulkhead creates a Bulkhead that runs at most maxConcurrent calls at
+// once and lets at most maxQueue more wait for a slot. A maxConcurrent of
+// zero or less defaults to 10; a negative maxQueue is treated as zero, which
+// rejects calls as soon as every slot is busy.
+func NewBulkhead(maxConcurrent, maxQueue int) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 10
+	}
+	if maxQueue < 0 {
+		maxQueue = 0
+	}
+	return &Bulkhead{
+		maxConcurrent: maxConcurrent,
+		maxQueue:      maxQueue,
+		slots:         make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Execute runs fn once a slot is free. If every slot is busy and the queue is
+// full, ErrBulkheadFull is returned without calling fn. If ctx is cancelled
+// while waiting in the queue, the context error is returned.
+func (b *Bulkhead) Execute(ctx context.Context, fn func(ctx context.Context) (any, error)) (any, error) {
+	if err := b.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer func() { <-b.slots }()
+	return fn(ctx)
+}
+
+// acquire takes a slot, waiting in the queue if there is room.
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	b.mu.Lock()
+	if b.queued >= b.maxQueue {
+		b.rejected++
+		b.mu.Unlock()
+		return ErrBulkheadFull
+	}
+	b.queued++
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.queued--
+		b.mu.Unlock()
+	}()
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Active returns the number of calls currently executing.
+func (b *Bulkhead) Active() int { return len(b.slots) }
+
+// Queued returns the number of calls waiting for a slot.
+func (b *Bulkhead) Queued() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.queued
+}
+
+// Metrics returns a snapshot of the bulkhead's load.
+func (b *Bulkhead) Metrics() BulkheadMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BulkheadMetrics{
+		Active:        len(b.slots),
+		Queued:        b.queued,
+		Rejected:      b.rejected,
+		MaxConcurrent: b.maxConcurrent,
+		MaxQueue:      b.maxQueue,
+	}
+}
@@ -0,0 +1,163 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewBulkhead_Defaults(t *testing.T) {
+	b := NewBulkhead(0, -1)
+	m := b.Metrics()
+	if m.MaxConcurrent != 10 {
+		t.Errorf("MaxConcurrent = %d, want 10 (default)", m.MaxConcurrent)
+	}
+	if m.MaxQueue != 0 {
+		t.Errorf("MaxQueue = %d, want 0", m.MaxQueue)
+	}
+}
+
+// fillBulkhead starts n calls that block until release is closed and waits
+// until they are all active or queued.
+func fillBulkhead(t *testing.T, b *Bulkhead, n int, release <-chan struct{}) *sync.WaitGroup {
+	t.Helper()
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			_, _ = b.Execute(context.Background(), func(_ context.Context) (any, error) {
+				<-release
+				return nil, nil
+			})
+		})
+	}
+	deadline := time.Now().Add(time.Second)
+	for b.Active()+b.Queued() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("active+queued = %d, want %d", b.Active()+b.Queued(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return &wg
+}
+
+func TestBulkhead_RejectsWhenQueueFull(t *testing.T) {
+	b := NewBulkhead(2, 1)
+	release := make(chan struct{})
+	wg := fillBulkhead(t, b, 3, release)
+
+	if got := b.Active(); got != 2 {
+		t.Errorf("Active() = %d, want 2", got)
+	}
+	if got := b.Queued(); got != 1 {
+		t.Errorf("Queued() = %d, want 1", got)
+	}
+
+	called := false
+	_, err := b.Execute(context.Background(), func(_ context.Context) (any, error) {
+		called = true
+		return nil, nil
+	})
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Execute() error = %v, want ErrBulkheadFull", err)
+	}
+	if called {
+		t.Error("fn should not be called when the bulkhead is full")
+	}
+	if got := b.Metrics().Rejected; got != 1 {
+		t.Errorf("Rejected = %d, want 1", got)
+	}
+
+	close(release)
+	wg.Wait()
+	if m := b.Metrics(); m.Active != 0 || m.Queued != 0 {
+		t.Errorf("Metrics() = %+v, want no active or queued calls", m)
+	}
+}
+
+func TestBulkhead_QueuedCallRuns(t *testing.T) {
+	b := NewBulkhead(1, 1)
+	release := make(chan struct{})
+	wg := fillBulkhead(t, b, 1, release)
+
+	done := make(chan error, 1)
+	go func() {
+		result, err := b.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return "ok", nil
+		})
+		if err == nil && result != "ok" {
+			err = errors.New("unexpected result")
+		}
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("queued call ran before a slot was free")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("queued Execute() error = %v", err)
+	}
+	wg.Wait()
+}
+
+func TestBulkhead_ContextCancelledWhileQueued(t *testing.T) {
+	b := NewBulkhead(1, 1)
+	release := make(chan struct{})
+	defer close(release)
+	fillBulkhead(t, b, 1, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := b.Execute(ctx, func(_ context.Context) (any, error) {
+		t.Error("fn should not be called")
+		return nil, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := b.Queued(); got != 0 {
+		t.Errorf("Queued() = %d, want 0", got)
+	}
+}
+
+func TestBulkhead_PropagatesResult(t *testing.T) {
+	b := NewBulkhead(1, 0)
+	errFail := errors.New("fail")
+	result, err := b.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "partial", errFail
+	})
+	if !errors.Is(err, errFail) || result != "partial" {
+		t.Errorf("Execute() = %v, %v, want partial, fail", result, err)
+	}
+	if got := b.Active(); got != 0 {
+		t.Errorf("Active() = %d, want 0 after a failed call", got)
+	}
+}
+
+func TestBulkhead_WithCircuitBreaker(t *testing.T) {
+	b := NewBulkhead(1, 0)
+	cb := NewCircuitBreaker(1, time.Minute)
+	errFail := errors.New("fail")
+	call := func() error {
+		_, err := b.Execute(context.Background(), func(ctx context.Context) (any, error) {
+			return cb.Execute(ctx, func(_ context.Context) (any, error) {
+				return nil, errFail
+			})
+		})
+		return err
+	}
+
+	if err := call(); !errors.Is(err, errFail) {
+		t.Fatalf("first call error = %v, want fail", err)
+	}
+	if err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second call error = %v, want ErrCircuitOpen", err)
+	}
+	if got := b.Active(); got != 0 {
+		t.Errorf("Active() = %d, want 0", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/o11y"
 )
 
 // State represents the current state of a CircuitBreaker.
@@ -25,6 +27,29 @@ const (
 // the reset timeout has not yet elapsed.
 var ErrCircuitOpen = errors.New("resilience: circuit breaker is open")
 
+// ignoredError marks an error whose call says nothing about the protected
+// dependency.
+type ignoredError struct {
+	err error
+}
+
+func (e ignoredError) Error() string { return e.err.Error() }
+
+func (e ignoredError) Unwrap() error { return e.err }
+
+// Ignore wraps err for a function run by CircuitBreaker.Execute whose
+// outcome says nothing about the dependency, for example because the caller
+// canceled the call before the dependency answered. Execute returns err
+// itself and counts the call as neither a success nor a failure; a
+// half-open probe that returns it frees its slot for another probe. The
+// function must return the wrapped error unchanged.
+func Ignore(err error) error {
+	if err == nil {
+		return nil
+	}
+	return ignoredError{err: err}
+}
+
 // CircuitBreaker implements the circuit-breaker stability pattern. It wraps
 // function calls and short-circuits when a failure threshold is exceeded,
 // giving the downstream dependency time to recover.
@@ -33,11 +58,21 @@ var ErrCircuitOpen = errors.New("resilience: circuit breaker is open")
 //
 //	closed  → open      after failureThreshold consecutive failures
 //	open    → half-open after resetTimeout elapses
-//	half-open → closed  on a successful probe call
+//	half-open → closed  after the configured number of successful probes
 //	half-open → open    on a failed probe call
+//
+// Every call outcome and state change is recorded as an o11y metric:
+// "resilience.circuit_breaker.calls" with an "outcome" attribute of
+// success, failure, rejected or ignored (see Ignore), and
+// "resilience.circuit_breaker.state_changes" with "from" and "to"
+// attributes. Both carry the breaker's name (see
+// WithCircuitBreakerName) in the "name" attribute.
 type CircuitBreaker struct {
 	failureThreshold int
 	resetTimeout     time.Duration
+	maxProbes        int
+	name             string
+	onStateChange    func(from, to State)
 
 	mu          sync.Mutex
 	state       State
@@ -45,23 +80,86 @@ type CircuitBreaker struct {
 	lastFailure time.Time
 	// successes tracks consecutive successes in half-open state.
 	successes int
+	// probes counts the probe calls admitted in the current half-open state.
+	probes int
+	// probeStarted is when the latest probe was admitted.
+	probeStarted time.Time
+	// epoch is incremented on every state change and whenever stale probes
+	// are discarded, so a call can tell whether the probe slot it took is
+	// still counted.
+	epoch uint64
+
+	// Lifetime counters reported by Stats.
+	totalSuccesses uint64
+	totalFailures  uint64
+	rejections     uint64
+}
+
+// CircuitBreakerStats is a snapshot of a CircuitBreaker's state and
+// counters.
+type CircuitBreakerStats struct {
+	// State is the current state.
+	State State
+	// Successes and Failures count completed calls since creation.
+	Successes uint64
+	Failures  uint64
+	// Rejections counts calls short-circuited with ErrCircuitOpen.
+	Rejections uint64
+	// ConsecutiveFailures is the current run of failures.
+	ConsecutiveFailures int
+}
+
+// CircuitBreakerOption configures a CircuitBreaker.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithOnStateChange registers a callback invoked on every state transition.
+// It is called while the breaker's lock is held, so it must be fast and must
+// not call methods of the breaker.
+func WithOnStateChange(fn func(from, to State)) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.onStateChange = fn
+	}
+}
+
+// WithHalfOpenMaxProbes sets how many probe calls the half-open state admits.
+// The breaker closes once all of them have succeeded and reopens on the
+// first failure; further calls are rejected while the probes are decided.
+// Default is 1.
+func WithHalfOpenMaxProbes(n int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		if n > 0 {
+			cb.maxProbes = n
+		}
+	}
+}
+
+// WithCircuitBreakerName names the protected dependency in metrics.
+func WithCircuitBreakerName(name string) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.name = name
+	}
 }
 
 // NewCircuitBreaker creates a CircuitBreaker that opens after
 // failureThreshold consecutive failures and stays open for resetTimeout
 // before transitioning to half-open.
-func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *CircuitBreaker {
+func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration, opts ...CircuitBreakerOption) *CircuitBreaker {
 	if failureThreshold <= 0 {
 		failureThreshold = 5
 	}
 	if resetTimeout <= 0 {
 		resetTimeout = 30 * time.Second
 	}
-	return &CircuitBreaker{
+	cb := &CircuitBreaker{
 		failureThreshold: failureThreshold,
 		resetTimeout:     resetTimeout,
+		maxProbes:        1,
 		state:            StateClosed,
 	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
 }
 
 // State returns the current state of the circuit breaker. If the breaker is
@@ -69,19 +167,60 @@ func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *Circui
 func (cb *CircuitBreaker) State() State {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
-	return cb.stateLocked()
+	return cb.stateLocked(context.Background())
+}
+
+// Stats returns the current state and call counters.
+func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return CircuitBreakerStats{
+		State:               cb.stateLocked(context.Background()),
+		Successes:           cb.totalSuccesses,
+		Failures:            cb.totalFailures,
+		Rejections:          cb.rejections,
+		ConsecutiveFailures: cb.failures,
+	}
 }
 
 // stateLocked returns the effective state, promoting open → half-open when
 // the reset timeout has elapsed. Caller must hold cb.mu.
-func (cb *CircuitBreaker) stateLocked() State {
+func (cb *CircuitBreaker) stateLocked(ctx context.Context) State {
 	if cb.state == StateOpen && time.Since(cb.lastFailure) >= cb.resetTimeout {
-		cb.state = StateHalfOpen
-		cb.successes = 0
+		cb.setState(ctx, StateHalfOpen)
 	}
 	return cb.state
 }
 
+// setState transitions to the given state, resetting the half-open probe
+// counters and reporting the change. Caller must hold cb.mu.
+func (cb *CircuitBreaker) setState(ctx context.Context, to State) {
+	from := cb.state
+	cb.state = to
+	cb.successes = 0
+	cb.probes = 0
+	cb.epoch++
+	if from == to {
+		return
+	}
+	o11y.CounterWithAttrs(ctx, "resilience.circuit_breaker.state_changes", 1, o11y.Attrs{
+		"name": cb.name,
+		"from": string(from),
+		"to":   string(to),
+	})
+	if cb.onStateChange != nil {
+		cb.onStateChange(from, to)
+	}
+}
+
+// recordCall reports a call outcome metric.
+func (cb *CircuitBreaker) recordCall(ctx context.Context, outcome string) {
+	o11y.CounterWithAttrs(ctx, "resilience.circuit_breaker.calls", 1, o11y.Attrs{
+		"name":    cb.name,
+		"outcome": outcome,
+	})
+}
+
 // Trip forces the circuit breaker into the open state and refreshes the
 // last-failure timestamp to now. This is intended for callers that need to
 // record a failure even when the breaker is already open, so that the reset
@@ -91,8 +230,7 @@ func (cb *CircuitBreaker) Trip() {
 	defer cb.mu.Unlock()
 	cb.failures++
 	cb.lastFailure = time.Now()
-	cb.successes = 0
-	cb.state = StateOpen
+	cb.setState(context.Background(), StateOpen)
 }
 
 // Reset manually resets the circuit breaker to the closed state, clearing
@@ -100,41 +238,78 @@ func (cb *CircuitBreaker) Trip() {
 func (cb *CircuitBreaker) Reset() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
-	cb.state = StateClosed
+	cb.setState(context.Background(), StateClosed)
 	cb.failures = 0
-	cb.successes = 0
 	cb.lastFailure = time.Time{}
 }
 
 // Execute runs fn through the circuit breaker. If the circuit is open,
-// ErrCircuitOpen is returned without calling fn. In half-open state a single
-// probe call is allowed.
+// ErrCircuitOpen is returned without calling fn. In half-open state only the
+// configured number of probe calls are allowed through. Probes that have not
+// finished within the reset timeout are considered lost and no longer hold
+// their slots, and a probe whose fn panics frees its slot.
 func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) (any, error)) (any, error) {
 	cb.mu.Lock()
-	s := cb.stateLocked()
-	if s == StateOpen {
+	s := cb.stateLocked(ctx)
+	if s == StateHalfOpen && cb.probes >= cb.maxProbes && time.Since(cb.probeStarted) >= cb.resetTimeout {
+		cb.probes = 0
+		cb.epoch++
+	}
+	if s == StateOpen || (s == StateHalfOpen && cb.probes >= cb.maxProbes) {
+		cb.rejections++
 		cb.mu.Unlock()
+		cb.recordCall(ctx, "rejected")
 		return nil, ErrCircuitOpen
 	}
+	probe := s == StateHalfOpen
+	if probe {
+		cb.probes++
+		cb.probeStarted = time.Now()
+	}
+	epoch := cb.epoch
 	cb.mu.Unlock()
 
+	done := false
+	defer func() {
+		if !done {
+			cb.mu.Lock()
+			cb.releaseProbeLocked(probe, epoch)
+			cb.mu.Unlock()
+		}
+	}()
 	result, err := fn(ctx)
+	done = true
 
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	if ign, ok := err.(ignoredError); ok {
+		cb.releaseProbeLocked(probe, epoch)
+		cb.recordCall(ctx, "ignored")
+		return result, ign.err
+	}
 	if err != nil {
-		cb.recordFailure()
+		cb.recordFailure(ctx)
 		return result, err
 	}
 
-	cb.recordSuccess()
+	cb.recordSuccess(ctx)
 	return result, nil
 }
 
+// releaseProbeLocked frees the slot of a probe call that ended without an
+// outcome, unless the slot was already discarded. Caller must hold cb.mu.
+func (cb *CircuitBreaker) releaseProbeLocked(probe bool, epoch uint64) {
+	if probe && cb.epoch == epoch {
+		cb.probes--
+	}
+}
+
 // recordFailure increments the failure counter and may trip the breaker.
 // Caller must hold cb.mu.
-func (cb *CircuitBreaker) recordFailure() {
+func (cb *CircuitBreaker) recordFailure(ctx context.Context) {
+	cb.totalFailures++
+	cb.recordCall(ctx, "failure")
 	cb.failures++
 	cb.lastFailure = time.Now()
 	cb.successes = 0
@@ -142,22 +317,24 @@ func (cb *CircuitBreaker) recordFailure() {
 	switch cb.state {
 	case StateClosed:
 		if cb.failures >= cb.failureThreshold {
-			cb.state = StateOpen
+			cb.setState(ctx, StateOpen)
 		}
 	case StateHalfOpen:
 		// A single failure in half-open sends us back to open.
-		cb.state = StateOpen
+		cb.setState(ctx, StateOpen)
 	}
 }
 
 // recordSuccess resets failure counters and may close the breaker.
 // Caller must hold cb.mu.
-func (cb *CircuitBreaker) recordSuccess() {
+func (cb *CircuitBreaker) recordSuccess(ctx context.Context) {
+	cb.totalSuccesses++
+	cb.recordCall(ctx, "success")
 	cb.successes++
 	cb.failures = 0
 
-	if cb.state == StateHalfOpen {
-		cb.state = StateClosed
+	if cb.state == StateHalfOpen && cb.successes >= cb.maxProbes {
+		cb.setState(ctx, StateClosed)
 		cb.lastFailure = time.Time{}
 	}
 }
@@ -281,3 +281,230 @@ func TestCircuitBreaker_FullCycle(t *testing.T) {
 		t.Fatalf("phase 4: State() = %q, want %q", cb.State(), StateClosed)
 	}
 }
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	var transitions []string
+	cb := NewCircuitBreaker(1, 10*time.Millisecond, WithOnStateChange(func(from, to State) {
+		transitions = append(transitions, string(from)+"->"+string(to))
+	}))
+
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	time.Sleep(20 * time.Millisecond)
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	})
+	cb.Trip()
+	cb.Reset()
+
+	want := []string{"closed->open", "open->half_open", "half_open->closed", "closed->open", "open->closed"}
+	if fmt.Sprint(transitions) != fmt.Sprint(want) {
+		t.Errorf("transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestCircuitBreaker_Stats(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Hour, WithCircuitBreakerName("llm"))
+
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	})
+	for range 2 {
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return nil, fmt.Errorf("fail")
+		})
+	}
+	for range 3 {
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return "unused", nil
+		})
+	}
+
+	want := CircuitBreakerStats{State: StateOpen, Successes: 1, Failures: 2, Rejections: 3, ConsecutiveFailures: 2}
+	if got := cb.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenMaxProbes(t *testing.T) {
+	cb := NewCircuitBreaker(1, 10*time.Millisecond, WithHalfOpenMaxProbes(2))
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	// The first probe is still running: a second is admitted, a third is not.
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			<-release
+			return "ok", nil
+		})
+		done <- err
+	}()
+	for cb.probesAdmitted() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	}); err != nil {
+		t.Fatalf("second probe error = %v", err)
+	}
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("State() = %q, want %q until every probe succeeds", cb.State(), StateHalfOpen)
+	}
+	if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	}); err != ErrCircuitOpen {
+		t.Errorf("third call error = %v, want ErrCircuitOpen", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("first probe error = %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("State() = %q, want %q after all probes succeed", cb.State(), StateClosed)
+	}
+}
+
+// probesAdmitted returns the probe calls admitted in the current half-open
+// state.
+func (cb *CircuitBreaker) probesAdmitted() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.probes
+}
+
+func TestCircuitBreaker_HalfOpenRejectsBeyondSingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker(1, 10*time.Millisecond)
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	_, _ = cb.Execute(context.Background(), func(ctx context.Context) (any, error) {
+		// A concurrent call while the probe runs is rejected.
+		_, err := cb.Execute(ctx, func(_ context.Context) (any, error) {
+			return "unused", nil
+		})
+		if err != ErrCircuitOpen {
+			t.Errorf("concurrent call error = %v, want ErrCircuitOpen", err)
+		}
+		return "ok", nil
+	})
+	if cb.State() != StateClosed {
+		t.Errorf("State() = %q, want %q", cb.State(), StateClosed)
+	}
+}
+
+func TestCircuitBreaker_Ignore(t *testing.T) {
+	canceled := fmt.Errorf("caller gave up")
+
+	t.Run("closed state keeps its failure count", func(t *testing.T) {
+		cb := NewCircuitBreaker(2, time.Hour)
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return nil, fmt.Errorf("fail")
+		})
+		_, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return nil, Ignore(canceled)
+		})
+		if err != canceled {
+			t.Errorf("err = %v, want the unwrapped error", err)
+		}
+		st := cb.Stats()
+		if st.ConsecutiveFailures != 1 || st.Successes != 0 || st.Failures != 1 {
+			t.Errorf("stats = %+v, want the ignored call uncounted", st)
+		}
+	})
+
+	t.Run("half-open probe is released", func(t *testing.T) {
+		cb := NewCircuitBreaker(1, 10*time.Millisecond)
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return nil, fmt.Errorf("fail")
+		})
+		time.Sleep(20 * time.Millisecond)
+
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return nil, Ignore(canceled)
+		})
+		if cb.State() != StateHalfOpen {
+			t.Fatalf("State() = %q, want %q after an ignored probe", cb.State(), StateHalfOpen)
+		}
+		if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			return "ok", nil
+		}); err != nil {
+			t.Fatalf("next probe error = %v, want it admitted", err)
+		}
+		if cb.State() != StateClosed {
+			t.Errorf("State() = %q, want %q", cb.State(), StateClosed)
+		}
+	})
+}
+
+func TestCircuitBreaker_ProbePanicReleasesSlot(t *testing.T) {
+	cb := NewCircuitBreaker(1, 10*time.Millisecond)
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected the probe's panic to propagate")
+			}
+		}()
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			panic("boom")
+		})
+	}()
+
+	if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	}); err != nil {
+		t.Fatalf("next probe error = %v, want it admitted", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("State() = %q, want %q", cb.State(), StateClosed)
+	}
+}
+
+func TestCircuitBreaker_StaleProbeExpires(t *testing.T) {
+	cb := NewCircuitBreaker(1, 20*time.Millisecond)
+	_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return nil, fmt.Errorf("fail")
+	})
+	time.Sleep(30 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+			close(started)
+			<-release
+			return nil, Ignore(context.Canceled)
+		})
+	}()
+	<-started
+	defer close(release)
+
+	if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	}); err != ErrCircuitOpen {
+		t.Fatalf("err = %v, want ErrCircuitOpen while the probe is in flight", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cb.Execute(context.Background(), func(_ context.Context) (any, error) {
+		return "ok", nil
+	}); err != nil {
+		t.Fatalf("err = %v, want a new probe admitted once the old one is stale", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("State() = %q, want %q", cb.State(), StateClosed)
+	}
+}
@@ -1,6 +1,7 @@
 // Package resilience provides fault-tolerance primitives for the Beluga AI
-// framework: retry with exponential backoff, circuit breakers, hedged requests,
-// and provider-aware rate limiting.
+// framework: retry with exponential backoff, deadline budgets, circuit
+// breakers, bulkheads, hedged requests, fallback chains, and provider-aware
+// and adaptive rate limiting.
 //
 // # Retry
 //
@@ -17,6 +18,21 @@
 // backoff multiplier, jitter, and optionally restricts retries to specific
 // error codes.
 //
+// # Deadline Budgets
+//
+// WithBudget sets an overall deadline for an operation, including all of its
+// retries. Retry consults the budget between attempts and stops with
+// ErrBudgetExhausted, wrapping the last error, as soon as the remaining time
+// cannot fit another backoff and attempt, rather than sleeping past the
+// caller's deadline:
+//
+//	ctx, budget, cancel := resilience.WithBudget(ctx, 2*time.Second)
+//	defer cancel()
+//	result, err := resilience.Retry(ctx, policy, callExternalAPI)
+//	if errors.Is(err, resilience.ErrBudgetExhausted) {
+//	    // gave up early; budget.Remaining() is what was left
+//	}
+//
 // # Circuit Breaker
 //
 // CircuitBreaker implements the circuit-breaker stability pattern. It wraps
@@ -27,12 +43,23 @@
 //
 //   - closed → open: after failureThreshold consecutive failures
 //   - open → half-open: after resetTimeout elapses
-//   - half-open → closed: on a successful probe call
+//   - half-open → closed: once the probe calls (one by default, see
+//     WithHalfOpenMaxProbes) succeed
 //   - half-open → open: on a failed probe call
 //
+// WithOnStateChange reports transitions, for example to alert when a
+// dependency trips, and Stats returns the state with success, failure and
+// rejection counters. Outcomes and transitions are also recorded as o11y
+// metrics tagged with the name given by WithCircuitBreakerName.
+//
 // Usage:
 //
-//	cb := resilience.NewCircuitBreaker(5, 30*time.Second)
+//	cb := resilience.NewCircuitBreaker(5, 30*time.Second,
+//	    resilience.WithCircuitBreakerName("openai"),
+//	    resilience.WithOnStateChange(func(from, to resilience.State) {
+//	        slog.Warn("circuit breaker state change", "from", from, "to", to)
+//	    }),
+//	)
 //	result, err := cb.Execute(ctx, func(ctx context.Context) (any, error) {
 //	    return callService(ctx)
 //	})
@@ -40,6 +67,19 @@
 //	    // circuit is open, handle gracefully
 //	}
 //
+// # Bulkhead
+//
+// Bulkhead limits the concurrent calls to one dependency and queues a bounded
+// number of extra calls; beyond that, calls fail fast with ErrBulkheadFull.
+// Give each dependency its own bulkhead so that one slow dependency cannot
+// starve the others, and wrap its CircuitBreaker inside it:
+//
+//	bh := resilience.NewBulkhead(20, 50)
+//	result, err := bh.Execute(ctx, func(ctx context.Context) (any, error) {
+//	    return cb.Execute(ctx, callService)
+//	})
+//	m := bh.Metrics() // Active, Queued, Rejected
+//
 // # Hedged Requests
 //
 // Hedge executes a primary function immediately. If it does not return within
@@ -49,6 +89,16 @@
 //
 //	result, err := resilience.Hedge(ctx, primaryFn, fallbackFn, 100*time.Millisecond)
 //
+// # Fallback Chains
+//
+// Fallback tries alternatives one after another until one succeeds. It only
+// moves on when ShouldFallback says the dependency failed (retryable errors,
+// an open circuit, a full bulkhead, an exhausted budget); errors such as
+// invalid input are returned at once. FallbackSteps takes a predicate per
+// step instead:
+//
+//	resp, err := resilience.Fallback(ctx, callPrimary, callSecondary, readCache)
+//
 // # Rate Limiting
 //
 // RateLimiter enforces provider-specific rate limits using token-bucket
@@ -65,4 +115,40 @@
 //	    // rate limited or context cancelled
 //	}
 //	defer rl.Release()
+//
+// For LLM calls, AllowN reserves an estimate of the request's tokens from the
+// TPM bucket before the call, and the reservation's Reconcile trues it up
+// with the actual usage afterwards, returning unused capacity:
+//
+//	res, err := rl.AllowN(ctx, estimateTokens(prompt))
+//	if err != nil {
+//	    return err
+//	}
+//	defer rl.Release()
+//	resp, err := model.Generate(ctx, prompt)
+//	res.Reconcile(resp.Usage.TotalTokens) // or 0 if the call failed
+//
+// AllowN takes the RPM token, the concurrency slot and the TPM reservation
+// together once all three are available, so a request waiting for token
+// capacity holds neither an RPM token nor a slot. Each request counts once
+// against RPM whatever its size; only TPM depends on the estimate.
+//
+// # Adaptive Concurrency
+//
+// AdaptiveLimiter replaces a fixed MaxConcurrent with a limit that follows
+// the dependency's health. AlgorithmAIMD grows the limit slowly while
+// requests succeed and cuts it on overload errors or latency above a
+// threshold; AlgorithmGradient shrinks it as latency rises above its
+// long-term baseline. Report each request's outcome with Release:
+//
+//	al := resilience.NewAdaptiveLimiter(resilience.AdaptiveConfig{
+//	    Algorithm: resilience.AlgorithmGradient,
+//	    MaxLimit:  100,
+//	})
+//	if err := al.Allow(ctx); err != nil {
+//	    return err
+//	}
+//	start := time.Now()
+//	resp, err := callProvider(ctx)
+//	al.Release(time.Since(start), err)
 package resilience
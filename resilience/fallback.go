@@ -0,0 +1,78 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// FallbackStep is one alternative in a fallback chain.
+type FallbackStep[T any] struct {
+	// Fn produces the result.
+	Fn func(ctx context.Context) (T, error)
+
+	// ShouldAdvance decides whether an error from Fn moves on to the next
+	// step. If it returns false the error is returned immediately. Nil means
+	// ShouldFallback.
+	ShouldAdvance func(err error) bool
+}
+
+// ShouldFallback reports whether err indicates that the dependency failed
+// rather than the request, so that an alternative may succeed: retryable
+// errors (rate limits, timeouts, unavailable providers), an open circuit
+// breaker, a full bulkhead, an exhausted deadline budget, or a deadline
+// that expired inside the step. Other errors, such as invalid input, would
+// fail the same way on every alternative.
+func ShouldFallback(err error) bool {
+	return core.IsRetryable(err) ||
+		errors.Is(err, ErrCircuitOpen) ||
+		errors.Is(err, ErrBulkheadFull) ||
+		errors.Is(err, ErrBudgetExhausted) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// Fallback invokes fns in order until one succeeds, such as a primary
+// provider, then a cheaper secondary, then a cached response. It advances to
+// the next function only when ShouldFallback classifies the error as a
+// failure of the dependency; any other error is returned immediately. If
+// every function fails, the last error is returned. Unlike Hedge, the
+// functions run one at a time.
+func Fallback[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	steps := make([]FallbackStep[T], len(fns))
+	for i, fn := range fns {
+		steps[i] = FallbackStep[T]{Fn: fn}
+	}
+	return FallbackSteps(ctx, steps...)
+}
+
+// FallbackSteps is like Fallback but lets each step decide which of its
+// errors advance to the next step. If ctx is cancelled between steps, the
+// context error is returned.
+func FallbackSteps[T any](ctx context.Context, steps ...FallbackStep[T]) (T, error) {
+	var zero T
+	if len(steps) == 0 {
+		return zero, core.Errorf(core.ErrInvalidInput, "resilience: fallback requires at least one step")
+	}
+
+	var lastErr error
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		result, err := step.Fn(ctx)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		advance := step.ShouldAdvance
+		if advance == nil {
+			advance = ShouldFallback
+		}
+		if !advance(err) {
+			break
+		}
+	}
+	return zero, lastErr
+}
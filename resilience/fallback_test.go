@@ -0,0 +1,148 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func TestFallback_FirstSuccessWins(t *testing.T) {
+	var calls []string
+	step := func(name string, err error) func(context.Context) (string, error) {
+		return func(_ context.Context) (string, error) {
+			calls = append(calls, name)
+			if err != nil {
+				return "", err
+			}
+			return name, nil
+		}
+	}
+
+	result, err := Fallback(context.Background(),
+		step("primary", core.NewError("op", core.ErrProviderDown, "down", nil)),
+		step("secondary", ErrCircuitOpen),
+		step("cache", nil),
+		step("unused", nil),
+	)
+	if err != nil {
+		t.Fatalf("Fallback() error = %v", err)
+	}
+	if result != "cache" {
+		t.Errorf("result = %q, want %q", result, "cache")
+	}
+	if len(calls) != 3 {
+		t.Errorf("calls = %v, want primary, secondary, cache", calls)
+	}
+}
+
+func TestFallback_StopsOnNonRecoverableError(t *testing.T) {
+	invalid := core.NewError("op", core.ErrInvalidInput, "bad prompt", nil)
+	called := false
+	_, err := Fallback(context.Background(),
+		func(_ context.Context) (string, error) { return "", invalid },
+		func(_ context.Context) (string, error) {
+			called = true
+			return "ok", nil
+		},
+	)
+	if !errors.Is(err, invalid) {
+		t.Errorf("Fallback() error = %v, want the invalid input error", err)
+	}
+	if called {
+		t.Error("fallback should not run after a non-recoverable error")
+	}
+}
+
+func TestFallback_ReturnsLastError(t *testing.T) {
+	first := core.NewError("op", core.ErrRateLimit, "throttled", nil)
+	last := core.NewError("op", core.ErrTimeout, "slow", nil)
+	_, err := Fallback(context.Background(),
+		func(_ context.Context) (int, error) { return 0, first },
+		func(_ context.Context) (int, error) { return 0, last },
+	)
+	if !errors.Is(err, last) {
+		t.Errorf("Fallback() error = %v, want the last error", err)
+	}
+}
+
+func TestFallback_NoSteps(t *testing.T) {
+	if _, err := Fallback[string](context.Background()); err == nil {
+		t.Error("expected error for an empty chain")
+	}
+}
+
+func TestFallback_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := false
+	_, err := Fallback(ctx,
+		func(_ context.Context) (string, error) {
+			cancel()
+			return "", core.NewError("op", core.ErrProviderDown, "down", nil)
+		},
+		func(_ context.Context) (string, error) {
+			called = true
+			return "ok", nil
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Fallback() error = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Error("fallback should not run after the context is cancelled")
+	}
+}
+
+func TestFallbackSteps_Predicate(t *testing.T) {
+	errNotCached := errors.New("not cached")
+	result, err := FallbackSteps(context.Background(),
+		FallbackStep[string]{
+			Fn: func(_ context.Context) (string, error) { return "", errNotCached },
+			ShouldAdvance: func(err error) bool {
+				return errors.Is(err, errNotCached)
+			},
+		},
+		FallbackStep[string]{
+			Fn: func(_ context.Context) (string, error) { return "live", nil },
+		},
+	)
+	if err != nil || result != "live" {
+		t.Errorf("FallbackSteps() = %q, %v, want live", result, err)
+	}
+
+	_, err = FallbackSteps(context.Background(),
+		FallbackStep[string]{
+			Fn:            func(_ context.Context) (string, error) { return "", ErrBulkheadFull },
+			ShouldAdvance: func(error) bool { return false },
+		},
+		FallbackStep[string]{
+			Fn: func(_ context.Context) (string, error) { return "unused", nil },
+		},
+	)
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("FallbackSteps() error = %v, want ErrBulkheadFull", err)
+	}
+}
+
+func TestShouldFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limit", core.NewError("op", core.ErrRateLimit, "", nil), true},
+		{"circuit open", ErrCircuitOpen, true},
+		{"bulkhead full", ErrBulkheadFull, true},
+		{"budget exhausted", ErrBudgetExhausted, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"invalid input", core.NewError("op", core.ErrInvalidInput, "", nil), false},
+		{"auth", core.NewError("op", core.ErrAuth, "", nil), false},
+		{"cancelled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := ShouldFallback(tt.err); got != tt.want {
+			t.Errorf("ShouldFallback(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
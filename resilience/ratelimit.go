@@ -29,7 +29,7 @@ type ProviderLimits struct {
 type RateLimiter struct {
 	limits ProviderLimits
 
-	mu            sync.Mutex
+	mu sync.Mutex
 	// RPM token bucket state.
 	rpmTokens     float64
 	rpmLastRefill time.Time
@@ -62,13 +62,38 @@ func NewRateLimiter(limits ProviderLimits) *RateLimiter {
 // Allow blocks until the rate limiter permits a new request, or the context
 // is cancelled. It reserves 1 RPM token and checks the concurrency limit.
 func (rl *RateLimiter) Allow(ctx context.Context) error {
+	return rl.allow(ctx, 0)
+}
+
+// AllowN is like Allow but also reserves estimatedTokens from the TPM bucket
+// before the request is made, so that concurrent requests cannot together
+// exceed the limit. It waits until the RPM token, the concurrency slot and
+// the TPM capacity are all available and takes them at once, so a request
+// never holds a slot while waiting for tokens. An estimate larger than TPM
+// waits for a full bucket and leaves it in debt, delaying later requests.
+//
+// Call Reconcile on the returned reservation with the actual token count
+// once it is known, and Release as with Allow. Do not also call
+// ConsumeTokens for the request.
+func (rl *RateLimiter) AllowN(ctx context.Context, estimatedTokens int) (*TokenReservation, error) {
+	if rl.limits.TPM <= 0 || estimatedTokens < 0 {
+		estimatedTokens = 0
+	}
+	if err := rl.allow(ctx, estimatedTokens); err != nil {
+		return nil, err
+	}
+	return &TokenReservation{rl: rl, reserved: estimatedTokens}, nil
+}
+
+// allow blocks until a request reserving tokens TPM tokens is permitted.
+func (rl *RateLimiter) allow(ctx context.Context, tokens int) error {
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
 		rl.mu.Lock()
-		ok, wait := rl.tryAcquire()
+		ok, wait := rl.tryAcquire(tokens)
 		rl.mu.Unlock()
 
 		if ok {
@@ -88,6 +113,37 @@ func (rl *RateLimiter) Allow(ctx context.Context) error {
 	}
 }
 
+// TokenReservation is TPM capacity reserved by AllowN for one request.
+type TokenReservation struct {
+	rl       *RateLimiter
+	reserved int
+
+	once sync.Once
+}
+
+// Reserved returns the number of TPM tokens reserved.
+func (r *TokenReservation) Reserved() int { return r.reserved }
+
+// Reconcile trues up the reservation with the tokens the request actually
+// consumed: unused capacity is returned to the TPM bucket and any excess is
+// deducted from it. Pass 0 for a request that failed without consuming
+// tokens. Only the first call has an effect.
+func (r *TokenReservation) Reconcile(actualTokens int) {
+	r.once.Do(func() {
+		rl := r.rl
+		if rl.limits.TPM <= 0 {
+			return
+		}
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+		rl.refillTPM()
+		rl.tpmTokens += float64(r.reserved - max(actualTokens, 0))
+		if rl.tpmTokens > float64(rl.limits.TPM) {
+			rl.tpmTokens = float64(rl.limits.TPM)
+		}
+	})
+}
+
 // Release signals that an in-flight request has completed, freeing a
 // concurrency slot.
 func (rl *RateLimiter) Release() {
@@ -114,7 +170,9 @@ func (rl *RateLimiter) Wait(ctx context.Context) error {
 
 // ConsumeTokens deducts count tokens from the TPM bucket. Call this after
 // learning how many tokens a request consumed. It blocks until enough tokens
-// are available or the context is cancelled.
+// are available or the context is cancelled. Because tokens are only
+// accounted for after the fact, concurrent requests can overshoot TPM; use
+// AllowN and Reconcile to reserve them up front instead.
 func (rl *RateLimiter) ConsumeTokens(ctx context.Context, count int) error {
 	if rl.limits.TPM <= 0 || count <= 0 {
 		return nil
@@ -149,10 +207,11 @@ func (rl *RateLimiter) ConsumeTokens(ctx context.Context, count int) error {
 	}
 }
 
-// tryAcquire attempts to take 1 RPM token and 1 concurrency slot. Returns
-// (true, 0) on success, or (false, suggestedWait) when the caller should
-// back off. Caller must hold rl.mu.
-func (rl *RateLimiter) tryAcquire() (ok bool, wait time.Duration) {
+// tryAcquire attempts to take 1 RPM token, 1 concurrency slot and tokens TPM
+// tokens. Nothing is taken unless all are available. Returns (true, 0) on
+// success, or (false, suggestedWait) when the caller should back off.
+// Caller must hold rl.mu.
+func (rl *RateLimiter) tryAcquire(tokens int) (ok bool, wait time.Duration) {
 	// Check concurrency.
 	if rl.limits.MaxConcurrent > 0 && rl.concurrent >= rl.limits.MaxConcurrent {
 		return false, 10 * time.Millisecond
@@ -167,9 +226,23 @@ func (rl *RateLimiter) tryAcquire() (ok bool, wait time.Duration) {
 			deficit := 1.0 - rl.rpmTokens
 			return false, time.Duration(deficit/rate*1e9) * time.Nanosecond
 		}
-		rl.rpmTokens--
 	}
 
+	// Check TPM. An estimate above the limit only needs a full bucket.
+	if tokens > 0 {
+		rl.refillTPM()
+		need := float64(min(tokens, rl.limits.TPM))
+		if rl.tpmTokens < need {
+			rate := float64(rl.limits.TPM) / 60.0 // tokens per second
+			deficit := need - rl.tpmTokens
+			return false, time.Duration(deficit/rate*1e9) * time.Nanosecond
+		}
+		rl.tpmTokens -= float64(tokens)
+	}
+
+	if rl.limits.RPM > 0 {
+		rl.rpmTokens--
+	}
 	rl.concurrent++
 	return true, 0
 }
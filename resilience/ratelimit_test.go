@@ -272,3 +272,78 @@ func TestRateLimiter_RPM_RefillsOverTime(t *testing.T) {
 		t.Errorf("Allow() after refill error = %v", err)
 	}
 }
+
+func TestRateLimiter_AllowN_ReservesTokens(t *testing.T) {
+	rl := NewRateLimiter(ProviderLimits{TPM: 1000})
+	res, err := rl.AllowN(context.Background(), 800)
+	if err != nil {
+		t.Fatalf("AllowN() error = %v", err)
+	}
+	if res.Reserved() != 800 {
+		t.Errorf("Reserved() = %d, want 800", res.Reserved())
+	}
+	if rl.tpmTokens > 201 {
+		t.Errorf("tpmTokens = %f, want about 200 after the reservation", rl.tpmTokens)
+	}
+
+	// A second large request must wait for capacity.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := rl.AllowN(ctx, 800); err == nil {
+		t.Error("AllowN() should block while the capacity is reserved")
+	}
+	if rl.concurrent != 1 {
+		t.Errorf("concurrent = %d, want 1: a waiting request must not hold a slot", rl.concurrent)
+	}
+}
+
+func TestRateLimiter_Reconcile(t *testing.T) {
+	rl := NewRateLimiter(ProviderLimits{TPM: 1000})
+
+	res, err := rl.AllowN(context.Background(), 800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Reconcile(300)
+	res.Reconcile(0) // only the first call counts
+	if rl.tpmTokens < 699 || rl.tpmTokens > 701 {
+		t.Errorf("tpmTokens = %f, want about 700 after returning the unused estimate", rl.tpmTokens)
+	}
+
+	res, err = rl.AllowN(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Reconcile(500)
+	if rl.tpmTokens < 199 || rl.tpmTokens > 201 {
+		t.Errorf("tpmTokens = %f, want about 200 after deducting the excess", rl.tpmTokens)
+	}
+}
+
+func TestRateLimiter_AllowN_EstimateAboveTPM(t *testing.T) {
+	rl := NewRateLimiter(ProviderLimits{TPM: 100})
+	res, err := rl.AllowN(context.Background(), 150)
+	if err != nil {
+		t.Fatalf("AllowN() error = %v, want an estimate above TPM to pass with a full bucket", err)
+	}
+	if rl.tpmTokens > -49 {
+		t.Errorf("tpmTokens = %f, want a debt of about 50", rl.tpmTokens)
+	}
+	res.Reconcile(150)
+}
+
+func TestRateLimiter_AllowN_NoTPM(t *testing.T) {
+	rl := NewRateLimiter(ProviderLimits{RPM: 60, MaxConcurrent: 1})
+	res, err := rl.AllowN(context.Background(), 1_000_000)
+	if err != nil {
+		t.Fatalf("AllowN() error = %v", err)
+	}
+	if res.Reserved() != 0 {
+		t.Errorf("Reserved() = %d, want 0 with unlimited TPM", res.Reserved())
+	}
+	res.Reconcile(10)
+	if rl.rpmTokens > 59.1 || rl.concurrent != 1 {
+		t.Errorf("AllowN() should take an RPM token and a slot, got rpmTokens=%f concurrent=%d", rl.rpmTokens, rl.concurrent)
+	}
+	rl.Release()
+}
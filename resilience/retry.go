@@ -3,6 +3,7 @@ package resilience
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/rand/v2"
 	"time"
@@ -50,19 +51,28 @@ func DefaultRetryPolicy() RetryPolicy {
 // it waits with exponential backoff (optionally jittered) before retrying. If
 // the context is cancelled the function returns immediately with the context
 // error.
+//
+// If ctx carries a Budget (see WithBudget), Retry only waits and retries when
+// the remaining budget fits the backoff plus another attempt, assuming the
+// next attempt takes as long as the slowest one so far. Otherwise it returns
+// the last error wrapped with ErrBudgetExhausted.
 func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
 	policy = normalizePolicy(policy)
 
 	retryableSet := buildRetryableSet(policy.RetryableErrors)
+	budget := BudgetFromContext(ctx)
 
 	var lastErr error
+	var slowest time.Duration
 	backoff := policy.InitialBackoff
 
 	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		start := time.Now()
 		result, err := fn(ctx)
 		if err == nil {
 			return result, nil
 		}
+		slowest = max(slowest, time.Since(start))
 
 		lastErr = err
 
@@ -81,6 +91,11 @@ func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.C
 			delay = jitter(delay)
 		}
 
+		if budget != nil && !budget.Fits(delay+slowest) {
+			var zero T
+			return zero, fmt.Errorf("%w: %w", ErrBudgetExhausted, lastErr)
+		}
+
 		select {
 		case <-ctx.Done():
 			var zero T
@@ -18,6 +18,9 @@ const (
 	// models that expose their chain-of-thought (e.g. OpenAI o-series,
 	// Claude with extended thinking).
 	ContentThinking ContentType = "thinking"
+	// ContentDocument represents a text document supplied as a source the
+	// model may cite.
+	ContentDocument ContentType = "document"
 )
 
 // ContentPart is the interface implemented by all multimodal content types.
@@ -96,6 +99,7 @@ var _ ContentPart = AudioPart{}
 var _ ContentPart = VideoPart{}
 var _ ContentPart = FilePart{}
 var _ ContentPart = ThinkingPart{}
+var _ ContentPart = DocumentPart{}
 
 // ThinkingPart holds reasoning/chain-of-thought content from models that
 // expose their internal reasoning process (e.g. OpenAI o-series, Claude
@@ -111,3 +115,20 @@ type ThinkingPart struct {
 
 // PartType returns ContentThinking.
 func (t ThinkingPart) PartType() ContentType { return ContentThinking }
+
+// DocumentPart holds a text document that the model should ground its
+// answer in. Providers that support citations, such as Anthropic and
+// Google, attribute the answer to it in AIMessage.Citations, referring to it
+// by its index among the DocumentParts of the request.
+type DocumentPart struct {
+	// Title is an optional title of the document, reported in citations.
+	Title string
+	// Text is the content of the document.
+	Text string
+	// Context is optional information about the document that the model
+	// may use but not cite, such as its source or date.
+	Context string
+}
+
+// PartType returns ContentDocument.
+func (d DocumentPart) PartType() ContentType { return ContentDocument }
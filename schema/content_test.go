@@ -182,3 +182,10 @@ func TestImagePart_EmptyFields(t *testing.T) {
 		t.Errorf("PartType() = %q, want %q", p.PartType(), ContentImage)
 	}
 }
+
+func TestDocumentPart_PartType(t *testing.T) {
+	p := DocumentPart{Title: "Handbook", Text: "Employees get 25 days of leave."}
+	if got := p.PartType(); got != ContentDocument {
+		t.Errorf("PartType() = %q, want %q", got, ContentDocument)
+	}
+}
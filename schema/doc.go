@@ -33,6 +33,7 @@
 //   - [AudioPart] — audio data with format and sample rate
 //   - [VideoPart] — video data (inline bytes or URL) with MIME type
 //   - [FilePart] — generic file attachments with name and MIME type
+//   - [DocumentPart] — a titled text document for the model to ground its answer in
 //
 // # Tool Types
 //
@@ -47,6 +48,11 @@
 // pipelines, carrying text content, metadata for filtering, optional
 // relevance scores from retrieval, and optional embedding vectors.
 //
+// To have the model cite retrieved documents, send them as [DocumentPart]
+// values. Providers that support citations report which spans of the answer
+// each source supports in AIMessage.Citations, as [Citation] values that
+// refer to documents by their index among the request's DocumentParts.
+//
 // # Streaming Events
 //
 // [StreamChunk] represents incremental pieces of a streaming model response
@@ -63,5 +69,7 @@
 // # Token Usage
 //
 // [Usage] tracks token consumption for model responses, including input
-// tokens, output tokens, total tokens, and cached tokens.
+// tokens, output tokens, total tokens, and cached tokens. When log
+// probabilities are requested, AIMessage.LogProbs and StreamChunk.LogProbs
+// hold a [TokenLogProb] per generated token.
 package schema
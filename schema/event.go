@@ -18,6 +18,9 @@ type StreamChunk struct {
 	// ReasoningDelta is the incremental reasoning/thinking content in this chunk.
 	// Non-empty only for models that stream their chain-of-thought.
 	ReasoningDelta string
+	// LogProbs holds the log probabilities of the tokens in Delta, when they
+	// were requested and the provider returns them.
+	LogProbs []TokenLogProb
 	// ModelID identifies the model that produced this chunk.
 	ModelID string
 }
@@ -95,10 +95,56 @@ type AIMessage struct {
 	Usage Usage
 	// ModelID identifies the model that generated this message.
 	ModelID string
+	// Citations attributes spans of the response text to their sources,
+	// for providers that report them.
+	Citations []Citation
+	// LogProbs holds the log probability of each generated token, in order,
+	// when they were requested and the provider returns them.
+	LogProbs []TokenLogProb
 	// Metadata holds arbitrary key-value pairs associated with this message.
 	Metadata map[string]any
 }
 
+// Citation attributes a span of an AI response to a source: a DocumentPart
+// of the request, or a source the provider found itself, such as a web
+// page found by search grounding.
+type Citation struct {
+	// Start and End are the byte offsets of the cited span in the response
+	// text returned by AIMessage.Text. Both are zero when the provider does
+	// not locate the span.
+	Start, End int
+	// DocumentIndex is the index of the cited document among the
+	// DocumentParts of the request, in order, or -1 if the source is not a
+	// request document.
+	DocumentIndex int
+	// DocumentTitle is the title of the cited source, if known.
+	DocumentTitle string
+	// URI locates the cited source, for sources other than request
+	// documents.
+	URI string
+	// CitedText is the passage of the source that supports the span.
+	CitedText string
+	// DocumentStart and DocumentEnd are the character offsets of CitedText
+	// in the document. Both are zero when the provider does not report them.
+	DocumentStart, DocumentEnd int
+}
+
+// TokenLogProb is the log probability of one generated token.
+type TokenLogProb struct {
+	// Token is the text of the token.
+	Token string
+	// LogProb is the natural log of the token's probability.
+	LogProb float64
+	// Bytes is the UTF-8 encoding of the token. A character that spans
+	// several tokens is only valid once their Bytes are joined. Nil if the
+	// provider does not report it.
+	Bytes []byte
+	// TopLogProbs are the most likely tokens at this position, most likely
+	// first, when alternatives were requested. Their own TopLogProbs are
+	// empty.
+	TopLogProbs []TokenLogProb
+}
+
 // GetRole returns RoleAI.
 func (m *AIMessage) GetRole() Role { return RoleAI }
 
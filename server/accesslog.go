@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccessLogOption configures WithAccessLog.
+type AccessLogOption func(*accessLogConfig)
+
+type accessLogConfig struct {
+	maxBody int
+	redact  func(string) string
+}
+
+// WithBodyCapture logs the request and response bodies, each truncated to
+// maxBytes. Bodies are captured as they stream through, so large or
+// streaming bodies are never buffered beyond maxBytes. Capture is off by
+// default.
+func WithBodyCapture(maxBytes int) AccessLogOption {
+	return func(c *accessLogConfig) {
+		if maxBytes > 0 {
+			c.maxBody = maxBytes
+		}
+	}
+}
+
+// WithRedaction installs a hook applied to captured bodies before they are
+// logged, for example to strip API keys or personal data. It has no effect
+// without WithBodyCapture.
+func WithRedaction(fn func(body string) string) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.redact = fn
+	}
+}
+
+// WithAccessLog returns middleware that logs one entry per request to
+// logger once the response is complete, with the method, path, route,
+// status, duration, and response size. When the request context carries an
+// OTel span, its trace and span IDs are included so entries can be
+// correlated with traces. Responses with status 500 or above are logged at
+// error level, everything else at info level.
+//
+// Streams are logged when they end: SSE responses add the number of events
+// sent, and WebSocket upgrades log status 101 once the socket closes.
+//
+// Example:
+//
+//	s = server.ApplyMiddleware(s, server.WithAccessLog(logger,
+//	    server.WithBodyCapture(2048),
+//	    server.WithRedaction(redactSecrets),
+//	))
+func WithAccessLog(logger *o11y.Logger, opts ...AccessLogOption) Middleware {
+	var cfg accessLogConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return HandlerMiddleware(func(route string, next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &accessRecorder{ResponseWriter: w, status: http.StatusOK, body: newCapture(cfg.maxBody)}
+			var reqBody *capture
+			if cfg.maxBody > 0 && r.Body != nil {
+				reqBody = newCapture(cfg.maxBody)
+				r.Body = &teeBody{ReadCloser: r.Body, c: reqBody}
+			}
+
+			next.ServeHTTP(rec, r)
+
+			attrs := []any{
+				"method", r.Method,
+				"path", requestPath(r),
+				"route", route,
+				"status", rec.status,
+				"duration", time.Since(start),
+				"bytes", rec.written,
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				attrs = append(attrs, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+			}
+			if rec.events > 0 || strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+				attrs = append(attrs, "sse_events", rec.events)
+			}
+			if reqBody != nil {
+				attrs = append(attrs, reqBody.attrs("request_body", cfg.redact)...)
+			}
+			if cfg.maxBody > 0 {
+				attrs = append(attrs, rec.body.attrs("response_body", cfg.redact)...)
+			}
+
+			if rec.status >= http.StatusInternalServerError {
+				logger.Error(r.Context(), "http request", attrs...)
+				return
+			}
+			logger.Info(r.Context(), "http request", attrs...)
+		})
+	})
+}
+
+// requestPath returns the path the client requested. Agent routes see a
+// path with the registration prefix stripped, so the original is recovered
+// from the request URI when available.
+func requestPath(r *http.Request) string {
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return r.URL.Path
+}
+
+// accessRecorder records the status, size, body prefix, and SSE event count
+// of a response while passing everything through.
+type accessRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	written     int64
+	events      int
+	body        *capture
+}
+
+func (rec *accessRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *accessRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(p)
+	rec.written += int64(n)
+	rec.body.write(p[:n])
+	return n, err
+}
+
+// Flush lets SSE streams pass through the recorder.
+func (rec *accessRecorder) Flush() {
+	_ = http.NewResponseController(rec.ResponseWriter).Flush()
+}
+
+// Hijack lets WebSocket upgrades pass through the recorder.
+func (rec *accessRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rec.ResponseWriter).Hijack()
+	if err == nil {
+		rec.status = http.StatusSwitchingProtocols
+		rec.wroteHeader = true
+	}
+	return conn, brw, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (rec *accessRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *accessRecorder) observeSSEEvent() {
+	rec.events++
+}
+
+// capture keeps the first limit bytes written to it.
+type capture struct {
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func newCapture(limit int) *capture {
+	return &capture{limit: limit}
+}
+
+func (c *capture) write(p []byte) {
+	if c.limit <= 0 {
+		return
+	}
+	room := c.limit - len(c.buf)
+	if len(p) > room {
+		p = p[:room]
+		c.truncated = true
+	}
+	c.buf = append(c.buf, p...)
+}
+
+// attrs returns the log attributes for the captured body, redacted.
+func (c *capture) attrs(key string, redact func(string) string) []any {
+	body := string(c.buf)
+	if redact != nil {
+		body = redact(body)
+	}
+	attrs := []any{key, body}
+	if c.truncated {
+		attrs = append(attrs, key+"_truncated", true)
+	}
+	return attrs
+}
+
+// teeBody copies what the handler reads from a request body into a capture.
+type teeBody struct {
+	io.ReadCloser
+	c *capture
+}
+
+func (t *teeBody) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.c.write(p[:n])
+	return n, err
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+)
+
+// logSink collects JSON log records.
+type logSink struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *logSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *logSink) records(t *testing.T) []map[string]any {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(s.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// newAccessLogged returns an agent handler mounted at route and wrapped with
+// WithAccessLog, logging into the returned sink.
+func newAccessLogged(t *testing.T, route string, a agent.Agent, opts ...AccessLogOption) (http.Handler, *logSink) {
+	t.Helper()
+	sink := &logSink{}
+	logger := o11y.NewLogger(o11y.WithLogHandler(slog.NewJSONHandler(sink, nil)))
+	hs := WithAccessLog(logger, opts...)(&captureAdapter{}).(*handlerServer)
+	return hs.wrap(route, http.StripPrefix(route, NewAgentHandler(a, WithWebSocket()))), sink
+}
+
+func TestWithAccessLog_Invoke(t *testing.T) {
+	tests := []struct {
+		name      string
+		agent     *mockAgent
+		wantCode  float64
+		wantLevel string
+	}{
+		{"success", &mockAgent{id: "a", result: "hello"}, 200, "INFO"},
+		{"agent error", &mockAgent{id: "a", err: errors.New("boom")}, 500, "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, sink := newAccessLogged(t, "/chat", tt.agent)
+			req := httptest.NewRequest(http.MethodPost, "/chat/invoke", strings.NewReader(`{"input":"hi"}`))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			recs := sink.records(t)
+			if len(recs) != 1 {
+				t.Fatalf("got %d log records, want 1", len(recs))
+			}
+			rec := recs[0]
+			if rec["level"] != tt.wantLevel || rec["msg"] != "http request" {
+				t.Errorf("level/msg = %v/%v", rec["level"], rec["msg"])
+			}
+			if rec["method"] != "POST" || rec["path"] != "/chat/invoke" || rec["route"] != "/chat" {
+				t.Errorf("method/path/route = %v %v %v", rec["method"], rec["path"], rec["route"])
+			}
+			if rec["status"] != tt.wantCode {
+				t.Errorf("status = %v, want %v", rec["status"], tt.wantCode)
+			}
+			if rec["bytes"] != float64(w.Body.Len()) {
+				t.Errorf("bytes = %v, want %d", rec["bytes"], w.Body.Len())
+			}
+			if _, ok := rec["duration"]; !ok {
+				t.Error("missing duration")
+			}
+			for _, key := range []string{"request_body", "response_body", "sse_events", "trace_id"} {
+				if _, ok := rec[key]; ok {
+					t.Errorf("unexpected %s", key)
+				}
+			}
+		})
+	}
+}
+
+func TestWithAccessLog_BodyCapture(t *testing.T) {
+	a := &mockAgent{id: "a", result: strings.Repeat("x", 100)}
+	h, sink := newAccessLogged(t, "/chat", a,
+		WithBodyCapture(40),
+		WithRedaction(func(body string) string {
+			return strings.ReplaceAll(body, "sk-secret", "[REDACTED]")
+		}),
+	)
+	req := httptest.NewRequest(http.MethodPost, "/chat/invoke", strings.NewReader(`{"input":"use sk-secret"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), strings.Repeat("x", 100)) {
+		t.Fatalf("response altered: %s", w.Body.String())
+	}
+	rec := sink.records(t)[0]
+	if rec["request_body"] != `{"input":"use [REDACTED]"}` {
+		t.Errorf("request_body = %v", rec["request_body"])
+	}
+	if _, ok := rec["request_body_truncated"]; ok {
+		t.Error("request body should not be truncated")
+	}
+	if body, _ := rec["response_body"].(string); len(body) != 40 || !strings.HasPrefix(body, `{"result":"xxx`) {
+		t.Errorf("response_body = %q", body)
+	}
+	if rec["response_body_truncated"] != true {
+		t.Error("expected response_body_truncated")
+	}
+}
+
+func TestWithAccessLog_SSE(t *testing.T) {
+	a := &mockAgent{id: "a", events: []agent.Event{
+		{Type: agent.EventText, Text: "one"},
+		{Type: agent.EventText, Text: "two"},
+	}}
+	h, sink := newAccessLogged(t, "/chat", a)
+	req := httptest.NewRequest(http.MethodPost, "/chat/stream", strings.NewReader(`{"input":"hi"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if !w.Flushed || !strings.Contains(w.Body.String(), "event: done") {
+		t.Fatalf("stream broken: flushed=%v body=%s", w.Flushed, w.Body.String())
+	}
+	rec := sink.records(t)[0]
+	if rec["sse_events"] != float64(3) {
+		t.Errorf("sse_events = %v, want 3", rec["sse_events"])
+	}
+	if rec["status"] != float64(200) {
+		t.Errorf("status = %v", rec["status"])
+	}
+}
+
+func TestWithAccessLog_TraceIDs(t *testing.T) {
+	h, sink := newAccessLogged(t, "/chat", &mockAgent{id: "a", result: "ok"})
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/chat/invoke", strings.NewReader(`{"input":"hi"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec := sink.records(t)[0]
+	if rec["trace_id"] != sc.TraceID().String() || rec["span_id"] != sc.SpanID().String() {
+		t.Errorf("trace_id/span_id = %v/%v", rec["trace_id"], rec["span_id"])
+	}
+}
+
+func TestWithAccessLog_WebSocket(t *testing.T) {
+	h, sink := newAccessLogged(t, "/chat", &mockAgent{id: "a", result: "ok"})
+	mux := http.NewServeMux()
+	mux.Handle("/chat/", h)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ws, err := httpclient.DialWS(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/chat/ws", nil)
+	if err != nil {
+		t.Fatalf("DialWS: %v", err)
+	}
+	if err := ws.WriteJSON(ctx, InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	readUntilTerminal(t, ws)
+	if len(sink.records(t)) != 0 {
+		t.Error("socket should be logged when it closes, not before")
+	}
+	_ = ws.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.records(t)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	recs := sink.records(t)
+	if len(recs) != 1 {
+		t.Fatalf("got %d log records, want 1", len(recs))
+	}
+	if recs[0]["status"] != float64(http.StatusSwitchingProtocols) || recs[0]["path"] != "/chat/ws" {
+		t.Errorf("status/path = %v/%v", recs[0]["status"], recs[0]["path"])
+	}
+}
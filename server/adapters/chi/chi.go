@@ -3,7 +3,9 @@ package chi
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 
@@ -17,16 +19,23 @@ type Adapter struct {
 	router chi.Router
 	lc     httputil.ServerLifecycle
 	cfg    server.Config
+
+	mu     sync.RWMutex
+	agents map[string]agent.Agent
 }
 
-// Compile-time interface check.
-var _ server.ServerAdapter = (*Adapter)(nil)
+// Compile-time interface checks.
+var (
+	_ server.ServerAdapter = (*Adapter)(nil)
+	_ server.AgentLister   = (*Adapter)(nil)
+)
 
 // New creates a new Chi adapter with the given configuration.
 func New(cfg server.Config) *Adapter {
 	return &Adapter{
 		router: chi.NewRouter(),
 		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
@@ -39,9 +48,20 @@ func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	handler := server.NewAgentHandler(ag)
 	stripped := http.StripPrefix(path, handler)
 	a.router.Handle(path+"/*", stripped)
+
+	a.mu.Lock()
+	a.agents[path] = ag
+	a.mu.Unlock()
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (a *Adapter) Agents() map[string]agent.Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return maps.Clone(a.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (a *Adapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -85,6 +86,26 @@ func TestAdapter_RegisterAgent(t *testing.T) {
 	})
 }
 
+func TestAdapter_Agents(t *testing.T) {
+	a := New(server.Config{})
+	ag := &mockAgent{id: "test", result: "hello"}
+	if err := a.RegisterAgent("/api/agent", ag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agents := a.Agents()
+	if len(agents) != 1 || agents["/api/agent"] != ag {
+		t.Fatalf("Agents() = %v, want the registered agent", agents)
+	}
+
+	doc, err := server.GenerateOpenAPI(a)
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+	if !bytes.Contains(doc, []byte(`"/api/agent/invoke"`)) {
+		t.Errorf("OpenAPI document does not describe /api/agent/invoke: %s", doc)
+	}
+}
+
 func TestAdapter_RegisterHandler(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := New(server.Config{})
@@ -294,3 +315,50 @@ func TestAdapter_Shutdown_Error(t *testing.T) {
 	cancel()
 	<-errCh
 }
+
+func TestAdapter_StreamResumption(t *testing.T) {
+	a := New(server.Config{})
+	s := server.ApplyMiddleware(a, server.WithAgentHandlerOptions(server.WithStreamResumption()))
+	ag := &mockAgent{
+		id: "test",
+		events: []agent.Event{
+			{Type: agent.EventText, Text: "chunk1"},
+			{Type: agent.EventText, Text: "chunk2"},
+		},
+	}
+	if err := s.RegisterAgent("/chat", ag); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	stream := func(lastEventID string) string {
+		body, _ := json.Marshal(server.InvokeRequest{Input: "hi"})
+		req := httptest.NewRequest(http.MethodPost, "/chat/stream", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		w := httptest.NewRecorder()
+		a.Router().(http.Handler).ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w.Body.String()
+	}
+
+	first := stream("")
+	var firstID string
+	for _, line := range strings.Split(first, "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			firstID = id
+			break
+		}
+	}
+	if !strings.HasSuffix(firstID, ":1") {
+		t.Fatalf("expected resumable event IDs, got %q in %q", firstID, first)
+	}
+
+	resumed := stream(firstID)
+	if strings.Contains(resumed, "chunk1") || !strings.Contains(resumed, "chunk2") {
+		t.Errorf("expected the events after %s, got %q", firstID, resumed)
+	}
+}
@@ -3,7 +3,9 @@ package connect
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"sync"
 
 	"github.com/lookatitude/beluga-ai/v2/agent"
 	"github.com/lookatitude/beluga-ai/v2/internal/httputil"
@@ -17,16 +19,23 @@ type Adapter struct {
 	mux *http.ServeMux
 	lc  httputil.ServerLifecycle
 	cfg server.Config
+
+	mu     sync.RWMutex
+	agents map[string]agent.Agent
 }
 
-// Compile-time interface check.
-var _ server.ServerAdapter = (*Adapter)(nil)
+// Compile-time interface checks.
+var (
+	_ server.ServerAdapter = (*Adapter)(nil)
+	_ server.AgentLister   = (*Adapter)(nil)
+)
 
 // New creates a new Connect-Go adapter with the given configuration.
 func New(cfg server.Config) *Adapter {
 	return &Adapter{
-		mux: http.NewServeMux(),
-		cfg: cfg,
+		mux:    http.NewServeMux(),
+		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
@@ -39,9 +48,20 @@ func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	handler := server.NewAgentHandler(ag)
 	stripped := http.StripPrefix(path, handler)
 	a.mux.Handle(path+"/", stripped)
+
+	a.mu.Lock()
+	a.agents[path] = ag
+	a.mu.Unlock()
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (a *Adapter) Agents() map[string]agent.Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return maps.Clone(a.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (a *Adapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
@@ -85,6 +85,26 @@ func TestAdapter_RegisterAgent(t *testing.T) {
 	})
 }
 
+func TestAdapter_Agents(t *testing.T) {
+	a := New(server.Config{})
+	ag := &mockAgent{id: "test", result: "hello"}
+	if err := a.RegisterAgent("/api/agent", ag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agents := a.Agents()
+	if len(agents) != 1 || agents["/api/agent"] != ag {
+		t.Fatalf("Agents() = %v, want the registered agent", agents)
+	}
+
+	doc, err := server.GenerateOpenAPI(a)
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+	if !bytes.Contains(doc, []byte(`"/api/agent/invoke"`)) {
+		t.Errorf("OpenAPI document does not describe /api/agent/invoke: %s", doc)
+	}
+}
+
 func TestAdapter_RegisterHandler(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := New(server.Config{})
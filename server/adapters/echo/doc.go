@@ -18,6 +18,15 @@
 // advanced configuration such as adding Echo-specific middleware or custom
 // routes.
 //
+// # Draining
+//
+// The adapter does not implement server.Drainer. server.Drain falls back to
+// Shutdown, which stops the Echo server from accepting connections and waits
+// for in-flight requests, but active SSE streams are closed without the
+// "server_closing" event. Deployments that need clients to reconnect cleanly
+// should put a draining-aware load balancer in front of the adapter or use
+// the stdlib adapter.
+//
 // # Key Types
 //
 //   - Adapter — implements server.ServerAdapter using Echo
@@ -3,7 +3,9 @@ package echo
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 
@@ -17,10 +19,16 @@ type Adapter struct {
 	echo *echo.Echo
 	lc   httputil.ServerLifecycle
 	cfg  server.Config
+
+	mu     sync.RWMutex
+	agents map[string]agent.Agent
 }
 
-// Compile-time interface check.
-var _ server.ServerAdapter = (*Adapter)(nil)
+// Compile-time interface checks.
+var (
+	_ server.ServerAdapter = (*Adapter)(nil)
+	_ server.AgentLister   = (*Adapter)(nil)
+)
 
 // New creates a new Echo adapter with the given configuration.
 func New(cfg server.Config) *Adapter {
@@ -28,8 +36,9 @@ func New(cfg server.Config) *Adapter {
 	e.HideBanner = true
 	e.HidePort = true
 	return &Adapter{
-		echo: e,
-		cfg:  cfg,
+		echo:   e,
+		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
@@ -42,9 +51,20 @@ func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	handler := server.NewAgentHandler(ag)
 	stripped := http.StripPrefix(path, handler)
 	a.echo.Any(path+"/*", echo.WrapHandler(stripped))
+
+	a.mu.Lock()
+	a.agents[path] = ag
+	a.mu.Unlock()
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (a *Adapter) Agents() map[string]agent.Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return maps.Clone(a.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (a *Adapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
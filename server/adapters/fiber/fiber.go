@@ -3,6 +3,7 @@ package fiber
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"sync"
 	"time"
@@ -18,10 +19,15 @@ type Adapter struct {
 	app *fiber.App
 	cfg server.Config
 	mu  sync.RWMutex
+
+	agents map[string]agent.Agent
 }
 
-// Compile-time interface check.
-var _ server.ServerAdapter = (*Adapter)(nil)
+// Compile-time interface checks.
+var (
+	_ server.ServerAdapter = (*Adapter)(nil)
+	_ server.AgentLister   = (*Adapter)(nil)
+)
 
 // New creates a new Fiber adapter with the given configuration.
 func New(cfg server.Config) *Adapter {
@@ -31,25 +37,39 @@ func New(cfg server.Config) *Adapter {
 		IdleTimeout:  cfg.IdleTimeout,
 	})
 	return &Adapter{
-		app: app,
-		cfg: cfg,
+		app:    app,
+		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
 // RegisterAgent registers an agent at the given path prefix. It creates
 // sub-routes for invoke and stream endpoints using the standard agent handler.
+// Agents given server.WithWebSocket through server.WithAgentHandlerOptions
+// are rejected, as Fiber cannot hand the connection over to a WebSocket.
 func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	if ag == nil {
 		return fmt.Errorf("server/fiber: agent must not be nil")
 	}
+	if server.HandlerConfigOf(ag).WebSocket {
+		return fmt.Errorf("server/fiber: WebSocket is not supported; Fiber cannot hand over the connection")
+	}
 	handler := server.NewAgentHandler(ag)
 	stripped := http.StripPrefix(path, handler)
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.app.All(path+"/*", stripped)
+	a.agents[path] = ag
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (a *Adapter) Agents() map[string]agent.Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return maps.Clone(a.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (a *Adapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
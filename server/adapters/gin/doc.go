@@ -17,6 +17,15 @@
 // The underlying gin.Engine is accessible via the Engine() method for advanced
 // configuration such as adding Gin-specific middleware or custom routes.
 //
+// # Draining
+//
+// The adapter does not implement server.Drainer. server.Drain falls back to
+// Shutdown, which stops the Gin server from accepting connections and waits
+// for in-flight requests, but active SSE streams are closed without the
+// "server_closing" event. Deployments that need clients to reconnect cleanly
+// should put a draining-aware load balancer in front of the adapter or use
+// the stdlib adapter.
+//
 // # Key Types
 //
 //   - Adapter — implements server.ServerAdapter using Gin
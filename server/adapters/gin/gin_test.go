@@ -8,10 +8,12 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
 	"github.com/lookatitude/beluga-ai/v2/server"
 	"github.com/lookatitude/beluga-ai/v2/tool"
 )
@@ -85,6 +87,26 @@ func TestAdapter_RegisterAgent(t *testing.T) {
 	})
 }
 
+func TestAdapter_Agents(t *testing.T) {
+	a := New(server.Config{})
+	ag := &mockAgent{id: "test", result: "hello"}
+	if err := a.RegisterAgent("/api/agent", ag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agents := a.Agents()
+	if len(agents) != 1 || agents["/api/agent"] != ag {
+		t.Fatalf("Agents() = %v, want the registered agent", agents)
+	}
+
+	doc, err := server.GenerateOpenAPI(a)
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+	if !bytes.Contains(doc, []byte(`"/api/agent/invoke"`)) {
+		t.Errorf("OpenAPI document does not describe /api/agent/invoke: %s", doc)
+	}
+}
+
 func TestAdapter_RegisterHandler(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		a := New(server.Config{})
@@ -298,3 +320,32 @@ func TestAdapter_Shutdown_Error(t *testing.T) {
 	cancel()
 	<-errCh
 }
+
+func TestAdapter_WebSocket(t *testing.T) {
+	a := New(server.Config{})
+	s := server.ApplyMiddleware(a, server.WithAgentHandlerOptions(server.WithWebSocket()))
+	ag := &mockAgent{id: "test", events: []agent.Event{{Type: agent.EventText, Text: "hello"}}}
+	if err := s.RegisterAgent("/chat", ag); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	srv := httptest.NewServer(a.Engine())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ws, err := httpclient.DialWS(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/chat/ws", nil)
+	if err != nil {
+		t.Fatalf("DialWS: %v", err)
+	}
+	defer ws.Close()
+	if err := ws.WriteJSON(ctx, server.InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	var ev server.StreamEvent
+	if err := ws.ReadJSON(ctx, &ev); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if ev.Text != "hello" {
+		t.Errorf("event = %+v, want text hello", ev)
+	}
+}
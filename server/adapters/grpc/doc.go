@@ -23,6 +23,13 @@
 // Requests and responses use JSON encoding. The ClientCodecOption function
 // returns a gRPC dial option for connecting with the JSON codec.
 //
+// Request middleware from the server package, such as WithAuthPolicy,
+// WithConcurrencyLimit, and WithAccessLog, applies to the RPCs of agents
+// registered through it. Each call is presented to the middleware as a POST
+// to "/invoke" or "/stream" whose headers are the incoming gRPC metadata,
+// and a rejected call fails with the matching status code, such as
+// PermissionDenied for 403 and ResourceExhausted for 429.
+//
 // Note: RegisterHandler is not supported for gRPC adapters. Use RegisterAgent
 // to expose agents.
 //
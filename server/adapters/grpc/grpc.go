@@ -67,7 +67,7 @@ func init() {
 // Adapter implements server.ServerAdapter using gRPC.
 type Adapter struct {
 	grpcServer *grpc.Server
-	agents     map[string]agent.Agent
+	agents     map[string]*registration
 	cfg        server.Config
 	mu         sync.RWMutex
 }
@@ -79,20 +79,38 @@ var _ server.ServerAdapter = (*Adapter)(nil)
 func New(cfg server.Config) *Adapter {
 	return &Adapter{
 		grpcServer: grpc.NewServer(grpc.ForceServerCodec(jsonCodec{})),
-		agents:     make(map[string]agent.Agent),
+		agents:     make(map[string]*registration),
 		cfg:        cfg,
 	}
 }
 
 // RegisterAgent registers an agent at the given path. The path is used as a
 // routing key for the Invoke and Stream RPCs.
+//
+// Request middleware added with server.HandlerMiddleware, such as
+// server.WithAuthPolicy, server.WithConcurrencyLimit, and
+// server.WithAccessLog, applies to the agent's RPCs: each call is presented
+// to it as a POST to "/invoke" or "/stream" whose headers are the incoming
+// gRPC metadata. A call the middleware rejects fails with the status code
+// matching the HTTP response, such as PermissionDenied for 403 and
+// ResourceExhausted for 429. Agents given the WebSocket or stream resumption
+// handler options through server.WithAgentHandlerOptions are rejected, as
+// those only apply to HTTP routes.
 func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	if ag == nil {
 		return fmt.Errorf("server/grpc: agent must not be nil")
 	}
+	if cfg := server.HandlerConfigOf(ag); cfg.WebSocket || cfg.StreamResumption {
+		return fmt.Errorf("server/grpc: WebSocket and stream resumption are HTTP handler options and are not supported")
+	}
+	inner, wrap := server.UnwrapAgent(ag)
+	reg := &registration{agent: inner}
+	if wrap != nil {
+		reg.handler = wrap(http.HandlerFunc(runGuarded))
+	}
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.agents[path] = ag
+	a.agents[path] = reg
 	return nil
 }
 
@@ -148,14 +166,14 @@ func (a *Adapter) Server() *grpc.Server {
 	return a.grpcServer
 }
 
-func (a *Adapter) getAgent(path string) (agent.Agent, error) {
+func (a *Adapter) getAgent(path string) (*registration, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	ag, ok := a.agents[path]
+	reg, ok := a.agents[path]
 	if !ok {
 		return nil, status.Errorf(codes.NotFound, "no agent registered at path %q", path)
 	}
-	return ag, nil
+	return reg, nil
 }
 
 // agentServiceServer is the interface for the gRPC agent service.
@@ -202,14 +220,21 @@ func (a *Adapter) invoke(ctx context.Context, reqBytes []byte) (*rawBytes, error
 		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
 	}
 
-	ag, err := a.getAgent(req.Path)
+	reg, err := a.getAgent(req.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := ag.Invoke(ctx, req.Input)
+	var result string
+	var invokeErr error
+	err = reg.guard(ctx, req.Path, "/invoke", func(ctx context.Context) {
+		result, invokeErr = reg.agent.Invoke(ctx, req.Input)
+	})
 	if err != nil {
-		resp := InvokeResponse{Error: err.Error()}
+		return nil, err
+	}
+	if invokeErr != nil {
+		resp := InvokeResponse{Error: invokeErr.Error()}
 		data, _ := json.Marshal(resp)
 		rb := rawBytes(data)
 		return &rb, nil
@@ -228,12 +253,25 @@ func (a *Adapter) stream(reqBytes []byte, ss grpc.ServerStream) error {
 		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
 	}
 
-	ag, err := a.getAgent(req.Path)
+	reg, err := a.getAgent(req.Path)
 	if err != nil {
 		return err
 	}
 
-	for event, err := range ag.Stream(ss.Context(), req.Input) {
+	var streamErr error
+	err = reg.guard(ss.Context(), req.Path, "/stream", func(ctx context.Context) {
+		streamErr = sendEvents(ctx, reg.agent, req.Input, ss)
+	})
+	if err != nil {
+		return err
+	}
+	return streamErr
+}
+
+// sendEvents streams the agent's events for input to ss, followed by a
+// "done" event, or an "error" event if the agent fails.
+func sendEvents(ctx context.Context, ag agent.Agent, input string, ss grpc.ServerStream) error {
+	for event, err := range ag.Stream(ctx, input) {
 		if err != nil {
 			errEvent := StreamEvent{Type: "error", Text: err.Error()}
 			data, _ := json.Marshal(errEvent)
@@ -86,6 +86,23 @@ func TestAdapter_RegisterAgent(t *testing.T) {
 			t.Fatal("expected error for nil agent")
 		}
 	})
+
+	t.Run("HTTP handler options return error", func(t *testing.T) {
+		s := server.ApplyMiddleware(New(server.Config{}), server.WithAgentHandlerOptions(server.WithWebSocket()))
+		if s.RegisterAgent("/chat", &mockAgent{id: "test"}) == nil {
+			t.Fatal("expected error for WebSocket on gRPC")
+		}
+	})
+}
+
+func TestAdapter_NoOpenAPI(t *testing.T) {
+	a := New(server.Config{})
+	if err := a.RegisterAgent("/chat", &mockAgent{id: "test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := server.GenerateOpenAPI(a); err == nil {
+		t.Fatal("expected GenerateOpenAPI to reject the gRPC adapter")
+	}
 }
 
 func TestAdapter_RegisterHandler(t *testing.T) {
@@ -110,11 +127,12 @@ func TestAdapter_Server(t *testing.T) {
 }
 
 // startTestServer starts a gRPC server on a random port and returns the address and cleanup.
-func startTestServer(t *testing.T, ag agent.Agent) (*Adapter, string, func()) {
+// The agent is registered at "/chat" through the given middlewares.
+func startTestServer(t *testing.T, ag agent.Agent, mws ...server.Middleware) (*Adapter, string, func()) {
 	t.Helper()
 
 	a := New(server.Config{})
-	if err := a.RegisterAgent("/chat", ag); err != nil {
+	if err := server.ApplyMiddleware(a, mws...).RegisterAgent("/chat", ag); err != nil {
 		t.Fatalf("RegisterAgent: %v", err)
 	}
 
@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
+// registration is an agent registered with the adapter. handler is the
+// request middleware attached to it by server.HandlerMiddleware, wrapped
+// around runGuarded, or nil when there is none. It is built once, at
+// registration, so that stateful middleware such as concurrency limits is
+// shared by all calls.
+type registration struct {
+	agent   agent.Agent
+	handler http.Handler
+}
+
+// guardedCall is an RPC presented to request middleware. reached records
+// whether the middleware let it through.
+type guardedCall struct {
+	run     func(ctx context.Context)
+	reached bool
+}
+
+type guardedCallKey struct{}
+
+// runGuarded is the handler the request middleware wraps: it runs the
+// guardedCall carried by the request context.
+func runGuarded(_ http.ResponseWriter, r *http.Request) {
+	c, ok := r.Context().Value(guardedCallKey{}).(*guardedCall)
+	if !ok {
+		return
+	}
+	c.reached = true
+	c.run(r.Context())
+}
+
+// guard runs call behind the request middleware of the agent registered at
+// path. The middleware sees a POST to method ("/invoke" or "/stream") with
+// the incoming metadata as headers; call receives the request context as
+// the middleware left it. If the middleware rejects the call, guard returns
+// the matching status error without running it.
+func (r *registration) guard(ctx context.Context, path, method string, call func(ctx context.Context)) error {
+	if r.handler == nil {
+		call(ctx)
+		return nil
+	}
+
+	c := &guardedCall{run: call}
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, guardedCallKey{}, c), http.MethodPost, method, http.NoBody)
+	if err != nil {
+		return status.Errorf(codes.Internal, "build request: %v", err)
+	}
+	req.RequestURI = path + method
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, vs := range md {
+			if strings.HasPrefix(k, ":") {
+				continue
+			}
+			for _, v := range vs {
+				req.Header.Add(k, v)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	rec := &rejection{header: make(http.Header), status: http.StatusOK}
+	r.handler.ServeHTTP(rec, req)
+	if c.reached {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return rec.err()
+}
+
+// rejection records the response of request middleware that rejected a
+// call.
+type rejection struct {
+	header http.Header
+	status int
+	body   []byte
+}
+
+func (w *rejection) Header() http.Header { return w.header }
+
+func (w *rejection) WriteHeader(status int) { w.status = status }
+
+func (w *rejection) Write(p []byte) (int, error) {
+	w.body = append(w.body, p...)
+	return len(p), nil
+}
+
+// err converts the recorded response into a gRPC status error, using the
+// "error" field of a JSON body as the message when present.
+func (w *rejection) err() error {
+	msg := http.StatusText(w.status)
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(w.body, &body) == nil && body.Error != "" {
+		msg = body.Error
+	}
+	if msg == "" {
+		msg = "rejected by server middleware"
+	}
+	return status.Error(httpStatusCode(w.status), msg)
+}
+
+// httpStatusCode maps an HTTP status to the closest gRPC code.
+func httpStatusCode(s int) codes.Code {
+	switch s {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	}
+	if s >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
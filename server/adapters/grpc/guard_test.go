@@ -0,0 +1,187 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/auth"
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+	"github.com/lookatitude/beluga-ai/v2/server"
+)
+
+// blockingAgent blocks each Invoke until release is closed.
+type blockingAgent struct {
+	mockAgent
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingAgent) Invoke(ctx context.Context, _ string, _ ...agent.Option) (string, error) {
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return "done", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (b *blockingAgent) Stream(context.Context, string, ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(func(agent.Event, error) bool) {}
+}
+
+func invokeChat(ctx context.Context, t *testing.T, addr string) (InvokeResponse, error) {
+	t.Helper()
+	conn := dialTest(t, addr)
+	defer conn.Close()
+
+	reqData, _ := json.Marshal(InvokeRequest{Path: "/chat", Input: "hi"})
+	req := rawBytes(reqData)
+	var resp rawBytes
+	if err := conn.Invoke(ctx, "/beluga.AgentService/Invoke", &req, &resp); err != nil {
+		return InvokeResponse{}, err
+	}
+	var out InvokeResponse
+	if err := json.Unmarshal(resp, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out, nil
+}
+
+func TestAdapter_AuthPolicy(t *testing.T) {
+	policy := auth.NewRBACPolicy("test")
+	if err := policy.AddRole(auth.Role{Name: "user", Permissions: []auth.Permission{"agent:invoke"}}); err != nil {
+		t.Fatalf("AddRole: %v", err)
+	}
+	if err := policy.AssignRole("alice", "user"); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	var resources []string
+	var mu sync.Mutex
+	extract := func(_ string, r *http.Request) (string, auth.Permission, string) {
+		mu.Lock()
+		resources = append(resources, r.URL.Path)
+		mu.Unlock()
+		return r.Header.Get("X-User"), "agent:invoke", r.URL.Path
+	}
+
+	ag := &mockAgent{id: "test", result: "secret answer"}
+	_, addr, cleanup := startTestServer(t, ag, server.WithAuthPolicy(policy, extract))
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		user     string
+		wantCode codes.Code
+	}{
+		{name: "allowed", user: "alice", wantCode: codes.OK},
+		{name: "denied", user: "mallory", wantCode: codes.PermissionDenied},
+		{name: "anonymous", wantCode: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.user != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-user", tt.user)
+			}
+			resp, err := invokeChat(ctx, t, addr)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err %v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && resp.Result != "secret answer" {
+				t.Errorf("result = %q", resp.Result)
+			}
+			if tt.wantCode != codes.OK && strings.Contains(resp.Result, "secret") {
+				t.Error("denied call reached the agent")
+			}
+		})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, r := range resources {
+		if r != "/invoke" {
+			t.Errorf("resource = %q, want /invoke", r)
+		}
+	}
+}
+
+func TestAdapter_ConcurrencyLimit(t *testing.T) {
+	ag := &blockingAgent{
+		mockAgent: mockAgent{id: "slow"},
+		started:   make(chan struct{}, 1),
+		release:   make(chan struct{}),
+	}
+	_, addr, cleanup := startTestServer(t, ag, server.WithConcurrencyLimit(1, 0))
+	defer cleanup()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := invokeChat(context.Background(), t, addr)
+		first <- err
+	}()
+	select {
+	case <-ag.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first call did not start")
+	}
+
+	_, err := invokeChat(context.Background(), t, addr)
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf("second call code = %v, want ResourceExhausted (err %v)", code, err)
+	}
+
+	close(ag.release)
+	if err := <-first; err != nil {
+		t.Errorf("first call: %v", err)
+	}
+}
+
+func TestAdapter_AccessLog(t *testing.T) {
+	var sink bytes.Buffer
+	var mu sync.Mutex
+	logger := o11y.NewLogger(o11y.WithLogHandler(slog.NewJSONHandler(&lockedWriter{w: &sink, mu: &mu}, nil)))
+
+	ag := &mockAgent{id: "test", result: "hello"}
+	_, addr, cleanup := startTestServer(t, ag, server.WithAccessLog(logger))
+	defer cleanup()
+
+	if _, err := invokeChat(context.Background(), t, addr); err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var entry map[string]any
+	if err := json.Unmarshal(sink.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log entry %q: %v", sink.String(), err)
+	}
+	if entry["path"] != "/chat/invoke" || entry["route"] != "/chat" {
+		t.Errorf("entry = %v, want path /chat/invoke on route /chat", entry)
+	}
+}
+
+// lockedWriter serializes writes to w.
+type lockedWriter struct {
+	w  *bytes.Buffer
+	mu *sync.Mutex
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -3,7 +3,9 @@ package huma
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"sync"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
@@ -19,10 +21,16 @@ type Adapter struct {
 	api huma.API
 	lc  httputil.ServerLifecycle
 	cfg server.Config
+
+	mu     sync.RWMutex
+	agents map[string]agent.Agent
 }
 
-// Compile-time interface check.
-var _ server.ServerAdapter = (*Adapter)(nil)
+// Compile-time interface checks.
+var (
+	_ server.ServerAdapter = (*Adapter)(nil)
+	_ server.AgentLister   = (*Adapter)(nil)
+)
 
 // New creates a new Huma adapter with the given configuration.
 func New(cfg server.Config) *Adapter {
@@ -37,9 +45,10 @@ func New(cfg server.Config) *Adapter {
 	}
 	api := humago.New(mux, huma.DefaultConfig(title, version))
 	return &Adapter{
-		mux: mux,
-		api: api,
-		cfg: cfg,
+		mux:    mux,
+		api:    api,
+		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
@@ -52,9 +61,20 @@ func (a *Adapter) RegisterAgent(path string, ag agent.Agent) error {
 	handler := server.NewAgentHandler(ag)
 	stripped := http.StripPrefix(path, handler)
 	a.mux.Handle(path+"/", stripped)
+
+	a.mu.Lock()
+	a.agents[path] = ag
+	a.mu.Unlock()
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (a *Adapter) Agents() map[string]agent.Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return maps.Clone(a.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (a *Adapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
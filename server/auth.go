@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+)
+
+// AuthExtractor maps an HTTP request to the subject, permission, and
+// resource passed to auth.Policy.Authorize. route is the path the agent or
+// handler was registered at (for example "/chat"), which identifies the
+// agent being called. For agent routes, r.URL.Path has the registration
+// prefix stripped (for example "/invoke").
+type AuthExtractor func(route string, r *http.Request) (subject string, perm auth.Permission, resource string)
+
+// WithAuthPolicy returns middleware that authorizes every request with
+// policy before it reaches the registered handler or agent. Denied requests
+// receive 403 Forbidden; requests whose authorization fails with an error
+// receive 500 Internal Server Error. Neither reaches the agent.
+//
+// Decisions are reported through the given auth hooks, so audit logging
+// configured with auth.Hooks (OnAllow, OnDeny, OnError) records HTTP
+// authorization too. A policy already wrapped with auth.WithAudit is audited
+// as usual.
+//
+// Example, authorizing each agent by its registration path:
+//
+//	s = server.ApplyMiddleware(s, server.WithAuthPolicy(policy,
+//	    func(route string, r *http.Request) (string, auth.Permission, string) {
+//	        return r.Header.Get("X-User"), "agent:invoke", route
+//	    },
+//	))
+func WithAuthPolicy(policy auth.Policy, extract AuthExtractor, hooks ...auth.Hooks) Middleware {
+	if len(hooks) > 0 {
+		policy = auth.ApplyMiddleware(policy, auth.WithHooks(auth.ComposeHooks(hooks...)))
+	}
+	return HandlerMiddleware(func(route string, next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, perm, resource := extract(route, r)
+			allowed, err := policy.Authorize(r.Context(), subject, perm, resource)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, InvokeResponse{Error: "authorization failed"})
+				return
+			}
+			if !allowed {
+				writeJSON(w, http.StatusForbidden, InvokeResponse{Error: "forbidden"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
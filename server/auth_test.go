@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/auth"
+)
+
+// errPolicy is an auth.Policy that always fails.
+type errPolicy struct{}
+
+func (errPolicy) Name() string { return "err" }
+func (errPolicy) Authorize(context.Context, string, auth.Permission, string) (bool, error) {
+	return false, errors.New("policy backend down")
+}
+
+func userExtractor(route string, r *http.Request) (string, auth.Permission, string) {
+	return r.Header.Get("X-User"), "agent:invoke", route
+}
+
+func TestWithAuthPolicy(t *testing.T) {
+	policy := auth.NewRBACPolicy("test")
+	if err := policy.AddRole(auth.Role{Name: "user", Permissions: []auth.Permission{"agent:invoke"}}); err != nil {
+		t.Fatalf("AddRole: %v", err)
+	}
+	if err := policy.AssignRole("alice", "user"); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	var allows, denies []string
+	hooks := auth.Hooks{
+		OnAllow: func(_ context.Context, subject string, _ auth.Permission, resource string) {
+			allows = append(allows, subject+":"+resource)
+		},
+		OnDeny: func(_ context.Context, subject string, _ auth.Permission, resource string) {
+			denies = append(denies, subject+":"+resource)
+		},
+	}
+
+	base := NewStdlibAdapter(Config{})
+	s := ApplyMiddleware(base, WithAuthPolicy(policy, userExtractor, hooks))
+	if err := s.RegisterAgent("/chat", &mockAgent{id: "a", result: "secret answer"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	if err := s.RegisterHandler("/admin", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})); err != nil {
+		t.Fatalf("RegisterHandler: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		user       string
+		wantStatus int
+	}{
+		{"allowed agent call", http.MethodPost, "/chat/invoke", "alice", http.StatusOK},
+		{"denied agent call", http.MethodPost, "/chat/invoke", "bob", http.StatusForbidden},
+		{"denied stream", http.MethodPost, "/chat/stream", "bob", http.StatusForbidden},
+		{"allowed raw handler", http.MethodGet, "/admin", "alice", http.StatusOK},
+		{"denied raw handler", http.MethodGet, "/admin", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"input":"hi"}`))
+			req.Header.Set("X-User", tt.user)
+			rec := httptest.NewRecorder()
+			base.mux.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusForbidden && strings.Contains(rec.Body.String(), "secret") {
+				t.Error("denied request reached the agent")
+			}
+		})
+	}
+
+	if len(allows) != 2 || allows[0] != "alice:/chat" {
+		t.Errorf("allows = %v", allows)
+	}
+	if len(denies) != 3 || denies[0] != "bob:/chat" {
+		t.Errorf("denies = %v", denies)
+	}
+}
+
+func TestWithAuthPolicy_PerAgent(t *testing.T) {
+	policy := auth.NewRBACPolicy("test")
+	if err := policy.AddRole(auth.Role{
+		Name:        "support",
+		Permissions: []auth.Permission{"agent:invoke"},
+		Resources:   []string{"/support"},
+	}); err != nil {
+		t.Fatalf("AddRole: %v", err)
+	}
+	if err := policy.AssignRole("alice", "support"); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	base := NewStdlibAdapter(Config{})
+	s := ApplyMiddleware(base, WithAuthPolicy(policy, userExtractor))
+	if err := s.RegisterAgent("/support", &mockAgent{id: "support", result: "support answer"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	if err := s.RegisterAgent("/billing", &mockAgent{id: "billing", result: "billing answer"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/support/invoke": http.StatusOK,
+		"/billing/invoke": http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"input":"hi"}`))
+		req.Header.Set("X-User", "alice")
+		rec := httptest.NewRecorder()
+		base.mux.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: status = %d, want %d (body %s)", path, rec.Code, want, rec.Body.String())
+		}
+	}
+}
+
+func TestWithAuthPolicy_PolicyError(t *testing.T) {
+	base := NewStdlibAdapter(Config{})
+	s := ApplyMiddleware(base, WithAuthPolicy(errPolicy{}, userExtractor))
+	if err := s.RegisterAgent("/chat", &mockAgent{id: "a", result: "ok"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	base.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chat/invoke", strings.NewReader(`{"input":"hi"}`)))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "backend down") {
+		t.Error("policy error details should not leak to the client")
+	}
+}
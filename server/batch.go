@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
+// Defaults for {prefix}/batch.
+const (
+	defaultBatchConcurrency = 4
+	defaultBatchMaxItems    = 100
+)
+
+// BatchRequest is the JSON body for batch endpoints.
+type BatchRequest struct {
+	// Items are the invocations to run.
+	Items []InvokeRequest `json:"items"`
+
+	// FailFast stops the batch at the first failed item. Items still running
+	// are canceled and items not yet started are skipped. By default every
+	// item runs and failures are reported per item.
+	FailFast bool `json:"fail_fast,omitempty"`
+}
+
+// BatchResponse is the JSON response for batch endpoints. Results are in the
+// same order as the request items; a failed or skipped item has its Error
+// set.
+type BatchResponse struct {
+	Results []InvokeResponse `json:"results"`
+
+	// Error is set when a fail-fast batch was aborted.
+	Error string `json:"error,omitempty"`
+}
+
+// WithBatchConcurrency sets how many items of one {prefix}/batch request run
+// at the same time. The default is 4.
+func WithBatchConcurrency(n int) HandlerOption {
+	return func(c *handlerConfig) {
+		if n > 0 {
+			c.batchConcurrency = n
+		}
+	}
+}
+
+// WithBatchMaxItems sets the largest number of items accepted by one
+// {prefix}/batch request; larger batches are rejected with 400 Bad Request.
+// The default is 100.
+func WithBatchMaxItems(n int) HandlerOption {
+	return func(c *handlerConfig) {
+		if n > 0 {
+			c.batchMaxItems = n
+		}
+	}
+}
+
+func handleBatch(w http.ResponseWriter, r *http.Request, a agent.Agent, cfg *handlerConfig) {
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, BatchResponse{
+			Error: fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	if len(req.Items) == 0 {
+		writeJSON(w, http.StatusBadRequest, BatchResponse{Error: "batch has no items"})
+		return
+	}
+	if len(req.Items) > cfg.batchMaxItems {
+		writeJSON(w, http.StatusBadRequest, BatchResponse{
+			Error: fmt.Sprintf("batch has %d items, limit is %d", len(req.Items), cfg.batchMaxItems),
+		})
+		return
+	}
+
+	resp := runBatch(r.Context(), a, req, cfg.batchConcurrency)
+	if resp.Error != "" {
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runBatch invokes the agent for each item with at most concurrency items in
+// flight.
+func runBatch(ctx context.Context, a agent.Agent, req BatchRequest, concurrency int) BatchResponse {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]InvokeResponse, len(req.Items))
+	started := make([]bool, len(req.Items))
+	var (
+		mu     sync.Mutex
+		failed = -1
+		wg     sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for i, item := range req.Items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		started[i] = true
+		wg.Go(func() {
+			defer func() { <-sem }()
+			resp, err := invoke(ctx, a, item)
+			results[i] = resp
+			if err != nil && req.FailFast {
+				mu.Lock()
+				if failed < 0 {
+					failed = i
+					cancel()
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	out := BatchResponse{Results: results}
+	if failed >= 0 {
+		out.Error = fmt.Sprintf("batch aborted: item %d failed: %s", failed, results[failed].Error)
+	} else if err := ctx.Err(); err != nil {
+		// The client went away; report items that never ran.
+		out.Error = fmt.Sprintf("batch aborted: %v", err)
+	}
+	for i := range results {
+		if !started[i] {
+			results[i].Error = "skipped: batch aborted"
+		}
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
+// echoAgent echoes its input, failing for inputs that start with "fail".
+// It records the peak number of concurrent invocations.
+type echoAgent struct {
+	mockAgent
+	delay    time.Duration
+	active   atomic.Int32
+	peak     atomic.Int32
+	canceled atomic.Int32
+}
+
+func (e *echoAgent) Invoke(ctx context.Context, input string, _ ...agent.Option) (string, error) {
+	n := e.active.Add(1)
+	defer e.active.Add(-1)
+	for {
+		p := e.peak.Load()
+		if n <= p || e.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	if strings.HasPrefix(input, "fail") {
+		return "", errors.New("boom: " + input)
+	}
+	select {
+	case <-time.After(e.delay):
+	case <-ctx.Done():
+		e.canceled.Add(1)
+		return "", ctx.Err()
+	}
+	return "echo: " + input, nil
+}
+
+func doBatch(t *testing.T, h http.Handler, body string) (int, BatchResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp BatchResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestHandleBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []HandlerOption
+		body       string
+		wantStatus int
+		wantErr    string
+		check      func(t *testing.T, resp BatchResponse)
+	}{
+		{
+			name:       "results in input order",
+			body:       `{"items":[{"input":"a"},{"input":"b"},{"input":"c"}]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, resp BatchResponse) {
+				for i, want := range []string{"echo: a", "echo: b", "echo: c"} {
+					if resp.Results[i].Result != want {
+						t.Errorf("results[%d] = %+v, want %q", i, resp.Results[i], want)
+					}
+				}
+			},
+		},
+		{
+			name:       "partial failure",
+			body:       `{"items":[{"input":"a"},{"input":"fail-1"},{"input":"c"}]}`,
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, resp BatchResponse) {
+				if resp.Results[0].Result != "echo: a" || resp.Results[2].Result != "echo: c" {
+					t.Errorf("successful items = %+v", resp.Results)
+				}
+				if !strings.Contains(resp.Results[1].Error, "boom: fail-1") {
+					t.Errorf("results[1].Error = %q", resp.Results[1].Error)
+				}
+			},
+		},
+		{
+			name:       "fail fast skips remaining items",
+			opts:       []HandlerOption{WithBatchConcurrency(1)},
+			body:       `{"items":[{"input":"a"},{"input":"fail-1"},{"input":"c"}],"fail_fast":true}`,
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "item 1 failed",
+			check: func(t *testing.T, resp BatchResponse) {
+				if resp.Results[0].Result != "echo: a" {
+					t.Errorf("results[0] = %+v", resp.Results[0])
+				}
+				if resp.Results[2].Error != "skipped: batch aborted" {
+					t.Errorf("results[2] = %+v, want skipped", resp.Results[2])
+				}
+			},
+		},
+		{
+			name:       "empty batch",
+			body:       `{"items":[]}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "no items",
+		},
+		{
+			name:       "too many items",
+			opts:       []HandlerOption{WithBatchMaxItems(2)},
+			body:       `{"items":[{"input":"a"},{"input":"b"},{"input":"c"}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "limit is 2",
+		},
+		{
+			name:       "invalid JSON",
+			body:       `[`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid request body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &echoAgent{mockAgent: mockAgent{id: "echo"}}
+			status, resp := doBatch(t, NewAgentHandler(a, tt.opts...), tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%+v)", status, tt.wantStatus, resp)
+			}
+			if !strings.Contains(resp.Error, tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", resp.Error, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, resp)
+			}
+		})
+	}
+}
+
+func TestHandleBatch_BoundedConcurrency(t *testing.T) {
+	a := &echoAgent{mockAgent: mockAgent{id: "echo"}, delay: 20 * time.Millisecond}
+	h := NewAgentHandler(a, WithBatchConcurrency(2))
+
+	items := make([]InvokeRequest, 8)
+	for i := range items {
+		items[i] = InvokeRequest{Input: "x"}
+	}
+	body, _ := json.Marshal(BatchRequest{Items: items})
+	status, resp := doBatch(t, h, string(body))
+	if status != http.StatusOK || len(resp.Results) != len(items) {
+		t.Fatalf("status = %d, results = %d", status, len(resp.Results))
+	}
+	if peak := a.peak.Load(); peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestHandleBatch_FailFastCancelsInFlight(t *testing.T) {
+	a := &echoAgent{mockAgent: mockAgent{id: "echo"}, delay: time.Minute}
+	h := NewAgentHandler(a, WithBatchConcurrency(4))
+
+	status, resp := doBatch(t, h, `{"items":[{"input":"slow"},{"input":"slow"},{"input":"fail"}],"fail_fast":true}`)
+	if status != http.StatusInternalServerError {
+		t.Fatalf("status = %d", status)
+	}
+	if got := a.canceled.Load(); got != 2 {
+		t.Errorf("canceled = %d, want 2", got)
+	}
+	for _, r := range resp.Results {
+		if r.Error == "" {
+			t.Errorf("expected every item to report an error, got %+v", resp.Results)
+		}
+	}
+}
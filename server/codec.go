@@ -0,0 +1,297 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Media types of the built-in codecs.
+const (
+	MediaTypeJSON     = "application/json"
+	MediaTypeMsgPack  = "application/msgpack"
+	MediaTypeProtobuf = "application/x-protobuf"
+)
+
+// Codec encodes and decodes the bodies of {prefix}/invoke requests and
+// responses. Implementations must support InvokeRequest and InvokeResponse
+// and be safe for concurrent use.
+type Codec interface {
+	// Marshal encodes v, which is an InvokeRequest or InvokeResponse.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal decodes data into v, which is a *InvokeRequest or
+	// *InvokeResponse.
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	codecMu sync.RWMutex
+	codecs  = make(map[string]Codec)
+)
+
+func init() {
+	RegisterCodec(MediaTypeJSON, jsonCodec{})
+	RegisterCodec(MediaTypeMsgPack, msgpackCodec{})
+	RegisterCodec("application/x-msgpack", msgpackCodec{})
+	RegisterCodec(MediaTypeProtobuf, protobufCodec{})
+	RegisterCodec("application/protobuf", protobufCodec{})
+}
+
+// RegisterCodec adds a codec for the given media type, such as
+// "application/cbor", to the global registry. The media type is matched
+// case-insensitively against the Content-Type and Accept headers of
+// {prefix}/invoke requests. Duplicate registrations for the same media type
+// silently overwrite the previous codec.
+func RegisterCodec(mediaType string, c Codec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	codecs[strings.ToLower(mediaType)] = c
+}
+
+// CodecFor returns the codec registered for mediaType, ignoring any
+// parameters such as charset.
+func CodecFor(mediaType string) (Codec, bool) {
+	if mt, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = mt
+	}
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	c, ok := codecs[strings.ToLower(mediaType)]
+	return c, ok
+}
+
+// ListCodecs returns the registered media types, sorted alphabetically.
+func ListCodecs() []string {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	types := make([]string, 0, len(codecs))
+	for mt := range codecs {
+		types = append(types, mt)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// requestCodec selects the codec for the request body from its Content-Type.
+// Requests without a registered Content-Type are decoded as JSON, so
+// existing clients that send plain or form content types keep working.
+func requestCodec(r *http.Request) Codec {
+	if c, ok := CodecFor(r.Header.Get("Content-Type")); ok {
+		return c
+	}
+	return jsonCodec{}
+}
+
+// responseCodec selects the codec and media type for the response from the
+// Accept header, honoring q-values. JSON is used when the header is missing,
+// accepts anything, or names no registered media type.
+func responseCodec(r *http.Request) (Codec, string) {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType: mt, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.mediaType == "*/*" || c.mediaType == "application/*" {
+			break
+		}
+		if codec, ok := CodecFor(c.mediaType); ok {
+			return codec, c.mediaType
+		}
+	}
+	return jsonCodec{}, MediaTypeJSON
+}
+
+// writeCodec writes v encoded with codec. Encoding failures fall back to a
+// JSON error response.
+func writeCodec(w http.ResponseWriter, codec Codec, mediaType string, status int, v any) {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, InvokeResponse{Error: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	// Write errors mean the client disconnected; nothing to do.
+	_, _ = w.Write(data)
+}
+
+// readCodec reads the request body and decodes it with codec.
+func readCodec(r *http.Request, codec Codec, v any) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, v)
+}
+
+// jsonCodec is the default codec.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// msgpackCodec encodes InvokeRequest and InvokeResponse as MessagePack maps
+// keyed by their JSON field names.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	switch v := deref(v).(type) {
+	case InvokeRequest:
+		b := msgp.AppendMapHeader(nil, 1)
+		b = msgp.AppendString(b, "input")
+		return msgp.AppendString(b, v.Input), nil
+	case InvokeResponse:
+		n := uint32(1)
+		if v.Error != "" {
+			n++
+		}
+		b := msgp.AppendMapHeader(nil, n)
+		b = msgp.AppendString(b, "result")
+		b = msgp.AppendString(b, v.Result)
+		if v.Error != "" {
+			b = msgp.AppendString(b, "error")
+			b = msgp.AppendString(b, v.Error)
+		}
+		return b, nil
+	default:
+		return nil, core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: unsupported type %T", v)
+	}
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	var fields map[string]*string
+	switch v := v.(type) {
+	case *InvokeRequest:
+		fields = map[string]*string{"input": &v.Input}
+	case *InvokeResponse:
+		fields = map[string]*string{"result": &v.Result, "error": &v.Error}
+	default:
+		return core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: unsupported type %T", v)
+	}
+
+	n, b, err := msgp.ReadMapHeaderBytes(data)
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: %w", err)
+	}
+	for range n {
+		var key string
+		if key, b, err = msgp.ReadStringBytes(b); err != nil {
+			return core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: %w", err)
+		}
+		dst, ok := fields[key]
+		if !ok || msgp.IsNil(b) {
+			if b, err = msgp.Skip(b); err != nil {
+				return core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: %w", err)
+			}
+			continue
+		}
+		if *dst, b, err = msgp.ReadStringBytes(b); err != nil {
+			return core.Errorf(core.ErrInvalidInput, "server/codec: msgpack: field %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// protobufCodec encodes InvokeRequest and InvokeResponse in the protobuf
+// wire format of these messages:
+//
+//	message InvokeRequest  { string input = 1; }
+//	message InvokeResponse { string result = 1; string error = 2; }
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	var fields []string
+	switch v := deref(v).(type) {
+	case InvokeRequest:
+		fields = []string{v.Input}
+	case InvokeResponse:
+		fields = []string{v.Result, v.Error}
+	default:
+		return nil, core.Errorf(core.ErrInvalidInput, "server/codec: protobuf: unsupported type %T", v)
+	}
+
+	var b []byte
+	for i, s := range fields {
+		if s == "" {
+			continue
+		}
+		b = protowire.AppendTag(b, protowire.Number(i+1), protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	return b, nil
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	var fields []*string
+	switch v := v.(type) {
+	case *InvokeRequest:
+		fields = []*string{&v.Input}
+	case *InvokeResponse:
+		fields = []*string{&v.Result, &v.Error}
+	default:
+		return core.Errorf(core.ErrInvalidInput, "server/codec: protobuf: unsupported type %T", v)
+	}
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return core.Errorf(core.ErrInvalidInput, "server/codec: protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+		if typ == protowire.BytesType && num >= 1 && int(num) <= len(fields) {
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return core.Errorf(core.ErrInvalidInput, "server/codec: protobuf: field %d: %w", num, protowire.ParseError(n))
+			}
+			*fields[num-1] = s
+			data = data[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return core.Errorf(core.ErrInvalidInput, "server/codec: protobuf: field %d: %w", num, protowire.ParseError(n))
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// deref returns the value pointed to by a *InvokeRequest or *InvokeResponse,
+// so codecs accept both values and pointers.
+func deref(v any) any {
+	switch p := v.(type) {
+	case *InvokeRequest:
+		return *p
+	case *InvokeResponse:
+		return *p
+	}
+	return v
+}
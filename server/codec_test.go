@@ -0,0 +1,203 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	for _, mt := range []string{MediaTypeJSON, MediaTypeMsgPack, MediaTypeProtobuf} {
+		t.Run(mt, func(t *testing.T) {
+			c, ok := CodecFor(mt)
+			if !ok {
+				t.Fatalf("no codec for %s", mt)
+			}
+
+			data, err := c.Marshal(InvokeRequest{Input: "hello\nworld"})
+			if err != nil {
+				t.Fatalf("Marshal request: %v", err)
+			}
+			var req InvokeRequest
+			if err := c.Unmarshal(data, &req); err != nil {
+				t.Fatalf("Unmarshal request: %v", err)
+			}
+			if req.Input != "hello\nworld" {
+				t.Errorf("input = %q", req.Input)
+			}
+
+			for _, want := range []InvokeResponse{{Result: "ok"}, {Error: "boom"}} {
+				data, err := c.Marshal(&want)
+				if err != nil {
+					t.Fatalf("Marshal response: %v", err)
+				}
+				var got InvokeResponse
+				if err := c.Unmarshal(data, &got); err != nil {
+					t.Fatalf("Unmarshal response: %v", err)
+				}
+				if got != want {
+					t.Errorf("got %+v, want %+v", got, want)
+				}
+			}
+
+			if _, err := c.Marshal(struct{}{}); mt != MediaTypeJSON && err == nil {
+				t.Error("expected error for unsupported type")
+			}
+			if err := c.Unmarshal([]byte{0xc1}, &req); err == nil {
+				t.Error("expected error for malformed data")
+			}
+		})
+	}
+}
+
+func TestCodecs_SkipUnknownFields(t *testing.T) {
+	mp := msgp.AppendMapHeader(nil, 2)
+	mp = msgp.AppendString(mp, "extra")
+	mp = msgp.AppendInt(mp, 7)
+	mp = msgp.AppendString(mp, "input")
+	mp = msgp.AppendString(mp, "hi")
+
+	pb := protowire.AppendTag(nil, 9, protowire.VarintType)
+	pb = protowire.AppendVarint(pb, 7)
+	pb = protowire.AppendTag(pb, 1, protowire.BytesType)
+	pb = protowire.AppendString(pb, "hi")
+
+	for mt, data := range map[string][]byte{MediaTypeMsgPack: mp, MediaTypeProtobuf: pb} {
+		c, _ := CodecFor(mt)
+		var req InvokeRequest
+		if err := c.Unmarshal(data, &req); err != nil {
+			t.Fatalf("%s: %v", mt, err)
+		}
+		if req.Input != "hi" {
+			t.Errorf("%s: input = %q", mt, req.Input)
+		}
+	}
+}
+
+func TestResponseCodec(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", MediaTypeJSON},
+		{"*/*", MediaTypeJSON},
+		{"application/msgpack", MediaTypeMsgPack},
+		{"application/x-msgpack", "application/x-msgpack"},
+		{"text/html, application/x-protobuf", MediaTypeProtobuf},
+		{"application/json;q=0.5, application/msgpack", MediaTypeMsgPack},
+		{"application/msgpack;q=0.2, application/json;q=0.9", MediaTypeJSON},
+		{"application/msgpack;q=0", MediaTypeJSON},
+		{"*/*;q=0.1, application/x-protobuf", MediaTypeProtobuf},
+		{"text/html", MediaTypeJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/invoke", nil)
+			r.Header.Set("Accept", tt.accept)
+			if _, got := responseCodec(r); got != tt.want {
+				t.Errorf("media type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleInvoke_ContentNegotiation(t *testing.T) {
+	a := &mockAgent{id: "test", result: "pong"}
+	h := NewAgentHandler(a)
+
+	for _, mt := range []string{MediaTypeMsgPack, MediaTypeProtobuf} {
+		t.Run(mt, func(t *testing.T) {
+			c, _ := CodecFor(mt)
+			body, _ := c.Marshal(InvokeRequest{Input: "ping"})
+			req := httptest.NewRequest(http.MethodPost, "/invoke", bytes.NewReader(body))
+			req.Header.Set("Content-Type", mt)
+			req.Header.Set("Accept", mt)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != mt {
+				t.Errorf("Content-Type = %q, want %q", ct, mt)
+			}
+			var resp InvokeResponse
+			if err := c.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Result != "pong" {
+				t.Errorf("result = %q", resp.Result)
+			}
+		})
+	}
+
+	t.Run("malformed body uses negotiated codec", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/invoke", strings.NewReader("\xc1"))
+		req.Header.Set("Content-Type", MediaTypeMsgPack)
+		req.Header.Set("Accept", MediaTypeMsgPack)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d", w.Code)
+		}
+		var resp InvokeResponse
+		c, _ := CodecFor(MediaTypeMsgPack)
+		if err := c.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error == "" {
+			t.Errorf("resp = %+v, err = %v", resp, err)
+		}
+	})
+
+	t.Run("unregistered content type decodes as JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/invoke", strings.NewReader(`{"input":"ping"}`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		var resp InvokeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || resp.Result != "pong" {
+			t.Errorf("status = %d, resp = %+v, err = %v", w.Code, resp, err)
+		}
+	})
+}
+
+// upperCodec is a custom JSON variant used to test registration.
+type upperCodec struct{ jsonCodec }
+
+func (u upperCodec) Marshal(v any) ([]byte, error) {
+	data, err := u.jsonCodec.Marshal(v)
+	return bytes.ToUpper(data), err
+}
+
+func TestRegisterCodec(t *testing.T) {
+	const mt = "application/vnd.test-upper+json"
+	RegisterCodec(mt, upperCodec{})
+	t.Cleanup(func() {
+		codecMu.Lock()
+		delete(codecs, mt)
+		codecMu.Unlock()
+	})
+
+	if !slices.Contains(ListCodecs(), mt) {
+		t.Fatalf("ListCodecs() = %v, missing %s", ListCodecs(), mt)
+	}
+	if _, ok := CodecFor("Application/VND.test-upper+json; charset=utf-8"); !ok {
+		t.Error("CodecFor should ignore case and parameters")
+	}
+
+	h := NewAgentHandler(&mockAgent{id: "test", result: "pong"})
+	req := httptest.NewRequest(http.MethodPost, "/invoke", strings.NewReader(`{"input":"ping"}`))
+	req.Header.Set("Accept", mt)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if got := strings.TrimSpace(w.Body.String()); got != `{"RESULT":"PONG"}` {
+		t.Errorf("body = %s", got)
+	}
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConcurrencyStats reports the load on one agent path. It is passed to the
+// metrics hook installed with WithConcurrencyMetrics whenever the counts
+// change.
+type ConcurrencyStats struct {
+	// Path is the agent registration path.
+	Path string
+
+	// InFlight is the number of requests currently executing.
+	InFlight int
+
+	// Queued is the number of requests waiting for a slot.
+	Queued int
+
+	// Rejected is the total number of requests rejected with 429 since the
+	// agent was registered.
+	Rejected int64
+}
+
+// ConcurrencyOption configures WithConcurrencyLimit.
+type ConcurrencyOption func(*concurrencyConfig)
+
+type concurrencyConfig struct {
+	retryAfter time.Duration
+	onChange   func(ConcurrencyStats)
+}
+
+// WithRetryAfter sets the Retry-After duration advertised with 429
+// responses. It is rounded up to whole seconds. The default is 1 second.
+func WithRetryAfter(d time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		if d > 0 {
+			c.retryAfter = d
+		}
+	}
+}
+
+// WithConcurrencyMetrics installs a hook that receives the current counts
+// for an agent path every time a request starts, finishes, enters or leaves
+// the queue, or is rejected. The hook is called synchronously on the request
+// path and must be fast and safe for concurrent use.
+func WithConcurrencyMetrics(fn func(ConcurrencyStats)) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.onChange = fn
+	}
+}
+
+// WithConcurrencyLimit returns middleware that limits the number of
+// simultaneous requests to each registered agent path to n. Up to
+// queueDepth further requests wait for a slot; once the queue is full,
+// requests are rejected with 429 Too Many Requests and a Retry-After header.
+// Queued requests whose client disconnects leave the queue. Invoke, stream,
+// and every other endpoint of an agent share the same limit, and a stream
+// holds its slot until it ends.
+//
+// Limits apply per agent path; raw handlers registered via RegisterHandler
+// are not limited. A non-positive n disables limiting, and a negative
+// queueDepth is treated as zero.
+//
+//	s = server.ApplyMiddleware(s, server.WithConcurrencyLimit(8, 32,
+//	    server.WithConcurrencyMetrics(func(st server.ConcurrencyStats) {
+//	        slog.Debug("agent load", "path", st.Path, "in_flight", st.InFlight, "queued", st.Queued)
+//	    }),
+//	))
+func WithConcurrencyLimit(n, queueDepth int, opts ...ConcurrencyOption) Middleware {
+	cfg := concurrencyConfig{retryAfter: time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return AgentHandlerMiddleware(func(path string, next http.Handler) http.Handler {
+		if n <= 0 {
+			return next
+		}
+		l := &limiter{
+			path:       path,
+			slots:      make(chan struct{}, n),
+			queueDepth: max(queueDepth, 0),
+			cfg:        cfg,
+		}
+		return l.wrap(next)
+	})
+}
+
+// limiter enforces the concurrency limit for one agent path.
+type limiter struct {
+	path       string
+	slots      chan struct{}
+	queueDepth int
+	cfg        concurrencyConfig
+
+	mu       sync.Mutex
+	inFlight int
+	queued   int
+	rejected int64
+}
+
+func (l *limiter) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.acquire(r) {
+			if r.Context().Err() != nil {
+				// The client gave up while queued; nobody reads a response.
+				return
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int((l.cfg.retryAfter+time.Second-1)/time.Second)))
+			writeJSON(w, http.StatusTooManyRequests, InvokeResponse{Error: "too many concurrent requests"})
+			return
+		}
+		defer l.release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquire obtains a slot, queueing if necessary. It reports false when the
+// queue is full or the request context ends while queued.
+func (l *limiter) acquire(r *http.Request) bool {
+	select {
+	case l.slots <- struct{}{}:
+		l.update(func() { l.inFlight++ })
+		return true
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.queueDepth {
+		l.rejected++
+		l.mu.Unlock()
+		l.report()
+		return false
+	}
+	l.queued++
+	l.mu.Unlock()
+	l.report()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.update(func() { l.queued--; l.inFlight++ })
+		return true
+	case <-r.Context().Done():
+		l.update(func() { l.queued-- })
+		return false
+	}
+}
+
+// release frees a slot.
+func (l *limiter) release() {
+	<-l.slots
+	l.update(func() { l.inFlight-- })
+}
+
+// update applies fn under the lock and reports the new counts.
+func (l *limiter) update(fn func()) {
+	l.mu.Lock()
+	fn()
+	l.mu.Unlock()
+	l.report()
+}
+
+// report passes the current counts to the metrics hook.
+func (l *limiter) report() {
+	if l.cfg.onChange == nil {
+		return
+	}
+	l.cfg.onChange(l.stats())
+}
+
+// stats returns a snapshot of the current counts.
+func (l *limiter) stats() ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ConcurrencyStats{Path: l.path, InFlight: l.inFlight, Queued: l.queued, Rejected: l.rejected}
+}
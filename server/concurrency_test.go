@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedHandler blocks each request until release is closed.
+type gatedHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	g.started <- struct{}{}
+	<-g.release
+	w.WriteHeader(http.StatusOK)
+}
+
+func newLimitedHandler(t *testing.T, n, queue int, opts ...ConcurrencyOption) (http.Handler, *gatedHandler) {
+	t.Helper()
+	g := &gatedHandler{started: make(chan struct{}, 16), release: make(chan struct{})}
+	hs := WithConcurrencyLimit(n, queue, opts...)(&captureAdapter{}).(*handlerServer)
+	return hs.wrap("/agent", g), g
+}
+
+func TestWithConcurrencyLimit_QueueAndReject(t *testing.T) {
+	var mu sync.Mutex
+	var last ConcurrencyStats
+	h, g := newLimitedHandler(t, 1, 1,
+		WithRetryAfter(1500*time.Millisecond),
+		WithConcurrencyMetrics(func(st ConcurrencyStats) {
+			mu.Lock()
+			last = st
+			mu.Unlock()
+		}),
+	)
+	stats := func() ConcurrencyStats {
+		mu.Lock()
+		defer mu.Unlock()
+		return last
+	}
+
+	codes := make(chan int, 2)
+	serve := func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invoke", nil))
+		codes <- rec.Code
+	}
+
+	go serve() // takes the slot
+	<-g.started
+	go serve() // queued
+	waitFor(t, func() bool { return stats().Queued == 1 })
+
+	// Third request overflows the queue.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invoke", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("overflow status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if st := stats(); st.Rejected != 1 || st.InFlight != 1 || st.Queued != 1 || st.Path != "/agent" {
+		t.Errorf("stats = %+v", st)
+	}
+
+	close(g.release)
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("queued request status = %d, want 200", code)
+		}
+	}
+	if st := stats(); st.InFlight != 0 || st.Queued != 0 {
+		t.Errorf("final stats = %+v, want idle", st)
+	}
+}
+
+func TestWithConcurrencyLimit_QueuedClientGivesUp(t *testing.T) {
+	var mu sync.Mutex
+	var last ConcurrencyStats
+	h, g := newLimitedHandler(t, 1, 4, WithConcurrencyMetrics(func(st ConcurrencyStats) {
+		mu.Lock()
+		last = st
+		mu.Unlock()
+	}))
+	defer close(g.release)
+
+	go h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/invoke", nil))
+	<-g.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/invoke", nil).WithContext(ctx))
+		close(done)
+	}()
+	waitFor(t, func() bool { mu.Lock(); defer mu.Unlock(); return last.Queued == 1 })
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if last.Queued != 0 || last.Rejected != 0 {
+		t.Errorf("stats after cancel = %+v", last)
+	}
+}
+
+func TestWithConcurrencyLimit_AgentRoutesOnly(t *testing.T) {
+	base := NewStdlibAdapter(Config{})
+	a := newBlockingAgent()
+	s := ApplyMiddleware(base, WithConcurrencyLimit(1, 0))
+	if err := s.RegisterAgent("/agent", a); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	if err := s.RegisterHandler("/health", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})); err != nil {
+		t.Fatalf("RegisterHandler: %v", err)
+	}
+
+	// A stream holds the only slot.
+	streamDone := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/agent/stream", strings.NewReader(`{"input":"x"}`)).WithContext(ctx)
+		base.mux.ServeHTTP(httptest.NewRecorder(), req)
+		close(streamDone)
+	}()
+	<-a.started
+
+	rec := httptest.NewRecorder()
+	base.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/agent/invoke", strings.NewReader(`{"input":"x"}`)))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("invoke during stream: status = %d, want 429", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	base.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("raw handler: status = %d, want 200 (not limited)", rec.Code)
+	}
+
+	cancel()
+	<-streamDone
+}
+
+func TestWithConcurrencyLimit_Disabled(t *testing.T) {
+	h, g := newLimitedHandler(t, 0, 0)
+	close(g.release)
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invoke", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+	}
+}
+
+// waitFor polls cond until it is true or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
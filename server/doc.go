@@ -13,6 +13,19 @@
 //   - Serve(ctx, addr) — starts the server, blocks until done
 //   - Shutdown(ctx) — gracefully shuts down the server
 //
+// # Connection Draining
+//
+// Adapters implementing Drainer support zero-dropped-request rolling
+// deployments. Drain stops accepting new requests (answering 503 with
+// Retry-After), sends a final "server_closing" SSE event telling active
+// streams to reconnect, waits for in-flight invocations up to the context
+// deadline, and then shuts down. The Drain function falls back to Shutdown
+// for adapters that do not implement Drainer:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	err := server.Drain(ctx, adapter)
+//
 // # Registry Pattern
 //
 // Adapters register themselves via init() using the standard Beluga registry
@@ -30,17 +43,70 @@
 //
 // # Agent Handler
 //
-// NewAgentHandler creates an http.Handler that exposes an agent via two
+// NewAgentHandler creates an http.Handler that exposes an agent via three
 // sub-paths:
 //
 //   - POST {prefix}/invoke — synchronous invocation returning JSON
 //   - POST {prefix}/stream — SSE stream of agent events
+//   - POST {prefix}/batch — several invocations in one request
+//
+// /invoke negotiates its encoding: the request body is decoded according to
+// Content-Type and the response encoded according to Accept. JSON is the
+// default; MessagePack (application/msgpack) and protobuf
+// (application/x-protobuf) are built in, and RegisterCodec adds further
+// formats.
+//
+// A batch takes a BatchRequest and runs its items through the same path as
+// /invoke, at most WithBatchConcurrency at a time. Results come back in
+// input order with per-item errors; setting fail_fast aborts the batch at
+// the first failure.
+//
+// WithWebSocket additionally exposes GET {prefix}/ws, a WebSocket endpoint
+// for clients that cannot use SSE or need a long-lived bidirectional
+// connection. Clients send InvokeRequest JSON messages and receive
+// StreamEvent JSON messages, each run ending with a "done" or "error"
+// event; closing the socket cancels the run in progress:
+//
+//	h := server.NewAgentHandler(myAgent, server.WithWebSocket())
+//	adapter.RegisterHandler("/chat/", http.StripPrefix("/chat", h))
+//
+// WithAgentHandlerOptions passes handler options to the agents registered
+// through RegisterAgent instead, with any adapter that serves them with
+// NewAgentHandler. The gRPC adapter rejects WebSocket and stream resumption,
+// and the Fiber adapter rejects WebSocket:
+//
+//	s = server.ApplyMiddleware(s, server.WithAgentHandlerOptions(server.WithWebSocket()))
+//	err := s.RegisterAgent("/chat", myAgent)
+//
+// # OpenAPI
+//
+// GenerateOpenAPI emits an OpenAPI 3.1 document describing the invoke,
+// batch and stream endpoints, and the WebSocket endpoint where enabled, of
+// every agent registered with an adapter that implements AgentLister
+// (StdlibAdapter and the HTTP adapters under server/adapters, but not gRPC),
+// looking through middleware. Each operation also lists the 401, 403, 429
+// and 503 responses middleware can send. Serving it is opt-in:
+//
+//	adapter.RegisterHandler("/openapi.json", server.OpenAPIHandler(adapter))
 //
 // # SSE Support
 //
 // The package provides SSEWriter for writing Server-Sent Events. It handles
 // event formatting, multi-line data per the SSE specification, reconnection
-// hints, and keep-alive heartbeats.
+// hints, and keep-alive heartbeats. EnableAutoID numbers events that carry
+// no ID of their own.
+//
+// WithStreamResumption makes an agent's stream endpoint resumable: the run
+// continues when the client disconnects, and a client that reconnects with
+// Last-Event-ID receives the buffered events after that ID. Retention is
+// bounded by WithReplayBuffer and WithReplayTTL; resuming from an evicted
+// event or an expired session answers 410 Gone.
+//
+//	h := server.NewAgentHandler(myAgent, server.WithStreamResumption(server.WithReplayBuffer(256)))
+//
+// Through RegisterAgent, enable it with WithAgentHandlerOptions:
+//
+//	s = server.ApplyMiddleware(s, server.WithAgentHandlerOptions(server.WithStreamResumption()))
 //
 // # Middleware and Hooks
 //
@@ -49,15 +115,41 @@
 // callbacks (BeforeRequest, AfterRequest, OnError) that are composable via
 // ComposeHooks.
 //
+// HandlerMiddleware builds request-level middleware that applies to every
+// adapter: it wraps raw handlers and the agent handlers built by
+// NewAgentHandler. Adapters that do not serve HTTP, such as gRPC, run each
+// call through the same wrappers via UnwrapAgent. WithAuthPolicy uses it to authorize each request against
+// an auth.Policy, answering 403 on deny before the agent runs:
+//
+//	s = server.ApplyMiddleware(s, server.WithAuthPolicy(policy, extract, auditHooks))
+//
+// WithAccessLog logs one entry per request with its method, path, status,
+// duration, and trace IDs, optionally with truncated and redacted bodies.
+// Streams are logged when they end, SSE responses with their event count.
+//
+// AgentHandlerMiddleware is the agent-only variant. WithConcurrencyLimit
+// uses it to cap simultaneous requests per agent path, queueing a bounded
+// number of requests and answering 429 with Retry-After beyond that.
+//
 // # Key Types
 //
 //   - ServerAdapter — interface for HTTP framework adapters
 //   - Config — adapter configuration (timeouts, extras)
 //   - Factory — creates a ServerAdapter from Config
 //   - StdlibAdapter — built-in net/http implementation
+//   - Drainer / Drain — connection draining for graceful restarts
 //   - Middleware — wraps a ServerAdapter to add behavior
+//   - HandlerMiddleware — request-level middleware for all adapters
+//   - WithAgentHandlerOptions — NewAgentHandler options for RegisterAgent
+//   - WithAuthPolicy — authorization middleware backed by auth.Policy
+//   - WithConcurrencyLimit — per-agent concurrency limiting and queueing
+//   - WithAccessLog — request logging with optional body capture
 //   - Hooks — optional lifecycle callbacks for request processing
 //   - SSEWriter / SSEEvent — Server-Sent Events support
+//   - WithStreamResumption — resumable SSE streams via Last-Event-ID
 //   - NewAgentHandler — creates HTTP handler for an agent
+//   - GenerateOpenAPI / OpenAPIHandler — OpenAPI 3.1 spec for registered agents
 //   - InvokeRequest / InvokeResponse / StreamEvent — request/response types
+//   - BatchRequest / BatchResponse — batch request/response types
+//   - Codec / RegisterCodec — pluggable /invoke encodings
 package server
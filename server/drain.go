@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// EventServerClosing is the SSE event type sent to active streams when the
+// server starts draining. Clients should reconnect, ideally to another
+// instance.
+const EventServerClosing = "server_closing"
+
+// drainRetryMillis is the reconnection hint sent with the closing event.
+const drainRetryMillis = 1000
+
+// Drainer is implemented by adapters that support connection draining.
+// Drain stops accepting new requests, sends a final EventServerClosing event
+// to every active SSE stream, waits for in-flight requests to complete
+// within the context deadline, and then shuts the server down.
+//
+// StdlibAdapter implements Drainer. Adapters that do not (Gin, Fiber, Echo,
+// and the other framework adapters) are expected to behave as if Drain were
+// Shutdown: their underlying servers stop accepting connections and wait for
+// in-flight requests, but active streams are cut without a closing event.
+// Use the Drain function to drain any adapter with that fallback.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// Drain drains s if it implements Drainer and otherwise falls back to
+// s.Shutdown. It is the recommended way to stop a server during rolling
+// deployments.
+func Drain(ctx context.Context, s ServerAdapter) error {
+	if d, ok := s.(Drainer); ok {
+		return d.Drain(ctx)
+	}
+	return s.Shutdown(ctx)
+}
+
+// drainKey is the context key under which the drain signal is stored.
+type drainKey struct{}
+
+// drainSignal returns a channel that is closed when the server starts
+// draining, or nil when the request is not served by a draining-aware
+// adapter.
+func drainSignal(ctx context.Context) <-chan struct{} {
+	ch, _ := ctx.Value(drainKey{}).(chan struct{})
+	return ch
+}
+
+// drainState tracks in-flight requests and the draining flag for an adapter.
+// The zero value is ready to use.
+type drainState struct {
+	mu       sync.Mutex
+	draining bool
+	closing  chan struct{}
+	inflight sync.WaitGroup
+}
+
+// signal returns the channel closed when draining begins.
+func (d *drainState) signal() chan struct{} {
+	if d.closing == nil {
+		d.closing = make(chan struct{})
+	}
+	return d.closing
+}
+
+// wrap returns a handler that rejects requests with 503 once draining has
+// begun and otherwise tracks them as in flight.
+func (d *drainState) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusServiceUnavailable, InvokeResponse{Error: "server is draining"})
+			return
+		}
+		d.inflight.Add(1)
+		closing := d.signal()
+		d.mu.Unlock()
+		defer d.inflight.Done()
+
+		ctx := context.WithValue(r.Context(), drainKey{}, closing)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// begin marks the state as draining and signals active streams. It is
+// idempotent.
+func (d *drainState) begin() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return
+	}
+	d.draining = true
+	close(d.signal())
+}
+
+// wait blocks until every in-flight request has completed or ctx is done.
+func (d *drainState) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain stops accepting new requests, sends an EventServerClosing event to
+// active SSE streams, waits for in-flight requests up to the context
+// deadline, and then shuts the server down. Requests that arrive while
+// draining receive 503 Service Unavailable with a Retry-After header.
+func (s *StdlibAdapter) Drain(ctx context.Context) error {
+	s.drain.begin()
+	waitErr := s.drain.wait(ctx)
+	if err := s.lc.Shutdown(ctx, "server/drain"); err != nil {
+		return err
+	}
+	if waitErr != nil {
+		return core.Errorf(core.ErrTimeout, "server/drain: in-flight requests did not complete: %w", waitErr)
+	}
+	return nil
+}
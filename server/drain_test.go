@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// blockingAgent blocks Invoke until release is closed and streams one event
+// before blocking until its context is canceled.
+type blockingAgent struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAgent) ID() string              { return "blocking" }
+func (a *blockingAgent) Persona() agent.Persona  { return agent.Persona{} }
+func (a *blockingAgent) Tools() []tool.Tool      { return nil }
+func (a *blockingAgent) Children() []agent.Agent { return nil }
+
+func (a *blockingAgent) Invoke(ctx context.Context, _ string, _ ...agent.Option) (string, error) {
+	a.started <- struct{}{}
+	<-a.release
+	return "finished", nil
+}
+
+func (a *blockingAgent) Stream(ctx context.Context, _ string, _ ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		if !yield(agent.Event{Type: agent.EventText, Text: "partial"}, nil) {
+			return
+		}
+		a.started <- struct{}{}
+		<-ctx.Done()
+		yield(agent.Event{}, ctx.Err())
+	}
+}
+
+func newBlockingAgent() *blockingAgent {
+	return &blockingAgent{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func TestDrainState_RejectsNewRequests(t *testing.T) {
+	var d drainState
+	h := d.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("before drain: status = %d, want 200", rec.Code)
+	}
+
+	d.begin()
+	d.begin() // idempotent
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("after drain: status = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
+
+func TestDrainState_StreamReceivesClosingEvent(t *testing.T) {
+	var d drainState
+	a := newBlockingAgent()
+	srv := httptest.NewServer(d.wrap(NewAgentHandler(a)))
+	defer srv.Close()
+
+	bodyCh := make(chan string, 1)
+	go func() {
+		resp, err := http.Post(srv.URL+"/stream", "application/json", strings.NewReader(`{"input":"hi"}`))
+		if err != nil {
+			bodyCh <- "error: " + err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		bodyCh <- string(b)
+	}()
+
+	<-a.started
+	d.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := d.wait(ctx); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	body := <-bodyCh
+	if !strings.Contains(body, "event: text") {
+		t.Errorf("expected the streamed event before closing, got %q", body)
+	}
+	if !strings.Contains(body, "event: "+EventServerClosing) {
+		t.Errorf("expected closing event, got %q", body)
+	}
+	if !strings.Contains(body, "retry: ") {
+		t.Errorf("expected retry hint, got %q", body)
+	}
+	if strings.Contains(body, "event: done") || strings.Contains(body, "event: error") {
+		t.Errorf("closing stream should not send done or error, got %q", body)
+	}
+}
+
+func TestStdlibAdapter_DrainWaitsForInFlight(t *testing.T) {
+	adapter := NewStdlibAdapter(Config{})
+	a := newBlockingAgent()
+	if err := adapter.RegisterAgent("/agent", a); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to get random port: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	go adapter.Serve(context.Background(), addr)
+	time.Sleep(100 * time.Millisecond)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := client.Post("http://"+addr+"/agent/invoke", "application/json", strings.NewReader(`{"input":"hi"}`))
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		resCh <- result{status: resp.StatusCode, body: string(b)}
+	}()
+	select {
+	case <-a.started:
+	case res := <-resCh:
+		t.Fatalf("invoke returned before drain: %+v", res)
+	}
+
+	drainErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		drainErr <- adapter.Drain(ctx)
+	}()
+
+	// Wait until the adapter is draining, then verify new requests are
+	// rejected while the in-flight invoke is still running.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		adapter.drain.mu.Lock()
+		draining := adapter.drain.draining
+		adapter.drain.mu.Unlock()
+		if draining || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	resp, err := client.Post("http://"+addr+"/agent/invoke", "application/json", strings.NewReader(`{"input":"late"}`))
+	if err != nil {
+		t.Fatalf("late request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("late request status = %d, want 503", resp.StatusCode)
+	}
+
+	select {
+	case err := <-drainErr:
+		t.Fatalf("Drain returned before in-flight request completed: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(a.release)
+	res := <-resCh
+	if res.err != nil || res.status != http.StatusOK || !strings.Contains(res.body, "finished") {
+		t.Fatalf("in-flight invoke = %+v, want 200 with result", res)
+	}
+	if err := <-drainErr; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+}
+
+func TestStdlibAdapter_DrainTimeout(t *testing.T) {
+	adapter := NewStdlibAdapter(Config{})
+	adapter.drain.inflight.Add(1)
+	defer adapter.drain.inflight.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := adapter.Drain(ctx)
+	var ce *core.Error
+	if !errors.As(err, &ce) || ce.Code != core.ErrTimeout {
+		t.Fatalf("Drain error = %v, want core timeout error", err)
+	}
+}
+
+// shutdownOnlyAdapter records Shutdown calls and does not implement Drainer.
+type shutdownOnlyAdapter struct {
+	tracingTestAdapter
+	shutdowns int
+}
+
+func (s *shutdownOnlyAdapter) Shutdown(ctx context.Context) error {
+	s.shutdowns++
+	return nil
+}
+
+func TestDrain_FallsBackToShutdown(t *testing.T) {
+	s := &shutdownOnlyAdapter{}
+	if err := Drain(context.Background(), s); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if s.shutdowns != 1 {
+		t.Errorf("shutdowns = %d, want 1", s.shutdowns)
+	}
+
+	traced := ApplyMiddleware(s, WithTracing())
+	if err := Drain(context.Background(), traced); err != nil {
+		t.Fatalf("Drain through middleware: %v", err)
+	}
+	if s.shutdowns != 2 {
+		t.Errorf("shutdowns = %d, want 2", s.shutdowns)
+	}
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/lookatitude/beluga-ai/v2/agent"
@@ -27,42 +29,117 @@ type StreamEvent struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// handlerConfig holds the options for NewAgentHandler.
+type handlerConfig struct {
+	resume *resumeStore
+
+	batchConcurrency int
+	batchMaxItems    int
+
+	websocket        bool
+	wsOriginPatterns []string
+}
+
+// HandlerOption configures the handler returned by NewAgentHandler.
+type HandlerOption func(*handlerConfig)
+
+// WithWebSocket exposes GET {prefix}/ws, which upgrades to a WebSocket. The
+// client sends InvokeRequest JSON messages; for each one the handler streams
+// StreamEvent JSON messages back, terminated by a "done" or "error" event.
+// Closing the socket cancels the run in progress.
+//
+// Cross-origin upgrades are rejected unless the Origin host matches one of
+// originPatterns (path.Match syntax, e.g. "*.example.com").
+func WithWebSocket(originPatterns ...string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.websocket = true
+		c.wsOriginPatterns = append(c.wsOriginPatterns, originPatterns...)
+	}
+}
+
 // NewAgentHandler creates an http.Handler that exposes an agent via HTTP.
-// It supports two sub-paths:
+// It supports three sub-paths:
 //   - POST {prefix}/invoke — synchronous invocation, returns JSON
 //   - POST {prefix}/stream — SSE stream of agent events
-func NewAgentHandler(a agent.Agent) http.Handler {
+//   - POST {prefix}/batch — several synchronous invocations, returns JSON
+//
+// Options enable additional sub-paths, such as {prefix}/ws via
+// WithWebSocket. Options attached to a by WithAgentHandlerOptions are
+// applied before opts.
+func NewAgentHandler(a agent.Agent, opts ...HandlerOption) http.Handler {
+	a, wraps, agentOpts := unwrapAgent(a)
+	cfg := handlerConfig{
+		batchConcurrency: defaultBatchConcurrency,
+		batchMaxItems:    defaultBatchMaxItems,
+	}
+	for _, opt := range append(agentOpts, opts...) {
+		opt(&cfg)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /invoke", func(w http.ResponseWriter, r *http.Request) {
 		handleInvoke(w, r, a)
 	})
 	mux.HandleFunc("POST /stream", func(w http.ResponseWriter, r *http.Request) {
-		handleStream(w, r, a)
+		handleStream(w, r, a, &cfg)
+	})
+	mux.HandleFunc("POST /batch", func(w http.ResponseWriter, r *http.Request) {
+		handleBatch(w, r, a, &cfg)
 	})
-	return mux
+	if cfg.websocket {
+		mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+			handleWebSocket(w, r, a, cfg.wsOriginPatterns)
+		})
+	}
+
+	// Apply wrappers installed by HandlerMiddleware.
+	var h http.Handler = mux
+	for _, wrap := range wraps {
+		h = wrap(h)
+	}
+	return h
 }
 
+// handleInvoke serves {prefix}/invoke. The request body is decoded with the
+// codec registered for its Content-Type and the response encoded with the
+// codec negotiated from Accept; both default to JSON.
 func handleInvoke(w http.ResponseWriter, r *http.Request, a agent.Agent) {
+	codec, mediaType := responseCodec(r)
+	w.Header().Add("Vary", "Accept")
+
 	var req InvokeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, InvokeResponse{
+	if err := readCodec(r, requestCodec(r), &req); err != nil {
+		writeCodec(w, codec, mediaType, http.StatusBadRequest, InvokeResponse{
 			Error: fmt.Sprintf("invalid request body: %v", err),
 		})
 		return
 	}
 
-	result, err := a.Invoke(r.Context(), req.Input)
+	resp, err := invoke(r.Context(), a, req)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, InvokeResponse{
-			Error: err.Error(),
-		})
+		writeCodec(w, codec, mediaType, http.StatusInternalServerError, resp)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, InvokeResponse{Result: result})
+	writeCodec(w, codec, mediaType, http.StatusOK, resp)
+}
+
+// invoke runs one synchronous invocation. On failure the returned response
+// carries the error message. It backs both /invoke and /batch.
+func invoke(ctx context.Context, a agent.Agent, req InvokeRequest) (InvokeResponse, error) {
+	result, err := a.Invoke(ctx, req.Input)
+	if err != nil {
+		return InvokeResponse{Error: err.Error()}, err
+	}
+	return InvokeResponse{Result: result}, nil
 }
 
-func handleStream(w http.ResponseWriter, r *http.Request, a agent.Agent) {
+func handleStream(w http.ResponseWriter, r *http.Request, a agent.Agent, cfg *handlerConfig) {
+	if cfg.resume != nil {
+		cfg.resume.serve(w, r, a)
+		return
+	}
+
 	var req InvokeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, InvokeResponse{
@@ -78,33 +155,95 @@ func handleStream(w http.ResponseWriter, r *http.Request, a agent.Agent) {
 		})
 		return
 	}
+	sw.EnableAutoID("")
 
-	for event, err := range a.Stream(r.Context(), req.Input) {
-		if err != nil {
-			errData, _ := json.Marshal(StreamEvent{Type: "error", Text: err.Error()})
-			_ = sw.WriteEvent(SSEEvent{Event: "error", Data: string(errData)})
-			return
-		}
+	ctx, cancel := drainableContext(r.Context())
+	defer cancel()
+	closing := drainSignal(ctx)
 
-		se := StreamEvent{
-			Type:     string(event.Type),
-			Text:     event.Text,
-			AgentID:  event.AgentID,
-			Metadata: event.Metadata,
-		}
-		data, _ := json.Marshal(se)
-		eventType := string(event.Type)
-		if eventType == "" {
-			eventType = "message"
+	for ev := range sseEvents(ctx, a, req.Input) {
+		if isClosing(closing) {
+			writeClosingEvent(sw)
+			return
 		}
-		if writeErr := sw.WriteEvent(SSEEvent{Event: eventType, Data: string(data)}); writeErr != nil {
+		if writeErr := sw.WriteEvent(ev); writeErr != nil {
 			return
 		}
 	}
+}
+
+// sseEvents runs the agent's stream and yields its events in SSE form,
+// followed by a final "done" event, or an "error" event if the stream
+// fails.
+func sseEvents(ctx context.Context, a agent.Agent, input string) iter.Seq[SSEEvent] {
+	return func(yield func(SSEEvent) bool) {
+		for event, err := range a.Stream(ctx, input) {
+			if err != nil {
+				errData, _ := json.Marshal(StreamEvent{Type: "error", Text: err.Error()})
+				yield(SSEEvent{Event: "error", Data: string(errData)})
+				return
+			}
+
+			data, _ := json.Marshal(toStreamEvent(event))
+			eventType := string(event.Type)
+			if eventType == "" {
+				eventType = "message"
+			}
+			if !yield(SSEEvent{Event: eventType, Data: string(data)}) {
+				return
+			}
+		}
+
+		// Send a done event to signal end of stream.
+		doneData, _ := json.Marshal(StreamEvent{Type: "done"})
+		yield(SSEEvent{Event: "done", Data: string(doneData)})
+	}
+}
+
+// drainableContext returns a context derived from parent that is also
+// canceled when the server starts draining, so that runs end promptly.
+func drainableContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	if closing := drainSignal(parent); closing != nil {
+		go func() {
+			select {
+			case <-closing:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
+// toStreamEvent converts an agent event to its wire format.
+func toStreamEvent(event agent.Event) StreamEvent {
+	return StreamEvent{
+		Type:     string(event.Type),
+		Text:     event.Text,
+		AgentID:  event.AgentID,
+		Metadata: event.Metadata,
+	}
+}
+
+// isClosing reports whether the drain signal has fired.
+func isClosing(closing <-chan struct{}) bool {
+	if closing == nil {
+		return false
+	}
+	select {
+	case <-closing:
+		return true
+	default:
+		return false
+	}
+}
 
-	// Send a done event to signal end of stream.
-	doneData, _ := json.Marshal(StreamEvent{Type: "done"})
-	_ = sw.WriteEvent(SSEEvent{Event: "done", Data: string(doneData)})
+// writeClosingEvent tells the client that the server is draining and that it
+// should reconnect.
+func writeClosingEvent(sw *SSEWriter) {
+	data, _ := json.Marshal(StreamEvent{Type: EventServerClosing, Text: "server closing, reconnect"})
+	_ = sw.WriteEvent(SSEEvent{Event: EventServerClosing, Data: string(data), Retry: drainRetryMillis})
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
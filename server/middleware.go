@@ -1,5 +1,13 @@
 package server
 
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
 // Middleware wraps a ServerAdapter to add cross-cutting behaviour.
 // Middlewares are composed via ApplyMiddleware and applied outside-in
 // (the last middleware in the list is the outermost wrapper).
@@ -13,3 +21,173 @@ func ApplyMiddleware(s ServerAdapter, mws ...Middleware) ServerAdapter {
 	}
 	return s
 }
+
+// HandlerMiddleware returns a Middleware that wraps the HTTP handlers an
+// adapter serves: handlers passed to RegisterHandler, and the agent handlers
+// built by NewAgentHandler for agents passed to RegisterAgent. wrap receives
+// the registration path and the handler to wrap. Because every HTTP adapter
+// builds its agent routes with NewAgentHandler, and the gRPC adapter runs
+// its calls through the same wrappers (see UnwrapAgent), request-level
+// middleware written with HandlerMiddleware works with all of them.
+//
+// Handler wrappers nest in registration order: when several are combined via
+// ApplyMiddleware, the wrapper of the first middleware sits closest to the
+// handler and the wrapper of the last one sees requests first.
+func HandlerMiddleware(wrap func(path string, next http.Handler) http.Handler) Middleware {
+	return func(next ServerAdapter) ServerAdapter {
+		return &handlerServer{next: next, wrap: wrap}
+	}
+}
+
+// AgentHandlerMiddleware is like HandlerMiddleware but wraps only the agent
+// handlers registered via RegisterAgent; raw handlers such as health checks
+// are registered unchanged.
+func AgentHandlerMiddleware(wrap func(path string, next http.Handler) http.Handler) Middleware {
+	return func(next ServerAdapter) ServerAdapter {
+		return &handlerServer{next: next, wrap: wrap, agentsOnly: true}
+	}
+}
+
+// WithAgentHandlerOptions returns a Middleware that builds the handler of
+// every agent registered through RegisterAgent with opts, so that options
+// such as WithWebSocket and WithStreamResumption reach the agent routes of
+// any adapter that serves them with NewAgentHandler:
+//
+//	s = server.ApplyMiddleware(s, server.WithAgentHandlerOptions(server.WithWebSocket()))
+//
+// Adapters that cannot honour handler options reject agents registered with
+// them: the gRPC adapter, which does not serve HTTP, and the Fiber adapter
+// for WithWebSocket, as it cannot hand over the connection.
+func WithAgentHandlerOptions(opts ...HandlerOption) Middleware {
+	return func(next ServerAdapter) ServerAdapter {
+		return &handlerServer{next: next, opts: opts, agentsOnly: true}
+	}
+}
+
+// handlerServer wraps the handlers registered with the next adapter.
+type handlerServer struct {
+	next       ServerAdapter
+	wrap       func(path string, next http.Handler) http.Handler
+	opts       []HandlerOption
+	agentsOnly bool
+}
+
+func (s *handlerServer) RegisterAgent(path string, a agent.Agent) error {
+	if a == nil {
+		return s.next.RegisterAgent(path, nil)
+	}
+	w := &wrappedAgent{Agent: a, opts: s.opts}
+	if s.wrap != nil {
+		w.wrap = func(h http.Handler) http.Handler {
+			return s.wrap(path, h)
+		}
+	}
+	return s.next.RegisterAgent(path, w)
+}
+
+func (s *handlerServer) RegisterHandler(path string, handler http.Handler) error {
+	if handler == nil || s.agentsOnly || s.wrap == nil {
+		return s.next.RegisterHandler(path, handler)
+	}
+	return s.next.RegisterHandler(path, s.wrap(path, handler))
+}
+
+func (s *handlerServer) Serve(ctx context.Context, addr string) error {
+	return s.next.Serve(ctx, addr)
+}
+
+func (s *handlerServer) Shutdown(ctx context.Context) error {
+	return s.next.Shutdown(ctx)
+}
+
+func (s *handlerServer) Drain(ctx context.Context) error {
+	return Drain(ctx, s.next)
+}
+
+func (s *handlerServer) Unwrap() ServerAdapter {
+	return s.next
+}
+
+// wrappedAgent carries a handler wrapper from a HandlerMiddleware, or the
+// options of WithAgentHandlerOptions, to NewAgentHandler or to an adapter
+// that applies them itself through UnwrapAgent. It behaves exactly like the
+// embedded agent otherwise.
+type wrappedAgent struct {
+	agent.Agent
+	wrap func(http.Handler) http.Handler
+	opts []HandlerOption
+}
+
+// UnwrapAgent returns the agent passed to RegisterAgent before any
+// HandlerMiddleware wrapped it, together with a function that applies the
+// handler wrappers of those middlewares, or nil when there are none.
+//
+// Adapters that serve agents with NewAgentHandler get the wrappers applied
+// for them. Adapters that do not serve HTTP, such as gRPC, must call
+// UnwrapAgent in RegisterAgent and run every call through a handler built
+// once with the returned function; otherwise middleware such as
+// WithAuthPolicy, WithConcurrencyLimit, and WithAccessLog is silently
+// bypassed. A call is allowed only if the wrapped handler is reached.
+func UnwrapAgent(a agent.Agent) (agent.Agent, func(http.Handler) http.Handler) {
+	a, wraps, _ := unwrapAgent(a)
+	if len(wraps) == 0 {
+		return a, nil
+	}
+	return a, func(h http.Handler) http.Handler {
+		for _, wrap := range wraps {
+			h = wrap(h)
+		}
+		return h
+	}
+}
+
+// AgentHandlerConfig reports the sub-paths the handler NewAgentHandler builds
+// for an agent passed to RegisterAgent would serve beyond invoke, stream and
+// batch.
+type AgentHandlerConfig struct {
+	// WebSocket is set when the handler serves {prefix}/ws.
+	WebSocket bool
+
+	// StreamResumption is set when {prefix}/stream resumes from
+	// Last-Event-ID.
+	StreamResumption bool
+}
+
+// HandlerConfigOf returns the AgentHandlerConfig that the handler options
+// attached by WithAgentHandlerOptions to an agent passed to RegisterAgent
+// produce. NewAgentHandler applies the options itself; adapters that serve
+// agents otherwise use it to reject options they cannot honour.
+func HandlerConfigOf(a agent.Agent) AgentHandlerConfig {
+	_, _, opts := unwrapAgent(a)
+	var cfg handlerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return AgentHandlerConfig{WebSocket: cfg.websocket, StreamResumption: cfg.resume != nil}
+}
+
+// unwrapAgent returns the innermost agent together with the handler wrappers
+// and handler options attached to it, innermost first.
+func unwrapAgent(a agent.Agent) (agent.Agent, []func(http.Handler) http.Handler, []HandlerOption) {
+	var wraps []func(http.Handler) http.Handler
+	var opts []HandlerOption
+	for {
+		w, ok := a.(*wrappedAgent)
+		if !ok {
+			break
+		}
+		if w.wrap != nil {
+			wraps = append(wraps, w.wrap)
+		}
+		opts = append(slices.Clone(w.opts), opts...)
+		a = w.Agent
+	}
+	slices.Reverse(wraps)
+	return a, wraps, opts
+}
+
+// Ensure handlerServer implements ServerAdapter and Drainer at compile time.
+var (
+	_ ServerAdapter = (*handlerServer)(nil)
+	_ Drainer       = (*handlerServer)(nil)
+)
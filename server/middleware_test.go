@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/lookatitude/beluga-ai/v2/agent"
@@ -84,3 +86,107 @@ func TestApplyMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlerMiddleware(t *testing.T) {
+	tag := func(name string, order *[]string) Middleware {
+		return HandlerMiddleware(func(path string, next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				*order = append(*order, name+"@"+path)
+				next.ServeHTTP(w, r)
+			})
+		})
+	}
+
+	t.Run("agent and raw handlers nest in the same order", func(t *testing.T) {
+		var order []string
+		base := NewStdlibAdapter(Config{})
+		s := ApplyMiddleware(base, tag("mw1", &order), tag("mw2", &order))
+
+		if err := s.RegisterAgent("/agent", &mockAgent{id: "a", result: "ok"}); err != nil {
+			t.Fatalf("RegisterAgent: %v", err)
+		}
+		if err := s.RegisterHandler("/raw", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})); err != nil {
+			t.Fatalf("RegisterHandler: %v", err)
+		}
+
+		rec := httptest.NewRecorder()
+		base.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/agent/invoke", strings.NewReader(`{"input":"x"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("agent status = %d", rec.Code)
+		}
+		base.mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/raw", nil))
+
+		want := []string{"mw2@/agent", "mw1@/agent", "mw2@/raw", "mw1@/raw"}
+		if strings.Join(order, ",") != strings.Join(want, ",") {
+			t.Errorf("order = %v, want %v", order, want)
+		}
+	})
+
+	t.Run("wrapped agent still behaves like the agent", func(t *testing.T) {
+		var registered agent.Agent
+		capture := &captureAdapter{onAgent: func(a agent.Agent) { registered = a }}
+		var order []string
+		s := ApplyMiddleware(capture, tag("mw", &order))
+		if err := s.RegisterAgent("/a", &mockAgent{id: "inner", result: "r"}); err != nil {
+			t.Fatalf("RegisterAgent: %v", err)
+		}
+		if registered.ID() != "inner" {
+			t.Errorf("ID = %q, want inner", registered.ID())
+		}
+		out, err := registered.Invoke(context.Background(), "x")
+		if err != nil || out != "r" {
+			t.Errorf("Invoke = %q, %v", out, err)
+		}
+	})
+
+	t.Run("UnwrapAgent exposes the wrappers to non-HTTP adapters", func(t *testing.T) {
+		var registered agent.Agent
+		capture := &captureAdapter{onAgent: func(a agent.Agent) { registered = a }}
+		var order []string
+		s := ApplyMiddleware(capture, tag("mw1", &order), tag("mw2", &order))
+		if err := s.RegisterAgent("/a", &mockAgent{id: "inner", result: "r"}); err != nil {
+			t.Fatalf("RegisterAgent: %v", err)
+		}
+
+		inner, wrap := UnwrapAgent(registered)
+		if _, ok := inner.(*mockAgent); !ok {
+			t.Fatalf("inner = %T, want *mockAgent", inner)
+		}
+		if wrap == nil {
+			t.Fatal("wrap = nil, want the middleware wrappers")
+		}
+		wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			order = append(order, "call")
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/invoke", nil))
+		want := []string{"mw2@/a", "mw1@/a", "call"}
+		if strings.Join(order, ",") != strings.Join(want, ",") {
+			t.Errorf("order = %v, want %v", order, want)
+		}
+
+		if _, wrap := UnwrapAgent(inner); wrap != nil {
+			t.Error("wrap != nil for an unwrapped agent")
+		}
+	})
+
+	t.Run("nil values are rejected by the inner adapter", func(t *testing.T) {
+		var order []string
+		s := ApplyMiddleware(NewStdlibAdapter(Config{}), tag("mw", &order))
+		if s.RegisterAgent("/a", nil) == nil {
+			t.Error("expected error for nil agent")
+		}
+		if s.RegisterHandler("/h", nil) == nil {
+			t.Error("expected error for nil handler")
+		}
+	})
+}
+
+// captureAdapter records registered agents.
+type captureAdapter struct {
+	tracingTestAdapter
+	onAgent func(agent.Agent)
+}
+
+func (c *captureAdapter) RegisterAgent(_ string, a agent.Agent) error {
+	c.onAgent(a)
+	return nil
+}
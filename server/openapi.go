@@ -0,0 +1,359 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// AgentLister is implemented by adapters that can report the agents
+// registered with them. StdlibAdapter and the HTTP adapters under
+// server/adapters implement it; the gRPC adapter does not, as it serves no
+// HTTP routes. GenerateOpenAPI relies on it to discover agent routes.
+type AgentLister interface {
+	// Agents returns the registered agents keyed by path prefix.
+	Agents() map[string]agent.Agent
+}
+
+// listAgents returns the agents registered with s, looking through
+// middleware wrappers that expose Unwrap. The agents are returned as
+// registered, with any handler options attached by middleware.
+func listAgents(s ServerAdapter) (map[string]agent.Agent, bool) {
+	for s != nil {
+		if l, ok := s.(AgentLister); ok {
+			return l.Agents(), true
+		}
+		u, ok := s.(interface{ Unwrap() ServerAdapter })
+		if !ok {
+			break
+		}
+		s = u.Unwrap()
+	}
+	return nil, false
+}
+
+// OpenAPIOption configures GenerateOpenAPI.
+type OpenAPIOption func(*openAPIConfig)
+
+type openAPIConfig struct {
+	title       string
+	version     string
+	description string
+	servers     []string
+}
+
+// WithOpenAPIInfo sets the document title and version. The defaults are
+// "Beluga AI" and "1.0.0".
+func WithOpenAPIInfo(title, version string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		if title != "" {
+			c.title = title
+		}
+		if version != "" {
+			c.version = version
+		}
+	}
+}
+
+// WithOpenAPIDescription sets the document description.
+func WithOpenAPIDescription(description string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.description = description
+	}
+}
+
+// WithOpenAPIServers lists the base URLs at which the API is served.
+func WithOpenAPIServers(urls ...string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.servers = append(c.servers, urls...)
+	}
+}
+
+// GenerateOpenAPI returns an OpenAPI 3.1 JSON document describing the invoke,
+// batch, and stream endpoints of every agent registered with adapter, and
+// its WebSocket endpoint when enabled through WithAgentHandlerOptions,
+// including the request, response, and StreamEvent schemas. Every operation
+// lists the error responses the server middleware can produce: 401, 403
+// (WithAuthPolicy), 429 (WithConcurrencyLimit) and 503 (draining). The
+// adapter, or an adapter it wraps, must implement AgentLister, so the gRPC
+// adapter, which serves its agents through RPCs, is rejected.
+func GenerateOpenAPI(adapter ServerAdapter, opts ...OpenAPIOption) ([]byte, error) {
+	agents, ok := listAgents(adapter)
+	if !ok {
+		return nil, core.Errorf(core.ErrInvalidInput, "server/openapi: adapter %T does not list its registered agents", adapter)
+	}
+
+	cfg := openAPIConfig{title: "Beluga AI", version: "1.0.0"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	info := map[string]any{"title": cfg.title, "version": cfg.version}
+	if cfg.description != "" {
+		info["description"] = cfg.description
+	}
+	doc := map[string]any{
+		"openapi": "3.1.0",
+		"info":    info,
+		"paths":   openAPIPaths(agents),
+		"components": map[string]any{
+			"schemas": openAPISchemas(),
+		},
+	}
+	if len(cfg.servers) > 0 {
+		servers := make([]map[string]any, len(cfg.servers))
+		for i, u := range cfg.servers {
+			servers[i] = map[string]any{"url": u}
+		}
+		doc["servers"] = servers
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "server/openapi: marshal: %w", err)
+	}
+	return data, nil
+}
+
+// OpenAPIHandler returns an http.Handler that serves the document produced
+// by GenerateOpenAPI. The document is regenerated on each request, so agents
+// registered after the handler are included. Register it explicitly to opt
+// in:
+//
+//	adapter.RegisterHandler("/openapi.json", server.OpenAPIHandler(adapter))
+func OpenAPIHandler(adapter ServerAdapter, opts ...OpenAPIOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, InvokeResponse{Error: "method not allowed"})
+			return
+		}
+		data, err := GenerateOpenAPI(adapter, opts...)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, InvokeResponse{Error: err.Error()})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+}
+
+// openAPIPaths builds the paths object for the given agents.
+func openAPIPaths(agents map[string]agent.Agent) map[string]any {
+	prefixes := make([]string, 0, len(agents))
+	for p := range agents {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	paths := make(map[string]any, 2*len(prefixes))
+	for _, prefix := range prefixes {
+		cfg := HandlerConfigOf(agents[prefix])
+		a, _, _ := unwrapAgent(agents[prefix])
+		id := operationID(prefix)
+		tag := a.ID()
+		if tag == "" {
+			tag = prefix
+		}
+		desc := agentDescription(a)
+
+		paths[prefix+"/invoke"] = map[string]any{
+			"post": map[string]any{
+				"operationId": id + "_invoke",
+				"summary":     "Invoke " + tag,
+				"description": desc,
+				"tags":        []string{tag},
+				"requestBody": jsonBody("InvokeRequest"),
+				"responses": withErrorResponses(map[string]any{
+					"200": jsonResponse("Agent result", "InvokeResponse"),
+					"400": jsonResponse("Invalid request body", "InvokeResponse"),
+					"500": jsonResponse("Agent error", "InvokeResponse"),
+				}),
+			},
+		}
+		paths[prefix+"/batch"] = map[string]any{
+			"post": map[string]any{
+				"operationId": id + "_batch",
+				"summary":     "Batch invoke " + tag,
+				"description": desc,
+				"tags":        []string{tag},
+				"requestBody": jsonBody("BatchRequest"),
+				"responses": withErrorResponses(map[string]any{
+					"200": jsonResponse("Results in request order, with per-item errors", "BatchResponse"),
+					"400": jsonResponse("Invalid request body", "BatchResponse"),
+					"500": jsonResponse("Fail-fast batch aborted", "BatchResponse"),
+				}),
+			},
+		}
+		streamResponses := withErrorResponses(map[string]any{
+			"200": map[string]any{
+				"description": "Server-Sent Events stream; each event's data is a StreamEvent. The stream ends with a \"done\" or \"error\" event.",
+				"content": map[string]any{
+					"text/event-stream": map[string]any{
+						"itemSchema": schemaRef("StreamEvent"),
+					},
+				},
+			},
+			"400": jsonResponse("Invalid request body", "InvokeResponse"),
+		})
+		stream := map[string]any{
+			"operationId": id + "_stream",
+			"summary":     "Stream " + tag,
+			"description": desc,
+			"tags":        []string{tag},
+			"requestBody": jsonBody("InvokeRequest"),
+			"responses":   streamResponses,
+		}
+		if cfg.StreamResumption {
+			streamResponses["410"] = jsonResponse("The Last-Event-ID was evicted or its session expired; restart the stream", "InvokeResponse")
+			stream["parameters"] = []map[string]any{{
+				"name":        "Last-Event-ID",
+				"in":          "header",
+				"description": "ID of the last event received, to resume the stream after it.",
+				"schema":      map[string]any{"type": "string"},
+			}}
+		}
+		paths[prefix+"/stream"] = map[string]any{"post": stream}
+		if cfg.WebSocket {
+			paths[prefix+"/ws"] = map[string]any{
+				"get": map[string]any{
+					"operationId": id + "_ws",
+					"summary":     "WebSocket " + tag,
+					"description": desc + " Send InvokeRequest JSON messages; each run streams StreamEvent JSON messages ending with a \"done\" or \"error\" event.",
+					"tags":        []string{tag},
+					"responses": withErrorResponses(map[string]any{
+						"101": map[string]any{"description": "Switched to the WebSocket protocol"},
+					}),
+				},
+			}
+		}
+	}
+	return paths
+}
+
+// withErrorResponses adds the responses that server middleware can send in
+// place of the agent handler's to responses.
+func withErrorResponses(responses map[string]any) map[string]any {
+	responses["401"] = jsonResponse("Missing or invalid credentials", "InvokeResponse")
+	responses["403"] = jsonResponse("Denied by the authorization policy", "InvokeResponse")
+	responses["429"] = retryAfterResponse("Too many concurrent requests")
+	responses["503"] = retryAfterResponse("Server is draining")
+	return responses
+}
+
+// openAPISchemas returns the component schemas for the request and response
+// types.
+func openAPISchemas() map[string]any {
+	return map[string]any{
+		"InvokeRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"input"},
+			"properties": map[string]any{
+				"input": map[string]any{"type": "string", "description": "Input passed to the agent."},
+			},
+		},
+		"InvokeResponse": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"result": map[string]any{"type": "string", "description": "Agent output."},
+				"error":  map[string]any{"type": "string", "description": "Error message, set on failure."},
+			},
+		},
+		"BatchRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"items"},
+			"properties": map[string]any{
+				"items":     map[string]any{"type": "array", "items": schemaRef("InvokeRequest")},
+				"fail_fast": map[string]any{"type": "boolean", "description": "Abort the batch at the first failed item."},
+			},
+		},
+		"BatchResponse": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"results": map[string]any{"type": "array", "items": schemaRef("InvokeResponse")},
+				"error":   map[string]any{"type": "string", "description": "Set when a fail-fast batch was aborted."},
+			},
+		},
+		"StreamEvent": map[string]any{
+			"type":     "object",
+			"required": []string{"type"},
+			"properties": map[string]any{
+				"type":     map[string]any{"type": "string", "description": "Event type, such as text, tool_call, done, or error."},
+				"text":     map[string]any{"type": "string"},
+				"agent_id": map[string]any{"type": "string"},
+				"metadata": map[string]any{"type": "object", "additionalProperties": true},
+			},
+		},
+	}
+}
+
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonBody(schema string) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaRef(schema)},
+		},
+	}
+}
+
+func jsonResponse(description, schema string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaRef(schema)},
+		},
+	}
+}
+
+func retryAfterResponse(description string) map[string]any {
+	resp := jsonResponse(description, "InvokeResponse")
+	resp["headers"] = map[string]any{
+		"Retry-After": map[string]any{
+			"description": "Seconds to wait before retrying.",
+			"schema":      map[string]any{"type": "integer"},
+		},
+	}
+	return resp
+}
+
+// operationID derives an operation ID prefix from a path, e.g.
+// "/api/chat" becomes "api_chat".
+func operationID(path string) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.Trim(path, "/"))
+	if id == "" {
+		return "root"
+	}
+	return id
+}
+
+// agentDescription summarizes an agent's persona for the operation
+// description.
+func agentDescription(a agent.Agent) string {
+	p := a.Persona()
+	var parts []string
+	if p.Role != "" {
+		parts = append(parts, "Role: "+p.Role+".")
+	}
+	if p.Goal != "" {
+		parts = append(parts, "Goal: "+p.Goal+".")
+	}
+	if len(parts) == 0 {
+		return "Agent " + a.ID() + "."
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	base := NewStdlibAdapter(Config{})
+	s := ApplyMiddleware(base, WithTracing(), WithConcurrencyLimit(2, 2))
+	if err := s.RegisterAgent("/api/chat", &mockAgent{id: "chat"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	if err := s.RegisterAgent("/search", &mockAgent{id: "search"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	data, err := GenerateOpenAPI(s, WithOpenAPIInfo("Test API", "2.0.0"), WithOpenAPIServers("https://api.example.com"))
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	if doc.Info.Title != "Test API" || doc.Info.Version != "2.0.0" {
+		t.Errorf("info = %+v", doc.Info)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com" {
+		t.Errorf("servers = %+v", doc.Servers)
+	}
+	for _, p := range []string{"/api/chat/invoke", "/api/chat/stream", "/api/chat/batch", "/search/invoke", "/search/stream", "/search/batch"} {
+		if _, ok := doc.Paths[p]["post"]; !ok {
+			t.Errorf("missing POST %s", p)
+		}
+	}
+	if len(doc.Paths) != 6 {
+		t.Errorf("got %d paths, want 6", len(doc.Paths))
+	}
+	if id := doc.Paths["/api/chat/invoke"]["post"]["operationId"]; id != "api_chat_invoke" {
+		t.Errorf("operationId = %v", id)
+	}
+	for _, name := range []string{"InvokeRequest", "InvokeResponse", "BatchRequest", "BatchResponse", "StreamEvent"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("missing schema %s", name)
+		}
+	}
+}
+
+func TestGenerateOpenAPI_UnsupportedAdapter(t *testing.T) {
+	if _, err := GenerateOpenAPI(&tracingTestAdapter{}); err == nil {
+		t.Fatal("expected error for adapter without AgentLister")
+	}
+	if _, err := GenerateOpenAPI(ApplyMiddleware(&tracingTestAdapter{}, WithTracing())); err == nil {
+		t.Fatal("expected error through middleware for adapter without AgentLister")
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	s := NewStdlibAdapter(Config{})
+	if err := s.RegisterHandler("/openapi.json", OpenAPIHandler(s)); err != nil {
+		t.Fatalf("RegisterHandler: %v", err)
+	}
+	// Registered after the handler; still included.
+	if err := s.RegisterAgent("/chat", &mockAgent{id: "chat"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := doc["paths"].(map[string]any)["/chat/invoke"]; !ok {
+		t.Error("expected /chat/invoke in served document")
+	}
+
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/openapi.json", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestGenerateOpenAPI_HandlerOptions(t *testing.T) {
+	base := NewStdlibAdapter(Config{})
+	if err := base.RegisterAgent("/plain", &mockAgent{id: "plain"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	s := ApplyMiddleware(base, WithAgentHandlerOptions(WithWebSocket(), WithStreamResumption()))
+	if err := s.RegisterAgent("/chat", &mockAgent{id: "chat"}); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+
+	data, err := GenerateOpenAPI(s)
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI: %v", err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Tags       []string       `json:"tags"`
+			Parameters []any          `json:"parameters"`
+			Responses  map[string]any `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	ws, ok := doc.Paths["/chat/ws"]["get"]
+	if !ok {
+		t.Fatal("missing GET /chat/ws")
+	}
+	if ws.Tags[0] != "chat" || ws.Responses["101"] == nil {
+		t.Errorf("ws operation = %+v", ws)
+	}
+	if _, ok := doc.Paths["/plain/ws"]; ok {
+		t.Error("unexpected /plain/ws without WithWebSocket")
+	}
+
+	if stream := doc.Paths["/chat/stream"]["post"]; stream.Responses["410"] == nil || len(stream.Parameters) != 1 {
+		t.Errorf("resumable stream operation = %+v", stream)
+	}
+	if stream := doc.Paths["/plain/stream"]["post"]; stream.Responses["410"] != nil {
+		t.Error("unexpected 410 without stream resumption")
+	}
+
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			for _, code := range []string{"401", "403", "429", "503"} {
+				if op.Responses[code] == nil {
+					t.Errorf("%s %s: missing %s response", method, path, code)
+				}
+			}
+		}
+	}
+}
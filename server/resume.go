@@ -0,0 +1,425 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
+// Default retention for resumable streams.
+const (
+	defaultReplayBuffer = 1024
+	defaultReplayTTL    = 5 * time.Minute
+)
+
+// ResumeOption configures stream resumption.
+type ResumeOption func(*resumeStore)
+
+// WithReplayBuffer sets how many of the most recent events each stream
+// session retains for replay. Older events are evicted. The default is 1024.
+func WithReplayBuffer(n int) ResumeOption {
+	return func(s *resumeStore) {
+		if n > 0 {
+			s.bufferSize = n
+		}
+	}
+}
+
+// WithReplayTTL sets how long a session is kept once it has no connected
+// client, measured from the last disconnect or from the end of the run,
+// whichever is later. When a session expires its run is canceled, its
+// buffer released, and it can no longer be resumed; expiry is scheduled
+// with a timer, so it happens even if the handler receives no further
+// requests. The default is 5 minutes.
+func WithReplayTTL(d time.Duration) ResumeOption {
+	return func(s *resumeStore) {
+		if d > 0 {
+			s.ttl = d
+		}
+	}
+}
+
+// WithStreamResumption makes {prefix}/stream resumable. Each stream becomes
+// a session whose agent run continues independently of the connection, and
+// whose events carry IDs of the form "<session>:<seq>". A client that
+// reconnects with a Last-Event-ID header receives the events after that ID,
+// followed by the live stream, instead of restarting the run; the request
+// body is ignored on reconnect.
+//
+// Retention: each session keeps its most recent events (see
+// WithReplayBuffer) until it expires (see WithReplayTTL). While a client is
+// connected the run waits for it rather than evicting events it has not yet
+// received, so only disconnected clients can fall behind. Reconnecting with
+// the ID of an evicted event, or of an expired or unknown session, yields
+// 410 Gone with an error explaining that the stream must be restarted.
+//
+// Pass it to NewAgentHandler, or to WithAgentHandlerOptions for agents
+// registered through RegisterAgent; the gRPC adapter rejects it.
+func WithStreamResumption(opts ...ResumeOption) HandlerOption {
+	return func(c *handlerConfig) {
+		s := &resumeStore{
+			bufferSize: defaultReplayBuffer,
+			ttl:        defaultReplayTTL,
+			sessions:   make(map[string]*streamSession),
+			now:        time.Now,
+		}
+		for _, opt := range opts {
+			opt(s)
+		}
+		c.resume = s
+	}
+}
+
+// resumeStore holds the stream sessions of one agent handler.
+type resumeStore struct {
+	bufferSize int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*streamSession
+}
+
+// bufferedEvent is an event retained for replay.
+type bufferedEvent struct {
+	seq   uint64
+	event SSEEvent
+}
+
+// streamSession buffers the events of one agent run. Connected clients apply
+// backpressure: the run does not evict an event that a connected client has
+// not yet written, so only disconnected clients can fall behind the buffer.
+type streamSession struct {
+	id     string
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	events    []bufferedEvent
+	next      uint64 // sequence number of the next event
+	done      bool
+	changed   chan struct{}  // closed and replaced on every state change
+	cursors   map[int]uint64 // last sequence number written, per client
+	nextSub   int
+	idleSince time.Time
+}
+
+// start creates a session with one attached client, positioned before the
+// first event, and launches the agent run in the background.
+func (s *resumeStore) start(parent context.Context, a agent.Agent, input string) (*streamSession, int) {
+	ctx, cancel := drainableContext(context.WithoutCancel(parent))
+	sess := &streamSession{
+		id:        newSessionID(),
+		cancel:    cancel,
+		next:      1,
+		changed:   make(chan struct{}),
+		cursors:   make(map[int]uint64),
+		idleSince: s.now(),
+	}
+	sub := sess.attachLocked(0)
+
+	s.mu.Lock()
+	s.sweepLocked()
+	s.sessions[sess.id] = sess
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		for ev := range sseEvents(ctx, a, input) {
+			if !sess.append(ctx, ev, s.bufferSize) {
+				return
+			}
+		}
+		if sess.finish(s.now()) {
+			s.scheduleExpiry(sess, s.ttl)
+		}
+	}()
+	return sess, sub
+}
+
+// detach detaches a client from sess and, if no client remains, schedules
+// the session's expiry.
+func (s *resumeStore) detach(sess *streamSession, sub int) {
+	if sess.detach(sub, s.now()) {
+		s.scheduleExpiry(sess, s.ttl)
+	}
+}
+
+// scheduleExpiry checks sess for expiry after d.
+func (s *resumeStore) scheduleExpiry(sess *streamSession, d time.Duration) {
+	time.AfterFunc(d, func() { s.expire(sess) })
+}
+
+// expire removes sess and cancels its run if it has been idle for the TTL.
+// If it is idle but has not yet expired, because it became idle again after
+// this check was scheduled, the check is rescheduled; if a client is
+// attached, that client's detach schedules the next one.
+func (s *resumeStore) expire(sess *streamSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions[sess.id] != sess {
+		return
+	}
+	idle, ok := sess.idleFor(s.now())
+	if !ok {
+		return
+	}
+	if idle >= s.ttl {
+		sess.cancel()
+		delete(s.sessions, sess.id)
+		return
+	}
+	s.scheduleExpiry(sess, s.ttl-idle)
+}
+
+// get returns the live session with the given ID, or nil.
+func (s *resumeStore) get(id string) *streamSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked()
+	return s.sessions[id]
+}
+
+// sweepLocked removes expired sessions and cancels their runs. s.mu must be
+// held.
+func (s *resumeStore) sweepLocked() {
+	now := s.now()
+	for id, sess := range s.sessions {
+		if sess.expired(now, s.ttl) {
+			sess.cancel()
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// serve handles a POST to {prefix}/stream with resumption enabled.
+func (s *resumeStore) serve(w http.ResponseWriter, r *http.Request, a agent.Agent) {
+	var sess *streamSession
+	var sub int
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		id, seq, ok := parseEventID(last)
+		if ok {
+			sess = s.get(id)
+		}
+		if sess == nil {
+			writeJSON(w, http.StatusGone, InvokeResponse{
+				Error: fmt.Sprintf("stream session for event %q not found or expired; restart the stream", last),
+			})
+			return
+		}
+		var oldest uint64
+		if sub, oldest, ok = sess.resume(seq); !ok {
+			writeJSON(w, http.StatusGone, InvokeResponse{
+				Error: fmt.Sprintf("stream event %q has been evicted from the replay buffer (oldest retained is %s:%d); restart the stream", last, id, oldest),
+			})
+			return
+		}
+	} else {
+		var req InvokeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, InvokeResponse{
+				Error: fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		sess, sub = s.start(r.Context(), a, req.Input)
+	}
+	defer func() { s.detach(sess, sub) }()
+
+	sw, err := NewSSEWriter(w)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, InvokeResponse{
+			Error: "streaming not supported",
+		})
+		return
+	}
+
+	closing := drainSignal(r.Context())
+	for {
+		events, done, wait := sess.pending(sub)
+		for _, be := range events {
+			if err := sw.WriteEvent(be.event); err != nil {
+				return
+			}
+			sess.advance(sub, be.seq)
+		}
+		if done {
+			return
+		}
+		select {
+		case <-wait:
+		case <-r.Context().Done():
+			return
+		case <-closing:
+			writeClosingEvent(sw)
+			return
+		}
+	}
+}
+
+// append buffers an event, evicting the oldest beyond limit. While the
+// buffer is full and a connected client has yet to write the oldest event,
+// it waits. It reports false if ctx ends first.
+func (ss *streamSession) append(ctx context.Context, ev SSEEvent, limit int) bool {
+	ss.mu.Lock()
+	for len(ss.events) >= limit && ss.laggingLocked() {
+		wait := ss.changed
+		ss.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return false
+		}
+		ss.mu.Lock()
+	}
+	defer ss.mu.Unlock()
+
+	ev.ID = ss.id + ":" + strconv.FormatUint(ss.next, 10)
+	ss.events = append(ss.events, bufferedEvent{seq: ss.next, event: ev})
+	ss.next++
+	if len(ss.events) > limit {
+		ss.events = ss.events[len(ss.events)-limit:]
+	}
+	ss.notifyLocked()
+	return true
+}
+
+// laggingLocked reports whether a connected client has not yet written the
+// oldest buffered event. ss.mu must be held.
+func (ss *streamSession) laggingLocked() bool {
+	oldest := ss.oldestLocked()
+	for _, cur := range ss.cursors {
+		if cur < oldest {
+			return true
+		}
+	}
+	return false
+}
+
+// finish marks the run as complete. It reports whether no client is
+// attached.
+func (ss *streamSession) finish(now time.Time) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.done = true
+	ss.idleSince = now
+	ss.notifyLocked()
+	return len(ss.cursors) == 0
+}
+
+// resume attaches a client positioned after seq if all later events are
+// still buffered. It also returns the oldest retained sequence number.
+func (ss *streamSession) resume(seq uint64) (sub int, oldest uint64, ok bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	oldest = ss.oldestLocked()
+	if seq+1 < oldest || seq >= ss.next {
+		return 0, oldest, false
+	}
+	return ss.attachLocked(seq), oldest, true
+}
+
+// pending returns the buffered events the client has not written, whether
+// the run is complete and no events remain, and a channel closed on the
+// next state change.
+func (ss *streamSession) pending(sub int) (events []bufferedEvent, done bool, wait <-chan struct{}) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	cur := ss.cursors[sub]
+	for _, be := range ss.events {
+		if be.seq > cur {
+			events = append(events, be)
+		}
+	}
+	return events, ss.done && len(events) == 0, ss.changed
+}
+
+// advance records that the client has written the event with sequence
+// number seq.
+func (ss *streamSession) advance(sub int, seq uint64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.cursors[sub] = seq
+	ss.notifyLocked()
+}
+
+// oldestLocked returns the sequence number of the oldest buffered event, or
+// the next sequence number when the buffer is empty. ss.mu must be held.
+func (ss *streamSession) oldestLocked() uint64 {
+	if len(ss.events) == 0 {
+		return ss.next
+	}
+	return ss.events[0].seq
+}
+
+// attachLocked registers a client positioned after seq. ss.mu must be held,
+// or the session not yet shared.
+func (ss *streamSession) attachLocked(seq uint64) int {
+	sub := ss.nextSub
+	ss.nextSub++
+	ss.cursors[sub] = seq
+	return sub
+}
+
+// detach unregisters a client. It reports whether no client remains
+// attached.
+func (ss *streamSession) detach(sub int, now time.Time) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.cursors, sub)
+	ss.idleSince = now
+	ss.notifyLocked()
+	return len(ss.cursors) == 0
+}
+
+// notifyLocked wakes everyone waiting on the session. ss.mu must be held.
+func (ss *streamSession) notifyLocked() {
+	close(ss.changed)
+	ss.changed = make(chan struct{})
+}
+
+// expired reports whether the session has had no client for longer than
+// ttl.
+func (ss *streamSession) expired(now time.Time, ttl time.Duration) bool {
+	idle, ok := ss.idleFor(now)
+	return ok && idle > ttl
+}
+
+// idleFor returns how long the session has had no client, and false if a
+// client is attached.
+func (ss *streamSession) idleFor(now time.Time) (time.Duration, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.cursors) > 0 {
+		return 0, false
+	}
+	return now.Sub(ss.idleSince), true
+}
+
+// parseEventID splits a "<session>:<seq>" event ID.
+func parseEventID(id string) (string, uint64, bool) {
+	sid, seqStr, ok := strings.Cut(id, ":")
+	if !ok || sid == "" {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return sid, seq, true
+}
+
+// newSessionID returns a random session identifier.
+func newSessionID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+)
+
+// parseSSE splits an SSE body into events, returning each event's id and
+// event type.
+func parseSSE(body string) (ids, types []string) {
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var id, typ string
+		for _, line := range strings.Split(block, "\n") {
+			if v, ok := strings.CutPrefix(line, "id: "); ok {
+				id = v
+			}
+			if v, ok := strings.CutPrefix(line, "event: "); ok {
+				typ = v
+			}
+		}
+		if typ != "" {
+			ids = append(ids, id)
+			types = append(types, typ)
+		}
+	}
+	return ids, types
+}
+
+func resumeRequest(lastID string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/stream", strings.NewReader(`{"input":"hi"}`))
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	return req
+}
+
+func textAgent(n int) *mockAgent {
+	a := &mockAgent{id: "a"}
+	for i := 0; i < n; i++ {
+		a.events = append(a.events, agent.Event{Type: agent.EventText, Text: "chunk"})
+	}
+	return a
+}
+
+func TestStreamResumption_ResumeAfterLastEventID(t *testing.T) {
+	h := NewAgentHandler(textAgent(3), WithStreamResumption())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, resumeRequest(""))
+	ids, types := parseSSE(rec.Body.String())
+	if len(ids) != 4 || types[3] != "done" {
+		t.Fatalf("first stream: ids=%v types=%v", ids, types)
+	}
+	sid, seq, ok := parseEventID(ids[0])
+	if !ok || seq != 1 {
+		t.Fatalf("first event ID %q not of the form <session>:1", ids[0])
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, resumeRequest(ids[1]))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resume status = %d: %s", rec.Code, rec.Body.String())
+	}
+	resumed, types := parseSSE(rec.Body.String())
+	want := []string{sid + ":3", sid + ":4"}
+	if strings.Join(resumed, ",") != strings.Join(want, ",") {
+		t.Errorf("resumed ids = %v, want %v", resumed, want)
+	}
+	if types[len(types)-1] != "done" {
+		t.Errorf("resumed stream should end with done, got %v", types)
+	}
+}
+
+func TestStreamResumption_Errors(t *testing.T) {
+	now := time.Now()
+	var mu sync.Mutex
+	clock := func() time.Time { mu.Lock(); defer mu.Unlock(); return now }
+	advance := func(d time.Duration) { mu.Lock(); now = now.Add(d); mu.Unlock() }
+
+	h := NewAgentHandler(textAgent(5), WithStreamResumption(WithReplayBuffer(2), WithReplayTTL(time.Minute)),
+		func(c *handlerConfig) { c.resume.now = clock })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, resumeRequest(""))
+	ids, _ := parseSSE(rec.Body.String())
+	if len(ids) != 6 {
+		t.Fatalf("ids = %v, status = %d, body = %s", ids, rec.Code, rec.Body.String())
+	}
+	sid, _, _ := parseEventID(ids[0])
+
+	tests := []struct {
+		name    string
+		lastID  string
+		wantMsg string
+	}{
+		{"evicted event", ids[1], "evicted"},
+		{"unknown session", "nope:1", "not found or expired"},
+		{"malformed id", "garbage", "not found or expired"},
+		{"future event", sid + ":99", "evicted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, resumeRequest(tt.lastID))
+			if rec.Code != http.StatusGone {
+				t.Fatalf("status = %d, want 410", rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantMsg) || !strings.Contains(rec.Body.String(), "restart the stream") {
+				t.Errorf("body = %s, want message containing %q", rec.Body.String(), tt.wantMsg)
+			}
+		})
+	}
+
+	// The last two events are still retained.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, resumeRequest(ids[3]))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resume within buffer: status = %d", rec.Code)
+	}
+
+	// After the TTL the session expires.
+	advance(2 * time.Minute)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, resumeRequest(ids[4]))
+	if rec.Code != http.StatusGone || !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("expired session: status = %d body = %s", rec.Code, rec.Body.String())
+	}
+}
+
+// gatedAgent emits one event per value received on next.
+type gatedAgent struct {
+	mockAgent
+	next chan string
+}
+
+func (g *gatedAgent) Stream(ctx context.Context, _ string, _ ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		for text := range g.next {
+			if !yield(agent.Event{Type: agent.EventText, Text: text}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStreamResumption_RunSurvivesDisconnect(t *testing.T) {
+	g := &gatedAgent{mockAgent: mockAgent{id: "g"}, next: make(chan string)}
+	srv := httptest.NewServer(NewAgentHandler(g, WithStreamResumption()))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/stream", strings.NewReader(`{"input":"hi"}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	g.next <- "first"
+	buf := make([]byte, 4096)
+	var got string
+	for !strings.Contains(got, "first") {
+		n, err := resp.Body.Read(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got += string(buf[:n])
+	}
+	ids, _ := parseSSE(got)
+	cancel()
+	resp.Body.Close()
+
+	// The run continues without a client.
+	g.next <- "second"
+	close(g.next)
+
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/stream", strings.NewReader(`{}`))
+	req.Header.Set("Last-Event-ID", ids[0])
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	defer resp.Body.Close()
+	var b strings.Builder
+	for {
+		n, err := resp.Body.Read(buf)
+		b.WriteString(string(buf[:n]))
+		if err != nil {
+			break
+		}
+	}
+	body := b.String()
+	if strings.Contains(body, "first") || !strings.Contains(body, "second") || !strings.Contains(body, "event: done") {
+		t.Errorf("resumed body = %s", body)
+	}
+}
+
+// ctxAgent emits one event, then blocks until its context is canceled.
+type ctxAgent struct {
+	mockAgent
+	canceled chan struct{}
+}
+
+func (c *ctxAgent) Stream(ctx context.Context, _ string, _ ...agent.Option) iter.Seq2[agent.Event, error] {
+	return func(yield func(agent.Event, error) bool) {
+		if !yield(agent.Event{Type: agent.EventText, Text: "first"}, nil) {
+			return
+		}
+		<-ctx.Done()
+		close(c.canceled)
+	}
+}
+
+func TestStreamResumption_ExpiresWithoutRequests(t *testing.T) {
+	a := &ctxAgent{mockAgent: mockAgent{id: "c"}, canceled: make(chan struct{})}
+	var store *resumeStore
+	h := NewAgentHandler(a, WithStreamResumption(WithReplayTTL(50*time.Millisecond)),
+		func(c *handlerConfig) { store = c.resume })
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/stream", strings.NewReader(`{"input":"hi"}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	buf := make([]byte, 4096)
+	var got string
+	for !strings.Contains(got, "first") {
+		n, err := resp.Body.Read(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got += string(buf[:n])
+	}
+	cancel()
+	resp.Body.Close()
+
+	// No further request arrives; the session still expires.
+	select {
+	case <-a.canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not canceled after the TTL")
+	}
+	store.mu.Lock()
+	n := len(store.sessions)
+	store.mu.Unlock()
+	if n != 0 {
+		t.Errorf("sessions = %d, want 0", n)
+	}
+}
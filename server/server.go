@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"maps"
 	"net/http"
 	"sort"
 	"sync"
@@ -91,31 +92,47 @@ func List() []string {
 // StdlibAdapter is the built-in ServerAdapter that uses the standard library
 // net/http package.
 type StdlibAdapter struct {
-	mux *http.ServeMux
-	lc  httputil.ServerLifecycle
-	cfg Config
+	mux   *http.ServeMux
+	lc    httputil.ServerLifecycle
+	cfg   Config
+	drain drainState
+
+	mu     sync.RWMutex
+	agents map[string]agent.Agent
 }
 
 // NewStdlibAdapter creates a new StdlibAdapter with the given configuration.
 func NewStdlibAdapter(cfg Config) *StdlibAdapter {
 	return &StdlibAdapter{
-		mux: http.NewServeMux(),
-		cfg: cfg,
+		mux:    http.NewServeMux(),
+		cfg:    cfg,
+		agents: make(map[string]agent.Agent),
 	}
 }
 
-// RegisterAgent registers an agent at the given path prefix. It creates
-// two sub-routes: {path}/invoke for synchronous invocation and {path}/stream
-// for SSE streaming.
+// RegisterAgent registers an agent at the given path prefix. It serves the
+// sub-routes of NewAgentHandler under path, such as {path}/invoke for
+// synchronous invocation and {path}/stream for SSE streaming.
 func (s *StdlibAdapter) RegisterAgent(path string, a agent.Agent) error {
 	if a == nil {
 		return core.Errorf(core.ErrInvalidInput, "server/register-agent: agent must not be nil")
 	}
 	handler := NewAgentHandler(a)
-	s.mux.Handle(path+"/", handler)
+	s.mux.Handle(path+"/", http.StripPrefix(path, handler))
+
+	s.mu.Lock()
+	s.agents[path] = a
+	s.mu.Unlock()
 	return nil
 }
 
+// Agents returns the registered agents keyed by path prefix.
+func (s *StdlibAdapter) Agents() map[string]agent.Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.agents)
+}
+
 // RegisterHandler registers a raw http.Handler at the given path.
 func (s *StdlibAdapter) RegisterHandler(path string, handler http.Handler) error {
 	if handler == nil {
@@ -128,7 +145,7 @@ func (s *StdlibAdapter) RegisterHandler(path string, handler http.Handler) error
 // Serve starts the HTTP server on the given address. It blocks until the
 // server exits or the context is canceled.
 func (s *StdlibAdapter) Serve(ctx context.Context, addr string) error {
-	return s.lc.Serve(ctx, addr, s.mux,
+	return s.lc.Serve(ctx, addr, s.drain.wrap(s.mux),
 		s.cfg.ReadTimeout, s.cfg.WriteTimeout, s.cfg.IdleTimeout,
 		"server/serve")
 }
@@ -5,6 +5,8 @@ import (
 	"iter"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -103,6 +105,23 @@ func TestStdlibAdapter_RegisterAgent(t *testing.T) {
 		}
 	})
 
+	t.Run("serves the agent routes under path", func(t *testing.T) {
+		adapter := NewStdlibAdapter(Config{})
+		a := &mockAgent{id: "test-agent", result: "hello"}
+		if err := adapter.RegisterAgent("/api/agent", a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/agent/invoke", strings.NewReader(`{"input":"hi"}`))
+		adapter.mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "hello") {
+			t.Errorf("body = %q, want the agent result", rec.Body.String())
+		}
+	})
+
 	t.Run("nil agent returns error", func(t *testing.T) {
 		adapter := NewStdlibAdapter(Config{})
 		if adapter.RegisterAgent("/api/agent", nil) == nil {
@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
@@ -30,6 +31,11 @@ type SSEEvent struct {
 type SSEWriter struct {
 	w       http.ResponseWriter
 	flusher http.Flusher
+
+	autoID   bool
+	idPrefix string
+	seq      uint64
+	lastID   string
 }
 
 // NewSSEWriter creates a new SSEWriter from an http.ResponseWriter. It returns
@@ -50,8 +56,28 @@ func NewSSEWriter(w http.ResponseWriter) (*SSEWriter, error) {
 	return &SSEWriter{w: w, flusher: flusher}, nil
 }
 
+// EnableAutoID makes WriteEvent assign incrementing IDs to events that have
+// none. IDs are prefix followed by a sequence number starting at 1. Clients
+// send the last ID they received in the Last-Event-ID header when they
+// reconnect.
+func (sw *SSEWriter) EnableAutoID(prefix string) {
+	sw.autoID = true
+	sw.idPrefix = prefix
+}
+
+// LastID returns the ID of the most recently written event, or "" if no
+// event with an ID has been written.
+func (sw *SSEWriter) LastID() string {
+	return sw.lastID
+}
+
 // WriteEvent writes a single SSE event to the stream and flushes it.
 func (sw *SSEWriter) WriteEvent(event SSEEvent) error {
+	if event.ID == "" && sw.autoID {
+		sw.seq++
+		event.ID = sw.idPrefix + strconv.FormatUint(sw.seq, 10)
+	}
+
 	var b strings.Builder
 
 	if event.ID != "" {
@@ -77,9 +103,21 @@ func (sw *SSEWriter) WriteEvent(event SSEEvent) error {
 		return core.Errorf(core.ErrProviderDown, "server/sse: write error: %w", err)
 	}
 	sw.flusher.Flush()
+	if event.ID != "" {
+		sw.lastID = event.ID
+	}
+	if o, ok := sw.w.(sseEventObserver); ok {
+		o.observeSSEEvent()
+	}
 	return nil
 }
 
+// sseEventObserver is implemented by response writers that count the SSE
+// events written through them, such as the access log recorder.
+type sseEventObserver interface {
+	observeSSEEvent()
+}
+
 // WriteHeartbeat writes an SSE comment (":heartbeat\n\n") to keep the
 // connection alive. This is useful for proxies that close idle connections.
 func (sw *SSEWriter) WriteHeartbeat() error {
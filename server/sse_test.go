@@ -180,3 +180,29 @@ func TestSSEWriter_WriteHeartbeat_Error(t *testing.T) {
 		t.Errorf("expected error to mention 'write error', got: %v", err)
 	}
 }
+
+func TestSSEWriter_AutoID(t *testing.T) {
+	w := httptest.NewRecorder()
+	sw, err := NewSSEWriter(w)
+	if err != nil {
+		t.Fatalf("NewSSEWriter: %v", err)
+	}
+	if sw.LastID() != "" {
+		t.Errorf("LastID before writes = %q, want empty", sw.LastID())
+	}
+
+	sw.EnableAutoID("s-")
+	_ = sw.WriteEvent(SSEEvent{Data: "a"})
+	_ = sw.WriteEvent(SSEEvent{Data: "b", ID: "explicit"})
+	_ = sw.WriteEvent(SSEEvent{Data: "c"})
+
+	body := w.Body.String()
+	for _, want := range []string{"id: s-1\ndata: a\n", "id: explicit\ndata: b\n", "id: s-2\ndata: c\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if sw.LastID() != "s-2" {
+		t.Errorf("LastID = %q, want s-2", sw.LastID())
+	}
+}
@@ -90,5 +90,30 @@ func (s *tracedServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// Drain drains the wrapped adapter, falling back to Shutdown when it does
+// not implement Drainer.
+func (s *tracedServer) Drain(ctx context.Context) error {
+	ctx, span := o11y.StartSpan(ctx, "server.drain", o11y.Attrs{
+		o11y.AttrOperationName: "server.drain",
+	})
+	defer span.End()
+
+	if err := Drain(ctx, s.next); err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return nil
+}
+
+// Unwrap returns the wrapped adapter.
+func (s *tracedServer) Unwrap() ServerAdapter {
+	return s.next
+}
+
 // Ensure tracedServer implements ServerAdapter at compile time.
-var _ ServerAdapter = (*tracedServer)(nil)
+var (
+	_ ServerAdapter = (*tracedServer)(nil)
+	_ Drainer       = (*tracedServer)(nil)
+)
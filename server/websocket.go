@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+)
+
+// wsCloseTimeout bounds the final writes made while closing a WebSocket.
+const wsCloseTimeout = time.Second
+
+// wsMessage is a request frame read from the socket, or the error that
+// prevented decoding it.
+type wsMessage struct {
+	req InvokeRequest
+	err error
+}
+
+// handleWebSocket serves the {prefix}/ws endpoint. A reader goroutine
+// decodes InvokeRequest frames; runs are processed one at a time. When the
+// client closes the socket the reader cancels ctx, which cancels the run in
+// progress.
+//
+// Socket reads and writes use the request context rather than ctx, because
+// the WebSocket library closes the connection when an operation's context is
+// canceled; canceling ctx must stop the run without dropping the socket
+// before the final messages are sent.
+func handleWebSocket(w http.ResponseWriter, r *http.Request, a agent.Agent, originPatterns []string) {
+	ws, err := httpclient.AcceptWS(w, r, originPatterns...)
+	if err != nil {
+		// The handshake has already written an HTTP error response.
+		return
+	}
+
+	connCtx := r.Context()
+	ctx, cancel := drainableContext(connCtx)
+	defer cancel()
+	closing := drainSignal(connCtx)
+
+	msgs := make(chan wsMessage)
+	go func() {
+		defer cancel()
+		for {
+			var req InvokeRequest
+			err := ws.ReadJSON(connCtx, &req)
+			if err != nil && !isDecodeError(err) {
+				return
+			}
+			select {
+			case msgs <- wsMessage{req: req, err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case msg := <-msgs:
+			if msg.err != nil {
+				_ = ws.WriteJSON(connCtx, StreamEvent{Type: "error", Text: fmt.Sprintf("invalid request: %v", msg.err)})
+				continue
+			}
+			if err := streamWebSocket(ctx, connCtx, ws, a, msg.req.Input); err != nil {
+				cancel()
+			}
+		}
+	}
+
+	if isClosing(closing) {
+		wctx, wcancel := context.WithTimeout(connCtx, wsCloseTimeout)
+		defer wcancel()
+		_ = ws.WriteJSON(wctx, StreamEvent{Type: EventServerClosing, Text: "server closing, reconnect"})
+		_ = ws.CloseWith(websocket.StatusGoingAway, "server closing")
+		return
+	}
+	_ = ws.Close()
+}
+
+// streamWebSocket runs one agent stream under ctx, writing each event under
+// connCtx as a JSON message followed by a "done" event. Agent errors are
+// reported as an "error" event and end the run but not the connection. A
+// non-nil return means the run was canceled or the connection is no longer
+// usable.
+func streamWebSocket(ctx, connCtx context.Context, ws *httpclient.WSConn, a agent.Agent, input string) error {
+	for event, err := range a.Stream(ctx, input) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return ws.WriteJSON(connCtx, StreamEvent{Type: "error", Text: err.Error()})
+		}
+		if err := ws.WriteJSON(connCtx, toStreamEvent(event)); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ws.WriteJSON(connCtx, StreamEvent{Type: "done"})
+}
+
+// isDecodeError reports whether err is a JSON decoding failure, after which
+// the socket is still usable.
+func isDecodeError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/lookatitude/beluga-ai/v2/agent"
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+)
+
+func dialAgentWS(t *testing.T, srv *httptest.Server) *httpclient.WSConn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ws, err := httpclient.DialWS(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("DialWS: %v", err)
+	}
+	return ws
+}
+
+// readUntilTerminal reads StreamEvents until a "done" or "error" event.
+func readUntilTerminal(t *testing.T, ws *httpclient.WSConn) []StreamEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var events []StreamEvent
+	for {
+		var ev StreamEvent
+		if err := ws.ReadJSON(ctx, &ev); err != nil {
+			t.Fatalf("ReadJSON: %v (events so far: %+v)", err, events)
+		}
+		events = append(events, ev)
+		if ev.Type == "done" || ev.Type == "error" {
+			return events
+		}
+	}
+}
+
+func TestWebSocket_Disabled(t *testing.T) {
+	srv := httptest.NewServer(NewAgentHandler(&mockAgent{id: "a"}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ws")
+	if err != nil {
+		t.Fatalf("GET /ws: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 when WebSocket is not enabled", resp.StatusCode)
+	}
+}
+
+func TestWebSocket_StreamsEvents(t *testing.T) {
+	a := &mockAgent{
+		id: "ws-agent",
+		events: []agent.Event{
+			{Type: agent.EventText, Text: "hello", AgentID: "ws-agent"},
+			{Type: agent.EventText, Text: " world", AgentID: "ws-agent"},
+		},
+	}
+	srv := httptest.NewServer(NewAgentHandler(a, WithWebSocket()))
+	defer srv.Close()
+
+	ws := dialAgentWS(t, srv)
+	defer ws.Close()
+
+	ctx := context.Background()
+	// Two runs over the same connection.
+	for run := 0; run < 2; run++ {
+		if err := ws.WriteJSON(ctx, InvokeRequest{Input: "hi"}); err != nil {
+			t.Fatalf("WriteJSON: %v", err)
+		}
+		events := readUntilTerminal(t, ws)
+		if len(events) != 3 {
+			t.Fatalf("run %d: got %d events, want 3: %+v", run, len(events), events)
+		}
+		if events[0].Text != "hello" || events[1].Text != " world" || events[0].AgentID != "ws-agent" {
+			t.Errorf("run %d: unexpected events %+v", run, events)
+		}
+		if events[2].Type != "done" {
+			t.Errorf("run %d: last event = %q, want done", run, events[2].Type)
+		}
+	}
+}
+
+func TestWebSocket_AgentHandlerOptions(t *testing.T) {
+	base := NewStdlibAdapter(Config{})
+	s := ApplyMiddleware(base, WithAgentHandlerOptions(WithWebSocket()))
+	a := &mockAgent{id: "ws-agent", events: []agent.Event{{Type: agent.EventText, Text: "hello"}}}
+	if err := s.RegisterAgent("/chat", a); err != nil {
+		t.Fatalf("RegisterAgent: %v", err)
+	}
+	if cfg := HandlerConfigOf(base.Agents()["/chat"]); !cfg.WebSocket || cfg.StreamResumption {
+		t.Errorf("HandlerConfigOf = %+v, want WebSocket only", cfg)
+	}
+	srv := httptest.NewServer(base.mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ws, err := httpclient.DialWS(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/chat/ws", nil)
+	if err != nil {
+		t.Fatalf("DialWS: %v", err)
+	}
+	defer ws.Close()
+	if err := ws.WriteJSON(ctx, InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	events := readUntilTerminal(t, ws)
+	if len(events) != 2 || events[0].Text != "hello" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestWebSocket_InvalidFrame(t *testing.T) {
+	a := &mockAgent{id: "a", events: []agent.Event{{Type: agent.EventText, Text: "ok"}}}
+	srv := httptest.NewServer(NewAgentHandler(a, WithWebSocket()))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.CloseNow()
+
+	if err := conn.Write(ctx, websocket.MessageText, []byte("{not json")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"error"`) || !strings.Contains(string(data), "invalid request") {
+		t.Errorf("expected invalid request error event, got %s", data)
+	}
+
+	// The connection remains usable after a bad frame.
+	if err := conn.Write(ctx, websocket.MessageText, []byte(`{"input":"hi"}`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	_, data, err = conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !strings.Contains(string(data), `"text":"ok"`) {
+		t.Errorf("expected agent event, got %s", data)
+	}
+}
+
+func TestWebSocket_AgentError(t *testing.T) {
+	a := &errorStreamAgent{id: "err-agent", err: errors.New("boom")}
+	srv := httptest.NewServer(NewAgentHandler(a, WithWebSocket()))
+	defer srv.Close()
+
+	ws := dialAgentWS(t, srv)
+	defer ws.Close()
+
+	if err := ws.WriteJSON(context.Background(), InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	events := readUntilTerminal(t, ws)
+	last := events[len(events)-1]
+	if last.Type != "error" || last.Text != "boom" {
+		t.Errorf("last event = %+v, want error boom", last)
+	}
+}
+
+func TestWebSocket_ClientCloseCancelsRun(t *testing.T) {
+	a := newBlockingAgent()
+	canceled := make(chan struct{})
+	srv := httptest.NewServer(NewAgentHandler(&cancelObserver{blockingAgent: a, canceled: canceled}, WithWebSocket()))
+	defer srv.Close()
+
+	ws := dialAgentWS(t, srv)
+	if err := ws.WriteJSON(context.Background(), InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	<-a.started
+	ws.Close()
+
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("closing the socket did not cancel the agent run")
+	}
+}
+
+func TestWebSocket_DrainSendsClosingEvent(t *testing.T) {
+	var d drainState
+	a := newBlockingAgent()
+	srv := httptest.NewServer(d.wrap(NewAgentHandler(a, WithWebSocket())))
+	defer srv.Close()
+
+	ws := dialAgentWS(t, srv)
+	defer ws.Close()
+	if err := ws.WriteJSON(context.Background(), InvokeRequest{Input: "hi"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	<-a.started
+	d.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var sawClosing bool
+	for {
+		var ev StreamEvent
+		err := ws.ReadJSON(ctx, &ev)
+		if err != nil {
+			if websocket.CloseStatus(err) != websocket.StatusGoingAway {
+				t.Errorf("close status = %v, want going away (%v)", websocket.CloseStatus(err), err)
+			}
+			break
+		}
+		sawClosing = sawClosing || ev.Type == EventServerClosing
+	}
+	if !sawClosing {
+		t.Error("expected server_closing event")
+	}
+}
+
+// cancelObserver closes canceled when the stream's context is canceled.
+type cancelObserver struct {
+	*blockingAgent
+	canceled chan struct{}
+}
+
+func (c *cancelObserver) Stream(ctx context.Context, input string, opts ...agent.Option) iter.Seq2[agent.Event, error] {
+	inner := c.blockingAgent.Stream(ctx, input, opts...)
+	return func(yield func(agent.Event, error) bool) {
+		defer func() {
+			if ctx.Err() != nil {
+				close(c.canceled)
+			}
+		}()
+		inner(yield)
+	}
+}
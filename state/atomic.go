@@ -0,0 +1,194 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrNotInteger is returned by Increment when the stored value is not an
+// integer.
+var ErrNotInteger = errors.New("state: value is not an integer")
+
+// AtomicStore extends Store with atomic read-modify-write operations on
+// values. Callers normally use the package-level CompareAndSwap and Increment
+// functions, which use these methods when available and fall back otherwise.
+type AtomicStore interface {
+	Store
+
+	// CompareAndSwapValue sets key to new only if its current value equals
+	// old, and reports whether it did. A nil old matches a missing key.
+	CompareAndSwapValue(ctx context.Context, key string, old, new any) (swapped bool, err error)
+
+	// Increment adds delta to the integer stored at key, treating a missing
+	// key as 0, and returns the result. It returns ErrNotInteger if the
+	// stored value is not an integer.
+	Increment(ctx context.Context, key string, delta int64) (int64, error)
+}
+
+// maxAtomicRetries bounds the optimistic retry loop used for VersionedStores.
+const maxAtomicRetries = 100
+
+// fallbackLocks serialize CompareAndSwap and Increment on stores that
+// implement neither AtomicStore nor VersionedStore. A key always maps to the
+// same lock.
+var fallbackLocks [64]sync.Mutex
+
+// fallbackLock returns the lock guarding key in fallbackLocks.
+func fallbackLock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return &fallbackLocks[h.Sum32()%uint32(len(fallbackLocks))]
+}
+
+// CompareAndSwap sets key to new only if its current value equals old, and
+// reports whether it did. A nil old matches a missing key.
+//
+// Stores implementing AtomicStore perform the operation natively. For a
+// VersionedStore it is an optimistic read and version-checked write, retried
+// on conflict, which is atomic against all writers. Any other Store gets a
+// Get followed by a Set under an in-process lock for key, which is atomic
+// against other CompareAndSwap and Increment calls in this process but not
+// against plain writes or other processes; see WithLocking.
+func CompareAndSwap(ctx context.Context, s Store, key string, old, new any) (bool, error) {
+	switch st := s.(type) {
+	case AtomicStore:
+		return st.CompareAndSwapValue(ctx, key, old, new)
+	case VersionedStore:
+		for range maxAtomicRetries {
+			current, version, err := st.GetVersioned(ctx, key)
+			if err != nil {
+				return false, err
+			}
+			if !reflect.DeepEqual(current, old) {
+				return false, nil
+			}
+			_, err = st.CompareAndSwap(ctx, key, version, new)
+			if err == nil {
+				return true, nil
+			}
+			if !errors.Is(err, ErrVersionMismatch) {
+				return false, err
+			}
+		}
+		return false, ErrVersionMismatch
+	default:
+		mu := fallbackLock(key)
+		mu.Lock()
+		defer mu.Unlock()
+		return compareAndSwapValue(ctx, s, key, old, new)
+	}
+}
+
+// Increment adds delta to the integer stored at key, treating a missing key
+// as 0, and returns the result. It has the same consistency guarantees per
+// store type as CompareAndSwap.
+func Increment(ctx context.Context, s Store, key string, delta int64) (int64, error) {
+	switch st := s.(type) {
+	case AtomicStore:
+		return st.Increment(ctx, key, delta)
+	case VersionedStore:
+		for range maxAtomicRetries {
+			current, version, err := st.GetVersioned(ctx, key)
+			if err != nil {
+				return 0, err
+			}
+			n, err := ToInt64(current)
+			if err != nil {
+				return 0, core.Errorf(core.ErrInvalidInput, "state: increment %q: %w", key, err)
+			}
+			_, err = st.CompareAndSwap(ctx, key, version, n+delta)
+			if err == nil {
+				return n + delta, nil
+			}
+			if !errors.Is(err, ErrVersionMismatch) {
+				return 0, err
+			}
+		}
+		return 0, ErrVersionMismatch
+	default:
+		mu := fallbackLock(key)
+		mu.Lock()
+		defer mu.Unlock()
+		return increment(ctx, s, key, delta)
+	}
+}
+
+// compareAndSwapValue sets key to new with a Get followed by a Set. The
+// caller must serialize it against other writers of key.
+func compareAndSwapValue(ctx context.Context, s Store, key string, old, new any) (bool, error) {
+	current, err := s.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if !reflect.DeepEqual(current, old) {
+		return false, nil
+	}
+	if err := s.Set(ctx, key, new); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// increment adds delta to key with a Get followed by a Set. The caller must
+// serialize it against other writers of key.
+func increment(ctx context.Context, s Store, key string, delta int64) (int64, error) {
+	current, err := s.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := ToInt64(current)
+	if err != nil {
+		return 0, core.Errorf(core.ErrInvalidInput, "state: increment %q: %w", key, err)
+	}
+	if err := s.Set(ctx, key, n+delta); err != nil {
+		return 0, err
+	}
+	return n + delta, nil
+}
+
+// ToInt64 converts a stored counter value to int64. nil converts to 0, and
+// floats are accepted when they hold a whole number, as values decoded from
+// JSON do. Any other value returns ErrNotInteger.
+func ToInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint:
+		return int64(n), nil
+	case uint8:
+		return int64(n), nil
+	case uint16:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case uint64:
+		if n <= math.MaxInt64 {
+			return int64(n), nil
+		}
+	case float32:
+		if n == float32(math.Trunc(float64(n))) {
+			return int64(n), nil
+		}
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+			return int64(n), nil
+		}
+	}
+	return 0, ErrNotInteger
+}
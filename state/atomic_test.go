@@ -0,0 +1,153 @@
+package state
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareAndSwap(t *testing.T) {
+	stores := map[string]func() Store{
+		"versioned": func() Store { return newMockVersionedStore() },
+		"locked":    func() Store { return ApplyMiddleware(newMockStore(), WithLocking()) },
+		"plain":     func() Store { return newMockStore() },
+		"wrapped":   func() Store { return ApplyMiddleware(newMockVersionedStore(), WithTracing(), WithHooks(Hooks{})) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newStore()
+
+			swapped, err := CompareAndSwap(ctx, s, "leader", nil, "a")
+			require.NoError(t, err)
+			assert.True(t, swapped, "nil old matches a missing key")
+
+			swapped, err = CompareAndSwap(ctx, s, "leader", nil, "b")
+			require.NoError(t, err)
+			assert.False(t, swapped)
+
+			swapped, err = CompareAndSwap(ctx, s, "leader", "a", "b")
+			require.NoError(t, err)
+			assert.True(t, swapped)
+
+			v, _ := s.Get(ctx, "leader")
+			assert.Equal(t, "b", v)
+		})
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	stores := map[string]func() Store{
+		"versioned": func() Store { return newMockVersionedStore() },
+		"locked":    func() Store { return ApplyMiddleware(newMockStore(), WithLocking()) },
+		"plain":     func() Store { return newMockStore() },
+		"wrapped":   func() Store { return ApplyMiddleware(newMockVersionedStore(), WithTracing(), WithHooks(Hooks{})) },
+		"checked": func() Store {
+			return ApplyMiddleware(newMockVersionedStore(), WithHooks(Hooks{
+				BeforeSet: func(context.Context, string, any) error { return nil },
+			}))
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newStore()
+
+			const workers, perWorker = 8, 25
+			var wg sync.WaitGroup
+			for range workers {
+				wg.Go(func() {
+					for range perWorker {
+						_, err := Increment(ctx, s, "hits", 1)
+						assert.NoError(t, err)
+					}
+				})
+			}
+			wg.Wait()
+
+			n, err := Increment(ctx, s, "hits", -10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(workers*perWorker-10), n)
+
+			require.NoError(t, s.Set(ctx, "name", "ada"))
+			_, err = Increment(ctx, s, "name", 1)
+			assert.ErrorIs(t, err, ErrNotInteger)
+		})
+	}
+}
+
+func TestAtomic_Fallback(t *testing.T) {
+	ctx := context.Background()
+	s := newMockStore()
+
+	const workers, perWorker = 8, 50
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				_, err := Increment(ctx, s, "hits", 1)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+	v, err := s.Get(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers*perWorker), v)
+
+	won, err := CompareAndSwap(ctx, s, "leader", nil, "a")
+	require.NoError(t, err)
+	assert.True(t, won)
+	won, err = CompareAndSwap(ctx, s, "leader", nil, "b")
+	require.NoError(t, err)
+	assert.False(t, won)
+}
+
+func TestIncrement_Ownership(t *testing.T) {
+	om := NewOwnershipManager()
+	require.NoError(t, om.Claim("counter", "agent-a"))
+	s := ApplyMiddleware(newMockVersionedStore(), WithOwnership(om))
+
+	_, err := Increment(WithOwnerID(context.Background(), "agent-b"), s, "counter", 1)
+	assert.ErrorIs(t, err, ErrOwnershipDenied)
+	_, err = CompareAndSwap(WithOwnerID(context.Background(), "agent-b"), s, "counter", nil, 1)
+	assert.ErrorIs(t, err, ErrOwnershipDenied)
+
+	n, err := Increment(WithOwnerID(context.Background(), "agent-a"), s, "counter", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestToInt64(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      any
+		want    int64
+		wantErr bool
+	}{
+		{name: "nil", in: nil, want: 0},
+		{name: "int", in: 7, want: 7},
+		{name: "int32", in: int32(-3), want: -3},
+		{name: "uint8", in: uint8(9), want: 9},
+		{name: "whole float", in: 42.0, want: 42},
+		{name: "fractional float", in: 1.5, wantErr: true},
+		{name: "huge float", in: math.MaxFloat64, wantErr: true},
+		{name: "uint64 overflow", in: uint64(math.MaxUint64), wantErr: true},
+		{name: "string", in: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToInt64(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNotInteger)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
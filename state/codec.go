@@ -0,0 +1,148 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrKeyNotFound is returned by GetInto when the key does not exist.
+var ErrKeyNotFound = errors.New("state: key not found")
+
+// Codec serializes values for stores that keep them as bytes. Encode and
+// Decode must round-trip: decoding into a pointer to the encoded value's type
+// yields an equal value, and decoding into a *any yields a usable value.
+type Codec interface {
+	// Encode serializes v.
+	Encode(v any) ([]byte, error)
+
+	// Decode deserializes data into dest, which must be a non-nil pointer.
+	Decode(data []byte, dest any) error
+}
+
+// JSONCodec encodes values as JSON. Decoding into a *any yields the generic
+// JSON types (float64, string, bool, []any, map[string]any); use GetInto to
+// recover structs. It is the default codec.
+type JSONCodec struct{}
+
+// Encode serializes v as JSON.
+func (JSONCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode deserializes JSON data into dest.
+func (JSONCodec) Decode(data []byte, dest any) error {
+	return json.Unmarshal(data, dest)
+}
+
+// GobCodec encodes values with encoding/gob, preserving their concrete Go
+// types. Values are encoded as interfaces, so every concrete type stored,
+// other than the basic types, must be registered with gob.Register.
+type GobCodec struct{}
+
+// Encode serializes v with gob.
+func (GobCodec) Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode deserializes gob data into dest. The decoded value must be
+// assignable to the type dest points to.
+func (GobCodec) Decode(data []byte, dest any) error {
+	var v any
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return err
+	}
+	return assign(v, dest)
+}
+
+// CodecByName returns the codec registered under name: "json" (also the
+// empty name) or "gob".
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "gob":
+		return GobCodec{}, nil
+	}
+	return nil, core.Errorf(core.ErrInvalidInput, "state: unknown codec %q", name)
+}
+
+// CodecFromConfig returns cfg.Codec if set, otherwise the codec named by
+// cfg.Extra["codec"], defaulting to JSONCodec. Providers that store bytes
+// use it to select their codec.
+func CodecFromConfig(cfg Config) (Codec, error) {
+	if cfg.Codec != nil {
+		return cfg.Codec, nil
+	}
+	name, _ := cfg.Extra["codec"].(string)
+	return CodecByName(name)
+}
+
+// TypedStore extends Store with decoding into a typed destination.
+type TypedStore interface {
+	Store
+
+	// GetInto decodes the value of key into dest, which must be a non-nil
+	// pointer. It returns ErrKeyNotFound if the key does not exist.
+	GetInto(ctx context.Context, key string, dest any) error
+}
+
+// GetInto reads key from s into dest, which must be a non-nil pointer. It
+// returns ErrKeyNotFound if the key does not exist.
+//
+// Stores implementing TypedStore decode the stored bytes directly into dest.
+// For other stores the value from Get is assigned to dest when its type
+// allows, and otherwise converted through JSON, so a map[string]any can be
+// read into a struct.
+func GetInto(ctx context.Context, s Store, key string, dest any) error {
+	if ts, ok := s.(TypedStore); ok {
+		return ts.GetInto(ctx, key, dest)
+	}
+	v, err := s.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return core.Errorf(core.ErrNotFound, "state: get %q: %w", key, ErrKeyNotFound)
+	}
+	if err := assign(v, dest); err == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(data, dest)
+	}
+	if err != nil {
+		return core.Errorf(core.ErrInvalidInput, "state: get %q into %T: %w", key, dest, err)
+	}
+	return nil
+}
+
+// assign stores v in the value dest points to.
+func assign(v, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, got %T", dest)
+	}
+	elem := rv.Elem()
+	if v == nil {
+		elem.SetZero()
+		return nil
+	}
+	val := reflect.ValueOf(v)
+	if !val.Type().AssignableTo(elem.Type()) {
+		return fmt.Errorf("cannot assign %T to %s", v, elem.Type())
+	}
+	elem.Set(val)
+	return nil
+}
@@ -0,0 +1,120 @@
+package state
+
+import (
+	"context"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+type codecCart struct {
+	Items []string
+	Total int
+}
+
+func init() {
+	gob.Register(codecCart{})
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	cart := codecCart{Items: []string{"a", "b"}, Total: 3}
+	tests := []struct {
+		name    string
+		codec   Codec
+		wantAny any
+	}{
+		{name: "json", codec: JSONCodec{}, wantAny: map[string]any{"Items": []any{"a", "b"}, "Total": float64(3)}},
+		{name: "gob", codec: GobCodec{}, wantAny: cart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.codec.Encode(cart)
+			require.NoError(t, err)
+
+			var typed codecCart
+			require.NoError(t, tt.codec.Decode(data, &typed))
+			assert.Equal(t, cart, typed)
+
+			var untyped any
+			require.NoError(t, tt.codec.Decode(data, &untyped))
+			assert.Equal(t, tt.wantAny, untyped)
+		})
+	}
+}
+
+func TestGobCodec_DecodeMismatch(t *testing.T) {
+	data, err := GobCodec{}.Encode("text")
+	require.NoError(t, err)
+	var n int
+	assert.Error(t, GobCodec{}.Decode(data, &n))
+}
+
+func TestCodecFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    Codec
+		wantErr bool
+	}{
+		{name: "default", cfg: Config{}, want: JSONCodec{}},
+		{name: "by name", cfg: Config{Extra: map[string]any{"codec": "gob"}}, want: GobCodec{}},
+		{name: "field wins", cfg: Config{Codec: GobCodec{}, Extra: map[string]any{"codec": "json"}}, want: GobCodec{}},
+		{name: "unknown", cfg: Config{Extra: map[string]any{"codec": "xml"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := CodecFromConfig(tt.cfg)
+			if tt.wantErr {
+				var ce *core.Error
+				require.ErrorAs(t, err, &ce)
+				assert.Equal(t, core.ErrInvalidInput, ce.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestGetInto(t *testing.T) {
+	ctx := context.Background()
+	inner := newMockStore()
+	inner.data["cart"] = codecCart{Items: []string{"a"}, Total: 1}
+	inner.data["decoded"] = map[string]any{"Items": []any{"b"}, "Total": float64(2)}
+	inner.data["name"] = "ada"
+	s := ApplyMiddleware(inner, WithTracing(), WithHooks(Hooks{}))
+
+	tests := []struct {
+		name    string
+		key     string
+		want    codecCart
+		wantErr error
+	}{
+		{name: "assignable", key: "cart", want: codecCart{Items: []string{"a"}, Total: 1}},
+		{name: "converted", key: "decoded", want: codecCart{Items: []string{"b"}, Total: 2}},
+		{name: "missing", key: "none", wantErr: ErrKeyNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got codecCart
+			err := GetInto(ctx, s, tt.key, &got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var got codecCart
+	err := GetInto(ctx, s, "name", &got)
+	var ce *core.Error
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, core.ErrInvalidInput, ce.Code)
+	assert.Error(t, GetInto(ctx, s, "cart", got))
+}
@@ -42,6 +42,50 @@
 //	err := store.Set(ctx, key, 42)
 //	val, err := store.Get(ctx, key)
 //
+// # Typed Reads and Codecs
+//
+// Stores that keep values as bytes serialize them with a [Codec]: [JSONCodec]
+// (the default) or [GobCodec], selected through [Config].Codec or the
+// "codec" Extra key. A plain Get then returns the codec's generic decoding,
+// such as map[string]any for a JSON object; [GetInto] recovers the original
+// type:
+//
+//	var cart Cart
+//	err := state.GetInto(ctx, store, state.ScopedKey(state.ScopeSession, "cart"), &cart)
+//
+// Stores implementing [TypedStore] decode the stored bytes directly into the
+// destination. For others the value from Get is assigned to it, or converted
+// through JSON when the types differ. GetInto returns [ErrKeyNotFound] for a
+// missing key.
+//
+// # Expiry
+//
+// [SetWithTTL] stores a value that is removed once its TTL elapses, so state
+// of abandoned sessions does not accumulate. Watchers observe the expiry as
+// an [OpDelete] change:
+//
+//	err := state.SetWithTTL(ctx, store, state.ScopedKey(state.ScopeSession, "cart"), cart, 30*time.Minute)
+//
+// Stores opt in by implementing [ExpiringStore]; for others SetWithTTL
+// returns [ErrTTLUnsupported]. A later write without a TTL clears the expiry.
+//
+// # Listing Keys
+//
+// [ListKeys] returns the keys with a given prefix and [Scan] streams them
+// with their values, for cleanup jobs and admin views. [ScopePrefix] selects
+// every key of a scope:
+//
+//	for entry, err := range state.Scan(ctx, store, state.ScopePrefix(state.ScopeSession)) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(entry.Key, entry.Value)
+//	}
+//
+// Stores opt in by implementing [ScannableStore]; for others both return
+// [ErrScanUnsupported]. Prefer Scan for large key spaces: the redis store
+// fetches one batch at a time, while List collects every key.
+//
 // # Watch for Changes
 //
 // The [Store.Watch] method returns an iter.Seq2 stream of [StateChange]
@@ -57,6 +101,56 @@
 // Each [StateChange] includes the key, old value, new value, and the operation
 // type ([OpSet] or [OpDelete]).
 //
+// # Atomic Operations
+//
+// A Get followed by a Set races with other writers. [CompareAndSwap] and
+// [Increment] perform the read and write as one operation, for distributed
+// counters, leader election flags and rate tracking:
+//
+//	won, err := state.CompareAndSwap(ctx, store, "leader", nil, agentID)
+//	hits, err := state.Increment(ctx, store, "global:hits", 1)
+//
+// How atomic they are depends on the store:
+//
+//   - [AtomicStore] implementations (inmemory, redis) run them natively;
+//     inmemory under its lock, redis as a server-side script, so they are
+//     atomic across every process sharing the server.
+//   - Other [VersionedStore] implementations use an optimistic read and
+//     version-checked write, retried on conflict; this is atomic against all
+//     writers of that store.
+//   - Any other Store falls back to a Get followed by a Set under a
+//     per-key lock of this package. That is atomic against other
+//     CompareAndSwap and Increment calls in the process, but not against
+//     plain writes or other processes. Wrapping the store with
+//     [WithLocking] also serializes the writes made through that wrapper
+//     with the same lock, which is atomic within one process only.
+//
+// The middleware in this package forwards both operations to the wrapped
+// store, so wrapping does not weaken these guarantees.
+//
+// # Transactions
+//
+// [Transaction] updates several keys so that either every write is applied or
+// none is. Writes made through the [Tx] are staged and committed when the
+// function returns nil; returning an error discards them. Watchers receive one
+// notification per changed key after the commit:
+//
+//	err := state.Transaction(ctx, store, func(tx state.Tx) error {
+//	    balance, err := tx.Get(ctx, "balance")
+//	    if err != nil {
+//	        return err
+//	    }
+//	    tx.Set(ctx, "balance", balance.(int)-amount)
+//	    return tx.Set(ctx, "last_payment", amount)
+//	})
+//
+// Stores opt in by implementing [TransactionalStore]; for others Transaction
+// returns [ErrTxUnsupported]. Isolation depends on the store: inmemory runs
+// the transaction under its write lock (serializable), while redis is
+// optimistic and returns [ErrTxConflict] if a key the transaction read
+// changed before it committed. Middleware forwards transactions, and hooks,
+// ownership checks and reducers apply to the staged writes.
+//
 // # Middleware and Hooks
 //
 // Store operations can be wrapped with [Middleware] for cross-cutting concerns
@@ -77,4 +171,5 @@
 // Store backends are in sub-packages under state/providers/:
 //
 //   - state/providers/inmemory — in-memory (development/testing)
+//   - state/providers/redis — Redis, shared across agent replicas
 package state
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+)
+
+// WithLocking returns middleware that makes CompareAndSwap and Increment
+// atomic against plain writes on a Store that implements neither AtomicStore
+// nor VersionedStore. Without it those operations only exclude each other.
+// The wrapper holds a lock of its own and takes it for every write made
+// through it, so the read-modify-write operations are atomic against all
+// writers that share the same wrapped value, but not against other processes
+// or other wrappers of the same backend.
+//
+//	s = state.ApplyMiddleware(s, state.WithLocking())
+//	hits, err := state.Increment(ctx, s, "global:hits", 1)
+func WithLocking() Middleware {
+	return func(next Store) Store {
+		return &lockedStore{next: next}
+	}
+}
+
+// lockedStore serializes the writes made through it with mu.
+type lockedStore struct {
+	mu   sync.Mutex
+	next Store
+}
+
+func (s *lockedStore) Get(ctx context.Context, key string) (any, error) {
+	return s.next.Get(ctx, key)
+}
+
+func (s *lockedStore) Set(ctx context.Context, key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next.Set(ctx, key, value)
+}
+
+func (s *lockedStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next.Delete(ctx, key)
+}
+
+func (s *lockedStore) Watch(ctx context.Context, key string) iter.Seq2[StateChange, error] {
+	return s.next.Watch(ctx, key)
+}
+
+func (s *lockedStore) Close() error {
+	return s.next.Close()
+}
+
+func (s *lockedStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SetWithTTL(ctx, s.next, key, value, ttl)
+}
+
+// CompareAndSwapValue reads and conditionally writes key under the lock.
+func (s *lockedStore) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return compareAndSwapValue(ctx, s.next, key, old, new)
+}
+
+// Increment reads, adds to and writes key under the lock.
+func (s *lockedStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return increment(ctx, s.next, key, delta)
+}
+
+// Transaction runs the transaction under the lock, so fn must write through
+// its Tx and not through the store.
+func (s *lockedStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Transaction(ctx, s.next, fn)
+}
+
+func (s *lockedStore) GetInto(ctx context.Context, key string, dest any) error {
+	return GetInto(ctx, s.next, key, dest)
+}
+
+func (s *lockedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return ListKeys(ctx, s.next, prefix)
+}
+
+func (s *lockedStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	return Scan(ctx, s.next, prefix)
+}
+
+// Ensure lockedStore implements the optional Store interfaces at compile
+// time.
+var (
+	_ AtomicStore        = (*lockedStore)(nil)
+	_ TransactionalStore = (*lockedStore)(nil)
+	_ ScannableStore     = (*lockedStore)(nil)
+	_ ExpiringStore      = (*lockedStore)(nil)
+	_ TypedStore         = (*lockedStore)(nil)
+)
@@ -3,6 +3,9 @@ package state
 import (
 	"context"
 	"iter"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
 )
 
 // Middleware wraps a Store to add cross-cutting behavior.
@@ -133,8 +136,214 @@ func (s *hookedStore) Close() error {
 	return s.next.Close()
 }
 
-// Ensure hookedStore implements Store at compile time.
-var _ Store = (*hookedStore)(nil)
+// SetWithTTL delegates to the next store through SetWithTTL, running the
+// same hooks as Set.
+func (s *hookedStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if s.hooks.BeforeSet != nil {
+		if err := s.hooks.BeforeSet(ctx, key, value); err != nil {
+			return err
+		}
+	}
+
+	err := SetWithTTL(ctx, s.next, key, value, ttl)
+
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+
+	if s.hooks.AfterSet != nil {
+		s.hooks.AfterSet(ctx, key, value, err)
+	}
+
+	return err
+}
+
+// CompareAndSwapValue delegates to the next store through CompareAndSwap,
+// running the same hooks as Set with new as the value. AfterSet fires only
+// when the value was swapped or the call failed.
+func (s *hookedStore) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	if s.hooks.BeforeSet != nil {
+		if err := s.hooks.BeforeSet(ctx, key, new); err != nil {
+			return false, err
+		}
+	}
+
+	swapped, err := CompareAndSwap(ctx, s.next, key, old, new)
+
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+
+	if (swapped || err != nil) && s.hooks.AfterSet != nil {
+		s.hooks.AfterSet(ctx, key, new, err)
+	}
+
+	return swapped, err
+}
+
+// Increment delegates to the next store through Increment, running the same
+// hooks as Set with the resulting int64 as the value. When BeforeSet is
+// configured the result must be known before it is written, so the
+// increment is performed as a read followed by CompareAndSwap, retried on
+// conflict.
+func (s *hookedStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	var n int64
+	var err error
+	if s.hooks.BeforeSet == nil {
+		n, err = Increment(ctx, s.next, key, delta)
+	} else {
+		n, err = s.checkedIncrement(ctx, key, delta)
+	}
+
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+
+	if s.hooks.AfterSet != nil {
+		s.hooks.AfterSet(ctx, key, n, err)
+	}
+
+	return n, err
+}
+
+// checkedIncrement computes the incremented value, passes it to BeforeSet
+// and writes it with CompareAndSwap.
+func (s *hookedStore) checkedIncrement(ctx context.Context, key string, delta int64) (int64, error) {
+	for range maxAtomicRetries {
+		current, err := s.next.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		n, err := ToInt64(current)
+		if err != nil {
+			return 0, core.Errorf(core.ErrInvalidInput, "state: increment %q: %w", key, err)
+		}
+		if err := s.hooks.BeforeSet(ctx, key, n+delta); err != nil {
+			return 0, err
+		}
+		swapped, err := CompareAndSwap(ctx, s.next, key, current, n+delta)
+		if err != nil {
+			return 0, err
+		}
+		if swapped {
+			return n + delta, nil
+		}
+	}
+	return 0, ErrVersionMismatch
+}
+
+// Transaction delegates to the next store through Transaction. Inside the
+// transaction, BeforeGet, AfterGet, BeforeSet and OnDelete fire as each
+// operation is staged, so they can still veto writes; AfterSet does not fire.
+// OnError fires if the transaction fails.
+func (s *hookedStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	err := Transaction(ctx, s.next, func(tx Tx) error {
+		return fn(&hookedTx{next: tx, hooks: s.hooks})
+	})
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+	return err
+}
+
+// GetInto delegates to the next store through GetInto, running BeforeGet and
+// OnError. AfterGet receives dest.
+func (s *hookedStore) GetInto(ctx context.Context, key string, dest any) error {
+	if s.hooks.BeforeGet != nil {
+		if err := s.hooks.BeforeGet(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	err := GetInto(ctx, s.next, key, dest)
+
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+
+	if s.hooks.AfterGet != nil {
+		s.hooks.AfterGet(ctx, key, dest, err)
+	}
+
+	return err
+}
+
+// List delegates to the next store through ListKeys. Only OnError fires.
+func (s *hookedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := ListKeys(ctx, s.next, prefix)
+	if err != nil && s.hooks.OnError != nil {
+		err = s.hooks.OnError(ctx, err)
+	}
+	return keys, err
+}
+
+// Scan delegates to the next store through Scan. Only OnError fires; an
+// OnError that returns nil suppresses the error.
+func (s *hookedStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	inner := Scan(ctx, s.next, prefix)
+	if s.hooks.OnError == nil {
+		return inner
+	}
+	return func(yield func(Entry, error) bool) {
+		for entry, err := range inner {
+			if err != nil {
+				if err = s.hooks.OnError(ctx, err); err == nil {
+					continue
+				}
+			}
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}
+}
+
+// hookedTx runs the validating hooks around transaction operations.
+type hookedTx struct {
+	next  Tx
+	hooks Hooks
+}
+
+func (t *hookedTx) Get(ctx context.Context, key string) (any, error) {
+	if t.hooks.BeforeGet != nil {
+		if err := t.hooks.BeforeGet(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	val, err := t.next.Get(ctx, key)
+	if t.hooks.AfterGet != nil {
+		t.hooks.AfterGet(ctx, key, val, err)
+	}
+	return val, err
+}
+
+func (t *hookedTx) Set(ctx context.Context, key string, value any) error {
+	if t.hooks.BeforeSet != nil {
+		if err := t.hooks.BeforeSet(ctx, key, value); err != nil {
+			return err
+		}
+	}
+	return t.next.Set(ctx, key, value)
+}
+
+func (t *hookedTx) Delete(ctx context.Context, key string) error {
+	if t.hooks.OnDelete != nil {
+		if err := t.hooks.OnDelete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return t.next.Delete(ctx, key)
+}
+
+// Ensure hookedStore implements the optional Store interfaces at compile
+// time.
+var (
+	_ AtomicStore        = (*hookedStore)(nil)
+	_ TransactionalStore = (*hookedStore)(nil)
+	_ ScannableStore     = (*hookedStore)(nil)
+	_ ExpiringStore      = (*hookedStore)(nil)
+	_ TypedStore         = (*hookedStore)(nil)
+)
 
 // WrapVersionedWithHooks returns a VersionedStore that invokes the given
 // Hooks around Get/Set/Delete/Watch and delegates the versioned operations
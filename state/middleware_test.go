@@ -199,6 +199,58 @@ func TestWithHooks_AfterSetCalled(t *testing.T) {
 	assert.NoError(t, afterErr)
 }
 
+func TestWithHooks_AtomicOperations(t *testing.T) {
+	var before, after []any
+	hooks := Hooks{
+		BeforeSet: func(ctx context.Context, key string, value any) error {
+			before = append(before, value)
+			return nil
+		},
+		AfterSet: func(ctx context.Context, key string, value any, err error) {
+			after = append(after, value)
+		},
+	}
+	wrapped := ApplyMiddleware(newMockVersionedStore(), WithHooks(hooks))
+	ctx := context.Background()
+
+	swapped, err := CompareAndSwap(ctx, wrapped, "leader", nil, "a")
+	require.NoError(t, err)
+	require.True(t, swapped)
+	swapped, err = CompareAndSwap(ctx, wrapped, "leader", nil, "b")
+	require.NoError(t, err)
+	require.False(t, swapped)
+
+	n, err := Increment(ctx, wrapped, "hits", 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+
+	assert.Equal(t, []any{"a", "b", int64(3)}, before)
+	assert.Equal(t, []any{"a", int64(3)}, after, "AfterSet skips a failed swap")
+}
+
+func TestWithHooks_BeforeSetVetoesAtomicOperations(t *testing.T) {
+	errAbort := errors.New("abort set")
+	hooks := Hooks{
+		BeforeSet: func(ctx context.Context, key string, value any) error {
+			if n, ok := value.(int64); ok && n > 5 {
+				return errAbort
+			}
+			return nil
+		},
+	}
+	base := newMockVersionedStore()
+	wrapped := ApplyMiddleware(base, WithHooks(hooks))
+	ctx := context.Background()
+
+	_, err := Increment(ctx, wrapped, "hits", 5)
+	require.NoError(t, err)
+	_, err = Increment(ctx, wrapped, "hits", 1)
+	require.ErrorIs(t, err, errAbort)
+
+	v, _ := base.Get(ctx, "hits")
+	assert.Equal(t, int64(5), v)
+}
+
 func TestWithHooks_OnErrorSuppresses(t *testing.T) {
 	hooks := Hooks{
 		OnError: func(ctx context.Context, err error) error {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"iter"
 	"sync"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
 )
@@ -84,8 +85,9 @@ func (om *OwnershipManager) CheckWrite(key, ownerID string) error {
 	return nil
 }
 
-// WithOwnership returns middleware that enforces ownership on Set and Delete
-// operations. The ownerID is extracted from the context using OwnerIDFromContext.
+// WithOwnership returns middleware that enforces ownership on Set,
+// SetWithTTL, Delete, CompareAndSwap and Increment operations, and on writes
+// inside transactions. The ownerID is extracted from the context using OwnerIDFromContext.
 // Keys without ownership claims are accessible to all writers.
 func WithOwnership(om *OwnershipManager) Middleware {
 	return func(next Store) Store {
@@ -137,6 +139,84 @@ func (s *ownedStore) Delete(ctx context.Context, key string) error {
 	return s.next.Delete(ctx, key)
 }
 
+func (s *ownedStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	ownerID := OwnerIDFromContext(ctx)
+	if ownerID != "" {
+		if err := s.om.CheckWrite(key, ownerID); err != nil {
+			return err
+		}
+	}
+	return SetWithTTL(ctx, s.next, key, value, ttl)
+}
+
+func (s *ownedStore) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	ownerID := OwnerIDFromContext(ctx)
+	if ownerID != "" {
+		if err := s.om.CheckWrite(key, ownerID); err != nil {
+			return false, err
+		}
+	}
+	return CompareAndSwap(ctx, s.next, key, old, new)
+}
+
+func (s *ownedStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	ownerID := OwnerIDFromContext(ctx)
+	if ownerID != "" {
+		if err := s.om.CheckWrite(key, ownerID); err != nil {
+			return 0, err
+		}
+	}
+	return Increment(ctx, s.next, key, delta)
+}
+
+func (s *ownedStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	return Transaction(ctx, s.next, func(tx Tx) error {
+		return fn(&ownedTx{next: tx, om: s.om})
+	})
+}
+
+func (s *ownedStore) GetInto(ctx context.Context, key string, dest any) error {
+	return GetInto(ctx, s.next, key, dest)
+}
+
+func (s *ownedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return ListKeys(ctx, s.next, prefix)
+}
+
+func (s *ownedStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	return Scan(ctx, s.next, prefix)
+}
+
+// ownedTx enforces ownership on writes staged in a transaction.
+type ownedTx struct {
+	next Tx
+	om   *OwnershipManager
+}
+
+func (t *ownedTx) Get(ctx context.Context, key string) (any, error) {
+	return t.next.Get(ctx, key)
+}
+
+func (t *ownedTx) Set(ctx context.Context, key string, value any) error {
+	ownerID := OwnerIDFromContext(ctx)
+	if ownerID != "" {
+		if err := t.om.CheckWrite(key, ownerID); err != nil {
+			return err
+		}
+	}
+	return t.next.Set(ctx, key, value)
+}
+
+func (t *ownedTx) Delete(ctx context.Context, key string) error {
+	ownerID := OwnerIDFromContext(ctx)
+	if ownerID != "" {
+		if err := t.om.CheckWrite(key, ownerID); err != nil {
+			return err
+		}
+	}
+	return t.next.Delete(ctx, key)
+}
+
 func (s *ownedStore) Watch(ctx context.Context, key string) iter.Seq2[StateChange, error] {
 	return s.next.Watch(ctx, key)
 }
@@ -145,4 +225,10 @@ func (s *ownedStore) Close() error {
 	return s.next.Close()
 }
 
-var _ Store = (*ownedStore)(nil)
+var (
+	_ AtomicStore        = (*ownedStore)(nil)
+	_ TransactionalStore = (*ownedStore)(nil)
+	_ ScannableStore     = (*ownedStore)(nil)
+	_ ExpiringStore      = (*ownedStore)(nil)
+	_ TypedStore         = (*ownedStore)(nil)
+)
@@ -24,8 +24,19 @@
 // blocking; notifications that arrive while the buffer is full are dropped.
 // Iterators end when the store is closed or the watch context is cancelled.
 //
+// # Expiry
+//
+// [Store.SetWithTTL] stores keys that expire. Expired keys are hidden from
+// reads at once and removed by a background sweeper, started by the first
+// SetWithTTL, which notifies watchers with an OpDelete change. Use
+// [WithSweepInterval] to change how often it runs. Other writes clear the
+// expiry, except [Store.Increment], which keeps it.
+//
 // # Thread Safety
 //
 // All operations are protected by a sync.RWMutex and are safe for concurrent
-// use from multiple goroutines.
+// use from multiple goroutines. [Store.CompareAndSwapValue] and
+// [Store.Increment] read and write under the same lock, so they are atomic
+// within the process. [Store.Transaction] holds the write lock for the whole
+// transaction, which makes transactions serializable.
 package inmemory
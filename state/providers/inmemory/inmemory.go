@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/state"
 )
@@ -15,10 +19,20 @@ func init() {
 	})
 }
 
-// entry holds a value and its monotonic version counter.
+// defaultSweepInterval is how often expired keys are removed.
+const defaultSweepInterval = time.Second
+
+// entry holds a value, its monotonic version counter and its expiry time
+// (zero for none).
 type entry struct {
 	value   any
 	version uint64
+	expires time.Time
+}
+
+// expired reports whether the entry's TTL has passed at now.
+func (e entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
 }
 
 // Store is a thread-safe in-memory implementation of state.VersionedStore.
@@ -28,15 +42,39 @@ type Store struct {
 	watchers map[string][]chan state.StateChange
 	closed   bool
 	done     chan struct{} // closed on Close() to unblock context goroutines
+
+	sweepInterval time.Duration
+	sweepOnce     sync.Once
+	now           func() time.Time
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithSweepInterval sets how often the background sweeper removes expired
+// keys and notifies their watchers. The default is one second. Expired keys
+// are never returned by reads, whether or not they have been swept.
+func WithSweepInterval(d time.Duration) Option {
+	return func(s *Store) {
+		if d > 0 {
+			s.sweepInterval = d
+		}
+	}
 }
 
 // New creates a new in-memory Store.
-func New() *Store {
-	return &Store{
-		data:     make(map[string]entry),
-		watchers: make(map[string][]chan state.StateChange),
-		done:     make(chan struct{}),
+func New(opts ...Option) *Store {
+	s := &Store{
+		data:          make(map[string]entry),
+		watchers:      make(map[string][]chan state.StateChange),
+		done:          make(chan struct{}),
+		sweepInterval: defaultSweepInterval,
+		now:           time.Now,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Get retrieves the value for the given key. Returns nil, nil if the key
@@ -53,7 +91,7 @@ func (s *Store) Get(ctx context.Context, key string) (any, error) {
 		return nil, fmt.Errorf("state/get: store is closed")
 	}
 
-	e, ok := s.data[key]
+	e, ok := s.live(key)
 	if !ok {
 		return nil, nil
 	}
@@ -74,7 +112,7 @@ func (s *Store) GetVersioned(ctx context.Context, key string) (any, uint64, erro
 		return nil, 0, fmt.Errorf("state/get_versioned: store is closed")
 	}
 
-	e, ok := s.data[key]
+	e, ok := s.live(key)
 	if !ok {
 		return nil, 0, nil
 	}
@@ -82,6 +120,7 @@ func (s *Store) GetVersioned(ctx context.Context, key string) (any, uint64, erro
 }
 
 // Set stores a value under the given key, incrementing the version counter.
+// It clears any expiry set by SetWithTTL.
 func (s *Store) Set(ctx context.Context, key string, value any) error {
 	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("state/set: %w", err)
@@ -94,20 +133,32 @@ func (s *Store) Set(ctx context.Context, key string, value any) error {
 		return fmt.Errorf("state/set: store is closed")
 	}
 
-	e := s.data[key]
-	oldValue := e.value
-	e.version++
-	e.value = value
-	s.data[key] = e
+	s.write(key, value, time.Time{})
+	return nil
+}
 
-	s.broadcast(state.StateChange{
-		Key:      key,
-		OldValue: oldValue,
-		Value:    value,
-		Op:       state.OpSet,
-		Version:  e.version,
-	})
+// SetWithTTL stores a value under the given key and removes it once ttl has
+// elapsed; a ttl <= 0 stores it without expiry. When the key expires,
+// watchers receive an OpDelete change. Any later write without a TTL clears
+// the expiry.
+func (s *Store) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("state/set_with_ttl: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	if s.closed {
+		return fmt.Errorf("state/set_with_ttl: store is closed")
+	}
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = s.now().Add(ttl)
+		s.sweepOnce.Do(func() { go s.sweep() })
+	}
+	s.write(key, value, expires)
 	return nil
 }
 
@@ -126,6 +177,7 @@ func (s *Store) CompareAndSwap(ctx context.Context, key string, expectedVersion
 		return 0, fmt.Errorf("state/cas: store is closed")
 	}
 
+	s.expire(key)
 	e, exists := s.data[key]
 	currentVersion := e.version
 	if !exists {
@@ -139,6 +191,7 @@ func (s *Store) CompareAndSwap(ctx context.Context, key string, expectedVersion
 	oldValue := e.value
 	e.version = currentVersion + 1
 	e.value = value
+	e.expires = time.Time{}
 	s.data[key] = e
 
 	s.broadcast(state.StateChange{
@@ -152,6 +205,75 @@ func (s *Store) CompareAndSwap(ctx context.Context, key string, expectedVersion
 	return e.version, nil
 }
 
+// CompareAndSwapValue sets key to new only if its current value is
+// reflect.DeepEqual to old, and reports whether it did. A nil old matches a
+// missing key. The check and write happen under the store's lock.
+func (s *Store) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("state/compare_and_swap: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false, fmt.Errorf("state/compare_and_swap: store is closed")
+	}
+
+	s.expire(key)
+	if !reflect.DeepEqual(s.data[key].value, old) {
+		return false, nil
+	}
+	s.write(key, new, time.Time{})
+	return true, nil
+}
+
+// Increment adds delta to the integer stored at key, treating a missing key
+// as 0, and returns the result, which is stored as an int64. The read and
+// write happen under the store's lock. Unlike other writes, Increment keeps
+// an expiry set by SetWithTTL, so a counter can expire as a whole.
+func (s *Store) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("state/increment: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, fmt.Errorf("state/increment: store is closed")
+	}
+
+	s.expire(key)
+	n, err := state.ToInt64(s.data[key].value)
+	if err != nil {
+		return 0, fmt.Errorf("state/increment: key %q: %w", key, err)
+	}
+	n += delta
+	s.write(key, n, s.data[key].expires)
+	return n, nil
+}
+
+// write stores value under key with the given expiry, increments its
+// version and notifies watchers. Must be called with s.mu held.
+func (s *Store) write(key string, value any, expires time.Time) {
+	s.expire(key)
+	e := s.data[key]
+	oldValue := e.value
+	e.version++
+	e.value = value
+	e.expires = expires
+	s.data[key] = e
+
+	s.broadcast(state.StateChange{
+		Key:      key,
+		OldValue: oldValue,
+		Value:    value,
+		Op:       state.OpSet,
+		Version:  e.version,
+	})
+}
+
 // Delete removes the given key. Deleting a non-existent key is a no-op.
 func (s *Store) Delete(ctx context.Context, key string) error {
 	if err := ctx.Err(); err != nil {
@@ -165,6 +287,7 @@ func (s *Store) Delete(ctx context.Context, key string) error {
 		return fmt.Errorf("state/delete: store is closed")
 	}
 
+	s.expire(key)
 	e, exists := s.data[key]
 	if !exists {
 		return nil
@@ -183,6 +306,157 @@ func (s *Store) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Transaction runs fn with the store's write lock held and then applies the
+// writes it staged, or none of them if fn returns an error. Transactions are
+// serializable: no other operation runs while fn executes, so fn must be
+// short and must not call the store directly, which would deadlock. Watchers
+// are notified once per changed key after all writes are applied.
+func (s *Store) Transaction(ctx context.Context, fn func(tx state.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("state/transaction: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("state/transaction: store is closed")
+	}
+
+	tx := &memTx{store: s}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("state/transaction: %w", err)
+	}
+
+	var changes []state.StateChange
+	for _, w := range tx.buf.Writes() {
+		s.expire(w.Key)
+		e, exists := s.data[w.Key]
+		if w.Delete {
+			if !exists {
+				continue
+			}
+			delete(s.data, w.Key)
+			changes = append(changes, state.StateChange{
+				Key: w.Key, OldValue: e.value, Op: state.OpDelete, Version: e.version + 1,
+			})
+			continue
+		}
+		oldValue := e.value
+		e.version++
+		e.value = w.Value
+		e.expires = time.Time{}
+		s.data[w.Key] = e
+		changes = append(changes, state.StateChange{
+			Key: w.Key, OldValue: oldValue, Value: w.Value, Op: state.OpSet, Version: e.version,
+		})
+	}
+	for _, change := range changes {
+		s.broadcast(change)
+	}
+	return nil
+}
+
+// memTx is the state.Tx of an in-memory transaction. It runs with the
+// store's lock held.
+type memTx struct {
+	store *Store
+	buf   state.TxBuffer
+}
+
+func (t *memTx) Get(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("state/transaction: %w", err)
+	}
+	if w, ok := t.buf.Lookup(key); ok {
+		if w.Delete {
+			return nil, nil
+		}
+		return w.Value, nil
+	}
+	e, _ := t.store.live(key)
+	return e.value, nil
+}
+
+func (t *memTx) Set(_ context.Context, key string, value any) error {
+	t.buf.Set(key, value)
+	return nil
+}
+
+func (t *memTx) Delete(_ context.Context, key string) error {
+	t.buf.Delete(key)
+	return nil
+}
+
+// List returns the keys starting with prefix in lexical order.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("state/list: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("state/list: store is closed")
+	}
+	return s.keys(prefix), nil
+}
+
+// Scan streams the keys starting with prefix, in lexical order, together with
+// their values. The matching keys are collected under the read lock when
+// iteration starts; each value is then read as it is yielded, so keys deleted
+// during the scan are skipped and the store may be used from the loop body.
+func (s *Store) Scan(ctx context.Context, prefix string) iter.Seq2[state.Entry, error] {
+	return func(yield func(state.Entry, error) bool) {
+		if err := ctx.Err(); err != nil {
+			yield(state.Entry{}, fmt.Errorf("state/scan: %w", err))
+			return
+		}
+		s.mu.RLock()
+		if s.closed {
+			s.mu.RUnlock()
+			yield(state.Entry{}, fmt.Errorf("state/scan: store is closed"))
+			return
+		}
+		keys := s.keys(prefix)
+		s.mu.RUnlock()
+
+		for _, key := range keys {
+			if err := ctx.Err(); err != nil {
+				yield(state.Entry{}, fmt.Errorf("state/scan: %w", err))
+				return
+			}
+			s.mu.RLock()
+			e, ok := s.live(key)
+			s.mu.RUnlock()
+			if !ok {
+				continue
+			}
+			if !yield(state.Entry{Key: key, Value: e.value}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// keys returns the sorted keys starting with prefix. Must be called with
+// s.mu held.
+func (s *Store) keys(prefix string) []string {
+	var keys []string
+	now := s.now()
+	for key, e := range s.data {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Watch returns an iter.Seq2 stream of StateChange notifications for the
 // given key. The subscription is established eagerly before Watch returns,
 // so events produced after this call but before the caller starts iterating
@@ -252,6 +526,55 @@ func (s *Store) Watch(ctx context.Context, key string) iter.Seq2[state.StateChan
 	}
 }
 
+// live returns the entry for key unless it is missing or expired. Must be
+// called with s.mu held.
+func (s *Store) live(key string) (entry, bool) {
+	e, ok := s.data[key]
+	if !ok || e.expired(s.now()) {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// expire removes key if it has expired and notifies its watchers. Must be
+// called with s.mu held for writing.
+func (s *Store) expire(key string) {
+	e, ok := s.data[key]
+	if !ok || !e.expired(s.now()) {
+		return
+	}
+	delete(s.data, key)
+	s.broadcast(state.StateChange{
+		Key:      key,
+		OldValue: e.value,
+		Op:       state.OpDelete,
+		Version:  e.version + 1,
+	})
+}
+
+// sweep periodically removes expired keys until the store is closed. It is
+// started by the first SetWithTTL.
+func (s *Store) sweep() {
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		for key := range s.data {
+			s.expire(key)
+		}
+		s.mu.Unlock()
+	}
+}
+
 // Close releases resources and signals all active watcher iterators to exit
 // by closing the done channel. Individual watcher channels are not closed —
 // iterators observe the done signal via select and unsubscribe themselves.
@@ -318,3 +641,7 @@ func (s *Store) removeWatcherOrphan(key string, target chan state.StateChange) {
 // Compile-time checks.
 var _ state.Store = (*Store)(nil)
 var _ state.VersionedStore = (*Store)(nil)
+var _ state.AtomicStore = (*Store)(nil)
+var _ state.TransactionalStore = (*Store)(nil)
+var _ state.ScannableStore = (*Store)(nil)
+var _ state.ExpiringStore = (*Store)(nil)
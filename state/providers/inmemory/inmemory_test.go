@@ -2,6 +2,7 @@ package inmemory
 
 import (
 	"context"
+	"errors"
 	"iter"
 	"sync"
 	"testing"
@@ -581,3 +582,228 @@ func TestDeleteIncrementsVersion(t *testing.T) {
 
 // Compile-time check for VersionedStore.
 var _ state.VersionedStore = (*Store)(nil)
+
+func TestCompareAndSwapValue(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	defer s.Close()
+
+	next, stop := pullWatch(t, s, ctx, "flag")
+	defer stop()
+
+	swapped, err := s.CompareAndSwapValue(ctx, "flag", nil, "agent-a")
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	swapped, err = s.CompareAndSwapValue(ctx, "flag", nil, "agent-b")
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	v, version, _ := s.GetVersioned(ctx, "flag")
+	assert.Equal(t, "agent-a", v)
+	assert.Equal(t, uint64(1), version)
+
+	change := recvOne(t, next)
+	assert.Equal(t, "agent-a", change.Value)
+	assert.Equal(t, uint64(1), change.Version)
+}
+
+func TestIncrement(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	defer s.Close()
+
+	const workers, perWorker = 10, 100
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				_, err := s.Increment(ctx, "hits", 1)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	v, version, err := s.GetVersioned(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers*perWorker), v)
+	assert.Equal(t, uint64(workers*perWorker), version)
+
+	require.NoError(t, s.Set(ctx, "name", "ada"))
+	_, err = s.Increment(ctx, "name", 1)
+	assert.ErrorIs(t, err, state.ErrNotInteger)
+
+	require.NoError(t, s.Close())
+	_, err = s.Increment(ctx, "hits", 1)
+	assert.Error(t, err)
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	t.Run("commit", func(t *testing.T) {
+		s := New()
+		defer s.Close()
+		require.NoError(t, s.Set(ctx, "a", 1))
+		require.NoError(t, s.Set(ctx, "b", 1))
+		next, stop := pullWatch(t, s, ctx, "a")
+		defer stop()
+
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			v, err := tx.Get(ctx, "a")
+			if err != nil {
+				return err
+			}
+			_ = tx.Set(ctx, "a", v.(int)+1)
+			_ = tx.Set(ctx, "a", v.(int)+2)
+			_ = tx.Delete(ctx, "b")
+			_ = tx.Delete(ctx, "missing")
+			got, _ := tx.Get(ctx, "a")
+			assert.Equal(t, 3, got, "reads see staged writes")
+			return nil
+		})
+		require.NoError(t, err)
+
+		v, version, _ := s.GetVersioned(ctx, "a")
+		assert.Equal(t, 3, v)
+		assert.Equal(t, uint64(2), version)
+		b, _ := s.Get(ctx, "b")
+		assert.Nil(t, b)
+
+		// One notification for "a", carrying the committed value.
+		change := recvOne(t, next)
+		assert.Equal(t, state.StateChange{Key: "a", OldValue: 1, Value: 3, Op: state.OpSet, Version: 2}, change)
+		require.NoError(t, s.Set(ctx, "a", 4))
+		assert.Equal(t, 4, recvOne(t, next).Value)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		s := New()
+		defer s.Close()
+		require.NoError(t, s.Set(ctx, "a", 1))
+
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			_ = tx.Set(ctx, "a", 2)
+			_ = tx.Set(ctx, "b", 2)
+			return boom
+		})
+		assert.ErrorIs(t, err, boom)
+		v, _ := s.Get(ctx, "a")
+		assert.Equal(t, 1, v)
+		v, _ = s.Get(ctx, "b")
+		assert.Nil(t, v)
+	})
+
+	t.Run("closed store", func(t *testing.T) {
+		s := New()
+		require.NoError(t, s.Close())
+		err := s.Transaction(ctx, func(state.Tx) error { return nil })
+		assert.Error(t, err)
+	})
+}
+
+func TestListAndScan(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	defer s.Close()
+
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeSession, "b"), 2))
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeSession, "a"), 1))
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeSession, "c"), 3))
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeAgent, "a"), "agent"))
+
+	keys, err := s.List(ctx, state.ScopePrefix(state.ScopeSession))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"session:a", "session:b", "session:c"}, keys)
+
+	all, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// The store can be modified from the loop body; deleted keys are skipped.
+	var entries []state.Entry
+	for entry, err := range s.Scan(ctx, state.ScopePrefix(state.ScopeSession)) {
+		require.NoError(t, err)
+		entries = append(entries, entry)
+		if entry.Key == "session:a" {
+			require.NoError(t, s.Delete(ctx, "session:b"))
+		}
+	}
+	assert.Equal(t, []state.Entry{{Key: "session:a", Value: 1}, {Key: "session:c", Value: 3}}, entries)
+
+	require.NoError(t, s.Close())
+	_, err = s.List(ctx, "")
+	assert.Error(t, err)
+	for _, err := range s.Scan(ctx, "") {
+		assert.Error(t, err)
+	}
+}
+
+func TestSetWithTTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expired keys are hidden", func(t *testing.T) {
+		s := New(WithSweepInterval(time.Hour))
+		defer s.Close()
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		require.NoError(t, s.SetWithTTL(ctx, "session:a", "x", time.Minute))
+		require.NoError(t, s.SetWithTTL(ctx, "session:b", "y", time.Minute))
+		require.NoError(t, s.Set(ctx, "session:b", "z")) // clears the TTL
+		require.NoError(t, s.SetWithTTL(ctx, "session:c", "w", 0))
+
+		now = now.Add(2 * time.Minute)
+		v, err := s.Get(ctx, "session:a")
+		require.NoError(t, err)
+		assert.Nil(t, v)
+		keys, _ := s.List(ctx, "session:")
+		assert.Equal(t, []string{"session:b", "session:c"}, keys)
+
+		// Writing an expired key starts it afresh.
+		n, err := s.Increment(ctx, "session:a", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("increment keeps the expiry", func(t *testing.T) {
+		s := New(WithSweepInterval(time.Hour))
+		defer s.Close()
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		require.NoError(t, s.SetWithTTL(ctx, "hits", int64(1), time.Minute))
+		n, err := s.Increment(ctx, "hits", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		now = now.Add(2 * time.Minute)
+		v, err := s.Get(ctx, "hits")
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("watchers see expiry as delete", func(t *testing.T) {
+		s := New(WithSweepInterval(5 * time.Millisecond))
+		defer s.Close()
+		next, stop := pullWatch(t, s, ctx, "k")
+		defer stop()
+
+		require.NoError(t, s.SetWithTTL(ctx, "k", "v", 20*time.Millisecond))
+		assert.Equal(t, state.OpSet, recvOne(t, next).Op)
+
+		change := recvOne(t, next)
+		assert.Equal(t, state.StateChange{Key: "k", OldValue: "v", Op: state.OpDelete, Version: 2}, change)
+		s.mu.RLock()
+		assert.Empty(t, s.data, "expired key was not removed")
+		s.mu.RUnlock()
+	})
+
+	t.Run("closed store", func(t *testing.T) {
+		s := New()
+		require.NoError(t, s.Close())
+		assert.Error(t, s.SetWithTTL(ctx, "k", "v", time.Second))
+	})
+}
@@ -0,0 +1,62 @@
+// Package redis provides a Redis-backed implementation of the [state.Store]
+// and [state.VersionedStore] interfaces, so state can be shared by several
+// agent replicas. It uses github.com/redis/go-redis/v9 and requires Lua
+// scripting and pub/sub on the server.
+//
+// The store registers itself under the name "redis" via init():
+//
+//	import _ "github.com/lookatitude/beluga-ai/v2/state/providers/redis"
+//
+//	store, err := state.New("redis", state.Config{Extra: map[string]any{
+//	    "addr": "localhost:6379",
+//	    "ttl":  "24h", // optional
+//	}})
+//
+// Or created directly:
+//
+//	store := redis.New("localhost:6379", redis.WithTTL(24*time.Hour))
+//	defer store.Close()
+//
+// # Keys and Values
+//
+// Each key is stored as a Redis hash named by the store prefix ("beluga:state:"
+// by default) followed by the key, so scoped keys from [state.ScopedKey] form
+// Redis namespaces such as "beluga:state:session:*". Values are encoded with
+// the store's [state.Codec], JSON unless [WithCodec] or the "codec" config key
+// selects another; [Store.GetInto] decodes them into a typed destination.
+// Writes, version increments and change notifications happen atomically in
+// Lua scripts, so they are safe across replicas. With the JSON codec
+// [Store.CompareAndSwapValue] and [Store.Increment] are scripts too; with
+// other codecs, which the server cannot interpret, they run as optimistic
+// transactions retried on conflict.
+//
+// # Listing Keys
+//
+// [Store.List] and [Store.Scan] walk the keys under the prefix with SCAN, so
+// they never block the server. Scan fetches values one batch at a time and
+// may yield a key twice if keys change during the scan. With a Redis Cluster
+// client only the node serving the request is scanned.
+//
+// # Transactions
+//
+// [Store.Transaction] commits staged writes with MULTI/EXEC. Keys read in the
+// transaction are WATCHed: if another writer changes one before the commit,
+// nothing is written and [state.ErrTxConflict] is returned.
+//
+// # TTL
+//
+// [WithTTL] expires keys a fixed time after each write; [Store.SetWithTTL]
+// overrides it for one write. Both map to PEXPIRE. Without WithTTL,
+// [Store.Increment] keeps the expiry of the key it updates when it runs as a
+// script (the JSON codec). Watchers report expiry as
+// an OpDelete change when the server publishes keyspace notifications for
+// expired keys (notify-keyspace-events containing "Kx").
+//
+// # Watch Support
+//
+// Changes are published on a pub/sub channel named after the key, so watchers
+// see writes from every replica. The go-redis client resubscribes after a
+// dropped connection; the watcher then re-reads the key and yields a single
+// change covering anything missed while disconnected. Iterators end when the
+// watch context is cancelled or the store is closed.
+package redis
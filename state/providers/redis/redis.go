@@ -0,0 +1,863 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/state"
+)
+
+func init() {
+	state.Register("redis", func(cfg state.Config) (state.Store, error) {
+		return NewFromConfig(cfg)
+	})
+}
+
+// watchBuffer is the number of notifications buffered per watcher before
+// the caller starts iterating.
+const watchBuffer = 16
+
+// frameLua defines frame, which builds the pub/sub payload of a change:
+// "<op> <version> " followed by the old and new values, each either "-" when
+// absent or "<length>:<bytes>". Lengths keep the payload binary-safe for any
+// codec.
+const frameLua = `
+local function frame(op, ver, old, val)
+  local function part(s)
+    if s then return #s .. ':' .. s end
+    return '-'
+  end
+  return op .. ' ' .. tostring(ver) .. ' ' .. part(old) .. part(val)
+end
+`
+
+// writeLua stores the Lua local val under KEYS[1], bumps the version, applies
+// the TTL in ARGV[2] (milliseconds, 0 for none) and publishes the change on
+// KEYS[2]. It leaves the new version in the local ver.
+const writeLua = frameLua + `
+local prev = redis.call('HGET', KEYS[1], 'val')
+local ver = redis.call('HINCRBY', KEYS[1], 'ver', 1)
+redis.call('HSET', KEYS[1], 'val', val)
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+  redis.call('PEXPIRE', KEYS[1], ttl)
+else
+  redis.call('PERSIST', KEYS[1])
+end
+redis.call('PUBLISH', KEYS[2], frame('set', ver, prev, val))
+`
+
+// setScript stores ARGV[1]. If ARGV[3] is not empty, the write only happens
+// when the current version equals it. It returns {applied, version}.
+var setScript = goredis.NewScript(`
+local cur = tonumber(redis.call('HGET', KEYS[1], 'ver') or '0')
+if ARGV[3] ~= '' and cur ~= tonumber(ARGV[3]) then
+  return {0, cur}
+end
+local val = ARGV[1]
+` + writeLua + `
+return {1, ver}
+`)
+
+// swapScript stores ARGV[1] if the current value's encoding equals ARGV[3],
+// where "null" also matches a missing key. It returns 1 if it did. It is only
+// used with the JSON codec.
+var swapScript = goredis.NewScript(`
+local cur = redis.call('HGET', KEYS[1], 'val')
+if ARGV[3] == 'null' then
+  if cur and cur ~= 'null' then
+    return 0
+  end
+elseif cur ~= ARGV[3] then
+  return 0
+end
+local val = ARGV[1]
+` + writeLua + `
+return 1
+`)
+
+// incrScript adds ARGV[1] to the integer at KEYS[1] and returns the result.
+// Without a TTL in ARGV[2] it keeps the key's current expiry. It is only used
+// with the JSON codec.
+var incrScript = goredis.NewScript(`
+if tonumber(ARGV[2]) == 0 then
+  local pttl = redis.call('PTTL', KEYS[1])
+  if pttl > 0 then
+    ARGV[2] = pttl
+  end
+end
+local cur = redis.call('HGET', KEYS[1], 'val')
+local n = 0
+if cur and cur ~= 'null' then
+  n = tonumber(cur)
+  if not n or n % 1 ~= 0 then
+    return redis.error_reply('NOTINT value is not an integer')
+  end
+end
+n = n + tonumber(ARGV[1])
+local val = string.format('%d', n)
+` + writeLua + `
+return n
+`)
+
+// deleteScript removes KEYS[1] and publishes the change on KEYS[2].
+var deleteScript = goredis.NewScript(frameLua + `
+local old = redis.call('HGET', KEYS[1], 'val')
+if not old then
+  return 0
+end
+local ver = tonumber(redis.call('HGET', KEYS[1], 'ver') or '0') + 1
+redis.call('DEL', KEYS[1])
+redis.call('PUBLISH', KEYS[2], frame('delete', ver, old, nil))
+return 1
+`)
+
+// Store is a Redis-backed implementation of state.VersionedStore. Each key is
+// a Redis hash holding the encoded value and its version; changes are
+// published on a channel of the same name for Watch.
+type Store struct {
+	client     goredis.UniversalClient
+	ownsClient bool
+	prefix     string
+	ttl        time.Duration
+	codec      state.Codec
+	db         int // for keyspace notification channels
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{} // closed on Close() to end watchers
+}
+
+// Compile-time interface checks.
+var (
+	_ state.Store              = (*Store)(nil)
+	_ state.VersionedStore     = (*Store)(nil)
+	_ state.AtomicStore        = (*Store)(nil)
+	_ state.TransactionalStore = (*Store)(nil)
+	_ state.ScannableStore     = (*Store)(nil)
+	_ state.ExpiringStore      = (*Store)(nil)
+	_ state.TypedStore         = (*Store)(nil)
+)
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithClient sets the Redis client. The store does not close a client
+// supplied this way.
+func WithClient(c goredis.UniversalClient) Option {
+	return func(s *Store) { s.client = c }
+}
+
+// WithPrefix sets the prefix prepended to every key. The default is
+// "beluga:state:", so state.ScopedKey(state.ScopeSession, "k") is stored as
+// "beluga:state:session:k".
+func WithPrefix(prefix string) Option {
+	return func(s *Store) { s.prefix = prefix }
+}
+
+// WithTTL sets the time to live applied to keys on every Set. Zero, the
+// default, keeps keys until they are deleted.
+func WithTTL(d time.Duration) Option {
+	return func(s *Store) { s.ttl = d }
+}
+
+// WithCodec sets the codec used to serialize values. The default is
+// state.JSONCodec{}. With other codecs CompareAndSwapValue and Increment run
+// as optimistic transactions instead of server-side scripts.
+func WithCodec(c state.Codec) Option {
+	return func(s *Store) { s.codec = c }
+}
+
+// New creates a Store connected to the Redis server at addr. The address is
+// ignored if WithClient is given.
+func New(addr string, opts ...Option) *Store {
+	s := &Store{
+		prefix: "beluga:state:",
+		codec:  state.JSONCodec{},
+		done:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.client == nil {
+		s.client = goredis.NewClient(&goredis.Options{Addr: addr})
+		s.ownsClient = true
+	}
+	if c, ok := s.client.(*goredis.Client); ok {
+		s.db = c.Options().DB
+	}
+	return s
+}
+
+// NewFromConfig creates a Store from a state.Config. The codec is selected by
+// state.CodecFromConfig. Recognised Extra keys are "addr" (required unless
+// "client" is set), "password", "db", "prefix", "ttl" (a duration string such
+// as "10m"), "codec" and "client" (a goredis.UniversalClient).
+func NewFromConfig(cfg state.Config) (*Store, error) {
+	codec, err := state.CodecFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []Option{WithCodec(codec)}
+	if p, ok := cfg.Extra["prefix"].(string); ok {
+		opts = append(opts, WithPrefix(p))
+	}
+	if v, ok := cfg.Extra["ttl"].(string); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "redis: invalid ttl %q: %w", v, err)
+		}
+		opts = append(opts, WithTTL(d))
+	}
+	if c, ok := cfg.Extra["client"].(goredis.UniversalClient); ok {
+		return New("", append(opts, WithClient(c))...), nil
+	}
+
+	addr, _ := cfg.Extra["addr"].(string)
+	if addr == "" {
+		return nil, core.Errorf(core.ErrInvalidInput, "redis: addr is required")
+	}
+	password, _ := cfg.Extra["password"].(string)
+	var db int
+	switch v := cfg.Extra["db"].(type) {
+	case int:
+		db = v
+	case float64:
+		db = int(v)
+	}
+	s := New("", append(opts, WithClient(goredis.NewClient(&goredis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})))...)
+	s.ownsClient = true
+	return s, nil
+}
+
+// Get retrieves the value for the given key. Returns nil, nil if the key
+// does not exist.
+func (s *Store) Get(ctx context.Context, key string) (any, error) {
+	value, _, err := s.getVersioned(ctx, "get", key)
+	return value, err
+}
+
+// GetVersioned retrieves the value and current version for the given key.
+// Returns (nil, 0, nil) if the key does not exist.
+func (s *Store) GetVersioned(ctx context.Context, key string) (any, uint64, error) {
+	return s.getVersioned(ctx, "get_versioned", key)
+}
+
+func (s *Store) getVersioned(ctx context.Context, op, key string) (any, uint64, error) {
+	if err := s.check(op); err != nil {
+		return nil, 0, err
+	}
+	return s.read(ctx, s.client, op, key)
+}
+
+// read fetches key's value and version through c.
+func (s *Store) read(ctx context.Context, c goredis.Cmdable, op, key string) (any, uint64, error) {
+	fields, err := c.HMGet(ctx, s.prefix+key, "val", "ver").Result()
+	if err != nil {
+		return nil, 0, core.Errorf(core.ErrProviderDown, "redis: %s %q: %w", op, key, err)
+	}
+	raw, ok := fields[0].(string)
+	if !ok {
+		return nil, 0, nil
+	}
+	value, err := s.decode(raw)
+	if err != nil {
+		return nil, 0, core.Errorf(core.ErrInvalidInput, "redis: %s %q: %w", op, key, err)
+	}
+	ver, _ := fields[1].(string)
+	version, _ := strconv.ParseUint(ver, 10, 64)
+	return value, version, nil
+}
+
+// GetInto decodes the value for key into dest with the store's codec. It
+// returns state.ErrKeyNotFound if the key does not exist.
+func (s *Store) GetInto(ctx context.Context, key string, dest any) error {
+	if err := s.check("get_into"); err != nil {
+		return err
+	}
+	raw, err := s.client.HGet(ctx, s.prefix+key, "val").Result()
+	if errors.Is(err, goredis.Nil) {
+		return core.Errorf(core.ErrNotFound, "redis: get_into %q: %w", key, state.ErrKeyNotFound)
+	}
+	if err != nil {
+		return core.Errorf(core.ErrProviderDown, "redis: get_into %q: %w", key, err)
+	}
+	if err := s.codec.Decode([]byte(raw), dest); err != nil {
+		return core.Errorf(core.ErrInvalidInput, "redis: get_into %q: %w", key, err)
+	}
+	return nil
+}
+
+// Set stores a value under the given key, incrementing its version. The value
+// must be encodable by the store's codec. Get returns the codec's decoding
+// into an any (with JSON, numbers as float64 and objects as map[string]any);
+// use GetInto to read it back as its original type.
+func (s *Store) Set(ctx context.Context, key string, value any) error {
+	_, _, err := s.set(ctx, "set", key, value, s.ttl, "")
+	return err
+}
+
+// SetWithTTL is like Set but expires the key after ttl, overriding the store's
+// default TTL.
+func (s *Store) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	_, _, err := s.set(ctx, "set", key, value, ttl, "")
+	return err
+}
+
+// CompareAndSwap atomically sets the value for key only if the current version
+// matches expectedVersion. Returns the new version on success. For new keys,
+// expectedVersion must be 0.
+func (s *Store) CompareAndSwap(ctx context.Context, key string, expectedVersion uint64, value any) (uint64, error) {
+	applied, version, err := s.set(ctx, "cas", key, value, s.ttl, strconv.FormatUint(expectedVersion, 10))
+	if err != nil {
+		return 0, err
+	}
+	if !applied {
+		return version, state.ErrVersionMismatch
+	}
+	return version, nil
+}
+
+func (s *Store) set(ctx context.Context, op, key string, value any, ttl time.Duration, expected string) (bool, uint64, error) {
+	if err := s.check(op); err != nil {
+		return false, 0, err
+	}
+	data, err := s.codec.Encode(value)
+	if err != nil {
+		return false, 0, core.Errorf(core.ErrInvalidInput, "redis: %s %q: encode value: %w", op, key, err)
+	}
+	res, err := setScript.Run(ctx, s.client, []string{s.prefix + key, s.prefix + key},
+		string(data), ttl.Milliseconds(), expected).Int64Slice()
+	if err != nil {
+		return false, 0, core.Errorf(core.ErrProviderDown, "redis: %s %q: %w", op, key, err)
+	}
+	return res[0] == 1, uint64(res[1]), nil
+}
+
+// CompareAndSwapValue sets key to new only if its current value equals old,
+// and reports whether it did. A nil old matches a missing key. With the JSON
+// codec values are compared by their encoding, so int(1) matches a stored
+// float64(1), and the comparison and write are a single atomic script on the
+// server. With other codecs the decoded values are compared with
+// reflect.DeepEqual in an optimistic transaction. Either way the operation is
+// atomic across replicas.
+func (s *Store) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	if err := s.check("compare_and_swap"); err != nil {
+		return false, err
+	}
+	if !s.scripted() {
+		var swapped bool
+		err := s.update(ctx, "compare_and_swap", key, func(cur any) (any, error) {
+			swapped = reflect.DeepEqual(cur, old)
+			if !swapped {
+				return nil, errSkip
+			}
+			return new, nil
+		})
+		return swapped, err
+	}
+	oldData, err := s.codec.Encode(old)
+	if err != nil {
+		return false, core.Errorf(core.ErrInvalidInput, "redis: compare_and_swap %q: encode old value: %w", key, err)
+	}
+	newData, err := s.codec.Encode(new)
+	if err != nil {
+		return false, core.Errorf(core.ErrInvalidInput, "redis: compare_and_swap %q: encode value: %w", key, err)
+	}
+	swapped, err := swapScript.Run(ctx, s.client, []string{s.prefix + key, s.prefix + key},
+		string(newData), s.ttl.Milliseconds(), string(oldData)).Int()
+	if err != nil {
+		return false, core.Errorf(core.ErrProviderDown, "redis: compare_and_swap %q: %w", key, err)
+	}
+	return swapped == 1, nil
+}
+
+// Increment adds delta to the integer stored at key, treating a missing key
+// as 0, and returns the result. With the JSON codec it runs as a single
+// atomic script on the server and counters are Lua numbers, exact up to 2^53;
+// with other codecs it runs as an optimistic transaction and stores an int64.
+// Either way it is atomic across replicas. Like any write it applies the
+// store's TTL; without one, the script keeps an expiry set by SetWithTTL, so
+// a counter can expire as a whole.
+func (s *Store) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := s.check("increment"); err != nil {
+		return 0, err
+	}
+	if !s.scripted() {
+		var n int64
+		err := s.update(ctx, "increment", key, func(cur any) (any, error) {
+			var err error
+			if n, err = state.ToInt64(cur); err != nil {
+				return nil, core.Errorf(core.ErrInvalidInput, "redis: increment %q: %w", key, err)
+			}
+			n += delta
+			return n, nil
+		})
+		return n, err
+	}
+	n, err := incrScript.Run(ctx, s.client, []string{s.prefix + key, s.prefix + key},
+		delta, s.ttl.Milliseconds()).Int64()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "NOTINT") {
+			return 0, core.Errorf(core.ErrInvalidInput, "redis: increment %q: %w", key, state.ErrNotInteger)
+		}
+		return 0, core.Errorf(core.ErrProviderDown, "redis: increment %q: %w", key, err)
+	}
+	return n, nil
+}
+
+// maxUpdateRetries bounds the optimistic retry loop in update.
+const maxUpdateRetries = 100
+
+// errSkip is returned by an update function to leave the key unchanged.
+var errSkip = errors.New("skip")
+
+// scripted reports whether values can be interpreted by the server-side
+// scripts, which understand JSON only.
+func (s *Store) scripted() bool {
+	_, ok := s.codec.(state.JSONCodec)
+	return ok
+}
+
+// update replaces the value of key with fn's result in a transaction,
+// retrying on conflict. fn returns errSkip to leave the key unchanged.
+func (s *Store) update(ctx context.Context, op, key string, fn func(cur any) (any, error)) error {
+	for range maxUpdateRetries {
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			cur, err := tx.Get(ctx, key)
+			if err != nil {
+				return err
+			}
+			next, err := fn(cur)
+			if err != nil {
+				return err
+			}
+			return tx.Set(ctx, key, next)
+		})
+		switch {
+		case errors.Is(err, errSkip):
+			return nil
+		case errors.Is(err, state.ErrTxConflict):
+			continue
+		}
+		return err
+	}
+	return core.Errorf(core.ErrProviderDown, "redis: %s %q: %w", op, key, state.ErrTxConflict)
+}
+
+// Delete removes the given key. Deleting a non-existent key is a no-op.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.check("delete"); err != nil {
+		return err
+	}
+	if err := deleteScript.Run(ctx, s.client, []string{s.prefix + key, s.prefix + key}).Err(); err != nil {
+		return core.Errorf(core.ErrProviderDown, "redis: delete %q: %w", key, err)
+	}
+	return nil
+}
+
+// Transaction runs fn and applies the writes it staged atomically with
+// MULTI/EXEC, or none of them if fn returns an error. Isolation is
+// optimistic: every key read through tx is WATCHed, and if any of them is
+// modified by another writer before the commit, nothing is applied and
+// ErrTxConflict is returned; the caller may retry. Keys only written are not
+// checked. Watchers are notified once per changed key when the commit
+// executes.
+func (s *Store) Transaction(ctx context.Context, fn func(tx state.Tx) error) error {
+	if err := s.check("transaction"); err != nil {
+		return err
+	}
+
+	var fnErr error
+	err := s.client.Watch(ctx, func(rtx *goredis.Tx) error {
+		tx := &redisTx{store: s, rtx: rtx}
+		if fnErr = fn(tx); fnErr != nil {
+			return fnErr
+		}
+		writes := tx.buf.Writes()
+		if len(writes) == 0 {
+			return nil
+		}
+
+		encoded := make([]string, len(writes))
+		for i, w := range writes {
+			if w.Delete {
+				continue
+			}
+			data, err := s.codec.Encode(w.Value)
+			if err != nil {
+				return core.Errorf(core.ErrInvalidInput, "redis: transaction: encode value for %q: %w", w.Key, err)
+			}
+			encoded[i] = string(data)
+		}
+		_, err := rtx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for i, w := range writes {
+				keys := []string{s.prefix + w.Key, s.prefix + w.Key}
+				if w.Delete {
+					deleteScript.Eval(ctx, pipe, keys)
+				} else {
+					setScript.Eval(ctx, pipe, keys, encoded[i], s.ttl.Milliseconds(), "")
+				}
+			}
+			return nil
+		})
+		return err
+	})
+	switch {
+	case fnErr != nil:
+		return fnErr
+	case errors.Is(err, goredis.TxFailedErr):
+		return core.Errorf(core.ErrInvalidInput, "redis: transaction: %w", state.ErrTxConflict)
+	case err != nil:
+		var ce *core.Error
+		if errors.As(err, &ce) {
+			return err
+		}
+		return core.Errorf(core.ErrProviderDown, "redis: transaction: %w", err)
+	}
+	return nil
+}
+
+// redisTx is the state.Tx of a Redis transaction.
+type redisTx struct {
+	store *Store
+	rtx   *goredis.Tx
+	buf   state.TxBuffer
+}
+
+func (t *redisTx) Get(ctx context.Context, key string) (any, error) {
+	if w, ok := t.buf.Lookup(key); ok {
+		if w.Delete {
+			return nil, nil
+		}
+		return w.Value, nil
+	}
+	if err := t.rtx.Watch(ctx, t.store.prefix+key).Err(); err != nil {
+		return nil, core.Errorf(core.ErrProviderDown, "redis: transaction: watch %q: %w", key, err)
+	}
+	value, _, err := t.store.read(ctx, t.rtx, "transaction: get", key)
+	return value, err
+}
+
+func (t *redisTx) Set(_ context.Context, key string, value any) error {
+	t.buf.Set(key, value)
+	return nil
+}
+
+func (t *redisTx) Delete(_ context.Context, key string) error {
+	t.buf.Delete(key)
+	return nil
+}
+
+// List returns the keys starting with prefix in lexical order. Keys are
+// found with SCAN, so listing does not block the server; the result itself is
+// collected in memory, use Scan for large key spaces.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := s.check("list"); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	err := s.scanKeys(ctx, "list", prefix, func(keys []string) bool {
+		for _, key := range keys {
+			seen[strings.TrimPrefix(key, s.prefix)] = true
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Scan streams the keys starting with prefix together with their values. It
+// walks the key space with SCAN and fetches values one batch at a time, so
+// only a batch is held in memory. As with SCAN, keys are unordered and a key
+// may be yielded more than once if the key space changes during the scan.
+func (s *Store) Scan(ctx context.Context, prefix string) iter.Seq2[state.Entry, error] {
+	return func(yield func(state.Entry, error) bool) {
+		if err := s.check("scan"); err != nil {
+			yield(state.Entry{}, err)
+			return
+		}
+		var stopped bool
+		err := s.scanKeys(ctx, "scan", prefix, func(keys []string) bool {
+			cmds, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.HGet(ctx, key, "val")
+				}
+				return nil
+			})
+			// Replies such as nil for a deleted key are handled per command
+			// below; anything else means the batch failed.
+			var reply goredis.Error
+			if err != nil && !errors.As(err, &reply) {
+				stopped = true
+				yield(state.Entry{}, core.Errorf(core.ErrProviderDown, "redis: scan: %w", err))
+				return false
+			}
+			for i, cmd := range cmds {
+				raw, err := cmd.(*goredis.StringCmd).Result()
+				if errors.Is(err, goredis.Nil) {
+					continue // deleted since it was listed
+				}
+				key := strings.TrimPrefix(keys[i], s.prefix)
+				var value any
+				if err == nil {
+					value, err = s.decode(raw)
+				}
+				if err != nil {
+					err = core.Errorf(core.ErrInvalidInput, "redis: scan %q: %w", key, err)
+					stopped = !yield(state.Entry{}, err)
+				} else {
+					stopped = !yield(state.Entry{Key: key, Value: value}, nil)
+				}
+				if stopped {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil && !stopped {
+			yield(state.Entry{}, err)
+		}
+	}
+}
+
+// scanBatch is the COUNT hint passed to SCAN.
+const scanBatch = 100
+
+// scanKeys calls fn with each batch of Redis keys under prefix until fn
+// returns false or the scan completes.
+func (s *Store) scanKeys(ctx context.Context, op, prefix string, fn func(keys []string) bool) error {
+	match := globEscaper.Replace(s.prefix+prefix) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, scanBatch).Result()
+		if err != nil {
+			return core.Errorf(core.ErrProviderDown, "redis: %s: %w", op, err)
+		}
+		if len(keys) > 0 && !fn(keys) {
+			return nil
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// globEscaper escapes the characters special in SCAN MATCH patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// Watch returns an iter.Seq2 stream of StateChange notifications for the
+// given key, delivered through Redis pub/sub so changes made by any replica
+// sharing the server are seen. The subscription is confirmed before Watch
+// returns; notifications are then buffered (capacity 16) until the caller
+// iterates.
+//
+// The client reconnects and resubscribes automatically after a dropped
+// connection. Notifications published while disconnected are lost, so on
+// resubscribing the key is re-read and, if it changed meanwhile, a single
+// StateChange with the current value is yielded.
+//
+// Expiry of the key is reported as an OpDelete change carrying the last value
+// the watcher saw. It is detected through Redis keyspace notifications, which
+// must be enabled on the server with notify-keyspace-events containing "Kx"
+// (for example "Kx" or "KEA"); otherwise expiry goes unreported.
+//
+// The iterator ends when ctx is cancelled, the store is closed, or the
+// caller breaks out of the loop.
+func (s *Store) Watch(ctx context.Context, key string) iter.Seq2[state.StateChange, error] {
+	fail := func(err error) iter.Seq2[state.StateChange, error] {
+		return func(yield func(state.StateChange, error) bool) {
+			yield(state.StateChange{}, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return fail(core.Errorf(core.ErrTimeout, "redis: watch %q: %w", key, err))
+	}
+	if err := s.check("watch"); err != nil {
+		return fail(err)
+	}
+
+	keyspace := fmt.Sprintf("__keyspace@%d__:%s", s.db, s.prefix+key)
+	ps := s.client.Subscribe(ctx, s.prefix+key, keyspace)
+	for range 2 { // one confirmation per channel
+		if _, err := ps.Receive(ctx); err != nil {
+			_ = ps.Close()
+			return fail(core.Errorf(core.ErrProviderDown, "redis: watch %q: %w", key, err))
+		}
+	}
+	last, lastVersion, err := s.GetVersioned(ctx, key)
+	if err != nil {
+		_ = ps.Close()
+		return fail(err)
+	}
+	msgs := ps.ChannelWithSubscriptions(goredis.WithChannelSize(watchBuffer))
+
+	var closeOnce sync.Once
+	unsub := func() { closeOnce.Do(func() { _ = ps.Close() }) }
+
+	// Release the subscription when ctx ends or the store closes, even if
+	// the caller never iterates.
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+		}
+		unsub()
+	}()
+
+	return func(yield func(state.StateChange, error) bool) {
+		defer unsub()
+		for {
+			var msg any
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			case m, ok := <-msgs:
+				if !ok {
+					return
+				}
+				msg = m
+			}
+
+			var (
+				change state.StateChange
+				err    error
+			)
+			switch m := msg.(type) {
+			case *goredis.Message:
+				if m.Channel == keyspace {
+					if m.Payload != "expired" || last == nil {
+						continue
+					}
+					change = state.StateChange{Key: key, OldValue: last, Op: state.OpDelete, Version: lastVersion + 1}
+					break
+				}
+				change, err = s.decodeChange(key, m.Payload)
+			case *goredis.Subscription:
+				// Resubscribed after a reconnect: catch up on what was missed.
+				change, err = s.resync(ctx, key, last, lastVersion)
+				if err == nil && change.Op == "" {
+					continue
+				}
+			default:
+				continue
+			}
+			if err == nil {
+				last, lastVersion = change.Value, change.Version
+			}
+			if !yield(change, err) {
+				return
+			}
+		}
+	}
+}
+
+// resync compares key against the last state a watcher saw and returns the
+// change that brings the watcher up to date, or a zero StateChange if none.
+func (s *Store) resync(ctx context.Context, key string, last any, lastVersion uint64) (state.StateChange, error) {
+	value, version, err := s.GetVersioned(ctx, key)
+	if err != nil {
+		return state.StateChange{}, err
+	}
+	switch {
+	case value == nil && last != nil:
+		return state.StateChange{Key: key, OldValue: last, Op: state.OpDelete, Version: lastVersion + 1}, nil
+	case value != nil && version != lastVersion:
+		return state.StateChange{Key: key, OldValue: last, Value: value, Op: state.OpSet, Version: version}, nil
+	}
+	return state.StateChange{}, nil
+}
+
+// Close ends all watchers and, if the store created its client, closes it.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	if s.ownsClient {
+		return s.client.Close()
+	}
+	return nil
+}
+
+// check returns an error if the store is closed.
+func (s *Store) check(op string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return core.Errorf(core.ErrInvalidInput, "redis: %s: %w", op, state.ErrStoreClosed)
+	}
+	return nil
+}
+
+// decodeChange parses a payload built by frame in the scripts.
+func (s *Store) decodeChange(key, payload string) (state.StateChange, error) {
+	change, err := s.parseFrame(key, payload)
+	if err != nil {
+		return state.StateChange{}, core.Errorf(core.ErrInvalidInput, "redis: watch %q: decode notification: %w", key, err)
+	}
+	return change, nil
+}
+
+func (s *Store) parseFrame(key, payload string) (state.StateChange, error) {
+	op, rest, ok1 := strings.Cut(payload, " ")
+	ver, rest, ok2 := strings.Cut(rest, " ")
+	if !ok1 || !ok2 {
+		return state.StateChange{}, errors.New("malformed header")
+	}
+	change := state.StateChange{Key: key, Op: state.ChangeOp(op)}
+	change.Version, _ = strconv.ParseUint(ver, 10, 64)
+	for _, dst := range []*any{&change.OldValue, &change.Value} {
+		if strings.HasPrefix(rest, "-") {
+			rest = rest[1:]
+			continue
+		}
+		size, body, ok := strings.Cut(rest, ":")
+		n, err := strconv.Atoi(size)
+		if !ok || err != nil || n < 0 || n > len(body) {
+			return state.StateChange{}, errors.New("malformed value")
+		}
+		if *dst, err = s.decode(body[:n]); err != nil {
+			return state.StateChange{}, err
+		}
+		rest = body[n:]
+	}
+	return change, nil
+}
+
+// decode decodes a stored value with the store's codec.
+func (s *Store) decode(raw string) (any, error) {
+	var v any
+	if err := s.codec.Decode([]byte(raw), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
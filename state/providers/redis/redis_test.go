@@ -0,0 +1,520 @@
+package redis
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/state"
+)
+
+func newTestStore(t *testing.T, opts ...Option) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	s := New(mr.Addr(), opts...)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, mr
+}
+
+// next reads one change from ch.
+func next(t *testing.T, ch <-chan state.StateChange) state.StateChange {
+	t.Helper()
+	select {
+	case c := <-ch:
+		return c
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for change")
+		return state.StateChange{}
+	}
+}
+
+// watch consumes store.Watch in the background.
+func watch(t *testing.T, ctx context.Context, s state.Store, key string) <-chan state.StateChange {
+	t.Helper()
+	seq := s.Watch(ctx, key)
+	ch := make(chan state.StateChange, 16)
+	go func() {
+		for change, err := range seq {
+			if err != nil {
+				continue
+			}
+			ch <- change
+		}
+	}()
+	return ch
+}
+
+func TestRegistry(t *testing.T) {
+	assert.Contains(t, state.List(), "redis")
+
+	mr := miniredis.RunT(t)
+	s, err := state.New("redis", state.Config{Extra: map[string]any{
+		"addr":   mr.Addr(),
+		"prefix": "app:",
+		"ttl":    "1m",
+	}})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set(context.Background(), "k", "v"))
+	assert.True(t, mr.Exists("app:k"))
+	assert.Equal(t, time.Minute, mr.TTL("app:k"))
+}
+
+func TestNewFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra map[string]any
+	}{
+		{name: "missing addr", extra: nil},
+		{name: "invalid ttl", extra: map[string]any{"addr": "localhost:6379", "ttl": "soon"}},
+		{name: "unknown codec", extra: map[string]any{"addr": "localhost:6379", "codec": "xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromConfig(state.Config{Extra: tt.extra})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStore_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestStore(t)
+
+	v, err := s.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeSession, "user"), map[string]any{"name": "ada", "age": 36}))
+	assert.True(t, mr.Exists("beluga:state:session:user"))
+
+	v, version, err := s.GetVersioned(ctx, "session:user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "ada", "age": float64(36)}, v)
+	assert.Equal(t, uint64(1), version)
+
+	require.NoError(t, s.Set(ctx, "session:user", "replaced"))
+	_, version, _ = s.GetVersioned(ctx, "session:user")
+	assert.Equal(t, uint64(2), version)
+
+	require.NoError(t, s.Delete(ctx, "session:user"))
+	require.NoError(t, s.Delete(ctx, "session:user"))
+	v, err = s.Get(ctx, "session:user")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	assert.Error(t, s.Set(ctx, "bad", func() {}))
+}
+
+func TestStore_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+
+	v1, err := s.CompareAndSwap(ctx, "counter", 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), v1)
+
+	cur, err := s.CompareAndSwap(ctx, "counter", 0, 2)
+	assert.ErrorIs(t, err, state.ErrVersionMismatch)
+	assert.Equal(t, uint64(1), cur)
+
+	v2, err := s.CompareAndSwap(ctx, "counter", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), v2)
+
+	got, _ := s.Get(ctx, "counter")
+	assert.Equal(t, float64(2), got)
+}
+
+func TestStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestStore(t, WithTTL(time.Minute))
+
+	require.NoError(t, s.Set(ctx, "short", "x"))
+	require.NoError(t, s.SetWithTTL(ctx, "long", "y", time.Hour))
+	assert.Equal(t, time.Hour, mr.TTL("beluga:state:long"))
+
+	mr.FastForward(2 * time.Minute)
+	v, err := s.Get(ctx, "short")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	v, _ = s.Get(ctx, "long")
+	assert.Equal(t, "y", v)
+
+	require.NoError(t, s.SetWithTTL(ctx, "long", "z", 0))
+	assert.Zero(t, mr.TTL("beluga:state:long"))
+}
+
+func TestStore_IncrementKeepsTTL(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestStore(t)
+
+	require.NoError(t, s.SetWithTTL(ctx, "hits", 1, time.Hour))
+	n, err := s.Increment(ctx, "hits", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, time.Hour, mr.TTL("beluga:state:hits"))
+
+	_, err = s.Increment(ctx, "plain", 1)
+	require.NoError(t, err)
+	assert.Zero(t, mr.TTL("beluga:state:plain"))
+}
+
+func TestStore_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mr := newTestStore(t)
+	// A second replica sharing the same server.
+	other := New(mr.Addr())
+	defer other.Close()
+
+	changes := watch(t, ctx, s, "k")
+	require.NoError(t, other.Set(ctx, "k", "a"))
+	require.NoError(t, s.Set(ctx, "k", "b"))
+	require.NoError(t, other.Delete(ctx, "k"))
+	require.NoError(t, s.Set(ctx, "unrelated", "x"))
+
+	assert.Equal(t, state.StateChange{Key: "k", Value: "a", Op: state.OpSet, Version: 1}, next(t, changes))
+	assert.Equal(t, state.StateChange{Key: "k", OldValue: "a", Value: "b", Op: state.OpSet, Version: 2}, next(t, changes))
+	assert.Equal(t, state.StateChange{Key: "k", OldValue: "b", Op: state.OpDelete, Version: 3}, next(t, changes))
+}
+
+func TestStore_WatchEnds(t *testing.T) {
+	t.Run("context cancelled", func(t *testing.T) {
+		s, _ := newTestStore(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		seq := s.Watch(ctx, "k")
+		cancel()
+		for range seq {
+			t.Fatal("unexpected change")
+		}
+	})
+
+	t.Run("store closed", func(t *testing.T) {
+		s, _ := newTestStore(t)
+		seq := s.Watch(context.Background(), "k")
+		require.NoError(t, s.Close())
+		for range seq {
+			t.Fatal("unexpected change")
+		}
+
+		var gotErr error
+		for _, err := range s.Watch(context.Background(), "k") {
+			gotErr = err
+		}
+		assert.ErrorIs(t, gotErr, state.ErrStoreClosed)
+		assert.ErrorIs(t, s.Set(context.Background(), "k", 1), state.ErrStoreClosed)
+	})
+}
+
+func TestStore_WatchResyncsAfterReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+	s := New("", WithClient(client))
+	defer s.Close()
+
+	require.NoError(t, s.Set(ctx, "k", "a"))
+	changes := watch(t, ctx, s, "k")
+
+	// Drop the connection and change the key while the watcher is
+	// disconnected, so its notification is never received.
+	mr.Close()
+	mr.HSet("beluga:state:k", "val", `"b"`, "ver", "2")
+	require.NoError(t, mr.Restart())
+
+	assert.Equal(t, state.StateChange{Key: "k", OldValue: "a", Value: "b", Op: state.OpSet, Version: 2}, next(t, changes))
+
+	// Live notifications continue after the resubscribe.
+	require.NoError(t, s.Set(ctx, "k", "c"))
+	assert.Equal(t, state.StateChange{Key: "k", OldValue: "b", Value: "c", Op: state.OpSet, Version: 3}, next(t, changes))
+}
+
+func TestStore_CompareAndSwapValue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mr := newTestStore(t)
+	other := New(mr.Addr())
+	defer other.Close()
+	changes := watch(t, ctx, s, "leader")
+
+	swapped, err := s.CompareAndSwapValue(ctx, "leader", nil, "agent-a")
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	// A second replica loses the election.
+	swapped, err = other.CompareAndSwapValue(ctx, "leader", nil, "agent-b")
+	require.NoError(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = other.CompareAndSwapValue(ctx, "leader", "agent-a", "agent-b")
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	// Values compare by JSON encoding.
+	require.NoError(t, s.Set(ctx, "n", 1))
+	swapped, err = s.CompareAndSwapValue(ctx, "n", 1.0, 2)
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	assert.Equal(t, state.StateChange{Key: "leader", Value: "agent-a", Op: state.OpSet, Version: 1}, next(t, changes))
+	assert.Equal(t, state.StateChange{Key: "leader", OldValue: "agent-a", Value: "agent-b", Op: state.OpSet, Version: 2}, next(t, changes))
+}
+
+func TestStore_Increment(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestStore(t, WithTTL(time.Minute))
+	other := New(mr.Addr())
+	defer other.Close()
+
+	const workers, perWorker = 4, 25
+	var wg sync.WaitGroup
+	for i := range workers {
+		replica := s
+		if i%2 == 1 {
+			replica = other
+		}
+		wg.Go(func() {
+			for range perWorker {
+				_, err := replica.Increment(ctx, "hits", 1)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	n, err := s.Increment(ctx, "hits", -10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers*perWorker-10), n)
+	v, version, _ := s.GetVersioned(ctx, "hits")
+	assert.Equal(t, float64(n), v)
+	assert.Equal(t, uint64(workers*perWorker+1), version)
+	assert.Equal(t, time.Minute, mr.TTL("beluga:state:hits"))
+
+	require.NoError(t, s.Set(ctx, "name", "ada"))
+	_, err = s.Increment(ctx, "name", 1)
+	assert.ErrorIs(t, err, state.ErrNotInteger)
+}
+
+func TestStore_Transaction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	boom := errors.New("boom")
+
+	t.Run("commit", func(t *testing.T) {
+		s, _ := newTestStore(t)
+		require.NoError(t, s.Set(ctx, "from", 10))
+		require.NoError(t, s.Set(ctx, "stale", "x"))
+		changes := watch(t, ctx, s, "to")
+
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			v, err := tx.Get(ctx, "from")
+			if err != nil {
+				return err
+			}
+			n := v.(float64)
+			_ = tx.Set(ctx, "from", n-3)
+			_ = tx.Set(ctx, "to", 1)
+			_ = tx.Set(ctx, "to", 3)
+			_ = tx.Delete(ctx, "stale")
+			got, _ := tx.Get(ctx, "to")
+			assert.Equal(t, 3, got, "reads see staged writes")
+			return nil
+		})
+		require.NoError(t, err)
+
+		v, _ := s.Get(ctx, "from")
+		assert.Equal(t, float64(7), v)
+		v, _ = s.Get(ctx, "stale")
+		assert.Nil(t, v)
+
+		// One notification for "to", carrying the committed value.
+		assert.Equal(t, state.StateChange{Key: "to", Value: float64(3), Op: state.OpSet, Version: 1}, next(t, changes))
+		require.NoError(t, s.Set(ctx, "to", 4))
+		assert.Equal(t, float64(4), next(t, changes).Value)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		s, _ := newTestStore(t)
+		require.NoError(t, s.Set(ctx, "a", 1))
+
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			_ = tx.Set(ctx, "a", 2)
+			_ = tx.Set(ctx, "b", 2)
+			return boom
+		})
+		assert.ErrorIs(t, err, boom)
+		v, _ := s.Get(ctx, "a")
+		assert.Equal(t, float64(1), v)
+		v, _ = s.Get(ctx, "b")
+		assert.Nil(t, v)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		s, mr := newTestStore(t)
+		other := New(mr.Addr())
+		defer other.Close()
+		require.NoError(t, s.Set(ctx, "a", 1))
+
+		err := s.Transaction(ctx, func(tx state.Tx) error {
+			if _, err := tx.Get(ctx, "a"); err != nil {
+				return err
+			}
+			// Another replica writes a key this transaction read.
+			require.NoError(t, other.Set(ctx, "a", 5))
+			return tx.Set(ctx, "b", 2)
+		})
+		assert.ErrorIs(t, err, state.ErrTxConflict)
+		v, _ := s.Get(ctx, "b")
+		assert.Nil(t, v)
+	})
+}
+
+func TestStore_ListAndScan(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestStore(t)
+	// Keys outside the store prefix are never listed.
+	mr.Set("other:session:x", "1")
+
+	for i := range 250 {
+		require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeSession, fmt.Sprintf("k%03d", i)), i))
+	}
+	require.NoError(t, s.Set(ctx, state.ScopedKey(state.ScopeAgent, "k"), "agent"))
+	require.NoError(t, s.Set(ctx, "glob*", 1))
+	require.NoError(t, s.Set(ctx, "globber", 2))
+
+	keys, err := s.List(ctx, state.ScopePrefix(state.ScopeSession))
+	require.NoError(t, err)
+	require.Len(t, keys, 250)
+	assert.Equal(t, "session:k000", keys[0])
+	assert.Equal(t, "session:k249", keys[249])
+
+	keys, err = s.List(ctx, "glob*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"glob*"}, keys, "pattern characters are matched literally")
+
+	seen := make(map[string]any)
+	for entry, err := range s.Scan(ctx, state.ScopePrefix(state.ScopeSession)) {
+		require.NoError(t, err)
+		seen[entry.Key] = entry.Value
+	}
+	assert.Len(t, seen, 250)
+	assert.Equal(t, float64(7), seen["session:k007"])
+
+	var n int
+	for _, err := range s.Scan(ctx, "") {
+		require.NoError(t, err)
+		if n++; n == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, n)
+
+	require.NoError(t, s.Close())
+	_, err = s.List(ctx, "")
+	assert.ErrorIs(t, err, state.ErrStoreClosed)
+}
+
+func TestStore_WatchExpiry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mr := newTestStore(t)
+	changes := watch(t, ctx, s, "session:k")
+
+	require.NoError(t, state.SetWithTTL(ctx, s, "session:k", "v", time.Second))
+	assert.Equal(t, state.OpSet, next(t, changes).Op)
+
+	// miniredis does not emit keyspace notifications; publish the one a
+	// configured server sends when the key expires.
+	mr.FastForward(2 * time.Second)
+	mr.Publish("__keyspace@0__:beluga:state:session:k", "expired")
+
+	assert.Equal(t, state.StateChange{Key: "session:k", OldValue: "v", Op: state.OpDelete, Version: 2}, next(t, changes))
+	v, err := s.Get(ctx, "session:k")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+type cart struct {
+	Items []string
+	Total int
+}
+
+func init() {
+	gob.Register(cart{})
+}
+
+func TestStore_GetInto(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+
+	require.NoError(t, s.Set(ctx, "cart", cart{Items: []string{"a"}, Total: 1}))
+	var got cart
+	require.NoError(t, state.GetInto(ctx, s, "cart", &got))
+	assert.Equal(t, cart{Items: []string{"a"}, Total: 1}, got)
+
+	err := state.GetInto(ctx, s, "missing", &got)
+	assert.ErrorIs(t, err, state.ErrKeyNotFound)
+}
+
+func TestStore_GobCodec(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	st, err := state.New("redis", state.Config{Extra: map[string]any{"addr": mr.Addr(), "codec": "gob"}})
+	require.NoError(t, err)
+	defer st.Close()
+	s := st.(*Store)
+	changes := watch(t, ctx, s, "cart")
+
+	// Values keep their Go types, including through binary notifications.
+	v1, v2 := cart{Items: []string{"a"}, Total: 1}, cart{Items: []string{"a", "b"}, Total: 2}
+	require.NoError(t, s.Set(ctx, "cart", v1))
+	v, err := s.Get(ctx, "cart")
+	require.NoError(t, err)
+	assert.Equal(t, v1, v)
+
+	swapped, err := s.CompareAndSwapValue(ctx, "cart", v2, v1)
+	require.NoError(t, err)
+	assert.False(t, swapped)
+	swapped, err = s.CompareAndSwapValue(ctx, "cart", v1, v2)
+	require.NoError(t, err)
+	assert.True(t, swapped)
+	require.NoError(t, s.Delete(ctx, "cart"))
+
+	assert.Equal(t, state.StateChange{Key: "cart", Value: v1, Op: state.OpSet, Version: 1}, next(t, changes))
+	assert.Equal(t, state.StateChange{Key: "cart", OldValue: v1, Value: v2, Op: state.OpSet, Version: 2}, next(t, changes))
+	assert.Equal(t, state.StateChange{Key: "cart", OldValue: v2, Op: state.OpDelete, Version: 3}, next(t, changes))
+
+	// Increment runs as a transaction and stores an int64.
+	const workers, perWorker = 4, 10
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				_, err := s.Increment(ctx, "hits", 1)
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+	v, err = s.Get(ctx, "hits")
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers*perWorker), v)
+
+	require.NoError(t, s.Set(ctx, "name", "ada"))
+	_, err = s.Increment(ctx, "name", 1)
+	assert.ErrorIs(t, err, state.ErrNotInteger)
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"iter"
+	"time"
 )
 
 // ReducerFunc merges an old value with a new value. The old value may be nil
@@ -102,6 +103,66 @@ func (rs *ReducerStore) CompareAndSwap(ctx context.Context, key string, expected
 	return rs.inner.CompareAndSwap(ctx, key, expectedVersion, value)
 }
 
+// CompareAndSwapValue delegates to the inner store through the package-level
+// CompareAndSwap. Reducers are not applied.
+func (rs *ReducerStore) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	return CompareAndSwap(ctx, rs.inner, key, old, new)
+}
+
+// Increment delegates to the inner store through the package-level
+// Increment. Reducers are not applied.
+func (rs *ReducerStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	return Increment(ctx, rs.inner, key, delta)
+}
+
+// Transaction delegates to the inner store through the package-level
+// Transaction. Reducers are applied to writes inside the transaction, merging
+// with the value the transaction sees.
+func (rs *ReducerStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	return Transaction(ctx, rs.inner, func(tx Tx) error {
+		return fn(&reducerTx{Tx: tx, rs: rs})
+	})
+}
+
+// reducerTx applies reducers to Set within a transaction.
+type reducerTx struct {
+	Tx
+	rs *ReducerStore
+}
+
+func (t *reducerTx) Set(ctx context.Context, key string, value any) error {
+	reducer := t.rs.reducerFor(key)
+	if reducer == nil {
+		return t.Tx.Set(ctx, key, value)
+	}
+	old, err := t.Tx.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return t.Tx.Set(ctx, key, reducer(old, value))
+}
+
+// SetWithTTL delegates to the inner store through the package-level
+// SetWithTTL. Reducers are not applied: the value replaces the stored one.
+func (rs *ReducerStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return SetWithTTL(ctx, rs.inner, key, value, ttl)
+}
+
+// GetInto delegates to the inner store through GetInto.
+func (rs *ReducerStore) GetInto(ctx context.Context, key string, dest any) error {
+	return GetInto(ctx, rs.inner, key, dest)
+}
+
+// List delegates to the inner store through ListKeys.
+func (rs *ReducerStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return ListKeys(ctx, rs.inner, prefix)
+}
+
+// Scan delegates to the inner store through Scan.
+func (rs *ReducerStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	return Scan(ctx, rs.inner, prefix)
+}
+
 // Delete delegates to the inner store.
 func (rs *ReducerStore) Delete(ctx context.Context, key string) error {
 	return rs.inner.Delete(ctx, key)
@@ -130,3 +191,8 @@ func (rs *ReducerStore) reducerFor(key string) ReducerFunc {
 // Compile-time checks.
 var _ Store = (*ReducerStore)(nil)
 var _ VersionedStore = (*ReducerStore)(nil)
+var _ AtomicStore = (*ReducerStore)(nil)
+var _ TransactionalStore = (*ReducerStore)(nil)
+var _ ScannableStore = (*ReducerStore)(nil)
+var _ ExpiringStore = (*ReducerStore)(nil)
+var _ TypedStore = (*ReducerStore)(nil)
@@ -0,0 +1,62 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrScanUnsupported is returned by ListKeys and Scan for stores that do not
+// implement ScannableStore.
+var ErrScanUnsupported = errors.New("state: store does not support listing keys")
+
+// Entry is a key and its value, as yielded by Scan.
+type Entry struct {
+	Key   string
+	Value any
+}
+
+// ScannableStore extends Store with key enumeration.
+type ScannableStore interface {
+	Store
+
+	// List returns the keys starting with prefix in lexical order. An empty
+	// prefix lists every key.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Scan streams the keys starting with prefix together with their
+	// values. Keys written or deleted during the scan may or may not be
+	// included. The order is implementation-defined. Errors are reported by
+	// yielding a zero Entry with a non-nil error.
+	Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error]
+}
+
+// ScopePrefix returns the key prefix shared by all keys in scope, for use
+// with ListKeys and Scan.
+func ScopePrefix(scope Scope) string {
+	return ScopedKey(scope, "")
+}
+
+// ListKeys returns the keys of s starting with prefix in lexical order. It
+// returns ErrScanUnsupported if s does not implement ScannableStore.
+func ListKeys(ctx context.Context, s Store, prefix string) ([]string, error) {
+	ss, ok := s.(ScannableStore)
+	if !ok {
+		return nil, core.Errorf(core.ErrInvalidInput, "state: list: %w", ErrScanUnsupported)
+	}
+	return ss.List(ctx, prefix)
+}
+
+// Scan streams the entries of s whose keys start with prefix. It yields
+// ErrScanUnsupported if s does not implement ScannableStore.
+func Scan(ctx context.Context, s Store, prefix string) iter.Seq2[Entry, error] {
+	ss, ok := s.(ScannableStore)
+	if !ok {
+		return func(yield func(Entry, error) bool) {
+			yield(Entry{}, core.Errorf(core.ErrInvalidInput, "state: scan: %w", ErrScanUnsupported))
+		}
+	}
+	return ss.Scan(ctx, prefix)
+}
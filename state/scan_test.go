@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockScanStore is a mockVersionedStore that can list its keys.
+type mockScanStore struct {
+	*mockVersionedStore
+}
+
+func (m *mockScanStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *mockScanStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		keys, _ := m.List(ctx, prefix)
+		for _, key := range keys {
+			v, _ := m.Get(ctx, key)
+			if !yield(Entry{Key: key, Value: v}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestScopePrefix(t *testing.T) {
+	assert.Equal(t, "session:", ScopePrefix(ScopeSession))
+	assert.True(t, strings.HasPrefix(ScopedKey(ScopeSession, "k"), ScopePrefix(ScopeSession)))
+}
+
+func TestListKeysAndScan(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := ListKeys(ctx, newMockStore(), "")
+	assert.ErrorIs(t, err, ErrScanUnsupported)
+	for _, err := range Scan(ctx, newMockStore(), "") {
+		assert.ErrorIs(t, err, ErrScanUnsupported)
+	}
+
+	inner := &mockScanStore{mockVersionedStore: newMockVersionedStore()}
+	require.NoError(t, inner.Set(ctx, ScopedKey(ScopeSession, "a"), 1))
+	require.NoError(t, inner.Set(ctx, ScopedKey(ScopeGlobal, "b"), 2))
+
+	// Middleware forwards listing to the wrapped store.
+	s := ApplyMiddleware(inner, WithTracing(), WithHooks(Hooks{}), WithOwnership(NewOwnershipManager()))
+	keys, err := ListKeys(ctx, s, ScopePrefix(ScopeSession))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"session:a"}, keys)
+
+	var entries []Entry
+	for entry, err := range Scan(ctx, s, "") {
+		require.NoError(t, err)
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, []Entry{{Key: "global:b", Value: 2}, {Key: "session:a", Value: 1}}, entries)
+}
@@ -107,6 +107,11 @@ func ScopedKey(scope Scope, key string) string {
 
 // Config holds configuration for creating a Store via the registry.
 type Config struct {
+	// Codec serializes values for providers that store bytes. If nil, the
+	// codec named by Extra["codec"] ("json" or "gob") is used, defaulting to
+	// JSON. See CodecFromConfig.
+	Codec Codec
+
 	// Extra holds provider-specific configuration.
 	Extra map[string]any
 }
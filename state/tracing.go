@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"iter"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/o11y"
 )
@@ -106,5 +107,115 @@ func (s *tracedStore) Close() error {
 	return nil
 }
 
-// Ensure tracedStore implements Store at compile time.
-var _ Store = (*tracedStore)(nil)
+func (s *tracedStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	ctx, span := o11y.StartSpan(ctx, "state.set_with_ttl", o11y.Attrs{
+		o11y.AttrOperationName: "state.set_with_ttl",
+	})
+	defer span.End()
+
+	err := SetWithTTL(ctx, s.next, key, value, ttl)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return nil
+}
+
+func (s *tracedStore) CompareAndSwapValue(ctx context.Context, key string, old, new any) (bool, error) {
+	ctx, span := o11y.StartSpan(ctx, "state.compare_and_swap", o11y.Attrs{
+		o11y.AttrOperationName: "state.compare_and_swap",
+	})
+	defer span.End()
+
+	swapped, err := CompareAndSwap(ctx, s.next, key, old, new)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return false, err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return swapped, nil
+}
+
+func (s *tracedStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	ctx, span := o11y.StartSpan(ctx, "state.increment", o11y.Attrs{
+		o11y.AttrOperationName: "state.increment",
+	})
+	defer span.End()
+
+	n, err := Increment(ctx, s.next, key, delta)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return 0, err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return n, nil
+}
+
+// Transaction wraps the whole transaction, including fn, in a single span.
+func (s *tracedStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	ctx, span := o11y.StartSpan(ctx, "state.transaction", o11y.Attrs{
+		o11y.AttrOperationName: "state.transaction",
+	})
+	defer span.End()
+
+	err := Transaction(ctx, s.next, fn)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return nil
+}
+
+func (s *tracedStore) GetInto(ctx context.Context, key string, dest any) error {
+	ctx, span := o11y.StartSpan(ctx, "state.get_into", o11y.Attrs{
+		o11y.AttrOperationName: "state.get_into",
+	})
+	defer span.End()
+
+	err := GetInto(ctx, s.next, key, dest)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return nil
+}
+
+func (s *tracedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	ctx, span := o11y.StartSpan(ctx, "state.list", o11y.Attrs{
+		o11y.AttrOperationName: "state.list",
+	})
+	defer span.End()
+
+	keys, err := ListKeys(ctx, s.next, prefix)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(o11y.StatusError, err.Error())
+		return nil, err
+	}
+	span.SetStatus(o11y.StatusOK, "")
+	return keys, nil
+}
+
+// Scan is not traced: like Watch, its iteration may be long-lived, so it
+// delegates directly.
+func (s *tracedStore) Scan(ctx context.Context, prefix string) iter.Seq2[Entry, error] {
+	return Scan(ctx, s.next, prefix)
+}
+
+// Ensure tracedStore implements the optional Store interfaces at compile
+// time.
+var (
+	_ AtomicStore        = (*tracedStore)(nil)
+	_ TransactionalStore = (*tracedStore)(nil)
+	_ ScannableStore     = (*tracedStore)(nil)
+	_ ExpiringStore      = (*tracedStore)(nil)
+	_ TypedStore         = (*tracedStore)(nil)
+)
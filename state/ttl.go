@@ -0,0 +1,34 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrTTLUnsupported is returned by SetWithTTL for stores that do not
+// implement ExpiringStore.
+var ErrTTLUnsupported = errors.New("state: store does not support expiry")
+
+// ExpiringStore extends Store with per-key expiry.
+type ExpiringStore interface {
+	Store
+
+	// SetWithTTL stores value under key and removes the key once ttl has
+	// elapsed; a ttl <= 0 stores it without expiry. Watchers observe the
+	// expiry as an OpDelete change.
+	SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error
+}
+
+// SetWithTTL stores value under key on s and removes it once ttl has
+// elapsed. It returns ErrTTLUnsupported if s does not implement
+// ExpiringStore.
+func SetWithTTL(ctx context.Context, s Store, key string, value any, ttl time.Duration) error {
+	es, ok := s.(ExpiringStore)
+	if !ok {
+		return core.Errorf(core.ErrInvalidInput, "state: set with ttl: %w", ErrTTLUnsupported)
+	}
+	return es.SetWithTTL(ctx, key, value, ttl)
+}
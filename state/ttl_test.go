@@ -0,0 +1,41 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTTLStore is a mockVersionedStore that records TTLs.
+type mockTTLStore struct {
+	*mockVersionedStore
+	ttls map[string]time.Duration
+}
+
+func (m *mockTTLStore) SetWithTTL(ctx context.Context, key string, value any, ttl time.Duration) error {
+	m.ttls[key] = ttl
+	return m.Set(ctx, key, value)
+}
+
+func TestSetWithTTL(t *testing.T) {
+	ctx := context.Background()
+
+	err := SetWithTTL(ctx, newMockStore(), "k", 1, time.Minute)
+	assert.ErrorIs(t, err, ErrTTLUnsupported)
+
+	inner := &mockTTLStore{mockVersionedStore: newMockVersionedStore(), ttls: make(map[string]time.Duration)}
+	var hooked []string
+	s := ApplyMiddleware(inner, WithTracing(), WithHooks(Hooks{
+		BeforeSet: func(_ context.Context, key string, _ any) error {
+			hooked = append(hooked, key)
+			return nil
+		},
+	}))
+
+	require.NoError(t, SetWithTTL(ctx, s, ScopedKey(ScopeSession, "k"), 1, time.Minute))
+	assert.Equal(t, time.Minute, inner.ttls["session:k"])
+	assert.Equal(t, []string{"session:k"}, hooked)
+}
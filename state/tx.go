@@ -0,0 +1,109 @@
+package state
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// ErrTxConflict is returned by Transaction when a key read by the
+// transaction was modified by another writer before it committed. No change
+// was applied; the caller may retry.
+var ErrTxConflict = errors.New("state: transaction conflict")
+
+// ErrTxUnsupported is returned by Transaction for stores that do not
+// implement TransactionalStore.
+var ErrTxUnsupported = errors.New("state: store does not support transactions")
+
+// Tx is the view of the store inside a transaction. Writes are staged and
+// applied together when the transaction function returns nil; reads see the
+// transaction's own staged writes.
+type Tx interface {
+	// Get returns the value of key, including writes staged by this
+	// transaction. Returns nil, nil if the key does not exist.
+	Get(ctx context.Context, key string) (any, error)
+
+	// Set stages a write of value under key.
+	Set(ctx context.Context, key string, value any) error
+
+	// Delete stages the removal of key.
+	Delete(ctx context.Context, key string) error
+}
+
+// TransactionalStore extends Store with multi-key transactions.
+type TransactionalStore interface {
+	Store
+
+	// Transaction runs fn and then applies all writes it staged on tx, or
+	// none of them if fn returns an error, in which case that error is
+	// returned. Watchers are notified once per changed key, after the
+	// commit. The isolation level is implementation-defined and documented
+	// by each store. fn must not use the store directly.
+	Transaction(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// Transaction runs fn as a transaction on s. It returns ErrTxUnsupported if
+// s does not implement TransactionalStore.
+func Transaction(ctx context.Context, s Store, fn func(tx Tx) error) error {
+	ts, ok := s.(TransactionalStore)
+	if !ok {
+		return core.Errorf(core.ErrInvalidInput, "state: transaction: %w", ErrTxUnsupported)
+	}
+	return ts.Transaction(ctx, fn)
+}
+
+// TxWrite is a write staged by a transaction.
+type TxWrite struct {
+	// Key is the affected key.
+	Key string
+	// Value is the new value; ignored for deletes.
+	Value any
+	// Delete reports whether the key is removed.
+	Delete bool
+}
+
+// TxBuffer stages transaction writes for store implementations. It keeps
+// one write per key, the last one, in first-write order. The zero value is
+// ready to use.
+type TxBuffer struct {
+	writes []TxWrite
+	index  map[string]int
+}
+
+// Set stages a write of value under key.
+func (b *TxBuffer) Set(key string, value any) {
+	b.put(TxWrite{Key: key, Value: value})
+}
+
+// Delete stages the removal of key.
+func (b *TxBuffer) Delete(key string) {
+	b.put(TxWrite{Key: key, Delete: true})
+}
+
+// Lookup returns the staged write for key, if any.
+func (b *TxBuffer) Lookup(key string) (TxWrite, bool) {
+	i, ok := b.index[key]
+	if !ok {
+		return TxWrite{}, false
+	}
+	return b.writes[i], true
+}
+
+// Writes returns the staged writes in the order their keys were first
+// written.
+func (b *TxBuffer) Writes() []TxWrite {
+	return b.writes
+}
+
+func (b *TxBuffer) put(w TxWrite) {
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	if i, ok := b.index[w.Key]; ok {
+		b.writes[i] = w
+		return
+	}
+	b.index[w.Key] = len(b.writes)
+	b.writes = append(b.writes, w)
+}
@@ -0,0 +1,157 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTxStore is a mockVersionedStore with all-or-nothing transactions.
+type mockTxStore struct {
+	*mockVersionedStore
+}
+
+func newMockTxStore() *mockTxStore {
+	return &mockTxStore{mockVersionedStore: newMockVersionedStore()}
+}
+
+func (m *mockTxStore) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	tx := &mockTx{store: m.mockVersionedStore}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, w := range tx.buf.Writes() {
+		if w.Delete {
+			delete(m.data, w.Key)
+			continue
+		}
+		m.versions[w.Key]++
+		m.data[w.Key] = w.Value
+	}
+	return nil
+}
+
+type mockTx struct {
+	store *mockVersionedStore
+	buf   TxBuffer
+}
+
+func (t *mockTx) Get(ctx context.Context, key string) (any, error) {
+	if w, ok := t.buf.Lookup(key); ok {
+		if w.Delete {
+			return nil, nil
+		}
+		return w.Value, nil
+	}
+	return t.store.Get(ctx, key)
+}
+
+func (t *mockTx) Set(_ context.Context, key string, value any) error {
+	t.buf.Set(key, value)
+	return nil
+}
+
+func (t *mockTx) Delete(_ context.Context, key string) error {
+	t.buf.Delete(key)
+	return nil
+}
+
+func TestTransaction_Unsupported(t *testing.T) {
+	err := Transaction(context.Background(), newMockStore(), func(Tx) error { return nil })
+	assert.ErrorIs(t, err, ErrTxUnsupported)
+
+	// Wrapping a store without transactions does not add them.
+	s := ApplyMiddleware(newMockStore(), WithTracing(), WithHooks(Hooks{}))
+	err = Transaction(context.Background(), s, func(Tx) error { return nil })
+	assert.ErrorIs(t, err, ErrTxUnsupported)
+}
+
+func TestTxBuffer(t *testing.T) {
+	var b TxBuffer
+	b.Set("a", 1)
+	b.Set("b", 2)
+	b.Delete("a")
+	b.Set("b", 3)
+
+	assert.Equal(t, []TxWrite{{Key: "a", Delete: true}, {Key: "b", Value: 3}}, b.Writes())
+	w, ok := b.Lookup("b")
+	assert.True(t, ok)
+	assert.Equal(t, 3, w.Value)
+	_, ok = b.Lookup("c")
+	assert.False(t, ok)
+}
+
+func TestTransaction_Middleware(t *testing.T) {
+	ctx := context.Background()
+	denied := errors.New("denied")
+	om := NewOwnershipManager()
+	require.NoError(t, om.Claim("owned", "agent-a"))
+
+	inner := newMockTxStore()
+	s := ApplyMiddleware(inner,
+		WithTracing(),
+		WithHooks(Hooks{BeforeSet: func(_ context.Context, key string, _ any) error {
+			if key == "forbidden" {
+				return denied
+			}
+			return nil
+		}}),
+		WithOwnership(om),
+	)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		key     string
+		wantErr error
+	}{
+		{name: "commits", ctx: ctx, key: "free"},
+		{name: "hook veto rolls back", ctx: ctx, key: "forbidden", wantErr: denied},
+		{name: "ownership rolls back", ctx: WithOwnerID(ctx, "agent-b"), key: "owned", wantErr: ErrOwnershipDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Transaction(tt.ctx, s, func(tx Tx) error {
+				if err := tx.Set(tt.ctx, "first-"+tt.key, 1); err != nil {
+					return err
+				}
+				return tx.Set(tt.ctx, tt.key, 2)
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				v, _ := inner.Get(ctx, "first-"+tt.key)
+				assert.Nil(t, v, "staged write was applied")
+				return
+			}
+			require.NoError(t, err)
+			v, _ := inner.Get(ctx, tt.key)
+			assert.Equal(t, 2, v)
+		})
+	}
+}
+
+func TestTransaction_Reducer(t *testing.T) {
+	ctx := context.Background()
+	inner := newMockTxStore()
+	rs := NewReducerStore(inner, WithReducer("log", func(old, new any) any {
+		if old == nil {
+			return []any{new}
+		}
+		return append(old.([]any), new)
+	}))
+
+	err := rs.Transaction(ctx, func(tx Tx) error {
+		if err := tx.Set(ctx, "log", "a"); err != nil {
+			return err
+		}
+		return tx.Set(ctx, "log", "b")
+	})
+	require.NoError(t, err)
+	v, _ := inner.Get(ctx, "log")
+	assert.Equal(t, []any{"a", "b"}, v)
+}
@@ -0,0 +1,121 @@
+package tool
+
+import "context"
+
+// Annotations are hints describing how a tool behaves, used by LLMs to
+// choose tools and by guards and approval middleware to decide which calls
+// need scrutiny. They are hints: nothing enforces them, so do not rely on
+// annotations from untrusted sources for security decisions.
+type Annotations struct {
+	// ReadOnly indicates that the tool does not modify its environment.
+	ReadOnly bool
+
+	// Destructive indicates that the tool may perform destructive updates,
+	// rather than only additive ones. It is meaningful only when ReadOnly
+	// is false.
+	Destructive bool
+
+	// Idempotent indicates that calling the tool repeatedly with the same
+	// arguments has no additional effect. It is meaningful only when
+	// ReadOnly is false.
+	Idempotent bool
+}
+
+// Annotated is implemented by tools that describe their behaviour with
+// Annotations.
+type Annotated interface {
+	Annotations() Annotations
+}
+
+// OutputSchemaProvider is implemented by tools whose results have a known
+// structure, described by a JSON Schema (as a map).
+type OutputSchemaProvider interface {
+	OutputSchema() map[string]any
+}
+
+// Wrapper is implemented by tools that wrap another tool, such as those
+// returned by middleware. AnnotationsOf and OutputSchemaOf look through
+// wrappers to the tool they wrap.
+type Wrapper interface {
+	Unwrap() Tool
+}
+
+// AnnotationsOf returns the annotations of t, or false if neither t nor any
+// tool it wraps implements Annotated.
+func AnnotationsOf(t Tool) (Annotations, bool) {
+	for t != nil {
+		if a, ok := t.(Annotated); ok {
+			return a.Annotations(), true
+		}
+		w, ok := t.(Wrapper)
+		if !ok {
+			break
+		}
+		t = w.Unwrap()
+	}
+	return Annotations{}, false
+}
+
+// OutputSchemaOf returns the output schema of t, or nil if neither t nor
+// any tool it wraps implements OutputSchemaProvider.
+func OutputSchemaOf(t Tool) map[string]any {
+	for t != nil {
+		if p, ok := t.(OutputSchemaProvider); ok {
+			return p.OutputSchema()
+		}
+		w, ok := t.(Wrapper)
+		if !ok {
+			break
+		}
+		t = w.Unwrap()
+	}
+	return nil
+}
+
+// WithAnnotations returns a Middleware that attaches annotations to a tool,
+// overriding any of the tool's own.
+//
+//	deleteFile = tool.ApplyMiddleware(deleteFile, tool.WithAnnotations(tool.Annotations{Destructive: true}))
+func WithAnnotations(a Annotations) Middleware {
+	return func(t Tool) Tool {
+		return &annotatedTool{tool: t, annotations: a}
+	}
+}
+
+type annotatedTool struct {
+	tool        Tool
+	annotations Annotations
+}
+
+func (a *annotatedTool) Name() string                { return a.tool.Name() }
+func (a *annotatedTool) Description() string         { return a.tool.Description() }
+func (a *annotatedTool) InputSchema() map[string]any { return a.tool.InputSchema() }
+func (a *annotatedTool) Annotations() Annotations    { return a.annotations }
+func (a *annotatedTool) Unwrap() Tool                { return a.tool }
+
+func (a *annotatedTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
+	return a.tool.Execute(ctx, input)
+}
+
+// WithOutputSchema returns a Middleware that attaches a JSON Schema
+// describing the tool's results, overriding any of the tool's own.
+func WithOutputSchema(schema map[string]any) Middleware {
+	return func(t Tool) Tool {
+		return &outputSchemaTool{tool: t, schema: schema}
+	}
+}
+
+type outputSchemaTool struct {
+	tool   Tool
+	schema map[string]any
+}
+
+func (o *outputSchemaTool) Name() string                 { return o.tool.Name() }
+func (o *outputSchemaTool) Description() string          { return o.tool.Description() }
+func (o *outputSchemaTool) InputSchema() map[string]any  { return o.tool.InputSchema() }
+func (o *outputSchemaTool) OutputSchema() map[string]any { return o.schema }
+func (o *outputSchemaTool) Unwrap() Tool                 { return o.tool }
+
+func (o *outputSchemaTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
+	return o.tool.Execute(ctx, input)
+}
@@ -0,0 +1,66 @@
+package tool
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnnotationsOf_NotAnnotated(t *testing.T) {
+	if _, ok := AnnotationsOf(&mockTool{name: "plain"}); ok {
+		t.Error("expected no annotations for a plain tool")
+	}
+	if s := OutputSchemaOf(&mockTool{name: "plain"}); s != nil {
+		t.Errorf("expected no output schema, got %v", s)
+	}
+}
+
+func TestWithAnnotations(t *testing.T) {
+	want := Annotations{Destructive: true, Idempotent: true}
+	wrapped := ApplyMiddleware(&mockTool{name: "rm"}, WithAnnotations(want))
+
+	got, ok := AnnotationsOf(wrapped)
+	if !ok {
+		t.Fatal("expected annotations")
+	}
+	if got != want {
+		t.Errorf("annotations = %+v, want %+v", got, want)
+	}
+	if wrapped.Name() != "rm" {
+		t.Errorf("Name() = %q, want %q", wrapped.Name(), "rm")
+	}
+	result, err := wrapped.Execute(context.Background(), nil)
+	if err != nil || result == nil {
+		t.Errorf("Execute() = %v, %v", result, err)
+	}
+}
+
+func TestAnnotationsOf_ThroughMiddleware(t *testing.T) {
+	schema := map[string]any{"type": "object"}
+	base := ApplyMiddleware(&mockTool{name: "search"},
+		WithAnnotations(Annotations{ReadOnly: true}),
+		WithOutputSchema(schema),
+	)
+	wrapped := WithHooks(ApplyMiddleware(base, WithTracing(), WithRetry(2), WithTimeout(time.Second)), Hooks{})
+
+	a, ok := AnnotationsOf(wrapped)
+	if !ok || !a.ReadOnly {
+		t.Errorf("AnnotationsOf() = %+v, %v, want read-only", a, ok)
+	}
+	if got := OutputSchemaOf(wrapped); !reflect.DeepEqual(got, schema) {
+		t.Errorf("OutputSchemaOf() = %v, want %v", got, schema)
+	}
+}
+
+func TestWithAnnotations_Overrides(t *testing.T) {
+	wrapped := ApplyMiddleware(&mockTool{name: "x"},
+		WithAnnotations(Annotations{ReadOnly: true}),
+		WithAnnotations(Annotations{Destructive: true}),
+	)
+	// The first middleware is outermost, so its annotations win.
+	a, _ := AnnotationsOf(wrapped)
+	if !a.ReadOnly || a.Destructive {
+		t.Errorf("annotations = %+v, want the outermost", a)
+	}
+}
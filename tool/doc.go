@@ -99,6 +99,19 @@
 //	    tool.WithRetry(3),
 //	)
 //
+// # Annotations and Output Schemas
+//
+// Tools may describe their behaviour by implementing [Annotated], with
+// read-only, destructive and idempotent hints, and the shape of their
+// results by implementing [OutputSchemaProvider]. [WithAnnotations] and
+// [WithOutputSchema] attach these to any tool. Guards and approval
+// middleware read them with [AnnotationsOf] and [OutputSchemaOf], which see
+// through middleware that implements [Wrapper]:
+//
+//	if a, ok := tool.AnnotationsOf(t); !ok || a.Destructive {
+//	    // require approval
+//	}
+//
 // # Hooks
 //
 // [Hooks] provide lifecycle callbacks around tool execution. Compose multiple
@@ -54,9 +54,10 @@ type hookedTool struct {
 	hooks Hooks
 }
 
-func (h *hookedTool) Name() string               { return h.tool.Name() }
+func (h *hookedTool) Name() string                { return h.tool.Name() }
 func (h *hookedTool) Description() string         { return h.tool.Description() }
 func (h *hookedTool) InputSchema() map[string]any { return h.tool.InputSchema() }
+func (h *hookedTool) Unwrap() Tool                { return h.tool }
 
 func (h *hookedTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
 	name := h.tool.Name()
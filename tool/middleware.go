@@ -38,6 +38,7 @@ type timeoutTool struct {
 func (t *timeoutTool) Name() string                { return t.tool.Name() }
 func (t *timeoutTool) Description() string         { return t.tool.Description() }
 func (t *timeoutTool) InputSchema() map[string]any { return t.tool.InputSchema() }
+func (t *timeoutTool) Unwrap() Tool                { return t.tool }
 
 func (t *timeoutTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
 	ctx, cancel := context.WithTimeout(ctx, t.timeout)
@@ -71,6 +72,7 @@ type retryTool struct {
 func (r *retryTool) Name() string                { return r.tool.Name() }
 func (r *retryTool) Description() string         { return r.tool.Description() }
 func (r *retryTool) InputSchema() map[string]any { return r.tool.InputSchema() }
+func (r *retryTool) Unwrap() Tool                { return r.tool }
 
 func (r *retryTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
 	var lastErr error
@@ -29,6 +29,7 @@ type tracedTool struct {
 func (t *tracedTool) Name() string                { return t.next.Name() }
 func (t *tracedTool) Description() string         { return t.next.Description() }
 func (t *tracedTool) InputSchema() map[string]any { return t.next.InputSchema() }
+func (t *tracedTool) Unwrap() Tool                { return t.next }
 
 func (t *tracedTool) Execute(ctx context.Context, input map[string]any) (*Result, error) {
 	ctx, span := o11y.StartSpan(ctx, "tool.execute", o11y.Attrs{
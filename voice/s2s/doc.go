@@ -21,8 +21,21 @@
 //	    Recv(ctx context.Context) iter.Seq2[SessionEvent, error]
 //	    Interrupt(ctx context.Context) error
 //	    Close() error
+//	    InputFormat() AudioFormat
+//	    OutputFormat() AudioFormat
 //	}
 //
+// # Audio Formats
+//
+// Each provider has native formats, reported by InputFormat and
+// OutputFormat: openai_realtime uses 24 kHz PCM16 both ways, gemini_live and
+// nova take 16 kHz and produce 24 kHz. Pass [WithAudioFormat] to Start to use
+// a single format of your own; the session then resamples SendAudio input
+// and EventAudioOutput audio, so 16 kHz microphone audio works with any
+// provider:
+//
+//	session, err := engine.Start(ctx, s2s.WithAudioFormat(s2s.AudioFormat{SampleRate: 16000}))
+//
 // # Session Events
 //
 // Events yielded by the Recv iterator are typed by [SessionEventType]:
@@ -32,6 +45,7 @@
 //   - [EventTranscript] — user speech transcript
 //   - [EventToolCall] — tool invocation request
 //   - [EventTurnEnd] — end of conversational turn
+//   - [EventInterrupted] — an Interrupt call took effect
 //   - [EventError] — error occurred
 //
 // # Registry Pattern
@@ -58,15 +72,83 @@
 //	    }
 //	}
 //
+// # Tool Dispatch
+//
+// By default the application handles [EventToolCall] and replies with
+// SendToolResult. Pass [WithToolRegistry] to Start and the session does it
+// instead: registry tools are offered to the model, executed when called
+// (through any tool middleware such as tool.WithTimeout), and their results
+// sent back. Calls to other tools are still yielded. [WithHooks] reports
+// each dispatched call and result:
+//
+//	session, err := engine.Start(ctx,
+//	    s2s.WithToolRegistry(reg),
+//	    s2s.WithHooks(s2s.Hooks{OnToolResult: logResult}),
+//	)
+//
+// Providers apply these options, [WithAudioFormat] and
+// [WithInterruptOptions] through [WrapSession].
+//
+// # Interruption
+//
+// Session.Interrupt stops the model's current response, typically when the
+// user barges in. Every provider then behaves the same way, as configured by
+// [WithInterruptOptions]: by default output of the interrupted response that
+// has not been delivered yet is discarded, and with CancelToolCalls pending
+// tool calls are cancelled and their late results dropped. Recv confirms each
+// call with an [EventInterrupted] whose [Interruption] reports what was
+// cancelled. OpenAI Realtime cancels the response on the server, Nova Sonic
+// signals the interruption in its input stream, and Gemini Live, whose server
+// detects barge-in from the audio itself, reports it as the end of the turn.
+//
 // # Frame Processor Integration
 //
 // Use [AsFrameProcessor] to wrap an S2S engine as a voice.FrameProcessor for
 // integration with the cascading or hybrid pipeline.
 //
+// # Middleware
+//
+// [Middleware] wraps an S2S engine; compose with [ApplyMiddleware].
+//
+// [WithReconnect] re-establishes a session after a transient disconnect. It
+// emits an [EventError] with Recovering set (cause [ErrReconnecting]) so the
+// application can tell the user, restarts the session with the original
+// options, which replays the instructions and tool definitions, and queues
+// SendAudio input up to a bounded buffer while reconnecting:
+//
+//	engine = s2s.ApplyMiddleware(engine, s2s.WithReconnect(
+//	    s2s.WithReconnectBuffer(96_000),
+//	))
+//
+// Provider support for seamless resume:
+//
+//   - openai_realtime — no server-side resume; the new session starts
+//     without the earlier conversation
+//   - gemini_live — no; the Live API's session resumption handles are not
+//     used by this provider, so the conversation restarts
+//   - nova — no; Bedrock bidirectional streams cannot be resumed
+//
+// All three report a dropped connection the same way, an error event
+// followed by the end of the Recv stream, which is what WithReconnect
+// detects.
+//
+// [WithMetrics] records time to first audio (from the start of user speech
+// in SendAudio to the first [EventAudioOutput]), turns, interruptions, and
+// audio bytes in each direction as OTel instruments tagged with the
+// provider, matching the latency view of the cascading pipeline.
+//
+// [WithHistory] records the conversation: its sessions implement
+// [HistoryProvider], whose History method returns schema.Turn values built
+// from user transcripts (input) and model text and tool calls (output), one
+// per [EventTurnEnd]. [WithTranscriptSink] delivers each turn as it
+// completes. [Transcript] does the same for callers consuming events
+// themselves.
+//
 // # Hooks
 //
 // The [Hooks] struct provides callbacks for S2S-specific events: OnTurn,
-// OnInterrupt, OnToolCall, and OnError. Use [ComposeHooks] to merge hooks.
+// OnInterrupt, OnToolCall, OnToolResult, and OnError. Use [ComposeHooks] to
+// merge hooks.
 //
 // # Available Providers
 //
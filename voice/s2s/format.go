@@ -0,0 +1,190 @@
+package s2s
+
+import (
+	"context"
+	"encoding/binary"
+	"iter"
+	"math"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// AudioEncoding identifies how audio samples are encoded.
+type AudioEncoding string
+
+const (
+	// EncodingPCM16 is signed 16-bit little-endian mono PCM.
+	EncodingPCM16 AudioEncoding = "pcm16"
+)
+
+// AudioFormat describes the audio exchanged with a session.
+type AudioFormat struct {
+	// SampleRate is the sample rate in Hz.
+	SampleRate int
+
+	// Encoding is the sample encoding. Empty means EncodingPCM16.
+	Encoding AudioEncoding
+}
+
+// IsZero reports whether f is unset.
+func (f AudioFormat) IsZero() bool {
+	return f.SampleRate == 0 && f.Encoding == ""
+}
+
+func (f AudioFormat) encoding() AudioEncoding {
+	if f.Encoding == "" {
+		return EncodingPCM16
+	}
+	return f.Encoding
+}
+
+// SessionFormat reports the audio formats a session exchanges.
+type SessionFormat interface {
+	// InputFormat is the format SendAudio expects.
+	InputFormat() AudioFormat
+
+	// OutputFormat is the format of EventAudioOutput audio.
+	OutputFormat() AudioFormat
+}
+
+// WithAudioFormat sets the format the caller sends to SendAudio and wants
+// for EventAudioOutput audio. When it differs from the provider's native
+// formats, the session resamples in both directions, so for example 16 kHz
+// microphone audio can be fed to any provider. Only EncodingPCM16 is
+// supported.
+func WithAudioFormat(f AudioFormat) Option {
+	return func(cfg *Config) {
+		cfg.AudioFormat = f
+	}
+}
+
+// AdaptAudio returns a session that exchanges audio in the format want,
+// converting to and from the native formats reported by sess. WrapSession
+// calls it with Config.AudioFormat; it returns sess unchanged when want is
+// zero or already matches. If the conversion is not supported,
+// sess is closed and an error returned.
+func AdaptAudio(sess Session, want AudioFormat) (Session, error) {
+	if want.IsZero() {
+		return sess, nil
+	}
+	in, out := sess.InputFormat(), sess.OutputFormat()
+	if want.SampleRate == 0 {
+		want.SampleRate = in.SampleRate
+	}
+	for _, f := range []AudioFormat{want, in, out} {
+		if f.encoding() != EncodingPCM16 {
+			_ = sess.Close()
+			return nil, core.Errorf(core.ErrInvalidInput, "s2s: audio format: unsupported encoding %q", f.Encoding)
+		}
+	}
+	if want.SampleRate <= 0 {
+		_ = sess.Close()
+		return nil, core.Errorf(core.ErrInvalidInput, "s2s: audio format: invalid sample rate %d", want.SampleRate)
+	}
+	want.Encoding = EncodingPCM16
+	if want.SampleRate == in.SampleRate && want.SampleRate == out.SampleRate {
+		return sess, nil
+	}
+	return &formatSession{
+		Session: sess,
+		format:  want,
+		in:      newResampler(want.SampleRate, in.SampleRate),
+		out:     newResampler(out.SampleRate, want.SampleRate),
+	}, nil
+}
+
+// formatSession resamples audio between the caller's format and the
+// provider's native formats.
+type formatSession struct {
+	Session
+	format AudioFormat
+	in     *resampler
+	out    *resampler
+}
+
+func (s *formatSession) InputFormat() AudioFormat  { return s.format }
+func (s *formatSession) OutputFormat() AudioFormat { return s.format }
+
+func (s *formatSession) SendAudio(ctx context.Context, audio []byte) error {
+	converted := s.in.process(audio)
+	if len(converted) == 0 {
+		return nil
+	}
+	return s.Session.SendAudio(ctx, converted)
+}
+
+func (s *formatSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		for event, err := range s.Session.Recv(ctx) {
+			if err == nil && event.Type == EventAudioOutput {
+				event.Audio = s.out.process(event.Audio)
+				if len(event.Audio) == 0 {
+					continue
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// resampler converts a stream of PCM16 mono audio between sample rates by
+// linear interpolation. It keeps state across chunks so chunk boundaries do
+// not click. It is not safe for concurrent use; SendAudio and Recv each own
+// one.
+type resampler struct {
+	step  float64 // input samples per output sample
+	pos   float64 // next output position, relative to prev
+	prev  int16
+	have  bool   // prev holds a sample
+	carry []byte // odd trailing byte of the last chunk
+}
+
+func newResampler(from, to int) *resampler {
+	if from == to || from <= 0 || to <= 0 {
+		return nil
+	}
+	return &resampler{step: float64(from) / float64(to)}
+}
+
+// process converts one chunk. A nil resampler passes audio through.
+func (r *resampler) process(audio []byte) []byte {
+	if r == nil {
+		return audio
+	}
+	if len(r.carry) > 0 {
+		audio = append(r.carry, audio...)
+		r.carry = nil
+	}
+	if len(audio)%2 == 1 {
+		r.carry = []byte{audio[len(audio)-1]}
+		audio = audio[:len(audio)-1]
+	}
+
+	samples := make([]int16, 0, len(audio)/2+1)
+	if r.have {
+		samples = append(samples, r.prev)
+	}
+	for i := 0; i+1 < len(audio); i += 2 {
+		samples = append(samples, int16(binary.LittleEndian.Uint16(audio[i:])))
+	}
+	if len(samples) < 2 {
+		if len(samples) == 1 {
+			r.prev, r.have = samples[0], true
+		}
+		return nil
+	}
+
+	last := float64(len(samples) - 1)
+	out := make([]byte, 0, int(last/r.step+1)*2)
+	for ; r.pos < last; r.pos += r.step {
+		i := int(r.pos)
+		frac := r.pos - float64(i)
+		v := float64(samples[i])*(1-frac) + float64(samples[i+1])*frac
+		out = binary.LittleEndian.AppendUint16(out, uint16(int16(math.Round(v))))
+	}
+	r.pos -= last
+	r.prev, r.have = samples[len(samples)-1], true
+	return out
+}
@@ -0,0 +1,162 @@
+package s2s
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pcm16(samples ...int16) []byte {
+	b := make([]byte, 0, len(samples)*2)
+	for _, s := range samples {
+		b = binary.LittleEndian.AppendUint16(b, uint16(s))
+	}
+	return b
+}
+
+func samplesOf(b []byte) []int16 {
+	out := make([]int16, len(b)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(b[i*2:]))
+	}
+	return out
+}
+
+func ramp(n int) []int16 {
+	s := make([]int16, n)
+	for i := range s {
+		s[i] = int16(i * 10)
+	}
+	return s
+}
+
+func TestWithAudioFormat(t *testing.T) {
+	cfg := Config{}
+	WithAudioFormat(AudioFormat{SampleRate: 16000, Encoding: EncodingPCM16})(&cfg)
+	assert.Equal(t, AudioFormat{SampleRate: 16000, Encoding: EncodingPCM16}, cfg.AudioFormat)
+	assert.True(t, AudioFormat{}.IsZero())
+	assert.False(t, cfg.AudioFormat.IsZero())
+}
+
+func TestResampler(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		in       []int16
+		want     []int16
+	}{
+		{
+			name: "upsample 2x",
+			from: 8000, to: 16000,
+			in:   []int16{0, 100, 200},
+			want: []int16{0, 50, 100, 150},
+		},
+		{
+			name: "downsample 2x",
+			from: 16000, to: 8000,
+			in:   []int16{0, 10, 20, 30, 40},
+			want: []int16{0, 20},
+		},
+		{
+			name: "16k to 24k",
+			from: 16000, to: 24000,
+			in:   []int16{0, 300, 600, 900},
+			want: []int16{0, 200, 400, 600, 800},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newResampler(tt.from, tt.to)
+			require.NotNil(t, r)
+			assert.Equal(t, tt.want, samplesOf(r.process(pcm16(tt.in...))))
+		})
+	}
+}
+
+func TestResampler_SameRate(t *testing.T) {
+	assert.Nil(t, newResampler(24000, 24000))
+	var r *resampler
+	audio := pcm16(1, 2, 3)
+	assert.Equal(t, audio, r.process(audio))
+}
+
+func TestResampler_ChunkContinuity(t *testing.T) {
+	in := pcm16(ramp(480)...)
+
+	whole := newResampler(16000, 24000).process(in)
+
+	r := newResampler(16000, 24000)
+	var chunked []byte
+	// Odd chunk sizes split samples across chunks.
+	for start, size := 0, 0; start < len(in); start += size {
+		size = min(77, len(in)-start)
+		chunked = append(chunked, r.process(in[start:start+size])...)
+	}
+	assert.Equal(t, samplesOf(whole), samplesOf(chunked))
+	assert.Len(t, samplesOf(whole), 719)
+}
+
+func TestAdaptAudio(t *testing.T) {
+	newSess := func() *mockSession {
+		return &mockSession{recvChan: make(chan SessionEvent, 4)}
+	}
+
+	t.Run("zero format passes through", func(t *testing.T) {
+		sess := newSess()
+		got, err := AdaptAudio(sess, AudioFormat{})
+		require.NoError(t, err)
+		assert.Same(t, sess, got)
+	})
+
+	t.Run("matching format passes through", func(t *testing.T) {
+		sess := newSess()
+		got, err := AdaptAudio(sess, AudioFormat{SampleRate: 24000})
+		require.NoError(t, err)
+		assert.Same(t, sess, got)
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		sess := newSess()
+		_, err := AdaptAudio(sess, AudioFormat{SampleRate: 8000, Encoding: "mulaw"})
+		require.Error(t, err)
+		var ce *core.Error
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, core.ErrInvalidInput, ce.Code)
+		assert.True(t, sess.closed)
+	})
+
+	t.Run("resamples both directions", func(t *testing.T) {
+		sess := newSess()
+		var sent []byte
+		sess.sendAudioFn = func(_ context.Context, audio []byte) error {
+			sent = append(sent, audio...)
+			return nil
+		}
+		got, err := AdaptAudio(sess, AudioFormat{SampleRate: 12000})
+		require.NoError(t, err)
+		assert.Equal(t, AudioFormat{SampleRate: 12000, Encoding: EncodingPCM16}, got.InputFormat())
+		assert.Equal(t, AudioFormat{SampleRate: 12000, Encoding: EncodingPCM16}, got.OutputFormat())
+
+		ctx := context.Background()
+		require.NoError(t, got.SendAudio(ctx, pcm16(0, 100, 200)))
+		assert.Equal(t, []int16{0, 50, 100, 150}, samplesOf(sent))
+
+		sess.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: pcm16(0, 10, 20, 30, 40)}
+		sess.recvChan <- SessionEvent{Type: EventTextOutput, Text: "hi"}
+		close(sess.recvChan)
+		sess.closed = true
+
+		var events []SessionEvent
+		for ev, err := range got.Recv(ctx) {
+			require.NoError(t, err)
+			events = append(events, ev)
+		}
+		require.Len(t, events, 2)
+		assert.Equal(t, []int16{0, 20}, samplesOf(events[0].Audio))
+		assert.Equal(t, "hi", events[1].Text)
+	})
+}
@@ -0,0 +1,220 @@
+package s2s
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// InterruptOptions controls what Session.Interrupt cancels besides the
+// model's response, which it always stops.
+type InterruptOptions struct {
+	// FlushOutput discards audio and text of the interrupted response that
+	// the provider had already produced but Recv had not yet delivered, so
+	// playback stops at once. Output resumes with the next response.
+	FlushOutput bool
+
+	// CancelToolCalls cancels tool calls the model requested that have no
+	// result yet: tools run by WithToolRegistry are canceled, and results
+	// sent for them afterwards are dropped instead of reaching the model.
+	CancelToolCalls bool
+}
+
+// DefaultInterruptOptions are used when WithInterruptOptions is not given:
+// output is flushed and tool calls are left to finish.
+var DefaultInterruptOptions = InterruptOptions{FlushOutput: true}
+
+// WithInterruptOptions sets how Session.Interrupt behaves.
+func WithInterruptOptions(o InterruptOptions) Option {
+	return func(cfg *Config) {
+		cfg.Interrupt = &o
+	}
+}
+
+// Interruption describes what an Interrupt call cancelled. It is carried by
+// EventInterrupted events.
+type Interruption struct {
+	// FlushedOutput reports whether a response was being delivered and its
+	// remaining output is being discarded.
+	FlushedOutput bool
+
+	// CancelledToolCalls holds the IDs of the cancelled tool calls.
+	CancelledToolCalls []string
+}
+
+func interruptOptions(cfg Config) InterruptOptions {
+	if cfg.Interrupt == nil {
+		return DefaultInterruptOptions
+	}
+	return *cfg.Interrupt
+}
+
+// interruptSession gives every provider the same Interrupt semantics. It
+// tracks whether a response is being delivered and which tool calls await a
+// result, and confirms each Interrupt with an EventInterrupted.
+type interruptSession struct {
+	Session
+	opts  InterruptOptions
+	hooks Hooks
+
+	mu         sync.Mutex
+	responding bool               // output seen since the last EventTurnEnd
+	flushing   bool               // discarding output until EventTurnEnd
+	pending    map[string]bool    // tool calls awaiting a result
+	cancelled  map[string]bool    // tool calls cancelled by Interrupt
+	control    []SessionEvent     // EventInterrupted events not yet delivered
+	signal     chan struct{}      // wakes Recv when control is appended
+	onCancel   func(ids []string) // set by dispatchSession
+}
+
+func newInterruptSession(sess Session, opts InterruptOptions, hooks Hooks) *interruptSession {
+	return &interruptSession{
+		Session:   sess,
+		opts:      opts,
+		hooks:     hooks,
+		pending:   make(map[string]bool),
+		cancelled: make(map[string]bool),
+		signal:    make(chan struct{}, 1),
+	}
+}
+
+// Interrupt stops the model's response, applies the InterruptOptions and
+// queues an EventInterrupted describing the outcome.
+func (s *interruptSession) Interrupt(ctx context.Context) error {
+	if err := s.Session.Interrupt(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	var result Interruption
+	if s.opts.FlushOutput && s.responding {
+		s.flushing = true
+		result.FlushedOutput = true
+	}
+	if s.opts.CancelToolCalls {
+		for id := range s.pending {
+			s.cancelled[id] = true
+			result.CancelledToolCalls = append(result.CancelledToolCalls, id)
+		}
+		clear(s.pending)
+	}
+	s.responding = false
+	onCancel := s.onCancel
+	s.control = append(s.control, SessionEvent{Type: EventInterrupted, Interruption: &result})
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+	if onCancel != nil && len(result.CancelledToolCalls) > 0 {
+		onCancel(result.CancelledToolCalls)
+	}
+	if s.hooks.OnInterrupt != nil {
+		s.hooks.OnInterrupt(ctx)
+	}
+	return nil
+}
+
+// SendToolResult sends result unless its call was cancelled by Interrupt.
+func (s *interruptSession) SendToolResult(ctx context.Context, result schema.ToolResult) error {
+	s.mu.Lock()
+	if s.cancelled[result.CallID] {
+		delete(s.cancelled, result.CallID)
+		s.mu.Unlock()
+		return nil
+	}
+	delete(s.pending, result.CallID)
+	s.mu.Unlock()
+	return s.Session.SendToolResult(ctx, result)
+}
+
+func (s *interruptSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type item struct {
+			event SessionEvent
+			err   error
+		}
+		in := make(chan item)
+		go func() {
+			defer close(in)
+			for event, err := range s.Session.Recv(ctx) {
+				select {
+				case in <- item{event, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for {
+			if !s.yieldControl(yield) {
+				return
+			}
+			select {
+			case it, ok := <-in:
+				if !ok {
+					s.yieldControl(yield)
+					return
+				}
+				if it.err == nil && !s.observe(it.event) {
+					continue
+				}
+				if !yield(it.event, it.err) {
+					return
+				}
+			case <-s.signal:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// yieldControl delivers queued EventInterrupted events. It returns false if
+// the consumer stopped.
+func (s *interruptSession) yieldControl(yield func(SessionEvent, error) bool) bool {
+	s.mu.Lock()
+	control := s.control
+	s.control = nil
+	s.mu.Unlock()
+	for _, event := range control {
+		if !yield(event, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// observe updates the response and tool call state for event and reports
+// whether it should be delivered.
+func (s *interruptSession) observe(event SessionEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch event.Type {
+	case EventAudioOutput, EventTextOutput:
+		if s.flushing {
+			return false
+		}
+		s.responding = true
+	case EventToolCall:
+		if event.ToolCall != nil {
+			s.pending[event.ToolCall.ID] = true
+		}
+	case EventTurnEnd:
+		s.responding = false
+		if s.flushing {
+			// The interrupted response ended; its turn end is delivered.
+			s.flushing = false
+		}
+	case EventTranscript:
+		// New user speech means the provider has moved on.
+		s.flushing = false
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package s2s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// pullEvents consumes sess in the background and returns a function reading
+// the next event.
+func pullEvents(t *testing.T, sess Session) func() SessionEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan SessionEvent, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev, err := range sess.Recv(ctx) {
+			if err != nil {
+				return
+			}
+			events <- ev
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return func() SessionEvent {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for event")
+			return SessionEvent{}
+		}
+	}
+}
+
+func TestInterrupt_FlushOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		speaking    bool
+		wantFlushed bool
+	}{
+		{name: "default flushes a response in progress", speaking: true, wantFlushed: true},
+		{name: "nothing to flush when idle", speaking: false, wantFlushed: false},
+		{
+			name:        "flush disabled",
+			opts:        []Option{WithInterruptOptions(InterruptOptions{FlushOutput: false})},
+			speaking:    true,
+			wantFlushed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newMockSession()
+			sess, err := WrapSession(inner, ApplyOptions(tt.opts...))
+			require.NoError(t, err)
+			next := pullEvents(t, sess)
+
+			if tt.speaking {
+				inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{1}}
+				assert.Equal(t, EventAudioOutput, next().Type)
+			}
+
+			require.NoError(t, sess.Interrupt(context.Background()))
+			assert.True(t, inner.interrupted)
+
+			ev := next()
+			require.Equal(t, EventInterrupted, ev.Type)
+			assert.Equal(t, tt.wantFlushed, ev.Interruption.FlushedOutput)
+			assert.Empty(t, ev.Interruption.CancelledToolCalls)
+
+			// Output the provider had already produced for the interrupted
+			// response, then its end, then the next response.
+			inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{2}}
+			inner.recvChan <- SessionEvent{Type: EventTurnEnd}
+			inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{3}}
+
+			if !tt.wantFlushed {
+				assert.Equal(t, []byte{2}, next().Audio)
+			}
+			assert.Equal(t, EventTurnEnd, next().Type)
+			assert.Equal(t, []byte{3}, next().Audio)
+		})
+	}
+}
+
+func TestInterrupt_CancelToolCalls(t *testing.T) {
+	var interrupts int
+	inner := newMockSession()
+	sess, err := WrapSession(inner, ApplyOptions(
+		WithInterruptOptions(InterruptOptions{CancelToolCalls: true}),
+		WithHooks(Hooks{OnInterrupt: func(context.Context) { interrupts++ }}),
+	))
+	require.NoError(t, err)
+	next := pullEvents(t, sess)
+	ctx := context.Background()
+
+	inner.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &schema.ToolCall{ID: "c1", Name: "lookup"}}
+	inner.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &schema.ToolCall{ID: "c2", Name: "lookup"}}
+	assert.Equal(t, "c1", next().ToolCall.ID)
+	assert.Equal(t, "c2", next().ToolCall.ID)
+	require.NoError(t, sess.SendToolResult(ctx, schema.ToolResult{CallID: "c1"}))
+
+	require.NoError(t, sess.Interrupt(ctx))
+	ev := next()
+	require.Equal(t, EventInterrupted, ev.Type)
+	assert.Equal(t, []string{"c2"}, ev.Interruption.CancelledToolCalls)
+	assert.Equal(t, 1, interrupts)
+
+	// The late result for the cancelled call does not reach the model.
+	require.NoError(t, sess.SendToolResult(ctx, schema.ToolResult{CallID: "c2"}))
+	require.Len(t, inner.resultSent, 1)
+	assert.Equal(t, "c1", inner.resultSent[0].CallID)
+}
+
+func TestInterrupt_CancelsDispatchedTools(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	reg := tool.NewRegistry()
+	require.NoError(t, reg.Add(tool.NewFuncTool("slow", "Waits until cancelled",
+		func(ctx context.Context, _ struct{}) (*tool.Result, error) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})))
+
+	inner := newResultSession()
+	sess, err := WrapSession(inner, ApplyOptions(
+		WithToolRegistry(reg),
+		WithInterruptOptions(InterruptOptions{CancelToolCalls: true}),
+	))
+	require.NoError(t, err)
+	next := pullEvents(t, sess)
+
+	inner.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &schema.ToolCall{ID: "c1", Name: "slow"}}
+	<-started
+	require.NoError(t, sess.Interrupt(context.Background()))
+
+	ev := next()
+	require.Equal(t, EventInterrupted, ev.Type)
+	assert.Equal(t, []string{"c1"}, ev.Interruption.CancelledToolCalls)
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool was not cancelled")
+	}
+	select {
+	case result := <-inner.results:
+		t.Fatalf("cancelled result %+v reached the model", result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
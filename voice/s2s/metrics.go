@@ -0,0 +1,241 @@
+package s2s
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+	"github.com/lookatitude/beluga-ai/v2/voice"
+)
+
+// Instrument names recorded by WithMetrics.
+const (
+	MetricTimeToFirstAudio = "beluga.s2s.time_to_first_audio"
+	MetricTurns            = "beluga.s2s.turns"
+	MetricInterruptions    = "beluga.s2s.interruptions"
+	MetricAudioBytes       = "beluga.s2s.audio.bytes"
+)
+
+// MetricsOption configures WithMetrics.
+type MetricsOption func(*metricsConfig)
+
+type metricsConfig struct {
+	provider      string
+	meterProvider metric.MeterProvider
+	newVAD        func() voice.ActivityDetector
+}
+
+// WithMetricsProvider sets the provider name, such as "openai_realtime",
+// recorded in the gen_ai.system attribute of every measurement.
+func WithMetricsProvider(name string) MetricsOption {
+	return func(c *metricsConfig) {
+		c.provider = name
+	}
+}
+
+// WithMeterProvider sets the OTel meter provider. The default is the global
+// provider.
+func WithMeterProvider(mp metric.MeterProvider) MetricsOption {
+	return func(c *metricsConfig) {
+		c.meterProvider = mp
+	}
+}
+
+// WithSpeechDetector sets how the start of user speech is found in
+// SendAudio input. newVAD is called once per session. The default is a
+// voice.EnergyVAD with its default threshold.
+func WithSpeechDetector(newVAD func() voice.ActivityDetector) MetricsOption {
+	return func(c *metricsConfig) {
+		c.newVAD = newVAD
+	}
+}
+
+// WithMetrics returns middleware that records session metrics as OTel
+// instruments, so S2S latency can be compared with the cascading pipeline:
+//
+//   - beluga.s2s.time_to_first_audio (ms): from the start of user speech in
+//     SendAudio to the first EventAudioOutput of the response
+//   - beluga.s2s.turns: completed turns (EventTurnEnd)
+//   - beluga.s2s.interruptions: calls to Interrupt
+//   - beluga.s2s.audio.bytes: audio bytes, with direction "input" or "output"
+//
+// Measurements carry the provider set with WithMetricsProvider.
+//
+//	engine = s2s.ApplyMiddleware(engine, s2s.WithMetrics(
+//	    s2s.WithMetricsProvider("openai_realtime"),
+//	))
+func WithMetrics(opts ...MetricsOption) Middleware {
+	cfg := metricsConfig{
+		meterProvider: otel.GetMeterProvider(),
+		newVAD: func() voice.ActivityDetector {
+			return voice.NewEnergyVAD(voice.EnergyVADConfig{})
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inst := newS2SInstruments(cfg.meterProvider.Meter("github.com/lookatitude/beluga-ai/v2/voice/s2s"))
+	var attrs []attribute.KeyValue
+	if cfg.provider != "" {
+		attrs = append(attrs, attribute.String(o11y.AttrSystem, cfg.provider))
+	}
+	set := attribute.NewSet(attrs...)
+	return func(next S2S) S2S {
+		return &metricsEngine{next: next, cfg: cfg, inst: inst, attrs: set}
+	}
+}
+
+// s2sInstruments holds the instruments of one WithMetrics middleware. An
+// instrument that fails to register is left nil and skipped.
+type s2sInstruments struct {
+	ttfa          metric.Float64Histogram
+	turns         metric.Int64Counter
+	interruptions metric.Int64Counter
+	audioBytes    metric.Int64Counter
+}
+
+func newS2SInstruments(m metric.Meter) s2sInstruments {
+	var inst s2sInstruments
+	if h, err := m.Float64Histogram(MetricTimeToFirstAudio,
+		metric.WithDescription("Time from the start of user speech to the first audio of the response"),
+		metric.WithUnit("ms"),
+	); err == nil {
+		inst.ttfa = h
+	}
+	if c, err := m.Int64Counter(MetricTurns,
+		metric.WithDescription("Number of completed S2S turns"),
+		metric.WithUnit("{turn}"),
+	); err == nil {
+		inst.turns = c
+	}
+	if c, err := m.Int64Counter(MetricInterruptions,
+		metric.WithDescription("Number of S2S interruptions"),
+		metric.WithUnit("{interruption}"),
+	); err == nil {
+		inst.interruptions = c
+	}
+	if c, err := m.Int64Counter(MetricAudioBytes,
+		metric.WithDescription("Audio bytes sent to and received from S2S providers"),
+		metric.WithUnit("By"),
+	); err == nil {
+		inst.audioBytes = c
+	}
+	return inst
+}
+
+type metricsEngine struct {
+	next  S2S
+	cfg   metricsConfig
+	inst  s2sInstruments
+	attrs attribute.Set
+}
+
+func (e *metricsEngine) Start(ctx context.Context, opts ...Option) (Session, error) {
+	sess, err := e.next.Start(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsSession{
+		Session: sess,
+		engine:  e,
+		vad:     e.cfg.newVAD(),
+		now:     time.Now,
+	}, nil
+}
+
+// metricsSession measures a session. A turn runs from the start of user
+// speech to EventTurnEnd; time to first audio is measured once per turn.
+type metricsSession struct {
+	Session
+	engine *metricsEngine
+	now    func() time.Time
+
+	mu          sync.Mutex
+	vad         voice.ActivityDetector
+	speechStart time.Time // start of user speech awaiting a response
+	responding  bool      // the response's first audio has arrived
+}
+
+func (s *metricsSession) SendAudio(ctx context.Context, audio []byte) error {
+	s.addBytes(ctx, len(audio), "input")
+
+	s.mu.Lock()
+	if !s.responding && s.speechStart.IsZero() {
+		if res, err := s.vad.DetectActivity(ctx, audio); err == nil && res.IsSpeech {
+			s.speechStart = s.now()
+		}
+	}
+	s.mu.Unlock()
+
+	return s.Session.SendAudio(ctx, audio)
+}
+
+func (s *metricsSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		for event, err := range s.Session.Recv(ctx) {
+			if err == nil {
+				s.observe(ctx, event)
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+func (s *metricsSession) observe(ctx context.Context, event SessionEvent) {
+	inst, attrs := s.engine.inst, metric.WithAttributeSet(s.engine.attrs)
+	switch event.Type {
+	case EventAudioOutput:
+		s.addBytes(ctx, len(event.Audio), "output")
+		s.mu.Lock()
+		var ttfa time.Duration
+		if !s.responding {
+			s.responding = true
+			if !s.speechStart.IsZero() {
+				ttfa = s.now().Sub(s.speechStart)
+				s.speechStart = time.Time{}
+			}
+		}
+		s.mu.Unlock()
+		if ttfa > 0 && inst.ttfa != nil {
+			inst.ttfa.Record(ctx, float64(ttfa)/float64(time.Millisecond), attrs)
+		}
+	case EventTurnEnd:
+		s.mu.Lock()
+		s.responding = false
+		s.speechStart = time.Time{}
+		s.mu.Unlock()
+		if inst.turns != nil {
+			inst.turns.Add(ctx, 1, attrs)
+		}
+	}
+}
+
+// Interrupt counts the interruption. The user is speaking, so the next
+// response is timed from now.
+func (s *metricsSession) Interrupt(ctx context.Context) error {
+	if inst := s.engine.inst.interruptions; inst != nil {
+		inst.Add(ctx, 1, metric.WithAttributeSet(s.engine.attrs))
+	}
+	s.mu.Lock()
+	s.responding = false
+	s.speechStart = s.now()
+	s.mu.Unlock()
+	return s.Session.Interrupt(ctx)
+}
+
+func (s *metricsSession) addBytes(ctx context.Context, n int, direction string) {
+	inst := s.engine.inst.audioBytes
+	if inst == nil || n == 0 {
+		return
+	}
+	attrs := append(s.engine.attrs.ToSlice(), attribute.String("direction", direction))
+	inst.Add(ctx, int64(n), metric.WithAttributes(attrs...))
+}
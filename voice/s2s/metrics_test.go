@@ -0,0 +1,132 @@
+package s2s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/lookatitude/beluga-ai/v2/o11y"
+)
+
+// collectMetrics reads all metrics from reader, keyed by instrument name.
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	out := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m.Data
+		}
+	}
+	return out
+}
+
+func sumByDirection(t *testing.T, data metricdata.Aggregation) map[string]int64 {
+	t.Helper()
+	sum, ok := data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	out := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		dir, _ := dp.Attributes.Value("direction")
+		out[dir.AsString()] = dp.Value
+		provider, _ := dp.Attributes.Value(o11y.AttrSystem)
+		assert.Equal(t, "mock", provider.AsString())
+	}
+	return out
+}
+
+func TestWithMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	inner := newMockSession()
+	engine := ApplyMiddleware(&mockS2S{session: inner}, WithMetrics(
+		WithMetricsProvider("mock"),
+		WithMeterProvider(mp),
+	))
+	sess, err := engine.Start(context.Background())
+	require.NoError(t, err)
+
+	ms := sess.(*metricsSession)
+	clock := time.Unix(0, 0)
+	ms.now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	silence := pcm16(0, 0, 0, 0)
+	speech := pcm16(8000, -8000, 8000, -8000)
+
+	// Silence does not start the clock; speech does.
+	require.NoError(t, sess.SendAudio(ctx, silence))
+	clock = clock.Add(time.Second)
+	require.NoError(t, sess.SendAudio(ctx, speech))
+	clock = clock.Add(300 * time.Millisecond)
+
+	inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{1, 2, 3, 4}}
+	inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{5, 6}}
+	inner.recvChan <- SessionEvent{Type: EventTurnEnd}
+	close(inner.recvChan)
+	inner.closed = true
+	for _, err := range sess.Recv(ctx) {
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sess.Interrupt(ctx))
+
+	got := collectMetrics(t, reader)
+
+	ttfa, ok := got[MetricTimeToFirstAudio].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, ttfa.DataPoints, 1)
+	assert.Equal(t, uint64(1), ttfa.DataPoints[0].Count)
+	assert.InDelta(t, 300.0, ttfa.DataPoints[0].Sum, 0.001)
+	assert.Equal(t, attribute.NewSet(attribute.String(o11y.AttrSystem, "mock")), ttfa.DataPoints[0].Attributes)
+
+	turns, ok := got[MetricTurns].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, turns.DataPoints, 1)
+	assert.Equal(t, int64(1), turns.DataPoints[0].Value)
+
+	interrupts, ok := got[MetricInterruptions].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, interrupts.DataPoints, 1)
+	assert.Equal(t, int64(1), interrupts.DataPoints[0].Value)
+
+	assert.Equal(t, map[string]int64{"input": 16, "output": 6}, sumByDirection(t, got[MetricAudioBytes]))
+}
+
+func TestWithMetrics_TimesFromInterrupt(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	inner := newMockSession()
+	engine := ApplyMiddleware(&mockS2S{session: inner}, WithMetrics(WithMeterProvider(mp)))
+	sess, err := engine.Start(context.Background())
+	require.NoError(t, err)
+
+	ms := sess.(*metricsSession)
+	clock := time.Unix(0, 0)
+	ms.now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	require.NoError(t, sess.Interrupt(ctx))
+	clock = clock.Add(120 * time.Millisecond)
+
+	inner.recvChan <- SessionEvent{Type: EventAudioOutput, Audio: []byte{1, 2}}
+	close(inner.recvChan)
+	inner.closed = true
+	for _, err := range sess.Recv(ctx) {
+		require.NoError(t, err)
+	}
+
+	ttfa, ok := collectMetrics(t, reader)[MetricTimeToFirstAudio].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, ttfa.DataPoints, 1)
+	assert.InDelta(t, 120.0, ttfa.DataPoints[0].Sum, 0.001)
+}
@@ -0,0 +1,15 @@
+package s2s
+
+// Middleware wraps an S2S engine to add cross-cutting behaviour.
+// Middlewares are composed via ApplyMiddleware and applied outside-in
+// (the last middleware in the list is the outermost wrapper).
+type Middleware func(S2S) S2S
+
+// ApplyMiddleware wraps engine with the given middlewares in reverse order so
+// that the first middleware in the list is the outermost (first to execute).
+func ApplyMiddleware(engine S2S, mws ...Middleware) S2S {
+	for i := len(mws) - 1; i >= 0; i-- {
+		engine = mws[i](engine)
+	}
+	return engine
+}
@@ -87,7 +87,7 @@ func (e *Engine) Start(ctx context.Context, opts ...s2s.Option) (s2s.Session, er
 
 	go sess.readLoop(ctx)
 
-	return sess, nil
+	return s2s.WrapSession(sess, cfg)
 }
 
 // geminiSession implements s2s.Session for Gemini Live.
@@ -110,6 +110,7 @@ type geminiServerMsg struct {
 type geminiContent struct {
 	ModelTurn    *geminiTurn `json:"modelTurn,omitempty"`
 	TurnComplete bool        `json:"turnComplete,omitempty"`
+	Interrupted  bool        `json:"interrupted,omitempty"`
 }
 
 type geminiTurn struct {
@@ -191,7 +192,9 @@ func (s *geminiSession) handleServerContent(content *geminiContent) {
 			s.handleContentPart(part)
 		}
 	}
-	if content.TurnComplete {
+	// An interrupted generation ends without turnComplete; report it as
+	// the end of the turn like the other providers do.
+	if content.TurnComplete || content.Interrupted {
 		s.events <- s2s.SessionEvent{
 			Type: s2s.EventTurnEnd,
 		}
@@ -359,11 +362,23 @@ func (s *geminiSession) Recv(ctx context.Context) iter.Seq2[s2s.SessionEvent, er
 
 // Interrupt signals user interruption to the Gemini Live session.
 func (s *geminiSession) Interrupt(ctx context.Context) error {
-	// Gemini Live handles interruptions via VAD on the server side.
-	// Send an empty audio chunk to signal activity.
+	// Gemini Live detects barge-in with server-side VAD and stops the
+	// generation itself, so there is nothing to send. The rest of the
+	// interruption (flushing output, cancelling tool calls) is handled by
+	// s2s.WrapSession.
 	return nil
 }
 
+// InputFormat reports the Live API's input format, 16 kHz mono PCM16.
+func (s *geminiSession) InputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 16000, Encoding: s2s.EncodingPCM16}
+}
+
+// OutputFormat reports the Live API's output format, 24 kHz mono PCM16.
+func (s *geminiSession) OutputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 24000, Encoding: s2s.EncodingPCM16}
+}
+
 // Close terminates the session and releases resources.
 func (s *geminiSession) Close() error {
 	s.once.Do(func() {
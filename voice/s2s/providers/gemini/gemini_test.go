@@ -756,3 +756,10 @@ func TestRegistry(t *testing.T) {
 		assert.NotNil(t, engine)
 	})
 }
+
+func TestHandleServerContent_InterruptedEndsTurn(t *testing.T) {
+	s := &geminiSession{events: make(chan s2s.SessionEvent, 1)}
+	s.handleServerContent(&geminiContent{Interrupted: true})
+	require.Len(t, s.events, 1)
+	assert.Equal(t, s2s.EventTurnEnd, (<-s.events).Type)
+}
@@ -88,7 +88,7 @@ func (e *Engine) Start(ctx context.Context, opts ...s2s.Option) (s2s.Session, er
 
 	go sess.readLoop(ctx)
 
-	return sess, nil
+	return s2s.WrapSession(sess, cfg)
 }
 
 // novaSession implements s2s.Session for Amazon Nova.
@@ -339,6 +339,16 @@ func (s *novaSession) Interrupt(ctx context.Context) error {
 	return s.conn.Write(ctx, websocket.MessageText, data)
 }
 
+// InputFormat reports Nova Sonic's input format, 16 kHz mono PCM16.
+func (s *novaSession) InputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 16000, Encoding: s2s.EncodingPCM16}
+}
+
+// OutputFormat reports Nova Sonic's output format, 24 kHz mono PCM16.
+func (s *novaSession) OutputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 24000, Encoding: s2s.EncodingPCM16}
+}
+
 // Close terminates the session.
 func (s *novaSession) Close() error {
 	s.once.Do(func() {
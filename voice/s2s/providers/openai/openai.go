@@ -95,7 +95,7 @@ func (e *Engine) Start(ctx context.Context, opts ...s2s.Option) (s2s.Session, er
 	// Start reading events.
 	go sess.readLoop(ctx)
 
-	return sess, nil
+	return s2s.WrapSession(sess, cfg)
 }
 
 // realtimeSession implements s2s.Session for OpenAI Realtime.
@@ -355,6 +355,16 @@ func (s *realtimeSession) Interrupt(ctx context.Context) error {
 	return s.conn.Write(ctx, websocket.MessageText, data)
 }
 
+// InputFormat reports the Realtime API's pcm16 input format, 24 kHz mono.
+func (s *realtimeSession) InputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 24000, Encoding: s2s.EncodingPCM16}
+}
+
+// OutputFormat reports the Realtime API's pcm16 output format, 24 kHz mono.
+func (s *realtimeSession) OutputFormat() s2s.AudioFormat {
+	return s2s.AudioFormat{SampleRate: 24000, Encoding: s2s.EncodingPCM16}
+}
+
 // Close terminates the session and releases resources.
 func (s *realtimeSession) Close() error {
 	s.once.Do(func() {
@@ -0,0 +1,415 @@
+package s2s
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+const (
+	// defaultReconnectBuffer is the default number of audio bytes queued
+	// while reconnecting: about 10 seconds of 24 kHz PCM16.
+	defaultReconnectBuffer = 480_000
+
+	// disconnectGrace is how long an error event is held back to see
+	// whether the provider closes its event stream right after it, which
+	// marks the error as a dropped connection.
+	disconnectGrace = 100 * time.Millisecond
+)
+
+// ErrReconnecting is the cause carried by the Recovering error event that
+// WithReconnect emits when a session drops. Test for it with errors.Is.
+var ErrReconnecting = errors.New("s2s: connection lost, reconnecting")
+
+// ReconnectOption configures WithReconnect.
+type ReconnectOption func(*reconnectConfig)
+
+type reconnectConfig struct {
+	policy      resilience.RetryPolicy
+	bufferBytes int
+}
+
+// WithReconnectPolicy sets the retry policy used to re-establish a dropped
+// session. The default allows 5 attempts with backoff from 250 ms to 5 s.
+func WithReconnectPolicy(p resilience.RetryPolicy) ReconnectOption {
+	return func(c *reconnectConfig) {
+		c.policy = p
+	}
+}
+
+// WithReconnectBuffer sets how many bytes of audio SendAudio queues while
+// the session is reconnecting. Once the buffer is full SendAudio fails until
+// the session is back. The default is 480,000 bytes, about 10 seconds of
+// 24 kHz PCM16.
+func WithReconnectBuffer(maxBytes int) ReconnectOption {
+	return func(c *reconnectConfig) {
+		if maxBytes >= 0 {
+			c.bufferBytes = maxBytes
+		}
+	}
+}
+
+// WithReconnect returns middleware that re-establishes sessions after
+// transient disconnects. When a session's event stream ends without Close
+// being called, the middleware emits an EventError with Recovering set and
+// ErrReconnecting as the cause, then starts a new session with the original
+// Start options, so the instructions and tool definitions are sent again.
+// Audio passed to SendAudio meanwhile is queued, up to a bounded buffer, and
+// sent once the new session is up; SendText and SendToolResult wait for it.
+// If every attempt fails, a final EventError without Recovering is emitted
+// and the event stream ends.
+//
+// Reconnection starts a fresh provider session, so the model does not keep
+// the conversation so far; see the package documentation for per-provider
+// details.
+//
+//	engine = s2s.ApplyMiddleware(engine, s2s.WithReconnect())
+func WithReconnect(opts ...ReconnectOption) Middleware {
+	cfg := reconnectConfig{
+		policy: resilience.RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: 250 * time.Millisecond,
+			MaxBackoff:     5 * time.Second,
+			BackoffFactor:  2,
+			Jitter:         true,
+		},
+		bufferBytes: defaultReconnectBuffer,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next S2S) S2S {
+		return &reconnectingEngine{next: next, cfg: cfg}
+	}
+}
+
+type reconnectingEngine struct {
+	next S2S
+	cfg  reconnectConfig
+}
+
+func (e *reconnectingEngine) Start(ctx context.Context, opts ...Option) (Session, error) {
+	sess, err := e.next.Start(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	lifeCtx, cancel := context.WithCancel(ctx)
+	ready := make(chan struct{})
+	close(ready)
+	rs := &reconnectingSession{
+		engine: e,
+		opts:   opts,
+		ctx:    lifeCtx,
+		cancel: cancel,
+		cur:    sess,
+		ready:  ready,
+		events: make(chan SessionEvent, 64),
+	}
+	go rs.run()
+	return rs, nil
+}
+
+// reconnectingSession forwards to the current provider session and replaces
+// it when it drops.
+type reconnectingSession struct {
+	engine *reconnectingEngine
+	opts   []Option
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan SessionEvent
+
+	mu           sync.Mutex
+	cur          Session
+	ready        chan struct{} // closed while connected
+	reconnecting bool
+	pending      [][]byte
+	pendingBytes int
+	closed       bool
+}
+
+// run pumps events from the current session and reconnects when its stream
+// ends unexpectedly. It is the sole writer to s.events.
+func (s *reconnectingSession) run() {
+	defer close(s.events)
+	for {
+		cause := s.forward(s.current())
+		if s.isClosed() {
+			return
+		}
+		if !s.emit(SessionEvent{Type: EventError, Error: errors.Join(ErrReconnecting, cause), Recovering: true}) {
+			return
+		}
+		if err := s.reconnect(); err != nil {
+			if !s.isClosed() {
+				s.emit(SessionEvent{Type: EventError, Error: core.Errorf(core.ErrProviderDown, "s2s: reconnect failed: %w", err)})
+			}
+			return
+		}
+	}
+}
+
+// forward relays events from sess until its stream ends, returning the error
+// that ended it, if any.
+func (s *reconnectingSession) forward(sess Session) error {
+	in := make(chan SessionEvent)
+	var transportErr error
+	go func() {
+		defer close(in)
+		for ev, err := range sess.Recv(s.ctx) {
+			if err != nil {
+				transportErr = err
+				return
+			}
+			select {
+			case in <- ev:
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for ev := range in {
+		if ev.Type == EventError && ev.Error != nil {
+			// A provider reports a dropped connection as an error event
+			// followed by the end of its stream.
+			select {
+			case next, ok := <-in:
+				if !ok {
+					return ev.Error
+				}
+				if !s.emit(ev) || !s.emit(next) {
+					return nil
+				}
+				continue
+			case <-time.After(disconnectGrace):
+			}
+		}
+		if !s.emit(ev) {
+			return nil
+		}
+	}
+	return transportErr
+}
+
+// reconnect starts a new provider session, sends the queued audio and then
+// switches to it. If the queued audio cannot be sent, the new session is
+// closed, the unsent audio is queued again and the attempt counts as failed
+// under the retry policy.
+func (s *reconnectingSession) reconnect() error {
+	s.mu.Lock()
+	old := s.cur
+	s.reconnecting = true
+	s.ready = make(chan struct{})
+	s.mu.Unlock()
+	_ = old.Close()
+
+	_, err := resilience.Retry(s.ctx, s.engine.cfg.policy, func(ctx context.Context) (Session, error) {
+		sess, err := s.engine.next.Start(ctx, s.opts...)
+		if err != nil {
+			var ce *core.Error
+			if errors.As(err, &ce) {
+				return nil, err
+			}
+			return nil, core.Errorf(core.ErrProviderDown, "s2s: restart session: %w", err)
+		}
+		if err := s.resume(ctx, sess); err != nil {
+			_ = sess.Close()
+			return nil, core.Errorf(core.ErrProviderDown, "s2s: resend queued audio: %w", err)
+		}
+		return sess, nil
+	})
+	return err
+}
+
+// resume sends the queued audio to sess in order, then makes it the current
+// session. Audio arriving meanwhile is queued behind it until the queue is
+// empty. If a send fails, the unsent audio is put back at the front of the
+// queue and the error returned.
+func (s *reconnectingSession) resume(ctx context.Context, sess Session) error {
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = sess.Close()
+			return nil
+		}
+		batch := s.pending
+		s.pending, s.pendingBytes = nil, 0
+		if len(batch) == 0 {
+			s.cur = sess
+			s.reconnecting = false
+			close(s.ready)
+			s.mu.Unlock()
+			return nil
+		}
+		s.mu.Unlock()
+		for i, chunk := range batch {
+			if err := sess.SendAudio(ctx, chunk); err != nil {
+				s.requeue(batch[i:])
+				return err
+			}
+		}
+	}
+}
+
+// requeue puts chunks back at the front of the queue.
+func (s *reconnectingSession) requeue(chunks [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range chunks {
+		s.pendingBytes += len(c)
+	}
+	s.pending = append(slices.Clip(chunks), s.pending...)
+}
+
+func (s *reconnectingSession) emit(ev SessionEvent) bool {
+	select {
+	case s.events <- ev:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *reconnectingSession) current() Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+func (s *reconnectingSession) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed || s.ctx.Err() != nil
+}
+
+// connected waits until the session is connected and returns it.
+func (s *reconnectingSession) connected(ctx context.Context) (Session, error) {
+	s.mu.Lock()
+	ready, closed := s.ready, s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, core.Errorf(core.ErrInvalidInput, "s2s: session closed")
+	}
+	select {
+	case <-ready:
+		return s.current(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.ctx.Done():
+		return nil, core.Errorf(core.ErrProviderDown, "s2s: session ended")
+	}
+}
+
+// SendAudio sends audio to the current session, or queues it while
+// reconnecting.
+func (s *reconnectingSession) SendAudio(ctx context.Context, audio []byte) error {
+	if queued, err := s.queue(audio); queued || err != nil {
+		return err
+	}
+	err := s.current().SendAudio(ctx, audio)
+	if err != nil {
+		// The connection may have dropped before run noticed.
+		if queued, qerr := s.queue(audio); queued || qerr != nil {
+			return qerr
+		}
+	}
+	return err
+}
+
+// queue buffers audio if the session is reconnecting. It reports whether
+// the audio was queued, or an error if the buffer is full.
+func (s *reconnectingSession) queue(audio []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reconnecting {
+		return false, nil
+	}
+	if s.pendingBytes+len(audio) > s.engine.cfg.bufferBytes {
+		return false, core.Errorf(core.ErrProviderDown, "s2s: reconnecting: audio buffer full (%d bytes)", s.engine.cfg.bufferBytes)
+	}
+	s.pending = append(s.pending, append([]byte(nil), audio...))
+	s.pendingBytes += len(audio)
+	return true, nil
+}
+
+// SendText sends text once the session is connected.
+func (s *reconnectingSession) SendText(ctx context.Context, text string) error {
+	sess, err := s.connected(ctx)
+	if err != nil {
+		return err
+	}
+	return sess.SendText(ctx, text)
+}
+
+// SendToolResult sends a tool result once the session is connected.
+func (s *reconnectingSession) SendToolResult(ctx context.Context, result schema.ToolResult) error {
+	sess, err := s.connected(ctx)
+	if err != nil {
+		return err
+	}
+	return sess.SendToolResult(ctx, result)
+}
+
+// Recv returns an iterator over the events of all underlying sessions.
+func (s *reconnectingSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		for {
+			select {
+			case ev, ok := <-s.events:
+				if !ok {
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Interrupt interrupts the current session. While reconnecting there is no
+// output to interrupt, so it does nothing.
+func (s *reconnectingSession) Interrupt(ctx context.Context) error {
+	s.mu.Lock()
+	sess, reconnecting := s.cur, s.reconnecting
+	s.mu.Unlock()
+	if reconnecting {
+		return nil
+	}
+	return sess.Interrupt(ctx)
+}
+
+// InputFormat reports the input format of the current session.
+func (s *reconnectingSession) InputFormat() AudioFormat {
+	return s.current().InputFormat()
+}
+
+// OutputFormat reports the output format of the current session.
+func (s *reconnectingSession) OutputFormat() AudioFormat {
+	return s.current().OutputFormat()
+}
+
+// Close ends the session and stops reconnecting.
+func (s *reconnectingSession) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	sess, reconnecting := s.cur, s.reconnecting
+	s.mu.Unlock()
+	s.cancel()
+	if reconnecting {
+		// The dropped session is already closed.
+		return nil
+	}
+	return sess.Close()
+}
@@ -0,0 +1,314 @@
+package s2s
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/resilience"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dropSession is a concurrency-safe session whose connection can be dropped
+// from the test.
+type dropSession struct {
+	events chan SessionEvent
+
+	mu      sync.Mutex
+	audio   [][]byte
+	text    []string
+	closed  bool
+	sendErr error // returned by SendAudio after the first chunk
+}
+
+func newDropSession() *dropSession {
+	return &dropSession{events: make(chan SessionEvent, 16)}
+}
+
+// drop simulates a provider losing its connection: an error event followed
+// by the end of the stream.
+func (d *dropSession) drop() {
+	d.events <- SessionEvent{Type: EventError, Error: errors.New("read: connection reset")}
+	d.Close()
+}
+
+func (d *dropSession) SendAudio(_ context.Context, audio []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return errors.New("closed")
+	}
+	if d.sendErr != nil && len(d.audio) > 0 {
+		return d.sendErr
+	}
+	d.audio = append(d.audio, audio)
+	return nil
+}
+
+func (d *dropSession) SendText(_ context.Context, text string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.text = append(d.text, text)
+	return nil
+}
+
+func (d *dropSession) SendToolResult(context.Context, schema.ToolResult) error { return nil }
+func (d *dropSession) Interrupt(context.Context) error                         { return nil }
+func (d *dropSession) InputFormat() AudioFormat                                { return AudioFormat{SampleRate: 24000} }
+func (d *dropSession) OutputFormat() AudioFormat                               { return AudioFormat{SampleRate: 24000} }
+
+func (d *dropSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		for {
+			select {
+			case ev, ok := <-d.events:
+				if !ok || !yield(ev, nil) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (d *dropSession) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.closed {
+		d.closed = true
+		close(d.events)
+	}
+	return nil
+}
+
+func (d *dropSession) sentAudio() [][]byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]byte(nil), d.audio...)
+}
+
+// dropEngine hands out the sessions sent on next, recording the options of
+// each Start call.
+type dropEngine struct {
+	next chan *dropSession
+	err  error
+
+	mu      sync.Mutex
+	configs []Config
+}
+
+func (e *dropEngine) Start(ctx context.Context, opts ...Option) (Session, error) {
+	e.mu.Lock()
+	e.configs = append(e.configs, ApplyOptions(opts...))
+	e.mu.Unlock()
+	if e.err != nil && len(e.startConfigs()) > 1 {
+		return nil, e.err
+	}
+	select {
+	case s := <-e.next:
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (e *dropEngine) startConfigs() []Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Config(nil), e.configs...)
+}
+
+var fastReconnect = WithReconnectPolicy(resilience.RetryPolicy{
+	MaxAttempts:    2,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+})
+
+func nextEvent(t *testing.T, events func() (SessionEvent, error, bool)) SessionEvent {
+	t.Helper()
+	type result struct {
+		ev SessionEvent
+		ok bool
+	}
+	ch := make(chan result, 1)
+	go func() {
+		ev, _, ok := events()
+		ch <- result{ev, ok}
+	}()
+	select {
+	case r := <-ch:
+		require.True(t, r.ok, "event stream ended")
+		return r.ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return SessionEvent{}
+	}
+}
+
+func TestWithReconnect_ResumesAfterDrop(t *testing.T) {
+	first, second := newDropSession(), newDropSession()
+	eng := &dropEngine{next: make(chan *dropSession, 2)}
+	eng.next <- first
+
+	engine := ApplyMiddleware(eng, WithReconnect(fastReconnect))
+	sess, err := engine.Start(context.Background(),
+		WithInstructions("be brief"),
+		WithTools([]schema.ToolDefinition{{Name: "lookup"}}),
+	)
+	require.NoError(t, err)
+	defer sess.Close()
+
+	next, stop := iter.Pull2(sess.Recv(context.Background()))
+	defer stop()
+
+	first.events <- SessionEvent{Type: EventTextOutput, Text: "hello"}
+	assert.Equal(t, "hello", nextEvent(t, next).Text)
+
+	first.drop()
+	ev := nextEvent(t, next)
+	assert.Equal(t, EventError, ev.Type)
+	assert.True(t, ev.Recovering)
+	assert.ErrorIs(t, ev.Error, ErrReconnecting)
+	assert.ErrorContains(t, ev.Error, "connection reset")
+
+	// Audio sent while reconnecting is queued, not lost.
+	require.NoError(t, sess.SendAudio(context.Background(), []byte{1}))
+	require.NoError(t, sess.SendAudio(context.Background(), []byte{2}))
+
+	eng.next <- second
+	second.events <- SessionEvent{Type: EventTextOutput, Text: "back"}
+	assert.Equal(t, "back", nextEvent(t, next).Text)
+
+	assert.Equal(t, [][]byte{{1}, {2}}, second.sentAudio())
+	require.NoError(t, sess.SendAudio(context.Background(), []byte{3}))
+	assert.Equal(t, [][]byte{{1}, {2}, {3}}, second.sentAudio())
+
+	// The system prompt and tools are replayed on the new session.
+	configs := eng.startConfigs()
+	require.Len(t, configs, 2)
+	assert.Equal(t, "be brief", configs[1].Instructions)
+	assert.Equal(t, "lookup", configs[1].Tools[0].Name)
+}
+
+func TestWithReconnect_RequeuesUnsentAudio(t *testing.T) {
+	first, second, third := newDropSession(), newDropSession(), newDropSession()
+	second.sendErr = errors.New("write: broken pipe")
+	eng := &dropEngine{next: make(chan *dropSession, 3)}
+	eng.next <- first
+
+	sess, err := WithReconnect(fastReconnect)(eng).Start(context.Background())
+	require.NoError(t, err)
+	defer sess.Close()
+	next, stop := iter.Pull2(sess.Recv(context.Background()))
+	defer stop()
+
+	first.drop()
+	require.True(t, nextEvent(t, next).Recovering)
+	for _, chunk := range [][]byte{{1}, {2}, {3}} {
+		require.NoError(t, sess.SendAudio(context.Background(), chunk))
+	}
+
+	// The second session fails after taking the first chunk; the rest is
+	// sent to the third.
+	eng.next <- second
+	eng.next <- third
+	third.events <- SessionEvent{Type: EventTextOutput, Text: "back"}
+	assert.Equal(t, "back", nextEvent(t, next).Text)
+
+	assert.Equal(t, [][]byte{{1}}, second.sentAudio())
+	assert.Equal(t, [][]byte{{2}, {3}}, third.sentAudio())
+	second.mu.Lock()
+	assert.True(t, second.closed, "failed session should be closed")
+	second.mu.Unlock()
+}
+
+func TestWithReconnect_BufferLimit(t *testing.T) {
+	first := newDropSession()
+	eng := &dropEngine{next: make(chan *dropSession, 1)}
+	eng.next <- first
+
+	sess, err := WithReconnect(fastReconnect, WithReconnectBuffer(3))(eng).Start(context.Background())
+	require.NoError(t, err)
+	defer sess.Close()
+	next, stop := iter.Pull2(sess.Recv(context.Background()))
+	defer stop()
+
+	first.drop()
+	require.True(t, nextEvent(t, next).Recovering)
+
+	require.NoError(t, sess.SendAudio(context.Background(), []byte{1, 2}))
+	err = sess.SendAudio(context.Background(), []byte{3, 4})
+	assert.ErrorContains(t, err, "audio buffer full")
+}
+
+func TestWithReconnect_GivesUp(t *testing.T) {
+	first := newDropSession()
+	eng := &dropEngine{next: make(chan *dropSession, 1), err: errors.New("dial refused")}
+	eng.next <- first
+
+	sess, err := WithReconnect(fastReconnect)(eng).Start(context.Background())
+	require.NoError(t, err)
+	defer sess.Close()
+	next, stop := iter.Pull2(sess.Recv(context.Background()))
+	defer stop()
+
+	first.drop()
+	require.True(t, nextEvent(t, next).Recovering)
+
+	ev := nextEvent(t, next)
+	assert.Equal(t, EventError, ev.Type)
+	assert.False(t, ev.Recovering)
+	assert.ErrorContains(t, ev.Error, "dial refused")
+	_, _, ok := next()
+	assert.False(t, ok, "stream should end after giving up")
+	assert.Len(t, eng.startConfigs(), 3, "initial start plus two attempts")
+}
+
+func TestWithReconnect_NonFatalErrorAndClose(t *testing.T) {
+	first := newDropSession()
+	eng := &dropEngine{next: make(chan *dropSession, 1)}
+	eng.next <- first
+
+	sess, err := WithReconnect(fastReconnect)(eng).Start(context.Background())
+	require.NoError(t, err)
+	next, stop := iter.Pull2(sess.Recv(context.Background()))
+	defer stop()
+
+	// An error event followed by more events is forwarded as is.
+	first.events <- SessionEvent{Type: EventError, Error: errors.New("bad request")}
+	first.events <- SessionEvent{Type: EventTurnEnd}
+	ev := nextEvent(t, next)
+	assert.False(t, ev.Recovering)
+	assert.EqualError(t, ev.Error, "bad request")
+	assert.Equal(t, EventTurnEnd, nextEvent(t, next).Type)
+
+	// Closing the session does not trigger a reconnect.
+	require.NoError(t, sess.Close())
+	_, _, ok := next()
+	assert.False(t, ok)
+	assert.Len(t, eng.startConfigs(), 1)
+	assert.Error(t, sess.SendText(context.Background(), "hi"))
+}
+
+func TestAsFrameProcessor_RecoveringErrorIsNotFatal(t *testing.T) {
+	ms := newMockSession()
+	ms.recvChan <- SessionEvent{Type: EventError, Error: ErrReconnecting, Recovering: true}
+	ms.recvChan <- SessionEvent{Type: EventTextOutput, Text: "still here"}
+	close(ms.recvChan)
+	ms.closed = true
+
+	proc := AsFrameProcessor(&mockS2S{session: ms})
+	var texts []string
+	for frame, err := range proc.Process(context.Background(), func(func(voice.Frame, error) bool) {}) {
+		require.NoError(t, err)
+		texts = append(texts, frame.Text())
+	}
+	assert.Equal(t, []string{"still here"}, texts)
+}
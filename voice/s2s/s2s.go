@@ -8,6 +8,7 @@ import (
 	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/internal/hookutil"
 	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
 	"github.com/lookatitude/beluga-ai/v2/voice"
 )
 
@@ -32,6 +33,10 @@ const (
 
 	// EventError indicates an error occurred.
 	EventError SessionEventType = "error"
+
+	// EventInterrupted confirms an Interrupt call and reports what it
+	// cancelled.
+	EventInterrupted SessionEventType = "interrupted"
 )
 
 // SessionEvent represents an event from an active S2S session.
@@ -50,6 +55,14 @@ type SessionEvent struct {
 
 	// Error carries error information for Error events.
 	Error error
+
+	// Interruption describes what was cancelled, for Interrupted events.
+	Interruption *Interruption
+
+	// Recovering marks an Error event reporting a dropped connection that is
+	// being re-established (see WithReconnect). The session remains usable;
+	// the application may tell the user that the call is recovering.
+	Recovering bool
 }
 
 // S2S is the speech-to-speech interface. Implementations provide bidirectional
@@ -86,6 +99,8 @@ type SessionReceiver interface {
 // SessionControl governs session lifecycle — interruption and termination.
 type SessionControl interface {
 	// Interrupt signals that the user has interrupted the model's output.
+	// The response stops; what else is cancelled is set with
+	// WithInterruptOptions, and an EventInterrupted confirms it.
 	Interrupt(ctx context.Context) error
 
 	// Close terminates the session and releases resources.
@@ -94,14 +109,14 @@ type SessionControl interface {
 
 // Session represents an active bidirectional audio session with an S2S provider.
 //
-// Session is composed from three smaller interfaces (SessionSender,
-// SessionReceiver, SessionControl) so consumers can depend on the narrowest
-// surface they need. Every existing implementation of Session automatically
-// satisfies all three sub-interfaces; no migration is required.
+// Session is composed from smaller interfaces (SessionSender,
+// SessionReceiver, SessionControl, SessionFormat) so consumers can depend on
+// the narrowest surface they need.
 type Session interface {
 	SessionSender
 	SessionReceiver
 	SessionControl
+	SessionFormat
 }
 
 // Config holds configuration options for S2S sessions.
@@ -121,6 +136,21 @@ type Config struct {
 	// SampleRate is the audio sample rate in Hz.
 	SampleRate int
 
+	// AudioFormat is the caller's audio format. When set, sessions convert
+	// to and from the provider's native formats (see WithAudioFormat).
+	AudioFormat AudioFormat
+
+	// ToolRegistry, when set, holds tools the session executes itself (see
+	// WithToolRegistry).
+	ToolRegistry *tool.Registry
+
+	// Hooks are invoked by the session (see WithHooks).
+	Hooks Hooks
+
+	// Interrupt controls what Session.Interrupt cancels. Nil means
+	// DefaultInterruptOptions.
+	Interrupt *InterruptOptions
+
 	// Extra holds provider-specific configuration.
 	Extra map[string]any
 }
@@ -183,6 +213,10 @@ type Hooks struct {
 	// OnToolCall is called when the model requests a tool call.
 	OnToolCall func(ctx context.Context, call schema.ToolCall)
 
+	// OnToolResult is called with the result of a tool call executed by the
+	// session, before it is sent to the model.
+	OnToolResult func(ctx context.Context, call schema.ToolCall, result schema.ToolResult)
+
 	// OnError is called when an error occurs. Returning nil suppresses it.
 	OnError func(ctx context.Context, err error) error
 }
@@ -200,6 +234,9 @@ func ComposeHooks(hooks ...Hooks) Hooks {
 		OnToolCall: hookutil.ComposeVoid1(h, func(hk Hooks) func(context.Context, schema.ToolCall) {
 			return hk.OnToolCall
 		}),
+		OnToolResult: hookutil.ComposeVoid2(h, func(hk Hooks) func(context.Context, schema.ToolCall, schema.ToolResult) {
+			return hk.OnToolResult
+		}),
 		OnError: hookutil.ComposeErrorPassthrough(h, func(hk Hooks) func(context.Context, error) error {
 			return hk.OnError
 		}),
@@ -208,10 +245,11 @@ func ComposeHooks(hooks ...Hooks) Hooks {
 
 // sessionEventToFrame converts an S2S SessionEvent to a voice.Frame, returning
 // ok=false if the event should be dropped (e.g. EventError with nil Error).
-func sessionEventToFrame(event SessionEvent) (voice.Frame, bool) {
+// Audio frames are tagged with sampleRate.
+func sessionEventToFrame(event SessionEvent, sampleRate int) (voice.Frame, bool) {
 	switch event.Type {
 	case EventAudioOutput:
-		return voice.NewAudioFrame(event.Audio, 24000), true
+		return voice.NewAudioFrame(event.Audio, sampleRate), true
 	case EventTextOutput:
 		return voice.NewTextFrame(event.Text), true
 	case EventTurnEnd:
@@ -326,19 +364,20 @@ func runOutputPump(
 ) {
 	defer wg.Done()
 	defer close(outResults)
+	sampleRate := session.OutputFormat().SampleRate
 	for event, rerr := range session.Recv(pumpCtx) {
 		if rerr != nil {
 			sendResult(pumpCtx, outResults, frameResult{err: rerr})
 			return
 		}
 		if event.Type == EventError {
-			if event.Error != nil {
+			if event.Error != nil && !event.Recovering {
 				sendResult(pumpCtx, outResults, frameResult{err: event.Error})
 				return
 			}
 			continue
 		}
-		frame, ok := sessionEventToFrame(event)
+		frame, ok := sessionEventToFrame(event, sampleRate)
 		if !ok {
 			continue
 		}
@@ -81,6 +81,9 @@ func (m *mockSession) Interrupt(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockSession) InputFormat() AudioFormat  { return AudioFormat{SampleRate: 24000} }
+func (m *mockSession) OutputFormat() AudioFormat { return AudioFormat{SampleRate: 24000} }
+
 func (m *mockSession) Close() error {
 	if !m.closed {
 		close(m.recvChan)
@@ -340,6 +343,10 @@ func TestComposeHooks(t *testing.T) {
 		},
 	}
 
+	hooks2.OnToolResult = func(ctx context.Context, call schema.ToolCall, result schema.ToolResult) {
+		callOrder = append(callOrder, "hooks2-result:"+result.CallID)
+	}
+
 	composed := ComposeHooks(hooks1, hooks2)
 
 	// Call composed hooks.
@@ -347,12 +354,14 @@ func TestComposeHooks(t *testing.T) {
 	composed.OnTurn(ctx, "user", "agent")
 	composed.OnInterrupt(ctx)
 	composed.OnToolCall(ctx, schema.ToolCall{Name: "search"})
+	composed.OnToolResult(ctx, schema.ToolCall{Name: "search"}, schema.ToolResult{CallID: "c1"})
 
 	expected := []string{
 		"hooks1-turn:user:agent",
 		"hooks2-turn:user:agent",
 		"hooks1-interrupt",
 		"hooks2-tool:search",
+		"hooks2-result:c1",
 	}
 	assert.Equal(t, expected, callOrder)
 }
@@ -0,0 +1,170 @@
+package s2s
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+)
+
+// WithToolRegistry makes the session execute tool calls itself. Tools in reg
+// are offered to the model, in addition to any set with WithTools, and when
+// the model calls one the session runs it and sends the result back with
+// SendToolResult; the EventToolCall is not yielded by Recv. Calls to tools
+// not in reg are yielded as before, so the application can still handle
+// them manually. Tools run concurrently with the event stream, through
+// whatever middleware they were registered with (timeouts, retries, hooks).
+// Use WithHooks to observe calls and results.
+func WithToolRegistry(reg *tool.Registry) Option {
+	return func(cfg *Config) {
+		cfg.ToolRegistry = reg
+		if reg == nil {
+			return
+		}
+		have := make(map[string]bool, len(cfg.Tools))
+		for _, def := range cfg.Tools {
+			have[def.Name] = true
+		}
+		for _, t := range reg.All() {
+			if !have[t.Name()] {
+				cfg.Tools = append(cfg.Tools, tool.ToDefinition(t))
+			}
+		}
+	}
+}
+
+// WithHooks sets the hooks invoked by the session. OnToolCall, OnToolResult
+// and OnError are called for tools dispatched through WithToolRegistry.
+func WithHooks(h Hooks) Option {
+	return func(cfg *Config) {
+		cfg.Hooks = h
+	}
+}
+
+// WrapSession applies the session features selected in cfg to a provider's
+// native session: audio format conversion (see AdaptAudio), interruption
+// semantics (see WithInterruptOptions) and tool dispatch (see
+// WithToolRegistry). Providers call it at the end of Start. If it fails,
+// sess is closed.
+func WrapSession(sess Session, cfg Config) (Session, error) {
+	sess, err := AdaptAudio(sess, cfg.AudioFormat)
+	if err != nil {
+		return nil, err
+	}
+	is := newInterruptSession(sess, interruptOptions(cfg), cfg.Hooks)
+	if cfg.ToolRegistry == nil {
+		return is, nil
+	}
+	ds := &dispatchSession{
+		Session:  is,
+		registry: cfg.ToolRegistry,
+		hooks:    cfg.Hooks,
+		running:  make(map[string]context.CancelFunc),
+	}
+	is.onCancel = ds.cancel
+	return ds, nil
+}
+
+// dispatchSession executes tool calls against a registry.
+type dispatchSession struct {
+	Session
+	registry *tool.Registry
+	hooks    Hooks
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // by call ID
+}
+
+// cancel cancels the running tool calls with the given IDs.
+func (s *dispatchSession) cancel(ids []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if cancel, ok := s.running[id]; ok {
+			cancel()
+		}
+	}
+}
+
+func (s *dispatchSession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		// Tool runs are bound to this Recv: when it ends they are canceled
+		// and waited for.
+		runCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		for event, err := range s.Session.Recv(ctx) {
+			if err == nil && event.Type == EventToolCall && event.ToolCall != nil {
+				if t, getErr := s.registry.Get(event.ToolCall.Name); getErr == nil {
+					call := *event.ToolCall
+					wg.Go(func() { s.dispatch(runCtx, t, call) })
+					continue
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// dispatch runs one tool call and sends its result to the model. Failures
+// are sent as IsError results so the model can recover. The result of a call
+// cancelled by Interrupt is dropped by the interrupt layer.
+func (s *dispatchSession) dispatch(ctx context.Context, t tool.Tool, call schema.ToolCall) {
+	if s.hooks.OnToolCall != nil {
+		s.hooks.OnToolCall(ctx, call)
+	}
+
+	callCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[call.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, call.ID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	res := runTool(callCtx, t, call)
+	result := schema.ToolResult{CallID: call.ID, Content: res.Content, IsError: res.IsError}
+	if s.hooks.OnToolResult != nil {
+		s.hooks.OnToolResult(ctx, call, result)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err := s.Session.SendToolResult(ctx, result); err != nil && s.hooks.OnError != nil {
+		_ = s.hooks.OnError(ctx, core.Errorf(core.ErrProviderDown, "s2s: send tool result for %q: %w", call.Name, err))
+	}
+}
+
+// runTool parses the call's arguments and executes t. Any error is encoded
+// as an IsError result.
+func runTool(ctx context.Context, t tool.Tool, call schema.ToolCall) *tool.Result {
+	var args map[string]any
+	if strings.TrimSpace(call.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return tool.ErrorResult(core.Errorf(core.ErrInvalidInput, "invalid arguments for tool %q: %w", call.Name, err))
+		}
+	}
+	res, err := t.Execute(ctx, args)
+	if err != nil {
+		return tool.ErrorResult(core.Errorf(core.ErrToolFailed, "tool %q execution failed: %w", call.Name, err))
+	}
+	if res == nil {
+		return &tool.Result{}
+	}
+	return res
+}
@@ -0,0 +1,166 @@
+package s2s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/lookatitude/beluga-ai/v2/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherInput struct {
+	City string `json:"city"`
+}
+
+func newTestRegistry(t *testing.T) *tool.Registry {
+	t.Helper()
+	reg := tool.NewRegistry()
+	require.NoError(t, reg.Add(tool.NewFuncTool("weather", "Get the weather",
+		func(_ context.Context, in weatherInput) (*tool.Result, error) {
+			return tool.TextResult("sunny in " + in.City), nil
+		})))
+	require.NoError(t, reg.Add(tool.NewFuncTool("broken", "Always fails",
+		func(context.Context, struct{}) (*tool.Result, error) {
+			return nil, errors.New("boom")
+		})))
+	return reg
+}
+
+// resultSession reports tool results on a channel, since they are sent from
+// the dispatching goroutine.
+type resultSession struct {
+	*mockSession
+	results chan schema.ToolResult
+}
+
+func newResultSession() *resultSession {
+	return &resultSession{mockSession: newMockSession(), results: make(chan schema.ToolResult, 4)}
+}
+
+func (s *resultSession) SendToolResult(_ context.Context, result schema.ToolResult) error {
+	s.results <- result
+	return nil
+}
+
+// collect consumes sess until the provider stream closes, which happens once
+// n tool results have been sent.
+func (s *resultSession) collect(t *testing.T, sess Session, n int) ([]SessionEvent, []schema.ToolResult) {
+	t.Helper()
+	var results []schema.ToolResult
+	go func() {
+		for range n {
+			results = append(results, <-s.results)
+		}
+		close(s.recvChan)
+	}()
+	var events []SessionEvent
+	for ev, err := range sess.Recv(context.Background()) {
+		require.NoError(t, err)
+		events = append(events, ev)
+	}
+	return events, results
+}
+
+func TestWithToolRegistry_Definitions(t *testing.T) {
+	reg := newTestRegistry(t)
+	cfg := ApplyOptions(
+		WithTools([]schema.ToolDefinition{{Name: "weather", Description: "custom"}, {Name: "manual"}}),
+		WithToolRegistry(reg),
+	)
+	require.Len(t, cfg.Tools, 3)
+	assert.Equal(t, "custom", cfg.Tools[0].Description)
+	assert.Equal(t, "manual", cfg.Tools[1].Name)
+	assert.Equal(t, "broken", cfg.Tools[2].Name)
+	assert.Same(t, reg, cfg.ToolRegistry)
+}
+
+func TestWrapSession_NoRegistry(t *testing.T) {
+	sess := newMockSession()
+	got, err := WrapSession(sess, Config{})
+	require.NoError(t, err)
+	_, dispatching := got.(*dispatchSession)
+	assert.False(t, dispatching)
+}
+
+func TestWrapSession_DispatchesTools(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var results []schema.ToolResult
+	hooks := Hooks{
+		OnToolCall: func(_ context.Context, call schema.ToolCall) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call.Name)
+		},
+		OnToolResult: func(_ context.Context, _ schema.ToolCall, result schema.ToolResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		},
+	}
+
+	sess := newResultSession()
+	cfg := ApplyOptions(WithToolRegistry(newTestRegistry(t)), WithHooks(hooks))
+	wrapped, err := WrapSession(sess, cfg)
+	require.NoError(t, err)
+
+	sess.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &schema.ToolCall{ID: "c1", Name: "weather", Arguments: `{"city":"Lisbon"}`}}
+	sess.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &schema.ToolCall{ID: "c2", Name: "manual"}}
+	sess.recvChan <- SessionEvent{Type: EventTextOutput, Text: "ok"}
+	events, sentResults := sess.collect(t, wrapped, 1)
+
+	// Only the unregistered call reaches the application.
+	require.Len(t, events, 2)
+	assert.Equal(t, "manual", events[0].ToolCall.Name)
+	assert.Equal(t, "ok", events[1].Text)
+
+	require.Len(t, sentResults, 1)
+	sent := sentResults[0]
+	assert.Equal(t, "c1", sent.CallID)
+	assert.False(t, sent.IsError)
+	require.Len(t, sent.Content, 1)
+	assert.Equal(t, "sunny in Lisbon", sent.Content[0].(schema.TextPart).Text)
+
+	assert.Equal(t, []string{"weather"}, calls)
+	assert.Equal(t, []schema.ToolResult{sent}, results)
+}
+
+func TestWrapSession_ToolErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		call schema.ToolCall
+		want string
+	}{
+		{
+			name: "execution error",
+			call: schema.ToolCall{ID: "c1", Name: "broken"},
+			want: "boom",
+		},
+		{
+			name: "invalid arguments",
+			call: schema.ToolCall{ID: "c2", Name: "weather", Arguments: "{not json"},
+			want: "invalid arguments",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := newResultSession()
+			wrapped, err := WrapSession(sess, ApplyOptions(WithToolRegistry(newTestRegistry(t))))
+			require.NoError(t, err)
+
+			call := tt.call
+			sess.recvChan <- SessionEvent{Type: EventToolCall, ToolCall: &call}
+			_, sentResults := sess.collect(t, wrapped, 1)
+
+			require.Len(t, sentResults, 1)
+			sent := sentResults[0]
+			assert.Equal(t, tt.call.ID, sent.CallID)
+			assert.True(t, sent.IsError)
+			assert.Contains(t, sent.Content[0].(schema.TextPart).Text, tt.want)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package s2s
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+)
+
+// HistoryProvider is implemented by sessions that record their conversation,
+// such as those created through WithHistory.
+type HistoryProvider interface {
+	// History returns the completed turns so far, oldest first.
+	History() []schema.Turn
+}
+
+// TranscriptOption configures a Transcript.
+type TranscriptOption func(*Transcript)
+
+// WithTranscriptSink calls fn with each turn as it completes, for example to
+// persist it to a session store. fn is called synchronously from the
+// goroutine consuming the session's events.
+func WithTranscriptSink(fn func(ctx context.Context, turn schema.Turn)) TranscriptOption {
+	return func(t *Transcript) {
+		t.sink = fn
+	}
+}
+
+// Transcript accumulates the events of an S2S session into schema.Turn
+// values, giving S2S calls the same history a cascading pipeline records.
+// EventTranscript text (and text sent with SendText through WithHistory)
+// forms the turn's input, EventTextOutput text and EventToolCall calls form
+// its output, and EventTurnEnd completes it. Turns with no content are
+// dropped. It is safe for concurrent use.
+type Transcript struct {
+	sink func(ctx context.Context, turn schema.Turn)
+	now  func() time.Time
+
+	mu        sync.Mutex
+	turns     []schema.Turn
+	user      []string
+	agent     strings.Builder
+	toolCalls []schema.ToolCall
+	start     time.Time
+}
+
+// NewTranscript creates an empty Transcript.
+func NewTranscript(opts ...TranscriptOption) *Transcript {
+	t := &Transcript{now: time.Now}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Observe records one session event.
+func (t *Transcript) Observe(ctx context.Context, event SessionEvent) {
+	switch event.Type {
+	case EventTranscript:
+		t.addUser(event.Text)
+	case EventTextOutput:
+		t.mu.Lock()
+		t.begin()
+		t.agent.WriteString(event.Text)
+		t.mu.Unlock()
+	case EventToolCall:
+		if event.ToolCall != nil {
+			t.mu.Lock()
+			t.begin()
+			t.toolCalls = append(t.toolCalls, *event.ToolCall)
+			t.mu.Unlock()
+		}
+	case EventTurnEnd:
+		t.endTurn(ctx)
+	}
+}
+
+// History returns the completed turns so far, oldest first.
+func (t *Transcript) History() []schema.Turn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]schema.Turn(nil), t.turns...)
+}
+
+func (t *Transcript) addUser(text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.begin()
+	t.user = append(t.user, text)
+}
+
+// begin marks the start of the current turn. t.mu must be held.
+func (t *Transcript) begin() {
+	if t.start.IsZero() {
+		t.start = t.now()
+	}
+}
+
+func (t *Transcript) endTurn(ctx context.Context) {
+	t.mu.Lock()
+	if len(t.user) == 0 && t.agent.Len() == 0 && len(t.toolCalls) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	output := schema.NewAIMessage(t.agent.String())
+	output.ToolCalls = t.toolCalls
+	turn := schema.Turn{
+		Input:     schema.NewHumanMessage(strings.Join(t.user, " ")),
+		Output:    output,
+		Timestamp: t.start,
+	}
+	t.turns = append(t.turns, turn)
+	t.user, t.toolCalls, t.start = nil, nil, time.Time{}
+	t.agent.Reset()
+	sink := t.sink
+	t.mu.Unlock()
+
+	if sink != nil {
+		sink(ctx, turn)
+	}
+}
+
+// WithHistory returns middleware whose sessions record their conversation
+// in a Transcript. The sessions implement HistoryProvider; turns are
+// recorded as the application consumes Recv, and also delivered to any
+// WithTranscriptSink.
+//
+//	engine = s2s.ApplyMiddleware(engine, s2s.WithHistory())
+//	sess, _ := engine.Start(ctx)
+//	// ... consume sess.Recv ...
+//	turns := sess.(s2s.HistoryProvider).History()
+func WithHistory(opts ...TranscriptOption) Middleware {
+	return func(next S2S) S2S {
+		return &historyEngine{next: next, opts: opts}
+	}
+}
+
+type historyEngine struct {
+	next S2S
+	opts []TranscriptOption
+}
+
+func (e *historyEngine) Start(ctx context.Context, opts ...Option) (Session, error) {
+	sess, err := e.next.Start(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &historySession{Session: sess, transcript: NewTranscript(e.opts...)}, nil
+}
+
+// historySession feeds a session's events into a Transcript.
+type historySession struct {
+	Session
+	transcript *Transcript
+}
+
+var _ HistoryProvider = (*historySession)(nil)
+
+// SendText records typed input as part of the user's turn.
+func (s *historySession) SendText(ctx context.Context, text string) error {
+	if err := s.Session.SendText(ctx, text); err != nil {
+		return err
+	}
+	s.transcript.addUser(text)
+	return nil
+}
+
+func (s *historySession) Recv(ctx context.Context) iter.Seq2[SessionEvent, error] {
+	return func(yield func(SessionEvent, error) bool) {
+		for event, err := range s.Session.Recv(ctx) {
+			if err == nil {
+				s.transcript.Observe(ctx, event)
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+func (s *historySession) History() []schema.Turn {
+	return s.transcript.History()
+}
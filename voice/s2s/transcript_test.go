@@ -0,0 +1,78 @@
+package s2s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript_BuildsTurns(t *testing.T) {
+	var sunk []schema.Turn
+	tr := NewTranscript(WithTranscriptSink(func(_ context.Context, turn schema.Turn) {
+		sunk = append(sunk, turn)
+	}))
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tr.now = func() time.Time { return start }
+
+	ctx := context.Background()
+	call := &schema.ToolCall{ID: "c1", Name: "weather", Arguments: `{"city":"Lisbon"}`}
+	for _, ev := range []SessionEvent{
+		{Type: EventTurnEnd}, // empty turns are dropped
+		{Type: EventTranscript, Text: "What's the weather"},
+		{Type: EventTranscript, Text: " in Lisbon? "},
+		{Type: EventToolCall, ToolCall: call},
+		{Type: EventTextOutput, Text: "It is "},
+		{Type: EventAudioOutput, Audio: []byte{1, 2}},
+		{Type: EventTextOutput, Text: "sunny."},
+		{Type: EventTurnEnd},
+		{Type: EventTextOutput, Text: "Anything else?"},
+		{Type: EventTurnEnd},
+	} {
+		tr.Observe(ctx, ev)
+	}
+
+	turns := tr.History()
+	require.Len(t, turns, 2)
+	assert.Equal(t, "What's the weather in Lisbon?", turns[0].Input.(*schema.HumanMessage).Text())
+	out := turns[0].Output.(*schema.AIMessage)
+	assert.Equal(t, "It is sunny.", out.Text())
+	assert.Equal(t, []schema.ToolCall{*call}, out.ToolCalls)
+	assert.Equal(t, start, turns[0].Timestamp)
+
+	assert.Equal(t, "", turns[1].Input.(*schema.HumanMessage).Text())
+	assert.Equal(t, "Anything else?", turns[1].Output.(*schema.AIMessage).Text())
+	assert.Equal(t, turns, sunk)
+}
+
+func TestWithHistory(t *testing.T) {
+	ms := newMockSession()
+	engine := ApplyMiddleware(&mockS2S{session: ms}, WithHistory())
+	sess, err := engine.Start(context.Background())
+	require.NoError(t, err)
+
+	hp, ok := sess.(HistoryProvider)
+	require.True(t, ok, "session should implement HistoryProvider")
+
+	require.NoError(t, sess.SendText(context.Background(), "hello"))
+	assert.Equal(t, []string{"hello"}, ms.textSent)
+
+	ms.recvChan <- SessionEvent{Type: EventTextOutput, Text: "hi there"}
+	ms.recvChan <- SessionEvent{Type: EventTurnEnd}
+	require.NoError(t, sess.Close())
+
+	var types []SessionEventType
+	for ev, err := range sess.Recv(context.Background()) {
+		require.NoError(t, err)
+		types = append(types, ev.Type)
+	}
+	assert.Equal(t, []SessionEventType{EventTextOutput, EventTurnEnd}, types)
+
+	turns := hp.History()
+	require.Len(t, turns, 1)
+	assert.Equal(t, "hello", turns[0].Input.(*schema.HumanMessage).Text())
+	assert.Equal(t, "hi there", turns[0].Output.(*schema.AIMessage).Text())
+}
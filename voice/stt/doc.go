@@ -17,6 +17,54 @@
 // transcribed text, finality flag, confidence score, timestamp, detected
 // language, and optional word-level timing via [Word].
 //
+// With [WithDiarization], providers that identify speakers label each [Word]
+// with its Speaker, and the event's Speaker is the one with the most words
+// (see [SpeakerOf]).
+//
+// # Word Filtering
+//
+// [WithMinWordConfidence] drops low-confidence words from streaming
+// transcripts and [WithProfanityFilter] masks the given terms with
+// asterisks. Both Text and Words reflect the filtering, and the unfiltered
+// text is kept in RawText for audit:
+//
+//	events := engine.TranscribeStream(ctx, audioStream,
+//	    stt.WithMinWordConfidence(0.6),
+//	    stt.WithProfanityFilter(blocklist...),
+//	)
+//
+// Providers apply the filters with [FilterTranscripts] and, for batch
+// results, which carry no word confidences, [FilterText].
+//
+// # Stalled Streams
+//
+// A streaming provider that stops responding mid-utterance would leave the
+// turn waiting for a final transcript forever. [WithInterimTimeout] bounds
+// the silence after an interim result and [WithFinalizationTimeout] bounds
+// how long an utterance may stay interim; when either expires, the latest
+// interim result is emitted as final with Synthetic set:
+//
+//	events := engine.TranscribeStream(ctx, audioStream,
+//	    stt.WithInterimTimeout(800*time.Millisecond),
+//	    stt.WithFinalizationTimeout(5*time.Second),
+//	)
+//
+// Streaming providers implement this with [FinalizeStalled].
+//
+// # Long Recordings
+//
+// Many providers cap the audio accepted by one request. [TranscribeLong]
+// splits a recording of any length at silences found with an energy VAD
+// into segments no longer than [WithMaxSegmentDuration], transcribes each
+// as a WAV file, and returns the joined text with a timestamped event per
+// segment. It accepts WAV or raw 16-bit mono PCM:
+//
+//	result, err := stt.TranscribeLong(ctx, engine, wav,
+//	    stt.WithMaxSegmentDuration(10*time.Minute))
+//	for _, seg := range result.Segments {
+//	    fmt.Printf("[%v] %s\n", seg.Timestamp, seg.Text)
+//	}
+//
 // # Registry Pattern
 //
 // Providers register via [Register] in their init() function and are created
@@ -40,6 +88,10 @@
 //
 //	processor := stt.AsFrameProcessor(engine)
 //
+// With [WithDiarization] the processor transcribes through TranscribeStream
+// and emits a text frame per final transcript, its "speaker" metadata naming
+// the speaker.
+//
 // # Configuration
 //
 // The [Config] struct supports language, model, punctuation, diarization,
@@ -60,4 +112,5 @@
 //   - groq — Groq Whisper (voice/stt/providers/groq)
 //   - elevenlabs — ElevenLabs Scribe (voice/stt/providers/elevenlabs)
 //   - gladia — Gladia (voice/stt/providers/gladia)
+//   - whispercpp — local whisper.cpp, offline (voice/stt/providers/whispercpp)
 package stt
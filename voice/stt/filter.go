@@ -0,0 +1,132 @@
+package stt
+
+import (
+	"iter"
+	"strings"
+	"unicode"
+)
+
+// alignWindow is how many words ahead of the current position a text token
+// is matched against, so that a word the provider renders differently in
+// the text (e.g., "20" for "twenty") does not stall the alignment.
+const alignWindow = 3
+
+// FilterTranscripts applies the word filters of cfg (MinWordConfidence and
+// ProfanityTerms) to each event of events with FilterEvent. Providers wrap
+// their streams with it so that filtering behaves the same everywhere.
+func FilterTranscripts(cfg Config, events iter.Seq2[TranscriptEvent, error]) iter.Seq2[TranscriptEvent, error] {
+	if !filtering(cfg) {
+		return events
+	}
+	return func(yield func(TranscriptEvent, error) bool) {
+		for event, err := range events {
+			if err == nil {
+				event = FilterEvent(cfg, event)
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// FilterEvent drops the words of event whose confidence is below
+// cfg.MinWordConfidence and masks the words listed in cfg.ProfanityTerms,
+// in both Text and Words. The unfiltered text is kept in RawText. Words
+// are located in Text by matching them in order, ignoring case and
+// surrounding punctuation.
+func FilterEvent(cfg Config, event TranscriptEvent) TranscriptEvent {
+	if !filtering(cfg) {
+		return event
+	}
+	profane := profanitySet(cfg.ProfanityTerms)
+	lowConfidence := func(w Word) bool {
+		return cfg.MinWordConfidence > 0 && w.Confidence < cfg.MinWordConfidence
+	}
+
+	event.RawText = event.Text
+
+	tokens := strings.Fields(event.Text)
+	kept := make([]string, 0, len(tokens))
+	next := 0
+	for _, token := range tokens {
+		key := normalizeWord(token)
+		for i := next; i < len(event.Words) && i < next+alignWindow; i++ {
+			if normalizeWord(event.Words[i].Text) != key {
+				continue
+			}
+			next = i + 1
+			if lowConfidence(event.Words[i]) {
+				token = ""
+			}
+			break
+		}
+		if token != "" {
+			kept = append(kept, maskWord(token, profane))
+		}
+	}
+	event.Text = strings.Join(kept, " ")
+
+	if event.Words != nil {
+		words := make([]Word, 0, len(event.Words))
+		for _, w := range event.Words {
+			if lowConfidence(w) {
+				continue
+			}
+			w.Text = maskWord(w.Text, profane)
+			words = append(words, w)
+		}
+		event.Words = words
+	}
+	return event
+}
+
+// FilterText masks the words listed in cfg.ProfanityTerms in text. Batch
+// transcription has no word confidences, so MinWordConfidence does not
+// apply.
+func FilterText(cfg Config, text string) string {
+	if len(cfg.ProfanityTerms) == 0 {
+		return text
+	}
+	profane := profanitySet(cfg.ProfanityTerms)
+	tokens := strings.Fields(text)
+	for i, token := range tokens {
+		tokens[i] = maskWord(token, profane)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// filtering reports whether cfg enables any word filter.
+func filtering(cfg Config) bool {
+	return cfg.MinWordConfidence > 0 || len(cfg.ProfanityTerms) > 0
+}
+
+func profanitySet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		if t = normalizeWord(t); t != "" {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+// normalizeWord lowercases w and trims the punctuation around it.
+func normalizeWord(w string) string {
+	return strings.ToLower(strings.TrimFunc(w, notWordRune))
+}
+
+func notWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// maskWord replaces the letters of w with asterisks if it is a profane
+// term, keeping the punctuation around it.
+func maskWord(w string, profane map[string]bool) string {
+	if len(profane) == 0 || !profane[normalizeWord(w)] {
+		return w
+	}
+	start := len(w) - len(strings.TrimLeftFunc(w, notWordRune))
+	end := len(strings.TrimRightFunc(w, notWordRune))
+	return w[:start] + strings.Repeat("*", len([]rune(w[start:end]))) + w[end:]
+}
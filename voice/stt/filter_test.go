@@ -0,0 +1,107 @@
+package stt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterEvent_MinWordConfidence(t *testing.T) {
+	cfg := ApplyOptions(WithMinWordConfidence(0.5))
+	event := TranscriptEvent{
+		Text: "Well, I think it's fine.",
+		Words: []Word{
+			{Text: "well", Confidence: 0.9},
+			{Text: "i", Confidence: 0.2},
+			{Text: "think", Confidence: 0.8},
+			{Text: "it's", Confidence: 0.3},
+			{Text: "fine", Confidence: 0.95},
+		},
+	}
+
+	got := FilterEvent(cfg, event)
+	assert.Equal(t, "Well, think fine.", got.Text)
+	assert.Equal(t, "Well, I think it's fine.", got.RawText)
+	require.Len(t, got.Words, 3)
+	assert.Equal(t, []string{"well", "think", "fine"}, wordTexts(got.Words))
+	assert.Len(t, event.Words, 5, "the input event should not be modified")
+}
+
+func TestFilterEvent_Profanity(t *testing.T) {
+	cfg := ApplyOptions(WithProfanityFilter("Darn", "heck"))
+	event := TranscriptEvent{
+		Text:  "Darn it, what the heck!",
+		Words: []Word{{Text: "darn"}, {Text: "it"}, {Text: "what"}, {Text: "the"}, {Text: "heck"}},
+	}
+
+	got := FilterEvent(cfg, event)
+	assert.Equal(t, "**** it, what the ****!", got.Text)
+	assert.Equal(t, "Darn it, what the heck!", got.RawText)
+	assert.Equal(t, []string{"****", "it", "what", "the", "****"}, wordTexts(got.Words))
+}
+
+func TestFilterEvent_Alignment(t *testing.T) {
+	// The text renders "twenty" as "20"; words after it must still align.
+	cfg := ApplyOptions(WithMinWordConfidence(0.5))
+	event := TranscriptEvent{
+		Text: "I have 20 apples",
+		Words: []Word{
+			{Text: "i", Confidence: 0.9},
+			{Text: "have", Confidence: 0.9},
+			{Text: "twenty", Confidence: 0.9},
+			{Text: "apples", Confidence: 0.1},
+		},
+	}
+
+	got := FilterEvent(cfg, event)
+	assert.Equal(t, "I have 20", got.Text)
+	assert.Equal(t, []string{"i", "have", "twenty"}, wordTexts(got.Words))
+}
+
+func TestFilterEvent_Disabled(t *testing.T) {
+	event := TranscriptEvent{Text: "hello  world", Words: []Word{{Text: "hello", Confidence: 0.1}}}
+	got := FilterEvent(Config{}, event)
+	assert.Equal(t, event, got)
+	assert.Empty(t, got.RawText)
+}
+
+func TestFilterTranscripts(t *testing.T) {
+	events := func(yield func(TranscriptEvent, error) bool) {
+		if !yield(TranscriptEvent{Text: "oh heck", IsFinal: true}, nil) {
+			return
+		}
+		yield(TranscriptEvent{}, errors.New("stream failed"))
+	}
+
+	var got []TranscriptEvent
+	var gotErr error
+	for ev, err := range FilterTranscripts(ApplyOptions(WithProfanityFilter("heck")), events) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, ev)
+	}
+	require.Len(t, got, 1)
+	assert.Equal(t, "oh ****", got[0].Text)
+	assert.Equal(t, "oh heck", got[0].RawText)
+	assert.True(t, got[0].IsFinal)
+	assert.EqualError(t, gotErr, "stream failed")
+}
+
+func TestFilterText(t *testing.T) {
+	cfg := ApplyOptions(WithMinWordConfidence(0.9), WithProfanityFilter("heck"))
+	assert.Equal(t, "What the ****?", FilterText(cfg, "What the HECK?"))
+	assert.Equal(t, "What the heck?", FilterText(Config{}, "What the heck?"))
+	assert.Equal(t, "hecklers", FilterText(cfg, "hecklers"), "only whole words are masked")
+}
+
+func wordTexts(words []Word) []string {
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.Text
+	}
+	return texts
+}
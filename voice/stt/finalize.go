@@ -0,0 +1,153 @@
+package stt
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FinalizeStalled applies the InterimTimeout and FinalizationTimeout of cfg to
+// the transcript stream produced by stream. When a timeout expires while an
+// utterance has interim results but no final, the latest interim result is
+// emitted as a final event with Synthetic set, so that a stalled provider
+// cannot hold up the turn. The same happens if the stream ends with an
+// utterance still interim. A final the provider sends late for a forced
+// utterance is dropped: the next final, if no new interim result came
+// first, is taken to be that late final when its text continues the forced
+// text or it carries the same timestamp. Any other final is passed through.
+//
+// stream is called with a context that is cancelled when the consumer stops
+// iterating. If neither timeout is set, the stream is returned unchanged.
+// Streaming providers call this from TranscribeStream.
+func FinalizeStalled(ctx context.Context, cfg Config, stream func(ctx context.Context) iter.Seq2[TranscriptEvent, error]) iter.Seq2[TranscriptEvent, error] {
+	if cfg.InterimTimeout <= 0 && cfg.FinalizationTimeout <= 0 {
+		return stream(ctx)
+	}
+	return func(yield func(TranscriptEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		type result struct {
+			event TranscriptEvent
+			err   error
+		}
+		results := make(chan result)
+		go func() {
+			defer close(results)
+			for event, err := range stream(ctx) {
+				select {
+				case results <- result{event, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		defer func() {
+			cancel()
+			for range results {
+			}
+		}()
+
+		timer := time.NewTimer(0)
+		timer.Stop()
+		defer timer.Stop()
+
+		var (
+			pending *TranscriptEvent // latest interim of the open utterance
+			opened  time.Time        // when the open utterance started
+			forced  *TranscriptEvent // the utterance last forced final, until its late final is ruled out
+		)
+		finalize := func() bool {
+			event := *pending
+			event.IsFinal = true
+			event.Synthetic = true
+			pending, forced = nil, &event
+			return yield(event, nil)
+		}
+
+		for {
+			select {
+			case r, ok := <-results:
+				if !ok {
+					if pending != nil {
+						finalize()
+					}
+					return
+				}
+				if r.err != nil {
+					if !yield(r.event, r.err) {
+						return
+					}
+					continue
+				}
+				if r.event.IsFinal {
+					timer.Stop()
+					pending = nil
+					late := forced != nil && sameUtterance(*forced, r.event)
+					forced = nil
+					if late {
+						continue
+					}
+					if !yield(r.event, nil) {
+						return
+					}
+					continue
+				}
+
+				now := time.Now()
+				if pending == nil {
+					opened = now
+				}
+				event := r.event
+				pending, forced = &event, nil
+				timer.Reset(finalizeDelay(cfg, now, opened))
+				if !yield(r.event, nil) {
+					return
+				}
+			case <-timer.C:
+				if pending != nil && !finalize() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// sameUtterance reports whether final is the provider's final for the
+// utterance that was forced final as forced.
+func sameUtterance(forced, final TranscriptEvent) bool {
+	if forced.Timestamp > 0 && forced.Timestamp == final.Timestamp {
+		return true
+	}
+	a, b := normalizeTranscript(forced.Text), normalizeTranscript(final.Text)
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(b, a) || strings.HasPrefix(a, b)
+}
+
+// normalizeTranscript lowercases text and reduces it to words separated by
+// single spaces, so that revisions differing only in case and punctuation
+// compare equal.
+func normalizeTranscript(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	return strings.Join(words, " ")
+}
+
+// finalizeDelay returns how long after now the open utterance, started at
+// opened, is forced final.
+func finalizeDelay(cfg Config, now, opened time.Time) time.Duration {
+	delay := time.Duration(-1)
+	if cfg.InterimTimeout > 0 {
+		delay = cfg.InterimTimeout
+	}
+	if cfg.FinalizationTimeout > 0 {
+		d := opened.Add(cfg.FinalizationTimeout).Sub(now)
+		if delay < 0 || d < delay {
+			delay = d
+		}
+	}
+	return max(delay, 0)
+}
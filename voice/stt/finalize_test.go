@@ -0,0 +1,215 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// step is a scripted provider event delivered after a delay.
+type step struct {
+	after time.Duration
+	event TranscriptEvent
+	err   error
+}
+
+// scriptedStream returns a stream function that plays steps and then blocks
+// until its context is cancelled, like a provider that went silent.
+func scriptedStream(steps []step, hang bool) func(context.Context) iter.Seq2[TranscriptEvent, error] {
+	return func(ctx context.Context) iter.Seq2[TranscriptEvent, error] {
+		return func(yield func(TranscriptEvent, error) bool) {
+			for _, s := range steps {
+				select {
+				case <-time.After(s.after):
+				case <-ctx.Done():
+					return
+				}
+				if !yield(s.event, s.err) {
+					return
+				}
+			}
+			if hang {
+				<-ctx.Done()
+			}
+		}
+	}
+}
+
+func collectEvents(t *testing.T, seq iter.Seq2[TranscriptEvent, error], n int) []TranscriptEvent {
+	t.Helper()
+	var events []TranscriptEvent
+	for event, err := range seq {
+		require.NoError(t, err)
+		events = append(events, event)
+		if len(events) == n {
+			break
+		}
+	}
+	return events
+}
+
+func interim(text string) TranscriptEvent { return TranscriptEvent{Text: text} }
+func final(text string) TranscriptEvent   { return TranscriptEvent{Text: text, IsFinal: true} }
+
+func TestFinalizeStalled_Disabled(t *testing.T) {
+	called := false
+	stream := func(context.Context) iter.Seq2[TranscriptEvent, error] {
+		called = true
+		return scriptedStream([]step{{event: final("hi")}}, false)(context.Background())
+	}
+	events := collectEvents(t, FinalizeStalled(context.Background(), Config{}, stream), 0)
+	assert.True(t, called)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Synthetic)
+}
+
+func TestFinalizeStalled_InterimTimeout(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(30 * time.Millisecond))
+	stream := scriptedStream([]step{
+		{event: interim("hello")},
+		{after: 10 * time.Millisecond, event: interim("hello wor")},
+	}, true)
+
+	start := time.Now()
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 3)
+	require.Len(t, events, 3)
+	assert.Equal(t, TranscriptEvent{Text: "hello wor", IsFinal: true, Synthetic: true}, events[2])
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestFinalizeStalled_FinalizationTimeout(t *testing.T) {
+	// Interims keep arriving, so only the finalization timeout can fire.
+	cfg := ApplyOptions(WithInterimTimeout(time.Second), WithFinalizationTimeout(50*time.Millisecond))
+	var steps []step
+	for i := range 20 {
+		steps = append(steps, step{after: 10 * time.Millisecond, event: interim(string(rune('a' + i)))})
+	}
+
+	var forced *TranscriptEvent
+	for event, err := range FinalizeStalled(context.Background(), cfg, scriptedStream(steps, true)) {
+		require.NoError(t, err)
+		if event.IsFinal {
+			forced = &event
+			break
+		}
+	}
+	require.NotNil(t, forced)
+	assert.True(t, forced.Synthetic)
+	assert.Less(t, forced.Text, "t", "expected finalization while interims were still arriving")
+}
+
+func TestFinalizeStalled_ProviderFinal(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(30 * time.Millisecond))
+	stream := scriptedStream([]step{
+		{event: interim("hel")},
+		{after: 5 * time.Millisecond, event: final("hello")},
+		{after: 60 * time.Millisecond, event: interim("next")},
+		{after: 5 * time.Millisecond, event: final("next one")},
+	}, false)
+
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 0)
+	assert.Equal(t, []TranscriptEvent{interim("hel"), final("hello"), interim("next"), final("next one")}, events)
+}
+
+func TestFinalizeStalled_DropsLateFinal(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(20 * time.Millisecond))
+	stream := scriptedStream([]step{
+		{event: interim("hello")},
+		{after: 60 * time.Millisecond, event: final("hello world")},
+		{event: interim("bye")},
+		{event: final("bye")},
+	}, false)
+
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 0)
+	assert.Equal(t, []TranscriptEvent{
+		interim("hello"),
+		{Text: "hello", IsFinal: true, Synthetic: true},
+		interim("bye"),
+		final("bye"),
+	}, events)
+}
+
+func TestFinalizeStalled_KeepsFinalWithoutInterim(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(20 * time.Millisecond))
+	stream := scriptedStream([]step{
+		{event: interim("hello")},
+		// A new utterance whose final arrives with no interim before it.
+		{after: 60 * time.Millisecond, event: final("good morning")},
+		{event: final("how are you")},
+	}, false)
+
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 0)
+	assert.Equal(t, []TranscriptEvent{
+		interim("hello"),
+		{Text: "hello", IsFinal: true, Synthetic: true},
+		final("good morning"),
+		final("how are you"),
+	}, events)
+}
+
+func TestFinalizeStalled_DropsLateFinalByTimestamp(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(20 * time.Millisecond))
+	at := func(e TranscriptEvent) TranscriptEvent { e.Timestamp = 2 * time.Second; return e }
+	stream := scriptedStream([]step{
+		{event: at(interim("their"))},
+		{after: 60 * time.Millisecond, event: at(final("there we go"))},
+	}, false)
+
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 0)
+	assert.Equal(t, []TranscriptEvent{
+		at(interim("their")),
+		{Text: "their", IsFinal: true, Synthetic: true, Timestamp: 2 * time.Second},
+	}, events)
+}
+
+func TestFinalizeStalled_StreamEndsInterim(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(time.Minute))
+	stream := scriptedStream([]step{{event: interim("cut off")}}, false)
+
+	events := collectEvents(t, FinalizeStalled(context.Background(), cfg, stream), 0)
+	require.Len(t, events, 2)
+	assert.Equal(t, TranscriptEvent{Text: "cut off", IsFinal: true, Synthetic: true}, events[1])
+}
+
+func TestFinalizeStalled_PassesErrors(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(time.Minute))
+	errBoom := errors.New("boom")
+	stream := scriptedStream([]step{{err: errBoom}}, false)
+
+	var got error
+	for _, err := range FinalizeStalled(context.Background(), cfg, stream) {
+		if err != nil {
+			got = err
+			break
+		}
+	}
+	assert.ErrorIs(t, got, errBoom)
+}
+
+func TestFinalizeStalled_CancelsStreamOnBreak(t *testing.T) {
+	cfg := ApplyOptions(WithInterimTimeout(time.Minute))
+	stopped := make(chan struct{})
+	stream := func(ctx context.Context) iter.Seq2[TranscriptEvent, error] {
+		return func(yield func(TranscriptEvent, error) bool) {
+			defer close(stopped)
+			if !yield(interim("a"), nil) {
+				return
+			}
+			<-ctx.Done()
+		}
+	}
+
+	for range FinalizeStalled(context.Background(), cfg, stream) {
+		break
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("provider stream was not stopped")
+	}
+}
@@ -0,0 +1,161 @@
+package stt
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/voice"
+)
+
+const (
+	// defaultMaxSegmentDuration is the default segment length for
+	// TranscribeLong.
+	defaultMaxSegmentDuration = 30 * time.Second
+
+	// defaultLongSampleRate is the sample rate TranscribeLong assumes when
+	// none is configured.
+	defaultLongSampleRate = 16000
+
+	// vadFrameDuration is the length of the frames TranscribeLong classifies
+	// as speech or silence.
+	vadFrameDuration = 30 * time.Millisecond
+)
+
+// LongTranscript is the result of TranscribeLong.
+type LongTranscript struct {
+	// Text is the transcript of the whole recording, the segment texts
+	// joined with spaces.
+	Text string
+
+	// Segments holds a final event per transcribed segment. Timestamp is
+	// the offset of the segment in the recording.
+	Segments []TranscriptEvent
+}
+
+// TranscribeLong transcribes a recording of any length with engine by
+// splitting it into segments of at most MaxSegmentDuration (see
+// WithMaxSegmentDuration) and transcribing each with Transcribe. Segments
+// are cut in the longest silence, as detected by an energy VAD, in their
+// second half, or at the maximum length when there is none; segments
+// without speech are skipped.
+//
+// The audio is either a WAV file holding 16-bit mono PCM, whose header sets
+// the sample rate, or raw 16-bit mono PCM at the configured sample rate
+// (16 kHz by default). Each segment is sent to engine as a WAV file, the
+// format the batch providers upload.
+func TranscribeLong(ctx context.Context, engine STT, audio []byte, opts ...Option) (*LongTranscript, error) {
+	cfg := ApplyOptions(opts...)
+	sampleRate := cfg.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = defaultLongSampleRate
+	}
+	if isWAV(audio) {
+		var err error
+		audio, sampleRate, err = wavPCM(audio)
+		if err != nil {
+			return nil, err
+		}
+	}
+	maxDuration := cfg.MaxSegmentDuration
+	if maxDuration <= 0 {
+		maxDuration = defaultMaxSegmentDuration
+	}
+
+	bytesPerSecond := sampleRate * 2
+	frameBytes := max(2, int(vadFrameDuration.Seconds()*float64(sampleRate))*2)
+	maxBytes := max(frameBytes, int(maxDuration.Seconds()*float64(sampleRate))*2)
+
+	vad := voice.NewEnergyVAD(voice.EnergyVADConfig{})
+	speech, err := classifyFrames(ctx, vad, audio, frameBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LongTranscript{}
+	var texts []string
+	for start := 0; start < len(audio); {
+		end := len(audio)
+		if end-start > maxBytes {
+			end = cutPoint(speech, frameBytes, start, maxBytes)
+		}
+		segmentStart := start
+		segment := audio[start:end]
+		start = end
+
+		if !hasSpeech(speech, segmentStart/frameBytes, (segmentStart+len(segment)+frameBytes-1)/frameBytes) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		offset := time.Duration(segmentStart) * time.Second / time.Duration(bytesPerSecond)
+		text, err := engine.Transcribe(ctx, wavFile(segment, sampleRate), opts...)
+		if err != nil {
+			return nil, core.Errorf(core.ErrProviderDown, "stt: transcribe segment at %v: %w", offset, err)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		texts = append(texts, text)
+		result.Segments = append(result.Segments, TranscriptEvent{
+			Text:      text,
+			IsFinal:   true,
+			Timestamp: offset,
+			Language:  cfg.Language,
+		})
+	}
+	result.Text = strings.Join(texts, " ")
+	return result, nil
+}
+
+// classifyFrames reports for each frame of audio whether it holds speech.
+func classifyFrames(ctx context.Context, vad voice.ActivityDetector, audio []byte, frameBytes int) ([]bool, error) {
+	speech := make([]bool, 0, (len(audio)+frameBytes-1)/frameBytes)
+	for off := 0; off < len(audio); off += frameBytes {
+		res, err := vad.DetectActivity(ctx, audio[off:min(off+frameBytes, len(audio))])
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "stt: detect activity: %w", err)
+		}
+		speech = append(speech, res.IsSpeech)
+	}
+	return speech, nil
+}
+
+// cutPoint returns the byte offset at which to end a segment starting at
+// start and spanning at most maxBytes: the middle of the longest run of
+// silent frames in the second half of that span, preferring the latest on
+// a tie, or start+maxBytes when the span has no silence.
+func cutPoint(speech []bool, frameBytes, start, maxBytes int) int {
+	limit := start + maxBytes
+	bestLen, bestMid := 0, -1
+	run := 0
+	for i := (start + maxBytes/2 + frameBytes - 1) / frameBytes; (i+1)*frameBytes <= limit; i++ {
+		if speech[i] {
+			run = 0
+			continue
+		}
+		run++
+		if run >= bestLen {
+			bestLen = run
+			bestMid = i + 1 - (run+1)/2
+		}
+	}
+	if bestMid*frameBytes <= start {
+		return limit
+	}
+	return bestMid * frameBytes
+}
+
+// hasSpeech reports whether any frame in [from, to) holds speech.
+func hasSpeech(speech []bool, from, to int) bool {
+	for _, s := range speech[from:min(to, len(speech))] {
+		if s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package stt
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcm returns d of 16 kHz 16-bit PCM, a loud square wave for speech or
+// zeros for silence.
+func pcm(d time.Duration, speech bool) []byte {
+	n := int(d.Seconds() * 16000)
+	buf := make([]byte, n*2)
+	if speech {
+		for i := range n {
+			v := int16(5000)
+			if i%2 == 1 {
+				v = -5000
+			}
+			binary.LittleEndian.PutUint16(buf[i*2:], uint16(v))
+		}
+	}
+	return buf
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// segmentRecorder returns an engine that transcribes each segment as
+// "segN" and records segment lengths. Segments must be WAV files.
+func segmentRecorder(lengths *[]time.Duration) *mockSTT {
+	return &mockSTT{
+		transcribeFunc: func(ctx context.Context, audio []byte, opts ...Option) (string, error) {
+			samples, rate, err := wavPCM(audio)
+			if err != nil {
+				return "", err
+			}
+			*lengths = append(*lengths, time.Duration(len(samples)/2)*time.Second/time.Duration(rate))
+			return fmt.Sprintf(" seg%d ", len(*lengths)), nil
+		},
+	}
+}
+
+func TestTranscribeLong_CutsAtSilence(t *testing.T) {
+	audio := concat(
+		pcm(10*time.Second, true),
+		pcm(500*time.Millisecond, false),
+		pcm(10*time.Second, true),
+		pcm(time.Second, false),
+		pcm(4*time.Second, true),
+	)
+	var lengths []time.Duration
+	engine := segmentRecorder(&lengths)
+
+	got, err := TranscribeLong(context.Background(), engine, audio, WithMaxSegmentDuration(15*time.Second), WithLanguage("en"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "seg1 seg2 seg3", got.Text)
+	require.Len(t, got.Segments, 3)
+	const tolerance = float64(60 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), got.Segments[0].Timestamp)
+	assert.InDelta(t, float64(10250*time.Millisecond), float64(got.Segments[1].Timestamp), tolerance)
+	assert.InDelta(t, float64(21*time.Second), float64(got.Segments[2].Timestamp), tolerance)
+	for _, seg := range got.Segments {
+		assert.True(t, seg.IsFinal)
+		assert.Equal(t, "en", seg.Language)
+	}
+	for _, l := range lengths {
+		assert.LessOrEqual(t, l, 15*time.Second)
+	}
+}
+
+func TestTranscribeLong_HardCutWithoutSilence(t *testing.T) {
+	var lengths []time.Duration
+	engine := segmentRecorder(&lengths)
+
+	got, err := TranscribeLong(context.Background(), engine, pcm(25*time.Second, true), WithMaxSegmentDuration(10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second, 10 * time.Second, 5 * time.Second}, lengths)
+	require.Len(t, got.Segments, 3)
+	assert.Equal(t, 20*time.Second, got.Segments[2].Timestamp)
+}
+
+func TestTranscribeLong_WAVInput(t *testing.T) {
+	var lengths []time.Duration
+	engine := segmentRecorder(&lengths)
+
+	// The WAV header's sample rate overrides the configured one.
+	wav := wavFile(pcm(25*time.Second, true), 16000)
+	got, err := TranscribeLong(context.Background(), engine, wav, WithMaxSegmentDuration(10*time.Second), WithSampleRate(8000))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second, 10 * time.Second, 5 * time.Second}, lengths)
+	require.Len(t, got.Segments, 3)
+	assert.Equal(t, 20*time.Second, got.Segments[2].Timestamp)
+}
+
+func TestTranscribeLong_UnsupportedWAV(t *testing.T) {
+	wav := wavFile(pcm(time.Second, true), 16000)
+	binary.LittleEndian.PutUint16(wav[22:], 2) // stereo
+	_, err := TranscribeLong(context.Background(), &mockSTT{}, wav)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mono 16-bit PCM")
+}
+
+func TestTranscribeLong_ShortAudio(t *testing.T) {
+	var lengths []time.Duration
+	engine := segmentRecorder(&lengths)
+
+	got, err := TranscribeLong(context.Background(), engine, pcm(2*time.Second, true))
+	require.NoError(t, err)
+	assert.Equal(t, "seg1", got.Text)
+	assert.Equal(t, []time.Duration{2 * time.Second}, lengths)
+}
+
+func TestTranscribeLong_SkipsSilentSegments(t *testing.T) {
+	var lengths []time.Duration
+	engine := segmentRecorder(&lengths)
+
+	audio := concat(pcm(12*time.Second, false), pcm(3*time.Second, true))
+	got, err := TranscribeLong(context.Background(), engine, audio, WithMaxSegmentDuration(10*time.Second))
+	require.NoError(t, err)
+	require.Len(t, got.Segments, 1)
+	assert.Len(t, lengths, 1, "silent segments should not be transcribed")
+	assert.GreaterOrEqual(t, got.Segments[0].Timestamp, 5*time.Second)
+
+	got, err = TranscribeLong(context.Background(), engine, pcm(time.Second, false))
+	require.NoError(t, err)
+	assert.Empty(t, got.Text)
+	assert.Empty(t, got.Segments)
+}
+
+func TestTranscribeLong_Error(t *testing.T) {
+	calls := 0
+	engine := &mockSTT{
+		transcribeFunc: func(ctx context.Context, audio []byte, opts ...Option) (string, error) {
+			calls++
+			if calls == 2 {
+				return "", errors.New("request too large")
+			}
+			return "ok", nil
+		},
+	}
+
+	_, err := TranscribeLong(context.Background(), engine, pcm(25*time.Second, true), WithMaxSegmentDuration(10*time.Second))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "segment at 10s")
+	assert.Contains(t, err.Error(), "request too large")
+	assert.Equal(t, 2, calls)
+}
+
+func TestTranscribeLong_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := TranscribeLong(ctx, &mockSTT{}, pcm(time.Second, true))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -214,7 +214,11 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 		return "", err
 	}
 
-	return e.pollTranscript(ctx, txResult)
+	text, err := e.pollTranscript(ctx, txResult)
+	if err != nil {
+		return "", err
+	}
+	return stt.FilterText(cfg, text), nil
 }
 
 // realtimeMessage is a message from the AssemblyAI real-time WebSocket API.
@@ -224,12 +228,17 @@ type realtimeMessage struct {
 	AudioStart  int    `json:"audio_start"`
 	AudioEnd    int    `json:"audio_end"`
 	Confidence  float64 `json:"confidence"`
-	Words       []struct {
-		Text       string  `json:"text"`
-		Start      int     `json:"start"`
-		End        int     `json:"end"`
-		Confidence float64 `json:"confidence"`
-	} `json:"words"`
+	Words       []realtimeWord `json:"words"`
+}
+
+// realtimeWord is a word of a real-time transcript.
+type realtimeWord struct {
+	Text       string  `json:"text"`
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	Confidence float64 `json:"confidence"`
+	// Speaker is the speaker label, present when speaker labels are enabled.
+	Speaker string `json:"speaker,omitempty"`
 }
 
 // dialStream opens a WebSocket connection to AssemblyAI's real-time endpoint.
@@ -271,6 +280,7 @@ func (e *Engine) parseStreamMessage(data []byte, language string) (stt.Transcrip
 			Start:      time.Duration(w.Start) * time.Millisecond,
 			End:        time.Duration(w.End) * time.Millisecond,
 			Confidence: w.Confidence,
+			Speaker:    w.Speaker,
 		})
 	}
 
@@ -281,6 +291,7 @@ func (e *Engine) parseStreamMessage(data []byte, language string) (stt.Transcrip
 		Timestamp:  time.Duration(msg.AudioStart) * time.Millisecond,
 		Language:   language,
 		Words:      words,
+		Speaker:    stt.SpeakerOf(words),
 	}, true
 }
 
@@ -324,12 +335,18 @@ func (e *Engine) sendAudioStream(ctx context.Context, conn *websocket.Conn, audi
 // TranscribeStream converts streaming audio to transcript events
 // using AssemblyAI's real-time WebSocket API.
 func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
-	return func(yield func(stt.TranscriptEvent, error) bool) {
-		cfg := e.cfg
-		for _, opt := range opts {
-			opt(&cfg)
-		}
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return stt.FilterTranscripts(cfg, stt.FinalizeStalled(ctx, cfg, func(ctx context.Context) iter.Seq2[stt.TranscriptEvent, error] {
+		return e.transcribeStream(ctx, audioStream, cfg)
+	}))
+}
 
+// transcribeStream streams audio over AssemblyAI's real-time WebSocket API.
+func (e *Engine) transcribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], cfg stt.Config) iter.Seq2[stt.TranscriptEvent, error] {
+	return func(yield func(stt.TranscriptEvent, error) bool) {
 		conn, err := e.dialStream(ctx, cfg)
 		if err != nil {
 			yield(stt.TranscriptEvent{}, err)
@@ -335,14 +335,9 @@ func TestTranscribeStream_SuccessfulStreaming(t *testing.T) {
 			Confidence:  0.98,
 			AudioStart:  0,
 			AudioEnd:    1500,
-			Words: []struct {
-				Text       string  `json:"text"`
-				Start      int     `json:"start"`
-				End        int     `json:"end"`
-				Confidence float64 `json:"confidence"`
-			}{
-				{Text: "hello", Start: 0, End: 500, Confidence: 0.99},
-				{Text: "world", Start: 600, End: 1500, Confidence: 0.97},
+			Words: []realtimeWord{
+				{Text: "hello", Start: 0, End: 500, Confidence: 0.99, Speaker: "A"},
+				{Text: "world", Start: 600, End: 1500, Confidence: 0.97, Speaker: "A"},
 			},
 		})
 		conn.Close(websocket.StatusNormalClosure, "") //nolint:errcheck
@@ -373,6 +368,8 @@ func TestTranscribeStream_SuccessfulStreaming(t *testing.T) {
 	assert.Equal(t, 0.98, events[0].Confidence)
 	require.Len(t, events[0].Words, 2)
 	assert.Equal(t, "hello", events[0].Words[0].Text)
+	assert.Equal(t, "A", events[0].Words[0].Speaker)
+	assert.Equal(t, "A", events[0].Speaker)
 }
 
 func TestTranscribeStream_PartialTranscript(t *testing.T) {
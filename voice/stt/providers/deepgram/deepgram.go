@@ -8,6 +8,7 @@ import (
 	"iter"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -98,6 +99,8 @@ type deepgramWord struct {
 	Start      float64 `json:"start"`
 	End        float64 `json:"end"`
 	Confidence float64 `json:"confidence"`
+	// Speaker is the speaker index, present when diarize is enabled.
+	Speaker *int `json:"speaker,omitempty"`
 }
 
 // Transcribe converts a complete audio buffer to text using Deepgram's REST API.
@@ -163,7 +166,7 @@ func (e *Engine) transcribeRaw(ctx context.Context, audio []byte, cfg stt.Config
 	}
 
 	if len(result.Results.Channels) > 0 && len(result.Results.Channels[0].Alternatives) > 0 {
-		return result.Results.Channels[0].Alternatives[0].Transcript, nil
+		return stt.FilterText(cfg, result.Results.Channels[0].Alternatives[0].Transcript), nil
 	}
 	return "", nil
 }
@@ -224,6 +227,7 @@ func (e *Engine) parseStreamMessage(data []byte, language string) (stt.Transcrip
 			Start:      time.Duration(w.Start * float64(time.Second)),
 			End:        time.Duration(w.End * float64(time.Second)),
 			Confidence: w.Confidence,
+			Speaker:    speakerLabel(w.Speaker),
 		})
 	}
 
@@ -234,9 +238,18 @@ func (e *Engine) parseStreamMessage(data []byte, language string) (stt.Transcrip
 		Timestamp:  time.Duration(msg.Start * float64(time.Second)),
 		Language:   language,
 		Words:      words,
+		Speaker:    stt.SpeakerOf(words),
 	}, true
 }
 
+// speakerLabel formats a Deepgram speaker index as a speaker label.
+func speakerLabel(speaker *int) string {
+	if speaker == nil {
+		return ""
+	}
+	return strconv.Itoa(*speaker)
+}
+
 // readStreamMessages reads WebSocket messages and sends parsed events to the channel.
 func (e *Engine) readStreamMessages(ctx context.Context, conn *websocket.Conn, language string, events chan<- stt.TranscriptEvent, errs chan<- error) {
 	defer close(events)
@@ -275,12 +288,18 @@ func (e *Engine) sendAudioStream(ctx context.Context, conn *websocket.Conn, audi
 // TranscribeStream converts a streaming audio source to transcript events
 // using Deepgram's WebSocket API.
 func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
-	return func(yield func(stt.TranscriptEvent, error) bool) {
-		cfg := e.cfg
-		for _, opt := range opts {
-			opt(&cfg)
-		}
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return stt.FilterTranscripts(cfg, stt.FinalizeStalled(ctx, cfg, func(ctx context.Context) iter.Seq2[stt.TranscriptEvent, error] {
+		return e.transcribeStream(ctx, audioStream, cfg)
+	}))
+}
 
+// transcribeStream streams audio over Deepgram's WebSocket API.
+func (e *Engine) transcribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], cfg stt.Config) iter.Seq2[stt.TranscriptEvent, error] {
+	return func(yield func(stt.TranscriptEvent, error) bool) {
 		conn, err := e.dialStream(ctx, cfg)
 		if err != nil {
 			yield(stt.TranscriptEvent{}, err)
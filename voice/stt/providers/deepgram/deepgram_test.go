@@ -331,6 +331,7 @@ func TestTranscribeStream(t *testing.T) {
 			conn.Write(ctx, websocket.MessageText, data)
 
 			// Send a final result.
+			speaker1 := 1
 			final := deepgramStreamResponse{
 				Type:    "Results",
 				IsFinal: true,
@@ -345,7 +346,8 @@ func TestTranscribeStream(t *testing.T) {
 					Transcript: "hello world",
 					Confidence: 0.98,
 					Words: []deepgramWord{
-						{Word: "hello", Start: 0.1, End: 0.5, Confidence: 0.99},
+						{Word: "hello", Start: 0.1, End: 0.5, Confidence: 0.99, Speaker: &speaker1},
+						{Word: "world", Start: 0.6, End: 0.9, Confidence: 0.97, Speaker: &speaker1},
 					},
 				},
 			}
@@ -392,6 +394,9 @@ func TestTranscribeStream(t *testing.T) {
 			if ev.IsFinal {
 				hasFinal = true
 				assert.Equal(t, "hello world", ev.Text)
+				assert.Equal(t, "1", ev.Speaker)
+				require.Len(t, ev.Words, 2)
+				assert.Equal(t, "1", ev.Words[0].Speaker)
 			}
 		}
 		assert.True(t, hasFinal, "expected a final transcript event")
@@ -227,7 +227,11 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 		resultURL = e.baseURL + "/transcription/" + txResp.ID
 	}
 
-	return e.pollTranscription(ctx, resultURL)
+	text, err := e.pollTranscription(ctx, resultURL)
+	if err != nil {
+		return "", err
+	}
+	return stt.FilterText(cfg, text), nil
 }
 
 // gladiaStreamMsg is a message from the Gladia real-time WebSocket.
@@ -340,12 +344,18 @@ func (e *Engine) sendAudioStream(ctx context.Context, conn *websocket.Conn, audi
 // TranscribeStream converts streaming audio to transcript events using Gladia's
 // WebSocket API.
 func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
-	return func(yield func(stt.TranscriptEvent, error) bool) {
-		cfg := e.cfg
-		for _, opt := range opts {
-			opt(&cfg)
-		}
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return stt.FilterTranscripts(cfg, stt.FinalizeStalled(ctx, cfg, func(ctx context.Context) iter.Seq2[stt.TranscriptEvent, error] {
+		return e.transcribeStream(ctx, audioStream, cfg)
+	}))
+}
 
+// transcribeStream streams audio over Gladia's WebSocket API.
+func (e *Engine) transcribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], cfg stt.Config) iter.Seq2[stt.TranscriptEvent, error] {
+	return func(yield func(stt.TranscriptEvent, error) bool) {
 		conn, err := e.dialStream(ctx, cfg)
 		if err != nil {
 			yield(stt.TranscriptEvent{}, err)
@@ -67,7 +67,15 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	text, err := e.transcribe(ctx, audio, cfg)
+	if err != nil {
+		return "", err
+	}
+	return stt.FilterText(cfg, text), nil
+}
 
+// transcribe sends audio to the Groq Whisper API and returns the unfiltered text.
+func (e *Engine) transcribe(ctx context.Context, audio []byte, cfg stt.Config) (string, error) {
 	var buf bytes.Buffer
 	w := multipart.NewWriter(&buf)
 
@@ -126,7 +134,11 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 // Groq Whisper does not support native streaming, so this buffers
 // audio chunks and transcribes them as they arrive.
 func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
-	return func(yield func(stt.TranscriptEvent, error) bool) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return stt.FilterTranscripts(cfg, func(yield func(stt.TranscriptEvent, error) bool) {
 		var allAudio []byte
 		for chunk, err := range audioStream {
 			if err != nil {
@@ -144,7 +156,7 @@ func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]b
 			return
 		}
 
-		text, err := e.Transcribe(ctx, allAudio, opts...)
+		text, err := e.transcribe(ctx, allAudio, cfg)
 		if err != nil {
 			yield(stt.TranscriptEvent{}, err)
 			return
@@ -156,5 +168,5 @@ func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]b
 				IsFinal: true,
 			}, nil)
 		}
-	}
+	})
 }
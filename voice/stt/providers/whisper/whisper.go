@@ -68,7 +68,15 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	text, err := e.transcribe(ctx, audio, cfg)
+	if err != nil {
+		return "", err
+	}
+	return stt.FilterText(cfg, text), nil
+}
 
+// transcribe sends audio to the OpenAI Whisper API and returns the unfiltered text.
+func (e *Engine) transcribe(ctx context.Context, audio []byte, cfg stt.Config) (string, error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
@@ -121,25 +129,29 @@ func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Optio
 }
 
 // transcribeChunk transcribes a single audio chunk, checking for context cancellation.
-func (e *Engine) transcribeChunk(ctx context.Context, chunk []byte, opts ...stt.Option) (string, error) {
+func (e *Engine) transcribeChunk(ctx context.Context, chunk []byte, cfg stt.Config) (string, error) {
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
-	return e.Transcribe(ctx, chunk, opts...)
+	return e.transcribe(ctx, chunk, cfg)
 }
 
 // TranscribeStream implements streaming transcription by collecting audio chunks
 // and performing batch transcription. Whisper does not support native streaming,
 // so each audio chunk in the stream is transcribed independently.
 func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
-	return func(yield func(stt.TranscriptEvent, error) bool) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return stt.FilterTranscripts(cfg, func(yield func(stt.TranscriptEvent, error) bool) {
 		for chunk, err := range audioStream {
 			if err != nil {
 				yield(stt.TranscriptEvent{}, err)
 				return
 			}
 
-			text, transcribeErr := e.transcribeChunk(ctx, chunk, opts...)
+			text, transcribeErr := e.transcribeChunk(ctx, chunk, cfg)
 			if transcribeErr != nil {
 				yield(stt.TranscriptEvent{}, transcribeErr)
 				return
@@ -154,5 +166,5 @@ func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]b
 				}
 			}
 		}
-	}
+	})
 }
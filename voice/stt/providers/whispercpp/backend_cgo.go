@@ -0,0 +1,95 @@
+//go:build whispercpp && cgo
+
+package whispercpp
+
+/*
+#cgo LDFLAGS: -lwhisper
+#include <stdlib.h>
+#include <whisper.h>
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// cgoBackend runs inference in-process through libwhisper. The model is
+// loaded once; a whisper context is not safe for concurrent use, so
+// transcriptions are serialized.
+type cgoBackend struct {
+	mu      sync.Mutex
+	ctx     *C.struct_whisper_context
+	threads int
+}
+
+// newBackend loads the model at modelPath with libwhisper.
+func newBackend(modelPath string, threads int, _ map[string]any) (backend, error) {
+	cPath := C.CString(modelPath)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ctx := C.whisper_init_from_file_with_params(cPath, C.whisper_context_default_params())
+	if ctx == nil {
+		return nil, fmt.Errorf("whispercpp: load model %q", modelPath)
+	}
+	return &cgoBackend{ctx: ctx, threads: threads}, nil
+}
+
+// transcribe runs whisper_full on pcm. A running inference cannot be
+// interrupted, so ctx is only checked before it starts.
+func (b *cgoBackend) transcribe(ctx context.Context, pcm []byte, language string) (string, error) {
+	samples := make([]float32, len(pcm)/2)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(pcm[2*i:]))) / 32768
+	}
+	if len(samples) == 0 {
+		return "", nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if b.ctx == nil {
+		return "", fmt.Errorf("whispercpp: engine is closed")
+	}
+
+	cLang := C.CString(language)
+	defer C.free(unsafe.Pointer(cLang))
+
+	params := C.whisper_full_default_params(C.WHISPER_SAMPLING_GREEDY)
+	params.n_threads = C.int(b.threads)
+	params.language = cLang
+	params.no_timestamps = C.bool(true)
+	params.print_progress = C.bool(false)
+	params.print_realtime = C.bool(false)
+	params.print_timestamps = C.bool(false)
+
+	if rc := C.whisper_full(b.ctx, params, (*C.float)(unsafe.Pointer(&samples[0])), C.int(len(samples))); rc != 0 {
+		return "", fmt.Errorf("whispercpp: inference failed with code %d", int(rc))
+	}
+
+	n := int(C.whisper_full_n_segments(b.ctx))
+	parts := make([]string, 0, n)
+	for i := range n {
+		if text := strings.TrimSpace(C.GoString(C.whisper_full_get_segment_text(b.ctx, C.int(i)))); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (b *cgoBackend) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ctx != nil {
+		C.whisper_free(b.ctx)
+		b.ctx = nil
+	}
+	return nil
+}
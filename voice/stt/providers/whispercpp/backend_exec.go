@@ -0,0 +1,9 @@
+//go:build !whispercpp || !cgo
+
+package whispercpp
+
+// newBackend returns the whisper.cpp command-line backend. Build with the
+// whispercpp tag and cgo enabled to link libwhisper instead.
+func newBackend(modelPath string, threads int, extra map[string]any) (backend, error) {
+	return newExecBackend(modelPath, threads, extra)
+}
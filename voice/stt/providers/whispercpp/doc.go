@@ -0,0 +1,59 @@
+// Package whispercpp provides a local, offline STT provider for the Beluga AI
+// voice pipeline built on whisper.cpp. Audio is transcribed on the host and
+// never sent over the network, which suits on-premises deployments.
+//
+// # Registration
+//
+// This package registers itself as "whispercpp" with the stt registry. Import
+// it with a blank identifier to enable:
+//
+//	import _ "github.com/lookatitude/beluga-ai/v2/voice/stt/providers/whispercpp"
+//
+// # Usage
+//
+//	engine, err := stt.New("whispercpp", stt.Config{
+//	    Language: "en",
+//	    Extra: map[string]any{
+//	        "model_path": "/models/ggml-base.en.bin",
+//	        "threads":    8,
+//	    },
+//	})
+//	text, err := engine.Transcribe(ctx, wavBytes)
+//
+// Audio must be 16 kHz mono 16-bit PCM, raw or in a WAV container. whisper.cpp
+// has no incremental decoding, so TranscribeStream buffers raw PCM chunks into
+// windows of chunk_seconds and emits a final event per window.
+//
+// # Build Tags
+//
+// By default the provider runs the whisper.cpp command-line tool
+// (whisper-cli) for each transcription, which needs no cgo but reloads the
+// model on every call. To link libwhisper and keep the model loaded, build
+// with the whispercpp tag and cgo enabled, pointing the compiler at the
+// whisper.cpp headers and libraries:
+//
+//	CGO_ENABLED=1 \
+//	CGO_CFLAGS="-I/opt/whisper.cpp/include -I/opt/whisper.cpp/ggml/include" \
+//	CGO_LDFLAGS="-L/opt/whisper.cpp/build/src" \
+//	go build -tags whispercpp ./...
+//
+// Call Close to free the loaded model when the engine is no longer needed.
+//
+// # Configuration
+//
+// Configuration in Config.Extra:
+//
+//   - model_path — Path to a ggml whisper model file (required)
+//   - threads — Number of inference threads (default: 4)
+//   - chunk_seconds — Streaming window length in seconds (default: 5)
+//   - binary — whisper.cpp command-line tool, without the whispercpp tag
+//     (default: whisper-cli on the PATH)
+//
+// Language is passed to whisper.cpp by its primary subtag ("en-US" becomes
+// "en"); when empty the language is detected automatically.
+//
+// # Exported Types
+//
+//   - [Engine] — implements stt.STT using whisper.cpp
+//   - [New] — constructor accepting stt.Config
+package whispercpp
@@ -0,0 +1,89 @@
+package whispercpp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const defaultBinary = "whisper-cli"
+
+// execBackend runs the whisper.cpp command-line tool once per transcription.
+// It needs no cgo but reloads the model on every call.
+type execBackend struct {
+	binary    string
+	modelPath string
+	threads   int
+}
+
+// newExecBackend resolves the whisper.cpp binary, extra["binary"] or
+// whisper-cli on the PATH, and checks that the model file exists.
+func newExecBackend(modelPath string, threads int, extra map[string]any) (*execBackend, error) {
+	binary, _ := extra["binary"].(string)
+	if binary == "" {
+		binary = defaultBinary
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("whispercpp: find binary %q: %w", binary, err)
+	}
+	if _, err := os.Stat(modelPath); err != nil {
+		return nil, fmt.Errorf("whispercpp: model: %w", err)
+	}
+	return &execBackend{binary: path, modelPath: modelPath, threads: threads}, nil
+}
+
+func (b *execBackend) transcribe(ctx context.Context, pcm []byte, language string) (string, error) {
+	dir, err := os.MkdirTemp("", "beluga-whispercpp-*")
+	if err != nil {
+		return "", fmt.Errorf("whispercpp: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	audioPath := filepath.Join(dir, "audio.wav")
+	if err := os.WriteFile(audioPath, wavFile(pcm), 0600); err != nil {
+		return "", fmt.Errorf("whispercpp: write audio: %w", err)
+	}
+
+	args := []string{
+		"-m", b.modelPath,
+		"-t", strconv.Itoa(b.threads),
+		"-l", language,
+		"-nt", // no timestamps
+		"-np", // no progress or system output
+		"-f", audioPath,
+	}
+	// The binary comes from the engine configuration, never from audio or
+	// request data, and the audio path is generated in a fresh temp dir.
+	cmd := exec.CommandContext(ctx, b.binary, args...) //#nosec G204 -- binary is operator-configured, arguments are not shell-interpreted
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("whispercpp: %s: %w: %s", filepath.Base(b.binary), err, strings.TrimSpace(stderr.String()))
+	}
+	return joinLines(stdout.String()), nil
+}
+
+func (b *execBackend) close() error { return nil }
+
+// joinLines joins the non-empty lines of whisper.cpp output into one
+// transcript.
+func joinLines(out string) string {
+	var parts []string
+	for line := range strings.Lines(out) {
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,106 @@
+//go:build !whispercpp || !cgo
+
+package whispercpp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/stt"
+)
+
+// fakeCLI writes a shell script standing in for whisper-cli. It records its
+// arguments and a copy of the audio file in dir and runs body.
+func fakeCLI(t *testing.T, body string) (binary, dir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake whisper-cli is a shell script")
+	}
+	dir = t.TempDir()
+	binary = filepath.Join(dir, "whisper-cli")
+	script := `#!/bin/sh
+echo "$@" > "` + dir + `/args"
+while [ $# -gt 0 ]; do
+	if [ "$1" = "-f" ]; then cp "$2" "` + dir + `/audio.wav"; fi
+	shift
+done
+` + body + "\n"
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0700))
+	return binary, dir
+}
+
+func modelFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ggml-tiny.bin")
+	require.NoError(t, os.WriteFile(path, []byte("model"), 0600))
+	return path
+}
+
+func TestNew_ExecBackend(t *testing.T) {
+	binary, dir := fakeCLI(t, `printf ' hello\n\n world \n'`)
+	model := modelFile(t)
+
+	engine, err := stt.New("whispercpp", stt.Config{
+		Language: "en-US",
+		Extra:    map[string]any{"model_path": model, "threads": 2.0, "binary": binary},
+	})
+	require.NoError(t, err)
+
+	pcm := []byte{0x01, 0x00, 0x02, 0x00}
+	text, err := engine.Transcribe(context.Background(), pcm)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", text)
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "-m "+model+" -t 2 -l en -nt -np -f ")
+
+	audio, err := os.ReadFile(filepath.Join(dir, "audio.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, wavFile(pcm), audio)
+
+	require.NoError(t, engine.(*Engine).Close())
+}
+
+func TestNew_ExecBackendErrors(t *testing.T) {
+	_, err := New(stt.Config{Extra: map[string]any{
+		"model_path": modelFile(t),
+		"binary":     filepath.Join(t.TempDir(), "missing-whisper-cli"),
+	}})
+	assert.ErrorContains(t, err, "find binary")
+
+	binary, _ := fakeCLI(t, "")
+	_, err = New(stt.Config{Extra: map[string]any{
+		"model_path": filepath.Join(t.TempDir(), "missing.bin"),
+		"binary":     binary,
+	}})
+	assert.ErrorContains(t, err, "model")
+}
+
+func TestExecBackend_Failure(t *testing.T) {
+	binary, _ := fakeCLI(t, `echo "failed to load model" >&2; exit 3`)
+	b, err := newExecBackend(modelFile(t), 1, map[string]any{"binary": binary})
+	require.NoError(t, err)
+
+	_, err = b.transcribe(context.Background(), []byte{0, 0}, "en")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "failed to load model"), err.Error())
+}
+
+func TestExecBackend_ContextCancelled(t *testing.T) {
+	binary, _ := fakeCLI(t, "sleep 5")
+	b, err := newExecBackend(modelFile(t), 1, map[string]any{"binary": binary})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = b.transcribe(ctx, []byte{0, 0}, "en")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,77 @@
+package whispercpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// pcmData returns the 16-bit PCM samples of audio, which is either raw PCM
+// or a WAV file holding 16 kHz mono 16-bit PCM.
+func pcmData(audio []byte) ([]byte, error) {
+	if len(audio) < 12 || !bytes.Equal(audio[0:4], []byte("RIFF")) || !bytes.Equal(audio[8:12], []byte("WAVE")) {
+		if len(audio)%2 != 0 {
+			return nil, fmt.Errorf("whispercpp: raw PCM has an odd number of bytes")
+		}
+		return audio, nil
+	}
+
+	var sawFormat bool
+	for rest := audio[12:]; len(rest) >= 8; {
+		id := string(rest[0:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		body := rest[8:]
+		if size > len(body) {
+			size = len(body)
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("whispercpp: malformed WAV fmt chunk")
+			}
+			format := binary.LittleEndian.Uint16(body[0:2])
+			channels := binary.LittleEndian.Uint16(body[2:4])
+			rate := binary.LittleEndian.Uint32(body[4:8])
+			bits := binary.LittleEndian.Uint16(body[14:16])
+			if format != 1 || channels != 1 || rate != defaultSampleRate || bits != 16 {
+				return nil, fmt.Errorf("whispercpp: WAV must be 16 kHz mono 16-bit PCM, got format %d, %d channels, %d Hz, %d bits",
+					format, channels, rate, bits)
+			}
+			sawFormat = true
+		case "data":
+			if !sawFormat {
+				return nil, fmt.Errorf("whispercpp: WAV data chunk precedes fmt chunk")
+			}
+			return body[:size&^1], nil
+		}
+		// Chunks are padded to an even size.
+		skip := 8 + size + size&1
+		if skip > len(rest) {
+			break
+		}
+		rest = rest[skip:]
+	}
+	return nil, fmt.Errorf("whispercpp: WAV has no data chunk")
+}
+
+// wavFile wraps 16 kHz mono 16-bit PCM in a WAV container.
+func wavFile(pcm []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(44 + len(pcm))
+	le := binary.LittleEndian
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, le, uint32(36+len(pcm))) // #nosec G115 -- audio buffers are far below 4 GiB
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, le, uint32(16))
+	_ = binary.Write(&buf, le, uint16(1)) // PCM
+	_ = binary.Write(&buf, le, uint16(1)) // mono
+	_ = binary.Write(&buf, le, uint32(defaultSampleRate))
+	_ = binary.Write(&buf, le, uint32(defaultSampleRate*2)) // byte rate
+	_ = binary.Write(&buf, le, uint16(2))                   // block align
+	_ = binary.Write(&buf, le, uint16(16))                  // bits per sample
+	buf.WriteString("data")
+	_ = binary.Write(&buf, le, uint32(len(pcm))) // #nosec G115 -- audio buffers are far below 4 GiB
+	buf.Write(pcm)
+	return buf.Bytes()
+}
@@ -0,0 +1,195 @@
+package whispercpp
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/stt"
+)
+
+const (
+	defaultSampleRate   = 16000
+	defaultThreads      = 4
+	defaultChunkSeconds = 5.0
+)
+
+var _ stt.STT = (*Engine)(nil) // compile-time interface check
+
+func init() {
+	stt.Register("whispercpp", func(cfg stt.Config) (stt.STT, error) {
+		return New(cfg)
+	})
+}
+
+// backend runs whisper.cpp inference on 16-bit mono PCM audio.
+type backend interface {
+	transcribe(ctx context.Context, pcm []byte, language string) (string, error)
+	close() error
+}
+
+// Engine implements stt.STT by running whisper.cpp locally. Audio never
+// leaves the host.
+type Engine struct {
+	backend    backend
+	chunkBytes int
+	cfg        stt.Config
+}
+
+// New creates a new whisper.cpp STT engine. Config.Extra must contain
+// model_path, the path to a ggml model file.
+func New(cfg stt.Config) (*Engine, error) {
+	modelPath, _ := cfg.Extra["model_path"].(string)
+	if modelPath == "" {
+		return nil, fmt.Errorf("whispercpp: model_path is required in Extra")
+	}
+
+	threads := intExtra(cfg.Extra, "threads", defaultThreads)
+	if threads <= 0 {
+		return nil, fmt.Errorf("whispercpp: threads must be positive, got %d", threads)
+	}
+
+	if cfg.SampleRate == 0 {
+		cfg.SampleRate = defaultSampleRate
+	}
+	if cfg.SampleRate != defaultSampleRate {
+		return nil, fmt.Errorf("whispercpp: sample rate must be %d Hz, got %d", defaultSampleRate, cfg.SampleRate)
+	}
+
+	chunkSeconds := floatExtra(cfg.Extra, "chunk_seconds", defaultChunkSeconds)
+	if chunkSeconds <= 0 {
+		return nil, fmt.Errorf("whispercpp: chunk_seconds must be positive, got %v", chunkSeconds)
+	}
+
+	b, err := newBackend(modelPath, threads, cfg.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{
+		backend:    b,
+		chunkBytes: int(chunkSeconds*float64(cfg.SampleRate)) * 2,
+		cfg:        cfg,
+	}, nil
+}
+
+// Close releases the resources held by the engine, such as a loaded model.
+func (e *Engine) Close() error {
+	return e.backend.close()
+}
+
+// Transcribe converts a complete audio buffer to text. The audio is 16 kHz
+// mono 16-bit PCM, either raw or in a WAV container.
+func (e *Engine) Transcribe(ctx context.Context, audio []byte, opts ...stt.Option) (string, error) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	pcm, err := pcmData(audio)
+	if err != nil {
+		return "", err
+	}
+	if len(pcm) == 0 {
+		return "", nil
+	}
+	text, err := e.backend.transcribe(ctx, pcm, whisperLanguage(cfg.Language))
+	if err != nil {
+		return "", err
+	}
+	return stt.FilterText(cfg, text), nil
+}
+
+// TranscribeStream implements streaming transcription by buffering raw PCM
+// chunks into windows of chunk_seconds and transcribing each window as it
+// fills. whisper.cpp has no incremental decoding, so every event is final.
+func (e *Engine) TranscribeStream(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...stt.Option) iter.Seq2[stt.TranscriptEvent, error] {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	language := whisperLanguage(cfg.Language)
+	bytesPerSecond := cfg.SampleRate * 2
+
+	return stt.FilterTranscripts(cfg, func(yield func(stt.TranscriptEvent, error) bool) {
+		var window []byte
+		var offset int
+
+		// emit transcribes a window of audio and reports whether to go on.
+		emit := func(pcm []byte) bool {
+			start := offset
+			offset += len(pcm)
+
+			text, err := e.backend.transcribe(ctx, pcm, language)
+			if err != nil {
+				yield(stt.TranscriptEvent{}, err)
+				return false
+			}
+			if text == "" {
+				return true
+			}
+			return yield(stt.TranscriptEvent{
+				Text:      text,
+				IsFinal:   true,
+				Timestamp: time.Duration(start) * time.Second / time.Duration(bytesPerSecond),
+				Language:  cfg.Language,
+			}, nil)
+		}
+
+		for chunk, err := range audioStream {
+			if err != nil {
+				yield(stt.TranscriptEvent{}, err)
+				return
+			}
+			if ctx.Err() != nil {
+				yield(stt.TranscriptEvent{}, ctx.Err())
+				return
+			}
+			window = append(window, chunk...)
+			for len(window) >= e.chunkBytes {
+				pcm := window[:e.chunkBytes]
+				window = window[e.chunkBytes:]
+				if !emit(pcm) {
+					return
+				}
+			}
+		}
+		if len(window) > 0 {
+			emit(window)
+		}
+	})
+}
+
+// whisperLanguage converts a BCP-47 code to the language code whisper.cpp
+// expects, using automatic detection when none is set.
+func whisperLanguage(lang string) string {
+	if lang == "" {
+		return "auto"
+	}
+	lang, _, _ = strings.Cut(lang, "-")
+	return strings.ToLower(lang)
+}
+
+// intExtra returns the integer value of extra[key], or def when it is unset.
+func intExtra(extra map[string]any, key string, def int) int {
+	switch v := extra[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return def
+}
+
+// floatExtra returns the numeric value of extra[key], or def when it is unset.
+func floatExtra(extra map[string]any, key string, def float64) float64 {
+	switch v := extra[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return def
+}
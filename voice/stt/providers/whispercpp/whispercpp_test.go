@@ -0,0 +1,229 @@
+package whispercpp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/stt"
+)
+
+// fakeBackend records the audio it is given and returns canned transcripts.
+type fakeBackend struct {
+	calls     [][]byte
+	languages []string
+	texts     []string
+	err       error
+	closed    bool
+}
+
+func (f *fakeBackend) transcribe(_ context.Context, pcm []byte, language string) (string, error) {
+	f.calls = append(f.calls, append([]byte(nil), pcm...))
+	f.languages = append(f.languages, language)
+	if f.err != nil {
+		return "", f.err
+	}
+	if len(f.texts) == 0 {
+		return "", nil
+	}
+	text := f.texts[0]
+	f.texts = f.texts[1:]
+	return text, nil
+}
+
+func (f *fakeBackend) close() error {
+	f.closed = true
+	return nil
+}
+
+// newTestEngine returns an engine over b with a one-second streaming window.
+func newTestEngine(b backend, cfg stt.Config) *Engine {
+	cfg.SampleRate = defaultSampleRate
+	return &Engine{backend: b, chunkBytes: defaultSampleRate * 2, cfg: cfg}
+}
+
+func chunks(data ...[]byte) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		for _, d := range data {
+			if !yield(d, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  stt.Config
+		want string
+	}{
+		{name: "missing model path", cfg: stt.Config{}, want: "model_path is required"},
+		{name: "bad threads", cfg: stt.Config{Extra: map[string]any{"model_path": "m.bin", "threads": 0}}, want: "threads must be positive"},
+		{name: "bad sample rate", cfg: stt.Config{SampleRate: 8000, Extra: map[string]any{"model_path": "m.bin"}}, want: "sample rate must be 16000"},
+		{name: "bad chunk", cfg: stt.Config{Extra: map[string]any{"model_path": "m.bin", "chunk_seconds": -1.0}}, want: "chunk_seconds must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestTranscribe(t *testing.T) {
+	fb := &fakeBackend{texts: []string{"hello world"}}
+	e := newTestEngine(fb, stt.Config{Language: "en-US"})
+
+	pcm := []byte{0x01, 0x00, 0x02, 0x00}
+	text, err := e.Transcribe(context.Background(), wavFile(pcm))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", text)
+	require.Len(t, fb.calls, 1)
+	assert.Equal(t, pcm, fb.calls[0], "WAV header should be stripped")
+	assert.Equal(t, "en", fb.languages[0])
+
+	_, err = e.Transcribe(context.Background(), pcm, stt.WithLanguage(""))
+	require.NoError(t, err)
+	assert.Equal(t, "auto", fb.languages[1])
+
+	text, err = e.Transcribe(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+	assert.Len(t, fb.calls, 2, "empty audio should not be transcribed")
+
+	require.NoError(t, e.Close())
+	assert.True(t, fb.closed)
+}
+
+func TestTranscribe_BadAudio(t *testing.T) {
+	e := newTestEngine(&fakeBackend{}, stt.Config{})
+
+	_, err := e.Transcribe(context.Background(), []byte{0x01, 0x02, 0x03})
+	assert.ErrorContains(t, err, "odd number of bytes")
+
+	wav := wavFile([]byte{0, 0})
+	binary.LittleEndian.PutUint32(wav[24:28], 44100)
+	_, err = e.Transcribe(context.Background(), wav)
+	assert.ErrorContains(t, err, "16 kHz mono 16-bit PCM")
+}
+
+func TestTranscribeStream_Windows(t *testing.T) {
+	fb := &fakeBackend{texts: []string{"first", "", "third"}}
+	e := newTestEngine(fb, stt.Config{Language: "en"})
+
+	second := make([]byte, defaultSampleRate*2)
+	half := second[:defaultSampleRate]
+
+	var events []stt.TranscriptEvent
+	for ev, err := range e.TranscribeStream(context.Background(), chunks(half, half, half, half, half)) {
+		require.NoError(t, err)
+		events = append(events, ev)
+	}
+
+	// 2.5 seconds of audio make two full windows and a final partial one.
+	require.Len(t, fb.calls, 3)
+	assert.Len(t, fb.calls[0], len(second))
+	assert.Len(t, fb.calls[1], len(second))
+	assert.Len(t, fb.calls[2], len(half))
+
+	require.Len(t, events, 2, "empty windows should not produce events")
+	assert.Equal(t, "first", events[0].Text)
+	assert.True(t, events[0].IsFinal)
+	assert.Equal(t, time.Duration(0), events[0].Timestamp)
+	assert.Equal(t, "en", events[0].Language)
+	assert.Equal(t, "third", events[1].Text)
+	assert.Equal(t, 2*time.Second, events[1].Timestamp)
+}
+
+func TestTranscribeStream_Errors(t *testing.T) {
+	t.Run("audio error", func(t *testing.T) {
+		e := newTestEngine(&fakeBackend{}, stt.Config{})
+		audio := func(yield func([]byte, error) bool) {
+			yield(nil, errors.New("mic unplugged"))
+		}
+		var gotErr error
+		for _, err := range e.TranscribeStream(context.Background(), audio) {
+			gotErr = err
+		}
+		assert.ErrorContains(t, gotErr, "mic unplugged")
+	})
+
+	t.Run("backend error", func(t *testing.T) {
+		e := newTestEngine(&fakeBackend{err: errors.New("inference failed")}, stt.Config{})
+		var gotErr error
+		for _, err := range e.TranscribeStream(context.Background(), chunks([]byte{0, 0})) {
+			gotErr = err
+		}
+		assert.ErrorContains(t, gotErr, "inference failed")
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		fb := &fakeBackend{}
+		e := newTestEngine(fb, stt.Config{})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var gotErr error
+		for _, err := range e.TranscribeStream(ctx, chunks([]byte{0, 0})) {
+			gotErr = err
+		}
+		assert.ErrorIs(t, gotErr, context.Canceled)
+		assert.Empty(t, fb.calls)
+	})
+}
+
+func TestTranscribeStream_StopEarly(t *testing.T) {
+	fb := &fakeBackend{texts: []string{"one", "two"}}
+	e := newTestEngine(fb, stt.Config{})
+	second := make([]byte, defaultSampleRate*2)
+
+	for range e.TranscribeStream(context.Background(), chunks(second, second)) {
+		break
+	}
+	assert.Len(t, fb.calls, 1)
+}
+
+func TestPCMData(t *testing.T) {
+	t.Run("skips unknown chunks", func(t *testing.T) {
+		wav := wavFile([]byte{1, 0, 2, 0})
+		// Insert a LIST chunk with an odd size, padded to even, before data.
+		list := []byte{'L', 'I', 'S', 'T', 3, 0, 0, 0, 'a', 'b', 'c', 0}
+		wav = append(wav[:36:36], append(list, wav[36:]...)...)
+		pcm, err := pcmData(wav)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1, 0, 2, 0}, pcm)
+	})
+
+	t.Run("no data chunk", func(t *testing.T) {
+		_, err := pcmData(wavFile(nil)[:36])
+		assert.ErrorContains(t, err, "no data chunk")
+	})
+}
+
+func TestWhisperLanguage(t *testing.T) {
+	assert.Equal(t, "auto", whisperLanguage(""))
+	assert.Equal(t, "en", whisperLanguage("en"))
+	assert.Equal(t, "pt", whisperLanguage("PT-br"))
+}
+
+func TestTranscribe_Filters(t *testing.T) {
+	fb := &fakeBackend{texts: []string{"what the heck", "oh heck"}}
+	e := newTestEngine(fb, stt.Config{ProfanityTerms: []string{"heck"}})
+
+	text, err := e.Transcribe(context.Background(), []byte{0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "what the ****", text)
+
+	for ev, err := range e.TranscribeStream(context.Background(), chunks([]byte{0, 0})) {
+		require.NoError(t, err)
+		assert.Equal(t, "oh ****", ev.Text)
+		assert.Equal(t, "oh heck", ev.RawText)
+	}
+}
@@ -17,6 +17,11 @@ type TranscriptEvent struct {
 	// Text is the transcribed text.
 	Text string
 
+	// RawText is the transcribed text before word filtering (see
+	// WithMinWordConfidence and WithProfanityFilter), kept for audit. It is
+	// empty when no filter is enabled.
+	RawText string
+
 	// IsFinal indicates whether this is a final (non-revisable) transcript.
 	IsFinal bool
 
@@ -31,6 +36,16 @@ type TranscriptEvent struct {
 
 	// Words holds word-level timing information when available.
 	Words []Word
+
+	// Speaker identifies the speaker of the transcript when diarization is
+	// enabled, using the provider's labels (e.g., "0", "A"). When the words
+	// are attributed to several speakers it is the one with the most words.
+	Speaker string
+
+	// Synthetic is set on a final event that was not produced by the
+	// provider but forced from the latest interim result because no final
+	// arrived in time (see WithInterimTimeout and WithFinalizationTimeout).
+	Synthetic bool
 }
 
 // Word represents a single word with timing information.
@@ -46,6 +61,33 @@ type Word struct {
 
 	// Confidence is the word-level confidence score.
 	Confidence float64
+
+	// Speaker identifies the speaker of the word when diarization is
+	// enabled.
+	Speaker string
+}
+
+// SpeakerOf returns the speaker attributed to the most words, preferring the
+// one that speaks first on a tie. It returns "" when no word has a speaker.
+func SpeakerOf(words []Word) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, w := range words {
+		if w.Speaker == "" {
+			continue
+		}
+		if counts[w.Speaker] == 0 {
+			order = append(order, w.Speaker)
+		}
+		counts[w.Speaker]++
+	}
+	best := ""
+	for _, speaker := range order {
+		if counts[speaker] > counts[best] {
+			best = speaker
+		}
+	}
+	return best
 }
 
 // STT is the speech-to-text interface. Implementations convert audio data
@@ -79,6 +121,29 @@ type Config struct {
 	// Encoding is the audio encoding format (e.g., "linear16", "opus").
 	Encoding string
 
+	// InterimTimeout is how long a streaming transcription may go without
+	// any event after an interim result before that result is emitted as a
+	// synthetic final. Zero disables it.
+	InterimTimeout time.Duration
+
+	// FinalizationTimeout is how long after its first interim result an
+	// utterance may go without a final before the latest interim result is
+	// emitted as a synthetic final. Zero disables it.
+	FinalizationTimeout time.Duration
+
+	// MaxSegmentDuration is the longest segment TranscribeLong sends to
+	// Transcribe; set it to the provider's per-request limit. Zero means
+	// 30 seconds.
+	MaxSegmentDuration time.Duration
+
+	// MinWordConfidence drops words whose confidence is below it from
+	// streaming transcripts. Zero disables it.
+	MinWordConfidence float64
+
+	// ProfanityTerms lists words to mask with asterisks in transcripts,
+	// matched as whole words regardless of case.
+	ProfanityTerms []string
+
 	// Extra holds provider-specific configuration.
 	Extra map[string]any
 }
@@ -128,6 +193,45 @@ func WithEncoding(encoding string) Option {
 	}
 }
 
+// WithInterimTimeout sets how long a stream may stay silent after an interim
+// result before it is forced final.
+func WithInterimTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.InterimTimeout = d
+	}
+}
+
+// WithFinalizationTimeout sets how long an utterance may stay interim before
+// it is forced final.
+func WithFinalizationTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.FinalizationTimeout = d
+	}
+}
+
+// WithMaxSegmentDuration sets the longest segment TranscribeLong sends to
+// the provider in one request.
+func WithMaxSegmentDuration(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.MaxSegmentDuration = d
+	}
+}
+
+// WithMinWordConfidence drops words with a confidence below minConfidence
+// (0.0 to 1.0) from streaming transcripts.
+func WithMinWordConfidence(minConfidence float64) Option {
+	return func(cfg *Config) {
+		cfg.MinWordConfidence = minConfidence
+	}
+}
+
+// WithProfanityFilter masks the given terms in transcripts.
+func WithProfanityFilter(terms ...string) Option {
+	return func(cfg *Config) {
+		cfg.ProfanityTerms = terms
+	}
+}
+
 // ApplyOptions applies the given options to a Config and returns it.
 func ApplyOptions(opts ...Option) Config {
 	var cfg Config
@@ -171,6 +275,13 @@ func transcribeFrame(ctx context.Context, engine STT, frame voice.Frame, opts ..
 	if frame.Type != voice.FrameAudio {
 		return []voice.Frame{frame}, nil
 	}
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.Diarization {
+		return transcribeFrameSpeakers(ctx, engine, frame, opts...)
+	}
 	text, err := engine.Transcribe(ctx, frame.Data, opts...)
 	if err != nil {
 		return nil, core.Errorf(core.ErrProviderDown, "stt: transcribe: %w", err)
@@ -181,9 +292,35 @@ func transcribeFrame(ctx context.Context, engine STT, frame voice.Frame, opts ..
 	return []voice.Frame{voice.NewTextFrame(text)}, nil
 }
 
+// transcribeFrameSpeakers transcribes an audio frame through the streaming
+// API, which carries speaker labels, and returns a text frame per final
+// transcript with the speaker in its "speaker" metadata.
+func transcribeFrameSpeakers(ctx context.Context, engine STT, frame voice.Frame, opts ...Option) ([]voice.Frame, error) {
+	audio := func(yield func([]byte, error) bool) {
+		yield(frame.Data, nil)
+	}
+	var out []voice.Frame
+	for event, err := range engine.TranscribeStream(ctx, audio, opts...) {
+		if err != nil {
+			return nil, core.Errorf(core.ErrProviderDown, "stt: transcribe: %w", err)
+		}
+		if !event.IsFinal || event.Text == "" {
+			continue
+		}
+		f := voice.NewTextFrame(event.Text)
+		if event.Speaker != "" {
+			f.Metadata = map[string]any{"speaker": event.Speaker}
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
 // AsFrameProcessor wraps an STT engine as a voice.FrameProcessor.
 // It reads audio frames from the input stream, runs transcription, and yields
-// text frames for each successful transcription result.
+// text frames for each successful transcription result. With WithDiarization
+// the frames are transcribed through TranscribeStream and each final
+// transcript becomes a text frame whose "speaker" metadata names its speaker.
 func AsFrameProcessor(engine STT, opts ...Option) voice.FrameProcessor {
 	return voice.FrameLoop(func(ctx context.Context, frame voice.Frame) ([]voice.Frame, error) {
 		return transcribeFrame(ctx, engine, frame, opts...)
@@ -390,6 +390,82 @@ func TestAsFrameProcessor_AudioToText(t *testing.T) {
 	assert.Equal(t, "hello world", frames[0].Text())
 }
 
+func TestAsFrameProcessor_Diarization(t *testing.T) {
+	mock := &mockSTT{
+		transcribeFunc: func(ctx context.Context, audio []byte, opts ...Option) (string, error) {
+			t.Error("Transcribe should not be used with diarization")
+			return "", nil
+		},
+		transcribeStreamFunc: func(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...Option) iter.Seq2[TranscriptEvent, error] {
+			return func(yield func(TranscriptEvent, error) bool) {
+				for chunk, err := range audioStream {
+					require.NoError(t, err)
+					assert.Equal(t, []byte{0x01, 0x02}, chunk)
+				}
+				events := []TranscriptEvent{
+					{Text: "hi", IsFinal: false, Speaker: "0"},
+					{Text: "hi there", IsFinal: true, Speaker: "0"},
+					{Text: "hello", IsFinal: true, Speaker: "1"},
+					{Text: "no label", IsFinal: true},
+				}
+				for _, ev := range events {
+					if !yield(ev, nil) {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	proc := AsFrameProcessor(mock, WithDiarization(true))
+
+	frames, err := runProcessor(context.Background(), proc, voice.NewAudioFrame([]byte{0x01, 0x02}, 16000))
+	require.NoError(t, err)
+	require.Len(t, frames, 3)
+	assert.Equal(t, "hi there", frames[0].Text())
+	assert.Equal(t, "0", frames[0].Metadata["speaker"])
+	assert.Equal(t, "hello", frames[1].Text())
+	assert.Equal(t, "1", frames[1].Metadata["speaker"])
+	assert.Equal(t, "no label", frames[2].Text())
+	assert.NotContains(t, frames[2].Metadata, "speaker")
+}
+
+func TestAsFrameProcessor_DiarizationError(t *testing.T) {
+	mock := &mockSTT{
+		transcribeStreamFunc: func(ctx context.Context, audioStream iter.Seq2[[]byte, error], opts ...Option) iter.Seq2[TranscriptEvent, error] {
+			return func(yield func(TranscriptEvent, error) bool) {
+				yield(TranscriptEvent{}, errors.New("stream failed"))
+			}
+		},
+	}
+
+	proc := AsFrameProcessor(mock, WithDiarization(true))
+
+	_, err := runProcessor(context.Background(), proc, voice.NewAudioFrame([]byte{0x01}, 16000))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stream failed")
+}
+
+func TestSpeakerOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []Word
+		want  string
+	}{
+		{name: "no words", want: ""},
+		{name: "no labels", words: []Word{{Text: "a"}, {Text: "b"}}, want: ""},
+		{name: "single speaker", words: []Word{{Speaker: "A"}, {Speaker: "A"}}, want: "A"},
+		{name: "majority", words: []Word{{Speaker: "A"}, {Speaker: "B"}, {Speaker: "B"}}, want: "B"},
+		{name: "tie goes to first", words: []Word{{Speaker: "B"}, {Speaker: "A"}, {Speaker: "A"}, {Speaker: "B"}}, want: "B"},
+		{name: "unlabelled words ignored", words: []Word{{}, {}, {Speaker: "C"}}, want: "C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SpeakerOf(tt.words))
+		})
+	}
+}
+
 func TestAsFrameProcessor_NonAudioPassThrough(t *testing.T) {
 	mock := &mockSTT{}
 
@@ -0,0 +1,73 @@
+package stt
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// isWAV reports whether audio starts with a RIFF/WAVE header.
+func isWAV(audio []byte) bool {
+	return len(audio) >= 12 && bytes.Equal(audio[0:4], []byte("RIFF")) && bytes.Equal(audio[8:12], []byte("WAVE"))
+}
+
+// wavPCM returns the samples and sample rate of a WAV file holding 16-bit
+// mono PCM.
+func wavPCM(wav []byte) ([]byte, int, error) {
+	rate := 0
+	for off := 12; off+8 <= len(wav); {
+		id := string(wav[off : off+4])
+		size := int(binary.LittleEndian.Uint32(wav[off+4 : off+8]))
+		body := off + 8
+		// Streamed WAV files declare an unknown size as 0xFFFFFFFF, which
+		// may also overflow int on 32-bit platforms.
+		if size < 0 || size > len(wav)-body {
+			size = len(wav) - body
+		}
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, core.Errorf(core.ErrInvalidInput, "stt: malformed WAV fmt chunk")
+			}
+			format := binary.LittleEndian.Uint16(wav[body : body+2])
+			channels := binary.LittleEndian.Uint16(wav[body+2 : body+4])
+			bits := binary.LittleEndian.Uint16(wav[body+14 : body+16])
+			if format != 1 || channels != 1 || bits != 16 {
+				return nil, 0, core.Errorf(core.ErrInvalidInput, "stt: WAV must hold mono 16-bit PCM, got format %d, %d channels, %d bits",
+					format, channels, bits)
+			}
+			rate = int(binary.LittleEndian.Uint32(wav[body+4 : body+8]))
+		case "data":
+			if rate <= 0 {
+				return nil, 0, core.Errorf(core.ErrInvalidInput, "stt: WAV data chunk precedes fmt chunk")
+			}
+			return wav[body : body+size&^1], rate, nil
+		}
+		// Chunks are padded to an even size.
+		off = body + size + size&1
+	}
+	return nil, 0, core.Errorf(core.ErrInvalidInput, "stt: WAV has no data chunk")
+}
+
+// wavFile wraps 16-bit mono PCM at sampleRate in a WAV container.
+func wavFile(pcm []byte, sampleRate int) []byte {
+	var buf bytes.Buffer
+	buf.Grow(44 + len(pcm))
+	le := binary.LittleEndian
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, le, uint32(36+len(pcm))) // #nosec G115 -- audio buffers are far below 4 GiB
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, le, uint32(16))
+	_ = binary.Write(&buf, le, uint16(1))            // PCM
+	_ = binary.Write(&buf, le, uint16(1))            // mono
+	_ = binary.Write(&buf, le, uint32(sampleRate))   // #nosec G115 -- sample rates are small
+	_ = binary.Write(&buf, le, uint32(sampleRate*2)) // #nosec G115 -- byte rate
+	_ = binary.Write(&buf, le, uint16(2))            // block align
+	_ = binary.Write(&buf, le, uint16(16))           // bits per sample
+	buf.WriteString("data")
+	_ = binary.Write(&buf, le, uint32(len(pcm))) // #nosec G115 -- audio buffers are far below 4 GiB
+	buf.Write(pcm)
+	return buf.Bytes()
+}
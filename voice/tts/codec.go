@@ -0,0 +1,292 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// PCMAudio is decoded audio: interleaved 16-bit little-endian samples.
+type PCMAudio struct {
+	// Samples holds the interleaved 16-bit little-endian samples.
+	Samples []byte
+
+	// SampleRate is the number of sample frames per second.
+	SampleRate int
+
+	// Channels is the number of interleaved channels.
+	Channels int
+}
+
+// Codec converts between an encoded audio format and PCMAudio. Transcode
+// uses codecs to convert provider output to the requested format. WAV is
+// supported natively; register codecs for compressed formats such as MP3 or
+// Opus, which typically wrap a cgo library, with RegisterCodec.
+type Codec interface {
+	// Decode decodes a complete encoded audio buffer.
+	Decode(audio []byte) (PCMAudio, error)
+
+	// Encode encodes pcm as a complete audio buffer.
+	Encode(pcm PCMAudio) ([]byte, error)
+}
+
+var (
+	codecMu sync.RWMutex
+	codecs  = map[AudioFormat]Codec{FormatWAV: wavCodec{}}
+)
+
+// RegisterCodec adds the codec for format to the global codec registry. It is
+// intended to be called from init() functions. RegisterCodec panics if format
+// is empty or FormatPCM, which needs no codec, if c is nil, or if format
+// already has a codec.
+func RegisterCodec(format AudioFormat, c Codec) {
+	if format == "" || format == FormatPCM {
+		panic("tts: RegisterCodec called with invalid format " + string(format))
+	}
+	if c == nil {
+		panic("tts: RegisterCodec called with nil codec for " + string(format))
+	}
+
+	codecMu.Lock()
+	defer codecMu.Unlock()
+
+	if _, dup := codecs[format]; dup {
+		panic("tts: RegisterCodec called twice for " + string(format))
+	}
+	codecs[format] = c
+}
+
+// Codecs returns the sorted formats that have a registered codec.
+func Codecs() []AudioFormat {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+
+	formats := make([]AudioFormat, 0, len(codecs))
+	for f := range codecs {
+		formats = append(formats, f)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// codecFor returns the codec for format.
+func codecFor(format AudioFormat) (Codec, error) {
+	codecMu.RLock()
+	c, ok := codecs[format]
+	codecMu.RUnlock()
+
+	if !ok {
+		return nil, core.Errorf(core.ErrNotFound, "tts: no codec for %q audio (registered: %v); see RegisterCodec", format, Codecs())
+	}
+	return c, nil
+}
+
+// DetectFormat identifies the format of audio from its leading bytes: a
+// RIFF/WAVE header, an Ogg page (Opus), or an ID3 tag or MPEG Layer III
+// frames (MP3). Anything else is assumed to be raw PCM.
+func DetectFormat(audio []byte) AudioFormat {
+	switch {
+	case isWAV(audio):
+		return FormatWAV
+	case bytes.HasPrefix(audio, []byte("OggS")):
+		return FormatOpus
+	case bytes.HasPrefix(audio, []byte("ID3")), isMP3(audio):
+		return FormatMP3
+	}
+	return FormatPCM
+}
+
+// Transcode converts audio to format at sampleRate. The source format is
+// found with DetectFormat; raw PCM sources are taken to be mono at
+// sampleRate. A zero sampleRate keeps the source rate, and an empty format
+// returns audio unchanged. Audio already in format at sampleRate is returned
+// unchanged. Raw PCM output carries no channel count, so it is downmixed to
+// mono.
+//
+// Formats other than PCM and WAV need a codec registered with RegisterCodec;
+// without one Transcode returns an ErrNotFound error.
+func Transcode(audio []byte, format AudioFormat, sampleRate int) ([]byte, error) {
+	if format == "" || len(audio) == 0 {
+		return audio, nil
+	}
+	from := DetectFormat(audio)
+	if from == format && (format == FormatPCM || sampleRate == 0) {
+		return audio, nil
+	}
+
+	var pcm PCMAudio
+	if from == FormatPCM {
+		if sampleRate <= 0 {
+			return nil, core.Errorf(core.ErrInvalidInput, "tts: transcode raw PCM to %q needs a sample rate", format)
+		}
+		pcm = PCMAudio{Samples: audio, SampleRate: sampleRate, Channels: 1}
+	} else {
+		dec, err := codecFor(from)
+		if err != nil {
+			return nil, err
+		}
+		if pcm, err = dec.Decode(audio); err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "tts: decode %q audio: %w", from, err)
+		}
+	}
+
+	if from == format && pcm.SampleRate == sampleRate {
+		return audio, nil
+	}
+	if format == FormatPCM && pcm.Channels > 1 {
+		pcm = downmix(pcm)
+	}
+	if sampleRate > 0 && pcm.SampleRate != sampleRate {
+		pcm = resample(pcm, sampleRate)
+	}
+	if format == FormatPCM {
+		return pcm.Samples, nil
+	}
+
+	enc, err := codecFor(format)
+	if err != nil {
+		return nil, err
+	}
+	out, err := enc.Encode(pcm)
+	if err != nil {
+		return nil, core.Errorf(core.ErrInvalidInput, "tts: encode %q audio: %w", format, err)
+	}
+	return out, nil
+}
+
+// downmix averages the channels of pcm into one.
+func downmix(pcm PCMAudio) PCMAudio {
+	frames := len(pcm.Samples) / (2 * pcm.Channels)
+	out := make([]byte, 2*frames)
+	for i := range frames {
+		sum := 0
+		for c := range pcm.Channels {
+			sum += int(sample(pcm.Samples, i*pcm.Channels+c))
+		}
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(sum/pcm.Channels))) // #nosec G115 -- average of int16 values
+	}
+	return PCMAudio{Samples: out, SampleRate: pcm.SampleRate, Channels: 1}
+}
+
+// resample converts pcm to rate by linear interpolation.
+func resample(pcm PCMAudio, rate int) PCMAudio {
+	channels := max(pcm.Channels, 1)
+	in := len(pcm.Samples) / (2 * channels)
+	if in == 0 || pcm.SampleRate <= 0 {
+		return PCMAudio{SampleRate: rate, Channels: channels}
+	}
+	n := int(int64(in) * int64(rate) / int64(pcm.SampleRate))
+	out := make([]byte, 2*n*channels)
+	for i := range n {
+		pos := float64(i) * float64(pcm.SampleRate) / float64(rate)
+		j := int(pos)
+		frac := pos - float64(j)
+		k := min(j+1, in-1)
+		for c := range channels {
+			a := float64(sample(pcm.Samples, j*channels+c))
+			b := float64(sample(pcm.Samples, k*channels+c))
+			v := int16(a + (b-a)*frac) // #nosec G115 -- interpolated between int16 values
+			binary.LittleEndian.PutUint16(out[2*(i*channels+c):], uint16(v))
+		}
+	}
+	return PCMAudio{Samples: out, SampleRate: rate, Channels: channels}
+}
+
+// sample returns the i-th 16-bit sample of samples.
+func sample(samples []byte, i int) int16 {
+	return int16(binary.LittleEndian.Uint16(samples[2*i:])) // #nosec G115 -- reinterpreting sample bits
+}
+
+// wavCodec is the built-in Codec for 16-bit PCM WAV files.
+type wavCodec struct{}
+
+func (wavCodec) Decode(audio []byte) (PCMAudio, error) {
+	info, ok := parseWAV(audio)
+	if !ok {
+		return PCMAudio{}, core.Errorf(core.ErrInvalidInput, "tts: malformed WAV header")
+	}
+	if info.format != 1 || info.bitsPerSample != 16 || info.channels <= 0 {
+		return PCMAudio{}, core.Errorf(core.ErrInvalidInput,
+			"tts: unsupported WAV encoding (format %d, %d bits); only 16-bit PCM is supported", info.format, info.bitsPerSample)
+	}
+	return PCMAudio{
+		Samples:    audio[info.dataOffset : info.dataOffset+info.dataSize],
+		SampleRate: info.sampleRate,
+		Channels:   info.channels,
+	}, nil
+}
+
+func (wavCodec) Encode(pcm PCMAudio) ([]byte, error) {
+	if pcm.SampleRate <= 0 || pcm.Channels <= 0 {
+		return nil, core.Errorf(core.ErrInvalidInput, "tts: WAV encode needs a sample rate and channel count")
+	}
+	return encodeWAV(pcm), nil
+}
+
+// encodeWAV returns pcm as a 16-bit PCM WAV file.
+func encodeWAV(pcm PCMAudio) []byte {
+	blockAlign := 2 * pcm.Channels
+	out := make([]byte, 44, 44+len(pcm.Samples))
+	copy(out[0:], "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(36+len(pcm.Samples))) // #nosec G115 -- audio buffers are far below 4 GiB
+	copy(out[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(out[16:], 16)
+	binary.LittleEndian.PutUint16(out[20:], 1)
+	binary.LittleEndian.PutUint16(out[22:], uint16(pcm.Channels))              // #nosec G115 -- small channel count
+	binary.LittleEndian.PutUint32(out[24:], uint32(pcm.SampleRate))            // #nosec G115 -- positive sample rate
+	binary.LittleEndian.PutUint32(out[28:], uint32(pcm.SampleRate*blockAlign)) // #nosec G115 -- positive byte rate
+	binary.LittleEndian.PutUint16(out[32:], uint16(blockAlign))                // #nosec G115 -- small block size
+	binary.LittleEndian.PutUint16(out[34:], 16)
+	copy(out[36:], "data")
+	binary.LittleEndian.PutUint32(out[40:], uint32(len(pcm.Samples))) // #nosec G115 -- audio buffers are far below 4 GiB
+	return append(out, pcm.Samples...)
+}
+
+// mp3Bitrates holds the Layer III bitrates in kbit/s by bitrate index, for
+// MPEG-1 and for MPEG-2 and 2.5.
+var mp3Bitrates = [2][15]int{
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+}
+
+// mp3SampleRates holds the sample rates by version bits and rate index.
+var mp3SampleRates = map[byte][3]int{
+	3: {44100, 48000, 32000}, // MPEG-1
+	2: {22050, 24000, 16000}, // MPEG-2
+	0: {11025, 12000, 8000},  // MPEG-2.5
+}
+
+// isMP3 reports whether audio starts with an MPEG Layer III frame that is
+// followed by another frame or fills the buffer. Checking the second frame
+// keeps raw PCM that happens to start with a sync word from matching.
+func isMP3(audio []byte) bool {
+	n := mp3FrameLen(audio)
+	if n <= 0 {
+		return false
+	}
+	return n == len(audio) || mp3FrameLen(audio[min(n, len(audio)):]) > 0
+}
+
+// mp3FrameLen returns the length of the Layer III frame whose header starts
+// audio, or 0 when audio does not start with a valid header.
+func mp3FrameLen(audio []byte) int {
+	if len(audio) < 4 || audio[0] != 0xFF || audio[1]&0xE0 != 0xE0 {
+		return 0
+	}
+	version := audio[1] >> 3 & 3
+	layer := audio[1] >> 1 & 3
+	bitrateIdx := audio[2] >> 4
+	rateIdx := audio[2] >> 2 & 3
+	rates, ok := mp3SampleRates[version]
+	if !ok || layer != 1 || bitrateIdx == 0 || bitrateIdx == 15 || rateIdx == 3 {
+		return 0
+	}
+	padding := int(audio[2] >> 1 & 1)
+	if version == 3 {
+		return 144000*mp3Bitrates[0][bitrateIdx]/rates[rateIdx] + padding
+	}
+	return 72000*mp3Bitrates[1][bitrateIdx]/rates[rateIdx] + padding
+}
@@ -0,0 +1,181 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOpus is a Codec that "encodes" by prefixing an Ogg capture pattern.
+type fakeOpus struct{}
+
+func (fakeOpus) Decode(audio []byte) (PCMAudio, error) {
+	if len(audio) < 4 {
+		return PCMAudio{}, errors.New("short")
+	}
+	return PCMAudio{Samples: audio[4:], SampleRate: 48000, Channels: 1}, nil
+}
+
+func (fakeOpus) Encode(pcm PCMAudio) ([]byte, error) {
+	return append([]byte("OggS"), pcm.Samples...), nil
+}
+
+func init() {
+	RegisterCodec(FormatOpus, fakeOpus{})
+}
+
+// samples returns 16-bit little-endian PCM holding vs.
+func samples(vs ...int16) []byte {
+	out := make([]byte, 2*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(v))
+	}
+	return out
+}
+
+// assertCode asserts that err is a core.Error with code.
+func assertCode(t *testing.T, err error, code core.ErrorCode) {
+	t.Helper()
+	var coreErr *core.Error
+	require.ErrorAs(t, err, &coreErr)
+	assert.Equal(t, code, coreErr.Code)
+}
+
+// mp3Frame returns an MPEG-1 Layer III frame at 128 kbit/s and 44.1 kHz.
+func mp3Frame() []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return frame
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatWAV, DetectFormat(wav(samples(1, 2))))
+	assert.Equal(t, FormatOpus, DetectFormat([]byte("OggS\x00\x02")))
+	assert.Equal(t, FormatMP3, DetectFormat([]byte("ID3\x04\x00")))
+	assert.Equal(t, FormatMP3, DetectFormat(append(mp3Frame(), mp3Frame()...)))
+	assert.Equal(t, FormatPCM, DetectFormat(samples(1, 2, 3)))
+	// A sync word without a following frame is sample data.
+	assert.Equal(t, FormatPCM, DetectFormat(append([]byte{0xFF, 0xFB, 0x90, 0x00}, make([]byte, 500)...)))
+}
+
+func TestTranscode_PCMAndWAV(t *testing.T) {
+	pcm := samples(100, -100, 200, -200)
+
+	t.Run("pcm to wav", func(t *testing.T) {
+		out, err := Transcode(pcm, FormatWAV, 16000)
+		require.NoError(t, err)
+		assert.Equal(t, wav(pcm), out)
+	})
+
+	t.Run("wav to pcm", func(t *testing.T) {
+		out, err := Transcode(wav(pcm), FormatPCM, 16000)
+		require.NoError(t, err)
+		assert.Equal(t, pcm, out)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		out, err := Transcode(pcm, FormatPCM, 24000)
+		require.NoError(t, err)
+		assert.Equal(t, pcm, out)
+
+		in := wav(pcm)
+		out, err = Transcode(in, FormatWAV, 16000)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+
+		out, err = Transcode(in, "", 8000)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("resample", func(t *testing.T) {
+		out, err := Transcode(wav(samples(0, 100, 200, 300)), FormatPCM, 8000)
+		require.NoError(t, err)
+		assert.Equal(t, samples(0, 200), out)
+
+		out, err = Transcode(wav(samples(0, 100)), FormatPCM, 32000)
+		require.NoError(t, err)
+		assert.Equal(t, samples(0, 50, 100, 100), out)
+	})
+
+	t.Run("downmix", func(t *testing.T) {
+		stereo := encodeWAV(PCMAudio{Samples: samples(100, 300, -100, -300), SampleRate: 16000, Channels: 2})
+		out, err := Transcode(stereo, FormatPCM, 0)
+		require.NoError(t, err)
+		assert.Equal(t, samples(200, -200), out)
+
+		// WAV output keeps the channels.
+		out, err = Transcode(stereo, FormatWAV, 8000)
+		require.NoError(t, err)
+		info, ok := parseWAV(out)
+		require.True(t, ok)
+		assert.Equal(t, 2, info.channels)
+		assert.Equal(t, 8000, info.sampleRate)
+	})
+
+	t.Run("raw pcm needs rate", func(t *testing.T) {
+		_, err := Transcode(pcm, FormatWAV, 0)
+		assertCode(t, err, core.ErrInvalidInput)
+	})
+
+	t.Run("unsupported wav", func(t *testing.T) {
+		in := wav(pcm)
+		binary.LittleEndian.PutUint16(in[34:], 8)
+		_, err := Transcode(in, FormatPCM, 0)
+		assertCode(t, err, core.ErrInvalidInput)
+	})
+}
+
+func TestTranscode_Codecs(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		out, err := Transcode(wav(samples(1, 2)), FormatOpus, 16000)
+		require.NoError(t, err)
+		assert.Equal(t, append([]byte("OggS"), samples(1, 2)...), out)
+
+		out, err = Transcode(append([]byte("OggS"), samples(1, 2, 3)...), FormatPCM, 0)
+		require.NoError(t, err)
+		assert.Equal(t, samples(1, 2, 3), out)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := Transcode([]byte("ID3\x04\x00"), FormatPCM, 16000)
+		require.Error(t, err)
+		assertCode(t, err, core.ErrNotFound)
+		assert.Contains(t, err.Error(), "mp3")
+	})
+
+	assert.Equal(t, []AudioFormat{FormatOpus, FormatWAV}, Codecs())
+}
+
+func TestRegisterCodec_Panics(t *testing.T) {
+	assert.Panics(t, func() { RegisterCodec("", fakeOpus{}) })
+	assert.Panics(t, func() { RegisterCodec(FormatPCM, fakeOpus{}) })
+	assert.Panics(t, func() { RegisterCodec(FormatMP3, nil) })
+	assert.Panics(t, func() { RegisterCodec(FormatWAV, fakeOpus{}) })
+}
+
+func TestAsFrameProcessor_Transcodes(t *testing.T) {
+	engine := &mockTTS{synthesizeFunc: func(context.Context, string, ...Option) ([]byte, error) {
+		return wav(samples(1, 2, 3, 4)), nil
+	}}
+	proc := AsFrameProcessor(engine, 8000, WithFormat(FormatPCM))
+
+	in := iter.Seq2[voice.Frame, error](func(yield func(voice.Frame, error) bool) {
+		yield(voice.NewTextFrame("hi"), nil)
+	})
+	var frames []voice.Frame
+	for f, err := range proc.Process(context.Background(), in) {
+		require.NoError(t, err)
+		frames = append(frames, f)
+	}
+	require.Len(t, frames, 1)
+	assert.Equal(t, samples(1, 3), frames[0].Data)
+	assert.Equal(t, 8000, frames[0].Metadata["sample_rate"])
+}
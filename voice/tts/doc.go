@@ -16,6 +16,21 @@
 // Supported output formats are defined as [AudioFormat] constants:
 // [FormatPCM], [FormatOpus], [FormatMP3], and [FormatWAV].
 //
+// # Transcoding
+//
+// Providers do not all honor every format. [Transcode] converts audio to the
+// format and sample rate a transport needs, detecting the source format with
+// [DetectFormat]. PCM and 16-bit WAV are converted natively, including
+// resampling and downmixing; compressed formats need a [Codec] registered
+// with [RegisterCodec], typically wrapping a cgo library:
+//
+//	func init() { tts.RegisterCodec(tts.FormatMP3, mp3Codec{}) }
+//
+//	pcm, err := tts.Transcode(audio, tts.FormatPCM, 16000)
+//
+// [AsFrameProcessor] transcodes every frame when a format is set with
+// [WithFormat], so the pipeline receives the format it expects.
+//
 // # Registry Pattern
 //
 // Providers register via [Register] in their init() function and are created
@@ -43,8 +58,80 @@
 //
 // The [Config] struct supports voice, model, sample rate, format, speed, pitch,
 // and provider-specific extras. Use functional options like [WithVoice],
-// [WithModel], [WithSampleRate], [WithFormat], [WithSpeed], and [WithPitch]
-// to configure individual operations.
+// [WithModel], [WithSampleRate], [WithFormat], [WithSpeed], [WithPitch],
+// [WithSSML] and [WithPrefetchBuffer] to configure individual operations.
+//
+// # SSML
+//
+// Enable [WithSSML] to write prosody once in standard SSML and run it on any
+// provider. [ParseSSML] parses a subset of SSML (<speak>, <p>, <s>, <break>,
+// <emphasis>, <prosody> rate and volume, <say-as> and <sub>) into neutral
+// [SSMLSegment] values, and each provider renders them in its native markup
+// through [PrepareText], or strips them to plain text. With SSML enabled,
+// Synthesize takes an SSML document or fragment, and each SynthesizeStream
+// chunk must be a complete fragment.
+//
+//	audio, err := engine.Synthesize(ctx,
+//	    `<speak>Welcome back. <break time="500ms"/> Ready?</speak>`,
+//	    tts.WithSSML(true))
+//
+// Tags honored by provider:
+//   - elevenlabs — <break> (up to 3s)
+//   - cartesia — <break>, <prosody> rate and volume (as <speed> and <volume>
+//     ratios, clamped to 0.6–1.5 and 0.5–2.0)
+//   - playht, lmnt, fish, groq, smallest — none; markup is stripped
+//
+// All providers speak the alias of <sub> and the content of <say-as>.
+// <emphasis> is parsed but not honored by any built-in provider.
+//
+// # Word Timings
+//
+// [SynthesizeWithTimings] returns audio with a [WordTiming] per word, for
+// lip-sync and captioning. Providers that implement [TimedTTS] report the
+// alignment computed by the service (elevenlabs, cartesia). For other
+// providers the audio duration is spread over the words and the timings are
+// flagged Estimated, which requires PCM or WAV output:
+//
+//	audio, timings, err := tts.SynthesizeWithTimings(ctx, engine, "Hello there",
+//	    tts.WithFormat(tts.FormatPCM), tts.WithSampleRate(24000))
+//
+// [AsFrameProcessor] attaches the timings of [TimedTTS] engines to each audio
+// frame under the "word_timings" metadata key.
+//
+// # Sentence Streaming
+//
+// [SynthesizeSentences] buffers a token stream into sentences and synthesizes
+// each one whole, avoiding the unnatural pauses of chunks cut mid-sentence.
+// WAV output is stitched into a single stream with one header:
+//
+//	for chunk, err := range tts.SynthesizeSentences(ctx, engine, llmTokens) {
+//	    if err != nil { break }
+//	    transport.Send(chunk)
+//	}
+//
+// # Prefetching
+//
+// LLMs stream tokens at uneven rates, and synthesizing them as they come
+// produces silence followed by bursts. [SynthesizeStream] with
+// [WithPrefetchBuffer] synthesizes ahead of playback and releases audio only
+// once the given duration is buffered. An empty text chunk marks the end of
+// an utterance and flushes the buffer at once:
+//
+//	stream := tts.SynthesizeStream(ctx, engine, llmTokens,
+//	    tts.WithFormat(tts.FormatPCM), tts.WithSampleRate(24000),
+//	    tts.WithPrefetchBuffer(300*time.Millisecond))
+//
+// # Custom Voices
+//
+// Providers that support voice cloning implement [VoiceManager] to list,
+// create and delete [Voice] values. Check for it with a type assertion:
+//
+//	if vm, ok := engine.(tts.VoiceManager); ok {
+//	    v, err := vm.CreateVoice(ctx, "tenant-42", [][]byte{sample})
+//	    audio, err := engine.Synthesize(ctx, "Hi!", tts.WithVoice(v.ID))
+//	}
+//
+// elevenlabs accepts several samples per voice; cartesia takes exactly one.
 //
 // # Hooks
 //
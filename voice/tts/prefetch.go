@@ -0,0 +1,174 @@
+package tts
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// prefetchQueue is the number of audio chunks SynthesizeStream synthesizes
+// ahead of the consumer.
+const prefetchQueue = 64
+
+// prefetchEvent is a chunk of audio, an error or an end-of-utterance change
+// passed from the synthesis goroutine to the consumer.
+type prefetchEvent struct {
+	audio []byte
+	err   error
+
+	// utterance reports a change of end-of-utterance state: eou is true
+	// when the text stream signalled the end of an utterance and false when
+	// text resumed.
+	utterance bool
+	eou       bool
+}
+
+// SynthesizeStream converts textStream to a stream of audio chunks with
+// engine. Without a prefetch buffer it is engine.SynthesizeStream.
+//
+// With WithPrefetchBuffer, synthesis runs in its own goroutine up to 64
+// chunks ahead of the consumer, and audio is held back until the buffer
+// holds the requested duration, so that pauses between LLM tokens are
+// absorbed rather than heard as silence followed by a burst. When the buffer
+// runs dry it fills again before releasing more audio. An empty text chunk
+// signals the end of an utterance: the audio of the text before it is
+// released as soon as it is synthesized, and buffering resumes with the
+// next text. The end of textStream flushes the buffer the same way.
+//
+// Measuring the buffer needs WAV audio, or PCM audio with WithFormat(FormatPCM)
+// and WithSampleRate. For other formats synthesis still runs ahead but audio
+// is released as it arrives.
+func SynthesizeStream(ctx context.Context, engine TTS, textStream iter.Seq2[string, error], opts ...Option) iter.Seq2[[]byte, error] {
+	cfg := ApplyOptions(opts...)
+	if cfg.PrefetchBuffer <= 0 {
+		return engine.SynthesizeStream(ctx, textStream, opts...)
+	}
+
+	return func(yield func([]byte, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		events := make(chan prefetchEvent, prefetchQueue)
+		send := func(ev prefetchEvent) bool {
+			select {
+			case events <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		go func() {
+			defer close(events)
+			eou := false
+			text := func(yield func(string, error) bool) {
+				for chunk, err := range textStream {
+					if err == nil && (chunk == "") != eou {
+						eou = chunk == ""
+						if !send(prefetchEvent{utterance: true, eou: eou}) {
+							return
+						}
+					}
+					if !yield(chunk, err) {
+						return
+					}
+				}
+			}
+			for audio, err := range engine.SynthesizeStream(ctx, text, opts...) {
+				if !send(prefetchEvent{audio: audio, err: err}) || err != nil {
+					return
+				}
+			}
+		}()
+
+		var (
+			pending  []timedChunk
+			buffered time.Duration
+			primed   bool // the buffer filled and audio is being released
+			flushing bool // an utterance ended; release audio as it arrives
+			done     bool
+			meter    audioMeter
+		)
+		for {
+			if (primed || flushing || done) && len(pending) > 0 {
+				chunk := pending[0]
+				pending = pending[1:]
+				buffered -= chunk.duration
+				if len(pending) == 0 {
+					primed = false
+				}
+				if !yield(chunk.audio, nil) {
+					return
+				}
+				continue
+			}
+			if done {
+				return
+			}
+
+			var ev prefetchEvent
+			var ok bool
+			select {
+			case ev, ok = <-events:
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			}
+			switch {
+			case !ok:
+				done = true
+			case ev.err != nil:
+				// Deliver the audio synthesized before the failure first.
+				for _, chunk := range pending {
+					if !yield(chunk.audio, nil) {
+						return
+					}
+				}
+				yield(nil, ev.err)
+				return
+			case ev.utterance:
+				flushing = ev.eou
+			default:
+				d, known := meter.measure(ev.audio, cfg)
+				pending = append(pending, timedChunk{audio: ev.audio, duration: d})
+				buffered += d
+				if !known || buffered >= cfg.PrefetchBuffer {
+					primed = true
+				}
+			}
+		}
+	}
+}
+
+// timedChunk is a buffered audio chunk and its playing time.
+type timedChunk struct {
+	audio    []byte
+	duration time.Duration
+}
+
+// audioMeter measures the playing time of consecutive chunks of a stream,
+// remembering the byte rate of a WAV header in the first chunk.
+type audioMeter struct {
+	started  bool
+	byteRate int
+}
+
+// measure returns the playing time of the next chunk of the stream and
+// whether it could be determined.
+func (m *audioMeter) measure(chunk []byte, cfg Config) (time.Duration, bool) {
+	if !m.started {
+		m.started = true
+		if info, ok := parseWAV(chunk); ok {
+			m.byteRate = int(info.byteRate)
+			return time.Duration(info.dataSize) * time.Second / time.Duration(m.byteRate), true
+		}
+	}
+	rate := m.byteRate
+	if rate == 0 && cfg.Format == FormatPCM && cfg.SampleRate > 0 {
+		rate = 2 * cfg.SampleRate
+	}
+	if rate == 0 {
+		return 0, false
+	}
+	return time.Duration(len(chunk)) * time.Second / time.Duration(rate), true
+}
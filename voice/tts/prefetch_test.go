@@ -0,0 +1,185 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcmEngine returns a mockTTS whose stream emits 50ms of 16 kHz PCM per
+// non-empty text chunk, filled with the chunk's first byte.
+func pcmEngine() *mockTTS {
+	return &mockTTS{synthesizeStreamFunc: func(_ context.Context, text iter.Seq2[string, error], _ ...Option) iter.Seq2[[]byte, error] {
+		return func(yield func([]byte, error) bool) {
+			for chunk, err := range text {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if chunk == "" {
+					continue
+				}
+				audio := make([]byte, 1600)
+				for i := range audio {
+					audio[i] = chunk[0]
+				}
+				if !yield(audio, nil) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// chanText returns a text stream fed from ch.
+func chanText(ch <-chan string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for s := range ch {
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+// consume collects stream into a channel of the first byte of each chunk.
+func consume(stream iter.Seq2[[]byte, error]) (<-chan byte, <-chan error) {
+	out := make(chan byte, 16)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for chunk, err := range stream {
+			if err != nil {
+				errc <- err
+				return
+			}
+			out <- chunk[0]
+		}
+	}()
+	return out, errc
+}
+
+func expectChunk(t *testing.T, out <-chan byte, want byte) {
+	t.Helper()
+	select {
+	case got := <-out:
+		assert.Equal(t, want, got)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for chunk %q", want)
+	}
+}
+
+func expectNone(t *testing.T, out <-chan byte) {
+	t.Helper()
+	select {
+	case got, ok := <-out:
+		if ok {
+			t.Fatalf("unexpected chunk %q", got)
+		}
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSynthesizeStream_NoPrefetch(t *testing.T) {
+	var chunks []string
+	for audio, err := range SynthesizeStream(context.Background(), &mockTTS{}, textChunks("a", "b")) {
+		require.NoError(t, err)
+		chunks = append(chunks, string(audio))
+	}
+	assert.Equal(t, []string{"audio:a", "audio:b"}, chunks)
+}
+
+func TestSynthesizeStream_Prefetch(t *testing.T) {
+	text := make(chan string)
+	stream := SynthesizeStream(context.Background(), pcmEngine(), chanText(text),
+		WithFormat(FormatPCM), WithSampleRate(16000), WithPrefetchBuffer(100*time.Millisecond))
+	out, _ := consume(stream)
+
+	// 50ms is held back until the buffer holds 100ms.
+	text <- "a"
+	expectNone(t, out)
+	text <- "b"
+	expectChunk(t, out, 'a')
+	expectChunk(t, out, 'b')
+
+	// The buffer ran dry, so it fills again.
+	text <- "c"
+	expectNone(t, out)
+
+	// An end of utterance flushes it.
+	text <- ""
+	expectChunk(t, out, 'c')
+
+	// Buffering resumes with the next text; the end of the stream flushes.
+	text <- "d"
+	expectNone(t, out)
+	close(text)
+	expectChunk(t, out, 'd')
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestSynthesizeStream_PrefetchUnknownFormat(t *testing.T) {
+	text := make(chan string)
+	stream := SynthesizeStream(context.Background(), pcmEngine(), chanText(text),
+		WithFormat(FormatMP3), WithPrefetchBuffer(time.Second))
+	out, _ := consume(stream)
+
+	text <- "a"
+	expectChunk(t, out, 'a')
+	close(text)
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestSynthesizeStream_PrefetchWAV(t *testing.T) {
+	var m audioMeter
+	d, ok := m.measure(wav(make([]byte, 3200)), Config{})
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, d)
+
+	// Later chunks are headerless samples at the same rate.
+	d, ok = m.measure(make([]byte, 1600), Config{})
+	require.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, d)
+}
+
+func TestSynthesizeStream_PrefetchError(t *testing.T) {
+	textErr := errors.New("llm failed")
+	text := func(yield func(string, error) bool) {
+		if yield("a", nil) {
+			yield("", textErr)
+		}
+	}
+	stream := SynthesizeStream(context.Background(), pcmEngine(), text,
+		WithFormat(FormatPCM), WithSampleRate(16000), WithPrefetchBuffer(time.Second))
+
+	var chunks int
+	var gotErr error
+	for _, err := range stream {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		chunks++
+	}
+	assert.Equal(t, 1, chunks, "buffered audio is delivered before the error")
+	assert.ErrorIs(t, gotErr, textErr)
+}
+
+func TestSynthesizeStream_PrefetchEarlyBreak(t *testing.T) {
+	stream := SynthesizeStream(context.Background(), pcmEngine(), textChunks("a", "b", "c", ""),
+		WithFormat(FormatPCM), WithSampleRate(16000), WithPrefetchBuffer(10*time.Millisecond))
+	var got []byte
+	for audio, err := range stream {
+		require.NoError(t, err)
+		got = append(got, audio[0])
+		break
+	}
+	assert.Equal(t, []byte("a"), got)
+}
@@ -2,11 +2,14 @@ package cartesia
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"iter"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
 	"github.com/lookatitude/beluga-ai/v2/voice/tts"
@@ -18,7 +21,7 @@ const (
 	apiVersion     = "2024-06-10"
 )
 
-var _ tts.TTS = (*Engine)(nil) // compile-time interface check
+var _ tts.TimedTTS = (*Engine)(nil) // compile-time interface check
 
 func init() {
 	tts.Register("cartesia", func(cfg tts.Config) (tts.TTS, error) {
@@ -28,8 +31,10 @@ func init() {
 
 // Engine implements tts.TTS using the Cartesia API.
 type Engine struct {
-	client *httpclient.Client
-	cfg    tts.Config
+	client  *httpclient.Client
+	apiKey  string
+	baseURL string
+	cfg     tts.Config
 }
 
 // New creates a new Cartesia TTS engine.
@@ -56,8 +61,10 @@ func New(cfg tts.Config) (*Engine, error) {
 	)
 
 	return &Engine{
-		client: client,
-		cfg:    cfg,
+		client:  client,
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		cfg:     cfg,
 	}, nil
 }
 
@@ -67,6 +74,9 @@ type cartesiaRequest struct {
 	Transcript   string            `json:"transcript"`
 	Voice        cartesiaVoice     `json:"voice"`
 	OutputFormat cartesiaOutFormat `json:"output_format"`
+
+	// AddTimestamps requests word timestamps on the SSE endpoint.
+	AddTimestamps bool `json:"add_timestamps,omitempty"`
 }
 
 type cartesiaVoice struct {
@@ -80,19 +90,14 @@ type cartesiaOutFormat struct {
 	SampleRate int    `json:"sample_rate"`
 }
 
-// Synthesize converts text to audio using the Cartesia TTS API.
-func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option) ([]byte, error) {
-	cfg := e.cfg
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-
+// newRequest builds the request body for synthesizing text with cfg.
+func newRequest(cfg tts.Config, text string) cartesiaRequest {
 	sampleRate := cfg.SampleRate
 	if sampleRate == 0 {
 		sampleRate = 24000
 	}
 
-	reqBody := cartesiaRequest{
+	return cartesiaRequest{
 		ModelID:    cfg.Model,
 		Transcript: text,
 		Voice: cartesiaVoice{
@@ -105,14 +110,34 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 			SampleRate: sampleRate,
 		},
 	}
+}
+
+// Synthesize converts text to audio using the Cartesia TTS API.
+func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option) ([]byte, error) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
+	text, err := tts.PrepareText(cfg, text, renderSSML)
+	if err != nil {
+		return nil, fmt.Errorf("cartesia: %w", err)
+	}
+
+	reqBody := newRequest(cfg, text)
 	resp, err := e.client.Do(ctx, http.MethodPost, "/tts/bytes", reqBody, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cartesia: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	return readResponse(resp)
+}
+
+// readResponse returns the body of a successful response, or an
+// httpclient.APIError carrying the API's error message.
+func readResponse(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		body, _ := io.ReadAll(resp.Body)
 
 		msg := string(body)
@@ -130,12 +155,81 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		}
 	}
 
-	audio, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("cartesia: read response: %w", err)
 	}
 
-	return audio, nil
+	return body, nil
+}
+
+// sseEvent is the JSON payload of an event from the Cartesia SSE endpoint.
+type sseEvent struct {
+	Type           string          `json:"type"`
+	Data           string          `json:"data"`
+	Error          string          `json:"error"`
+	WordTimestamps *wordTimestamps `json:"word_timestamps"`
+}
+
+type wordTimestamps struct {
+	Words []string  `json:"words"`
+	Start []float64 `json:"start"`
+	End   []float64 `json:"end"`
+}
+
+// SynthesizeWithTimings converts text to audio and returns the timing of
+// each word, using the word timestamps of the Cartesia SSE endpoint.
+func (e *Engine) SynthesizeWithTimings(ctx context.Context, text string, opts ...tts.Option) ([]byte, []tts.WordTiming, error) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	text, err := tts.PrepareText(cfg, text, renderSSML)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cartesia: %w", err)
+	}
+
+	reqBody := newRequest(cfg, text)
+	reqBody.AddTimestamps = true
+
+	var audio []byte
+	var timings []tts.WordTiming
+	for ev, err := range httpclient.StreamSSEWithBody(ctx, e.client, http.MethodPost, "/tts/sse", reqBody) {
+		if err != nil {
+			return nil, nil, fmt.Errorf("cartesia: request failed: %w", err)
+		}
+		var msg sseEvent
+		if err := json.Unmarshal([]byte(ev.Data), &msg); err != nil {
+			return nil, nil, fmt.Errorf("cartesia: decode event: %w", err)
+		}
+		switch msg.Type {
+		case "chunk":
+			chunk, err := base64.StdEncoding.DecodeString(msg.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("cartesia: decode audio: %w", err)
+			}
+			audio = append(audio, chunk...)
+		case "timestamps":
+			if wt := msg.WordTimestamps; wt != nil {
+				for i, w := range wt.Words {
+					if i >= len(wt.Start) || i >= len(wt.End) {
+						break
+					}
+					timings = append(timings, tts.WordTiming{
+						Word:  w,
+						Start: time.Duration(wt.Start[i] * float64(time.Second)),
+						End:   time.Duration(wt.End[i] * float64(time.Second)),
+					})
+				}
+			}
+		case "error":
+			return nil, nil, fmt.Errorf("cartesia: synthesis failed: %s", msg.Error)
+		case "done":
+			return audio, timings, nil
+		}
+	}
+	return audio, timings, nil
 }
 
 // synthesizeChunk synthesizes a single text chunk and returns the audio or an error.
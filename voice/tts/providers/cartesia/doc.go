@@ -29,6 +29,7 @@
 //
 // # Exported Types
 //
-//   - [Engine] — implements tts.TTS using Cartesia
+//   - [Engine] — implements tts.TTS, tts.TimedTTS and tts.VoiceManager using
+//     Cartesia
 //   - [New] — constructor accepting tts.Config
 package cartesia
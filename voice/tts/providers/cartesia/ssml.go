@@ -0,0 +1,54 @@
+package cartesia
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+// Ranges of the speed and volume ratios Cartesia accepts.
+const (
+	minSpeed, maxSpeed   = 0.6, 1.5
+	minVolume, maxVolume = 0.5, 2.0
+)
+
+// renderSSML renders parsed SSML as a Cartesia transcript. Pauses become
+// <break time="Xs"/> tags and prosody rate and volume become <speed ratio/>
+// and <volume ratio/> tags, clamped to the ranges Cartesia accepts.
+// Emphasis has no Cartesia equivalent and is dropped.
+func renderSSML(segments []tts.SSMLSegment) string {
+	var b strings.Builder
+	speed, volume := 1.0, 1.0
+	for _, s := range segments {
+		if s.Break > 0 {
+			b.WriteString(` <break time="` + tts.FormatSeconds(s.Break) + `"/> `)
+			continue
+		}
+		if r := ratio(s.Rate, minSpeed, maxSpeed); r != speed {
+			b.WriteString(` <speed ratio="` + formatRatio(r) + `"/> `)
+			speed = r
+		}
+		if r := ratio(s.Volume, minVolume, maxVolume); r != volume {
+			b.WriteString(` <volume ratio="` + formatRatio(r) + `"/> `)
+			volume = r
+		}
+		b.WriteString(s.Text)
+	}
+	return tts.CollapseSpace(b.String())
+}
+
+// ratio returns the multiplier m clamped to [lo, hi], or 1 when m is the
+// default.
+func ratio(m, lo, hi float64) float64 {
+	if m == 0 {
+		return 1
+	}
+	return min(max(m, lo), hi)
+}
+
+// formatRatio formats r with at most two decimals.
+func formatRatio(r float64) string {
+	return strconv.FormatFloat(math.Round(r*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,41 @@
+package cartesia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func TestRenderSSML(t *testing.T) {
+	segments, err := tts.ParseSSML(`<speak>Hello <break time="250ms"/>` +
+		`<prosody rate="fast" volume="loud">quick</prosody> <prosody rate="x-slow">slow</prosody> normal</speak>`)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`Hello <break time="0.25s"/> <speed ratio="1.25"/> <volume ratio="1.5"/> quick <speed ratio="0.6"/> <volume ratio="1"/> slow <speed ratio="1"/> normal`,
+		renderSSML(segments))
+}
+
+func TestSynthesize_SSML(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cartesiaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		got = req.Transcript
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	_, err = e.Synthesize(context.Background(), `Pause <break time="2s"/> here`, tts.WithSSML(true))
+	require.NoError(t, err)
+	assert.Equal(t, `Pause <break time="2s"/> here`, got)
+}
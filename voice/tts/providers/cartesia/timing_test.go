@@ -0,0 +1,62 @@
+package cartesia
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func TestSynthesizeWithTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tts/sse", r.URL.Path)
+		var req cartesiaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.AddTimestamps)
+		assert.Equal(t, "Hi you", req.Transcript)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, ev := range []string{
+			`{"type":"chunk","data":"` + base64.StdEncoding.EncodeToString([]byte{1, 2}) + `"}`,
+			`{"type":"timestamps","word_timestamps":{"words":["Hi","you"],"start":[0,0.25],"end":[0.2,0.5]}}`,
+			`{"type":"chunk","data":"` + base64.StdEncoding.EncodeToString([]byte{3, 4}) + `"}`,
+			`{"type":"done"}`,
+		} {
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", ev)
+		}
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	audio, timings, err := e.SynthesizeWithTimings(context.Background(), "Hi you")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, audio)
+	assert.Equal(t, []tts.WordTiming{
+		{Word: "Hi", Start: 0, End: 200 * time.Millisecond},
+		{Word: "you", Start: 250 * time.Millisecond, End: 500 * time.Millisecond},
+	}, timings)
+}
+
+func TestSynthesizeWithTimings_ErrorEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"type\":\"error\",\"error\":\"voice not found\"}\n\n")
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	_, _, err = e.SynthesizeWithTimings(context.Background(), "Hi")
+	assert.ErrorContains(t, err, "voice not found")
+}
@@ -0,0 +1,138 @@
+package cartesia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+var _ tts.VoiceManager = (*Engine)(nil) // compile-time interface check
+
+// voiceResponse is a voice in the Cartesia voices API.
+type voiceResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+func (v voiceResponse) voice() tts.Voice {
+	return tts.Voice{
+		ID:          v.ID,
+		Name:        v.Name,
+		Description: v.Description,
+		Language:    v.Language,
+		Custom:      !v.IsPublic,
+	}
+}
+
+// ListVoices returns the public voices and the account's own voices. Voices
+// that are not public are reported as Custom.
+func (e *Engine) ListVoices(ctx context.Context) ([]tts.Voice, error) {
+	resp, err := e.client.Do(ctx, http.MethodGet, "/voices", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cartesia: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API returns a plain array, or a page object in newer versions.
+	var list []voiceResponse
+	if err := json.Unmarshal(body, &list); err != nil {
+		var page struct {
+			Data []voiceResponse `json:"data"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("cartesia: decode voices: %w", err)
+		}
+		list = page.Data
+	}
+	voices := make([]tts.Voice, len(list))
+	for i, v := range list {
+		voices[i] = v.voice()
+	}
+	return voices, nil
+}
+
+// CreateVoice clones a voice called name from a single audio clip of the
+// speaker; Cartesia recommends 5 to 10 seconds of clear speech. It returns
+// an ErrInvalidInput error unless exactly one sample is given.
+func (e *Engine) CreateVoice(ctx context.Context, name string, samples [][]byte) (tts.Voice, error) {
+	if name == "" || len(samples) != 1 {
+		return tts.Voice{}, core.Errorf(core.ErrInvalidInput, "cartesia: create voice needs a name and exactly one sample, got %d", len(samples))
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("name", name); err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: build request: %w", err)
+	}
+	if err := w.WriteField("mode", "similarity"); err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: build request: %w", err)
+	}
+	part, err := w.CreateFormFile("clip", "sample."+string(tts.DetectFormat(samples[0])))
+	if err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: build request: %w", err)
+	}
+	if _, err := part.Write(samples[0]); err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: build request: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: build request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/voices/clone", &buf)
+	if err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", e.apiKey)
+	req.Header.Set("Cartesia-Version", apiVersion)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return tts.Voice{}, err
+	}
+	var v voiceResponse
+	if err := json.Unmarshal(body, &v); err != nil {
+		return tts.Voice{}, fmt.Errorf("cartesia: decode voice: %w", err)
+	}
+	voice := v.voice()
+	if voice.Name == "" {
+		voice.Name = name
+	}
+	return voice, nil
+}
+
+// DeleteVoice deletes the custom voice with the given ID.
+func (e *Engine) DeleteVoice(ctx context.Context, id string) error {
+	if id == "" {
+		return core.Errorf(core.ErrInvalidInput, "cartesia: delete voice needs an ID")
+	}
+	resp, err := e.client.Do(ctx, http.MethodDelete, "/voices/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return fmt.Errorf("cartesia: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, err = readResponse(resp)
+	return err
+}
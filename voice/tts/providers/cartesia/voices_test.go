@@ -0,0 +1,106 @@
+package cartesia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/internal/httpclient"
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func newVoiceTestEngine(t *testing.T, h http.HandlerFunc) *Engine {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "sk-test", "base_url": srv.URL}})
+	require.NoError(t, err)
+	return e
+}
+
+func TestEngine_ImplementsVoiceManager(t *testing.T) {
+	var engine tts.TTS = &Engine{}
+	_, ok := engine.(tts.VoiceManager)
+	assert.True(t, ok)
+}
+
+func TestListVoices(t *testing.T) {
+	voices := []map[string]any{
+		{"id": "v1", "name": "Barbershop Man", "language": "en", "is_public": true},
+		{"id": "v2", "name": "Tenant", "description": "support line"},
+	}
+	want := []tts.Voice{
+		{ID: "v1", Name: "Barbershop Man", Language: "en"},
+		{ID: "v2", Name: "Tenant", Description: "support line", Custom: true},
+	}
+
+	t.Run("array", func(t *testing.T) {
+		e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/voices", r.URL.Path)
+			assert.Equal(t, "sk-test", r.Header.Get("X-API-Key"))
+			_ = json.NewEncoder(w).Encode(voices)
+		})
+		got, err := e.ListVoices(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": voices, "has_more": false})
+		})
+		got, err := e.ListVoices(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestCreateVoice(t *testing.T) {
+	e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/voices/clone", r.URL.Path)
+		assert.Equal(t, "sk-test", r.Header.Get("X-API-Key"))
+		assert.Equal(t, apiVersion, r.Header.Get("Cartesia-Version"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "tenant-42", r.FormValue("name"))
+		_, header, err := r.FormFile("clip")
+		require.NoError(t, err)
+		assert.Equal(t, "sample.wav", header.Filename)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "new-id", "name": "tenant-42", "language": "en"})
+	})
+
+	clip := append([]byte("RIFF\x00\x00\x00\x00WAVE"), make([]byte, 32)...)
+	v, err := e.CreateVoice(context.Background(), "tenant-42", [][]byte{clip})
+	require.NoError(t, err)
+	assert.Equal(t, tts.Voice{ID: "new-id", Name: "tenant-42", Language: "en", Custom: true}, v)
+
+	_, err = e.CreateVoice(context.Background(), "tenant-42", [][]byte{clip, clip})
+	assert.ErrorContains(t, err, "exactly one sample")
+}
+
+func TestDeleteVoice(t *testing.T) {
+	e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		if r.URL.Path == "/voices/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"voice not found"}`))
+			return
+		}
+		assert.Equal(t, "/voices/v2", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(t, e.DeleteVoice(context.Background(), "v2"))
+
+	err := e.DeleteVoice(context.Background(), "missing")
+	var apiErr *httpclient.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "voice not found", apiErr.Message)
+}
@@ -29,6 +29,7 @@
 //
 // # Exported Types
 //
-//   - [Engine] — implements tts.TTS using ElevenLabs
+//   - [Engine] — implements tts.TTS, tts.TimedTTS and tts.VoiceManager using
+//     ElevenLabs
 //   - [New] — constructor accepting tts.Config
 package elevenlabs
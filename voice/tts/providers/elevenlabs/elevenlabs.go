@@ -3,11 +3,13 @@ package elevenlabs
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"iter"
 	"net/http"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/voice/tts"
 )
@@ -18,7 +20,7 @@ const (
 	defaultModel   = "eleven_monolingual_v1"
 )
 
-var _ tts.TTS = (*Engine)(nil) // compile-time interface check
+var _ tts.TimedTTS = (*Engine)(nil) // compile-time interface check
 
 func init() {
 	tts.Register("elevenlabs", func(cfg tts.Config) (tts.TTS, error) {
@@ -78,6 +80,71 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		opt(&cfg)
 	}
 
+	text, err := tts.PrepareText(cfg, text, renderSSML)
+	if err != nil {
+		return nil, fmt.Errorf("elevenlabs tts: %w", err)
+	}
+
+	return e.post(ctx, cfg, text, "", "audio/mpeg")
+}
+
+// timestampsResponse is the JSON body returned by the with-timestamps
+// endpoint.
+type timestampsResponse struct {
+	AudioBase64 string    `json:"audio_base64"`
+	Alignment   alignment `json:"alignment"`
+}
+
+type alignment struct {
+	Characters []string  `json:"characters"`
+	Starts     []float64 `json:"character_start_times_seconds"`
+	Ends       []float64 `json:"character_end_times_seconds"`
+}
+
+// SynthesizeWithTimings converts text to audio and returns the timing of
+// each word, using the ElevenLabs character alignment from the
+// with-timestamps endpoint.
+func (e *Engine) SynthesizeWithTimings(ctx context.Context, text string, opts ...tts.Option) ([]byte, []tts.WordTiming, error) {
+	cfg := e.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	text, err := tts.PrepareText(cfg, text, renderSSML)
+	if err != nil {
+		return nil, nil, fmt.Errorf("elevenlabs tts: %w", err)
+	}
+
+	body, err := e.post(ctx, cfg, text, "/with-timestamps", "application/json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp timestampsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, fmt.Errorf("elevenlabs tts: decode response: %w", err)
+	}
+	audio, err := base64.StdEncoding.DecodeString(resp.AudioBase64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("elevenlabs tts: decode audio: %w", err)
+	}
+
+	a := resp.Alignment
+	return audio, tts.WordsFromCharacters(a.Characters, seconds(a.Starts), seconds(a.Ends)), nil
+}
+
+// seconds converts offsets in seconds to durations.
+func seconds(s []float64) []time.Duration {
+	d := make([]time.Duration, len(s))
+	for i, v := range s {
+		d[i] = time.Duration(v * float64(time.Second))
+	}
+	return d
+}
+
+// post sends a synthesis request for text to the voice endpoint with the
+// given path suffix and returns the response body.
+func (e *Engine) post(ctx context.Context, cfg tts.Config, text, suffix, accept string) ([]byte, error) {
 	reqBody := synthesizeRequest{
 		Text:    text,
 		ModelID: cfg.Model,
@@ -92,14 +159,23 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		return nil, fmt.Errorf("elevenlabs tts: marshal request: %w", err)
 	}
 
-	u := fmt.Sprintf("%s/text-to-speech/%s", e.baseURL, cfg.Voice)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	return e.do(ctx, http.MethodPost, "/text-to-speech/"+cfg.Voice+suffix, "application/json", accept, bytes.NewReader(data))
+}
+
+// do sends a request to path, relative to the base URL, and returns the
+// response body. Empty contentType and accept headers are not sent.
+func (e *Engine) do(ctx context.Context, method, path, contentType, accept string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, body)
 	if err != nil {
 		return nil, fmt.Errorf("elevenlabs tts: create request: %w", err)
 	}
 	req.Header.Set("xi-api-key", e.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "audio/mpeg")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -112,12 +188,12 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		return nil, fmt.Errorf("elevenlabs tts: API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	audio, err := io.ReadAll(resp.Body)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("elevenlabs tts: read response: %w", err)
 	}
 
-	return audio, nil
+	return data, nil
 }
 
 // synthesizeChunk synthesizes a single text chunk and returns the audio or an error.
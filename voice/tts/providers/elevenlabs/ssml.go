@@ -0,0 +1,26 @@
+package elevenlabs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+// maxBreak is the longest pause ElevenLabs honors in a <break> tag.
+const maxBreak = 3 * time.Second
+
+// renderSSML renders parsed SSML as ElevenLabs input text. Pauses become
+// <break time="Xs" /> tags, capped at three seconds; emphasis and prosody
+// have no ElevenLabs equivalent and are dropped.
+func renderSSML(segments []tts.SSMLSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		if s.Break > 0 {
+			b.WriteString(` <break time="` + tts.FormatSeconds(min(s.Break, maxBreak)) + `" /> `)
+			continue
+		}
+		b.WriteString(s.Text)
+	}
+	return tts.CollapseSpace(b.String())
+}
@@ -0,0 +1,41 @@
+package elevenlabs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func TestRenderSSML(t *testing.T) {
+	segments, err := tts.ParseSSML(`<speak>Hello <break time="500ms"/> <emphasis>world</emphasis><break time="10s"/></speak>`)
+	require.NoError(t, err)
+	assert.Equal(t, `Hello <break time="0.5s" /> world <break time="3s" />`, renderSSML(segments))
+}
+
+func TestSynthesize_SSML(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req synthesizeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		got = req.Text
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	_, err = e.Synthesize(context.Background(), `<speak>Wait <break time="1s"/> go</speak>`, tts.WithSSML(true))
+	require.NoError(t, err)
+	assert.Equal(t, `Wait <break time="1s" /> go`, got)
+
+	_, err = e.Synthesize(context.Background(), `<speak>broken`, tts.WithSSML(true))
+	assert.ErrorContains(t, err, "invalid SSML")
+}
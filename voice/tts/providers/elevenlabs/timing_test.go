@@ -0,0 +1,57 @@
+package elevenlabs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func TestSynthesizeWithTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/text-to-speech/"+defaultVoice+"/with-timestamps", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"audio_base64": base64.StdEncoding.EncodeToString([]byte("mp3-audio")),
+			"alignment": map[string]any{
+				"characters":                    []string{"H", "i", " ", "y", "o"},
+				"character_start_times_seconds": []float64{0, 0.1, 0.2, 0.3, 0.4},
+				"character_end_times_seconds":   []float64{0.1, 0.2, 0.3, 0.4, 0.5},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	audio, timings, err := e.SynthesizeWithTimings(context.Background(), "Hi yo")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("mp3-audio"), audio)
+	assert.Equal(t, []tts.WordTiming{
+		{Word: "Hi", Start: 0, End: 200 * time.Millisecond},
+		{Word: "yo", Start: 300 * time.Millisecond, End: 500 * time.Millisecond},
+	}, timings)
+}
+
+func TestSynthesizeWithTimings_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("bad key"))
+	}))
+	defer srv.Close()
+
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "key", "base_url": srv.URL}})
+	require.NoError(t, err)
+
+	_, _, err = e.SynthesizeWithTimings(context.Background(), "Hi")
+	assert.ErrorContains(t, err, "status 401")
+}
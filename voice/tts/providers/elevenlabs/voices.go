@@ -0,0 +1,103 @@
+package elevenlabs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+var _ tts.VoiceManager = (*Engine)(nil) // compile-time interface check
+
+// voiceResponse is a voice in the ElevenLabs voices API.
+type voiceResponse struct {
+	VoiceID     string            `json:"voice_id"`
+	Name        string            `json:"name"`
+	Category    string            `json:"category"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels"`
+}
+
+func (v voiceResponse) voice() tts.Voice {
+	return tts.Voice{
+		ID:          v.VoiceID,
+		Name:        v.Name,
+		Description: v.Description,
+		Language:    v.Labels["language"],
+		Custom:      v.Category != "premade",
+	}
+}
+
+// ListVoices returns the stock and custom voices of the account. Voices
+// whose category is not "premade" are reported as Custom.
+func (e *Engine) ListVoices(ctx context.Context) ([]tts.Voice, error) {
+	body, err := e.do(ctx, http.MethodGet, "/voices", "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Voices []voiceResponse `json:"voices"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("elevenlabs tts: decode voices: %w", err)
+	}
+	voices := make([]tts.Voice, len(resp.Voices))
+	for i, v := range resp.Voices {
+		voices[i] = v.voice()
+	}
+	return voices, nil
+}
+
+// CreateVoice creates an instant voice clone called name from one or more
+// audio samples of the speaker (MP3, WAV and other common formats).
+func (e *Engine) CreateVoice(ctx context.Context, name string, samples [][]byte) (tts.Voice, error) {
+	if name == "" || len(samples) == 0 {
+		return tts.Voice{}, core.Errorf(core.ErrInvalidInput, "elevenlabs tts: create voice needs a name and at least one sample")
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("name", name); err != nil {
+		return tts.Voice{}, fmt.Errorf("elevenlabs tts: build request: %w", err)
+	}
+	for i, s := range samples {
+		part, err := w.CreateFormFile("files", fmt.Sprintf("sample%d.%s", i+1, tts.DetectFormat(s)))
+		if err != nil {
+			return tts.Voice{}, fmt.Errorf("elevenlabs tts: build request: %w", err)
+		}
+		if _, err := part.Write(s); err != nil {
+			return tts.Voice{}, fmt.Errorf("elevenlabs tts: build request: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return tts.Voice{}, fmt.Errorf("elevenlabs tts: build request: %w", err)
+	}
+
+	body, err := e.do(ctx, http.MethodPost, "/voices/add", w.FormDataContentType(), "", &buf)
+	if err != nil {
+		return tts.Voice{}, err
+	}
+	var resp struct {
+		VoiceID string `json:"voice_id"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return tts.Voice{}, fmt.Errorf("elevenlabs tts: decode voice: %w", err)
+	}
+	return tts.Voice{ID: resp.VoiceID, Name: name, Custom: true}, nil
+}
+
+// DeleteVoice deletes the custom voice with the given ID.
+func (e *Engine) DeleteVoice(ctx context.Context, id string) error {
+	if id == "" {
+		return core.Errorf(core.ErrInvalidInput, "elevenlabs tts: delete voice needs an ID")
+	}
+	_, err := e.do(ctx, http.MethodDelete, "/voices/"+url.PathEscape(id), "", "", nil)
+	return err
+}
@@ -0,0 +1,95 @@
+package elevenlabs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice/tts"
+)
+
+func newVoiceTestEngine(t *testing.T, h http.HandlerFunc) *Engine {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	e, err := New(tts.Config{Extra: map[string]any{"api_key": "xi-test", "base_url": srv.URL}})
+	require.NoError(t, err)
+	return e
+}
+
+func TestEngine_ImplementsVoiceManager(t *testing.T) {
+	var engine tts.TTS = &Engine{}
+	_, ok := engine.(tts.VoiceManager)
+	assert.True(t, ok)
+}
+
+func TestListVoices(t *testing.T) {
+	e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/voices", r.URL.Path)
+		assert.Equal(t, "xi-test", r.Header.Get("xi-api-key"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"voices": []map[string]any{
+			{"voice_id": "v1", "name": "Rachel", "category": "premade", "labels": map[string]string{"language": "en"}},
+			{"voice_id": "v2", "name": "Tenant", "category": "cloned", "description": "support line"},
+		}})
+	})
+
+	voices, err := e.ListVoices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []tts.Voice{
+		{ID: "v1", Name: "Rachel", Language: "en"},
+		{ID: "v2", Name: "Tenant", Description: "support line", Custom: true},
+	}, voices)
+}
+
+func TestCreateVoice(t *testing.T) {
+	e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/voices/add", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "tenant-42", r.FormValue("name"))
+		files := r.MultipartForm.File["files"]
+		require.Len(t, files, 2)
+		assert.Equal(t, "sample1.mp3", files[0].Filename)
+		f, err := files[1].Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(f)
+		assert.Equal(t, "ID3second", string(data))
+		_ = json.NewEncoder(w).Encode(map[string]string{"voice_id": "new-id"})
+	})
+
+	v, err := e.CreateVoice(context.Background(), "tenant-42", [][]byte{[]byte("ID3first"), []byte("ID3second")})
+	require.NoError(t, err)
+	assert.Equal(t, tts.Voice{ID: "new-id", Name: "tenant-42", Custom: true}, v)
+
+	_, err = e.CreateVoice(context.Background(), "tenant-42", nil)
+	assert.ErrorContains(t, err, "at least one sample")
+}
+
+func TestDeleteVoice(t *testing.T) {
+	var deleted string
+	e := newVoiceTestEngine(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = r.URL.Path
+		if r.URL.Path == "/voices/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("voice not found"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	require.NoError(t, e.DeleteVoice(context.Background(), "v2"))
+	assert.Equal(t, "/voices/v2", deleted)
+
+	err := e.DeleteVoice(context.Background(), "missing")
+	assert.ErrorContains(t, err, "status 404")
+
+	assert.Error(t, e.DeleteVoice(context.Background(), ""))
+}
@@ -70,6 +70,11 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		opt(&cfg)
 	}
 
+	text, err := tts.PrepareText(cfg, text, nil)
+	if err != nil {
+		return nil, fmt.Errorf("lmnt: %w", err)
+	}
+
 	reqBody := synthesizeRequest{
 		Text:  text,
 		Voice: cfg.Voice,
@@ -78,6 +78,11 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		opt(&cfg)
 	}
 
+	text, err := tts.PrepareText(cfg, text, nil)
+	if err != nil {
+		return nil, fmt.Errorf("playht: %w", err)
+	}
+
 	format := "mp3"
 	if cfg.Format != "" {
 		format = string(cfg.Format)
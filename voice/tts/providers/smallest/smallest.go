@@ -75,6 +75,11 @@ func (e *Engine) Synthesize(ctx context.Context, text string, opts ...tts.Option
 		opt(&cfg)
 	}
 
+	text, err := tts.PrepareText(cfg, text, nil)
+	if err != nil {
+		return nil, fmt.Errorf("smallest: %w", err)
+	}
+
 	reqBody := synthesizeRequest{
 		Text:  text,
 		Voice: cfg.Voice,
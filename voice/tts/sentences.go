@@ -0,0 +1,153 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"iter"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SynthesizeSentences synthesizes a streaming text source, such as tokens
+// from an LLM, one sentence at a time. Text is buffered until a sentence
+// ends, and each sentence is synthesized with engine.Synthesize and yielded
+// as one audio chunk, so that chunk boundaries fall between sentences rather
+// than mid-word. Text left when the stream ends is synthesized as a final
+// sentence.
+//
+// WAV output is stitched into one gapless stream: the first chunk starts with
+// a WAV header declaring an unknown length (0xFFFFFFFF) and later chunks
+// carry only samples, so no header bytes are played between sentences. Other
+// formats are yielded as returned by the engine.
+//
+// With SSML enabled (see WithSSML), each text chunk is synthesized whole
+// because splitting it could break the markup.
+func SynthesizeSentences(ctx context.Context, engine TTS, textStream iter.Seq2[string, error], opts ...Option) iter.Seq2[[]byte, error] {
+	cfg := ApplyOptions(opts...)
+	return func(yield func([]byte, error) bool) {
+		var st stitcher
+		synthesize := func(sentence string) bool {
+			if strings.TrimSpace(sentence) == "" {
+				return true
+			}
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return false
+			}
+			audio, err := engine.Synthesize(ctx, sentence, opts...)
+			if err != nil {
+				yield(nil, err)
+				return false
+			}
+			if audio = st.next(audio); len(audio) == 0 {
+				return true
+			}
+			return yield(audio, nil)
+		}
+
+		var pending strings.Builder
+		for text, err := range textStream {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if cfg.SSML {
+				if !synthesize(text) {
+					return
+				}
+				continue
+			}
+			pending.WriteString(text)
+			buf := pending.String()
+			rest := buf
+			for {
+				end := sentenceEnd(rest)
+				if end < 0 {
+					break
+				}
+				if !synthesize(strings.TrimSpace(rest[:end])) {
+					return
+				}
+				rest = rest[end:]
+			}
+			if len(rest) != len(buf) {
+				pending.Reset()
+				pending.WriteString(rest)
+			}
+		}
+		synthesize(strings.TrimSpace(pending.String()))
+	}
+}
+
+// sentenceEnd returns the offset just past the first complete sentence in
+// text, or -1 when text holds no complete sentence yet. A sentence ends at a
+// newline, at '.', '!' or '?' (plus any closing quotes or brackets) once
+// followed by whitespace, or at CJK full-width terminal punctuation.
+func sentenceEnd(text string) int {
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		switch r {
+		case '\n':
+			return i
+		case '。', '！', '？':
+			return i
+		case '.', '!', '?':
+			j := i
+			for j < len(text) && strings.IndexByte(`"')]`, text[j]) >= 0 {
+				j++
+			}
+			if j == len(text) {
+				// The next chunk decides whether the sentence ended.
+				return -1
+			}
+			if next, _ := utf8.DecodeRuneInString(text[j:]); unicode.IsSpace(next) {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// stitcher joins per-sentence audio into one stream, removing the WAV
+// headers that would otherwise be played as clicks between sentences.
+type stitcher struct {
+	started bool
+	wav     bool
+	carry   []byte // trailing partial sample frame of the previous chunk
+}
+
+// next returns the bytes of audio to append to the stream.
+func (s *stitcher) next(audio []byte) []byte {
+	first := !s.started
+	s.started = true
+	if first {
+		s.wav = isWAV(audio)
+	}
+	if !s.wav {
+		return audio
+	}
+
+	info, ok := parseWAV(audio)
+	if !ok {
+		// Headerless continuation audio; treat it as raw samples.
+		return audio
+	}
+	data := append(s.carry, audio[info.dataOffset:info.dataOffset+info.dataSize]...)
+	s.carry = nil
+	if align := int(info.blockAlign); align > 1 {
+		whole := len(data) / align * align
+		s.carry = append([]byte(nil), data[whole:]...)
+		data = data[:whole]
+	}
+	if !first {
+		return data
+	}
+
+	out := make([]byte, 0, info.dataOffset+len(data))
+	out = append(out, audio[:info.dataOffset]...)
+	binary.LittleEndian.PutUint32(out[4:8], 0xFFFFFFFF)
+	binary.LittleEndian.PutUint32(out[info.dataOffset-4:info.dataOffset], 0xFFFFFFFF)
+	return append(out, data...)
+}
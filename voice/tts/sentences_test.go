@@ -0,0 +1,176 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// textChunks returns a text stream of chunks.
+func textChunks(chunks ...string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, c := range chunks {
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
+}
+
+func collectAudio(t *testing.T, stream iter.Seq2[[]byte, error]) [][]byte {
+	t.Helper()
+	var out [][]byte
+	for chunk, err := range stream {
+		require.NoError(t, err)
+		out = append(out, chunk)
+	}
+	return out
+}
+
+func TestSentenceEnd(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Hello there. How", 12},
+		{"Hello there.", -1},
+		{"Pi is 3.14 exactly", -1},
+		{`He said "stop!" then`, 15},
+		{"Really? Yes", 7},
+		{"line one\nline two", 9},
+		{"你好。再见", 9},
+		{"no end yet", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, sentenceEnd(tt.text))
+		})
+	}
+}
+
+func TestSynthesizeSentences_BuffersSentences(t *testing.T) {
+	var texts []string
+	engine := &mockTTS{synthesizeFunc: func(_ context.Context, text string, _ ...Option) ([]byte, error) {
+		texts = append(texts, text)
+		return []byte("<" + text + ">"), nil
+	}}
+
+	stream := textChunks("Hel", "lo there", ". How are", " you? I'm", " fine.", " Bye")
+	audio := collectAudio(t, SynthesizeSentences(context.Background(), engine, stream))
+
+	assert.Equal(t, []string{"Hello there.", "How are you?", "I'm fine.", "Bye"}, texts)
+	assert.Equal(t, [][]byte{
+		[]byte("<Hello there.>"), []byte("<How are you?>"), []byte("<I'm fine.>"), []byte("<Bye>"),
+	}, audio)
+}
+
+func TestSynthesizeSentences_StitchesWAV(t *testing.T) {
+	engine := &mockTTS{synthesizeFunc: func(_ context.Context, text string, _ ...Option) ([]byte, error) {
+		switch text {
+		case "One.":
+			return wav([]byte{1, 2, 3}), nil
+		case "Two.":
+			return wav([]byte{4, 5, 6}), nil
+		}
+		return wav([]byte{7, 8}), nil
+	}}
+
+	audio := collectAudio(t, SynthesizeSentences(context.Background(), engine, textChunks("One. Two. Three")))
+	require.Len(t, audio, 3)
+
+	first := audio[0]
+	require.Len(t, first, 44+2)
+	assert.Equal(t, "RIFF", string(first[0:4]))
+	assert.Equal(t, uint32(0xFFFFFFFF), binary.LittleEndian.Uint32(first[4:8]))
+	assert.Equal(t, "data", string(first[36:40]))
+	assert.Equal(t, uint32(0xFFFFFFFF), binary.LittleEndian.Uint32(first[40:44]))
+	assert.Equal(t, []byte{1, 2}, first[44:])
+
+	// Later chunks carry only samples, completing sample frames split across
+	// sentences.
+	assert.Equal(t, []byte{3, 4, 5, 6}, audio[1])
+	assert.Equal(t, []byte{7, 8}, audio[2])
+
+	info, ok := parseWAV(first)
+	require.True(t, ok)
+	assert.Equal(t, 44, info.dataOffset)
+	assert.Equal(t, 2, info.dataSize)
+}
+
+func TestSynthesizeSentences_PassesOtherFormats(t *testing.T) {
+	engine := &mockTTS{}
+	audio := collectAudio(t, SynthesizeSentences(context.Background(), engine, textChunks("A. B."), WithFormat(FormatMP3)))
+	assert.Equal(t, [][]byte{[]byte("audio:A."), []byte("audio:B.")}, audio)
+}
+
+func TestSynthesizeSentences_SSMLChunksWhole(t *testing.T) {
+	var texts []string
+	engine := &mockTTS{synthesizeFunc: func(_ context.Context, text string, _ ...Option) ([]byte, error) {
+		texts = append(texts, text)
+		return []byte("x"), nil
+	}}
+	chunk := `<speak>One. <break time="1s"/> Two.</speak>`
+	collectAudio(t, SynthesizeSentences(context.Background(), engine, textChunks(chunk), WithSSML(true)))
+	assert.Equal(t, []string{chunk}, texts)
+}
+
+func TestSynthesizeSentences_Errors(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		streamErr := errors.New("llm failed")
+		stream := func(yield func(string, error) bool) {
+			if yield("Done. Not", nil) {
+				yield("", streamErr)
+			}
+		}
+		var chunks int
+		var gotErr error
+		for _, err := range SynthesizeSentences(context.Background(), &mockTTS{}, stream) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			chunks++
+		}
+		assert.Equal(t, 1, chunks)
+		assert.ErrorIs(t, gotErr, streamErr)
+	})
+
+	t.Run("synthesize", func(t *testing.T) {
+		synthErr := errors.New("provider down")
+		engine := &mockTTS{synthesizeFunc: func(context.Context, string, ...Option) ([]byte, error) {
+			return nil, synthErr
+		}}
+		var gotErr error
+		for _, err := range SynthesizeSentences(context.Background(), engine, textChunks("One. Two.")) {
+			gotErr = err
+		}
+		assert.ErrorIs(t, gotErr, synthErr)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var gotErr error
+		for _, err := range SynthesizeSentences(ctx, &mockTTS{}, textChunks("One. Two.")) {
+			gotErr = err
+		}
+		assert.ErrorIs(t, gotErr, context.Canceled)
+	})
+}
+
+func TestSynthesizeSentences_EarlyBreak(t *testing.T) {
+	var calls int
+	engine := &mockTTS{synthesizeFunc: func(_ context.Context, text string, _ ...Option) ([]byte, error) {
+		calls++
+		return []byte(text), nil
+	}}
+	for range SynthesizeSentences(context.Background(), engine, textChunks("One. Two. Three.")) {
+		break
+	}
+	assert.Equal(t, 1, calls)
+}
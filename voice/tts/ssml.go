@@ -0,0 +1,286 @@
+package tts
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// SSMLSegment is a run of text, or a pause, from parsed SSML along with the
+// prosody in effect for it.
+type SSMLSegment struct {
+	// Text is the text to speak. It is empty for pauses.
+	Text string
+
+	// Break is the length of a pause. It is zero for text.
+	Break time.Duration
+
+	// Emphasis is the <emphasis> level: "strong", "moderate", "reduced" or
+	// "none". Empty means no emphasis.
+	Emphasis string
+
+	// Rate is the speaking-rate multiplier from <prosody rate>. Zero means
+	// the default rate.
+	Rate float64
+
+	// Volume is the loudness multiplier from <prosody volume>. Zero means the
+	// default volume.
+	Volume float64
+}
+
+// SSMLRenderer renders parsed SSML as a provider's native input text.
+type SSMLRenderer func(segments []SSMLSegment) string
+
+// breakStrengths maps <break strength> values to pause lengths.
+var breakStrengths = map[string]time.Duration{
+	"none":     0,
+	"x-weak":   100 * time.Millisecond,
+	"weak":     250 * time.Millisecond,
+	"medium":   500 * time.Millisecond,
+	"strong":   time.Second,
+	"x-strong": 2 * time.Second,
+}
+
+// rateNames maps named <prosody rate> values to multipliers.
+var rateNames = map[string]float64{
+	"x-slow": 0.5, "slow": 0.75, "medium": 1, "default": 1, "fast": 1.25, "x-fast": 1.5,
+}
+
+// volumeNames maps named <prosody volume> values to multipliers.
+var volumeNames = map[string]float64{
+	"silent": 0.01, "x-soft": 0.5, "soft": 0.75, "medium": 1, "default": 1, "loud": 1.5, "x-loud": 2,
+}
+
+// ParseSSML parses an SSML document, or a fragment without a <speak> root,
+// into segments. The supported subset is <speak>, <p>, <s>, <break> (time or
+// strength), <emphasis>, <prosody> (rate and volume), <say-as> and <sub>
+// (replaced by its alias). Other elements are ignored but their text is
+// kept. Malformed markup returns an ErrInvalidInput error.
+func ParseSSML(ssml string) ([]SSMLSegment, error) {
+	trimmed := strings.TrimSpace(ssml)
+	if !strings.HasPrefix(trimmed, "<speak") && !strings.HasPrefix(trimmed, "<?xml") {
+		trimmed = "<speak>" + trimmed + "</speak>"
+	}
+
+	type state struct {
+		emphasis string
+		rate     float64
+		volume   float64
+	}
+	stack := []state{{rate: 1, volume: 1}}
+	var segments []SSMLSegment
+	skip := 0 // depth inside a <sub> whose text is replaced by its alias
+
+	text := func(s string) {
+		top := stack[len(stack)-1]
+		segments = append(segments, SSMLSegment{
+			Text:     s,
+			Emphasis: top.emphasis,
+			Rate:     relative(top.rate),
+			Volume:   relative(top.volume),
+		})
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(trimmed))
+	dec.Strict = true
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, core.Errorf(core.ErrInvalidInput, "tts: invalid SSML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			top := stack[len(stack)-1]
+			next := state{emphasis: top.emphasis, rate: top.rate, volume: top.volume}
+			switch t.Name.Local {
+			case "break":
+				segments = append(segments, SSMLSegment{Break: breakDuration(t)})
+			case "emphasis":
+				next.emphasis = attr(t, "level")
+				if next.emphasis == "" {
+					next.emphasis = "moderate"
+				}
+			case "prosody":
+				next.rate = prosodyValue(attr(t, "rate"), top.rate, rateNames, false)
+				next.volume = prosodyValue(attr(t, "volume"), top.volume, volumeNames, true)
+			case "sub":
+				if skip == 0 {
+					text(" " + attr(t, "alias") + " ")
+				}
+				skip++
+			case "p", "s":
+				text(" ")
+			}
+			stack = append(stack, next)
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "sub":
+				skip--
+			case "p", "s":
+				text(" ")
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if skip == 0 {
+				text(string(t))
+			}
+		}
+	}
+	return mergeSegments(segments), nil
+}
+
+// mergeSegments joins adjacent text segments with the same prosody and drops
+// segments without text.
+func mergeSegments(segments []SSMLSegment) []SSMLSegment {
+	var out []SSMLSegment
+	for _, s := range segments {
+		if s.Break == 0 && s.Text == "" {
+			continue
+		}
+		if n := len(out); n > 0 && s.Break == 0 && out[n-1].Break == 0 &&
+			out[n-1].Emphasis == s.Emphasis && out[n-1].Rate == s.Rate && out[n-1].Volume == s.Volume {
+			out[n-1].Text += s.Text
+			continue
+		}
+		out = append(out, s)
+	}
+	// Fold whitespace-only text into the preceding segment so that words
+	// stay separated without a segment of their own.
+	kept := out[:0]
+	for _, s := range out {
+		if s.Break == 0 && strings.TrimSpace(s.Text) == "" {
+			if n := len(kept); n > 0 && kept[n-1].Break == 0 {
+				kept[n-1].Text += " "
+			}
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// StripSSML returns the text of an SSML document with all markup removed.
+func StripSSML(ssml string) (string, error) {
+	segments, err := ParseSSML(ssml)
+	if err != nil {
+		return "", err
+	}
+	return PlainText(segments), nil
+}
+
+// PlainText is the SSMLRenderer for providers without markup support. It
+// returns the text of segments with whitespace collapsed; pauses, emphasis
+// and prosody are dropped.
+func PlainText(segments []SSMLSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString(s.Text)
+		b.WriteByte(' ')
+	}
+	return CollapseSpace(b.String())
+}
+
+// CollapseSpace trims s and replaces each run of whitespace with one space.
+// SSMLRenderer implementations use it to tidy their output.
+func CollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// PrepareText returns text ready to send to a provider. When SSML is not
+// enabled in cfg (see WithSSML) text is returned unchanged; otherwise it is
+// parsed with ParseSSML and rendered with render, or PlainText when render
+// is nil.
+func PrepareText(cfg Config, text string, render SSMLRenderer) (string, error) {
+	if !cfg.SSML {
+		return text, nil
+	}
+	segments, err := ParseSSML(text)
+	if err != nil {
+		return "", err
+	}
+	if render == nil {
+		render = PlainText
+	}
+	return render(segments), nil
+}
+
+// FormatSeconds formats d in seconds with an "s" suffix, as used by the time
+// attribute of <break> tags, for example "0.5s".
+func FormatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// breakDuration returns the pause length of a <break> element.
+func breakDuration(el xml.StartElement) time.Duration {
+	if v := attr(el, "time"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	if d, ok := breakStrengths[attr(el, "strength")]; ok {
+		return d
+	}
+	return breakStrengths["medium"]
+}
+
+// prosodyValue converts a <prosody> rate or volume to a multiplier of base.
+// It accepts named values, percentages ("120%", "+20%", "-10%"), plain
+// multipliers ("1.2") and, for volume, decibels ("+6dB"). Unrecognised
+// values leave base unchanged.
+func prosodyValue(v string, base float64, names map[string]float64, decibels bool) float64 {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return base
+	}
+	if m, ok := names[v]; ok {
+		return m
+	}
+	if decibels && strings.HasSuffix(strings.ToLower(v), "db") {
+		if db, err := strconv.ParseFloat(v[:len(v)-2], 64); err == nil {
+			return base * math.Pow(10, db/20)
+		}
+		return base
+	}
+	if p, ok := strings.CutSuffix(v, "%"); ok {
+		pct, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return base
+		}
+		if p[0] == '+' || p[0] == '-' {
+			return base * (1 + pct/100)
+		}
+		return base * pct / 100
+	}
+	if m, err := strconv.ParseFloat(v, 64); err == nil && m > 0 {
+		return base * m
+	}
+	return base
+}
+
+// relative returns 0 for the default multiplier 1, else m.
+func relative(m float64) float64 {
+	if math.Abs(m-1) < 1e-9 {
+		return 0
+	}
+	return m
+}
+
+// attr returns the value of the named attribute of el.
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
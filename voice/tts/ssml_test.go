@@ -0,0 +1,105 @@
+package tts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+func TestParseSSML(t *testing.T) {
+	segments, err := ParseSSML(`<speak>Hello <break time="750ms"/> there.
+		<emphasis level="strong">Listen</emphasis> <prosody rate="slow" volume="+6dB">carefully</prosody>
+		<sub alias="World Wide Web Consortium">W3C</sub> <say-as interpret-as="characters">SSML</say-as></speak>`)
+	require.NoError(t, err)
+
+	require.Len(t, segments, 6)
+	assert.Equal(t, "Hello ", segments[0].Text)
+	assert.Equal(t, 750*time.Millisecond, segments[1].Break)
+	assert.Empty(t, segments[1].Text)
+	assert.Equal(t, "Listen ", segments[3].Text, "whitespace between elements should be kept")
+	assert.Equal(t, "strong", segments[3].Emphasis)
+	assert.Equal(t, "carefully", segments[4].Text)
+	assert.Equal(t, 0.75, segments[4].Rate)
+	assert.InDelta(t, 1.995, segments[4].Volume, 0.01)
+	assert.Zero(t, segments[5].Rate, "prosody should end with its element")
+	assert.Contains(t, segments[5].Text, "World Wide Web Consortium")
+	assert.NotContains(t, segments[5].Text, "W3C")
+	assert.Contains(t, segments[5].Text, "SSML")
+
+	text, err := StripSSML(`<emphasis>Listen</emphasis> <prosody rate="slow">now</prosody>`)
+	require.NoError(t, err)
+	assert.Equal(t, "Listen now", text)
+}
+
+func TestParseSSML_Fragment(t *testing.T) {
+	segments, err := ParseSSML(`Wait <break strength="strong"/> now`)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	assert.Equal(t, time.Second, segments[1].Break)
+
+	segments, err = ParseSSML(`plain text`)
+	require.NoError(t, err)
+	assert.Equal(t, []SSMLSegment{{Text: "plain text"}}, segments)
+}
+
+func TestParseSSML_Prosody(t *testing.T) {
+	tests := []struct {
+		ssml string
+		rate float64
+	}{
+		{`<prosody rate="120%">x</prosody>`, 1.2},
+		{`<prosody rate="+20%">x</prosody>`, 1.2},
+		{`<prosody rate="-10%">x</prosody>`, 0.9},
+		{`<prosody rate="1.5">x</prosody>`, 1.5},
+		{`<prosody rate="x-fast">x</prosody>`, 1.5},
+		{`<prosody rate="medium">x</prosody>`, 0},
+		{`<prosody rate="bogus">x</prosody>`, 0},
+		{`<prosody rate="fast"><prosody rate="+20%">x</prosody></prosody>`, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ssml, func(t *testing.T) {
+			segments, err := ParseSSML(tt.ssml)
+			require.NoError(t, err)
+			require.Len(t, segments, 1)
+			assert.InDelta(t, tt.rate, segments[0].Rate, 1e-9)
+		})
+	}
+}
+
+func TestParseSSML_Invalid(t *testing.T) {
+	_, err := ParseSSML(`<speak>unclosed <emphasis>tag</speak>`)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, core.Errorf(core.ErrInvalidInput, ""))
+}
+
+func TestStripSSML(t *testing.T) {
+	text, err := StripSSML("<speak><p>One.</p><p>Two <break/> three.</p></speak>")
+	require.NoError(t, err)
+	assert.Equal(t, "One. Two three.", text)
+}
+
+func TestPrepareText(t *testing.T) {
+	in := `Hi <break time="1s"/> there`
+
+	out, err := PrepareText(Config{}, in, nil)
+	require.NoError(t, err)
+	assert.Equal(t, in, out, "text should be unchanged when SSML is disabled")
+
+	cfg := ApplyOptions(WithSSML(true))
+	out, err = PrepareText(cfg, in, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi there", out)
+
+	out, err = PrepareText(cfg, in, func(segments []SSMLSegment) string {
+		return FormatSeconds(segments[1].Break)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1s", out)
+
+	_, err = PrepareText(cfg, "<speak>", nil)
+	assert.Error(t, err)
+}
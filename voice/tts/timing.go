@@ -0,0 +1,149 @@
+package tts
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lookatitude/beluga-ai/v2/core"
+)
+
+// WordTiming is the span of synthesized audio in which a word is spoken.
+type WordTiming struct {
+	// Word is the spoken word, including attached punctuation.
+	Word string
+
+	// Start is the offset of the start of the word in the audio.
+	Start time.Duration
+
+	// End is the offset of the end of the word in the audio.
+	End time.Duration
+
+	// Estimated reports that the timing was estimated from the text rather
+	// than reported by the provider.
+	Estimated bool
+}
+
+// TimedTTS is implemented by providers that report when each word is
+// spoken, such as ElevenLabs and Cartesia.
+type TimedTTS interface {
+	TTS
+
+	// SynthesizeWithTimings converts text to a complete audio buffer and
+	// returns the timing of each word in it.
+	SynthesizeWithTimings(ctx context.Context, text string, opts ...Option) ([]byte, []WordTiming, error)
+}
+
+// SynthesizeWithTimings converts text to audio with engine and returns the
+// timing of each word. Engines implementing TimedTTS report the provider's
+// alignment. For other engines the audio duration is spread over the words
+// in proportion to their length and the timings are flagged Estimated; this
+// needs WAV output, or PCM output with WithFormat(FormatPCM) and
+// WithSampleRate, and returns an ErrInvalidInput error for other formats.
+func SynthesizeWithTimings(ctx context.Context, engine TTS, text string, opts ...Option) ([]byte, []WordTiming, error) {
+	if timed, ok := engine.(TimedTTS); ok {
+		return timed.SynthesizeWithTimings(ctx, text, opts...)
+	}
+
+	cfg := ApplyOptions(opts...)
+	audio, err := engine.Synthesize(ctx, text, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	duration, ok := audioDuration(audio, cfg)
+	if !ok {
+		return nil, nil, core.Errorf(core.ErrInvalidInput,
+			"tts: cannot estimate word timings for %q audio; request FormatPCM with a sample rate, or FormatWAV", cfg.Format)
+	}
+	if cfg.SSML {
+		if text, err = StripSSML(text); err != nil {
+			return nil, nil, err
+		}
+	}
+	return audio, EstimateTimings(text, duration), nil
+}
+
+// EstimateTimings spreads duration over the words of text in proportion to
+// their length in characters, counting one extra character per word for the
+// gap after it. The timings are flagged Estimated.
+func EstimateTimings(text string, duration time.Duration) []WordTiming {
+	words := strings.Fields(text)
+	if len(words) == 0 || duration <= 0 {
+		return nil
+	}
+	total := 0
+	for _, w := range words {
+		total += utf8.RuneCountInString(w) + 1
+	}
+
+	timings := make([]WordTiming, len(words))
+	units := 0
+	for i, w := range words {
+		n := utf8.RuneCountInString(w)
+		timings[i] = WordTiming{
+			Word:      w,
+			Start:     duration * time.Duration(units) / time.Duration(total),
+			End:       duration * time.Duration(units+n) / time.Duration(total),
+			Estimated: true,
+		}
+		units += n + 1
+	}
+	return timings
+}
+
+// WordsFromCharacters groups per-character alignment, as reported by some
+// providers, into word timings. Characters inside markup tags ("<...>") are
+// skipped and whitespace separates words.
+func WordsFromCharacters(chars []string, starts, ends []time.Duration) []WordTiming {
+	var timings []WordTiming
+	var word strings.Builder
+	var start, end time.Duration
+	inTag := false
+
+	flush := func() {
+		if word.Len() > 0 {
+			timings = append(timings, WordTiming{Word: word.String(), Start: start, End: end})
+			word.Reset()
+		}
+	}
+
+	for i, c := range chars {
+		if i >= len(starts) || i >= len(ends) {
+			break
+		}
+		switch {
+		case c == "<":
+			flush()
+			inTag = true
+		case inTag:
+			inTag = c != ">"
+		case strings.TrimSpace(c) == "":
+			flush()
+		default:
+			if word.Len() == 0 {
+				start = starts[i]
+			}
+			word.WriteString(c)
+			end = ends[i]
+		}
+	}
+	flush()
+	return timings
+}
+
+// audioDuration returns the playing time of WAV audio, or of PCM audio when
+// cfg requests PCM at a known sample rate.
+func audioDuration(audio []byte, cfg Config) (time.Duration, bool) {
+	if isWAV(audio) {
+		info, ok := parseWAV(audio)
+		if !ok {
+			return 0, false
+		}
+		return time.Duration(info.dataSize) * time.Second / time.Duration(info.byteRate), true
+	}
+	if cfg.Format == FormatPCM && cfg.SampleRate > 0 {
+		return time.Duration(len(audio)/2) * time.Second / time.Duration(cfg.SampleRate), true
+	}
+	return 0, false
+}
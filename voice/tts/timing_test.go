@@ -0,0 +1,128 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lookatitude/beluga-ai/v2/voice"
+)
+
+// timedTTS is a mock TimedTTS returning fixed timings.
+type timedTTS struct {
+	mockTTS
+	timings []WordTiming
+}
+
+var _ TimedTTS = (*timedTTS)(nil)
+
+func (m *timedTTS) SynthesizeWithTimings(ctx context.Context, text string, opts ...Option) ([]byte, []WordTiming, error) {
+	return []byte("timed:" + text), m.timings, nil
+}
+
+// wav returns a 16 kHz mono 16-bit WAV file holding pcm.
+func wav(pcm []byte) []byte {
+	out := make([]byte, 44, 44+len(pcm))
+	copy(out[0:], "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(36+len(pcm)))
+	copy(out[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(out[16:], 16)
+	binary.LittleEndian.PutUint16(out[20:], 1)
+	binary.LittleEndian.PutUint16(out[22:], 1)
+	binary.LittleEndian.PutUint32(out[24:], 16000)
+	binary.LittleEndian.PutUint32(out[28:], 32000)
+	binary.LittleEndian.PutUint16(out[32:], 2)
+	binary.LittleEndian.PutUint16(out[34:], 16)
+	copy(out[36:], "data")
+	binary.LittleEndian.PutUint32(out[40:], uint32(len(pcm)))
+	return append(out, pcm...)
+}
+
+func TestEstimateTimings(t *testing.T) {
+	timings := EstimateTimings("hi there", 900*time.Millisecond)
+	require.Len(t, timings, 2)
+	// "hi" and "there" weigh 3 and 6 units of 9.
+	assert.Equal(t, WordTiming{Word: "hi", Start: 0, End: 200 * time.Millisecond, Estimated: true}, timings[0])
+	assert.Equal(t, WordTiming{Word: "there", Start: 300 * time.Millisecond, End: 800 * time.Millisecond, Estimated: true}, timings[1])
+
+	assert.Nil(t, EstimateTimings("  ", time.Second))
+	assert.Nil(t, EstimateTimings("word", 0))
+}
+
+func TestSynthesizeWithTimings_Estimated(t *testing.T) {
+	second := make([]byte, 32000)
+
+	t.Run("pcm", func(t *testing.T) {
+		engine := &mockTTS{synthesizeFunc: func(context.Context, string, ...Option) ([]byte, error) {
+			return second, nil
+		}}
+		audio, timings, err := SynthesizeWithTimings(context.Background(), engine, "one two",
+			WithFormat(FormatPCM), WithSampleRate(16000))
+		require.NoError(t, err)
+		assert.Len(t, audio, len(second))
+		require.Len(t, timings, 2)
+		assert.True(t, timings[1].Estimated)
+		assert.Equal(t, 875*time.Millisecond, timings[1].End)
+	})
+
+	t.Run("wav with ssml", func(t *testing.T) {
+		engine := &mockTTS{synthesizeFunc: func(context.Context, string, ...Option) ([]byte, error) {
+			return wav(second), nil
+		}}
+		_, timings, err := SynthesizeWithTimings(context.Background(), engine,
+			`<speak>one <break time="1s"/> two</speak>`, WithSSML(true))
+		require.NoError(t, err)
+		require.Len(t, timings, 2)
+		assert.Equal(t, "two", timings[1].Word)
+		assert.Equal(t, 875*time.Millisecond, timings[1].End)
+	})
+
+	t.Run("unknown duration", func(t *testing.T) {
+		_, _, err := SynthesizeWithTimings(context.Background(), &mockTTS{}, "mp3 audio", WithFormat(FormatMP3))
+		assert.ErrorContains(t, err, "cannot estimate word timings")
+	})
+}
+
+func TestSynthesizeWithTimings_Timed(t *testing.T) {
+	want := []WordTiming{{Word: "hi", Start: 10 * time.Millisecond, End: 200 * time.Millisecond}}
+	audio, timings, err := SynthesizeWithTimings(context.Background(), &timedTTS{timings: want}, "hi")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("timed:hi"), audio)
+	assert.Equal(t, want, timings)
+}
+
+func TestWordsFromCharacters(t *testing.T) {
+	chars := []string{"H", "i", " ", "<", "b", ">", " ", "y", "o", "u", "!"}
+	starts := make([]time.Duration, len(chars))
+	ends := make([]time.Duration, len(chars))
+	for i := range chars {
+		starts[i] = time.Duration(i) * 100 * time.Millisecond
+		ends[i] = starts[i] + 100*time.Millisecond
+	}
+
+	timings := WordsFromCharacters(chars, starts, ends)
+	assert.Equal(t, []WordTiming{
+		{Word: "Hi", Start: 0, End: 200 * time.Millisecond},
+		{Word: "you!", Start: 700 * time.Millisecond, End: 1100 * time.Millisecond},
+	}, timings)
+}
+
+func TestAsFrameProcessor_WordTimings(t *testing.T) {
+	want := []WordTiming{{Word: "hello", End: 300 * time.Millisecond}}
+	proc := AsFrameProcessor(&timedTTS{timings: want}, 24000)
+
+	frames, err := runProcessor(context.Background(), proc, voice.NewTextFrame("hello"))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	assert.Equal(t, []byte("timed:hello"), frames[0].Data)
+	assert.Equal(t, want, frames[0].Metadata["word_timings"])
+
+	frames, err = runProcessor(context.Background(), AsFrameProcessor(&mockTTS{}, 24000), voice.NewTextFrame("hello"))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	assert.NotContains(t, frames[0].Metadata, "word_timings")
+}
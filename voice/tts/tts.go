@@ -3,6 +3,7 @@ package tts
 import (
 	"context"
 	"iter"
+	"time"
 
 	"github.com/lookatitude/beluga-ai/v2/core"
 	"github.com/lookatitude/beluga-ai/v2/internal/hookutil"
@@ -58,6 +59,14 @@ type Config struct {
 	// Pitch adjusts the voice pitch (-20.0 to 20.0, 0 = default).
 	Pitch float64
 
+	// SSML marks input text as SSML. Providers translate it to their native
+	// markup, or strip it when they have none. See ParseSSML.
+	SSML bool
+
+	// PrefetchBuffer is the amount of audio SynthesizeStream buffers ahead
+	// of playback. Zero disables prefetching. See WithPrefetchBuffer.
+	PrefetchBuffer time.Duration
+
 	// Extra holds provider-specific configuration.
 	Extra map[string]any
 }
@@ -107,6 +116,23 @@ func WithPitch(pitch float64) Option {
 	}
 }
 
+// WithSSML sets whether input text is SSML. When enabled, Synthesize and
+// SynthesizeStream accept an SSML document or fragment per call or chunk.
+func WithSSML(enabled bool) Option {
+	return func(cfg *Config) {
+		cfg.SSML = enabled
+	}
+}
+
+// WithPrefetchBuffer makes SynthesizeStream synthesize ahead of playback and
+// hold audio back until d of it is buffered, smoothing playback when the text
+// stream stalls.
+func WithPrefetchBuffer(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.PrefetchBuffer = d
+	}
+}
+
 // ApplyOptions applies the given options to a Config and returns it.
 func ApplyOptions(opts ...Option) Config {
 	var cfg Config
@@ -150,19 +176,40 @@ func synthesizeFrame(ctx context.Context, engine TTS, frame voice.Frame, sampleR
 	if frame.Type != voice.FrameText {
 		return []voice.Frame{frame}, nil
 	}
-	audio, err := engine.Synthesize(ctx, frame.Text(), opts...)
+	var (
+		audio   []byte
+		timings []WordTiming
+		err     error
+	)
+	if timed, ok := engine.(TimedTTS); ok {
+		audio, timings, err = timed.SynthesizeWithTimings(ctx, frame.Text(), opts...)
+	} else {
+		audio, err = engine.Synthesize(ctx, frame.Text(), opts...)
+	}
 	if err != nil {
 		return nil, core.Errorf(core.ErrProviderDown, "tts: synthesize: %w", err)
 	}
 	if len(audio) == 0 {
 		return nil, nil
 	}
-	return []voice.Frame{voice.NewAudioFrame(audio, sampleRate)}, nil
+	if format := ApplyOptions(opts...).Format; format != "" {
+		if audio, err = Transcode(audio, format, sampleRate); err != nil {
+			return nil, err
+		}
+	}
+	out := voice.NewAudioFrame(audio, sampleRate)
+	if len(timings) > 0 {
+		out.Metadata["word_timings"] = timings
+	}
+	return []voice.Frame{out}, nil
 }
 
 // AsFrameProcessor wraps a TTS engine as a voice.FrameProcessor.
 // It reads text frames from the input stream, runs synthesis, and yields
-// audio frames containing the synthesized audio.
+// audio frames containing the synthesized audio. When the engine implements
+// TimedTTS, each audio frame carries its []WordTiming in the "word_timings"
+// metadata key. When opts set a format with WithFormat, audio is converted to
+// that format at sampleRate with Transcode, whatever the provider returned.
 func AsFrameProcessor(engine TTS, sampleRate int, opts ...Option) voice.FrameProcessor {
 	return voice.FrameLoop(func(ctx context.Context, frame voice.Frame) ([]voice.Frame, error) {
 		return synthesizeFrame(ctx, engine, frame, sampleRate, opts...)
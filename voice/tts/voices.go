@@ -0,0 +1,42 @@
+package tts
+
+import "context"
+
+// Voice describes a voice offered by a provider.
+type Voice struct {
+	// ID is the provider's voice identifier, usable with WithVoice.
+	ID string
+
+	// Name is the display name of the voice.
+	Name string
+
+	// Description is the provider's description of the voice, if any.
+	Description string
+
+	// Language is the primary language of the voice, if known.
+	Language string
+
+	// Custom reports that the voice belongs to the account, for example a
+	// cloned voice, rather than being one of the provider's stock voices.
+	Custom bool
+}
+
+// VoiceManager is implemented by providers that support custom voices, such
+// as ElevenLabs and Cartesia. Obtain it from an engine with a type
+// assertion:
+//
+//	if vm, ok := engine.(tts.VoiceManager); ok {
+//	    v, err := vm.CreateVoice(ctx, "tenant-42", samples)
+//	}
+type VoiceManager interface {
+	// ListVoices returns the voices available to the account, both stock
+	// and custom.
+	ListVoices(ctx context.Context) ([]Voice, error)
+
+	// CreateVoice clones a new voice called name from audio samples of the
+	// speaker. Accepted sample formats and counts are provider-specific.
+	CreateVoice(ctx context.Context, name string, samples [][]byte) (Voice, error)
+
+	// DeleteVoice deletes the custom voice with the given ID.
+	DeleteVoice(ctx context.Context, id string) error
+}
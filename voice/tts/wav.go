@@ -0,0 +1,81 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// wavInfo describes the layout of a WAV file.
+type wavInfo struct {
+	// format is the WAVE format tag; 1 is integer PCM.
+	format uint16
+
+	// channels is the number of interleaved channels.
+	channels int
+
+	// sampleRate is the number of sample frames per second.
+	sampleRate int
+
+	// bitsPerSample is the size of one sample of one channel.
+	bitsPerSample int
+
+	// byteRate is the number of audio bytes per second.
+	byteRate uint32
+
+	// blockAlign is the size in bytes of one sample frame.
+	blockAlign uint16
+
+	// dataOffset is the offset of the first sample.
+	dataOffset int
+
+	// dataSize is the number of sample bytes present, at most the size
+	// declared by the data chunk.
+	dataSize int
+}
+
+// isWAV reports whether audio starts with a RIFF/WAVE header.
+func isWAV(audio []byte) bool {
+	return len(audio) >= 12 && bytes.Equal(audio[0:4], []byte("RIFF")) && bytes.Equal(audio[8:12], []byte("WAVE"))
+}
+
+// parseWAV locates the fmt and data chunks of a WAV file.
+func parseWAV(wav []byte) (wavInfo, bool) {
+	if !isWAV(wav) {
+		return wavInfo{}, false
+	}
+	var info wavInfo
+	for off := 12; off+8 <= len(wav); {
+		id := string(wav[off : off+4])
+		size := int(binary.LittleEndian.Uint32(wav[off+4 : off+8]))
+		body := off + 8
+		switch id {
+		case "fmt ":
+			if body+16 > len(wav) {
+				return wavInfo{}, false
+			}
+			info.format = binary.LittleEndian.Uint16(wav[body : body+2])
+			info.channels = int(binary.LittleEndian.Uint16(wav[body+2 : body+4]))
+			info.sampleRate = int(binary.LittleEndian.Uint32(wav[body+4 : body+8]))
+			info.byteRate = binary.LittleEndian.Uint32(wav[body+8 : body+12])
+			info.blockAlign = binary.LittleEndian.Uint16(wav[body+12 : body+14])
+			info.bitsPerSample = int(binary.LittleEndian.Uint16(wav[body+14 : body+16]))
+		case "data":
+			if info.byteRate == 0 {
+				return wavInfo{}, false
+			}
+			info.dataOffset = body
+			// Streamed WAV files declare an unknown data size as 0xFFFFFFFF,
+			// which may also overflow int on 32-bit platforms.
+			if size < 0 || size > len(wav)-body {
+				size = len(wav) - body
+			}
+			info.dataSize = size
+			return info, true
+		}
+		if size < 0 || size > len(wav) {
+			return wavInfo{}, false
+		}
+		off = body + size + size%2
+	}
+	return wavInfo{}, false
+}